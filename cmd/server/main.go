@@ -9,8 +9,13 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/waynenilsen/power-pro-v3/internal/auth"
 	"github.com/waynenilsen/power-pro-v3/internal/database"
+	"github.com/waynenilsen/power-pro-v3/internal/mail"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/oidc"
 	"github.com/waynenilsen/power-pro-v3/internal/server"
+	"github.com/waynenilsen/power-pro-v3/internal/userdata"
 )
 
 func main() {
@@ -18,8 +23,44 @@ func main() {
 	port := flag.Int("port", 8080, "Server port")
 	dbPath := flag.String("db", "powerpro.db", "Database file path")
 	migrationsPath := flag.String("migrations", "migrations", "Migrations directory path")
+	oidcConfigPath := flag.String("oidc-config", "", "JSON file of OpenID Connect providers for single sign-on")
+	appURL := flag.String("app-url", "", "Client app URL that password reset and verification emails link to")
+	mailFrom := flag.String("mail-from", "PowerPro <noreply@localhost>", "Sender address of outgoing email")
+	mailDir := flag.String("mail-dir", "mail", "Directory outgoing email is written to when no SMTP host is set")
+	smtpHost := flag.String("smtp-host", "", "SMTP server host for outgoing email")
+	smtpPort := flag.Int("smtp-port", 587, "SMTP server port")
+	smtpUsername := flag.String("smtp-username", "", "SMTP username; the password is read from POWERPRO_SMTP_PASSWORD")
+	requireAdmin2FA := flag.Bool("require-admin-2fa", false, "Withhold admin privileges from admins until they enable two-factor authentication")
+	authRateLimit := flag.Int("auth-rate-limit", 10, "Requests per minute each client IP can make to sign-in and recovery routes; 0 disables")
+	readRateLimit := flag.Int("read-rate-limit", 600, "GET requests per minute each user can make; 0 disables")
+	writeRateLimit := flag.Int("write-rate-limit", 120, "Other requests per minute each user can make; 0 disables")
+	loginLockout := flag.Bool("login-lockout", true, "Lock out email addresses and client IPs after repeated failed logins")
+	sessionTokenLifetime := flag.Duration("session-token-lifetime", auth.DefaultSessionConfig().TokenLifetime, "How long session tokens last before a refresh token renews them; 0 makes them last the whole session")
+	accountDeletionGrace := flag.Duration("account-deletion-grace", userdata.DefaultDeletionGracePeriod, "How long accounts are kept after their deletion is requested, during which it can be cancelled")
+	trustProxy := flag.Bool("trust-proxy", false, "Take client IPs from X-Forwarded-For; enable only behind a reverse proxy")
 	flag.Parse()
 
+	// Send email over SMTP when configured, otherwise write it to files
+	var mailer mail.Mailer = mail.NewFileMailer(*mailDir, *mailFrom)
+	if *smtpHost != "" {
+		mailer = mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:     *smtpHost,
+			Port:     *smtpPort,
+			Username: *smtpUsername,
+			Password: os.Getenv("POWERPRO_SMTP_PASSWORD"),
+			From:     *mailFrom,
+		})
+	}
+
+	// Load single sign-on providers
+	var oidcProviders []oidc.ProviderConfig
+	if *oidcConfigPath != "" {
+		var err error
+		if oidcProviders, err = oidc.LoadProviders(*oidcConfigPath); err != nil {
+			log.Fatalf("Failed to load OIDC providers: %v", err)
+		}
+	}
+
 	// Open database
 	db, err := database.Open(database.Config{
 		Path:           *dbPath,
@@ -30,10 +71,31 @@ func main() {
 	}
 	defer db.Close()
 
+	sessions := auth.DefaultSessionConfig()
+	sessions.TokenLifetime = *sessionTokenLifetime
+
+	var lockout auth.LockoutConfig
+	if *loginLockout {
+		lockout = auth.DefaultLockoutConfig()
+	}
+
 	// Create and start server
 	srv := server.New(server.Config{
-		Port: *port,
-		DB:   db,
+		Port:                  *port,
+		DB:                    db,
+		OIDCProviders:         oidcProviders,
+		Mailer:                mailer,
+		AppURL:                *appURL,
+		RequireAdminTwoFactor: *requireAdmin2FA,
+		RateLimits: server.RateLimits{
+			Auth:  middleware.PerMinute(*authRateLimit),
+			Read:  middleware.PerMinute(*readRateLimit),
+			Write: middleware.PerMinute(*writeRateLimit),
+		},
+		LoginLockout:         lockout,
+		Sessions:             sessions,
+		AccountDeletionGrace: *accountDeletionGrace,
+		TrustProxy:           *trustProxy,
 	})
 
 	// Handle graceful shutdown
@@ -130,11 +130,14 @@ export interface ClientConfig {
   isAdmin?: boolean;
   /** Bearer token for Authorization header */
   token?: string;
+  /** Single-use token that gets a new Bearer token once it expires */
+  refreshToken?: string;
 }
 
 // Storage keys for auth - must match auth-types.ts
 const AUTH_STORAGE_KEY = 'powerpro_user_id';
 const TOKEN_STORAGE_KEY = 'powerpro_token';
+const REFRESH_TOKEN_STORAGE_KEY = 'powerpro_refresh_token';
 
 // Initialize config from localStorage synchronously on module load
 function getInitialConfig(): ClientConfig {
@@ -142,10 +145,12 @@ function getInitialConfig(): ClientConfig {
 
   const token = localStorage.getItem(TOKEN_STORAGE_KEY);
   const userId = localStorage.getItem(AUTH_STORAGE_KEY);
+  const refreshToken = localStorage.getItem(REFRESH_TOKEN_STORAGE_KEY);
 
   const config: ClientConfig = {};
   if (token) config.token = token;
   if (userId) config.userId = userId;
+  if (refreshToken) config.refreshToken = refreshToken;
 
   return config;
 }
@@ -169,6 +174,69 @@ export function getClientConfig(): ClientConfig {
   return { ...globalConfig };
 }
 
+let refreshing: Promise<boolean> | null = null;
+
+/**
+ * Exchanges the refresh token for a new session token, storing both new tokens.
+ * Concurrent callers share one refresh, since each refresh token works only once.
+ * @returns Whether the session was refreshed
+ */
+function refreshSession(): Promise<boolean> {
+  if (!refreshing) {
+    refreshing = (async () => {
+      try {
+        const response = await fetch(buildUrl('/auth/refresh'), {
+          method: 'POST',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ refreshToken: globalConfig.refreshToken }),
+        });
+        if (!response.ok) {
+          return false;
+        }
+        const json: ApiResponse<{ token: string; refreshToken?: string }> = await response.json();
+        configureClient({ token: json.data.token, refreshToken: json.data.refreshToken });
+        localStorage.setItem(TOKEN_STORAGE_KEY, json.data.token);
+        if (json.data.refreshToken) {
+          localStorage.setItem(REFRESH_TOKEN_STORAGE_KEY, json.data.refreshToken);
+        }
+        return true;
+      } catch {
+        return false;
+      } finally {
+        refreshing = null;
+      }
+    })();
+  }
+  return refreshing;
+}
+
+/**
+ * Sends a request with the authentication headers. When the session token has expired
+ * and a refresh token is available, refreshes the session and retries once.
+ * @param url - The request URL
+ * @param init - Request options; headers given here replace the authentication headers
+ * @returns The fetch Response
+ */
+async function send(url: string, init: RequestInit): Promise<Response> {
+  const usedToken = globalConfig.token;
+  const response = await fetch(url, {
+    headers: createHeaders(globalConfig.userId, globalConfig.isAdmin, usedToken),
+    ...init,
+  });
+  if (response.status !== 401 || !usedToken || !globalConfig.refreshToken) {
+    return response;
+  }
+
+  // Another request may have refreshed the session already
+  if (globalConfig.token === usedToken && !(await refreshSession())) {
+    return response;
+  }
+  return fetch(url, {
+    headers: createHeaders(globalConfig.userId, globalConfig.isAdmin, globalConfig.token),
+    ...init,
+  });
+}
+
 /**
  * Handles response without unwrapping {data: T} - for paginated endpoints
  * that return {data: items[], meta: {...}} directly.
@@ -210,9 +278,8 @@ async function handleRawResponse<T>(response: Response): Promise<T> {
 export async function get<T>(path: string, options?: RequestOptions): Promise<T> {
   const url = buildUrl(path, options?.params);
 
-  const response = await fetch(url, {
+  const response = await send(url, {
     method: 'GET',
-    headers: createHeaders(globalConfig.userId, globalConfig.isAdmin, globalConfig.token),
     ...options,
   });
 
@@ -226,9 +293,8 @@ export async function get<T>(path: string, options?: RequestOptions): Promise<T>
 export async function getRaw<T>(path: string, options?: RequestOptions): Promise<T> {
   const url = buildUrl(path, options?.params);
 
-  const response = await fetch(url, {
+  const response = await send(url, {
     method: 'GET',
-    headers: createHeaders(globalConfig.userId, globalConfig.isAdmin, globalConfig.token),
     ...options,
   });
 
@@ -246,9 +312,8 @@ export async function getRaw<T>(path: string, options?: RequestOptions): Promise
 export async function post<T, B = unknown>(path: string, body?: B, options?: RequestOptions): Promise<T> {
   const url = buildUrl(path, options?.params);
 
-  const response = await fetch(url, {
+  const response = await send(url, {
     method: 'POST',
-    headers: createHeaders(globalConfig.userId, globalConfig.isAdmin, globalConfig.token),
     body: body ? JSON.stringify(body) : undefined,
     ...options,
   });
@@ -267,9 +332,8 @@ export async function post<T, B = unknown>(path: string, body?: B, options?: Req
 export async function put<T, B = unknown>(path: string, body?: B, options?: RequestOptions): Promise<T> {
   const url = buildUrl(path, options?.params);
 
-  const response = await fetch(url, {
+  const response = await send(url, {
     method: 'PUT',
-    headers: createHeaders(globalConfig.userId, globalConfig.isAdmin, globalConfig.token),
     body: body ? JSON.stringify(body) : undefined,
     ...options,
   });
@@ -287,9 +351,8 @@ export async function put<T, B = unknown>(path: string, body?: B, options?: Requ
 export async function del<T = void>(path: string, options?: RequestOptions): Promise<T> {
   const url = buildUrl(path, options?.params);
 
-  const response = await fetch(url, {
+  const response = await send(url, {
     method: 'DELETE',
-    headers: createHeaders(globalConfig.userId, globalConfig.isAdmin, globalConfig.token),
     ...options,
   });
 
@@ -26,6 +26,9 @@ export interface UserResponse {
 export interface LoginResponse {
   token: string;
   expiresAt: string;
+  /** Single-use token for a new session token once this one expires */
+  refreshToken?: string;
+  refreshExpiresAt?: string;
   user: UserResponse;
 }
 
@@ -3,7 +3,13 @@ import { configureClient } from '../api/client';
 import { onUnauthorized, clearUnauthorizedHandler } from '../api/auth-error-handler';
 import { queryClient } from '../lib/query';
 import * as authApi from '../api/endpoints/auth';
-import { AUTH_STORAGE_KEY, TOKEN_STORAGE_KEY, type AuthContextValue, type AuthProviderProps } from './auth-types';
+import {
+  AUTH_STORAGE_KEY,
+  REFRESH_TOKEN_STORAGE_KEY,
+  TOKEN_STORAGE_KEY,
+  type AuthContextValue,
+  type AuthProviderProps,
+} from './auth-types';
 import { AuthContext } from './auth-context';
 
 function generateUserId(): string {
@@ -44,8 +50,9 @@ export function AuthProvider({ children }: AuthProviderProps) {
       } catch {
         // Token is invalid, clear it
         localStorage.removeItem(TOKEN_STORAGE_KEY);
+        localStorage.removeItem(REFRESH_TOKEN_STORAGE_KEY);
         localStorage.removeItem(AUTH_STORAGE_KEY);
-        configureClient({ token: undefined, userId: undefined });
+        configureClient({ token: undefined, refreshToken: undefined, userId: undefined });
         setUserId(null);
       }
 
@@ -58,24 +65,30 @@ export function AuthProvider({ children }: AuthProviderProps) {
   const loginWithId = useCallback((newUserId: string) => {
     localStorage.setItem(AUTH_STORAGE_KEY, newUserId);
     localStorage.removeItem(TOKEN_STORAGE_KEY); // Clear any existing token
+    localStorage.removeItem(REFRESH_TOKEN_STORAGE_KEY);
     setUserId(newUserId);
     setEmail(null);
-    configureClient({ userId: newUserId, token: undefined });
+    configureClient({ userId: newUserId, token: undefined, refreshToken: undefined });
   }, []);
 
   const loginWithCredentials = useCallback(async (emailInput: string, password: string) => {
     const response = await authApi.login({ email: emailInput, password });
 
-    // Store token and userId
+    // Store tokens and userId
     localStorage.setItem(TOKEN_STORAGE_KEY, response.token);
+    if (response.refreshToken) {
+      localStorage.setItem(REFRESH_TOKEN_STORAGE_KEY, response.refreshToken);
+    } else {
+      localStorage.removeItem(REFRESH_TOKEN_STORAGE_KEY);
+    }
     localStorage.setItem(AUTH_STORAGE_KEY, response.user.id);
 
     // Update state
     setUserId(response.user.id);
     setEmail(response.user.email);
 
-    // Configure client with both userId and token
-    configureClient({ userId: response.user.id, token: response.token });
+    // Configure client with userId and tokens
+    configureClient({ userId: response.user.id, token: response.token, refreshToken: response.refreshToken });
   }, []);
 
   const registerUser = useCallback(async (emailInput: string, password: string, name?: string) => {
@@ -97,9 +110,10 @@ export function AuthProvider({ children }: AuthProviderProps) {
     // Clear storage
     localStorage.removeItem(AUTH_STORAGE_KEY);
     localStorage.removeItem(TOKEN_STORAGE_KEY);
+    localStorage.removeItem(REFRESH_TOKEN_STORAGE_KEY);
 
     // Clear client config
-    configureClient({ userId: undefined, token: undefined });
+    configureClient({ userId: undefined, token: undefined, refreshToken: undefined });
 
     // Clear query cache
     queryClient.clear();
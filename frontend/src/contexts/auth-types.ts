@@ -2,6 +2,7 @@ import type { ReactNode } from 'react';
 
 export const AUTH_STORAGE_KEY = 'powerpro_user_id';
 export const TOKEN_STORAGE_KEY = 'powerpro_token';
+export const REFRESH_TOKEN_STORAGE_KEY = 'powerpro_refresh_token';
 
 export interface AuthState {
   userId: string | null;
@@ -0,0 +1,342 @@
+// Package analytics provides training load analytics for coaches and lifters.
+// Logged sets are aggregated in SQL into per-lift and total series of tonnage,
+// hard sets, relative intensity and INOL, with the acute:chronic workload ratio
+// computed from daily tonnage.
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/domain/trainingload"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/profile"
+)
+
+// defaultRangeWeeks is the range covered when no start date is given.
+const defaultRangeWeeks = 12
+
+// bucketExpressions maps each bucket to the SQLite expression for the period start of a set.
+var bucketExpressions = map[trainingload.Bucket]string{
+	trainingload.BucketDay:   "date(ls.created_at)",
+	trainingload.BucketWeek:  "date(ls.created_at, '-6 days', 'weekday 1')",
+	trainingload.BucketMonth: "date(ls.created_at, 'start of month')",
+}
+
+// Query holds the parameters for an analytics request.
+type Query struct {
+	// Start is the beginning of the range (inclusive). Zero means 12 weeks before End.
+	Start time.Time
+	// End is the end of the range (inclusive). Zero means now.
+	End time.Time
+	// Bucket is the period size for the series. Empty means WEEK.
+	Bucket trainingload.Bucket
+	// LiftID restricts the analytics to a single lift.
+	LiftID *string
+}
+
+// Point holds the aggregated load for one period.
+type Point struct {
+	PeriodStart time.Time `json:"periodStart"`
+	Sets        int       `json:"sets"`
+	Reps        int       `json:"reps"`
+	Tonnage     float64   `json:"tonnage"`
+	HardSets    int       `json:"hardSets"`
+	// AvgRelativeIntensity is the mean fraction of 1RM across sets with a 1RM in effect.
+	AvgRelativeIntensity *float64 `json:"avgRelativeIntensity"`
+	// INOL is the summed INOL of sets with a 1RM in effect.
+	INOL *float64 `json:"inol"`
+	// ACWR is the acute:chronic workload ratio on the last day of the period.
+	ACWR *float64 `json:"acwr"`
+}
+
+// LiftSeries is the series for a single lift.
+type LiftSeries struct {
+	LiftID   string  `json:"liftId"`
+	LiftName string  `json:"liftName"`
+	LiftSlug string  `json:"liftSlug"`
+	Series   []Point `json:"series"`
+}
+
+// Analytics is the complete analytics response.
+type Analytics struct {
+	Start  time.Time    `json:"start"`
+	End    time.Time    `json:"end"`
+	Bucket string       `json:"bucket"`
+	Unit   string       `json:"unit"`
+	Totals []Point      `json:"totals"`
+	Lifts  []LiftSeries `json:"lifts"`
+}
+
+// Service provides training analytics operations.
+type Service struct {
+	db             *sql.DB
+	profileService *profile.Service
+	now            func() time.Time
+}
+
+// NewService creates a new analytics service.
+func NewService(sqlDB *sql.DB, profileService *profile.Service) *Service {
+	return &Service{
+		db:             sqlDB,
+		profileService: profileService,
+		now:            time.Now,
+	}
+}
+
+// periodRow is one aggregated (lift, period) row. LiftID is empty for the all-lift totals.
+type periodRow struct {
+	liftID   string
+	liftName string
+	liftSlug string
+	period   time.Time
+	point    Point
+}
+
+// GetAnalytics computes the user's training load series for the query.
+// Every period in the range is present in each series, with zero values for periods
+// without sets. Relative intensity uses the ONE_RM in effect when each set was logged;
+// sets logged before any ONE_RM count toward tonnage and hard sets only.
+func (s *Service) GetAnalytics(ctx context.Context, userID string, q Query) (*Analytics, error) {
+	p, err := s.profileService.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.End.IsZero() {
+		q.End = s.now()
+	}
+	if q.Start.IsZero() {
+		q.Start = trainingload.Day(q.End).AddDate(0, 0, -7*defaultRangeWeeks+1)
+	}
+	if q.Bucket == "" {
+		q.Bucket = trainingload.BucketWeek
+	}
+	q.Start = q.Start.UTC()
+	q.End = q.End.UTC()
+
+	if !trainingload.ValidBuckets[q.Bucket] {
+		return nil, apperrors.NewValidation("bucket", trainingload.ErrInvalidBucket.Error())
+	}
+	if err := trainingload.ValidateRange(q.Start, q.End); err != nil {
+		return nil, apperrors.NewValidation("start", err.Error())
+	}
+
+	rows, err := s.aggregatePeriods(ctx, userID, q)
+	if err != nil {
+		return nil, err
+	}
+
+	// Daily tonnage from the start of the first chronic window, for ACWR
+	dailyStart := trainingload.Day(q.Start).AddDate(0, 0, -(trainingload.ChronicDays - 1))
+	daily, err := s.dailyTonnage(ctx, userID, dailyStart, q.End, q.LiftID)
+	if err != nil {
+		return nil, err
+	}
+
+	periods := trainingload.Periods(q.Start, q.End, q.Bucket)
+	lastDay := trainingload.Day(q.End)
+
+	// Build zero-filled series, then overlay the aggregated rows
+	newSeries := func(tonnage map[time.Time]float64) []Point {
+		series := make([]Point, len(periods))
+		for i, period := range periods {
+			end := trainingload.NextPeriod(period, q.Bucket).AddDate(0, 0, -1)
+			if end.After(lastDay) {
+				end = lastDay
+			}
+			series[i] = Point{
+				PeriodStart: period,
+				ACWR:        trainingload.ACWR(tonnage, end),
+			}
+		}
+		return series
+	}
+	index := make(map[time.Time]int, len(periods))
+	for i, period := range periods {
+		index[period] = i
+	}
+
+	result := &Analytics{
+		Start:  q.Start,
+		End:    q.End,
+		Bucket: string(q.Bucket),
+		Unit:   p.WeightUnit,
+		Totals: newSeries(daily[""]),
+		Lifts:  []LiftSeries{},
+	}
+
+	liftIndex := make(map[string]int)
+	for _, row := range rows {
+		i, ok := index[row.period]
+		if !ok {
+			continue
+		}
+		if row.liftID == "" {
+			row.point.ACWR = result.Totals[i].ACWR
+			result.Totals[i] = row.point
+			continue
+		}
+
+		li, ok := liftIndex[row.liftID]
+		if !ok {
+			li = len(result.Lifts)
+			liftIndex[row.liftID] = li
+			result.Lifts = append(result.Lifts, LiftSeries{
+				LiftID:   row.liftID,
+				LiftName: row.liftName,
+				LiftSlug: row.liftSlug,
+				Series:   newSeries(daily[row.liftID]),
+			})
+		}
+		row.point.ACWR = result.Lifts[li].Series[i].ACWR
+		result.Lifts[li].Series[i] = row.point
+	}
+
+	return result, nil
+}
+
+// aggregatePeriods aggregates the user's logged sets per lift and period, and per period
+// across all lifts. Rows are ordered by lift name then period; total rows come first.
+func (s *Service) aggregatePeriods(ctx context.Context, userID string, q Query) ([]periodRow, error) {
+	query := fmt.Sprintf(`
+		WITH scored AS (
+			SELECT
+				ls.lift_id,
+				l.name AS lift_name,
+				l.slug AS lift_slug,
+				%s AS period,
+				ls.weight,
+				ls.reps_performed,
+				ls.target_reps,
+				ls.is_amrap,
+				ls.rpe,
+				ls.weight / (
+					SELECT lm.value FROM lift_maxes lm
+					WHERE lm.user_id = ls.user_id AND lm.lift_id = ls.lift_id AND lm.type = 'ONE_RM'
+					  AND lm.effective_date <= ls.created_at AND lm.value > 0
+					ORDER BY lm.effective_date DESC
+					LIMIT 1
+				) AS intensity
+			FROM logged_sets ls
+			JOIN lifts l ON l.id = ls.lift_id
+			WHERE ls.user_id = ? AND ls.created_at >= ? AND ls.created_at <= ?
+			  AND (? IS NULL OR ls.lift_id = ?)
+		),
+		flagged AS (
+			SELECT *,
+				CASE WHEN rpe >= ? OR (rpe IS NULL AND (is_amrap OR reps_performed < target_reps OR intensity >= ?))
+					THEN 1 ELSE 0 END AS is_hard,
+				CASE WHEN intensity IS NOT NULL
+					THEN reps_performed / (100.0 * (1 - MIN(intensity, ?))) END AS inol
+			FROM scored
+		)
+		SELECT lift_id, MIN(lift_name), MIN(lift_slug), period,
+			COUNT(*), SUM(reps_performed), SUM(weight * reps_performed), SUM(is_hard), AVG(intensity), SUM(inol)
+		FROM flagged
+		GROUP BY lift_id, period
+		UNION ALL
+		SELECT '', '', '', period,
+			COUNT(*), SUM(reps_performed), SUM(weight * reps_performed), SUM(is_hard), AVG(intensity), SUM(inol)
+		FROM flagged
+		GROUP BY period
+		ORDER BY 2, 4
+	`, bucketExpressions[q.Bucket])
+
+	var liftID sql.NullString
+	if q.LiftID != nil {
+		liftID = sql.NullString{String: *q.LiftID, Valid: true}
+	}
+
+	rows, err := s.db.QueryContext(ctx, query,
+		userID, q.Start.Format(time.RFC3339), q.End.Format(time.RFC3339), liftID, liftID,
+		trainingload.HardSetMinRPE, trainingload.HardSetMinIntensity, trainingload.MaxINOLIntensity,
+	)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to aggregate training analytics", err)
+	}
+	defer rows.Close()
+
+	result := []periodRow{}
+	for rows.Next() {
+		var row periodRow
+		var period string
+		var intensity, inol sql.NullFloat64
+		if err := rows.Scan(
+			&row.liftID, &row.liftName, &row.liftSlug, &period,
+			&row.point.Sets, &row.point.Reps, &row.point.Tonnage, &row.point.HardSets, &intensity, &inol,
+		); err != nil {
+			return nil, apperrors.NewInternal("failed to aggregate training analytics", err)
+		}
+		row.period, err = time.Parse("2006-01-02", period)
+		if err != nil {
+			return nil, apperrors.NewInternal("failed to parse analytics period", err)
+		}
+		row.point.PeriodStart = row.period
+		row.point.Tonnage = round(row.point.Tonnage, 1)
+		if intensity.Valid {
+			v := round(intensity.Float64, 3)
+			row.point.AvgRelativeIntensity = &v
+		}
+		if inol.Valid {
+			v := round(inol.Float64, 2)
+			row.point.INOL = &v
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewInternal("failed to aggregate training analytics", err)
+	}
+	return result, nil
+}
+
+// dailyTonnage returns tonnage per UTC day, keyed by lift ID. The "" key holds the total across lifts.
+func (s *Service) dailyTonnage(ctx context.Context, userID string, start, end time.Time, liftID *string) (map[string]map[time.Time]float64, error) {
+	var filterLiftID sql.NullString
+	if liftID != nil {
+		filterLiftID = sql.NullString{String: *liftID, Valid: true}
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT lift_id, date(created_at) AS day, SUM(weight * reps_performed)
+		FROM logged_sets
+		WHERE user_id = ? AND created_at >= ? AND created_at <= ?
+		  AND (? IS NULL OR lift_id = ?)
+		GROUP BY lift_id, day
+	`, userID, start.Format(time.RFC3339), end.Format(time.RFC3339), filterLiftID, filterLiftID)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to aggregate daily tonnage", err)
+	}
+	defer rows.Close()
+
+	daily := map[string]map[time.Time]float64{"": {}}
+	for rows.Next() {
+		var lift, day string
+		var tonnage float64
+		if err := rows.Scan(&lift, &day, &tonnage); err != nil {
+			return nil, apperrors.NewInternal("failed to aggregate daily tonnage", err)
+		}
+		d, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			return nil, apperrors.NewInternal("failed to parse analytics day", err)
+		}
+		if daily[lift] == nil {
+			daily[lift] = map[time.Time]float64{}
+		}
+		daily[lift][d] += tonnage
+		daily[""][d] += tonnage
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewInternal("failed to aggregate daily tonnage", err)
+	}
+	return daily, nil
+}
+
+// round rounds v to the given number of decimal places.
+func round(v float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(v*scale) / scale
+}
@@ -0,0 +1,191 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waynenilsen/power-pro-v3/internal/database"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/trainingload"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/profile"
+)
+
+const (
+	squatID = "00000000-0000-0000-0000-000000000001"
+	benchID = "00000000-0000-0000-0000-000000000002"
+)
+
+type testEnv struct {
+	svc *Service
+	db  *sql.DB
+}
+
+func setupTestEnv(t *testing.T) (*testEnv, func()) {
+	sqlDB, cleanup, err := database.OpenTemp("../../migrations")
+	require.NoError(t, err)
+
+	profileService := profile.NewService(profile.NewSQLiteProfileRepository(sqlDB))
+	return &testEnv{
+		svc: NewService(sqlDB, profileService),
+		db:  sqlDB,
+	}, cleanup
+}
+
+func (e *testEnv) createUser(t *testing.T, userID string) {
+	_, err := e.db.Exec(`
+		INSERT INTO users (id, email, weight_unit, created_at, updated_at)
+		VALUES (?, ?, 'lb', datetime('now'), datetime('now'))
+	`, userID, userID+"@example.com")
+	require.NoError(t, err)
+}
+
+func (e *testEnv) createOneRM(t *testing.T, userID, liftID string, value float64, effectiveDate time.Time) {
+	ts := effectiveDate.Format(time.RFC3339)
+	_, err := e.db.Exec(`
+		INSERT INTO lift_maxes (id, user_id, lift_id, type, value, effective_date, created_at, updated_at)
+		VALUES (?, ?, ?, 'ONE_RM', ?, ?, ?, ?)
+	`, uuid.New().String(), userID, liftID, value, ts, ts, ts)
+	require.NoError(t, err)
+}
+
+func (e *testEnv) logSet(t *testing.T, userID, liftID string, weight float64, reps, targetReps int, rpe *float64, at time.Time) {
+	_, err := e.db.Exec(`
+		INSERT INTO logged_sets (id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, rpe, created_at)
+		VALUES (?, ?, ?, 'prescription', ?, 1, ?, ?, ?, FALSE, ?, ?)
+	`, uuid.New().String(), userID, uuid.New().String(), liftID, weight, targetReps, reps, rpe, at.Format(time.RFC3339))
+	require.NoError(t, err)
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestService_GetAnalytics(t *testing.T) {
+	env, cleanup := setupTestEnv(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	userID := "analytics-user"
+	env.createUser(t, userID)
+
+	// Monday 2024-01-01
+	monday := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	env.createOneRM(t, userID, squatID, 400, monday.AddDate(0, 0, -1))
+
+	// Week 1: squat 5x300 (75%) and a set at RPE 8; bench without a max
+	env.logSet(t, userID, squatID, 300, 5, 5, nil, monday)
+	env.logSet(t, userID, squatID, 320, 5, 5, floatPtr(8), monday.AddDate(0, 0, 2))
+	env.logSet(t, userID, benchID, 200, 5, 5, nil, monday.AddDate(0, 0, 2))
+	// Week 3: a failed squat set at 85%
+	env.logSet(t, userID, squatID, 340, 3, 5, nil, monday.AddDate(0, 0, 14))
+	// Outside the range
+	env.logSet(t, userID, squatID, 300, 5, 5, nil, monday.AddDate(0, 0, 30))
+
+	result, err := env.svc.GetAnalytics(ctx, userID, Query{
+		Start:  monday,
+		End:    monday.AddDate(0, 0, 20),
+		Bucket: trainingload.BucketWeek,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "WEEK", result.Bucket)
+	assert.Equal(t, "lb", result.Unit)
+	require.Len(t, result.Totals, 3)
+
+	t.Run("totals aggregate every lift per week", func(t *testing.T) {
+		week1 := result.Totals[0]
+		assert.Equal(t, 3, week1.Sets)
+		assert.Equal(t, 15, week1.Reps)
+		assert.Equal(t, 4100.0, week1.Tonnage)
+		assert.Equal(t, 1, week1.HardSets)
+
+		// Empty week is zero-filled
+		assert.Equal(t, 0, result.Totals[1].Sets)
+		assert.Nil(t, result.Totals[1].INOL)
+
+		assert.Equal(t, 1, result.Totals[2].HardSets)
+	})
+
+	t.Run("per-lift series uses the max in effect", func(t *testing.T) {
+		require.Len(t, result.Lifts, 2)
+		// Ordered by lift name: Bench Press, Squat
+		bench := result.Lifts[0]
+		squat := result.Lifts[1]
+		assert.Equal(t, "bench-press", bench.LiftSlug)
+		assert.Equal(t, "squat", squat.LiftSlug)
+
+		// Bench has no 1RM: tonnage only
+		assert.Equal(t, 1000.0, bench.Series[0].Tonnage)
+		assert.Nil(t, bench.Series[0].AvgRelativeIntensity)
+		assert.Nil(t, bench.Series[0].INOL)
+
+		week1 := squat.Series[0]
+		require.NotNil(t, week1.AvgRelativeIntensity)
+		// (0.75 + 0.8) / 2
+		assert.InDelta(t, 0.775, *week1.AvgRelativeIntensity, 0.001)
+		// 5/25 + 5/20
+		require.NotNil(t, week1.INOL)
+		assert.InDelta(t, 0.45, *week1.INOL, 0.001)
+
+		week3 := squat.Series[2]
+		assert.Equal(t, 1, week3.HardSets)
+		assert.InDelta(t, 0.85, *week3.AvgRelativeIntensity, 0.001)
+	})
+
+	t.Run("acwr compares the last week with the four-week average", func(t *testing.T) {
+		// Week 1 squat tonnage 3100 with no prior load: 3100 / (3100/4) = 4
+		require.NotNil(t, result.Lifts[1].Series[0].ACWR)
+		assert.Equal(t, 4.0, *result.Lifts[1].Series[0].ACWR)
+		// Week 2 has no acute load
+		require.NotNil(t, result.Lifts[1].Series[1].ACWR)
+		assert.Equal(t, 0.0, *result.Lifts[1].Series[1].ACWR)
+	})
+
+	t.Run("filters by lift and buckets by day", func(t *testing.T) {
+		liftID := squatID
+		daily, err := env.svc.GetAnalytics(ctx, userID, Query{
+			Start:  monday,
+			End:    monday.AddDate(0, 0, 6),
+			Bucket: trainingload.BucketDay,
+			LiftID: &liftID,
+		})
+		require.NoError(t, err)
+		assert.Len(t, daily.Totals, 7)
+		require.Len(t, daily.Lifts, 1)
+		assert.Equal(t, 1500.0, daily.Totals[0].Tonnage)
+		assert.Equal(t, 1600.0, daily.Totals[2].Tonnage)
+	})
+}
+
+func TestService_GetAnalytics_Validation(t *testing.T) {
+	env, cleanup := setupTestEnv(t)
+	defer cleanup()
+	ctx := context.Background()
+	env.createUser(t, "validation-user")
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := env.svc.GetAnalytics(ctx, "validation-user", Query{Start: start, End: start.AddDate(0, 0, -1)})
+	assert.True(t, apperrors.IsValidation(err))
+
+	_, err = env.svc.GetAnalytics(ctx, "validation-user", Query{Start: start, End: start.AddDate(5, 0, 0)})
+	assert.True(t, apperrors.IsValidation(err))
+
+	_, err = env.svc.GetAnalytics(ctx, "validation-user", Query{Bucket: "YEAR"})
+	assert.True(t, apperrors.IsValidation(err))
+
+	_, err = env.svc.GetAnalytics(ctx, "missing-user", Query{})
+	assert.True(t, apperrors.IsNotFound(err))
+
+	// Defaults: 12 weekly buckets ending now (13 when the range straddles a partial week)
+	result, err := env.svc.GetAnalytics(ctx, "validation-user", Query{})
+	require.NoError(t, err)
+	assert.Equal(t, "WEEK", result.Bucket)
+	assert.GreaterOrEqual(t, len(result.Totals), 12)
+	assert.LessOrEqual(t, len(result.Totals), 13)
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/auth"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+)
+
+// AccessTokenHandler handles HTTP requests for personal access tokens.
+type AccessTokenHandler struct {
+	tokenService *auth.AccessTokenService
+}
+
+// NewAccessTokenHandler creates a new AccessTokenHandler.
+func NewAccessTokenHandler(tokenService *auth.AccessTokenService) *AccessTokenHandler {
+	return &AccessTokenHandler{tokenService: tokenService}
+}
+
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *AccessTokenHandler) WithTx(tx *sql.Tx) *AccessTokenHandler {
+	txHandler := *h
+	txHandler.tokenService = h.tokenService.WithTx(tx)
+	return &txHandler
+}
+
+// CreateAccessTokenRequest represents the request body for creating an access token.
+type CreateAccessTokenRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// ExpiresAt is when the token stops working. Omit it for a token that never expires.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// AccessTokenResponse represents the API response format for an access token.
+type AccessTokenResponse struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Prefix string   `json:"prefix"`
+	Scopes []string `json:"scopes"`
+	// Token is only returned when the token is created.
+	Token      string     `json:"token,omitempty"`
+	Active     bool       `json:"active"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+func accessTokenToResponse(t *auth.AccessToken, now time.Time) AccessTokenResponse {
+	scopes := make([]string, len(t.Scopes))
+	for i, s := range t.Scopes {
+		scopes[i] = string(s)
+	}
+	return AccessTokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     scopes,
+		Active:     t.Active(now),
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		RevokedAt:  t.RevokedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+// Create handles POST /users/{userId}/access-tokens
+// Only the user can create their tokens; admins cannot create tokens for other users.
+func (h *AccessTokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if middleware.GetUserID(r) != userID {
+		writeDomainError(w, apperrors.NewForbidden("you can only create your own access tokens"))
+		return
+	}
+
+	var req CreateAccessTokenRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	scopes := make([]auth.Scope, len(req.Scopes))
+	for i, s := range req.Scopes {
+		scopes[i] = auth.Scope(s)
+	}
+	token, secret, err := h.tokenService.Create(r.Context(), userID, auth.CreateAccessTokenRequest{
+		Name:      req.Name,
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	resp := accessTokenToResponse(token, time.Now())
+	resp.Token = secret
+	writeData(w, http.StatusCreated, resp)
+}
+
+// List handles GET /users/{userId}/access-tokens
+func (h *AccessTokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	tokens, err := h.tokenService.List(r.Context(), r.PathValue("userId"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	now := time.Now()
+	data := make([]AccessTokenResponse, len(tokens))
+	for i := range tokens {
+		data[i] = accessTokenToResponse(&tokens[i], now)
+	}
+	writeData(w, http.StatusOK, data)
+}
+
+// Revoke handles DELETE /users/{userId}/access-tokens/{tokenId}
+func (h *AccessTokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	token, err := h.tokenService.Get(r.Context(), r.PathValue("tokenId"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if token.UserID != r.PathValue("userId") {
+		writeDomainError(w, apperrors.NewNotFound("access token", token.ID))
+		return
+	}
+
+	if _, err := h.tokenService.Revoke(r.Context(), token.ID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
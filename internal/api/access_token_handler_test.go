@@ -0,0 +1,124 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// AccessTokenTestResponse represents an access token in test responses.
+type AccessTokenTestResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Token      string     `json:"token"`
+	Active     bool       `json:"active"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+}
+
+// tokenRequest sends a request authenticated only by a bearer token and returns the status.
+func tokenRequest(t *testing.T, method, url string, body interface{}, token string) int {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestAccessTokenHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	user, other := "pat-user", "pat-other"
+	createLSTestUser(t, ts, user)
+	createLSTestUser(t, ts, other)
+	tokensURL := ts.URL("/users/" + user + "/access-tokens")
+
+	var token AccessTokenTestResponse
+	coachingRequest(t, http.MethodPost, tokensURL, map[string]interface{}{
+		"name": "Log export", "scopes": []string{"read:logs"},
+	}, user, http.StatusCreated, &token)
+	if token.Token == "" || token.Prefix != token.Token[:len(token.Prefix)] || !token.Active {
+		t.Fatalf("Unexpected token: %+v", token)
+	}
+
+	t.Run("tokens are created only by their owner", func(t *testing.T) {
+		resp, err := webhookRequest(http.MethodPost, tokensURL, map[string]interface{}{
+			"name": "Admin token", "scopes": []string{"read:logs"},
+		}, testutil.TestAdminID, true)
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status %d for an admin creating another user's token, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+		coachingRequest(t, http.MethodPost, tokensURL, map[string]interface{}{
+			"name": "Unknown", "scopes": []string{"write:everything"},
+		}, user, http.StatusBadRequest, nil)
+		coachingRequest(t, http.MethodGet, tokensURL, nil, other, http.StatusForbidden, nil)
+	})
+
+	t.Run("scopes limit the routes a token can use", func(t *testing.T) {
+		if status := tokenRequest(t, http.MethodGet, ts.URL("/users/"+user+"/logged-sets"), nil, token.Token); status != http.StatusOK {
+			t.Errorf("Expected read:logs token to read logged sets, got %d", status)
+		}
+		if status := tokenRequest(t, http.MethodGet, ts.URL("/users/"+other+"/logged-sets"), nil, token.Token); status != http.StatusForbidden {
+			t.Errorf("Expected token to be limited to its user's data, got %d", status)
+		}
+		if status := tokenRequest(t, http.MethodGet, ts.URL("/programs"), nil, token.Token); status != http.StatusForbidden {
+			t.Errorf("Expected read:logs token to be denied read:programs routes, got %d", status)
+		}
+		if status := tokenRequest(t, http.MethodPost, tokensURL, map[string]interface{}{
+			"name": "Escalation", "scopes": []string{"write:sets"},
+		}, token.Token); status != http.StatusForbidden {
+			t.Errorf("Expected tokens to be unable to create tokens, got %d", status)
+		}
+	})
+
+	t.Run("list shows last use without the token", func(t *testing.T) {
+		var tokens []AccessTokenTestResponse
+		coachingRequest(t, http.MethodGet, tokensURL, nil, user, http.StatusOK, &tokens)
+		if len(tokens) != 1 || tokens[0].Token != "" || tokens[0].LastUsedAt == nil {
+			t.Errorf("Unexpected token list: %+v", tokens)
+		}
+	})
+
+	t.Run("revoked tokens stop working", func(t *testing.T) {
+		coachingRequest(t, http.MethodDelete, ts.URL("/users/"+other+"/access-tokens/"+token.ID), nil, other, http.StatusNotFound, nil)
+		coachingRequest(t, http.MethodDelete, tokensURL+"/"+token.ID, nil, user, http.StatusNoContent, nil)
+
+		if status := tokenRequest(t, http.MethodGet, ts.URL("/users/"+user+"/logged-sets"), nil, token.Token); status != http.StatusUnauthorized {
+			t.Errorf("Expected revoked token to be rejected, got %d", status)
+		}
+
+		var tokens []AccessTokenTestResponse
+		coachingRequest(t, http.MethodGet, tokensURL, nil, user, http.StatusOK, &tokens)
+		if len(tokens) != 1 || tokens[0].Active || tokens[0].RevokedAt == nil {
+			t.Errorf("Expected the revoked token in the list, got %+v", tokens)
+		}
+	})
+}
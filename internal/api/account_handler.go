@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/waynenilsen/power-pro-v3/internal/auth"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+)
+
+// AccountHandler handles HTTP requests for password resets and email verification.
+type AccountHandler struct {
+	service *auth.AccountService
+}
+
+// NewAccountHandler creates a new AccountHandler.
+func NewAccountHandler(service *auth.AccountService) *AccountHandler {
+	return &AccountHandler{service: service}
+}
+
+// PasswordResetRequest represents the request body for requesting a password reset.
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+// ConfirmPasswordResetRequest represents the request body for setting a new password.
+type ConfirmPasswordResetRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// VerifyEmailRequest represents the request body for verifying an email address.
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// ChangeEmailRequest represents the request body for changing a user's email.
+type ChangeEmailRequest struct {
+	Email string `json:"email"`
+	// Password confirms the change for users who have one.
+	Password string `json:"password"`
+}
+
+// RequestPasswordReset handles POST /auth/password-reset
+// Always responds 202 Accepted, whether or not the email belongs to a user.
+func (h *AccountHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req PasswordResetRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+	if strings.TrimSpace(req.Email) == "" {
+		writeDomainError(w, apperrors.NewValidation("email", "email is required"))
+		return
+	}
+
+	if err := h.service.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ConfirmPasswordReset handles POST /auth/password-reset/confirm
+// Sets the new password and ends all of the user's sessions.
+func (h *AccountHandler) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req ConfirmPasswordResetRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	if err := h.service.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyEmail handles POST /auth/verify-email
+func (h *AccountHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	user, err := h.service.VerifyEmail(r.Context(), req.Token)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, userToResponse(user))
+}
+
+// SendEmailVerification handles POST /users/{userId}/email-verification
+func (h *AccountHandler) SendEmailVerification(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.SendEmailVerification(r.Context(), r.PathValue("userId")); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ChangeEmail handles PUT /users/{userId}/email
+// Only the user can change their email; it changes once the new address is verified.
+func (h *AccountHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if middleware.GetUserID(r) != userID {
+		writeDomainError(w, apperrors.NewForbidden("you can only change your own email"))
+		return
+	}
+
+	var req ChangeEmailRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	if err := h.service.ChangeEmail(r.Context(), userID, req.Email, req.Password); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
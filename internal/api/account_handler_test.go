@@ -0,0 +1,104 @@
+package api_test
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// mailedTokenPattern finds the token on its own line in messages sent without an app URL.
+var mailedTokenPattern = regexp.MustCompile(`\n\n(\S+)\n\n`)
+
+// mailedToken returns the token in the last message the server sent to the address.
+func mailedToken(t *testing.T, ts *testutil.TestServer, to string) string {
+	t.Helper()
+	msg, ok := ts.Mail.Last(to)
+	if !ok {
+		t.Fatalf("No message sent to %s", to)
+	}
+	match := mailedTokenPattern.FindStringSubmatch(msg.Body)
+	if match == nil {
+		t.Fatalf("No token in message %q", msg.Body)
+	}
+	return match[1]
+}
+
+func TestAccountHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	var user UserTestResponse
+	coachingRequest(t, http.MethodPost, ts.URL("/auth/register"), map[string]string{
+		"email": "reset@example.com", "password": "old-password",
+	}, "", http.StatusCreated, &user)
+	if user.EmailVerified {
+		t.Fatalf("Expected a new account to be unverified: %+v", user)
+	}
+
+	var login LoginTestResponse
+	coachingRequest(t, http.MethodPost, ts.URL("/auth/login"), map[string]string{
+		"email": "reset@example.com", "password": "old-password",
+	}, "", http.StatusOK, &login)
+
+	t.Run("password reset ends sessions", func(t *testing.T) {
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/password-reset"), map[string]string{"email": "nobody@example.com"}, "", http.StatusAccepted, nil)
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/password-reset"), map[string]string{"email": "reset@example.com"}, "", http.StatusAccepted, nil)
+		if len(ts.Mail.Messages()) != 1 {
+			t.Fatalf("Expected one message, got %d", len(ts.Mail.Messages()))
+		}
+		token := mailedToken(t, ts, "reset@example.com")
+
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/password-reset/confirm"), map[string]string{
+			"token": token, "password": "new-password",
+		}, "", http.StatusNoContent, nil)
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/password-reset/confirm"), map[string]string{
+			"token": token, "password": "newer-password",
+		}, "", http.StatusBadRequest, nil)
+
+		if status := tokenRequest(t, http.MethodGet, ts.URL("/auth/me"), nil, login.Token); status != http.StatusUnauthorized {
+			t.Errorf("Expected the old session to end with status %d, got %d", http.StatusUnauthorized, status)
+		}
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/login"), map[string]string{
+			"email": "reset@example.com", "password": "new-password",
+		}, "", http.StatusOK, &login)
+		if !login.User.EmailVerified {
+			t.Errorf("Expected a reset to verify the email: %+v", login.User)
+		}
+	})
+
+	t.Run("email verification and change", func(t *testing.T) {
+		coachingRequest(t, http.MethodPost, ts.URL("/users/"+user.ID+"/email-verification"), nil, user.ID, http.StatusConflict, nil)
+
+		changeURL := ts.URL("/users/" + user.ID + "/email")
+		coachingRequest(t, http.MethodPut, changeURL, map[string]string{
+			"email": "changed@example.com", "password": "new-password",
+		}, "someone-else", http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodPut, changeURL, map[string]string{
+			"email": "changed@example.com", "password": "wrong-password",
+		}, user.ID, http.StatusBadRequest, nil)
+		coachingRequest(t, http.MethodPut, changeURL, map[string]string{
+			"email": "changed@example.com", "password": "new-password",
+		}, user.ID, http.StatusAccepted, nil)
+
+		var verified UserTestResponse
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/verify-email"), map[string]string{
+			"token": mailedToken(t, ts, "changed@example.com"),
+		}, "", http.StatusOK, &verified)
+		if verified.Email != "changed@example.com" || !verified.EmailVerified {
+			t.Errorf("Unexpected user after verification: %+v", verified)
+		}
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/verify-email"), map[string]string{"token": "forged"}, "", http.StatusBadRequest, nil)
+	})
+}
+
+// UserTestResponse represents a user in account tests.
+type UserTestResponse struct {
+	ID            string `json:"id"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"emailVerified"`
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/waynenilsen/power-pro-v3/internal/analytics"
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/trainingload"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// AnalyticsHandler handles HTTP requests for training analytics.
+type AnalyticsHandler struct {
+	analyticsService *analytics.Service
+}
+
+// NewAnalyticsHandler creates a new AnalyticsHandler.
+func NewAnalyticsHandler(analyticsService *analytics.Service) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// Get handles GET /users/{userId}/analytics
+// Query parameters:
+//   - start: beginning of the range (ISO 8601, default 12 weeks before end)
+//   - end: end of the range, inclusive (ISO 8601, default now)
+//   - bucket: DAY, WEEK or MONTH (default WEEK)
+//   - liftId: restrict to a single lift
+func (h *AnalyticsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if userID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing user ID"))
+		return
+	}
+
+	// Authorization check: the user, their coach or an admin can view analytics
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
+		writeDomainError(w, apperrors.NewForbidden("you can only access your own analytics"))
+		return
+	}
+
+	query := r.URL.Query()
+	var q analytics.Query
+
+	start, err := ParseFilterDate(query, "start")
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if start != nil {
+		q.Start = *start
+	}
+
+	end, err := ParseFilterDateEndOfDay(query, "end")
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if end != nil {
+		q.End = *end
+	}
+
+	bucket, err := ParseFilterEnum(query, "bucket", []string{
+		string(trainingload.BucketDay),
+		string(trainingload.BucketWeek),
+		string(trainingload.BucketMonth),
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if bucket != nil {
+		q.Bucket = trainingload.Bucket(*bucket)
+	}
+
+	q.LiftID = ParseFilterString(query, "liftId")
+
+	result, err := h.analyticsService.GetAnalytics(r.Context(), userID, q)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, result)
+}
@@ -0,0 +1,97 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/analytics"
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// AnalyticsEnvelope wraps an analytics response.
+type AnalyticsEnvelope struct {
+	Data analytics.Analytics `json:"data"`
+}
+
+func TestAnalytics(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID := createTestUserForProfile(t, ts, "analytics@example.com", "password123", "Analytics User")
+	otherID := createTestUserForProfile(t, ts, "analytics-other@example.com", "password123", "Other User")
+
+	squatID := "00000000-0000-0000-0000-000000000001"
+	day := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	if _, err := ts.DB().Exec(`
+		INSERT INTO lift_maxes (id, user_id, lift_id, type, value, effective_date, created_at, updated_at)
+		VALUES (?, ?, ?, 'ONE_RM', 400, ?, ?, ?)
+	`, uuid.New().String(), userID, squatID, day.AddDate(0, 0, -7).Format(time.RFC3339), day.Format(time.RFC3339), day.Format(time.RFC3339)); err != nil {
+		t.Fatalf("Failed to create lift max: %v", err)
+	}
+	for i, weight := range []float64{300, 320} {
+		if _, err := ts.DB().Exec(`
+			INSERT INTO logged_sets (id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, created_at)
+			VALUES (?, ?, 'analytics-session', 'analytics-prescription', ?, ?, ?, 5, 5, FALSE, ?)
+		`, uuid.New().String(), userID, squatID, i+1, weight, day.Format(time.RFC3339)); err != nil {
+			t.Fatalf("Failed to create logged set: %v", err)
+		}
+	}
+
+	t.Run("returns weekly series for the range", func(t *testing.T) {
+		resp, err := userRequestBodyweight(http.MethodGet, ts.URL("/users/"+userID+"/analytics?start=2024-03-04&end=2024-03-17&bucket=week"), userID, "")
+		if err != nil {
+			t.Fatalf("Failed to get analytics: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			respBody, _ := io.ReadAll(resp.Body)
+			t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, respBody)
+		}
+
+		var envelope AnalyticsEnvelope
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if envelope.Data.Bucket != "WEEK" {
+			t.Errorf("Expected bucket WEEK, got %s", envelope.Data.Bucket)
+		}
+		if len(envelope.Data.Totals) != 2 {
+			t.Fatalf("Expected 2 weekly points, got %d", len(envelope.Data.Totals))
+		}
+		week := envelope.Data.Totals[0]
+		if week.Sets != 2 || week.Tonnage != 3100 {
+			t.Errorf("Expected 2 sets and 3100 tonnage, got %d sets and %f", week.Sets, week.Tonnage)
+		}
+		if week.INOL == nil || *week.INOL != 0.45 {
+			t.Errorf("Expected INOL 0.45, got %v", week.INOL)
+		}
+		if len(envelope.Data.Lifts) != 1 || envelope.Data.Lifts[0].LiftSlug != "squat" {
+			t.Errorf("Expected a single squat series, got %+v", envelope.Data.Lifts)
+		}
+	})
+
+	t.Run("invalid parameters return 400", func(t *testing.T) {
+		for _, query := range []string{"?bucket=year", "?start=yesterday", "?start=2024-03-10&end=2024-03-01"} {
+			resp, _ := userRequestBodyweight(http.MethodGet, ts.URL("/users/"+userID+"/analytics"+query), userID, "")
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", query, resp.StatusCode)
+			}
+		}
+	})
+
+	t.Run("other users cannot view analytics", func(t *testing.T) {
+		resp, _ := userRequestBodyweight(http.MethodGet, ts.URL("/users/"+userID+"/analytics"), otherID, "")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", resp.StatusCode)
+		}
+	})
+}
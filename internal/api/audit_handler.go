@@ -0,0 +1,312 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/waynenilsen/power-pro-v3/internal/audit"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+)
+
+// AuditHandler handles HTTP requests for the audit log.
+type AuditHandler struct {
+	service *audit.Service
+}
+
+// NewAuditHandler creates a new AuditHandler.
+func NewAuditHandler(service *audit.Service) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// List handles GET /admin/audit
+// Returns audit entries, newest first, optionally filtered by actorId, action, entityType,
+// entityId, userId (the user whose data changed), from and to.
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pg := ParsePagination(query)
+	from, err := ParseFilterDate(query, "from")
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	to, err := ParseFilterDateEndOfDay(query, "to")
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	filter := audit.Filter{
+		ActorID:       ParseFilterString(query, "actorId"),
+		Action:        ParseFilterString(query, "action"),
+		EntityType:    ParseFilterString(query, "entityType"),
+		EntityID:      ParseFilterString(query, "entityId"),
+		SubjectUserID: ParseFilterString(query, "userId"),
+		From:          from,
+		To:            to,
+	}
+
+	entries, total, err := h.service.List(r.Context(), filter, int64(pg.Limit), int64(pg.Offset))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writePaginatedData(w, http.StatusOK, entries, total, pg.Limit, pg.Offset)
+}
+
+// Auditor records audit entries for the mutations of wrapped handlers.
+type Auditor struct {
+	service *audit.Service
+}
+
+// NewAuditor creates a new Auditor.
+func NewAuditor(service *audit.Service) *Auditor {
+	return &Auditor{service: service}
+}
+
+// maxAuditedBodyBytes limits the request bodies read for an entity's ID.
+const maxAuditedBodyBytes = 1 << 20
+
+// errChangeFailed rolls back the transaction of a handler that did not succeed.
+var errChangeFailed = errors.New("change failed")
+
+// AuditedEntity wraps the handlers of H that change one type of entity. Each change is
+// made in a transaction, by the copy of the handler that withTx returns, and its entry is
+// recorded in the same transaction, so a change whose entry cannot be recorded is rolled
+// back. The entity is read with its GET handler in the transaction before and after the
+// change, and after a creation once its ID is known; the transaction holds the database's
+// write lock, so no other change can fall between the two reads. Entities without a GET
+// handler are recorded from the handler's response.
+type AuditedEntity[H any] struct {
+	auditor    *Auditor
+	entityType string
+	// idParam is the path parameter holding the entity's ID.
+	idParam string
+	// idField, when set, is the request body field holding the entity's ID instead.
+	idField string
+	// subjectParam, when set, is the path parameter holding the user whose data changes.
+	subjectParam string
+	withTx       func(tx *sql.Tx) H
+	get          func(H, http.ResponseWriter, *http.Request)
+	// onBehalfOnly skips changes users make to their own data.
+	onBehalfOnly bool
+}
+
+// AuditEntity describes an audited entity type changed by handlers of H. withTx returns a
+// copy of a handler that makes its changes in tx. get, which may be nil, must serve the
+// entity at a path with the same idParam as the wrapped handlers.
+func AuditEntity[H any](a *Auditor, entityType, idParam string, withTx func(tx *sql.Tx) H, get func(H, http.ResponseWriter, *http.Request)) *AuditedEntity[H] {
+	return &AuditedEntity[H]{auditor: a, entityType: entityType, idParam: idParam, withTx: withTx, get: get}
+}
+
+// OnBehalf returns a copy of the entity that does not record changes users make to their
+// own data. The user whose data changes comes from the entity's userId field, or from the
+// path with ForUser; changes to data no user owns are always recorded.
+func (e *AuditedEntity[H]) OnBehalf() *AuditedEntity[H] {
+	c := *e
+	c.onBehalfOnly = true
+	return &c
+}
+
+// ForUser returns a copy of the entity whose changes belong to the user in the path
+// parameter.
+func (e *AuditedEntity[H]) ForUser(param string) *AuditedEntity[H] {
+	c := *e
+	c.subjectParam = param
+	return &c
+}
+
+// IDFromBody returns a copy of the entity whose ID is the named field of the request
+// body, for routes that act on an entity named in the body rather than the path.
+func (e *AuditedEntity[H]) IDFromBody(field string) *AuditedEntity[H] {
+	c := *e
+	c.idField = field
+	return &c
+}
+
+// Create records the entities created by h.
+func (e *AuditedEntity[H]) Create(h func(H, http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return e.wrap(audit.ActionCreate, h)
+}
+
+// Update records the changes h makes to an entity. It also suits handlers that change an
+// entity's children, such as a day's prescriptions.
+func (e *AuditedEntity[H]) Update(h func(H, http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return e.wrap(audit.ActionUpdate, h)
+}
+
+// Delete records the entities deleted by h.
+func (e *AuditedEntity[H]) Delete(h func(H, http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return e.wrap(audit.ActionDelete, h)
+}
+
+func (e *AuditedEntity[H]) wrap(action string, h func(H, http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue(e.idParam)
+		if e.idField != "" {
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAuditedBodyBytes))
+			if err != nil {
+				writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			id = stringField(body, e.idField)
+		}
+
+		buf := &bufferedResponse{header: w.Header(), status: http.StatusOK}
+		err := e.auditor.service.Transact(r.Context(), func(tx *sql.Tx) (*audit.Entry, error) {
+			handler := e.withTx(tx)
+
+			var before json.RawMessage
+			if action != audit.ActionCreate {
+				before = e.snapshot(handler, r, id)
+			}
+
+			h(handler, buf, r)
+			if buf.status < 200 || buf.status >= 300 {
+				return nil, errChangeFailed
+			}
+
+			var after json.RawMessage
+			if action != audit.ActionDelete {
+				data := responseData(buf.body.Bytes())
+				if id == "" {
+					id = stringField(data, "id")
+				}
+				if after = e.snapshot(handler, r, id); after == nil {
+					after = data
+				}
+			}
+			if dryRun(after) {
+				// Dry runs change nothing
+				return nil, nil
+			}
+
+			actorID := middleware.GetUserID(r)
+			subject := e.subject(r, before, after)
+			if e.onBehalfOnly && subject != nil && *subject == actorID {
+				return nil, nil
+			}
+			return &audit.Entry{
+				ActorID:       actorID,
+				Action:        action,
+				EntityType:    e.entityType,
+				EntityID:      id,
+				SubjectUserID: subject,
+				Method:        r.Method,
+				Path:          r.URL.Path,
+				Before:        before,
+				After:         after,
+			}, nil
+		})
+		if err != nil && !errors.Is(err, errChangeFailed) {
+			// The change was rolled back with its entry
+			for key := range w.Header() {
+				w.Header().Del(key)
+			}
+			writeDomainError(w, apperrors.NewInternal("failed to record audit entry", err))
+			return
+		}
+		buf.writeTo(w)
+	}
+}
+
+// subject returns the user whose data changes: the user in the subject path parameter,
+// or else the entity's userId field.
+func (e *AuditedEntity[H]) subject(r *http.Request, before, after json.RawMessage) *string {
+	if e.subjectParam != "" {
+		if userID := r.PathValue(e.subjectParam); userID != "" {
+			return &userID
+		}
+	}
+	if userID := stringField(before, "userId"); userID != "" {
+		return &userID
+	}
+	if userID := stringField(after, "userId"); userID != "" {
+		return &userID
+	}
+	return nil
+}
+
+// snapshot reads the entity with the ID with the handler's GET method, or returns nil if
+// it cannot be read.
+func (e *AuditedEntity[H]) snapshot(handler H, r *http.Request, id string) json.RawMessage {
+	if e.get == nil || id == "" {
+		return nil
+	}
+	req := r.Clone(r.Context())
+	req.Method = http.MethodGet
+	req.Body = http.NoBody
+	req.ContentLength = 0
+	req.SetPathValue(e.idParam, id)
+	buf := &bufferedResponse{header: http.Header{}, status: http.StatusOK}
+	e.get(handler, buf, req)
+	if buf.status != http.StatusOK {
+		return nil
+	}
+	return responseData(buf.body.Bytes())
+}
+
+// bufferedResponse keeps a response in memory.
+type bufferedResponse struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if !b.wroteHeader {
+		b.status = status
+		b.wroteHeader = true
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(p)
+}
+
+// writeTo sends the buffered status and body. Headers are already in place when the
+// buffer shares the destination's header map.
+func (b *bufferedResponse) writeTo(w http.ResponseWriter) {
+	w.WriteHeader(b.status)
+	_, _ = w.Write(b.body.Bytes())
+}
+
+// responseData returns the data of a response in the standard envelope.
+func responseData(body []byte) json.RawMessage {
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || len(envelope.Data) == 0 || string(envelope.Data) == "null" {
+		return nil
+	}
+	return envelope.Data
+}
+
+// dryRun reports whether a response is marked as a dry run.
+func dryRun(doc json.RawMessage) bool {
+	var m struct {
+		DryRun bool `json:"dryRun"`
+	}
+	return len(doc) > 0 && json.Unmarshal(doc, &m) == nil && m.DryRun
+}
+
+// stringField returns a string field of a JSON object, or "" if it has none.
+func stringField(doc json.RawMessage, name string) string {
+	var m map[string]json.RawMessage
+	if len(doc) == 0 || json.Unmarshal(doc, &m) != nil {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(m[name], &s) != nil {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,261 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// AuditEntryTestResponse represents an audit log entry in test responses.
+type AuditEntryTestResponse struct {
+	ID            string                           `json:"id"`
+	ActorID       string                           `json:"actorId"`
+	Action        string                           `json:"action"`
+	EntityType    string                           `json:"entityType"`
+	EntityID      string                           `json:"entityId"`
+	SubjectUserID *string                          `json:"subjectUserId"`
+	Method        string                           `json:"method"`
+	Path          string                           `json:"path"`
+	Before        map[string]interface{}           `json:"before"`
+	After         map[string]interface{}           `json:"after"`
+	Changes       map[string]AuditChangeTestResult `json:"changes"`
+}
+
+// AuditChangeTestResult represents a changed field in test responses.
+type AuditChangeTestResult struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+func TestAuditHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	listAudit := func(t *testing.T, query string) ([]AuditEntryTestResponse, *PaginationMeta) {
+		t.Helper()
+		resp, err := webhookRequest(http.MethodGet, ts.URL("/admin/audit?"+query), nil, testutil.TestAdminID, true)
+		if err != nil {
+			t.Fatalf("Failed to list audit entries: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+		var envelope struct {
+			Data []AuditEntryTestResponse `json:"data"`
+			Meta *PaginationMeta          `json:"meta"`
+		}
+		json.NewDecoder(resp.Body).Decode(&envelope)
+		return envelope.Data, envelope.Meta
+	}
+
+	liftID := createLSTestLift(t, ts, "Audit Squat", "audit-squat")
+
+	t.Run("records catalog creations, updates and deletions", func(t *testing.T) {
+		coachingRequest(t, http.MethodPut, ts.URL("/lifts/"+liftID), map[string]interface{}{"name": "Audited Squat"},
+			testutil.TestAdminID, http.StatusForbidden, nil)
+		resp, err := webhookRequest(http.MethodPut, ts.URL("/lifts/"+liftID), map[string]interface{}{"name": "Audited Squat"}, testutil.TestAdminID, true)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("Failed to update lift: %v %v", err, resp)
+		}
+		resp.Body.Close()
+
+		entries, meta := listAudit(t, "entityType=lift&entityId="+liftID)
+		if len(entries) != 2 || meta.Total != 2 {
+			t.Fatalf("Expected 2 entries, got %+v", entries)
+		}
+		update, create := entries[0], entries[1]
+		if create.Action != "CREATE" || create.ActorID != testutil.TestAdminID || create.Before != nil || create.After["name"] != "Audit Squat" {
+			t.Errorf("Unexpected creation entry: %+v", create)
+		}
+		if create.Changes["slug"].After != "audit-squat" {
+			t.Errorf("Expected the creation to list every field, got %+v", create.Changes)
+		}
+		if update.Action != "UPDATE" || update.Method != http.MethodPut || update.Path != "/lifts/"+liftID {
+			t.Errorf("Unexpected update entry: %+v", update)
+		}
+		if update.Before["name"] != "Audit Squat" || update.After["name"] != "Audited Squat" {
+			t.Errorf("Expected the lift before and after the update, got %+v / %+v", update.Before, update.After)
+		}
+		if change, ok := update.Changes["name"]; !ok || change.Before != "Audit Squat" || change.After != "Audited Squat" {
+			t.Errorf("Expected the name change, got %+v", update.Changes)
+		}
+		if _, ok := update.Changes["slug"]; ok {
+			t.Errorf("Unchanged fields should not be listed, got %+v", update.Changes)
+		}
+
+		cycleID := createLSTestCycle(t, ts, "Audit Cycle")
+		resp, err = webhookRequest(http.MethodDelete, ts.URL("/cycles/"+cycleID), nil, testutil.TestAdminID, true)
+		if err != nil || resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("Failed to delete cycle: %v %v", err, resp)
+		}
+		resp.Body.Close()
+		entries, _ = listAudit(t, "entityType=cycle&action=DELETE&entityId="+cycleID)
+		if len(entries) != 1 || entries[0].Before["name"] != "Audit Cycle" || entries[0].After != nil {
+			t.Errorf("Expected the deleted cycle, got %+v", entries)
+		}
+	})
+
+	t.Run("failed requests are not recorded", func(t *testing.T) {
+		resp, err := webhookRequest(http.MethodPut, ts.URL("/lifts/missing"), map[string]interface{}{"name": "Nothing"}, testutil.TestAdminID, true)
+		if err != nil || resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("Expected 404: %v %v", err, resp)
+		}
+		resp.Body.Close()
+		if entries, _ := listAudit(t, "entityId=missing"); len(entries) != 0 {
+			t.Errorf("Expected no entries, got %+v", entries)
+		}
+	})
+
+	t.Run("records lift max changes made for another user only", func(t *testing.T) {
+		athlete := "audit-athlete"
+		createLSTestUser(t, ts, athlete)
+
+		var own struct {
+			ID string `json:"id"`
+		}
+		coachingRequest(t, http.MethodPost, ts.URL("/users/"+athlete+"/lift-maxes"), map[string]interface{}{
+			"liftId": liftID, "type": "ONE_RM", "value": 300,
+		}, athlete, http.StatusCreated, &own)
+		coachingRequest(t, http.MethodPut, ts.URL("/lift-maxes/"+own.ID), map[string]interface{}{"value": 305}, athlete, http.StatusOK, nil)
+		if entries, _ := listAudit(t, "entityType=lift_max"); len(entries) != 0 {
+			t.Fatalf("Expected users' own changes to go unrecorded, got %+v", entries)
+		}
+
+		resp, err := webhookRequest(http.MethodPut, ts.URL("/lift-maxes/"+own.ID), map[string]interface{}{"value": 310}, testutil.TestAdminID, true)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("Failed to update lift max: %v %v", err, resp)
+		}
+		resp.Body.Close()
+
+		entries, _ := listAudit(t, "userId="+athlete)
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 entry, got %+v", entries)
+		}
+		entry := entries[0]
+		if entry.EntityType != "lift_max" || entry.EntityID != own.ID || entry.SubjectUserID == nil || *entry.SubjectUserID != athlete {
+			t.Errorf("Unexpected entry: %+v", entry)
+		}
+		if change := entry.Changes["value"]; change.Before != float64(305) || change.After != float64(310) {
+			t.Errorf("Expected the value change, got %+v", entry.Changes)
+		}
+	})
+
+	t.Run("records admin webhooks and account changes made for another user", func(t *testing.T) {
+		webhook := createTestWebhook(t, ts.URL("/webhooks"), testutil.TestAdminID, true, map[string]interface{}{
+			"url": "http://hooks.test/audit", "eventTypes": []string{"WORKOUT_STARTED"},
+		})
+		entries, _ := listAudit(t, "entityType=webhook&entityId="+webhook.ID)
+		if len(entries) != 1 || entries[0].Action != "CREATE" || entries[0].After["url"] != "http://hooks.test/audit" {
+			t.Fatalf("Expected the admin webhook's creation, got %+v", entries)
+		}
+		if _, ok := entries[0].After["secret"]; ok {
+			t.Errorf("The webhook secret must not be recorded")
+		}
+
+		member := "audit-member"
+		createLSTestUser(t, ts, member)
+		deletionURL := ts.URL("/users/" + member + "/deletion")
+		resp, err := webhookRequest(http.MethodPost, deletionURL, map[string]interface{}{}, testutil.TestAdminID, true)
+		if err != nil || resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("Failed to schedule deletion: %v %v", err, resp)
+		}
+		resp.Body.Close()
+		coachingRequest(t, http.MethodDelete, deletionURL, nil, member, http.StatusNoContent, nil)
+
+		entries, _ = listAudit(t, "entityType=account_deletion&userId="+member)
+		if len(entries) != 1 || entries[0].Action != "CREATE" || entries[0].ActorID != testutil.TestAdminID || entries[0].After == nil {
+			t.Errorf("Expected only the admin's scheduling, got %+v", entries)
+		}
+	})
+
+	t.Run("records progressions triggered and reverted for another user", func(t *testing.T) {
+		userID, liftID, progressionID, _ := setupManualTriggerTestData(t, ts)
+		triggerURL := ts.URL("/users/" + userID + "/progressions/trigger")
+		for _, dryRun := range []bool{true, false} {
+			resp, err := webhookRequest(http.MethodPost, triggerURL, map[string]interface{}{
+				"progressionId": progressionID, "liftId": liftID, "dryRun": dryRun,
+			}, testutil.TestAdminID, true)
+			if err != nil || resp.StatusCode != http.StatusOK {
+				t.Fatalf("Failed to trigger progression: %v %v", err, resp)
+			}
+			resp.Body.Close()
+		}
+
+		entries, _ := listAudit(t, "entityType=manual_progression&userId="+userID)
+		if len(entries) != 1 || entries[0].EntityID != progressionID || entries[0].Path != "/users/"+userID+"/progressions/trigger" {
+			t.Fatalf("Expected only the applied trigger, got %+v", entries)
+		}
+
+		var history []struct {
+			ID string `json:"id"`
+		}
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+userID+"/progression-history?limit=1"), nil, userID, http.StatusOK, &history)
+		if len(history) != 1 {
+			t.Fatalf("Expected the applied progression, got %+v", history)
+		}
+		resp, err := webhookRequest(http.MethodPost, ts.URL("/users/"+userID+"/progression-history/"+history[0].ID+"/revert"), nil, testutil.TestAdminID, true)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("Failed to revert progression: %v %v", err, resp)
+		}
+		resp.Body.Close()
+
+		entries, _ = listAudit(t, "entityType=progression_revert&entityId="+history[0].ID)
+		if len(entries) != 1 || entries[0].SubjectUserID == nil || *entries[0].SubjectUserID != userID || entries[0].After["revertedLogId"] != history[0].ID {
+			t.Errorf("Expected the revert, got %+v", entries)
+		}
+	})
+
+	t.Run("changes fail when their entry cannot be recorded", func(t *testing.T) {
+		if _, err := ts.DB().Exec(`ALTER TABLE audit_log RENAME TO audit_log_unavailable`); err != nil {
+			t.Fatalf("Failed to hide the audit log: %v", err)
+		}
+		resp, err := webhookRequest(http.MethodPut, ts.URL("/lifts/"+liftID), map[string]interface{}{"name": "Unaudited Squat"}, testutil.TestAdminID, true)
+		if _, renameErr := ts.DB().Exec(`ALTER TABLE audit_log_unavailable RENAME TO audit_log`); renameErr != nil {
+			t.Fatalf("Failed to restore the audit log: %v", renameErr)
+		}
+		if err != nil {
+			t.Fatalf("Failed to update lift: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusInternalServerError {
+			t.Errorf("Expected status 500, got %d", resp.StatusCode)
+		}
+
+		var lift map[string]interface{}
+		coachingRequest(t, http.MethodGet, ts.URL("/lifts/"+liftID), nil, testutil.TestAdminID, http.StatusOK, &lift)
+		if lift["name"] != "Audited Squat" {
+			t.Errorf("Expected the update to be rolled back, got name %v", lift["name"])
+		}
+	})
+
+	t.Run("filters and pages entries", func(t *testing.T) {
+		entries, meta := listAudit(t, "actorId="+testutil.TestAdminID+"&limit=1")
+		if len(entries) != 1 || meta.Total < 3 || !meta.HasMore {
+			t.Errorf("Expected one page of the admin's entries, got %+v %+v", entries, meta)
+		}
+		if entries, _ := listAudit(t, "from=2000-01-01&to=2000-12-31"); len(entries) != 0 {
+			t.Errorf("Expected no entries in 2000, got %d", len(entries))
+		}
+
+		for _, query := range []string{"action=PATCH", "from=yesterday"} {
+			resp, err := webhookRequest(http.MethodGet, ts.URL("/admin/audit?"+query), nil, testutil.TestAdminID, true)
+			if err != nil {
+				t.Fatalf("Failed to list audit entries: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("%s: expected status 400, got %d", query, resp.StatusCode)
+			}
+		}
+	})
+
+	t.Run("only admins can read the log", func(t *testing.T) {
+		coachingRequest(t, http.MethodGet, ts.URL("/admin/audit"), nil, testutil.TestUserID, http.StatusForbidden, nil)
+	})
+}
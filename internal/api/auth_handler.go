@@ -35,30 +35,41 @@ type LoginRequest struct {
 
 // UserResponse represents the API response format for a user.
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID            string    `json:"id"`
+	Email         string    `json:"email"`
+	Name          string    `json:"name"`
+	EmailVerified bool      `json:"emailVerified"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
 }
 
 // LoginResponse represents the API response for a successful login.
 type LoginResponse struct {
-	Token     string       `json:"token"`
-	ExpiresAt time.Time    `json:"expiresAt"`
-	User      UserResponse `json:"user"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// RefreshToken gets a new token from POST /auth/refresh once this one expires.
+	// Omitted when session tokens last as long as the session.
+	RefreshToken     string       `json:"refreshToken,omitempty"`
+	RefreshExpiresAt *time.Time   `json:"refreshExpiresAt,omitempty"`
+	User             UserResponse `json:"user"`
 }
 
-// sessionDuration must match auth.sessionDuration (7 days)
-const sessionDuration = 7 * 24 * time.Hour
+// TwoFactorChallengeResponse represents the API response for a login that needs a
+// two-factor code before it gets a session.
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool      `json:"twoFactorRequired"`
+	Challenge         string    `json:"challenge"`
+	ExpiresAt         time.Time `json:"expiresAt"`
+}
 
 func userToResponse(u *auth.User) UserResponse {
 	return UserResponse{
-		ID:        u.ID,
-		Email:     u.Email,
-		Name:      u.Name,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:            u.ID,
+		Email:         u.Email,
+		Name:          u.Name,
+		EmailVerified: u.EmailVerifiedAt != nil,
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
 	}
 }
 
@@ -111,23 +122,41 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.service.Login(r.Context(), auth.LoginRequest{
+	result, err := h.service.Login(clientContext(r), auth.LoginRequest{
 		Email:    req.Email,
 		Password: req.Password,
+		ClientIP: middleware.ClientIP(r),
 	})
 	if err != nil {
 		writeDomainError(w, err)
 		return
 	}
 
-	// Calculate expiration time
-	expiresAt := time.Now().Add(sessionDuration)
+	writeLoginResult(w, result)
+}
+
+// writeLoginResult writes a new session, or the challenge of a user with two-factor
+// authentication.
+func writeLoginResult(w http.ResponseWriter, result *auth.LoginResult) {
+	if result.Challenge != "" {
+		writeData(w, http.StatusOK, TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			Challenge:         result.Challenge,
+			ExpiresAt:         result.ChallengeExpiresAt,
+		})
+		return
+	}
 
-	writeData(w, http.StatusOK, LoginResponse{
+	resp := LoginResponse{
 		Token:     result.Token,
-		ExpiresAt: expiresAt,
+		ExpiresAt: result.ExpiresAt,
 		User:      userToResponse(result.User),
-	})
+	}
+	if result.RefreshToken != "" {
+		resp.RefreshToken = result.RefreshToken
+		resp.RefreshExpiresAt = &result.RefreshExpiresAt
+	}
+	writeData(w, http.StatusOK, resp)
 }
 
 // Logout handles POST /auth/logout
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/auth"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+)
+
+// RefreshRequest represents the request body for refreshing a session token.
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// SessionResponse represents the API response format for a session.
+type SessionResponse struct {
+	ID string `json:"id"`
+	// Device describes the browser and operating system, when the user agent is recognized.
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	Current    bool      `json:"current"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// RevokeSessionsResponse represents the API response for revoking other sessions.
+type RevokeSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+// clientContext returns the request context carrying the client that sessions started
+// by the request record.
+func clientContext(r *http.Request) context.Context {
+	return auth.WithClient(r.Context(), auth.Client{
+		IPAddress: middleware.ClientIP(r),
+		UserAgent: r.UserAgent(),
+	})
+}
+
+func sessionToResponse(s auth.Session, currentID string) SessionResponse {
+	lastSeen := s.LastSeenAt
+	if lastSeen.IsZero() {
+		lastSeen = s.CreatedAt
+	}
+	return SessionResponse{
+		ID:         s.ID,
+		Device:     auth.DeviceName(s.UserAgent),
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		Current:    s.ID == currentID,
+		LastSeenAt: lastSeen,
+		ExpiresAt:  s.ExpiresAt,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+// Refresh handles POST /auth/refresh
+// Exchanges a refresh token for a new session token and refresh token.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	result, err := h.service.Refresh(clientContext(r), req.RefreshToken)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeLoginResult(w, result)
+}
+
+// ListSessions handles GET /auth/sessions
+// Lists the user's active sessions, marking the one making the request.
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := h.service.ListSessions(r.Context(), middleware.GetUserID(r))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	currentID := h.currentSessionID(r)
+	resp := make([]SessionResponse, len(sessions))
+	for i, s := range sessions {
+		resp[i] = sessionToResponse(s, currentID)
+	}
+	writeData(w, http.StatusOK, resp)
+}
+
+// RevokeSession handles DELETE /auth/sessions/{sessionId}
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RevokeSession(r.Context(), middleware.GetUserID(r), r.PathValue("sessionId")); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeOtherSessions handles DELETE /auth/sessions/others
+// Signs the user out everywhere except the session making the request.
+func (h *AuthHandler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	revoked, err := h.service.RevokeOtherSessions(r.Context(), middleware.GetUserID(r), h.currentSessionID(r))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, RevokeSessionsResponse{Revoked: revoked})
+}
+
+// currentSessionID returns the ID of the session whose token authenticated the request,
+// or "" for requests authenticated otherwise.
+func (h *AuthHandler) currentSessionID(r *http.Request) string {
+	token := extractBearerToken(r)
+	if token == "" {
+		return ""
+	}
+	session, err := h.service.CurrentSession(r.Context(), token)
+	if err != nil {
+		return ""
+	}
+	return session.ID
+}
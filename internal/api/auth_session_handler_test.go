@@ -0,0 +1,141 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/auth"
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+type sessionLoginResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+type sessionTestResponse struct {
+	ID        string `json:"id"`
+	Device    string `json:"device"`
+	IPAddress string `json:"ipAddress"`
+	Current   bool   `json:"current"`
+}
+
+// sessionRequest sends a request with a session token and user agent and decodes the data
+// of a successful response into out.
+func sessionRequest(t *testing.T, method, url string, body interface{}, token, userAgent string, wantStatus int, out interface{}) {
+	t.Helper()
+	payload, _ := json.Marshal(body)
+	req, err := http.NewRequest(method, url, bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("%s %s: expected status %d, got %d", method, url, wantStatus, resp.StatusCode)
+	}
+	if out != nil {
+		envelope := struct {
+			Data interface{} `json:"data"`
+		}{Data: out}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+	}
+}
+
+func TestSessionHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer(testutil.WithSessions(auth.SessionConfig{TokenLifetime: 15 * time.Minute, Sliding: true}))
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	coachingRequest(t, http.MethodPost, ts.URL("/auth/register"), map[string]string{
+		"email": "sessions@example.com", "password": "password123",
+	}, "", http.StatusCreated, nil)
+	credentials := map[string]string{"email": "sessions@example.com", "password": "password123"}
+	const firefox = "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0"
+	const safari = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+
+	var laptop, phone sessionLoginResponse
+	sessionRequest(t, http.MethodPost, ts.URL("/auth/login"), credentials, "", firefox, http.StatusOK, &laptop)
+	sessionRequest(t, http.MethodPost, ts.URL("/auth/login"), credentials, "", safari, http.StatusOK, &phone)
+	if laptop.RefreshToken == "" {
+		t.Fatal("Expected a refresh token")
+	}
+
+	t.Run("listing sessions", func(t *testing.T) {
+		var sessions []sessionTestResponse
+		sessionRequest(t, http.MethodGet, ts.URL("/auth/sessions"), nil, laptop.Token, firefox, http.StatusOK, &sessions)
+		if len(sessions) != 2 {
+			t.Fatalf("Expected 2 sessions, got %+v", sessions)
+		}
+		devices := map[string]bool{}
+		for _, s := range sessions {
+			devices[s.Device] = s.Current
+			if s.IPAddress == "" {
+				t.Errorf("Expected an IP address for %+v", s)
+			}
+		}
+		if current, ok := devices["Firefox on Windows"]; !ok || !current {
+			t.Errorf("Expected the laptop session to be current, got %+v", sessions)
+		}
+		if current, ok := devices["Safari on iOS"]; !ok || current {
+			t.Errorf("Expected the phone session not to be current, got %+v", sessions)
+		}
+	})
+
+	t.Run("refreshing rotates tokens", func(t *testing.T) {
+		var refreshed sessionLoginResponse
+		sessionRequest(t, http.MethodPost, ts.URL("/auth/refresh"), map[string]string{"refreshToken": phone.RefreshToken}, "", safari, http.StatusOK, &refreshed)
+		if refreshed.Token == phone.Token || refreshed.RefreshToken == phone.RefreshToken {
+			t.Fatalf("Expected new tokens, got %+v", refreshed)
+		}
+		sessionRequest(t, http.MethodGet, ts.URL("/auth/me"), nil, refreshed.Token, safari, http.StatusOK, nil)
+		sessionRequest(t, http.MethodGet, ts.URL("/auth/me"), nil, phone.Token, safari, http.StatusUnauthorized, nil)
+
+		// Reuse of the old refresh token ends the session
+		sessionRequest(t, http.MethodPost, ts.URL("/auth/refresh"), map[string]string{"refreshToken": phone.RefreshToken}, "", safari, http.StatusUnauthorized, nil)
+		sessionRequest(t, http.MethodGet, ts.URL("/auth/me"), nil, refreshed.Token, safari, http.StatusUnauthorized, nil)
+	})
+
+	t.Run("revoking sessions", func(t *testing.T) {
+		var other sessionLoginResponse
+		sessionRequest(t, http.MethodPost, ts.URL("/auth/login"), credentials, "", safari, http.StatusOK, &other)
+
+		var sessions []sessionTestResponse
+		sessionRequest(t, http.MethodGet, ts.URL("/auth/sessions"), nil, laptop.Token, firefox, http.StatusOK, &sessions)
+		var otherID string
+		for _, s := range sessions {
+			if !s.Current {
+				otherID = s.ID
+			}
+		}
+		sessionRequest(t, http.MethodDelete, ts.URL("/auth/sessions/"+otherID), nil, laptop.Token, firefox, http.StatusNoContent, nil)
+		sessionRequest(t, http.MethodGet, ts.URL("/auth/me"), nil, other.Token, safari, http.StatusUnauthorized, nil)
+		sessionRequest(t, http.MethodDelete, ts.URL("/auth/sessions/"+otherID), nil, laptop.Token, firefox, http.StatusNotFound, nil)
+
+		sessionRequest(t, http.MethodPost, ts.URL("/auth/login"), credentials, "", safari, http.StatusOK, &other)
+		var revoked struct {
+			Revoked int `json:"revoked"`
+		}
+		sessionRequest(t, http.MethodDelete, ts.URL("/auth/sessions/others"), nil, laptop.Token, firefox, http.StatusOK, &revoked)
+		if revoked.Revoked != 1 {
+			t.Errorf("Expected 1 revoked session, got %d", revoked.Revoked)
+		}
+		sessionRequest(t, http.MethodGet, ts.URL("/auth/me"), nil, other.Token, safari, http.StatusUnauthorized, nil)
+		sessionRequest(t, http.MethodGet, ts.URL("/auth/me"), nil, laptop.Token, firefox, http.StatusOK, nil)
+	})
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/bodyweight"
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// BodyweightHandler handles HTTP requests for bodyweight tracking operations.
+type BodyweightHandler struct {
+	bodyweightService *bodyweight.Service
+}
+
+// NewBodyweightHandler creates a new BodyweightHandler.
+func NewBodyweightHandler(bodyweightService *bodyweight.Service) *BodyweightHandler {
+	return &BodyweightHandler{
+		bodyweightService: bodyweightService,
+	}
+}
+
+// LogBodyweightRequest represents the request body for logging a bodyweight entry.
+type LogBodyweightRequest struct {
+	Weight     float64    `json:"weight"`
+	Unit       string     `json:"unit,omitempty"`
+	RecordedAt *time.Time `json:"recordedAt,omitempty"`
+	Notes      *string    `json:"notes,omitempty"`
+}
+
+// BodyweightEntryResponse represents the API response format for a bodyweight entry.
+type BodyweightEntryResponse struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"userId"`
+	Weight     float64   `json:"weight"`
+	Unit       string    `json:"unit"`
+	RecordedAt time.Time `json:"recordedAt"`
+	Notes      *string   `json:"notes"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func bodyweightEntryToResponse(e *bodyweight.Entry) BodyweightEntryResponse {
+	return BodyweightEntryResponse{
+		ID:         e.ID,
+		UserID:     e.UserID,
+		Weight:     e.Weight,
+		Unit:       e.Unit,
+		RecordedAt: e.RecordedAt,
+		Notes:      e.Notes,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+// authorizeBodyweightAccess checks that the caller is the user, an admin, or a coach with
+// the permission. An empty permission allows only the user and admins.
+func authorizeBodyweightAccess(r *http.Request, userID string, permission coaching.Permission) error {
+	if userID == "" {
+		return apperrors.NewBadRequest("missing user ID")
+	}
+	if !canAccessUser(r, userID, permission) {
+		return apperrors.NewForbidden("you can only access your own bodyweight data")
+	}
+	return nil
+}
+
+// Create handles POST /users/{userId}/bodyweight
+func (h *BodyweightHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if err := authorizeBodyweightAccess(r, userID, ""); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	var req LogBodyweightRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	entry, err := h.bodyweightService.LogEntry(r.Context(), userID, bodyweight.LogEntryRequest{
+		Weight:     req.Weight,
+		Unit:       req.Unit,
+		RecordedAt: req.RecordedAt,
+		Notes:      req.Notes,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusCreated, bodyweightEntryToResponse(entry))
+}
+
+// List handles GET /users/{userId}/bodyweight
+func (h *BodyweightHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if err := authorizeBodyweightAccess(r, userID, coaching.PermissionViewLogs); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	pg := ParsePagination(r.URL.Query())
+
+	entries, total, err := h.bodyweightService.ListEntries(r.Context(), userID, int64(pg.Limit), int64(pg.Offset))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	data := make([]BodyweightEntryResponse, len(entries))
+	for i, e := range entries {
+		data[i] = bodyweightEntryToResponse(&e)
+	}
+
+	writePaginatedData(w, http.StatusOK, data, total, pg.Limit, pg.Offset)
+}
+
+// Delete handles DELETE /users/{userId}/bodyweight/{entryId}
+func (h *BodyweightHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if err := authorizeBodyweightAccess(r, userID, ""); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	entryID := r.PathValue("entryId")
+	if entryID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing entry ID"))
+		return
+	}
+
+	if err := h.bodyweightService.DeleteEntry(r.Context(), userID, entryID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSummary handles GET /users/{userId}/bodyweight/summary
+func (h *BodyweightHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if err := authorizeBodyweightAccess(r, userID, coaching.PermissionViewLogs); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	summary, err := h.bodyweightService.GetSummary(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, summary)
+}
+
+// GetTrend handles GET /users/{userId}/bodyweight/trend
+func (h *BodyweightHandler) GetTrend(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if err := authorizeBodyweightAccess(r, userID, coaching.PermissionViewLogs); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	points, err := h.bodyweightService.GetTrend(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, points)
+}
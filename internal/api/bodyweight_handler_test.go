@@ -0,0 +1,272 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/waynenilsen/power-pro-v3/internal/api"
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// BodyweightEntryEnvelope wraps a bodyweight entry response.
+type BodyweightEntryEnvelope struct {
+	Data api.BodyweightEntryResponse `json:"data"`
+}
+
+// BodyweightListEnvelope wraps a paginated bodyweight list response.
+type BodyweightListEnvelope struct {
+	Data []api.BodyweightEntryResponse `json:"data"`
+	Meta *api.Meta                     `json:"meta"`
+}
+
+// BodyweightSummaryEnvelope wraps a bodyweight summary response.
+type BodyweightSummaryEnvelope struct {
+	Data struct {
+		Unit               string   `json:"unit"`
+		EntryCount         int      `json:"entryCount"`
+		LatestWeight       *float64 `json:"latestWeight"`
+		TrendWeight        *float64 `json:"trendWeight"`
+		CurrentWeightClass *string  `json:"currentWeightClass"`
+		WeightClassTarget  *string  `json:"weightClassTarget"`
+		WeightClassLimit   *float64 `json:"weightClassLimit"`
+		DistanceToLimit    *float64 `json:"distanceToLimit"`
+	} `json:"data"`
+}
+
+// StrengthScoresEnvelope wraps a strength scores response.
+type StrengthScoresEnvelope struct {
+	Data api.StrengthScoresWithHistoryResponse `json:"data"`
+}
+
+// userRequestBodyweight performs a request with X-User-ID header (test mode auth).
+func userRequestBodyweight(method, url, userID, body string) (*http.Response, error) {
+	var reader io.Reader
+	if body != "" {
+		reader = bytes.NewBufferString(body)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-User-ID", userID)
+	return http.DefaultClient.Do(req)
+}
+
+func logBodyweight(t *testing.T, ts *testutil.TestServer, userID, body string) api.BodyweightEntryResponse {
+	t.Helper()
+	resp, err := userRequestBodyweight(http.MethodPost, ts.URL("/users/"+userID+"/bodyweight"), userID, body)
+	if err != nil {
+		t.Fatalf("Failed to log bodyweight: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Expected status 201, got %d: %s", resp.StatusCode, respBody)
+	}
+	var envelope BodyweightEntryEnvelope
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return envelope.Data
+}
+
+func TestBodyweightCRUD(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID := createTestUserForProfile(t, ts, "bodyweight-crud@example.com", "password123", "Bodyweight User")
+	otherID := createTestUserForProfile(t, ts, "bodyweight-other@example.com", "password123", "Other User")
+
+	t.Run("log entry defaults unit to profile preference", func(t *testing.T) {
+		entry := logBodyweight(t, ts, userID, `{"weight": 198.4, "recordedAt": "2024-01-01T07:00:00Z", "notes": "fasted"}`)
+		if entry.Unit != "lb" {
+			t.Errorf("Expected unit lb, got %s", entry.Unit)
+		}
+		if entry.Weight != 198.4 {
+			t.Errorf("Expected weight 198.4, got %f", entry.Weight)
+		}
+		if entry.Notes == nil || *entry.Notes != "fasted" {
+			t.Errorf("Expected notes 'fasted', got %v", entry.Notes)
+		}
+	})
+
+	t.Run("invalid weight returns 400", func(t *testing.T) {
+		resp, _ := userRequestBodyweight(http.MethodPost, ts.URL("/users/"+userID+"/bodyweight"), userID, `{"weight": 0}`)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("other users cannot log or view entries", func(t *testing.T) {
+		resp, _ := userRequestBodyweight(http.MethodPost, ts.URL("/users/"+userID+"/bodyweight"), otherID, `{"weight": 180}`)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", resp.StatusCode)
+		}
+
+		resp, _ = userRequestBodyweight(http.MethodGet, ts.URL("/users/"+userID+"/bodyweight"), otherID, "")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("list, summary and delete", func(t *testing.T) {
+		second := logBodyweight(t, ts, userID, `{"weight": 90, "unit": "kg", "recordedAt": "2024-01-02T07:00:00Z"}`)
+
+		resp, _ := userRequestBodyweight(http.MethodGet, ts.URL("/users/"+userID+"/bodyweight"), userID, "")
+		var list BodyweightListEnvelope
+		json.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if len(list.Data) != 2 || list.Meta == nil || *list.Meta.Total != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(list.Data))
+		}
+		if list.Data[0].ID != second.ID {
+			t.Errorf("Expected newest entry first")
+		}
+
+		resp, _ = userRequestBodyweight(http.MethodGet, ts.URL("/users/"+userID+"/bodyweight/summary"), userID, "")
+		var summary BodyweightSummaryEnvelope
+		json.NewDecoder(resp.Body).Decode(&summary)
+		resp.Body.Close()
+		if summary.Data.EntryCount != 2 || summary.Data.LatestWeight == nil {
+			t.Fatalf("Unexpected summary: %+v", summary.Data)
+		}
+		// 90kg converted to the profile unit (lb)
+		if *summary.Data.LatestWeight != 198.4 {
+			t.Errorf("Expected latest weight 198.4 lb, got %f", *summary.Data.LatestWeight)
+		}
+
+		resp, _ = userRequestBodyweight(http.MethodDelete, ts.URL("/users/"+userID+"/bodyweight/"+second.ID), userID, "")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", resp.StatusCode)
+		}
+
+		resp, _ = userRequestBodyweight(http.MethodDelete, ts.URL("/users/"+userID+"/bodyweight/"+second.ID), userID, "")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestStrengthScores(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID := createTestUserForProfile(t, ts, "strength-scores@example.com", "password123", "Strength User")
+
+	getScores := func() api.StrengthScoresWithHistoryResponse {
+		t.Helper()
+		resp, err := userRequestBodyweight(http.MethodGet, ts.URL("/users/"+userID+"/strength-scores"), userID, "")
+		if err != nil {
+			t.Fatalf("Failed to get strength scores: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			respBody, _ := io.ReadAll(resp.Body)
+			t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, respBody)
+		}
+		var envelope StrengthScoresEnvelope
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return envelope.Data
+	}
+
+	t.Run("reports missing requirements", func(t *testing.T) {
+		scores := getScores()
+		if scores.Current.Available {
+			t.Error("Expected scores to be unavailable")
+		}
+		// 3 competition lifts, bodyweight and sex
+		if len(scores.Current.Missing) != 5 {
+			t.Errorf("Expected 5 missing requirements, got %v", scores.Current.Missing)
+		}
+		if len(scores.History) != 0 {
+			t.Errorf("Expected empty history, got %d points", len(scores.History))
+		}
+	})
+
+	// Switch to kg, set sex, and log maxes and bodyweight
+	resp, _ := userPutProfile(ts.URL("/users/"+userID+"/profile"), userID, `{"weightUnit": "kg", "sex": "male"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Failed to update profile, status %d", resp.StatusCode)
+	}
+
+	logBodyweight(t, ts, userID, `{"weight": 93, "recordedAt": "2024-01-01T07:00:00Z"}`)
+
+	maxes := map[string]float64{
+		"00000000-0000-0000-0000-000000000001": 250,
+		"00000000-0000-0000-0000-000000000002": 160,
+		"00000000-0000-0000-0000-000000000003": 290,
+	}
+	for liftID, value := range maxes {
+		body, _ := json.Marshal(map[string]interface{}{
+			"liftId":        liftID,
+			"type":          "ONE_RM",
+			"value":         value,
+			"effectiveDate": "2024-01-05T00:00:00Z",
+		})
+		resp, _ := userRequestBodyweight(http.MethodPost, ts.URL("/users/"+userID+"/lift-maxes"), userID, string(body))
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Failed to create lift max, status %d", resp.StatusCode)
+		}
+	}
+
+	t.Run("computes scores from competition maxes", func(t *testing.T) {
+		scores := getScores()
+		if !scores.Current.Available {
+			t.Fatalf("Expected scores to be available, missing: %v", scores.Current.Missing)
+		}
+		if scores.Current.Total == nil || *scores.Current.Total != 700 {
+			t.Errorf("Expected total 700, got %v", scores.Current.Total)
+		}
+		if scores.Current.DOTS == nil || *scores.Current.DOTS != 445.38 {
+			t.Errorf("Expected DOTS 445.38, got %v", scores.Current.DOTS)
+		}
+		if scores.Current.IPFGL == nil || *scores.Current.IPFGL != 91.57 {
+			t.Errorf("Expected IPF GL 91.57, got %v", scores.Current.IPFGL)
+		}
+		if len(scores.Current.Lifts) != 3 {
+			t.Errorf("Expected 3 contributing lifts, got %d", len(scores.Current.Lifts))
+		}
+		if len(scores.History) != 1 {
+			t.Fatalf("Expected 1 history point, got %d", len(scores.History))
+		}
+		if scores.History[0].Bodyweight != 93 {
+			t.Errorf("Expected history bodyweight 93, got %f", scores.History[0].Bodyweight)
+		}
+	})
+
+	t.Run("scores appear on the dashboard", func(t *testing.T) {
+		resp, _ := userRequestBodyweight(http.MethodGet, ts.URL("/users/"+userID+"/dashboard"), userID, "")
+		defer resp.Body.Close()
+		var envelope struct {
+			Data api.DashboardResponse `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&envelope)
+		if envelope.Data.StrengthScores == nil || !envelope.Data.StrengthScores.Available {
+			t.Fatal("Expected available strength scores on dashboard")
+		}
+		if *envelope.Data.StrengthScores.Wilks != 439.73 {
+			t.Errorf("Expected Wilks 439.73, got %f", *envelope.Data.StrengthScores.Wilks)
+		}
+	})
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/waynenilsen/power-pro-v3/internal/calendar"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/prescription"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/workout"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/profile"
+	"github.com/waynenilsen/power-pro-v3/internal/repository"
+)
+
+// CalendarHandler handles HTTP requests for iCalendar feeds of planned sessions.
+type CalendarHandler struct {
+	service        *calendar.Service
+	workoutRepo    *repository.WorkoutRepository
+	generator      *cycleDayGenerator
+	profileService *profile.Service
+}
+
+// NewCalendarHandler creates a new CalendarHandler.
+func NewCalendarHandler(service *calendar.Service, workoutRepo *repository.WorkoutRepository, sqlDB *sql.DB, profileService *profile.Service) *CalendarHandler {
+	return &CalendarHandler{
+		service:        service,
+		workoutRepo:    workoutRepo,
+		generator:      newCycleDayGenerator(sqlDB),
+		profileService: profileService,
+	}
+}
+
+// CalendarFeedResponse represents the API response format for a calendar feed.
+type CalendarFeedResponse struct {
+	calendar.Feed
+	// Token and FeedPath are only returned when the feed is created or its token rotated.
+	Token    string `json:"token,omitempty"`
+	FeedPath string `json:"feedPath,omitempty"`
+}
+
+func calendarFeedToResponse(feed *calendar.Feed, token string) CalendarFeedResponse {
+	resp := CalendarFeedResponse{Feed: *feed, Token: token}
+	if token != "" {
+		resp.FeedPath = "/calendar/" + token + ".ics"
+	}
+	return resp
+}
+
+// Get handles GET /users/{userId}/calendar-feed
+func (h *CalendarHandler) Get(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.service.Get(r.Context(), r.PathValue("userId"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, calendarFeedToResponse(feed, ""))
+}
+
+// Put handles PUT /users/{userId}/calendar-feed
+// Creates the feed with 201 and its token, or updates its settings with 200.
+func (h *CalendarHandler) Put(w http.ResponseWriter, r *http.Request) {
+	var req calendar.Settings
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	feed, token, err := h.service.Configure(r.Context(), r.PathValue("userId"), req)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	status := http.StatusOK
+	if token != "" {
+		status = http.StatusCreated
+	}
+	writeData(w, status, calendarFeedToResponse(feed, token))
+}
+
+// RotateToken handles POST /users/{userId}/calendar-feed/token
+// Replaces the feed token; subscriptions to the old feed URL stop updating.
+func (h *CalendarHandler) RotateToken(w http.ResponseWriter, r *http.Request) {
+	feed, token, err := h.service.RotateToken(r.Context(), r.PathValue("userId"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, calendarFeedToResponse(feed, token))
+}
+
+// Delete handles DELETE /users/{userId}/calendar-feed
+func (h *CalendarHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Delete(r.Context(), r.PathValue("userId")); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Feed handles GET /calendar/{token}
+// Serves the feed as an iCalendar document. The token in the path authenticates the
+// request, since calendar apps cannot sign in; a trailing .ics is ignored.
+// Users without an enrollment get a calendar without sessions.
+func (h *CalendarHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.service.Resolve(r.Context(), strings.TrimSuffix(r.PathValue("token"), ".ics"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	userID := feed.UserID
+
+	p, err := h.profileService.GetProfile(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	schedule, err := h.service.Schedule(r.Context(), feed)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	cal := &calendar.Calendar{Name: "PowerPro training", Stamp: schedule.Now}
+	data, err := h.workoutRepo.GetCycleGenerationData(userID)
+	if err != nil && !errors.Is(err, workout.ErrUserNotEnrolled) {
+		writeDomainError(w, apperrors.NewInternal("failed to retrieve cycle data", err))
+		return
+	}
+	if data != nil {
+		cal.Name = data.Enrollment.ProgramName
+
+		weeks := make([]calendar.WeekLength, len(data.Weeks))
+		for i, week := range data.Weeks {
+			weeks[i] = calendar.WeekLength{Number: week.WeekNumber, Days: len(week.Days)}
+		}
+		start := calendar.Position{
+			CycleIteration: data.Enrollment.CurrentCycleIteration,
+			WeekNumber:     data.Enrollment.CurrentWeek,
+		}
+		if data.Enrollment.CurrentDayIndex != nil {
+			start.DayIndex = *data.Enrollment.CurrentDayIndex
+		}
+
+		for i, position := range calendar.Walk(weeks, start, len(schedule.Sessions)) {
+			day := dayAt(data, position)
+			session := calendar.Session{
+				Position:    position,
+				ProgramName: data.Enrollment.ProgramName,
+				DayName:     day.Day.Name,
+				WeightUnit:  p.WeightUnit,
+			}
+			if len(day.Prescriptions) > 0 {
+				date := schedule.Sessions[i].Format("2006-01-02")
+				generated, err := h.generator.generate(r.Context(), userID, data, position.CycleIteration, position.WeekNumber, day, date)
+				switch {
+				case errors.Is(err, prescription.ErrMaxNotFound):
+					session.MissingMaxes = true
+				case err != nil:
+					writeDomainError(w, apperrors.NewInternal("failed to generate calendar feed", err))
+					return
+				default:
+					session.Exercises = generated.Exercises
+				}
+			}
+			cal.Events = append(cal.Events, calendar.SessionEvent(userID, schedule.Sessions[i], schedule.Duration, session))
+		}
+	}
+	if schedule.MeetDate != nil {
+		cal.Events = append(cal.Events, calendar.MeetEvent(userID, *schedule.MeetDate))
+	}
+
+	var buf bytes.Buffer
+	if err := calendar.Render(&buf, cal); err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to render calendar feed", err))
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// dayAt returns the day of the cycle at a position from calendar.Walk.
+func dayAt(data *repository.CycleGenerationData, position calendar.Position) repository.CycleDayData {
+	for _, week := range data.Weeks {
+		if week.WeekNumber == position.WeekNumber {
+			return week.Days[position.DayIndex]
+		}
+	}
+	return repository.CycleDayData{}
+}
@@ -0,0 +1,113 @@
+package api_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+type calendarFeedTestResponse struct {
+	UserID       string   `json:"userId"`
+	TokenPrefix  string   `json:"tokenPrefix"`
+	TrainingDays []string `json:"trainingDays"`
+	StartTime    string   `json:"startTime"`
+	WeeksAhead   int      `json:"weeksAhead"`
+	Token        string   `json:"token"`
+	FeedPath     string   `json:"feedPath"`
+}
+
+func getCalendarFeed(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusOK && !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/calendar") {
+		t.Errorf("Expected a text/calendar response, got %s", resp.Header.Get("Content-Type"))
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestCalendarHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	var user UserTestResponse
+	coachingRequest(t, http.MethodPost, ts.URL("/auth/register"), map[string]string{
+		"email": "calendar@example.com", "password": "password123",
+	}, "", http.StatusCreated, &user)
+	feedURL := ts.URL("/users/" + user.ID + "/calendar-feed")
+
+	coachingRequest(t, http.MethodGet, feedURL, nil, user.ID, http.StatusNotFound, nil)
+	coachingRequest(t, http.MethodPut, feedURL, map[string]interface{}{"trainingDays": []string{"NOPE"}}, user.ID, http.StatusBadRequest, nil)
+
+	settings := map[string]interface{}{
+		"trainingDays": []string{"MON", "TUE", "WED", "THU", "FRI", "SAT", "SUN"},
+		"weeksAhead":   1,
+	}
+	var feed calendarFeedTestResponse
+	coachingRequest(t, http.MethodPut, feedURL, settings, user.ID, http.StatusCreated, &feed)
+	if feed.Token == "" || feed.FeedPath != "/calendar/"+feed.Token+".ics" || feed.StartTime != "18:00" {
+		t.Fatalf("Unexpected feed: %+v", feed)
+	}
+	token := feed.Token
+
+	t.Run("serves an empty calendar before enrollment", func(t *testing.T) {
+		status, body := getCalendarFeed(t, ts.URL(feed.FeedPath))
+		if status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", status, body)
+		}
+		if !strings.Contains(body, "BEGIN:VCALENDAR") || strings.Contains(body, "BEGIN:VEVENT") {
+			t.Errorf("Expected a calendar without events, got:\n%s", body)
+		}
+	})
+
+	t.Run("lists upcoming sessions with top sets", func(t *testing.T) {
+		setupWorkoutTest(t, ts, user.ID)
+		status, body := getCalendarFeed(t, ts.URL("/calendar/"+token))
+		if status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", status, body)
+		}
+		if n := strings.Count(body, "BEGIN:VEVENT"); n != 7 {
+			t.Errorf("Expected 7 sessions, got %d", n)
+		}
+		if unfolded := strings.ReplaceAll(body, "\r\n ", ""); !strings.Contains(unfolded, "Back Squat 225 lb × 5") {
+			t.Errorf("Expected the top set in the summary, got:\n%s", body)
+		}
+	})
+
+	t.Run("manages the feed", func(t *testing.T) {
+		settings["startTime"] = "06:15"
+		feed = calendarFeedTestResponse{}
+		coachingRequest(t, http.MethodPut, feedURL, settings, user.ID, http.StatusOK, &feed)
+		if feed.Token != "" || feed.StartTime != "06:15" {
+			t.Errorf("Expected updated settings without a token, got %+v", feed)
+		}
+		coachingRequest(t, http.MethodGet, feedURL, nil, user.ID, http.StatusOK, &feed)
+		if feed.TokenPrefix != token[:12] || len(feed.TrainingDays) != 7 {
+			t.Errorf("Unexpected feed: %+v", feed)
+		}
+		coachingRequest(t, http.MethodGet, feedURL, nil, "someone-else", http.StatusForbidden, nil)
+
+		coachingRequest(t, http.MethodPost, feedURL+"/token", nil, user.ID, http.StatusOK, &feed)
+		if status, _ := getCalendarFeed(t, ts.URL("/calendar/"+token+".ics")); status != http.StatusNotFound {
+			t.Errorf("Expected the old token to stop working, got %d", status)
+		}
+		if status, _ := getCalendarFeed(t, ts.URL(feed.FeedPath)); status != http.StatusOK {
+			t.Errorf("Expected the new token to work, got %d", status)
+		}
+
+		coachingRequest(t, http.MethodDelete, feedURL, nil, user.ID, http.StatusNoContent, nil)
+		if status, _ := getCalendarFeed(t, ts.URL(feed.FeedPath)); status != http.StatusNotFound {
+			t.Errorf("Expected the deleted feed to be gone, got %d", status)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/organization"
+)
+
+// Lifts, programs and lookups either belong to the global catalog or to an organization.
+// Admins see and maintain everything. Other users see the global catalog and their
+// organizations' entries, and owners and coaches maintain their organizations' entries.
+
+// catalogViewerID returns the user catalog lists are limited to, or nil for admins,
+// who see every entry.
+func catalogViewerID(r *http.Request) *string {
+	if middleware.IsAdmin(r) {
+		return nil
+	}
+	userID := middleware.GetUserID(r)
+	return &userID
+}
+
+// canViewCatalogEntry reports whether the caller may see an entry owned by organizationID.
+func canViewCatalogEntry(r *http.Request, orgService *organization.Service, organizationID *string) (bool, error) {
+	if middleware.IsAdmin(r) {
+		return true, nil
+	}
+	return orgService.CanView(r.Context(), organizationID, middleware.GetUserID(r))
+}
+
+// authorizeCatalogManage checks that the caller may create, update or delete an entry
+// owned by organizationID: an admin, or an owner or coach of the organization.
+func authorizeCatalogManage(r *http.Request, orgService *organization.Service, organizationID *string) error {
+	if middleware.IsAdmin(r) {
+		return nil
+	}
+	allowed, err := orgService.CanManageCatalog(r.Context(), organizationID, middleware.GetUserID(r))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		if organizationID == nil {
+			return apperrors.NewForbidden("Admin privileges required")
+		}
+		return apperrors.NewForbidden("only organization owners and coaches can manage its catalog")
+	}
+	return nil
+}
+
+// canReferenceCatalogEntry reports whether an entry owned by ownerID may reference one
+// owned by referencedID. Global entries reference only global entries, so they never
+// expose an organization's private catalog; organization entries also reference their own.
+func canReferenceCatalogEntry(ownerID, referencedID *string) bool {
+	if referencedID == nil {
+		return true
+	}
+	return ownerID != nil && *ownerID == *referencedID
+}
@@ -0,0 +1,507 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/outbox"
+	"github.com/waynenilsen/power-pro-v3/internal/repository"
+)
+
+// CoachingHandler handles HTTP requests for coach–athlete relationships, the coach-wide
+// views across athletes, and workout comments.
+type CoachingHandler struct {
+	coachingService *coaching.Service
+	outboxService   *outbox.Service
+	sessionRepo     *repository.WorkoutSessionRepository
+	stateRepo       *repository.UserProgramStateRepository
+}
+
+// NewCoachingHandler creates a new CoachingHandler.
+func NewCoachingHandler(
+	coachingService *coaching.Service,
+	outboxService *outbox.Service,
+	sessionRepo *repository.WorkoutSessionRepository,
+	stateRepo *repository.UserProgramStateRepository,
+) *CoachingHandler {
+	return &CoachingHandler{
+		coachingService: coachingService,
+		outboxService:   outboxService,
+		sessionRepo:     sessionRepo,
+		stateRepo:       stateRepo,
+	}
+}
+
+// canAccessUser reports whether the caller may access ownerID's data with the permission:
+// the user themselves, an admin, or a coach the user has granted the permission to.
+// An empty permission allows only the user and admins.
+func canAccessUser(r *http.Request, ownerID string, permission coaching.Permission) bool {
+	return middleware.CanAccessUser(r, ownerID, string(permission))
+}
+
+// CoachingInviteRequest represents the request body for inviting a coach or athlete.
+// The invitee is identified by userId or email.
+type CoachingInviteRequest struct {
+	UserID      string   `json:"userId,omitempty"`
+	Email       string   `json:"email,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// UpdateCoachingPermissionsRequest represents the request body for changing a coach's permissions.
+type UpdateCoachingPermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
+
+// CoachingRelationshipResponse represents the API response format for a coaching relationship.
+type CoachingRelationshipResponse struct {
+	ID          string     `json:"id"`
+	CoachID     string     `json:"coachId"`
+	AthleteID   string     `json:"athleteId"`
+	Status      string     `json:"status"`
+	Permissions []string   `json:"permissions"`
+	InvitedBy   string     `json:"invitedBy"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	RespondedAt *time.Time `json:"respondedAt"`
+	EndedAt     *time.Time `json:"endedAt"`
+}
+
+// CreateWorkoutCommentRequest represents the request body for commenting on a workout session.
+type CreateWorkoutCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// WorkoutCommentResponse represents the API response format for a workout comment.
+type WorkoutCommentResponse struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"sessionId"`
+	AuthorID  string    `json:"authorId"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func coachingRelationshipToResponse(rel *coaching.Relationship) CoachingRelationshipResponse {
+	permissions := make([]string, len(rel.Permissions))
+	for i, p := range rel.Permissions {
+		permissions[i] = string(p)
+	}
+	return CoachingRelationshipResponse{
+		ID:          rel.ID,
+		CoachID:     rel.CoachID,
+		AthleteID:   rel.AthleteID,
+		Status:      string(rel.Status),
+		Permissions: permissions,
+		InvitedBy:   rel.InvitedBy,
+		CreatedAt:   rel.CreatedAt,
+		UpdatedAt:   rel.UpdatedAt,
+		RespondedAt: rel.RespondedAt,
+		EndedAt:     rel.EndedAt,
+	}
+}
+
+func workoutCommentToResponse(c *coaching.Comment) WorkoutCommentResponse {
+	return WorkoutCommentResponse{
+		ID:        c.ID,
+		SessionID: c.SessionID,
+		AuthorID:  c.AuthorID,
+		Body:      c.Body,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func toCoachingPermissions(permissions []string) []coaching.Permission {
+	result := make([]coaching.Permission, len(permissions))
+	for i, p := range permissions {
+		result[i] = coaching.Permission(p)
+	}
+	return result
+}
+
+// parseCoachingStatus parses the optional status query parameter.
+func parseCoachingStatus(r *http.Request) (*coaching.Status, error) {
+	value := ParseFilterString(r.URL.Query(), "status")
+	if value == nil {
+		return nil, nil
+	}
+	status := coaching.Status(*value)
+	switch status {
+	case coaching.StatusPending, coaching.StatusActive, coaching.StatusDeclined, coaching.StatusEnded:
+		return &status, nil
+	}
+	return nil, apperrors.NewValidation("status", "must be PENDING, ACTIVE, DECLINED or ENDED")
+}
+
+// authorizeCoachingUser checks that the caller is the user or an admin. Relationships
+// are managed by their parties only, never by a coach on an athlete's behalf.
+func authorizeCoachingUser(r *http.Request, userID string) error {
+	if userID == "" {
+		return apperrors.NewBadRequest("missing user ID")
+	}
+	if middleware.GetUserID(r) != userID && !middleware.IsAdmin(r) {
+		return apperrors.NewForbidden("you can only manage your own coaching relationships")
+	}
+	return nil
+}
+
+// InviteCoach handles POST /users/{userId}/coaches
+// The athlete invites a coach; the relationship is active once the coach accepts.
+func (h *CoachingHandler) InviteCoach(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if err := authorizeCoachingUser(r, userID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	var req CoachingInviteRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	rel, err := h.coachingService.InviteCoach(r.Context(), userID, coaching.InviteRequest{
+		UserID:      req.UserID,
+		Email:       req.Email,
+		Permissions: toCoachingPermissions(req.Permissions),
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusCreated, coachingRelationshipToResponse(rel))
+}
+
+// ListCoaches handles GET /users/{userId}/coaches
+// Lists the user's relationships as an athlete, newest first, optionally filtered by status.
+func (h *CoachingHandler) ListCoaches(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if err := authorizeCoachingUser(r, userID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	h.list(w, r, coaching.RelationshipFilter{AthleteID: &userID})
+}
+
+// InviteAthlete handles POST /users/{userId}/athletes
+// The coach invites an athlete; the relationship is active once the athlete accepts.
+func (h *CoachingHandler) InviteAthlete(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if err := authorizeCoachingUser(r, userID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	var req CoachingInviteRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	rel, err := h.coachingService.InviteAthlete(r.Context(), userID, coaching.InviteRequest{
+		UserID:      req.UserID,
+		Email:       req.Email,
+		Permissions: toCoachingPermissions(req.Permissions),
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusCreated, coachingRelationshipToResponse(rel))
+}
+
+// ListAthletes handles GET /users/{userId}/athletes
+// Lists the user's relationships as a coach, newest first, optionally filtered by status.
+func (h *CoachingHandler) ListAthletes(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if err := authorizeCoachingUser(r, userID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	h.list(w, r, coaching.RelationshipFilter{CoachID: &userID})
+}
+
+func (h *CoachingHandler) list(w http.ResponseWriter, r *http.Request, filter coaching.RelationshipFilter) {
+	status, err := parseCoachingStatus(r)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	filter.Status = status
+
+	pg := ParsePagination(r.URL.Query())
+	rels, total, err := h.coachingService.ListRelationships(r.Context(), filter, int64(pg.Limit), int64(pg.Offset))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	data := make([]CoachingRelationshipResponse, len(rels))
+	for i := range rels {
+		data[i] = coachingRelationshipToResponse(&rels[i])
+	}
+
+	writePaginatedData(w, http.StatusOK, data, total, pg.Limit, pg.Offset)
+}
+
+// ListAthleteEvents handles GET /users/{userId}/athletes/events
+// Returns the state events of every athlete the user coaches with VIEW_LOGS, newest
+// first, optionally filtered by type and athleteId.
+func (h *CoachingHandler) ListAthleteEvents(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if err := authorizeCoachingUser(r, userID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	athleteIDs, err := h.coachingService.AthleteIDs(r.Context(), userID, coaching.PermissionViewLogs)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	query := r.URL.Query()
+	if athleteID := ParseFilterString(query, "athleteId"); athleteID != nil {
+		coached := false
+		for _, id := range athleteIDs {
+			if id == *athleteID {
+				coached = true
+				break
+			}
+		}
+		if !coached {
+			writeDomainError(w, apperrors.NewForbidden("you do not have permission to view this athlete's logs"))
+			return
+		}
+		athleteIDs = []string{*athleteID}
+	}
+
+	pg := ParsePagination(query)
+	filter := outbox.EventFilter{
+		EventType: ParseFilterString(query, "type"),
+		UserIDs:   athleteIDs,
+	}
+	events, total, err := h.outboxService.ListEvents(r.Context(), filter, int64(pg.Limit), int64(pg.Offset))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	data := make([]StoredEventResponse, len(events))
+	for i := range events {
+		data[i] = storedEventToResponse(&events[i])
+	}
+
+	writePaginatedData(w, http.StatusOK, data, total, pg.Limit, pg.Offset)
+}
+
+// getRelationship loads a relationship and checks the caller is one of its parties or an admin.
+func (h *CoachingHandler) getRelationship(r *http.Request) (*coaching.Relationship, error) {
+	rel, err := h.coachingService.GetRelationship(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return nil, err
+	}
+	if !rel.IsParty(middleware.GetUserID(r)) && !middleware.IsAdmin(r) {
+		return nil, apperrors.NewForbidden("you are not part of this coaching relationship")
+	}
+	return rel, nil
+}
+
+// Get handles GET /coaching-relationships/{id}
+func (h *CoachingHandler) Get(w http.ResponseWriter, r *http.Request) {
+	rel, err := h.getRelationship(r)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, coachingRelationshipToResponse(rel))
+}
+
+// Accept handles POST /coaching-relationships/{id}/accept
+// Only the invitee (or an admin) can accept an invitation.
+func (h *CoachingHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, h.coachingService.Accept)
+}
+
+// Decline handles POST /coaching-relationships/{id}/decline
+// Only the invitee (or an admin) can decline an invitation.
+func (h *CoachingHandler) Decline(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, h.coachingService.Decline)
+}
+
+func (h *CoachingHandler) respond(w http.ResponseWriter, r *http.Request, action func(ctx context.Context, id string) (*coaching.Relationship, error)) {
+	rel, err := h.getRelationship(r)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if rel.Invitee() != middleware.GetUserID(r) && !middleware.IsAdmin(r) {
+		writeDomainError(w, apperrors.NewForbidden("only the invited user can respond to this invitation"))
+		return
+	}
+
+	rel, err = action(r.Context(), rel.ID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, coachingRelationshipToResponse(rel))
+}
+
+// UpdatePermissions handles PUT /coaching-relationships/{id}/permissions
+// Only the athlete (or an admin) can change what the coach may do.
+func (h *CoachingHandler) UpdatePermissions(w http.ResponseWriter, r *http.Request) {
+	rel, err := h.getRelationship(r)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if rel.AthleteID != middleware.GetUserID(r) && !middleware.IsAdmin(r) {
+		writeDomainError(w, apperrors.NewForbidden("only the athlete can change a coach's permissions"))
+		return
+	}
+
+	var req UpdateCoachingPermissionsRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	rel, err = h.coachingService.UpdatePermissions(r.Context(), rel.ID, toCoachingPermissions(req.Permissions))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, coachingRelationshipToResponse(rel))
+}
+
+// End handles DELETE /coaching-relationships/{id}
+// Either party (or an admin) can end a relationship or withdraw an invitation.
+func (h *CoachingHandler) End(w http.ResponseWriter, r *http.Request) {
+	rel, err := h.getRelationship(r)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	rel, err = h.coachingService.End(r.Context(), rel.ID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, coachingRelationshipToResponse(rel))
+}
+
+// authorizeSessionComments checks the workout session exists and the caller may comment
+// on it: its owner, an admin, or a coach with COMMENT.
+func (h *CoachingHandler) authorizeSessionComments(r *http.Request, sessionID string) (ownerID string, err error) {
+	session, err := h.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return "", apperrors.NewInternal("failed to get session", err)
+	}
+	if session == nil {
+		return "", apperrors.NewNotFound("workout session", sessionID)
+	}
+
+	state, err := h.stateRepo.GetByID(session.UserProgramStateID)
+	if err != nil {
+		return "", apperrors.NewInternal("failed to get program state", err)
+	}
+	if state == nil {
+		return "", apperrors.NewInternal("session references invalid program state", nil)
+	}
+
+	if !canAccessUser(r, state.UserID, coaching.PermissionComment) {
+		return "", apperrors.NewForbidden("you do not have permission to comment on this workout session")
+	}
+	return state.UserID, nil
+}
+
+// ListComments handles GET /workouts/{id}/comments
+// Returns the session's comments, oldest first.
+func (h *CoachingHandler) ListComments(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("id")
+	if _, err := h.authorizeSessionComments(r, sessionID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	pg := ParsePagination(r.URL.Query())
+	comments, total, err := h.coachingService.ListComments(r.Context(), sessionID, int64(pg.Limit), int64(pg.Offset))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	data := make([]WorkoutCommentResponse, len(comments))
+	for i := range comments {
+		data[i] = workoutCommentToResponse(&comments[i])
+	}
+
+	writePaginatedData(w, http.StatusOK, data, total, pg.Limit, pg.Offset)
+}
+
+// CreateComment handles POST /workouts/{id}/comments
+func (h *CoachingHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("id")
+	if _, err := h.authorizeSessionComments(r, sessionID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	var req CreateWorkoutCommentRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	comment, err := h.coachingService.AddComment(r.Context(), sessionID, middleware.GetUserID(r), req.Body)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusCreated, workoutCommentToResponse(comment))
+}
+
+// DeleteComment handles DELETE /workouts/{id}/comments/{commentId}
+// A comment can be deleted by its author, the session's owner or an admin.
+func (h *CoachingHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("id")
+	ownerID, err := h.authorizeSessionComments(r, sessionID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	commentID := r.PathValue("commentId")
+	comment, err := h.coachingService.GetComment(r.Context(), commentID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if comment.SessionID != sessionID {
+		writeDomainError(w, apperrors.NewNotFound("workout comment", commentID))
+		return
+	}
+
+	userID := middleware.GetUserID(r)
+	if comment.AuthorID != userID && ownerID != userID && !middleware.IsAdmin(r) {
+		writeDomainError(w, apperrors.NewForbidden("you can only delete your own comments"))
+		return
+	}
+
+	if err := h.coachingService.DeleteComment(r.Context(), commentID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
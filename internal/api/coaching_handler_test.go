@@ -0,0 +1,215 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// CoachingRelationshipTestResponse represents a coaching relationship in test responses.
+type CoachingRelationshipTestResponse struct {
+	ID          string   `json:"id"`
+	CoachID     string   `json:"coachId"`
+	AthleteID   string   `json:"athleteId"`
+	Status      string   `json:"status"`
+	Permissions []string `json:"permissions"`
+	InvitedBy   string   `json:"invitedBy"`
+}
+
+// coachingRequest sends a request as userID and checks the response status. When out is
+// non-nil, the response's data is decoded into it.
+func coachingRequest(t *testing.T, method, url string, body interface{}, userID string, wantStatus int, out interface{}) {
+	t.Helper()
+	resp, err := webhookRequest(method, url, body, userID, false)
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("%s %s as %s: expected status %d, got %d: %s", method, url, userID, wantStatus, resp.StatusCode, bodyBytes)
+	}
+	if out != nil {
+		envelope := struct {
+			Data interface{} `json:"data"`
+		}{Data: out}
+		if err := json.Unmarshal(bodyBytes, &envelope); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+	}
+}
+
+func TestCoachingHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	coach, athlete, stranger := "coaching-coach", "coaching-athlete", "coaching-stranger"
+	for _, userID := range []string{coach, athlete, stranger} {
+		createLSTestUser(t, ts, userID)
+	}
+	liftID := createLSTestLift(t, ts, "Squat", "squat-coaching")
+	cycleID := createLSTestCycle(t, ts, "Coaching Cycle")
+	programID := createLSTestProgram(t, ts, "Coaching Program", "coaching-program", cycleID)
+	enrollLSTestUser(t, ts, athlete, programID)
+	sessionID := startLSWorkoutSession(t, ts, athlete)
+
+	var rel CoachingRelationshipTestResponse
+
+	t.Run("coach has no access before the athlete invites them", func(t *testing.T) {
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+athlete+"/program"), nil, coach, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/workouts/"+sessionID), nil, coach, http.StatusForbidden, nil)
+	})
+
+	t.Run("athlete invites a coach and the coach accepts", func(t *testing.T) {
+		coachingRequest(t, http.MethodPost, ts.URL("/users/"+athlete+"/coaches"), map[string]interface{}{
+			"userId":      coach,
+			"permissions": []string{"VIEW_LOGS", "COMMENT"},
+		}, athlete, http.StatusCreated, &rel)
+		if rel.Status != "PENDING" || rel.CoachID != coach || rel.AthleteID != athlete {
+			t.Fatalf("Unexpected relationship: %+v", rel)
+		}
+
+		// Pending invitations grant nothing, and only the invitee can accept
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+athlete+"/program"), nil, coach, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodPost, ts.URL("/coaching-relationships/"+rel.ID+"/accept"), nil, athlete, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/coaching-relationships/"+rel.ID), nil, stranger, http.StatusForbidden, nil)
+
+		coachingRequest(t, http.MethodPost, ts.URL("/coaching-relationships/"+rel.ID+"/accept"), nil, coach, http.StatusOK, &rel)
+		if rel.Status != "ACTIVE" {
+			t.Errorf("Expected ACTIVE, got %s", rel.Status)
+		}
+
+		var roster []CoachingRelationshipTestResponse
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+coach+"/athletes?status=ACTIVE"), nil, coach, http.StatusOK, &roster)
+		if len(roster) != 1 || roster[0].AthleteID != athlete {
+			t.Errorf("Expected the athlete on the roster, got %+v", roster)
+		}
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+athlete+"/coaches"), nil, coach, http.StatusForbidden, nil)
+	})
+
+	t.Run("coach uses the granted permissions only", func(t *testing.T) {
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+athlete+"/program"), nil, coach, http.StatusOK, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+athlete+"/lift-maxes"), nil, coach, http.StatusOK, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+athlete+"/logged-sets"), nil, coach, http.StatusOK, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/workouts/"+sessionID), nil, coach, http.StatusOK, nil)
+
+		coachingRequest(t, http.MethodPost, ts.URL("/users/"+athlete+"/lift-maxes"), map[string]interface{}{
+			"liftId": liftID, "type": "ONE_RM", "value": 300,
+		}, coach, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodDelete, ts.URL("/users/"+athlete+"/program"), nil, coach, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodPut, ts.URL("/users/"+athlete+"/profile"), map[string]interface{}{"name": "Coached"}, coach, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+athlete+"/dashboard"), nil, coach, http.StatusForbidden, nil)
+
+		// Access is not symmetric
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+coach+"/program"), nil, athlete, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+athlete+"/program"), nil, stranger, http.StatusForbidden, nil)
+	})
+
+	t.Run("athlete changes the coach's permissions", func(t *testing.T) {
+		coachingRequest(t, http.MethodPut, ts.URL("/coaching-relationships/"+rel.ID+"/permissions"), map[string]interface{}{
+			"permissions": []string{"EDIT_MAXES"},
+		}, coach, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodPut, ts.URL("/coaching-relationships/"+rel.ID+"/permissions"), map[string]interface{}{
+			"permissions": []string{"UNKNOWN"},
+		}, athlete, http.StatusBadRequest, nil)
+
+		coachingRequest(t, http.MethodPut, ts.URL("/coaching-relationships/"+rel.ID+"/permissions"), map[string]interface{}{
+			"permissions": []string{"VIEW_LOGS", "EDIT_MAXES", "COMMENT"},
+		}, athlete, http.StatusOK, &rel)
+
+		coachingRequest(t, http.MethodPost, ts.URL("/users/"+athlete+"/lift-maxes"), map[string]interface{}{
+			"liftId": liftID, "type": "ONE_RM", "value": 300,
+		}, coach, http.StatusCreated, nil)
+	})
+
+	t.Run("coach views events across athletes", func(t *testing.T) {
+		var events []struct {
+			Type   string `json:"type"`
+			UserID string `json:"userId"`
+		}
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+coach+"/athletes/events?type=ENROLLED"), nil, coach, http.StatusOK, &events)
+		if len(events) != 1 || events[0].UserID != athlete {
+			t.Errorf("Expected the athlete's enrollment event, got %+v", events)
+		}
+
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+coach+"/athletes/events?athleteId="+stranger), nil, coach, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+coach+"/athletes/events"), nil, stranger, http.StatusForbidden, nil)
+
+		var none []struct{}
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+stranger+"/athletes/events"), nil, stranger, http.StatusOK, &none)
+		if len(none) != 0 {
+			t.Errorf("Expected no events for a user without athletes, got %d", len(none))
+		}
+	})
+
+	t.Run("comments on workout sessions", func(t *testing.T) {
+		var comment struct {
+			ID       string `json:"id"`
+			AuthorID string `json:"authorId"`
+			Body     string `json:"body"`
+		}
+		coachingRequest(t, http.MethodPost, ts.URL("/workouts/"+sessionID+"/comments"), map[string]interface{}{
+			"body": "Brace harder on the last set",
+		}, coach, http.StatusCreated, &comment)
+		if comment.AuthorID != coach {
+			t.Errorf("Expected comment by coach, got %s", comment.AuthorID)
+		}
+
+		coachingRequest(t, http.MethodPost, ts.URL("/workouts/"+sessionID+"/comments"), map[string]interface{}{
+			"body": "Nice",
+		}, stranger, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodPost, ts.URL("/workouts/"+sessionID+"/comments"), map[string]interface{}{
+			"body": "",
+		}, athlete, http.StatusBadRequest, nil)
+
+		var comments []struct {
+			ID string `json:"id"`
+		}
+		coachingRequest(t, http.MethodGet, ts.URL("/workouts/"+sessionID+"/comments"), nil, athlete, http.StatusOK, &comments)
+		if len(comments) != 1 {
+			t.Fatalf("Expected 1 comment, got %d", len(comments))
+		}
+
+		// The session owner can remove a coach's comment
+		coachingRequest(t, http.MethodDelete, ts.URL("/workouts/"+sessionID+"/comments/"+comment.ID), nil, athlete, http.StatusNoContent, nil)
+		coachingRequest(t, http.MethodDelete, ts.URL("/workouts/"+sessionID+"/comments/"+comment.ID), nil, athlete, http.StatusNotFound, nil)
+	})
+
+	t.Run("ending the relationship revokes access", func(t *testing.T) {
+		coachingRequest(t, http.MethodDelete, ts.URL("/coaching-relationships/"+rel.ID), nil, stranger, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodDelete, ts.URL("/coaching-relationships/"+rel.ID), nil, coach, http.StatusOK, &rel)
+		if rel.Status != "ENDED" {
+			t.Errorf("Expected ENDED, got %s", rel.Status)
+		}
+
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+athlete+"/program"), nil, coach, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/workouts/"+sessionID+"/comments"), nil, coach, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodDelete, ts.URL("/coaching-relationships/"+rel.ID), nil, athlete, http.StatusConflict, nil)
+	})
+
+	t.Run("coach invites an athlete who declines", func(t *testing.T) {
+		var invite CoachingRelationshipTestResponse
+		coachingRequest(t, http.MethodPost, ts.URL("/users/"+coach+"/athletes"), map[string]interface{}{
+			"userId": stranger,
+		}, coach, http.StatusCreated, &invite)
+		if len(invite.Permissions) != 1 || invite.Permissions[0] != "VIEW_LOGS" {
+			t.Errorf("Expected view-only default permissions, got %v", invite.Permissions)
+		}
+		coachingRequest(t, http.MethodPost, ts.URL("/users/"+coach+"/athletes"), map[string]interface{}{
+			"userId": stranger,
+		}, coach, http.StatusConflict, nil)
+
+		coachingRequest(t, http.MethodPost, ts.URL("/coaching-relationships/"+invite.ID+"/decline"), nil, stranger, http.StatusOK, &invite)
+		if invite.Status != "DECLINED" {
+			t.Errorf("Expected DECLINED, got %s", invite.Status)
+		}
+		coachingRequest(t, http.MethodPost, ts.URL("/coaching-relationships/"+invite.ID+"/accept"), nil, stranger, http.StatusConflict, nil)
+	})
+}
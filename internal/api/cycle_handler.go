@@ -1,6 +1,7 @@
 package api
 
 import (
+	"database/sql"
 	"net/http"
 	"strings"
 	"time"
@@ -23,6 +24,13 @@ func NewCycleHandler(repo *repository.CycleRepository) *CycleHandler {
 	}
 }
 
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *CycleHandler) WithTx(tx *sql.Tx) *CycleHandler {
+	txHandler := *h
+	txHandler.repo = h.repo.WithTx(tx)
+	return &txHandler
+}
+
 // CycleResponse represents the API response format for a cycle.
 type CycleResponse struct {
 	ID          string    `json:"id"`
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/waynenilsen/power-pro-v3/internal/domain/cyclesheet"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/loadstrategy"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/prescription"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/rpechart"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/setscheme"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/workout"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/profile"
+	"github.com/waynenilsen/power-pro-v3/internal/repository"
+)
+
+// CycleSheetHandler handles HTTP requests for printable cycle sheets.
+type CycleSheetHandler struct {
+	workoutRepo    *repository.WorkoutRepository
+	generator      *cycleDayGenerator
+	profileService *profile.Service
+}
+
+// NewCycleSheetHandler creates a new CycleSheetHandler.
+func NewCycleSheetHandler(workoutRepo *repository.WorkoutRepository, sqlDB *sql.DB, profileService *profile.Service) *CycleSheetHandler {
+	return &CycleSheetHandler{
+		workoutRepo:    workoutRepo,
+		generator:      newCycleDayGenerator(sqlDB),
+		profileService: profileService,
+	}
+}
+
+// Get handles GET /users/{userId}/cycle-sheet
+// Renders every workout of the user's current cycle with weights resolved from their
+// current maxes. Readiness check-ins do not apply, since the sheet covers future days.
+// Optional query params: format (html, markdown or csv; default html), weeks (e.g. 1,3 or 2-4)
+func (h *CycleSheetHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+
+	format, err := cyclesheet.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		writeDomainError(w, apperrors.NewValidation("format", err.Error()))
+		return
+	}
+	weeks, err := cyclesheet.ParseWeeks(r.URL.Query().Get("weeks"))
+	if err != nil {
+		writeDomainError(w, apperrors.NewValidation("weeks", err.Error()))
+		return
+	}
+
+	p, err := h.profileService.GetProfile(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	data, err := h.workoutRepo.GetCycleGenerationData(userID)
+	if err != nil {
+		if errors.Is(err, workout.ErrUserNotEnrolled) {
+			writeDomainError(w, apperrors.NewNotFound("enrollment", userID))
+			return
+		}
+		writeDomainError(w, apperrors.NewInternal("failed to retrieve cycle data", err))
+		return
+	}
+
+	selected := data.Weeks
+	if weeks != nil {
+		byNumber := make(map[int]repository.CycleWeekData, len(data.Weeks))
+		for _, week := range data.Weeks {
+			byNumber[week.WeekNumber] = week
+		}
+		selected = make([]repository.CycleWeekData, 0, len(weeks))
+		for _, number := range weeks {
+			week, ok := byNumber[number]
+			if !ok {
+				writeDomainError(w, apperrors.NewValidation("weeks", fmt.Sprintf("week %d is not in the cycle", number)))
+				return
+			}
+			selected = append(selected, week)
+		}
+	}
+
+	date := workout.GetDateString()
+	sheet := &cyclesheet.Sheet{
+		ProgramName:    data.Enrollment.ProgramName,
+		CycleIteration: data.Enrollment.CurrentCycleIteration,
+		WeightUnit:     p.WeightUnit,
+		GeneratedOn:    date,
+		Weeks:          make([]cyclesheet.Week, 0, len(selected)),
+	}
+	for _, week := range selected {
+		sheetWeek := cyclesheet.Week{Number: week.WeekNumber, Days: make([]cyclesheet.Day, 0, len(week.Days))}
+		for _, day := range week.Days {
+			sheetDay := cyclesheet.Day{Name: day.Day.Name, Slug: day.Day.Slug}
+			if len(day.Prescriptions) > 0 {
+				generated, err := h.generator.generate(r.Context(), userID, data, data.Enrollment.CurrentCycleIteration, week.WeekNumber, day, date)
+				if err != nil {
+					if errors.Is(err, prescription.ErrMaxNotFound) {
+						writeDomainError(w, apperrors.NewValidationMsg("missing lift max: set up your training maxes to generate workouts"), err.Error())
+						return
+					}
+					writeDomainError(w, apperrors.NewInternal("failed to generate cycle sheet", err))
+					return
+				}
+				sheetDay.Exercises = generated.Exercises
+			}
+			sheetWeek.Days = append(sheetWeek.Days, sheetDay)
+		}
+		sheet.Weeks = append(sheet.Weeks, sheetWeek)
+	}
+
+	// Render before responding so that failures still get an error response
+	var buf bytes.Buffer
+	if err := cyclesheet.Render(&buf, format, sheet); err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to render cycle sheet", err))
+		return
+	}
+	w.Header().Set("Content-Type", cyclesheet.ContentType(format))
+	if format == cyclesheet.FormatCSV {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("cycle-%d-%s.csv", sheet.CycleIteration, date)))
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// cycleDayGenerator generates the workout of any week and day of a user's current cycle,
+// as the workout preview does.
+type cycleDayGenerator struct {
+	liftLookup *repository.LiftLookupAdapter
+	maxLookup  *repository.MaxLookupAdapter
+	rpeChart   *rpechart.RPEChart
+}
+
+func newCycleDayGenerator(sqlDB *sql.DB) *cycleDayGenerator {
+	return &cycleDayGenerator{
+		liftLookup: repository.NewLiftLookupAdapter(sqlDB),
+		maxLookup:  repository.NewMaxLookupAdapter(sqlDB),
+		rpeChart:   rpechart.NewDefaultRPEChart(),
+	}
+}
+
+// generate resolves one day of the cycle with the user's current maxes.
+func (g *cycleDayGenerator) generate(ctx context.Context, userID string, data *repository.CycleGenerationData, cycleIteration, weekNumber int, day repository.CycleDayData, date string) (*workout.Workout, error) {
+	// Inject dependencies (MaxLookup, RPE chart) into prescriptions for load strategy resolution
+	repository.InjectDependencies(day.Prescriptions, g.maxLookup, g.rpeChart)
+
+	genCtx := workout.GenerationContext{
+		LiftLookup:    g.liftLookup,
+		SetGenContext: setscheme.DefaultSetGenerationContext(),
+	}
+	if data.WeeklyLookup != nil || data.DailyLookup != nil {
+		genCtx.LookupContext = &loadstrategy.LookupContext{
+			WeekNumber:   weekNumber,
+			DaySlug:      day.Day.Slug,
+			WeeklyLookup: data.WeeklyLookup,
+			DailyLookup:  data.DailyLookup,
+		}
+	}
+
+	return workout.GenerateWorkout(
+		ctx,
+		userID,
+		workout.ProgramContext{
+			ProgramID:        data.Enrollment.ProgramID,
+			ProgramName:      data.Enrollment.ProgramName,
+			CycleID:          data.Enrollment.CycleID,
+			CycleLengthWeeks: data.Enrollment.CycleLengthWeeks,
+		},
+		workout.UserState{
+			CurrentWeek:           weekNumber,
+			CurrentCycleIteration: cycleIteration,
+		},
+		workout.DayContext{
+			DayID:   day.Day.ID,
+			DaySlug: day.Day.Slug,
+			DayName: day.Day.Name,
+		},
+		day.Prescriptions,
+		genCtx,
+		date,
+	)
+}
@@ -0,0 +1,95 @@
+package api_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+func getCycleSheet(t *testing.T, url, userID string) (*http.Response, string) {
+	t.Helper()
+	resp, err := userGetWorkout(url, userID)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestCycleSheetHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	var user UserTestResponse
+	coachingRequest(t, http.MethodPost, ts.URL("/auth/register"), map[string]string{
+		"email": "cyclesheet@example.com", "password": "password123",
+	}, "", http.StatusCreated, &user)
+	userID := user.ID
+	setupWorkoutTest(t, ts, userID)
+	sheetURL := ts.URL("/users/" + userID + "/cycle-sheet")
+
+	t.Run("renders HTML by default", func(t *testing.T) {
+		resp, body := getCycleSheet(t, sheetURL, userID)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, body)
+		}
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+			t.Errorf("Expected HTML content type, got %s", ct)
+		}
+		for _, want := range []string{"Week 1", "Back Squat", "5 × 5 @ 225 lb", "3:00", "Focus on form"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("Expected sheet to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("renders Markdown", func(t *testing.T) {
+		resp, body := getCycleSheet(t, sheetURL+"?format=markdown&weeks=1", userID)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, body)
+		}
+		if !strings.Contains(body, "| Back Squat | 5 × 5 @ 225 lb | 3:00 | Focus on form |") {
+			t.Errorf("Unexpected markdown sheet:\n%s", body)
+		}
+	})
+
+	t.Run("renders CSV as an attachment", func(t *testing.T) {
+		resp, body := getCycleSheet(t, sheetURL+"?format=csv", userID)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, body)
+		}
+		if cd := resp.Header.Get("Content-Disposition"); !strings.Contains(cd, "attachment") {
+			t.Errorf("Expected an attachment, got %q", cd)
+		}
+		lines := strings.Split(strings.TrimSpace(body), "\n")
+		if len(lines) != 6 {
+			t.Fatalf("Expected a header and 5 sets, got %d lines", len(lines))
+		}
+		if !strings.HasPrefix(lines[1], "1,") || !strings.Contains(lines[1], ",225,lb,5,false,true,180,") {
+			t.Errorf("Unexpected first set row: %s", lines[1])
+		}
+	})
+
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"?format=pdf", "?weeks=abc", "?weeks=2"} {
+			resp, body := getCycleSheet(t, sheetURL+query, userID)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("%s: expected status 400, got %d: %s", query, resp.StatusCode, body)
+			}
+		}
+	})
+
+	t.Run("denies other users", func(t *testing.T) {
+		resp, _ := getCycleSheet(t, sheetURL, "someone-else")
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"database/sql"
 	"net/http"
 	"strings"
 	"time"
@@ -8,21 +9,31 @@ import (
 	"github.com/google/uuid"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/dailylookup"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/organization"
 	"github.com/waynenilsen/power-pro-v3/internal/repository"
 )
 
 // DailyLookupHandler handles HTTP requests for daily lookup operations.
 type DailyLookupHandler struct {
-	repo *repository.DailyLookupRepository
+	repo       *repository.DailyLookupRepository
+	orgService *organization.Service
 }
 
 // NewDailyLookupHandler creates a new DailyLookupHandler.
-func NewDailyLookupHandler(repo *repository.DailyLookupRepository) *DailyLookupHandler {
+func NewDailyLookupHandler(repo *repository.DailyLookupRepository, orgService *organization.Service) *DailyLookupHandler {
 	return &DailyLookupHandler{
-		repo: repo,
+		repo:       repo,
+		orgService: orgService,
 	}
 }
 
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *DailyLookupHandler) WithTx(tx *sql.Tx) *DailyLookupHandler {
+	txHandler := *h
+	txHandler.repo = h.repo.WithTx(tx)
+	return &txHandler
+}
+
 // DailyLookupEntryResponse represents an entry in the API response.
 type DailyLookupEntryResponse struct {
 	DayIdentifier      string  `json:"dayIdentifier"`
@@ -32,19 +43,22 @@ type DailyLookupEntryResponse struct {
 
 // DailyLookupResponse represents the API response format for a daily lookup.
 type DailyLookupResponse struct {
-	ID        string                    `json:"id"`
-	Name      string                    `json:"name"`
-	Entries   []DailyLookupEntryResponse `json:"entries"`
-	ProgramID *string                   `json:"programId,omitempty"`
-	CreatedAt time.Time                 `json:"createdAt"`
-	UpdatedAt time.Time                 `json:"updatedAt"`
+	ID             string                     `json:"id"`
+	Name           string                     `json:"name"`
+	Entries        []DailyLookupEntryResponse `json:"entries"`
+	ProgramID      *string                    `json:"programId,omitempty"`
+	OrganizationID *string                    `json:"organizationId"`
+	CreatedAt      time.Time                  `json:"createdAt"`
+	UpdatedAt      time.Time                  `json:"updatedAt"`
 }
 
 // CreateDailyLookupRequest represents the request body for creating a daily lookup.
+// A lookup with an organizationId is visible only to that organization's members.
 type CreateDailyLookupRequest struct {
-	Name      string                     `json:"name"`
-	Entries   []DailyLookupEntryRequest  `json:"entries"`
-	ProgramID *string                    `json:"programId,omitempty"`
+	Name           string                    `json:"name"`
+	Entries        []DailyLookupEntryRequest `json:"entries"`
+	ProgramID      *string                   `json:"programId,omitempty"`
+	OrganizationID *string                   `json:"organizationId,omitempty"`
 }
 
 // DailyLookupEntryRequest represents an entry in the create/update request.
@@ -72,12 +86,13 @@ func dailyLookupToResponse(d *dailylookup.DailyLookup) DailyLookupResponse {
 	}
 
 	return DailyLookupResponse{
-		ID:        d.ID,
-		Name:      d.Name,
-		Entries:   entries,
-		ProgramID: d.ProgramID,
-		CreatedAt: d.CreatedAt,
-		UpdatedAt: d.UpdatedAt,
+		ID:             d.ID,
+		Name:           d.Name,
+		Entries:        entries,
+		ProgramID:      d.ProgramID,
+		OrganizationID: d.OrganizationID,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
 	}
 }
 
@@ -126,6 +141,7 @@ func (h *DailyLookupHandler) List(w http.ResponseWriter, r *http.Request) {
 		Offset:    int64(pg.Offset),
 		SortBy:    sortBy,
 		SortOrder: sortOrder,
+		ViewerID:  catalogViewerID(r),
 	}
 
 	lookups, total, err := h.repo.List(params)
@@ -156,8 +172,8 @@ func (h *DailyLookupHandler) Get(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewInternal("failed to get daily lookup", err))
 		return
 	}
-	if lookup == nil {
-		writeDomainError(w, apperrors.NewNotFound("daily lookup", id))
+	if err := h.checkVisible(r, lookup, id); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -172,6 +188,11 @@ func (h *DailyLookupHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := authorizeCatalogManage(r, h.orgService, req.OrganizationID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
 	// Generate UUID
 	id := uuid.New().String()
 
@@ -191,6 +212,7 @@ func (h *DailyLookupHandler) Create(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewValidationMsg("validation failed"), details...)
 		return
 	}
+	newLookup.OrganizationID = req.OrganizationID
 
 	// Persist
 	if err := h.repo.Create(newLookup); err != nil {
@@ -215,8 +237,8 @@ func (h *DailyLookupHandler) Update(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewInternal("failed to get daily lookup", err))
 		return
 	}
-	if existing == nil {
-		writeDomainError(w, apperrors.NewNotFound("daily lookup", id))
+	if err := h.checkManage(r, existing, id); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -269,8 +291,8 @@ func (h *DailyLookupHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewInternal("failed to get daily lookup", err))
 		return
 	}
-	if existing == nil {
-		writeDomainError(w, apperrors.NewNotFound("daily lookup", id))
+	if err := h.checkManage(r, existing, id); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -293,3 +315,27 @@ func (h *DailyLookupHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// checkVisible returns a not found error when the lookup does not exist or belongs to an
+// organization the caller is not a member of.
+func (h *DailyLookupHandler) checkVisible(r *http.Request, lookup *dailylookup.DailyLookup, id string) error {
+	if lookup == nil {
+		return apperrors.NewNotFound("daily lookup", id)
+	}
+	visible, err := canViewCatalogEntry(r, h.orgService, lookup.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return apperrors.NewNotFound("daily lookup", id)
+	}
+	return nil
+}
+
+// checkManage checks the lookup is visible to the caller and that they may change it.
+func (h *DailyLookupHandler) checkManage(r *http.Request, lookup *dailylookup.DailyLookup, id string) error {
+	if err := h.checkVisible(r, lookup, id); err != nil {
+		return err
+	}
+	return authorizeCatalogManage(r, h.orgService, lookup.OrganizationID)
+}
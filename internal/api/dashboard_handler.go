@@ -28,6 +28,7 @@ type DashboardResponse struct {
 	CurrentSession *SessionSummaryResponse      `json:"currentSession"`
 	RecentWorkouts []WorkoutSummaryResponse     `json:"recentWorkouts"`
 	CurrentMaxes   []MaxSummaryResponse         `json:"currentMaxes"`
+	StrengthScores *StrengthScoresResponse      `json:"strengthScores"`
 }
 
 // EnrollmentSummaryResponse represents the enrollment section.
@@ -94,12 +95,12 @@ func (h *DashboardHandler) Get(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Build and return response
-	response := h.buildDashboardResponse(dash)
+	response := dashboardToResponse(dash)
 	writeData(w, http.StatusOK, response)
 }
 
-// buildDashboardResponse converts the domain dashboard to a response.
-func (h *DashboardHandler) buildDashboardResponse(dash *dashboard.Dashboard) DashboardResponse {
+// dashboardToResponse converts the domain dashboard to a response.
+func dashboardToResponse(dash *dashboard.Dashboard) DashboardResponse {
 	response := DashboardResponse{
 		RecentWorkouts: make([]WorkoutSummaryResponse, 0),
 		CurrentMaxes:   make([]MaxSummaryResponse, 0),
@@ -156,5 +157,11 @@ func (h *DashboardHandler) buildDashboardResponse(dash *dashboard.Dashboard) Das
 		})
 	}
 
+	// Strength scores
+	if dash.StrengthScores != nil {
+		scores := buildStrengthScoresResponse(dash.StrengthScores)
+		response.StrengthScores = &scores
+	}
+
 	return response
 }
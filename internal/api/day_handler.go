@@ -1,6 +1,7 @@
 package api
 
 import (
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"strings"
@@ -26,6 +27,14 @@ func NewDayHandler(repo *repository.DayRepository, prescriptionRepo *repository.
 	}
 }
 
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *DayHandler) WithTx(tx *sql.Tx) *DayHandler {
+	txHandler := *h
+	txHandler.repo = h.repo.WithTx(tx)
+	txHandler.prescriptionRepo = h.prescriptionRepo.WithTx(tx)
+	return &txHandler
+}
+
 // DayResponse represents the API response format for a day.
 type DayResponse struct {
 	ID        string                 `json:"id"`
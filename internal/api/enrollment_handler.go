@@ -1,38 +1,51 @@
 package api
 
 import (
-	"context"
+	"database/sql"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/event"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/progression"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/userprogramstate"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
-	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/organization"
+	"github.com/waynenilsen/power-pro-v3/internal/outbox"
 	"github.com/waynenilsen/power-pro-v3/internal/repository"
+	"github.com/waynenilsen/power-pro-v3/internal/service"
 )
 
 // EnrollmentHandler handles HTTP requests for user program enrollment operations.
 type EnrollmentHandler struct {
-	stateRepo   *repository.UserProgramStateRepository
-	programRepo *repository.ProgramRepository
-	sessionRepo *repository.WorkoutSessionRepository
-	eventBus    *event.Bus
+	stateRepo          *repository.UserProgramStateRepository
+	programRepo        *repository.ProgramRepository
+	sessionRepo        *repository.WorkoutSessionRepository
+	progressionService *service.ProgressionService
+	outbox             *outbox.Service
+	orgService         *organization.Service
 }
 
 // NewEnrollmentHandler creates a new EnrollmentHandler.
+// progressionService is used to preview the progressions a week advance would trigger.
+// Enrollment changes and their events are stored together through outboxService.
+// Users can only enroll in organization programs of organizations they belong to.
 func NewEnrollmentHandler(
 	stateRepo *repository.UserProgramStateRepository,
 	programRepo *repository.ProgramRepository,
 	sessionRepo *repository.WorkoutSessionRepository,
-	eventBus *event.Bus,
+	progressionService *service.ProgressionService,
+	outboxService *outbox.Service,
+	orgService *organization.Service,
 ) *EnrollmentHandler {
 	return &EnrollmentHandler{
-		stateRepo:   stateRepo,
-		programRepo: programRepo,
-		sessionRepo: sessionRepo,
-		eventBus:    eventBus,
+		stateRepo:          stateRepo,
+		programRepo:        programRepo,
+		sessionRepo:        sessionRepo,
+		progressionService: progressionService,
+		outbox:             outboxService,
+		orgService:         orgService,
 	}
 }
 
@@ -116,10 +129,8 @@ func (h *EnrollmentHandler) Enroll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Authorization check: only the user themselves or an admin can enroll
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if authUserID != userID && !isAdmin {
+	// Authorization check: the user, their coach or an admin can enroll
+	if !canAccessUser(r, userID, coaching.PermissionManageEnrollment) {
 		writeDomainError(w, apperrors.NewForbidden("you can only manage your own enrollment"))
 		return
 	}
@@ -140,6 +151,15 @@ func (h *EnrollmentHandler) Enroll(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewValidation("programId", "program not found"))
 		return
 	}
+	visible, err := h.orgService.CanView(r.Context(), program.OrganizationID, userID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if !visible {
+		writeDomainError(w, apperrors.NewValidation("programId", "program not found"))
+		return
+	}
 
 	// Check if user is already enrolled
 	isEnrolled, err := h.stateRepo.UserIsEnrolled(userID)
@@ -148,15 +168,6 @@ func (h *EnrollmentHandler) Enroll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// If already enrolled, replace existing enrollment (re-enrollment replaces existing)
-	if isEnrolled {
-		// Delete existing enrollment
-		if err := h.stateRepo.DeleteByUserID(userID); err != nil {
-			writeDomainError(w, apperrors.NewInternal("failed to remove existing enrollment", err))
-			return
-		}
-	}
-
 	// Generate UUID for new enrollment
 	id := uuid.New().String()
 
@@ -176,9 +187,27 @@ func (h *EnrollmentHandler) Enroll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Persist
-	if err := h.stateRepo.Create(newState); err != nil {
-		writeDomainError(w, apperrors.NewInternal("failed to create enrollment", err))
+	// Persist the enrollment and its ENROLLED event together
+	err = h.outbox.Transact(r.Context(), func(tx *sql.Tx) ([]event.StateEvent, error) {
+		stateRepo := h.stateRepo.WithTx(tx)
+
+		// If already enrolled, replace existing enrollment (re-enrollment replaces existing)
+		if isEnrolled {
+			if err := stateRepo.DeleteByUserID(userID); err != nil {
+				return nil, apperrors.NewInternal("failed to remove existing enrollment", err)
+			}
+		}
+
+		if err := stateRepo.Create(newState); err != nil {
+			return nil, apperrors.NewInternal("failed to create enrollment", err)
+		}
+
+		evt := event.NewStateEvent(event.EventEnrolled, userID, req.ProgramID).
+			WithPayload(event.PayloadEnrolledAt, newState.EnrolledAt)
+		return []event.StateEvent{evt}, nil
+	})
+	if err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -193,13 +222,6 @@ func (h *EnrollmentHandler) Enroll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Emit ENROLLED event
-	if h.eventBus != nil {
-		evt := event.NewStateEvent(event.EventEnrolled, userID, req.ProgramID).
-			WithPayload(event.PayloadEnrolledAt, newState.EnrolledAt)
-		h.eventBus.PublishAsync(context.Background(), evt)
-	}
-
 	// New enrollment has no active workout session
 	writeData(w, http.StatusCreated, enrollmentToResponse(enrollment, nil))
 }
@@ -212,10 +234,8 @@ func (h *EnrollmentHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Authorization check: only the user themselves or an admin can view enrollment
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if authUserID != userID && !isAdmin {
+	// Authorization check: the user, their coach or an admin can view enrollment
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
 		writeDomainError(w, apperrors.NewForbidden("you can only view your own enrollment"))
 		return
 	}
@@ -261,10 +281,8 @@ func (h *EnrollmentHandler) Unenroll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Authorization check: only the user themselves or an admin can unenroll
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if authUserID != userID && !isAdmin {
+	// Authorization check: the user, their coach or an admin can unenroll
+	if !canAccessUser(r, userID, coaching.PermissionManageEnrollment) {
 		writeDomainError(w, apperrors.NewForbidden("you can only manage your own enrollment"))
 		return
 	}
@@ -297,17 +315,19 @@ func (h *EnrollmentHandler) Unenroll(w http.ResponseWriter, r *http.Request) {
 	}
 	weeksCompleted := (cyclesCompleted * enrollment.CycleLengthWeeks) + weeksInCurrentCycle
 
-	// Emit QUIT event before deletion
-	if h.eventBus != nil {
+	// Delete the enrollment and store its QUIT event together
+	err = h.outbox.Transact(r.Context(), func(tx *sql.Tx) ([]event.StateEvent, error) {
+		if err := h.stateRepo.WithTx(tx).DeleteByUserID(userID); err != nil {
+			return nil, apperrors.NewInternal("failed to unenroll", err)
+		}
+
 		evt := event.NewStateEvent(event.EventQuit, userID, enrollment.State.ProgramID).
 			WithPayload(event.PayloadCyclesCompleted, cyclesCompleted).
 			WithPayload(event.PayloadWeeksCompleted, weeksCompleted)
-		h.eventBus.PublishAsync(context.Background(), evt)
-	}
-
-	// Delete enrollment
-	if err := h.stateRepo.DeleteByUserID(userID); err != nil {
-		writeDomainError(w, apperrors.NewInternal("failed to unenroll", err))
+		return []event.StateEvent{evt}, nil
+	})
+	if err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -324,9 +344,7 @@ func (h *EnrollmentHandler) NextCycle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Authorization check
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if authUserID != userID && !isAdmin {
+	if !canAccessUser(r, userID, coaching.PermissionManageEnrollment) {
 		writeDomainError(w, apperrors.NewForbidden("you can only manage your own enrollment"))
 		return
 	}
@@ -357,18 +375,20 @@ func (h *EnrollmentHandler) NextCycle(w http.ResponseWriter, r *http.Request) {
 	enrollment.State.WeekStatus = userprogramstate.WeekStatusPending
 	enrollment.State.UpdatedAt = time.Now()
 
-	// Persist changes
-	if err := h.stateRepo.Update(enrollment.State); err != nil {
-		writeDomainError(w, apperrors.NewInternal("failed to update enrollment", err))
-		return
-	}
+	// Persist changes and the CYCLE_STARTED event together
+	err = h.outbox.Transact(r.Context(), func(tx *sql.Tx) ([]event.StateEvent, error) {
+		if err := h.stateRepo.WithTx(tx).Update(enrollment.State); err != nil {
+			return nil, apperrors.NewInternal("failed to update enrollment", err)
+		}
 
-	// Emit CYCLE_STARTED event
-	if h.eventBus != nil {
 		evt := event.NewStateEvent(event.EventCycleStarted, userID, enrollment.State.ProgramID).
 			WithPayload(event.PayloadCycleIteration, enrollment.State.CurrentCycleIteration).
 			WithPayload(event.PayloadWeekNumber, enrollment.State.CurrentWeek)
-		h.eventBus.PublishAsync(context.Background(), evt)
+		return []event.StateEvent{evt}, nil
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
 	}
 
 	// Fetch updated enrollment for response
@@ -383,6 +403,8 @@ func (h *EnrollmentHandler) NextCycle(w http.ResponseWriter, r *http.Request) {
 
 // AdvanceWeek handles POST /users/{userId}/enrollment/advance-week
 // Advances to the next week in the cycle. If at the final week, transitions to BETWEEN_CYCLES.
+// With ?dryRun=true, the enrollment is left unchanged and the response previews the
+// AFTER_WEEK (or, at the final week, AFTER_CYCLE) progressions the advance would apply.
 func (h *EnrollmentHandler) AdvanceWeek(w http.ResponseWriter, r *http.Request) {
 	userID := r.PathValue("userId")
 	if userID == "" {
@@ -391,9 +413,7 @@ func (h *EnrollmentHandler) AdvanceWeek(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Authorization check
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if authUserID != userID && !isAdmin {
+	if !canAccessUser(r, userID, coaching.PermissionManageEnrollment) {
 		writeDomainError(w, apperrors.NewForbidden("you can only manage your own enrollment"))
 		return
 	}
@@ -415,6 +435,16 @@ func (h *EnrollmentHandler) AdvanceWeek(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	dryRun, err := ParseFilterBool(r.URL.Query(), "dryRun")
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if dryRun != nil && *dryRun {
+		h.previewAdvanceWeek(w, r, enrollment)
+		return
+	}
+
 	previousWeek := enrollment.State.CurrentWeek
 	cycleBoundaryReached := false
 
@@ -434,27 +464,30 @@ func (h *EnrollmentHandler) AdvanceWeek(w http.ResponseWriter, r *http.Request)
 
 	enrollment.State.UpdatedAt = time.Now()
 
-	// Persist changes
-	if err := h.stateRepo.Update(enrollment.State); err != nil {
-		writeDomainError(w, apperrors.NewInternal("failed to update enrollment", err))
-		return
-	}
+	// Persist changes and the WEEK_COMPLETED event together
+	err = h.outbox.Transact(r.Context(), func(tx *sql.Tx) ([]event.StateEvent, error) {
+		if err := h.stateRepo.WithTx(tx).Update(enrollment.State); err != nil {
+			return nil, apperrors.NewInternal("failed to update enrollment", err)
+		}
 
-	// Emit WEEK_COMPLETED event
-	if h.eventBus != nil {
-		evt := event.NewStateEvent(event.EventWeekCompleted, userID, enrollment.State.ProgramID).
-			WithPayload(event.PayloadPreviousWeek, previousWeek).
-			WithPayload(event.PayloadNewWeek, enrollment.State.CurrentWeek).
-			WithPayload(event.PayloadCycleIteration, enrollment.State.CurrentCycleIteration)
-		h.eventBus.PublishAsync(context.Background(), evt)
+		events := []event.StateEvent{
+			event.NewStateEvent(event.EventWeekCompleted, userID, enrollment.State.ProgramID).
+				WithPayload(event.PayloadPreviousWeek, previousWeek).
+				WithPayload(event.PayloadNewWeek, enrollment.State.CurrentWeek).
+				WithPayload(event.PayloadCycleIteration, enrollment.State.CurrentCycleIteration),
+		}
 
 		// Emit CYCLE_BOUNDARY_REACHED if applicable
 		if cycleBoundaryReached {
-			boundaryEvt := event.NewStateEvent(event.EventCycleBoundaryReached, userID, enrollment.State.ProgramID).
+			events = append(events, event.NewStateEvent(event.EventCycleBoundaryReached, userID, enrollment.State.ProgramID).
 				WithPayload(event.PayloadCompletedCycle, enrollment.State.CurrentCycleIteration).
-				WithPayload(event.PayloadTotalWeeks, enrollment.CycleLengthWeeks)
-			h.eventBus.PublishAsync(context.Background(), boundaryEvt)
+				WithPayload(event.PayloadTotalWeeks, enrollment.CycleLengthWeeks))
 		}
+		return events, nil
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
 	}
 
 	// Fetch updated enrollment for response
@@ -466,3 +499,25 @@ func (h *EnrollmentHandler) AdvanceWeek(w http.ResponseWriter, r *http.Request)
 
 	writeData(w, http.StatusOK, enrollmentToResponse(updatedEnrollment, nil))
 }
+
+// previewAdvanceWeek writes the progressions that advancing the week would apply, without
+// changing the enrollment or persisting any progression.
+func (h *EnrollmentHandler) previewAdvanceWeek(w http.ResponseWriter, r *http.Request, enrollment *userprogramstate.EnrollmentWithProgram) {
+	state := enrollment.State
+
+	var result *service.AggregateResult
+	var err error
+	if state.CurrentWeek >= enrollment.CycleLengthWeeks {
+		evt := progression.NewCycleTriggerEvent(state.UserID, state.CurrentCycleIteration, enrollment.CycleLengthWeeks)
+		result, err = h.progressionService.DryRunCycleComplete(r.Context(), evt)
+	} else {
+		evt := progression.NewWeekTriggerEvent(state.UserID, state.CurrentWeek, state.CurrentWeek+1, state.CurrentCycleIteration)
+		result, err = h.progressionService.DryRunWeekAdvance(r.Context(), evt)
+	}
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to preview progressions", err))
+		return
+	}
+
+	writeData(w, http.StatusOK, aggregateResultToResponse(result))
+}
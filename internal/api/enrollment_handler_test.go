@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/waynenilsen/power-pro-v3/internal/api"
 	"github.com/waynenilsen/power-pro-v3/internal/testutil"
 )
 
@@ -688,6 +689,29 @@ func TestEnrollmentAdvanceWeek(t *testing.T) {
 	}
 	resp.Body.Close()
 
+	t.Run("dry run previews week progressions without advancing", func(t *testing.T) {
+		resp, err := userPostAdvanceWeek(ts.URL("/users/"+userID+"/enrollment/advance-week?dryRun=true"), userID)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, bodyBytes)
+		}
+
+		var envelope struct {
+			Data api.TriggerResponse `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if !envelope.Data.DryRun || envelope.Data.TriggerType != "AFTER_WEEK" {
+			t.Errorf("Expected AFTER_WEEK dry run, got %+v", envelope.Data)
+		}
+	})
+
 	t.Run("advances from week 1 to week 2", func(t *testing.T) {
 		resp, err := userPostAdvanceWeek(ts.URL("/users/"+userID+"/enrollment/advance-week"), userID)
 		if err != nil {
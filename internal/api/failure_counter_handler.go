@@ -5,8 +5,8 @@ package api
 import (
 	"net/http"
 
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
-	"github.com/waynenilsen/power-pro-v3/internal/middleware"
 	"github.com/waynenilsen/power-pro-v3/internal/service"
 )
 
@@ -39,10 +39,8 @@ func (h *FailureCounterHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Authorization: user can query their own failure counters, admin can query any
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if !isAdmin && authUserID != userID {
+	// Authorization: user can query their own failure counters, coaches and admins can query others
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
 		writeDomainError(w, apperrors.NewForbidden("you do not have permission to view failure counters for this user"))
 		return
 	}
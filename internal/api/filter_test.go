@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/waynenilsen/power-pro-v3/internal/api"
+	"github.com/waynenilsen/power-pro-v3/internal/repository"
 )
 
 // TestParseFilterString tests the string filter parsing utility.
@@ -524,6 +525,52 @@ func TestParseFilterEnum(t *testing.T) {
 	}
 }
 
+// TestParseCursorPage tests the cursor pagination parsing utility.
+func TestParseCursorPage(t *testing.T) {
+	page, err := api.ParseCursorPage(url.Values{"limit": {"5"}, "offset": {"10"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.SortOrder != repository.SortDesc || page.After != nil || page.Limit != 5 || page.Offset != 10 {
+		t.Errorf("expected newest first from offset 10, got %+v", page)
+	}
+	if page.NextCursor(nil) != nil {
+		t.Error("expected no cursor after the last page")
+	}
+
+	page, err = api.ParseCursorPage(url.Values{"sortOrder": {"ASC"}})
+	if err != nil || page.SortOrder != repository.SortAsc {
+		t.Fatalf("expected ascending sort, got %+v, %v", page, err)
+	}
+
+	next := repository.Keyset{Value: "2024-01-15T10:00:00.123456789Z", Seq: 42}
+	cursor := *page.NextCursor(&next)
+	resumed, err := api.ParseCursorPage(url.Values{"cursor": {cursor}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resumed.SortOrder != repository.SortAsc || resumed.After == nil || *resumed.After != next {
+		t.Errorf("expected the cursor's sort and position, got %+v", resumed)
+	}
+
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{"unknown sort order", url.Values{"sortOrder": {"newest"}}},
+		{"malformed cursor", url.Values{"cursor": {"not-a-cursor"}}},
+		{"cursor for another sort order", url.Values{"cursor": {cursor}, "sortOrder": {"desc"}}},
+		{"cursor with offset", url.Values{"cursor": {cursor}, "offset": {"0"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := api.ParseCursorPage(tt.query); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
 // Helper functions
 func strPtr(s string) *string {
 	return &s
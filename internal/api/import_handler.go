@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/importer"
+)
+
+// ImportHandler handles HTTP requests for training history imports.
+type ImportHandler struct {
+	service *importer.Service
+}
+
+// NewImportHandler creates a new ImportHandler.
+func NewImportHandler(service *importer.Service) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// ImportRequest represents the request body for importing training history.
+type ImportRequest struct {
+	// Format is the source app: STRONG, HEVY, FITNOTES or GENERIC.
+	Format string `json:"format"`
+	// CSV is the contents of the exported file.
+	CSV string `json:"csv"`
+	// Columns maps the columns of GENERIC files.
+	Columns *importer.Columns `json:"columns"`
+	// Unit is the weight unit of files that do not name one. Defaults to the user's unit.
+	Unit string `json:"unit"`
+	// Exercises maps exercise names to a lift ID or slug, or to "" to skip them.
+	Exercises   map[string]string `json:"exercises"`
+	DryRun      bool              `json:"dryRun"`
+	DeriveMaxes bool              `json:"deriveMaxes"`
+}
+
+// Create handles POST /users/{userId}/imports
+// Responds with 200 and the report for dry runs, and 201 when workouts were imported.
+func (h *ImportHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req ImportRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	report, err := h.service.Import(r.Context(), r.PathValue("userId"), importer.Request{
+		Format:      req.Format,
+		CSV:         req.CSV,
+		Columns:     req.Columns,
+		Unit:        req.Unit,
+		Exercises:   req.Exercises,
+		DryRun:      req.DryRun,
+		DeriveMaxes: req.DeriveMaxes,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if report.ImportID != nil {
+		status = http.StatusCreated
+	}
+	writeData(w, status, report)
+}
+
+// List handles GET /users/{userId}/imports
+func (h *ImportHandler) List(w http.ResponseWriter, r *http.Request) {
+	imports, err := h.service.ListImports(r.Context(), r.PathValue("userId"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, imports)
+}
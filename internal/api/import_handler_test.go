@@ -0,0 +1,97 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+type importReportTestResponse struct {
+	ImportID *string `json:"importId"`
+	DryRun   bool    `json:"dryRun"`
+	Sessions struct {
+		Total           int `json:"total"`
+		New             int `json:"new"`
+		AlreadyImported int `json:"alreadyImported"`
+	} `json:"sessions"`
+	Sets struct {
+		New int `json:"new"`
+	} `json:"sets"`
+	Exercises []struct {
+		Name        string  `json:"name"`
+		Status      string  `json:"status"`
+		LiftID      *string `json:"liftId"`
+		Suggestions []struct {
+			LiftID string `json:"liftId"`
+		} `json:"suggestions"`
+	} `json:"exercises"`
+	Maxes []struct {
+		LiftID string  `json:"liftId"`
+		Value  float64 `json:"value"`
+	} `json:"maxes"`
+}
+
+func TestImportHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	var user UserTestResponse
+	coachingRequest(t, http.MethodPost, ts.URL("/auth/register"), map[string]string{
+		"email": "switcher@example.com", "password": "password123",
+	}, "", http.StatusCreated, &user)
+	importsURL := ts.URL("/users/" + user.ID + "/imports")
+
+	csv := "Date,Workout Name,Exercise Name,Set Order,Weight,Reps,RPE\n" +
+		"2023-03-01 07:00:00,Heavy,Squat (Barbell),1,315,3,\n" +
+		"2023-03-01 07:00:00,Heavy,Front Squat (Barbell),1,225,3,\n"
+	request := map[string]interface{}{"format": "STRONG", "csv": csv, "dryRun": true, "deriveMaxes": true}
+
+	var report importReportTestResponse
+	coachingRequest(t, http.MethodPost, importsURL, request, user.ID, http.StatusOK, &report)
+	if !report.DryRun || report.ImportID != nil || report.Sessions.New != 1 || len(report.Exercises) != 2 {
+		t.Fatalf("Unexpected dry run report: %+v", report)
+	}
+	front := report.Exercises[1]
+	if front.Status != "NEEDS_CONFIRMATION" || len(front.Suggestions) == 0 || front.Suggestions[0].LiftID != "00000000-0000-0000-0000-000000000001" {
+		t.Errorf("Expected squat to be suggested for front squats, got %+v", front)
+	}
+
+	request["dryRun"] = false
+	coachingRequest(t, http.MethodPost, importsURL, request, user.ID, http.StatusBadRequest, nil)
+
+	request["exercises"] = map[string]string{"Front Squat (Barbell)": ""}
+	coachingRequest(t, http.MethodPost, importsURL, request, user.ID, http.StatusCreated, &report)
+	if report.ImportID == nil || report.Sets.New != 1 || len(report.Maxes) != 1 {
+		t.Fatalf("Unexpected import report: %+v", report)
+	}
+
+	// Imported sets count toward personal records
+	var records []struct {
+		RecordType string `json:"recordType"`
+	}
+	coachingRequest(t, http.MethodGet, ts.URL("/users/"+user.ID+"/records"), nil, user.ID, http.StatusOK, &records)
+	if len(records) == 0 {
+		t.Error("Expected personal records from the imported sets")
+	}
+
+	coachingRequest(t, http.MethodPost, importsURL, request, user.ID, http.StatusOK, &report)
+	if report.ImportID != nil || report.Sessions.AlreadyImported != 1 {
+		t.Errorf("Expected the workout to be skipped on re-import, got %+v", report)
+	}
+
+	var imports []struct {
+		ID           string `json:"id"`
+		SessionCount int    `json:"sessionCount"`
+	}
+	coachingRequest(t, http.MethodGet, importsURL, nil, user.ID, http.StatusOK, &imports)
+	if len(imports) != 1 || imports[0].SessionCount != 1 {
+		t.Errorf("Expected one import, got %+v", imports)
+	}
+
+	coachingRequest(t, http.MethodGet, importsURL, nil, "someone-else", http.StatusForbidden, nil)
+	coachingRequest(t, http.MethodPost, importsURL, map[string]interface{}{"format": "JEFIT", "csv": csv}, user.ID, http.StatusBadRequest, nil)
+}
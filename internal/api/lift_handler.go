@@ -1,6 +1,7 @@
 package api
 
 import (
+	"database/sql"
 	"net/http"
 	"strings"
 	"time"
@@ -8,17 +9,26 @@ import (
 	"github.com/google/uuid"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/lift"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/organization"
 	"github.com/waynenilsen/power-pro-v3/internal/repository"
 )
 
 // LiftHandler handles HTTP requests for lift operations.
 type LiftHandler struct {
-	repo *repository.LiftRepository
+	repo       *repository.LiftRepository
+	orgService *organization.Service
 }
 
 // NewLiftHandler creates a new LiftHandler.
-func NewLiftHandler(repo *repository.LiftRepository) *LiftHandler {
-	return &LiftHandler{repo: repo}
+func NewLiftHandler(repo *repository.LiftRepository, orgService *organization.Service) *LiftHandler {
+	return &LiftHandler{repo: repo, orgService: orgService}
+}
+
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *LiftHandler) WithTx(tx *sql.Tx) *LiftHandler {
+	txHandler := *h
+	txHandler.repo = h.repo.WithTx(tx)
+	return &txHandler
 }
 
 // LiftResponse represents the API response format for a lift.
@@ -28,16 +38,19 @@ type LiftResponse struct {
 	Slug              string    `json:"slug"`
 	IsCompetitionLift bool      `json:"isCompetitionLift"`
 	ParentLiftID      *string   `json:"parentLiftId"`
+	OrganizationID    *string   `json:"organizationId"`
 	CreatedAt         time.Time `json:"createdAt"`
 	UpdatedAt         time.Time `json:"updatedAt"`
 }
 
 // CreateLiftRequest represents the request body for creating a lift.
+// A lift with an organizationId is visible only to that organization's members.
 type CreateLiftRequest struct {
 	Name              string  `json:"name"`
 	Slug              string  `json:"slug,omitempty"`
 	IsCompetitionLift bool    `json:"isCompetitionLift"`
 	ParentLiftID      *string `json:"parentLiftId,omitempty"`
+	OrganizationID    *string `json:"organizationId,omitempty"`
 }
 
 // UpdateLiftRequest represents the request body for updating a lift.
@@ -56,6 +69,7 @@ func liftToResponse(l *lift.Lift) LiftResponse {
 		Slug:              l.Slug,
 		IsCompetitionLift: l.IsCompetitionLift,
 		ParentLiftID:      l.ParentLiftID,
+		OrganizationID:    l.OrganizationID,
 		CreatedAt:         l.CreatedAt,
 		UpdatedAt:         l.UpdatedAt,
 	}
@@ -102,6 +116,7 @@ func (h *LiftHandler) List(w http.ResponseWriter, r *http.Request) {
 		SortBy:            sortBy,
 		SortOrder:         sortOrder,
 		FilterCompetition: filterCompetition,
+		ViewerID:          catalogViewerID(r),
 	}
 
 	lifts, total, err := h.repo.List(params)
@@ -132,8 +147,8 @@ func (h *LiftHandler) Get(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewInternal("failed to get lift", err))
 		return
 	}
-	if l == nil {
-		writeDomainError(w, apperrors.NewNotFound("lift", id))
+	if err := h.checkVisible(r, l, id); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -153,8 +168,8 @@ func (h *LiftHandler) GetBySlug(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewInternal("failed to get lift", err))
 		return
 	}
-	if l == nil {
-		writeDomainError(w, apperrors.NewNotFound("lift", slug))
+	if err := h.checkVisible(r, l, slug); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -169,6 +184,11 @@ func (h *LiftHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := authorizeCatalogManage(r, h.orgService, req.OrganizationID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
 	// Generate UUID
 	id := uuid.New().String()
 
@@ -178,6 +198,7 @@ func (h *LiftHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Slug:              req.Slug,
 		IsCompetitionLift: req.IsCompetitionLift,
 		ParentLiftID:      req.ParentLiftID,
+		OrganizationID:    req.OrganizationID,
 	}
 
 	newLift, result := lift.CreateLift(input, id, h.repo)
@@ -203,13 +224,8 @@ func (h *LiftHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	// Check parent lift exists if provided
 	if req.ParentLiftID != nil {
-		parent, err := h.repo.GetByID(*req.ParentLiftID)
-		if err != nil {
-			writeDomainError(w, apperrors.NewInternal("failed to verify parent lift", err))
-			return
-		}
-		if parent == nil {
-			writeDomainError(w, apperrors.NewValidation("parentLiftId", "parent lift not found"))
+		if err := h.checkParent(*req.ParentLiftID, newLift.OrganizationID); err != nil {
+			writeDomainError(w, err)
 			return
 		}
 	}
@@ -237,8 +253,8 @@ func (h *LiftHandler) Update(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewInternal("failed to get lift", err))
 		return
 	}
-	if existing == nil {
-		writeDomainError(w, apperrors.NewNotFound("lift", id))
+	if err := h.checkManage(r, existing, id); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -263,13 +279,8 @@ func (h *LiftHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	// Check parent lift exists if being set
 	if req.ParentLiftID != nil && !req.ClearParentLift {
-		parent, err := h.repo.GetByID(*req.ParentLiftID)
-		if err != nil {
-			writeDomainError(w, apperrors.NewInternal("failed to verify parent lift", err))
-			return
-		}
-		if parent == nil {
-			writeDomainError(w, apperrors.NewValidation("parentLiftId", "parent lift not found"))
+		if err := h.checkParent(*req.ParentLiftID, existing.OrganizationID); err != nil {
+			writeDomainError(w, err)
 			return
 		}
 	}
@@ -316,8 +327,8 @@ func (h *LiftHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewInternal("failed to get lift", err))
 		return
 	}
-	if existing == nil {
-		writeDomainError(w, apperrors.NewNotFound("lift", id))
+	if err := h.checkManage(r, existing, id); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -347,3 +358,40 @@ func (h *LiftHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// checkVisible returns a not found error when the lift does not exist or belongs to an
+// organization the caller is not a member of.
+func (h *LiftHandler) checkVisible(r *http.Request, l *lift.Lift, identifier string) error {
+	if l == nil {
+		return apperrors.NewNotFound("lift", identifier)
+	}
+	visible, err := canViewCatalogEntry(r, h.orgService, l.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return apperrors.NewNotFound("lift", identifier)
+	}
+	return nil
+}
+
+// checkManage checks the lift is visible to the caller and that they may change it.
+func (h *LiftHandler) checkManage(r *http.Request, l *lift.Lift, identifier string) error {
+	if err := h.checkVisible(r, l, identifier); err != nil {
+		return err
+	}
+	return authorizeCatalogManage(r, h.orgService, l.OrganizationID)
+}
+
+// checkParent checks the parent lift exists and may be referenced by a lift owned by
+// organizationID.
+func (h *LiftHandler) checkParent(parentID string, organizationID *string) error {
+	parent, err := h.repo.GetByID(parentID)
+	if err != nil {
+		return apperrors.NewInternal("failed to verify parent lift", err)
+	}
+	if parent == nil || !canReferenceCatalogEntry(organizationID, parent.OrganizationID) {
+		return apperrors.NewValidation("parentLiftId", "parent lift not found")
+	}
+	return nil
+}
@@ -30,10 +30,11 @@ type LiftResponse struct {
 
 // PaginationMeta contains pagination metadata.
 type PaginationMeta struct {
-	Total   int64 `json:"total"`
-	Limit   int   `json:"limit"`
-	Offset  int   `json:"offset"`
-	HasMore bool  `json:"hasMore"`
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	HasMore    bool   `json:"hasMore"`
+	NextCursor string `json:"nextCursor"`
 }
 
 // PaginatedLiftsResponse is the paginated list response with standard envelope.
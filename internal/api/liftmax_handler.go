@@ -1,15 +1,16 @@
 package api
 
 import (
+	"database/sql"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/liftmax"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
-	"github.com/waynenilsen/power-pro-v3/internal/middleware"
 	"github.com/waynenilsen/power-pro-v3/internal/repository"
+	"github.com/waynenilsen/power-pro-v3/internal/service"
 )
 
 // LiftMaxHandler handles HTTP requests for lift max operations.
@@ -23,6 +24,14 @@ func NewLiftMaxHandler(repo *repository.LiftMaxRepository, liftRepo *repository.
 	return &LiftMaxHandler{repo: repo, liftRepo: liftRepo}
 }
 
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *LiftMaxHandler) WithTx(tx *sql.Tx) *LiftMaxHandler {
+	txHandler := *h
+	txHandler.repo = h.repo.WithTx(tx)
+	txHandler.liftRepo = h.liftRepo.WithTx(tx)
+	return &txHandler
+}
+
 // LiftMaxResponse represents the API response format for a lift max.
 type LiftMaxResponse struct {
 	ID            string    `json:"id"`
@@ -75,40 +84,53 @@ func (h *LiftMaxHandler) List(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
 	query := r.URL.Query()
 
-	// Pagination (limit/offset)
-	pg := ParsePagination(query)
-
-	// Sorting (default: descending by effective_date)
-	sortOrder := repository.SortDesc
-	if o := query.Get("sortOrder"); o != "" {
-		switch strings.ToLower(o) {
-		case "asc":
-			sortOrder = repository.SortAsc
-		case "desc":
-			sortOrder = repository.SortDesc
-		}
+	// Pagination (limit/offset or cursor) and sort (default: descending by effective_date)
+	page, err := ParseCursorPage(query)
+	if err != nil {
+		writeDomainError(w, err)
+		return
 	}
 
-	// Filter by lift_id
-	filterLiftID := ParseFilterString(query, "lift_id")
+	// Filter by liftId (lift_id is accepted as well)
+	filter := repository.LiftMaxFilter{UserID: userID, LiftID: ParseFilterString(query, "liftId")}
+	if filter.LiftID == nil {
+		filter.LiftID = ParseFilterString(query, "lift_id")
+	}
 
 	// Filter by type (enum validation)
-	filterType, err := ParseFilterEnum(query, "type", []string{string(liftmax.OneRM), string(liftmax.TrainingMax)})
-	if err != nil {
+	if filter.Type, err = ParseFilterEnum(query, "type", []string{string(liftmax.OneRM), string(liftmax.TrainingMax)}); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	// Filter by effective date range
+	if filter.From, err = ParseFilterDate(query, "startDate"); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if filter.To, err = ParseFilterDateEndOfDay(query, "endDate"); err != nil {
 		writeDomainError(w, err)
 		return
 	}
 
-	params := repository.LiftMaxListParams{
-		UserID:    userID,
-		LiftID:    filterLiftID,
-		Type:      filterType,
-		SortOrder: sortOrder,
-		Limit:     int64(pg.Limit),
-		Offset:    int64(pg.Offset),
+	// Count the first page only
+	var total *int64
+	if page.After == nil {
+		count, err := h.repo.Count(filter)
+		if err != nil {
+			writeDomainError(w, apperrors.NewInternal("failed to count lift maxes", err))
+			return
+		}
+		total = &count
 	}
 
-	maxes, total, err := h.repo.List(params)
+	maxes, next, err := h.repo.List(repository.LiftMaxListParams{
+		Filter:    filter,
+		SortOrder: page.SortOrder,
+		After:     page.After,
+		Limit:     int64(page.Limit),
+		Offset:    int64(page.Offset),
+	})
 	if err != nil {
 		writeDomainError(w, apperrors.NewInternal("failed to list lift maxes", err))
 		return
@@ -120,7 +142,7 @@ func (h *LiftMaxHandler) List(w http.ResponseWriter, r *http.Request) {
 		data[i] = liftMaxToResponse(&m)
 	}
 
-	writePaginatedData(w, http.StatusOK, data, total, pg.Limit, pg.Offset)
+	writeCursorPaginatedData(w, http.StatusOK, data, page, total, next)
 }
 
 // Get handles GET /lift-maxes/{id}
@@ -141,10 +163,8 @@ func (h *LiftMaxHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check ownership: user must be owner or admin
-	requestingUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if requestingUserID != m.UserID && !isAdmin {
+	// Check access: user must be owner, their coach or an admin
+	if !canAccessUser(r, m.UserID, coaching.PermissionViewLogs) {
 		writeDomainError(w, apperrors.NewForbidden("you do not have permission to access this resource"))
 		return
 	}
@@ -244,40 +264,7 @@ func (h *LiftMaxHandler) Create(w http.ResponseWriter, r *http.Request) {
 // syncTrainingMax creates or updates a Training Max based on a 1RM value.
 // The TM is set to 90% of the 1RM, rounded to the nearest 0.25.
 func (h *LiftMaxHandler) syncTrainingMax(oneRM *liftmax.LiftMax) error {
-	calculator := liftmax.NewMaxCalculator()
-	tmValue, err := calculator.ConvertToTM(oneRM.Value, nil) // Uses default 90%
-	if err != nil {
-		return err
-	}
-
-	// Check if a TM already exists for this user/lift with the same effective date
-	existingTM, err := h.repo.GetCurrentMax(oneRM.UserID, oneRM.LiftID, string(liftmax.TrainingMax))
-	if err != nil {
-		return err
-	}
-
-	now := time.Now()
-
-	if existingTM != nil {
-		// Update existing TM
-		existingTM.Value = tmValue
-		existingTM.EffectiveDate = oneRM.EffectiveDate
-		existingTM.UpdatedAt = now
-		return h.repo.Update(existingTM)
-	}
-
-	// Create new TM
-	newTM := &liftmax.LiftMax{
-		ID:            uuid.New().String(),
-		UserID:        oneRM.UserID,
-		LiftID:        oneRM.LiftID,
-		Type:          liftmax.TrainingMax,
-		Value:         tmValue,
-		EffectiveDate: oneRM.EffectiveDate,
-		CreatedAt:     now,
-		UpdatedAt:     now,
-	}
-	return h.repo.Create(newTM)
+	return service.SyncTrainingMax(h.repo, oneRM)
 }
 
 // Update handles PUT /lift-maxes/{id}
@@ -305,10 +292,8 @@ func (h *LiftMaxHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check ownership: user must be owner or admin
-	requestingUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if requestingUserID != existing.UserID && !isAdmin {
+	// Check access: user must be owner, their coach or an admin
+	if !canAccessUser(r, existing.UserID, coaching.PermissionEditMaxes) {
 		writeDomainError(w, apperrors.NewForbidden("you do not have permission to modify this resource"))
 		return
 	}
@@ -391,10 +376,8 @@ func (h *LiftMaxHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check ownership: user must be owner or admin
-	requestingUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if requestingUserID != existing.UserID && !isAdmin {
+	// Check access: user must be owner, their coach or an admin
+	if !canAccessUser(r, existing.UserID, coaching.PermissionEditMaxes) {
 		writeDomainError(w, apperrors.NewForbidden("you do not have permission to delete this resource"))
 		return
 	}
@@ -271,6 +271,61 @@ func TestListLiftMaxes(t *testing.T) {
 	})
 }
 
+func TestListLiftMaxesCursor(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID := "cursor-max-user"
+	createLSTestUser(t, ts, userID)
+	dates := []time.Time{
+		time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+	}
+	for i := range dates {
+		createMax(t, ts, userID, testSquatID, "ONE_RM", 300.0+float64(i)*10, &dates[i])
+	}
+	listURL := ts.URL(fmt.Sprintf("/users/%s/lift-maxes?limit=3", userID))
+
+	t.Run("pages through 1RMs and the training max", func(t *testing.T) {
+		ids, metas := walkCursorPages(t, listURL, userID)
+		if len(ids) != 4 || len(metas) != 2 || metas[0].Total != 4 || metas[1].HasMore {
+			t.Fatalf("Expected 4 maxes over 2 pages, got %v %+v", ids, metas)
+		}
+
+		ascending, _ := walkCursorPages(t, listURL+"&sortOrder=asc", userID)
+		for i := range ids {
+			if ascending[i] != ids[len(ids)-1-i] {
+				t.Fatalf("Expected sortOrder=asc to reverse the default order, got %v and %v", ascending, ids)
+			}
+		}
+	})
+
+	t.Run("filters by effective date and lift", func(t *testing.T) {
+		ids, metas := walkCursorPages(t, listURL+"&startDate=2024-02-01&endDate=2024-02-28&liftId="+testSquatID, userID)
+		if len(ids) != 1 || metas[0].Total != 1 {
+			t.Errorf("Expected the February 1RM, got %v", ids)
+		}
+		if ids, _ := walkCursorPages(t, listURL+"&type=ONE_RM&endDate=2024-02-10", userID); len(ids) != 2 {
+			t.Errorf("Expected the January and February 1RMs, got %v", ids)
+		}
+	})
+
+	t.Run("returns 400 for an unknown sort order", func(t *testing.T) {
+		resp, err := authGetUser(listURL+"&sortOrder=value", userID)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+}
+
 func TestGetLiftMax(t *testing.T) {
 	ts, err := testutil.NewTestServer()
 	if err != nil {
@@ -6,9 +6,9 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/liftmax"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
-	"github.com/waynenilsen/power-pro-v3/internal/middleware"
 )
 
 // ConversionResponse represents the API response format for a max conversion.
@@ -95,10 +95,8 @@ func (h *LiftMaxHandler) Convert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check ownership: user must be owner or admin
-	requestingUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if requestingUserID != existing.UserID && !isAdmin {
+	// Check access: user must be owner, their coach or an admin
+	if !canAccessUser(r, existing.UserID, coaching.PermissionViewLogs) {
 		writeDomainError(w, apperrors.NewForbidden("you do not have permission to access this resource"))
 		return
 	}
@@ -1,16 +1,19 @@
 package api
 
 import (
-	"context"
+	"database/sql"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/event"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/loggedset"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/workoutsession"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
 	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/outbox"
 	"github.com/waynenilsen/power-pro-v3/internal/repository"
 	"github.com/waynenilsen/power-pro-v3/internal/service"
 )
@@ -21,23 +24,31 @@ type LoggedSetHandler struct {
 	workoutSessionRepo *repository.WorkoutSessionRepository
 	stateRepo          *repository.UserProgramStateRepository
 	failureService     *service.FailureService
-	eventBus           *event.Bus
+	prService          *service.PersonalRecordService
+	loggedSetService   *service.LoggedSetService
+	outbox             *outbox.Service
 }
 
 // NewLoggedSetHandler creates a new LoggedSetHandler.
+// loggedSetService handles set corrections, deletions, and session amendments.
+// New sets and their events are stored together through outboxService.
 func NewLoggedSetHandler(
 	repo *repository.LoggedSetRepository,
 	workoutSessionRepo *repository.WorkoutSessionRepository,
 	stateRepo *repository.UserProgramStateRepository,
 	failureService *service.FailureService,
-	eventBus *event.Bus,
+	prService *service.PersonalRecordService,
+	loggedSetService *service.LoggedSetService,
+	outboxService *outbox.Service,
 ) *LoggedSetHandler {
 	return &LoggedSetHandler{
 		repo:               repo,
 		workoutSessionRepo: workoutSessionRepo,
 		stateRepo:          stateRepo,
 		failureService:     failureService,
-		eventBus:           eventBus,
+		prService:          prService,
+		loggedSetService:   loggedSetService,
+		outbox:             outboxService,
 	}
 }
 
@@ -55,6 +66,9 @@ type LoggedSetResponse struct {
 	IsAMRAP        bool      `json:"isAmrap"`
 	RPE            *float64  `json:"rpe,omitempty"`
 	CreatedAt      time.Time `json:"createdAt"`
+	// IsPR and PersonalRecords are only populated when the set is created.
+	IsPR            bool                     `json:"isPr"`
+	PersonalRecords []PersonalRecordResponse `json:"personalRecords,omitempty"`
 }
 
 // CreateLoggedSetRequest represents a single logged set in the batch request.
@@ -171,8 +185,23 @@ func (h *LoggedSetHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if err := h.repo.Create(newSet); err != nil {
-			writeDomainError(w, apperrors.NewInternal("failed to create logged set", err))
+		// Create the set, detecting personal records when configured, and store its
+		// SET_LOGGED and PR_ACHIEVED events in the same transaction
+		var records []service.PersonalRecord
+		err := h.outbox.Transact(r.Context(), func(tx *sql.Tx) ([]event.StateEvent, error) {
+			var err error
+			if h.prService != nil {
+				records, err = h.prService.LogSetWithRecordsTx(r.Context(), tx, newSet)
+			} else {
+				err = h.repo.WithTx(tx).Create(newSet)
+			}
+			if err != nil {
+				return nil, apperrors.NewInternal("failed to create logged set", err)
+			}
+			return setLoggedEvents(userID, programID, newSet, records), nil
+		})
+		if err != nil {
+			writeDomainError(w, err)
 			return
 		}
 
@@ -184,27 +213,56 @@ func (h *LoggedSetHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
 			// best-effort and logged separately.
 		}
 
-		// Emit SET_LOGGED event
-		if h.eventBus != nil {
-			isFailure := newSet.RepsPerformed < newSet.TargetReps
-			evt := event.NewStateEvent(event.EventSetLogged, userID, programID).
-				WithPayload(event.PayloadLoggedSetID, newSet.ID).
-				WithPayload(event.PayloadSessionID, sessionID).
-				WithPayload(event.PayloadLiftID, newSet.LiftID).
-				WithPayload(event.PayloadRepsPerformed, newSet.RepsPerformed).
-				WithPayload(event.PayloadTargetReps, newSet.TargetReps).
-				WithPayload(event.PayloadWeight, newSet.Weight).
-				WithPayload(event.PayloadIsAMRAP, newSet.IsAMRAP).
-				WithPayload(event.PayloadIsFailure, isFailure)
-			h.eventBus.PublishAsync(context.Background(), evt)
+		resp := loggedSetToResponse(newSet)
+		if len(records) > 0 {
+			resp.IsPR = true
+			resp.PersonalRecords = make([]PersonalRecordResponse, len(records))
+			for j := range records {
+				resp.PersonalRecords[j] = personalRecordToResponse(&records[j])
+			}
 		}
-
-		responses = append(responses, loggedSetToResponse(newSet))
+		responses = append(responses, resp)
 	}
 
 	writeData(w, http.StatusCreated, responses)
 }
 
+// setLoggedEvents builds SET_LOGGED for a newly logged set and PR_ACHIEVED for each
+// record it achieved.
+func setLoggedEvents(userID, programID string, ls *loggedset.LoggedSet, records []service.PersonalRecord) []event.StateEvent {
+	isFailure := ls.RepsPerformed < ls.TargetReps
+	evt := event.NewStateEvent(event.EventSetLogged, userID, programID).
+		WithPayload(event.PayloadLoggedSetID, ls.ID).
+		WithPayload(event.PayloadSessionID, ls.SessionID).
+		WithPayload(event.PayloadPrescriptionID, ls.PrescriptionID).
+		WithPayload(event.PayloadLiftID, ls.LiftID).
+		WithPayload(event.PayloadRepsPerformed, ls.RepsPerformed).
+		WithPayload(event.PayloadTargetReps, ls.TargetReps).
+		WithPayload(event.PayloadWeight, ls.Weight).
+		WithPayload(event.PayloadIsAMRAP, ls.IsAMRAP).
+		WithPayload(event.PayloadIsFailure, isFailure)
+	events := []event.StateEvent{evt}
+
+	// Emit PR_ACHIEVED event for each record the set achieved
+	for _, rec := range records {
+		prEvt := event.NewStateEvent(event.EventPRAchieved, userID, programID).
+			WithPayload(event.PayloadRecordID, rec.ID).
+			WithPayload(event.PayloadRecordType, string(rec.RecordType)).
+			WithPayload(event.PayloadRecordValue, rec.Value).
+			WithPayload(event.PayloadLoggedSetID, ls.ID).
+			WithPayload(event.PayloadSessionID, ls.SessionID).
+			WithPayload(event.PayloadLiftID, ls.LiftID)
+		if rec.RepCount != nil {
+			prEvt = prEvt.WithPayload(event.PayloadRepCount, *rec.RepCount)
+		}
+		if rec.PreviousValue != nil {
+			prEvt = prEvt.WithPayload(event.PayloadPreviousValue, *rec.PreviousValue)
+		}
+		events = append(events, prEvt)
+	}
+	return events
+}
+
 // ListBySession handles GET /sessions/{sessionId}/sets
 func (h *LoggedSetHandler) ListBySession(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.PathValue("sessionId")
@@ -243,24 +301,52 @@ func (h *LoggedSetHandler) ListByUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if the requesting user can access this data
-	// Users can only access their own logged sets (or admins can access anyone's)
-	isAdmin := middleware.IsAdmin(r)
-	if currentUserID != userID && !isAdmin {
+	// Users can access their own logged sets; coaches and admins can access others'
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
 		writeDomainError(w, apperrors.NewForbidden("cannot access other user's logged sets"))
 		return
 	}
 
-	// Parse pagination
+	// Parse pagination and sort (default: newest first)
 	query := r.URL.Query()
-	pg := ParsePagination(query)
+	page, err := ParseCursorPage(query)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
 
-	params := repository.LoggedSetListParams{
+	// Filter by lift and creation date range
+	filter := repository.LoggedSetFilter{
 		UserID: userID,
-		Limit:  int64(pg.Limit),
-		Offset: int64(pg.Offset),
+		LiftID: ParseFilterString(query, "liftId"),
+	}
+	if filter.From, err = ParseFilterDate(query, "startDate"); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if filter.To, err = ParseFilterDateEndOfDay(query, "endDate"); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	// Count the first page only; cursor pages carry on from it
+	var total *int64
+	if page.After == nil {
+		count, err := h.repo.CountByUser(filter)
+		if err != nil {
+			writeDomainError(w, apperrors.NewInternal("failed to count logged sets", err))
+			return
+		}
+		total = &count
 	}
 
-	sets, total, err := h.repo.ListByUser(params)
+	sets, next, err := h.repo.ListByUser(repository.LoggedSetListParams{
+		Filter:    filter,
+		SortOrder: page.SortOrder,
+		After:     page.After,
+		Limit:     int64(page.Limit),
+		Offset:    int64(page.Offset),
+	})
 	if err != nil {
 		writeDomainError(w, apperrors.NewInternal("failed to list logged sets", err))
 		return
@@ -271,5 +357,347 @@ func (h *LoggedSetHandler) ListByUser(w http.ResponseWriter, r *http.Request) {
 		data[i] = loggedSetToResponse(&s)
 	}
 
-	writePaginatedData(w, http.StatusOK, data, total, pg.Limit, pg.Offset)
+	writeCursorPaginatedData(w, http.StatusOK, data, page, total, next)
+}
+
+// UpdateLoggedSetRequest represents the request body for correcting a logged set.
+// Only provided fields are changed.
+type UpdateLoggedSetRequest struct {
+	SetNumber     *int     `json:"setNumber,omitempty"`
+	Weight        *float64 `json:"weight,omitempty"`
+	TargetReps    *int     `json:"targetReps,omitempty"`
+	RepsPerformed *int     `json:"repsPerformed,omitempty"`
+	IsAMRAP       *bool    `json:"isAmrap,omitempty"`
+	RPE           *float64 `json:"rpe,omitempty"`
+	// Reason is recorded in the set's revision history.
+	Reason *string `json:"reason,omitempty"`
+}
+
+func (req UpdateLoggedSetRequest) toInput() loggedset.UpdateLoggedSetInput {
+	return loggedset.UpdateLoggedSetInput{
+		SetNumber:     req.SetNumber,
+		Weight:        req.Weight,
+		TargetReps:    req.TargetReps,
+		RepsPerformed: req.RepsPerformed,
+		IsAMRAP:       req.IsAMRAP,
+		RPE:           req.RPE,
+	}
+}
+
+// AmendSetUpdate is a single set correction in a session amendment.
+type AmendSetUpdate struct {
+	SetID         string   `json:"setId"`
+	SetNumber     *int     `json:"setNumber,omitempty"`
+	Weight        *float64 `json:"weight,omitempty"`
+	TargetReps    *int     `json:"targetReps,omitempty"`
+	RepsPerformed *int     `json:"repsPerformed,omitempty"`
+	IsAMRAP       *bool    `json:"isAmrap,omitempty"`
+	RPE           *float64 `json:"rpe,omitempty"`
+}
+
+// AmendSessionRequest represents the request body for amending a session's sets.
+type AmendSessionRequest struct {
+	Reason  string           `json:"reason"`
+	Updates []AmendSetUpdate `json:"updates"`
+	Deletes []string         `json:"deletes"`
+}
+
+// LoggedSetSnapshotResponse is the state of a set before or after a revision.
+type LoggedSetSnapshotResponse struct {
+	SetNumber     int      `json:"setNumber"`
+	Weight        float64  `json:"weight"`
+	TargetReps    int      `json:"targetReps"`
+	RepsPerformed int      `json:"repsPerformed"`
+	IsAMRAP       bool     `json:"isAmrap"`
+	RPE           *float64 `json:"rpe,omitempty"`
+}
+
+// LoggedSetRevisionResponse represents the API response format for a logged set revision.
+type LoggedSetRevisionResponse struct {
+	ID          string                     `json:"id"`
+	LoggedSetID string                     `json:"loggedSetId"`
+	SessionID   string                     `json:"sessionId"`
+	UserID      string                     `json:"userId"`
+	ChangedBy   string                     `json:"changedBy"`
+	Action      string                     `json:"action"`
+	Source      string                     `json:"source"`
+	Reason      *string                    `json:"reason,omitempty"`
+	Previous    LoggedSetSnapshotResponse  `json:"previous"`
+	New         *LoggedSetSnapshotResponse `json:"new,omitempty"`
+	CreatedAt   time.Time                  `json:"createdAt"`
+}
+
+// SetEditResponse represents the outcome of a set correction, deletion, or amendment.
+type SetEditResponse struct {
+	SessionID     string                      `json:"sessionId"`
+	Sets          []LoggedSetResponse         `json:"sets"`
+	DeletedSetIDs []string                    `json:"deletedSetIds"`
+	Revisions     []LoggedSetRevisionResponse `json:"revisions"`
+	// RevertedProgressions are progressions the changed sets had triggered that were undone.
+	RevertedProgressions []service.RevertResult `json:"revertedProgressions"`
+	// ReappliedProgressions are AFTER_SET progressions evaluated again for the corrected sets.
+	ReappliedProgressions []TriggerResultResponse `json:"reappliedProgressions"`
+}
+
+func snapshotToResponse(s service.LoggedSetSnapshot) LoggedSetSnapshotResponse {
+	return LoggedSetSnapshotResponse{
+		SetNumber:     s.SetNumber,
+		Weight:        s.Weight,
+		TargetReps:    s.TargetReps,
+		RepsPerformed: s.RepsPerformed,
+		IsAMRAP:       s.IsAMRAP,
+		RPE:           s.RPE,
+	}
+}
+
+func revisionToResponse(rev *service.LoggedSetRevision) LoggedSetRevisionResponse {
+	resp := LoggedSetRevisionResponse{
+		ID:          rev.ID,
+		LoggedSetID: rev.LoggedSetID,
+		SessionID:   rev.SessionID,
+		UserID:      rev.UserID,
+		ChangedBy:   rev.ChangedBy,
+		Action:      string(rev.Action),
+		Source:      string(rev.Source),
+		Reason:      rev.Reason,
+		Previous:    snapshotToResponse(rev.Previous),
+		CreatedAt:   rev.CreatedAt,
+	}
+	if rev.New != nil {
+		next := snapshotToResponse(*rev.New)
+		resp.New = &next
+	}
+	return resp
+}
+
+func setEditResultToResponse(result *service.SetEditResult) SetEditResponse {
+	resp := SetEditResponse{
+		SessionID:             result.SessionID,
+		Sets:                  make([]LoggedSetResponse, len(result.Sets)),
+		DeletedSetIDs:         result.DeletedSetIDs,
+		Revisions:             make([]LoggedSetRevisionResponse, len(result.Revisions)),
+		RevertedProgressions:  result.RevertedProgressions,
+		ReappliedProgressions: triggerResultsToResponse(result.ReappliedProgressions),
+	}
+	for i := range result.Sets {
+		resp.Sets[i] = loggedSetToResponse(&result.Sets[i])
+	}
+	for i := range result.Revisions {
+		resp.Revisions[i] = revisionToResponse(&result.Revisions[i])
+	}
+	return resp
+}
+
+// writeSetEditError maps a set edit error to its API response.
+func writeSetEditError(w http.ResponseWriter, err error, sessionID, setID string) {
+	var validationErr *service.SetValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		details := make([]string, len(validationErr.Errors))
+		for i, e := range validationErr.Errors {
+			details[i] = e.Error()
+		}
+		writeDomainError(w, apperrors.NewValidationMsg("validation failed for set "+validationErr.LoggedSetID), details...)
+	case errors.Is(err, service.ErrWorkoutSessionNotFound):
+		writeDomainError(w, apperrors.NewNotFound("workout session", sessionID))
+	case errors.Is(err, service.ErrLoggedSetNotFound):
+		if setID != "" {
+			writeDomainError(w, apperrors.NewNotFound("logged set", setID))
+		} else {
+			writeDomainError(w, apperrors.NewBadRequest(err.Error()))
+		}
+	case errors.Is(err, service.ErrSessionRequiresAmendment):
+		writeDomainError(w, apperrors.NewSessionRequiresAmendment(sessionID))
+	case errors.Is(err, service.ErrSessionNotEditable):
+		writeDomainError(w, apperrors.NewSessionNotEditable(string(workoutsession.StatusAbandoned)))
+	case errors.Is(err, service.ErrNoSetChanges),
+		errors.Is(err, service.ErrDuplicateSetChange):
+		writeDomainError(w, apperrors.NewBadRequest(err.Error()))
+	case errors.Is(err, service.ErrAmendReasonRequired),
+		errors.Is(err, service.ErrRevisionReasonTooLong):
+		writeDomainError(w, apperrors.NewValidation("reason", err.Error()))
+	case errors.Is(err, service.ErrProgressionHasDependents),
+		errors.Is(err, service.ErrMaxChangedSinceProgression),
+		errors.Is(err, service.ErrNoCurrentMax):
+		// A progression the set triggered can no longer be undone safely
+		writeDomainError(w, apperrors.NewConflict(err.Error()))
+	default:
+		writeDomainError(w, apperrors.NewInternal("failed to change logged sets", err))
+	}
+}
+
+// authorizeSet loads a logged set in a session and checks the caller may change it.
+// Writes the error response and returns nil when the set cannot be changed.
+func (h *LoggedSetHandler) authorizeSet(w http.ResponseWriter, r *http.Request, sessionID, setID string) *loggedset.LoggedSet {
+	ls, err := h.repo.GetByID(setID)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to get logged set", err))
+		return nil
+	}
+	if ls == nil || ls.SessionID != sessionID {
+		writeDomainError(w, apperrors.NewNotFound("logged set", setID))
+		return nil
+	}
+	if ls.UserID != middleware.GetUserID(r) && !middleware.IsAdmin(r) {
+		writeDomainError(w, apperrors.NewForbidden("you can only change your own logged sets"))
+		return nil
+	}
+	return ls
+}
+
+// authorizeSession checks the caller owns a workout session, is an admin, or is a coach
+// with the permission. An empty permission allows only the owner and admins.
+// Writes the error response and returns false when access is denied.
+func (h *LoggedSetHandler) authorizeSession(w http.ResponseWriter, r *http.Request, sessionID string, permission coaching.Permission) bool {
+	session, err := h.workoutSessionRepo.GetByID(sessionID)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to get workout session", err))
+		return false
+	}
+	if session == nil {
+		writeDomainError(w, apperrors.NewNotFound("workout session", sessionID))
+		return false
+	}
+
+	state, err := h.stateRepo.GetByID(session.UserProgramStateID)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to get program state", err))
+		return false
+	}
+	if state == nil {
+		writeDomainError(w, apperrors.NewInternal("session references invalid program state", nil))
+		return false
+	}
+	if !canAccessUser(r, state.UserID, permission) {
+		writeDomainError(w, apperrors.NewForbidden("you can only manage your own workout sessions"))
+		return false
+	}
+	return true
+}
+
+// Update handles PATCH /sessions/{sessionId}/sets/{setId}
+func (h *LoggedSetHandler) Update(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("sessionId")
+	setID := r.PathValue("setId")
+	if sessionID == "" || setID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing session or set ID"))
+		return
+	}
+
+	if h.authorizeSet(w, r, sessionID, setID) == nil {
+		return
+	}
+
+	var req UpdateLoggedSetRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	result, err := h.loggedSetService.UpdateSet(r.Context(), middleware.GetUserID(r), sessionID, setID, req.toInput(), req.Reason)
+	if err != nil {
+		writeSetEditError(w, err, sessionID, setID)
+		return
+	}
+
+	writeData(w, http.StatusOK, setEditResultToResponse(result))
+}
+
+// Delete handles DELETE /sessions/{sessionId}/sets/{setId}
+// An optional reason for the revision history can be passed as the "reason" query parameter.
+func (h *LoggedSetHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("sessionId")
+	setID := r.PathValue("setId")
+	if sessionID == "" || setID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing session or set ID"))
+		return
+	}
+
+	if h.authorizeSet(w, r, sessionID, setID) == nil {
+		return
+	}
+
+	var reason *string
+	if v := r.URL.Query().Get("reason"); v != "" {
+		reason = &v
+	}
+
+	if _, err := h.loggedSetService.DeleteSet(r.Context(), middleware.GetUserID(r), sessionID, setID, reason); err != nil {
+		writeSetEditError(w, err, sessionID, setID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AmendSession handles POST /workouts/{id}/amend
+// Amending is the only way to change the sets of a completed session.
+func (h *LoggedSetHandler) AmendSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("id")
+	if sessionID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing session ID"))
+		return
+	}
+
+	if !h.authorizeSession(w, r, sessionID, "") {
+		return
+	}
+
+	var req AmendSessionRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	changes := make([]service.LoggedSetChange, 0, len(req.Updates)+len(req.Deletes))
+	for _, u := range req.Updates {
+		changes = append(changes, service.LoggedSetChange{
+			LoggedSetID: u.SetID,
+			Update: loggedset.UpdateLoggedSetInput{
+				SetNumber:     u.SetNumber,
+				Weight:        u.Weight,
+				TargetReps:    u.TargetReps,
+				RepsPerformed: u.RepsPerformed,
+				IsAMRAP:       u.IsAMRAP,
+				RPE:           u.RPE,
+			},
+		})
+	}
+	for _, id := range req.Deletes {
+		changes = append(changes, service.LoggedSetChange{LoggedSetID: id, Delete: true})
+	}
+
+	result, err := h.loggedSetService.AmendSession(r.Context(), middleware.GetUserID(r), sessionID, changes, req.Reason)
+	if err != nil {
+		writeSetEditError(w, err, sessionID, "")
+		return
+	}
+
+	writeData(w, http.StatusOK, setEditResultToResponse(result))
+}
+
+// ListRevisions handles GET /sessions/{sessionId}/sets/revisions
+func (h *LoggedSetHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("sessionId")
+	if sessionID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing session ID"))
+		return
+	}
+
+	if !h.authorizeSession(w, r, sessionID, coaching.PermissionViewLogs) {
+		return
+	}
+
+	revisions, err := h.loggedSetService.ListRevisions(r.Context(), sessionID)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to list logged set revisions", err))
+		return
+	}
+
+	data := make([]LoggedSetRevisionResponse, len(revisions))
+	for i := range revisions {
+		data[i] = revisionToResponse(&revisions[i])
+	}
+
+	writeData(w, http.StatusOK, data)
 }
@@ -630,3 +630,432 @@ func TestLoggedSetHandler_ListByUser(t *testing.T) {
 		}
 	})
 }
+
+// walkCursorPages follows nextCursor from the first page of a list and returns the IDs
+// of every item along with each page's metadata.
+func walkCursorPages(t *testing.T, url, userID string) ([]string, []PaginationMeta) {
+	t.Helper()
+	var ids []string
+	var metas []PaginationMeta
+	next := url
+	for len(metas) < 50 {
+		resp, err := authGetLoggedSets(next, userID)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		var page struct {
+			Data []struct {
+				ID string `json:"id"`
+			} `json:"data"`
+			Meta PaginationMeta `json:"meta"`
+		}
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s: expected status 200, got %d: %s", next, resp.StatusCode, bodyBytes)
+		}
+		if err := json.Unmarshal(bodyBytes, &page); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		for _, item := range page.Data {
+			ids = append(ids, item.ID)
+		}
+		metas = append(metas, page.Meta)
+		if page.Meta.NextCursor == "" {
+			break
+		}
+		next = url + "&cursor=" + page.Meta.NextCursor
+	}
+	return ids, metas
+}
+
+func TestLoggedSetHandler_ListByUserCursor(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID := "ls-cursor-user"
+	createLSTestUser(t, ts, userID)
+	squatID := createLSTestLift(t, ts, "Cursor Squat", "cursor-squat")
+	benchID := createLSTestLift(t, ts, "Cursor Bench", "cursor-bench")
+	cycleID := createLSTestCycle(t, ts, "LS Cursor Cycle")
+	programID := createLSTestProgram(t, ts, "LS Cursor Program", "ls-cursor-program", cycleID)
+	enrollLSTestUser(t, ts, userID, programID)
+	sessionID := startLSWorkoutSession(t, ts, userID)
+	logged := logLSTestSets(t, ts, sessionID, userID, squatID, 5, 5, 5, 4, 3)
+	logged = append(logged, logLSTestSets(t, ts, sessionID, userID, benchID, 8, 8)...)
+
+	listURL := ts.URL("/users/" + userID + "/logged-sets?limit=2")
+
+	t.Run("pages through every set exactly once", func(t *testing.T) {
+		ids, metas := walkCursorPages(t, listURL, userID)
+		if len(ids) != len(logged) || len(metas) != 4 {
+			t.Fatalf("Expected %d sets over 4 pages, got %d over %d", len(logged), len(ids), len(metas))
+		}
+		seen := map[string]bool{}
+		for _, id := range ids {
+			if seen[id] {
+				t.Errorf("Set %s was listed twice", id)
+			}
+			seen[id] = true
+		}
+		for _, id := range logged {
+			if !seen[id] {
+				t.Errorf("Set %s was skipped", id)
+			}
+		}
+
+		if metas[0].Total != int64(len(logged)) || !metas[0].HasMore {
+			t.Errorf("Expected the first page to count every set, got %+v", metas[0])
+		}
+		if metas[1].Total != 0 || !metas[1].HasMore || metas[1].Limit != 2 {
+			t.Errorf("Expected later pages to skip the count, got %+v", metas[1])
+		}
+		if last := metas[len(metas)-1]; last.HasMore || last.NextCursor != "" {
+			t.Errorf("Expected the last page to end the list, got %+v", last)
+		}
+
+		ascending, _ := walkCursorPages(t, listURL+"&sortOrder=asc", userID)
+		for i := range ascending {
+			if ascending[i] != ids[len(ids)-1-i] {
+				t.Fatalf("Expected sortOrder=asc to reverse the default order, got %v and %v", ascending, ids)
+			}
+			// Sets logged together share a timestamp and stay in the order they were logged
+			if ascending[i] != logged[i] {
+				t.Fatalf("Expected sets in the order they were logged, got %v and %v", ascending, logged)
+			}
+		}
+	})
+
+	t.Run("filters by lift and date range", func(t *testing.T) {
+		ids, metas := walkCursorPages(t, listURL+"&liftId="+benchID, userID)
+		if len(ids) != 2 || metas[0].Total != 2 || metas[0].HasMore {
+			t.Errorf("Expected the 2 bench sets on one page, got %v %+v", ids, metas[0])
+		}
+
+		today := time.Now().UTC().Format("2006-01-02")
+		if ids, _ := walkCursorPages(t, listURL+"&startDate="+today+"&endDate="+today, userID); len(ids) != len(logged) {
+			t.Errorf("Expected every set to be logged today, got %d", len(ids))
+		}
+		if ids, metas := walkCursorPages(t, listURL+"&endDate=2000-01-01", userID); len(ids) != 0 || metas[0].Total != 0 {
+			t.Errorf("Expected no sets before 2000, got %d", len(ids))
+		}
+	})
+
+	t.Run("rejects invalid sorts and cursors", func(t *testing.T) {
+		_, metas := walkCursorPages(t, listURL+"&sortOrder=asc", userID)
+		cursor := metas[0].NextCursor
+		for _, query := range []string{
+			"sortOrder=weight",
+			"cursor=not-a-cursor",
+			"sortOrder=desc&cursor=" + cursor,
+			"offset=2&cursor=" + cursor,
+			"startDate=yesterday",
+		} {
+			resp, err := authGetLoggedSets(ts.URL("/users/"+userID+"/logged-sets?"+query), userID)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("%s: expected status 400, got %d", query, resp.StatusCode)
+			}
+		}
+	})
+}
+
+func authLoggedSetRequest(method, url, body, userID string) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", userID)
+	return http.DefaultClient.Do(req)
+}
+
+// SetEditTestResponse wraps the response of a set edit or session amendment.
+type SetEditTestResponse struct {
+	Data struct {
+		SessionID     string                  `json:"sessionId"`
+		Sets          []LoggedSetTestResponse `json:"sets"`
+		DeletedSetIDs []string                `json:"deletedSetIds"`
+		Revisions     []struct {
+			LoggedSetID string  `json:"loggedSetId"`
+			Action      string  `json:"action"`
+			Source      string  `json:"source"`
+			Reason      *string `json:"reason"`
+			Previous    struct {
+				RepsPerformed int `json:"repsPerformed"`
+			} `json:"previous"`
+			New *struct {
+				RepsPerformed int `json:"repsPerformed"`
+			} `json:"new"`
+		} `json:"revisions"`
+	} `json:"data"`
+}
+
+// logLSTestSets logs sets with the given reps performed against a target of 5 and returns their IDs.
+func logLSTestSets(t *testing.T, ts *testutil.TestServer, sessionID, userID, liftID string, reps ...int) []string {
+	t.Helper()
+	prescriptionID := uuid.New().String()
+	sets := make([]map[string]interface{}, len(reps))
+	for i, r := range reps {
+		sets[i] = map[string]interface{}{
+			"prescriptionId": prescriptionID,
+			"liftId":         liftID,
+			"setNumber":      i + 1,
+			"weight":         200.0,
+			"targetReps":     5,
+			"repsPerformed":  r,
+		}
+	}
+	body, _ := json.Marshal(map[string]interface{}{"sets": sets})
+
+	resp, err := authPostLoggedSets(ts.URL("/sessions/"+sessionID+"/sets"), string(body), userID)
+	if err != nil {
+		t.Fatalf("Failed to log sets: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Expected status 201, got %d: %s", resp.StatusCode, bodyBytes)
+	}
+
+	var result LoggedSetTestListResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	ids := make([]string, len(result.Data))
+	for i, s := range result.Data {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+func TestLoggedSetHandler_UpdateAndDelete(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID := "ls-edit-user"
+	otherUserID := "ls-edit-other-user"
+	createLSTestUser(t, ts, userID)
+	createLSTestUser(t, ts, otherUserID)
+	liftID := createLSTestLift(t, ts, "Squat", "squat-ls-edit")
+	cycleID := createLSTestCycle(t, ts, "LS Edit Cycle")
+	programID := createLSTestProgram(t, ts, "LS Edit Program", "ls-edit-program", cycleID)
+	enrollLSTestUser(t, ts, userID, programID)
+	sessionID := startLSWorkoutSession(t, ts, userID)
+	setIDs := logLSTestSets(t, ts, sessionID, userID, liftID, 50, 5)
+	setURL := ts.URL("/sessions/" + sessionID + "/sets/" + setIDs[0])
+
+	t.Run("corrects a set and records a revision", func(t *testing.T) {
+		resp, err := authLoggedSetRequest(http.MethodPatch, setURL, `{"repsPerformed": 5, "reason": "typo"}`, userID)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, bodyBytes)
+		}
+
+		var result SetEditTestResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if len(result.Data.Sets) != 1 || result.Data.Sets[0].RepsPerformed != 5 {
+			t.Errorf("Expected corrected set with 5 reps, got %+v", result.Data.Sets)
+		}
+		if len(result.Data.Revisions) != 1 {
+			t.Fatalf("Expected 1 revision, got %d", len(result.Data.Revisions))
+		}
+		rev := result.Data.Revisions[0]
+		if rev.Action != "UPDATE" || rev.Source != "EDIT" || rev.Previous.RepsPerformed != 50 || rev.New == nil || rev.New.RepsPerformed != 5 {
+			t.Errorf("Unexpected revision: %+v", rev)
+		}
+		if rev.Reason == nil || *rev.Reason != "typo" {
+			t.Errorf("Expected reason 'typo', got %v", rev.Reason)
+		}
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		resp, _ := authLoggedSetRequest(http.MethodPatch, setURL, `{"repsPerformed": -1}`, userID)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("forbids other users", func(t *testing.T) {
+		resp, _ := authLoggedSetRequest(http.MethodPatch, setURL, `{"repsPerformed": 4}`, otherUserID)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("returns 404 for a set in another session", func(t *testing.T) {
+		url := ts.URL("/sessions/" + uuid.New().String() + "/sets/" + setIDs[0])
+		resp, _ := authLoggedSetRequest(http.MethodPatch, url, `{"repsPerformed": 4}`, userID)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("deletes a set", func(t *testing.T) {
+		url := ts.URL("/sessions/" + sessionID + "/sets/" + setIDs[1] + "?reason=duplicate")
+		resp, _ := authLoggedSetRequest(http.MethodDelete, url, "", userID)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			t.Fatalf("Expected status 204, got %d: %s", resp.StatusCode, bodyBytes)
+		}
+
+		listResp, _ := authGetLoggedSets(ts.URL("/sessions/"+sessionID+"/sets"), userID)
+		defer listResp.Body.Close()
+		var list LoggedSetTestListResponse
+		json.NewDecoder(listResp.Body).Decode(&list)
+		if len(list.Data) != 1 || list.Data[0].ID != setIDs[0] {
+			t.Errorf("Expected only the first set to remain, got %+v", list.Data)
+		}
+	})
+
+	t.Run("lists revisions", func(t *testing.T) {
+		resp, _ := authGetLoggedSets(ts.URL("/sessions/"+sessionID+"/sets/revisions"), userID)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+		var result struct {
+			Data []struct {
+				LoggedSetID string `json:"loggedSetId"`
+				Action      string `json:"action"`
+			} `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		if len(result.Data) != 2 || result.Data[0].Action != "UPDATE" || result.Data[1].Action != "DELETE" {
+			t.Errorf("Expected UPDATE then DELETE revisions, got %+v", result.Data)
+		}
+
+		otherResp, _ := authGetLoggedSets(ts.URL("/sessions/"+sessionID+"/sets/revisions"), otherUserID)
+		defer otherResp.Body.Close()
+		if otherResp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403 for other user, got %d", otherResp.StatusCode)
+		}
+	})
+
+	t.Run("requires amendment once the session is completed", func(t *testing.T) {
+		finishLSWorkoutSession(t, ts, sessionID, userID)
+
+		resp, _ := authLoggedSetRequest(http.MethodPatch, setURL, `{"repsPerformed": 4}`, userID)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("Expected status 409, got %d", resp.StatusCode)
+		}
+		var errResp struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		if errResp.Error.Code != "session_requires_amendment" {
+			t.Errorf("Expected session_requires_amendment, got %s", errResp.Error.Code)
+		}
+	})
+}
+
+func TestLoggedSetHandler_AmendSession(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID := "ls-amend-user"
+	createLSTestUser(t, ts, userID)
+	liftID := createLSTestLift(t, ts, "Deadlift", "deadlift-ls-amend")
+	cycleID := createLSTestCycle(t, ts, "LS Amend Cycle")
+	programID := createLSTestProgram(t, ts, "LS Amend Program", "ls-amend-program", cycleID)
+	enrollLSTestUser(t, ts, userID, programID)
+	sessionID := startLSWorkoutSession(t, ts, userID)
+	setIDs := logLSTestSets(t, ts, sessionID, userID, liftID, 50, 5, 5)
+	finishLSWorkoutSession(t, ts, sessionID, userID)
+	amendURL := ts.URL("/workouts/" + sessionID + "/amend")
+
+	t.Run("requires a reason", func(t *testing.T) {
+		body := `{"updates": [{"setId": "` + setIDs[0] + `", "repsPerformed": 5}]}`
+		resp, _ := authLoggedSetRequest(http.MethodPost, amendURL, body, userID)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("rejects unknown sets without applying other changes", func(t *testing.T) {
+		body := `{"reason": "fix", "updates": [{"setId": "` + setIDs[0] + `", "repsPerformed": 5}], "deletes": ["` + uuid.New().String() + `"]}`
+		resp, _ := authLoggedSetRequest(http.MethodPost, amendURL, body, userID)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+
+		listResp, _ := authGetLoggedSets(ts.URL("/sessions/"+sessionID+"/sets"), userID)
+		defer listResp.Body.Close()
+		var list LoggedSetTestListResponse
+		json.NewDecoder(listResp.Body).Decode(&list)
+		for _, s := range list.Data {
+			if s.ID == setIDs[0] && s.RepsPerformed != 50 {
+				t.Errorf("Expected set to be unchanged after failed amendment, got %d reps", s.RepsPerformed)
+			}
+		}
+	})
+
+	t.Run("amends a completed session", func(t *testing.T) {
+		body := `{
+			"reason": "typed 50 instead of 5",
+			"updates": [{"setId": "` + setIDs[0] + `", "repsPerformed": 5}],
+			"deletes": ["` + setIDs[2] + `"]
+		}`
+		resp, err := authLoggedSetRequest(http.MethodPost, amendURL, body, userID)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, bodyBytes)
+		}
+
+		var result SetEditTestResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if len(result.Data.Sets) != 1 || result.Data.Sets[0].RepsPerformed != 5 {
+			t.Errorf("Expected amended set with 5 reps, got %+v", result.Data.Sets)
+		}
+		if len(result.Data.DeletedSetIDs) != 1 || result.Data.DeletedSetIDs[0] != setIDs[2] {
+			t.Errorf("Expected deleted set %s, got %v", setIDs[2], result.Data.DeletedSetIDs)
+		}
+		for _, rev := range result.Data.Revisions {
+			if rev.Source != "AMEND" {
+				t.Errorf("Expected AMEND revision source, got %s", rev.Source)
+			}
+		}
+	})
+
+	t.Run("forbids other users", func(t *testing.T) {
+		otherUserID := "ls-amend-other-user"
+		createLSTestUser(t, ts, otherUserID)
+		body := `{"reason": "fix", "deletes": ["` + setIDs[1] + `"]}`
+		resp, _ := authLoggedSetRequest(http.MethodPost, amendURL, body, otherUserID)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -2,13 +2,14 @@
 package api
 
 import (
+	"database/sql"
 	"errors"
 	"net/http"
 	"time"
 
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/progression"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
-	"github.com/waynenilsen/power-pro-v3/internal/middleware"
 	"github.com/waynenilsen/power-pro-v3/internal/service"
 )
 
@@ -22,11 +23,20 @@ func NewManualTriggerHandler(progressionService *service.ProgressionService) *Ma
 	return &ManualTriggerHandler{progressionService: progressionService}
 }
 
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *ManualTriggerHandler) WithTx(tx *sql.Tx) *ManualTriggerHandler {
+	txHandler := *h
+	txHandler.progressionService = h.progressionService.WithTx(tx)
+	return &txHandler
+}
+
 // TriggerRequest represents the request body for manual progression trigger.
 type TriggerRequest struct {
 	ProgressionID string `json:"progressionId"`
 	LiftID        string `json:"liftId,omitempty"`
 	Force         bool   `json:"force"`
+	// DryRun previews the progression without persisting anything.
+	DryRun bool `json:"dryRun"`
 }
 
 // TriggerResultResponse represents a single progression result in the API response.
@@ -38,6 +48,10 @@ type TriggerResultResponse struct {
 	SkipReason    string                   `json:"skipReason,omitempty"`
 	Result        *ProgressionResultDetail `json:"result,omitempty"`
 	Error         string                   `json:"error,omitempty"`
+	// StageChange is set when a stage progression moved to a different stage.
+	StageChange *service.StageChange `json:"stageChange,omitempty"`
+	// FailureCounterChange is set when the progression reset the failure counter.
+	FailureCounterChange *service.FailureCounterChange `json:"failureCounterChange,omitempty"`
 }
 
 // ProgressionResultDetail contains the details of an applied progression.
@@ -50,11 +64,62 @@ type ProgressionResultDetail struct {
 }
 
 // TriggerResponse represents the response for manual progression trigger.
+// It is also used for dry-run previews of session completion and week advancement.
 type TriggerResponse struct {
+	// TriggerType is set on session and week previews.
+	TriggerType  string                  `json:"triggerType,omitempty"`
 	Results      []TriggerResultResponse `json:"results"`
 	TotalApplied int                     `json:"totalApplied"`
 	TotalSkipped int                     `json:"totalSkipped"`
 	TotalErrors  int                     `json:"totalErrors"`
+	// DryRun is true when nothing was persisted.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// triggerResultsToResponse converts progression trigger results to the API response format.
+func triggerResultsToResponse(results []service.TriggerResult) []TriggerResultResponse {
+	responses := make([]TriggerResultResponse, len(results))
+	for i, tr := range results {
+		resp := TriggerResultResponse{
+			ProgressionID:        tr.ProgressionID,
+			LiftID:               tr.LiftID,
+			Applied:              tr.Applied,
+			Skipped:              tr.Skipped,
+			SkipReason:           tr.SkipReason,
+			Error:                tr.Error,
+			StageChange:          tr.StageChange,
+			FailureCounterChange: tr.FailureCounterChange,
+		}
+
+		if tr.Result != nil {
+			maxType := string(tr.Result.MaxType)
+			if maxType == "" {
+				maxType = string(progression.TrainingMax)
+			}
+			resp.Result = &ProgressionResultDetail{
+				PreviousValue: tr.Result.PreviousValue,
+				NewValue:      tr.Result.NewValue,
+				Delta:         tr.Result.Delta,
+				MaxType:       maxType,
+				AppliedAt:     tr.Result.AppliedAt,
+			}
+		}
+
+		responses[i] = resp
+	}
+	return responses
+}
+
+// aggregateResultToResponse converts the result of a trigger event to the API response format.
+func aggregateResultToResponse(result *service.AggregateResult) TriggerResponse {
+	return TriggerResponse{
+		TriggerType:  string(result.TriggerType),
+		Results:      triggerResultsToResponse(result.Results),
+		TotalApplied: result.TotalApplied,
+		TotalSkipped: result.TotalSkipped,
+		TotalErrors:  result.TotalErrors,
+		DryRun:       result.DryRun,
+	}
 }
 
 // Trigger handles POST /users/{userId}/progressions/trigger
@@ -66,10 +131,8 @@ func (h *ManualTriggerHandler) Trigger(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Authorization: user can trigger their own progressions, admin can trigger for any user
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if !isAdmin && authUserID != userID {
+	// Authorization: user can trigger their own progressions, coaches and admins can trigger for others
+	if !canAccessUser(r, userID, coaching.PermissionEditMaxes) {
 		writeDomainError(w, apperrors.NewForbidden("you do not have permission to trigger progressions for this user"))
 		return
 	}
@@ -87,8 +150,14 @@ func (h *ManualTriggerHandler) Trigger(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Apply progression manually
-	result, err := h.progressionService.ApplyProgressionManually(r.Context(), userID, req.ProgressionID, req.LiftID, req.Force)
+	// Apply progression manually, or preview it for a dry run
+	var result *service.ManualTriggerResult
+	var err error
+	if req.DryRun {
+		result, err = h.progressionService.DryRunProgressionManually(r.Context(), userID, req.ProgressionID, req.LiftID, req.Force)
+	} else {
+		result, err = h.progressionService.ApplyProgressionManually(r.Context(), userID, req.ProgressionID, req.LiftID, req.Force)
+	}
 	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrProgressionNotFound):
@@ -107,33 +176,11 @@ func (h *ManualTriggerHandler) Trigger(w http.ResponseWriter, r *http.Request) {
 
 	// Convert to API response format
 	response := TriggerResponse{
-		Results:      make([]TriggerResultResponse, len(result.Results)),
+		Results:      triggerResultsToResponse(result.Results),
 		TotalApplied: result.TotalApplied,
 		TotalSkipped: result.TotalSkipped,
 		TotalErrors:  result.TotalErrors,
-	}
-
-	for i, tr := range result.Results {
-		resp := TriggerResultResponse{
-			ProgressionID: tr.ProgressionID,
-			LiftID:        tr.LiftID,
-			Applied:       tr.Applied,
-			Skipped:       tr.Skipped,
-			SkipReason:    tr.SkipReason,
-			Error:         tr.Error,
-		}
-
-		if tr.Result != nil {
-			resp.Result = &ProgressionResultDetail{
-				PreviousValue: tr.Result.PreviousValue,
-				NewValue:      tr.Result.NewValue,
-				Delta:         tr.Result.Delta,
-				MaxType:       string(progression.TrainingMax), // Default, actual maxType is in result
-				AppliedAt:     tr.Result.AppliedAt,
-			}
-		}
-
-		response.Results[i] = resp
+		DryRun:       result.DryRun,
 	}
 
 	writeData(w, http.StatusOK, response)
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/api"
 	"github.com/waynenilsen/power-pro-v3/internal/db"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/progression"
 	"github.com/waynenilsen/power-pro-v3/internal/testutil"
@@ -932,3 +933,68 @@ func TestManualTriggerWithTestServer(t *testing.T) {
 		}
 	})
 }
+
+func TestManualTriggerDryRun(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID, liftID, progressionID, _ := setupManualTriggerTestData(t, ts)
+
+	trigger := func(dryRun bool) api.TriggerResponse {
+		t.Helper()
+		body := map[string]interface{}{
+			"progressionId": progressionID,
+			"liftId":        liftID,
+			"dryRun":        dryRun,
+		}
+		resp, err := authPostTrigger(ts.URL("/users/"+userID+"/progressions/trigger"), body, userID)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			respBody, _ := io.ReadAll(resp.Body)
+			t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, respBody)
+		}
+
+		var envelope struct {
+			Data api.TriggerResponse `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return envelope.Data
+	}
+
+	// Repeated dry runs preview the same application because nothing is persisted
+	var previewValue float64
+	for i := 0; i < 2; i++ {
+		preview := trigger(true)
+		if !preview.DryRun {
+			t.Error("Expected dryRun=true")
+		}
+		if preview.TotalApplied != 1 || preview.Results[0].Result == nil {
+			t.Fatalf("Expected one previewed application, got %+v", preview.Results)
+		}
+		r := preview.Results[0].Result
+		if r.NewValue != r.PreviousValue+5 {
+			t.Errorf("Expected newValue=%f, got %f", r.PreviousValue+5, r.NewValue)
+		}
+		if i > 0 && r.NewValue != previewValue {
+			t.Errorf("Expected repeated dry run to preview %f, got %f", previewValue, r.NewValue)
+		}
+		previewValue = r.NewValue
+	}
+
+	applied := trigger(false)
+	if applied.DryRun {
+		t.Error("Expected dryRun=false")
+	}
+	if applied.TotalApplied != 1 || applied.Results[0].Result.NewValue != previewValue {
+		t.Errorf("Expected application to match preview %f, got %+v", previewValue, applied.Results)
+	}
+}
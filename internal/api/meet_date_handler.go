@@ -4,9 +4,9 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/userprogramstate"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
-	"github.com/waynenilsen/power-pro-v3/internal/middleware"
 	"github.com/waynenilsen/power-pro-v3/internal/repository"
 )
 
@@ -55,10 +55,8 @@ func (h *MeetDateHandler) SetMeetDate(w http.ResponseWriter, r *http.Request) {
 	// programId is in the path but we don't use it for now since users can only have one enrollment
 	// This matches the API design pattern for future multi-program support
 
-	// Authorization check: only the user themselves or an admin can set meet date
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if authUserID != userID && !isAdmin {
+	// Authorization check: the user, their coach or an admin can set meet date
+	if !canAccessUser(r, userID, coaching.PermissionManageEnrollment) {
 		writeDomainError(w, apperrors.NewForbidden("you can only manage your own program state"))
 		return
 	}
@@ -129,10 +127,8 @@ func (h *MeetDateHandler) GetCountdown(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Authorization check: only the user themselves or an admin can view countdown
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if authUserID != userID && !isAdmin {
+	// Authorization check: the user, their coach or an admin can view countdown
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
 		writeDomainError(w, apperrors.NewForbidden("you can only view your own program state"))
 		return
 	}
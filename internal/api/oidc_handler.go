@@ -0,0 +1,114 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/oidc"
+)
+
+// OIDCHandler handles HTTP requests for single sign-on and linked identities.
+type OIDCHandler struct {
+	service *oidc.Service
+}
+
+// NewOIDCHandler creates a new OIDCHandler.
+func NewOIDCHandler(service *oidc.Service) *OIDCHandler {
+	return &OIDCHandler{service: service}
+}
+
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *OIDCHandler) WithTx(tx *sql.Tx) *OIDCHandler {
+	txHandler := *h
+	txHandler.service = h.service.WithTx(tx)
+	return &txHandler
+}
+
+// IdentityProviderResponse represents the API response format for an identity provider.
+type IdentityProviderResponse struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// IdentityResponse represents the API response format for a linked identity.
+type IdentityResponse struct {
+	ID          string    `json:"id"`
+	Provider    string    `json:"provider"`
+	Subject     string    `json:"subject"`
+	Email       *string   `json:"email"`
+	CreatedAt   time.Time `json:"createdAt"`
+	LastLoginAt time.Time `json:"lastLoginAt"`
+}
+
+// ListProviders handles GET /auth/oidc/providers
+func (h *OIDCHandler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	providers := h.service.Providers()
+	data := make([]IdentityProviderResponse, len(providers))
+	for i, p := range providers {
+		data[i] = IdentityProviderResponse{Name: p.Name, DisplayName: p.DisplayName}
+	}
+	writeData(w, http.StatusOK, data)
+}
+
+// Login handles GET /auth/oidc/{provider}/login
+// Redirects the user to the identity provider to sign in.
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	authURL, err := h.service.Begin(r.Context(), r.PathValue("provider"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback handles GET /auth/oidc/{provider}/callback
+// Completes the sign-in with the code and state the provider returned and starts a session,
+// or a two-factor challenge like /auth/login.
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if providerErr := q.Get("error"); providerErr != "" {
+		writeDomainError(w, apperrors.NewUnauthorized("identity provider returned an error: "+providerErr))
+		return
+	}
+
+	result, err := h.service.Complete(clientContext(r), r.PathValue("provider"), q.Get("code"), q.Get("state"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeLoginResult(w, result)
+}
+
+// ListIdentities handles GET /users/{userId}/identities
+func (h *OIDCHandler) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	identities, err := h.service.ListIdentities(r.Context(), r.PathValue("userId"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	data := make([]IdentityResponse, len(identities))
+	for i, identity := range identities {
+		data[i] = IdentityResponse{
+			ID:          identity.ID,
+			Provider:    identity.Provider,
+			Subject:     identity.Subject,
+			Email:       identity.Email,
+			CreatedAt:   identity.CreatedAt,
+			LastLoginAt: identity.LastLoginAt,
+		}
+	}
+	writeData(w, http.StatusOK, data)
+}
+
+// Unlink handles DELETE /users/{userId}/identities/{identityId}
+func (h *OIDCHandler) Unlink(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Unlink(r.Context(), r.PathValue("userId"), r.PathValue("identityId")); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
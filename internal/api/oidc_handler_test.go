@@ -0,0 +1,122 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/waynenilsen/power-pro-v3/internal/oidc"
+	"github.com/waynenilsen/power-pro-v3/internal/oidc/oidctest"
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// ssoLogin follows the login redirects through the stand-in provider to the callback and
+// returns the callback's status and decoded login response.
+func ssoLogin(t *testing.T, ts *testutil.TestServer) (int, LoginTestResponse) {
+	t.Helper()
+	resp, err := http.Get(ts.URL("/auth/oidc/gym-idp/login"))
+	if err != nil {
+		t.Fatalf("Failed to sign in: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Data LoginTestResponse `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusOK {
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			t.Fatalf("Failed to decode login response: %v", err)
+		}
+	}
+	return resp.StatusCode, envelope.Data
+}
+
+// LoginTestResponse represents a login response in single sign-on tests.
+type LoginTestResponse struct {
+	Token string           `json:"token"`
+	User  UserTestResponse `json:"user"`
+}
+
+func TestOIDCHandler(t *testing.T) {
+	idp := oidctest.NewProvider("http://idp.test", "powerpro", "client-secret")
+	ts, err := testutil.NewTestServer(testutil.WithOIDCProviders(oidc.ProviderConfig{
+		Name:         "gym-idp",
+		DisplayName:  "Gym Login",
+		Issuer:       "http://idp.test",
+		ClientID:     "powerpro",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://powerpro.test/auth/oidc/gym-idp/callback",
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+	ts.HandleHost("idp.test", idp)
+
+	t.Run("lists providers", func(t *testing.T) {
+		var providers []struct {
+			Name        string `json:"name"`
+			DisplayName string `json:"displayName"`
+		}
+		coachingRequest(t, http.MethodGet, ts.URL("/auth/oidc/providers"), nil, "", http.StatusOK, &providers)
+		if len(providers) != 1 || providers[0].Name != "gym-idp" || providers[0].DisplayName != "Gym Login" {
+			t.Errorf("Unexpected providers: %+v", providers)
+		}
+	})
+
+	var login LoginTestResponse
+
+	t.Run("sign-in provisions an account and starts a session", func(t *testing.T) {
+		idp.SetUser(oidctest.User{Subject: "member-42", Email: "sso@example.com", EmailVerified: true, Name: "SSO Lifter"})
+
+		var status int
+		status, login = ssoLogin(t, ts)
+		if status != http.StatusOK || login.Token == "" || login.User.Email != "sso@example.com" {
+			t.Fatalf("Unexpected sign-in result %d: %+v", status, login)
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, ts.URL("/auth/me"), nil)
+		req.Header.Set("Authorization", "Bearer "+login.Token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected the session to authenticate, got %d", resp.StatusCode)
+		}
+
+		status, again := ssoLogin(t, ts)
+		if status != http.StatusOK || again.User.ID != login.User.ID {
+			t.Errorf("Expected the same user on the next sign-in, got %d: %+v", status, again)
+		}
+	})
+
+	t.Run("identities can be listed but the last one of a passwordless user kept", func(t *testing.T) {
+		var identities []struct {
+			ID       string `json:"id"`
+			Provider string `json:"provider"`
+			Subject  string `json:"subject"`
+		}
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+login.User.ID+"/identities"), nil, login.User.ID, http.StatusOK, &identities)
+		if len(identities) != 1 || identities[0].Provider != "gym-idp" || identities[0].Subject != "member-42" {
+			t.Fatalf("Unexpected identities: %+v", identities)
+		}
+		coachingRequest(t, http.MethodDelete, ts.URL("/users/"+login.User.ID+"/identities/"+identities[0].ID), nil, login.User.ID, http.StatusConflict, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+login.User.ID+"/identities"), nil, "someone-else", http.StatusForbidden, nil)
+	})
+
+	t.Run("failed sign-ins", func(t *testing.T) {
+		idp.SetDeny(true)
+		status, _ := ssoLogin(t, ts)
+		idp.SetDeny(false)
+		if status != http.StatusUnauthorized {
+			t.Errorf("Expected status %d when the provider denies the sign-in, got %d", http.StatusUnauthorized, status)
+		}
+
+		coachingRequest(t, http.MethodGet, ts.URL("/auth/oidc/gym-idp/callback?code=forged&state=forged"), nil, "", http.StatusUnauthorized, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/auth/oidc/unknown/login"), nil, "", http.StatusNotFound, nil)
+	})
+}
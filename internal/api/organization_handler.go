@@ -0,0 +1,533 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/dashboard"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/organization"
+)
+
+// OrganizationHandler handles HTTP requests for organizations, their members and
+// invitations, and the roster dashboard.
+type OrganizationHandler struct {
+	orgService       *organization.Service
+	dashboardService *dashboard.Service
+}
+
+// NewOrganizationHandler creates a new OrganizationHandler.
+func NewOrganizationHandler(orgService *organization.Service, dashboardService *dashboard.Service) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService:       orgService,
+		dashboardService: dashboardService,
+	}
+}
+
+// CreateOrganizationRequest represents the request body for creating an organization.
+// An empty slug is generated from the name.
+type CreateOrganizationRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug,omitempty"`
+}
+
+// UpdateOrganizationRequest represents the request body for updating an organization.
+type UpdateOrganizationRequest struct {
+	Name *string `json:"name,omitempty"`
+	Slug *string `json:"slug,omitempty"`
+}
+
+// InviteOrganizationMemberRequest represents the request body for inviting a member.
+// The user is identified by userId or email; the role defaults to ATHLETE.
+type InviteOrganizationMemberRequest struct {
+	UserID string `json:"userId,omitempty"`
+	Email  string `json:"email,omitempty"`
+	Role   string `json:"role,omitempty"`
+}
+
+// UpdateOrganizationMemberRequest represents the request body for changing a member's role.
+type UpdateOrganizationMemberRequest struct {
+	Role string `json:"role"`
+}
+
+// OrganizationResponse represents the API response format for an organization.
+type OrganizationResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// OrganizationMemberResponse represents the API response format for a membership.
+type OrganizationMemberResponse struct {
+	OrganizationID string    `json:"organizationId"`
+	UserID         string    `json:"userId"`
+	Role           string    `json:"role"`
+	JoinedAt       time.Time `json:"joinedAt"`
+}
+
+// OrganizationInvitationResponse represents the API response format for an invitation.
+type OrganizationInvitationResponse struct {
+	ID             string     `json:"id"`
+	OrganizationID string     `json:"organizationId"`
+	UserID         string     `json:"userId"`
+	Role           string     `json:"role"`
+	Status         string     `json:"status"`
+	InvitedBy      string     `json:"invitedBy"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	RespondedAt    *time.Time `json:"respondedAt,omitempty"`
+}
+
+// RosterSummaryResponse totals the roster dashboard.
+type RosterSummaryResponse struct {
+	Users     int `json:"users"`
+	Enrolled  int `json:"enrolled"`
+	InSession int `json:"inSession"`
+}
+
+// RosterEntryResponse is one member's dashboard within the roster dashboard.
+type RosterEntryResponse struct {
+	UserID    string            `json:"userId"`
+	Role      string            `json:"role"`
+	Dashboard DashboardResponse `json:"dashboard"`
+}
+
+// OrganizationDashboardResponse represents the response for GET /organizations/{id}/dashboard.
+type OrganizationDashboardResponse struct {
+	Summary RosterSummaryResponse `json:"summary"`
+	Entries []RosterEntryResponse `json:"entries"`
+}
+
+func organizationToResponse(org *organization.Organization) OrganizationResponse {
+	return OrganizationResponse{
+		ID:        org.ID,
+		Name:      org.Name,
+		Slug:      org.Slug,
+		CreatedBy: org.CreatedBy,
+		CreatedAt: org.CreatedAt,
+		UpdatedAt: org.UpdatedAt,
+	}
+}
+
+func organizationMemberToResponse(m *organization.Member) OrganizationMemberResponse {
+	return OrganizationMemberResponse{
+		OrganizationID: m.OrganizationID,
+		UserID:         m.UserID,
+		Role:           string(m.Role),
+		JoinedAt:       m.JoinedAt,
+	}
+}
+
+func organizationInvitationToResponse(inv *organization.Invitation) OrganizationInvitationResponse {
+	return OrganizationInvitationResponse{
+		ID:             inv.ID,
+		OrganizationID: inv.OrganizationID,
+		UserID:         inv.UserID,
+		Role:           string(inv.Role),
+		Status:         string(inv.Status),
+		InvitedBy:      inv.InvitedBy,
+		CreatedAt:      inv.CreatedAt,
+		RespondedAt:    inv.RespondedAt,
+	}
+}
+
+func organizationInvitationsToResponse(invitations []organization.Invitation) []OrganizationInvitationResponse {
+	data := make([]OrganizationInvitationResponse, len(invitations))
+	for i := range invitations {
+		data[i] = organizationInvitationToResponse(&invitations[i])
+	}
+	return data
+}
+
+// parseOrganizationRole parses the optional role query parameter.
+func parseOrganizationRole(r *http.Request) *organization.Role {
+	value := ParseFilterString(r.URL.Query(), "role")
+	if value == nil {
+		return nil
+	}
+	role := organization.Role(*value)
+	return &role
+}
+
+// authorize checks the organization exists and the caller holds one of the roles in it.
+// Admins pass unless adminAllowed is false. No roles allows any member.
+func (h *OrganizationHandler) authorize(r *http.Request, organizationID string, adminAllowed bool, roles ...organization.Role) error {
+	if organizationID == "" {
+		return apperrors.NewBadRequest("missing organization ID")
+	}
+	if _, err := h.orgService.Get(r.Context(), organizationID); err != nil {
+		return err
+	}
+	if adminAllowed && middleware.IsAdmin(r) {
+		return nil
+	}
+
+	role, err := h.orgService.Role(r.Context(), organizationID, middleware.GetUserID(r))
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		return apperrors.NewForbidden("you are not a member of this organization")
+	}
+	if len(roles) == 0 {
+		return nil
+	}
+	for _, allowed := range roles {
+		if role == allowed {
+			return nil
+		}
+	}
+	return apperrors.NewForbidden("your organization role does not allow this")
+}
+
+// Create handles POST /organizations
+// The caller becomes the organization's owner.
+func (h *OrganizationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateOrganizationRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	org, err := h.orgService.Create(r.Context(), middleware.GetUserID(r), organization.CreateRequest{
+		Name: req.Name,
+		Slug: req.Slug,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusCreated, organizationToResponse(org))
+}
+
+// List handles GET /organizations
+// Lists the caller's organizations by name; admins see every organization.
+func (h *OrganizationHandler) List(w http.ResponseWriter, r *http.Request) {
+	var memberID *string
+	if !middleware.IsAdmin(r) {
+		userID := middleware.GetUserID(r)
+		memberID = &userID
+	}
+
+	pg := ParsePagination(r.URL.Query())
+	orgs, total, err := h.orgService.List(r.Context(), memberID, int64(pg.Limit), int64(pg.Offset))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	data := make([]OrganizationResponse, len(orgs))
+	for i := range orgs {
+		data[i] = organizationToResponse(&orgs[i])
+	}
+
+	writePaginatedData(w, http.StatusOK, data, total, pg.Limit, pg.Offset)
+}
+
+// Get handles GET /organizations/{id}
+func (h *OrganizationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.authorize(r, id, true); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	org, err := h.orgService.Get(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, organizationToResponse(org))
+}
+
+// Update handles PUT /organizations/{id}
+// Only owners (or an admin) can rename an organization.
+func (h *OrganizationHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.authorize(r, id, true, organization.RoleOwner); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	var req UpdateOrganizationRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	org, err := h.orgService.Update(r.Context(), id, organization.UpdateRequest{
+		Name: req.Name,
+		Slug: req.Slug,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, organizationToResponse(org))
+}
+
+// Delete handles DELETE /organizations/{id}
+// Only owners (or an admin) can delete an organization, once it owns no catalog entries.
+func (h *OrganizationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.authorize(r, id, true, organization.RoleOwner); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	if err := h.orgService.Delete(r.Context(), id); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListMembers handles GET /organizations/{id}/members
+// Returns the roster in the order members joined, optionally filtered by role.
+func (h *OrganizationHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.authorize(r, id, true); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	members, err := h.orgService.ListMembers(r.Context(), id, parseOrganizationRole(r))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	data := make([]OrganizationMemberResponse, len(members))
+	for i := range members {
+		data[i] = organizationMemberToResponse(&members[i])
+	}
+
+	writeData(w, http.StatusOK, data)
+}
+
+// Invite handles POST /organizations/{id}/invitations
+// Only owners (or an admin) can invite members; the user joins once they accept.
+func (h *OrganizationHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.authorize(r, id, true, organization.RoleOwner); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	var req InviteOrganizationMemberRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	inv, err := h.orgService.Invite(r.Context(), id, middleware.GetUserID(r), organization.InviteRequest{
+		UserID: req.UserID,
+		Email:  req.Email,
+		Role:   organization.Role(req.Role),
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusCreated, organizationInvitationToResponse(inv))
+}
+
+// ListInvitations handles GET /organizations/{id}/invitations
+// Only owners (or an admin) can view an organization's pending invitations.
+func (h *OrganizationHandler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.authorize(r, id, true, organization.RoleOwner); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	invitations, err := h.orgService.ListInvitations(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, organizationInvitationsToResponse(invitations))
+}
+
+// ListUserInvitations handles GET /users/{userId}/organization-invitations
+// Lists the user's pending invitations, oldest first.
+func (h *OrganizationHandler) ListUserInvitations(w http.ResponseWriter, r *http.Request) {
+	invitations, err := h.orgService.ListUserInvitations(r.Context(), r.PathValue("userId"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, organizationInvitationsToResponse(invitations))
+}
+
+// AcceptInvitation handles POST /organization-invitations/{id}/accept
+// Only the invited user (or an admin) can accept; the response is the new membership.
+func (h *OrganizationHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	inv, err := h.getInvitee(r)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	member, err := h.orgService.AcceptInvitation(r.Context(), inv.ID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, organizationMemberToResponse(member))
+}
+
+// DeclineInvitation handles POST /organization-invitations/{id}/decline
+// Only the invited user (or an admin) can decline.
+func (h *OrganizationHandler) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	inv, err := h.getInvitee(r)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	inv, err = h.orgService.DeclineInvitation(r.Context(), inv.ID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, organizationInvitationToResponse(inv))
+}
+
+// CancelInvitation handles DELETE /organization-invitations/{id}
+// Only the organization's owners (or an admin) can withdraw a pending invitation.
+func (h *OrganizationHandler) CancelInvitation(w http.ResponseWriter, r *http.Request) {
+	inv, err := h.orgService.GetInvitation(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if err := h.authorize(r, inv.OrganizationID, true, organization.RoleOwner); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	if err := h.orgService.CancelInvitation(r.Context(), inv.ID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getInvitee loads an invitation and checks the caller is the invited user or an admin.
+func (h *OrganizationHandler) getInvitee(r *http.Request) (*organization.Invitation, error) {
+	inv, err := h.orgService.GetInvitation(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return nil, err
+	}
+	if inv.UserID != middleware.GetUserID(r) && !middleware.IsAdmin(r) {
+		return nil, apperrors.NewForbidden("only the invited user can respond to this invitation")
+	}
+	return inv, nil
+}
+
+// UpdateMember handles PUT /organizations/{id}/members/{userId}
+// Only owners (or an admin) can change roles; the last owner cannot be demoted.
+func (h *OrganizationHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.authorize(r, id, true, organization.RoleOwner); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	var req UpdateOrganizationMemberRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	member, err := h.orgService.UpdateMemberRole(r.Context(), id, r.PathValue("userId"), organization.Role(req.Role))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, organizationMemberToResponse(member))
+}
+
+// RemoveMember handles DELETE /organizations/{id}/members/{userId}
+// Owners (or an admin) can remove anyone, and members can leave; the last owner cannot.
+func (h *OrganizationHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	userID := r.PathValue("userId")
+	if userID == middleware.GetUserID(r) {
+		if err := h.authorize(r, id, true); err != nil {
+			writeDomainError(w, err)
+			return
+		}
+	} else if err := h.authorize(r, id, true, organization.RoleOwner); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	if err := h.orgService.RemoveMember(r.Context(), id, userID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Dashboard handles GET /organizations/{id}/dashboard
+// Returns every member's dashboard, optionally filtered by role, with roster totals.
+// Like personal dashboards, only the organization's owners and coaches can view it;
+// admins have no access.
+func (h *OrganizationHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.authorize(r, id, false, organization.RoleOwner, organization.RoleCoach); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	members, err := h.orgService.ListMembers(r.Context(), id, parseOrganizationRole(r))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	userIDs := make([]string, len(members))
+	for i, m := range members {
+		userIDs[i] = m.UserID
+	}
+
+	roster, err := h.dashboardService.GetRosterDashboard(r.Context(), userIDs)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	response := OrganizationDashboardResponse{
+		Summary: RosterSummaryResponse{
+			Users:     roster.Summary.Users,
+			Enrolled:  roster.Summary.Enrolled,
+			InSession: roster.Summary.InSession,
+		},
+		Entries: make([]RosterEntryResponse, len(roster.Entries)),
+	}
+	for i, entry := range roster.Entries {
+		response.Entries[i] = RosterEntryResponse{
+			UserID:    entry.UserID,
+			Role:      string(members[i].Role),
+			Dashboard: dashboardToResponse(entry.Dashboard),
+		}
+	}
+
+	writeData(w, http.StatusOK, response)
+}
@@ -0,0 +1,271 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// OrganizationTestResponse represents an organization in test responses.
+type OrganizationTestResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	CreatedBy string `json:"createdBy"`
+}
+
+// OrganizationMemberTestResponse represents a membership in test responses.
+type OrganizationMemberTestResponse struct {
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+}
+
+// OrganizationInvitationTestResponse represents an invitation in test responses.
+type OrganizationInvitationTestResponse struct {
+	ID     string `json:"id"`
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+	Status string `json:"status"`
+}
+
+// catalogTestEntry represents the fields shared by lift, program and lookup test responses.
+type catalogTestEntry struct {
+	ID             string  `json:"id"`
+	OrganizationID *string `json:"organizationId"`
+}
+
+func TestOrganizationHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	owner, coach, athlete, stranger := "org-owner", "org-coach", "org-athlete", "org-stranger"
+	for _, userID := range []string{owner, coach, athlete, stranger} {
+		createLSTestUser(t, ts, userID)
+	}
+	cycleID := createLSTestCycle(t, ts, "Organization Cycle")
+	globalProgramID := createLSTestProgram(t, ts, "Organization Global Program", "org-global-program", cycleID)
+
+	var org OrganizationTestResponse
+
+	t.Run("owner creates an organization and builds the roster", func(t *testing.T) {
+		coachingRequest(t, http.MethodPost, ts.URL("/organizations"), map[string]interface{}{
+			"name": "Iron Gym",
+		}, owner, http.StatusCreated, &org)
+		if org.Slug != "iron-gym" || org.CreatedBy != owner {
+			t.Fatalf("Unexpected organization: %+v", org)
+		}
+
+		var coachInv, athleteInv OrganizationInvitationTestResponse
+		coachingRequest(t, http.MethodPost, ts.URL("/organizations/"+org.ID+"/invitations"), map[string]interface{}{
+			"userId": coach, "role": "COACH",
+		}, owner, http.StatusCreated, &coachInv)
+		coachingRequest(t, http.MethodPost, ts.URL("/organizations/"+org.ID+"/invitations"), map[string]interface{}{
+			"userId": athlete,
+		}, owner, http.StatusCreated, &athleteInv)
+		if athleteInv.Status != "PENDING" || athleteInv.Role != "ATHLETE" {
+			t.Fatalf("Unexpected invitation: %+v", athleteInv)
+		}
+		coachingRequest(t, http.MethodPost, ts.URL("/organizations/"+org.ID+"/invitations"), map[string]interface{}{
+			"userId": athlete,
+		}, owner, http.StatusConflict, nil)
+
+		// Invitees are not members until they accept
+		var invitations []OrganizationInvitationTestResponse
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+athlete+"/organization-invitations"), nil, athlete, http.StatusOK, &invitations)
+		if len(invitations) != 1 || invitations[0].ID != athleteInv.ID {
+			t.Errorf("Expected the athlete's invitation, got %+v", invitations)
+		}
+		coachingRequest(t, http.MethodGet, ts.URL("/organizations/"+org.ID+"/members"), nil, athlete, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodPost, ts.URL("/organization-invitations/"+athleteInv.ID+"/accept"), nil, coach, http.StatusForbidden, nil)
+
+		coachingRequest(t, http.MethodPost, ts.URL("/organization-invitations/"+coachInv.ID+"/accept"), nil, coach, http.StatusOK, nil)
+		var member OrganizationMemberTestResponse
+		coachingRequest(t, http.MethodPost, ts.URL("/organization-invitations/"+athleteInv.ID+"/accept"), nil, athlete, http.StatusOK, &member)
+		if member.UserID != athlete || member.Role != "ATHLETE" {
+			t.Errorf("Unexpected membership: %+v", member)
+		}
+		coachingRequest(t, http.MethodPost, ts.URL("/organization-invitations/"+athleteInv.ID+"/accept"), nil, athlete, http.StatusConflict, nil)
+		coachingRequest(t, http.MethodPost, ts.URL("/organizations/"+org.ID+"/invitations"), map[string]interface{}{
+			"userId": stranger,
+		}, coach, http.StatusForbidden, nil)
+
+		var members []OrganizationMemberTestResponse
+		coachingRequest(t, http.MethodGet, ts.URL("/organizations/"+org.ID+"/members"), nil, athlete, http.StatusOK, &members)
+		if len(members) != 3 || members[0].Role != "OWNER" || members[2].UserID != athlete || members[2].Role != "ATHLETE" {
+			t.Errorf("Unexpected roster: %+v", members)
+		}
+		coachingRequest(t, http.MethodGet, ts.URL("/organizations/"+org.ID+"/members"), nil, stranger, http.StatusForbidden, nil)
+
+		var orgs []OrganizationTestResponse
+		coachingRequest(t, http.MethodGet, ts.URL("/organizations"), nil, athlete, http.StatusOK, &orgs)
+		if len(orgs) != 1 || orgs[0].ID != org.ID {
+			t.Errorf("Expected the athlete's organization, got %+v", orgs)
+		}
+		coachingRequest(t, http.MethodGet, ts.URL("/organizations"), nil, stranger, http.StatusOK, &orgs)
+		if len(orgs) != 0 {
+			t.Errorf("Expected no organizations for a non-member, got %+v", orgs)
+		}
+	})
+
+	var orgLift, orgProgram catalogTestEntry
+
+	t.Run("coaches manage the organization catalog", func(t *testing.T) {
+		coachingRequest(t, http.MethodPost, ts.URL("/lifts"), map[string]interface{}{
+			"name": "Gym Safety Bar Squat", "slug": "org-ssb-squat", "organizationId": org.ID,
+		}, coach, http.StatusCreated, &orgLift)
+		if orgLift.OrganizationID == nil || *orgLift.OrganizationID != org.ID {
+			t.Fatalf("Expected the lift to belong to the organization, got %+v", orgLift)
+		}
+		coachingRequest(t, http.MethodPost, ts.URL("/lifts"), map[string]interface{}{
+			"name": "Athlete Lift", "slug": "org-athlete-lift", "organizationId": org.ID,
+		}, athlete, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodPost, ts.URL("/lifts"), map[string]interface{}{
+			"name": "Global Lift", "slug": "org-global-lift",
+		}, coach, http.StatusForbidden, nil)
+
+		var weekly catalogTestEntry
+		coachingRequest(t, http.MethodPost, ts.URL("/weekly-lookups"), map[string]interface{}{
+			"name":           "Gym Waves",
+			"entries":        []map[string]interface{}{{"weekNumber": 1, "percentages": []float64{70, 80, 90}, "reps": []int{5, 3, 1}}},
+			"organizationId": org.ID,
+		}, coach, http.StatusCreated, &weekly)
+
+		coachingRequest(t, http.MethodPost, ts.URL("/programs"), map[string]interface{}{
+			"name": "Gym Program", "slug": "org-gym-program", "cycleId": cycleID,
+			"weeklyLookupId": weekly.ID, "organizationId": org.ID,
+		}, owner, http.StatusCreated, &orgProgram)
+
+		// Global programs cannot reference an organization's private lookups
+		resp, err := webhookRequest(http.MethodPost, ts.URL("/programs"), map[string]interface{}{
+			"name": "Leaky Program", "slug": "org-leaky-program", "cycleId": cycleID,
+			"weeklyLookupId": weekly.ID,
+		}, testutil.TestAdminID, true)
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status %d for a global program using an organization lookup, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+
+	var strangerInv OrganizationInvitationTestResponse
+
+	t.Run("organization entries are visible only to members", func(t *testing.T) {
+		// A pending invitation grants no access
+		coachingRequest(t, http.MethodPost, ts.URL("/organizations/"+org.ID+"/invitations"), map[string]interface{}{
+			"userId": stranger,
+		}, owner, http.StatusCreated, &strangerInv)
+
+		coachingRequest(t, http.MethodGet, ts.URL("/lifts/"+orgLift.ID), nil, athlete, http.StatusOK, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/lifts/"+orgLift.ID), nil, stranger, http.StatusNotFound, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/lifts/by-slug/org-ssb-squat"), nil, stranger, http.StatusNotFound, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/programs/"+orgProgram.ID), nil, stranger, http.StatusNotFound, nil)
+		coachingRequest(t, http.MethodPut, ts.URL("/lifts/"+orgLift.ID), map[string]interface{}{"name": "Renamed"}, stranger, http.StatusNotFound, nil)
+
+		var lifts []catalogTestEntry
+		coachingRequest(t, http.MethodGet, ts.URL("/lifts?limit=100"), nil, stranger, http.StatusOK, &lifts)
+		for _, l := range lifts {
+			if l.ID == orgLift.ID {
+				t.Errorf("Expected the organization lift to be hidden from non-members")
+			}
+		}
+
+		var programs []catalogTestEntry
+		coachingRequest(t, http.MethodGet, ts.URL("/programs?limit=100"), nil, athlete, http.StatusOK, &programs)
+		var sawGlobal, sawOrg bool
+		for _, p := range programs {
+			sawGlobal = sawGlobal || p.ID == globalProgramID
+			sawOrg = sawOrg || p.ID == orgProgram.ID
+		}
+		if !sawGlobal || !sawOrg {
+			t.Errorf("Expected members to see global and organization programs, got %+v", programs)
+		}
+
+		coachingRequest(t, http.MethodPost, ts.URL("/users/"+stranger+"/program"), map[string]interface{}{
+			"programId": orgProgram.ID,
+		}, stranger, http.StatusBadRequest, nil)
+	})
+
+	t.Run("owners and coaches view the roster dashboard", func(t *testing.T) {
+		enrollLSTestUser(t, ts, athlete, globalProgramID)
+
+		var dash struct {
+			Summary struct {
+				Users    int `json:"users"`
+				Enrolled int `json:"enrolled"`
+			} `json:"summary"`
+			Entries []struct {
+				UserID string `json:"userId"`
+				Role   string `json:"role"`
+			} `json:"entries"`
+		}
+		coachingRequest(t, http.MethodGet, ts.URL("/organizations/"+org.ID+"/dashboard"), nil, coach, http.StatusOK, &dash)
+		if dash.Summary.Users != 3 || dash.Summary.Enrolled != 1 {
+			t.Errorf("Unexpected roster summary: %+v", dash.Summary)
+		}
+
+		coachingRequest(t, http.MethodGet, ts.URL("/organizations/"+org.ID+"/dashboard?role=ATHLETE"), nil, owner, http.StatusOK, &dash)
+		if len(dash.Entries) != 1 || dash.Entries[0].UserID != athlete || dash.Entries[0].Role != "ATHLETE" {
+			t.Errorf("Expected only the athlete's dashboard, got %+v", dash.Entries)
+		}
+
+		coachingRequest(t, http.MethodGet, ts.URL("/organizations/"+org.ID+"/dashboard"), nil, athlete, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/organizations/"+org.ID+"/dashboard"), nil, stranger, http.StatusForbidden, nil)
+	})
+
+	t.Run("invitees join once they accept", func(t *testing.T) {
+		var invitations []OrganizationInvitationTestResponse
+		coachingRequest(t, http.MethodGet, ts.URL("/organizations/"+org.ID+"/invitations"), nil, owner, http.StatusOK, &invitations)
+		if len(invitations) != 1 || invitations[0].UserID != stranger {
+			t.Errorf("Expected the stranger's pending invitation, got %+v", invitations)
+		}
+		coachingRequest(t, http.MethodGet, ts.URL("/organizations/"+org.ID+"/invitations"), nil, athlete, http.StatusForbidden, nil)
+
+		coachingRequest(t, http.MethodPost, ts.URL("/organization-invitations/"+strangerInv.ID+"/accept"), nil, stranger, http.StatusOK, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/lifts/"+orgLift.ID), nil, stranger, http.StatusOK, nil)
+		coachingRequest(t, http.MethodDelete, ts.URL("/organizations/"+org.ID+"/members/"+stranger), nil, stranger, http.StatusNoContent, nil)
+	})
+
+	t.Run("membership changes", func(t *testing.T) {
+		coachingRequest(t, http.MethodPut, ts.URL("/organizations/"+org.ID+"/members/"+owner), map[string]interface{}{
+			"role": "COACH",
+		}, owner, http.StatusConflict, nil)
+		coachingRequest(t, http.MethodDelete, ts.URL("/organizations/"+org.ID+"/members/"+owner), nil, owner, http.StatusConflict, nil)
+
+		// Demoted coaches lose catalog access
+		coachingRequest(t, http.MethodPut, ts.URL("/organizations/"+org.ID+"/members/"+coach), map[string]interface{}{
+			"role": "ATHLETE",
+		}, owner, http.StatusOK, nil)
+		coachingRequest(t, http.MethodDelete, ts.URL("/lifts/"+orgLift.ID), nil, coach, http.StatusForbidden, nil)
+
+		// Members can leave, after which the catalog is hidden from them
+		coachingRequest(t, http.MethodDelete, ts.URL("/organizations/"+org.ID+"/members/"+coach), nil, coach, http.StatusNoContent, nil)
+		coachingRequest(t, http.MethodGet, ts.URL("/lifts/"+orgLift.ID), nil, coach, http.StatusNotFound, nil)
+
+		// Owners can withdraw a pending invitation; invitees can decline one
+		var inv OrganizationInvitationTestResponse
+		coachingRequest(t, http.MethodPost, ts.URL("/organizations/"+org.ID+"/invitations"), map[string]interface{}{
+			"userId": coach,
+		}, owner, http.StatusCreated, &inv)
+		coachingRequest(t, http.MethodDelete, ts.URL("/organization-invitations/"+inv.ID), nil, athlete, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodDelete, ts.URL("/organization-invitations/"+inv.ID), nil, owner, http.StatusNoContent, nil)
+		coachingRequest(t, http.MethodPost, ts.URL("/organizations/"+org.ID+"/invitations"), map[string]interface{}{
+			"userId": coach,
+		}, owner, http.StatusCreated, &inv)
+		coachingRequest(t, http.MethodPost, ts.URL("/organization-invitations/"+inv.ID+"/decline"), nil, coach, http.StatusOK, &inv)
+		if inv.Status != "DECLINED" {
+			t.Errorf("Expected a declined invitation, got %+v", inv)
+		}
+		coachingRequest(t, http.MethodGet, ts.URL("/organizations/"+org.ID+"/members"), nil, coach, http.StatusForbidden, nil)
+	})
+
+	t.Run("organizations owning catalog entries cannot be deleted", func(t *testing.T) {
+		coachingRequest(t, http.MethodDelete, ts.URL("/organizations/"+org.ID), nil, athlete, http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodDelete, ts.URL("/organizations/"+org.ID), nil, owner, http.StatusConflict, nil)
+	})
+}
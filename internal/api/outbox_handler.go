@@ -0,0 +1,218 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/audit"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/outbox"
+)
+
+// OutboxHandler handles HTTP requests for inspecting and replaying stored state events.
+type OutboxHandler struct {
+	outboxService *outbox.Service
+	auditService  *audit.Service
+}
+
+// NewOutboxHandler creates a new OutboxHandler. Replays are recorded with auditService.
+func NewOutboxHandler(outboxService *outbox.Service, auditService *audit.Service) *OutboxHandler {
+	return &OutboxHandler{
+		outboxService: outboxService,
+		auditService:  auditService,
+	}
+}
+
+// StoredEventResponse represents the API response format for a stored state event.
+type StoredEventResponse struct {
+	ID         string                 `json:"id"`
+	Seq        int64                  `json:"seq"`
+	Type       string                 `json:"type"`
+	UserID     string                 `json:"userId"`
+	ProgramID  *string                `json:"programId"`
+	Payload    map[string]interface{} `json:"payload"`
+	OccurredAt time.Time              `json:"occurredAt"`
+	CreatedAt  time.Time              `json:"createdAt"`
+	// Deliveries is only included on single-event responses.
+	Deliveries []EventDeliveryResponse `json:"deliveries,omitempty"`
+}
+
+// EventDeliveryResponse represents an event's delivery status for one handler.
+type EventDeliveryResponse struct {
+	Handler  string  `json:"handler"`
+	Status   string  `json:"status"`
+	Attempts int     `json:"attempts"`
+	Error    *string `json:"error"`
+}
+
+// EventReplayResponse represents the outcome of replaying an event to one handler.
+type EventReplayResponse struct {
+	Handler   string  `json:"handler"`
+	Succeeded bool    `json:"succeeded"`
+	Error     *string `json:"error"`
+}
+
+// EventHandlerStateResponse represents an event handler's progress through the outbox.
+type EventHandlerStateResponse struct {
+	Name string `json:"name"`
+	// EventTypes is null when the handler receives every event type.
+	EventTypes   []string   `json:"eventTypes"`
+	LastSeq      int64      `json:"lastSeq"`
+	Lag          int64      `json:"lag"`
+	Attempts     int        `json:"attempts"`
+	LastError    *string    `json:"lastError"`
+	FailedEvents int64      `json:"failedEvents"`
+	UpdatedAt    *time.Time `json:"updatedAt"`
+}
+
+func storedEventToResponse(evt *outbox.Event) StoredEventResponse {
+	var programID *string
+	if evt.ProgramID != "" {
+		programID = &evt.ProgramID
+	}
+	return StoredEventResponse{
+		ID:         evt.ID,
+		Seq:        evt.Seq,
+		Type:       string(evt.Type),
+		UserID:     evt.UserID,
+		ProgramID:  programID,
+		Payload:    evt.Payload,
+		OccurredAt: evt.OccurredAt,
+		CreatedAt:  evt.CreatedAt,
+	}
+}
+
+// List handles GET /events
+// Returns stored events, newest first, optionally filtered by type and userId.
+func (h *OutboxHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pg := ParsePagination(query)
+	filter := outbox.EventFilter{
+		EventType: ParseFilterString(query, "type"),
+		UserID:    ParseFilterString(query, "userId"),
+	}
+
+	events, total, err := h.outboxService.ListEvents(r.Context(), filter, int64(pg.Limit), int64(pg.Offset))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	data := make([]StoredEventResponse, len(events))
+	for i := range events {
+		data[i] = storedEventToResponse(&events[i])
+	}
+
+	writePaginatedData(w, http.StatusOK, data, total, pg.Limit, pg.Offset)
+}
+
+// Get handles GET /events/{id}
+// Returns a stored event with its delivery status for each handler that receives it.
+func (h *OutboxHandler) Get(w http.ResponseWriter, r *http.Request) {
+	detail, err := h.outboxService.GetEvent(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	resp := storedEventToResponse(&detail.Event)
+	resp.Deliveries = make([]EventDeliveryResponse, len(detail.Deliveries))
+	for i, d := range detail.Deliveries {
+		resp.Deliveries[i] = EventDeliveryResponse{
+			Handler:  d.Handler,
+			Status:   string(d.Status),
+			Attempts: d.Attempts,
+			Error:    d.Error,
+		}
+	}
+
+	writeData(w, http.StatusOK, resp)
+}
+
+// Replay handles POST /events/{id}/replay
+// Delivers a stored event again to the handlers named by repeated handler query
+// parameters, or to every handler that receives it when none are named.
+func (h *OutboxHandler) Replay(w http.ResponseWriter, r *http.Request) {
+	eventID := r.PathValue("id")
+	handlers, err := h.outboxService.ReplayTargets(r.Context(), eventID, r.URL.Query()["handler"])
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	// Handlers write outside any transaction of ours and deliveries cannot be taken back,
+	// so the replay is recorded before the event is delivered
+	after, err := json.Marshal(map[string][]string{"handlers": handlers})
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to encode replay", err))
+		return
+	}
+	if _, err := h.auditService.Record(r.Context(), audit.Entry{
+		ActorID:    middleware.GetUserID(r),
+		Action:     audit.ActionCreate,
+		EntityType: audit.EntityEventReplay,
+		EntityID:   eventID,
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		After:      after,
+	}); err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to record audit entry", err))
+		return
+	}
+
+	results, err := h.outboxService.Replay(r.Context(), eventID, handlers)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	data := make([]EventReplayResponse, len(results))
+	for i, res := range results {
+		data[i] = EventReplayResponse{
+			Handler:   res.Handler,
+			Succeeded: res.Error == nil,
+			Error:     res.Error,
+		}
+	}
+
+	writeData(w, http.StatusOK, data)
+}
+
+// ListHandlers handles GET /event-handlers
+// Returns each event handler's checkpoint and how far it is behind the newest event.
+func (h *OutboxHandler) ListHandlers(w http.ResponseWriter, r *http.Request) {
+	states, err := h.outboxService.ListHandlers(r.Context())
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	data := make([]EventHandlerStateResponse, len(states))
+	for i, st := range states {
+		var eventTypes []string
+		if st.EventTypes != nil {
+			eventTypes = make([]string, len(st.EventTypes))
+			for j, et := range st.EventTypes {
+				eventTypes[j] = string(et)
+			}
+		}
+		var updatedAt *time.Time
+		if !st.Checkpoint.UpdatedAt.IsZero() {
+			updatedAt = &st.Checkpoint.UpdatedAt
+		}
+		data[i] = EventHandlerStateResponse{
+			Name:         st.Name,
+			EventTypes:   eventTypes,
+			LastSeq:      st.Checkpoint.LastSeq,
+			Lag:          st.Lag,
+			Attempts:     st.Checkpoint.Attempts,
+			LastError:    st.Checkpoint.LastError,
+			FailedEvents: st.FailedEvents,
+			UpdatedAt:    updatedAt,
+		}
+	}
+
+	writeData(w, http.StatusOK, data)
+}
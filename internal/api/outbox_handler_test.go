@@ -0,0 +1,196 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+	"github.com/waynenilsen/power-pro-v3/internal/webhook"
+)
+
+// StoredEventTestResponse represents a stored event in test responses.
+type StoredEventTestResponse struct {
+	ID         string                   `json:"id"`
+	Seq        int64                    `json:"seq"`
+	Type       string                   `json:"type"`
+	UserID     string                   `json:"userId"`
+	ProgramID  *string                  `json:"programId"`
+	Payload    map[string]interface{}   `json:"payload"`
+	Deliveries []EventDeliveryTestEntry `json:"deliveries"`
+}
+
+// EventDeliveryTestEntry represents an event's delivery status for one handler in test responses.
+type EventDeliveryTestEntry struct {
+	Handler string `json:"handler"`
+	Status  string `json:"status"`
+}
+
+// EventHandlerTestResponse represents an event handler's progress in test responses.
+type EventHandlerTestResponse struct {
+	Name         string `json:"name"`
+	LastSeq      int64  `json:"lastSeq"`
+	Lag          int64  `json:"lag"`
+	FailedEvents int64  `json:"failedEvents"`
+}
+
+func getTestEvent(t *testing.T, ts *testutil.TestServer, eventID string) StoredEventTestResponse {
+	t.Helper()
+	resp, err := webhookRequest(http.MethodGet, ts.URL("/events/"+eventID), nil, testutil.TestAdminID, true)
+	if err != nil {
+		t.Fatalf("Failed to get event: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	var envelope struct {
+		Data StoredEventTestResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&envelope)
+	return envelope.Data
+}
+
+func TestOutboxHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	receiver := &webhookReceiver{}
+	ts.HandleHost("hooks.test", receiver)
+
+	userID := "outbox-user"
+	createLSTestUser(t, ts, userID)
+	liftID := createLSTestLift(t, ts, "Squat", "squat-outbox")
+	cycleID := createLSTestCycle(t, ts, "Outbox Cycle")
+	programID := createLSTestProgram(t, ts, "Outbox Program", "outbox-program", cycleID)
+	enrollLSTestUser(t, ts, userID, programID)
+
+	createTestWebhook(t, ts.URL("/users/"+userID+"/webhooks"), userID, false, map[string]interface{}{
+		"url":        "http://hooks.test/outbox",
+		"eventTypes": []string{"SET_LOGGED"},
+	})
+
+	sessionID := startLSWorkoutSession(t, ts, userID)
+	logLSTestSets(t, ts, sessionID, userID, liftID, 5)
+
+	listEvents := func(t *testing.T, query string) ([]StoredEventTestResponse, *PaginationMeta) {
+		t.Helper()
+		resp, err := webhookRequest(http.MethodGet, ts.URL("/events?"+query), nil, testutil.TestAdminID, true)
+		if err != nil {
+			t.Fatalf("Failed to list events: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+		var envelope struct {
+			Data []StoredEventTestResponse `json:"data"`
+			Meta *PaginationMeta           `json:"meta"`
+		}
+		json.NewDecoder(resp.Body).Decode(&envelope)
+		return envelope.Data, envelope.Meta
+	}
+
+	var setEvent StoredEventTestResponse
+	t.Run("lists events newest first", func(t *testing.T) {
+		events, meta := listEvents(t, "userId="+userID)
+		n := len(events)
+		if n < 3 || events[n-1].Type != "ENROLLED" || events[n-2].Type != "WORKOUT_STARTED" {
+			t.Fatalf("Expected ENROLLED then WORKOUT_STARTED first, got %+v", events)
+		}
+		if meta == nil || meta.Total != int64(n) {
+			t.Errorf("Expected total %d, got %+v", n, meta)
+		}
+		for i := 1; i < n; i++ {
+			if events[i].Seq >= events[i-1].Seq {
+				t.Errorf("Expected events newest first, got seq %d after %d", events[i].Seq, events[i-1].Seq)
+			}
+		}
+
+		setEvents, _ := listEvents(t, "type=SET_LOGGED&userId="+userID)
+		if len(setEvents) != 1 {
+			t.Fatalf("Expected 1 SET_LOGGED event, got %d", len(setEvents))
+		}
+		setEvent = setEvents[0]
+		if setEvent.ProgramID == nil || *setEvent.ProgramID != programID || setEvent.Payload["sessionId"] != sessionID {
+			t.Errorf("Unexpected event: %+v", setEvent)
+		}
+	})
+
+	t.Run("admin only", func(t *testing.T) {
+		for _, path := range []string{"/events", "/event-handlers"} {
+			resp, _ := webhookRequest(http.MethodGet, ts.URL(path), nil, userID, false)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusForbidden {
+				t.Errorf("GET %s: expected status 403, got %d", path, resp.StatusCode)
+			}
+		}
+	})
+
+	t.Run("dispatches stored events to webhooks", func(t *testing.T) {
+		requests, _ := receiver.waitFor(t, 1)
+		if requests[0].Header.Get(webhook.HeaderEventID) != setEvent.ID {
+			t.Errorf("Expected webhook event ID %s, got %s", setEvent.ID, requests[0].Header.Get(webhook.HeaderEventID))
+		}
+
+		// The checkpoint is saved just after the handler returns
+		deadline := time.Now().Add(5 * time.Second)
+		evt := getTestEvent(t, ts, setEvent.ID)
+		for len(evt.Deliveries) == 1 && evt.Deliveries[0].Status == "PENDING" && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+			evt = getTestEvent(t, ts, setEvent.ID)
+		}
+		if len(evt.Deliveries) != 1 || evt.Deliveries[0].Handler != "webhooks" || evt.Deliveries[0].Status != "DELIVERED" {
+			t.Errorf("Unexpected deliveries: %+v", evt.Deliveries)
+		}
+
+		resp, _ := webhookRequest(http.MethodGet, ts.URL("/event-handlers"), nil, testutil.TestAdminID, true)
+		defer resp.Body.Close()
+		var envelope struct {
+			Data []EventHandlerTestResponse `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&envelope)
+		if len(envelope.Data) != 1 || envelope.Data[0].Name != "webhooks" || envelope.Data[0].Lag != 0 || envelope.Data[0].FailedEvents != 0 {
+			t.Errorf("Unexpected handlers: %+v", envelope.Data)
+		}
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		resp, _ := webhookRequest(http.MethodPost, ts.URL("/events/"+setEvent.ID+"/replay?handler=webhooks"), nil, testutil.TestAdminID, true)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+		var envelope struct {
+			Data []struct {
+				Handler   string `json:"handler"`
+				Succeeded bool   `json:"succeeded"`
+			} `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&envelope)
+		if len(envelope.Data) != 1 || envelope.Data[0].Handler != "webhooks" || !envelope.Data[0].Succeeded {
+			t.Errorf("Unexpected replay results: %+v", envelope.Data)
+		}
+
+		requests, _ := receiver.waitFor(t, 2)
+		if requests[1].Header.Get(webhook.HeaderEventID) != setEvent.ID {
+			t.Errorf("Expected the replay to keep the event ID, got %s", requests[1].Header.Get(webhook.HeaderEventID))
+		}
+
+		resp, _ = webhookRequest(http.MethodPost, ts.URL("/events/"+setEvent.ID+"/replay?handler=missing"), nil, testutil.TestAdminID, true)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for an unknown handler, got %d", resp.StatusCode)
+		}
+
+		resp, _ = webhookRequest(http.MethodPost, ts.URL("/events/missing/replay"), nil, testutil.TestAdminID, true)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status 404 for an unknown event, got %d", resp.StatusCode)
+		}
+	})
+}
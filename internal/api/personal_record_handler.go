@@ -0,0 +1,108 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/personalrecord"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/service"
+)
+
+// PersonalRecordHandler handles HTTP requests for personal record operations.
+type PersonalRecordHandler struct {
+	personalRecordService *service.PersonalRecordService
+}
+
+// NewPersonalRecordHandler creates a new PersonalRecordHandler.
+func NewPersonalRecordHandler(personalRecordService *service.PersonalRecordService) *PersonalRecordHandler {
+	return &PersonalRecordHandler{
+		personalRecordService: personalRecordService,
+	}
+}
+
+// PersonalRecordResponse represents the API response format for a personal record.
+type PersonalRecordResponse struct {
+	ID            string    `json:"id"`
+	LiftID        *string   `json:"liftId"`
+	LiftName      *string   `json:"liftName,omitempty"`
+	LiftSlug      *string   `json:"liftSlug,omitempty"`
+	RecordType    string    `json:"recordType"`
+	RepCount      *int      `json:"repCount"`
+	Value         float64   `json:"value"`
+	Weight        *float64  `json:"weight"`
+	Reps          *int      `json:"reps"`
+	PreviousValue *float64  `json:"previousValue"`
+	LoggedSetID   *string   `json:"loggedSetId"`
+	SessionID     *string   `json:"sessionId"`
+	AchievedAt    time.Time `json:"achievedAt"`
+}
+
+func personalRecordToResponse(r *service.PersonalRecord) PersonalRecordResponse {
+	return PersonalRecordResponse{
+		ID:            r.ID,
+		LiftID:        r.LiftID,
+		RecordType:    string(r.RecordType),
+		RepCount:      r.RepCount,
+		Value:         r.Value,
+		Weight:        r.Weight,
+		Reps:          r.Reps,
+		PreviousValue: r.PreviousValue,
+		LoggedSetID:   r.LoggedSetID,
+		SessionID:     r.SessionID,
+		AchievedAt:    r.AchievedAt,
+	}
+}
+
+// List handles GET /users/{userId}/records
+// Returns the user's current best record for each lift, record type and rep count.
+// Supports filtering by liftId and type.
+func (h *PersonalRecordHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if userID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing user ID"))
+		return
+	}
+
+	// Authorization check: the user, their coach or an admin can view records
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
+		writeDomainError(w, apperrors.NewForbidden("you can only access your own personal records"))
+		return
+	}
+
+	query := r.URL.Query()
+	filterLiftID := ParseFilterString(query, "liftId")
+	filterType, err := ParseFilterEnum(query, "type", []string{
+		string(personalrecord.TypeRepMax),
+		string(personalrecord.TypeE1RM),
+		string(personalrecord.TypeSessionVolume),
+		string(personalrecord.TypeCompetitionTotal),
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	records, err := h.personalRecordService.ListCurrentRecords(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to list personal records", err))
+		return
+	}
+
+	data := make([]PersonalRecordResponse, 0, len(records))
+	for _, rec := range records {
+		if filterLiftID != nil && (rec.LiftID == nil || *rec.LiftID != *filterLiftID) {
+			continue
+		}
+		if filterType != nil && string(rec.RecordType) != *filterType {
+			continue
+		}
+		resp := personalRecordToResponse(&rec.PersonalRecord)
+		resp.LiftName = rec.LiftName
+		resp.LiftSlug = rec.LiftSlug
+		data = append(data, resp)
+	}
+
+	writeData(w, http.StatusOK, data)
+}
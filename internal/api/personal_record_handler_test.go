@@ -0,0 +1,183 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/api"
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// PRLoggedSetListEnvelope wraps logged sets returned with personal record flags.
+type PRLoggedSetListEnvelope struct {
+	Data []api.LoggedSetResponse `json:"data"`
+}
+
+// PersonalRecordListEnvelope wraps a personal record list response.
+type PersonalRecordListEnvelope struct {
+	Data []api.PersonalRecordResponse `json:"data"`
+}
+
+// logPRTestSet logs a single set and returns the created set.
+func logPRTestSet(t *testing.T, ts *testutil.TestServer, userID, sessionID, liftID string, setNumber int, weight float64, reps int) api.LoggedSetResponse {
+	t.Helper()
+	body, _ := json.Marshal(map[string]interface{}{
+		"sets": []map[string]interface{}{{
+			"prescriptionId": "pr-test-prescription",
+			"liftId":         liftID,
+			"setNumber":      setNumber,
+			"weight":         weight,
+			"targetReps":     reps,
+			"repsPerformed":  reps,
+		}},
+	})
+	resp, err := authPostLoggedSets(ts.URL("/sessions/"+sessionID+"/sets"), string(body), userID)
+	if err != nil {
+		t.Fatalf("Failed to log set: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Expected status 201, got %d: %s", resp.StatusCode, respBody)
+	}
+	var envelope PRLoggedSetListEnvelope
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return envelope.Data[0]
+}
+
+func TestPersonalRecords(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID := "pr-test-user"
+	createLSTestUser(t, ts, userID)
+	liftID := createLSTestLift(t, ts, "PR Squat", "pr-squat-test")
+	cycleID := createLSTestCycle(t, ts, "PR Test Cycle")
+	programID := createLSTestProgram(t, ts, "PR Test Program", "pr-test-program", cycleID)
+	enrollLSTestUser(t, ts, userID, programID)
+
+	firstSession := startLSWorkoutSession(t, ts, userID)
+
+	t.Run("first set is flagged as a PR", func(t *testing.T) {
+		set := logPRTestSet(t, ts, userID, firstSession, liftID, 1, 300, 5)
+		if !set.IsPR {
+			t.Fatal("Expected first set to be a PR")
+		}
+		types := map[string]bool{}
+		for _, rec := range set.PersonalRecords {
+			types[rec.RecordType] = true
+		}
+		for _, expected := range []string{"REP_MAX", "E1RM", "SESSION_VOLUME"} {
+			if !types[expected] {
+				t.Errorf("Expected %s record, got %v", expected, set.PersonalRecords)
+			}
+		}
+	})
+
+	t.Run("lighter set is not a PR", func(t *testing.T) {
+		set := logPRTestSet(t, ts, userID, firstSession, liftID, 2, 275, 5)
+		if set.IsPR || len(set.PersonalRecords) != 0 {
+			t.Errorf("Expected no PR, got %v", set.PersonalRecords)
+		}
+	})
+
+	finishLSWorkoutSession(t, ts, firstSession, userID)
+	secondSession := startLSWorkoutSession(t, ts, userID)
+
+	t.Run("heavier set reports the beaten record", func(t *testing.T) {
+		set := logPRTestSet(t, ts, userID, secondSession, liftID, 1, 315, 5)
+		if !set.IsPR {
+			t.Fatal("Expected heavier set to be a PR")
+		}
+		var repMax *api.PersonalRecordResponse
+		for i, rec := range set.PersonalRecords {
+			if rec.RecordType == "REP_MAX" {
+				repMax = &set.PersonalRecords[i]
+			}
+		}
+		if repMax == nil {
+			t.Fatalf("Expected REP_MAX record, got %v", set.PersonalRecords)
+		}
+		if repMax.Value != 315 || repMax.PreviousValue == nil || *repMax.PreviousValue != 300 {
+			t.Errorf("Expected 5RM 315 beating 300, got %v (previous %v)", repMax.Value, repMax.PreviousValue)
+		}
+		if repMax.RepCount == nil || *repMax.RepCount != 5 {
+			t.Errorf("Expected rep count 5, got %v", repMax.RepCount)
+		}
+	})
+
+	t.Run("lists current records", func(t *testing.T) {
+		resp, err := authGetLoggedSets(ts.URL("/users/"+userID+"/records"), userID)
+		if err != nil {
+			t.Fatalf("Failed to list records: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+		var envelope PersonalRecordListEnvelope
+		json.NewDecoder(resp.Body).Decode(&envelope)
+
+		// One 5RM, one E1RM, one session volume (the first session's 2875)
+		if len(envelope.Data) != 3 {
+			t.Fatalf("Expected 3 current records, got %d", len(envelope.Data))
+		}
+		for _, rec := range envelope.Data {
+			if rec.LiftSlug == nil || *rec.LiftSlug != "pr-squat-test" {
+				t.Errorf("Expected lift slug pr-squat-test, got %v", rec.LiftSlug)
+			}
+			if rec.RecordType == "SESSION_VOLUME" && rec.Value != 2875 {
+				t.Errorf("Expected session volume 2875, got %v", rec.Value)
+			}
+		}
+	})
+
+	t.Run("filters records by type", func(t *testing.T) {
+		resp, err := authGetLoggedSets(ts.URL("/users/"+userID+"/records?type=e1rm&liftId="+liftID), userID)
+		if err != nil {
+			t.Fatalf("Failed to list records: %v", err)
+		}
+		defer resp.Body.Close()
+		var envelope PersonalRecordListEnvelope
+		json.NewDecoder(resp.Body).Decode(&envelope)
+		if len(envelope.Data) != 1 || envelope.Data[0].RecordType != "E1RM" {
+			t.Fatalf("Expected a single E1RM record, got %v", envelope.Data)
+		}
+		// 315 x 5 by Epley
+		if envelope.Data[0].Value != 367.5 {
+			t.Errorf("Expected E1RM 367.5, got %v", envelope.Data[0].Value)
+		}
+	})
+
+	t.Run("invalid type returns 400", func(t *testing.T) {
+		resp, _ := authGetLoggedSets(ts.URL("/users/"+userID+"/records?type=BEST"), userID)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("other users cannot view records", func(t *testing.T) {
+		resp, _ := authGetLoggedSets(ts.URL("/users/"+userID+"/records"), uuid.New().String())
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("admin can view any user's records", func(t *testing.T) {
+		resp, _ := adminGetLoggedSets(ts.URL("/users/" + userID + "/records"))
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"strings"
@@ -40,6 +41,15 @@ func NewPrescriptionHandler(
 	}
 }
 
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *PrescriptionHandler) WithTx(tx *sql.Tx) *PrescriptionHandler {
+	txHandler := *h
+	txHandler.repo = h.repo.WithTx(tx)
+	txHandler.liftRepo = h.liftRepo.WithTx(tx)
+	txHandler.liftMaxRepo = h.liftMaxRepo.WithTx(tx)
+	return &txHandler
+}
+
 // PrescriptionResponse represents the API response format for a prescription.
 type PrescriptionResponse struct {
 	ID           string          `json:"id"`
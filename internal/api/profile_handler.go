@@ -1,9 +1,11 @@
 package api
 
 import (
+	"database/sql"
 	"net/http"
 	"time"
 
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
 	"github.com/waynenilsen/power-pro-v3/internal/middleware"
 	"github.com/waynenilsen/power-pro-v3/internal/profile"
@@ -21,20 +23,33 @@ func NewProfileHandler(profileService *profile.Service) *ProfileHandler {
 	}
 }
 
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *ProfileHandler) WithTx(tx *sql.Tx) *ProfileHandler {
+	txHandler := *h
+	txHandler.profileService = h.profileService.WithTx(tx)
+	return &txHandler
+}
+
 // UpdateProfileRequest represents the request body for updating a profile.
 type UpdateProfileRequest struct {
-	Name       *string `json:"name,omitempty"`
-	WeightUnit *string `json:"weightUnit,omitempty"`
+	Name              *string `json:"name,omitempty"`
+	WeightUnit        *string `json:"weightUnit,omitempty"`
+	Sex               *string `json:"sex,omitempty"`
+	BirthDate         *string `json:"birthDate,omitempty"`
+	WeightClassTarget *string `json:"weightClassTarget,omitempty"`
 }
 
 // ProfileResponse represents the response for profile operations.
 type ProfileResponse struct {
-	ID         string    `json:"id"`
-	Email      string    `json:"email"`
-	Name       *string   `json:"name"`
-	WeightUnit string    `json:"weightUnit"`
-	CreatedAt  time.Time `json:"createdAt"`
-	UpdatedAt  time.Time `json:"updatedAt"`
+	ID                string    `json:"id"`
+	Email             string    `json:"email"`
+	Name              *string   `json:"name"`
+	WeightUnit        string    `json:"weightUnit"`
+	Sex               *string   `json:"sex"`
+	BirthDate         *string   `json:"birthDate"`
+	WeightClassTarget *string   `json:"weightClassTarget"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
 }
 
 // Get handles GET /users/{userId}/profile
@@ -45,10 +60,8 @@ func (h *ProfileHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Authorization check: only the user themselves or an admin can view profile
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if authUserID != userID && !isAdmin {
+	// Authorization check: the user, their coach or an admin can view profile
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
 		writeDomainError(w, apperrors.NewForbidden("you can only access your own profile"))
 		return
 	}
@@ -89,8 +102,11 @@ func (h *ProfileHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	// Convert to service request
 	serviceReq := profile.UpdateProfileRequest{
-		Name:       req.Name,
-		WeightUnit: req.WeightUnit,
+		Name:              req.Name,
+		WeightUnit:        req.WeightUnit,
+		Sex:               req.Sex,
+		BirthDate:         req.BirthDate,
+		WeightClassTarget: req.WeightClassTarget,
 	}
 
 	// Update profile via service
@@ -108,11 +124,14 @@ func (h *ProfileHandler) Update(w http.ResponseWriter, r *http.Request) {
 // buildProfileResponse builds the ProfileResponse from a profile.
 func (h *ProfileHandler) buildProfileResponse(p *profile.Profile) ProfileResponse {
 	return ProfileResponse{
-		ID:         p.ID,
-		Email:      p.Email,
-		Name:       p.Name,
-		WeightUnit: p.WeightUnit,
-		CreatedAt:  p.CreatedAt,
-		UpdatedAt:  p.UpdatedAt,
+		ID:                p.ID,
+		Email:             p.Email,
+		Name:              p.Name,
+		WeightUnit:        p.WeightUnit,
+		Sex:               p.Sex,
+		BirthDate:         p.BirthDate,
+		WeightClassTarget: p.WeightClassTarget,
+		CreatedAt:         p.CreatedAt,
+		UpdatedAt:         p.UpdatedAt,
 	}
 }
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"database/sql"
 	"net/http"
 	"strconv"
 	"strings"
@@ -9,23 +10,46 @@ import (
 	"github.com/google/uuid"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/program"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/organization"
 	"github.com/waynenilsen/power-pro-v3/internal/repository"
 )
 
 // ProgramHandler handles HTTP requests for program operations.
 type ProgramHandler struct {
-	repo      *repository.ProgramRepository
-	cycleRepo *repository.CycleRepository
+	repo             *repository.ProgramRepository
+	cycleRepo        *repository.CycleRepository
+	weeklyLookupRepo *repository.WeeklyLookupRepository
+	dailyLookupRepo  *repository.DailyLookupRepository
+	orgService       *organization.Service
 }
 
 // NewProgramHandler creates a new ProgramHandler.
-func NewProgramHandler(repo *repository.ProgramRepository, cycleRepo *repository.CycleRepository) *ProgramHandler {
+func NewProgramHandler(
+	repo *repository.ProgramRepository,
+	cycleRepo *repository.CycleRepository,
+	weeklyLookupRepo *repository.WeeklyLookupRepository,
+	dailyLookupRepo *repository.DailyLookupRepository,
+	orgService *organization.Service,
+) *ProgramHandler {
 	return &ProgramHandler{
-		repo:      repo,
-		cycleRepo: cycleRepo,
+		repo:             repo,
+		cycleRepo:        cycleRepo,
+		weeklyLookupRepo: weeklyLookupRepo,
+		dailyLookupRepo:  dailyLookupRepo,
+		orgService:       orgService,
 	}
 }
 
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *ProgramHandler) WithTx(tx *sql.Tx) *ProgramHandler {
+	txHandler := *h
+	txHandler.repo = h.repo.WithTx(tx)
+	txHandler.cycleRepo = h.cycleRepo.WithTx(tx)
+	txHandler.weeklyLookupRepo = h.weeklyLookupRepo.WithTx(tx)
+	txHandler.dailyLookupRepo = h.dailyLookupRepo.WithTx(tx)
+	return &txHandler
+}
+
 // ProgramResponse represents the API response format for a program (list view).
 type ProgramResponse struct {
 	ID              string    `json:"id"`
@@ -40,6 +64,7 @@ type ProgramResponse struct {
 	DaysPerWeek     int       `json:"daysPerWeek"`
 	Focus           string    `json:"focus"`
 	HasAmrap        bool      `json:"hasAmrap"`
+	OrganizationID  *string   `json:"organizationId"`
 	CreatedAt       time.Time `json:"createdAt"`
 	UpdatedAt       time.Time `json:"updatedAt"`
 }
@@ -88,11 +113,13 @@ type ProgramDetailResponse struct {
 	SampleWeek              []SampleWeekDayResponse  `json:"sampleWeek"`
 	LiftRequirements        []string                 `json:"liftRequirements"`
 	EstimatedSessionMinutes int                      `json:"estimatedSessionMinutes"`
+	OrganizationID          *string                  `json:"organizationId"`
 	CreatedAt               time.Time                `json:"createdAt"`
 	UpdatedAt               time.Time                `json:"updatedAt"`
 }
 
 // CreateProgramRequest represents the request body for creating a program.
+// A program with an organizationId is visible only to that organization's members.
 type CreateProgramRequest struct {
 	Name            string   `json:"name"`
 	Slug            string   `json:"slug"`
@@ -101,6 +128,7 @@ type CreateProgramRequest struct {
 	WeeklyLookupID  *string  `json:"weeklyLookupId,omitempty"`
 	DailyLookupID   *string  `json:"dailyLookupId,omitempty"`
 	DefaultRounding *float64 `json:"defaultRounding,omitempty"`
+	OrganizationID  *string  `json:"organizationId,omitempty"`
 }
 
 // UpdateProgramRequest represents the request body for updating a program.
@@ -128,6 +156,7 @@ func programToResponse(p *program.Program) ProgramResponse {
 		DaysPerWeek:     p.DaysPerWeek,
 		Focus:           p.Focus,
 		HasAmrap:        p.HasAmrap,
+		OrganizationID:  p.OrganizationID,
 		CreatedAt:       p.CreatedAt,
 		UpdatedAt:       p.UpdatedAt,
 	}
@@ -207,6 +236,7 @@ func programToDetailResponse(data ProgramDetailData) ProgramDetailResponse {
 		SampleWeek:              sampleWeek,
 		LiftRequirements:        liftRequirements,
 		EstimatedSessionMinutes: data.EstimatedSessionMinutes,
+		OrganizationID:          p.OrganizationID,
 		CreatedAt:               p.CreatedAt,
 		UpdatedAt:               p.UpdatedAt,
 	}
@@ -265,6 +295,7 @@ func (h *ProgramHandler) List(w http.ResponseWriter, r *http.Request) {
 		SortBy:    sortBy,
 		SortOrder: sortOrder,
 		Filters:   filters,
+		ViewerID:  catalogViewerID(r),
 	}
 
 	programs, total, err := h.repo.List(params)
@@ -356,8 +387,8 @@ func (h *ProgramHandler) Get(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewInternal("failed to get program", err))
 		return
 	}
-	if p == nil {
-		writeDomainError(w, apperrors.NewNotFound("program", id))
+	if err := h.checkVisible(r, p, id); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -448,6 +479,11 @@ func (h *ProgramHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := authorizeCatalogManage(r, h.orgService, req.OrganizationID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
 	// Check if cycle exists
 	cycle, err := h.cycleRepo.GetByID(req.CycleID)
 	if err != nil {
@@ -459,6 +495,12 @@ func (h *ProgramHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check lookups are available to the program's organization
+	if err := h.checkLookups(req.OrganizationID, req.WeeklyLookupID, req.DailyLookupID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
 	// Check slug uniqueness
 	slugExists, err := h.repo.SlugExists(req.Slug)
 	if err != nil {
@@ -493,6 +535,7 @@ func (h *ProgramHandler) Create(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewValidationMsg("validation failed"), details...)
 		return
 	}
+	newProgram.OrganizationID = req.OrganizationID
 
 	// Persist
 	if err := h.repo.Create(newProgram); err != nil {
@@ -517,8 +560,8 @@ func (h *ProgramHandler) Update(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewInternal("failed to get program", err))
 		return
 	}
-	if existing == nil {
-		writeDomainError(w, apperrors.NewNotFound("program", id))
+	if err := h.checkManage(r, existing, id); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -541,6 +584,19 @@ func (h *ProgramHandler) Update(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Check changed lookups are available to the program's organization
+	var weeklyLookupID, dailyLookupID *string
+	if req.WeeklyLookupID != nil {
+		weeklyLookupID = *req.WeeklyLookupID
+	}
+	if req.DailyLookupID != nil {
+		dailyLookupID = *req.DailyLookupID
+	}
+	if err := h.checkLookups(existing.OrganizationID, weeklyLookupID, dailyLookupID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
 	// Check slug uniqueness if changing
 	if req.Slug != nil && *req.Slug != existing.Slug {
 		slugExists, err := h.repo.SlugExistsExcluding(*req.Slug, id)
@@ -598,8 +654,8 @@ func (h *ProgramHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewInternal("failed to get program", err))
 		return
 	}
-	if existing == nil {
-		writeDomainError(w, apperrors.NewNotFound("program", id))
+	if err := h.checkManage(r, existing, id); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -622,3 +678,51 @@ func (h *ProgramHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// checkVisible returns a not found error when the program does not exist or belongs to
+// an organization the caller is not a member of.
+func (h *ProgramHandler) checkVisible(r *http.Request, p *program.Program, id string) error {
+	if p == nil {
+		return apperrors.NewNotFound("program", id)
+	}
+	visible, err := canViewCatalogEntry(r, h.orgService, p.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return apperrors.NewNotFound("program", id)
+	}
+	return nil
+}
+
+// checkManage checks the program is visible to the caller and that they may change it.
+func (h *ProgramHandler) checkManage(r *http.Request, p *program.Program, id string) error {
+	if err := h.checkVisible(r, p, id); err != nil {
+		return err
+	}
+	return authorizeCatalogManage(r, h.orgService, p.OrganizationID)
+}
+
+// checkLookups checks that the referenced lookups may be used by a program owned by
+// organizationID. Lookups that do not exist are left to the foreign key checks.
+func (h *ProgramHandler) checkLookups(organizationID, weeklyLookupID, dailyLookupID *string) error {
+	if weeklyLookupID != nil {
+		lookup, err := h.weeklyLookupRepo.GetByID(*weeklyLookupID)
+		if err != nil {
+			return apperrors.NewInternal("failed to verify weekly lookup", err)
+		}
+		if lookup != nil && !canReferenceCatalogEntry(organizationID, lookup.OrganizationID) {
+			return apperrors.NewValidation("weeklyLookupId", "weekly lookup not found")
+		}
+	}
+	if dailyLookupID != nil {
+		lookup, err := h.dailyLookupRepo.GetByID(*dailyLookupID)
+		if err != nil {
+			return apperrors.NewInternal("failed to verify daily lookup", err)
+		}
+		if lookup != nil && !canReferenceCatalogEntry(organizationID, lookup.OrganizationID) {
+			return apperrors.NewValidation("dailyLookupId", "daily lookup not found")
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"time"
@@ -33,6 +34,16 @@ func NewProgramProgressionHandler(
 	}
 }
 
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *ProgramProgressionHandler) WithTx(tx *sql.Tx) *ProgramProgressionHandler {
+	txHandler := *h
+	txHandler.ppRepo = h.ppRepo.WithTx(tx)
+	txHandler.programRepo = h.programRepo.WithTx(tx)
+	txHandler.progressionRepo = h.progressionRepo.WithTx(tx)
+	txHandler.liftRepo = h.liftRepo.WithTx(tx)
+	return &txHandler
+}
+
 // ProgramProgressionResponse represents the API response format for a program progression configuration.
 type ProgramProgressionResponse struct {
 	ID                string          `json:"id"`
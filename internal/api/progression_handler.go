@@ -1,6 +1,7 @@
 package api
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -23,6 +24,13 @@ func NewProgressionHandler(repo *repository.ProgressionRepository) *ProgressionH
 	return &ProgressionHandler{repo: repo}
 }
 
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *ProgressionHandler) WithTx(tx *sql.Tx) *ProgressionHandler {
+	txHandler := *h
+	txHandler.repo = h.repo.WithTx(tx)
+	return &txHandler
+}
+
 // ProgressionResponse represents the API response format for a progression.
 type ProgressionResponse struct {
 	ID         string          `json:"id"`
@@ -1,24 +1,36 @@
 package api
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/progression"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
-	"github.com/waynenilsen/power-pro-v3/internal/middleware"
 	"github.com/waynenilsen/power-pro-v3/internal/repository"
+	"github.com/waynenilsen/power-pro-v3/internal/service"
 )
 
 // ProgressionHistoryHandler handles HTTP requests for progression history queries.
 type ProgressionHistoryHandler struct {
-	repo *repository.ProgressionHistoryRepository
+	repo               *repository.ProgressionHistoryRepository
+	progressionService *service.ProgressionService
 }
 
 // NewProgressionHistoryHandler creates a new ProgressionHistoryHandler.
-func NewProgressionHistoryHandler(repo *repository.ProgressionHistoryRepository) *ProgressionHistoryHandler {
-	return &ProgressionHistoryHandler{repo: repo}
+func NewProgressionHistoryHandler(repo *repository.ProgressionHistoryRepository, progressionService *service.ProgressionService) *ProgressionHistoryHandler {
+	return &ProgressionHistoryHandler{repo: repo, progressionService: progressionService}
+}
+
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *ProgressionHistoryHandler) WithTx(tx *sql.Tx) *ProgressionHistoryHandler {
+	txHandler := *h
+	txHandler.repo = h.repo.WithTx(tx)
+	txHandler.progressionService = h.progressionService.WithTx(tx)
+	return &txHandler
 }
 
 // ProgressionHistoryResponse represents the API response format for a progression history entry.
@@ -35,6 +47,8 @@ type ProgressionHistoryResponse struct {
 	TriggerType     string          `json:"triggerType"`
 	TriggerContext  json.RawMessage `json:"triggerContext"`
 	AppliedAt       time.Time       `json:"appliedAt"`
+	RevertsLogID    *string         `json:"revertsLogId,omitempty"`
+	RevertedByLogID *string         `json:"revertedByLogId,omitempty"`
 }
 
 // List handles GET /users/{userId}/progression-history
@@ -46,10 +60,8 @@ func (h *ProgressionHistoryHandler) List(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Authorization check: user can only query their own history, admin can query any
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if !isAdmin && authUserID != userID {
+	// Authorization check: user can query their own history, coaches and admins can query others
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
 		writeDomainError(w, apperrors.NewForbidden("you do not have permission to access this resource"))
 		return
 	}
@@ -57,15 +69,15 @@ func (h *ProgressionHistoryHandler) List(w http.ResponseWriter, r *http.Request)
 	// Parse query parameters
 	query := r.URL.Query()
 
-	// Pagination (limit/offset)
-	pg := ParsePagination(query)
+	// Pagination (limit/offset or cursor) and sort (default: most recently applied first)
+	page, err := ParseCursorPage(query)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
 
 	// Build filter
-	filter := repository.ProgressionHistoryFilter{
-		UserID: userID,
-		Limit:  int64(pg.Limit),
-		Offset: int64(pg.Offset),
-	}
+	filter := repository.ProgressionHistoryFilter{UserID: userID}
 
 	// Filter by liftId
 	filter.LiftID = ParseFilterString(query, "liftId")
@@ -95,23 +107,36 @@ func (h *ProgressionHistoryHandler) List(w http.ResponseWriter, r *http.Request)
 	filter.TriggerType = triggerType
 
 	// Filter by startDate (ISO 8601)
-	startDate, err := ParseFilterDate(query, "startDate")
-	if err != nil {
+	if filter.From, err = ParseFilterDate(query, "startDate"); err != nil {
 		writeDomainError(w, err)
 		return
 	}
-	filter.StartDate = startDate
 
 	// Filter by endDate (ISO 8601, end of day for date-only format)
-	endDate, err := ParseFilterDateEndOfDay(query, "endDate")
-	if err != nil {
+	if filter.To, err = ParseFilterDateEndOfDay(query, "endDate"); err != nil {
 		writeDomainError(w, err)
 		return
 	}
-	filter.EndDate = endDate
+
+	// Count the first page only
+	var total *int64
+	if page.After == nil {
+		count, err := h.repo.Count(r.Context(), filter)
+		if err != nil {
+			writeDomainError(w, apperrors.NewInternal("failed to count progression history", err))
+			return
+		}
+		total = &count
+	}
 
 	// Fetch data
-	entries, total, err := h.repo.List(r.Context(), filter)
+	entries, next, err := h.repo.List(r.Context(), repository.ProgressionHistoryListParams{
+		Filter:    filter,
+		SortOrder: page.SortOrder,
+		After:     page.After,
+		Limit:     int64(page.Limit),
+		Offset:    int64(page.Offset),
+	})
 	if err != nil {
 		writeDomainError(w, apperrors.NewInternal("failed to fetch progression history", err))
 		return
@@ -133,9 +158,51 @@ func (h *ProgressionHistoryHandler) List(w http.ResponseWriter, r *http.Request)
 			TriggerType:     entry.TriggerType,
 			TriggerContext:  entry.TriggerContext,
 			AppliedAt:       entry.AppliedAt,
+			RevertsLogID:    entry.RevertsLogID,
+			RevertedByLogID: entry.RevertedByLogID,
 		}
 	}
 
 	// Use standard envelope with pagination metadata
-	writePaginatedData(w, http.StatusOK, data, total, pg.Limit, pg.Offset)
+	writeCursorPaginatedData(w, http.StatusOK, data, page, total, next)
+}
+
+// Revert handles POST /users/{userId}/progression-history/{logId}/revert
+func (h *ProgressionHistoryHandler) Revert(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if userID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing user ID"))
+		return
+	}
+	logID := r.PathValue("logId")
+	if logID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing progression log ID"))
+		return
+	}
+
+	// Authorization check: user can revert their own progressions, coaches and admins can revert others
+	if !canAccessUser(r, userID, coaching.PermissionEditMaxes) {
+		writeDomainError(w, apperrors.NewForbidden("you do not have permission to revert progressions for this user"))
+		return
+	}
+
+	result, err := h.progressionService.RevertProgression(r.Context(), userID, logID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrProgressionLogNotFound):
+			writeDomainError(w, apperrors.NewNotFound("progression log", logID))
+		case errors.Is(err, service.ErrCannotRevertRevert):
+			writeDomainError(w, apperrors.NewBadRequest(err.Error()))
+		case errors.Is(err, service.ErrProgressionAlreadyReverted),
+			errors.Is(err, service.ErrProgressionHasDependents),
+			errors.Is(err, service.ErrMaxChangedSinceProgression),
+			errors.Is(err, service.ErrNoCurrentMax):
+			writeDomainError(w, apperrors.NewConflict(err.Error()))
+		default:
+			writeDomainError(w, apperrors.NewInternal("failed to revert progression", err))
+		}
+		return
+	}
+
+	writeData(w, http.StatusOK, result)
 }
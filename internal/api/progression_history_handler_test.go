@@ -29,6 +29,8 @@ type ProgressionHistoryTestEntry struct {
 	TriggerType     string          `json:"triggerType"`
 	TriggerContext  json.RawMessage `json:"triggerContext"`
 	AppliedAt       time.Time       `json:"appliedAt"`
+	RevertsLogID    *string         `json:"revertsLogId"`
+	RevertedByLogID *string         `json:"revertedByLogId"`
 }
 
 // ProgressionHistoryTestMeta contains pagination metadata for the progression history response.
@@ -642,6 +644,9 @@ func TestProgressionHistoryDataInsertAndQuery(t *testing.T) {
 		{"invalid triggerType", "triggerType=INVALID", false},
 		{"invalid startDate", "startDate=notadate", false},
 		{"invalid endDate", "endDate=notadate", false},
+		{"ascending", "sortOrder=asc", true},
+		{"invalid sortOrder", "sortOrder=delta", false},
+		{"invalid cursor", "cursor=notacursor", false},
 	}
 
 	for _, tc := range testCases {
@@ -671,6 +676,155 @@ func TestProgressionHistoryDataInsertAndQuery(t *testing.T) {
 	}
 }
 
+// TestProgressionHistoryCursor tests paging through applied progressions with a cursor.
+func TestProgressionHistoryCursor(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID, liftID, progressionID, _ := setupManualTriggerTestData(t, ts)
+	for i := 0; i < 3; i++ {
+		body := ManualTriggerRequest{ProgressionID: progressionID, LiftID: liftID, Force: true}
+		resp, err := authPostTrigger(ts.URL("/users/"+userID+"/progressions/trigger"), body, userID)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	listURL := ts.URL("/users/" + userID + "/progression-history?limit=2")
+	ids, metas := walkCursorPages(t, listURL, userID)
+	if len(ids) != 3 || len(metas) != 2 || metas[0].Total != 3 {
+		t.Fatalf("Expected 3 entries over 2 pages, got %v %+v", ids, metas)
+	}
+	ascending, _ := walkCursorPages(t, listURL+"&sortOrder=asc", userID)
+	if len(ascending) != 3 || ascending[0] != ids[2] || ascending[2] != ids[0] {
+		t.Errorf("Expected sortOrder=asc to reverse the default order, got %v and %v", ascending, ids)
+	}
+	if ids, _ := walkCursorPages(t, listURL+"&liftId="+liftID+"&endDate=2000-01-01", userID); len(ids) != 0 {
+		t.Errorf("Expected no entries before 2000, got %v", ids)
+	}
+}
+
+// TestProgressionHistoryRevert tests reverting an applied progression through the API.
+func TestProgressionHistoryRevert(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID, liftID, progressionID, _ := setupManualTriggerTestData(t, ts)
+
+	body := map[string]interface{}{"progressionId": progressionID, "liftId": liftID}
+	resp, err := authPostTrigger(ts.URL("/users/"+userID+"/progressions/trigger"), body, userID)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected trigger status 200, got %d", resp.StatusCode)
+	}
+
+	listHistory := func() []ProgressionHistoryTestEntry {
+		t.Helper()
+		resp, err := authGetHistory(ts.URL("/users/"+userID+"/progression-history?liftId="+liftID), userID)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		var history ProgressionHistoryTestListResponse
+		if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return history.Data
+	}
+
+	entries := listHistory()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 history entry, got %d", len(entries))
+	}
+	logID := entries[0].ID
+	revertURL := ts.URL("/users/" + userID + "/progression-history/" + logID + "/revert")
+
+	t.Run("other user is forbidden", func(t *testing.T) {
+		resp, err := authPostTrigger(revertURL, nil, "other-user-id")
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("unknown log returns 404", func(t *testing.T) {
+		resp, err := authPostTrigger(ts.URL("/users/"+userID+"/progression-history/"+uuid.New().String()+"/revert"), nil, userID)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", resp.StatusCode)
+		}
+	})
+
+	resp, err = authPostTrigger(revertURL, nil, userID)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, respBody)
+	}
+	var envelope struct {
+		Data struct {
+			RevertedLogID string  `json:"revertedLogId"`
+			RevertLogID   string  `json:"revertLogId"`
+			PreviousValue float64 `json:"previousValue"`
+			RestoredValue float64 `json:"restoredValue"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	resp.Body.Close()
+	if envelope.Data.RevertedLogID != logID || envelope.Data.RestoredValue != entries[0].PreviousValue {
+		t.Errorf("Unexpected revert response: %+v", envelope.Data)
+	}
+
+	entries = listHistory()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 history entries after revert, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		switch entry.ID {
+		case logID:
+			if entry.RevertedByLogID == nil || *entry.RevertedByLogID != envelope.Data.RevertLogID {
+				t.Errorf("Expected original entry to reference revert entry, got %v", entry.RevertedByLogID)
+			}
+		case envelope.Data.RevertLogID:
+			if entry.RevertsLogID == nil || *entry.RevertsLogID != logID {
+				t.Errorf("Expected revert entry to reference original entry, got %v", entry.RevertsLogID)
+			}
+		}
+	}
+
+	t.Run("reverting twice conflicts", func(t *testing.T) {
+		resp, err := authPostTrigger(revertURL, nil, userID)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d", resp.StatusCode)
+		}
+	})
+}
+
 // directDBInsertProgressionLog creates a progression log entry directly in the database
 // This is a helper for integration tests that need test data
 func directDBInsertProgressionLog(ctx context.Context, queries *db.Queries, userID, progressionID, liftID string, previousValue, newValue, delta float64, triggerType string, appliedAt time.Time) (string, error) {
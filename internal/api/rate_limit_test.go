@@ -0,0 +1,110 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/auth"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/server"
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// rateLimitedRequest sends a request as the user, or anonymously when userID is empty,
+// and returns the response with its body closed.
+func rateLimitedRequest(t *testing.T, method, url string, body interface{}, userID string) *http.Response {
+	t.Helper()
+	payload, _ := json.Marshal(body)
+	req, err := http.NewRequest(method, url, bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if userID != "" {
+		req.Header.Set("X-User-ID", userID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestRateLimits(t *testing.T) {
+	ts, err := testutil.NewTestServer(testutil.WithRateLimits(server.RateLimits{
+		Auth:  middleware.RateLimit{Requests: 2, Per: time.Minute},
+		Write: middleware.RateLimit{Requests: 1, Per: time.Minute},
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	t.Run("auth routes are limited per client", func(t *testing.T) {
+		credentials := map[string]string{"email": "nobody@example.com", "password": "password123"}
+		for i := 0; i < 2; i++ {
+			if resp := rateLimitedRequest(t, http.MethodPost, ts.URL("/auth/login"), credentials, ""); resp.StatusCode != http.StatusUnauthorized {
+				t.Fatalf("Expected 401 within the limit, got %d", resp.StatusCode)
+			}
+		}
+		resp := rateLimitedRequest(t, http.MethodPost, ts.URL("/auth/login"), credentials, "")
+		if resp.StatusCode != http.StatusTooManyRequests {
+			t.Fatalf("Expected 429, got %d", resp.StatusCode)
+		}
+		if resp.Header.Get("Retry-After") != "30" {
+			t.Errorf("Expected Retry-After 30, got %q", resp.Header.Get("Retry-After"))
+		}
+	})
+
+	t.Run("writes are limited per user", func(t *testing.T) {
+		url := ts.URL("/users/limited-user/profile")
+		if resp := rateLimitedRequest(t, http.MethodPut, url, map[string]string{"name": "Limited"}, "limited-user"); resp.StatusCode == http.StatusTooManyRequests {
+			t.Fatal("Expected the first write to be allowed")
+		}
+		if resp := rateLimitedRequest(t, http.MethodPut, url, map[string]string{"name": "Limited"}, "limited-user"); resp.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("Expected 429, got %d", resp.StatusCode)
+		}
+		if resp := rateLimitedRequest(t, http.MethodGet, ts.URL("/auth/me"), nil, "limited-user"); resp.StatusCode == http.StatusTooManyRequests {
+			t.Error("Expected reads to stay unlimited")
+		}
+		if resp := rateLimitedRequest(t, http.MethodPut, ts.URL("/users/other-user/profile"), map[string]string{"name": "Other"}, "other-user"); resp.StatusCode == http.StatusTooManyRequests {
+			t.Error("Expected other users to have their own limit")
+		}
+	})
+}
+
+func TestLoginLockout(t *testing.T) {
+	ts, err := testutil.NewTestServer(testutil.WithLoginLockout(auth.LockoutConfig{
+		AccountAttempts: 2,
+		IPAttempts:      10,
+		Lockout:         time.Minute,
+		MaxLockout:      time.Hour,
+		ResetAfter:      time.Hour,
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	coachingRequest(t, http.MethodPost, ts.URL("/auth/register"), map[string]string{
+		"email": "lockout@example.com", "password": "password123",
+	}, "", http.StatusCreated, nil)
+
+	wrong := map[string]string{"email": "lockout@example.com", "password": "wrong-password"}
+	coachingRequest(t, http.MethodPost, ts.URL("/auth/login"), wrong, "", http.StatusUnauthorized, nil)
+	coachingRequest(t, http.MethodPost, ts.URL("/auth/login"), wrong, "", http.StatusUnauthorized, nil)
+
+	resp := rateLimitedRequest(t, http.MethodPost, ts.URL("/auth/login"), map[string]string{
+		"email": "lockout@example.com", "password": "password123",
+	}, "")
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected the account to be locked, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") != "60" {
+		t.Errorf("Expected Retry-After 60, got %q", resp.Header.Get("Retry-After"))
+	}
+}
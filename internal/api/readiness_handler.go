@@ -0,0 +1,316 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/readiness"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/workout"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/repository"
+	"github.com/waynenilsen/power-pro-v3/internal/service"
+)
+
+// ReadinessHandler handles HTTP requests for readiness check-ins and program readiness mappings.
+type ReadinessHandler struct {
+	readinessService *service.ReadinessService
+	programRepo      *repository.ProgramRepository
+}
+
+// NewReadinessHandler creates a new ReadinessHandler.
+func NewReadinessHandler(readinessService *service.ReadinessService, programRepo *repository.ProgramRepository) *ReadinessHandler {
+	return &ReadinessHandler{
+		readinessService: readinessService,
+		programRepo:      programRepo,
+	}
+}
+
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *ReadinessHandler) WithTx(tx *sql.Tx) *ReadinessHandler {
+	txHandler := *h
+	txHandler.readinessService = h.readinessService.WithTx(tx)
+	txHandler.programRepo = h.programRepo.WithTx(tx)
+	return &txHandler
+}
+
+// ReadinessCheckInRequest represents the request body for a readiness check-in.
+type ReadinessCheckInRequest struct {
+	// Date is the day the check-in applies to (YYYY-MM-DD). Defaults to today.
+	Date           *string  `json:"date,omitempty"`
+	SleepQuality   *int     `json:"sleepQuality,omitempty"`
+	Soreness       *int     `json:"soreness,omitempty"`
+	Stress         *int     `json:"stress,omitempty"`
+	ReadinessScore *int     `json:"readinessScore,omitempty"`
+	HRV            *float64 `json:"hrv,omitempty"`
+	Notes          *string  `json:"notes,omitempty"`
+}
+
+// ReadinessCheckInResponse represents the API response format for a readiness check-in.
+type ReadinessCheckInResponse struct {
+	ID             string    `json:"id"`
+	UserID         string    `json:"userId"`
+	Date           string    `json:"date"`
+	SleepQuality   *int      `json:"sleepQuality"`
+	Soreness       *int      `json:"soreness"`
+	Stress         *int      `json:"stress"`
+	ReadinessScore *int      `json:"readinessScore"`
+	HRV            *float64  `json:"hrv"`
+	Score          float64   `json:"score"`
+	Notes          *string   `json:"notes"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// ReadinessAdjustmentResponse represents the readiness adjustment applied to a workout.
+type ReadinessAdjustmentResponse struct {
+	CheckInID      *string `json:"checkInId"`
+	Score          float64 `json:"score"`
+	LoadModifier   float64 `json:"loadModifier"`
+	VolumeModifier float64 `json:"volumeModifier"`
+}
+
+// ReadinessTierRequest represents a tier in a readiness mapping request or response.
+type ReadinessTierRequest struct {
+	MinScore       float64 `json:"minScore"`
+	LoadModifier   float64 `json:"loadModifier"`
+	VolumeModifier float64 `json:"volumeModifier"`
+}
+
+// ReadinessMappingRequest represents the request body for setting a program's readiness mapping.
+type ReadinessMappingRequest struct {
+	Tiers []ReadinessTierRequest `json:"tiers"`
+}
+
+// ReadinessMappingResponse represents the API response format for a program's readiness mapping.
+type ReadinessMappingResponse struct {
+	ProgramID string                 `json:"programId"`
+	IsDefault bool                   `json:"isDefault"`
+	Tiers     []ReadinessTierRequest `json:"tiers"`
+}
+
+func readinessCheckInToResponse(c *service.ReadinessCheckIn) ReadinessCheckInResponse {
+	return ReadinessCheckInResponse{
+		ID:             c.ID,
+		UserID:         c.UserID,
+		Date:           c.Date,
+		SleepQuality:   c.SleepQuality,
+		Soreness:       c.Soreness,
+		Stress:         c.Stress,
+		ReadinessScore: c.ReadinessScore,
+		HRV:            c.HRV,
+		Score:          c.Score,
+		Notes:          c.Notes,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
+
+func readinessAdjustmentToResponse(a *readiness.Adjustment) *ReadinessAdjustmentResponse {
+	if a == nil {
+		return nil
+	}
+	return &ReadinessAdjustmentResponse{
+		CheckInID:      a.CheckInID,
+		Score:          a.Score,
+		LoadModifier:   a.LoadModifier,
+		VolumeModifier: a.VolumeModifier,
+	}
+}
+
+func readinessMappingToResponse(m *service.ReadinessMapping) ReadinessMappingResponse {
+	tiers := make([]ReadinessTierRequest, len(m.Tiers))
+	for i, t := range m.Tiers {
+		tiers[i] = ReadinessTierRequest{
+			MinScore:       t.MinScore,
+			LoadModifier:   t.LoadModifier,
+			VolumeModifier: t.VolumeModifier,
+		}
+	}
+	return ReadinessMappingResponse{
+		ProgramID: m.ProgramID,
+		IsDefault: m.IsDefault,
+		Tiers:     tiers,
+	}
+}
+
+// CheckIn handles POST /users/{userId}/readiness
+// Records the readiness check-in for a day, replacing any earlier check-in for that day.
+func (h *ReadinessHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if userID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing user ID"))
+		return
+	}
+
+	// Authorization check: only the user themselves or an admin can check in
+	authUserID := middleware.GetUserID(r)
+	if authUserID != userID && !middleware.IsAdmin(r) {
+		writeDomainError(w, apperrors.NewForbidden("you can only check in for yourself"))
+		return
+	}
+
+	var req ReadinessCheckInRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	date := workout.GetDateString()
+	if req.Date != nil {
+		if _, err := time.Parse(service.CheckInDateFormat, *req.Date); err != nil {
+			writeDomainError(w, apperrors.NewValidation("date", "must be in YYYY-MM-DD format"))
+			return
+		}
+		date = *req.Date
+	}
+
+	if req.Notes != nil && len(*req.Notes) > 500 {
+		writeDomainError(w, apperrors.NewValidation("notes", "notes must be 500 characters or less"))
+		return
+	}
+
+	inputs := readiness.Inputs{
+		SleepQuality:   req.SleepQuality,
+		Soreness:       req.Soreness,
+		Stress:         req.Stress,
+		ReadinessScore: req.ReadinessScore,
+		HRV:            req.HRV,
+	}
+	if err := readiness.ValidateInputs(inputs); err != nil {
+		writeDomainError(w, apperrors.NewValidationMsg("validation failed"), err.Error())
+		return
+	}
+
+	checkIn, err := h.readinessService.RecordCheckIn(r.Context(), userID, date, inputs, req.Notes)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to record readiness check-in", err))
+		return
+	}
+
+	writeData(w, http.StatusCreated, readinessCheckInToResponse(checkIn))
+}
+
+// List handles GET /users/{userId}/readiness
+// Returns the user's readiness check-ins, most recent day first.
+func (h *ReadinessHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if userID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing user ID"))
+		return
+	}
+
+	// Authorization check: the user, their coach or an admin can view check-ins
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
+		writeDomainError(w, apperrors.NewForbidden("you can only access your own readiness check-ins"))
+		return
+	}
+
+	pg := ParsePagination(r.URL.Query())
+
+	checkIns, total, err := h.readinessService.ListCheckIns(r.Context(), userID, int64(pg.Limit), int64(pg.Offset))
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to list readiness check-ins", err))
+		return
+	}
+
+	data := make([]ReadinessCheckInResponse, len(checkIns))
+	for i, c := range checkIns {
+		data[i] = readinessCheckInToResponse(&c)
+	}
+
+	writePaginatedData(w, http.StatusOK, data, total, pg.Limit, pg.Offset)
+}
+
+// GetMapping handles GET /programs/{id}/readiness-mapping
+// Returns the program's readiness mapping, or the default mapping if none is configured.
+func (h *ReadinessHandler) GetMapping(w http.ResponseWriter, r *http.Request) {
+	programID, ok := h.requireProgram(w, r)
+	if !ok {
+		return
+	}
+
+	mapping, err := h.readinessService.GetMapping(r.Context(), programID)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to get readiness mapping", err))
+		return
+	}
+
+	writeData(w, http.StatusOK, readinessMappingToResponse(mapping))
+}
+
+// SetMapping handles PUT /programs/{id}/readiness-mapping
+// Replaces the program's readiness mapping.
+func (h *ReadinessHandler) SetMapping(w http.ResponseWriter, r *http.Request) {
+	programID, ok := h.requireProgram(w, r)
+	if !ok {
+		return
+	}
+
+	var req ReadinessMappingRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	mapping := readiness.Mapping{Tiers: make([]readiness.Tier, len(req.Tiers))}
+	for i, t := range req.Tiers {
+		mapping.Tiers[i] = readiness.Tier{
+			MinScore:       t.MinScore,
+			LoadModifier:   t.LoadModifier,
+			VolumeModifier: t.VolumeModifier,
+		}
+	}
+	if err := readiness.ValidateMapping(mapping); err != nil {
+		writeDomainError(w, apperrors.NewValidationMsg("validation failed"), err.Error())
+		return
+	}
+
+	saved, err := h.readinessService.SetMapping(r.Context(), programID, mapping)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to save readiness mapping", err))
+		return
+	}
+
+	writeData(w, http.StatusOK, readinessMappingToResponse(saved))
+}
+
+// DeleteMapping handles DELETE /programs/{id}/readiness-mapping
+// Removes the program's readiness mapping so the default mapping applies.
+func (h *ReadinessHandler) DeleteMapping(w http.ResponseWriter, r *http.Request) {
+	programID, ok := h.requireProgram(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.readinessService.DeleteMapping(r.Context(), programID); err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to delete readiness mapping", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireProgram reads the program ID from the path and checks the program exists.
+// Writes an error response and returns false if it does not.
+func (h *ReadinessHandler) requireProgram(w http.ResponseWriter, r *http.Request) (string, bool) {
+	programID := r.PathValue("id")
+	if programID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing program ID"))
+		return "", false
+	}
+
+	p, err := h.programRepo.GetByID(programID)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to get program", err))
+		return "", false
+	}
+	if p == nil {
+		writeDomainError(w, apperrors.NewNotFound("program", programID))
+		return "", false
+	}
+
+	return programID, true
+}
@@ -0,0 +1,200 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/waynenilsen/power-pro-v3/internal/api"
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// ReadinessCheckInEnvelope wraps a readiness check-in response.
+type ReadinessCheckInEnvelope struct {
+	Data api.ReadinessCheckInResponse `json:"data"`
+}
+
+// ReadinessWorkoutEnvelope wraps a workout response including its readiness adjustment.
+type ReadinessWorkoutEnvelope struct {
+	Data api.WorkoutResponse `json:"data"`
+}
+
+// ReadinessSessionEnvelope wraps a workout session response including its readiness adjustment.
+type ReadinessSessionEnvelope struct {
+	Data api.WorkoutSessionResponse `json:"data"`
+}
+
+func TestReadiness(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID := createTestUserForProfile(t, ts, "readiness@example.com", "password123", "Readiness User")
+	setup := setupWorkoutTest(t, ts, userID)
+
+	t.Run("workout is unadjusted without a check-in", func(t *testing.T) {
+		resp, err := userGetWorkout(ts.URL("/users/"+userID+"/workout"), userID)
+		if err != nil {
+			t.Fatalf("Failed to get workout: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var envelope ReadinessWorkoutEnvelope
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if envelope.Data.Readiness != nil {
+			t.Errorf("Expected no readiness adjustment, got %+v", envelope.Data.Readiness)
+		}
+		if len(envelope.Data.Exercises[0].Sets) != 5 {
+			t.Errorf("Expected 5 sets, got %d", len(envelope.Data.Exercises[0].Sets))
+		}
+	})
+
+	t.Run("invalid check-in returns 400", func(t *testing.T) {
+		for _, body := range []string{`{}`, `{"soreness": 6}`, `{"stress": 3, "date": "yesterday"}`} {
+			resp, _ := userRequestBodyweight(http.MethodPost, ts.URL("/users/"+userID+"/readiness"), userID, body)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", body, resp.StatusCode)
+			}
+		}
+	})
+
+	t.Run("mapping changes require admin", func(t *testing.T) {
+		resp, _ := userRequestBodyweight(http.MethodPut, ts.URL("/programs/"+setup.ProgramID+"/readiness-mapping"), userID, `{"tiers": []}`)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", resp.StatusCode)
+		}
+
+		resp, _ = adminPut(ts.URL("/programs/"+setup.ProgramID+"/readiness-mapping"), `{"tiers": [{"minScore": 0, "loadModifier": 200, "volumeModifier": 100}]}`)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for invalid mapping, got %d", resp.StatusCode)
+		}
+	})
+
+	mapping := `{"tiers": [
+		{"minScore": 50, "loadModifier": 100, "volumeModifier": 100},
+		{"minScore": 0, "loadModifier": 80, "volumeModifier": 60}
+	]}`
+	resp, err := adminPut(ts.URL("/programs/"+setup.ProgramID+"/readiness-mapping"), mapping)
+	if err != nil {
+		t.Fatalf("Failed to set readiness mapping: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, body)
+	}
+	resp.Body.Close()
+
+	t.Run("check-in records the score", func(t *testing.T) {
+		resp, err := userRequestBodyweight(http.MethodPost, ts.URL("/users/"+userID+"/readiness"), userID, `{"sleepQuality": 2, "soreness": 4, "stress": 4}`)
+		if err != nil {
+			t.Fatalf("Failed to check in: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("Expected status 201, got %d: %s", resp.StatusCode, body)
+		}
+
+		var envelope ReadinessCheckInEnvelope
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if envelope.Data.Score != 25 {
+			t.Errorf("Expected score 25, got %v", envelope.Data.Score)
+		}
+	})
+
+	t.Run("workout applies the load and volume adjustment", func(t *testing.T) {
+		resp, err := userGetWorkout(ts.URL("/users/"+userID+"/workout"), userID)
+		if err != nil {
+			t.Fatalf("Failed to get workout: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var envelope ReadinessWorkoutEnvelope
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		adj := envelope.Data.Readiness
+		if adj == nil || adj.LoadModifier != 80 || adj.VolumeModifier != 60 {
+			t.Fatalf("Expected 80%% load and 60%% volume, got %+v", adj)
+		}
+
+		// 75% * 80% = 60% of 300 TM = 180; 60% of 5 sets = 3
+		sets := envelope.Data.Exercises[0].Sets
+		if len(sets) != 3 {
+			t.Fatalf("Expected 3 sets, got %d", len(sets))
+		}
+		if sets[0].Weight != 180 {
+			t.Errorf("Expected weight 180, got %v", sets[0].Weight)
+		}
+	})
+
+	t.Run("starting a workout records the adjustment on the session", func(t *testing.T) {
+		resp, err := authPostUser(ts.URL("/workouts/start"), `{}`, userID)
+		if err != nil {
+			t.Fatalf("Failed to start workout: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("Expected status 201, got %d: %s", resp.StatusCode, body)
+		}
+
+		var started ReadinessSessionEnvelope
+		if err := json.NewDecoder(resp.Body).Decode(&started); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if started.Data.Readiness == nil || started.Data.Readiness.LoadModifier != 80 {
+			t.Fatalf("Expected readiness on started session, got %+v", started.Data.Readiness)
+		}
+
+		getResp, err := userGetWorkout(ts.URL("/workouts/"+started.Data.ID), userID)
+		if err != nil {
+			t.Fatalf("Failed to get session: %v", err)
+		}
+		defer getResp.Body.Close()
+
+		var fetched ReadinessSessionEnvelope
+		if err := json.NewDecoder(getResp.Body).Decode(&fetched); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if fetched.Data.Readiness == nil || fetched.Data.Readiness.Score != 25 {
+			t.Errorf("Expected recorded readiness score 25, got %+v", fetched.Data.Readiness)
+		}
+	})
+
+	t.Run("lists check-ins", func(t *testing.T) {
+		resp, err := userRequestBodyweight(http.MethodGet, ts.URL("/users/"+userID+"/readiness"), userID, "")
+		if err != nil {
+			t.Fatalf("Failed to list check-ins: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var envelope struct {
+			Data []api.ReadinessCheckInResponse `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if len(envelope.Data) != 1 {
+			t.Errorf("Expected 1 check-in, got %d", len(envelope.Data))
+		}
+	})
+
+	t.Run("other users cannot check in", func(t *testing.T) {
+		resp, _ := userRequestBodyweight(http.MethodPost, ts.URL("/users/"+userID+"/readiness"), "someone-else", `{"stress": 3}`)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -2,6 +2,7 @@
 package api
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"log"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/repository"
 )
 
 // ===== Standard Response Envelope Types =====
@@ -32,10 +34,11 @@ type Response struct {
 // Meta contains optional metadata for responses.
 type Meta struct {
 	// Pagination fields (for list responses)
-	Total   *int64 `json:"total,omitempty"`
-	Limit   *int   `json:"limit,omitempty"`
-	Offset  *int   `json:"offset,omitempty"`
-	HasMore *bool  `json:"hasMore,omitempty"`
+	Total      *int64  `json:"total,omitempty"`
+	Limit      *int    `json:"limit,omitempty"`
+	Offset     *int    `json:"offset,omitempty"`
+	HasMore    *bool   `json:"hasMore,omitempty"`
+	NextCursor *string `json:"nextCursor,omitempty"`
 }
 
 // ErrorDetail represents the structured error information.
@@ -101,12 +104,84 @@ type QueryGetter interface {
 	Get(key string) string
 }
 
+// ===== Cursor Pagination Utilities =====
+//
+// History lists (logged sets, workouts, lift maxes, progression history) also support
+// opaque cursor pagination ordered by their time field:
+//   - Query params: sortOrder (asc or desc; default desc), limit, and cursor (the
+//     nextCursor of the previous page)
+//   - The first page may use offset and includes total; pages fetched with a cursor
+//     skip the count and include only limit, hasMore and nextCursor
+//   - A cursor is only valid with the sort order it was issued for; filters must be repeated
+
+// CursorPage holds parsed cursor pagination parameters.
+type CursorPage struct {
+	Limit     int
+	Offset    int
+	SortOrder repository.SortOrder
+	// After is the position decoded from the cursor; nil on the first page.
+	After *repository.Keyset
+}
+
+// cursorToken is the encoded form of a cursor.
+type cursorToken struct {
+	SortOrder repository.SortOrder `json:"o"`
+	Value     string               `json:"v"`
+	Seq       int64                `json:"q"`
+}
+
+// ParseCursorPage extracts limit, offset, sortOrder and cursor from query parameters.
+// The sort order defaults to the cursor's, or descending without one.
+// Returns an error for an unknown sort order, a malformed cursor, a cursor issued for
+// another sort order, or an offset combined with a cursor.
+func ParseCursorPage(query QueryGetter) (CursorPage, error) {
+	pg := ParsePagination(query)
+	page := CursorPage{Limit: pg.Limit, Offset: pg.Offset, SortOrder: repository.SortOrder(strings.ToLower(query.Get("sortOrder")))}
+
+	if c := query.Get("cursor"); c != "" {
+		var token cursorToken
+		raw, err := base64.RawURLEncoding.DecodeString(c)
+		if err != nil || json.Unmarshal(raw, &token) != nil || token.Seq <= 0 {
+			return CursorPage{}, apperrors.NewValidation("cursor", "invalid cursor")
+		}
+		if page.SortOrder == "" {
+			page.SortOrder = token.SortOrder
+		}
+		if token.SortOrder != page.SortOrder {
+			return CursorPage{}, apperrors.NewValidation("cursor", "cursor was issued for sortOrder "+string(token.SortOrder))
+		}
+		if query.Get("offset") != "" {
+			return CursorPage{}, apperrors.NewValidation("offset", "cannot be combined with cursor")
+		}
+		page.After = &repository.Keyset{Value: token.Value, Seq: token.Seq}
+	}
+
+	if page.SortOrder == "" {
+		page.SortOrder = repository.SortDesc
+	}
+	if page.SortOrder != repository.SortAsc && page.SortOrder != repository.SortDesc {
+		return CursorPage{}, apperrors.NewValidation("sortOrder", "invalid value; valid values: asc, desc")
+	}
+	return page, nil
+}
+
+// NextCursor encodes the cursor that continues the list after next, or nil on the last page.
+func (p CursorPage) NextCursor(next *repository.Keyset) *string {
+	if next == nil {
+		return nil
+	}
+	raw, _ := json.Marshal(cursorToken{SortOrder: p.SortOrder, Value: next.Value, Seq: next.Seq})
+	cursor := base64.RawURLEncoding.EncodeToString(raw)
+	return &cursor
+}
+
 // ===== Filtering Utilities =====
 //
 // All list endpoints use consistent filter parameter naming:
 //   - Simple filters: field names in snake_case (e.g., ?lift_id=123&user_id=456)
 //   - Boolean filters: use "true"/"false" or "1"/"0" (e.g., ?is_competition_lift=true)
-//   - Date ranges: use _after/_before suffixes (e.g., ?created_after=2024-01-01&created_before=2024-12-31)
+//   - Date ranges: use _after/_before suffixes on the field name, both inclusive; a date
+//     without a time includes the whole day (e.g., ?created_after=2024-01-01&created_before=2024-12-31)
 //   - Numeric ranges: use _gte/_lte suffixes (e.g., ?weight_gte=100&weight_lte=200)
 //
 // Filter behavior:
@@ -292,6 +367,22 @@ func writePaginatedData(w http.ResponseWriter, status int, data interface{}, tot
 	})
 }
 
+// writeCursorPaginatedData writes a cursor-paginated list response with the standard envelope.
+// total is counted for the first page only; later pages report just limit, hasMore and nextCursor.
+func writeCursorPaginatedData(w http.ResponseWriter, status int, data interface{}, page CursorPage, total *int64, next *repository.Keyset) {
+	hasMore := next != nil
+	meta := &Meta{
+		Limit:      &page.Limit,
+		HasMore:    &hasMore,
+		NextCursor: page.NextCursor(next),
+	}
+	if total != nil {
+		meta.Total = total
+		meta.Offset = &page.Offset
+	}
+	writeJSON(w, status, Response{Data: data, Meta: meta})
+}
+
 // writeError writes an error response using the standard error envelope.
 func writeError(w http.ResponseWriter, status int, code, message string, details interface{}) {
 	resp := ErrorResponse{
@@ -334,6 +425,9 @@ func writeDomainError(w http.ResponseWriter, err error, details ...string) {
 	if apperrors.IsInternal(err) {
 		log.Printf("Internal error: %v", err)
 	}
+	if retryAfter := apperrors.GetRetryAfter(err); retryAfter > 0 {
+		setRetryAfter(w, retryAfter)
+	}
 
 	// Convert details slice to structured format if present
 	var detailsObj interface{}
@@ -344,6 +438,11 @@ func writeDomainError(w http.ResponseWriter, err error, details ...string) {
 	writeError(w, status, code, message, detailsObj)
 }
 
+// setRetryAfter sets the Retry-After header in whole seconds, rounding up.
+func setRetryAfter(w http.ResponseWriter, d time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int((d+time.Second-1)/time.Second)))
+}
+
 // mapErrorToStatus maps a domain error to an HTTP status code.
 func mapErrorToStatus(err error) int {
 	switch {
@@ -359,6 +458,8 @@ func mapErrorToStatus(err error) int {
 		return http.StatusUnauthorized
 	case apperrors.IsBadRequest(err):
 		return http.StatusBadRequest
+	case apperrors.IsTooManyRequests(err):
+		return http.StatusTooManyRequests
 	default:
 		return http.StatusInternalServerError
 	}
@@ -379,6 +480,8 @@ func domainErrorToCode(err error) string {
 		return "UNAUTHORIZED"
 	case apperrors.IsBadRequest(err):
 		return "BAD_REQUEST"
+	case apperrors.IsTooManyRequests(err):
+		return "RATE_LIMITED"
 	default:
 		return "INTERNAL_ERROR"
 	}
@@ -399,6 +502,8 @@ func httpStatusToErrorCode(status int) string {
 		return "CONFLICT"
 	case http.StatusUnprocessableEntity:
 		return "UNPROCESSABLE_ENTITY"
+	case http.StatusTooManyRequests:
+		return "RATE_LIMITED"
 	default:
 		return "INTERNAL_ERROR"
 	}
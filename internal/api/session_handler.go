@@ -82,6 +82,10 @@ func (h *SessionHandler) GetNextSet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	writeData(w, http.StatusOK, nextSetToResponse(result))
+}
+
+func nextSetToResponse(result *service.NextSetResult) NextSetResponse {
 	response := NextSetResponse{
 		IsComplete:         result.IsComplete,
 		TotalSetsCompleted: result.TotalSetsCompleted,
@@ -97,6 +101,5 @@ func (h *SessionHandler) GetNextSet(w http.ResponseWriter, r *http.Request) {
 			IsWorkSet:  result.NextSet.IsWorkSet,
 		}
 	}
-
-	writeData(w, http.StatusOK, response)
+	return response
 }
@@ -5,9 +5,9 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/userprogramstate"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
-	"github.com/waynenilsen/power-pro-v3/internal/middleware"
 	"github.com/waynenilsen/power-pro-v3/internal/repository"
 )
 
@@ -42,10 +42,8 @@ func (h *StateAdvancementHandler) Advance(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Authorization check: only the user themselves or an admin can advance state
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if authUserID != userID && !isAdmin {
+	// Authorization check: the user, their coach or an admin can advance state
+	if !canAccessUser(r, userID, coaching.PermissionManageEnrollment) {
 		writeDomainError(w, apperrors.NewForbidden("you can only advance your own state"))
 		return
 	}
@@ -0,0 +1,329 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/event"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/outbox"
+	"github.com/waynenilsen/power-pro-v3/internal/repository"
+	"github.com/waynenilsen/power-pro-v3/internal/service"
+)
+
+const (
+	// StreamHeartbeatInterval is how often an idle stream sends a comment to keep
+	// proxies and clients from closing the connection. The stream's session is
+	// revalidated at the same interval.
+	StreamHeartbeatInterval = 15 * time.Second
+
+	// StreamEventNextSet is the stream event type for next-set recommendations. It is
+	// sent after SET_LOGGED events for variable set scheme prescriptions.
+	StreamEventNextSet = "NEXT_SET"
+
+	streamBatchSize = 100
+)
+
+// StreamHandler handles Server-Sent Event streams of a user's state events.
+type StreamHandler struct {
+	outbox         *outbox.Service
+	sessionRepo    *repository.WorkoutSessionRepository
+	stateRepo      *repository.UserProgramStateRepository
+	sessionService *service.SessionService
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewStreamHandler creates a new StreamHandler.
+// Streams follow events as they are committed to outboxService; sessionService supplies
+// the next-set recommendations sent after each logged set.
+func NewStreamHandler(
+	outboxService *outbox.Service,
+	sessionRepo *repository.WorkoutSessionRepository,
+	stateRepo *repository.UserProgramStateRepository,
+	sessionService *service.SessionService,
+) *StreamHandler {
+	return &StreamHandler{
+		outbox:         outboxService,
+		sessionRepo:    sessionRepo,
+		stateRepo:      stateRepo,
+		sessionService: sessionService,
+		done:           make(chan struct{}),
+	}
+}
+
+// Close ends every open stream. Streams never go idle, so the server calls Close
+// when it shuts down.
+func (h *StreamHandler) Close() {
+	h.closeOnce.Do(func() {
+		close(h.done)
+	})
+}
+
+// NextSetStreamResponse represents a next-set recommendation sent on a stream.
+type NextSetStreamResponse struct {
+	SessionID      string `json:"sessionId"`
+	PrescriptionID string `json:"prescriptionId"`
+	NextSetResponse
+}
+
+// StreamWorkout handles GET /workouts/{id}/stream
+// Streams the events of one workout session: sets logged, next-set recommendations,
+// personal records and status changes.
+func (h *StreamHandler) StreamWorkout(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("id")
+	if sessionID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing session ID"))
+		return
+	}
+
+	session, err := h.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to get session", err))
+		return
+	}
+	if session == nil {
+		writeDomainError(w, apperrors.NewNotFound("workout session", sessionID))
+		return
+	}
+
+	state, err := h.stateRepo.GetByID(session.UserProgramStateID)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to get program state", err))
+		return
+	}
+	if state == nil {
+		writeDomainError(w, apperrors.NewInternal("session references invalid program state", nil))
+		return
+	}
+
+	// Authorization: the session's owner, their coach or an admin can stream it
+	if !canAccessUser(r, state.UserID, coaching.PermissionViewLogs) {
+		writeDomainError(w, apperrors.NewForbidden("you can only stream your own workout sessions"))
+		return
+	}
+
+	h.stream(w, r, state.UserID, func(evt *outbox.Event) bool {
+		id, _ := evt.Payload[event.PayloadSessionID].(string)
+		return id == sessionID
+	})
+}
+
+// StreamUser handles GET /users/{userId}/stream
+// Streams every state event for a user.
+func (h *StreamHandler) StreamUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if userID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing user ID"))
+		return
+	}
+
+	// Authorization: the user, their coach or an admin can stream the user's events
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
+		writeDomainError(w, apperrors.NewForbidden("you can only stream your own events"))
+		return
+	}
+
+	h.stream(w, r, userID, func(*outbox.Event) bool { return true })
+}
+
+// stream writes the user's events that match, starting after the client's last event ID
+// or, for a new stream, after the newest stored event. It returns when the client
+// disconnects, the handler is closed, or the session the stream was opened with is
+// revoked or expires, which is checked before sending events and on each heartbeat.
+func (h *StreamHandler) stream(w http.ResponseWriter, r *http.Request, userID string, match func(*outbox.Event) bool) {
+	ctx := r.Context()
+
+	after, err := lastEventID(r)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if after < 0 {
+		if after, err = h.outbox.LatestSeq(ctx); err != nil {
+			writeDomainError(w, err)
+			return
+		}
+	}
+
+	// Streams outlive the server's write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(StreamHeartbeatInterval)
+	defer heartbeat.Stop()
+	revalidate := func() bool {
+		if err := middleware.Revalidate(r); err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Stream closed: session no longer valid for user %s: %v", middleware.GetUserID(r), err)
+			}
+			return false
+		}
+		return true
+	}
+
+	for {
+		// Take the channel before reading so a commit in between is not missed
+		changed := h.outbox.Changed()
+		events, err := h.outbox.ListUserEventsAfter(ctx, userID, after, streamBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Stream error: %v", err)
+			}
+			return
+		}
+
+		matched := make([]*outbox.Event, 0, len(events))
+		for i := range events {
+			after = events[i].Seq
+			if match(&events[i]) {
+				matched = append(matched, &events[i])
+			}
+		}
+		if len(matched) > 0 {
+			if !revalidate() {
+				return
+			}
+			if err := h.writeEvents(w, r, matched); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+			heartbeat.Reset(StreamHeartbeatInterval)
+		}
+		if len(events) == streamBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.done:
+			return
+		case <-changed:
+		case <-heartbeat.C:
+			if !revalidate() {
+				return
+			}
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeEvents writes events as stream frames, following each SET_LOGGED with the
+// next-set recommendation for its prescription. When a batch holds several sets for the
+// same prescription, only the last is followed by a recommendation.
+func (h *StreamHandler) writeEvents(w io.Writer, r *http.Request, events []*outbox.Event) error {
+	type prescriptionKey struct{ sessionID, prescriptionID string }
+	lastSet := make(map[prescriptionKey]int)
+	for i, evt := range events {
+		if evt.Type == event.EventSetLogged {
+			se := evt.StateEvent()
+			lastSet[prescriptionKey{se.GetString(event.PayloadSessionID), se.GetString(event.PayloadPrescriptionID)}] = i
+		}
+	}
+
+	for i, evt := range events {
+		if err := writeStreamFrame(w, strconv.FormatInt(evt.Seq, 10), string(evt.Type), storedEventToResponse(evt)); err != nil {
+			return err
+		}
+		if evt.Type != event.EventSetLogged {
+			continue
+		}
+
+		se := evt.StateEvent()
+		key := prescriptionKey{se.GetString(event.PayloadSessionID), se.GetString(event.PayloadPrescriptionID)}
+		if lastSet[key] != i || key.prescriptionID == "" {
+			continue
+		}
+		next, ok := h.nextSet(r, evt.UserID, key.sessionID, key.prescriptionID)
+		if !ok {
+			continue
+		}
+		if err := writeStreamFrame(w, "", StreamEventNextSet, next); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextSet returns the next-set recommendation for a prescription, or false when the
+// prescription has none.
+func (h *StreamHandler) nextSet(r *http.Request, userID, sessionID, prescriptionID string) (NextSetStreamResponse, bool) {
+	result, err := h.sessionService.GetNextSet(r.Context(), service.NextSetRequest{
+		SessionID:      sessionID,
+		PrescriptionID: prescriptionID,
+		UserID:         userID,
+	})
+	if err != nil {
+		if !errors.Is(err, service.ErrNotVariableScheme) &&
+			!errors.Is(err, service.ErrPrescriptionNotFound) &&
+			!errors.Is(err, service.ErrNoSetsLogged) {
+			log.Printf("Stream error: failed to get next set: %v", err)
+		}
+		return NextSetStreamResponse{}, false
+	}
+	return NextSetStreamResponse{
+		SessionID:       sessionID,
+		PrescriptionID:  prescriptionID,
+		NextSetResponse: nextSetToResponse(result),
+	}, true
+}
+
+// writeStreamFrame writes one Server-Sent Event. Frames without an ID do not move the
+// client's last event ID.
+func writeStreamFrame(w io.Writer, id, eventType string, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, body)
+	return err
+}
+
+// lastEventID returns the sequence number a resuming client last received, from the
+// Last-Event-ID header or the lastEventId query parameter, or -1 for a new stream.
+func lastEventID(r *http.Request) (int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	if value == "" {
+		return -1, nil
+	}
+	seq, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, apperrors.NewValidation("lastEventId", "must be a non-negative integer")
+	}
+	return seq, nil
+}
@@ -0,0 +1,357 @@
+package api_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// streamFrame is one Server-Sent Event read from a stream.
+type streamFrame struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// streamWriter is a ResponseWriter that passes the response body through a pipe as it is
+// written, so a test can read a stream while the handler is still serving it.
+type streamWriter struct {
+	header     http.Header
+	status     chan int
+	headerOnce sync.Once
+	pw         *io.PipeWriter
+}
+
+func (sw *streamWriter) Header() http.Header { return sw.header }
+
+func (sw *streamWriter) WriteHeader(status int) {
+	sw.headerOnce.Do(func() { sw.status <- status })
+}
+
+func (sw *streamWriter) Write(p []byte) (int, error) {
+	sw.WriteHeader(http.StatusOK)
+	return sw.pw.Write(p)
+}
+
+func (sw *streamWriter) Flush() {}
+
+// testStream is an open event stream.
+type testStream struct {
+	Status int
+	Header http.Header
+	frames chan streamFrame
+}
+
+// next returns the next frame, failing the test if none arrives in time.
+func (s *testStream) next(t *testing.T) streamFrame {
+	t.Helper()
+	select {
+	case frame, ok := <-s.frames:
+		if !ok {
+			t.Fatal("Stream closed")
+		}
+		return frame
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a stream event")
+	}
+	return streamFrame{}
+}
+
+// nextOfType skips frames until one of the given event type arrives.
+func (s *testStream) nextOfType(t *testing.T, eventType string) streamFrame {
+	t.Helper()
+	for {
+		if frame := s.next(t); frame.Event == eventType {
+			return frame
+		}
+	}
+}
+
+// openTestStream serves a stream request through the server's handler and parses its
+// frames in the background until the test ends.
+func openTestStream(t *testing.T, ts *testutil.TestServer, path string, header http.Header) *testStream {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	pr, pw := io.Pipe()
+	sw := &streamWriter{header: http.Header{}, status: make(chan int, 1), pw: pw}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ts.Server.Handler().ServeHTTP(sw, req)
+		pw.Close()
+	}()
+
+	stream := &testStream{frames: make(chan streamFrame, 100)}
+	go func() {
+		defer close(stream.frames)
+		reader := bufio.NewReader(pr)
+		var frame streamFrame
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				io.Copy(io.Discard, pr)
+				return
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "":
+				if frame.Event != "" {
+					stream.frames <- frame
+				}
+				frame = streamFrame{}
+			case strings.HasPrefix(line, "id: "):
+				frame.ID = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				frame.Event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				frame.Data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	select {
+	case stream.Status = <-sw.status:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the stream to open")
+	}
+	stream.Header = sw.header
+	return stream
+}
+
+func streamHeader(userID string) http.Header {
+	header := http.Header{}
+	header.Set("X-User-ID", userID)
+	return header
+}
+
+func TestStreamHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID := "stream-user"
+	createLSTestUser(t, ts, userID)
+	liftID := createLSTestLift(t, ts, "Squat", "squat-stream")
+	cycleID := createLSTestCycle(t, ts, "Stream Cycle")
+	programID := createLSTestProgram(t, ts, "Stream Program", "stream-program", cycleID)
+	enrollLSTestUser(t, ts, userID, programID)
+	sessionID := startLSWorkoutSession(t, ts, userID)
+
+	// A variable set scheme prescription, so logged sets get next-set recommendations
+	prescResp, err := adminPost(ts.URL("/prescriptions"), fmt.Sprintf(`{
+		"liftId": "%s",
+		"loadStrategy": {"type": "PERCENT_OF", "referenceType": "TRAINING_MAX", "percentage": 75},
+		"setScheme": {"type": "MRS", "target_total_reps": 25, "min_reps_per_set": 3, "max_sets": 10},
+		"order": 0
+	}`, liftID))
+	if err != nil {
+		t.Fatalf("Failed to create prescription: %v", err)
+	}
+	var prescEnvelope PrescriptionEnvelopeInteg
+	json.NewDecoder(prescResp.Body).Decode(&prescEnvelope)
+	prescResp.Body.Close()
+	prescriptionID := prescEnvelope.Data.ID
+
+	logSet := func(t *testing.T, setNumber, reps int) {
+		t.Helper()
+		resp, err := authPostLoggedSets(ts.URL("/sessions/"+sessionID+"/sets"), fmt.Sprintf(`{
+			"sets": [{"prescriptionId": "%s", "liftId": "%s", "setNumber": %d, "weight": 300.0, "targetReps": 3, "repsPerformed": %d}]
+		}`, prescriptionID, liftID, setNumber, reps), userID)
+		if err != nil {
+			t.Fatalf("Failed to log set: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+		}
+	}
+
+	var setLoggedID string
+	t.Run("streams workout events with next-set recommendations", func(t *testing.T) {
+		stream := openTestStream(t, ts, ts.URL("/workouts/"+sessionID+"/stream"), streamHeader(userID))
+		if stream.Status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", stream.Status)
+		}
+		if ct := stream.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Errorf("Expected Content-Type text/event-stream, got %s", ct)
+		}
+
+		logSet(t, 1, 10)
+
+		frame := stream.next(t)
+		if frame.Event != "SET_LOGGED" || frame.ID == "" {
+			t.Fatalf("Expected a SET_LOGGED event with an ID, got %+v", frame)
+		}
+		setLoggedID = frame.ID
+		var setEvent StoredEventTestResponse
+		json.Unmarshal([]byte(frame.Data), &setEvent)
+		if setEvent.UserID != userID || setEvent.Payload["sessionId"] != sessionID || setEvent.Payload["prescriptionId"] != prescriptionID {
+			t.Errorf("Unexpected SET_LOGGED data: %s", frame.Data)
+		}
+
+		frame = stream.next(t)
+		if frame.Event != "NEXT_SET" || frame.ID != "" {
+			t.Fatalf("Expected a NEXT_SET event without an ID, got %+v", frame)
+		}
+		var nextSet struct {
+			SessionID          string `json:"sessionId"`
+			PrescriptionID     string `json:"prescriptionId"`
+			IsComplete         bool   `json:"isComplete"`
+			TotalRepsCompleted int    `json:"totalRepsCompleted"`
+			NextSet            *struct {
+				SetNumber int `json:"setNumber"`
+			} `json:"nextSet"`
+		}
+		json.Unmarshal([]byte(frame.Data), &nextSet)
+		if nextSet.SessionID != sessionID || nextSet.PrescriptionID != prescriptionID || nextSet.TotalRepsCompleted != 10 || nextSet.IsComplete {
+			t.Errorf("Unexpected NEXT_SET data: %s", frame.Data)
+		}
+		if nextSet.NextSet == nil || nextSet.NextSet.SetNumber != 2 {
+			t.Errorf("Expected set 2 to be recommended, got %s", frame.Data)
+		}
+
+		// The first set of a lift is also a personal record
+		if frame := stream.next(t); frame.Event != "PR_ACHIEVED" {
+			t.Errorf("Expected a PR_ACHIEVED event, got %+v", frame)
+		}
+	})
+
+	t.Run("resumes after the last event ID", func(t *testing.T) {
+		logSet(t, 2, 8)
+
+		header := streamHeader(userID)
+		header.Set("Last-Event-ID", setLoggedID)
+		stream := openTestStream(t, ts, ts.URL("/workouts/"+sessionID+"/stream"), header)
+		if stream.Status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", stream.Status)
+		}
+
+		frame := stream.nextOfType(t, "SET_LOGGED")
+		var setEvent StoredEventTestResponse
+		json.Unmarshal([]byte(frame.Data), &setEvent)
+		seq, _ := strconv.ParseInt(frame.ID, 10, 64)
+		lastSeq, _ := strconv.ParseInt(setLoggedID, 10, 64)
+		if seq <= lastSeq || setEvent.Payload["repsPerformed"] != float64(8) {
+			t.Errorf("Expected the second set after event %s, got %+v", setLoggedID, frame)
+		}
+	})
+
+	t.Run("streams a user's status changes", func(t *testing.T) {
+		stream := openTestStream(t, ts, ts.URL("/users/"+userID+"/stream?lastEventId=0"), streamHeader(userID))
+		if stream.Status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", stream.Status)
+		}
+		if frame := stream.next(t); frame.Event != "ENROLLED" {
+			t.Errorf("Expected the stream to start from the first event, got %+v", frame)
+		}
+
+		finishLSWorkoutSession(t, ts, sessionID, userID)
+		stream.nextOfType(t, "WORKOUT_COMPLETED")
+	})
+
+	t.Run("accepts a session token in the query", func(t *testing.T) {
+		resp, _ := anonPost(ts.URL("/auth/register"), `{"email": "streamer@example.com", "password": "password123", "name": "Streamer"}`)
+		resp.Body.Close()
+		resp, err := anonPost(ts.URL("/auth/login"), `{"email": "streamer@example.com", "password": "password123"}`)
+		if err != nil {
+			t.Fatalf("Failed to log in: %v", err)
+		}
+		var login AuthLoginEnvelope
+		json.NewDecoder(resp.Body).Decode(&login)
+		resp.Body.Close()
+
+		stream := openTestStream(t, ts, ts.URL("/users/"+login.Data.User.ID+"/stream?access_token="+login.Data.Token), nil)
+		if stream.Status != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", stream.Status)
+		}
+
+		resp, _ = http.Get(ts.URL("/users/" + userID + "/stream?access_token=" + login.Data.Token))
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403 for another user's stream, got %d", resp.StatusCode)
+		}
+
+		// Logging out ends the stream before it sends any further events
+		req, _ := http.NewRequest(http.MethodPost, ts.URL("/auth/logout"), nil)
+		req.Header.Set("Authorization", "Bearer "+login.Data.Token)
+		resp, err = http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to log out: %v", err)
+		}
+		resp.Body.Close()
+		enrollLSTestUser(t, ts, login.Data.User.ID, programID)
+
+		select {
+		case frame, ok := <-stream.frames:
+			if ok {
+				t.Errorf("Expected the stream to close after logout, got %+v", frame)
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("Timed out waiting for the stream to close after logout")
+		}
+	})
+
+	t.Run("authorization", func(t *testing.T) {
+		createLSTestUser(t, ts, "stream-other")
+		for _, path := range []string{"/workouts/" + sessionID + "/stream", "/users/" + userID + "/stream"} {
+			resp, _ := authGetLoggedSets(ts.URL(path), "stream-other")
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusForbidden {
+				t.Errorf("GET %s: expected status 403, got %d", path, resp.StatusCode)
+			}
+		}
+
+		stream := openTestStream(t, ts, ts.URL("/users/"+userID+"/stream"), func() http.Header {
+			header := streamHeader(testutil.TestAdminID)
+			header.Set("X-Admin", "true")
+			return header
+		}())
+		if stream.Status != http.StatusOK {
+			t.Errorf("Expected admin to stream any user, got status %d", stream.Status)
+		}
+
+		resp, _ := http.Get(ts.URL("/users/" + userID + "/stream"))
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 without a token, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		resp, _ := authGetLoggedSets(ts.URL("/workouts/missing/stream"), userID)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status 404 for an unknown session, got %d", resp.StatusCode)
+		}
+
+		resp, _ = authGetLoggedSets(ts.URL("/users/"+userID+"/stream?lastEventId=abc"), userID)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for an invalid last event ID, got %d", resp.StatusCode)
+		}
+	})
+}
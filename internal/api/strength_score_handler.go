@@ -0,0 +1,137 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/strength"
+)
+
+// StrengthScoreHandler handles HTTP requests for strength score operations.
+type StrengthScoreHandler struct {
+	strengthService *strength.Service
+}
+
+// NewStrengthScoreHandler creates a new StrengthScoreHandler.
+func NewStrengthScoreHandler(strengthService *strength.Service) *StrengthScoreHandler {
+	return &StrengthScoreHandler{
+		strengthService: strengthService,
+	}
+}
+
+// StrengthScoresResponse represents a user's current strength scores.
+type StrengthScoresResponse struct {
+	Available       bool                    `json:"available"`
+	Missing         []string                `json:"missing"`
+	Unit            string                  `json:"unit"`
+	Lifts           []ScoredLiftMaxResponse `json:"lifts"`
+	Total           *float64                `json:"total"`
+	Bodyweight      *float64                `json:"bodyweight"`
+	DOTS            *float64                `json:"dots"`
+	IPFGL           *float64                `json:"ipfGl"`
+	Wilks           *float64                `json:"wilks"`
+	Age             *int                    `json:"age"`
+	AgeCoefficient  *float64                `json:"ageCoefficient"`
+	AgeAdjustedDOTS *float64                `json:"ageAdjustedDots"`
+}
+
+// ScoredLiftMaxResponse represents a competition lift max contributing to the total.
+type ScoredLiftMaxResponse struct {
+	LiftID        string    `json:"liftId"`
+	LiftName      string    `json:"liftName"`
+	LiftSlug      string    `json:"liftSlug"`
+	Value         float64   `json:"value"`
+	EffectiveDate time.Time `json:"effectiveDate"`
+}
+
+// StrengthScoreHistoryPointResponse represents the scores after a max change.
+type StrengthScoreHistoryPointResponse struct {
+	Date            time.Time `json:"date"`
+	Total           float64   `json:"total"`
+	Bodyweight      float64   `json:"bodyweight"`
+	DOTS            float64   `json:"dots"`
+	IPFGL           float64   `json:"ipfGl"`
+	Wilks           float64   `json:"wilks"`
+	AgeAdjustedDOTS *float64  `json:"ageAdjustedDots"`
+}
+
+// StrengthScoresWithHistoryResponse is the response for GET /users/{userId}/strength-scores.
+type StrengthScoresWithHistoryResponse struct {
+	Current StrengthScoresResponse              `json:"current"`
+	History []StrengthScoreHistoryPointResponse `json:"history"`
+}
+
+func buildStrengthScoresResponse(s *strength.Scores) StrengthScoresResponse {
+	lifts := make([]ScoredLiftMaxResponse, len(s.Lifts))
+	for i, l := range s.Lifts {
+		lifts[i] = ScoredLiftMaxResponse{
+			LiftID:        l.LiftID,
+			LiftName:      l.LiftName,
+			LiftSlug:      l.LiftSlug,
+			Value:         l.Value,
+			EffectiveDate: l.EffectiveDate,
+		}
+	}
+
+	return StrengthScoresResponse{
+		Available:       s.Available,
+		Missing:         s.Missing,
+		Unit:            s.Unit,
+		Lifts:           lifts,
+		Total:           s.Total,
+		Bodyweight:      s.Bodyweight,
+		DOTS:            s.DOTS,
+		IPFGL:           s.IPFGL,
+		Wilks:           s.Wilks,
+		Age:             s.Age,
+		AgeCoefficient:  s.AgeCoefficient,
+		AgeAdjustedDOTS: s.AgeAdjustedDOTS,
+	}
+}
+
+// Get handles GET /users/{userId}/strength-scores
+func (h *StrengthScoreHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if userID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing user ID"))
+		return
+	}
+
+	// Authorization check: the user, their coach or an admin can view scores
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
+		writeDomainError(w, apperrors.NewForbidden("you can only access your own strength scores"))
+		return
+	}
+
+	current, err := h.strengthService.GetScores(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	history, err := h.strengthService.GetHistory(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	response := StrengthScoresWithHistoryResponse{
+		Current: buildStrengthScoresResponse(current),
+		History: make([]StrengthScoreHistoryPointResponse, len(history)),
+	}
+	for i, p := range history {
+		response.History[i] = StrengthScoreHistoryPointResponse{
+			Date:            p.Date,
+			Total:           p.Total,
+			Bodyweight:      p.Bodyweight,
+			DOTS:            p.DOTS,
+			IPFGL:           p.IPFGL,
+			Wilks:           p.Wilks,
+			AgeAdjustedDOTS: p.AgeAdjustedDOTS,
+		}
+	}
+
+	writeData(w, http.StatusOK, response)
+}
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/domain/event"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/service"
+)
+
+// SyncHandler handles HTTP requests from offline-first clients.
+type SyncHandler struct {
+	syncService *service.SyncService
+}
+
+// NewSyncHandler creates a new SyncHandler.
+func NewSyncHandler(syncService *service.SyncService) *SyncHandler {
+	return &SyncHandler{
+		syncService: syncService,
+	}
+}
+
+// SyncSetPayload represents a set in a LOG_SETS mutation.
+type SyncSetPayload struct {
+	ID             string   `json:"id"`
+	PrescriptionID string   `json:"prescriptionId"`
+	LiftID         string   `json:"liftId"`
+	SetNumber      int      `json:"setNumber"`
+	Weight         float64  `json:"weight"`
+	TargetReps     int      `json:"targetReps"`
+	RepsPerformed  int      `json:"repsPerformed"`
+	IsAMRAP        bool     `json:"isAmrap"`
+	RPE            *float64 `json:"rpe,omitempty"`
+	// LoggedAt is when the set was performed. Defaults to the mutation's clientTimestamp.
+	LoggedAt *time.Time `json:"loggedAt,omitempty"`
+}
+
+// SyncMutationPayload holds the fields of every mutation type; each type reads its own.
+type SyncMutationPayload struct {
+	// SessionID is used by START_SESSION, LOG_SETS and FINISH_SESSION.
+	SessionID string `json:"sessionId,omitempty"`
+	// Sets is used by LOG_SETS.
+	Sets []SyncSetPayload `json:"sets,omitempty"`
+	// ID, LiftID, Value and EffectiveDate are used by SET_MAX.
+	ID            string     `json:"id,omitempty"`
+	LiftID        string     `json:"liftId,omitempty"`
+	Value         float64    `json:"value,omitempty"`
+	EffectiveDate *time.Time `json:"effectiveDate,omitempty"`
+}
+
+// SyncMutationRequest represents a single client mutation.
+type SyncMutationRequest struct {
+	ID              string              `json:"id"`
+	Type            string              `json:"type"`
+	ClientTimestamp time.Time           `json:"clientTimestamp"`
+	Payload         SyncMutationPayload `json:"payload"`
+}
+
+// SyncRequest represents the request body for a sync.
+type SyncRequest struct {
+	// Cursor is the cursor returned by the previous sync. Omit it to receive the full state.
+	Cursor    *string               `json:"cursor,omitempty"`
+	Mutations []SyncMutationRequest `json:"mutations"`
+}
+
+// SyncMutationResultResponse represents the outcome of a mutation.
+type SyncMutationResultResponse struct {
+	MutationID string `json:"mutationId"`
+	Type       string `json:"type"`
+	Status     string `json:"status"`
+	Code       string `json:"code,omitempty"`
+	Message    string `json:"message,omitempty"`
+	EntityID   string `json:"entityId,omitempty"`
+	Replayed   bool   `json:"replayed"`
+}
+
+// DeletedLoggedSetResponse identifies a deleted logged set in the change feed.
+type DeletedLoggedSetResponse struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"sessionId"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// SyncChangesResponse represents the server changes since the request cursor.
+type SyncChangesResponse struct {
+	Sessions    []WorkoutSessionResponse   `json:"sessions"`
+	Sets        []LoggedSetResponse        `json:"sets"`
+	DeletedSets []DeletedLoggedSetResponse `json:"deletedSets"`
+	LiftMaxes   []LiftMaxResponse          `json:"liftMaxes"`
+}
+
+// SyncResponse represents the API response format for a sync.
+type SyncResponse struct {
+	Results []SyncMutationResultResponse `json:"results"`
+	Changes SyncChangesResponse          `json:"changes"`
+	Cursor  string                       `json:"cursor"`
+}
+
+func (req SyncMutationRequest) toMutation() service.SyncMutation {
+	sets := make([]service.SyncSet, len(req.Payload.Sets))
+	for i, s := range req.Payload.Sets {
+		sets[i] = service.SyncSet{
+			ID:             s.ID,
+			PrescriptionID: s.PrescriptionID,
+			LiftID:         s.LiftID,
+			SetNumber:      s.SetNumber,
+			Weight:         s.Weight,
+			TargetReps:     s.TargetReps,
+			RepsPerformed:  s.RepsPerformed,
+			IsAMRAP:        s.IsAMRAP,
+			RPE:            s.RPE,
+			LoggedAt:       s.LoggedAt,
+		}
+	}
+	return service.SyncMutation{
+		ID:              req.ID,
+		Type:            service.SyncMutationType(req.Type),
+		ClientTimestamp: req.ClientTimestamp,
+		SessionID:       req.Payload.SessionID,
+		Sets:            sets,
+		MaxID:           req.Payload.ID,
+		LiftID:          req.Payload.LiftID,
+		Value:           req.Payload.Value,
+		EffectiveDate:   req.Payload.EffectiveDate,
+	}
+}
+
+func syncResultToResponse(result *service.SyncResult) SyncResponse {
+	results := make([]SyncMutationResultResponse, len(result.Results))
+	for i, res := range result.Results {
+		results[i] = SyncMutationResultResponse{
+			MutationID: res.MutationID,
+			Type:       string(res.Type),
+			Status:     string(res.Status),
+			Code:       res.Code,
+			Message:    res.Message,
+			EntityID:   res.EntityID,
+			Replayed:   res.Replayed,
+		}
+	}
+
+	changes := SyncChangesResponse{
+		Sessions:    make([]WorkoutSessionResponse, len(result.Changes.Sessions)),
+		Sets:        make([]LoggedSetResponse, len(result.Changes.Sets)),
+		DeletedSets: make([]DeletedLoggedSetResponse, len(result.Changes.DeletedSets)),
+		LiftMaxes:   make([]LiftMaxResponse, len(result.Changes.LiftMaxes)),
+	}
+	for i, ws := range result.Changes.Sessions {
+		changes.Sessions[i] = workoutSessionToResponse(ws)
+	}
+	for i := range result.Changes.Sets {
+		changes.Sets[i] = loggedSetToResponse(&result.Changes.Sets[i])
+	}
+	for i, d := range result.Changes.DeletedSets {
+		changes.DeletedSets[i] = DeletedLoggedSetResponse{ID: d.ID, SessionID: d.SessionID, DeletedAt: d.DeletedAt}
+	}
+	for i := range result.Changes.LiftMaxes {
+		changes.LiftMaxes[i] = liftMaxToResponse(&result.Changes.LiftMaxes[i])
+	}
+
+	return SyncResponse{
+		Results: results,
+		Changes: changes,
+		Cursor:  result.Cursor.Format(time.RFC3339),
+	}
+}
+
+// Sync handles POST /users/{userId}/sync
+// Applies an ordered batch of mutations recorded offline and returns the server changes
+// since the request cursor. Conflicts and rejections are reported per mutation.
+func (h *SyncHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if userID == "" {
+		writeDomainError(w, apperrors.NewBadRequest("missing user ID"))
+		return
+	}
+
+	// Authorization check: only the user themselves or an admin can sync
+	authUserID := middleware.GetUserID(r)
+	if authUserID != userID && !middleware.IsAdmin(r) {
+		writeDomainError(w, apperrors.NewForbidden("you can only sync your own data"))
+		return
+	}
+
+	var req SyncRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	var cursor *time.Time
+	if req.Cursor != nil && *req.Cursor != "" {
+		parsed, err := time.Parse(time.RFC3339, *req.Cursor)
+		if err != nil {
+			writeDomainError(w, apperrors.NewValidation("cursor", "must be a cursor returned by a previous sync"))
+			return
+		}
+		cursor = &parsed
+	}
+
+	mutations := make([]service.SyncMutation, len(req.Mutations))
+	for i, m := range req.Mutations {
+		mutations[i] = m.toMutation()
+	}
+
+	result, err := h.syncService.Sync(r.Context(), userID, cursor, mutations, syncMutationEvents)
+	if err != nil {
+		if errors.Is(err, service.ErrTooManySyncMutations) {
+			writeDomainError(w, apperrors.NewValidation("mutations", err.Error()))
+			return
+		}
+		writeDomainError(w, apperrors.NewInternal("failed to sync", err))
+		return
+	}
+
+	writeData(w, http.StatusOK, syncResultToResponse(result))
+}
+
+// syncMutationEvents builds the events the equivalent online request emits for a
+// mutation applied by a sync.
+func syncMutationEvents(userID string, res *service.SyncMutationResult) []event.StateEvent {
+	events := []event.StateEvent{}
+	switch res.Type {
+	case service.SyncStartSession, service.SyncFinishSession:
+		eventType := event.EventWorkoutStarted
+		if res.Type == service.SyncFinishSession {
+			eventType = event.EventWorkoutCompleted
+		}
+		events = append(events, sessionEvent(eventType, userID, res.ProgramID, res.Session))
+	case service.SyncLogSets:
+		for _, s := range res.Sets {
+			events = append(events, setLoggedEvents(userID, res.ProgramID, s.Set, s.Records)...)
+		}
+	}
+	return events
+}
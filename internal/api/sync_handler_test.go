@@ -0,0 +1,185 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// SyncTestResponse wraps the response of a sync.
+type SyncTestResponse struct {
+	Data struct {
+		Results []struct {
+			MutationID string `json:"mutationId"`
+			Type       string `json:"type"`
+			Status     string `json:"status"`
+			Code       string `json:"code"`
+			EntityID   string `json:"entityId"`
+			Replayed   bool   `json:"replayed"`
+		} `json:"results"`
+		Changes struct {
+			Sessions []struct {
+				ID         string     `json:"id"`
+				Status     string     `json:"status"`
+				StartedAt  time.Time  `json:"startedAt"`
+				FinishedAt *time.Time `json:"finishedAt"`
+			} `json:"sessions"`
+			Sets      []LoggedSetTestResponse `json:"sets"`
+			LiftMaxes []struct {
+				ID    string  `json:"id"`
+				Type  string  `json:"type"`
+				Value float64 `json:"value"`
+			} `json:"liftMaxes"`
+		} `json:"changes"`
+		Cursor string `json:"cursor"`
+	} `json:"data"`
+}
+
+func postSync(t *testing.T, ts *testutil.TestServer, userID, authUserID string, body interface{}) (int, SyncTestResponse) {
+	t.Helper()
+	payload, _ := json.Marshal(body)
+	resp, err := authLoggedSetRequest(http.MethodPost, ts.URL("/users/"+userID+"/sync"), string(payload), authUserID)
+	if err != nil {
+		t.Fatalf("Failed to sync: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result SyncTestResponse
+	if resp.StatusCode == http.StatusOK {
+		json.NewDecoder(resp.Body).Decode(&result)
+	} else {
+		io.Copy(io.Discard, resp.Body)
+	}
+	return resp.StatusCode, result
+}
+
+func TestSyncHandler_Sync(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	userID := "sync-user"
+	createLSTestUser(t, ts, userID)
+	liftID := createLSTestLift(t, ts, "Squat", "squat-sync")
+	cycleID := createLSTestCycle(t, ts, "Sync Cycle")
+	programID := createLSTestProgram(t, ts, "Sync Program", "sync-program", cycleID)
+	enrollLSTestUser(t, ts, userID, programID)
+
+	started := time.Now().Add(-90 * time.Minute).UTC().Truncate(time.Second)
+	sessionID := uuid.New().String()
+	setID := uuid.New().String()
+	maxID := uuid.New().String()
+	body := map[string]interface{}{
+		"mutations": []map[string]interface{}{
+			{"id": uuid.New().String(), "type": "START_SESSION", "clientTimestamp": started, "payload": map[string]interface{}{"sessionId": sessionID}},
+			{"id": uuid.New().String(), "type": "LOG_SETS", "clientTimestamp": started.Add(5 * time.Minute), "payload": map[string]interface{}{
+				"sessionId": sessionID,
+				"sets": []map[string]interface{}{{
+					"id": setID, "prescriptionId": uuid.New().String(), "liftId": liftID,
+					"setNumber": 1, "weight": 225.0, "targetReps": 5, "repsPerformed": 5,
+				}},
+			}},
+			{"id": uuid.New().String(), "type": "FINISH_SESSION", "clientTimestamp": started.Add(time.Hour), "payload": map[string]interface{}{"sessionId": sessionID}},
+			{"id": uuid.New().String(), "type": "SET_MAX", "clientTimestamp": started, "payload": map[string]interface{}{"id": maxID, "liftId": liftID, "value": 315.0}},
+		},
+	}
+
+	t.Run("applies offline mutations in order", func(t *testing.T) {
+		status, result := postSync(t, ts, userID, userID, body)
+		if status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		for _, res := range result.Data.Results {
+			if res.Status != "APPLIED" {
+				t.Errorf("Expected %s to be applied, got %s (%s)", res.Type, res.Status, res.Code)
+			}
+		}
+		if result.Data.Cursor == "" {
+			t.Error("Expected a cursor")
+		}
+
+		if len(result.Data.Changes.Sessions) != 1 {
+			t.Fatalf("Expected 1 session in changes, got %d", len(result.Data.Changes.Sessions))
+		}
+		session := result.Data.Changes.Sessions[0]
+		if session.ID != sessionID || session.Status != "COMPLETED" || !session.StartedAt.Equal(started) {
+			t.Errorf("Unexpected synced session: %+v", session)
+		}
+		if len(result.Data.Changes.Sets) != 1 || result.Data.Changes.Sets[0].ID != setID {
+			t.Errorf("Expected set %s in changes, got %+v", setID, result.Data.Changes.Sets)
+		}
+		if len(result.Data.Changes.LiftMaxes) != 2 {
+			t.Errorf("Expected the 1RM and derived TM in changes, got %+v", result.Data.Changes.LiftMaxes)
+		}
+	})
+
+	t.Run("replaying the batch is idempotent", func(t *testing.T) {
+		status, result := postSync(t, ts, userID, userID, body)
+		if status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		for _, res := range result.Data.Results {
+			if res.Status != "APPLIED" || !res.Replayed {
+				t.Errorf("Expected %s to replay as applied, got %s replayed=%v", res.Type, res.Status, res.Replayed)
+			}
+		}
+
+		listResp, _ := authGetLoggedSets(ts.URL("/sessions/"+sessionID+"/sets"), userID)
+		defer listResp.Body.Close()
+		var list LoggedSetTestListResponse
+		json.NewDecoder(listResp.Body).Decode(&list)
+		if len(list.Data) != 1 {
+			t.Errorf("Expected 1 logged set after replay, got %d", len(list.Data))
+		}
+	})
+
+	t.Run("finish from a second device conflicts", func(t *testing.T) {
+		status, result := postSync(t, ts, userID, userID, map[string]interface{}{
+			"mutations": []map[string]interface{}{
+				{"id": uuid.New().String(), "type": "FINISH_SESSION", "clientTimestamp": time.Now().UTC(), "payload": map[string]interface{}{"sessionId": sessionID}},
+			},
+		})
+		if status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		res := result.Data.Results[0]
+		if res.Status != "CONFLICT" || res.Code != "SESSION_ALREADY_FINISHED" || res.EntityID != sessionID {
+			t.Errorf("Unexpected result: %+v", res)
+		}
+	})
+
+	t.Run("cursor limits the change feed", func(t *testing.T) {
+		cursor := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		status, result := postSync(t, ts, userID, userID, map[string]interface{}{"cursor": cursor, "mutations": []interface{}{}})
+		if status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		changes := result.Data.Changes
+		if len(changes.Sessions) != 0 || len(changes.Sets) != 0 || len(changes.LiftMaxes) != 0 {
+			t.Errorf("Expected no changes after a future cursor, got %+v", changes)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		status, _ := postSync(t, ts, userID, userID, map[string]interface{}{"cursor": "yesterday"})
+		if status != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", status)
+		}
+	})
+
+	t.Run("other users cannot sync", func(t *testing.T) {
+		otherID := "sync-other-user"
+		createLSTestUser(t, ts, otherID)
+		status, _ := postSync(t, ts, userID, otherID, map[string]interface{}{"mutations": []interface{}{}})
+		if status != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", status)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/auth"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+)
+
+// TwoFactorHandler handles HTTP requests for two-factor authentication.
+type TwoFactorHandler struct {
+	service *auth.TwoFactorService
+}
+
+// NewTwoFactorHandler creates a new TwoFactorHandler.
+func NewTwoFactorHandler(service *auth.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{service: service}
+}
+
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *TwoFactorHandler) WithTx(tx *sql.Tx) *TwoFactorHandler {
+	txHandler := *h
+	txHandler.service = h.service.WithTx(tx)
+	return &txHandler
+}
+
+// TwoFactorCodeRequest represents a request body holding a two-factor code.
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// VerifyTwoFactorRequest represents the request body for completing a login challenge.
+type VerifyTwoFactorRequest struct {
+	Challenge string `json:"challenge"`
+	// Code is a code from the user's authenticator app or one of their recovery codes.
+	Code string `json:"code"`
+}
+
+// TwoFactorStatusResponse represents the API response format for two-factor status.
+type TwoFactorStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	Pending                bool       `json:"pending"`
+	Required               bool       `json:"required"`
+	ConfirmedAt            *time.Time `json:"confirmedAt"`
+	RecoveryCodesRemaining int        `json:"recoveryCodesRemaining"`
+}
+
+// TOTPEnrollmentResponse represents the API response for starting a TOTP enrollment.
+type TOTPEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// RecoveryCodesResponse represents the API response holding new recovery codes.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// Verify handles POST /auth/2fa/verify
+// Completes a login challenge and returns a session like /auth/login.
+func (h *TwoFactorHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	var req VerifyTwoFactorRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	result, err := h.service.CompleteLogin(clientContext(r), req.Challenge, req.Code)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeLoginResult(w, result)
+}
+
+// Status handles GET /users/{userId}/2fa
+func (h *TwoFactorHandler) Status(w http.ResponseWriter, r *http.Request) {
+	status, err := h.service.Status(r.Context(), r.PathValue("userId"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, TwoFactorStatusResponse{
+		Enabled:                status.Enabled,
+		Pending:                status.Pending,
+		Required:               status.Required,
+		ConfirmedAt:            status.ConfirmedAt,
+		RecoveryCodesRemaining: status.RecoveryCodesRemaining,
+	})
+}
+
+// Enroll handles POST /users/{userId}/2fa/totp
+// Only the user can enroll; the enrollment takes effect once confirmed with a code.
+func (h *TwoFactorHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if middleware.GetUserID(r) != userID {
+		writeDomainError(w, apperrors.NewForbidden("you can only set up your own two-factor authentication"))
+		return
+	}
+
+	enrollment, err := h.service.BeginEnrollment(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeData(w, http.StatusCreated, TOTPEnrollmentResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	})
+}
+
+// Confirm handles POST /users/{userId}/2fa/totp/confirm
+// Enables two-factor authentication and returns the recovery codes, shown only once.
+func (h *TwoFactorHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if middleware.GetUserID(r) != userID {
+		writeDomainError(w, apperrors.NewForbidden("you can only set up your own two-factor authentication"))
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	codes, err := h.service.ConfirmEnrollment(r.Context(), userID, req.Code)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// RegenerateRecoveryCodes handles POST /users/{userId}/2fa/recovery-codes
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if middleware.GetUserID(r) != userID {
+		writeDomainError(w, apperrors.NewForbidden("you can only manage your own recovery codes"))
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(r.Context(), userID, req.Code)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable handles DELETE /users/{userId}/2fa/totp
+// Users confirm with a code; admins can turn off another user's two-factor authentication
+// without one, for users who lost their authenticator and recovery codes.
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if middleware.GetUserID(r) != userID {
+		// withOwner only lets admins through for other users
+		if err := h.service.Reset(r.Context(), userID); err != nil {
+			writeDomainError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+	if err := h.service.Disable(r.Context(), userID, req.Code); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
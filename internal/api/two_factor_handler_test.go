@@ -0,0 +1,119 @@
+package api_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+// totpAt computes the authenticator code for a base32 secret, offset by whole periods
+// from now so tests can use a code other than the one already accepted.
+func totpAt(t *testing.T, secret string, periods int64) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatalf("Invalid secret %q: %v", secret, err)
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(time.Now().Unix()/30+periods))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff)%1000000)
+}
+
+func TestTwoFactorHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	var user UserTestResponse
+	coachingRequest(t, http.MethodPost, ts.URL("/auth/register"), map[string]string{
+		"email": "2fa@example.com", "password": "password123",
+	}, "", http.StatusCreated, &user)
+	credentials := map[string]string{"email": "2fa@example.com", "password": "password123"}
+	totpURL := ts.URL("/users/" + user.ID + "/2fa/totp")
+
+	var enrollment struct {
+		Secret          string `json:"secret"`
+		ProvisioningURI string `json:"provisioningUri"`
+	}
+	var recovery struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}
+
+	t.Run("enrollment", func(t *testing.T) {
+		coachingRequest(t, http.MethodPost, totpURL, nil, "someone-else", http.StatusForbidden, nil)
+		coachingRequest(t, http.MethodPost, totpURL, nil, user.ID, http.StatusCreated, &enrollment)
+		if enrollment.Secret == "" || enrollment.ProvisioningURI == "" {
+			t.Fatalf("Unexpected enrollment: %+v", enrollment)
+		}
+
+		coachingRequest(t, http.MethodPost, totpURL+"/confirm", map[string]string{"code": "000000"}, user.ID, http.StatusBadRequest, nil)
+		coachingRequest(t, http.MethodPost, totpURL+"/confirm", map[string]string{"code": totpAt(t, enrollment.Secret, 0)}, user.ID, http.StatusOK, &recovery)
+		if len(recovery.RecoveryCodes) != 10 {
+			t.Fatalf("Expected 10 recovery codes, got %v", recovery.RecoveryCodes)
+		}
+
+		var status struct {
+			Enabled                bool `json:"enabled"`
+			RecoveryCodesRemaining int  `json:"recoveryCodesRemaining"`
+		}
+		coachingRequest(t, http.MethodGet, ts.URL("/users/"+user.ID+"/2fa"), nil, user.ID, http.StatusOK, &status)
+		if !status.Enabled || status.RecoveryCodesRemaining != 10 {
+			t.Errorf("Unexpected status: %+v", status)
+		}
+	})
+
+	t.Run("two-step login", func(t *testing.T) {
+		var challenge struct {
+			TwoFactorRequired bool   `json:"twoFactorRequired"`
+			Challenge         string `json:"challenge"`
+		}
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/login"), credentials, "", http.StatusOK, &challenge)
+		if !challenge.TwoFactorRequired || challenge.Challenge == "" {
+			t.Fatalf("Expected a challenge, got %+v", challenge)
+		}
+
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/2fa/verify"), map[string]string{
+			"challenge": challenge.Challenge, "code": "000000",
+		}, "", http.StatusUnauthorized, nil)
+
+		var login LoginTestResponse
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/2fa/verify"), map[string]string{
+			"challenge": challenge.Challenge, "code": totpAt(t, enrollment.Secret, 1),
+		}, "", http.StatusOK, &login)
+		if login.Token == "" || login.User.ID != user.ID {
+			t.Fatalf("Unexpected login: %+v", login)
+		}
+		if status := tokenRequest(t, http.MethodGet, ts.URL("/auth/me"), nil, login.Token); status != http.StatusOK {
+			t.Errorf("Expected the session to authenticate, got %d", status)
+		}
+
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/login"), credentials, "", http.StatusOK, &challenge)
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/2fa/verify"), map[string]string{
+			"challenge": challenge.Challenge, "code": recovery.RecoveryCodes[0],
+		}, "", http.StatusOK, &login)
+	})
+
+	t.Run("disabling", func(t *testing.T) {
+		coachingRequest(t, http.MethodDelete, totpURL, map[string]string{"code": "wrong"}, user.ID, http.StatusBadRequest, nil)
+		coachingRequest(t, http.MethodDelete, totpURL, map[string]string{"code": recovery.RecoveryCodes[1]}, user.ID, http.StatusNoContent, nil)
+
+		var login LoginTestResponse
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/login"), credentials, "", http.StatusOK, &login)
+		if login.Token == "" {
+			t.Errorf("Expected a session once two-factor authentication is off, got %+v", login)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/auth"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/userdata"
+)
+
+// UserDataHandler handles HTTP requests for data export and account deletion.
+type UserDataHandler struct {
+	service     *userdata.Service
+	authService *auth.Service
+}
+
+// NewUserDataHandler creates a new UserDataHandler.
+func NewUserDataHandler(service *userdata.Service, authService *auth.Service) *UserDataHandler {
+	return &UserDataHandler{service: service, authService: authService}
+}
+
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *UserDataHandler) WithTx(tx *sql.Tx) *UserDataHandler {
+	txHandler := *h
+	txHandler.service = h.service.WithTx(tx)
+	return &txHandler
+}
+
+// ScheduleDeletionRequest represents the request body for deleting an account.
+type ScheduleDeletionRequest struct {
+	// Password confirms the deletion for users who have one. Admins deleting another
+	// user's account leave it empty.
+	Password string `json:"password"`
+}
+
+// AccountDeletionResponse represents the API response for an account's deletion status.
+type AccountDeletionResponse struct {
+	Scheduled    bool       `json:"scheduled"`
+	RequestedAt  *time.Time `json:"requestedAt"`
+	ScheduledFor *time.Time `json:"scheduledFor"`
+}
+
+// Export handles POST /users/{userId}/export
+// Responds with a zip archive of the user's data.
+func (h *UserDataHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	export, err := h.service.Export(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	// Build the archive before responding so that failures still get an error response
+	var buf bytes.Buffer
+	if err := userdata.WriteArchive(&buf, export); err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to write export archive", err))
+		return
+	}
+	filename := fmt.Sprintf("powerpro-export-%s.zip", export.ExportedAt.Format("20060102"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// GetDeletion handles GET /users/{userId}/deletion
+func (h *UserDataHandler) GetDeletion(w http.ResponseWriter, r *http.Request) {
+	deletion, err := h.service.GetDeletion(r.Context(), r.PathValue("userId"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, deletionToResponse(deletion))
+}
+
+// ScheduleDeletion handles POST /users/{userId}/deletion
+// Schedules the account for deletion after the grace period. Users confirm with their
+// password; admins can schedule the deletion of any account.
+func (h *UserDataHandler) ScheduleDeletion(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	var req ScheduleDeletionRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+	if middleware.GetUserID(r) == userID {
+		if err := h.authService.ConfirmPassword(r.Context(), userID, req.Password); err != nil {
+			writeDomainError(w, err)
+			return
+		}
+	}
+
+	deletion, err := h.service.ScheduleDeletion(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeData(w, http.StatusAccepted, deletionToResponse(deletion))
+}
+
+// CancelDeletion handles DELETE /users/{userId}/deletion
+func (h *UserDataHandler) CancelDeletion(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.CancelDeletion(r.Context(), r.PathValue("userId")); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func deletionToResponse(deletion *userdata.Deletion) AccountDeletionResponse {
+	if deletion == nil {
+		return AccountDeletionResponse{}
+	}
+	return AccountDeletionResponse{
+		Scheduled:    true,
+		RequestedAt:  &deletion.RequestedAt,
+		ScheduledFor: &deletion.ScheduledFor,
+	}
+}
@@ -0,0 +1,129 @@
+package api_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+)
+
+type accountDeletionTestResponse struct {
+	Scheduled    bool       `json:"scheduled"`
+	RequestedAt  *time.Time `json:"requestedAt"`
+	ScheduledFor *time.Time `json:"scheduledFor"`
+}
+
+func TestUserDataHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	var user UserTestResponse
+	coachingRequest(t, http.MethodPost, ts.URL("/auth/register"), map[string]string{
+		"email": "leaving@example.com", "password": "password123",
+	}, "", http.StatusCreated, &user)
+	coachingRequest(t, http.MethodPost, ts.URL("/users/"+user.ID+"/bodyweight"), map[string]interface{}{
+		"weight": 200, "unit": "lb",
+	}, user.ID, http.StatusCreated, nil)
+
+	t.Run("export", func(t *testing.T) {
+		resp, err := webhookRequest(http.MethodPost, ts.URL("/users/"+user.ID+"/export"), nil, user.ID, false)
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, body)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "application/zip" {
+			t.Errorf("Expected a zip archive, got %q", ct)
+		}
+		if cd := resp.Header.Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment; filename=\"powerpro-export-") {
+			t.Errorf("Expected an attachment, got %q", cd)
+		}
+
+		archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			t.Fatalf("Failed to read archive: %v", err)
+		}
+		files := map[string]*zip.File{}
+		for _, f := range archive.File {
+			files[f.Name] = f
+		}
+		for _, name := range []string{"manifest.json", "data.json", "profile.csv", "lift_maxes.csv", "logged_sets.csv", "bodyweight_entries.csv"} {
+			if files[name] == nil {
+				t.Errorf("Expected %s in the archive", name)
+			}
+		}
+
+		rc, err := files["data.json"].Open()
+		if err != nil {
+			t.Fatalf("Failed to open data.json: %v", err)
+		}
+		defer rc.Close()
+		var data struct {
+			Version int `json:"version"`
+			Profile struct {
+				Email string `json:"email"`
+			} `json:"profile"`
+			BodyweightEntries []struct {
+				Weight float64 `json:"weight"`
+			} `json:"bodyweightEntries"`
+		}
+		if err := json.NewDecoder(rc).Decode(&data); err != nil {
+			t.Fatalf("Failed to decode data.json: %v", err)
+		}
+		if data.Version != 1 || data.Profile.Email != "leaving@example.com" || len(data.BodyweightEntries) != 1 {
+			t.Errorf("Unexpected export: %+v", data)
+		}
+
+		coachingRequest(t, http.MethodPost, ts.URL("/users/"+user.ID+"/export"), nil, "someone-else", http.StatusForbidden, nil)
+	})
+
+	t.Run("scheduling and cancelling deletion", func(t *testing.T) {
+		deletionURL := ts.URL("/users/" + user.ID + "/deletion")
+		var status accountDeletionTestResponse
+		coachingRequest(t, http.MethodGet, deletionURL, nil, user.ID, http.StatusOK, &status)
+		if status.Scheduled {
+			t.Fatalf("Expected no deletion scheduled, got %+v", status)
+		}
+
+		coachingRequest(t, http.MethodPost, deletionURL, map[string]string{"password": "wrong"}, user.ID, http.StatusBadRequest, nil)
+		coachingRequest(t, http.MethodPost, deletionURL, map[string]string{"password": "password123"}, user.ID, http.StatusAccepted, &status)
+		if !status.Scheduled || status.ScheduledFor.Sub(*status.RequestedAt) != 30*24*time.Hour {
+			t.Fatalf("Expected deletion in 30 days, got %+v", status)
+		}
+
+		// The account stays usable during the grace period
+		coachingRequest(t, http.MethodPost, ts.URL("/auth/login"), map[string]string{
+			"email": "leaving@example.com", "password": "password123",
+		}, "", http.StatusOK, nil)
+
+		coachingRequest(t, http.MethodDelete, deletionURL, nil, user.ID, http.StatusNoContent, nil)
+		coachingRequest(t, http.MethodDelete, deletionURL, nil, user.ID, http.StatusNotFound, nil)
+		coachingRequest(t, http.MethodGet, deletionURL, nil, user.ID, http.StatusOK, &status)
+		if status.Scheduled {
+			t.Errorf("Expected the deletion to be cancelled, got %+v", status)
+		}
+	})
+
+	t.Run("admins schedule deletion without a password", func(t *testing.T) {
+		resp, err := webhookRequest(http.MethodPost, ts.URL("/users/"+user.ID+"/deletion"), map[string]string{}, "deletion-admin", true)
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			t.Errorf("Expected status 202, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,305 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/webhook"
+)
+
+// WebhookHandler handles HTTP requests for webhook subscriptions and their delivery log.
+type WebhookHandler struct {
+	webhookService *webhook.Service
+}
+
+// NewWebhookHandler creates a new WebhookHandler.
+func NewWebhookHandler(webhookService *webhook.Service) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *WebhookHandler) WithTx(tx *sql.Tx) *WebhookHandler {
+	txHandler := *h
+	txHandler.webhookService = h.webhookService.WithTx(tx)
+	return &txHandler
+}
+
+// CreateWebhookRequest represents the request body for creating a webhook subscription.
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	// Secret is the signing secret. Omit it to have one generated.
+	Secret      string  `json:"secret,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// UpdateWebhookRequest represents the request body for updating a webhook subscription.
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url,omitempty"`
+	EventTypes  []string `json:"eventTypes,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// WebhookResponse represents the API response format for a webhook subscription.
+type WebhookResponse struct {
+	ID          string   `json:"id"`
+	UserID      *string  `json:"userId"`
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"eventTypes"`
+	Description *string  `json:"description"`
+	Active      bool     `json:"active"`
+	// Secret is only returned when the subscription is created.
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// WebhookDeliveryResponse represents the API response format for a webhook delivery.
+type WebhookDeliveryResponse struct {
+	ID             string     `json:"id"`
+	SubscriptionID string     `json:"subscriptionId"`
+	EventID        string     `json:"eventId"`
+	EventType      string     `json:"eventType"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt"`
+	LastStatusCode *int       `json:"lastStatusCode"`
+	LastError      *string    `json:"lastError"`
+	ReplayOf       *string    `json:"replayOf"`
+	Payload        string     `json:"payload"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	DeliveredAt    *time.Time `json:"deliveredAt"`
+}
+
+func webhookToResponse(sub *webhook.Subscription) WebhookResponse {
+	return WebhookResponse{
+		ID:          sub.ID,
+		UserID:      sub.UserID,
+		URL:         sub.URL,
+		EventTypes:  sub.EventTypes,
+		Description: sub.Description,
+		Active:      sub.Active,
+		CreatedAt:   sub.CreatedAt,
+		UpdatedAt:   sub.UpdatedAt,
+	}
+}
+
+func webhookDeliveryToResponse(d *webhook.Delivery) WebhookDeliveryResponse {
+	return WebhookDeliveryResponse{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		ReplayOf:       d.ReplayOf,
+		Payload:        d.Payload,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+}
+
+// authorizeWebhookUser checks that the caller is the user or an admin.
+func authorizeWebhookUser(r *http.Request, userID string) error {
+	if userID == "" {
+		return apperrors.NewBadRequest("missing user ID")
+	}
+	if middleware.GetUserID(r) != userID && !middleware.IsAdmin(r) {
+		return apperrors.NewForbidden("you can only manage your own webhooks")
+	}
+	return nil
+}
+
+// getAuthorizedWebhook loads a subscription and checks the caller may manage it.
+// Admin subscriptions can only be managed by admins.
+func (h *WebhookHandler) getAuthorizedWebhook(r *http.Request) (*webhook.Subscription, error) {
+	sub, err := h.webhookService.GetSubscription(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return nil, err
+	}
+	if middleware.IsAdmin(r) {
+		return sub, nil
+	}
+	if sub.UserID == nil || *sub.UserID != middleware.GetUserID(r) {
+		return nil, apperrors.NewForbidden("you can only manage your own webhooks")
+	}
+	return sub, nil
+}
+
+// CreateForUser handles POST /users/{userId}/webhooks
+func (h *WebhookHandler) CreateForUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if err := authorizeWebhookUser(r, userID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	h.create(w, r, userID)
+}
+
+// ListForUser handles GET /users/{userId}/webhooks
+func (h *WebhookHandler) ListForUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userId")
+	if err := authorizeWebhookUser(r, userID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	h.list(w, r, userID)
+}
+
+// CreateAdmin handles POST /webhooks
+// Creates an admin subscription that receives events for every user.
+func (h *WebhookHandler) CreateAdmin(w http.ResponseWriter, r *http.Request) {
+	h.create(w, r, "")
+}
+
+// ListAdmin handles GET /webhooks
+func (h *WebhookHandler) ListAdmin(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, "")
+}
+
+func (h *WebhookHandler) create(w http.ResponseWriter, r *http.Request, userID string) {
+	var req CreateWebhookRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	sub, err := h.webhookService.CreateSubscription(r.Context(), userID, middleware.GetUserID(r), webhook.CreateSubscriptionRequest{
+		URL:         req.URL,
+		EventTypes:  req.EventTypes,
+		Secret:      req.Secret,
+		Description: req.Description,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	resp := webhookToResponse(sub)
+	resp.Secret = sub.Secret
+	writeData(w, http.StatusCreated, resp)
+}
+
+func (h *WebhookHandler) list(w http.ResponseWriter, r *http.Request, userID string) {
+	pg := ParsePagination(r.URL.Query())
+
+	subs, total, err := h.webhookService.ListSubscriptions(r.Context(), userID, int64(pg.Limit), int64(pg.Offset))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	data := make([]WebhookResponse, len(subs))
+	for i := range subs {
+		data[i] = webhookToResponse(&subs[i])
+	}
+
+	writePaginatedData(w, http.StatusOK, data, total, pg.Limit, pg.Offset)
+}
+
+// Get handles GET /webhooks/{id}
+func (h *WebhookHandler) Get(w http.ResponseWriter, r *http.Request) {
+	sub, err := h.getAuthorizedWebhook(r)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, webhookToResponse(sub))
+}
+
+// Update handles PUT /webhooks/{id}
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	sub, err := h.getAuthorizedWebhook(r)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := readJSON(r, &req); err != nil {
+		writeDomainError(w, apperrors.NewBadRequest("invalid request body"))
+		return
+	}
+
+	updated, err := h.webhookService.UpdateSubscription(r.Context(), sub.ID, webhook.UpdateSubscriptionRequest{
+		URL:         req.URL,
+		EventTypes:  req.EventTypes,
+		Description: req.Description,
+		Active:      req.Active,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusOK, webhookToResponse(updated))
+}
+
+// Delete handles DELETE /webhooks/{id}
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	sub, err := h.getAuthorizedWebhook(r)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	if err := h.webhookService.DeleteSubscription(r.Context(), sub.ID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /webhooks/{id}/deliveries
+// Returns the subscription's delivery log, newest first.
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	sub, err := h.getAuthorizedWebhook(r)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	pg := ParsePagination(r.URL.Query())
+
+	deliveries, total, err := h.webhookService.ListDeliveries(r.Context(), sub.ID, int64(pg.Limit), int64(pg.Offset))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	data := make([]WebhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		data[i] = webhookDeliveryToResponse(&deliveries[i])
+	}
+
+	writePaginatedData(w, http.StatusOK, data, total, pg.Limit, pg.Offset)
+}
+
+// ReplayDelivery handles POST /webhooks/{id}/deliveries/{deliveryId}/replay
+// Sends the delivery's event again as a new delivery and returns it.
+func (h *WebhookHandler) ReplayDelivery(w http.ResponseWriter, r *http.Request) {
+	sub, err := h.getAuthorizedWebhook(r)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	d, err := h.webhookService.Replay(r.Context(), sub.ID, r.PathValue("deliveryId"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeData(w, http.StatusCreated, webhookDeliveryToResponse(d))
+}
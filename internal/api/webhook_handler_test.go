@@ -0,0 +1,306 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/testutil"
+	"github.com/waynenilsen/power-pro-v3/internal/webhook"
+)
+
+// WebhookTestResponse represents a webhook subscription in test responses.
+type WebhookTestResponse struct {
+	ID         string   `json:"id"`
+	UserID     *string  `json:"userId"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	Active     bool     `json:"active"`
+	Secret     string   `json:"secret"`
+}
+
+// WebhookDeliveryTestResponse represents a webhook delivery in test responses.
+type WebhookDeliveryTestResponse struct {
+	ID             string  `json:"id"`
+	EventID        string  `json:"eventId"`
+	EventType      string  `json:"eventType"`
+	Status         string  `json:"status"`
+	Attempts       int     `json:"attempts"`
+	LastStatusCode *int    `json:"lastStatusCode"`
+	ReplayOf       *string `json:"replayOf"`
+}
+
+// webhookReceiver is a local stand-in for a subscriber's HTTP endpoint.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rcv.mu.Lock()
+	rcv.requests = append(rcv.requests, r)
+	rcv.bodies = append(rcv.bodies, body)
+	rcv.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// waitFor waits for the receiver to have received n requests; events are delivered asynchronously.
+func (rcv *webhookReceiver) waitFor(t *testing.T, n int) ([]*http.Request, [][]byte) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rcv.mu.Lock()
+		got := len(rcv.requests)
+		requests := append([]*http.Request(nil), rcv.requests...)
+		bodies := append([][]byte(nil), rcv.bodies...)
+		rcv.mu.Unlock()
+		if got >= n {
+			return requests, bodies
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d webhook requests, got %d", n, got)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func webhookRequest(method, url string, body interface{}, userID string, isAdmin bool) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", userID)
+	if isAdmin {
+		req.Header.Set("X-Admin", "true")
+	}
+	return http.DefaultClient.Do(req)
+}
+
+func createTestWebhook(t *testing.T, url, userID string, isAdmin bool, body map[string]interface{}) WebhookTestResponse {
+	t.Helper()
+	resp, err := webhookRequest(http.MethodPost, url, body, userID, isAdmin)
+	if err != nil {
+		t.Fatalf("Failed to create webhook: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Expected status 201, got %d: %s", resp.StatusCode, bodyBytes)
+	}
+	var envelope struct {
+		Data WebhookTestResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&envelope)
+	return envelope.Data
+}
+
+func listTestWebhookDeliveries(t *testing.T, ts *testutil.TestServer, webhookID, userID string) []WebhookDeliveryTestResponse {
+	t.Helper()
+	resp, err := webhookRequest(http.MethodGet, ts.URL("/webhooks/"+webhookID+"/deliveries"), nil, userID, false)
+	if err != nil {
+		t.Fatalf("Failed to list deliveries: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	var envelope struct {
+		Data []WebhookDeliveryTestResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&envelope)
+	return envelope.Data
+}
+
+func TestWebhookHandler(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	receiver := &webhookReceiver{}
+	ts.HandleHost("hooks.test", receiver)
+
+	userID := "webhook-user"
+	otherID := "webhook-other-user"
+	createLSTestUser(t, ts, userID)
+	createLSTestUser(t, ts, otherID)
+	liftID := createLSTestLift(t, ts, "Squat", "squat-webhook")
+	cycleID := createLSTestCycle(t, ts, "Webhook Cycle")
+	programID := createLSTestProgram(t, ts, "Webhook Program", "webhook-program", cycleID)
+	enrollLSTestUser(t, ts, userID, programID)
+
+	secret := "receiver-shared-secret"
+	sub := createTestWebhook(t, ts.URL("/users/"+userID+"/webhooks"), userID, false, map[string]interface{}{
+		"url":        "http://hooks.test/powerpro",
+		"eventTypes": []string{"SET_LOGGED"},
+		"secret":     secret,
+	})
+
+	t.Run("create returns the secret once", func(t *testing.T) {
+		if sub.Secret != secret || sub.UserID == nil || *sub.UserID != userID || !sub.Active {
+			t.Errorf("Unexpected webhook: %+v", sub)
+		}
+
+		resp, _ := webhookRequest(http.MethodGet, ts.URL("/webhooks/"+sub.ID), nil, userID, false)
+		defer resp.Body.Close()
+		var envelope struct {
+			Data WebhookTestResponse `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&envelope)
+		if resp.StatusCode != http.StatusOK || envelope.Data.Secret != "" {
+			t.Errorf("Expected 200 without secret, got %d %+v", resp.StatusCode, envelope.Data)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		resp, _ := webhookRequest(http.MethodPost, ts.URL("/users/"+userID+"/webhooks"), map[string]interface{}{
+			"url": "http://hooks.test/powerpro", "eventTypes": []string{"NOT_AN_EVENT"},
+		}, userID, false)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("delivers signed events", func(t *testing.T) {
+		sessionID := startLSWorkoutSession(t, ts, userID)
+		setIDs := logLSTestSets(t, ts, sessionID, userID, liftID, 5)
+
+		requests, bodies := receiver.waitFor(t, 1)
+		req, body := requests[0], bodies[0]
+		if req.Header.Get(webhook.HeaderEvent) != "SET_LOGGED" {
+			t.Errorf("Expected SET_LOGGED, got %s", req.Header.Get(webhook.HeaderEvent))
+		}
+
+		sig := req.Header.Get(webhook.HeaderSignature)
+		sentAt, err := strconv.ParseInt(strings.TrimPrefix(strings.Split(sig, ",")[0], "t="), 10, 64)
+		if err != nil || webhook.Sign(secret, sentAt, body) != sig {
+			t.Errorf("Signature %q does not verify", sig)
+		}
+
+		var payload webhook.EventPayload
+		json.Unmarshal(body, &payload)
+		if payload.UserID != userID || payload.Data["loggedSetId"] != setIDs[0] {
+			t.Errorf("Unexpected payload: %+v", payload)
+		}
+	})
+
+	t.Run("delivery log and replay", func(t *testing.T) {
+		// The outcome is recorded just after the receiver responds
+		deadline := time.Now().Add(5 * time.Second)
+		deliveries := listTestWebhookDeliveries(t, ts, sub.ID, userID)
+		for len(deliveries) == 1 && deliveries[0].Status == "PENDING" && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+			deliveries = listTestWebhookDeliveries(t, ts, sub.ID, userID)
+		}
+		if len(deliveries) != 1 {
+			t.Fatalf("Expected 1 delivery, got %d", len(deliveries))
+		}
+		d := deliveries[0]
+		if d.Status != "SUCCEEDED" || d.Attempts != 1 || d.LastStatusCode == nil || *d.LastStatusCode != http.StatusNoContent {
+			t.Errorf("Unexpected delivery: %+v", d)
+		}
+
+		resp, _ := webhookRequest(http.MethodPost, ts.URL("/webhooks/"+sub.ID+"/deliveries/"+d.ID+"/replay"), nil, userID, false)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+		}
+		var envelope struct {
+			Data WebhookDeliveryTestResponse `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&envelope)
+		replay := envelope.Data
+		if replay.ReplayOf == nil || *replay.ReplayOf != d.ID || replay.EventID != d.EventID || replay.Status != "SUCCEEDED" {
+			t.Errorf("Unexpected replay: %+v", replay)
+		}
+
+		_, bodies := receiver.waitFor(t, 2)
+		if !bytes.Equal(bodies[0], bodies[1]) {
+			t.Error("Expected the replay to resend the original body")
+		}
+		if len(listTestWebhookDeliveries(t, ts, sub.ID, userID)) != 2 {
+			t.Error("Expected the replay in the delivery log")
+		}
+	})
+
+	t.Run("other users cannot access the webhook", func(t *testing.T) {
+		for _, path := range []string{"/webhooks/" + sub.ID, "/webhooks/" + sub.ID + "/deliveries", "/users/" + userID + "/webhooks"} {
+			resp, _ := webhookRequest(http.MethodGet, ts.URL(path), nil, otherID, false)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusForbidden {
+				t.Errorf("GET %s: expected status 403, got %d", path, resp.StatusCode)
+			}
+		}
+	})
+
+	t.Run("admin webhooks", func(t *testing.T) {
+		resp, _ := webhookRequest(http.MethodPost, ts.URL("/webhooks"), map[string]interface{}{
+			"url": "http://hooks.test/admin", "eventTypes": []string{"WORKOUT_STARTED"},
+		}, userID, false)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected non-admin create to be forbidden, got %d", resp.StatusCode)
+		}
+
+		admin := createTestWebhook(t, ts.URL("/webhooks"), testutil.TestAdminID, true, map[string]interface{}{
+			"url": "http://hooks.test/admin", "eventTypes": []string{"WORKOUT_STARTED"},
+		})
+		if admin.UserID != nil {
+			t.Errorf("Expected an admin webhook without a user, got %v", *admin.UserID)
+		}
+
+		// An admin webhook receives events for every user
+		enrollLSTestUser(t, ts, otherID, programID)
+		startLSWorkoutSession(t, ts, otherID)
+		requests, _ := receiver.waitFor(t, 3)
+		if requests[2].URL.Path != "/admin" || requests[2].Header.Get(webhook.HeaderEvent) != "WORKOUT_STARTED" {
+			t.Errorf("Unexpected request %s %s", requests[2].URL.Path, requests[2].Header.Get(webhook.HeaderEvent))
+		}
+
+		// Admin webhooks are admin-only
+		resp, _ = webhookRequest(http.MethodGet, ts.URL("/webhooks/"+admin.ID), nil, otherID, false)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		resp, _ := webhookRequest(http.MethodPut, ts.URL("/webhooks/"+sub.ID), map[string]interface{}{"active": false}, userID, false)
+		defer resp.Body.Close()
+		var envelope struct {
+			Data WebhookTestResponse `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&envelope)
+		if resp.StatusCode != http.StatusOK || envelope.Data.Active {
+			t.Errorf("Expected the webhook to be disabled, got %d %+v", resp.StatusCode, envelope.Data)
+		}
+
+		resp, _ = webhookRequest(http.MethodDelete, ts.URL("/webhooks/"+sub.ID), nil, userID, false)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", resp.StatusCode)
+		}
+		resp, _ = webhookRequest(http.MethodGet, ts.URL("/webhooks/"+sub.ID), nil, userID, false)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status 404 after delete, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"database/sql"
 	"net/http"
 	"strings"
 	"time"
@@ -23,6 +24,13 @@ func NewWeekHandler(repo *repository.WeekRepository) *WeekHandler {
 	}
 }
 
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *WeekHandler) WithTx(tx *sql.Tx) *WeekHandler {
+	txHandler := *h
+	txHandler.repo = h.repo.WithTx(tx)
+	return &txHandler
+}
+
 // WeekResponse represents the API response format for a week.
 type WeekResponse struct {
 	ID         string    `json:"id"`
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"database/sql"
 	"net/http"
 	"strings"
 	"time"
@@ -8,21 +9,31 @@ import (
 	"github.com/google/uuid"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/weeklylookup"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/organization"
 	"github.com/waynenilsen/power-pro-v3/internal/repository"
 )
 
 // WeeklyLookupHandler handles HTTP requests for weekly lookup operations.
 type WeeklyLookupHandler struct {
-	repo *repository.WeeklyLookupRepository
+	repo       *repository.WeeklyLookupRepository
+	orgService *organization.Service
 }
 
 // NewWeeklyLookupHandler creates a new WeeklyLookupHandler.
-func NewWeeklyLookupHandler(repo *repository.WeeklyLookupRepository) *WeeklyLookupHandler {
+func NewWeeklyLookupHandler(repo *repository.WeeklyLookupRepository, orgService *organization.Service) *WeeklyLookupHandler {
 	return &WeeklyLookupHandler{
-		repo: repo,
+		repo:       repo,
+		orgService: orgService,
 	}
 }
 
+// WithTx returns a copy of the handler that makes its changes in tx.
+func (h *WeeklyLookupHandler) WithTx(tx *sql.Tx) *WeeklyLookupHandler {
+	txHandler := *h
+	txHandler.repo = h.repo.WithTx(tx)
+	return &txHandler
+}
+
 // WeeklyLookupEntryResponse represents an entry in the API response.
 type WeeklyLookupEntryResponse struct {
 	WeekNumber         int       `json:"weekNumber"`
@@ -33,19 +44,22 @@ type WeeklyLookupEntryResponse struct {
 
 // WeeklyLookupResponse represents the API response format for a weekly lookup.
 type WeeklyLookupResponse struct {
-	ID        string                     `json:"id"`
-	Name      string                     `json:"name"`
-	Entries   []WeeklyLookupEntryResponse `json:"entries"`
-	ProgramID *string                    `json:"programId,omitempty"`
-	CreatedAt time.Time                  `json:"createdAt"`
-	UpdatedAt time.Time                  `json:"updatedAt"`
+	ID             string                      `json:"id"`
+	Name           string                      `json:"name"`
+	Entries        []WeeklyLookupEntryResponse `json:"entries"`
+	ProgramID      *string                     `json:"programId,omitempty"`
+	OrganizationID *string                     `json:"organizationId"`
+	CreatedAt      time.Time                   `json:"createdAt"`
+	UpdatedAt      time.Time                   `json:"updatedAt"`
 }
 
 // CreateWeeklyLookupRequest represents the request body for creating a weekly lookup.
+// A lookup with an organizationId is visible only to that organization's members.
 type CreateWeeklyLookupRequest struct {
-	Name      string                       `json:"name"`
-	Entries   []WeeklyLookupEntryRequest   `json:"entries"`
-	ProgramID *string                      `json:"programId,omitempty"`
+	Name           string                     `json:"name"`
+	Entries        []WeeklyLookupEntryRequest `json:"entries"`
+	ProgramID      *string                    `json:"programId,omitempty"`
+	OrganizationID *string                    `json:"organizationId,omitempty"`
 }
 
 // WeeklyLookupEntryRequest represents an entry in the create/update request.
@@ -58,9 +72,9 @@ type WeeklyLookupEntryRequest struct {
 
 // UpdateWeeklyLookupRequest represents the request body for updating a weekly lookup.
 type UpdateWeeklyLookupRequest struct {
-	Name      *string                      `json:"name,omitempty"`
-	Entries   *[]WeeklyLookupEntryRequest  `json:"entries,omitempty"`
-	ProgramID **string                     `json:"programId,omitempty"`
+	Name      *string                     `json:"name,omitempty"`
+	Entries   *[]WeeklyLookupEntryRequest `json:"entries,omitempty"`
+	ProgramID **string                    `json:"programId,omitempty"`
 }
 
 func weeklyLookupToResponse(w *weeklylookup.WeeklyLookup) WeeklyLookupResponse {
@@ -75,12 +89,13 @@ func weeklyLookupToResponse(w *weeklylookup.WeeklyLookup) WeeklyLookupResponse {
 	}
 
 	return WeeklyLookupResponse{
-		ID:        w.ID,
-		Name:      w.Name,
-		Entries:   entries,
-		ProgramID: w.ProgramID,
-		CreatedAt: w.CreatedAt,
-		UpdatedAt: w.UpdatedAt,
+		ID:             w.ID,
+		Name:           w.Name,
+		Entries:        entries,
+		ProgramID:      w.ProgramID,
+		OrganizationID: w.OrganizationID,
+		CreatedAt:      w.CreatedAt,
+		UpdatedAt:      w.UpdatedAt,
 	}
 }
 
@@ -130,6 +145,7 @@ func (h *WeeklyLookupHandler) List(w http.ResponseWriter, r *http.Request) {
 		Offset:    int64(pg.Offset),
 		SortBy:    sortBy,
 		SortOrder: sortOrder,
+		ViewerID:  catalogViewerID(r),
 	}
 
 	lookups, total, err := h.repo.List(params)
@@ -160,8 +176,8 @@ func (h *WeeklyLookupHandler) Get(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewInternal("failed to get weekly lookup", err))
 		return
 	}
-	if lookup == nil {
-		writeDomainError(w, apperrors.NewNotFound("weekly lookup", id))
+	if err := h.checkVisible(r, lookup, id); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -176,6 +192,11 @@ func (h *WeeklyLookupHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := authorizeCatalogManage(r, h.orgService, req.OrganizationID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
 	// Generate UUID
 	id := uuid.New().String()
 
@@ -195,6 +216,7 @@ func (h *WeeklyLookupHandler) Create(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewValidationMsg("validation failed"), details...)
 		return
 	}
+	newLookup.OrganizationID = req.OrganizationID
 
 	// Persist
 	if err := h.repo.Create(newLookup); err != nil {
@@ -219,8 +241,8 @@ func (h *WeeklyLookupHandler) Update(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewInternal("failed to get weekly lookup", err))
 		return
 	}
-	if existing == nil {
-		writeDomainError(w, apperrors.NewNotFound("weekly lookup", id))
+	if err := h.checkManage(r, existing, id); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -273,8 +295,8 @@ func (h *WeeklyLookupHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, apperrors.NewInternal("failed to get weekly lookup", err))
 		return
 	}
-	if existing == nil {
-		writeDomainError(w, apperrors.NewNotFound("weekly lookup", id))
+	if err := h.checkManage(r, existing, id); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
@@ -297,3 +319,27 @@ func (h *WeeklyLookupHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// checkVisible returns a not found error when the lookup does not exist or belongs to an
+// organization the caller is not a member of.
+func (h *WeeklyLookupHandler) checkVisible(r *http.Request, lookup *weeklylookup.WeeklyLookup, id string) error {
+	if lookup == nil {
+		return apperrors.NewNotFound("weekly lookup", id)
+	}
+	visible, err := canViewCatalogEntry(r, h.orgService, lookup.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return apperrors.NewNotFound("weekly lookup", id)
+	}
+	return nil
+}
+
+// checkManage checks the lookup is visible to the caller and that they may change it.
+func (h *WeeklyLookupHandler) checkManage(r *http.Request, lookup *weeklylookup.WeeklyLookup, id string) error {
+	if err := h.checkVisible(r, lookup, id); err != nil {
+		return err
+	}
+	return authorizeCatalogManage(r, h.orgService, lookup.OrganizationID)
+}
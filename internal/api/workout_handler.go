@@ -7,31 +7,35 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/loadstrategy"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/prescription"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/rpechart"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/setscheme"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/workout"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
-	"github.com/waynenilsen/power-pro-v3/internal/middleware"
 	"github.com/waynenilsen/power-pro-v3/internal/repository"
+	"github.com/waynenilsen/power-pro-v3/internal/service"
 )
 
 // WorkoutHandler handles HTTP requests for workout generation operations.
 type WorkoutHandler struct {
-	workoutRepo *repository.WorkoutRepository
-	liftLookup  *repository.LiftLookupAdapter
-	maxLookup   *repository.MaxLookupAdapter
-	rpeChart    *rpechart.RPEChart
+	workoutRepo      *repository.WorkoutRepository
+	liftLookup       *repository.LiftLookupAdapter
+	maxLookup        *repository.MaxLookupAdapter
+	rpeChart         *rpechart.RPEChart
+	readinessService *service.ReadinessService
 }
 
 // NewWorkoutHandler creates a new WorkoutHandler.
-func NewWorkoutHandler(workoutRepo *repository.WorkoutRepository, sqlDB *sql.DB) *WorkoutHandler {
+// readinessService is optional; when nil, workouts are generated without readiness adjustments.
+func NewWorkoutHandler(workoutRepo *repository.WorkoutRepository, sqlDB *sql.DB, readinessService *service.ReadinessService) *WorkoutHandler {
 	return &WorkoutHandler{
-		workoutRepo: workoutRepo,
-		liftLookup:  repository.NewLiftLookupAdapter(sqlDB),
-		maxLookup:   repository.NewMaxLookupAdapter(sqlDB),
-		rpeChart:    rpechart.NewDefaultRPEChart(),
+		workoutRepo:      workoutRepo,
+		liftLookup:       repository.NewLiftLookupAdapter(sqlDB),
+		maxLookup:        repository.NewMaxLookupAdapter(sqlDB),
+		rpeChart:         rpechart.NewDefaultRPEChart(),
+		readinessService: readinessService,
 	}
 }
 
@@ -68,6 +72,8 @@ type WorkoutResponse struct {
 	DaySlug        string                    `json:"daySlug"`
 	Date           string                    `json:"date"`
 	Exercises      []WorkoutExerciseResponse `json:"exercises"`
+	// Readiness is the adjustment from the day's readiness check-in, if the user checked in.
+	Readiness *ReadinessAdjustmentResponse `json:"readiness,omitempty"`
 }
 
 func workoutToResponse(w *workout.Workout) WorkoutResponse {
@@ -103,11 +109,14 @@ func workoutToResponse(w *workout.Workout) WorkoutResponse {
 		DaySlug:        w.DaySlug,
 		Date:           w.Date,
 		Exercises:      exercises,
+		Readiness:      readinessAdjustmentToResponse(w.Readiness),
 	}
 }
 
 // Generate handles GET /users/{userId}/workout
 // Generates the current workout for the user based on their program state.
+// If the user has checked in for the workout date, loads and volume are adjusted
+// according to the program's readiness mapping.
 // Optional query params: date, weekNumber, daySlug
 func (h *WorkoutHandler) Generate(w http.ResponseWriter, r *http.Request) {
 	userID := r.PathValue("userId")
@@ -116,10 +125,8 @@ func (h *WorkoutHandler) Generate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Authorization check: only the user themselves or an admin can generate workout
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if authUserID != userID && !isAdmin {
+	// Authorization check: the user, their coach or an admin can generate workout
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
 		writeDomainError(w, apperrors.NewForbidden("you can only view your own workouts"))
 		return
 	}
@@ -185,14 +192,27 @@ func (h *WorkoutHandler) Generate(w http.ResponseWriter, r *http.Request) {
 		SetGenContext: setscheme.DefaultSetGenerationContext(),
 	}
 
-	// Build lookup context if lookups are configured
-	if data.WeeklyLookup != nil || data.DailyLookup != nil {
+	// Resolve the readiness adjustment from the day's check-in, if any
+	if h.readinessService != nil {
+		adj, err := h.readinessService.GetAdjustment(r.Context(), userID, data.Enrollment.ProgramID, workoutDate)
+		if err != nil {
+			writeDomainError(w, apperrors.NewInternal("failed to resolve readiness adjustment", err))
+			return
+		}
+		genCtx.Readiness = adj
+	}
+
+	// Build lookup context if lookups or a readiness adjustment are configured
+	if data.WeeklyLookup != nil || data.DailyLookup != nil || genCtx.Readiness != nil {
 		genCtx.LookupContext = &loadstrategy.LookupContext{
 			WeekNumber:   data.Enrollment.CurrentWeek,
 			DaySlug:      data.Day.Slug,
 			WeeklyLookup: data.WeeklyLookup,
 			DailyLookup:  data.DailyLookup,
 		}
+		if genCtx.Readiness != nil {
+			genCtx.LookupContext.ReadinessModifier = &genCtx.Readiness.LoadModifier
+		}
 	}
 
 	// Build program context
@@ -251,10 +271,8 @@ func (h *WorkoutHandler) Preview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Authorization check: only the user themselves or an admin can preview workout
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if authUserID != userID && !isAdmin {
+	// Authorization check: the user, their coach or an admin can preview workout
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
 		writeDomainError(w, apperrors.NewForbidden("you can only view your own workouts"))
 		return
 	}
@@ -2,35 +2,50 @@ package api
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/event"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/userprogramstate"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/workout"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/workoutsession"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
 	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/outbox"
 	"github.com/waynenilsen/power-pro-v3/internal/repository"
+	"github.com/waynenilsen/power-pro-v3/internal/service"
 )
 
 // WorkoutSessionHandler handles HTTP requests for workout session operations.
 type WorkoutSessionHandler struct {
-	sessionRepo *repository.WorkoutSessionRepository
-	stateRepo   *repository.UserProgramStateRepository
-	eventBus    *event.Bus
+	sessionRepo        *repository.WorkoutSessionRepository
+	stateRepo          *repository.UserProgramStateRepository
+	readinessService   *service.ReadinessService
+	progressionService *service.ProgressionService
+	outbox             *outbox.Service
 }
 
 // NewWorkoutSessionHandler creates a new WorkoutSessionHandler.
+// readinessService is optional; when nil, readiness adjustments are not recorded on sessions.
+// progressionService is used to preview the progressions a finished session would trigger.
+// Session status changes and their events are stored together through outboxService.
 func NewWorkoutSessionHandler(
 	sessionRepo *repository.WorkoutSessionRepository,
 	stateRepo *repository.UserProgramStateRepository,
-	eventBus *event.Bus,
+	readinessService *service.ReadinessService,
+	progressionService *service.ProgressionService,
+	outboxService *outbox.Service,
 ) *WorkoutSessionHandler {
 	return &WorkoutSessionHandler{
-		sessionRepo: sessionRepo,
-		stateRepo:   stateRepo,
-		eventBus:    eventBus,
+		sessionRepo:        sessionRepo,
+		stateRepo:          stateRepo,
+		readinessService:   readinessService,
+		progressionService: progressionService,
+		outbox:             outboxService,
 	}
 }
 
@@ -45,6 +60,9 @@ type WorkoutSessionResponse struct {
 	FinishedAt         *time.Time `json:"finishedAt,omitempty"`
 	CreatedAt          time.Time  `json:"createdAt"`
 	UpdatedAt          time.Time  `json:"updatedAt"`
+	// Readiness is the readiness adjustment recorded when the session was started.
+	// Only included on single-session responses.
+	Readiness *ReadinessAdjustmentResponse `json:"readiness,omitempty"`
 }
 
 // StartWorkoutRequest represents the request body for starting a workout.
@@ -66,8 +84,24 @@ func workoutSessionToResponse(ws *workoutsession.WorkoutSession) WorkoutSessionR
 	}
 }
 
+// sessionResponseWithReadiness converts a session to its response format including
+// the readiness adjustment recorded on it.
+func (h *WorkoutSessionHandler) sessionResponseWithReadiness(ctx context.Context, ws *workoutsession.WorkoutSession) (WorkoutSessionResponse, error) {
+	resp := workoutSessionToResponse(ws)
+	if h.readinessService == nil {
+		return resp, nil
+	}
+	adj, err := h.readinessService.GetSessionAdjustment(ctx, ws.ID)
+	if err != nil {
+		return resp, err
+	}
+	resp.Readiness = readinessAdjustmentToResponse(adj)
+	return resp, nil
+}
+
 // Start handles POST /workouts/start
 // Starts a new workout session for the authenticated user.
+// If the user has checked in today, the resulting readiness adjustment is recorded on the session.
 func (h *WorkoutSessionHandler) Start(w http.ResponseWriter, r *http.Request) {
 	authUserID := middleware.GetUserID(r)
 	if authUserID == "" {
@@ -128,22 +162,36 @@ func (h *WorkoutSessionHandler) Start(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Persist
-	if err := h.sessionRepo.Create(session); err != nil {
-		writeDomainError(w, apperrors.NewInternal("failed to create workout session", err))
+	// Persist the session and its WORKOUT_STARTED event together
+	err = h.outbox.Transact(r.Context(), func(tx *sql.Tx) ([]event.StateEvent, error) {
+		if err := h.sessionRepo.WithTx(tx).Create(session); err != nil {
+			return nil, apperrors.NewInternal("failed to create workout session", err)
+		}
+		return []event.StateEvent{sessionEvent(event.EventWorkoutStarted, authUserID, enrollment.State.ProgramID, session)}, nil
+	})
+	if err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
-	// Emit WORKOUT_STARTED event
-	if h.eventBus != nil {
-		evt := event.NewStateEvent(event.EventWorkoutStarted, authUserID, enrollment.State.ProgramID).
-			WithPayload(event.PayloadSessionID, session.ID).
-			WithPayload(event.PayloadWeekNumber, session.WeekNumber).
-			WithPayload(event.PayloadDaySlug, session.DayIndex)
-		h.eventBus.PublishAsync(context.Background(), evt)
+	// Record the readiness adjustment from today's check-in, if any
+	resp := workoutSessionToResponse(session)
+	if h.readinessService != nil {
+		adj, err := h.readinessService.GetAdjustment(r.Context(), authUserID, enrollment.State.ProgramID, workout.GetDateString())
+		if err != nil {
+			writeDomainError(w, apperrors.NewInternal("failed to resolve readiness adjustment", err))
+			return
+		}
+		if adj != nil {
+			if err := h.readinessService.RecordSessionAdjustment(r.Context(), session.ID, adj); err != nil {
+				writeDomainError(w, apperrors.NewInternal("failed to record readiness adjustment", err))
+				return
+			}
+			resp.Readiness = readinessAdjustmentToResponse(adj)
+		}
 	}
 
-	writeData(w, http.StatusCreated, workoutSessionToResponse(session))
+	writeData(w, http.StatusCreated, resp)
 }
 
 // Get handles GET /workouts/{id}
@@ -165,11 +213,7 @@ func (h *WorkoutSessionHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Authorization: check if user owns this session or is admin
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-
-	// Get the user program state to check ownership
+	// Get the user program state to check access
 	state, err := h.stateRepo.GetByID(session.UserProgramStateID)
 	if err != nil {
 		writeDomainError(w, apperrors.NewInternal("failed to get program state", err))
@@ -180,16 +224,25 @@ func (h *WorkoutSessionHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if state.UserID != authUserID && !isAdmin {
+	// Authorization: the session's owner, their coach or an admin can view it
+	if !canAccessUser(r, state.UserID, coaching.PermissionViewLogs) {
 		writeDomainError(w, apperrors.NewForbidden("you can only view your own workout sessions"))
 		return
 	}
 
-	writeData(w, http.StatusOK, workoutSessionToResponse(session))
+	resp, err := h.sessionResponseWithReadiness(r.Context(), session)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to get session readiness", err))
+		return
+	}
+
+	writeData(w, http.StatusOK, resp)
 }
 
 // Finish handles POST /workouts/{id}/finish
 // Marks a workout session as completed.
+// With ?dryRun=true, the session is left unchanged and the response previews the
+// AFTER_SESSION progressions that completing it would apply.
 func (h *WorkoutSessionHandler) Finish(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.PathValue("id")
 	if sessionID == "" {
@@ -226,6 +279,16 @@ func (h *WorkoutSessionHandler) Finish(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	dryRun, err := ParseFilterBool(r.URL.Query(), "dryRun")
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if dryRun != nil && *dryRun {
+		h.previewFinish(w, r, session, state)
+		return
+	}
+
 	// Complete the session
 	if err := session.Complete(); err != nil {
 		switch err {
@@ -239,22 +302,46 @@ func (h *WorkoutSessionHandler) Finish(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Persist
-	if err := h.sessionRepo.Complete(session); err != nil {
-		writeDomainError(w, apperrors.NewInternal("failed to save completed session", err))
+	// Persist the completion and its WORKOUT_COMPLETED event together
+	err = h.outbox.Transact(r.Context(), func(tx *sql.Tx) ([]event.StateEvent, error) {
+		if err := h.sessionRepo.WithTx(tx).Complete(session); err != nil {
+			return nil, apperrors.NewInternal("failed to save completed session", err)
+		}
+		return []event.StateEvent{sessionEvent(event.EventWorkoutCompleted, state.UserID, state.ProgramID, session)}, nil
+	})
+	if err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
-	// Emit WORKOUT_COMPLETED event
-	if h.eventBus != nil {
-		evt := event.NewStateEvent(event.EventWorkoutCompleted, state.UserID, state.ProgramID).
-			WithPayload(event.PayloadSessionID, session.ID).
-			WithPayload(event.PayloadWeekNumber, session.WeekNumber).
-			WithPayload(event.PayloadDaySlug, session.DayIndex)
-		h.eventBus.PublishAsync(context.Background(), evt)
+	writeData(w, http.StatusOK, workoutSessionToResponse(session))
+}
+
+// previewFinish writes the progressions that finishing the session would apply, without
+// completing the session or persisting any progression.
+func (h *WorkoutSessionHandler) previewFinish(w http.ResponseWriter, r *http.Request, session *workoutsession.WorkoutSession, state *userprogramstate.UserProgramState) {
+	if session.Status != workoutsession.StatusInProgress {
+		writeDomainError(w, apperrors.NewSessionNotActive(string(session.Status)))
+		return
 	}
 
-	writeData(w, http.StatusOK, workoutSessionToResponse(session))
+	evt, err := h.progressionService.NewWorkoutSessionTriggerEvent(r.Context(), state.UserID, state.ProgramID, session.ID, session.WeekNumber, session.DayIndex)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to build session trigger", err))
+		return
+	}
+
+	result, err := h.progressionService.DryRunSessionComplete(r.Context(), evt)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotEnrolled) {
+			writeDomainError(w, apperrors.NewBadRequest("user is not enrolled in any program"))
+			return
+		}
+		writeDomainError(w, apperrors.NewInternal("failed to preview progressions", err))
+		return
+	}
+
+	writeData(w, http.StatusOK, aggregateResultToResponse(result))
 }
 
 // Abandon handles POST /workouts/{id}/abandon
@@ -308,26 +395,31 @@ func (h *WorkoutSessionHandler) Abandon(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Persist
-	if err := h.sessionRepo.Abandon(session); err != nil {
-		writeDomainError(w, apperrors.NewInternal("failed to save abandoned session", err))
+	// Persist the abandonment and its WORKOUT_ABANDONED event together
+	err = h.outbox.Transact(r.Context(), func(tx *sql.Tx) ([]event.StateEvent, error) {
+		if err := h.sessionRepo.WithTx(tx).Abandon(session); err != nil {
+			return nil, apperrors.NewInternal("failed to save abandoned session", err)
+		}
+		return []event.StateEvent{sessionEvent(event.EventWorkoutAbandoned, state.UserID, state.ProgramID, session)}, nil
+	})
+	if err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
-	// Emit WORKOUT_ABANDONED event
-	if h.eventBus != nil {
-		evt := event.NewStateEvent(event.EventWorkoutAbandoned, state.UserID, state.ProgramID).
-			WithPayload(event.PayloadSessionID, session.ID).
-			WithPayload(event.PayloadWeekNumber, session.WeekNumber).
-			WithPayload(event.PayloadDaySlug, session.DayIndex)
-		h.eventBus.PublishAsync(context.Background(), evt)
-	}
-
 	writeData(w, http.StatusOK, workoutSessionToResponse(session))
 }
 
+// sessionEvent builds a workout session status event.
+func sessionEvent(eventType event.EventType, userID, programID string, session *workoutsession.WorkoutSession) event.StateEvent {
+	return event.NewStateEvent(eventType, userID, programID).
+		WithPayload(event.PayloadSessionID, session.ID).
+		WithPayload(event.PayloadWeekNumber, session.WeekNumber).
+		WithPayload(event.PayloadDaySlug, session.DayIndex)
+}
+
 // ListByUser handles GET /users/{id}/workouts
-// Lists a user's workout history with pagination.
+// Lists a user's workout history with cursor pagination.
 func (h *WorkoutSessionHandler) ListByUser(w http.ResponseWriter, r *http.Request) {
 	userID := r.PathValue("id")
 	if userID == "" {
@@ -336,32 +428,53 @@ func (h *WorkoutSessionHandler) ListByUser(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Authorization check
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if authUserID != userID && !isAdmin {
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
 		writeDomainError(w, apperrors.NewForbidden("you can only view your own workouts"))
 		return
 	}
 
-	// Parse pagination
-	pagination := ParsePagination(r.URL.Query())
-
-	// Parse optional status filter
-	status, err := ParseFilterEnum(r.URL.Query(), "status", []string{"IN_PROGRESS", "COMPLETED", "ABANDONED"})
+	// Parse pagination and sort (default: most recently started first)
+	query := r.URL.Query()
+	page, err := ParseCursorPage(query)
 	if err != nil {
 		writeDomainError(w, err)
 		return
 	}
 
-	// Get total count
-	total, err := h.sessionRepo.CountByUserID(userID, status)
-	if err != nil {
-		writeDomainError(w, apperrors.NewInternal("failed to count sessions", err))
+	// Parse optional status and start date filters
+	filter := repository.WorkoutSessionFilter{UserID: userID}
+	if filter.Status, err = ParseFilterEnum(query, "status", []string{"IN_PROGRESS", "COMPLETED", "ABANDONED"}); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if filter.From, err = ParseFilterDate(query, "startDate"); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	if filter.To, err = ParseFilterDateEndOfDay(query, "endDate"); err != nil {
+		writeDomainError(w, err)
 		return
 	}
 
+	// Get total count for the first page only
+	var total *int64
+	if page.After == nil {
+		count, err := h.sessionRepo.CountByUser(filter)
+		if err != nil {
+			writeDomainError(w, apperrors.NewInternal("failed to count sessions", err))
+			return
+		}
+		total = &count
+	}
+
 	// Get sessions
-	sessions, err := h.sessionRepo.GetByUserID(userID, status, pagination.Limit, pagination.Offset)
+	sessions, next, err := h.sessionRepo.ListByUser(repository.WorkoutSessionListParams{
+		Filter:    filter,
+		SortOrder: page.SortOrder,
+		After:     page.After,
+		Limit:     int64(page.Limit),
+		Offset:    int64(page.Offset),
+	})
 	if err != nil {
 		writeDomainError(w, apperrors.NewInternal("failed to get sessions", err))
 		return
@@ -373,7 +486,7 @@ func (h *WorkoutSessionHandler) ListByUser(w http.ResponseWriter, r *http.Reques
 		responses[i] = workoutSessionToResponse(session)
 	}
 
-	writePaginatedData(w, http.StatusOK, responses, total, pagination.Limit, pagination.Offset)
+	writeCursorPaginatedData(w, http.StatusOK, responses, page, total, next)
 }
 
 // GetCurrentByUser handles GET /users/{id}/workouts/current
@@ -386,9 +499,7 @@ func (h *WorkoutSessionHandler) GetCurrentByUser(w http.ResponseWriter, r *http.
 	}
 
 	// Authorization check
-	authUserID := middleware.GetUserID(r)
-	isAdmin := middleware.IsAdmin(r)
-	if authUserID != userID && !isAdmin {
+	if !canAccessUser(r, userID, coaching.PermissionViewLogs) {
 		writeDomainError(w, apperrors.NewForbidden("you can only view your own workouts"))
 		return
 	}
@@ -404,5 +515,11 @@ func (h *WorkoutSessionHandler) GetCurrentByUser(w http.ResponseWriter, r *http.
 		return
 	}
 
-	writeData(w, http.StatusOK, workoutSessionToResponse(session))
+	resp, err := h.sessionResponseWithReadiness(r.Context(), session)
+	if err != nil {
+		writeDomainError(w, apperrors.NewInternal("failed to get session readiness", err))
+		return
+	}
+
+	writeData(w, http.StatusOK, resp)
 }
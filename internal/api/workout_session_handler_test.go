@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/waynenilsen/power-pro-v3/internal/api"
 	"github.com/waynenilsen/power-pro-v3/internal/testutil"
 )
 
@@ -681,6 +682,29 @@ func TestWorkoutSessionStatusFilter(t *testing.T) {
 		}
 	})
 
+	t.Run("pages through sessions with a cursor", func(t *testing.T) {
+		listURL := ts.URL("/users/" + userID + "/workouts?limit=1")
+		ids, metas := walkCursorPages(t, listURL, userID)
+		if len(ids) != 3 || len(metas) != 3 || metas[0].Total != 3 {
+			t.Fatalf("Expected 3 sessions over 3 pages, got %v %+v", ids, metas)
+		}
+		if ids[0] == ids[1] || ids[1] == ids[2] || ids[0] == ids[2] {
+			t.Errorf("Expected distinct sessions, got %v", ids)
+		}
+
+		ascending, _ := walkCursorPages(t, listURL+"&sortOrder=asc", userID)
+		if len(ascending) != 3 || ascending[0] != ids[2] || ascending[2] != ids[0] {
+			t.Errorf("Expected sortOrder=asc to reverse the default order, got %v and %v", ascending, ids)
+		}
+
+		if ids, _ := walkCursorPages(t, listURL+"&status=COMPLETED", userID); len(ids) != 1 {
+			t.Errorf("Expected 1 completed session, got %d", len(ids))
+		}
+		if ids, _ := walkCursorPages(t, listURL+"&startDate=2000-01-01&endDate=2000-12-31", userID); len(ids) != 0 {
+			t.Errorf("Expected no sessions in 2000, got %d", len(ids))
+		}
+	})
+
 	t.Run("rejects invalid status filter", func(t *testing.T) {
 		resp, err := userGetWorkoutSession(ts.URL("/users/"+userID+"/workouts?status=INVALID"), userID)
 		if err != nil {
@@ -693,3 +717,85 @@ func TestWorkoutSessionStatusFilter(t *testing.T) {
 		}
 	})
 }
+
+func TestWorkoutSessionFinishDryRun(t *testing.T) {
+	ts, err := testutil.NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	cycleID := createWorkoutSessionTestCycle(t, ts, "Dry Run Test Cycle")
+	programID := createWorkoutSessionTestProgram(t, ts, "Dry Run Test Program", "dry-run-test-program", cycleID)
+	userID := createTestUserForProfile(t, ts, "dry-run@example.com", "password123", "Dry Run User")
+
+	enrollUserForWorkoutSession(t, ts, userID, programID)
+
+	resp, _ := userPostWorkoutStart(ts.URL("/workouts/start"), userID)
+	var startEnvelope WorkoutSessionEnvelope
+	json.NewDecoder(resp.Body).Decode(&startEnvelope)
+	resp.Body.Close()
+	sessionID := startEnvelope.Data.ID
+
+	t.Run("previews progressions without finishing", func(t *testing.T) {
+		resp, err := userPostWorkoutFinish(ts.URL("/workouts/"+sessionID+"/finish?dryRun=true"), userID)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, bodyBytes)
+		}
+
+		var envelope struct {
+			Data api.TriggerResponse `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if !envelope.Data.DryRun || envelope.Data.TriggerType != "AFTER_SESSION" {
+			t.Errorf("Expected AFTER_SESSION dry run, got %+v", envelope.Data)
+		}
+
+		getResp, err := userGetWorkoutSession(ts.URL("/workouts/"+sessionID), userID)
+		if err != nil {
+			t.Fatalf("Failed to get session: %v", err)
+		}
+		defer getResp.Body.Close()
+
+		var session WorkoutSessionEnvelope
+		json.NewDecoder(getResp.Body).Decode(&session)
+		if session.Data.Status != "IN_PROGRESS" {
+			t.Errorf("Expected session to remain IN_PROGRESS, got %s", session.Data.Status)
+		}
+	})
+
+	t.Run("invalid dryRun value returns 400", func(t *testing.T) {
+		resp, err := userPostWorkoutFinish(ts.URL("/workouts/"+sessionID+"/finish?dryRun=maybe"), userID)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("cannot preview a finished session", func(t *testing.T) {
+		resp, _ := userPostWorkoutFinish(ts.URL("/workouts/"+sessionID+"/finish"), userID)
+		resp.Body.Close()
+
+		resp, err := userPostWorkoutFinish(ts.URL("/workouts/"+sessionID+"/finish?dryRun=true"), userID)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusOK {
+			t.Error("Expected preview of a finished session to fail")
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// SQLiteRepository implements Repository using SQLite.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository creates a new SQLite-backed audit log repository.
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// sortableTime is the layout of stored times: UTC with a fixed-width fraction, so that
+// they sort as text.
+const sortableTime = "2006-01-02T15:04:05.000000000Z07:00"
+
+const entryColumns = `id, actor_id, action, entity_type, entity_id, subject_user_id, method, path, before_data, after_data, changes, created_at`
+
+// Create appends an entry to the log in tx.
+func (r *SQLiteRepository) Create(ctx context.Context, tx *sql.Tx, entry *Entry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return apperrors.NewInternal("failed to encode audit changes", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO audit_log (`+entryColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.ID, entry.ActorID, entry.Action, entry.EntityType, entry.EntityID, nullString(entry.SubjectUserID),
+		entry.Method, entry.Path, nullJSON(entry.Before), nullJSON(entry.After), string(changes),
+		entry.CreatedAt.UTC().Format(sortableTime))
+	if err != nil {
+		return apperrors.NewInternal("failed to record audit entry", err)
+	}
+	return nil
+}
+
+// List returns a page of entries matching the filter, newest first, and the total count.
+func (r *SQLiteRepository) List(ctx context.Context, filter Filter, limit, offset int64) ([]Entry, int64, error) {
+	where := "1 = 1"
+	args := []interface{}{}
+	for _, f := range []struct {
+		column string
+		value  *string
+	}{
+		{"actor_id", filter.ActorID},
+		{"action", filter.Action},
+		{"entity_type", filter.EntityType},
+		{"entity_id", filter.EntityID},
+		{"subject_user_id", filter.SubjectUserID},
+	} {
+		if f.value != nil {
+			where += " AND " + f.column + " = ?"
+			args = append(args, *f.value)
+		}
+	}
+	if filter.From != nil {
+		where += " AND created_at >= ?"
+		args = append(args, filter.From.UTC().Format(sortableTime))
+	}
+	if filter.To != nil {
+		where += " AND created_at <= ?"
+		args = append(args, filter.To.UTC().Format(sortableTime))
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_log WHERE "+where, args...).Scan(&total); err != nil {
+		return nil, 0, apperrors.NewInternal("failed to count audit entries", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+entryColumns+` FROM audit_log WHERE `+where+`
+		ORDER BY seq DESC
+		LIMIT ? OFFSET ?
+	`, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list audit entries", err)
+	}
+	defer rows.Close()
+
+	entries := []Entry{}
+	for rows.Next() {
+		var e Entry
+		var subject, before, after sql.NullString
+		var changes, createdAt string
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.Action, &e.EntityType, &e.EntityID, &subject,
+			&e.Method, &e.Path, &before, &after, &changes, &createdAt); err != nil {
+			return nil, 0, apperrors.NewInternal("failed to scan audit entry", err)
+		}
+		if subject.Valid {
+			e.SubjectUserID = &subject.String
+		}
+		if before.Valid {
+			e.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			e.After = json.RawMessage(after.String)
+		}
+		if err := json.Unmarshal([]byte(changes), &e.Changes); err != nil {
+			return nil, 0, apperrors.NewInternal("failed to decode audit changes", err)
+		}
+		e.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list audit entries", err)
+	}
+	return entries, total, nil
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func nullJSON(v json.RawMessage) sql.NullString {
+	if len(v) == 0 {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(v), Valid: true}
+}
@@ -0,0 +1,246 @@
+// Package audit keeps an append-only log of changes to shared data: the program catalog
+// (lifts, prescriptions, days, weeks, cycles, lookups, programs and progressions), admin
+// webhooks and event replays, and user data changed on behalf of another user, such as
+// lift maxes, progressions and account security settings. Each entry records who made the
+// change, the entity, its JSON before and after the change, and the top-level fields that
+// changed.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// Actions
+const (
+	ActionCreate = "CREATE"
+	ActionUpdate = "UPDATE"
+	ActionDelete = "DELETE"
+)
+
+// Entity types
+const (
+	EntityLift               = "lift"
+	EntityLiftMax            = "lift_max"
+	EntityPrescription       = "prescription"
+	EntityDay                = "day"
+	EntityWeek               = "week"
+	EntityCycle              = "cycle"
+	EntityWeeklyLookup       = "weekly_lookup"
+	EntityDailyLookup        = "daily_lookup"
+	EntityProgram            = "program"
+	EntityProgression        = "progression"
+	EntityProgramProgression = "program_progression"
+	EntityReadinessMapping   = "readiness_mapping"
+	EntityWebhook            = "webhook"
+	// Operations on user data are recorded as the creation of the operation, identified by
+	// the entity it acts on: a progression log, a progression or an event.
+	EntityProgressionRevert = "progression_revert"
+	EntityManualProgression = "manual_progression"
+	EntityEventReplay       = "event_replay"
+	EntityProfile           = "profile"
+	EntityTwoFactor         = "two_factor"
+	EntityAccountDeletion   = "account_deletion"
+	EntityAccessToken       = "access_token"
+	EntityIdentity          = "identity"
+)
+
+// Change is the value of a field before and after a change. Null means the field was
+// absent.
+type Change struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// Entry is a recorded change.
+type Entry struct {
+	ID      string `json:"id"`
+	ActorID string `json:"actorId"`
+	Action  string `json:"action"`
+	// EntityType is one of the Entity constants.
+	EntityType string `json:"entityType"`
+	EntityID   string `json:"entityId"`
+	// SubjectUserID is the user whose data changed, for user data such as lift maxes.
+	SubjectUserID *string `json:"subjectUserId"`
+	// Method and Path are the request that made the change.
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Before is nil for creations and After for deletions.
+	Before    json.RawMessage   `json:"before"`
+	After     json.RawMessage   `json:"after"`
+	Changes   map[string]Change `json:"changes"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// Filter narrows a listing of entries. Nil fields match everything.
+type Filter struct {
+	ActorID       *string
+	Action        *string
+	EntityType    *string
+	EntityID      *string
+	SubjectUserID *string
+	// From and To bound when entries were recorded, inclusive.
+	From *time.Time
+	To   *time.Time
+}
+
+// Repository defines the interface for audit log persistence.
+type Repository interface {
+	// Create appends an entry to the log in tx.
+	Create(ctx context.Context, tx *sql.Tx, entry *Entry) error
+	// List returns a page of entries matching the filter, newest first, and the total count.
+	List(ctx context.Context, filter Filter, limit, offset int64) ([]Entry, int64, error)
+}
+
+// Service records and lists audit entries.
+type Service struct {
+	db   *sql.DB
+	repo Repository
+	now  func() time.Time
+}
+
+// NewService creates a new audit service.
+// db is used to begin the transactions entries are recorded in.
+func NewService(db *sql.DB, repo Repository) *Service {
+	return &Service{db: db, repo: repo, now: time.Now}
+}
+
+// Transact runs fn in a transaction and records the entry it returns in the same
+// transaction, so a change is committed together with its entry or not at all. A nil
+// entry records nothing. Transactions take the write lock when they begin, so nothing
+// else changes the database while fn runs.
+func (s *Service) Transact(ctx context.Context, fn func(tx *sql.Tx) (*Entry, error)) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return apperrors.NewInternal("failed to begin transaction", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	entry, err := fn(tx)
+	if err != nil {
+		return err
+	}
+	if entry != nil {
+		if err = s.record(ctx, tx, entry); err != nil {
+			return err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return apperrors.NewInternal("failed to commit transaction", err)
+	}
+	return nil
+}
+
+// Record appends an entry to the log in a transaction of its own.
+func (s *Service) Record(ctx context.Context, entry Entry) (*Entry, error) {
+	if err := s.Transact(ctx, func(*sql.Tx) (*Entry, error) { return &entry, nil }); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// record appends an entry to the log in tx, working out the changed fields from Before
+// and After. The entry's ID and time are set here.
+func (s *Service) record(ctx context.Context, tx *sql.Tx, entry *Entry) error {
+	if !ValidAction(entry.Action) {
+		return apperrors.NewValidation("action", "action must be CREATE, UPDATE or DELETE")
+	}
+	changes, err := Diff(entry.Before, entry.After)
+	if err != nil {
+		return apperrors.NewInternal("failed to compare audited values", err)
+	}
+	entry.ID = uuid.New().String()
+	entry.Changes = changes
+	entry.CreatedAt = s.now().UTC()
+	return s.repo.Create(ctx, tx, entry)
+}
+
+// List returns a page of entries matching the filter, newest first, and the total count.
+func (s *Service) List(ctx context.Context, filter Filter, limit, offset int64) ([]Entry, int64, error) {
+	if filter.Action != nil && !ValidAction(*filter.Action) {
+		return nil, 0, apperrors.NewValidation("action", "action must be CREATE, UPDATE or DELETE")
+	}
+	return s.repo.List(ctx, filter, limit, offset)
+}
+
+// ValidAction reports whether action is one of the Action constants.
+func ValidAction(action string) bool {
+	return action == ActionCreate || action == ActionUpdate || action == ActionDelete
+}
+
+// Diff compares two JSON objects field by field and returns the fields whose values
+// differ. A missing or null document counts as an object without fields. Values that are
+// not objects are compared as a whole, under the field name "".
+func Diff(before, after json.RawMessage) (map[string]Change, error) {
+	beforeFields, beforeIsObject, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, afterIsObject, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]Change{}
+	if !beforeIsObject || !afterIsObject {
+		if !equalJSON(before, after) {
+			changes[""] = Change{Before: nullable(before), After: nullable(after)}
+		}
+		return changes, nil
+	}
+	for name, b := range beforeFields {
+		if a, ok := afterFields[name]; !ok || !equalJSON(a, b) {
+			changes[name] = Change{Before: b, After: nullable(afterFields[name])}
+		}
+	}
+	for name, a := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = Change{Before: nullable(nil), After: a}
+		}
+	}
+	return changes, nil
+}
+
+// fields decodes a JSON object into its fields. Empty and null documents are objects
+// without fields.
+func fields(doc json.RawMessage) (map[string]json.RawMessage, bool, error) {
+	trimmed := bytes.TrimSpace(doc)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return map[string]json.RawMessage{}, true, nil
+	}
+	if trimmed[0] != '{' {
+		return nil, false, nil
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &m); err != nil {
+		return nil, false, err
+	}
+	return m, true, nil
+}
+
+// equalJSON compares JSON values ignoring insignificant whitespace.
+func equalJSON(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, nullable(a)) != nil || json.Compact(&cb, nullable(b)) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// nullable returns null for an empty value.
+func nullable(v json.RawMessage) json.RawMessage {
+	if len(bytes.TrimSpace(v)) == 0 {
+		return json.RawMessage("null")
+	}
+	return v
+}
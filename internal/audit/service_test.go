@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waynenilsen/power-pro-v3/internal/database"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+func setupTestService(t *testing.T) (*Service, *sql.DB, func()) {
+	sqlDB, cleanup, err := database.OpenTemp("../../migrations")
+	require.NoError(t, err)
+	return NewService(sqlDB, NewSQLiteRepository(sqlDB)), sqlDB, cleanup
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestDiff(t *testing.T) {
+	changes, err := Diff(
+		json.RawMessage(`{"name": "Squat", "slug": "squat", "parentLiftId": null, "tags": [1, 2]}`),
+		json.RawMessage(`{"name":"Back Squat","slug":"squat","tags":[1,2],"isCompetitionLift":true}`),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Change{
+		"name":              {Before: json.RawMessage(`"Squat"`), After: json.RawMessage(`"Back Squat"`)},
+		"parentLiftId":      {Before: json.RawMessage(`null`), After: json.RawMessage(`null`)},
+		"isCompetitionLift": {Before: json.RawMessage(`null`), After: json.RawMessage(`true`)},
+	}, changes, "whitespace differences are ignored")
+
+	changes, err = Diff(nil, json.RawMessage(`{"id":"a"}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Change{"id": {Before: json.RawMessage(`null`), After: json.RawMessage(`"a"`)}}, changes)
+
+	changes, err = Diff(json.RawMessage(`{"id":"a"}`), json.RawMessage(`null`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Change{"id": {Before: json.RawMessage(`"a"`), After: json.RawMessage(`null`)}}, changes)
+
+	changes, err = Diff(json.RawMessage(`[1]`), json.RawMessage(`[2]`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Change{"": {Before: json.RawMessage(`[1]`), After: json.RawMessage(`[2]`)}}, changes)
+
+	_, err = Diff(json.RawMessage(`{"broken"`), nil)
+	assert.Error(t, err)
+}
+
+func TestRecordAndList(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	times := []time.Time{
+		time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 1, 9, 0, 0, 500, time.UTC),
+		time.Date(2024, 3, 2, 9, 0, 0, 0, time.FixedZone("CST", -6*3600)),
+	}
+	next := 0
+	svc.now = func() time.Time { next++; return times[next-1] }
+
+	_, err := svc.Record(ctx, Entry{ActorID: "admin", Action: "PATCH", EntityType: EntityLift, EntityID: "l1"})
+	assert.True(t, apperrors.IsValidation(err))
+
+	created, err := svc.Record(ctx, Entry{
+		ActorID: "admin", Action: ActionCreate, EntityType: EntityLift, EntityID: "l1",
+		Method: "POST", Path: "/lifts", After: json.RawMessage(`{"id":"l1","name":"Squat"}`),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.Len(t, created.Changes, 2)
+
+	_, err = svc.Record(ctx, Entry{
+		ActorID: "admin", Action: ActionUpdate, EntityType: EntityLift, EntityID: "l1",
+		Method: "PUT", Path: "/lifts/l1",
+		Before: json.RawMessage(`{"id":"l1","name":"Squat"}`), After: json.RawMessage(`{"id":"l1","name":"Back Squat"}`),
+	})
+	require.NoError(t, err)
+	_, err = svc.Record(ctx, Entry{
+		ActorID: "coach", Action: ActionDelete, EntityType: EntityLiftMax, EntityID: "m1", SubjectUserID: strPtr("athlete"),
+		Method: "DELETE", Path: "/lift-maxes/m1", Before: json.RawMessage(`{"id":"m1","userId":"athlete"}`),
+	})
+	require.NoError(t, err)
+
+	entries, total, err := svc.List(ctx, Filter{}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []string{ActionDelete, ActionUpdate, ActionCreate}, []string{entries[0].Action, entries[1].Action, entries[2].Action}, "newest first")
+	assert.Equal(t, created.Changes, entries[2].Changes)
+	assert.Equal(t, times[0], entries[2].CreatedAt)
+	assert.Nil(t, entries[2].Before)
+	assert.Nil(t, entries[0].After)
+	assert.Equal(t, "athlete", *entries[0].SubjectUserID)
+
+	for name, tc := range map[string]struct {
+		filter Filter
+		want   int
+	}{
+		"actor":   {Filter{ActorID: strPtr("admin")}, 2},
+		"action":  {Filter{Action: strPtr(ActionUpdate)}, 1},
+		"entity":  {Filter{EntityType: strPtr(EntityLift), EntityID: strPtr("l1")}, 2},
+		"subject": {Filter{SubjectUserID: strPtr("athlete")}, 1},
+		// The fraction of a second is kept, so the bound falls between the first two entries
+		"from": {Filter{From: &times[1]}, 2},
+		"to":   {Filter{To: &times[1]}, 2},
+	} {
+		_, total, err := svc.List(ctx, tc.filter, 10, 0)
+		require.NoError(t, err, name)
+		assert.Equal(t, int64(tc.want), total, name)
+	}
+
+	page, total, err := svc.List(ctx, Filter{}, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, page, 1)
+	assert.Equal(t, ActionUpdate, page[0].Action)
+
+	_, _, err = svc.List(ctx, Filter{Action: strPtr("PATCH")}, 10, 0)
+	assert.True(t, apperrors.IsValidation(err))
+}
+
+func TestLogIsAppendOnly(t *testing.T) {
+	svc, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+
+	entry, err := svc.Record(context.Background(), Entry{ActorID: "admin", Action: ActionCreate, EntityType: EntityCycle, EntityID: "c1"})
+	require.NoError(t, err)
+
+	_, err = sqlDB.Exec(`UPDATE audit_log SET actor_id = 'someone-else' WHERE id = ?`, entry.ID)
+	assert.Error(t, err)
+	_, err = sqlDB.Exec(`DELETE FROM audit_log WHERE id = ?`, entry.ID)
+	assert.Error(t, err)
+
+	entries, _, err := svc.List(context.Background(), Filter{}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "admin", entries[0].ActorID)
+}
@@ -0,0 +1,467 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+const (
+	// AccessTokenPrefix starts every personal access token, which tells them apart from
+	// session tokens.
+	AccessTokenPrefix = "ppat_"
+	// maxAccessTokenNameLength is the maximum allowed length for a token name.
+	maxAccessTokenNameLength = 100
+	// accessTokenHintLength is how many leading characters of a token are kept so its
+	// owner can recognize it.
+	accessTokenHintLength = 12
+	// lastUsedResolution is how stale a token's last use may get before it is recorded
+	// again, so busy tokens do not write on every request.
+	lastUsedResolution = time.Minute
+)
+
+// Scope names a group of routes a personal access token may use.
+type Scope string
+
+const (
+	// ScopeReadLogs allows reading training data: workouts, logged sets, maxes, records,
+	// bodyweight, analytics, enrollment and the dashboard.
+	ScopeReadLogs Scope = "read:logs"
+	// ScopeWriteSets allows recording training: starting and finishing workouts, logging
+	// and correcting sets, syncing, readiness check-ins and bodyweight entries.
+	ScopeWriteSets Scope = "write:sets"
+	// ScopeWriteMaxes allows changing lift maxes and triggering or reverting progressions.
+	ScopeWriteMaxes Scope = "write:maxes"
+	// ScopeReadPrograms allows reading the catalog: lifts, programs, cycles, weeks, days,
+	// prescriptions, lookups and progressions.
+	ScopeReadPrograms Scope = "read:programs"
+	// ScopeAdminPrograms allows creating, updating and deleting catalog entries the
+	// token's user may manage.
+	ScopeAdminPrograms Scope = "admin:programs"
+)
+
+// AllScopes lists every scope in canonical order.
+var AllScopes = []Scope{
+	ScopeReadLogs,
+	ScopeWriteSets,
+	ScopeWriteMaxes,
+	ScopeReadPrograms,
+	ScopeAdminPrograms,
+}
+
+// AccessToken is a long-lived personal access token. The token itself is never stored.
+type AccessToken struct {
+	ID     string
+	UserID string
+	Name   string
+	// Prefix is the start of the token, for recognizing it in lists.
+	Prefix     string
+	Scopes     []Scope
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// Active reports whether the token can still be used at the given time.
+func (t *AccessToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// Has reports whether the token holds the scope.
+func (t *AccessToken) Has(scope Scope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessTokenRepository defines the interface for access token persistence.
+type AccessTokenRepository interface {
+	Create(ctx context.Context, token *AccessToken, hash string) error
+	Get(ctx context.Context, id string) (*AccessToken, error)
+	GetByHash(ctx context.Context, hash string) (*AccessToken, error)
+	// ListByUser returns a user's tokens, newest first.
+	ListByUser(ctx context.Context, userID string) ([]AccessToken, error)
+	Revoke(ctx context.Context, id string, at time.Time) error
+	// RevokeByUser marks all of a user's unrevoked tokens revoked and returns how many.
+	RevokeByUser(ctx context.Context, userID string, at time.Time) (int64, error)
+	UpdateLastUsed(ctx context.Context, id string, at time.Time) error
+	// WithTx returns a repository that runs its queries in tx.
+	WithTx(tx *sql.Tx) AccessTokenRepository
+}
+
+// AccessTokenService issues, validates and revokes personal access tokens.
+type AccessTokenService struct {
+	userRepo  UserRepository
+	tokenRepo AccessTokenRepository
+	now       func() time.Time
+}
+
+// NewAccessTokenService creates a new access token service.
+func NewAccessTokenService(userRepo UserRepository, tokenRepo AccessTokenRepository) *AccessTokenService {
+	return &AccessTokenService{
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		now:       time.Now,
+	}
+}
+
+// WithTx returns a copy of the service whose token repository runs in tx.
+func (s *AccessTokenService) WithTx(tx *sql.Tx) *AccessTokenService {
+	txService := *s
+	txService.tokenRepo = s.tokenRepo.WithTx(tx)
+	return &txService
+}
+
+// CreateAccessTokenRequest contains the data needed to create an access token.
+type CreateAccessTokenRequest struct {
+	Name   string
+	Scopes []Scope
+	// ExpiresAt is when the token stops working. Nil means it never expires.
+	ExpiresAt *time.Time
+}
+
+// Create issues an access token for the user. The returned token string is the only
+// time the token is available; only its hash is stored.
+func (s *AccessTokenService) Create(ctx context.Context, userID string, req CreateAccessTokenRequest) (*AccessToken, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, "", apperrors.NewValidation("name", "name is required")
+	}
+	if len(name) > maxAccessTokenNameLength {
+		return nil, "", apperrors.NewValidation("name", fmt.Sprintf("name must be at most %d characters", maxAccessTokenNameLength))
+	}
+	scopes, err := validateScopes(req.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	now := s.now().UTC()
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			return nil, "", apperrors.NewValidation("expiresAt", "expiresAt must be in the future")
+		}
+		t := req.ExpiresAt.UTC().Truncate(time.Second)
+		expiresAt = &t
+	}
+
+	secret, err := generateToken()
+	if err != nil {
+		return nil, "", apperrors.NewInternal("failed to generate access token", err)
+	}
+	secret = AccessTokenPrefix + secret
+
+	token := &AccessToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    secret[:accessTokenHintLength],
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}
+	if err := s.tokenRepo.Create(ctx, token, hashToken(secret)); err != nil {
+		return nil, "", err
+	}
+	return token, secret, nil
+}
+
+// Get retrieves an access token by ID.
+func (s *AccessTokenService) Get(ctx context.Context, id string) (*AccessToken, error) {
+	return s.tokenRepo.Get(ctx, id)
+}
+
+// List returns a user's access tokens, newest first, including revoked and expired ones.
+func (s *AccessTokenService) List(ctx context.Context, userID string) ([]AccessToken, error) {
+	return s.tokenRepo.ListByUser(ctx, userID)
+}
+
+// Revoke stops an access token from working. Revoking a revoked token is a no-op.
+func (s *AccessTokenService) Revoke(ctx context.Context, id string) (*AccessToken, error) {
+	token, err := s.tokenRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if token.RevokedAt != nil {
+		return token, nil
+	}
+	now := s.now().UTC()
+	if err := s.tokenRepo.Revoke(ctx, id, now); err != nil {
+		return nil, err
+	}
+	token.RevokedAt = &now
+	return token, nil
+}
+
+// Validate checks that an access token exists and is neither revoked nor expired, and
+// records its use. Returns the token's user (without password hash) and the token.
+func (s *AccessTokenService) Validate(ctx context.Context, secret string) (*User, *AccessToken, error) {
+	if !strings.HasPrefix(secret, AccessTokenPrefix) {
+		return nil, nil, apperrors.NewUnauthorized("invalid access token")
+	}
+
+	token, err := s.tokenRepo.GetByHash(ctx, hashToken(secret))
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, nil, apperrors.NewUnauthorized("invalid access token")
+		}
+		return nil, nil, apperrors.NewInternal("failed to lookup access token", err)
+	}
+	now := s.now().UTC()
+	if token.RevokedAt != nil {
+		return nil, nil, apperrors.NewUnauthorized("access token revoked")
+	}
+	if !token.Active(now) {
+		return nil, nil, apperrors.NewUnauthorized("access token expired")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, nil, apperrors.NewUnauthorized("user not found")
+		}
+		return nil, nil, apperrors.NewInternal("failed to lookup user", err)
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		if err := s.tokenRepo.UpdateLastUsed(ctx, token.ID, now); err != nil {
+			return nil, nil, err
+		}
+		token.LastUsedAt = &now
+	}
+
+	return &User{
+		ID:              user.ID,
+		Email:           user.Email,
+		Name:            user.Name,
+		IsAdmin:         user.IsAdmin,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+	}, token, nil
+}
+
+// validateScopes checks scopes are known and returns them without duplicates, in
+// canonical order. A token needs at least one scope.
+func validateScopes(scopes []Scope) ([]Scope, error) {
+	if len(scopes) == 0 {
+		return nil, apperrors.NewValidation("scopes", "at least one scope is required")
+	}
+	requested := make(map[Scope]bool, len(scopes))
+	for _, sc := range scopes {
+		known := false
+		for _, candidate := range AllScopes {
+			if sc == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, apperrors.NewValidation("scopes", fmt.Sprintf("unknown scope %q", sc))
+		}
+		requested[sc] = true
+	}
+
+	result := make([]Scope, 0, len(requested))
+	for _, sc := range AllScopes {
+		if requested[sc] {
+			result = append(result, sc)
+		}
+	}
+	return result, nil
+}
+
+// hashToken returns the hex SHA-256 of a token. Tokens are long and random, so a
+// fast hash is enough and lets tokens be looked up by hash.
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// SQLiteAccessTokenRepository implements AccessTokenRepository using SQLite.
+type SQLiteAccessTokenRepository struct {
+	db *sql.DB
+	// tx, when set, is the transaction the repository runs in.
+	tx *sql.Tx
+}
+
+// NewSQLiteAccessTokenRepository creates a new SQLite-backed access token repository.
+func NewSQLiteAccessTokenRepository(db *sql.DB) *SQLiteAccessTokenRepository {
+	return &SQLiteAccessTokenRepository{db: db}
+}
+
+// WithTx returns a repository that runs its queries in tx.
+func (r *SQLiteAccessTokenRepository) WithTx(tx *sql.Tx) AccessTokenRepository {
+	return &SQLiteAccessTokenRepository{db: r.db, tx: tx}
+}
+
+// conn returns the repository's transaction, or else its database.
+func (r *SQLiteAccessTokenRepository) conn() db.DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const accessTokenColumns = `id, user_id, name, token_prefix, scopes, expires_at, last_used_at, revoked_at, created_at`
+
+// Create persists a new access token with the hash of its secret.
+func (r *SQLiteAccessTokenRepository) Create(ctx context.Context, token *AccessToken, hash string) error {
+	scopes, err := json.Marshal(token.Scopes)
+	if err != nil {
+		return apperrors.NewInternal("failed to encode scopes", err)
+	}
+	_, err = r.conn().ExecContext(ctx, `
+		INSERT INTO access_tokens (id, user_id, name, token_hash, token_prefix, scopes, expires_at, last_used_at, revoked_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, token.ID, token.UserID, token.Name, hash, token.Prefix, string(scopes),
+		formatNullTime(token.ExpiresAt), formatNullTime(token.LastUsedAt), formatNullTime(token.RevokedAt),
+		token.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return apperrors.NewInternal("failed to create access token", err)
+	}
+	return nil
+}
+
+// Get retrieves an access token by ID.
+func (r *SQLiteAccessTokenRepository) Get(ctx context.Context, id string) (*AccessToken, error) {
+	row := r.conn().QueryRowContext(ctx, `SELECT `+accessTokenColumns+` FROM access_tokens WHERE id = ?`, id)
+	token, err := scanAccessToken(row)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("access token", id)
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve access token", err)
+	}
+	return token, nil
+}
+
+// GetByHash retrieves an access token by the hash of its secret.
+func (r *SQLiteAccessTokenRepository) GetByHash(ctx context.Context, hash string) (*AccessToken, error) {
+	row := r.conn().QueryRowContext(ctx, `SELECT `+accessTokenColumns+` FROM access_tokens WHERE token_hash = ?`, hash)
+	token, err := scanAccessToken(row)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("access token", "")
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve access token", err)
+	}
+	return token, nil
+}
+
+// ListByUser returns a user's tokens, newest first.
+func (r *SQLiteAccessTokenRepository) ListByUser(ctx context.Context, userID string) ([]AccessToken, error) {
+	rows, err := r.conn().QueryContext(ctx, `
+		SELECT `+accessTokenColumns+` FROM access_tokens WHERE user_id = ?
+		ORDER BY created_at DESC, rowid DESC
+	`, userID)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list access tokens", err)
+	}
+	defer rows.Close()
+
+	tokens := []AccessToken{}
+	for rows.Next() {
+		token, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, apperrors.NewInternal("failed to list access tokens", err)
+		}
+		tokens = append(tokens, *token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewInternal("failed to list access tokens", err)
+	}
+	return tokens, nil
+}
+
+// Revoke marks an access token revoked.
+func (r *SQLiteAccessTokenRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	res, err := r.conn().ExecContext(ctx, `
+		UPDATE access_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL
+	`, at.Format(time.RFC3339), id)
+	if err != nil {
+		return apperrors.NewInternal("failed to revoke access token", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NewNotFound("access token", id)
+	}
+	return nil
+}
+
+// RevokeByUser marks all of a user's unrevoked tokens revoked and returns how many.
+func (r *SQLiteAccessTokenRepository) RevokeByUser(ctx context.Context, userID string, at time.Time) (int64, error) {
+	res, err := r.conn().ExecContext(ctx, `
+		UPDATE access_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL
+	`, at.Format(time.RFC3339), userID)
+	if err != nil {
+		return 0, apperrors.NewInternal("failed to revoke access tokens", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// UpdateLastUsed records when an access token was last used.
+func (r *SQLiteAccessTokenRepository) UpdateLastUsed(ctx context.Context, id string, at time.Time) error {
+	if _, err := r.conn().ExecContext(ctx, `
+		UPDATE access_tokens SET last_used_at = ? WHERE id = ?
+	`, at.Format(time.RFC3339), id); err != nil {
+		return apperrors.NewInternal("failed to record access token use", err)
+	}
+	return nil
+}
+
+// accessTokenScanner abstracts *sql.Row and *sql.Rows for scanning.
+type accessTokenScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAccessToken scans a single access token row.
+func scanAccessToken(row accessTokenScanner) (*AccessToken, error) {
+	var token AccessToken
+	var scopes, createdAt string
+	var expiresAt, lastUsedAt, revokedAt sql.NullString
+
+	if err := row.Scan(&token.ID, &token.UserID, &token.Name, &token.Prefix, &scopes,
+		&expiresAt, &lastUsedAt, &revokedAt, &createdAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(scopes), &token.Scopes); err != nil {
+		return nil, err
+	}
+	token.ExpiresAt = parseNullTime(expiresAt)
+	token.LastUsedAt = parseNullTime(lastUsedAt)
+	token.RevokedAt = parseNullTime(revokedAt)
+	token.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+
+	return &token, nil
+}
+
+func formatNullTime(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.UTC().Format(time.RFC3339), Valid: true}
+}
+
+func parseNullTime(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
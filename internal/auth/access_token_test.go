@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+func setupAccessTokenTest(t *testing.T) (*AccessTokenService, *Service, func()) {
+	userRepo, sessionRepo, cleanup := setupTestDB(t)
+	tokenRepo := NewSQLiteAccessTokenRepository(userRepo.db)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, userRepo.Create(context.Background(), &User{
+		ID:           "token-user",
+		Email:        "tokens@example.com",
+		PasswordHash: "hashed-password",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}))
+
+	return NewAccessTokenService(userRepo, tokenRepo), NewService(userRepo, sessionRepo), cleanup
+}
+
+func TestAccessTokenService_Create(t *testing.T) {
+	svc, _, cleanup := setupAccessTokenTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	t.Run("issues a prefixed token with canonical scopes", func(t *testing.T) {
+		token, secret, err := svc.Create(ctx, "token-user", CreateAccessTokenRequest{
+			Name:   "  Export script ",
+			Scopes: []Scope{ScopeWriteSets, ScopeReadLogs, ScopeReadLogs},
+		})
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(secret, AccessTokenPrefix))
+		assert.Equal(t, secret[:accessTokenHintLength], token.Prefix)
+		assert.Equal(t, "Export script", token.Name)
+		assert.Equal(t, []Scope{ScopeReadLogs, ScopeWriteSets}, token.Scopes)
+		assert.Nil(t, token.ExpiresAt)
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		for name, req := range map[string]CreateAccessTokenRequest{
+			"missing name":    {Scopes: []Scope{ScopeReadLogs}},
+			"long name":       {Name: strings.Repeat("x", 101), Scopes: []Scope{ScopeReadLogs}},
+			"no scopes":       {Name: "Script"},
+			"unknown scope":   {Name: "Script", Scopes: []Scope{"delete:everything"}},
+			"past expiration": {Name: "Script", Scopes: []Scope{ScopeReadLogs}, ExpiresAt: &past},
+		} {
+			_, _, err := svc.Create(ctx, "token-user", req)
+			assert.True(t, apperrors.IsValidation(err), "%s: expected validation error, got %v", name, err)
+		}
+	})
+}
+
+func TestAccessTokenService_Validate(t *testing.T) {
+	svc, _, cleanup := setupAccessTokenTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	svc.now = func() time.Time { return now }
+
+	expiresAt := now.Add(time.Hour)
+	token, secret, err := svc.Create(ctx, "token-user", CreateAccessTokenRequest{
+		Name:      "Logger",
+		Scopes:    []Scope{ScopeWriteSets},
+		ExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+
+	t.Run("valid token returns its user and records the use", func(t *testing.T) {
+		user, validated, err := svc.Validate(ctx, secret)
+		require.NoError(t, err)
+		assert.Equal(t, "token-user", user.ID)
+		assert.Empty(t, user.PasswordHash)
+		assert.Equal(t, token.ID, validated.ID)
+
+		stored, err := svc.Get(ctx, token.ID)
+		require.NoError(t, err)
+		require.NotNil(t, stored.LastUsedAt)
+		assert.True(t, stored.LastUsedAt.Equal(now))
+	})
+
+	t.Run("unknown tokens are rejected", func(t *testing.T) {
+		_, _, err := svc.Validate(ctx, AccessTokenPrefix+"unknown")
+		assert.True(t, apperrors.IsUnauthorized(err))
+		_, _, err = svc.Validate(ctx, "not-an-access-token")
+		assert.True(t, apperrors.IsUnauthorized(err))
+	})
+
+	t.Run("expired tokens are rejected", func(t *testing.T) {
+		svc.now = func() time.Time { return expiresAt.Add(time.Second) }
+		defer func() { svc.now = func() time.Time { return now } }()
+
+		_, _, err := svc.Validate(ctx, secret)
+		assert.True(t, apperrors.IsUnauthorized(err))
+	})
+
+	t.Run("revoked tokens are rejected", func(t *testing.T) {
+		revoked, err := svc.Revoke(ctx, token.ID)
+		require.NoError(t, err)
+		require.NotNil(t, revoked.RevokedAt)
+
+		_, _, err = svc.Validate(ctx, secret)
+		assert.True(t, apperrors.IsUnauthorized(err))
+
+		// Revoking again is a no-op
+		_, err = svc.Revoke(ctx, token.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("list includes revoked tokens", func(t *testing.T) {
+		tokens, err := svc.List(ctx, "token-user")
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		assert.False(t, tokens[0].Active(now))
+	})
+}
+
+func TestSessionValidatorAdapter_AccessTokens(t *testing.T) {
+	tokens, svc, cleanup := setupAccessTokenTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, secret, err := tokens.Create(ctx, "token-user", CreateAccessTokenRequest{
+		Name:   "Reader",
+		Scopes: []Scope{ScopeReadLogs, ScopeReadPrograms},
+	})
+	require.NoError(t, err)
+
+	t.Run("access tokens carry their scopes", func(t *testing.T) {
+		adapter := NewSessionValidatorAdapter(svc).WithAccessTokens(tokens)
+		authUser, err := adapter.ValidateSession(ctx, secret)
+		require.NoError(t, err)
+		assert.Equal(t, "token-user", authUser.ID)
+		assert.Equal(t, []string{"read:logs", "read:programs"}, authUser.Scopes)
+	})
+
+	t.Run("sessions have no scope restrictions", func(t *testing.T) {
+		adapter := NewSessionValidatorAdapter(svc).WithAccessTokens(tokens)
+		_, err := svc.Register(ctx, RegisterRequest{Email: "session@example.com", Password: "password123"})
+		require.NoError(t, err)
+		login, err := svc.Login(ctx, LoginRequest{Email: "session@example.com", Password: "password123"})
+		require.NoError(t, err)
+
+		authUser, err := adapter.ValidateSession(ctx, login.Token)
+		require.NoError(t, err)
+		assert.Nil(t, authUser.Scopes)
+	})
+
+	t.Run("adapters without access tokens reject them", func(t *testing.T) {
+		adapter := NewSessionValidatorAdapter(svc)
+		_, err := adapter.ValidateSession(ctx, secret)
+		assert.True(t, apperrors.IsUnauthorized(err))
+	})
+}
@@ -0,0 +1,390 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/mail"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// passwordResetLifetime is how long a password reset token can be used.
+	passwordResetLifetime = time.Hour
+	// emailVerificationLifetime is how long an email verification token can be used.
+	emailVerificationLifetime = 24 * time.Hour
+)
+
+// AccountTokenPurpose names what an account token lets its holder do.
+type AccountTokenPurpose string
+
+const (
+	// PurposePasswordReset tokens set a new password.
+	PurposePasswordReset AccountTokenPurpose = "password_reset"
+	// PurposeEmailVerification tokens verify the address they were mailed to, making it
+	// the user's email if it is not already.
+	PurposeEmailVerification AccountTokenPurpose = "email_verification"
+)
+
+// AccountToken is a single-use token mailed to a user. The token itself is never stored.
+type AccountToken struct {
+	ID      string
+	UserID  string
+	Purpose AccountTokenPurpose
+	// Email is the address the token was mailed to.
+	Email     string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// AccountTokenRepository defines the interface for account token persistence.
+type AccountTokenRepository interface {
+	Create(ctx context.Context, token *AccountToken, hash string) error
+	// Use marks the unused token with the hash and purpose as used and returns it.
+	// Returns a not found error when there is no such unused token.
+	Use(ctx context.Context, purpose AccountTokenPurpose, hash string, at time.Time) (*AccountToken, error)
+	// Invalidate marks the user's unused tokens with the purpose as used.
+	Invalidate(ctx context.Context, userID string, purpose AccountTokenPurpose, at time.Time) error
+}
+
+// CredentialRepository updates the credentials of user accounts.
+type CredentialRepository interface {
+	UpdatePassword(ctx context.Context, userID, passwordHash string, at time.Time) error
+	// SetVerifiedEmail sets the user's email and marks it verified.
+	SetVerifiedEmail(ctx context.Context, userID, email string, at time.Time) error
+}
+
+// AccountConfig configures the messages the account service sends.
+type AccountConfig struct {
+	// AppURL is the client app that emailed links open, such as https://app.example.com.
+	// Links go to {AppURL}/reset-password?token=... and {AppURL}/verify-email?token=...
+	// Empty means messages hold only the token.
+	AppURL string
+}
+
+// AccountService resets passwords and verifies and changes email addresses with
+// single-use tokens it mails to users.
+type AccountService struct {
+	service      *Service
+	credentials  CredentialRepository
+	tokens       AccountTokenRepository
+	accessTokens AccessTokenRepository
+	mailer       mail.Mailer
+	config       AccountConfig
+	now          func() time.Time
+}
+
+// NewAccountService creates a new account service. Password resets revoke the user's
+// personal access tokens in accessTokens.
+func NewAccountService(service *Service, credentials CredentialRepository, tokens AccountTokenRepository, accessTokens AccessTokenRepository, mailer mail.Mailer, config AccountConfig) *AccountService {
+	config.AppURL = strings.TrimSuffix(config.AppURL, "/")
+	return &AccountService{
+		service:      service,
+		credentials:  credentials,
+		tokens:       tokens,
+		accessTokens: accessTokens,
+		mailer:       mailer,
+		config:       config,
+		now:          time.Now,
+	}
+}
+
+// RequestPasswordReset mails a password reset token to the user with the email, replacing
+// any earlier one. It succeeds whether or not there is such a user, to prevent user
+// enumeration.
+func (s *AccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.service.FindUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	token, err := s.issue(ctx, user.ID, PurposePasswordReset, user.Email, passwordResetLifetime)
+	if err != nil {
+		return err
+	}
+	return s.send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your PowerPro password",
+		Body: "Someone asked to reset the password for your PowerPro account. If it was you, " +
+			"set a new password within the next hour:\n\n" + s.link("reset-password", token) +
+			"\n\nIf it was not you, ignore this message; your password has not changed.",
+	})
+}
+
+// ResetPassword sets a new password with a password reset token, ends all of the user's
+// sessions and revokes their personal access tokens, any of which may be in the hands of
+// whoever the user is locking out. The token also verifies the email it was mailed to.
+func (s *AccountService) ResetPassword(ctx context.Context, token, password string) error {
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+	now := s.now().UTC()
+	user, used, err := s.use(ctx, PurposePasswordReset, token, now)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(used.Email, user.Email) {
+		// The email changed since the token was mailed
+		return invalidAccountToken()
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
+	if err != nil {
+		return apperrors.NewInternal("failed to hash password", err)
+	}
+	if err := s.credentials.UpdatePassword(ctx, user.ID, string(hash), now); err != nil {
+		return apperrors.NewInternal("failed to update password", err)
+	}
+	if user.EmailVerifiedAt == nil {
+		if err := s.credentials.SetVerifiedEmail(ctx, user.ID, user.Email, now); err != nil {
+			return apperrors.NewInternal("failed to verify email", err)
+		}
+	}
+	if err := s.tokens.Invalidate(ctx, user.ID, PurposePasswordReset, now); err != nil {
+		return apperrors.NewInternal("failed to invalidate reset tokens", err)
+	}
+	if _, err := s.service.DeleteUserSessions(ctx, user.ID); err != nil {
+		return apperrors.NewInternal("failed to end sessions", err)
+	}
+	if _, err := s.accessTokens.RevokeByUser(ctx, user.ID, now); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SendEmailVerification mails a verification token to the user's current email.
+func (s *AccountService) SendEmailVerification(ctx context.Context, userID string) error {
+	user, err := s.service.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return apperrors.NewConflict("email already verified")
+	}
+	return s.sendVerification(ctx, user.ID, user.Email)
+}
+
+// ChangeEmail mails a verification token to a new address. The user's email changes
+// when the token is used. Users with a password must confirm it.
+func (s *AccountService) ChangeEmail(ctx context.Context, userID, email, password string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if err := validateEmail(email); err != nil {
+		return err
+	}
+	user, err := s.service.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.PasswordHash != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+			return apperrors.NewValidation("password", "password is incorrect")
+		}
+	}
+	if strings.EqualFold(email, user.Email) {
+		return apperrors.NewValidation("email", "email is unchanged")
+	}
+	exists, err := s.service.userRepo.EmailExists(ctx, email)
+	if err != nil {
+		return apperrors.NewInternal("failed to check email availability", err)
+	}
+	if exists {
+		return apperrors.NewConflict("email already registered")
+	}
+	return s.sendVerification(ctx, user.ID, email)
+}
+
+// VerifyEmail uses an email verification token, making the address it was mailed to the
+// user's verified email. Returns the updated user (without password hash).
+func (s *AccountService) VerifyEmail(ctx context.Context, token string) (*User, error) {
+	now := s.now().UTC()
+	user, used, err := s.use(ctx, PurposeEmailVerification, token, now)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(used.Email, user.Email) {
+		exists, err := s.service.userRepo.EmailExists(ctx, used.Email)
+		if err != nil {
+			return nil, apperrors.NewInternal("failed to check email availability", err)
+		}
+		if exists {
+			return nil, apperrors.NewConflict("email already registered")
+		}
+	}
+	if err := s.credentials.SetVerifiedEmail(ctx, user.ID, used.Email, now); err != nil {
+		return nil, apperrors.NewInternal("failed to verify email", err)
+	}
+	if err := s.tokens.Invalidate(ctx, user.ID, PurposeEmailVerification, now); err != nil {
+		return nil, apperrors.NewInternal("failed to invalidate verification tokens", err)
+	}
+
+	return &User{
+		ID:              user.ID,
+		Email:           used.Email,
+		Name:            user.Name,
+		IsAdmin:         user.IsAdmin,
+		EmailVerifiedAt: &now,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       now,
+	}, nil
+}
+
+func (s *AccountService) sendVerification(ctx context.Context, userID, email string) error {
+	token, err := s.issue(ctx, userID, PurposeEmailVerification, email, emailVerificationLifetime)
+	if err != nil {
+		return err
+	}
+	return s.send(ctx, mail.Message{
+		To:      email,
+		Subject: "Verify your PowerPro email address",
+		Body: "Confirm that this is the email address for your PowerPro account within the " +
+			"next 24 hours:\n\n" + s.link("verify-email", token) +
+			"\n\nIf you did not ask for this, ignore this message.",
+	})
+}
+
+// issue replaces the user's unused tokens with the purpose with a new one and returns it.
+func (s *AccountService) issue(ctx context.Context, userID string, purpose AccountTokenPurpose, email string, lifetime time.Duration) (string, error) {
+	now := s.now().UTC()
+	if err := s.tokens.Invalidate(ctx, userID, purpose, now); err != nil {
+		return "", apperrors.NewInternal("failed to invalidate account tokens", err)
+	}
+	secret, err := generateToken()
+	if err != nil {
+		return "", apperrors.NewInternal("failed to generate account token", err)
+	}
+	token := &AccountToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Purpose:   purpose,
+		Email:     email,
+		ExpiresAt: now.Add(lifetime),
+		CreatedAt: now,
+	}
+	if err := s.tokens.Create(ctx, token, hashToken(secret)); err != nil {
+		return "", apperrors.NewInternal("failed to store account token", err)
+	}
+	return secret, nil
+}
+
+// use spends a token and returns it with its user. Unknown, used and expired tokens all
+// get the same error.
+func (s *AccountService) use(ctx context.Context, purpose AccountTokenPurpose, secret string, now time.Time) (*User, *AccountToken, error) {
+	if secret == "" {
+		return nil, nil, apperrors.NewValidation("token", "token is required")
+	}
+	token, err := s.tokens.Use(ctx, purpose, hashToken(secret), now)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, nil, invalidAccountToken()
+		}
+		return nil, nil, apperrors.NewInternal("failed to lookup account token", err)
+	}
+	if !now.Before(token.ExpiresAt) {
+		return nil, nil, invalidAccountToken()
+	}
+	user, err := s.service.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, nil, invalidAccountToken()
+		}
+		return nil, nil, apperrors.NewInternal("failed to lookup user", err)
+	}
+	return user, token, nil
+}
+
+func (s *AccountService) send(ctx context.Context, msg mail.Message) error {
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return apperrors.NewInternal("failed to send email", err)
+	}
+	return nil
+}
+
+// link returns the app link for a token, or the token itself when no app is configured.
+func (s *AccountService) link(path, token string) string {
+	if s.config.AppURL == "" {
+		return token
+	}
+	return fmt.Sprintf("%s/%s?token=%s", s.config.AppURL, path, url.QueryEscape(token))
+}
+
+func invalidAccountToken() error {
+	return apperrors.NewValidation("token", "token is invalid or expired")
+}
+
+// UpdatePassword sets a user's password hash.
+func (r *SQLiteUserRepository) UpdatePassword(ctx context.Context, userID, passwordHash string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users SET password_hash = ?, updated_at = ? WHERE id = ?
+	`, passwordHash, at.UTC().Format(time.RFC3339), userID)
+	return err
+}
+
+// SetVerifiedEmail sets a user's email and marks it verified.
+func (r *SQLiteUserRepository) SetVerifiedEmail(ctx context.Context, userID, email string, at time.Time) error {
+	stamp := at.UTC().Format(time.RFC3339)
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users SET email = ?, email_verified_at = ?, updated_at = ? WHERE id = ?
+	`, email, stamp, stamp, userID)
+	return err
+}
+
+// SQLiteAccountTokenRepository implements AccountTokenRepository using SQLite.
+type SQLiteAccountTokenRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteAccountTokenRepository creates a new SQLite-backed account token repository.
+func NewSQLiteAccountTokenRepository(db *sql.DB) *SQLiteAccountTokenRepository {
+	return &SQLiteAccountTokenRepository{db: db}
+}
+
+// Create persists a new account token with the hash of its secret.
+func (r *SQLiteAccountTokenRepository) Create(ctx context.Context, token *AccountToken, hash string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO account_tokens (id, user_id, purpose, token_hash, email, expires_at, used_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, token.ID, token.UserID, string(token.Purpose), hash, token.Email,
+		token.ExpiresAt.UTC().Format(time.RFC3339), formatNullTime(token.UsedAt), token.CreatedAt.UTC().Format(time.RFC3339))
+	return err
+}
+
+// Use marks an unused token as used and returns it.
+func (r *SQLiteAccountTokenRepository) Use(ctx context.Context, purpose AccountTokenPurpose, hash string, at time.Time) (*AccountToken, error) {
+	var token AccountToken
+	var purposeStr, expiresAt, usedAt, createdAt string
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE account_tokens SET used_at = ?
+		WHERE token_hash = ? AND purpose = ? AND used_at IS NULL
+		RETURNING id, user_id, purpose, email, expires_at, used_at, created_at
+	`, at.UTC().Format(time.RFC3339), hash, string(purpose)).Scan(
+		&token.ID, &token.UserID, &purposeStr, &token.Email, &expiresAt, &usedAt, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("account token", "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	token.Purpose = AccountTokenPurpose(purposeStr)
+	token.ExpiresAt, _ = time.Parse(time.RFC3339, expiresAt)
+	token.UsedAt = parseNullTime(sql.NullString{String: usedAt, Valid: true})
+	token.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &token, nil
+}
+
+// Invalidate marks a user's unused tokens with the purpose as used.
+func (r *SQLiteAccountTokenRepository) Invalidate(ctx context.Context, userID string, purpose AccountTokenPurpose, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE account_tokens SET used_at = ? WHERE user_id = ? AND purpose = ? AND used_at IS NULL
+	`, at.UTC().Format(time.RFC3339), userID, string(purpose))
+	return err
+}
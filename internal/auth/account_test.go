@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/mail"
+)
+
+var linkPattern = regexp.MustCompile(`https://app\.powerpro\.test/[a-z-]+\?token=\S+`)
+
+func setupAccountTest(t *testing.T) (*AccountService, *Service, *mail.MemoryMailer, func()) {
+	userRepo, sessionRepo, cleanup := setupTestDB(t)
+	svc := NewService(userRepo, sessionRepo)
+	mailer := mail.NewMemoryMailer()
+	accounts := NewAccountService(svc, userRepo, NewSQLiteAccountTokenRepository(userRepo.db),
+		NewSQLiteAccessTokenRepository(userRepo.db), mailer, AccountConfig{
+			AppURL: "https://app.powerpro.test/",
+		})
+
+	_, err := svc.Register(context.Background(), RegisterRequest{Email: "lifter@example.com", Password: "old-password"})
+	require.NoError(t, err)
+	return accounts, svc, mailer, cleanup
+}
+
+// mailedToken returns the token in the link of the last message to the address.
+func mailedToken(t *testing.T, mailer *mail.MemoryMailer, to string) string {
+	t.Helper()
+	msg, ok := mailer.Last(to)
+	require.True(t, ok, "no message to %s", to)
+	link := linkPattern.FindString(msg.Body)
+	require.NotEmpty(t, link, "no link in %q", msg.Body)
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	return parsed.Query().Get("token")
+}
+
+func TestAccountService_PasswordReset(t *testing.T) {
+	accounts, svc, mailer, cleanup := setupAccountTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	session, err := svc.Login(ctx, LoginRequest{Email: "lifter@example.com", Password: "old-password"})
+	require.NoError(t, err)
+	accessTokens := NewAccessTokenService(svc.userRepo, accounts.accessTokens)
+	_, accessToken, err := accessTokens.Create(ctx, session.User.ID, CreateAccessTokenRequest{
+		Name: "Script", Scopes: []Scope{ScopeReadLogs},
+	})
+	require.NoError(t, err)
+
+	t.Run("unknown emails succeed without sending mail", func(t *testing.T) {
+		require.NoError(t, accounts.RequestPasswordReset(ctx, "nobody@example.com"))
+		assert.Empty(t, mailer.Messages())
+	})
+
+	t.Run("reset sets the password, ends sessions and revokes access tokens", func(t *testing.T) {
+		require.NoError(t, accounts.RequestPasswordReset(ctx, " Lifter@Example.com "))
+		msg, _ := mailer.Last("lifter@example.com")
+		assert.Contains(t, msg.Body, "https://app.powerpro.test/reset-password?token=")
+		token := mailedToken(t, mailer, "lifter@example.com")
+
+		err := accounts.ResetPassword(ctx, token, "short")
+		assert.True(t, apperrors.IsValidation(err))
+
+		require.NoError(t, accounts.ResetPassword(ctx, token, "new-password"))
+
+		_, err = svc.ValidateSession(ctx, session.Token)
+		assert.True(t, apperrors.IsUnauthorized(err), "old session should end")
+		_, _, err = accessTokens.Validate(ctx, accessToken)
+		assert.True(t, apperrors.IsUnauthorized(err), "access tokens should be revoked")
+		_, err = svc.Login(ctx, LoginRequest{Email: "lifter@example.com", Password: "old-password"})
+		assert.True(t, apperrors.IsUnauthorized(err))
+		result, err := svc.Login(ctx, LoginRequest{Email: "lifter@example.com", Password: "new-password"})
+		require.NoError(t, err)
+		assert.NotNil(t, result.User.EmailVerifiedAt, "resetting proves the mailbox")
+
+		err = accounts.ResetPassword(ctx, token, "another-password")
+		assert.True(t, apperrors.IsValidation(err), "tokens are single-use")
+	})
+
+	t.Run("a new request replaces the earlier token", func(t *testing.T) {
+		require.NoError(t, accounts.RequestPasswordReset(ctx, "lifter@example.com"))
+		first := mailedToken(t, mailer, "lifter@example.com")
+		require.NoError(t, accounts.RequestPasswordReset(ctx, "lifter@example.com"))
+		second := mailedToken(t, mailer, "lifter@example.com")
+
+		assert.True(t, apperrors.IsValidation(accounts.ResetPassword(ctx, first, "new-password")))
+		assert.NoError(t, accounts.ResetPassword(ctx, second, "new-password"))
+	})
+
+	t.Run("expired tokens are rejected", func(t *testing.T) {
+		require.NoError(t, accounts.RequestPasswordReset(ctx, "lifter@example.com"))
+		token := mailedToken(t, mailer, "lifter@example.com")
+
+		accounts.now = func() time.Time { return time.Now().Add(passwordResetLifetime + time.Minute) }
+		defer func() { accounts.now = time.Now }()
+		assert.True(t, apperrors.IsValidation(accounts.ResetPassword(ctx, token, "new-password")))
+	})
+
+	t.Run("unknown tokens are rejected", func(t *testing.T) {
+		assert.True(t, apperrors.IsValidation(accounts.ResetPassword(ctx, "not-a-token", "new-password")))
+		assert.True(t, apperrors.IsValidation(accounts.ResetPassword(ctx, "", "new-password")))
+	})
+}
+
+func TestAccountService_EmailVerification(t *testing.T) {
+	accounts, svc, mailer, cleanup := setupAccountTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	user, err := svc.FindUserByEmail(ctx, "lifter@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, user.EmailVerifiedAt)
+
+	t.Run("verifies the current email", func(t *testing.T) {
+		require.NoError(t, accounts.SendEmailVerification(ctx, user.ID))
+		token := mailedToken(t, mailer, "lifter@example.com")
+
+		verified, err := accounts.VerifyEmail(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, "lifter@example.com", verified.Email)
+		assert.NotNil(t, verified.EmailVerifiedAt)
+
+		_, err = accounts.VerifyEmail(ctx, token)
+		assert.True(t, apperrors.IsValidation(err))
+		assert.True(t, apperrors.IsConflict(accounts.SendEmailVerification(ctx, user.ID)))
+	})
+
+	t.Run("password reset tokens do not verify email", func(t *testing.T) {
+		require.NoError(t, accounts.RequestPasswordReset(ctx, "lifter@example.com"))
+		_, err := accounts.VerifyEmail(ctx, mailedToken(t, mailer, "lifter@example.com"))
+		assert.True(t, apperrors.IsValidation(err))
+	})
+
+	t.Run("changes email once the new address is verified", func(t *testing.T) {
+		err := accounts.ChangeEmail(ctx, user.ID, "new@example.com", "wrong-password")
+		assert.True(t, apperrors.IsValidation(err))
+		err = accounts.ChangeEmail(ctx, user.ID, "LIFTER@example.com", "old-password")
+		assert.True(t, apperrors.IsValidation(err))
+
+		require.NoError(t, accounts.ChangeEmail(ctx, user.ID, "New@Example.com", "old-password"))
+		token := mailedToken(t, mailer, "new@example.com")
+
+		unchanged, err := svc.FindUserByEmail(ctx, "lifter@example.com")
+		require.NoError(t, err)
+		require.NotNil(t, unchanged, "email changes only once verified")
+
+		changed, err := accounts.VerifyEmail(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", changed.Email)
+
+		_, err = svc.Login(ctx, LoginRequest{Email: "new@example.com", Password: "old-password"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("cannot take a registered email", func(t *testing.T) {
+		_, err := svc.Register(ctx, RegisterRequest{Email: "taken@example.com", Password: "password123"})
+		require.NoError(t, err)
+		assert.True(t, apperrors.IsConflict(accounts.ChangeEmail(ctx, user.ID, "taken@example.com", "old-password")))
+	})
+
+	t.Run("reset tokens mailed before an email change are rejected", func(t *testing.T) {
+		_, err := svc.Register(ctx, RegisterRequest{Email: "mover@example.com", Password: "password123"})
+		require.NoError(t, err)
+		mover, err := svc.FindUserByEmail(ctx, "mover@example.com")
+		require.NoError(t, err)
+
+		require.NoError(t, accounts.RequestPasswordReset(ctx, "mover@example.com"))
+		resetToken := mailedToken(t, mailer, "mover@example.com")
+		require.NoError(t, accounts.ChangeEmail(ctx, mover.ID, "moved@example.com", "password123"))
+		_, err = accounts.VerifyEmail(ctx, mailedToken(t, mailer, "moved@example.com"))
+		require.NoError(t, err)
+
+		assert.True(t, apperrors.IsValidation(accounts.ResetPassword(ctx, resetToken, "new-password")))
+	})
+}
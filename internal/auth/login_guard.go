@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// LockoutConfig configures progressive lockout after failed logins. Zero attempts
+// disables lockout for that key.
+type LockoutConfig struct {
+	// AccountAttempts is how many failures an email address is allowed before it is locked.
+	AccountAttempts int
+	// IPAttempts is how many failures a client IP is allowed, across all email addresses,
+	// before it is locked.
+	IPAttempts int
+	// Lockout is the first lockout; each further failure doubles it, up to MaxLockout.
+	Lockout    time.Duration
+	MaxLockout time.Duration
+	// ResetAfter is how long after the last failure the count starts over.
+	ResetAfter time.Duration
+}
+
+// DefaultLockoutConfig returns the lockout used unless configured otherwise.
+func DefaultLockoutConfig() LockoutConfig {
+	return LockoutConfig{
+		AccountAttempts: 5,
+		IPAttempts:      20,
+		Lockout:         30 * time.Second,
+		MaxLockout:      15 * time.Minute,
+		ResetAfter:      time.Hour,
+	}
+}
+
+// loginFailures tracks the recent failed logins of one email address or client IP.
+type loginFailures struct {
+	count       int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// LoginGuard locks out email addresses and client IPs with repeated failed logins, for
+// longer with each further failure. Counts live in memory, so they apply per server
+// process and start over on restart.
+type LoginGuard struct {
+	config LockoutConfig
+
+	mu        sync.Mutex
+	failures  map[string]*loginFailures
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewLoginGuard creates a LoginGuard.
+func NewLoginGuard(config LockoutConfig) *LoginGuard {
+	return &LoginGuard{
+		config:   config,
+		failures: make(map[string]*loginFailures),
+		now:      time.Now,
+	}
+}
+
+// Check returns how much longer logins to the email address or from the client IP are
+// locked out, or 0 when they are allowed.
+func (g *LoginGuard) Check(email, ip string) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	var wait time.Duration
+	for _, key := range g.keys(email, ip) {
+		if f, ok := g.failures[key]; ok && f.lockedUntil.After(now) {
+			wait = max(wait, f.lockedUntil.Sub(now))
+		}
+	}
+	return wait
+}
+
+// Fail records a failed login to the email address from the client IP.
+func (g *LoginGuard) Fail(email, ip string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	g.sweep(now)
+	g.fail("account:"+email, g.config.AccountAttempts, now)
+	if ip != "" {
+		g.fail("ip:"+ip, g.config.IPAttempts, now)
+	}
+}
+
+// Succeed clears the failures of the email address. The client IP's failures stay, so
+// logging into one account does not reset attempts at guessing others.
+func (g *LoginGuard) Succeed(email string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.failures, "account:"+email)
+}
+
+func (g *LoginGuard) keys(email, ip string) []string {
+	keys := []string{"account:" + email}
+	if ip != "" {
+		keys = append(keys, "ip:"+ip)
+	}
+	return keys
+}
+
+// fail counts a failure for key and locks it once attempts are used up. Callers hold g.mu.
+func (g *LoginGuard) fail(key string, attempts int, now time.Time) {
+	if attempts <= 0 {
+		return
+	}
+	f, ok := g.failures[key]
+	if !ok || g.expired(f, now) {
+		f = &loginFailures{}
+		g.failures[key] = f
+	}
+	f.count++
+	f.last = now
+
+	if extra := f.count - attempts; extra >= 0 {
+		lockout := g.config.Lockout
+		for i := 0; i < extra && lockout < g.config.MaxLockout; i++ {
+			lockout *= 2
+		}
+		lockout = min(lockout, g.config.MaxLockout)
+		f.lockedUntil = now.Add(lockout)
+	}
+}
+
+// expired reports whether failures are old enough to be forgotten.
+func (g *LoginGuard) expired(f *loginFailures, now time.Time) bool {
+	return !f.lockedUntil.After(now) && now.Sub(f.last) >= g.config.ResetAfter
+}
+
+// sweep drops forgotten failures at most once a minute. Callers hold g.mu.
+func (g *LoginGuard) sweep(now time.Time) {
+	if now.Sub(g.lastSweep) < time.Minute {
+		return
+	}
+	g.lastSweep = now
+	for key, f := range g.failures {
+		if g.expired(f, now) {
+			delete(g.failures, key)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+func newTestLoginGuard() (*LoginGuard, *time.Time) {
+	guard := NewLoginGuard(LockoutConfig{
+		AccountAttempts: 3,
+		IPAttempts:      5,
+		Lockout:         time.Minute,
+		MaxLockout:      5 * time.Minute,
+		ResetAfter:      time.Hour,
+	})
+	clock := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	guard.now = func() time.Time { return clock }
+	return guard, &clock
+}
+
+func TestLoginGuard_ProgressiveLockout(t *testing.T) {
+	guard, clock := newTestLoginGuard()
+
+	guard.Fail("coach@example.com", "")
+	guard.Fail("coach@example.com", "")
+	assert.Zero(t, guard.Check("coach@example.com", ""))
+
+	guard.Fail("coach@example.com", "")
+	assert.Equal(t, time.Minute, guard.Check("coach@example.com", ""))
+	assert.Zero(t, guard.Check("athlete@example.com", ""), "lockouts are per account")
+
+	*clock = clock.Add(time.Minute)
+	assert.Zero(t, guard.Check("coach@example.com", ""))
+	guard.Fail("coach@example.com", "")
+	assert.Equal(t, 2*time.Minute, guard.Check("coach@example.com", ""), "each further failure doubles the lockout")
+
+	for i := 0; i < 5; i++ {
+		guard.Fail("coach@example.com", "")
+	}
+	assert.Equal(t, 5*time.Minute, guard.Check("coach@example.com", ""), "lockouts are capped")
+
+	*clock = clock.Add(5 * time.Minute)
+	guard.Succeed("coach@example.com")
+	guard.Fail("coach@example.com", "")
+	assert.Zero(t, guard.Check("coach@example.com", ""), "a successful login starts the count over")
+}
+
+func TestLoginGuard_IPLockout(t *testing.T) {
+	guard, clock := newTestLoginGuard()
+
+	// Guessing across accounts locks the client IP even though no account is locked
+	emails := []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"}
+	for _, email := range emails {
+		guard.Fail(email, "203.0.113.9")
+	}
+	assert.Equal(t, time.Minute, guard.Check("f@example.com", "203.0.113.9"))
+	assert.Zero(t, guard.Check("f@example.com", "198.51.100.7"))
+
+	guard.Succeed("a@example.com")
+	assert.Equal(t, time.Minute, guard.Check("a@example.com", "203.0.113.9"), "success does not clear the IP")
+
+	*clock = clock.Add(2 * time.Hour)
+	guard.Fail("a@example.com", "203.0.113.9")
+	assert.Zero(t, guard.Check("a@example.com", "203.0.113.9"), "old failures are forgotten")
+	assert.Len(t, guard.failures, 2, "forgotten failures are swept")
+}
+
+func TestServiceLogin_Lockout(t *testing.T) {
+	userRepo, sessionRepo, cleanup := setupTestDB(t)
+	defer cleanup()
+	guard, clock := newTestLoginGuard()
+	svc := NewService(userRepo, sessionRepo).WithLoginGuard(guard)
+	ctx := context.Background()
+
+	_, err := svc.Register(ctx, RegisterRequest{Email: "coach@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = svc.Login(ctx, LoginRequest{Email: "coach@example.com", Password: "wrong-password"})
+		assert.True(t, apperrors.IsUnauthorized(err))
+	}
+
+	_, err = svc.Login(ctx, LoginRequest{Email: "Coach@example.com", Password: "password123"})
+	assert.True(t, apperrors.IsTooManyRequests(err), "the right password is refused while locked")
+	assert.Equal(t, time.Minute, apperrors.GetRetryAfter(err))
+
+	*clock = clock.Add(time.Minute)
+	result, err := svc.Login(ctx, LoginRequest{Email: "coach@example.com", Password: "password123"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Token)
+
+	// Unknown emails count the same, so lockouts do not reveal registered addresses
+	for i := 0; i < 3; i++ {
+		_, err = svc.Login(ctx, LoginRequest{Email: "nobody@example.com", Password: "password123"})
+		assert.True(t, apperrors.IsUnauthorized(err))
+	}
+	_, err = svc.Login(ctx, LoginRequest{Email: "nobody@example.com", Password: "password123"})
+	assert.True(t, apperrors.IsTooManyRequests(err))
+}
@@ -20,10 +20,13 @@ const (
 	bcryptCost = 12
 	// tokenBytes is the number of random bytes for session tokens.
 	tokenBytes = 32
-	// sessionDuration is the lifetime of a session.
+	// sessionDuration is the lifetime of a session, from login or, with sliding
+	// expiration, from its last use.
 	sessionDuration = 7 * 24 * time.Hour
 	// minPasswordLength is the minimum required password length.
 	minPasswordLength = 8
+	// maxNameLength is the maximum stored length of a user's name.
+	maxNameLength = 100
 )
 
 // User represents a user in the system.
@@ -33,8 +36,10 @@ type User struct {
 	Name         string
 	PasswordHash string
 	IsAdmin      bool
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	// EmailVerifiedAt is when the user proved they receive mail at Email. Nil means unverified.
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
 // Session represents an active user session.
@@ -43,6 +48,14 @@ type Session struct {
 	UserID    string
 	Token     string
 	ExpiresAt time.Time
+	// TokenExpiresAt ends Token before the session when session tokens are short-lived,
+	// after which a refresh token gets a new one. Zero means Token lasts as long as the session.
+	TokenExpiresAt time.Time
+	// LastSeenAt is when the session was last used.
+	LastSeenAt time.Time
+	// IPAddress and UserAgent describe the client that last signed in or refreshed the session.
+	IPAddress string
+	UserAgent string
 	CreatedAt time.Time
 }
 
@@ -61,12 +74,26 @@ type SessionRepository interface {
 	DeleteByToken(ctx context.Context, token string) error
 	DeleteByUserID(ctx context.Context, userID string) (int64, error)
 	CleanupExpired(ctx context.Context, before time.Time) (int64, error)
+	GetByID(ctx context.Context, id string) (*Session, error)
+	ListByUserID(ctx context.Context, userID string) ([]Session, error)
+	// Update saves a session's token, expiry, last use and client.
+	Update(ctx context.Context, session *Session) error
+	DeleteByID(ctx context.Context, id string) error
+	// DeleteOthers deletes the user's sessions other than keepID.
+	DeleteOthers(ctx context.Context, userID, keepID string) (int64, error)
+	CreateRefreshToken(ctx context.Context, sessionID, hash string, at time.Time) error
+	// UseRefreshToken marks the refresh token with the hash used and returns its session.
+	// reused is true when it was already used, in which case nothing changes.
+	UseRefreshToken(ctx context.Context, hash string, at time.Time) (sessionID string, reused bool, err error)
 }
 
 // Service provides authentication operations.
 type Service struct {
 	userRepo    UserRepository
 	sessionRepo SessionRepository
+	twoFactor   *TwoFactorService
+	loginGuard  *LoginGuard
+	sessions    SessionConfig
 	now         func() time.Time
 }
 
@@ -79,6 +106,19 @@ func NewService(userRepo UserRepository, sessionRepo SessionRepository) *Service
 	}
 }
 
+// WithTwoFactor makes logins of users who enabled two-factor authentication return a
+// challenge, completed with TwoFactorService.CompleteLogin, instead of a session.
+func (s *Service) WithTwoFactor(twoFactor *TwoFactorService) *Service {
+	s.twoFactor = twoFactor
+	return s
+}
+
+// WithLoginGuard locks out email addresses and client IPs with repeated failed logins.
+func (s *Service) WithLoginGuard(guard *LoginGuard) *Service {
+	s.loginGuard = guard
+	return s
+}
+
 // RegisterRequest contains the data needed to register a new user.
 type RegisterRequest struct {
 	Email    string
@@ -141,12 +181,13 @@ func (s *Service) Register(ctx context.Context, req RegisterRequest) (*RegisterR
 	// Return user without password hash
 	return &RegisterResult{
 		User: &User{
-			ID:        user.ID,
-			Email:     user.Email,
-			Name:      user.Name,
-			IsAdmin:   user.IsAdmin,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
+			ID:              user.ID,
+			Email:           user.Email,
+			Name:            user.Name,
+			IsAdmin:         user.IsAdmin,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
+			EmailVerifiedAt: user.EmailVerifiedAt,
 		},
 	}, nil
 }
@@ -155,12 +196,24 @@ func (s *Service) Register(ctx context.Context, req RegisterRequest) (*RegisterR
 type LoginRequest struct {
 	Email    string
 	Password string
+	// ClientIP is the address the login came from, for locking out repeated failures.
+	ClientIP string
 }
 
-// LoginResult contains the result of a successful login.
+// LoginResult contains the result of a successful login: a session, or a challenge
+// for users with two-factor authentication, in which case User and Token are empty.
 type LoginResult struct {
 	User  *User
 	Token string
+	// ExpiresAt is when Token stops authenticating requests.
+	ExpiresAt time.Time
+	// RefreshToken gets a new Token once it expires, when session tokens are short-lived.
+	// It can be used once, and lasts until RefreshExpiresAt.
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+	// Challenge is completed with a two-factor code before it expires to get a session.
+	Challenge          string
+	ChallengeExpiresAt time.Time
 }
 
 // Login authenticates a user and creates a session.
@@ -169,23 +222,74 @@ func (s *Service) Login(ctx context.Context, req LoginRequest) (*LoginResult, er
 	// Normalize email
 	email := strings.ToLower(strings.TrimSpace(req.Email))
 
+	// Locked out logins are refused before the password is checked, so guessing
+	// cannot continue while locked
+	if s.loginGuard != nil {
+		if wait := s.loginGuard.Check(email, req.ClientIP); wait > 0 {
+			return nil, apperrors.NewTooManyRequests("too many failed login attempts, try again later", wait)
+		}
+	}
+
 	// Lookup user by email
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		if apperrors.IsNotFound(err) {
-			return nil, apperrors.NewUnauthorized("invalid credentials")
+			return nil, s.loginFailed(email, req.ClientIP)
 		}
 		return nil, apperrors.NewInternal("failed to lookup user", err)
 	}
 	if user == nil {
-		return nil, apperrors.NewUnauthorized("invalid credentials")
+		return nil, s.loginFailed(email, req.ClientIP)
 	}
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		return nil, apperrors.NewUnauthorized("invalid credentials")
+		return nil, s.loginFailed(email, req.ClientIP)
+	}
+
+	if s.loginGuard != nil {
+		s.loginGuard.Succeed(email)
+	}
+	return s.beginSession(ctx, user)
+}
+
+// loginFailed records a failed login and returns its error. Unknown email addresses
+// count too, so lockouts do not reveal which addresses are registered.
+func (s *Service) loginFailed(email, clientIP string) error {
+	if s.loginGuard != nil {
+		s.loginGuard.Fail(email, clientIP)
 	}
+	return apperrors.NewUnauthorized("invalid credentials")
+}
 
+// StartSession creates a session, or a two-factor challenge, for a user authenticated by
+// other means, such as single sign-on.
+func (s *Service) StartSession(ctx context.Context, userID string) (*LoginResult, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternal("failed to lookup user", err)
+	}
+	return s.beginSession(ctx, user)
+}
+
+// beginSession starts a session, or a challenge for users with two-factor authentication.
+func (s *Service) beginSession(ctx context.Context, user *User) (*LoginResult, error) {
+	if s.twoFactor != nil {
+		enabled, err := s.twoFactor.Enabled(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if enabled {
+			return s.twoFactor.challenge(ctx, user.ID)
+		}
+	}
+	return s.startSession(ctx, user)
+}
+
+func (s *Service) startSession(ctx context.Context, user *User) (*LoginResult, error) {
 	// Generate session token
 	token, err := generateToken()
 	if err != nil {
@@ -194,12 +298,19 @@ func (s *Service) Login(ctx context.Context, req LoginRequest) (*LoginResult, er
 
 	// Create session
 	now := s.now()
+	client := ClientFromContext(ctx)
 	session := &Session{
-		ID:        uuid.New().String(),
-		UserID:    user.ID,
-		Token:     token,
-		ExpiresAt: now.Add(sessionDuration),
-		CreatedAt: now,
+		ID:         uuid.New().String(),
+		UserID:     user.ID,
+		Token:      token,
+		ExpiresAt:  now.Add(sessionDuration),
+		LastSeenAt: now,
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+		CreatedAt:  now,
+	}
+	if s.sessions.TokenLifetime > 0 {
+		session.TokenExpiresAt = now.Add(s.sessions.TokenLifetime)
 	}
 
 	if err := s.sessionRepo.Create(ctx, session); err != nil {
@@ -207,19 +318,94 @@ func (s *Service) Login(ctx context.Context, req LoginRequest) (*LoginResult, er
 	}
 
 	// Return user (without password hash) and token
-	return &LoginResult{
-		User: &User{
-			ID:        user.ID,
-			Email:     user.Email,
-			Name:      user.Name,
-			IsAdmin:   user.IsAdmin,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-		},
-		Token: token,
+	return s.sessionResult(ctx, session, user)
+}
+
+// FindUserByEmail returns the user with the email, ignoring case, or nil when there is none.
+// The password hash is not returned.
+func (s *Service) FindUserByEmail(ctx context.Context, email string) (*User, error) {
+	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, apperrors.NewInternal("failed to lookup user", err)
+	}
+	return &User{
+		ID:              user.ID,
+		Email:           user.Email,
+		Name:            user.Name,
+		IsAdmin:         user.IsAdmin,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+		EmailVerifiedAt: user.EmailVerifiedAt,
 	}, nil
 }
 
+// ProvisionUser creates an account without a password for a user who signs in through
+// an external identity provider.
+func (s *Service) ProvisionUser(ctx context.Context, email, name string) (*User, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if err := validateEmail(email); err != nil {
+		return nil, err
+	}
+	exists, err := s.userRepo.EmailExists(ctx, email)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to check email availability", err)
+	}
+	if exists {
+		return nil, apperrors.NewConflict("email already registered")
+	}
+
+	name = strings.TrimSpace(name)
+	if runes := []rune(name); len(runes) > maxNameLength {
+		name = string(runes[:maxNameLength])
+	}
+	now := s.now()
+	user := &User{
+		ID:        uuid.New().String(),
+		Email:     email,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, apperrors.NewInternal("failed to create user", err)
+	}
+	return user, nil
+}
+
+// HasPassword reports whether the user can log in with a password.
+func (s *Service) HasPassword(ctx context.Context, userID string) (bool, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return false, err
+		}
+		return false, apperrors.NewInternal("failed to lookup user", err)
+	}
+	return user.PasswordHash != "", nil
+}
+
+// ConfirmPassword checks the password of a user who has one before a sensitive change.
+// Users without a password, who sign in with single sign-on, need not confirm.
+func (s *Service) ConfirmPassword(ctx context.Context, userID, password string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return err
+		}
+		return apperrors.NewInternal("failed to lookup user", err)
+	}
+	if user.PasswordHash == "" {
+		return nil
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return apperrors.NewValidation("password", "password is incorrect")
+	}
+	return nil
+}
+
 // Logout invalidates a session by its token.
 // This operation is idempotent - returns success even if session doesn't exist.
 func (s *Service) Logout(ctx context.Context, token string) error {
@@ -249,9 +435,13 @@ func (s *Service) ValidateSession(ctx context.Context, token string) (*User, err
 	}
 
 	// Check if session is expired
-	if s.now().After(session.ExpiresAt) {
+	now := s.now()
+	if now.After(session.ExpiresAt) {
 		return nil, apperrors.NewUnauthorized("session expired")
 	}
+	if !session.TokenExpiresAt.IsZero() && now.After(session.TokenExpiresAt) {
+		return nil, apperrors.NewUnauthorized("session token expired")
+	}
 
 	// Get user
 	user, err := s.userRepo.GetByID(ctx, session.UserID)
@@ -265,14 +455,17 @@ func (s *Service) ValidateSession(ctx context.Context, token string) (*User, err
 		return nil, apperrors.NewUnauthorized("user not found")
 	}
 
+	s.touchSession(ctx, session, now)
+
 	// Return user without password hash
 	return &User{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		IsAdmin:   user.IsAdmin,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:              user.ID,
+		Email:           user.Email,
+		Name:            user.Name,
+		IsAdmin:         user.IsAdmin,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+		EmailVerifiedAt: user.EmailVerifiedAt,
 	}, nil
 }
 
@@ -346,12 +539,13 @@ func (r *SQLiteUserRepository) GetByID(ctx context.Context, id string) (*User, e
 	var user User
 	var email, name, passwordHash sql.NullString
 	var isAdmin int64
+	var emailVerifiedAt sql.NullString
 	var createdAt, updatedAt string
 
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, email, name, password_hash, is_admin, created_at, updated_at
+		SELECT id, email, name, password_hash, is_admin, email_verified_at, created_at, updated_at
 		FROM users WHERE id = ?
-	`, id).Scan(&user.ID, &email, &name, &passwordHash, &isAdmin, &createdAt, &updatedAt)
+	`, id).Scan(&user.ID, &email, &name, &passwordHash, &isAdmin, &emailVerifiedAt, &createdAt, &updatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, apperrors.NewNotFound("user", id)
@@ -364,6 +558,7 @@ func (r *SQLiteUserRepository) GetByID(ctx context.Context, id string) (*User, e
 	user.Name = name.String
 	user.PasswordHash = passwordHash.String
 	user.IsAdmin = isAdmin == 1
+	user.EmailVerifiedAt = parseNullTime(emailVerifiedAt)
 	user.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	user.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 
@@ -375,12 +570,13 @@ func (r *SQLiteUserRepository) GetByEmail(ctx context.Context, email string) (*U
 	var user User
 	var dbEmail, name, passwordHash sql.NullString
 	var isAdmin int64
+	var emailVerifiedAt sql.NullString
 	var createdAt, updatedAt string
 
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, email, name, password_hash, is_admin, created_at, updated_at
+		SELECT id, email, name, password_hash, is_admin, email_verified_at, created_at, updated_at
 		FROM users WHERE LOWER(email) = LOWER(?)
-	`, email).Scan(&user.ID, &dbEmail, &name, &passwordHash, &isAdmin, &createdAt, &updatedAt)
+	`, email).Scan(&user.ID, &dbEmail, &name, &passwordHash, &isAdmin, &emailVerifiedAt, &createdAt, &updatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, apperrors.NewNotFound("user", email)
@@ -393,6 +589,7 @@ func (r *SQLiteUserRepository) GetByEmail(ctx context.Context, email string) (*U
 	user.Name = name.String
 	user.PasswordHash = passwordHash.String
 	user.IsAdmin = isAdmin == 1
+	user.EmailVerifiedAt = parseNullTime(emailVerifiedAt)
 	user.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	user.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 
@@ -433,33 +630,26 @@ func NewSQLiteSessionRepository(db *sql.DB) *SQLiteSessionRepository {
 // Create persists a new session.
 func (r *SQLiteSessionRepository) Create(ctx context.Context, session *Session) error {
 	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO sessions (id, user_id, token, expires_at, created_at)
-		VALUES (?, ?, ?, ?, ?)
-	`, session.ID, session.UserID, session.Token, session.ExpiresAt.Format(time.RFC3339), session.CreatedAt.Format(time.RFC3339))
+		INSERT INTO sessions (id, user_id, token, expires_at, token_expires_at, last_seen_at, ip_address, user_agent, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, session.ID, session.UserID, session.Token, session.ExpiresAt.Format(time.RFC3339),
+		formatOptionalTime(session.TokenExpiresAt), formatOptionalTime(session.LastSeenAt),
+		session.IPAddress, session.UserAgent, session.CreatedAt.Format(time.RFC3339))
 	return err
 }
 
 // GetByToken retrieves a session by its token.
 func (r *SQLiteSessionRepository) GetByToken(ctx context.Context, token string) (*Session, error) {
-	var session Session
-	var expiresAt, createdAt string
-
-	err := r.db.QueryRowContext(ctx, `
-		SELECT id, user_id, token, expires_at, created_at
-		FROM sessions WHERE token = ?
-	`, token).Scan(&session.ID, &session.UserID, &session.Token, &expiresAt, &createdAt)
-
+	session, err := scanSession(r.db.QueryRowContext(ctx, `
+		SELECT `+sessionColumns+` FROM sessions WHERE token = ?
+	`, token))
 	if err == sql.ErrNoRows {
 		return nil, apperrors.NewNotFound("session", token)
 	}
 	if err != nil {
 		return nil, err
 	}
-
-	session.ExpiresAt, _ = time.Parse(time.RFC3339, expiresAt)
-	session.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
-
-	return &session, nil
+	return session, nil
 }
 
 // DeleteByToken deletes a session by its token.
@@ -125,6 +125,63 @@ func (m *mockSessionRepo) DeleteByUserID(ctx context.Context, userID string) (in
 	return count, nil
 }
 
+func (m *mockSessionRepo) GetByID(ctx context.Context, id string) (*Session, error) {
+	for _, session := range m.sessions {
+		if session.ID == id {
+			return session, nil
+		}
+	}
+	return nil, apperrors.NewNotFound("session", id)
+}
+
+func (m *mockSessionRepo) ListByUserID(ctx context.Context, userID string) ([]Session, error) {
+	var sessions []Session
+	for _, session := range m.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, *session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *mockSessionRepo) Update(ctx context.Context, session *Session) error {
+	for token, existing := range m.sessions {
+		if existing.ID == session.ID {
+			delete(m.sessions, token)
+		}
+	}
+	m.sessions[session.Token] = session
+	return nil
+}
+
+func (m *mockSessionRepo) DeleteByID(ctx context.Context, id string) error {
+	for token, session := range m.sessions {
+		if session.ID == id {
+			delete(m.sessions, token)
+		}
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) DeleteOthers(ctx context.Context, userID, keepID string) (int64, error) {
+	var count int64
+	for token, session := range m.sessions {
+		if session.UserID == userID && session.ID != keepID {
+			delete(m.sessions, token)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockSessionRepo) CreateRefreshToken(ctx context.Context, sessionID, hash string, at time.Time) error {
+	return nil
+}
+
+func (m *mockSessionRepo) UseRefreshToken(ctx context.Context, hash string, at time.Time) (string, bool, error) {
+	return "", false, apperrors.NewNotFound("refresh token", "")
+}
+
 func (m *mockSessionRepo) CleanupExpired(ctx context.Context, before time.Time) (int64, error) {
 	if m.cleanupExpiredErr != nil {
 		return 0, m.cleanupExpiredErr
@@ -0,0 +1,431 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// touchInterval is how often a session in use records its last use, which is also when
+// sliding expiration extends it.
+const touchInterval = time.Minute
+
+// SessionConfig configures session tokens and expiration.
+type SessionConfig struct {
+	// TokenLifetime is how long a session token authenticates requests. Logins and
+	// refreshes also return a single-use refresh token to get the next one. Zero makes
+	// session tokens last as long as the session, without refresh tokens.
+	TokenLifetime time.Duration
+	// Sliding extends a session to sessionDuration from each use, instead of ending it
+	// sessionDuration after login.
+	Sliding bool
+}
+
+// DefaultSessionConfig returns the session configuration used unless configured otherwise.
+func DefaultSessionConfig() SessionConfig {
+	return SessionConfig{
+		TokenLifetime: 15 * time.Minute,
+		Sliding:       true,
+	}
+}
+
+// WithSessionConfig configures session tokens and expiration.
+func (s *Service) WithSessionConfig(config SessionConfig) *Service {
+	s.sessions = config
+	return s
+}
+
+// Client describes the client a session is signed in from.
+type Client struct {
+	IPAddress string
+	UserAgent string
+}
+
+type clientKey struct{}
+
+// WithClient returns a context whose new and refreshed sessions record the client.
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the client recorded by WithClient, or an empty one.
+func ClientFromContext(ctx context.Context) Client {
+	client, _ := ctx.Value(clientKey{}).(Client)
+	return client
+}
+
+// sessionResult returns a LoginResult for the session, issuing a refresh token when
+// session tokens are short-lived.
+func (s *Service) sessionResult(ctx context.Context, session *Session, user *User) (*LoginResult, error) {
+	result := &LoginResult{
+		User: &User{
+			ID:              user.ID,
+			Email:           user.Email,
+			Name:            user.Name,
+			IsAdmin:         user.IsAdmin,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
+			EmailVerifiedAt: user.EmailVerifiedAt,
+		},
+		Token:     session.Token,
+		ExpiresAt: session.ExpiresAt,
+	}
+	if session.TokenExpiresAt.IsZero() {
+		return result, nil
+	}
+
+	refreshToken, err := generateToken()
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to generate refresh token", err)
+	}
+	if err := s.sessionRepo.CreateRefreshToken(ctx, session.ID, hashToken(refreshToken), s.now()); err != nil {
+		return nil, apperrors.NewInternal("failed to create refresh token", err)
+	}
+	result.ExpiresAt = session.TokenExpiresAt
+	result.RefreshToken = refreshToken
+	result.RefreshExpiresAt = session.ExpiresAt
+	return result, nil
+}
+
+// touchSession records that the session was used, at most once per touchInterval, and
+// extends it when expiration is sliding. Failures are logged, since the request is
+// already authenticated.
+func (s *Service) touchSession(ctx context.Context, session *Session, now time.Time) {
+	lastSeen := session.LastSeenAt
+	if lastSeen.IsZero() {
+		lastSeen = session.CreatedAt
+	}
+	if now.Sub(lastSeen) < touchInterval {
+		return
+	}
+
+	session.LastSeenAt = now
+	if s.sessions.Sliding {
+		session.ExpiresAt = now.Add(sessionDuration)
+	}
+	if err := s.sessionRepo.Update(ctx, session); err != nil {
+		log.Printf("AUTH: Failed to record use of session %s: %v", session.ID, err)
+	}
+}
+
+// Refresh exchanges a refresh token for a new session token and refresh token. Each
+// refresh token works once: using one again means it was stolen or leaked, so the whole
+// session is revoked.
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (*LoginResult, error) {
+	if refreshToken == "" {
+		return nil, apperrors.NewUnauthorized("invalid refresh token")
+	}
+	now := s.now()
+
+	sessionID, reused, err := s.sessionRepo.UseRefreshToken(ctx, hashToken(refreshToken), now)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewUnauthorized("invalid refresh token")
+		}
+		return nil, apperrors.NewInternal("failed to use refresh token", err)
+	}
+	if reused {
+		log.Printf("AUTH: Refresh token reused for session %s, revoking the session", sessionID)
+		if err := s.sessionRepo.DeleteByID(ctx, sessionID); err != nil {
+			return nil, apperrors.NewInternal("failed to revoke session", err)
+		}
+		return nil, apperrors.NewUnauthorized("refresh token already used, the session has been revoked")
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewUnauthorized("invalid refresh token")
+		}
+		return nil, apperrors.NewInternal("failed to lookup session", err)
+	}
+	if now.After(session.ExpiresAt) {
+		return nil, apperrors.NewUnauthorized("session expired")
+	}
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewUnauthorized("user not found")
+		}
+		return nil, apperrors.NewInternal("failed to lookup user", err)
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to generate session token", err)
+	}
+	session.Token = token
+	session.TokenExpiresAt = time.Time{}
+	if s.sessions.TokenLifetime > 0 {
+		session.TokenExpiresAt = now.Add(s.sessions.TokenLifetime)
+	}
+	session.LastSeenAt = now
+	if s.sessions.Sliding {
+		session.ExpiresAt = now.Add(sessionDuration)
+	}
+	if client := ClientFromContext(ctx); client != (Client{}) {
+		session.IPAddress = client.IPAddress
+		session.UserAgent = client.UserAgent
+	}
+	if err := s.sessionRepo.Update(ctx, session); err != nil {
+		return nil, apperrors.NewInternal("failed to refresh session", err)
+	}
+	return s.sessionResult(ctx, session, user)
+}
+
+// ListSessions returns the user's active sessions, most recently used first.
+func (s *Service) ListSessions(ctx context.Context, userID string) ([]Session, error) {
+	sessions, err := s.sessionRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list sessions", err)
+	}
+	now := s.now()
+	active := make([]Session, 0, len(sessions))
+	for _, session := range sessions {
+		if !now.After(session.ExpiresAt) {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+// CurrentSession returns the session of a session token.
+func (s *Service) CurrentSession(ctx context.Context, token string) (*Session, error) {
+	session, err := s.sessionRepo.GetByToken(ctx, token)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternal("failed to lookup session", err)
+	}
+	return session, nil
+}
+
+// RevokeSession ends one of the user's sessions. Other users' sessions are reported as
+// not found.
+func (s *Service) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return err
+		}
+		return apperrors.NewInternal("failed to lookup session", err)
+	}
+	if session.UserID != userID {
+		return apperrors.NewNotFound("session", sessionID)
+	}
+	if err := s.sessionRepo.DeleteByID(ctx, sessionID); err != nil {
+		return apperrors.NewInternal("failed to revoke session", err)
+	}
+	return nil
+}
+
+// RevokeOtherSessions ends all of the user's sessions except keepID, returning how many
+// ended.
+func (s *Service) RevokeOtherSessions(ctx context.Context, userID, keepID string) (int64, error) {
+	count, err := s.sessionRepo.DeleteOthers(ctx, userID, keepID)
+	if err != nil {
+		return 0, apperrors.NewInternal("failed to revoke sessions", err)
+	}
+	return count, nil
+}
+
+// RunSessionCleanup removes expired sessions every interval until ctx is cancelled.
+func (s *Service) RunSessionCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.CleanupExpiredSessions(ctx); err != nil {
+				log.Printf("AUTH: Failed to clean up expired sessions: %v", err)
+			}
+		}
+	}
+}
+
+// DeviceName describes the browser and operating system of a user agent, such as
+// "Firefox on Windows", or returns "" when it recognizes neither.
+func DeviceName(userAgent string) string {
+	browsers := []struct{ token, name string }{
+		// Order matters: Edge and Opera user agents also mention Chrome, and Chrome's
+		// mentions Safari
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	}
+	systems := []struct{ token, name string }{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+
+	var browser, system string
+	for _, b := range browsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	for _, sys := range systems {
+		if strings.Contains(userAgent, sys.token) {
+			system = sys.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	default:
+		return system
+	}
+}
+
+// sessionColumns are the columns scanSession reads, in order.
+const sessionColumns = `id, user_id, token, expires_at, token_expires_at, last_seen_at, ip_address, user_agent, created_at`
+
+type sessionScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSession(row sessionScanner) (*Session, error) {
+	var session Session
+	var expiresAt, createdAt string
+	var tokenExpiresAt, lastSeenAt sql.NullString
+	if err := row.Scan(&session.ID, &session.UserID, &session.Token, &expiresAt, &tokenExpiresAt,
+		&lastSeenAt, &session.IPAddress, &session.UserAgent, &createdAt); err != nil {
+		return nil, err
+	}
+	session.ExpiresAt, _ = time.Parse(time.RFC3339, expiresAt)
+	session.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	if t := parseNullTime(tokenExpiresAt); t != nil {
+		session.TokenExpiresAt = *t
+	}
+	if t := parseNullTime(lastSeenAt); t != nil {
+		session.LastSeenAt = *t
+	}
+	return &session, nil
+}
+
+// formatOptionalTime formats a time for storage, with NULL for the zero time.
+func formatOptionalTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+	return formatNullTime(&t)
+}
+
+// GetByID retrieves a session by its ID.
+func (r *SQLiteSessionRepository) GetByID(ctx context.Context, id string) (*Session, error) {
+	session, err := scanSession(r.db.QueryRowContext(ctx, `
+		SELECT `+sessionColumns+` FROM sessions WHERE id = ?
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("session", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// ListByUserID lists a user's sessions, most recently used first.
+func (r *SQLiteSessionRepository) ListByUserID(ctx context.Context, userID string) ([]Session, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+sessionColumns+` FROM sessions WHERE user_id = ?
+		ORDER BY COALESCE(last_seen_at, created_at) DESC, id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, rows.Err()
+}
+
+// Update saves a session's token, expiry, last use and client.
+func (r *SQLiteSessionRepository) Update(ctx context.Context, session *Session) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE sessions
+		SET token = ?, expires_at = ?, token_expires_at = ?, last_seen_at = ?, ip_address = ?, user_agent = ?
+		WHERE id = ?
+	`, session.Token, session.ExpiresAt.Format(time.RFC3339), formatOptionalTime(session.TokenExpiresAt),
+		formatOptionalTime(session.LastSeenAt), session.IPAddress, session.UserAgent, session.ID)
+	return err
+}
+
+// DeleteByID deletes a session and its refresh tokens.
+func (r *SQLiteSessionRepository) DeleteByID(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE id = ?`, id)
+	return err
+}
+
+// DeleteOthers deletes the user's sessions other than keepID.
+// Returns the number of sessions deleted.
+func (r *SQLiteSessionRepository) DeleteOthers(ctx context.Context, userID, keepID string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE user_id = ? AND id != ?`, userID, keepID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// CreateRefreshToken stores the hash of a new refresh token for a session.
+func (r *SQLiteSessionRepository) CreateRefreshToken(ctx context.Context, sessionID, hash string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO refresh_tokens (id, session_id, token_hash, created_at)
+		VALUES (?, ?, ?, ?)
+	`, uuid.New().String(), sessionID, hash, at.Format(time.RFC3339))
+	return err
+}
+
+// UseRefreshToken marks the refresh token with the hash used and returns its session.
+// reused is true when it was already used.
+func (r *SQLiteSessionRepository) UseRefreshToken(ctx context.Context, hash string, at time.Time) (string, bool, error) {
+	var sessionID string
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE refresh_tokens SET used_at = ?
+		WHERE token_hash = ? AND used_at IS NULL
+		RETURNING session_id
+	`, at.Format(time.RFC3339), hash).Scan(&sessionID)
+	if err == nil {
+		return sessionID, false, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", false, err
+	}
+
+	err = r.db.QueryRowContext(ctx, `SELECT session_id FROM refresh_tokens WHERE token_hash = ?`, hash).Scan(&sessionID)
+	if err == sql.ErrNoRows {
+		return "", false, apperrors.NewNotFound("refresh token", "")
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return sessionID, true, nil
+}
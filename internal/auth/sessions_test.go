@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+func setupSessionTest(t *testing.T) (*Service, *time.Time, func()) {
+	userRepo, sessionRepo, cleanup := setupTestDB(t)
+	svc := NewService(userRepo, sessionRepo).WithSessionConfig(DefaultSessionConfig())
+	clock := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return clock }
+
+	_, err := svc.Register(context.Background(), RegisterRequest{Email: "coach@example.com", Password: "password123"})
+	require.NoError(t, err)
+	return svc, &clock, cleanup
+}
+
+func TestRefreshTokens(t *testing.T) {
+	svc, clock, cleanup := setupSessionTest(t)
+	defer cleanup()
+	ctx := WithClient(context.Background(), Client{IPAddress: "203.0.113.9", UserAgent: "curl/8.4.0"})
+
+	login, err := svc.Login(ctx, LoginRequest{Email: "coach@example.com", Password: "password123"})
+	require.NoError(t, err)
+	require.NotEmpty(t, login.RefreshToken)
+	assert.Equal(t, clock.Add(15*time.Minute), login.ExpiresAt)
+	assert.Equal(t, clock.Add(sessionDuration), login.RefreshExpiresAt)
+
+	*clock = clock.Add(16 * time.Minute)
+	_, err = svc.ValidateSession(ctx, login.Token)
+	assert.True(t, apperrors.IsUnauthorized(err), "session tokens are short-lived")
+
+	refreshed, err := svc.Refresh(ctx, login.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, login.Token, refreshed.Token)
+	assert.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+	assert.Equal(t, clock.Add(sessionDuration), refreshed.RefreshExpiresAt, "refreshing extends the session")
+
+	user, err := svc.ValidateSession(ctx, refreshed.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "coach@example.com", user.Email)
+
+	t.Run("reusing a refresh token revokes the session", func(t *testing.T) {
+		_, err := svc.Refresh(ctx, login.RefreshToken)
+		assert.True(t, apperrors.IsUnauthorized(err))
+
+		_, err = svc.ValidateSession(ctx, refreshed.Token)
+		assert.True(t, apperrors.IsUnauthorized(err))
+		_, err = svc.Refresh(ctx, refreshed.RefreshToken)
+		assert.True(t, apperrors.IsUnauthorized(err))
+	})
+
+	t.Run("unknown refresh tokens are rejected", func(t *testing.T) {
+		_, err := svc.Refresh(ctx, "not-a-token")
+		assert.True(t, apperrors.IsUnauthorized(err))
+	})
+}
+
+func TestSlidingExpiration(t *testing.T) {
+	svc, clock, cleanup := setupSessionTest(t)
+	defer cleanup()
+	svc.WithSessionConfig(SessionConfig{Sliding: true})
+	ctx := context.Background()
+
+	login, err := svc.Login(ctx, LoginRequest{Email: "coach@example.com", Password: "password123"})
+	require.NoError(t, err)
+	assert.Empty(t, login.RefreshToken, "tokens last the whole session without a token lifetime")
+
+	// Each use extends the session, so it outlives the first sessionDuration
+	for i := 0; i < 3; i++ {
+		*clock = clock.Add(5 * 24 * time.Hour)
+		_, err = svc.ValidateSession(ctx, login.Token)
+		require.NoError(t, err, "use %d", i+1)
+	}
+
+	*clock = clock.Add(sessionDuration + time.Minute)
+	_, err = svc.ValidateSession(ctx, login.Token)
+	assert.True(t, apperrors.IsUnauthorized(err), "idle sessions still expire")
+
+	removed, err := svc.CleanupExpiredSessions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+}
+
+func TestSessionManagement(t *testing.T) {
+	svc, clock, cleanup := setupSessionTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	user, err := svc.FindUserByEmail(ctx, "coach@example.com")
+	require.NoError(t, err)
+
+	phone, err := svc.Login(WithClient(ctx, Client{IPAddress: "198.51.100.7", UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Safari/604.1"}),
+		LoginRequest{Email: "coach@example.com", Password: "password123"})
+	require.NoError(t, err)
+	*clock = clock.Add(time.Minute)
+	laptop, err := svc.Login(WithClient(ctx, Client{IPAddress: "203.0.113.9", UserAgent: "Mozilla/5.0 (Windows NT 10.0) Firefox/121.0"}),
+		LoginRequest{Email: "coach@example.com", Password: "password123"})
+	require.NoError(t, err)
+	*clock = clock.Add(time.Minute)
+	_, err = svc.Login(ctx, LoginRequest{Email: "coach@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	sessions, err := svc.ListSessions(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+	assert.Equal(t, "203.0.113.9", sessions[1].IPAddress, "most recently used first")
+	assert.Equal(t, "Firefox on Windows", DeviceName(sessions[1].UserAgent))
+
+	current, err := svc.CurrentSession(ctx, laptop.Token)
+	require.NoError(t, err)
+
+	phoneSession, err := svc.CurrentSession(ctx, phone.Token)
+	require.NoError(t, err)
+	assert.True(t, apperrors.IsNotFound(svc.RevokeSession(ctx, "someone-else", phoneSession.ID)))
+	require.NoError(t, svc.RevokeSession(ctx, user.ID, phoneSession.ID))
+	_, err = svc.ValidateSession(ctx, phone.Token)
+	assert.True(t, apperrors.IsUnauthorized(err))
+
+	revoked, err := svc.RevokeOtherSessions(ctx, user.ID, current.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), revoked)
+
+	sessions, err = svc.ListSessions(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, current.ID, sessions[0].ID)
+}
+
+func TestDeviceName(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "Chrome on macOS"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0", "Edge on Windows"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1", "Safari on iOS"},
+		{"Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", "Chrome on Android"},
+		{"curl/8.4.0", "curl"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, DeviceName(tt.userAgent), tt.userAgent)
+	}
+}
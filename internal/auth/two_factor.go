@@ -0,0 +1,650 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+const (
+	// totpPeriod is how long each TOTP code is valid, per RFC 6238.
+	totpPeriod = 30 * time.Second
+	// totpDigits is the length of TOTP codes.
+	totpDigits = 6
+	// totpSkewSteps is how many periods either side of the current one are accepted, for
+	// clock drift between the server and the user's authenticator.
+	totpSkewSteps = 1
+	// totpSecretBytes is the length of TOTP secrets (160 bits, as RFC 4226 recommends).
+	totpSecretBytes = 20
+	// recoveryCodeCount is how many recovery codes are issued at a time.
+	recoveryCodeCount = 10
+	// challengeLifetime is how long a login challenge can be completed.
+	challengeLifetime = 5 * time.Minute
+	// maxChallengeAttempts is how many wrong codes end a login challenge.
+	maxChallengeAttempts = 5
+)
+
+// base32NoPadding encodes TOTP secrets the way authenticator apps expect.
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTP is a user's TOTP authenticator. It is pending until a code confirms the user set
+// up their authenticator app.
+type TOTP struct {
+	UserID      string
+	Secret      string
+	ConfirmedAt *time.Time
+	// LastUsedStep is the time step of the last accepted code, which cannot be used again.
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+// LoginChallenge is a login waiting for a two-factor code. Its token is never stored.
+type LoginChallenge struct {
+	ID        string
+	UserID    string
+	Attempts  int
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// TwoFactorRepository defines the interface for two-factor authentication persistence.
+type TwoFactorRepository interface {
+	GetTOTP(ctx context.Context, userID string) (*TOTP, error)
+	// SaveTOTP creates or replaces a user's TOTP authenticator.
+	SaveTOTP(ctx context.Context, totp *TOTP) error
+	ConfirmTOTP(ctx context.Context, userID string, at time.Time) error
+	// DeleteTOTP removes a user's TOTP authenticator and recovery codes.
+	DeleteTOTP(ctx context.Context, userID string) error
+	// AdvanceStep records an accepted code's time step, reporting false when the step is
+	// not after the last one, which means the code was already used.
+	AdvanceStep(ctx context.Context, userID string, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID string, hashes []string, at time.Time) error
+	// UseRecoveryCode marks an unused code as used, reporting false when there is none.
+	UseRecoveryCode(ctx context.Context, userID, hash string, at time.Time) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID string) (int, error)
+	CreateChallenge(ctx context.Context, challenge *LoginChallenge, hash string) error
+	GetChallenge(ctx context.Context, hash string) (*LoginChallenge, error)
+	// AddChallengeAttempt counts a wrong code and returns the attempts so far.
+	AddChallengeAttempt(ctx context.Context, id string) (int, error)
+	DeleteChallenge(ctx context.Context, id string) error
+	// WithTx returns a repository that runs its queries in tx.
+	WithTx(tx *sql.Tx) TwoFactorRepository
+}
+
+// TwoFactorConfig configures two-factor authentication.
+type TwoFactorConfig struct {
+	// Issuer names the service in authenticator apps.
+	Issuer string
+	// RequireForAdmins withholds admin privileges from admins until they enable
+	// two-factor authentication.
+	RequireForAdmins bool
+}
+
+// TwoFactorService enrolls users in TOTP two-factor authentication and completes the
+// logins that need a code.
+type TwoFactorService struct {
+	service *Service
+	repo    TwoFactorRepository
+	config  TwoFactorConfig
+	now     func() time.Time
+}
+
+// NewTwoFactorService creates a new two-factor service. Pass it to Service.WithTwoFactor
+// so logins ask for codes.
+func NewTwoFactorService(service *Service, repo TwoFactorRepository, config TwoFactorConfig) *TwoFactorService {
+	if config.Issuer == "" {
+		config.Issuer = "PowerPro"
+	}
+	return &TwoFactorService{
+		service: service,
+		repo:    repo,
+		config:  config,
+		now:     time.Now,
+	}
+}
+
+// WithTx returns a copy of the service whose repository runs in tx.
+func (s *TwoFactorService) WithTx(tx *sql.Tx) *TwoFactorService {
+	txService := *s
+	txService.repo = s.repo.WithTx(tx)
+	return &txService
+}
+
+// TwoFactorStatus describes a user's two-factor authentication.
+type TwoFactorStatus struct {
+	Enabled bool
+	// Pending is true while an enrollment waits for its first code.
+	Pending                bool
+	ConfirmedAt            *time.Time
+	RecoveryCodesRemaining int
+	// Required is true for admins when two-factor authentication is required for them.
+	Required bool
+}
+
+// TOTPEnrollment holds what a user needs to set up an authenticator app.
+type TOTPEnrollment struct {
+	Secret string
+	// ProvisioningURI is the otpauth:// URI that authenticator apps read from a QR code.
+	ProvisioningURI string
+}
+
+// Enabled reports whether the user has a confirmed TOTP authenticator.
+func (s *TwoFactorService) Enabled(ctx context.Context, userID string) (bool, error) {
+	totp, err := s.getTOTP(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return totp != nil && totp.ConfirmedAt != nil, nil
+}
+
+// AdminAllowed reports whether an admin may use admin privileges, which they may not
+// before enabling two-factor authentication when it is required for admins.
+func (s *TwoFactorService) AdminAllowed(ctx context.Context, userID string) (bool, error) {
+	if !s.config.RequireForAdmins {
+		return true, nil
+	}
+	return s.Enabled(ctx, userID)
+}
+
+// Status returns the user's two-factor authentication status.
+func (s *TwoFactorService) Status(ctx context.Context, userID string) (*TwoFactorStatus, error) {
+	user, err := s.service.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	totp, err := s.getTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &TwoFactorStatus{Required: user.IsAdmin && s.config.RequireForAdmins}
+	if totp == nil {
+		return status, nil
+	}
+	status.Enabled = totp.ConfirmedAt != nil
+	status.Pending = totp.ConfirmedAt == nil
+	status.ConfirmedAt = totp.ConfirmedAt
+	if status.Enabled {
+		if status.RecoveryCodesRemaining, err = s.repo.CountRecoveryCodes(ctx, userID); err != nil {
+			return nil, apperrors.NewInternal("failed to count recovery codes", err)
+		}
+	}
+	return status, nil
+}
+
+// BeginEnrollment creates a new TOTP secret for the user, replacing a pending one. It
+// takes effect once ConfirmEnrollment accepts a code from it.
+func (s *TwoFactorService) BeginEnrollment(ctx context.Context, userID string) (*TOTPEnrollment, error) {
+	user, err := s.service.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.getTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ConfirmedAt != nil {
+		return nil, apperrors.NewConflict("two-factor authentication is already enabled")
+	}
+
+	raw := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, apperrors.NewInternal("failed to generate TOTP secret", err)
+	}
+	secret := base32NoPadding.EncodeToString(raw)
+	if err := s.repo.SaveTOTP(ctx, &TOTP{UserID: userID, Secret: secret, CreatedAt: s.now().UTC()}); err != nil {
+		return nil, apperrors.NewInternal("failed to save TOTP secret", err)
+	}
+	return &TOTPEnrollment{Secret: secret, ProvisioningURI: s.provisioningURI(user, secret)}, nil
+}
+
+// ConfirmEnrollment enables two-factor authentication with a code from the pending
+// secret and returns the user's recovery codes, which are shown only this once.
+func (s *TwoFactorService) ConfirmEnrollment(ctx context.Context, userID, code string) ([]string, error) {
+	totp, err := s.getTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if totp == nil {
+		return nil, apperrors.NewConflict("no two-factor enrollment in progress")
+	}
+	if totp.ConfirmedAt != nil {
+		return nil, apperrors.NewConflict("two-factor authentication is already enabled")
+	}
+	ok, err := s.checkTOTP(ctx, totp, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, apperrors.NewValidation("code", "code is incorrect")
+	}
+	if err := s.repo.ConfirmTOTP(ctx, userID, s.now().UTC()); err != nil {
+		return nil, apperrors.NewInternal("failed to enable two-factor authentication", err)
+	}
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after checking a code from
+// their authenticator.
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	if err := s.requireCode(ctx, userID, code, false); err != nil {
+		return nil, err
+	}
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+// Disable turns off two-factor authentication after checking a code from the user's
+// authenticator or a recovery code.
+func (s *TwoFactorService) Disable(ctx context.Context, userID, code string) error {
+	if err := s.requireCode(ctx, userID, code, true); err != nil {
+		return err
+	}
+	return s.Reset(ctx, userID)
+}
+
+// Reset turns off a user's two-factor authentication without a code, for admins helping
+// users who lost their authenticator and recovery codes.
+func (s *TwoFactorService) Reset(ctx context.Context, userID string) error {
+	if _, err := s.service.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteTOTP(ctx, userID); err != nil {
+		return apperrors.NewInternal("failed to disable two-factor authentication", err)
+	}
+	return nil
+}
+
+// CompleteLogin finishes a login challenge with a code from the user's authenticator or
+// a recovery code, and starts a session. Too many wrong codes end the challenge.
+func (s *TwoFactorService) CompleteLogin(ctx context.Context, token, code string) (*LoginResult, error) {
+	if token == "" {
+		return nil, apperrors.NewValidation("challenge", "challenge is required")
+	}
+	challenge, err := s.repo.GetChallenge(ctx, hashToken(token))
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewUnauthorized("invalid or expired challenge")
+		}
+		return nil, apperrors.NewInternal("failed to lookup challenge", err)
+	}
+	if !s.now().Before(challenge.ExpiresAt) {
+		_ = s.repo.DeleteChallenge(ctx, challenge.ID)
+		return nil, apperrors.NewUnauthorized("invalid or expired challenge")
+	}
+
+	ok, err := s.verify(ctx, challenge.UserID, code, true)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		attempts, err := s.repo.AddChallengeAttempt(ctx, challenge.ID)
+		if err != nil {
+			return nil, apperrors.NewInternal("failed to record attempt", err)
+		}
+		if attempts >= maxChallengeAttempts {
+			_ = s.repo.DeleteChallenge(ctx, challenge.ID)
+		}
+		return nil, apperrors.NewUnauthorized("invalid two-factor code")
+	}
+	if err := s.repo.DeleteChallenge(ctx, challenge.ID); err != nil {
+		return nil, apperrors.NewInternal("failed to delete challenge", err)
+	}
+
+	user, err := s.service.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewUnauthorized("user not found")
+		}
+		return nil, apperrors.NewInternal("failed to lookup user", err)
+	}
+	return s.service.startSession(ctx, user)
+}
+
+// challenge starts a login challenge for the user.
+func (s *TwoFactorService) challenge(ctx context.Context, userID string) (*LoginResult, error) {
+	token, err := generateToken()
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to generate challenge", err)
+	}
+	now := s.now().UTC()
+	challenge := &LoginChallenge{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		ExpiresAt: now.Add(challengeLifetime),
+		CreatedAt: now,
+	}
+	if err := s.repo.CreateChallenge(ctx, challenge, hashToken(token)); err != nil {
+		return nil, apperrors.NewInternal("failed to create challenge", err)
+	}
+	return &LoginResult{Challenge: token, ChallengeExpiresAt: challenge.ExpiresAt}, nil
+}
+
+// requireCode checks a code for a user with two-factor authentication enabled.
+func (s *TwoFactorService) requireCode(ctx context.Context, userID, code string, allowRecovery bool) error {
+	enabled, err := s.Enabled(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return apperrors.NewConflict("two-factor authentication is not enabled")
+	}
+	ok, err := s.verify(ctx, userID, code, allowRecovery)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperrors.NewValidation("code", "code is incorrect")
+	}
+	return nil
+}
+
+// verify checks a TOTP code, or a recovery code when allowed, for a user with a confirmed
+// authenticator. Accepted codes cannot be used again.
+func (s *TwoFactorService) verify(ctx context.Context, userID, code string, allowRecovery bool) (bool, error) {
+	totp, err := s.getTOTP(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if totp == nil || totp.ConfirmedAt == nil {
+		return false, nil
+	}
+	code = normalizeCode(code)
+	if len(code) == totpDigits {
+		return s.checkTOTP(ctx, totp, code)
+	}
+	if !allowRecovery || code == "" {
+		return false, nil
+	}
+	used, err := s.repo.UseRecoveryCode(ctx, userID, hashToken(code), s.now().UTC())
+	if err != nil {
+		return false, apperrors.NewInternal("failed to check recovery code", err)
+	}
+	return used, nil
+}
+
+// checkTOTP checks a TOTP code against the current time steps and records the step of an
+// accepted code, so each code works once.
+func (s *TwoFactorService) checkTOTP(ctx context.Context, totp *TOTP, code string) (bool, error) {
+	code = normalizeCode(code)
+	secret, err := base32NoPadding.DecodeString(totp.Secret)
+	if err != nil || len(code) != totpDigits {
+		return false, nil
+	}
+	current := s.now().Unix() / int64(totpPeriod/time.Second)
+	for step := current - totpSkewSteps; step <= current+totpSkewSteps; step++ {
+		if step <= totp.LastUsedStep || !hmac.Equal([]byte(totpCode(secret, step)), []byte(code)) {
+			continue
+		}
+		advanced, err := s.repo.AdvanceStep(ctx, totp.UserID, step)
+		if err != nil {
+			return false, apperrors.NewInternal("failed to record TOTP use", err)
+		}
+		return advanced, nil
+	}
+	return false, nil
+}
+
+func (s *TwoFactorService) issueRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, apperrors.NewInternal("failed to generate recovery codes", err)
+		}
+		code := strings.ToLower(base32NoPadding.EncodeToString(raw))
+		codes[i] = code[:4] + "-" + code[4:]
+		hashes[i] = hashToken(code)
+	}
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes, s.now().UTC()); err != nil {
+		return nil, apperrors.NewInternal("failed to save recovery codes", err)
+	}
+	return codes, nil
+}
+
+func (s *TwoFactorService) getTOTP(ctx context.Context, userID string) (*TOTP, error) {
+	totp, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, apperrors.NewInternal("failed to lookup TOTP", err)
+	}
+	return totp, nil
+}
+
+// provisioningURI returns the otpauth:// URI for the user's authenticator app.
+func (s *TwoFactorService) provisioningURI(user *User, secret string) string {
+	account := user.Email
+	if account == "" {
+		account = user.ID
+	}
+	params := url.Values{
+		"secret":    {secret},
+		"issuer":    {s.config.Issuer},
+		"algorithm": {"SHA1"},
+		"digits":    {fmt.Sprint(totpDigits)},
+		"period":    {fmt.Sprint(int(totpPeriod / time.Second))},
+	}
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + s.config.Issuer + ":" + account,
+		RawQuery: params.Encode(),
+	}).String()
+}
+
+// totpCode computes the TOTP code for a time step (RFC 6238 with HMAC-SHA1).
+func totpCode(secret []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// normalizeCode strips the spaces and dashes users type in codes and lowercases them.
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+// SQLiteTwoFactorRepository implements TwoFactorRepository using SQLite.
+type SQLiteTwoFactorRepository struct {
+	db *sql.DB
+	// tx, when set, is the transaction the repository runs in.
+	tx *sql.Tx
+}
+
+// NewSQLiteTwoFactorRepository creates a new SQLite-backed two-factor repository.
+func NewSQLiteTwoFactorRepository(db *sql.DB) *SQLiteTwoFactorRepository {
+	return &SQLiteTwoFactorRepository{db: db}
+}
+
+// WithTx returns a repository that runs its queries in tx.
+func (r *SQLiteTwoFactorRepository) WithTx(tx *sql.Tx) TwoFactorRepository {
+	return &SQLiteTwoFactorRepository{db: r.db, tx: tx}
+}
+
+// conn returns the repository's transaction, or else its database.
+func (r *SQLiteTwoFactorRepository) conn() db.DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// GetTOTP retrieves a user's TOTP authenticator.
+func (r *SQLiteTwoFactorRepository) GetTOTP(ctx context.Context, userID string) (*TOTP, error) {
+	var totp TOTP
+	var confirmedAt sql.NullString
+	var createdAt string
+	err := r.conn().QueryRowContext(ctx, `
+		SELECT user_id, secret, confirmed_at, last_used_step, created_at FROM user_totp WHERE user_id = ?
+	`, userID).Scan(&totp.UserID, &totp.Secret, &confirmedAt, &totp.LastUsedStep, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("totp", userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	totp.ConfirmedAt = parseNullTime(confirmedAt)
+	totp.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &totp, nil
+}
+
+// SaveTOTP creates or replaces a user's TOTP authenticator.
+func (r *SQLiteTwoFactorRepository) SaveTOTP(ctx context.Context, totp *TOTP) error {
+	_, err := r.conn().ExecContext(ctx, `
+		INSERT INTO user_totp (user_id, secret, confirmed_at, last_used_step, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			secret = excluded.secret,
+			confirmed_at = excluded.confirmed_at,
+			last_used_step = excluded.last_used_step,
+			created_at = excluded.created_at
+	`, totp.UserID, totp.Secret, formatNullTime(totp.ConfirmedAt), totp.LastUsedStep, totp.CreatedAt.UTC().Format(time.RFC3339))
+	return err
+}
+
+// ConfirmTOTP marks a user's TOTP authenticator confirmed.
+func (r *SQLiteTwoFactorRepository) ConfirmTOTP(ctx context.Context, userID string, at time.Time) error {
+	_, err := r.conn().ExecContext(ctx, `UPDATE user_totp SET confirmed_at = ? WHERE user_id = ?`, at.UTC().Format(time.RFC3339), userID)
+	return err
+}
+
+// DeleteTOTP removes a user's TOTP authenticator and recovery codes.
+func (r *SQLiteTwoFactorRepository) DeleteTOTP(ctx context.Context, userID string) error {
+	return r.transact(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = ?`, userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = ?`, userID)
+		return err
+	})
+}
+
+// AdvanceStep records an accepted code's time step if it is after the last one.
+func (r *SQLiteTwoFactorRepository) AdvanceStep(ctx context.Context, userID string, step int64) (bool, error) {
+	result, err := r.conn().ExecContext(ctx, `
+		UPDATE user_totp SET last_used_step = ? WHERE user_id = ? AND last_used_step < ?
+	`, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+// ReplaceRecoveryCodes replaces a user's recovery codes with new ones.
+func (r *SQLiteTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, hashes []string, at time.Time) error {
+	return r.transact(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = ?`, userID); err != nil {
+			return err
+		}
+		for _, hash := range hashes {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO recovery_codes (id, user_id, code_hash, created_at) VALUES (?, ?, ?, ?)
+			`, uuid.New().String(), userID, hash, at.UTC().Format(time.RFC3339)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// transact runs fn in the repository's transaction, or else in a new one.
+func (r *SQLiteTwoFactorRepository) transact(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UseRecoveryCode marks an unused recovery code as used.
+func (r *SQLiteTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, hash string, at time.Time) (bool, error) {
+	result, err := r.conn().ExecContext(ctx, `
+		UPDATE recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL
+	`, at.UTC().Format(time.RFC3339), userID, hash)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+// CountRecoveryCodes counts a user's unused recovery codes.
+func (r *SQLiteTwoFactorRepository) CountRecoveryCodes(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.conn().QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM recovery_codes WHERE user_id = ? AND used_at IS NULL
+	`, userID).Scan(&count)
+	return count, err
+}
+
+// CreateChallenge persists a new login challenge with the hash of its token.
+func (r *SQLiteTwoFactorRepository) CreateChallenge(ctx context.Context, challenge *LoginChallenge, hash string) error {
+	_, err := r.conn().ExecContext(ctx, `
+		INSERT INTO login_challenges (id, user_id, token_hash, attempts, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, challenge.ID, challenge.UserID, hash, challenge.Attempts,
+		challenge.ExpiresAt.UTC().Format(time.RFC3339), challenge.CreatedAt.UTC().Format(time.RFC3339))
+	return err
+}
+
+// GetChallenge retrieves a login challenge by the hash of its token.
+func (r *SQLiteTwoFactorRepository) GetChallenge(ctx context.Context, hash string) (*LoginChallenge, error) {
+	var challenge LoginChallenge
+	var expiresAt, createdAt string
+	err := r.conn().QueryRowContext(ctx, `
+		SELECT id, user_id, attempts, expires_at, created_at FROM login_challenges WHERE token_hash = ?
+	`, hash).Scan(&challenge.ID, &challenge.UserID, &challenge.Attempts, &expiresAt, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("login challenge", "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	challenge.ExpiresAt, _ = time.Parse(time.RFC3339, expiresAt)
+	challenge.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &challenge, nil
+}
+
+// AddChallengeAttempt counts a wrong code and returns the attempts so far.
+func (r *SQLiteTwoFactorRepository) AddChallengeAttempt(ctx context.Context, id string) (int, error) {
+	var attempts int
+	err := r.conn().QueryRowContext(ctx, `
+		UPDATE login_challenges SET attempts = attempts + 1 WHERE id = ? RETURNING attempts
+	`, id).Scan(&attempts)
+	if err == sql.ErrNoRows {
+		return maxChallengeAttempts, nil
+	}
+	return attempts, err
+}
+
+// DeleteChallenge deletes a login challenge.
+func (r *SQLiteTwoFactorRepository) DeleteChallenge(ctx context.Context, id string) error {
+	_, err := r.conn().ExecContext(ctx, `DELETE FROM login_challenges WHERE id = ?`, id)
+	return err
+}
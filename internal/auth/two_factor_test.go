@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+func setupTwoFactorTest(t *testing.T, config TwoFactorConfig) (*TwoFactorService, *Service, *time.Time, func()) {
+	userRepo, sessionRepo, cleanup := setupTestDB(t)
+	svc := NewService(userRepo, sessionRepo)
+	twoFactor := NewTwoFactorService(svc, NewSQLiteTwoFactorRepository(userRepo.db), config)
+	svc.WithTwoFactor(twoFactor)
+
+	clock := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	twoFactor.now = func() time.Time { return clock }
+
+	_, err := svc.Register(context.Background(), RegisterRequest{Email: "coach@example.com", Password: "password123"})
+	require.NoError(t, err)
+	return twoFactor, svc, &clock, cleanup
+}
+
+// currentCode returns the TOTP code for the secret at the time.
+func currentCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	raw, err := base32NoPadding.DecodeString(secret)
+	require.NoError(t, err)
+	return totpCode(raw, at.Unix()/30)
+}
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 test vectors for HMAC-SHA1, truncated to six digits
+	secret := []byte("12345678901234567890")
+	assert.Equal(t, "287082", totpCode(secret, 59/30))
+	assert.Equal(t, "081804", totpCode(secret, 1111111109/30))
+	assert.Equal(t, "005924", totpCode(secret, 1234567890/30))
+}
+
+func TestTwoFactorService(t *testing.T) {
+	twoFactor, svc, clock, cleanup := setupTwoFactorTest(t, TwoFactorConfig{})
+	defer cleanup()
+	ctx := context.Background()
+
+	user, err := svc.FindUserByEmail(ctx, "coach@example.com")
+	require.NoError(t, err)
+
+	var enrollment *TOTPEnrollment
+	var recoveryCodes []string
+
+	t.Run("enrollment waits for a confirming code", func(t *testing.T) {
+		enrollment, err = twoFactor.BeginEnrollment(ctx, user.ID)
+		require.NoError(t, err)
+
+		uri, err := url.Parse(enrollment.ProvisioningURI)
+		require.NoError(t, err)
+		assert.Equal(t, "otpauth", uri.Scheme)
+		assert.Equal(t, "totp", uri.Host)
+		assert.Equal(t, "/PowerPro:coach@example.com", uri.Path)
+		assert.Equal(t, enrollment.Secret, uri.Query().Get("secret"))
+
+		// Logins do not need a code until the enrollment is confirmed
+		result, err := svc.Login(ctx, LoginRequest{Email: "coach@example.com", Password: "password123"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.Token)
+
+		_, err = twoFactor.ConfirmEnrollment(ctx, user.ID, "000000")
+		assert.True(t, apperrors.IsValidation(err))
+
+		recoveryCodes, err = twoFactor.ConfirmEnrollment(ctx, user.ID, currentCode(t, enrollment.Secret, *clock))
+		require.NoError(t, err)
+		assert.Len(t, recoveryCodes, recoveryCodeCount)
+
+		status, err := twoFactor.Status(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, status.Enabled)
+		assert.Equal(t, recoveryCodeCount, status.RecoveryCodesRemaining)
+
+		_, err = twoFactor.BeginEnrollment(ctx, user.ID)
+		assert.True(t, apperrors.IsConflict(err))
+	})
+
+	t.Run("login returns a challenge completed with a code", func(t *testing.T) {
+		result, err := svc.Login(ctx, LoginRequest{Email: "coach@example.com", Password: "password123"})
+		require.NoError(t, err)
+		assert.Empty(t, result.Token)
+		assert.Nil(t, result.User)
+		require.NotEmpty(t, result.Challenge)
+
+		// The code that confirmed the enrollment cannot be replayed
+		_, err = twoFactor.CompleteLogin(ctx, result.Challenge, currentCode(t, enrollment.Secret, *clock))
+		assert.True(t, apperrors.IsUnauthorized(err))
+
+		*clock = clock.Add(30 * time.Second)
+		session, err := twoFactor.CompleteLogin(ctx, result.Challenge, currentCode(t, enrollment.Secret, *clock))
+		require.NoError(t, err)
+		assert.NotEmpty(t, session.Token)
+		assert.Equal(t, user.ID, session.User.ID)
+
+		_, err = twoFactor.CompleteLogin(ctx, result.Challenge, currentCode(t, enrollment.Secret, *clock))
+		assert.True(t, apperrors.IsUnauthorized(err), "challenges are single-use")
+	})
+
+	t.Run("recovery codes work once", func(t *testing.T) {
+		result, err := svc.Login(ctx, LoginRequest{Email: "coach@example.com", Password: "password123"})
+		require.NoError(t, err)
+		session, err := twoFactor.CompleteLogin(ctx, result.Challenge, strings.ToUpper(recoveryCodes[0]))
+		require.NoError(t, err)
+		assert.NotEmpty(t, session.Token)
+
+		result, err = svc.Login(ctx, LoginRequest{Email: "coach@example.com", Password: "password123"})
+		require.NoError(t, err)
+		_, err = twoFactor.CompleteLogin(ctx, result.Challenge, recoveryCodes[0])
+		assert.True(t, apperrors.IsUnauthorized(err))
+
+		status, err := twoFactor.Status(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, recoveryCodeCount-1, status.RecoveryCodesRemaining)
+	})
+
+	t.Run("wrong codes end the challenge", func(t *testing.T) {
+		result, err := svc.Login(ctx, LoginRequest{Email: "coach@example.com", Password: "password123"})
+		require.NoError(t, err)
+		for i := 0; i < maxChallengeAttempts; i++ {
+			_, err = twoFactor.CompleteLogin(ctx, result.Challenge, "000000")
+			assert.True(t, apperrors.IsUnauthorized(err))
+		}
+		*clock = clock.Add(30 * time.Second)
+		_, err = twoFactor.CompleteLogin(ctx, result.Challenge, currentCode(t, enrollment.Secret, *clock))
+		assert.True(t, apperrors.IsUnauthorized(err))
+	})
+
+	t.Run("challenges expire", func(t *testing.T) {
+		result, err := svc.Login(ctx, LoginRequest{Email: "coach@example.com", Password: "password123"})
+		require.NoError(t, err)
+		*clock = clock.Add(challengeLifetime + time.Minute)
+		_, err = twoFactor.CompleteLogin(ctx, result.Challenge, currentCode(t, enrollment.Secret, *clock))
+		assert.True(t, apperrors.IsUnauthorized(err))
+	})
+
+	t.Run("regenerating recovery codes replaces them", func(t *testing.T) {
+		*clock = clock.Add(30 * time.Second)
+		codes, err := twoFactor.RegenerateRecoveryCodes(ctx, user.ID, currentCode(t, enrollment.Secret, *clock))
+		require.NoError(t, err)
+		assert.NotEqual(t, recoveryCodes, codes)
+		recoveryCodes = codes
+
+		_, err = twoFactor.RegenerateRecoveryCodes(ctx, user.ID, codes[0])
+		assert.True(t, apperrors.IsValidation(err), "recovery codes cannot regenerate recovery codes")
+	})
+
+	t.Run("disabling needs a code", func(t *testing.T) {
+		assert.True(t, apperrors.IsValidation(twoFactor.Disable(ctx, user.ID, "nope")))
+		require.NoError(t, twoFactor.Disable(ctx, user.ID, recoveryCodes[1]))
+
+		result, err := svc.Login(ctx, LoginRequest{Email: "coach@example.com", Password: "password123"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.Token)
+		assert.True(t, apperrors.IsConflict(twoFactor.Disable(ctx, user.ID, recoveryCodes[2])))
+	})
+}
+
+func TestTwoFactorRequiredForAdmins(t *testing.T) {
+	twoFactor, svc, clock, cleanup := setupTwoFactorTest(t, TwoFactorConfig{RequireForAdmins: true})
+	defer cleanup()
+	ctx := context.Background()
+
+	admin, err := svc.FindUserByEmail(ctx, "coach@example.com")
+	require.NoError(t, err)
+	_, err = svc.userRepo.(*SQLiteUserRepository).db.Exec(`UPDATE users SET is_admin = 1 WHERE id = ?`, admin.ID)
+	require.NoError(t, err)
+
+	adapter := NewSessionValidatorAdapter(svc).WithTwoFactor(twoFactor)
+	login, err := svc.Login(ctx, LoginRequest{Email: "coach@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	authUser, err := adapter.ValidateSession(ctx, login.Token)
+	require.NoError(t, err)
+	assert.False(t, authUser.IsAdmin, "admin privileges wait for two-factor authentication")
+
+	status, err := twoFactor.Status(ctx, admin.ID)
+	require.NoError(t, err)
+	assert.True(t, status.Required)
+
+	enrollment, err := twoFactor.BeginEnrollment(ctx, admin.ID)
+	require.NoError(t, err)
+	_, err = twoFactor.ConfirmEnrollment(ctx, admin.ID, currentCode(t, enrollment.Secret, *clock))
+	require.NoError(t, err)
+
+	authUser, err = adapter.ValidateSession(ctx, login.Token)
+	require.NoError(t, err)
+	assert.True(t, authUser.IsAdmin)
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"strings"
 
 	"github.com/waynenilsen/power-pro-v3/internal/middleware"
 )
@@ -9,7 +10,9 @@ import (
 // SessionValidatorAdapter adapts the auth.Service to implement middleware.SessionValidator.
 // This allows the auth service to be used with the auth middleware.
 type SessionValidatorAdapter struct {
-	service *Service
+	service   *Service
+	tokens    *AccessTokenService
+	twoFactor *TwoFactorService
 }
 
 // NewSessionValidatorAdapter creates a new adapter that wraps the auth service.
@@ -17,18 +20,64 @@ func NewSessionValidatorAdapter(service *Service) *SessionValidatorAdapter {
 	return &SessionValidatorAdapter{service: service}
 }
 
+// WithAccessTokens makes the adapter also accept personal access tokens, which it tells
+// apart from session tokens by their prefix.
+func (a *SessionValidatorAdapter) WithAccessTokens(tokens *AccessTokenService) *SessionValidatorAdapter {
+	a.tokens = tokens
+	return a
+}
+
+// WithTwoFactor makes the adapter withhold admin privileges from admins who have not
+// enabled two-factor authentication, when it is required for admins.
+func (a *SessionValidatorAdapter) WithTwoFactor(twoFactor *TwoFactorService) *SessionValidatorAdapter {
+	a.twoFactor = twoFactor
+	return a
+}
+
 // ValidateSession implements middleware.SessionValidator.
 // It validates the token and returns user information for the middleware context.
+// Users authenticated with an access token carry its scopes.
 func (a *SessionValidatorAdapter) ValidateSession(ctx context.Context, token string) (*middleware.AuthUser, error) {
+	if a.tokens != nil && strings.HasPrefix(token, AccessTokenPrefix) {
+		user, accessToken, err := a.tokens.Validate(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		scopes := make([]string, len(accessToken.Scopes))
+		for i, scope := range accessToken.Scopes {
+			scopes[i] = string(scope)
+		}
+		return a.withAdminCheck(ctx, &middleware.AuthUser{
+			ID:      user.ID,
+			Email:   user.Email,
+			Name:    user.Name,
+			IsAdmin: user.IsAdmin,
+			Scopes:  scopes,
+		})
+	}
+
 	user, err := a.service.ValidateSession(ctx, token)
 	if err != nil {
 		return nil, err
 	}
 
-	return &middleware.AuthUser{
+	return a.withAdminCheck(ctx, &middleware.AuthUser{
 		ID:      user.ID,
 		Email:   user.Email,
 		Name:    user.Name,
 		IsAdmin: user.IsAdmin,
-	}, nil
+	})
+}
+
+// withAdminCheck clears the admin flag of admins who may not use admin privileges yet.
+func (a *SessionValidatorAdapter) withAdminCheck(ctx context.Context, user *middleware.AuthUser) (*middleware.AuthUser, error) {
+	if !user.IsAdmin || a.twoFactor == nil {
+		return user, nil
+	}
+	allowed, err := a.twoFactor.AdminAllowed(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	user.IsAdmin = allowed
+	return user, nil
 }
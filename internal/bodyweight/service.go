@@ -0,0 +1,469 @@
+// Package bodyweight provides bodyweight logging and trend functionality.
+// This package handles bodyweight entries, exponentially smoothed trend weights,
+// and progress toward a user's weight class target.
+package bodyweight
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/strengthscore"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/profile"
+)
+
+const (
+	// maxNotesLength is the maximum allowed length for entry notes.
+	maxNotesLength = 500
+	// trendSmoothing is the smoothing factor for the exponentially weighted trend.
+	// Each new entry contributes 10% to the trend, which damps day-to-day water swings.
+	trendSmoothing = 0.1
+)
+
+// Valid weight units.
+const (
+	WeightUnitLb = "lb"
+	WeightUnitKg = "kg"
+)
+
+// Entry represents a single bodyweight measurement.
+type Entry struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"userId"`
+	Weight     float64   `json:"weight"`
+	Unit       string    `json:"unit"`
+	RecordedAt time.Time `json:"recordedAt"`
+	Notes      *string   `json:"notes"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// WeightKg returns the entry weight in kilograms.
+func (e *Entry) WeightKg() float64 {
+	return strengthscore.ToKg(e.Weight, e.Unit)
+}
+
+// LogEntryRequest represents a request to log a bodyweight entry.
+type LogEntryRequest struct {
+	// Weight is the measured bodyweight. Must be greater than 0.
+	Weight float64
+	// Unit is the unit of Weight ("lb" or "kg"). Empty means use the user's preferred unit.
+	Unit string
+	// RecordedAt is when the measurement was taken. Nil means now.
+	RecordedAt *time.Time
+	// Notes is an optional free-text note.
+	Notes *string
+}
+
+// TrendPoint is a bodyweight entry paired with its smoothed trend weight.
+type TrendPoint struct {
+	RecordedAt time.Time `json:"recordedAt"`
+	Weight     float64   `json:"weight"`
+	Trend      float64   `json:"trend"`
+}
+
+// Summary describes a user's current bodyweight and weight class progress.
+// All weights are expressed in Unit.
+type Summary struct {
+	Unit             string     `json:"unit"`
+	EntryCount       int        `json:"entryCount"`
+	LatestWeight     *float64   `json:"latestWeight"`
+	LatestRecordedAt *time.Time `json:"latestRecordedAt"`
+	TrendWeight      *float64   `json:"trendWeight"`
+	// CurrentWeightClass is the class the trend weight falls in (requires sex on the profile).
+	CurrentWeightClass *string `json:"currentWeightClass"`
+	WeightClassTarget  *string `json:"weightClassTarget"`
+	// WeightClassLimit is the upper limit of the target class (nil for unlimited classes).
+	WeightClassLimit *float64 `json:"weightClassLimit"`
+	// DistanceToLimit is trend weight minus the class limit. Positive means over the limit.
+	DistanceToLimit *float64 `json:"distanceToLimit"`
+}
+
+// Repository defines the interface for bodyweight entry persistence.
+type Repository interface {
+	Create(ctx context.Context, entry *Entry) error
+	GetByID(ctx context.Context, id string) (*Entry, error)
+	// ListByUser returns entries newest first along with the total count.
+	ListByUser(ctx context.Context, userID string, limit, offset int64) ([]Entry, int64, error)
+	// ListByUserChronological returns all entries for a user oldest first.
+	ListByUserChronological(ctx context.Context, userID string) ([]Entry, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// Service provides bodyweight operations.
+type Service struct {
+	repo           Repository
+	profileService *profile.Service
+	now            func() time.Time
+}
+
+// NewService creates a new bodyweight service.
+func NewService(repo Repository, profileService *profile.Service) *Service {
+	return &Service{
+		repo:           repo,
+		profileService: profileService,
+		now:            time.Now,
+	}
+}
+
+// LogEntry records a new bodyweight entry for a user.
+func (s *Service) LogEntry(ctx context.Context, userID string, req LogEntryRequest) (*Entry, error) {
+	if userID == "" {
+		return nil, apperrors.NewBadRequest("user ID is required")
+	}
+
+	if req.Weight <= 0 {
+		return nil, apperrors.NewValidation("weight", "weight must be greater than 0")
+	}
+
+	unit := req.Unit
+	if unit == "" {
+		p, err := s.profileService.GetProfile(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		unit = p.WeightUnit
+	}
+	if unit != WeightUnitLb && unit != WeightUnitKg {
+		return nil, apperrors.NewValidation("unit", "unit must be 'lb' or 'kg'")
+	}
+
+	now := s.now().UTC()
+	recordedAt := now
+	if req.RecordedAt != nil {
+		recordedAt = req.RecordedAt.UTC()
+		if recordedAt.After(now) {
+			return nil, apperrors.NewValidation("recordedAt", "recordedAt cannot be in the future")
+		}
+	}
+
+	var notes *string
+	if req.Notes != nil {
+		trimmed := strings.TrimSpace(*req.Notes)
+		if len(trimmed) > maxNotesLength {
+			return nil, apperrors.NewValidation("notes", "notes must be 500 characters or less")
+		}
+		if trimmed != "" {
+			notes = &trimmed
+		}
+	}
+
+	entry := &Entry{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Weight:     req.Weight,
+		Unit:       unit,
+		RecordedAt: recordedAt,
+		Notes:      notes,
+		CreatedAt:  now,
+	}
+
+	if err := s.repo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// ListEntries returns a page of a user's bodyweight entries, newest first.
+func (s *Service) ListEntries(ctx context.Context, userID string, limit, offset int64) ([]Entry, int64, error) {
+	if userID == "" {
+		return nil, 0, apperrors.NewBadRequest("user ID is required")
+	}
+	return s.repo.ListByUser(ctx, userID, limit, offset)
+}
+
+// DeleteEntry deletes a bodyweight entry belonging to the user.
+func (s *Service) DeleteEntry(ctx context.Context, userID, entryID string) error {
+	entry, err := s.repo.GetByID(ctx, entryID)
+	if err != nil {
+		return err
+	}
+	// Entries belonging to other users are reported as not found to avoid leaking existence
+	if entry.UserID != userID {
+		return apperrors.NewNotFound("bodyweight entry", entryID)
+	}
+	return s.repo.Delete(ctx, entryID)
+}
+
+// ListChronological returns all of a user's entries, oldest first.
+func (s *Service) ListChronological(ctx context.Context, userID string) ([]Entry, error) {
+	return s.repo.ListByUserChronological(ctx, userID)
+}
+
+// GetTrend returns every entry for a user with its smoothed trend weight, oldest first.
+// Weights are converted to the user's preferred weight unit.
+func (s *Service) GetTrend(ctx context.Context, userID string) ([]TrendPoint, error) {
+	p, err := s.profileService.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.ListByUserChronological(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return computeTrend(entries, p.WeightUnit), nil
+}
+
+// GetSummary returns the user's latest bodyweight, trend weight and weight class progress
+// in the user's preferred weight unit.
+func (s *Service) GetSummary(ctx context.Context, userID string) (*Summary, error) {
+	p, err := s.profileService.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.ListByUserChronological(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{
+		Unit:              p.WeightUnit,
+		EntryCount:        len(entries),
+		WeightClassTarget: p.WeightClassTarget,
+	}
+	if len(entries) == 0 {
+		return summary, nil
+	}
+
+	trend := computeTrend(entries, p.WeightUnit)
+	latest := trend[len(trend)-1]
+	summary.LatestWeight = &latest.Weight
+	summary.LatestRecordedAt = &latest.RecordedAt
+	summary.TrendWeight = &latest.Trend
+
+	if p.Sex == nil {
+		return summary, nil
+	}
+	sex := strengthscore.Sex(*p.Sex)
+	trendKg := strengthscore.ToKg(latest.Trend, p.WeightUnit)
+
+	if current, err := strengthscore.ClassForBodyweight(sex, trendKg); err == nil {
+		summary.CurrentWeightClass = &current.Name
+	}
+
+	if p.WeightClassTarget != nil {
+		target, err := strengthscore.LookupWeightClass(sex, *p.WeightClassTarget)
+		if err == nil && !target.Unlimited() {
+			limit := roundWeight(strengthscore.FromKg(target.LimitKg, p.WeightUnit))
+			distance := roundWeight(latest.Trend - limit)
+			summary.WeightClassLimit = &limit
+			summary.DistanceToLimit = &distance
+		}
+	}
+
+	return summary, nil
+}
+
+// WeightKgAt returns the user's bodyweight in kilograms at the given time.
+// It uses the most recent entry on or before the time; if the user has no entry
+// that early, the earliest entry is used as the best available estimate.
+// Returns nil if the user has never logged a bodyweight.
+func (s *Service) WeightKgAt(ctx context.Context, userID string, at time.Time) (*float64, error) {
+	entries, err := s.repo.ListByUserChronological(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return WeightKgAt(entries, at), nil
+}
+
+// WeightKgAt returns the bodyweight in kilograms at the given time from chronologically
+// ordered entries. See Service.WeightKgAt for the selection rules.
+func WeightKgAt(entries []Entry, at time.Time) *float64 {
+	if len(entries) == 0 {
+		return nil
+	}
+	selected := entries[0]
+	for _, e := range entries {
+		if e.RecordedAt.After(at) {
+			break
+		}
+		selected = e
+	}
+	weight := selected.WeightKg()
+	return &weight
+}
+
+// computeTrend calculates an exponentially weighted moving average over chronological entries.
+func computeTrend(entries []Entry, unit string) []TrendPoint {
+	points := make([]TrendPoint, 0, len(entries))
+	var trendKg float64
+	for i, e := range entries {
+		weightKg := e.WeightKg()
+		if i == 0 {
+			trendKg = weightKg
+		} else {
+			trendKg += trendSmoothing * (weightKg - trendKg)
+		}
+		points = append(points, TrendPoint{
+			RecordedAt: e.RecordedAt,
+			Weight:     roundWeight(convertEntryWeight(e, unit)),
+			Trend:      roundWeight(strengthscore.FromKg(trendKg, unit)),
+		})
+	}
+	return points
+}
+
+// convertEntryWeight returns an entry's weight in the given unit without a round trip
+// through kilograms when the units already match.
+func convertEntryWeight(e Entry, unit string) float64 {
+	if e.Unit == unit {
+		return e.Weight
+	}
+	return strengthscore.FromKg(e.WeightKg(), unit)
+}
+
+// roundWeight rounds a weight to one decimal place.
+func roundWeight(w float64) float64 {
+	return math.Round(w*10) / 10
+}
+
+// SQLiteRepository implements Repository using SQLite.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository creates a new SQLite-backed bodyweight repository.
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// Create inserts a new bodyweight entry.
+func (r *SQLiteRepository) Create(ctx context.Context, entry *Entry) error {
+	var notes sql.NullString
+	if entry.Notes != nil {
+		notes = sql.NullString{String: *entry.Notes, Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO bodyweight_entries (id, user_id, weight, unit, recorded_at, notes, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, entry.ID, entry.UserID, entry.Weight, entry.Unit,
+		entry.RecordedAt.Format(time.RFC3339), notes, entry.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return apperrors.NewInternal("failed to create bodyweight entry", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a bodyweight entry by its ID.
+func (r *SQLiteRepository) GetByID(ctx context.Context, id string) (*Entry, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, user_id, weight, unit, recorded_at, notes, created_at
+		FROM bodyweight_entries WHERE id = ?
+	`, id)
+
+	entry, err := scanEntry(row)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("bodyweight entry", id)
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve bodyweight entry", err)
+	}
+	return entry, nil
+}
+
+// ListByUser returns a page of entries for a user, newest first, along with the total count.
+func (r *SQLiteRepository) ListByUser(ctx context.Context, userID string, limit, offset int64) ([]Entry, int64, error) {
+	var total int64
+	if err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM bodyweight_entries WHERE user_id = ?", userID,
+	).Scan(&total); err != nil {
+		return nil, 0, apperrors.NewInternal("failed to count bodyweight entries", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, weight, unit, recorded_at, notes, created_at
+		FROM bodyweight_entries WHERE user_id = ?
+		ORDER BY recorded_at DESC, created_at DESC
+		LIMIT ? OFFSET ?
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list bodyweight entries", err)
+	}
+	defer rows.Close()
+
+	entries, err := scanEntries(rows)
+	if err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list bodyweight entries", err)
+	}
+	return entries, total, nil
+}
+
+// ListByUserChronological returns all entries for a user, oldest first.
+func (r *SQLiteRepository) ListByUserChronological(ctx context.Context, userID string) ([]Entry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, weight, unit, recorded_at, notes, created_at
+		FROM bodyweight_entries WHERE user_id = ?
+		ORDER BY recorded_at ASC, created_at ASC
+	`, userID)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list bodyweight entries", err)
+	}
+	defer rows.Close()
+
+	entries, err := scanEntries(rows)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list bodyweight entries", err)
+	}
+	return entries, nil
+}
+
+// Delete removes a bodyweight entry.
+func (r *SQLiteRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM bodyweight_entries WHERE id = ?", id)
+	if err != nil {
+		return apperrors.NewInternal("failed to delete bodyweight entry", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apperrors.NewInternal("failed to check delete result", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NewNotFound("bodyweight entry", id)
+	}
+	return nil
+}
+
+// rowScanner abstracts *sql.Row and *sql.Rows for scanning.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanEntry scans a single bodyweight entry row.
+func scanEntry(row rowScanner) (*Entry, error) {
+	var entry Entry
+	var notes sql.NullString
+	var recordedAt, createdAt string
+
+	if err := row.Scan(&entry.ID, &entry.UserID, &entry.Weight, &entry.Unit,
+		&recordedAt, &notes, &createdAt); err != nil {
+		return nil, err
+	}
+
+	if notes.Valid {
+		entry.Notes = &notes.String
+	}
+	entry.RecordedAt, _ = time.Parse(time.RFC3339, recordedAt)
+	entry.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+
+	return &entry, nil
+}
+
+// scanEntries scans all rows into entries.
+func scanEntries(rows *sql.Rows) ([]Entry, error) {
+	entries := []Entry{}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,232 @@
+package bodyweight
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waynenilsen/power-pro-v3/internal/database"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/profile"
+)
+
+func setupTestService(t *testing.T) (*Service, *sql.DB, func()) {
+	sqlDB, cleanup, err := database.OpenTemp("../../migrations")
+	require.NoError(t, err)
+
+	profileService := profile.NewService(profile.NewSQLiteProfileRepository(sqlDB))
+	svc := NewService(NewSQLiteRepository(sqlDB), profileService)
+	return svc, sqlDB, cleanup
+}
+
+func createTestUser(t *testing.T, sqlDB *sql.DB, userID, weightUnit string) {
+	_, err := sqlDB.Exec(`
+		INSERT INTO users (id, email, weight_unit, created_at, updated_at)
+		VALUES (?, ?, ?, datetime('now'), datetime('now'))
+	`, userID, userID+"@example.com", weightUnit)
+	require.NoError(t, err)
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestComputeTrend(t *testing.T) {
+	base := time.Date(2024, 1, 1, 7, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Weight: 200, Unit: "lb", RecordedAt: base},
+		{Weight: 210, Unit: "lb", RecordedAt: base.AddDate(0, 0, 1)},
+		{Weight: 190, Unit: "lb", RecordedAt: base.AddDate(0, 0, 2)},
+	}
+
+	points := computeTrend(entries, "lb")
+	require.Len(t, points, 3)
+
+	// First point seeds the trend
+	assert.Equal(t, 200.0, points[0].Trend)
+	// 200 + 0.1 * (210 - 200) = 201
+	assert.Equal(t, 201.0, points[1].Trend)
+	// 201 + 0.1 * (190 - 201) = 199.9
+	assert.Equal(t, 199.9, points[2].Trend)
+	// Raw weights are preserved
+	assert.Equal(t, 190.0, points[2].Weight)
+}
+
+func TestComputeTrend_MixedUnits(t *testing.T) {
+	base := time.Date(2024, 1, 1, 7, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Weight: 100, Unit: "kg", RecordedAt: base},
+		{Weight: 220.5, Unit: "lb", RecordedAt: base.AddDate(0, 0, 1)},
+	}
+
+	points := computeTrend(entries, "kg")
+	require.Len(t, points, 2)
+	assert.Equal(t, 100.0, points[0].Weight)
+	assert.Equal(t, 100.0, points[1].Weight)
+}
+
+func TestWeightKgAt(t *testing.T) {
+	base := time.Date(2024, 1, 1, 7, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Weight: 90, Unit: "kg", RecordedAt: base},
+		{Weight: 92, Unit: "kg", RecordedAt: base.AddDate(0, 0, 10)},
+	}
+
+	assert.Nil(t, WeightKgAt(nil, base))
+
+	// Before the first entry falls back to the earliest entry
+	require.NotNil(t, WeightKgAt(entries, base.AddDate(0, 0, -5)))
+	assert.Equal(t, 90.0, *WeightKgAt(entries, base.AddDate(0, 0, -5)))
+
+	assert.Equal(t, 90.0, *WeightKgAt(entries, base.AddDate(0, 0, 5)))
+	assert.Equal(t, 92.0, *WeightKgAt(entries, base.AddDate(0, 0, 10)))
+	assert.Equal(t, 92.0, *WeightKgAt(entries, base.AddDate(0, 1, 0)))
+}
+
+func TestService_LogEntry(t *testing.T) {
+	svc, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+
+	fixedTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return fixedTime }
+	ctx := context.Background()
+
+	createTestUser(t, sqlDB, "bw-kg-user", "kg")
+
+	t.Run("defaults unit to profile preference and time to now", func(t *testing.T) {
+		entry, err := svc.LogEntry(ctx, "bw-kg-user", LogEntryRequest{Weight: 82.5})
+		require.NoError(t, err)
+		assert.Equal(t, "kg", entry.Unit)
+		assert.Equal(t, fixedTime, entry.RecordedAt)
+		assert.Nil(t, entry.Notes)
+	})
+
+	t.Run("explicit unit, time and notes", func(t *testing.T) {
+		recordedAt := fixedTime.Add(-24 * time.Hour)
+		entry, err := svc.LogEntry(ctx, "bw-kg-user", LogEntryRequest{
+			Weight:     181,
+			Unit:       "lb",
+			RecordedAt: &recordedAt,
+			Notes:      strPtr("  morning, fasted  "),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "lb", entry.Unit)
+		assert.Equal(t, recordedAt, entry.RecordedAt)
+		require.NotNil(t, entry.Notes)
+		assert.Equal(t, "morning, fasted", *entry.Notes)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		future := fixedTime.Add(time.Hour)
+		cases := []LogEntryRequest{
+			{Weight: 0},
+			{Weight: -5},
+			{Weight: 80, Unit: "stone"},
+			{Weight: 80, RecordedAt: &future},
+			{Weight: 80, Notes: strPtr(string(make([]byte, 501)))},
+		}
+		for _, req := range cases {
+			_, err := svc.LogEntry(ctx, "bw-kg-user", req)
+			require.Error(t, err)
+			assert.True(t, apperrors.IsValidation(err), "expected validation error for %+v", req)
+		}
+	})
+
+	t.Run("unknown user without unit is not found", func(t *testing.T) {
+		_, err := svc.LogEntry(ctx, "nonexistent", LogEntryRequest{Weight: 80})
+		require.Error(t, err)
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+
+	t.Run("entries are listed newest first", func(t *testing.T) {
+		entries, total, err := svc.ListEntries(ctx, "bw-kg-user", 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, entries, 2)
+		assert.Equal(t, 82.5, entries[0].Weight)
+		assert.Equal(t, 181.0, entries[1].Weight)
+	})
+}
+
+func TestService_DeleteEntry(t *testing.T) {
+	svc, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	createTestUser(t, sqlDB, "bw-owner", "lb")
+	createTestUser(t, sqlDB, "bw-other", "lb")
+
+	entry, err := svc.LogEntry(ctx, "bw-owner", LogEntryRequest{Weight: 200})
+	require.NoError(t, err)
+
+	// Another user cannot delete the entry
+	err = svc.DeleteEntry(ctx, "bw-other", entry.ID)
+	require.Error(t, err)
+	assert.True(t, apperrors.IsNotFound(err))
+
+	require.NoError(t, svc.DeleteEntry(ctx, "bw-owner", entry.ID))
+
+	err = svc.DeleteEntry(ctx, "bw-owner", entry.ID)
+	require.Error(t, err)
+	assert.True(t, apperrors.IsNotFound(err))
+}
+
+func TestService_GetSummary(t *testing.T) {
+	svc, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+
+	fixedTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return fixedTime }
+	ctx := context.Background()
+
+	createTestUser(t, sqlDB, "bw-summary-user", "kg")
+
+	t.Run("no entries", func(t *testing.T) {
+		summary, err := svc.GetSummary(ctx, "bw-summary-user")
+		require.NoError(t, err)
+		assert.Equal(t, "kg", summary.Unit)
+		assert.Equal(t, 0, summary.EntryCount)
+		assert.Nil(t, summary.LatestWeight)
+		assert.Nil(t, summary.TrendWeight)
+	})
+
+	for i, w := range []float64{95, 95, 95} {
+		recordedAt := fixedTime.AddDate(0, 0, i-3)
+		_, err := svc.LogEntry(ctx, "bw-summary-user", LogEntryRequest{Weight: w, RecordedAt: &recordedAt})
+		require.NoError(t, err)
+	}
+
+	t.Run("without sex only weights are reported", func(t *testing.T) {
+		summary, err := svc.GetSummary(ctx, "bw-summary-user")
+		require.NoError(t, err)
+		assert.Equal(t, 3, summary.EntryCount)
+		assert.Equal(t, floatPtr(95), summary.LatestWeight)
+		assert.Equal(t, floatPtr(95), summary.TrendWeight)
+		assert.Nil(t, summary.CurrentWeightClass)
+		assert.Nil(t, summary.DistanceToLimit)
+	})
+
+	t.Run("with sex and target reports distance to class limit", func(t *testing.T) {
+		_, err := svc.profileService.UpdateProfile(ctx, "bw-summary-user", profile.UpdateProfileRequest{
+			Sex:               strPtr("male"),
+			WeightClassTarget: strPtr("93"),
+		})
+		require.NoError(t, err)
+
+		summary, err := svc.GetSummary(ctx, "bw-summary-user")
+		require.NoError(t, err)
+		require.NotNil(t, summary.CurrentWeightClass)
+		assert.Equal(t, "105", *summary.CurrentWeightClass)
+		require.NotNil(t, summary.WeightClassTarget)
+		assert.Equal(t, "93", *summary.WeightClassTarget)
+		assert.Equal(t, floatPtr(93), summary.WeightClassLimit)
+		assert.Equal(t, floatPtr(2), summary.DistanceToLimit)
+	})
+}
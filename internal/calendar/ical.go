@@ -0,0 +1,161 @@
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Position is a day of a program cycle.
+type Position struct {
+	CycleIteration int
+	WeekNumber     int
+	// DayIndex is the 0-based index of the day within its week.
+	DayIndex int
+}
+
+// WeekLength is the number of days in a week of a cycle.
+type WeekLength struct {
+	Number int
+	Days   int
+}
+
+// Walk returns n positions starting at start. It goes through the days of each week in
+// order, and after the last week starts the next cycle iteration at the first week.
+// A start outside the cycle moves on to the next day that exists. Weeks without days
+// are passed over; a cycle without any day yields no positions.
+func Walk(weeks []WeekLength, start Position, n int) []Position {
+	total := 0
+	for _, w := range weeks {
+		total += w.Days
+	}
+	if total == 0 || n <= 0 {
+		return nil
+	}
+
+	// Start at the start day, or at the first week after it when it does not exist
+	week, day, iteration := -1, start.DayIndex, start.CycleIteration
+	for i, w := range weeks {
+		if w.Number < start.WeekNumber || (w.Number == start.WeekNumber && day >= w.Days) {
+			continue
+		}
+		if w.Number > start.WeekNumber {
+			day = 0
+		}
+		week = i
+		break
+	}
+	if week < 0 {
+		week, day, iteration = 0, 0, iteration+1
+	}
+
+	positions := make([]Position, 0, n)
+	for len(positions) < n {
+		if day >= weeks[week].Days {
+			day = 0
+			week++
+			if week == len(weeks) {
+				week = 0
+				iteration++
+			}
+			continue
+		}
+		positions = append(positions, Position{CycleIteration: iteration, WeekNumber: weeks[week].Number, DayIndex: day})
+		day++
+	}
+	return positions
+}
+
+// Event is a calendar event. All-day events use only the date of Start.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+}
+
+// Calendar is an iCalendar document.
+type Calendar struct {
+	Name string
+	// Stamp is when the document was generated.
+	Stamp  time.Time
+	Events []Event
+}
+
+// refreshInterval asks calendar apps to fetch the feed again, so events follow the
+// lifter's progress.
+const refreshInterval = "PT1H"
+
+// Render writes the calendar as an iCalendar (RFC 5545) document.
+func Render(w io.Writer, c *Calendar) error {
+	var b strings.Builder
+	line := func(name, value string) {
+		writeFolded(&b, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//PowerPro//Training Calendar//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeText(c.Name))
+	line("REFRESH-INTERVAL;VALUE=DURATION", refreshInterval)
+	line("X-PUBLISHED-TTL", refreshInterval)
+	stamp := c.Stamp.UTC().Format("20060102T150405Z")
+	for _, e := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escapeText(e.UID))
+		line("DTSTAMP", stamp)
+		if e.AllDay {
+			line("DTSTART;VALUE=DATE", e.Start.Format("20060102"))
+			line("DTEND;VALUE=DATE", e.Start.AddDate(0, 0, 1).Format("20060102"))
+			line("TRANSP", "TRANSPARENT")
+		} else {
+			line("DTSTART", e.Start.UTC().Format("20060102T150405Z"))
+			line("DTEND", e.End.UTC().Format("20060102T150405Z"))
+		}
+		line("SUMMARY", escapeText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escapeText(e.Description))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeText escapes a TEXT property value.
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// maxLineOctets is the longest content line allowed before folding.
+const maxLineOctets = 75
+
+// writeFolded writes a content line, folding it onto continuation lines so no line is
+// longer than 75 octets. Lines are never split inside a UTF-8 sequence.
+func writeFolded(b *strings.Builder, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts toward their length
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
+
+// eventUID identifies a user's event so that calendar apps update it in place.
+func eventUID(userID, kind string, date time.Time) string {
+	return fmt.Sprintf("%s-%s-%s@powerpro", kind, userID, date.Format("20060102"))
+}
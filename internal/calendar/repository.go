@@ -0,0 +1,131 @@
+package calendar
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// SQLiteRepository implements Repository using SQLite.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository creates a new SQLite-backed calendar feed repository.
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+const feedColumns = `user_id, token_prefix, training_days, start_time, duration_minutes, timezone, weeks_ahead, created_at, updated_at`
+
+// Get returns the user's feed, or nil if they have none.
+func (r *SQLiteRepository) Get(ctx context.Context, userID string) (*Feed, error) {
+	return r.get(ctx, `SELECT `+feedColumns+` FROM calendar_feeds WHERE user_id = ?`, userID)
+}
+
+// GetByHash returns the feed with the token hash, or nil if there is none.
+func (r *SQLiteRepository) GetByHash(ctx context.Context, hash string) (*Feed, error) {
+	return r.get(ctx, `SELECT `+feedColumns+` FROM calendar_feeds WHERE token_hash = ?`, hash)
+}
+
+func (r *SQLiteRepository) get(ctx context.Context, query string, arg string) (*Feed, error) {
+	var f Feed
+	var days, createdAt, updatedAt string
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(&f.UserID, &f.TokenPrefix, &days, &f.StartTime,
+		&f.DurationMinutes, &f.Timezone, &f.WeeksAhead, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to get calendar feed", err)
+	}
+	f.TrainingDays = strings.Split(days, ",")
+	f.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	f.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return &f, nil
+}
+
+// Save creates or updates the user's feed. A non-empty hash replaces the feed token.
+func (r *SQLiteRepository) Save(ctx context.Context, feed *Feed, hash string) error {
+	days := strings.Join(feed.TrainingDays, ",")
+	var err error
+	if hash != "" {
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO calendar_feeds (user_id, token_hash, token_prefix, training_days, start_time,
+				duration_minutes, timezone, weeks_ahead, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (user_id) DO UPDATE SET
+				token_hash = excluded.token_hash,
+				token_prefix = excluded.token_prefix,
+				updated_at = excluded.updated_at
+		`, feed.UserID, hash, feed.TokenPrefix, days, feed.StartTime, feed.DurationMinutes, feed.Timezone,
+			feed.WeeksAhead, feed.CreatedAt.Format(time.RFC3339), feed.UpdatedAt.Format(time.RFC3339))
+	} else {
+		_, err = r.db.ExecContext(ctx, `
+			UPDATE calendar_feeds SET training_days = ?, start_time = ?, duration_minutes = ?, timezone = ?,
+				weeks_ahead = ?, updated_at = ?
+			WHERE user_id = ?
+		`, days, feed.StartTime, feed.DurationMinutes, feed.Timezone, feed.WeeksAhead,
+			feed.UpdatedAt.Format(time.RFC3339), feed.UserID)
+	}
+	if err != nil {
+		return apperrors.NewInternal("failed to save calendar feed", err)
+	}
+	return nil
+}
+
+// Delete removes the user's feed and reports whether there was one.
+func (r *SQLiteRepository) Delete(ctx context.Context, userID string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM calendar_feeds WHERE user_id = ?`, userID)
+	if err != nil {
+		return false, apperrors.NewInternal("failed to delete calendar feed", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, apperrors.NewInternal("failed to delete calendar feed", err)
+	}
+	return n > 0, nil
+}
+
+// GetMeetDate returns the meet date of the user's enrollment, or nil if there is none.
+func (r *SQLiteRepository) GetMeetDate(ctx context.Context, userID string) (*time.Time, error) {
+	var meetDate sql.NullString
+	err := r.db.QueryRowContext(ctx, `SELECT meet_date FROM user_program_states WHERE user_id = ?`, userID).Scan(&meetDate)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to get meet date", err)
+	}
+	return parseNullTime(meetDate), nil
+}
+
+// GetLastFinishedSession returns when the user last finished a workout session, or nil
+// if they never have.
+func (r *SQLiteRepository) GetLastFinishedSession(ctx context.Context, userID string) (*time.Time, error) {
+	var finishedAt sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT MAX(ws.finished_at)
+		FROM workout_sessions ws
+		JOIN user_program_states ups ON ups.id = ws.user_program_state_id
+		WHERE ups.user_id = ? AND ws.status = 'COMPLETED'
+	`, userID).Scan(&finishedAt)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to get last finished session", err)
+	}
+	return parseNullTime(finishedAt), nil
+}
+
+func parseNullTime(s sql.NullString) *time.Time {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
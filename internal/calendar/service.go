@@ -0,0 +1,427 @@
+// Package calendar publishes lifters' upcoming training sessions as iCalendar feeds.
+// Each user can have one feed, reached through a secret token so that calendar apps can
+// subscribe without signing in. Sessions are placed on the lifter's training days in
+// program order from their current position, and the meet date appears as an all-day
+// event. Feeds are generated on each request, so they follow the program as it advances.
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	// Embed the time zone database so feed time zones work on hosts without one
+	_ "time/tzdata"
+
+	"github.com/waynenilsen/power-pro-v3/internal/domain/cyclesheet"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/workout"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+const (
+	// TokenPrefix starts every feed token.
+	TokenPrefix = "ppcal_"
+	// tokenBytes is the number of random bytes in a feed token.
+	tokenBytes = 32
+	// tokenHintLength is how many leading characters of a token are kept so its owner can
+	// recognize it.
+	tokenHintLength = 12
+
+	defaultStartTime       = "18:00"
+	defaultDurationMinutes = 90
+	defaultTimezone        = "UTC"
+	defaultWeeksAhead      = 4
+	maxDurationMinutes     = 6 * 60
+	maxWeeksAhead          = 12
+)
+
+// weekdayCodes maps the weekday codes used for training days to weekdays.
+var weekdayCodes = map[string]time.Weekday{
+	"MON": time.Monday,
+	"TUE": time.Tuesday,
+	"WED": time.Wednesday,
+	"THU": time.Thursday,
+	"FRI": time.Friday,
+	"SAT": time.Saturday,
+	"SUN": time.Sunday,
+}
+
+// Settings controls when a feed places training sessions.
+type Settings struct {
+	// TrainingDays are the weekdays the lifter trains, as codes such as MON, WED and FRI.
+	TrainingDays []string `json:"trainingDays"`
+	// StartTime is when sessions start, as HH:MM in Timezone.
+	StartTime       string `json:"startTime"`
+	DurationMinutes int    `json:"durationMinutes"`
+	// Timezone is an IANA time zone name such as America/Chicago.
+	Timezone string `json:"timezone"`
+	// WeeksAhead is how many weeks of sessions the feed holds.
+	WeeksAhead int `json:"weeksAhead"`
+}
+
+// Feed is a user's calendar feed. The token itself is never stored.
+type Feed struct {
+	UserID string `json:"userId"`
+	// TokenPrefix is the start of the feed token, so its owner can recognize it.
+	TokenPrefix string `json:"tokenPrefix"`
+	Settings
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Repository defines the interface for calendar feed persistence.
+type Repository interface {
+	// Get returns the user's feed, or nil if they have none.
+	Get(ctx context.Context, userID string) (*Feed, error)
+	// GetByHash returns the feed with the token hash, or nil if there is none.
+	GetByHash(ctx context.Context, hash string) (*Feed, error)
+	// Save creates or updates the user's feed. A non-empty hash replaces the feed token.
+	Save(ctx context.Context, feed *Feed, hash string) error
+	// Delete removes the user's feed and reports whether there was one.
+	Delete(ctx context.Context, userID string) (bool, error)
+	// GetMeetDate returns the meet date of the user's enrollment, or nil if there is none.
+	GetMeetDate(ctx context.Context, userID string) (*time.Time, error)
+	// GetLastFinishedSession returns when the user last finished a workout session, or
+	// nil if they never have.
+	GetLastFinishedSession(ctx context.Context, userID string) (*time.Time, error)
+}
+
+// Service manages calendar feeds and works out their schedules.
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+// NewService creates a new calendar service.
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+// Configure sets the user's feed settings, creating the feed if the user has none.
+// The returned token is only set when the feed is created; it is the only time the token
+// is available.
+func (s *Service) Configure(ctx context.Context, userID string, settings Settings) (*Feed, string, error) {
+	settings, err := normalizeSettings(settings)
+	if err != nil {
+		return nil, "", err
+	}
+	feed, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	now := s.now().UTC()
+	if feed != nil {
+		feed.Settings = settings
+		feed.UpdatedAt = now
+		if err := s.repo.Save(ctx, feed, ""); err != nil {
+			return nil, "", err
+		}
+		return feed, "", nil
+	}
+
+	feed = &Feed{UserID: userID, Settings: settings, CreatedAt: now, UpdatedAt: now}
+	token, err := s.issueToken(ctx, feed)
+	if err != nil {
+		return nil, "", err
+	}
+	return feed, token, nil
+}
+
+// Get retrieves the user's feed.
+func (s *Service) Get(ctx context.Context, userID string) (*Feed, error) {
+	feed, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return nil, apperrors.NewNotFound("calendar feed", userID)
+	}
+	return feed, nil
+}
+
+// RotateToken replaces the feed token, so the old feed URL stops working.
+func (s *Service) RotateToken(ctx context.Context, userID string) (*Feed, string, error) {
+	feed, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	feed.UpdatedAt = s.now().UTC()
+	token, err := s.issueToken(ctx, feed)
+	if err != nil {
+		return nil, "", err
+	}
+	return feed, token, nil
+}
+
+// Delete removes the user's feed.
+func (s *Service) Delete(ctx context.Context, userID string) error {
+	deleted, err := s.repo.Delete(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperrors.NewNotFound("calendar feed", userID)
+	}
+	return nil
+}
+
+// Resolve returns the feed a token opens.
+func (s *Service) Resolve(ctx context.Context, token string) (*Feed, error) {
+	if !strings.HasPrefix(token, TokenPrefix) {
+		return nil, apperrors.NewNotFound("calendar feed", "invalid token")
+	}
+	feed, err := s.repo.GetByHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return nil, apperrors.NewNotFound("calendar feed", "invalid token")
+	}
+	return feed, nil
+}
+
+// issueToken gives the feed a new token and saves it.
+func (s *Service) issueToken(ctx context.Context, feed *Feed) (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", apperrors.NewInternal("failed to generate feed token", err)
+	}
+	token := TokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	feed.TokenPrefix = token[:tokenHintLength]
+	if err := s.repo.Save(ctx, feed, hashToken(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// hashToken returns the hex SHA-256 of a token.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeSettings validates settings and fills in defaults. Training days are
+// upper-cased, deduplicated and put in weekday order starting on Monday.
+func normalizeSettings(settings Settings) (Settings, error) {
+	if len(settings.TrainingDays) == 0 {
+		return settings, apperrors.NewValidation("trainingDays", "trainingDays is required")
+	}
+	seen := map[string]bool{}
+	days := make([]string, 0, len(settings.TrainingDays))
+	for _, d := range settings.TrainingDays {
+		code := strings.ToUpper(strings.TrimSpace(d))
+		if _, ok := weekdayCodes[code]; !ok {
+			return settings, apperrors.NewValidation("trainingDays", fmt.Sprintf("invalid training day %q: use MON, TUE, WED, THU, FRI, SAT or SUN", d))
+		}
+		if !seen[code] {
+			seen[code] = true
+			days = append(days, code)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return (weekdayCodes[days[i]]+6)%7 < (weekdayCodes[days[j]]+6)%7
+	})
+	settings.TrainingDays = days
+
+	if settings.StartTime == "" {
+		settings.StartTime = defaultStartTime
+	}
+	if _, _, err := parseClock(settings.StartTime); err != nil {
+		return settings, apperrors.NewValidation("startTime", "startTime must be a time of day as HH:MM")
+	}
+	if settings.DurationMinutes == 0 {
+		settings.DurationMinutes = defaultDurationMinutes
+	}
+	if settings.DurationMinutes < 1 || settings.DurationMinutes > maxDurationMinutes {
+		return settings, apperrors.NewValidation("durationMinutes", fmt.Sprintf("durationMinutes must be between 1 and %d", maxDurationMinutes))
+	}
+	if settings.Timezone == "" {
+		settings.Timezone = defaultTimezone
+	}
+	if _, err := time.LoadLocation(settings.Timezone); err != nil {
+		return settings, apperrors.NewValidation("timezone", "timezone must be an IANA time zone name such as America/Chicago")
+	}
+	if settings.WeeksAhead == 0 {
+		settings.WeeksAhead = defaultWeeksAhead
+	}
+	if settings.WeeksAhead < 1 || settings.WeeksAhead > maxWeeksAhead {
+		return settings, apperrors.NewValidation("weeksAhead", fmt.Sprintf("weeksAhead must be between 1 and %d", maxWeeksAhead))
+	}
+	return settings, nil
+}
+
+// parseClock reads a time of day written as HH:MM.
+func parseClock(s string) (hour, minute int, err error) {
+	h, m, ok := strings.Cut(s, ":")
+	if !ok || len(h) != 2 || len(m) != 2 {
+		return 0, 0, fmt.Errorf("invalid time %q", s)
+	}
+	if hour, err = strconv.Atoi(h); err != nil || hour < 0 || hour > 23 {
+		return 0, 0, fmt.Errorf("invalid time %q", s)
+	}
+	if minute, err = strconv.Atoi(m); err != nil || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("invalid time %q", s)
+	}
+	return hour, minute, nil
+}
+
+// Schedule is when a feed's upcoming sessions and meet take place.
+type Schedule struct {
+	// Now is when the schedule was worked out.
+	Now time.Time
+	// Sessions are the start times of upcoming sessions, in order.
+	Sessions []time.Time
+	Duration time.Duration
+	// MeetDate is the date of the user's meet, at midnight in the feed's time zone. Nil
+	// when there is no meet or it has passed.
+	MeetDate *time.Time
+}
+
+// Schedule places the feed's upcoming sessions on its training days, starting today and
+// covering WeeksAhead weeks. Today is left out once the user has finished a session on
+// it, since their program has already moved on to the next day. Sessions stop at the
+// meet date.
+func (s *Service) Schedule(ctx context.Context, feed *Feed) (*Schedule, error) {
+	loc, err := time.LoadLocation(feed.Timezone)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to load feed time zone", err)
+	}
+	now := s.now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	schedule := &Schedule{Now: now, Duration: time.Duration(feed.DurationMinutes) * time.Minute}
+	meet, err := s.repo.GetMeetDate(ctx, feed.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if meet != nil {
+		// Meet dates are calendar dates stored at midnight UTC
+		utc := meet.UTC()
+		date := time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, loc)
+		if !date.Before(today) {
+			schedule.MeetDate = &date
+		}
+	}
+
+	first := today
+	last, err := s.repo.GetLastFinishedSession(ctx, feed.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil && !last.In(loc).Before(today) {
+		first = today.AddDate(0, 0, 1)
+	}
+
+	trainingDays := map[time.Weekday]bool{}
+	for _, code := range feed.TrainingDays {
+		trainingDays[weekdayCodes[code]] = true
+	}
+	hour, minute, _ := parseClock(feed.StartTime)
+	end := today.AddDate(0, 0, 7*feed.WeeksAhead)
+	for day := first; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if schedule.MeetDate != nil && !day.Before(*schedule.MeetDate) {
+			break
+		}
+		if trainingDays[day.Weekday()] {
+			schedule.Sessions = append(schedule.Sessions, time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc))
+		}
+	}
+	return schedule, nil
+}
+
+// Session is a planned training session.
+type Session struct {
+	Position
+	ProgramName string
+	DayName     string
+	// Exercises are the generated exercises. Nil when MissingMaxes is set.
+	Exercises  []workout.ExerciseInfo
+	WeightUnit string
+	// MissingMaxes is set when the workout could not be generated because the user has no
+	// max for a lift, so the event lists no weights.
+	MissingMaxes bool
+}
+
+// SessionEvent describes a planned session as an event. The summary lists each lift with
+// its top set, such as "Squat Day: Squat 250 lb × 5+, Bench Press 185 lb × 5", and the
+// description lists every set.
+func SessionEvent(userID string, start time.Time, duration time.Duration, session Session) Event {
+	var tops []string
+	var description strings.Builder
+	fmt.Fprintf(&description, "%s, cycle %d, week %d, day %d",
+		session.ProgramName, session.CycleIteration, session.WeekNumber, session.DayIndex+1)
+	if session.MissingMaxes {
+		description.WriteString("\n\nSet up your training maxes to see weights.")
+	}
+	for _, e := range session.Exercises {
+		if top, ok := topSet(e.Sets); ok {
+			tops = append(tops, fmt.Sprintf("%s %s %s × %s", e.Lift.Name, formatWeight(top.Weight), session.WeightUnit, formatReps(top)))
+		} else {
+			tops = append(tops, e.Lift.Name)
+		}
+
+		fmt.Fprintf(&description, "\n\n%s", e.Lift.Name)
+		for _, g := range cyclesheet.GroupSets(e.Sets) {
+			fmt.Fprintf(&description, "\n%s", g.Format(session.WeightUnit))
+		}
+		if e.RestSeconds != nil {
+			fmt.Fprintf(&description, "\nRest %s", cyclesheet.FormatRest(e.RestSeconds))
+		}
+		if e.Notes != "" {
+			fmt.Fprintf(&description, "\n%s", e.Notes)
+		}
+	}
+
+	summary := session.DayName
+	if len(tops) > 0 {
+		summary += ": " + strings.Join(tops, ", ")
+	}
+	return Event{
+		UID:         eventUID(userID, "session", start),
+		Summary:     summary,
+		Description: description.String(),
+		Start:       start,
+		End:         start.Add(duration),
+	}
+}
+
+// MeetEvent is the all-day event of a meet date.
+func MeetEvent(userID string, date time.Time) Event {
+	return Event{
+		UID:     eventUID(userID, "meet", date),
+		Summary: "Meet day",
+		Start:   date,
+		AllDay:  true,
+	}
+}
+
+// topSet is the heaviest work set of an exercise, or its heaviest set when it has no
+// work sets. Ties go to the earlier set.
+func topSet(sets []workout.SetInfo) (workout.SetInfo, bool) {
+	var top workout.SetInfo
+	found := false
+	for _, s := range sets {
+		if !found || (s.IsWorkSet && !top.IsWorkSet) || (s.IsWorkSet == top.IsWorkSet && s.Weight > top.Weight) {
+			top, found = s, true
+		}
+	}
+	return top, found
+}
+
+func formatReps(s workout.SetInfo) string {
+	reps := strconv.Itoa(s.TargetReps)
+	if s.IsAMRAP {
+		reps += "+"
+	}
+	return reps
+}
+
+func formatWeight(weight float64) string {
+	return strconv.FormatFloat(weight, 'f', -1, 64)
+}
@@ -0,0 +1,220 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waynenilsen/power-pro-v3/internal/database"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/workout"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+const programID = "starting-strength-0000-0000-000000000001"
+
+func setupTestService(t *testing.T) (*Service, *sql.DB, func()) {
+	sqlDB, cleanup, err := database.OpenTemp("../../migrations")
+	require.NoError(t, err)
+	svc := NewService(NewSQLiteRepository(sqlDB))
+	// Friday 1 March 2024, 12:00 UTC
+	svc.now = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }
+	return svc, sqlDB, cleanup
+}
+
+func seedUser(t *testing.T, sqlDB *sql.DB, userID string) {
+	_, err := sqlDB.Exec(`INSERT INTO users (id, email, created_at, updated_at) VALUES (?1, ?1 || '@example.com', '2024-01-01T00:00:00Z', '2024-01-01T00:00:00Z')`, userID)
+	require.NoError(t, err)
+	_, err = sqlDB.Exec(`INSERT INTO user_program_states (id, user_id, program_id, current_week, current_cycle_iteration, enrolled_at, updated_at)
+		VALUES (?1 || '-state', ?1, ?2, 1, 1, '2024-01-03T00:00:00Z', '2024-01-03T00:00:00Z')`, userID, programID)
+	require.NoError(t, err)
+}
+
+func TestWalk(t *testing.T) {
+	weeks := []WeekLength{{Number: 1, Days: 2}, {Number: 2, Days: 0}, {Number: 3, Days: 1}}
+	pos := func(iteration, week, day int) Position {
+		return Position{CycleIteration: iteration, WeekNumber: week, DayIndex: day}
+	}
+
+	assert.Equal(t, []Position{pos(1, 1, 1), pos(1, 3, 0), pos(2, 1, 0), pos(2, 1, 1)},
+		Walk(weeks, pos(1, 1, 1), 4), "empty weeks are passed over and the next cycle follows the last week")
+	assert.Equal(t, []Position{pos(1, 3, 0)}, Walk(weeks, pos(1, 2, 0), 1), "a missing start day moves on to the next week")
+	assert.Equal(t, []Position{pos(1, 3, 0)}, Walk(weeks, pos(1, 1, 2), 1), "a day index past the week moves on to the next week")
+	assert.Equal(t, []Position{pos(2, 1, 0)}, Walk(weeks, pos(1, 4, 0), 1), "a start after the last week starts the next cycle")
+	assert.Nil(t, Walk([]WeekLength{{Number: 1}}, pos(1, 1, 0), 3))
+	assert.Nil(t, Walk(weeks, pos(1, 1, 0), 0))
+}
+
+func TestRender(t *testing.T) {
+	start := time.Date(2024, 3, 4, 18, 0, 0, 0, time.FixedZone("CST", -6*3600))
+	cal := &Calendar{
+		Name:  "Wendler 5/3/1",
+		Stamp: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Events: []Event{
+			{
+				UID:         "session-u-20240304@powerpro",
+				Summary:     "Squat Day: Squat 250 lb × 5+, Bench Press 185 lb × 5",
+				Description: "Squat\n3 × 5 @ 225 lb; go deep, stay tight and keep the bar over the middle of your foot",
+				Start:       start,
+				End:         start.Add(90 * time.Minute),
+			},
+			MeetEvent("u", time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC)),
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, cal))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Contains(t, out, "DTSTART:20240305T000000Z\r\nDTEND:20240305T013000Z\r\n", "times are written in UTC")
+	assert.Contains(t, out, "SUMMARY:Squat Day: Squat 250 lb × 5+\\, Bench Press 185 lb × 5\r\n")
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20240420\r\nDTEND;VALUE=DATE:20240421\r\n")
+	assert.Contains(t, out, "UID:meet-u-20240420@powerpro\r\n")
+
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, `DESCRIPTION:Squat\n3 × 5 @ 225 lb\; go deep\, stay tight`)
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, "line %q is not folded", line)
+	}
+}
+
+func TestSessionEvent(t *testing.T) {
+	rest := 180
+	start := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+	event := SessionEvent("u", start, time.Hour, Session{
+		Position:    Position{CycleIteration: 2, WeekNumber: 3, DayIndex: 0},
+		ProgramName: "Wendler 5/3/1",
+		DayName:     "Squat Day",
+		WeightUnit:  "lb",
+		Exercises: []workout.ExerciseInfo{
+			{
+				Lift: workout.LiftInfo{Name: "Squat"},
+				Sets: []workout.SetInfo{
+					{SetNumber: 1, Weight: 135, TargetReps: 5},
+					{SetNumber: 2, Weight: 250, TargetReps: 5, IsWorkSet: true, IsAMRAP: true},
+					{SetNumber: 3, Weight: 225, TargetReps: 5, IsWorkSet: true},
+				},
+				Notes:       "Stay tight",
+				RestSeconds: &rest,
+			},
+			{Lift: workout.LiftInfo{Name: "Chin-up"}},
+		},
+	})
+
+	assert.Equal(t, "session-u-20240304@powerpro", event.UID)
+	assert.Equal(t, "Squat Day: Squat 250 lb × 5+, Chin-up", event.Summary)
+	assert.Equal(t, "Wendler 5/3/1, cycle 2, week 3, day 1\n\nSquat\n1 × 5 @ 135 lb (warm-up)\n1 × 5+ @ 250 lb\n1 × 5 @ 225 lb\nRest 3:00\nStay tight\n\nChin-up", event.Description)
+	assert.Equal(t, start.Add(time.Hour), event.End)
+
+	event = SessionEvent("u", start, time.Hour, Session{ProgramName: "P", DayName: "Day A", MissingMaxes: true})
+	assert.Equal(t, "Day A", event.Summary)
+	assert.Contains(t, event.Description, "Set up your training maxes")
+}
+
+func TestConfigure(t *testing.T) {
+	svc, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	seedUser(t, sqlDB, "lifter")
+
+	_, err := svc.Get(ctx, "lifter")
+	assert.True(t, apperrors.IsNotFound(err))
+
+	for _, settings := range []Settings{
+		{},
+		{TrainingDays: []string{"MONDAY"}},
+		{TrainingDays: []string{"MON"}, StartTime: "6pm"},
+		{TrainingDays: []string{"MON"}, StartTime: "24:00"},
+		{TrainingDays: []string{"MON"}, DurationMinutes: -5},
+		{TrainingDays: []string{"MON"}, Timezone: "Mars/Olympus"},
+		{TrainingDays: []string{"MON"}, WeeksAhead: 13},
+	} {
+		_, _, err := svc.Configure(ctx, "lifter", settings)
+		assert.True(t, apperrors.IsValidation(err), "settings %+v", settings)
+	}
+
+	feed, token, err := svc.Configure(ctx, "lifter", Settings{TrainingDays: []string{"fri", "MON", "wed", "MON"}})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, TokenPrefix))
+	assert.Equal(t, token[:tokenHintLength], feed.TokenPrefix)
+	assert.Equal(t, Settings{TrainingDays: []string{"MON", "WED", "FRI"}, StartTime: "18:00", DurationMinutes: 90, Timezone: "UTC", WeeksAhead: 4}, feed.Settings)
+
+	resolved, err := svc.Resolve(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "lifter", resolved.UserID)
+	_, err = svc.Resolve(ctx, TokenPrefix+"unknown")
+	assert.True(t, apperrors.IsNotFound(err))
+
+	// Changing settings keeps the token
+	feed, newToken, err := svc.Configure(ctx, "lifter", Settings{TrainingDays: []string{"SUN"}, StartTime: "07:30", Timezone: "America/Chicago"})
+	require.NoError(t, err)
+	assert.Empty(t, newToken)
+	assert.Equal(t, []string{"SUN"}, feed.TrainingDays)
+	resolved, err = svc.Resolve(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "07:30", resolved.StartTime)
+
+	// Rotating replaces the token
+	_, rotated, err := svc.RotateToken(ctx, "lifter")
+	require.NoError(t, err)
+	_, err = svc.Resolve(ctx, token)
+	assert.True(t, apperrors.IsNotFound(err))
+	_, err = svc.Resolve(ctx, rotated)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Delete(ctx, "lifter"))
+	assert.True(t, apperrors.IsNotFound(svc.Delete(ctx, "lifter")))
+	_, err = svc.Resolve(ctx, rotated)
+	assert.True(t, apperrors.IsNotFound(err))
+}
+
+func TestSchedule(t *testing.T) {
+	svc, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	seedUser(t, sqlDB, "lifter")
+
+	feed, _, err := svc.Configure(ctx, "lifter", Settings{
+		TrainingDays: []string{"MON", "WED", "FRI"},
+		StartTime:    "17:30",
+		Timezone:     "America/Chicago",
+		WeeksAhead:   1,
+	})
+	require.NoError(t, err)
+
+	schedule, err := svc.Schedule(ctx, feed)
+	require.NoError(t, err)
+	chicago, err := time.LoadLocation("America/Chicago")
+	require.NoError(t, err)
+	at := func(day int) time.Time { return time.Date(2024, 3, day, 17, 30, 0, 0, chicago) }
+	assert.Equal(t, []time.Time{at(1), at(4), at(6)}, schedule.Sessions, "today is included until a session is finished")
+	assert.Equal(t, 90*time.Minute, schedule.Duration)
+	assert.Nil(t, schedule.MeetDate)
+
+	// A session finished this morning, Chicago time, moves the schedule on to the next training day
+	_, err = sqlDB.Exec(`INSERT INTO workout_sessions (id, user_program_state_id, week_number, day_index, status, started_at, finished_at)
+		VALUES ('s1', 'lifter-state', 1, 0, 'COMPLETED', '2024-03-01T14:00:00Z', '2024-03-01T15:00:00Z')`)
+	require.NoError(t, err)
+	// The meet ends the sessions
+	_, err = sqlDB.Exec(`UPDATE user_program_states SET meet_date = '2024-03-06T00:00:00Z' WHERE user_id = 'lifter'`)
+	require.NoError(t, err)
+
+	schedule, err = svc.Schedule(ctx, feed)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{at(4)}, schedule.Sessions)
+	require.NotNil(t, schedule.MeetDate)
+	assert.Equal(t, time.Date(2024, 3, 6, 0, 0, 0, 0, chicago), *schedule.MeetDate)
+
+	// Past meets are left out
+	_, err = sqlDB.Exec(`UPDATE user_program_states SET meet_date = '2024-02-20T00:00:00Z' WHERE user_id = 'lifter'`)
+	require.NoError(t, err)
+	schedule, err = svc.Schedule(ctx, feed)
+	require.NoError(t, err)
+	assert.Nil(t, schedule.MeetDate)
+	assert.Len(t, schedule.Sessions, 2)
+}
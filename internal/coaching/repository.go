@@ -0,0 +1,333 @@
+package coaching
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// SQLiteRepository implements Repository using SQLite.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository creates a new SQLite-backed coaching repository.
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+const relationshipColumns = `id, coach_id, athlete_id, status, permissions, invited_by, created_at, updated_at,
+	responded_at, ended_at`
+
+const commentColumns = `id, session_id, author_id, body, created_at`
+
+// FindUserIDByEmail returns the ID of the user with the email, ignoring case.
+func (r *SQLiteRepository) FindUserIDByEmail(ctx context.Context, email string) (string, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id FROM users WHERE lower(email) = lower(?)
+	`, email).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", apperrors.NewNotFound("user", email)
+	}
+	if err != nil {
+		return "", apperrors.NewInternal("failed to look up user", err)
+	}
+	return id, nil
+}
+
+// UserExists reports whether a user with the ID exists.
+func (r *SQLiteRepository) UserExists(ctx context.Context, userID string) (bool, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM users WHERE id = ?)
+	`, userID).Scan(&exists); err != nil {
+		return false, apperrors.NewInternal("failed to look up user", err)
+	}
+	return exists, nil
+}
+
+// CreateRelationship inserts a new relationship.
+func (r *SQLiteRepository) CreateRelationship(ctx context.Context, rel *Relationship) error {
+	permissions, err := json.Marshal(rel.Permissions)
+	if err != nil {
+		return apperrors.NewInternal("failed to encode permissions", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO coaching_relationships (`+relationshipColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, rel.ID, rel.CoachID, rel.AthleteID, string(rel.Status), string(permissions), rel.InvitedBy,
+		rel.CreatedAt.Format(time.RFC3339), rel.UpdatedAt.Format(time.RFC3339),
+		nullTime(rel.RespondedAt), nullTime(rel.EndedAt))
+	if err != nil {
+		return apperrors.NewInternal("failed to create coaching relationship", err)
+	}
+	return nil
+}
+
+// GetRelationship retrieves a relationship by its ID.
+func (r *SQLiteRepository) GetRelationship(ctx context.Context, id string) (*Relationship, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+relationshipColumns+` FROM coaching_relationships WHERE id = ?
+	`, id)
+
+	rel, err := scanRelationship(row)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("coaching relationship", id)
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve coaching relationship", err)
+	}
+	return rel, nil
+}
+
+// GetOpenRelationship returns the pending or active relationship between a coach and
+// athlete, or nil when there is none.
+func (r *SQLiteRepository) GetOpenRelationship(ctx context.Context, coachID, athleteID string) (*Relationship, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+relationshipColumns+` FROM coaching_relationships
+		WHERE coach_id = ? AND athlete_id = ? AND status IN ('PENDING', 'ACTIVE')
+	`, coachID, athleteID)
+
+	rel, err := scanRelationship(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve coaching relationship", err)
+	}
+	return rel, nil
+}
+
+// ListRelationships returns a page of relationships matching filter, newest first,
+// along with the total count.
+func (r *SQLiteRepository) ListRelationships(ctx context.Context, filter RelationshipFilter, limit, offset int64) ([]Relationship, int64, error) {
+	where := "1 = 1"
+	args := []interface{}{}
+	if filter.CoachID != nil {
+		where += " AND coach_id = ?"
+		args = append(args, *filter.CoachID)
+	}
+	if filter.AthleteID != nil {
+		where += " AND athlete_id = ?"
+		args = append(args, *filter.AthleteID)
+	}
+	if filter.Status != nil {
+		where += " AND status = ?"
+		args = append(args, string(*filter.Status))
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM coaching_relationships WHERE "+where, args...,
+	).Scan(&total); err != nil {
+		return nil, 0, apperrors.NewInternal("failed to count coaching relationships", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+relationshipColumns+` FROM coaching_relationships WHERE `+where+`
+		ORDER BY created_at DESC, rowid DESC
+		LIMIT ? OFFSET ?
+	`, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list coaching relationships", err)
+	}
+	defer rows.Close()
+
+	rels, err := scanRelationships(rows)
+	if err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list coaching relationships", err)
+	}
+	return rels, total, nil
+}
+
+// ListActiveForCoach returns all of a coach's active relationships.
+func (r *SQLiteRepository) ListActiveForCoach(ctx context.Context, coachID string) ([]Relationship, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+relationshipColumns+` FROM coaching_relationships
+		WHERE coach_id = ? AND status = 'ACTIVE'
+		ORDER BY created_at ASC, rowid ASC
+	`, coachID)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list coaching relationships", err)
+	}
+	defer rows.Close()
+
+	rels, err := scanRelationships(rows)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list coaching relationships", err)
+	}
+	return rels, nil
+}
+
+// UpdateRelationship saves the mutable fields of a relationship.
+func (r *SQLiteRepository) UpdateRelationship(ctx context.Context, rel *Relationship) error {
+	permissions, err := json.Marshal(rel.Permissions)
+	if err != nil {
+		return apperrors.NewInternal("failed to encode permissions", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE coaching_relationships
+		SET status = ?, permissions = ?, updated_at = ?, responded_at = ?, ended_at = ?
+		WHERE id = ?
+	`, string(rel.Status), string(permissions), rel.UpdatedAt.Format(time.RFC3339),
+		nullTime(rel.RespondedAt), nullTime(rel.EndedAt), rel.ID)
+	if err != nil {
+		return apperrors.NewInternal("failed to update coaching relationship", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NewNotFound("coaching relationship", rel.ID)
+	}
+	return nil
+}
+
+// CreateComment inserts a new workout comment.
+func (r *SQLiteRepository) CreateComment(ctx context.Context, c *Comment) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO workout_comments (`+commentColumns+`)
+		VALUES (?, ?, ?, ?, ?)
+	`, c.ID, c.SessionID, c.AuthorID, c.Body, c.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return apperrors.NewInternal("failed to create workout comment", err)
+	}
+	return nil
+}
+
+// GetComment retrieves a workout comment by its ID.
+func (r *SQLiteRepository) GetComment(ctx context.Context, id string) (*Comment, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+commentColumns+` FROM workout_comments WHERE id = ?
+	`, id)
+
+	c, err := scanComment(row)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("workout comment", id)
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve workout comment", err)
+	}
+	return c, nil
+}
+
+// ListComments returns a page of a session's comments, oldest first, along with the total count.
+func (r *SQLiteRepository) ListComments(ctx context.Context, sessionID string, limit, offset int64) ([]Comment, int64, error) {
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM workout_comments WHERE session_id = ?
+	`, sessionID).Scan(&total); err != nil {
+		return nil, 0, apperrors.NewInternal("failed to count workout comments", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+commentColumns+` FROM workout_comments
+		WHERE session_id = ?
+		ORDER BY created_at ASC, rowid ASC
+		LIMIT ? OFFSET ?
+	`, sessionID, limit, offset)
+	if err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list workout comments", err)
+	}
+	defer rows.Close()
+
+	comments := []Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, 0, apperrors.NewInternal("failed to list workout comments", err)
+		}
+		comments = append(comments, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list workout comments", err)
+	}
+	return comments, total, nil
+}
+
+// DeleteComment deletes a workout comment.
+func (r *SQLiteRepository) DeleteComment(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM workout_comments WHERE id = ?`, id)
+	if err != nil {
+		return apperrors.NewInternal("failed to delete workout comment", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NewNotFound("workout comment", id)
+	}
+	return nil
+}
+
+// rowScanner abstracts *sql.Row and *sql.Rows for scanning.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanRelationship scans a single relationship row.
+func scanRelationship(row rowScanner) (*Relationship, error) {
+	var rel Relationship
+	var status, permissions, createdAt, updatedAt string
+	var respondedAt, endedAt sql.NullString
+
+	if err := row.Scan(&rel.ID, &rel.CoachID, &rel.AthleteID, &status, &permissions, &rel.InvitedBy,
+		&createdAt, &updatedAt, &respondedAt, &endedAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(permissions), &rel.Permissions); err != nil {
+		return nil, err
+	}
+	rel.Status = Status(status)
+	rel.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	rel.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	rel.RespondedAt = parseNullTime(respondedAt)
+	rel.EndedAt = parseNullTime(endedAt)
+
+	return &rel, nil
+}
+
+// scanRelationships scans all rows into relationships.
+func scanRelationships(rows *sql.Rows) ([]Relationship, error) {
+	rels := []Relationship{}
+	for rows.Next() {
+		rel, err := scanRelationship(rows)
+		if err != nil {
+			return nil, err
+		}
+		rels = append(rels, *rel)
+	}
+	return rels, rows.Err()
+}
+
+// scanComment scans a single comment row.
+func scanComment(row rowScanner) (*Comment, error) {
+	var c Comment
+	var createdAt string
+
+	if err := row.Scan(&c.ID, &c.SessionID, &c.AuthorID, &c.Body, &createdAt); err != nil {
+		return nil, err
+	}
+	c.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+
+	return &c, nil
+}
+
+func nullTime(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.UTC().Format(time.RFC3339), Valid: true}
+}
+
+func parseNullTime(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
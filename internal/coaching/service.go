@@ -0,0 +1,410 @@
+// Package coaching provides coach–athlete relationships with delegated permissions.
+// Either user can invite the other; the relationship becomes active when the invitee
+// accepts it. The athlete controls which permissions the coach holds, and a coach with
+// the comment permission can comment on the athlete's workout sessions.
+package coaching
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+const (
+	// maxCommentLength is the maximum allowed length for a workout comment.
+	maxCommentLength = 2000
+)
+
+// Permission is something a coach may do with an athlete's data.
+type Permission string
+
+const (
+	// PermissionViewLogs allows viewing the athlete's workouts, logged sets, records,
+	// maxes, enrollment and other training data.
+	PermissionViewLogs Permission = "VIEW_LOGS"
+	// PermissionEditMaxes allows creating, updating and deleting the athlete's lift maxes
+	// and triggering or reverting their progressions.
+	PermissionEditMaxes Permission = "EDIT_MAXES"
+	// PermissionManageEnrollment allows enrolling the athlete in programs and moving
+	// their program state.
+	PermissionManageEnrollment Permission = "MANAGE_ENROLLMENT"
+	// PermissionComment allows viewing and adding comments on the athlete's workout sessions.
+	PermissionComment Permission = "COMMENT"
+)
+
+// AllPermissions lists every permission in canonical order.
+var AllPermissions = []Permission{
+	PermissionViewLogs,
+	PermissionEditMaxes,
+	PermissionManageEnrollment,
+	PermissionComment,
+}
+
+// Status represents the state of a coaching relationship.
+type Status string
+
+const (
+	// StatusPending is an invitation waiting for the invitee to respond.
+	StatusPending Status = "PENDING"
+	// StatusActive is an accepted relationship; the coach holds its permissions.
+	StatusActive Status = "ACTIVE"
+	// StatusDeclined is an invitation the invitee declined.
+	StatusDeclined Status = "DECLINED"
+	// StatusEnded is a relationship or invitation ended by either user.
+	StatusEnded Status = "ENDED"
+)
+
+// Relationship is a coach–athlete relationship.
+type Relationship struct {
+	ID          string
+	CoachID     string
+	AthleteID   string
+	Status      Status
+	Permissions []Permission
+	// InvitedBy is the user whose side sent the invitation: the coach or the athlete.
+	InvitedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	RespondedAt *time.Time
+	EndedAt     *time.Time
+}
+
+// Invitee returns the user who must accept or decline the invitation.
+func (r *Relationship) Invitee() string {
+	if r.InvitedBy == r.CoachID {
+		return r.AthleteID
+	}
+	return r.CoachID
+}
+
+// IsParty reports whether the user is the coach or the athlete.
+func (r *Relationship) IsParty(userID string) bool {
+	return userID == r.CoachID || userID == r.AthleteID
+}
+
+// Has reports whether the relationship grants the permission.
+func (r *Relationship) Has(permission Permission) bool {
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Comment is a comment on a workout session.
+type Comment struct {
+	ID        string
+	SessionID string
+	AuthorID  string
+	Body      string
+	CreatedAt time.Time
+}
+
+// InviteRequest identifies the user to invite, by ID or email, and the permissions
+// the coach is to hold.
+type InviteRequest struct {
+	UserID      string
+	Email       string
+	Permissions []Permission
+}
+
+// RelationshipFilter narrows relationship lists. Nil fields are not filtered on.
+type RelationshipFilter struct {
+	CoachID   *string
+	AthleteID *string
+	Status    *Status
+}
+
+// Repository defines the interface for coaching persistence.
+type Repository interface {
+	// FindUserIDByEmail returns the ID of the user with the email, ignoring case.
+	FindUserIDByEmail(ctx context.Context, email string) (string, error)
+	UserExists(ctx context.Context, userID string) (bool, error)
+	CreateRelationship(ctx context.Context, rel *Relationship) error
+	GetRelationship(ctx context.Context, id string) (*Relationship, error)
+	// GetOpenRelationship returns the pending or active relationship between a coach and
+	// athlete, or nil when there is none.
+	GetOpenRelationship(ctx context.Context, coachID, athleteID string) (*Relationship, error)
+	// ListRelationships returns a page of relationships matching filter, newest first,
+	// along with the total count.
+	ListRelationships(ctx context.Context, filter RelationshipFilter, limit, offset int64) ([]Relationship, int64, error)
+	// ListActiveForCoach returns all of a coach's active relationships.
+	ListActiveForCoach(ctx context.Context, coachID string) ([]Relationship, error)
+	UpdateRelationship(ctx context.Context, rel *Relationship) error
+	CreateComment(ctx context.Context, c *Comment) error
+	GetComment(ctx context.Context, id string) (*Comment, error)
+	// ListComments returns a page of a session's comments, oldest first, along with the total count.
+	ListComments(ctx context.Context, sessionID string, limit, offset int64) ([]Comment, int64, error)
+	DeleteComment(ctx context.Context, id string) error
+}
+
+// Service manages coaching relationships and workout comments.
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+// NewService creates a new coaching service.
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// InviteCoach invites a coach on behalf of an athlete.
+func (s *Service) InviteCoach(ctx context.Context, athleteID string, req InviteRequest) (*Relationship, error) {
+	coachID, err := s.resolveInvitee(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.invite(ctx, coachID, athleteID, athleteID, req.Permissions)
+}
+
+// InviteAthlete invites an athlete on behalf of a coach.
+func (s *Service) InviteAthlete(ctx context.Context, coachID string, req InviteRequest) (*Relationship, error) {
+	athleteID, err := s.resolveInvitee(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.invite(ctx, coachID, athleteID, coachID, req.Permissions)
+}
+
+func (s *Service) resolveInvitee(ctx context.Context, req InviteRequest) (string, error) {
+	userID := strings.TrimSpace(req.UserID)
+	email := strings.TrimSpace(req.Email)
+	switch {
+	case userID != "" && email != "":
+		return "", apperrors.NewValidation("userId", "provide either userId or email, not both")
+	case email != "":
+		return s.repo.FindUserIDByEmail(ctx, email)
+	case userID != "":
+		exists, err := s.repo.UserExists(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return "", apperrors.NewNotFound("user", userID)
+		}
+		return userID, nil
+	default:
+		return "", apperrors.NewValidation("userId", "userId or email is required")
+	}
+}
+
+func (s *Service) invite(ctx context.Context, coachID, athleteID, invitedBy string, permissions []Permission) (*Relationship, error) {
+	if coachID == athleteID {
+		return nil, apperrors.NewValidation("userId", "you cannot coach yourself")
+	}
+	perms, err := validatePermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := s.repo.GetOpenRelationship(ctx, coachID, athleteID)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		if open.Status == StatusActive {
+			return nil, apperrors.NewConflict("this coach already coaches this athlete")
+		}
+		return nil, apperrors.NewConflict("an invitation between this coach and athlete is already pending")
+	}
+
+	now := s.now().UTC()
+	rel := &Relationship{
+		ID:          uuid.New().String(),
+		CoachID:     coachID,
+		AthleteID:   athleteID,
+		Status:      StatusPending,
+		Permissions: perms,
+		InvitedBy:   invitedBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.CreateRelationship(ctx, rel); err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+// GetRelationship retrieves a relationship by ID.
+func (s *Service) GetRelationship(ctx context.Context, id string) (*Relationship, error) {
+	return s.repo.GetRelationship(ctx, id)
+}
+
+// ListRelationships returns a page of relationships matching filter, newest first.
+func (s *Service) ListRelationships(ctx context.Context, filter RelationshipFilter, limit, offset int64) ([]Relationship, int64, error) {
+	return s.repo.ListRelationships(ctx, filter, limit, offset)
+}
+
+// Accept activates a pending invitation.
+func (s *Service) Accept(ctx context.Context, id string) (*Relationship, error) {
+	return s.respond(ctx, id, StatusActive)
+}
+
+// Decline declines a pending invitation.
+func (s *Service) Decline(ctx context.Context, id string) (*Relationship, error) {
+	return s.respond(ctx, id, StatusDeclined)
+}
+
+func (s *Service) respond(ctx context.Context, id string, status Status) (*Relationship, error) {
+	rel, err := s.repo.GetRelationship(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rel.Status != StatusPending {
+		return nil, apperrors.NewConflict(fmt.Sprintf("invitation is %s, not PENDING", rel.Status))
+	}
+
+	now := s.now().UTC()
+	rel.Status = status
+	rel.RespondedAt = &now
+	rel.UpdatedAt = now
+	if err := s.repo.UpdateRelationship(ctx, rel); err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+// UpdatePermissions replaces the permissions of a pending or active relationship.
+func (s *Service) UpdatePermissions(ctx context.Context, id string, permissions []Permission) (*Relationship, error) {
+	perms, err := validatePermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := s.repo.GetRelationship(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rel.Status != StatusPending && rel.Status != StatusActive {
+		return nil, apperrors.NewConflict(fmt.Sprintf("relationship is %s", rel.Status))
+	}
+
+	rel.Permissions = perms
+	rel.UpdatedAt = s.now().UTC()
+	if err := s.repo.UpdateRelationship(ctx, rel); err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+// End ends an active relationship or withdraws a pending invitation. The coach loses
+// access immediately.
+func (s *Service) End(ctx context.Context, id string) (*Relationship, error) {
+	rel, err := s.repo.GetRelationship(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rel.Status != StatusPending && rel.Status != StatusActive {
+		return nil, apperrors.NewConflict(fmt.Sprintf("relationship is already %s", rel.Status))
+	}
+
+	now := s.now().UTC()
+	rel.Status = StatusEnded
+	rel.EndedAt = &now
+	rel.UpdatedAt = now
+	if err := s.repo.UpdateRelationship(ctx, rel); err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+// CanAccess reports whether actorID coaches ownerID with the permission. It implements
+// middleware.AccessChecker; self and admin access are decided by the caller.
+func (s *Service) CanAccess(ctx context.Context, actorID, ownerID, permission string) (bool, error) {
+	rel, err := s.repo.GetOpenRelationship(ctx, actorID, ownerID)
+	if err != nil {
+		return false, err
+	}
+	return rel != nil && rel.Status == StatusActive && rel.Has(Permission(permission)), nil
+}
+
+// AthleteIDs returns the athletes a coach actively coaches with the permission.
+func (s *Service) AthleteIDs(ctx context.Context, coachID string, permission Permission) ([]string, error) {
+	rels, err := s.repo.ListActiveForCoach(ctx, coachID)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for i := range rels {
+		if rels[i].Has(permission) {
+			ids = append(ids, rels[i].AthleteID)
+		}
+	}
+	return ids, nil
+}
+
+// AddComment adds a comment to a workout session.
+func (s *Service) AddComment(ctx context.Context, sessionID, authorID, body string) (*Comment, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, apperrors.NewValidation("body", "body is required")
+	}
+	if len(body) > maxCommentLength {
+		return nil, apperrors.NewValidation("body", fmt.Sprintf("body must be at most %d characters", maxCommentLength))
+	}
+
+	c := &Comment{
+		ID:        uuid.New().String(),
+		SessionID: sessionID,
+		AuthorID:  authorID,
+		Body:      body,
+		CreatedAt: s.now().UTC(),
+	}
+	if err := s.repo.CreateComment(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetComment retrieves a comment by ID.
+func (s *Service) GetComment(ctx context.Context, id string) (*Comment, error) {
+	return s.repo.GetComment(ctx, id)
+}
+
+// ListComments returns a page of a session's comments, oldest first.
+func (s *Service) ListComments(ctx context.Context, sessionID string, limit, offset int64) ([]Comment, int64, error) {
+	return s.repo.ListComments(ctx, sessionID, limit, offset)
+}
+
+// DeleteComment deletes a comment.
+func (s *Service) DeleteComment(ctx context.Context, id string) error {
+	return s.repo.DeleteComment(ctx, id)
+}
+
+// validatePermissions checks permissions are known and returns them without duplicates,
+// in canonical order. No permissions means view-only access.
+func validatePermissions(permissions []Permission) ([]Permission, error) {
+	if len(permissions) == 0 {
+		return []Permission{PermissionViewLogs}, nil
+	}
+	requested := make(map[Permission]bool, len(permissions))
+	for _, p := range permissions {
+		known := false
+		for _, candidate := range AllPermissions {
+			if p == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, apperrors.NewValidation("permissions", fmt.Sprintf("unknown permission %q", p))
+		}
+		requested[p] = true
+	}
+
+	perms := make([]Permission, 0, len(requested))
+	for _, p := range AllPermissions {
+		if requested[p] {
+			perms = append(perms, p)
+		}
+	}
+	return perms, nil
+}
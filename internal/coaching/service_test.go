@@ -0,0 +1,195 @@
+package coaching
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waynenilsen/power-pro-v3/internal/database"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+func setupTestService(t *testing.T) (*Service, *sql.DB, func()) {
+	sqlDB, cleanup, err := database.OpenTemp("../../migrations")
+	require.NoError(t, err)
+	for _, userID := range []string{"coach", "athlete", "other"} {
+		_, err := sqlDB.Exec(`
+			INSERT INTO users (id, email, created_at, updated_at)
+			VALUES (?, ?, datetime('now'), datetime('now'))
+		`, userID, userID+"@example.com")
+		require.NoError(t, err)
+	}
+	return NewService(NewSQLiteRepository(sqlDB)), sqlDB, cleanup
+}
+
+func TestInvite(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	t.Run("athlete invites a coach by email", func(t *testing.T) {
+		rel, err := svc.InviteCoach(ctx, "athlete", InviteRequest{
+			Email:       "Coach@Example.com",
+			Permissions: []Permission{PermissionComment, PermissionViewLogs, PermissionComment},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "coach", rel.CoachID)
+		assert.Equal(t, "athlete", rel.AthleteID)
+		assert.Equal(t, StatusPending, rel.Status)
+		assert.Equal(t, "coach", rel.Invitee())
+		assert.Equal(t, []Permission{PermissionViewLogs, PermissionComment}, rel.Permissions)
+
+		_, err = svc.InviteAthlete(ctx, "coach", InviteRequest{UserID: "athlete"})
+		assert.True(t, apperrors.IsConflict(err))
+	})
+
+	t.Run("coach invites an athlete with view-only access by default", func(t *testing.T) {
+		rel, err := svc.InviteAthlete(ctx, "coach", InviteRequest{UserID: "other"})
+		require.NoError(t, err)
+		assert.Equal(t, "other", rel.Invitee())
+		assert.Equal(t, []Permission{PermissionViewLogs}, rel.Permissions)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := svc.InviteCoach(ctx, "athlete", InviteRequest{})
+		assert.True(t, apperrors.IsValidation(err))
+
+		_, err = svc.InviteCoach(ctx, "athlete", InviteRequest{UserID: "athlete"})
+		assert.True(t, apperrors.IsValidation(err))
+
+		_, err = svc.InviteCoach(ctx, "athlete", InviteRequest{UserID: "other", Permissions: []Permission{"DELETE_ACCOUNT"}})
+		assert.True(t, apperrors.IsValidation(err))
+
+		_, err = svc.InviteCoach(ctx, "athlete", InviteRequest{UserID: "missing"})
+		assert.True(t, apperrors.IsNotFound(err))
+
+		_, err = svc.InviteCoach(ctx, "athlete", InviteRequest{Email: "missing@example.com"})
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+}
+
+func TestRelationshipLifecycle(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	rel, err := svc.InviteCoach(ctx, "athlete", InviteRequest{UserID: "coach", Permissions: []Permission{PermissionViewLogs}})
+	require.NoError(t, err)
+
+	// Pending invitations grant nothing
+	ok, err := svc.CanAccess(ctx, "coach", "athlete", string(PermissionViewLogs))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	rel, err = svc.Accept(ctx, rel.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusActive, rel.Status)
+	assert.NotNil(t, rel.RespondedAt)
+
+	_, err = svc.Accept(ctx, rel.ID)
+	assert.True(t, apperrors.IsConflict(err))
+
+	ok, err = svc.CanAccess(ctx, "coach", "athlete", string(PermissionViewLogs))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = svc.CanAccess(ctx, "coach", "athlete", string(PermissionEditMaxes))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = svc.CanAccess(ctx, "athlete", "coach", string(PermissionViewLogs))
+	require.NoError(t, err)
+	assert.False(t, ok, "access is not symmetric")
+
+	_, err = svc.UpdatePermissions(ctx, rel.ID, []Permission{PermissionEditMaxes, PermissionViewLogs})
+	require.NoError(t, err)
+	ids, err := svc.AthleteIDs(ctx, "coach", PermissionEditMaxes)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"athlete"}, ids)
+	ids, err = svc.AthleteIDs(ctx, "coach", PermissionComment)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	rel, err = svc.End(ctx, rel.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusEnded, rel.Status)
+	ok, err = svc.CanAccess(ctx, "coach", "athlete", string(PermissionViewLogs))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = svc.End(ctx, rel.ID)
+	assert.True(t, apperrors.IsConflict(err))
+
+	// Ended relationships can be started again
+	_, err = svc.InviteAthlete(ctx, "coach", InviteRequest{UserID: "athlete"})
+	require.NoError(t, err)
+
+	coachID := "coach"
+	rels, total, err := svc.ListRelationships(ctx, RelationshipFilter{CoachID: &coachID}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, StatusPending, rels[0].Status)
+	assert.Equal(t, StatusEnded, rels[1].Status)
+}
+
+func TestDecline(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	rel, err := svc.InviteAthlete(ctx, "coach", InviteRequest{UserID: "athlete"})
+	require.NoError(t, err)
+
+	rel, err = svc.Decline(ctx, rel.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusDeclined, rel.Status)
+
+	_, err = svc.UpdatePermissions(ctx, rel.ID, []Permission{PermissionComment})
+	assert.True(t, apperrors.IsConflict(err))
+}
+
+func TestComments(t *testing.T) {
+	svc, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, err := sqlDB.Exec(`
+		INSERT INTO cycles (id, name, length_weeks, created_at, updated_at)
+		VALUES ('cycle-1', 'Cycle', 4, datetime('now'), datetime('now'))
+	`)
+	require.NoError(t, err)
+	_, err = sqlDB.Exec(`
+		INSERT INTO programs (id, name, slug, cycle_id, created_at, updated_at)
+		VALUES ('program-1', 'Program', 'program', 'cycle-1', datetime('now'), datetime('now'))
+	`)
+	require.NoError(t, err)
+	_, err = sqlDB.Exec(`
+		INSERT INTO user_program_states (id, user_id, program_id, current_week, current_cycle_iteration, enrolled_at, updated_at)
+		VALUES ('state-1', 'athlete', 'program-1', 1, 1, datetime('now'), datetime('now'))
+	`)
+	require.NoError(t, err)
+	_, err = sqlDB.Exec(`
+		INSERT INTO workout_sessions (id, user_program_state_id, week_number, day_index, status, started_at, created_at, updated_at)
+		VALUES ('session-1', 'state-1', 1, 0, 'IN_PROGRESS', datetime('now'), datetime('now'), datetime('now'))
+	`)
+	require.NoError(t, err)
+
+	_, err = svc.AddComment(ctx, "session-1", "coach", "   ")
+	assert.True(t, apperrors.IsValidation(err))
+
+	first, err := svc.AddComment(ctx, "session-1", "coach", " Keep your chest up on the last set ")
+	require.NoError(t, err)
+	assert.Equal(t, "Keep your chest up on the last set", first.Body)
+	_, err = svc.AddComment(ctx, "session-1", "athlete", "Will do")
+	require.NoError(t, err)
+
+	comments, total, err := svc.ListComments(ctx, "session-1", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "coach", comments[0].AuthorID)
+	assert.Equal(t, "athlete", comments[1].AuthorID)
+
+	require.NoError(t, svc.DeleteComment(ctx, first.ID))
+	_, err = svc.GetComment(ctx, first.ID)
+	assert.True(t, apperrors.IsNotFound(err))
+}
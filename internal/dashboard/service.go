@@ -12,6 +12,7 @@ import (
 
 	"github.com/waynenilsen/power-pro-v3/internal/db"
 	"github.com/waynenilsen/power-pro-v3/internal/profile"
+	"github.com/waynenilsen/power-pro-v3/internal/strength"
 )
 
 // EnrollmentSummary represents the enrollment section of the dashboard.
@@ -57,26 +58,29 @@ type MaxSummary struct {
 
 // Dashboard represents the complete dashboard response.
 type Dashboard struct {
-	Enrollment     *EnrollmentSummary   `json:"enrollment"`
-	NextWorkout    *NextWorkoutPreview  `json:"nextWorkout"`
-	CurrentSession *SessionSummary      `json:"currentSession"`
-	RecentWorkouts []WorkoutSummary     `json:"recentWorkouts"`
-	CurrentMaxes   []MaxSummary         `json:"currentMaxes"`
+	Enrollment     *EnrollmentSummary  `json:"enrollment"`
+	NextWorkout    *NextWorkoutPreview `json:"nextWorkout"`
+	CurrentSession *SessionSummary     `json:"currentSession"`
+	RecentWorkouts []WorkoutSummary    `json:"recentWorkouts"`
+	CurrentMaxes   []MaxSummary        `json:"currentMaxes"`
+	StrengthScores *strength.Scores    `json:"strengthScores"`
 }
 
 // Service provides dashboard aggregation operations.
 type Service struct {
-	db             *sql.DB
-	queries        *db.Queries
-	profileService *profile.Service
+	db              *sql.DB
+	queries         *db.Queries
+	profileService  *profile.Service
+	strengthService *strength.Service
 }
 
 // NewService creates a new dashboard service.
-func NewService(sqlDB *sql.DB, profileService *profile.Service) *Service {
+func NewService(sqlDB *sql.DB, profileService *profile.Service, strengthService *strength.Service) *Service {
 	return &Service{
-		db:             sqlDB,
-		queries:        db.New(sqlDB),
-		profileService: profileService,
+		db:              sqlDB,
+		queries:         db.New(sqlDB),
+		profileService:  profileService,
+		strengthService: strengthService,
 	}
 }
 
@@ -135,9 +139,62 @@ func (s *Service) GetDashboard(ctx context.Context, userID string) (*Dashboard,
 		dashboard.CurrentMaxes = currentMaxes
 	}
 
+	// Get strength scores (DOTS, IPF GL, Wilks)
+	strengthScores, err := s.strengthService.GetScores(ctx, userID)
+	if err != nil {
+		log.Printf("Warning: failed to get strength scores for dashboard: %v", err)
+	}
+	dashboard.StrengthScores = strengthScores
+
 	return dashboard, nil
 }
 
+// RosterEntry is one user's dashboard within a roster dashboard.
+type RosterEntry struct {
+	UserID    string     `json:"userId"`
+	Dashboard *Dashboard `json:"dashboard"`
+}
+
+// RosterSummary totals the dashboards of a roster.
+type RosterSummary struct {
+	Users     int `json:"users"`
+	Enrolled  int `json:"enrolled"`
+	InSession int `json:"inSession"`
+}
+
+// RosterDashboard aggregates the dashboards of a group of users, such as an
+// organization's members.
+type RosterDashboard struct {
+	Summary RosterSummary `json:"summary"`
+	Entries []RosterEntry `json:"entries"`
+}
+
+// GetRosterDashboard retrieves the dashboard of each user, in the given order, and
+// totals them.
+func (s *Service) GetRosterDashboard(ctx context.Context, userIDs []string) (*RosterDashboard, error) {
+	roster := &RosterDashboard{
+		Entries: make([]RosterEntry, 0, len(userIDs)),
+	}
+
+	for _, userID := range userIDs {
+		dashboard, err := s.GetDashboard(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		roster.Entries = append(roster.Entries, RosterEntry{UserID: userID, Dashboard: dashboard})
+
+		roster.Summary.Users++
+		if dashboard.Enrollment != nil {
+			roster.Summary.Enrolled++
+		}
+		if dashboard.CurrentSession != nil {
+			roster.Summary.InSession++
+		}
+	}
+
+	return roster, nil
+}
+
 // aggregateEnrollment retrieves the enrollment summary for a user.
 func (s *Service) aggregateEnrollment(ctx context.Context, userID string) (*EnrollmentSummary, error) {
 	row, err := s.queries.GetEnrollmentWithProgram(ctx, userID)
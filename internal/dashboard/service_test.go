@@ -9,9 +9,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/waynenilsen/power-pro-v3/internal/bodyweight"
 	"github.com/waynenilsen/power-pro-v3/internal/database"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
 	"github.com/waynenilsen/power-pro-v3/internal/profile"
+	"github.com/waynenilsen/power-pro-v3/internal/strength"
 )
 
 // =============================================================================
@@ -46,12 +48,22 @@ func (m *mockProfileRepo) Update(ctx context.Context, userID string, update prof
 	return nil, nil
 }
 
+func (m *mockProfileRepo) WithTx(tx *sql.Tx) profile.ProfileRepository {
+	return m
+}
+
 func setupTestDB(t *testing.T) (*sql.DB, func()) {
 	db, cleanup, err := database.OpenTemp("../../migrations")
 	require.NoError(t, err)
 	return db, cleanup
 }
 
+// newTestService creates a dashboard service wired with the strength score service it depends on.
+func newTestService(sqlDB *sql.DB, profileSvc *profile.Service) *Service {
+	bodyweightSvc := bodyweight.NewService(bodyweight.NewSQLiteRepository(sqlDB), profileSvc)
+	return NewService(sqlDB, profileSvc, strength.NewService(sqlDB, profileSvc, bodyweightSvc))
+}
+
 // =============================================================================
 // UNIT TESTS FOR DASHBOARD SERVICE (REQ-TD2-008)
 // =============================================================================
@@ -63,11 +75,12 @@ func TestNewService(t *testing.T) {
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	require.NotNil(t, svc)
 	assert.NotNil(t, svc.db)
 	assert.NotNil(t, svc.queries)
 	assert.NotNil(t, svc.profileService)
+	assert.NotNil(t, svc.strengthService)
 }
 
 func TestGetDashboard_NoEnrollment(t *testing.T) {
@@ -82,7 +95,7 @@ func TestGetDashboard_NoEnrollment(t *testing.T) {
 	}
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// test-user-001 exists but has no enrollment
@@ -109,7 +122,7 @@ func TestGetDashboard_ProfileError(t *testing.T) {
 	profileRepo.getErr = apperrors.NewInternal("database error", nil)
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// Should still return a dashboard, just with default weight unit
@@ -125,7 +138,7 @@ func TestGetDashboard_NonexistentUser(t *testing.T) {
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// Should return empty dashboard for nonexistent user
@@ -230,7 +243,7 @@ func TestAggregateEnrollment_NoEnrollment(t *testing.T) {
 
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	enrollment, err := svc.aggregateEnrollment(ctx, "nonexistent-user")
@@ -244,7 +257,7 @@ func TestGetCurrentSession_NoSession(t *testing.T) {
 
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	session, err := svc.getCurrentSession(ctx, "test-user-001")
@@ -258,7 +271,7 @@ func TestCalculateNextWorkout_NoState(t *testing.T) {
 
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	nextWorkout, err := svc.calculateNextWorkout(ctx, "nonexistent-user")
@@ -275,7 +288,7 @@ func TestGetCurrentMaxes_NoMaxes(t *testing.T) {
 
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	maxes, err := svc.getCurrentMaxes(ctx, "nonexistent-user", "lb")
@@ -290,7 +303,7 @@ func TestGetCurrentMaxes_SortedByLiftName(t *testing.T) {
 
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// Maxes should be sorted alphabetically by lift name
@@ -328,7 +341,7 @@ func TestGetDayForWeekPosition_NotFound(t *testing.T) {
 
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// Should return nil for nonexistent program
@@ -343,7 +356,7 @@ func TestGetTotalSetsForDay_NoState(t *testing.T) {
 
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// Should return 0 for nonexistent user
@@ -358,7 +371,7 @@ func TestGetDayNameForSession_NoState(t *testing.T) {
 
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// Should return error for nonexistent user
@@ -378,7 +391,7 @@ func TestGetDashboard_WeightUnitPreference(t *testing.T) {
 		profileRepo := newMockProfileRepo()
 		// No profile added - will return not found
 		profileSvc := profile.NewService(profileRepo)
-		svc := NewService(db, profileSvc)
+		svc := newTestService(db, profileSvc)
 		ctx := context.Background()
 
 		dashboard, err := svc.GetDashboard(ctx, "test-user-001")
@@ -395,7 +408,7 @@ func TestGetDashboard_WeightUnitPreference(t *testing.T) {
 			WeightUnit: "lb",
 		}
 		profileSvc := profile.NewService(profileRepo)
-		svc := NewService(db, profileSvc)
+		svc := newTestService(db, profileSvc)
 		ctx := context.Background()
 
 		dashboard, err := svc.GetDashboard(ctx, "test-user-001")
@@ -411,7 +424,7 @@ func TestGetDashboard_WeightUnitPreference(t *testing.T) {
 			WeightUnit: "kg",
 		}
 		profileSvc := profile.NewService(profileRepo)
-		svc := NewService(db, profileSvc)
+		svc := newTestService(db, profileSvc)
 		ctx := context.Background()
 
 		dashboard, err := svc.GetDashboard(ctx, "test-user-001")
@@ -435,7 +448,7 @@ func TestDashboard_EmptyStateHandling(t *testing.T) {
 		WeightUnit: "lb",
 	}
 	profileSvc := profile.NewService(profileRepo)
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	t.Run("new user gets empty dashboard", func(t *testing.T) {
@@ -479,7 +492,7 @@ func TestNextWorkout_OnlyWhenEnrolledAndNoSession(t *testing.T) {
 		WeightUnit: "lb",
 	}
 	profileSvc := profile.NewService(profileRepo)
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// User with no enrollment should not have next workout
@@ -719,7 +732,7 @@ func TestGetDashboard_WithCompleteData(t *testing.T) {
 	}
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	t.Run("returns enrollment summary when enrolled", func(t *testing.T) {
@@ -802,7 +815,7 @@ func TestGetCurrentSession_WithSetsLogged(t *testing.T) {
 	}
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// Create active session with logged sets
@@ -843,7 +856,7 @@ func TestGetRecentWorkouts_WithCompletedSessions(t *testing.T) {
 	}
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	prescID := "presc-" + userID
@@ -872,7 +885,7 @@ func TestGetExerciseAndSetCounts(t *testing.T) {
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// Create a day with multiple prescriptions
@@ -920,7 +933,7 @@ func TestGetExerciseAndSetCounts_EmptyDay(t *testing.T) {
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// Create a day with no prescriptions
@@ -940,7 +953,7 @@ func TestGetExerciseAndSetCounts_AllSchemeTypes(t *testing.T) {
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// Test all scheme types from the CASE statement in the query
@@ -984,7 +997,7 @@ func TestAggregateEnrollment_ReturnsFullData(t *testing.T) {
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	enrollment, err := svc.aggregateEnrollment(ctx, userID)
@@ -1008,7 +1021,7 @@ func TestCalculateNextWorkout_ReturnsCorrectData(t *testing.T) {
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	nextWorkout, err := svc.calculateNextWorkout(ctx, userID)
@@ -1030,7 +1043,7 @@ func TestGetDayNameForSession_Success(t *testing.T) {
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	dayName, err := svc.getDayNameForSession(ctx, userID, 1, 0)
@@ -1048,7 +1061,7 @@ func TestGetTotalSetsForDay_Success(t *testing.T) {
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	totalSets, err := svc.getTotalSetsForDay(ctx, userID, 1, 0)
@@ -1066,7 +1079,7 @@ func TestGetTotalSetsForDay_NilDay(t *testing.T) {
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// Ask for a day index that doesn't exist (we only have day index 0)
@@ -1100,7 +1113,7 @@ func TestCalculateNextWorkout_NilDay(t *testing.T) {
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// Should return nil when no day is found
@@ -1134,7 +1147,7 @@ func TestGetDayNameForSession_NilDay(t *testing.T) {
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	// Should return "Unknown Day" when day is nil
@@ -1168,7 +1181,7 @@ func TestGetCurrentMaxes_MultipleMaxesSorted(t *testing.T) {
 	}
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	maxes, err := svc.getCurrentMaxes(ctx, userID, "lb")
@@ -1198,7 +1211,7 @@ func TestGetCurrentMaxes_OnlyMostRecentPerLift(t *testing.T) {
 	profileRepo := newMockProfileRepo()
 	profileSvc := profile.NewService(profileRepo)
 
-	svc := NewService(db, profileSvc)
+	svc := newTestService(db, profileSvc)
 	ctx := context.Background()
 
 	maxes, err := svc.getCurrentMaxes(ctx, userID, "lb")
@@ -1207,3 +1220,35 @@ func TestGetCurrentMaxes_OnlyMostRecentPerLift(t *testing.T) {
 	assert.Equal(t, "Squat", maxes[0].Lift)
 	assert.Equal(t, 315.0, maxes[0].Value, "should return the most recent max value")
 }
+
+func TestGetRosterDashboard(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	activeID, idleID := "roster-active-user", "roster-idle-user"
+	stateID, _, _ := setupCompleteTestData(t, db, activeID)
+	createTestUser(t, db, idleID, idleID+"@example.com")
+	createTestWorkoutSession(t, db, "roster-active", stateID, 1, 0, "IN_PROGRESS", "")
+
+	profileRepo := newMockProfileRepo()
+	for _, userID := range []string{activeID, idleID} {
+		profileRepo.profiles[userID] = &profile.Profile{ID: userID, WeightUnit: "lb"}
+	}
+	svc := newTestService(db, profile.NewService(profileRepo))
+
+	roster, err := svc.GetRosterDashboard(context.Background(), []string{idleID, activeID})
+	require.NoError(t, err)
+
+	assert.Equal(t, RosterSummary{Users: 2, Enrolled: 1, InSession: 1}, roster.Summary)
+	require.Len(t, roster.Entries, 2)
+	assert.Equal(t, idleID, roster.Entries[0].UserID)
+	assert.Nil(t, roster.Entries[0].Dashboard.Enrollment)
+	assert.Equal(t, activeID, roster.Entries[1].UserID)
+	require.NotNil(t, roster.Entries[1].Dashboard.CurrentSession)
+	assert.Equal(t, "roster-active", roster.Entries[1].Dashboard.CurrentSession.SessionID)
+
+	empty, err := svc.GetRosterDashboard(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, RosterSummary{}, empty.Summary)
+	assert.Empty(t, empty.Entries)
+}
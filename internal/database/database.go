@@ -27,7 +27,9 @@ func Open(cfg Config) (*sql.DB, error) {
 		}
 	}
 
-	db, err := sql.Open("sqlite3", cfg.Path+"?_foreign_keys=on")
+	// Transactions take the write lock when they begin, so concurrent writers such as
+	// the event dispatcher wait for each other instead of failing to upgrade a read lock.
+	db, err := sql.Open("sqlite3", cfg.Path+"?_foreign_keys=on&_txlock=immediate")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -12,27 +12,30 @@ import (
 
 const countDailyLookups = `-- name: CountDailyLookups :one
 SELECT COUNT(*) FROM daily_lookups
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 `
 
-func (q *Queries) CountDailyLookups(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countDailyLookups)
+func (q *Queries) CountDailyLookups(ctx context.Context, viewerID interface{}) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countDailyLookups, viewerID)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const createDailyLookup = `-- name: CreateDailyLookup :exec
-INSERT INTO daily_lookups (id, name, entries, program_id, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?)
+INSERT INTO daily_lookups (id, name, entries, program_id, created_at, updated_at, organization_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateDailyLookupParams struct {
-	ID        string         `json:"id"`
-	Name      string         `json:"name"`
-	Entries   string         `json:"entries"`
-	ProgramID sql.NullString `json:"program_id"`
-	CreatedAt string         `json:"created_at"`
-	UpdatedAt string         `json:"updated_at"`
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	Entries        string         `json:"entries"`
+	ProgramID      sql.NullString `json:"program_id"`
+	CreatedAt      string         `json:"created_at"`
+	UpdatedAt      string         `json:"updated_at"`
+	OrganizationID sql.NullString `json:"organization_id"`
 }
 
 func (q *Queries) CreateDailyLookup(ctx context.Context, arg CreateDailyLookupParams) error {
@@ -43,6 +46,7 @@ func (q *Queries) CreateDailyLookup(ctx context.Context, arg CreateDailyLookupPa
 		arg.ProgramID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.OrganizationID,
 	)
 	return err
 }
@@ -71,7 +75,7 @@ func (q *Queries) DeleteDailyLookup(ctx context.Context, id string) error {
 }
 
 const getDailyLookup = `-- name: GetDailyLookup :one
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM daily_lookups
 WHERE id = ?
 `
@@ -86,24 +90,28 @@ func (q *Queries) GetDailyLookup(ctx context.Context, id string) (DailyLookup, e
 		&i.ProgramID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.OrganizationID,
 	)
 	return i, err
 }
 
 const listDailyLookupsByCreatedAtAsc = `-- name: ListDailyLookupsByCreatedAtAsc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM daily_lookups
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 ORDER BY created_at ASC
-LIMIT ? OFFSET ?
+LIMIT ?3 OFFSET ?2
 `
 
 type ListDailyLookupsByCreatedAtAscParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	ViewerID interface{} `json:"viewer_id"`
+	Offset   int64       `json:"offset"`
+	Limit    int64       `json:"limit"`
 }
 
 func (q *Queries) ListDailyLookupsByCreatedAtAsc(ctx context.Context, arg ListDailyLookupsByCreatedAtAscParams) ([]DailyLookup, error) {
-	rows, err := q.db.QueryContext(ctx, listDailyLookupsByCreatedAtAsc, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listDailyLookupsByCreatedAtAsc, arg.ViewerID, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -118,6 +126,7 @@ func (q *Queries) ListDailyLookupsByCreatedAtAsc(ctx context.Context, arg ListDa
 			&i.ProgramID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -133,19 +142,22 @@ func (q *Queries) ListDailyLookupsByCreatedAtAsc(ctx context.Context, arg ListDa
 }
 
 const listDailyLookupsByCreatedAtDesc = `-- name: ListDailyLookupsByCreatedAtDesc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM daily_lookups
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 ORDER BY created_at DESC
-LIMIT ? OFFSET ?
+LIMIT ?3 OFFSET ?2
 `
 
 type ListDailyLookupsByCreatedAtDescParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	ViewerID interface{} `json:"viewer_id"`
+	Offset   int64       `json:"offset"`
+	Limit    int64       `json:"limit"`
 }
 
 func (q *Queries) ListDailyLookupsByCreatedAtDesc(ctx context.Context, arg ListDailyLookupsByCreatedAtDescParams) ([]DailyLookup, error) {
-	rows, err := q.db.QueryContext(ctx, listDailyLookupsByCreatedAtDesc, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listDailyLookupsByCreatedAtDesc, arg.ViewerID, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +172,7 @@ func (q *Queries) ListDailyLookupsByCreatedAtDesc(ctx context.Context, arg ListD
 			&i.ProgramID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -175,19 +188,22 @@ func (q *Queries) ListDailyLookupsByCreatedAtDesc(ctx context.Context, arg ListD
 }
 
 const listDailyLookupsByNameAsc = `-- name: ListDailyLookupsByNameAsc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM daily_lookups
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 ORDER BY name ASC
-LIMIT ? OFFSET ?
+LIMIT ?3 OFFSET ?2
 `
 
 type ListDailyLookupsByNameAscParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	ViewerID interface{} `json:"viewer_id"`
+	Offset   int64       `json:"offset"`
+	Limit    int64       `json:"limit"`
 }
 
 func (q *Queries) ListDailyLookupsByNameAsc(ctx context.Context, arg ListDailyLookupsByNameAscParams) ([]DailyLookup, error) {
-	rows, err := q.db.QueryContext(ctx, listDailyLookupsByNameAsc, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listDailyLookupsByNameAsc, arg.ViewerID, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -202,6 +218,7 @@ func (q *Queries) ListDailyLookupsByNameAsc(ctx context.Context, arg ListDailyLo
 			&i.ProgramID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -217,19 +234,22 @@ func (q *Queries) ListDailyLookupsByNameAsc(ctx context.Context, arg ListDailyLo
 }
 
 const listDailyLookupsByNameDesc = `-- name: ListDailyLookupsByNameDesc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM daily_lookups
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 ORDER BY name DESC
-LIMIT ? OFFSET ?
+LIMIT ?3 OFFSET ?2
 `
 
 type ListDailyLookupsByNameDescParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	ViewerID interface{} `json:"viewer_id"`
+	Offset   int64       `json:"offset"`
+	Limit    int64       `json:"limit"`
 }
 
 func (q *Queries) ListDailyLookupsByNameDesc(ctx context.Context, arg ListDailyLookupsByNameDescParams) ([]DailyLookup, error) {
-	rows, err := q.db.QueryContext(ctx, listDailyLookupsByNameDesc, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listDailyLookupsByNameDesc, arg.ViewerID, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -244,6 +264,7 @@ func (q *Queries) ListDailyLookupsByNameDesc(ctx context.Context, arg ListDailyL
 			&i.ProgramID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -10,60 +10,30 @@ import (
 )
 
 const countLiftMaxesByUser = `-- name: CountLiftMaxesByUser :one
-SELECT COUNT(*) FROM lift_maxes WHERE user_id = ?
+SELECT COUNT(*) FROM lift_maxes
+WHERE user_id = ?1
+  AND (?2 IS NULL OR lift_id = ?2)
+  AND (?3 IS NULL OR type = ?3)
+  AND (?4 IS NULL OR effective_date >= ?4)
+  AND (?5 IS NULL OR effective_date < ?5)
 `
 
-func (q *Queries) CountLiftMaxesByUser(ctx context.Context, userID string) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countLiftMaxesByUser, userID)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
-const countLiftMaxesByUserFilterLift = `-- name: CountLiftMaxesByUserFilterLift :one
-SELECT COUNT(*) FROM lift_maxes WHERE user_id = ? AND lift_id = ?
-`
-
-type CountLiftMaxesByUserFilterLiftParams struct {
-	UserID string `json:"user_id"`
-	LiftID string `json:"lift_id"`
-}
-
-func (q *Queries) CountLiftMaxesByUserFilterLift(ctx context.Context, arg CountLiftMaxesByUserFilterLiftParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countLiftMaxesByUserFilterLift, arg.UserID, arg.LiftID)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
-const countLiftMaxesByUserFilterLiftAndType = `-- name: CountLiftMaxesByUserFilterLiftAndType :one
-SELECT COUNT(*) FROM lift_maxes WHERE user_id = ? AND lift_id = ? AND type = ?
-`
-
-type CountLiftMaxesByUserFilterLiftAndTypeParams struct {
-	UserID string `json:"user_id"`
-	LiftID string `json:"lift_id"`
-	Type   string `json:"type"`
+type CountLiftMaxesByUserParams struct {
+	UserID     string      `json:"user_id"`
+	LiftID     interface{} `json:"lift_id"`
+	Type       interface{} `json:"type"`
+	FromTime   interface{} `json:"from_time"`
+	BeforeTime interface{} `json:"before_time"`
 }
 
-func (q *Queries) CountLiftMaxesByUserFilterLiftAndType(ctx context.Context, arg CountLiftMaxesByUserFilterLiftAndTypeParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countLiftMaxesByUserFilterLiftAndType, arg.UserID, arg.LiftID, arg.Type)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
-const countLiftMaxesByUserFilterType = `-- name: CountLiftMaxesByUserFilterType :one
-SELECT COUNT(*) FROM lift_maxes WHERE user_id = ? AND type = ?
-`
-
-type CountLiftMaxesByUserFilterTypeParams struct {
-	UserID string `json:"user_id"`
-	Type   string `json:"type"`
-}
-
-func (q *Queries) CountLiftMaxesByUserFilterType(ctx context.Context, arg CountLiftMaxesByUserFilterTypeParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countLiftMaxesByUserFilterType, arg.UserID, arg.Type)
+func (q *Queries) CountLiftMaxesByUser(ctx context.Context, arg CountLiftMaxesByUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countLiftMaxesByUser,
+		arg.UserID,
+		arg.LiftID,
+		arg.Type,
+		arg.FromTime,
+		arg.BeforeTime,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -201,191 +171,65 @@ func (q *Queries) LiftHasMaxReferences(ctx context.Context, liftID string) (int6
 }
 
 const listLiftMaxesByUserByEffectiveDateAsc = `-- name: ListLiftMaxesByUserByEffectiveDateAsc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
+SELECT lift_maxes.id, lift_maxes.user_id, lift_maxes.lift_id, lift_maxes.type, lift_maxes.value, lift_maxes.effective_date, lift_maxes.created_at, lift_maxes.updated_at, rowid AS seq
 FROM lift_maxes
-WHERE user_id = ?
-ORDER BY effective_date ASC
-LIMIT ? OFFSET ?
+WHERE user_id = ?1
+  AND (?2 IS NULL OR lift_id = ?2)
+  AND (?3 IS NULL OR type = ?3)
+  AND (?4 IS NULL OR effective_date >= ?4)
+  AND (?5 IS NULL OR effective_date < ?5)
+  AND effective_date >= ?6
+  AND (effective_date > ?6 OR rowid > ?7)
+ORDER BY effective_date ASC, rowid ASC
+LIMIT ?9 OFFSET ?8
 `
 
 type ListLiftMaxesByUserByEffectiveDateAscParams struct {
-	UserID string `json:"user_id"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
-}
-
-func (q *Queries) ListLiftMaxesByUserByEffectiveDateAsc(ctx context.Context, arg ListLiftMaxesByUserByEffectiveDateAscParams) ([]LiftMax, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftMaxesByUserByEffectiveDateAsc, arg.UserID, arg.Limit, arg.Offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []LiftMax{}
-	for rows.Next() {
-		var i LiftMax
-		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.LiftID,
-			&i.Type,
-			&i.Value,
-			&i.EffectiveDate,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const listLiftMaxesByUserByEffectiveDateDesc = `-- name: ListLiftMaxesByUserByEffectiveDateDesc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
-FROM lift_maxes
-WHERE user_id = ?
-ORDER BY effective_date DESC
-LIMIT ? OFFSET ?
-`
-
-type ListLiftMaxesByUserByEffectiveDateDescParams struct {
-	UserID string `json:"user_id"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
+	UserID     string      `json:"user_id"`
+	LiftID     interface{} `json:"lift_id"`
+	Type       interface{} `json:"type"`
+	FromTime   interface{} `json:"from_time"`
+	BeforeTime interface{} `json:"before_time"`
+	AfterValue string      `json:"after_value"`
+	AfterSeq   int64       `json:"after_seq"`
+	Offset     int64       `json:"offset"`
+	Limit      int64       `json:"limit"`
 }
 
-func (q *Queries) ListLiftMaxesByUserByEffectiveDateDesc(ctx context.Context, arg ListLiftMaxesByUserByEffectiveDateDescParams) ([]LiftMax, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftMaxesByUserByEffectiveDateDesc, arg.UserID, arg.Limit, arg.Offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []LiftMax{}
-	for rows.Next() {
-		var i LiftMax
-		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.LiftID,
-			&i.Type,
-			&i.Value,
-			&i.EffectiveDate,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type ListLiftMaxesByUserByEffectiveDateAscRow struct {
+	LiftMax LiftMax `json:"lift_max"`
+	Seq     int64   `json:"seq"`
 }
 
-const listLiftMaxesByUserFilterLiftAndTypeByEffectiveDateAsc = `-- name: ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateAsc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
-FROM lift_maxes
-WHERE user_id = ? AND lift_id = ? AND type = ?
-ORDER BY effective_date ASC
-LIMIT ? OFFSET ?
-`
-
-type ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateAscParams struct {
-	UserID string `json:"user_id"`
-	LiftID string `json:"lift_id"`
-	Type   string `json:"type"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
-}
-
-func (q *Queries) ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateAsc(ctx context.Context, arg ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateAscParams) ([]LiftMax, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftMaxesByUserFilterLiftAndTypeByEffectiveDateAsc,
+func (q *Queries) ListLiftMaxesByUserByEffectiveDateAsc(ctx context.Context, arg ListLiftMaxesByUserByEffectiveDateAscParams) ([]ListLiftMaxesByUserByEffectiveDateAscRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLiftMaxesByUserByEffectiveDateAsc,
 		arg.UserID,
 		arg.LiftID,
 		arg.Type,
-		arg.Limit,
+		arg.FromTime,
+		arg.BeforeTime,
+		arg.AfterValue,
+		arg.AfterSeq,
 		arg.Offset,
-	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []LiftMax{}
-	for rows.Next() {
-		var i LiftMax
-		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.LiftID,
-			&i.Type,
-			&i.Value,
-			&i.EffectiveDate,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const listLiftMaxesByUserFilterLiftAndTypeByEffectiveDateDesc = `-- name: ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateDesc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
-FROM lift_maxes
-WHERE user_id = ? AND lift_id = ? AND type = ?
-ORDER BY effective_date DESC
-LIMIT ? OFFSET ?
-`
-
-type ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateDescParams struct {
-	UserID string `json:"user_id"`
-	LiftID string `json:"lift_id"`
-	Type   string `json:"type"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
-}
-
-func (q *Queries) ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateDesc(ctx context.Context, arg ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateDescParams) ([]LiftMax, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftMaxesByUserFilterLiftAndTypeByEffectiveDateDesc,
-		arg.UserID,
-		arg.LiftID,
-		arg.Type,
 		arg.Limit,
-		arg.Offset,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []LiftMax{}
+	items := []ListLiftMaxesByUserByEffectiveDateAscRow{}
 	for rows.Next() {
-		var i LiftMax
+		var i ListLiftMaxesByUserByEffectiveDateAscRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.LiftID,
-			&i.Type,
-			&i.Value,
-			&i.EffectiveDate,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.LiftMax.ID,
+			&i.LiftMax.UserID,
+			&i.LiftMax.LiftID,
+			&i.LiftMax.Type,
+			&i.LiftMax.Value,
+			&i.LiftMax.EffectiveDate,
+			&i.LiftMax.CreatedAt,
+			&i.LiftMax.UpdatedAt,
+			&i.Seq,
 		); err != nil {
 			return nil, err
 		}
@@ -400,200 +244,66 @@ func (q *Queries) ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateDesc(ctx co
 	return items, nil
 }
 
-const listLiftMaxesByUserFilterLiftByEffectiveDateAsc = `-- name: ListLiftMaxesByUserFilterLiftByEffectiveDateAsc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
+const listLiftMaxesByUserByEffectiveDateDesc = `-- name: ListLiftMaxesByUserByEffectiveDateDesc :many
+SELECT lift_maxes.id, lift_maxes.user_id, lift_maxes.lift_id, lift_maxes.type, lift_maxes.value, lift_maxes.effective_date, lift_maxes.created_at, lift_maxes.updated_at, rowid AS seq
 FROM lift_maxes
-WHERE user_id = ? AND lift_id = ?
-ORDER BY effective_date ASC
-LIMIT ? OFFSET ?
+WHERE user_id = ?1
+  AND (?2 IS NULL OR lift_id = ?2)
+  AND (?3 IS NULL OR type = ?3)
+  AND (?4 IS NULL OR effective_date >= ?4)
+  AND (?5 IS NULL OR effective_date < ?5)
+  AND effective_date <= ?6
+  AND (effective_date < ?6 OR rowid < ?7)
+ORDER BY effective_date DESC, rowid DESC
+LIMIT ?9 OFFSET ?8
 `
 
-type ListLiftMaxesByUserFilterLiftByEffectiveDateAscParams struct {
-	UserID string `json:"user_id"`
-	LiftID string `json:"lift_id"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
-}
-
-func (q *Queries) ListLiftMaxesByUserFilterLiftByEffectiveDateAsc(ctx context.Context, arg ListLiftMaxesByUserFilterLiftByEffectiveDateAscParams) ([]LiftMax, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftMaxesByUserFilterLiftByEffectiveDateAsc,
-		arg.UserID,
-		arg.LiftID,
-		arg.Limit,
-		arg.Offset,
-	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []LiftMax{}
-	for rows.Next() {
-		var i LiftMax
-		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.LiftID,
-			&i.Type,
-			&i.Value,
-			&i.EffectiveDate,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type ListLiftMaxesByUserByEffectiveDateDescParams struct {
+	UserID     string      `json:"user_id"`
+	LiftID     interface{} `json:"lift_id"`
+	Type       interface{} `json:"type"`
+	FromTime   interface{} `json:"from_time"`
+	BeforeTime interface{} `json:"before_time"`
+	AfterValue string      `json:"after_value"`
+	AfterSeq   int64       `json:"after_seq"`
+	Offset     int64       `json:"offset"`
+	Limit      int64       `json:"limit"`
 }
 
-const listLiftMaxesByUserFilterLiftByEffectiveDateDesc = `-- name: ListLiftMaxesByUserFilterLiftByEffectiveDateDesc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
-FROM lift_maxes
-WHERE user_id = ? AND lift_id = ?
-ORDER BY effective_date DESC
-LIMIT ? OFFSET ?
-`
-
-type ListLiftMaxesByUserFilterLiftByEffectiveDateDescParams struct {
-	UserID string `json:"user_id"`
-	LiftID string `json:"lift_id"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
+type ListLiftMaxesByUserByEffectiveDateDescRow struct {
+	LiftMax LiftMax `json:"lift_max"`
+	Seq     int64   `json:"seq"`
 }
 
-func (q *Queries) ListLiftMaxesByUserFilterLiftByEffectiveDateDesc(ctx context.Context, arg ListLiftMaxesByUserFilterLiftByEffectiveDateDescParams) ([]LiftMax, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftMaxesByUserFilterLiftByEffectiveDateDesc,
+func (q *Queries) ListLiftMaxesByUserByEffectiveDateDesc(ctx context.Context, arg ListLiftMaxesByUserByEffectiveDateDescParams) ([]ListLiftMaxesByUserByEffectiveDateDescRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLiftMaxesByUserByEffectiveDateDesc,
 		arg.UserID,
 		arg.LiftID,
-		arg.Limit,
-		arg.Offset,
-	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []LiftMax{}
-	for rows.Next() {
-		var i LiftMax
-		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.LiftID,
-			&i.Type,
-			&i.Value,
-			&i.EffectiveDate,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const listLiftMaxesByUserFilterTypeByEffectiveDateAsc = `-- name: ListLiftMaxesByUserFilterTypeByEffectiveDateAsc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
-FROM lift_maxes
-WHERE user_id = ? AND type = ?
-ORDER BY effective_date ASC
-LIMIT ? OFFSET ?
-`
-
-type ListLiftMaxesByUserFilterTypeByEffectiveDateAscParams struct {
-	UserID string `json:"user_id"`
-	Type   string `json:"type"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
-}
-
-func (q *Queries) ListLiftMaxesByUserFilterTypeByEffectiveDateAsc(ctx context.Context, arg ListLiftMaxesByUserFilterTypeByEffectiveDateAscParams) ([]LiftMax, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftMaxesByUserFilterTypeByEffectiveDateAsc,
-		arg.UserID,
 		arg.Type,
-		arg.Limit,
+		arg.FromTime,
+		arg.BeforeTime,
+		arg.AfterValue,
+		arg.AfterSeq,
 		arg.Offset,
-	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []LiftMax{}
-	for rows.Next() {
-		var i LiftMax
-		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.LiftID,
-			&i.Type,
-			&i.Value,
-			&i.EffectiveDate,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const listLiftMaxesByUserFilterTypeByEffectiveDateDesc = `-- name: ListLiftMaxesByUserFilterTypeByEffectiveDateDesc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
-FROM lift_maxes
-WHERE user_id = ? AND type = ?
-ORDER BY effective_date DESC
-LIMIT ? OFFSET ?
-`
-
-type ListLiftMaxesByUserFilterTypeByEffectiveDateDescParams struct {
-	UserID string `json:"user_id"`
-	Type   string `json:"type"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
-}
-
-func (q *Queries) ListLiftMaxesByUserFilterTypeByEffectiveDateDesc(ctx context.Context, arg ListLiftMaxesByUserFilterTypeByEffectiveDateDescParams) ([]LiftMax, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftMaxesByUserFilterTypeByEffectiveDateDesc,
-		arg.UserID,
-		arg.Type,
 		arg.Limit,
-		arg.Offset,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []LiftMax{}
+	items := []ListLiftMaxesByUserByEffectiveDateDescRow{}
 	for rows.Next() {
-		var i LiftMax
+		var i ListLiftMaxesByUserByEffectiveDateDescRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.LiftID,
-			&i.Type,
-			&i.Value,
-			&i.EffectiveDate,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.LiftMax.ID,
+			&i.LiftMax.UserID,
+			&i.LiftMax.LiftID,
+			&i.LiftMax.Type,
+			&i.LiftMax.Value,
+			&i.LiftMax.EffectiveDate,
+			&i.LiftMax.CreatedAt,
+			&i.LiftMax.UpdatedAt,
+			&i.Seq,
 		); err != nil {
 			return nil, err
 		}
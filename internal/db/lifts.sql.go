@@ -12,29 +12,39 @@ import (
 
 const countLifts = `-- name: CountLifts :one
 SELECT COUNT(*) FROM lifts
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 `
 
-func (q *Queries) CountLifts(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countLifts)
+func (q *Queries) CountLifts(ctx context.Context, viewerID interface{}) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countLifts, viewerID)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const countLiftsFilteredByCompetition = `-- name: CountLiftsFilteredByCompetition :one
-SELECT COUNT(*) FROM lifts WHERE is_competition_lift = ?
+SELECT COUNT(*) FROM lifts
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
+  AND is_competition_lift = ?2
 `
 
-func (q *Queries) CountLiftsFilteredByCompetition(ctx context.Context, isCompetitionLift int64) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countLiftsFilteredByCompetition, isCompetitionLift)
+type CountLiftsFilteredByCompetitionParams struct {
+	ViewerID          interface{} `json:"viewer_id"`
+	IsCompetitionLift int64       `json:"is_competition_lift"`
+}
+
+func (q *Queries) CountLiftsFilteredByCompetition(ctx context.Context, arg CountLiftsFilteredByCompetitionParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countLiftsFilteredByCompetition, arg.ViewerID, arg.IsCompetitionLift)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const createLift = `-- name: CreateLift :exec
-INSERT INTO lifts (id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO lifts (id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateLiftParams struct {
@@ -45,6 +55,7 @@ type CreateLiftParams struct {
 	ParentLiftID      sql.NullString `json:"parent_lift_id"`
 	CreatedAt         string         `json:"created_at"`
 	UpdatedAt         string         `json:"updated_at"`
+	OrganizationID    sql.NullString `json:"organization_id"`
 }
 
 func (q *Queries) CreateLift(ctx context.Context, arg CreateLiftParams) error {
@@ -56,6 +67,7 @@ func (q *Queries) CreateLift(ctx context.Context, arg CreateLiftParams) error {
 		arg.ParentLiftID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.OrganizationID,
 	)
 	return err
 }
@@ -70,7 +82,7 @@ func (q *Queries) DeleteLift(ctx context.Context, id string) error {
 }
 
 const getLift = `-- name: GetLift :one
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
 WHERE id = ?
 `
@@ -86,12 +98,13 @@ func (q *Queries) GetLift(ctx context.Context, id string) (Lift, error) {
 		&i.ParentLiftID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.OrganizationID,
 	)
 	return i, err
 }
 
 const getLiftBySlug = `-- name: GetLiftBySlug :one
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
 WHERE slug = ?
 `
@@ -107,6 +120,7 @@ func (q *Queries) GetLiftBySlug(ctx context.Context, slug string) (Lift, error)
 		&i.ParentLiftID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.OrganizationID,
 	)
 	return i, err
 }
@@ -123,19 +137,22 @@ func (q *Queries) LiftHasChildReferences(ctx context.Context, parentLiftID sql.N
 }
 
 const listLiftsByCreatedAtAsc = `-- name: ListLiftsByCreatedAtAsc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 ORDER BY created_at ASC
-LIMIT ? OFFSET ?
+LIMIT ?3 OFFSET ?2
 `
 
 type ListLiftsByCreatedAtAscParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	ViewerID interface{} `json:"viewer_id"`
+	Offset   int64       `json:"offset"`
+	Limit    int64       `json:"limit"`
 }
 
 func (q *Queries) ListLiftsByCreatedAtAsc(ctx context.Context, arg ListLiftsByCreatedAtAscParams) ([]Lift, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftsByCreatedAtAsc, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listLiftsByCreatedAtAsc, arg.ViewerID, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -151,6 +168,7 @@ func (q *Queries) ListLiftsByCreatedAtAsc(ctx context.Context, arg ListLiftsByCr
 			&i.ParentLiftID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -166,19 +184,22 @@ func (q *Queries) ListLiftsByCreatedAtAsc(ctx context.Context, arg ListLiftsByCr
 }
 
 const listLiftsByCreatedAtDesc = `-- name: ListLiftsByCreatedAtDesc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 ORDER BY created_at DESC
-LIMIT ? OFFSET ?
+LIMIT ?3 OFFSET ?2
 `
 
 type ListLiftsByCreatedAtDescParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	ViewerID interface{} `json:"viewer_id"`
+	Offset   int64       `json:"offset"`
+	Limit    int64       `json:"limit"`
 }
 
 func (q *Queries) ListLiftsByCreatedAtDesc(ctx context.Context, arg ListLiftsByCreatedAtDescParams) ([]Lift, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftsByCreatedAtDesc, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listLiftsByCreatedAtDesc, arg.ViewerID, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -194,6 +215,7 @@ func (q *Queries) ListLiftsByCreatedAtDesc(ctx context.Context, arg ListLiftsByC
 			&i.ParentLiftID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -209,19 +231,22 @@ func (q *Queries) ListLiftsByCreatedAtDesc(ctx context.Context, arg ListLiftsByC
 }
 
 const listLiftsByNameAsc = `-- name: ListLiftsByNameAsc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 ORDER BY name ASC
-LIMIT ? OFFSET ?
+LIMIT ?3 OFFSET ?2
 `
 
 type ListLiftsByNameAscParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	ViewerID interface{} `json:"viewer_id"`
+	Offset   int64       `json:"offset"`
+	Limit    int64       `json:"limit"`
 }
 
 func (q *Queries) ListLiftsByNameAsc(ctx context.Context, arg ListLiftsByNameAscParams) ([]Lift, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftsByNameAsc, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listLiftsByNameAsc, arg.ViewerID, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -237,6 +262,7 @@ func (q *Queries) ListLiftsByNameAsc(ctx context.Context, arg ListLiftsByNameAsc
 			&i.ParentLiftID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -252,19 +278,22 @@ func (q *Queries) ListLiftsByNameAsc(ctx context.Context, arg ListLiftsByNameAsc
 }
 
 const listLiftsByNameDesc = `-- name: ListLiftsByNameDesc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 ORDER BY name DESC
-LIMIT ? OFFSET ?
+LIMIT ?3 OFFSET ?2
 `
 
 type ListLiftsByNameDescParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	ViewerID interface{} `json:"viewer_id"`
+	Offset   int64       `json:"offset"`
+	Limit    int64       `json:"limit"`
 }
 
 func (q *Queries) ListLiftsByNameDesc(ctx context.Context, arg ListLiftsByNameDescParams) ([]Lift, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftsByNameDesc, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listLiftsByNameDesc, arg.ViewerID, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -280,6 +309,7 @@ func (q *Queries) ListLiftsByNameDesc(ctx context.Context, arg ListLiftsByNameDe
 			&i.ParentLiftID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -295,21 +325,29 @@ func (q *Queries) ListLiftsByNameDesc(ctx context.Context, arg ListLiftsByNameDe
 }
 
 const listLiftsFilteredByCompetitionByCreatedAtAsc = `-- name: ListLiftsFilteredByCompetitionByCreatedAtAsc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
-WHERE is_competition_lift = ?
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
+  AND is_competition_lift = ?2
 ORDER BY created_at ASC
-LIMIT ? OFFSET ?
+LIMIT ?4 OFFSET ?3
 `
 
 type ListLiftsFilteredByCompetitionByCreatedAtAscParams struct {
-	IsCompetitionLift int64 `json:"is_competition_lift"`
-	Limit             int64 `json:"limit"`
-	Offset            int64 `json:"offset"`
+	ViewerID          interface{} `json:"viewer_id"`
+	IsCompetitionLift int64       `json:"is_competition_lift"`
+	Offset            int64       `json:"offset"`
+	Limit             int64       `json:"limit"`
 }
 
 func (q *Queries) ListLiftsFilteredByCompetitionByCreatedAtAsc(ctx context.Context, arg ListLiftsFilteredByCompetitionByCreatedAtAscParams) ([]Lift, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftsFilteredByCompetitionByCreatedAtAsc, arg.IsCompetitionLift, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listLiftsFilteredByCompetitionByCreatedAtAsc,
+		arg.ViewerID,
+		arg.IsCompetitionLift,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -325,6 +363,7 @@ func (q *Queries) ListLiftsFilteredByCompetitionByCreatedAtAsc(ctx context.Conte
 			&i.ParentLiftID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -340,21 +379,29 @@ func (q *Queries) ListLiftsFilteredByCompetitionByCreatedAtAsc(ctx context.Conte
 }
 
 const listLiftsFilteredByCompetitionByCreatedAtDesc = `-- name: ListLiftsFilteredByCompetitionByCreatedAtDesc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
-WHERE is_competition_lift = ?
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
+  AND is_competition_lift = ?2
 ORDER BY created_at DESC
-LIMIT ? OFFSET ?
+LIMIT ?4 OFFSET ?3
 `
 
 type ListLiftsFilteredByCompetitionByCreatedAtDescParams struct {
-	IsCompetitionLift int64 `json:"is_competition_lift"`
-	Limit             int64 `json:"limit"`
-	Offset            int64 `json:"offset"`
+	ViewerID          interface{} `json:"viewer_id"`
+	IsCompetitionLift int64       `json:"is_competition_lift"`
+	Offset            int64       `json:"offset"`
+	Limit             int64       `json:"limit"`
 }
 
 func (q *Queries) ListLiftsFilteredByCompetitionByCreatedAtDesc(ctx context.Context, arg ListLiftsFilteredByCompetitionByCreatedAtDescParams) ([]Lift, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftsFilteredByCompetitionByCreatedAtDesc, arg.IsCompetitionLift, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listLiftsFilteredByCompetitionByCreatedAtDesc,
+		arg.ViewerID,
+		arg.IsCompetitionLift,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -370,6 +417,7 @@ func (q *Queries) ListLiftsFilteredByCompetitionByCreatedAtDesc(ctx context.Cont
 			&i.ParentLiftID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -385,21 +433,29 @@ func (q *Queries) ListLiftsFilteredByCompetitionByCreatedAtDesc(ctx context.Cont
 }
 
 const listLiftsFilteredByCompetitionByNameAsc = `-- name: ListLiftsFilteredByCompetitionByNameAsc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
-WHERE is_competition_lift = ?
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
+  AND is_competition_lift = ?2
 ORDER BY name ASC
-LIMIT ? OFFSET ?
+LIMIT ?4 OFFSET ?3
 `
 
 type ListLiftsFilteredByCompetitionByNameAscParams struct {
-	IsCompetitionLift int64 `json:"is_competition_lift"`
-	Limit             int64 `json:"limit"`
-	Offset            int64 `json:"offset"`
+	ViewerID          interface{} `json:"viewer_id"`
+	IsCompetitionLift int64       `json:"is_competition_lift"`
+	Offset            int64       `json:"offset"`
+	Limit             int64       `json:"limit"`
 }
 
 func (q *Queries) ListLiftsFilteredByCompetitionByNameAsc(ctx context.Context, arg ListLiftsFilteredByCompetitionByNameAscParams) ([]Lift, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftsFilteredByCompetitionByNameAsc, arg.IsCompetitionLift, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listLiftsFilteredByCompetitionByNameAsc,
+		arg.ViewerID,
+		arg.IsCompetitionLift,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -415,6 +471,7 @@ func (q *Queries) ListLiftsFilteredByCompetitionByNameAsc(ctx context.Context, a
 			&i.ParentLiftID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -430,21 +487,29 @@ func (q *Queries) ListLiftsFilteredByCompetitionByNameAsc(ctx context.Context, a
 }
 
 const listLiftsFilteredByCompetitionByNameDesc = `-- name: ListLiftsFilteredByCompetitionByNameDesc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
-WHERE is_competition_lift = ?
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
+  AND is_competition_lift = ?2
 ORDER BY name DESC
-LIMIT ? OFFSET ?
+LIMIT ?4 OFFSET ?3
 `
 
 type ListLiftsFilteredByCompetitionByNameDescParams struct {
-	IsCompetitionLift int64 `json:"is_competition_lift"`
-	Limit             int64 `json:"limit"`
-	Offset            int64 `json:"offset"`
+	ViewerID          interface{} `json:"viewer_id"`
+	IsCompetitionLift int64       `json:"is_competition_lift"`
+	Offset            int64       `json:"offset"`
+	Limit             int64       `json:"limit"`
 }
 
 func (q *Queries) ListLiftsFilteredByCompetitionByNameDesc(ctx context.Context, arg ListLiftsFilteredByCompetitionByNameDescParams) ([]Lift, error) {
-	rows, err := q.db.QueryContext(ctx, listLiftsFilteredByCompetitionByNameDesc, arg.IsCompetitionLift, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listLiftsFilteredByCompetitionByNameDesc,
+		arg.ViewerID,
+		arg.IsCompetitionLift,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -460,6 +525,7 @@ func (q *Queries) ListLiftsFilteredByCompetitionByNameDesc(ctx context.Context,
 			&i.ParentLiftID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -11,11 +11,27 @@ import (
 )
 
 const countLoggedSetsByUser = `-- name: CountLoggedSetsByUser :one
-SELECT COUNT(*) FROM logged_sets WHERE user_id = ?
+SELECT COUNT(*) FROM logged_sets
+WHERE user_id = ?1
+  AND (?2 IS NULL OR lift_id = ?2)
+  AND (?3 IS NULL OR created_at >= ?3)
+  AND (?4 IS NULL OR created_at < ?4)
 `
 
-func (q *Queries) CountLoggedSetsByUser(ctx context.Context, userID string) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countLoggedSetsByUser, userID)
+type CountLoggedSetsByUserParams struct {
+	UserID     string      `json:"user_id"`
+	LiftID     interface{} `json:"lift_id"`
+	FromTime   interface{} `json:"from_time"`
+	BeforeTime interface{} `json:"before_time"`
+}
+
+func (q *Queries) CountLoggedSetsByUser(ctx context.Context, arg CountLoggedSetsByUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countLoggedSetsByUser,
+		arg.UserID,
+		arg.LiftID,
+		arg.FromTime,
+		arg.BeforeTime,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -166,6 +182,70 @@ func (q *Queries) GetLoggedSet(ctx context.Context, id string) (GetLoggedSetRow,
 	return i, err
 }
 
+const listLoggedSetsByProgramStateAndLift = `-- name: ListLoggedSetsByProgramStateAndLift :many
+SELECT ls.id, ls.user_id, ls.session_id, ls.prescription_id, ls.lift_id, ls.set_number, ls.weight, ls.target_reps, ls.reps_performed, ls.is_amrap, ls.rpe, ls.created_at
+FROM logged_sets ls
+JOIN workout_sessions ws ON ws.id = ls.session_id
+WHERE ws.user_program_state_id = ? AND ls.lift_id = ?
+ORDER BY ls.created_at ASC, ls.set_number ASC
+`
+
+type ListLoggedSetsByProgramStateAndLiftParams struct {
+	UserProgramStateID string `json:"user_program_state_id"`
+	LiftID             string `json:"lift_id"`
+}
+
+type ListLoggedSetsByProgramStateAndLiftRow struct {
+	ID             string          `json:"id"`
+	UserID         string          `json:"user_id"`
+	SessionID      string          `json:"session_id"`
+	PrescriptionID string          `json:"prescription_id"`
+	LiftID         string          `json:"lift_id"`
+	SetNumber      int64           `json:"set_number"`
+	Weight         float64         `json:"weight"`
+	TargetReps     int64           `json:"target_reps"`
+	RepsPerformed  int64           `json:"reps_performed"`
+	IsAmrap        bool            `json:"is_amrap"`
+	Rpe            sql.NullFloat64 `json:"rpe"`
+	CreatedAt      string          `json:"created_at"`
+}
+
+func (q *Queries) ListLoggedSetsByProgramStateAndLift(ctx context.Context, arg ListLoggedSetsByProgramStateAndLiftParams) ([]ListLoggedSetsByProgramStateAndLiftRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLoggedSetsByProgramStateAndLift, arg.UserProgramStateID, arg.LiftID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLoggedSetsByProgramStateAndLiftRow{}
+	for rows.Next() {
+		var i ListLoggedSetsByProgramStateAndLiftRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.SessionID,
+			&i.PrescriptionID,
+			&i.LiftID,
+			&i.SetNumber,
+			&i.Weight,
+			&i.TargetReps,
+			&i.RepsPerformed,
+			&i.IsAmrap,
+			&i.Rpe,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listLoggedSetsBySession = `-- name: ListLoggedSetsBySession :many
 SELECT id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, rpe, created_at
 FROM logged_sets
@@ -287,21 +367,186 @@ func (q *Queries) ListLoggedSetsBySessionAndPrescription(ctx context.Context, ar
 	return items, nil
 }
 
-const listLoggedSetsByUser = `-- name: ListLoggedSetsByUser :many
-SELECT id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, rpe, created_at
+const listLoggedSetsByUserByCreatedAtAsc = `-- name: ListLoggedSetsByUserByCreatedAtAsc :many
+SELECT id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, rpe, created_at, rowid AS seq
 FROM logged_sets
-WHERE user_id = ?
-ORDER BY created_at DESC
-LIMIT ? OFFSET ?
+WHERE user_id = ?1
+  AND (?2 IS NULL OR lift_id = ?2)
+  AND (?3 IS NULL OR created_at >= ?3)
+  AND (?4 IS NULL OR created_at < ?4)
+  AND created_at >= ?5
+  AND (created_at > ?5 OR rowid > ?6)
+ORDER BY created_at ASC, rowid ASC
+LIMIT ?8 OFFSET ?7
 `
 
-type ListLoggedSetsByUserParams struct {
-	UserID string `json:"user_id"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
+type ListLoggedSetsByUserByCreatedAtAscParams struct {
+	UserID     string      `json:"user_id"`
+	LiftID     interface{} `json:"lift_id"`
+	FromTime   interface{} `json:"from_time"`
+	BeforeTime interface{} `json:"before_time"`
+	AfterValue string      `json:"after_value"`
+	AfterSeq   int64       `json:"after_seq"`
+	Offset     int64       `json:"offset"`
+	Limit      int64       `json:"limit"`
+}
+
+type ListLoggedSetsByUserByCreatedAtAscRow struct {
+	ID             string          `json:"id"`
+	UserID         string          `json:"user_id"`
+	SessionID      string          `json:"session_id"`
+	PrescriptionID string          `json:"prescription_id"`
+	LiftID         string          `json:"lift_id"`
+	SetNumber      int64           `json:"set_number"`
+	Weight         float64         `json:"weight"`
+	TargetReps     int64           `json:"target_reps"`
+	RepsPerformed  int64           `json:"reps_performed"`
+	IsAmrap        bool            `json:"is_amrap"`
+	Rpe            sql.NullFloat64 `json:"rpe"`
+	CreatedAt      string          `json:"created_at"`
+	Seq            int64           `json:"seq"`
+}
+
+func (q *Queries) ListLoggedSetsByUserByCreatedAtAsc(ctx context.Context, arg ListLoggedSetsByUserByCreatedAtAscParams) ([]ListLoggedSetsByUserByCreatedAtAscRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLoggedSetsByUserByCreatedAtAsc,
+		arg.UserID,
+		arg.LiftID,
+		arg.FromTime,
+		arg.BeforeTime,
+		arg.AfterValue,
+		arg.AfterSeq,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLoggedSetsByUserByCreatedAtAscRow{}
+	for rows.Next() {
+		var i ListLoggedSetsByUserByCreatedAtAscRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.SessionID,
+			&i.PrescriptionID,
+			&i.LiftID,
+			&i.SetNumber,
+			&i.Weight,
+			&i.TargetReps,
+			&i.RepsPerformed,
+			&i.IsAmrap,
+			&i.Rpe,
+			&i.CreatedAt,
+			&i.Seq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLoggedSetsByUserByCreatedAtDesc = `-- name: ListLoggedSetsByUserByCreatedAtDesc :many
+SELECT id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, rpe, created_at, rowid AS seq
+FROM logged_sets
+WHERE user_id = ?1
+  AND (?2 IS NULL OR lift_id = ?2)
+  AND (?3 IS NULL OR created_at >= ?3)
+  AND (?4 IS NULL OR created_at < ?4)
+  AND created_at <= ?5
+  AND (created_at < ?5 OR rowid < ?6)
+ORDER BY created_at DESC, rowid DESC
+LIMIT ?8 OFFSET ?7
+`
+
+type ListLoggedSetsByUserByCreatedAtDescParams struct {
+	UserID     string      `json:"user_id"`
+	LiftID     interface{} `json:"lift_id"`
+	FromTime   interface{} `json:"from_time"`
+	BeforeTime interface{} `json:"before_time"`
+	AfterValue string      `json:"after_value"`
+	AfterSeq   int64       `json:"after_seq"`
+	Offset     int64       `json:"offset"`
+	Limit      int64       `json:"limit"`
+}
+
+type ListLoggedSetsByUserByCreatedAtDescRow struct {
+	ID             string          `json:"id"`
+	UserID         string          `json:"user_id"`
+	SessionID      string          `json:"session_id"`
+	PrescriptionID string          `json:"prescription_id"`
+	LiftID         string          `json:"lift_id"`
+	SetNumber      int64           `json:"set_number"`
+	Weight         float64         `json:"weight"`
+	TargetReps     int64           `json:"target_reps"`
+	RepsPerformed  int64           `json:"reps_performed"`
+	IsAmrap        bool            `json:"is_amrap"`
+	Rpe            sql.NullFloat64 `json:"rpe"`
+	CreatedAt      string          `json:"created_at"`
+	Seq            int64           `json:"seq"`
+}
+
+func (q *Queries) ListLoggedSetsByUserByCreatedAtDesc(ctx context.Context, arg ListLoggedSetsByUserByCreatedAtDescParams) ([]ListLoggedSetsByUserByCreatedAtDescRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLoggedSetsByUserByCreatedAtDesc,
+		arg.UserID,
+		arg.LiftID,
+		arg.FromTime,
+		arg.BeforeTime,
+		arg.AfterValue,
+		arg.AfterSeq,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLoggedSetsByUserByCreatedAtDescRow{}
+	for rows.Next() {
+		var i ListLoggedSetsByUserByCreatedAtDescRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.SessionID,
+			&i.PrescriptionID,
+			&i.LiftID,
+			&i.SetNumber,
+			&i.Weight,
+			&i.TargetReps,
+			&i.RepsPerformed,
+			&i.IsAmrap,
+			&i.Rpe,
+			&i.CreatedAt,
+			&i.Seq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-type ListLoggedSetsByUserRow struct {
+const listLoggedSetsByUserChronological = `-- name: ListLoggedSetsByUserChronological :many
+SELECT id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, rpe, created_at
+FROM logged_sets
+WHERE user_id = ?
+ORDER BY created_at ASC, set_number ASC
+`
+
+type ListLoggedSetsByUserChronologicalRow struct {
 	ID             string          `json:"id"`
 	UserID         string          `json:"user_id"`
 	SessionID      string          `json:"session_id"`
@@ -316,15 +561,15 @@ type ListLoggedSetsByUserRow struct {
 	CreatedAt      string          `json:"created_at"`
 }
 
-func (q *Queries) ListLoggedSetsByUser(ctx context.Context, arg ListLoggedSetsByUserParams) ([]ListLoggedSetsByUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, listLoggedSetsByUser, arg.UserID, arg.Limit, arg.Offset)
+func (q *Queries) ListLoggedSetsByUserChronological(ctx context.Context, userID string) ([]ListLoggedSetsByUserChronologicalRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLoggedSetsByUserChronological, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListLoggedSetsByUserRow{}
+	items := []ListLoggedSetsByUserChronologicalRow{}
 	for rows.Next() {
-		var i ListLoggedSetsByUserRow
+		var i ListLoggedSetsByUserChronologicalRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
@@ -351,3 +596,32 @@ func (q *Queries) ListLoggedSetsByUser(ctx context.Context, arg ListLoggedSetsBy
 	}
 	return items, nil
 }
+
+const updateLoggedSet = `-- name: UpdateLoggedSet :exec
+UPDATE logged_sets
+SET set_number = ?, weight = ?, target_reps = ?, reps_performed = ?, is_amrap = ?, rpe = ?
+WHERE id = ?
+`
+
+type UpdateLoggedSetParams struct {
+	SetNumber     int64           `json:"set_number"`
+	Weight        float64         `json:"weight"`
+	TargetReps    int64           `json:"target_reps"`
+	RepsPerformed int64           `json:"reps_performed"`
+	IsAmrap       bool            `json:"is_amrap"`
+	Rpe           sql.NullFloat64 `json:"rpe"`
+	ID            string          `json:"id"`
+}
+
+func (q *Queries) UpdateLoggedSet(ctx context.Context, arg UpdateLoggedSetParams) error {
+	_, err := q.db.ExecContext(ctx, updateLoggedSet,
+		arg.SetNumber,
+		arg.Weight,
+		arg.TargetReps,
+		arg.RepsPerformed,
+		arg.IsAmrap,
+		arg.Rpe,
+		arg.ID,
+	)
+	return err
+}
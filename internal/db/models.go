@@ -8,6 +8,16 @@ import (
 	"database/sql"
 )
 
+type BodyweightEntry struct {
+	ID         string         `json:"id"`
+	UserID     string         `json:"user_id"`
+	Weight     float64        `json:"weight"`
+	Unit       string         `json:"unit"`
+	RecordedAt string         `json:"recorded_at"`
+	Notes      sql.NullString `json:"notes"`
+	CreatedAt  string         `json:"created_at"`
+}
+
 type Cycle struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
@@ -17,12 +27,13 @@ type Cycle struct {
 }
 
 type DailyLookup struct {
-	ID        string         `json:"id"`
-	Name      string         `json:"name"`
-	Entries   string         `json:"entries"`
-	ProgramID sql.NullString `json:"program_id"`
-	CreatedAt string         `json:"created_at"`
-	UpdatedAt string         `json:"updated_at"`
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	Entries        string         `json:"entries"`
+	ProgramID      sql.NullString `json:"program_id"`
+	CreatedAt      string         `json:"created_at"`
+	UpdatedAt      string         `json:"updated_at"`
+	OrganizationID sql.NullString `json:"organization_id"`
 }
 
 type Day struct {
@@ -63,6 +74,7 @@ type Lift struct {
 	ParentLiftID      sql.NullString `json:"parent_lift_id"`
 	CreatedAt         string         `json:"created_at"`
 	UpdatedAt         string         `json:"updated_at"`
+	OrganizationID    sql.NullString `json:"organization_id"`
 }
 
 type LiftMax struct {
@@ -91,6 +103,36 @@ type LoggedSet struct {
 	Rpe            sql.NullFloat64 `json:"rpe"`
 }
 
+type LoggedSetRevision struct {
+	ID           string         `json:"id"`
+	LoggedSetID  string         `json:"logged_set_id"`
+	SessionID    string         `json:"session_id"`
+	UserID       string         `json:"user_id"`
+	ChangedBy    string         `json:"changed_by"`
+	Action       string         `json:"action"`
+	Source       string         `json:"source"`
+	Reason       sql.NullString `json:"reason"`
+	PreviousData string         `json:"previous_data"`
+	NewData      sql.NullString `json:"new_data"`
+	CreatedAt    string         `json:"created_at"`
+}
+
+type PersonalRecord struct {
+	ID            string          `json:"id"`
+	UserID        string          `json:"user_id"`
+	LiftID        sql.NullString  `json:"lift_id"`
+	RecordType    string          `json:"record_type"`
+	RepCount      sql.NullInt64   `json:"rep_count"`
+	Value         float64         `json:"value"`
+	Weight        sql.NullFloat64 `json:"weight"`
+	Reps          sql.NullInt64   `json:"reps"`
+	PreviousValue sql.NullFloat64 `json:"previous_value"`
+	LoggedSetID   sql.NullString  `json:"logged_set_id"`
+	SessionID     sql.NullString  `json:"session_id"`
+	AchievedAt    string          `json:"achieved_at"`
+	CreatedAt     string          `json:"created_at"`
+}
+
 type Prescription struct {
 	ID           string         `json:"id"`
 	LiftID       string         `json:"lift_id"`
@@ -118,6 +160,7 @@ type Program struct {
 	DaysPerWeek     int64           `json:"days_per_week"`
 	Focus           string          `json:"focus"`
 	HasAmrap        int64           `json:"has_amrap"`
+	OrganizationID  sql.NullString  `json:"organization_id"`
 }
 
 type ProgramProgression struct {
@@ -132,6 +175,13 @@ type ProgramProgression struct {
 	UpdatedAt         string          `json:"updated_at"`
 }
 
+type ProgramReadinessMapping struct {
+	ProgramID string `json:"program_id"`
+	Tiers     string `json:"tiers"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
 type Progression struct {
 	ID         string `json:"id"`
 	Name       string `json:"name"`
@@ -142,16 +192,35 @@ type Progression struct {
 }
 
 type ProgressionLog struct {
-	ID             string         `json:"id"`
-	UserID         string         `json:"user_id"`
-	ProgressionID  string         `json:"progression_id"`
-	LiftID         string         `json:"lift_id"`
-	PreviousValue  float64        `json:"previous_value"`
-	NewValue       float64        `json:"new_value"`
-	Delta          float64        `json:"delta"`
-	TriggerType    string         `json:"trigger_type"`
-	TriggerContext sql.NullString `json:"trigger_context"`
-	AppliedAt      string         `json:"applied_at"`
+	ID               string         `json:"id"`
+	UserID           string         `json:"user_id"`
+	ProgressionID    string         `json:"progression_id"`
+	LiftID           string         `json:"lift_id"`
+	PreviousValue    float64        `json:"previous_value"`
+	NewValue         float64        `json:"new_value"`
+	Delta            float64        `json:"delta"`
+	TriggerType      string         `json:"trigger_type"`
+	TriggerContext   sql.NullString `json:"trigger_context"`
+	AppliedAt        string         `json:"applied_at"`
+	PreviousStage    sql.NullInt64  `json:"previous_stage"`
+	RevertsLogID     sql.NullString `json:"reverts_log_id"`
+	RevertedByLogID  sql.NullString `json:"reverted_by_log_id"`
+	PreviousFailures sql.NullInt64  `json:"previous_failures"`
+}
+
+type ReadinessCheckin struct {
+	ID             string          `json:"id"`
+	UserID         string          `json:"user_id"`
+	CheckinDate    string          `json:"checkin_date"`
+	SleepQuality   sql.NullInt64   `json:"sleep_quality"`
+	Soreness       sql.NullInt64   `json:"soreness"`
+	Stress         sql.NullInt64   `json:"stress"`
+	ReadinessScore sql.NullInt64   `json:"readiness_score"`
+	Hrv            sql.NullFloat64 `json:"hrv"`
+	Score          float64         `json:"score"`
+	Notes          sql.NullString  `json:"notes"`
+	CreatedAt      string          `json:"created_at"`
+	UpdatedAt      string          `json:"updated_at"`
 }
 
 type RotationLookup struct {
@@ -171,15 +240,30 @@ type Session struct {
 	CreatedAt string `json:"created_at"`
 }
 
+type SyncMutation struct {
+	ID              string         `json:"id"`
+	UserID          string         `json:"user_id"`
+	MutationType    string         `json:"mutation_type"`
+	Status          string         `json:"status"`
+	Code            sql.NullString `json:"code"`
+	Message         sql.NullString `json:"message"`
+	EntityID        sql.NullString `json:"entity_id"`
+	ClientTimestamp string         `json:"client_timestamp"`
+	CreatedAt       string         `json:"created_at"`
+}
+
 type User struct {
-	ID           string         `json:"id"`
-	CreatedAt    string         `json:"created_at"`
-	UpdatedAt    string         `json:"updated_at"`
-	Email        sql.NullString `json:"email"`
-	PasswordHash sql.NullString `json:"password_hash"`
-	Name         sql.NullString `json:"name"`
-	IsAdmin      int64          `json:"is_admin"`
-	WeightUnit   string         `json:"weight_unit"`
+	ID                string         `json:"id"`
+	CreatedAt         string         `json:"created_at"`
+	UpdatedAt         string         `json:"updated_at"`
+	Email             sql.NullString `json:"email"`
+	PasswordHash      sql.NullString `json:"password_hash"`
+	Name              sql.NullString `json:"name"`
+	IsAdmin           int64          `json:"is_admin"`
+	WeightUnit        string         `json:"weight_unit"`
+	Sex               sql.NullString `json:"sex"`
+	BirthDate         sql.NullString `json:"birth_date"`
+	WeightClassTarget sql.NullString `json:"weight_class_target"`
 }
 
 type UserProgramState struct {
@@ -229,12 +313,13 @@ type WeekDay struct {
 }
 
 type WeeklyLookup struct {
-	ID        string         `json:"id"`
-	Name      string         `json:"name"`
-	Entries   string         `json:"entries"`
-	ProgramID sql.NullString `json:"program_id"`
-	CreatedAt string         `json:"created_at"`
-	UpdatedAt string         `json:"updated_at"`
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	Entries        string         `json:"entries"`
+	ProgramID      sql.NullString `json:"program_id"`
+	CreatedAt      string         `json:"created_at"`
+	UpdatedAt      string         `json:"updated_at"`
+	OrganizationID sql.NullString `json:"organization_id"`
 }
 
 type WorkoutSession struct {
@@ -248,3 +333,12 @@ type WorkoutSession struct {
 	CreatedAt          string         `json:"created_at"`
 	UpdatedAt          string         `json:"updated_at"`
 }
+
+type WorkoutSessionReadiness struct {
+	SessionID      string         `json:"session_id"`
+	CheckinID      sql.NullString `json:"checkin_id"`
+	Score          float64        `json:"score"`
+	LoadModifier   float64        `json:"load_modifier"`
+	VolumeModifier float64        `json:"volume_modifier"`
+	CreatedAt      string         `json:"created_at"`
+}
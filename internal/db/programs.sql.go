@@ -39,6 +39,8 @@ WHERE (?1 IS NULL OR difficulty = ?1)
   AND (?3 IS NULL OR focus = ?3)
   AND (?4 IS NULL OR has_amrap = ?4)
   AND (?5 IS NULL OR name LIKE '%' || ?5 || '%' COLLATE NOCASE)
+  AND (?6 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?6))
 `
 
 type CountProgramsFilteredParams struct {
@@ -47,6 +49,7 @@ type CountProgramsFilteredParams struct {
 	Focus       interface{} `json:"focus"`
 	HasAmrap    interface{} `json:"has_amrap"`
 	Search      interface{} `json:"search"`
+	ViewerID    interface{} `json:"viewer_id"`
 }
 
 func (q *Queries) CountProgramsFiltered(ctx context.Context, arg CountProgramsFilteredParams) (int64, error) {
@@ -56,6 +59,7 @@ func (q *Queries) CountProgramsFiltered(ctx context.Context, arg CountProgramsFi
 		arg.Focus,
 		arg.HasAmrap,
 		arg.Search,
+		arg.ViewerID,
 	)
 	var count int64
 	err := row.Scan(&count)
@@ -63,8 +67,8 @@ func (q *Queries) CountProgramsFiltered(ctx context.Context, arg CountProgramsFi
 }
 
 const createProgram = `-- name: CreateProgram :exec
-INSERT INTO programs (id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO programs (id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateProgramParams struct {
@@ -82,6 +86,7 @@ type CreateProgramParams struct {
 	HasAmrap        int64           `json:"has_amrap"`
 	CreatedAt       string          `json:"created_at"`
 	UpdatedAt       string          `json:"updated_at"`
+	OrganizationID  sql.NullString  `json:"organization_id"`
 }
 
 func (q *Queries) CreateProgram(ctx context.Context, arg CreateProgramParams) error {
@@ -100,6 +105,7 @@ func (q *Queries) CreateProgram(ctx context.Context, arg CreateProgramParams) er
 		arg.HasAmrap,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.OrganizationID,
 	)
 	return err
 }
@@ -133,7 +139,7 @@ func (q *Queries) GetCycleForProgram(ctx context.Context, id string) (Cycle, err
 }
 
 const getDailyLookupForProgram = `-- name: GetDailyLookupForProgram :one
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM daily_lookups
 WHERE id = ?
 `
@@ -148,12 +154,13 @@ func (q *Queries) GetDailyLookupForProgram(ctx context.Context, id string) (Dail
 		&i.ProgramID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.OrganizationID,
 	)
 	return i, err
 }
 
 const getProgram = `-- name: GetProgram :one
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 WHERE id = ?
 `
@@ -173,6 +180,7 @@ type GetProgramRow struct {
 	HasAmrap        int64           `json:"has_amrap"`
 	CreatedAt       string          `json:"created_at"`
 	UpdatedAt       string          `json:"updated_at"`
+	OrganizationID  sql.NullString  `json:"organization_id"`
 }
 
 func (q *Queries) GetProgram(ctx context.Context, id string) (GetProgramRow, error) {
@@ -193,12 +201,13 @@ func (q *Queries) GetProgram(ctx context.Context, id string) (GetProgramRow, err
 		&i.HasAmrap,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.OrganizationID,
 	)
 	return i, err
 }
 
 const getProgramBySlug = `-- name: GetProgramBySlug :one
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 WHERE slug = ?
 `
@@ -218,6 +227,7 @@ type GetProgramBySlugRow struct {
 	HasAmrap        int64           `json:"has_amrap"`
 	CreatedAt       string          `json:"created_at"`
 	UpdatedAt       string          `json:"updated_at"`
+	OrganizationID  sql.NullString  `json:"organization_id"`
 }
 
 func (q *Queries) GetProgramBySlug(ctx context.Context, slug string) (GetProgramBySlugRow, error) {
@@ -238,6 +248,7 @@ func (q *Queries) GetProgramBySlug(ctx context.Context, slug string) (GetProgram
 		&i.HasAmrap,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.OrganizationID,
 	)
 	return i, err
 }
@@ -385,7 +396,7 @@ func (q *Queries) GetProgramSessionStats(ctx context.Context, programID sql.Null
 }
 
 const getWeeklyLookupForProgram = `-- name: GetWeeklyLookupForProgram :one
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM weekly_lookups
 WHERE id = ?
 `
@@ -400,12 +411,13 @@ func (q *Queries) GetWeeklyLookupForProgram(ctx context.Context, id string) (Wee
 		&i.ProgramID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.OrganizationID,
 	)
 	return i, err
 }
 
 const listProgramsByCreatedAtAsc = `-- name: ListProgramsByCreatedAtAsc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 ORDER BY created_at ASC
 LIMIT ? OFFSET ?
@@ -431,6 +443,7 @@ type ListProgramsByCreatedAtAscRow struct {
 	HasAmrap        int64           `json:"has_amrap"`
 	CreatedAt       string          `json:"created_at"`
 	UpdatedAt       string          `json:"updated_at"`
+	OrganizationID  sql.NullString  `json:"organization_id"`
 }
 
 func (q *Queries) ListProgramsByCreatedAtAsc(ctx context.Context, arg ListProgramsByCreatedAtAscParams) ([]ListProgramsByCreatedAtAscRow, error) {
@@ -457,6 +470,7 @@ func (q *Queries) ListProgramsByCreatedAtAsc(ctx context.Context, arg ListProgra
 			&i.HasAmrap,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -472,7 +486,7 @@ func (q *Queries) ListProgramsByCreatedAtAsc(ctx context.Context, arg ListProgra
 }
 
 const listProgramsByCreatedAtDesc = `-- name: ListProgramsByCreatedAtDesc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?
@@ -498,6 +512,7 @@ type ListProgramsByCreatedAtDescRow struct {
 	HasAmrap        int64           `json:"has_amrap"`
 	CreatedAt       string          `json:"created_at"`
 	UpdatedAt       string          `json:"updated_at"`
+	OrganizationID  sql.NullString  `json:"organization_id"`
 }
 
 func (q *Queries) ListProgramsByCreatedAtDesc(ctx context.Context, arg ListProgramsByCreatedAtDescParams) ([]ListProgramsByCreatedAtDescRow, error) {
@@ -524,6 +539,7 @@ func (q *Queries) ListProgramsByCreatedAtDesc(ctx context.Context, arg ListProgr
 			&i.HasAmrap,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -539,7 +555,7 @@ func (q *Queries) ListProgramsByCreatedAtDesc(ctx context.Context, arg ListProgr
 }
 
 const listProgramsByNameAsc = `-- name: ListProgramsByNameAsc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 ORDER BY name ASC
 LIMIT ? OFFSET ?
@@ -565,6 +581,7 @@ type ListProgramsByNameAscRow struct {
 	HasAmrap        int64           `json:"has_amrap"`
 	CreatedAt       string          `json:"created_at"`
 	UpdatedAt       string          `json:"updated_at"`
+	OrganizationID  sql.NullString  `json:"organization_id"`
 }
 
 func (q *Queries) ListProgramsByNameAsc(ctx context.Context, arg ListProgramsByNameAscParams) ([]ListProgramsByNameAscRow, error) {
@@ -591,6 +608,7 @@ func (q *Queries) ListProgramsByNameAsc(ctx context.Context, arg ListProgramsByN
 			&i.HasAmrap,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -606,7 +624,7 @@ func (q *Queries) ListProgramsByNameAsc(ctx context.Context, arg ListProgramsByN
 }
 
 const listProgramsByNameDesc = `-- name: ListProgramsByNameDesc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 ORDER BY name DESC
 LIMIT ? OFFSET ?
@@ -632,6 +650,7 @@ type ListProgramsByNameDescRow struct {
 	HasAmrap        int64           `json:"has_amrap"`
 	CreatedAt       string          `json:"created_at"`
 	UpdatedAt       string          `json:"updated_at"`
+	OrganizationID  sql.NullString  `json:"organization_id"`
 }
 
 func (q *Queries) ListProgramsByNameDesc(ctx context.Context, arg ListProgramsByNameDescParams) ([]ListProgramsByNameDescRow, error) {
@@ -658,6 +677,7 @@ func (q *Queries) ListProgramsByNameDesc(ctx context.Context, arg ListProgramsBy
 			&i.HasAmrap,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -673,15 +693,17 @@ func (q *Queries) ListProgramsByNameDesc(ctx context.Context, arg ListProgramsBy
 }
 
 const listProgramsFilteredByCreatedAtAsc = `-- name: ListProgramsFilteredByCreatedAtAsc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 WHERE (?1 IS NULL OR difficulty = ?1)
   AND (?2 IS NULL OR days_per_week = ?2)
   AND (?3 IS NULL OR focus = ?3)
   AND (?4 IS NULL OR has_amrap = ?4)
   AND (?5 IS NULL OR name LIKE '%' || ?5 || '%' COLLATE NOCASE)
+  AND (?6 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?6))
 ORDER BY created_at ASC
-LIMIT ?7 OFFSET ?6
+LIMIT ?8 OFFSET ?7
 `
 
 type ListProgramsFilteredByCreatedAtAscParams struct {
@@ -690,6 +712,7 @@ type ListProgramsFilteredByCreatedAtAscParams struct {
 	Focus       interface{} `json:"focus"`
 	HasAmrap    interface{} `json:"has_amrap"`
 	Search      interface{} `json:"search"`
+	ViewerID    interface{} `json:"viewer_id"`
 	Offset      int64       `json:"offset"`
 	Limit       int64       `json:"limit"`
 }
@@ -709,6 +732,7 @@ type ListProgramsFilteredByCreatedAtAscRow struct {
 	HasAmrap        int64           `json:"has_amrap"`
 	CreatedAt       string          `json:"created_at"`
 	UpdatedAt       string          `json:"updated_at"`
+	OrganizationID  sql.NullString  `json:"organization_id"`
 }
 
 func (q *Queries) ListProgramsFilteredByCreatedAtAsc(ctx context.Context, arg ListProgramsFilteredByCreatedAtAscParams) ([]ListProgramsFilteredByCreatedAtAscRow, error) {
@@ -718,6 +742,7 @@ func (q *Queries) ListProgramsFilteredByCreatedAtAsc(ctx context.Context, arg Li
 		arg.Focus,
 		arg.HasAmrap,
 		arg.Search,
+		arg.ViewerID,
 		arg.Offset,
 		arg.Limit,
 	)
@@ -743,6 +768,7 @@ func (q *Queries) ListProgramsFilteredByCreatedAtAsc(ctx context.Context, arg Li
 			&i.HasAmrap,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -758,15 +784,17 @@ func (q *Queries) ListProgramsFilteredByCreatedAtAsc(ctx context.Context, arg Li
 }
 
 const listProgramsFilteredByCreatedAtDesc = `-- name: ListProgramsFilteredByCreatedAtDesc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 WHERE (?1 IS NULL OR difficulty = ?1)
   AND (?2 IS NULL OR days_per_week = ?2)
   AND (?3 IS NULL OR focus = ?3)
   AND (?4 IS NULL OR has_amrap = ?4)
   AND (?5 IS NULL OR name LIKE '%' || ?5 || '%' COLLATE NOCASE)
+  AND (?6 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?6))
 ORDER BY created_at DESC
-LIMIT ?7 OFFSET ?6
+LIMIT ?8 OFFSET ?7
 `
 
 type ListProgramsFilteredByCreatedAtDescParams struct {
@@ -775,6 +803,7 @@ type ListProgramsFilteredByCreatedAtDescParams struct {
 	Focus       interface{} `json:"focus"`
 	HasAmrap    interface{} `json:"has_amrap"`
 	Search      interface{} `json:"search"`
+	ViewerID    interface{} `json:"viewer_id"`
 	Offset      int64       `json:"offset"`
 	Limit       int64       `json:"limit"`
 }
@@ -794,6 +823,7 @@ type ListProgramsFilteredByCreatedAtDescRow struct {
 	HasAmrap        int64           `json:"has_amrap"`
 	CreatedAt       string          `json:"created_at"`
 	UpdatedAt       string          `json:"updated_at"`
+	OrganizationID  sql.NullString  `json:"organization_id"`
 }
 
 func (q *Queries) ListProgramsFilteredByCreatedAtDesc(ctx context.Context, arg ListProgramsFilteredByCreatedAtDescParams) ([]ListProgramsFilteredByCreatedAtDescRow, error) {
@@ -803,6 +833,7 @@ func (q *Queries) ListProgramsFilteredByCreatedAtDesc(ctx context.Context, arg L
 		arg.Focus,
 		arg.HasAmrap,
 		arg.Search,
+		arg.ViewerID,
 		arg.Offset,
 		arg.Limit,
 	)
@@ -828,6 +859,7 @@ func (q *Queries) ListProgramsFilteredByCreatedAtDesc(ctx context.Context, arg L
 			&i.HasAmrap,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -843,15 +875,17 @@ func (q *Queries) ListProgramsFilteredByCreatedAtDesc(ctx context.Context, arg L
 }
 
 const listProgramsFilteredByNameAsc = `-- name: ListProgramsFilteredByNameAsc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 WHERE (?1 IS NULL OR difficulty = ?1)
   AND (?2 IS NULL OR days_per_week = ?2)
   AND (?3 IS NULL OR focus = ?3)
   AND (?4 IS NULL OR has_amrap = ?4)
   AND (?5 IS NULL OR name LIKE '%' || ?5 || '%' COLLATE NOCASE)
+  AND (?6 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?6))
 ORDER BY name ASC
-LIMIT ?7 OFFSET ?6
+LIMIT ?8 OFFSET ?7
 `
 
 type ListProgramsFilteredByNameAscParams struct {
@@ -860,6 +894,7 @@ type ListProgramsFilteredByNameAscParams struct {
 	Focus       interface{} `json:"focus"`
 	HasAmrap    interface{} `json:"has_amrap"`
 	Search      interface{} `json:"search"`
+	ViewerID    interface{} `json:"viewer_id"`
 	Offset      int64       `json:"offset"`
 	Limit       int64       `json:"limit"`
 }
@@ -879,6 +914,7 @@ type ListProgramsFilteredByNameAscRow struct {
 	HasAmrap        int64           `json:"has_amrap"`
 	CreatedAt       string          `json:"created_at"`
 	UpdatedAt       string          `json:"updated_at"`
+	OrganizationID  sql.NullString  `json:"organization_id"`
 }
 
 func (q *Queries) ListProgramsFilteredByNameAsc(ctx context.Context, arg ListProgramsFilteredByNameAscParams) ([]ListProgramsFilteredByNameAscRow, error) {
@@ -888,6 +924,7 @@ func (q *Queries) ListProgramsFilteredByNameAsc(ctx context.Context, arg ListPro
 		arg.Focus,
 		arg.HasAmrap,
 		arg.Search,
+		arg.ViewerID,
 		arg.Offset,
 		arg.Limit,
 	)
@@ -913,6 +950,7 @@ func (q *Queries) ListProgramsFilteredByNameAsc(ctx context.Context, arg ListPro
 			&i.HasAmrap,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -928,15 +966,17 @@ func (q *Queries) ListProgramsFilteredByNameAsc(ctx context.Context, arg ListPro
 }
 
 const listProgramsFilteredByNameDesc = `-- name: ListProgramsFilteredByNameDesc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 WHERE (?1 IS NULL OR difficulty = ?1)
   AND (?2 IS NULL OR days_per_week = ?2)
   AND (?3 IS NULL OR focus = ?3)
   AND (?4 IS NULL OR has_amrap = ?4)
   AND (?5 IS NULL OR name LIKE '%' || ?5 || '%' COLLATE NOCASE)
+  AND (?6 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?6))
 ORDER BY name DESC
-LIMIT ?7 OFFSET ?6
+LIMIT ?8 OFFSET ?7
 `
 
 type ListProgramsFilteredByNameDescParams struct {
@@ -945,6 +985,7 @@ type ListProgramsFilteredByNameDescParams struct {
 	Focus       interface{} `json:"focus"`
 	HasAmrap    interface{} `json:"has_amrap"`
 	Search      interface{} `json:"search"`
+	ViewerID    interface{} `json:"viewer_id"`
 	Offset      int64       `json:"offset"`
 	Limit       int64       `json:"limit"`
 }
@@ -964,6 +1005,7 @@ type ListProgramsFilteredByNameDescRow struct {
 	HasAmrap        int64           `json:"has_amrap"`
 	CreatedAt       string          `json:"created_at"`
 	UpdatedAt       string          `json:"updated_at"`
+	OrganizationID  sql.NullString  `json:"organization_id"`
 }
 
 func (q *Queries) ListProgramsFilteredByNameDesc(ctx context.Context, arg ListProgramsFilteredByNameDescParams) ([]ListProgramsFilteredByNameDescRow, error) {
@@ -973,6 +1015,7 @@ func (q *Queries) ListProgramsFilteredByNameDesc(ctx context.Context, arg ListPr
 		arg.Focus,
 		arg.HasAmrap,
 		arg.Search,
+		arg.ViewerID,
 		arg.Offset,
 		arg.Limit,
 	)
@@ -998,6 +1041,7 @@ func (q *Queries) ListProgramsFilteredByNameDesc(ctx context.Context, arg ListPr
 			&i.HasAmrap,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
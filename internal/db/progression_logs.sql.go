@@ -38,13 +38,35 @@ func (q *Queries) CheckIdempotency(ctx context.Context, arg CheckIdempotencyPara
 	return already_applied, err
 }
 
-const countProgressionHistoryBase = `-- name: CountProgressionHistoryBase :one
+const countProgressionHistory = `-- name: CountProgressionHistory :one
 SELECT COUNT(*) FROM progression_logs pl
-WHERE pl.user_id = ?
+JOIN progressions p ON pl.progression_id = p.id
+WHERE pl.user_id = ?1
+  AND (?2 IS NULL OR pl.lift_id = ?2)
+  AND (?3 IS NULL OR p.type = ?3)
+  AND (?4 IS NULL OR pl.trigger_type = ?4)
+  AND (?5 IS NULL OR pl.applied_at >= ?5)
+  AND (?6 IS NULL OR pl.applied_at < ?6)
 `
 
-func (q *Queries) CountProgressionHistoryBase(ctx context.Context, userID string) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countProgressionHistoryBase, userID)
+type CountProgressionHistoryParams struct {
+	UserID          string      `json:"user_id"`
+	LiftID          interface{} `json:"lift_id"`
+	ProgressionType interface{} `json:"progression_type"`
+	TriggerType     interface{} `json:"trigger_type"`
+	FromTime        interface{} `json:"from_time"`
+	BeforeTime      interface{} `json:"before_time"`
+}
+
+func (q *Queries) CountProgressionHistory(ctx context.Context, arg CountProgressionHistoryParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countProgressionHistory,
+		arg.UserID,
+		arg.LiftID,
+		arg.ProgressionType,
+		arg.TriggerType,
+		arg.FromTime,
+		arg.BeforeTime,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -78,21 +100,25 @@ func (q *Queries) CountProgressionLogsByUserAndLift(ctx context.Context, arg Cou
 }
 
 const createProgressionLog = `-- name: CreateProgressionLog :exec
-INSERT INTO progression_logs (id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO progression_logs (id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateProgressionLogParams struct {
-	ID             string         `json:"id"`
-	UserID         string         `json:"user_id"`
-	ProgressionID  string         `json:"progression_id"`
-	LiftID         string         `json:"lift_id"`
-	PreviousValue  float64        `json:"previous_value"`
-	NewValue       float64        `json:"new_value"`
-	Delta          float64        `json:"delta"`
-	TriggerType    string         `json:"trigger_type"`
-	TriggerContext sql.NullString `json:"trigger_context"`
-	AppliedAt      string         `json:"applied_at"`
+	ID               string         `json:"id"`
+	UserID           string         `json:"user_id"`
+	ProgressionID    string         `json:"progression_id"`
+	LiftID           string         `json:"lift_id"`
+	PreviousValue    float64        `json:"previous_value"`
+	NewValue         float64        `json:"new_value"`
+	Delta            float64        `json:"delta"`
+	TriggerType      string         `json:"trigger_type"`
+	TriggerContext   sql.NullString `json:"trigger_context"`
+	AppliedAt        string         `json:"applied_at"`
+	PreviousStage    sql.NullInt64  `json:"previous_stage"`
+	RevertsLogID     sql.NullString `json:"reverts_log_id"`
+	RevertedByLogID  sql.NullString `json:"reverted_by_log_id"`
+	PreviousFailures sql.NullInt64  `json:"previous_failures"`
 }
 
 func (q *Queries) CreateProgressionLog(ctx context.Context, arg CreateProgressionLogParams) error {
@@ -107,6 +133,10 @@ func (q *Queries) CreateProgressionLog(ctx context.Context, arg CreateProgressio
 		arg.TriggerType,
 		arg.TriggerContext,
 		arg.AppliedAt,
+		arg.PreviousStage,
+		arg.RevertsLogID,
+		arg.RevertedByLogID,
+		arg.PreviousFailures,
 	)
 	return err
 }
@@ -120,8 +150,45 @@ func (q *Queries) DeleteProgressionLog(ctx context.Context, id string) error {
 	return err
 }
 
+const getLatestFailureResetLog = `-- name: GetLatestFailureResetLog :one
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
+FROM progression_logs
+WHERE user_id = ? AND lift_id = ? AND progression_id = ? AND previous_failures > 0
+    AND reverts_log_id IS NULL AND reverted_by_log_id IS NULL
+ORDER BY applied_at DESC
+LIMIT 1
+`
+
+type GetLatestFailureResetLogParams struct {
+	UserID        string `json:"user_id"`
+	LiftID        string `json:"lift_id"`
+	ProgressionID string `json:"progression_id"`
+}
+
+func (q *Queries) GetLatestFailureResetLog(ctx context.Context, arg GetLatestFailureResetLogParams) (ProgressionLog, error) {
+	row := q.db.QueryRowContext(ctx, getLatestFailureResetLog, arg.UserID, arg.LiftID, arg.ProgressionID)
+	var i ProgressionLog
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProgressionID,
+		&i.LiftID,
+		&i.PreviousValue,
+		&i.NewValue,
+		&i.Delta,
+		&i.TriggerType,
+		&i.TriggerContext,
+		&i.AppliedAt,
+		&i.PreviousStage,
+		&i.RevertsLogID,
+		&i.RevertedByLogID,
+		&i.PreviousFailures,
+	)
+	return i, err
+}
+
 const getProgressionLog = `-- name: GetProgressionLog :one
-SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
 FROM progression_logs
 WHERE id = ?
 `
@@ -140,14 +207,179 @@ func (q *Queries) GetProgressionLog(ctx context.Context, id string) (Progression
 		&i.TriggerType,
 		&i.TriggerContext,
 		&i.AppliedAt,
+		&i.PreviousStage,
+		&i.RevertsLogID,
+		&i.RevertedByLogID,
+		&i.PreviousFailures,
 	)
 	return i, err
 }
 
-const listProgressionHistoryBase = `-- name: ListProgressionHistoryBase :many
+const listActiveProgressionLogsByLoggedSet = `-- name: ListActiveProgressionLogsByLoggedSet :many
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
+FROM progression_logs
+WHERE user_id = ? AND json_extract(trigger_context, '$.loggedSetId') = ?
+    AND reverts_log_id IS NULL AND reverted_by_log_id IS NULL
+ORDER BY applied_at DESC
+`
+
+type ListActiveProgressionLogsByLoggedSetParams struct {
+	UserID      string      `json:"user_id"`
+	LoggedSetID interface{} `json:"logged_set_id"`
+}
+
+func (q *Queries) ListActiveProgressionLogsByLoggedSet(ctx context.Context, arg ListActiveProgressionLogsByLoggedSetParams) ([]ProgressionLog, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveProgressionLogsByLoggedSet, arg.UserID, arg.LoggedSetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ProgressionLog{}
+	for rows.Next() {
+		var i ProgressionLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ProgressionID,
+			&i.LiftID,
+			&i.PreviousValue,
+			&i.NewValue,
+			&i.Delta,
+			&i.TriggerType,
+			&i.TriggerContext,
+			&i.AppliedAt,
+			&i.PreviousStage,
+			&i.RevertsLogID,
+			&i.RevertedByLogID,
+			&i.PreviousFailures,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveSessionProgressionLogsByLift = `-- name: ListActiveSessionProgressionLogsByLift :many
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
+FROM progression_logs
+WHERE user_id = ? AND lift_id = ? AND trigger_type = 'AFTER_SESSION'
+    AND json_extract(trigger_context, '$.sessionId') = ?
+    AND reverts_log_id IS NULL AND reverted_by_log_id IS NULL
+ORDER BY applied_at DESC
+`
+
+type ListActiveSessionProgressionLogsByLiftParams struct {
+	UserID    string      `json:"user_id"`
+	LiftID    string      `json:"lift_id"`
+	SessionID interface{} `json:"session_id"`
+}
+
+func (q *Queries) ListActiveSessionProgressionLogsByLift(ctx context.Context, arg ListActiveSessionProgressionLogsByLiftParams) ([]ProgressionLog, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveSessionProgressionLogsByLift, arg.UserID, arg.LiftID, arg.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ProgressionLog{}
+	for rows.Next() {
+		var i ProgressionLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ProgressionID,
+			&i.LiftID,
+			&i.PreviousValue,
+			&i.NewValue,
+			&i.Delta,
+			&i.TriggerType,
+			&i.TriggerContext,
+			&i.AppliedAt,
+			&i.PreviousStage,
+			&i.RevertsLogID,
+			&i.RevertedByLogID,
+			&i.PreviousFailures,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDependentProgressionLogs = `-- name: ListDependentProgressionLogs :many
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
+FROM progression_logs
+WHERE user_id = ? AND lift_id = ? AND applied_at > ? AND id != ?
+    AND reverts_log_id IS NULL AND reverted_by_log_id IS NULL
+ORDER BY applied_at ASC
+`
+
+type ListDependentProgressionLogsParams struct {
+	UserID    string `json:"user_id"`
+	LiftID    string `json:"lift_id"`
+	AppliedAt string `json:"applied_at"`
+	ID        string `json:"id"`
+}
+
+func (q *Queries) ListDependentProgressionLogs(ctx context.Context, arg ListDependentProgressionLogsParams) ([]ProgressionLog, error) {
+	rows, err := q.db.QueryContext(ctx, listDependentProgressionLogs,
+		arg.UserID,
+		arg.LiftID,
+		arg.AppliedAt,
+		arg.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ProgressionLog{}
+	for rows.Next() {
+		var i ProgressionLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ProgressionID,
+			&i.LiftID,
+			&i.PreviousValue,
+			&i.NewValue,
+			&i.Delta,
+			&i.TriggerType,
+			&i.TriggerContext,
+			&i.AppliedAt,
+			&i.PreviousStage,
+			&i.RevertsLogID,
+			&i.RevertedByLogID,
+			&i.PreviousFailures,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProgressionHistoryByAppliedAtAsc = `-- name: ListProgressionHistoryByAppliedAtAsc :many
 SELECT
     pl.id,
-    pl.user_id,
     pl.progression_id,
     pl.lift_id,
     pl.previous_value,
@@ -156,26 +388,42 @@ SELECT
     pl.trigger_type,
     pl.trigger_context,
     pl.applied_at,
+    pl.reverts_log_id,
+    pl.reverted_by_log_id,
     p.name AS progression_name,
     p.type AS progression_type,
-    l.name AS lift_name
+    l.name AS lift_name,
+    pl.rowid AS seq
 FROM progression_logs pl
 JOIN progressions p ON pl.progression_id = p.id
 JOIN lifts l ON pl.lift_id = l.id
-WHERE pl.user_id = ?
-ORDER BY pl.applied_at DESC
-LIMIT ? OFFSET ?
+WHERE pl.user_id = ?1
+  AND (?2 IS NULL OR pl.lift_id = ?2)
+  AND (?3 IS NULL OR p.type = ?3)
+  AND (?4 IS NULL OR pl.trigger_type = ?4)
+  AND (?5 IS NULL OR pl.applied_at >= ?5)
+  AND (?6 IS NULL OR pl.applied_at < ?6)
+  AND pl.applied_at >= ?7
+  AND (pl.applied_at > ?7 OR pl.rowid > ?8)
+ORDER BY pl.applied_at ASC, pl.rowid ASC
+LIMIT ?10 OFFSET ?9
 `
 
-type ListProgressionHistoryBaseParams struct {
-	UserID string `json:"user_id"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
+type ListProgressionHistoryByAppliedAtAscParams struct {
+	UserID          string      `json:"user_id"`
+	LiftID          interface{} `json:"lift_id"`
+	ProgressionType interface{} `json:"progression_type"`
+	TriggerType     interface{} `json:"trigger_type"`
+	FromTime        interface{} `json:"from_time"`
+	BeforeTime      interface{} `json:"before_time"`
+	AfterValue      string      `json:"after_value"`
+	AfterSeq        int64       `json:"after_seq"`
+	Offset          int64       `json:"offset"`
+	Limit           int64       `json:"limit"`
 }
 
-type ListProgressionHistoryBaseRow struct {
+type ListProgressionHistoryByAppliedAtAscRow struct {
 	ID              string         `json:"id"`
-	UserID          string         `json:"user_id"`
 	ProgressionID   string         `json:"progression_id"`
 	LiftID          string         `json:"lift_id"`
 	PreviousValue   float64        `json:"previous_value"`
@@ -184,23 +432,149 @@ type ListProgressionHistoryBaseRow struct {
 	TriggerType     string         `json:"trigger_type"`
 	TriggerContext  sql.NullString `json:"trigger_context"`
 	AppliedAt       string         `json:"applied_at"`
+	RevertsLogID    sql.NullString `json:"reverts_log_id"`
+	RevertedByLogID sql.NullString `json:"reverted_by_log_id"`
 	ProgressionName string         `json:"progression_name"`
 	ProgressionType string         `json:"progression_type"`
 	LiftName        string         `json:"lift_name"`
+	Seq             int64          `json:"seq"`
 }
 
-func (q *Queries) ListProgressionHistoryBase(ctx context.Context, arg ListProgressionHistoryBaseParams) ([]ListProgressionHistoryBaseRow, error) {
-	rows, err := q.db.QueryContext(ctx, listProgressionHistoryBase, arg.UserID, arg.Limit, arg.Offset)
+func (q *Queries) ListProgressionHistoryByAppliedAtAsc(ctx context.Context, arg ListProgressionHistoryByAppliedAtAscParams) ([]ListProgressionHistoryByAppliedAtAscRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProgressionHistoryByAppliedAtAsc,
+		arg.UserID,
+		arg.LiftID,
+		arg.ProgressionType,
+		arg.TriggerType,
+		arg.FromTime,
+		arg.BeforeTime,
+		arg.AfterValue,
+		arg.AfterSeq,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListProgressionHistoryBaseRow{}
+	items := []ListProgressionHistoryByAppliedAtAscRow{}
 	for rows.Next() {
-		var i ListProgressionHistoryBaseRow
+		var i ListProgressionHistoryByAppliedAtAscRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProgressionID,
+			&i.LiftID,
+			&i.PreviousValue,
+			&i.NewValue,
+			&i.Delta,
+			&i.TriggerType,
+			&i.TriggerContext,
+			&i.AppliedAt,
+			&i.RevertsLogID,
+			&i.RevertedByLogID,
+			&i.ProgressionName,
+			&i.ProgressionType,
+			&i.LiftName,
+			&i.Seq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProgressionHistoryByAppliedAtDesc = `-- name: ListProgressionHistoryByAppliedAtDesc :many
+SELECT
+    pl.id,
+    pl.progression_id,
+    pl.lift_id,
+    pl.previous_value,
+    pl.new_value,
+    pl.delta,
+    pl.trigger_type,
+    pl.trigger_context,
+    pl.applied_at,
+    pl.reverts_log_id,
+    pl.reverted_by_log_id,
+    p.name AS progression_name,
+    p.type AS progression_type,
+    l.name AS lift_name,
+    pl.rowid AS seq
+FROM progression_logs pl
+JOIN progressions p ON pl.progression_id = p.id
+JOIN lifts l ON pl.lift_id = l.id
+WHERE pl.user_id = ?1
+  AND (?2 IS NULL OR pl.lift_id = ?2)
+  AND (?3 IS NULL OR p.type = ?3)
+  AND (?4 IS NULL OR pl.trigger_type = ?4)
+  AND (?5 IS NULL OR pl.applied_at >= ?5)
+  AND (?6 IS NULL OR pl.applied_at < ?6)
+  AND pl.applied_at <= ?7
+  AND (pl.applied_at < ?7 OR pl.rowid < ?8)
+ORDER BY pl.applied_at DESC, pl.rowid DESC
+LIMIT ?10 OFFSET ?9
+`
+
+type ListProgressionHistoryByAppliedAtDescParams struct {
+	UserID          string      `json:"user_id"`
+	LiftID          interface{} `json:"lift_id"`
+	ProgressionType interface{} `json:"progression_type"`
+	TriggerType     interface{} `json:"trigger_type"`
+	FromTime        interface{} `json:"from_time"`
+	BeforeTime      interface{} `json:"before_time"`
+	AfterValue      string      `json:"after_value"`
+	AfterSeq        int64       `json:"after_seq"`
+	Offset          int64       `json:"offset"`
+	Limit           int64       `json:"limit"`
+}
+
+type ListProgressionHistoryByAppliedAtDescRow struct {
+	ID              string         `json:"id"`
+	ProgressionID   string         `json:"progression_id"`
+	LiftID          string         `json:"lift_id"`
+	PreviousValue   float64        `json:"previous_value"`
+	NewValue        float64        `json:"new_value"`
+	Delta           float64        `json:"delta"`
+	TriggerType     string         `json:"trigger_type"`
+	TriggerContext  sql.NullString `json:"trigger_context"`
+	AppliedAt       string         `json:"applied_at"`
+	RevertsLogID    sql.NullString `json:"reverts_log_id"`
+	RevertedByLogID sql.NullString `json:"reverted_by_log_id"`
+	ProgressionName string         `json:"progression_name"`
+	ProgressionType string         `json:"progression_type"`
+	LiftName        string         `json:"lift_name"`
+	Seq             int64          `json:"seq"`
+}
+
+func (q *Queries) ListProgressionHistoryByAppliedAtDesc(ctx context.Context, arg ListProgressionHistoryByAppliedAtDescParams) ([]ListProgressionHistoryByAppliedAtDescRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProgressionHistoryByAppliedAtDesc,
+		arg.UserID,
+		arg.LiftID,
+		arg.ProgressionType,
+		arg.TriggerType,
+		arg.FromTime,
+		arg.BeforeTime,
+		arg.AfterValue,
+		arg.AfterSeq,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListProgressionHistoryByAppliedAtDescRow{}
+	for rows.Next() {
+		var i ListProgressionHistoryByAppliedAtDescRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.UserID,
 			&i.ProgressionID,
 			&i.LiftID,
 			&i.PreviousValue,
@@ -209,9 +583,12 @@ func (q *Queries) ListProgressionHistoryBase(ctx context.Context, arg ListProgre
 			&i.TriggerType,
 			&i.TriggerContext,
 			&i.AppliedAt,
+			&i.RevertsLogID,
+			&i.RevertedByLogID,
 			&i.ProgressionName,
 			&i.ProgressionType,
 			&i.LiftName,
+			&i.Seq,
 		); err != nil {
 			return nil, err
 		}
@@ -227,7 +604,7 @@ func (q *Queries) ListProgressionHistoryBase(ctx context.Context, arg ListProgre
 }
 
 const listProgressionLogsByUser = `-- name: ListProgressionLogsByUser :many
-SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
 FROM progression_logs
 WHERE user_id = ?
 ORDER BY applied_at DESC
@@ -260,6 +637,10 @@ func (q *Queries) ListProgressionLogsByUser(ctx context.Context, arg ListProgres
 			&i.TriggerType,
 			&i.TriggerContext,
 			&i.AppliedAt,
+			&i.PreviousStage,
+			&i.RevertsLogID,
+			&i.RevertedByLogID,
+			&i.PreviousFailures,
 		); err != nil {
 			return nil, err
 		}
@@ -275,7 +656,7 @@ func (q *Queries) ListProgressionLogsByUser(ctx context.Context, arg ListProgres
 }
 
 const listProgressionLogsByUserAndLift = `-- name: ListProgressionLogsByUserAndLift :many
-SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
 FROM progression_logs
 WHERE user_id = ? AND lift_id = ?
 ORDER BY applied_at DESC
@@ -314,6 +695,10 @@ func (q *Queries) ListProgressionLogsByUserAndLift(ctx context.Context, arg List
 			&i.TriggerType,
 			&i.TriggerContext,
 			&i.AppliedAt,
+			&i.PreviousStage,
+			&i.RevertsLogID,
+			&i.RevertedByLogID,
+			&i.PreviousFailures,
 		); err != nil {
 			return nil, err
 		}
@@ -327,3 +712,17 @@ func (q *Queries) ListProgressionLogsByUserAndLift(ctx context.Context, arg List
 	}
 	return items, nil
 }
+
+const markProgressionLogReverted = `-- name: MarkProgressionLogReverted :exec
+UPDATE progression_logs SET reverted_by_log_id = ? WHERE id = ?
+`
+
+type MarkProgressionLogRevertedParams struct {
+	RevertedByLogID sql.NullString `json:"reverted_by_log_id"`
+	ID              string         `json:"id"`
+}
+
+func (q *Queries) MarkProgressionLogReverted(ctx context.Context, arg MarkProgressionLogRevertedParams) error {
+	_, err := q.db.ExecContext(ctx, markProgressionLogReverted, arg.RevertedByLogID, arg.ID)
+	return err
+}
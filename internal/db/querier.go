@@ -14,22 +14,19 @@ type Querier interface {
 	CheckIdempotency(ctx context.Context, arg CheckIdempotencyParams) (int64, error)
 	CompleteWorkoutSession(ctx context.Context, arg CompleteWorkoutSessionParams) error
 	CountCycles(ctx context.Context) (int64, error)
-	CountDailyLookups(ctx context.Context) (int64, error)
+	CountDailyLookups(ctx context.Context, viewerID interface{}) (int64, error)
 	CountDayPrescriptions(ctx context.Context, dayID string) (int64, error)
 	CountDays(ctx context.Context) (int64, error)
 	CountDaysFilteredByProgram(ctx context.Context, programID sql.NullString) (int64, error)
 	CountDaysInWeek(ctx context.Context, weekID string) (int64, error)
 	CountEnrolledUsers(ctx context.Context, programID string) (int64, error)
 	CountFailureCountersByUser(ctx context.Context, userID string) (int64, error)
-	CountLiftMaxesByUser(ctx context.Context, userID string) (int64, error)
-	CountLiftMaxesByUserFilterLift(ctx context.Context, arg CountLiftMaxesByUserFilterLiftParams) (int64, error)
-	CountLiftMaxesByUserFilterLiftAndType(ctx context.Context, arg CountLiftMaxesByUserFilterLiftAndTypeParams) (int64, error)
-	CountLiftMaxesByUserFilterType(ctx context.Context, arg CountLiftMaxesByUserFilterTypeParams) (int64, error)
-	CountLifts(ctx context.Context) (int64, error)
-	CountLiftsFilteredByCompetition(ctx context.Context, isCompetitionLift int64) (int64, error)
+	CountLiftMaxesByUser(ctx context.Context, arg CountLiftMaxesByUserParams) (int64, error)
+	CountLifts(ctx context.Context, viewerID interface{}) (int64, error)
+	CountLiftsFilteredByCompetition(ctx context.Context, arg CountLiftsFilteredByCompetitionParams) (int64, error)
 	// Count logged sets for a session
 	CountLoggedSetsBySession(ctx context.Context, sessionID string) (int64, error)
-	CountLoggedSetsByUser(ctx context.Context, userID string) (int64, error)
+	CountLoggedSetsByUser(ctx context.Context, arg CountLoggedSetsByUserParams) (int64, error)
 	CountPrescriptions(ctx context.Context) (int64, error)
 	CountPrescriptionsFilterLift(ctx context.Context, liftID string) (int64, error)
 	CountProgramProgressionsByProgram(ctx context.Context, programID string) (int64, error)
@@ -37,18 +34,18 @@ type Querier interface {
 	CountProgramProgressionsWithDetailsByProgram(ctx context.Context, programID string) (int64, error)
 	CountPrograms(ctx context.Context) (int64, error)
 	CountProgramsFiltered(ctx context.Context, arg CountProgramsFilteredParams) (int64, error)
-	CountProgressionHistoryBase(ctx context.Context, userID string) (int64, error)
+	CountProgressionHistory(ctx context.Context, arg CountProgressionHistoryParams) (int64, error)
 	CountProgressionLogsByUser(ctx context.Context, userID string) (int64, error)
 	CountProgressionLogsByUserAndLift(ctx context.Context, arg CountProgressionLogsByUserAndLiftParams) (int64, error)
 	CountProgressions(ctx context.Context) (int64, error)
 	CountProgressionsByType(ctx context.Context, type_ string) (int64, error)
+	CountReadinessCheckInsByUser(ctx context.Context, userID string) (int64, error)
 	CountWeekDays(ctx context.Context, weekID string) (int64, error)
-	CountWeeklyLookups(ctx context.Context) (int64, error)
+	CountWeeklyLookups(ctx context.Context, viewerID interface{}) (int64, error)
 	CountWeeks(ctx context.Context) (int64, error)
 	CountWeeksByCycleID(ctx context.Context, cycleID string) (int64, error)
 	CountWeeksFilteredByCycle(ctx context.Context, cycleID string) (int64, error)
-	CountWorkoutSessionsByUserID(ctx context.Context, userID string) (int64, error)
-	CountWorkoutSessionsByUserIDWithStatus(ctx context.Context, arg CountWorkoutSessionsByUserIDWithStatusParams) (int64, error)
+	CountWorkoutSessionsByUser(ctx context.Context, arg CountWorkoutSessionsByUserParams) (int64, error)
 	CreateCycle(ctx context.Context, arg CreateCycleParams) error
 	CreateDailyLookup(ctx context.Context, arg CreateDailyLookupParams) error
 	CreateDay(ctx context.Context, arg CreateDayParams) error
@@ -57,11 +54,14 @@ type Querier interface {
 	CreateLift(ctx context.Context, arg CreateLiftParams) error
 	CreateLiftMax(ctx context.Context, arg CreateLiftMaxParams) error
 	CreateLoggedSet(ctx context.Context, arg CreateLoggedSetParams) error
+	CreateLoggedSetRevision(ctx context.Context, arg CreateLoggedSetRevisionParams) error
+	CreatePersonalRecord(ctx context.Context, arg CreatePersonalRecordParams) error
 	CreatePrescription(ctx context.Context, arg CreatePrescriptionParams) error
 	CreateProgram(ctx context.Context, arg CreateProgramParams) error
 	CreateProgramProgression(ctx context.Context, arg CreateProgramProgressionParams) error
 	CreateProgression(ctx context.Context, arg CreateProgressionParams) error
 	CreateProgressionLog(ctx context.Context, arg CreateProgressionLogParams) error
+	CreateSyncMutation(ctx context.Context, arg CreateSyncMutationParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) error
 	CreateUserProgramState(ctx context.Context, arg CreateUserProgramStateParams) error
 	// User Progression States Queries
@@ -71,6 +71,7 @@ type Querier interface {
 	CreateWeekDay(ctx context.Context, arg CreateWeekDayParams) error
 	CreateWeeklyLookup(ctx context.Context, arg CreateWeeklyLookupParams) error
 	CreateWorkoutSession(ctx context.Context, arg CreateWorkoutSessionParams) error
+	CreateWorkoutSessionReadiness(ctx context.Context, arg CreateWorkoutSessionReadinessParams) error
 	CycleIsUsedByPrograms(ctx context.Context, cycleID string) (int64, error)
 	DailyLookupIsUsedByPrograms(ctx context.Context, dailyLookupID sql.NullString) (int64, error)
 	DayIsUsedInWeeks(ctx context.Context, dayID string) (int64, error)
@@ -89,10 +90,12 @@ type Querier interface {
 	DeleteLiftMax(ctx context.Context, id string) error
 	DeleteLoggedSet(ctx context.Context, id string) error
 	DeleteLoggedSetsBySession(ctx context.Context, sessionID string) error
+	DeletePersonalRecordsByUser(ctx context.Context, userID string) error
 	DeletePrescription(ctx context.Context, id string) error
 	DeleteProgram(ctx context.Context, id string) error
 	DeleteProgramProgression(ctx context.Context, id string) error
 	DeleteProgramProgressionsByProgram(ctx context.Context, programID string) error
+	DeleteProgramReadinessMapping(ctx context.Context, programID string) error
 	DeleteProgression(ctx context.Context, id string) error
 	DeleteProgressionLog(ctx context.Context, id string) error
 	DeleteUserProgramStateByUserID(ctx context.Context, userID string) error
@@ -104,6 +107,10 @@ type Querier interface {
 	DeleteWorkoutSession(ctx context.Context, id string) error
 	GetActiveWorkoutSession(ctx context.Context, userProgramStateID string) (WorkoutSession, error)
 	GetActiveWorkoutSessionByUserID(ctx context.Context, userID string) (WorkoutSession, error)
+	GetBestCompetitionTotalRecord(ctx context.Context, userID string) (PersonalRecord, error)
+	GetBestE1RMRecord(ctx context.Context, arg GetBestE1RMRecordParams) (PersonalRecord, error)
+	GetBestRepMaxRecord(ctx context.Context, arg GetBestRepMaxRecordParams) (PersonalRecord, error)
+	GetBestSessionVolumeRecordExcludingSession(ctx context.Context, arg GetBestSessionVolumeRecordExcludingSessionParams) (PersonalRecord, error)
 	GetCurrentMax(ctx context.Context, arg GetCurrentMaxParams) (LiftMax, error)
 	// Get the most recent max for each lift a user has recorded.
 	//
@@ -136,6 +143,7 @@ type Querier interface {
 	GetFailureCounter(ctx context.Context, id string) (FailureCounter, error)
 	GetFailureCounterByKey(ctx context.Context, arg GetFailureCounterByKeyParams) (FailureCounter, error)
 	GetLatestAMRAPForLift(ctx context.Context, arg GetLatestAMRAPForLiftParams) (GetLatestAMRAPForLiftRow, error)
+	GetLatestFailureResetLog(ctx context.Context, arg GetLatestFailureResetLogParams) (ProgressionLog, error)
 	GetLift(ctx context.Context, id string) (Lift, error)
 	GetLiftBySlug(ctx context.Context, slug string) (Lift, error)
 	GetLiftMax(ctx context.Context, id string) (LiftMax, error)
@@ -149,6 +157,7 @@ type Querier interface {
 	GetProgramLiftRequirements(ctx context.Context, programID sql.NullString) ([]string, error)
 	GetProgramProgression(ctx context.Context, id string) (ProgramProgression, error)
 	GetProgramProgressionByProgramProgressionLift(ctx context.Context, arg GetProgramProgressionByProgramProgressionLiftParams) (ProgramProgression, error)
+	GetProgramReadinessMapping(ctx context.Context, programID string) (ProgramReadinessMapping, error)
 	// Returns days for the first week of a program with prescription counts
 	// For programs with week_days, uses week 1; otherwise falls back to days.program_id
 	GetProgramSampleWeek(ctx context.Context, arg GetProgramSampleWeekParams) ([]GetProgramSampleWeekRow, error)
@@ -157,11 +166,16 @@ type Querier interface {
 	GetProgramWithCycle(ctx context.Context, id string) (GetProgramWithCycleRow, error)
 	GetProgression(ctx context.Context, id string) (Progression, error)
 	GetProgressionLog(ctx context.Context, id string) (ProgressionLog, error)
+	GetReadinessCheckInByDate(ctx context.Context, arg GetReadinessCheckInByDateParams) (ReadinessCheckin, error)
+	GetReadinessHRVBaseline(ctx context.Context, arg GetReadinessHRVBaselineParams) (sql.NullFloat64, error)
 	// Dashboard aggregation queries
 	// Get recent completed workouts for a user with day name and sets completed
 	// day_index is used as an offset into the ordered days for the week
 	GetRecentCompletedWorkouts(ctx context.Context, arg GetRecentCompletedWorkoutsParams) ([]GetRecentCompletedWorkoutsRow, error)
+	GetSessionLiftVolume(ctx context.Context, arg GetSessionLiftVolumeParams) (float64, error)
+	GetSessionVolumeRecord(ctx context.Context, arg GetSessionVolumeRecordParams) (PersonalRecord, error)
 	GetStateAdvancementContext(ctx context.Context, userID string) (GetStateAdvancementContextRow, error)
+	GetSyncMutation(ctx context.Context, id string) (SyncMutation, error)
 	GetUser(ctx context.Context, id string) (GetUserRow, error)
 	GetUserProgramStateByID(ctx context.Context, id string) (GetUserProgramStateByIDRow, error)
 	GetUserProgramStateByUserID(ctx context.Context, userID string) (GetUserProgramStateByUserIDRow, error)
@@ -176,13 +190,16 @@ type Querier interface {
 	GetWeeklyLookup(ctx context.Context, id string) (WeeklyLookup, error)
 	GetWeeklyLookupForProgram(ctx context.Context, id string) (WeeklyLookup, error)
 	GetWorkoutSessionByID(ctx context.Context, id string) (WorkoutSession, error)
+	GetWorkoutSessionReadiness(ctx context.Context, sessionID string) (WorkoutSessionReadiness, error)
 	GetWorkoutSessionsByState(ctx context.Context, userProgramStateID string) ([]WorkoutSession, error)
-	GetWorkoutSessionsByUserID(ctx context.Context, arg GetWorkoutSessionsByUserIDParams) ([]WorkoutSession, error)
-	GetWorkoutSessionsByUserIDWithStatus(ctx context.Context, arg GetWorkoutSessionsByUserIDWithStatusParams) ([]WorkoutSession, error)
 	IncrementFailureCounter(ctx context.Context, arg IncrementFailureCounterParams) error
 	LiftHasChildReferences(ctx context.Context, parentLiftID sql.NullString) (int64, error)
 	LiftHasMaxReferences(ctx context.Context, liftID string) (int64, error)
 	LiftHasPrescriptionReferences(ctx context.Context, liftID string) (int64, error)
+	ListActiveProgressionLogsByLoggedSet(ctx context.Context, arg ListActiveProgressionLogsByLoggedSetParams) ([]ProgressionLog, error)
+	ListActiveSessionProgressionLogsByLift(ctx context.Context, arg ListActiveSessionProgressionLogsByLiftParams) ([]ProgressionLog, error)
+	ListCompetitionLiftBestE1RMs(ctx context.Context, userID string) ([]ListCompetitionLiftBestE1RMsRow, error)
+	ListCurrentPersonalRecordsByUser(ctx context.Context, userID string) ([]ListCurrentPersonalRecordsByUserRow, error)
 	ListCyclesByCreatedAtAsc(ctx context.Context, arg ListCyclesByCreatedAtAscParams) ([]Cycle, error)
 	ListCyclesByCreatedAtDesc(ctx context.Context, arg ListCyclesByCreatedAtDescParams) ([]Cycle, error)
 	ListCyclesByLengthWeeksAsc(ctx context.Context, arg ListCyclesByLengthWeeksAscParams) ([]Cycle, error)
@@ -202,19 +219,16 @@ type Querier interface {
 	ListDaysFilteredByProgramByCreatedAtDesc(ctx context.Context, arg ListDaysFilteredByProgramByCreatedAtDescParams) ([]Day, error)
 	ListDaysFilteredByProgramByNameAsc(ctx context.Context, arg ListDaysFilteredByProgramByNameAscParams) ([]Day, error)
 	ListDaysFilteredByProgramByNameDesc(ctx context.Context, arg ListDaysFilteredByProgramByNameDescParams) ([]Day, error)
+	ListDeletedLoggedSetsSince(ctx context.Context, arg ListDeletedLoggedSetsSinceParams) ([]ListDeletedLoggedSetsSinceRow, error)
+	ListDependentProgressionLogs(ctx context.Context, arg ListDependentProgressionLogsParams) ([]ProgressionLog, error)
 	ListEnabledProgramProgressionsByProgram(ctx context.Context, programID string) ([]ProgramProgression, error)
 	ListEnabledProgramProgressionsByProgramAndProgression(ctx context.Context, arg ListEnabledProgramProgressionsByProgramAndProgressionParams) ([]ProgramProgression, error)
 	ListFailureCountersByProgression(ctx context.Context, progressionID string) ([]FailureCounter, error)
 	ListFailureCountersByUser(ctx context.Context, userID string) ([]FailureCounter, error)
 	ListFailureCountersByUserAndLift(ctx context.Context, arg ListFailureCountersByUserAndLiftParams) ([]FailureCounter, error)
-	ListLiftMaxesByUserByEffectiveDateAsc(ctx context.Context, arg ListLiftMaxesByUserByEffectiveDateAscParams) ([]LiftMax, error)
-	ListLiftMaxesByUserByEffectiveDateDesc(ctx context.Context, arg ListLiftMaxesByUserByEffectiveDateDescParams) ([]LiftMax, error)
-	ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateAsc(ctx context.Context, arg ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateAscParams) ([]LiftMax, error)
-	ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateDesc(ctx context.Context, arg ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateDescParams) ([]LiftMax, error)
-	ListLiftMaxesByUserFilterLiftByEffectiveDateAsc(ctx context.Context, arg ListLiftMaxesByUserFilterLiftByEffectiveDateAscParams) ([]LiftMax, error)
-	ListLiftMaxesByUserFilterLiftByEffectiveDateDesc(ctx context.Context, arg ListLiftMaxesByUserFilterLiftByEffectiveDateDescParams) ([]LiftMax, error)
-	ListLiftMaxesByUserFilterTypeByEffectiveDateAsc(ctx context.Context, arg ListLiftMaxesByUserFilterTypeByEffectiveDateAscParams) ([]LiftMax, error)
-	ListLiftMaxesByUserFilterTypeByEffectiveDateDesc(ctx context.Context, arg ListLiftMaxesByUserFilterTypeByEffectiveDateDescParams) ([]LiftMax, error)
+	ListLiftMaxChangesSince(ctx context.Context, arg ListLiftMaxChangesSinceParams) ([]LiftMax, error)
+	ListLiftMaxesByUserByEffectiveDateAsc(ctx context.Context, arg ListLiftMaxesByUserByEffectiveDateAscParams) ([]ListLiftMaxesByUserByEffectiveDateAscRow, error)
+	ListLiftMaxesByUserByEffectiveDateDesc(ctx context.Context, arg ListLiftMaxesByUserByEffectiveDateDescParams) ([]ListLiftMaxesByUserByEffectiveDateDescRow, error)
 	ListLiftsByCreatedAtAsc(ctx context.Context, arg ListLiftsByCreatedAtAscParams) ([]Lift, error)
 	ListLiftsByCreatedAtDesc(ctx context.Context, arg ListLiftsByCreatedAtDescParams) ([]Lift, error)
 	ListLiftsByNameAsc(ctx context.Context, arg ListLiftsByNameAscParams) ([]Lift, error)
@@ -223,9 +237,14 @@ type Querier interface {
 	ListLiftsFilteredByCompetitionByCreatedAtDesc(ctx context.Context, arg ListLiftsFilteredByCompetitionByCreatedAtDescParams) ([]Lift, error)
 	ListLiftsFilteredByCompetitionByNameAsc(ctx context.Context, arg ListLiftsFilteredByCompetitionByNameAscParams) ([]Lift, error)
 	ListLiftsFilteredByCompetitionByNameDesc(ctx context.Context, arg ListLiftsFilteredByCompetitionByNameDescParams) ([]Lift, error)
+	ListLoggedSetChangesSince(ctx context.Context, arg ListLoggedSetChangesSinceParams) ([]ListLoggedSetChangesSinceRow, error)
+	ListLoggedSetRevisionsBySession(ctx context.Context, sessionID string) ([]LoggedSetRevision, error)
+	ListLoggedSetsByProgramStateAndLift(ctx context.Context, arg ListLoggedSetsByProgramStateAndLiftParams) ([]ListLoggedSetsByProgramStateAndLiftRow, error)
 	ListLoggedSetsBySession(ctx context.Context, sessionID string) ([]ListLoggedSetsBySessionRow, error)
 	ListLoggedSetsBySessionAndPrescription(ctx context.Context, arg ListLoggedSetsBySessionAndPrescriptionParams) ([]ListLoggedSetsBySessionAndPrescriptionRow, error)
-	ListLoggedSetsByUser(ctx context.Context, arg ListLoggedSetsByUserParams) ([]ListLoggedSetsByUserRow, error)
+	ListLoggedSetsByUserByCreatedAtAsc(ctx context.Context, arg ListLoggedSetsByUserByCreatedAtAscParams) ([]ListLoggedSetsByUserByCreatedAtAscRow, error)
+	ListLoggedSetsByUserByCreatedAtDesc(ctx context.Context, arg ListLoggedSetsByUserByCreatedAtDescParams) ([]ListLoggedSetsByUserByCreatedAtDescRow, error)
+	ListLoggedSetsByUserChronological(ctx context.Context, userID string) ([]ListLoggedSetsByUserChronologicalRow, error)
 	ListPrescriptionsByCreatedAtAsc(ctx context.Context, arg ListPrescriptionsByCreatedAtAscParams) ([]Prescription, error)
 	ListPrescriptionsByCreatedAtDesc(ctx context.Context, arg ListPrescriptionsByCreatedAtDescParams) ([]Prescription, error)
 	ListPrescriptionsByOrderAsc(ctx context.Context, arg ListPrescriptionsByOrderAscParams) ([]Prescription, error)
@@ -246,11 +265,13 @@ type Querier interface {
 	ListProgramsFilteredByCreatedAtDesc(ctx context.Context, arg ListProgramsFilteredByCreatedAtDescParams) ([]ListProgramsFilteredByCreatedAtDescRow, error)
 	ListProgramsFilteredByNameAsc(ctx context.Context, arg ListProgramsFilteredByNameAscParams) ([]ListProgramsFilteredByNameAscRow, error)
 	ListProgramsFilteredByNameDesc(ctx context.Context, arg ListProgramsFilteredByNameDescParams) ([]ListProgramsFilteredByNameDescRow, error)
-	ListProgressionHistoryBase(ctx context.Context, arg ListProgressionHistoryBaseParams) ([]ListProgressionHistoryBaseRow, error)
+	ListProgressionHistoryByAppliedAtAsc(ctx context.Context, arg ListProgressionHistoryByAppliedAtAscParams) ([]ListProgressionHistoryByAppliedAtAscRow, error)
+	ListProgressionHistoryByAppliedAtDesc(ctx context.Context, arg ListProgressionHistoryByAppliedAtDescParams) ([]ListProgressionHistoryByAppliedAtDescRow, error)
 	ListProgressionLogsByUser(ctx context.Context, arg ListProgressionLogsByUserParams) ([]ProgressionLog, error)
 	ListProgressionLogsByUserAndLift(ctx context.Context, arg ListProgressionLogsByUserAndLiftParams) ([]ProgressionLog, error)
 	ListProgressions(ctx context.Context, arg ListProgressionsParams) ([]Progression, error)
 	ListProgressionsByType(ctx context.Context, arg ListProgressionsByTypeParams) ([]Progression, error)
+	ListReadinessCheckInsByUser(ctx context.Context, arg ListReadinessCheckInsByUserParams) ([]ReadinessCheckin, error)
 	ListUserProgressionStatesByProgression(ctx context.Context, progressionID string) ([]UserProgressionState, error)
 	ListUserProgressionStatesByUser(ctx context.Context, userID string) ([]UserProgressionState, error)
 	ListWeekDays(ctx context.Context, weekID string) ([]WeekDay, error)
@@ -267,6 +288,10 @@ type Querier interface {
 	ListWeeksFilteredByCycleByCreatedAtDesc(ctx context.Context, arg ListWeeksFilteredByCycleByCreatedAtDescParams) ([]Week, error)
 	ListWeeksFilteredByCycleByWeekNumberAsc(ctx context.Context, arg ListWeeksFilteredByCycleByWeekNumberAscParams) ([]Week, error)
 	ListWeeksFilteredByCycleByWeekNumberDesc(ctx context.Context, arg ListWeeksFilteredByCycleByWeekNumberDescParams) ([]Week, error)
+	ListWorkoutSessionChangesSince(ctx context.Context, arg ListWorkoutSessionChangesSinceParams) ([]WorkoutSession, error)
+	ListWorkoutSessionsByUserByStartedAtAsc(ctx context.Context, arg ListWorkoutSessionsByUserByStartedAtAscParams) ([]ListWorkoutSessionsByUserByStartedAtAscRow, error)
+	ListWorkoutSessionsByUserByStartedAtDesc(ctx context.Context, arg ListWorkoutSessionsByUserByStartedAtDescParams) ([]ListWorkoutSessionsByUserByStartedAtDescRow, error)
+	MarkProgressionLogReverted(ctx context.Context, arg MarkProgressionLogRevertedParams) error
 	ProgramHasEnrolledUsers(ctx context.Context, programID string) (int64, error)
 	ProgramSlugExists(ctx context.Context, slug string) (int64, error)
 	ProgramSlugExistsExcluding(ctx context.Context, arg ProgramSlugExistsExcludingParams) (int64, error)
@@ -282,6 +307,8 @@ type Querier interface {
 	UpdateFailureCounter(ctx context.Context, arg UpdateFailureCounterParams) error
 	UpdateLift(ctx context.Context, arg UpdateLiftParams) error
 	UpdateLiftMax(ctx context.Context, arg UpdateLiftMaxParams) error
+	UpdateLoggedSet(ctx context.Context, arg UpdateLoggedSetParams) error
+	UpdatePersonalRecordValue(ctx context.Context, arg UpdatePersonalRecordValueParams) error
 	UpdatePrescription(ctx context.Context, arg UpdatePrescriptionParams) error
 	UpdateProgram(ctx context.Context, arg UpdateProgramParams) error
 	UpdateProgramProgression(ctx context.Context, arg UpdateProgramProgressionParams) error
@@ -293,6 +320,8 @@ type Querier interface {
 	UpdateWorkoutSessionStatus(ctx context.Context, arg UpdateWorkoutSessionStatusParams) error
 	UpsertFailureCounterOnFailure(ctx context.Context, arg UpsertFailureCounterOnFailureParams) error
 	UpsertFailureCounterOnSuccess(ctx context.Context, arg UpsertFailureCounterOnSuccessParams) error
+	UpsertProgramReadinessMapping(ctx context.Context, arg UpsertProgramReadinessMappingParams) error
+	UpsertReadinessCheckIn(ctx context.Context, arg UpsertReadinessCheckInParams) error
 	UpsertUserProgressionState(ctx context.Context, arg UpsertUserProgressionStateParams) error
 	UserIsEnrolled(ctx context.Context, userID string) (int64, error)
 	WeekIsUsedInActiveCycle(ctx context.Context, id string) (int64, error)
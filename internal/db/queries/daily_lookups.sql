@@ -1,38 +1,48 @@
 -- name: GetDailyLookup :one
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM daily_lookups
 WHERE id = ?;
 
 -- name: ListDailyLookupsByNameAsc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM daily_lookups
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY name ASC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListDailyLookupsByNameDesc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM daily_lookups
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY name DESC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListDailyLookupsByCreatedAtAsc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM daily_lookups
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY created_at ASC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListDailyLookupsByCreatedAtDesc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM daily_lookups
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY created_at DESC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountDailyLookups :one
-SELECT COUNT(*) FROM daily_lookups;
+SELECT COUNT(*) FROM daily_lookups
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')));
 
 -- name: CreateDailyLookup :exec
-INSERT INTO daily_lookups (id, name, entries, program_id, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?);
+INSERT INTO daily_lookups (id, name, entries, program_id, created_at, updated_at, organization_id)
+VALUES (?, ?, ?, ?, ?, ?, ?);
 
 -- name: UpdateDailyLookup :exec
 UPDATE daily_lookups
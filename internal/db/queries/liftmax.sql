@@ -3,73 +3,39 @@ SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
 FROM lift_maxes
 WHERE id = ?;
 
--- name: ListLiftMaxesByUserByEffectiveDateDesc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
-FROM lift_maxes
-WHERE user_id = ?
-ORDER BY effective_date DESC
-LIMIT ? OFFSET ?;
-
 -- name: ListLiftMaxesByUserByEffectiveDateAsc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
-FROM lift_maxes
-WHERE user_id = ?
-ORDER BY effective_date ASC
-LIMIT ? OFFSET ?;
-
--- name: ListLiftMaxesByUserFilterLiftByEffectiveDateDesc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
-FROM lift_maxes
-WHERE user_id = ? AND lift_id = ?
-ORDER BY effective_date DESC
-LIMIT ? OFFSET ?;
-
--- name: ListLiftMaxesByUserFilterLiftByEffectiveDateAsc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
+SELECT sqlc.embed(lift_maxes), rowid AS seq
 FROM lift_maxes
-WHERE user_id = ? AND lift_id = ?
-ORDER BY effective_date ASC
-LIMIT ? OFFSET ?;
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(lift_id) IS NULL OR lift_id = sqlc.narg(lift_id))
+  AND (sqlc.narg(type) IS NULL OR type = sqlc.narg(type))
+  AND (sqlc.narg(from_time) IS NULL OR effective_date >= sqlc.narg(from_time))
+  AND (sqlc.narg(before_time) IS NULL OR effective_date < sqlc.narg(before_time))
+  AND effective_date >= sqlc.arg(after_value)
+  AND (effective_date > sqlc.arg(after_value) OR rowid > sqlc.arg(after_seq))
+ORDER BY effective_date ASC, rowid ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
--- name: ListLiftMaxesByUserFilterTypeByEffectiveDateDesc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
-FROM lift_maxes
-WHERE user_id = ? AND type = ?
-ORDER BY effective_date DESC
-LIMIT ? OFFSET ?;
-
--- name: ListLiftMaxesByUserFilterTypeByEffectiveDateAsc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
-FROM lift_maxes
-WHERE user_id = ? AND type = ?
-ORDER BY effective_date ASC
-LIMIT ? OFFSET ?;
-
--- name: ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateDesc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
-FROM lift_maxes
-WHERE user_id = ? AND lift_id = ? AND type = ?
-ORDER BY effective_date DESC
-LIMIT ? OFFSET ?;
-
--- name: ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateAsc :many
-SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
+-- name: ListLiftMaxesByUserByEffectiveDateDesc :many
+SELECT sqlc.embed(lift_maxes), rowid AS seq
 FROM lift_maxes
-WHERE user_id = ? AND lift_id = ? AND type = ?
-ORDER BY effective_date ASC
-LIMIT ? OFFSET ?;
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(lift_id) IS NULL OR lift_id = sqlc.narg(lift_id))
+  AND (sqlc.narg(type) IS NULL OR type = sqlc.narg(type))
+  AND (sqlc.narg(from_time) IS NULL OR effective_date >= sqlc.narg(from_time))
+  AND (sqlc.narg(before_time) IS NULL OR effective_date < sqlc.narg(before_time))
+  AND effective_date <= sqlc.arg(after_value)
+  AND (effective_date < sqlc.arg(after_value) OR rowid < sqlc.arg(after_seq))
+ORDER BY effective_date DESC, rowid DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountLiftMaxesByUser :one
-SELECT COUNT(*) FROM lift_maxes WHERE user_id = ?;
-
--- name: CountLiftMaxesByUserFilterLift :one
-SELECT COUNT(*) FROM lift_maxes WHERE user_id = ? AND lift_id = ?;
-
--- name: CountLiftMaxesByUserFilterType :one
-SELECT COUNT(*) FROM lift_maxes WHERE user_id = ? AND type = ?;
-
--- name: CountLiftMaxesByUserFilterLiftAndType :one
-SELECT COUNT(*) FROM lift_maxes WHERE user_id = ? AND lift_id = ? AND type = ?;
+SELECT COUNT(*) FROM lift_maxes
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(lift_id) IS NULL OR lift_id = sqlc.narg(lift_id))
+  AND (sqlc.narg(type) IS NULL OR type = sqlc.narg(type))
+  AND (sqlc.narg(from_time) IS NULL OR effective_date >= sqlc.narg(from_time))
+  AND (sqlc.narg(before_time) IS NULL OR effective_date < sqlc.narg(before_time));
 
 -- name: CreateLiftMax :exec
 INSERT INTO lift_maxes (id, user_id, lift_id, type, value, effective_date, created_at, updated_at)
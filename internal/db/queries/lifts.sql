@@ -1,74 +1,95 @@
 -- name: GetLift :one
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
 WHERE id = ?;
 
 -- name: GetLiftBySlug :one
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
 WHERE slug = ?;
 
 -- name: ListLiftsByNameAsc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY name ASC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListLiftsByNameDesc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY name DESC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListLiftsByCreatedAtAsc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY created_at ASC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListLiftsByCreatedAtDesc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY created_at DESC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListLiftsFilteredByCompetitionByNameAsc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
-WHERE is_competition_lift = ?
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
+  AND is_competition_lift = sqlc.arg('is_competition_lift')
 ORDER BY name ASC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListLiftsFilteredByCompetitionByNameDesc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
-WHERE is_competition_lift = ?
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
+  AND is_competition_lift = sqlc.arg('is_competition_lift')
 ORDER BY name DESC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListLiftsFilteredByCompetitionByCreatedAtAsc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
-WHERE is_competition_lift = ?
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
+  AND is_competition_lift = sqlc.arg('is_competition_lift')
 ORDER BY created_at ASC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListLiftsFilteredByCompetitionByCreatedAtDesc :many
-SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at
+SELECT id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id
 FROM lifts
-WHERE is_competition_lift = ?
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
+  AND is_competition_lift = sqlc.arg('is_competition_lift')
 ORDER BY created_at DESC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountLifts :one
-SELECT COUNT(*) FROM lifts;
+SELECT COUNT(*) FROM lifts
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')));
 
 -- name: CountLiftsFilteredByCompetition :one
-SELECT COUNT(*) FROM lifts WHERE is_competition_lift = ?;
+SELECT COUNT(*) FROM lifts
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
+  AND is_competition_lift = sqlc.arg('is_competition_lift');
 
 -- name: CreateLift :exec
-INSERT INTO lifts (id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?);
+INSERT INTO lifts (id, name, slug, is_competition_lift, parent_lift_id, created_at, updated_at, organization_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: UpdateLift :exec
 UPDATE lifts
@@ -0,0 +1,9 @@
+-- name: CreateLoggedSetRevision :exec
+INSERT INTO logged_set_revisions (id, logged_set_id, session_id, user_id, changed_by, action, source, reason, previous_data, new_data, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListLoggedSetRevisionsBySession :many
+SELECT id, logged_set_id, session_id, user_id, changed_by, action, source, reason, previous_data, new_data, created_at
+FROM logged_set_revisions
+WHERE session_id = ?
+ORDER BY created_at ASC, id ASC;
@@ -13,15 +13,43 @@ FROM logged_sets
 WHERE session_id = ?
 ORDER BY created_at ASC, set_number ASC;
 
--- name: ListLoggedSetsByUser :many
-SELECT id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, rpe, created_at
+-- name: ListLoggedSetsByProgramStateAndLift :many
+SELECT ls.id, ls.user_id, ls.session_id, ls.prescription_id, ls.lift_id, ls.set_number, ls.weight, ls.target_reps, ls.reps_performed, ls.is_amrap, ls.rpe, ls.created_at
+FROM logged_sets ls
+JOIN workout_sessions ws ON ws.id = ls.session_id
+WHERE ws.user_program_state_id = ? AND ls.lift_id = ?
+ORDER BY ls.created_at ASC, ls.set_number ASC;
+
+-- name: ListLoggedSetsByUserByCreatedAtAsc :many
+SELECT id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, rpe, created_at, rowid AS seq
 FROM logged_sets
-WHERE user_id = ?
-ORDER BY created_at DESC
-LIMIT ? OFFSET ?;
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(lift_id) IS NULL OR lift_id = sqlc.narg(lift_id))
+  AND (sqlc.narg(from_time) IS NULL OR created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(before_time) IS NULL OR created_at < sqlc.narg(before_time))
+  AND created_at >= sqlc.arg(after_value)
+  AND (created_at > sqlc.arg(after_value) OR rowid > sqlc.arg(after_seq))
+ORDER BY created_at ASC, rowid ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListLoggedSetsByUserByCreatedAtDesc :many
+SELECT id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, rpe, created_at, rowid AS seq
+FROM logged_sets
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(lift_id) IS NULL OR lift_id = sqlc.narg(lift_id))
+  AND (sqlc.narg(from_time) IS NULL OR created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(before_time) IS NULL OR created_at < sqlc.narg(before_time))
+  AND created_at <= sqlc.arg(after_value)
+  AND (created_at < sqlc.arg(after_value) OR rowid < sqlc.arg(after_seq))
+ORDER BY created_at DESC, rowid DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountLoggedSetsByUser :one
-SELECT COUNT(*) FROM logged_sets WHERE user_id = ?;
+SELECT COUNT(*) FROM logged_sets
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(lift_id) IS NULL OR lift_id = sqlc.narg(lift_id))
+  AND (sqlc.narg(from_time) IS NULL OR created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(before_time) IS NULL OR created_at < sqlc.narg(before_time));
 
 -- name: GetLatestAMRAPForLift :one
 SELECT id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, rpe, created_at
@@ -41,3 +69,14 @@ SELECT id, user_id, session_id, prescription_id, lift_id, set_number, weight, ta
 FROM logged_sets
 WHERE session_id = ? AND prescription_id = ?
 ORDER BY set_number ASC;
+
+-- name: ListLoggedSetsByUserChronological :many
+SELECT id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, rpe, created_at
+FROM logged_sets
+WHERE user_id = ?
+ORDER BY created_at ASC, set_number ASC;
+
+-- name: UpdateLoggedSet :exec
+UPDATE logged_sets
+SET set_number = ?, weight = ?, target_reps = ?, reps_performed = ?, is_amrap = ?, rpe = ?
+WHERE id = ?;
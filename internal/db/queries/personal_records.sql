@@ -0,0 +1,71 @@
+-- name: CreatePersonalRecord :exec
+INSERT INTO personal_records (id, user_id, lift_id, record_type, rep_count, value, weight, reps, previous_value, logged_set_id, session_id, achieved_at, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: DeletePersonalRecordsByUser :exec
+DELETE FROM personal_records WHERE user_id = ?;
+
+-- name: UpdatePersonalRecordValue :exec
+UPDATE personal_records
+SET value = ?, weight = ?, reps = ?, logged_set_id = ?, achieved_at = ?
+WHERE id = ?;
+
+-- name: GetBestRepMaxRecord :one
+SELECT id, user_id, lift_id, record_type, rep_count, value, weight, reps, previous_value, logged_set_id, session_id, achieved_at, created_at
+FROM personal_records
+WHERE user_id = ? AND lift_id = ? AND record_type = 'REP_MAX' AND rep_count = ?
+ORDER BY value DESC, achieved_at ASC
+LIMIT 1;
+
+-- name: GetBestE1RMRecord :one
+SELECT id, user_id, lift_id, record_type, rep_count, value, weight, reps, previous_value, logged_set_id, session_id, achieved_at, created_at
+FROM personal_records
+WHERE user_id = ? AND lift_id = ? AND record_type = 'E1RM'
+ORDER BY value DESC, achieved_at ASC
+LIMIT 1;
+
+-- name: GetBestSessionVolumeRecordExcludingSession :one
+SELECT id, user_id, lift_id, record_type, rep_count, value, weight, reps, previous_value, logged_set_id, session_id, achieved_at, created_at
+FROM personal_records
+WHERE user_id = ? AND lift_id = ? AND record_type = 'SESSION_VOLUME' AND session_id != ?
+ORDER BY value DESC, achieved_at ASC
+LIMIT 1;
+
+-- name: GetSessionVolumeRecord :one
+SELECT id, user_id, lift_id, record_type, rep_count, value, weight, reps, previous_value, logged_set_id, session_id, achieved_at, created_at
+FROM personal_records
+WHERE session_id = ? AND lift_id = ? AND record_type = 'SESSION_VOLUME'
+LIMIT 1;
+
+-- name: GetBestCompetitionTotalRecord :one
+SELECT id, user_id, lift_id, record_type, rep_count, value, weight, reps, previous_value, logged_set_id, session_id, achieved_at, created_at
+FROM personal_records
+WHERE user_id = ? AND record_type = 'COMPETITION_TOTAL'
+ORDER BY value DESC, achieved_at ASC
+LIMIT 1;
+
+-- name: GetSessionLiftVolume :one
+SELECT CAST(COALESCE(SUM(weight * reps_performed), 0) AS REAL) AS volume
+FROM logged_sets
+WHERE session_id = ? AND lift_id = ?;
+
+-- name: ListCompetitionLiftBestE1RMs :many
+SELECT l.id AS lift_id, CAST(COALESCE(MAX(pr.value), 0) AS REAL) AS best_value
+FROM lifts l
+LEFT JOIN personal_records pr ON pr.lift_id = l.id AND pr.user_id = ? AND pr.record_type = 'E1RM'
+WHERE l.is_competition_lift = 1 AND l.parent_lift_id IS NULL AND l.organization_id IS NULL
+GROUP BY l.id
+ORDER BY l.id;
+
+-- name: ListCurrentPersonalRecordsByUser :many
+SELECT pr.id, pr.user_id, pr.lift_id, pr.record_type, pr.rep_count, pr.value, pr.weight, pr.reps, pr.previous_value, pr.logged_set_id, pr.session_id, pr.achieved_at, pr.created_at,
+       l.name AS lift_name, l.slug AS lift_slug
+FROM (
+    SELECT id, user_id, lift_id, record_type, rep_count, value, weight, reps, previous_value, logged_set_id, session_id, achieved_at, created_at,
+           ROW_NUMBER() OVER (PARTITION BY lift_id, record_type, rep_count ORDER BY value DESC, achieved_at ASC) AS rn
+    FROM personal_records
+    WHERE personal_records.user_id = ?
+) pr
+LEFT JOIN lifts l ON l.id = pr.lift_id
+WHERE pr.rn = 1
+ORDER BY pr.record_type, l.name, pr.rep_count;
@@ -1,54 +1,62 @@
 -- name: GetProgram :one
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 WHERE id = ?;
 
 -- name: GetProgramBySlug :one
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 WHERE slug = ?;
 
 -- name: ListProgramsFilteredByNameAsc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 WHERE (sqlc.narg('difficulty') IS NULL OR difficulty = sqlc.narg('difficulty'))
   AND (sqlc.narg('days_per_week') IS NULL OR days_per_week = sqlc.narg('days_per_week'))
   AND (sqlc.narg('focus') IS NULL OR focus = sqlc.narg('focus'))
   AND (sqlc.narg('has_amrap') IS NULL OR has_amrap = sqlc.narg('has_amrap'))
   AND (sqlc.narg('search') IS NULL OR name LIKE '%' || sqlc.narg('search') || '%' COLLATE NOCASE)
+  AND (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY name ASC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListProgramsFilteredByNameDesc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 WHERE (sqlc.narg('difficulty') IS NULL OR difficulty = sqlc.narg('difficulty'))
   AND (sqlc.narg('days_per_week') IS NULL OR days_per_week = sqlc.narg('days_per_week'))
   AND (sqlc.narg('focus') IS NULL OR focus = sqlc.narg('focus'))
   AND (sqlc.narg('has_amrap') IS NULL OR has_amrap = sqlc.narg('has_amrap'))
   AND (sqlc.narg('search') IS NULL OR name LIKE '%' || sqlc.narg('search') || '%' COLLATE NOCASE)
+  AND (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY name DESC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListProgramsFilteredByCreatedAtAsc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 WHERE (sqlc.narg('difficulty') IS NULL OR difficulty = sqlc.narg('difficulty'))
   AND (sqlc.narg('days_per_week') IS NULL OR days_per_week = sqlc.narg('days_per_week'))
   AND (sqlc.narg('focus') IS NULL OR focus = sqlc.narg('focus'))
   AND (sqlc.narg('has_amrap') IS NULL OR has_amrap = sqlc.narg('has_amrap'))
   AND (sqlc.narg('search') IS NULL OR name LIKE '%' || sqlc.narg('search') || '%' COLLATE NOCASE)
+  AND (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY created_at ASC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListProgramsFilteredByCreatedAtDesc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 WHERE (sqlc.narg('difficulty') IS NULL OR difficulty = sqlc.narg('difficulty'))
   AND (sqlc.narg('days_per_week') IS NULL OR days_per_week = sqlc.narg('days_per_week'))
   AND (sqlc.narg('focus') IS NULL OR focus = sqlc.narg('focus'))
   AND (sqlc.narg('has_amrap') IS NULL OR has_amrap = sqlc.narg('has_amrap'))
   AND (sqlc.narg('search') IS NULL OR name LIKE '%' || sqlc.narg('search') || '%' COLLATE NOCASE)
+  AND (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY created_at DESC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
@@ -58,28 +66,30 @@ WHERE (sqlc.narg('difficulty') IS NULL OR difficulty = sqlc.narg('difficulty'))
   AND (sqlc.narg('days_per_week') IS NULL OR days_per_week = sqlc.narg('days_per_week'))
   AND (sqlc.narg('focus') IS NULL OR focus = sqlc.narg('focus'))
   AND (sqlc.narg('has_amrap') IS NULL OR has_amrap = sqlc.narg('has_amrap'))
-  AND (sqlc.narg('search') IS NULL OR name LIKE '%' || sqlc.narg('search') || '%' COLLATE NOCASE);
+  AND (sqlc.narg('search') IS NULL OR name LIKE '%' || sqlc.narg('search') || '%' COLLATE NOCASE)
+  AND (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')));
 
 -- name: ListProgramsByNameAsc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 ORDER BY name ASC
 LIMIT ? OFFSET ?;
 
 -- name: ListProgramsByNameDesc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 ORDER BY name DESC
 LIMIT ? OFFSET ?;
 
 -- name: ListProgramsByCreatedAtAsc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 ORDER BY created_at ASC
 LIMIT ? OFFSET ?;
 
 -- name: ListProgramsByCreatedAtDesc :many
-SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at
+SELECT id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id
 FROM programs
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?;
@@ -88,8 +98,8 @@ LIMIT ? OFFSET ?;
 SELECT COUNT(*) FROM programs;
 
 -- name: CreateProgram :exec
-INSERT INTO programs (id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+INSERT INTO programs (id, name, slug, description, cycle_id, weekly_lookup_id, daily_lookup_id, default_rounding, difficulty, days_per_week, focus, has_amrap, created_at, updated_at, organization_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: UpdateProgram :exec
 UPDATE programs
@@ -124,12 +134,12 @@ FROM cycles c
 WHERE c.id = ?;
 
 -- name: GetWeeklyLookupForProgram :one
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM weekly_lookups
 WHERE id = ?;
 
 -- name: GetDailyLookupForProgram :one
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM daily_lookups
 WHERE id = ?;
 
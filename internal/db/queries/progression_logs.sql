@@ -1,9 +1,17 @@
 -- name: CreateProgressionLog :exec
-INSERT INTO progression_logs (id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+INSERT INTO progression_logs (id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetLatestFailureResetLog :one
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
+FROM progression_logs
+WHERE user_id = ? AND lift_id = ? AND progression_id = ? AND previous_failures > 0
+    AND reverts_log_id IS NULL AND reverted_by_log_id IS NULL
+ORDER BY applied_at DESC
+LIMIT 1;
 
 -- name: GetProgressionLog :one
-SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
 FROM progression_logs
 WHERE id = ?;
 
@@ -14,19 +22,44 @@ SELECT EXISTS(
 ) AS already_applied;
 
 -- name: ListProgressionLogsByUser :many
-SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
 FROM progression_logs
 WHERE user_id = ?
 ORDER BY applied_at DESC
 LIMIT ? OFFSET ?;
 
 -- name: ListProgressionLogsByUserAndLift :many
-SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
 FROM progression_logs
 WHERE user_id = ? AND lift_id = ?
 ORDER BY applied_at DESC
 LIMIT ? OFFSET ?;
 
+-- name: ListActiveProgressionLogsByLoggedSet :many
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
+FROM progression_logs
+WHERE user_id = ? AND json_extract(trigger_context, '$.loggedSetId') = sqlc.arg(logged_set_id)
+    AND reverts_log_id IS NULL AND reverted_by_log_id IS NULL
+ORDER BY applied_at DESC;
+
+-- name: ListActiveSessionProgressionLogsByLift :many
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
+FROM progression_logs
+WHERE user_id = ? AND lift_id = ? AND trigger_type = 'AFTER_SESSION'
+    AND json_extract(trigger_context, '$.sessionId') = sqlc.arg(session_id)
+    AND reverts_log_id IS NULL AND reverted_by_log_id IS NULL
+ORDER BY applied_at DESC;
+
+-- name: ListDependentProgressionLogs :many
+SELECT id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, trigger_context, applied_at, previous_stage, reverts_log_id, reverted_by_log_id, previous_failures
+FROM progression_logs
+WHERE user_id = ? AND lift_id = ? AND applied_at > ? AND id != ?
+    AND reverts_log_id IS NULL AND reverted_by_log_id IS NULL
+ORDER BY applied_at ASC;
+
+-- name: MarkProgressionLogReverted :exec
+UPDATE progression_logs SET reverted_by_log_id = ? WHERE id = ?;
+
 -- name: CountProgressionLogsByUser :one
 SELECT COUNT(*) FROM progression_logs WHERE user_id = ?;
 
@@ -36,10 +69,9 @@ SELECT COUNT(*) FROM progression_logs WHERE user_id = ? AND lift_id = ?;
 -- name: DeleteProgressionLog :exec
 DELETE FROM progression_logs WHERE id = ?;
 
--- name: ListProgressionHistoryBase :many
+-- name: ListProgressionHistoryByAppliedAtAsc :many
 SELECT
     pl.id,
-    pl.user_id,
     pl.progression_id,
     pl.lift_id,
     pl.previous_value,
@@ -48,16 +80,63 @@ SELECT
     pl.trigger_type,
     pl.trigger_context,
     pl.applied_at,
+    pl.reverts_log_id,
+    pl.reverted_by_log_id,
     p.name AS progression_name,
     p.type AS progression_type,
-    l.name AS lift_name
+    l.name AS lift_name,
+    pl.rowid AS seq
 FROM progression_logs pl
 JOIN progressions p ON pl.progression_id = p.id
 JOIN lifts l ON pl.lift_id = l.id
-WHERE pl.user_id = ?
-ORDER BY pl.applied_at DESC
-LIMIT ? OFFSET ?;
+WHERE pl.user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(lift_id) IS NULL OR pl.lift_id = sqlc.narg(lift_id))
+  AND (sqlc.narg(progression_type) IS NULL OR p.type = sqlc.narg(progression_type))
+  AND (sqlc.narg(trigger_type) IS NULL OR pl.trigger_type = sqlc.narg(trigger_type))
+  AND (sqlc.narg(from_time) IS NULL OR pl.applied_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(before_time) IS NULL OR pl.applied_at < sqlc.narg(before_time))
+  AND pl.applied_at >= sqlc.arg(after_value)
+  AND (pl.applied_at > sqlc.arg(after_value) OR pl.rowid > sqlc.arg(after_seq))
+ORDER BY pl.applied_at ASC, pl.rowid ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListProgressionHistoryByAppliedAtDesc :many
+SELECT
+    pl.id,
+    pl.progression_id,
+    pl.lift_id,
+    pl.previous_value,
+    pl.new_value,
+    pl.delta,
+    pl.trigger_type,
+    pl.trigger_context,
+    pl.applied_at,
+    pl.reverts_log_id,
+    pl.reverted_by_log_id,
+    p.name AS progression_name,
+    p.type AS progression_type,
+    l.name AS lift_name,
+    pl.rowid AS seq
+FROM progression_logs pl
+JOIN progressions p ON pl.progression_id = p.id
+JOIN lifts l ON pl.lift_id = l.id
+WHERE pl.user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(lift_id) IS NULL OR pl.lift_id = sqlc.narg(lift_id))
+  AND (sqlc.narg(progression_type) IS NULL OR p.type = sqlc.narg(progression_type))
+  AND (sqlc.narg(trigger_type) IS NULL OR pl.trigger_type = sqlc.narg(trigger_type))
+  AND (sqlc.narg(from_time) IS NULL OR pl.applied_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(before_time) IS NULL OR pl.applied_at < sqlc.narg(before_time))
+  AND pl.applied_at <= sqlc.arg(after_value)
+  AND (pl.applied_at < sqlc.arg(after_value) OR pl.rowid < sqlc.arg(after_seq))
+ORDER BY pl.applied_at DESC, pl.rowid DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
--- name: CountProgressionHistoryBase :one
+-- name: CountProgressionHistory :one
 SELECT COUNT(*) FROM progression_logs pl
-WHERE pl.user_id = ?;
+JOIN progressions p ON pl.progression_id = p.id
+WHERE pl.user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(lift_id) IS NULL OR pl.lift_id = sqlc.narg(lift_id))
+  AND (sqlc.narg(progression_type) IS NULL OR p.type = sqlc.narg(progression_type))
+  AND (sqlc.narg(trigger_type) IS NULL OR pl.trigger_type = sqlc.narg(trigger_type))
+  AND (sqlc.narg(from_time) IS NULL OR pl.applied_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(before_time) IS NULL OR pl.applied_at < sqlc.narg(before_time));
@@ -0,0 +1,60 @@
+-- name: UpsertReadinessCheckIn :exec
+INSERT INTO readiness_checkins (id, user_id, checkin_date, sleep_quality, soreness, stress, readiness_score, hrv, score, notes, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(user_id, checkin_date) DO UPDATE SET
+    sleep_quality = excluded.sleep_quality,
+    soreness = excluded.soreness,
+    stress = excluded.stress,
+    readiness_score = excluded.readiness_score,
+    hrv = excluded.hrv,
+    score = excluded.score,
+    notes = excluded.notes,
+    updated_at = excluded.updated_at;
+
+-- name: GetReadinessCheckInByDate :one
+SELECT id, user_id, checkin_date, sleep_quality, soreness, stress, readiness_score, hrv, score, notes, created_at, updated_at
+FROM readiness_checkins
+WHERE user_id = ? AND checkin_date = ?;
+
+-- name: ListReadinessCheckInsByUser :many
+SELECT id, user_id, checkin_date, sleep_quality, soreness, stress, readiness_score, hrv, score, notes, created_at, updated_at
+FROM readiness_checkins
+WHERE user_id = ?
+ORDER BY checkin_date DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountReadinessCheckInsByUser :one
+SELECT COUNT(*) FROM readiness_checkins WHERE user_id = ?;
+
+-- name: GetReadinessHRVBaseline :one
+SELECT CAST(AVG(hrv) AS REAL) AS baseline
+FROM (
+    SELECT hrv FROM readiness_checkins
+    WHERE user_id = ? AND checkin_date < ? AND hrv IS NOT NULL
+    ORDER BY checkin_date DESC
+    LIMIT ?
+);
+
+-- name: GetProgramReadinessMapping :one
+SELECT program_id, tiers, created_at, updated_at
+FROM program_readiness_mappings
+WHERE program_id = ?;
+
+-- name: UpsertProgramReadinessMapping :exec
+INSERT INTO program_readiness_mappings (program_id, tiers, created_at, updated_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(program_id) DO UPDATE SET
+    tiers = excluded.tiers,
+    updated_at = excluded.updated_at;
+
+-- name: DeleteProgramReadinessMapping :exec
+DELETE FROM program_readiness_mappings WHERE program_id = ?;
+
+-- name: CreateWorkoutSessionReadiness :exec
+INSERT INTO workout_session_readiness (session_id, checkin_id, score, load_modifier, volume_modifier, created_at)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: GetWorkoutSessionReadiness :one
+SELECT session_id, checkin_id, score, load_modifier, volume_modifier, created_at
+FROM workout_session_readiness
+WHERE session_id = ?;
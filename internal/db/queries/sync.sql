@@ -0,0 +1,45 @@
+-- name: CreateSyncMutation :exec
+INSERT INTO sync_mutations (id, user_id, mutation_type, status, code, message, entity_id, client_timestamp, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetSyncMutation :one
+SELECT id, user_id, mutation_type, status, code, message, entity_id, client_timestamp, created_at
+FROM sync_mutations
+WHERE id = ?;
+
+-- name: ListWorkoutSessionChangesSince :many
+SELECT ws.id, ws.user_program_state_id, ws.week_number, ws.day_index, ws.status, ws.started_at, ws.finished_at, ws.created_at, ws.updated_at
+FROM workout_sessions ws
+JOIN user_program_states ups ON ws.user_program_state_id = ups.id
+WHERE ups.user_id = sqlc.arg(user_id) AND substr(ws.updated_at, 1, 19) >= sqlc.arg(since)
+ORDER BY ws.updated_at ASC, ws.id ASC;
+
+-- name: ListLoggedSetChangesSince :many
+SELECT id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, rpe, created_at
+FROM logged_sets
+WHERE user_id = sqlc.arg(user_id) AND (
+    substr(created_at, 1, 19) >= sqlc.arg(since)
+    OR id IN (
+        SELECT logged_set_id FROM logged_set_revisions
+        WHERE logged_set_revisions.user_id = sqlc.arg(user_id) AND action = 'UPDATE'
+            AND substr(logged_set_revisions.created_at, 1, 19) >= sqlc.arg(since)
+    )
+    OR session_id IN (
+        SELECT entity_id FROM sync_mutations
+        WHERE sync_mutations.user_id = sqlc.arg(user_id) AND mutation_type = 'LOG_SETS' AND status = 'APPLIED'
+            AND substr(sync_mutations.created_at, 1, 19) >= sqlc.arg(since)
+    )
+)
+ORDER BY created_at ASC, set_number ASC;
+
+-- name: ListDeletedLoggedSetsSince :many
+SELECT logged_set_id, session_id, created_at
+FROM logged_set_revisions
+WHERE user_id = sqlc.arg(user_id) AND action = 'DELETE' AND substr(created_at, 1, 19) >= sqlc.arg(since)
+ORDER BY created_at ASC, id ASC;
+
+-- name: ListLiftMaxChangesSince :many
+SELECT id, user_id, lift_id, type, value, effective_date, created_at, updated_at
+FROM lift_maxes
+WHERE user_id = sqlc.arg(user_id) AND substr(updated_at, 1, 19) >= sqlc.arg(since)
+ORDER BY updated_at ASC, id ASC;
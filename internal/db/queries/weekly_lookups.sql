@@ -1,38 +1,48 @@
 -- name: GetWeeklyLookup :one
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM weekly_lookups
 WHERE id = ?;
 
 -- name: ListWeeklyLookupsByNameAsc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM weekly_lookups
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY name ASC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListWeeklyLookupsByNameDesc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM weekly_lookups
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY name DESC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListWeeklyLookupsByCreatedAtAsc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM weekly_lookups
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY created_at ASC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListWeeklyLookupsByCreatedAtDesc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM weekly_lookups
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')))
 ORDER BY created_at DESC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountWeeklyLookups :one
-SELECT COUNT(*) FROM weekly_lookups;
+SELECT COUNT(*) FROM weekly_lookups
+WHERE (sqlc.narg('viewer_id') IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = sqlc.narg('viewer_id')));
 
 -- name: CreateWeeklyLookup :exec
-INSERT INTO weekly_lookups (id, name, entries, program_id, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?);
+INSERT INTO weekly_lookups (id, name, entries, program_id, created_at, updated_at, organization_id)
+VALUES (?, ?, ?, ?, ?, ?, ?);
 
 -- name: UpdateWeeklyLookup :exec
 UPDATE weekly_lookups
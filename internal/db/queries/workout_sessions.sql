@@ -37,33 +37,40 @@ WHERE id = ?;
 -- name: DeleteWorkoutSession :exec
 DELETE FROM workout_sessions WHERE id = ?;
 
--- name: CountWorkoutSessionsByUserID :one
+-- name: CountWorkoutSessionsByUser :one
 SELECT COUNT(*) as count
 FROM workout_sessions ws
 JOIN user_program_states ups ON ws.user_program_state_id = ups.id
-WHERE ups.user_id = ?;
+WHERE ups.user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(status) IS NULL OR ws.status = sqlc.narg(status))
+  AND (sqlc.narg(from_time) IS NULL OR ws.started_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(before_time) IS NULL OR ws.started_at < sqlc.narg(before_time));
 
--- name: CountWorkoutSessionsByUserIDWithStatus :one
-SELECT COUNT(*) as count
-FROM workout_sessions ws
-JOIN user_program_states ups ON ws.user_program_state_id = ups.id
-WHERE ups.user_id = ? AND ws.status = ?;
-
--- name: GetWorkoutSessionsByUserID :many
-SELECT ws.id, ws.user_program_state_id, ws.week_number, ws.day_index, ws.status, ws.started_at, ws.finished_at, ws.created_at, ws.updated_at
+-- name: ListWorkoutSessionsByUserByStartedAtAsc :many
+SELECT sqlc.embed(ws), ws.rowid AS seq
 FROM workout_sessions ws
 JOIN user_program_states ups ON ws.user_program_state_id = ups.id
-WHERE ups.user_id = ?
-ORDER BY ws.created_at DESC
-LIMIT ? OFFSET ?;
+WHERE ups.user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(status) IS NULL OR ws.status = sqlc.narg(status))
+  AND (sqlc.narg(from_time) IS NULL OR ws.started_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(before_time) IS NULL OR ws.started_at < sqlc.narg(before_time))
+  AND ws.started_at >= sqlc.arg(after_value)
+  AND (ws.started_at > sqlc.arg(after_value) OR ws.rowid > sqlc.arg(after_seq))
+ORDER BY ws.started_at ASC, ws.rowid ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
--- name: GetWorkoutSessionsByUserIDWithStatus :many
-SELECT ws.id, ws.user_program_state_id, ws.week_number, ws.day_index, ws.status, ws.started_at, ws.finished_at, ws.created_at, ws.updated_at
+-- name: ListWorkoutSessionsByUserByStartedAtDesc :many
+SELECT sqlc.embed(ws), ws.rowid AS seq
 FROM workout_sessions ws
 JOIN user_program_states ups ON ws.user_program_state_id = ups.id
-WHERE ups.user_id = ? AND ws.status = ?
-ORDER BY ws.created_at DESC
-LIMIT ? OFFSET ?;
+WHERE ups.user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(status) IS NULL OR ws.status = sqlc.narg(status))
+  AND (sqlc.narg(from_time) IS NULL OR ws.started_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(before_time) IS NULL OR ws.started_at < sqlc.narg(before_time))
+  AND ws.started_at <= sqlc.arg(after_value)
+  AND (ws.started_at < sqlc.arg(after_value) OR ws.rowid < sqlc.arg(after_seq))
+ORDER BY ws.started_at DESC, ws.rowid DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: GetActiveWorkoutSessionByUserID :one
 SELECT ws.id, ws.user_program_state_id, ws.week_number, ws.day_index, ws.status, ws.started_at, ws.finished_at, ws.created_at, ws.updated_at
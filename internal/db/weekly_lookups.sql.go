@@ -12,27 +12,30 @@ import (
 
 const countWeeklyLookups = `-- name: CountWeeklyLookups :one
 SELECT COUNT(*) FROM weekly_lookups
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 `
 
-func (q *Queries) CountWeeklyLookups(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countWeeklyLookups)
+func (q *Queries) CountWeeklyLookups(ctx context.Context, viewerID interface{}) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countWeeklyLookups, viewerID)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const createWeeklyLookup = `-- name: CreateWeeklyLookup :exec
-INSERT INTO weekly_lookups (id, name, entries, program_id, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?)
+INSERT INTO weekly_lookups (id, name, entries, program_id, created_at, updated_at, organization_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateWeeklyLookupParams struct {
-	ID        string         `json:"id"`
-	Name      string         `json:"name"`
-	Entries   string         `json:"entries"`
-	ProgramID sql.NullString `json:"program_id"`
-	CreatedAt string         `json:"created_at"`
-	UpdatedAt string         `json:"updated_at"`
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	Entries        string         `json:"entries"`
+	ProgramID      sql.NullString `json:"program_id"`
+	CreatedAt      string         `json:"created_at"`
+	UpdatedAt      string         `json:"updated_at"`
+	OrganizationID sql.NullString `json:"organization_id"`
 }
 
 func (q *Queries) CreateWeeklyLookup(ctx context.Context, arg CreateWeeklyLookupParams) error {
@@ -43,6 +46,7 @@ func (q *Queries) CreateWeeklyLookup(ctx context.Context, arg CreateWeeklyLookup
 		arg.ProgramID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.OrganizationID,
 	)
 	return err
 }
@@ -57,7 +61,7 @@ func (q *Queries) DeleteWeeklyLookup(ctx context.Context, id string) error {
 }
 
 const getWeeklyLookup = `-- name: GetWeeklyLookup :one
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM weekly_lookups
 WHERE id = ?
 `
@@ -72,24 +76,28 @@ func (q *Queries) GetWeeklyLookup(ctx context.Context, id string) (WeeklyLookup,
 		&i.ProgramID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.OrganizationID,
 	)
 	return i, err
 }
 
 const listWeeklyLookupsByCreatedAtAsc = `-- name: ListWeeklyLookupsByCreatedAtAsc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM weekly_lookups
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 ORDER BY created_at ASC
-LIMIT ? OFFSET ?
+LIMIT ?3 OFFSET ?2
 `
 
 type ListWeeklyLookupsByCreatedAtAscParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	ViewerID interface{} `json:"viewer_id"`
+	Offset   int64       `json:"offset"`
+	Limit    int64       `json:"limit"`
 }
 
 func (q *Queries) ListWeeklyLookupsByCreatedAtAsc(ctx context.Context, arg ListWeeklyLookupsByCreatedAtAscParams) ([]WeeklyLookup, error) {
-	rows, err := q.db.QueryContext(ctx, listWeeklyLookupsByCreatedAtAsc, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listWeeklyLookupsByCreatedAtAsc, arg.ViewerID, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -104,6 +112,7 @@ func (q *Queries) ListWeeklyLookupsByCreatedAtAsc(ctx context.Context, arg ListW
 			&i.ProgramID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -119,19 +128,22 @@ func (q *Queries) ListWeeklyLookupsByCreatedAtAsc(ctx context.Context, arg ListW
 }
 
 const listWeeklyLookupsByCreatedAtDesc = `-- name: ListWeeklyLookupsByCreatedAtDesc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM weekly_lookups
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 ORDER BY created_at DESC
-LIMIT ? OFFSET ?
+LIMIT ?3 OFFSET ?2
 `
 
 type ListWeeklyLookupsByCreatedAtDescParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	ViewerID interface{} `json:"viewer_id"`
+	Offset   int64       `json:"offset"`
+	Limit    int64       `json:"limit"`
 }
 
 func (q *Queries) ListWeeklyLookupsByCreatedAtDesc(ctx context.Context, arg ListWeeklyLookupsByCreatedAtDescParams) ([]WeeklyLookup, error) {
-	rows, err := q.db.QueryContext(ctx, listWeeklyLookupsByCreatedAtDesc, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listWeeklyLookupsByCreatedAtDesc, arg.ViewerID, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -146,6 +158,7 @@ func (q *Queries) ListWeeklyLookupsByCreatedAtDesc(ctx context.Context, arg List
 			&i.ProgramID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -161,19 +174,22 @@ func (q *Queries) ListWeeklyLookupsByCreatedAtDesc(ctx context.Context, arg List
 }
 
 const listWeeklyLookupsByNameAsc = `-- name: ListWeeklyLookupsByNameAsc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM weekly_lookups
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 ORDER BY name ASC
-LIMIT ? OFFSET ?
+LIMIT ?3 OFFSET ?2
 `
 
 type ListWeeklyLookupsByNameAscParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	ViewerID interface{} `json:"viewer_id"`
+	Offset   int64       `json:"offset"`
+	Limit    int64       `json:"limit"`
 }
 
 func (q *Queries) ListWeeklyLookupsByNameAsc(ctx context.Context, arg ListWeeklyLookupsByNameAscParams) ([]WeeklyLookup, error) {
-	rows, err := q.db.QueryContext(ctx, listWeeklyLookupsByNameAsc, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listWeeklyLookupsByNameAsc, arg.ViewerID, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -188,6 +204,7 @@ func (q *Queries) ListWeeklyLookupsByNameAsc(ctx context.Context, arg ListWeekly
 			&i.ProgramID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -203,19 +220,22 @@ func (q *Queries) ListWeeklyLookupsByNameAsc(ctx context.Context, arg ListWeekly
 }
 
 const listWeeklyLookupsByNameDesc = `-- name: ListWeeklyLookupsByNameDesc :many
-SELECT id, name, entries, program_id, created_at, updated_at
+SELECT id, name, entries, program_id, created_at, updated_at, organization_id
 FROM weekly_lookups
+WHERE (?1 IS NULL OR organization_id IS NULL
+    OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1))
 ORDER BY name DESC
-LIMIT ? OFFSET ?
+LIMIT ?3 OFFSET ?2
 `
 
 type ListWeeklyLookupsByNameDescParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	ViewerID interface{} `json:"viewer_id"`
+	Offset   int64       `json:"offset"`
+	Limit    int64       `json:"limit"`
 }
 
 func (q *Queries) ListWeeklyLookupsByNameDesc(ctx context.Context, arg ListWeeklyLookupsByNameDescParams) ([]WeeklyLookup, error) {
-	rows, err := q.db.QueryContext(ctx, listWeeklyLookupsByNameDesc, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listWeeklyLookupsByNameDesc, arg.ViewerID, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -230,6 +250,7 @@ func (q *Queries) ListWeeklyLookupsByNameDesc(ctx context.Context, arg ListWeekl
 			&i.ProgramID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
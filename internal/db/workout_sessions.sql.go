@@ -44,34 +44,30 @@ func (q *Queries) CompleteWorkoutSession(ctx context.Context, arg CompleteWorkou
 	return err
 }
 
-const countWorkoutSessionsByUserID = `-- name: CountWorkoutSessionsByUserID :one
+const countWorkoutSessionsByUser = `-- name: CountWorkoutSessionsByUser :one
 SELECT COUNT(*) as count
 FROM workout_sessions ws
 JOIN user_program_states ups ON ws.user_program_state_id = ups.id
-WHERE ups.user_id = ?
+WHERE ups.user_id = ?1
+  AND (?2 IS NULL OR ws.status = ?2)
+  AND (?3 IS NULL OR ws.started_at >= ?3)
+  AND (?4 IS NULL OR ws.started_at < ?4)
 `
 
-func (q *Queries) CountWorkoutSessionsByUserID(ctx context.Context, userID string) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countWorkoutSessionsByUserID, userID)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
-const countWorkoutSessionsByUserIDWithStatus = `-- name: CountWorkoutSessionsByUserIDWithStatus :one
-SELECT COUNT(*) as count
-FROM workout_sessions ws
-JOIN user_program_states ups ON ws.user_program_state_id = ups.id
-WHERE ups.user_id = ? AND ws.status = ?
-`
-
-type CountWorkoutSessionsByUserIDWithStatusParams struct {
-	UserID string `json:"user_id"`
-	Status string `json:"status"`
+type CountWorkoutSessionsByUserParams struct {
+	UserID     string      `json:"user_id"`
+	Status     interface{} `json:"status"`
+	FromTime   interface{} `json:"from_time"`
+	BeforeTime interface{} `json:"before_time"`
 }
 
-func (q *Queries) CountWorkoutSessionsByUserIDWithStatus(ctx context.Context, arg CountWorkoutSessionsByUserIDWithStatusParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countWorkoutSessionsByUserIDWithStatus, arg.UserID, arg.Status)
+func (q *Queries) CountWorkoutSessionsByUser(ctx context.Context, arg CountWorkoutSessionsByUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countWorkoutSessionsByUser,
+		arg.UserID,
+		arg.Status,
+		arg.FromTime,
+		arg.BeforeTime,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -228,40 +224,65 @@ func (q *Queries) GetWorkoutSessionsByState(ctx context.Context, userProgramStat
 	return items, nil
 }
 
-const getWorkoutSessionsByUserID = `-- name: GetWorkoutSessionsByUserID :many
-SELECT ws.id, ws.user_program_state_id, ws.week_number, ws.day_index, ws.status, ws.started_at, ws.finished_at, ws.created_at, ws.updated_at
+const listWorkoutSessionsByUserByStartedAtAsc = `-- name: ListWorkoutSessionsByUserByStartedAtAsc :many
+SELECT ws.id, ws.user_program_state_id, ws.week_number, ws.day_index, ws.status, ws.started_at, ws.finished_at, ws.created_at, ws.updated_at, ws.rowid AS seq
 FROM workout_sessions ws
 JOIN user_program_states ups ON ws.user_program_state_id = ups.id
-WHERE ups.user_id = ?
-ORDER BY ws.created_at DESC
-LIMIT ? OFFSET ?
+WHERE ups.user_id = ?1
+  AND (?2 IS NULL OR ws.status = ?2)
+  AND (?3 IS NULL OR ws.started_at >= ?3)
+  AND (?4 IS NULL OR ws.started_at < ?4)
+  AND ws.started_at >= ?5
+  AND (ws.started_at > ?5 OR ws.rowid > ?6)
+ORDER BY ws.started_at ASC, ws.rowid ASC
+LIMIT ?8 OFFSET ?7
 `
 
-type GetWorkoutSessionsByUserIDParams struct {
-	UserID string `json:"user_id"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
+type ListWorkoutSessionsByUserByStartedAtAscParams struct {
+	UserID     string      `json:"user_id"`
+	Status     interface{} `json:"status"`
+	FromTime   interface{} `json:"from_time"`
+	BeforeTime interface{} `json:"before_time"`
+	AfterValue string      `json:"after_value"`
+	AfterSeq   int64       `json:"after_seq"`
+	Offset     int64       `json:"offset"`
+	Limit      int64       `json:"limit"`
+}
+
+type ListWorkoutSessionsByUserByStartedAtAscRow struct {
+	WorkoutSession WorkoutSession `json:"workout_session"`
+	Seq            int64          `json:"seq"`
 }
 
-func (q *Queries) GetWorkoutSessionsByUserID(ctx context.Context, arg GetWorkoutSessionsByUserIDParams) ([]WorkoutSession, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkoutSessionsByUserID, arg.UserID, arg.Limit, arg.Offset)
+func (q *Queries) ListWorkoutSessionsByUserByStartedAtAsc(ctx context.Context, arg ListWorkoutSessionsByUserByStartedAtAscParams) ([]ListWorkoutSessionsByUserByStartedAtAscRow, error) {
+	rows, err := q.db.QueryContext(ctx, listWorkoutSessionsByUserByStartedAtAsc,
+		arg.UserID,
+		arg.Status,
+		arg.FromTime,
+		arg.BeforeTime,
+		arg.AfterValue,
+		arg.AfterSeq,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []WorkoutSession{}
+	items := []ListWorkoutSessionsByUserByStartedAtAscRow{}
 	for rows.Next() {
-		var i WorkoutSession
+		var i ListWorkoutSessionsByUserByStartedAtAscRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.UserProgramStateID,
-			&i.WeekNumber,
-			&i.DayIndex,
-			&i.Status,
-			&i.StartedAt,
-			&i.FinishedAt,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.WorkoutSession.ID,
+			&i.WorkoutSession.UserProgramStateID,
+			&i.WorkoutSession.WeekNumber,
+			&i.WorkoutSession.DayIndex,
+			&i.WorkoutSession.Status,
+			&i.WorkoutSession.StartedAt,
+			&i.WorkoutSession.FinishedAt,
+			&i.WorkoutSession.CreatedAt,
+			&i.WorkoutSession.UpdatedAt,
+			&i.Seq,
 		); err != nil {
 			return nil, err
 		}
@@ -276,46 +297,65 @@ func (q *Queries) GetWorkoutSessionsByUserID(ctx context.Context, arg GetWorkout
 	return items, nil
 }
 
-const getWorkoutSessionsByUserIDWithStatus = `-- name: GetWorkoutSessionsByUserIDWithStatus :many
-SELECT ws.id, ws.user_program_state_id, ws.week_number, ws.day_index, ws.status, ws.started_at, ws.finished_at, ws.created_at, ws.updated_at
+const listWorkoutSessionsByUserByStartedAtDesc = `-- name: ListWorkoutSessionsByUserByStartedAtDesc :many
+SELECT ws.id, ws.user_program_state_id, ws.week_number, ws.day_index, ws.status, ws.started_at, ws.finished_at, ws.created_at, ws.updated_at, ws.rowid AS seq
 FROM workout_sessions ws
 JOIN user_program_states ups ON ws.user_program_state_id = ups.id
-WHERE ups.user_id = ? AND ws.status = ?
-ORDER BY ws.created_at DESC
-LIMIT ? OFFSET ?
+WHERE ups.user_id = ?1
+  AND (?2 IS NULL OR ws.status = ?2)
+  AND (?3 IS NULL OR ws.started_at >= ?3)
+  AND (?4 IS NULL OR ws.started_at < ?4)
+  AND ws.started_at <= ?5
+  AND (ws.started_at < ?5 OR ws.rowid < ?6)
+ORDER BY ws.started_at DESC, ws.rowid DESC
+LIMIT ?8 OFFSET ?7
 `
 
-type GetWorkoutSessionsByUserIDWithStatusParams struct {
-	UserID string `json:"user_id"`
-	Status string `json:"status"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
+type ListWorkoutSessionsByUserByStartedAtDescParams struct {
+	UserID     string      `json:"user_id"`
+	Status     interface{} `json:"status"`
+	FromTime   interface{} `json:"from_time"`
+	BeforeTime interface{} `json:"before_time"`
+	AfterValue string      `json:"after_value"`
+	AfterSeq   int64       `json:"after_seq"`
+	Offset     int64       `json:"offset"`
+	Limit      int64       `json:"limit"`
 }
 
-func (q *Queries) GetWorkoutSessionsByUserIDWithStatus(ctx context.Context, arg GetWorkoutSessionsByUserIDWithStatusParams) ([]WorkoutSession, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkoutSessionsByUserIDWithStatus,
+type ListWorkoutSessionsByUserByStartedAtDescRow struct {
+	WorkoutSession WorkoutSession `json:"workout_session"`
+	Seq            int64          `json:"seq"`
+}
+
+func (q *Queries) ListWorkoutSessionsByUserByStartedAtDesc(ctx context.Context, arg ListWorkoutSessionsByUserByStartedAtDescParams) ([]ListWorkoutSessionsByUserByStartedAtDescRow, error) {
+	rows, err := q.db.QueryContext(ctx, listWorkoutSessionsByUserByStartedAtDesc,
 		arg.UserID,
 		arg.Status,
-		arg.Limit,
+		arg.FromTime,
+		arg.BeforeTime,
+		arg.AfterValue,
+		arg.AfterSeq,
 		arg.Offset,
+		arg.Limit,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []WorkoutSession{}
+	items := []ListWorkoutSessionsByUserByStartedAtDescRow{}
 	for rows.Next() {
-		var i WorkoutSession
+		var i ListWorkoutSessionsByUserByStartedAtDescRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.UserProgramStateID,
-			&i.WeekNumber,
-			&i.DayIndex,
-			&i.Status,
-			&i.StartedAt,
-			&i.FinishedAt,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.WorkoutSession.ID,
+			&i.WorkoutSession.UserProgramStateID,
+			&i.WorkoutSession.WeekNumber,
+			&i.WorkoutSession.DayIndex,
+			&i.WorkoutSession.Status,
+			&i.WorkoutSession.StartedAt,
+			&i.WorkoutSession.FinishedAt,
+			&i.WorkoutSession.CreatedAt,
+			&i.WorkoutSession.UpdatedAt,
+			&i.Seq,
 		); err != nil {
 			return nil, err
 		}
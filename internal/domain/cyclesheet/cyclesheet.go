@@ -0,0 +1,294 @@
+// Package cyclesheet renders a lifter's training cycle as printable sheets.
+// A sheet holds every generated workout of the cycle, week by week, with resolved
+// weights. This package contains pure rendering logic with no database dependencies.
+package cyclesheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/waynenilsen/power-pro-v3/internal/domain/workout"
+)
+
+// Supported sheet formats.
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+)
+
+// Validation errors
+var (
+	ErrInvalidFormat = errors.New("format must be html, markdown or csv")
+	ErrInvalidWeeks  = errors.New("weeks must be a comma-separated list of week numbers or ranges such as 1-3")
+)
+
+// Sheet is a lifter's cycle with every workout resolved.
+type Sheet struct {
+	ProgramName    string
+	CycleIteration int
+	// WeightUnit is the unit of every weight on the sheet.
+	WeightUnit string
+	// GeneratedOn is the date the weights were resolved (YYYY-MM-DD).
+	GeneratedOn string
+	Weeks       []Week
+}
+
+// Week is one week of the cycle.
+type Week struct {
+	Number int
+	Days   []Day
+}
+
+// Day is one training day. Days without prescriptions have no exercises.
+type Day struct {
+	Name      string
+	Slug      string
+	Exercises []workout.ExerciseInfo
+}
+
+// SetGroup is a run of consecutive sets with the same weight, reps and kind, printed
+// as one line such as "3 × 5 @ 225 lb".
+type SetGroup struct {
+	Sets      int
+	Weight    float64
+	Reps      int
+	IsWorkSet bool
+	IsAMRAP   bool
+}
+
+// GroupSets collapses consecutive identical sets into groups.
+func GroupSets(sets []workout.SetInfo) []SetGroup {
+	var groups []SetGroup
+	for _, s := range sets {
+		if n := len(groups); n > 0 {
+			last := &groups[n-1]
+			if last.Weight == s.Weight && last.Reps == s.TargetReps && last.IsWorkSet == s.IsWorkSet && last.IsAMRAP == s.IsAMRAP {
+				last.Sets++
+				continue
+			}
+		}
+		groups = append(groups, SetGroup{Sets: 1, Weight: s.Weight, Reps: s.TargetReps, IsWorkSet: s.IsWorkSet, IsAMRAP: s.IsAMRAP})
+	}
+	return groups
+}
+
+// Format describes the group for a sheet. AMRAP reps are marked with "+" and warm-up
+// sets are labelled.
+func (g SetGroup) Format(unit string) string {
+	reps := strconv.Itoa(g.Reps)
+	if g.IsAMRAP {
+		reps += "+"
+	}
+	line := fmt.Sprintf("%d × %s @ %s %s", g.Sets, reps, formatWeight(g.Weight), unit)
+	if !g.IsWorkSet {
+		line += " (warm-up)"
+	}
+	return line
+}
+
+// ParseFormat validates a requested format. Empty means HTML.
+func ParseFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatHTML:
+		return FormatHTML, nil
+	case FormatMarkdown, "md":
+		return FormatMarkdown, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	}
+	return "", ErrInvalidFormat
+}
+
+// ParseWeeks reads a week selection such as "1,3" or "2-4" into sorted, distinct week
+// numbers. Empty means every week and returns nil.
+func ParseWeeks(s string) ([]int, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	seen := map[int]bool{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil || first < 1 {
+			return nil, ErrInvalidWeeks
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(strings.TrimSpace(to)); err != nil || last < first {
+				return nil, ErrInvalidWeeks
+			}
+		}
+		for w := first; w <= last; w++ {
+			seen[w] = true
+		}
+	}
+	weeks := make([]int, 0, len(seen))
+	for w := range seen {
+		weeks = append(weeks, w)
+	}
+	sort.Ints(weeks)
+	return weeks, nil
+}
+
+// ContentType returns the MIME type of a format.
+func ContentType(format string) string {
+	switch format {
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	}
+	return "text/html; charset=utf-8"
+}
+
+// Render writes the sheet in the given format.
+func Render(w io.Writer, format string, sheet *Sheet) error {
+	switch format {
+	case FormatHTML:
+		return renderHTML(w, sheet)
+	case FormatMarkdown:
+		return renderMarkdown(w, sheet)
+	case FormatCSV:
+		return renderCSV(w, sheet)
+	}
+	return ErrInvalidFormat
+}
+
+// Title is the sheet's heading, such as "Wendler 5/3/1 — Cycle 2".
+func (s *Sheet) Title() string {
+	return fmt.Sprintf("%s — Cycle %d", s.ProgramName, s.CycleIteration)
+}
+
+func renderMarkdown(w io.Writer, s *Sheet) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", s.Title())
+	fmt.Fprintf(&b, "Weights in %s, from maxes as of %s.\n", s.WeightUnit, s.GeneratedOn)
+	for _, week := range s.Weeks {
+		fmt.Fprintf(&b, "\n## Week %d\n", week.Number)
+		for _, day := range week.Days {
+			fmt.Fprintf(&b, "\n### %s\n\n", markdownText(day.Name))
+			if len(day.Exercises) == 0 {
+				b.WriteString("No exercises.\n")
+				continue
+			}
+			b.WriteString("| Exercise | Sets | Rest | Notes |\n|---|---|---|---|\n")
+			for _, e := range day.Exercises {
+				var sets []string
+				for _, g := range GroupSets(e.Sets) {
+					sets = append(sets, g.Format(s.WeightUnit))
+				}
+				fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+					markdownText(e.Lift.Name), strings.Join(sets, "<br>"), FormatRest(e.RestSeconds), markdownText(e.Notes))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownText keeps text on one table line and escapes characters with meaning in
+// Markdown tables.
+func markdownText(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "<", "&lt;").Replace(s)
+}
+
+func renderCSV(w io.Writer, s *Sheet) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"week", "day", "day_slug", "exercise_order", "lift", "lift_slug", "set_number",
+		"weight", "unit", "target_reps", "is_amrap", "is_work_set", "rest_seconds", "notes"}); err != nil {
+		return err
+	}
+	for _, week := range s.Weeks {
+		for _, day := range week.Days {
+			for i, e := range day.Exercises {
+				rest := ""
+				if e.RestSeconds != nil {
+					rest = strconv.Itoa(*e.RestSeconds)
+				}
+				for _, set := range e.Sets {
+					if err := writer.Write([]string{strconv.Itoa(week.Number), day.Name, day.Slug, strconv.Itoa(i + 1),
+						e.Lift.Name, e.Lift.Slug, strconv.Itoa(set.SetNumber), formatWeight(set.Weight), s.WeightUnit,
+						strconv.Itoa(set.TargetReps), strconv.FormatBool(set.IsAMRAP), strconv.FormatBool(set.IsWorkSet), rest, e.Notes}); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+var sheetTemplate = template.Must(template.New("sheet").Funcs(template.FuncMap{
+	"groups": GroupSets,
+	"rest":   FormatRest,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; font-size: 11pt; margin: 1.5cm; }
+h1 { font-size: 16pt; margin-bottom: 0; }
+h2 { font-size: 14pt; border-bottom: 2px solid #000; margin-top: 1.5em; }
+h3 { font-size: 12pt; margin-bottom: 0.3em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1em; page-break-inside: avoid; }
+th, td { border: 1px solid #555; padding: 4px 6px; text-align: left; vertical-align: top; }
+td.done { width: 20%; }
+.week { page-break-after: always; }
+.week:last-child { page-break-after: auto; }
+.meta { color: #444; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">Weights in {{.WeightUnit}}, from maxes as of {{.GeneratedOn}}. Reps marked + are as many as possible.</p>
+{{- $unit := .WeightUnit}}
+{{- range .Weeks}}
+<section class="week">
+<h2>Week {{.Number}}</h2>
+{{- range .Days}}
+<h3>{{.Name}}</h3>
+{{- if .Exercises}}
+<table>
+<thead><tr><th>Exercise</th><th>Sets</th><th>Rest</th><th>Notes</th><th>Done</th></tr></thead>
+<tbody>
+{{- range .Exercises}}
+<tr><td>{{.Lift.Name}}</td><td>{{range $i, $g := groups .Sets}}{{if $i}}<br>{{end}}{{$g.Format $unit}}{{end}}</td><td>{{rest .RestSeconds}}</td><td>{{.Notes}}</td><td class="done"></td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p>No exercises.</p>
+{{- end}}
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+func renderHTML(w io.Writer, s *Sheet) error {
+	return sheetTemplate.Execute(w, s)
+}
+
+// FormatRest formats a rest period as minutes and seconds, such as "3:00". Nil is empty.
+func FormatRest(seconds *int) string {
+	if seconds == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%02d", *seconds/60, *seconds%60)
+}
+
+func formatWeight(weight float64) string {
+	return strconv.FormatFloat(weight, 'f', -1, 64)
+}
@@ -0,0 +1,195 @@
+package cyclesheet
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/waynenilsen/power-pro-v3/internal/domain/workout"
+)
+
+func testSheet() *Sheet {
+	rest := 180
+	return &Sheet{
+		ProgramName:    "Wendler 5/3/1",
+		CycleIteration: 2,
+		WeightUnit:     "lb",
+		GeneratedOn:    "2024-01-15",
+		Weeks: []Week{{
+			Number: 1,
+			Days: []Day{
+				{
+					Name: "Squat Day",
+					Slug: "squat-day",
+					Exercises: []workout.ExerciseInfo{{
+						Lift: workout.LiftInfo{Name: "Squat", Slug: "squat"},
+						Sets: []workout.SetInfo{
+							{SetNumber: 1, Weight: 135, TargetReps: 5},
+							{SetNumber: 2, Weight: 195, TargetReps: 5, IsWorkSet: true},
+							{SetNumber: 3, Weight: 225, TargetReps: 5, IsWorkSet: true, IsAMRAP: true},
+						},
+						Notes:       "Brace | breathe",
+						RestSeconds: &rest,
+					}},
+				},
+				{Name: "Rest Day", Slug: "rest-day"},
+			},
+		}},
+	}
+}
+
+func TestGroupSets(t *testing.T) {
+	sets := []workout.SetInfo{
+		{SetNumber: 1, Weight: 135, TargetReps: 5},
+		{SetNumber: 2, Weight: 225, TargetReps: 5, IsWorkSet: true},
+		{SetNumber: 3, Weight: 225, TargetReps: 5, IsWorkSet: true},
+		{SetNumber: 4, Weight: 225, TargetReps: 5, IsWorkSet: true, IsAMRAP: true},
+	}
+	got := GroupSets(sets)
+	want := []SetGroup{
+		{Sets: 1, Weight: 135, Reps: 5},
+		{Sets: 2, Weight: 225, Reps: 5, IsWorkSet: true},
+		{Sets: 1, Weight: 225, Reps: 5, IsWorkSet: true, IsAMRAP: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("GroupSets() = %+v, want %+v", got, want)
+	}
+
+	formatted := []string{"1 × 5 @ 135 lb (warm-up)", "2 × 5 @ 225 lb", "1 × 5+ @ 225 lb"}
+	for i, g := range got {
+		if s := g.Format("lb"); s != formatted[i] {
+			t.Errorf("group %d: Format() = %q, want %q", i, s, formatted[i])
+		}
+	}
+	if s := (SetGroup{Sets: 3, Weight: 102.5, Reps: 3, IsWorkSet: true}).Format("kg"); s != "3 × 3 @ 102.5 kg" {
+		t.Errorf("Format() = %q", s)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", FormatHTML, false},
+		{"HTML", FormatHTML, false},
+		{"markdown", FormatMarkdown, false},
+		{"md", FormatMarkdown, false},
+		{"csv", FormatCSV, false},
+		{"pdf", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("ParseFormat(%q) error = %v, want ErrInvalidFormat", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseWeeks(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []int
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"2", []int{2}, false},
+		{"3, 1", []int{1, 3}, false},
+		{"2-4,3", []int{2, 3, 4}, false},
+		{"0", nil, true},
+		{"4-2", nil, true},
+		{"a", nil, true},
+		{"1,", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseWeeks(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidWeeks) {
+				t.Errorf("ParseWeeks(%q) error = %v, want ErrInvalidWeeks", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseWeeks(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Render(&buf, FormatMarkdown, testSheet()); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		out := buf.String()
+		for _, want := range []string{
+			"# Wendler 5/3/1 — Cycle 2",
+			"## Week 1",
+			"| Squat | 1 × 5 @ 135 lb (warm-up)<br>1 × 5 @ 195 lb<br>1 × 5+ @ 225 lb | 3:00 | Brace \\| breathe |",
+			"### Rest Day\n\nNo exercises.",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("expected markdown to contain %q, got:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Render(&buf, FormatCSV, testSheet()); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 4 {
+			t.Fatalf("expected header and 3 rows, got %d lines", len(lines))
+		}
+		if want := "1,Squat Day,squat-day,1,Squat,squat,3,225,lb,5,true,true,180,Brace | breathe"; lines[3] != want {
+			t.Errorf("row = %q, want %q", lines[3], want)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Render(&buf, FormatHTML, testSheet()); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		out := buf.String()
+		for _, want := range []string{
+			"<title>Wendler 5/3/1 — Cycle 2</title>",
+			`<section class="week">`,
+			"1 × 5 @ 195 lb",
+			"<p>No exercises.</p>",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("expected HTML to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("escapes HTML", func(t *testing.T) {
+		sheet := testSheet()
+		sheet.Weeks[0].Days[0].Exercises[0].Notes = "<script>"
+		var buf bytes.Buffer
+		if err := Render(&buf, FormatHTML, sheet); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		if strings.Contains(buf.String(), "<script>") {
+			t.Error("expected notes to be escaped")
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		if err := Render(&bytes.Buffer{}, "pdf", testSheet()); !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("Render() error = %v, want ErrInvalidFormat", err)
+		}
+	})
+}
@@ -48,12 +48,13 @@ func IsValidIntensityLevel(level string) bool {
 
 // DailyLookup represents a daily lookup domain entity with all business rules.
 type DailyLookup struct {
-	ID        string
-	Name      string
-	Entries   []DailyLookupEntry
-	ProgramID *string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID             string
+	Name           string
+	Entries        []DailyLookupEntry
+	ProgramID      *string
+	OrganizationID *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 // DailyLookupEntry represents an entry in a daily lookup table.
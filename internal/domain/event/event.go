@@ -30,6 +30,8 @@ const (
 	EventWorkoutAbandoned EventType = "WORKOUT_ABANDONED"
 	// EventSetLogged fires when a user logs a set.
 	EventSetLogged EventType = "SET_LOGGED"
+	// EventPRAchieved fires when a logged set achieves a personal record.
+	EventPRAchieved EventType = "PR_ACHIEVED"
 )
 
 // ValidEventTypes contains all valid event types for validation.
@@ -45,11 +47,15 @@ var ValidEventTypes = map[EventType]bool{
 	EventWorkoutCompleted:     true,
 	EventWorkoutAbandoned:     true,
 	EventSetLogged:            true,
+	EventPRAchieved:           true,
 }
 
 // StateEvent represents an event that occurred during a state transition.
 // Events carry contextual information about what changed.
 type StateEvent struct {
+	// ID uniquely identifies the event once it has been stored.
+	// It is empty for events that have not been written to the outbox.
+	ID string
 	// Type identifies the kind of event.
 	Type EventType
 	// UserID is the UUID of the user who triggered the event.
@@ -97,25 +103,35 @@ func (e StateEvent) GetString(key string) string {
 }
 
 // GetInt retrieves an int value from the payload.
-// Returns 0 if the key doesn't exist or isn't an int.
+// Whole float64 values are accepted, since payloads decoded from JSON hold numbers as float64.
+// Returns 0 if the key doesn't exist or isn't an integer.
 func (e StateEvent) GetInt(key string) int {
 	if e.Payload == nil {
 		return 0
 	}
-	if v, ok := e.Payload[key].(int); ok {
+	switch v := e.Payload[key].(type) {
+	case int:
 		return v
+	case float64:
+		if v == float64(int(v)) {
+			return int(v)
+		}
 	}
 	return 0
 }
 
 // GetFloat64 retrieves a float64 value from the payload.
-// Returns 0.0 if the key doesn't exist or isn't a float64.
+// int values are accepted and converted.
+// Returns 0.0 if the key doesn't exist or isn't a number.
 func (e StateEvent) GetFloat64(key string) float64 {
 	if e.Payload == nil {
 		return 0.0
 	}
-	if v, ok := e.Payload[key].(float64); ok {
+	switch v := e.Payload[key].(type) {
+	case float64:
 		return v
+	case int:
+		return float64(v)
 	}
 	return 0.0
 }
@@ -158,6 +174,8 @@ const (
 	PayloadLoggedSetID = "loggedSetId"
 	// PayloadLiftID is the key for lift ID.
 	PayloadLiftID = "liftId"
+	// PayloadPrescriptionID is the key for prescription ID.
+	PayloadPrescriptionID = "prescriptionId"
 	// PayloadRepsPerformed is the key for reps performed.
 	PayloadRepsPerformed = "repsPerformed"
 	// PayloadTargetReps is the key for target reps.
@@ -174,4 +192,14 @@ const (
 	PayloadCyclesCompleted = "cyclesCompleted"
 	// PayloadWeeksCompleted is the key for total weeks completed.
 	PayloadWeeksCompleted = "weeksCompleted"
+	// PayloadRecordID is the key for personal record ID.
+	PayloadRecordID = "recordId"
+	// PayloadRecordType is the key for personal record type.
+	PayloadRecordType = "recordType"
+	// PayloadRepCount is the key for the rep count of a rep-max record.
+	PayloadRepCount = "repCount"
+	// PayloadRecordValue is the key for the personal record value.
+	PayloadRecordValue = "recordValue"
+	// PayloadPreviousValue is the key for the record value that was beaten.
+	PayloadPreviousValue = "previousValue"
 )
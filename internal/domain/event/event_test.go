@@ -73,6 +73,17 @@ func TestStateEvent_GetInt(t *testing.T) {
 	if got := event.GetInt("strKey"); got != 0 {
 		t.Errorf("expected 0 for string value, got %d", got)
 	}
+
+	// Payloads decoded from JSON hold numbers as float64
+	decoded := NewStateEvent(EventSetLogged, "user", "program").
+		WithPayload("wholeKey", float64(5)).
+		WithPayload("fractionKey", 5.5)
+	if got := decoded.GetInt("wholeKey"); got != 5 {
+		t.Errorf("expected 5 for whole float64 value, got %d", got)
+	}
+	if got := decoded.GetInt("fractionKey"); got != 0 {
+		t.Errorf("expected 0 for fractional float64 value, got %d", got)
+	}
 	if got := event.GetInt("missing"); got != 0 {
 		t.Errorf("expected 0 for missing key, got %d", got)
 	}
@@ -95,6 +106,9 @@ func TestStateEvent_GetFloat64(t *testing.T) {
 	if got := event.GetFloat64("strKey"); got != 0.0 {
 		t.Errorf("expected 0.0 for string value, got %f", got)
 	}
+	if got := event.WithPayload("intKey", 3).GetFloat64("intKey"); got != 3.0 {
+		t.Errorf("expected 3.0 for int value, got %f", got)
+	}
 	if got := event.GetFloat64("missing"); got != 0.0 {
 		t.Errorf("expected 0.0 for missing key, got %f", got)
 	}
@@ -145,6 +159,7 @@ func TestValidEventTypes(t *testing.T) {
 		EventWorkoutCompleted,
 		EventWorkoutAbandoned,
 		EventSetLogged,
+		EventPRAchieved,
 	}
 
 	for _, et := range expectedTypes {
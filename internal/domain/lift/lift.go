@@ -21,6 +21,10 @@ var (
 	ErrNameTooLong       = errors.New("lift name must be 100 characters or less")
 	ErrCircularReference = errors.New("circular reference detected: lift cannot be its own ancestor")
 	ErrSelfReference     = errors.New("lift cannot reference itself as parent")
+	// ErrOrganizationCompetitionLift is returned for an organization lift marked as a
+	// competition lift. Competition lifts feed every user's strength scores and
+	// competition totals, so only global lifts can be competition lifts.
+	ErrOrganizationCompetitionLift = errors.New("organization lifts cannot be competition lifts")
 	// Slug errors delegated to shared validation package
 	ErrSlugEmpty   = validation.ErrSlugEmpty
 	ErrSlugInvalid = validation.ErrSlugInvalid
@@ -34,6 +38,7 @@ type Lift struct {
 	Slug              string
 	IsCompetitionLift bool
 	ParentLiftID      *string
+	OrganizationID    *string
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
@@ -138,6 +143,7 @@ type CreateLiftInput struct {
 	Slug              string  // Optional: auto-generated from Name if empty
 	IsCompetitionLift bool    // Defaults to false
 	ParentLiftID      *string // Optional
+	OrganizationID    *string // Optional: nil for a global lift
 }
 
 // CreateLift validates input and creates a new Lift domain entity.
@@ -167,6 +173,10 @@ func CreateLift(input CreateLiftInput, id string, repo LiftRepository) (*Lift, *
 		result.AddError(err)
 	}
 
+	if input.IsCompetitionLift && input.OrganizationID != nil {
+		result.AddError(ErrOrganizationCompetitionLift)
+	}
+
 	if !result.Valid {
 		return nil, result
 	}
@@ -178,6 +188,7 @@ func CreateLift(input CreateLiftInput, id string, repo LiftRepository) (*Lift, *
 		Slug:              slug,
 		IsCompetitionLift: input.IsCompetitionLift,
 		ParentLiftID:      input.ParentLiftID,
+		OrganizationID:    input.OrganizationID,
 		CreatedAt:         now,
 		UpdatedAt:         now,
 	}, result
@@ -217,7 +228,11 @@ func UpdateLift(lift *Lift, input UpdateLiftInput, repo LiftRepository) *Validat
 
 	// Update competition lift flag if provided
 	if input.IsCompetitionLift != nil {
-		lift.IsCompetitionLift = *input.IsCompetitionLift
+		if *input.IsCompetitionLift && lift.OrganizationID != nil {
+			result.AddError(ErrOrganizationCompetitionLift)
+		} else {
+			lift.IsCompetitionLift = *input.IsCompetitionLift
+		}
 	}
 
 	// Handle parent lift update
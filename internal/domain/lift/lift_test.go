@@ -313,6 +313,32 @@ func TestCreateLift_ValidInput(t *testing.T) {
 	}
 }
 
+func TestCreateLift_OrganizationCompetitionLift(t *testing.T) {
+	repo := newMockRepository()
+	orgID := "org-id"
+
+	lift, result := CreateLift(CreateLiftInput{
+		Name:              "Box Squat",
+		IsCompetitionLift: true,
+		OrganizationID:    &orgID,
+	}, "test-id", repo)
+
+	if result.Valid || lift != nil {
+		t.Error("CreateLift should reject an organization competition lift")
+	}
+	if len(result.Errors) != 1 || result.Errors[0] != ErrOrganizationCompetitionLift {
+		t.Errorf("expected ErrOrganizationCompetitionLift, got %v", result.Errors)
+	}
+
+	lift, result = CreateLift(CreateLiftInput{Name: "Box Squat", OrganizationID: &orgID}, "test-id", repo)
+	if !result.Valid {
+		t.Fatalf("CreateLift returned invalid result: %v", result.Errors)
+	}
+	if lift.OrganizationID == nil || *lift.OrganizationID != orgID {
+		t.Errorf("lift.OrganizationID = %v, want %q", lift.OrganizationID, orgID)
+	}
+}
+
 func TestCreateLift_WithProvidedSlug(t *testing.T) {
 	repo := newMockRepository()
 
@@ -503,6 +529,22 @@ func TestUpdateLift_UpdateCompetitionLift(t *testing.T) {
 	}
 }
 
+func TestUpdateLift_OrganizationCompetitionLift(t *testing.T) {
+	repo := newMockRepository()
+	orgID := "org-id"
+	lift := &Lift{ID: "test-id", Name: "Box Squat", Slug: "box-squat", OrganizationID: &orgID}
+
+	isCompetition := true
+	result := UpdateLift(lift, UpdateLiftInput{IsCompetitionLift: &isCompetition}, repo)
+
+	if result.Valid {
+		t.Error("UpdateLift should reject marking an organization lift as a competition lift")
+	}
+	if lift.IsCompetitionLift {
+		t.Error("lift.IsCompetitionLift should be unchanged")
+	}
+}
+
 func TestUpdateLift_SetParent(t *testing.T) {
 	repo := newMockRepository()
 	squat := &Lift{ID: "squat-id", Name: "Squat", Slug: "squat"}
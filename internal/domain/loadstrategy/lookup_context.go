@@ -45,6 +45,11 @@ type LookupContext struct {
 	// Optional: if nil, RPE-based lookups are not available.
 	// Programs like RTS (Reactive Training Systems) use this for autoregulated training.
 	RPEChart *rpechart.RPEChart
+
+	// ReadinessModifier is the load modifier from the lifter's daily readiness check-in,
+	// stored as a percentage (e.g., 95 means 95%).
+	// Optional: if nil, no readiness modifications are applied.
+	ReadinessModifier *float64
 }
 
 // HasWeeklyLookup returns true if a weekly lookup is configured and the week number is valid.
@@ -86,6 +91,7 @@ func (c *LookupContext) GetDailyEntry() *dailylookup.DailyLookupEntry {
 // The modification order is:
 //  1. Weekly lookup (set-specific percentage or percentage modifier)
 //  2. Daily lookup (percentage modifier)
+//  3. Readiness check-in (percentage modifier)
 //
 // Two distinct modification modes exist for weekly lookups:
 //   - Set-specific percentages: Used when each set has a different prescribed percentage
@@ -100,6 +106,9 @@ func (c *LookupContext) GetDailyEntry() *dailylookup.DailyLookupEntry {
 //
 // When both weekly and daily modifiers apply, they stack multiplicatively.
 // Example: Base 85%, weekly modifier 95%, daily modifier 90% → 85 * 0.95 * 0.90 = 72.675%
+//
+// The readiness modifier is applied last, on top of whatever the program prescribes,
+// so a poor check-in scales the day's loads without changing its structure.
 func (c *LookupContext) ApplyModifiers(basePercentage float64) float64 {
 	if c == nil {
 		return basePercentage
@@ -137,6 +146,12 @@ func (c *LookupContext) ApplyModifiers(basePercentage float64) float64 {
 		}
 	}
 
+	// Readiness modifications are applied last (multiplicatively).
+	// This autoregulates the day's loads based on how the lifter reports feeling.
+	if c.ReadinessModifier != nil {
+		resultPercentage = resultPercentage * (*c.ReadinessModifier / 100)
+	}
+
 	return resultPercentage
 }
 
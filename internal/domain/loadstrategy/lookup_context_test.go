@@ -514,6 +514,32 @@ func TestLookupContext_ApplyModifiers_WeeklyModifierAndDaily(t *testing.T) {
 	}
 }
 
+func TestLookupContext_ApplyModifiers_Readiness(t *testing.T) {
+	readiness95 := 95.0
+
+	// Readiness only: no lookups configured
+	ctx := &LookupContext{ReadinessModifier: &readiness95}
+	if result := ctx.ApplyModifiers(80); math.Abs(result-76) > 0.0001 {
+		t.Errorf("expected 76, got %f", result)
+	}
+
+	// Readiness stacks on top of the daily modifier
+	ctx = &LookupContext{
+		DailyLookup: &dailylookup.DailyLookup{
+			Entries: []dailylookup.DailyLookupEntry{
+				{DayIdentifier: "light", PercentageModifier: 80},
+			},
+		},
+		DaySlug:           "light",
+		ReadinessModifier: &readiness95,
+	}
+
+	// Base 100 -> daily 80% -> 80 -> readiness 95% -> 76
+	if result := ctx.ApplyModifiers(100); math.Abs(result-76) > 0.0001 {
+		t.Errorf("expected 76, got %f", result)
+	}
+}
+
 func TestLookupContext_GetRepsForSet(t *testing.T) {
 	weeklyLookup := &weeklylookup.WeeklyLookup{
 		Entries: []weeklylookup.WeeklyLookupEntry{
@@ -204,6 +204,70 @@ func NewLoggedSet(input CreateLoggedSetInput, id string) (*LoggedSet, *Validatio
 	}, result
 }
 
+// UpdateLoggedSetInput contains the input data for correcting an existing logged set.
+// The lift, prescription, and session a set belongs to cannot be changed.
+type UpdateLoggedSetInput struct {
+	SetNumber     *int     // Optional: only update if provided
+	Weight        *float64 // Optional: only update if provided
+	TargetReps    *int     // Optional: only update if provided
+	RepsPerformed *int     // Optional: only update if provided
+	IsAMRAP       *bool    // Optional: only update if provided
+	RPE           *float64 // Optional: only update if provided
+}
+
+// UpdateLoggedSet validates input and updates an existing LoggedSet.
+// Returns a validation result with errors if validation fails.
+func UpdateLoggedSet(l *LoggedSet, input UpdateLoggedSetInput) *ValidationResult {
+	result := NewValidationResult()
+
+	if input.SetNumber != nil {
+		if err := ValidateSetNumber(*input.SetNumber); err != nil {
+			result.AddError(err)
+		} else {
+			l.SetNumber = *input.SetNumber
+		}
+	}
+
+	if input.Weight != nil {
+		if err := ValidateWeight(*input.Weight); err != nil {
+			result.AddError(err)
+		} else {
+			l.Weight = *input.Weight
+		}
+	}
+
+	if input.TargetReps != nil {
+		if err := ValidateTargetReps(*input.TargetReps); err != nil {
+			result.AddError(err)
+		} else {
+			l.TargetReps = *input.TargetReps
+		}
+	}
+
+	if input.RepsPerformed != nil {
+		if err := ValidateRepsPerformed(*input.RepsPerformed); err != nil {
+			result.AddError(err)
+		} else {
+			l.RepsPerformed = *input.RepsPerformed
+		}
+	}
+
+	if input.IsAMRAP != nil {
+		l.IsAMRAP = *input.IsAMRAP
+	}
+
+	if input.RPE != nil {
+		if err := ValidateRPE(input.RPE); err != nil {
+			result.AddError(err)
+		} else {
+			rpe := *input.RPE
+			l.RPE = &rpe
+		}
+	}
+
+	return result
+}
+
 // Validate performs full validation on an existing logged set.
 func (l *LoggedSet) Validate() *ValidationResult {
 	result := NewValidationResult()
@@ -252,6 +316,11 @@ func (l *LoggedSet) ExceededTarget() bool {
 	return l.RepsPerformed > l.TargetReps
 }
 
+// IsFailure returns true if reps performed fell short of target reps.
+func (l *LoggedSet) IsFailure() bool {
+	return l.RepsPerformed < l.TargetReps
+}
+
 // RepsDifference returns the difference between reps performed and target.
 // Positive means exceeded target, negative means fell short.
 func (l *LoggedSet) RepsDifference() int {
@@ -491,6 +491,44 @@ func TestLoggedSet_Validate_WithInvalidRPE(t *testing.T) {
 	}
 }
 
+// ==================== UpdateLoggedSet Tests ====================
+
+func TestUpdateLoggedSet_PartialUpdate(t *testing.T) {
+	ls := &LoggedSet{SetNumber: 1, Weight: 225, TargetReps: 5, RepsPerformed: 50, IsAMRAP: true}
+	reps := 5
+	notAMRAP := false
+
+	result := UpdateLoggedSet(ls, UpdateLoggedSetInput{RepsPerformed: &reps, IsAMRAP: &notAMRAP})
+
+	if !result.Valid {
+		t.Fatalf("UpdateLoggedSet returned invalid result: %v", result.Errors)
+	}
+	if ls.RepsPerformed != 5 || ls.IsAMRAP {
+		t.Errorf("expected reps 5 and not AMRAP, got reps %d AMRAP %v", ls.RepsPerformed, ls.IsAMRAP)
+	}
+	if ls.Weight != 225 || ls.TargetReps != 5 || ls.SetNumber != 1 {
+		t.Errorf("unexpected change to fields not in input: %+v", ls)
+	}
+}
+
+func TestUpdateLoggedSet_Invalid(t *testing.T) {
+	ls := &LoggedSet{SetNumber: 1, Weight: 225, TargetReps: 5, RepsPerformed: 5}
+	weight := -10.0
+	rpe := 11.0
+
+	result := UpdateLoggedSet(ls, UpdateLoggedSetInput{Weight: &weight, RPE: &rpe})
+
+	if result.Valid {
+		t.Fatal("UpdateLoggedSet returned valid result for invalid input")
+	}
+	if len(result.Errors) != 2 {
+		t.Errorf("len(result.Errors) = %d, want 2", len(result.Errors))
+	}
+	if ls.Weight != 225 || ls.RPE != nil {
+		t.Errorf("invalid fields should not be applied, got %+v", ls)
+	}
+}
+
 // ==================== Helper Method Tests ====================
 
 func TestLoggedSet_ExceededTarget(t *testing.T) {
@@ -545,6 +583,15 @@ func TestLoggedSet_RepsDifference(t *testing.T) {
 	}
 }
 
+func TestLoggedSet_IsFailure(t *testing.T) {
+	if !(&LoggedSet{TargetReps: 5, RepsPerformed: 4}).IsFailure() {
+		t.Error("IsFailure() = false for reps below target")
+	}
+	if (&LoggedSet{TargetReps: 5, RepsPerformed: 5}).IsFailure() {
+		t.Error("IsFailure() = true for reps meeting target")
+	}
+}
+
 // ==================== ValidationResult Tests ====================
 
 func TestValidationResult_AddError(t *testing.T) {
@@ -0,0 +1,92 @@
+// Package personalrecord provides domain logic for detecting personal records (PRs).
+// It covers rep-max PRs (1-12+ reps), estimated 1RM PRs, per-session volume PRs,
+// and competition total PRs built from the best estimated 1RM of each competition lift.
+package personalrecord
+
+import (
+	"math"
+
+	"github.com/waynenilsen/power-pro-v3/internal/domain/e1rm"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/rpechart"
+)
+
+// RecordType identifies the kind of personal record.
+type RecordType string
+
+const (
+	// TypeRepMax is the heaviest weight lifted for a given rep count.
+	TypeRepMax RecordType = "REP_MAX"
+	// TypeE1RM is the highest estimated 1RM for a lift.
+	TypeE1RM RecordType = "E1RM"
+	// TypeSessionVolume is the most volume (weight x reps) for a lift in one session.
+	TypeSessionVolume RecordType = "SESSION_VOLUME"
+	// TypeCompetitionTotal is the highest sum of estimated 1RMs across the competition lifts.
+	TypeCompetitionTotal RecordType = "COMPETITION_TOTAL"
+)
+
+// ValidRecordTypes contains all valid record types for validation.
+var ValidRecordTypes = map[RecordType]bool{
+	TypeRepMax:           true,
+	TypeE1RM:             true,
+	TypeSessionVolume:    true,
+	TypeCompetitionTotal: true,
+}
+
+// MaxRepBucket is the highest tracked rep count. Sets of more reps count toward the 12+ bucket.
+const MaxRepBucket = 12
+
+// RepBucket returns the rep-max bucket for a set, or 0 if the set has no reps.
+func RepBucket(reps int) int {
+	if reps <= 0 {
+		return 0
+	}
+	if reps > MaxRepBucket {
+		return MaxRepBucket
+	}
+	return reps
+}
+
+// Estimator estimates 1RMs from performed sets.
+type Estimator struct {
+	calculator *e1rm.Calculator
+}
+
+// NewEstimator creates a new Estimator using the default RPE chart.
+func NewEstimator() *Estimator {
+	return &Estimator{calculator: e1rm.NewCalculator(rpechart.NewDefaultRPEChart())}
+}
+
+// EstimateOneRM estimates the 1RM for a set of weight x reps.
+// Sets with an RPE between 7 and 10 use the RPE chart; other sets use the Epley formula.
+// A single rep without RPE is its own 1RM. Returns 0 when the set cannot be estimated
+// (no weight, or reps outside 1-12).
+func (e *Estimator) EstimateOneRM(weight float64, reps int, rpe *float64) float64 {
+	if weight <= 0 || reps < 1 || reps > MaxRepBucket {
+		return 0
+	}
+
+	if rpe != nil {
+		if estimate, err := e.calculator.Calculate(weight, reps, *rpe); err == nil {
+			return estimate
+		}
+	}
+
+	if reps == 1 {
+		return weight
+	}
+	return Round(weight * (1 + float64(reps)/30))
+}
+
+// IsImprovement reports whether value beats the previous record.
+// Any positive value beats a missing record.
+func IsImprovement(value float64, previous *float64) bool {
+	if value <= 0 {
+		return false
+	}
+	return previous == nil || value > *previous
+}
+
+// Round rounds a record value to one decimal place.
+func Round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
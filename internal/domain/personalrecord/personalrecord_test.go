@@ -0,0 +1,69 @@
+package personalrecord
+
+import "testing"
+
+func TestRepBucket(t *testing.T) {
+	tests := []struct {
+		reps     int
+		expected int
+	}{
+		{0, 0},
+		{1, 1},
+		{5, 5},
+		{12, 12},
+		{15, 12},
+	}
+
+	for _, tt := range tests {
+		if got := RepBucket(tt.reps); got != tt.expected {
+			t.Errorf("RepBucket(%d) = %d, expected %d", tt.reps, got, tt.expected)
+		}
+	}
+}
+
+func TestEstimator_EstimateOneRM(t *testing.T) {
+	est := NewEstimator()
+	rpe8 := 8.0
+	rpe6 := 6.0
+
+	tests := []struct {
+		name     string
+		weight   float64
+		reps     int
+		rpe      *float64
+		expected float64
+	}{
+		{"single without RPE", 300, 1, nil, 300},
+		{"epley without RPE", 300, 5, nil, 350},
+		{"RPE chart", 315, 5, &rpe8, 410},
+		{"RPE outside chart falls back to epley", 300, 5, &rpe6, 350},
+		{"too many reps", 135, 13, nil, 0},
+		{"zero reps", 300, 0, nil, 0},
+		{"no weight", 0, 5, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := est.EstimateOneRM(tt.weight, tt.reps, tt.rpe); got != tt.expected {
+				t.Errorf("expected %.1f, got %.1f", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsImprovement(t *testing.T) {
+	prev := 300.0
+
+	if !IsImprovement(100, nil) {
+		t.Error("expected any positive value to beat a missing record")
+	}
+	if IsImprovement(0, nil) {
+		t.Error("expected zero to never be a record")
+	}
+	if IsImprovement(300, &prev) {
+		t.Error("expected tying a record not to be an improvement")
+	}
+	if !IsImprovement(302.5, &prev) {
+		t.Error("expected a higher value to be an improvement")
+	}
+}
@@ -51,6 +51,7 @@ type Program struct {
 	DaysPerWeek     int
 	Focus           string
 	HasAmrap        bool
+	OrganizationID  *string
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
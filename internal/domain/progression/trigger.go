@@ -169,6 +169,45 @@ func (c FailureTriggerContext) Validate() error {
 	return nil
 }
 
+// SetTriggerContext contains context for AFTER_SET triggers.
+// This context is passed when a progression is evaluated against a single logged set,
+// such as an AMRAP set.
+type SetTriggerContext struct {
+	// LoggedSetID is the UUID of the logged set that triggered the progression.
+	LoggedSetID string `json:"loggedSetId"`
+	// SessionID is the UUID of the session the set was logged in.
+	SessionID string `json:"sessionId"`
+	// LiftID is the UUID of the lift for the set.
+	LiftID string `json:"liftId"`
+	// Weight is the weight used for the set.
+	Weight float64 `json:"weight"`
+	// TargetReps is the number of reps that were prescribed.
+	TargetReps int `json:"targetReps"`
+	// RepsPerformed is the number of reps actually achieved.
+	RepsPerformed int `json:"repsPerformed"`
+	// IsAMRAP indicates whether the set was an AMRAP set.
+	IsAMRAP bool `json:"isAmrap"`
+}
+
+// TriggerType implements TriggerContext.
+func (c SetTriggerContext) TriggerType() TriggerType {
+	return TriggerAfterSet
+}
+
+// Validate implements TriggerContext.
+func (c SetTriggerContext) Validate() error {
+	if c.LoggedSetID == "" {
+		return fmt.Errorf("%w: loggedSetId is required for set trigger context", ErrInvalidParams)
+	}
+	if c.LiftID == "" {
+		return fmt.Errorf("%w: liftId is required for set trigger context", ErrInvalidParams)
+	}
+	if c.RepsPerformed < 0 {
+		return fmt.Errorf("%w: repsPerformed must be non-negative", ErrInvalidParams)
+	}
+	return nil
+}
+
 // TriggerEventV2 contains all parameters for a trigger event.
 // This is the new trigger event structure with strongly-typed context.
 // The "V2" suffix distinguishes it from the existing flat TriggerEvent during migration.
@@ -282,6 +321,12 @@ func UnmarshalTriggerContext(triggerType TriggerType, data json.RawMessage) (Tri
 			return nil, fmt.Errorf("failed to unmarshal failure trigger context: %w", err)
 		}
 		return ctx, nil
+	case TriggerAfterSet:
+		var ctx SetTriggerContext
+		if err := json.Unmarshal(data, &ctx); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal set trigger context: %w", err)
+		}
+		return ctx, nil
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrUnknownTriggerType, triggerType)
 	}
@@ -340,6 +385,16 @@ func NewFailureTriggerEvent(userID string, ctx FailureTriggerContext) *TriggerEv
 	}
 }
 
+// NewSetTriggerEvent creates a new AFTER_SET trigger event.
+func NewSetTriggerEvent(userID string, ctx SetTriggerContext) *TriggerEventV2 {
+	return &TriggerEventV2{
+		Type:      TriggerAfterSet,
+		UserID:    userID,
+		Timestamp: time.Now(),
+		Context:   ctx,
+	}
+}
+
 // TriggerContextEnvelope is a wrapper for polymorphic TriggerContext serialization.
 // It includes the trigger type to enable proper deserialization.
 type TriggerContextEnvelope struct {
@@ -878,6 +878,12 @@ func TestUnmarshalTriggerContext(t *testing.T) {
 			json:        `{"loggedSetId":"set-1","liftId":"lift-1","targetReps":5,"repsPerformed":3,"repsDifference":-2,"consecutiveFailures":2,"weight":100.0,"progressionId":"prog-1"}`,
 			wantErr:     false,
 		},
+		{
+			name:        "set context",
+			triggerType: TriggerAfterSet,
+			json:        `{"loggedSetId":"set-1","sessionId":"s-1","liftId":"lift-1","weight":225.0,"targetReps":5,"repsPerformed":8,"isAmrap":true}`,
+			wantErr:     false,
+		},
 		{
 			name:        "unknown trigger type",
 			triggerType: "UNKNOWN",
@@ -1066,6 +1072,41 @@ func TestNewFailureTriggerEvent(t *testing.T) {
 	}
 }
 
+// TestNewSetTriggerEvent tests set trigger event factory.
+func TestNewSetTriggerEvent(t *testing.T) {
+	setCtx := SetTriggerContext{
+		LoggedSetID:   "set-456",
+		SessionID:     "session-1",
+		LiftID:        "lift-789",
+		Weight:        225.0,
+		TargetReps:    5,
+		RepsPerformed: 8,
+		IsAMRAP:       true,
+	}
+
+	event := NewSetTriggerEvent("user-123", setCtx)
+
+	if event.Type != TriggerAfterSet {
+		t.Errorf("expected type %s, got %s", TriggerAfterSet, event.Type)
+	}
+	if err := event.Validate(); err != nil {
+		t.Errorf("expected valid event, got %v", err)
+	}
+	ctx, ok := event.Context.(SetTriggerContext)
+	if !ok {
+		t.Fatalf("expected SetTriggerContext, got %T", event.Context)
+	}
+	if ctx != setCtx {
+		t.Errorf("expected context %+v, got %+v", setCtx, ctx)
+	}
+
+	missingSet := setCtx
+	missingSet.LoggedSetID = ""
+	if err := missingSet.Validate(); err == nil {
+		t.Error("expected error for missing loggedSetId")
+	}
+}
+
 // TestMarshalTriggerContext tests MarshalTriggerContext with envelope.
 func TestMarshalTriggerContext(t *testing.T) {
 	tests := []struct {
@@ -1212,6 +1253,7 @@ func TestTriggerContext_Interface(t *testing.T) {
 	var _ TriggerContext = WeekTriggerContext{}
 	var _ TriggerContext = CycleTriggerContext{}
 	var _ TriggerContext = FailureTriggerContext{}
+	var _ TriggerContext = SetTriggerContext{}
 	var _ TriggerContext = ManualTriggerContext{}
 }
 
@@ -0,0 +1,239 @@
+// Package readiness provides domain logic for daily readiness check-ins.
+// A check-in (sleep, soreness, stress, a self-rated readiness score and an optional
+// HRV reading) is reduced to a 0-100 readiness score, which a per-program mapping
+// turns into load and volume modifiers for the day's workout.
+package readiness
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Validation errors
+var (
+	ErrNoInputs              = errors.New("at least one of sleepQuality, soreness, stress or readinessScore is required")
+	ErrSleepQualityInvalid   = errors.New("sleep quality must be between 1 and 5")
+	ErrSorenessInvalid       = errors.New("soreness must be between 1 and 5")
+	ErrStressInvalid         = errors.New("stress must be between 1 and 5")
+	ErrReadinessScoreInvalid = errors.New("readiness score must be between 1 and 10")
+	ErrHRVInvalid            = errors.New("hrv must be greater than 0")
+	ErrTiersRequired         = errors.New("at least one tier is required")
+	ErrTierMinScoreInvalid   = errors.New("tier minScore must be between 0 and 100")
+	ErrDuplicateTierMinScore = errors.New("duplicate tier minScore")
+	ErrLoadModifierInvalid   = errors.New("loadModifier must be between 50 and 120")
+	ErrVolumeModifierInvalid = errors.New("volumeModifier must be between 10 and 100")
+)
+
+const (
+	// MinRating and MaxRating bound the sleep, soreness and stress ratings.
+	MinRating = 1
+	MaxRating = 5
+	// MaxReadinessScore is the top of the self-rated readiness scale (1-10).
+	MaxReadinessScore = 10
+
+	// MinLoadModifier and MaxLoadModifier bound a tier's load modifier (percent).
+	MinLoadModifier = 50
+	MaxLoadModifier = 120
+	// MinVolumeModifier and MaxVolumeModifier bound a tier's volume modifier (percent).
+	// Readiness can only remove work sets, never add them.
+	MinVolumeModifier = 10
+	MaxVolumeModifier = 100
+
+	// NeutralModifier is a modifier that leaves loads and volume unchanged.
+	NeutralModifier = 100
+
+	// HRVBaselineWindow is the number of previous HRV readings averaged into the baseline.
+	HRVBaselineWindow = 7
+	// hrvSensitivity scales the HRV deviation from baseline: a reading 10% below
+	// baseline scores 0, a reading 10% above scores 1.
+	hrvSensitivity = 5
+)
+
+// Inputs are the values reported in a readiness check-in.
+// Sleep quality is rated 1 (terrible) to 5 (great); soreness and stress are rated
+// 1 (none) to 5 (severe). ReadinessScore is the lifter's own 1-10 rating.
+type Inputs struct {
+	SleepQuality   *int
+	Soreness       *int
+	Stress         *int
+	ReadinessScore *int
+	// HRV is the morning heart rate variability reading in milliseconds.
+	HRV *float64
+}
+
+// ValidateInputs validates a check-in.
+// Returns an error if validation fails, nil otherwise.
+func ValidateInputs(in Inputs) error {
+	if in.SleepQuality == nil && in.Soreness == nil && in.Stress == nil && in.ReadinessScore == nil {
+		return ErrNoInputs
+	}
+	if !validRating(in.SleepQuality, MaxRating) {
+		return ErrSleepQualityInvalid
+	}
+	if !validRating(in.Soreness, MaxRating) {
+		return ErrSorenessInvalid
+	}
+	if !validRating(in.Stress, MaxRating) {
+		return ErrStressInvalid
+	}
+	if !validRating(in.ReadinessScore, MaxReadinessScore) {
+		return ErrReadinessScoreInvalid
+	}
+	if in.HRV != nil && *in.HRV <= 0 {
+		return ErrHRVInvalid
+	}
+	return nil
+}
+
+func validRating(v *int, max int) bool {
+	return v == nil || (*v >= MinRating && *v <= max)
+}
+
+// Score computes the composite readiness score (0-100, one decimal).
+//
+// Each reported input is normalized to 0-1, with 1 meaning most ready (good sleep,
+// no soreness, no stress, high self-rating), and the score is their average.
+// A middling check-in therefore scores 50.
+//
+// HRV is only meaningful relative to the lifter's own baseline, so it contributes
+// only when hrvBaseline (the average of recent readings) is available. A reading
+// at baseline counts as neutral (0.5).
+func Score(in Inputs, hrvBaseline *float64) float64 {
+	var sum float64
+	var count int
+
+	add := func(v float64) {
+		sum += v
+		count++
+	}
+
+	if in.SleepQuality != nil {
+		add(float64(*in.SleepQuality-MinRating) / (MaxRating - MinRating))
+	}
+	if in.Soreness != nil {
+		add(float64(MaxRating-*in.Soreness) / (MaxRating - MinRating))
+	}
+	if in.Stress != nil {
+		add(float64(MaxRating-*in.Stress) / (MaxRating - MinRating))
+	}
+	if in.ReadinessScore != nil {
+		add(float64(*in.ReadinessScore-MinRating) / (MaxReadinessScore - MinRating))
+	}
+	if in.HRV != nil && hrvBaseline != nil && *hrvBaseline > 0 {
+		deviation := *in.HRV / *hrvBaseline - 1
+		add(math.Max(0, math.Min(1, 0.5+deviation*hrvSensitivity)))
+	}
+
+	if count == 0 {
+		return 50
+	}
+	return math.Round(sum/float64(count)*1000) / 10
+}
+
+// Tier maps readiness scores at or above MinScore to load and volume modifiers.
+// Modifiers are percentages: LoadModifier 95 scales every load to 95%, and
+// VolumeModifier 80 keeps 80% of each exercise's work sets (rounded up).
+type Tier struct {
+	MinScore       float64 `json:"minScore"`
+	LoadModifier   float64 `json:"loadModifier"`
+	VolumeModifier float64 `json:"volumeModifier"`
+}
+
+// Mapping is a program's readiness score to modifier mapping.
+type Mapping struct {
+	Tiers []Tier `json:"tiers"`
+}
+
+// DefaultMapping returns the mapping used by programs without their own.
+// A neutral or better check-in leaves the workout unchanged; progressively
+// worse check-ins reduce load first, then volume.
+func DefaultMapping() Mapping {
+	return Mapping{
+		Tiers: []Tier{
+			{MinScore: 40, LoadModifier: 100, VolumeModifier: 100},
+			{MinScore: 25, LoadModifier: 95, VolumeModifier: 100},
+			{MinScore: 10, LoadModifier: 90, VolumeModifier: 80},
+			{MinScore: 0, LoadModifier: 85, VolumeModifier: 60},
+		},
+	}
+}
+
+// ValidateMapping validates a readiness mapping.
+// Returns an error if validation fails, nil otherwise.
+func ValidateMapping(m Mapping) error {
+	if len(m.Tiers) == 0 {
+		return ErrTiersRequired
+	}
+
+	seen := make(map[float64]bool)
+	for _, tier := range m.Tiers {
+		if tier.MinScore < 0 || tier.MinScore > 100 {
+			return ErrTierMinScoreInvalid
+		}
+		if seen[tier.MinScore] {
+			return ErrDuplicateTierMinScore
+		}
+		seen[tier.MinScore] = true
+
+		if tier.LoadModifier < MinLoadModifier || tier.LoadModifier > MaxLoadModifier {
+			return ErrLoadModifierInvalid
+		}
+		if tier.VolumeModifier < MinVolumeModifier || tier.VolumeModifier > MaxVolumeModifier {
+			return ErrVolumeModifierInvalid
+		}
+	}
+	return nil
+}
+
+// Normalize returns a copy of the mapping with tiers ordered by descending MinScore.
+func (m Mapping) Normalize() Mapping {
+	tiers := make([]Tier, len(m.Tiers))
+	copy(tiers, m.Tiers)
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].MinScore > tiers[j].MinScore
+	})
+	return Mapping{Tiers: tiers}
+}
+
+// Resolve returns the tier for a readiness score: the tier with the highest
+// MinScore not above the score. Scores below every tier are left unadjusted.
+func (m Mapping) Resolve(score float64) Tier {
+	var match *Tier
+	for i := range m.Tiers {
+		tier := &m.Tiers[i]
+		if score >= tier.MinScore && (match == nil || tier.MinScore > match.MinScore) {
+			match = tier
+		}
+	}
+	if match == nil {
+		return Tier{LoadModifier: NeutralModifier, VolumeModifier: NeutralModifier}
+	}
+	return *match
+}
+
+// Adjustment is the readiness adjustment applied to a day's workout.
+type Adjustment struct {
+	// CheckInID is the check-in the adjustment was derived from.
+	// Nil if the check-in has since been deleted.
+	CheckInID      *string `json:"checkInId"`
+	Score          float64 `json:"score"`
+	LoadModifier   float64 `json:"loadModifier"`
+	VolumeModifier float64 `json:"volumeModifier"`
+}
+
+// NewAdjustment resolves the adjustment for a check-in's score under a mapping.
+func NewAdjustment(checkInID string, score float64, m Mapping) *Adjustment {
+	tier := m.Resolve(score)
+	return &Adjustment{
+		CheckInID:      &checkInID,
+		Score:          score,
+		LoadModifier:   tier.LoadModifier,
+		VolumeModifier: tier.VolumeModifier,
+	}
+}
+
+// IsNeutral returns true if the adjustment leaves loads and volume unchanged.
+func (a *Adjustment) IsNeutral() bool {
+	return a == nil || (a.LoadModifier == NeutralModifier && a.VolumeModifier == NeutralModifier)
+}
@@ -0,0 +1,152 @@
+package readiness
+
+import "testing"
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestValidateInputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       Inputs
+		expected error
+	}{
+		{"valid", Inputs{SleepQuality: intPtr(4), Soreness: intPtr(2), Stress: intPtr(3)}, nil},
+		{"readiness score only", Inputs{ReadinessScore: intPtr(7)}, nil},
+		{"no inputs", Inputs{HRV: floatPtr(60)}, ErrNoInputs},
+		{"sleep out of range", Inputs{SleepQuality: intPtr(6)}, ErrSleepQualityInvalid},
+		{"soreness out of range", Inputs{Soreness: intPtr(0)}, ErrSorenessInvalid},
+		{"stress out of range", Inputs{Stress: intPtr(9)}, ErrStressInvalid},
+		{"readiness out of range", Inputs{ReadinessScore: intPtr(11)}, ErrReadinessScoreInvalid},
+		{"hrv not positive", Inputs{ReadinessScore: intPtr(5), HRV: floatPtr(0)}, ErrHRVInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateInputs(tt.in); err != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       Inputs
+		baseline *float64
+		expected float64
+	}{
+		{"best possible", Inputs{SleepQuality: intPtr(5), Soreness: intPtr(1), Stress: intPtr(1), ReadinessScore: intPtr(10)}, nil, 100},
+		{"worst possible", Inputs{SleepQuality: intPtr(1), Soreness: intPtr(5), Stress: intPtr(5), ReadinessScore: intPtr(1)}, nil, 0},
+		{"middling", Inputs{SleepQuality: intPtr(3), Soreness: intPtr(3), Stress: intPtr(3)}, nil, 50},
+		// (0.75 + 0.25) / 2
+		{"partial inputs", Inputs{SleepQuality: intPtr(4), Soreness: intPtr(4)}, nil, 50},
+		{"hrv ignored without baseline", Inputs{ReadinessScore: intPtr(10), HRV: floatPtr(40)}, nil, 100},
+		// (1 + 0) / 2: HRV 10% below baseline scores 0
+		{"hrv below baseline", Inputs{ReadinessScore: intPtr(10), HRV: floatPtr(54)}, floatPtr(60), 50},
+		// (0.5 + 0.75) / 2: HRV 5% above baseline scores 0.75
+		{"hrv above baseline", Inputs{Stress: intPtr(3), HRV: floatPtr(63)}, floatPtr(60), 62.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(tt.in, tt.baseline); got != tt.expected {
+				t.Errorf("Score() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateMapping(t *testing.T) {
+	if err := ValidateMapping(DefaultMapping()); err != nil {
+		t.Errorf("default mapping should be valid: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		mapping  Mapping
+		expected error
+	}{
+		{"no tiers", Mapping{}, ErrTiersRequired},
+		{"min score out of range", Mapping{Tiers: []Tier{{MinScore: 101, LoadModifier: 100, VolumeModifier: 100}}}, ErrTierMinScoreInvalid},
+		{"duplicate min score", Mapping{Tiers: []Tier{
+			{MinScore: 50, LoadModifier: 100, VolumeModifier: 100},
+			{MinScore: 50, LoadModifier: 90, VolumeModifier: 100},
+		}}, ErrDuplicateTierMinScore},
+		{"load too high", Mapping{Tiers: []Tier{{MinScore: 0, LoadModifier: 150, VolumeModifier: 100}}}, ErrLoadModifierInvalid},
+		{"volume above 100", Mapping{Tiers: []Tier{{MinScore: 0, LoadModifier: 100, VolumeModifier: 110}}}, ErrVolumeModifierInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateMapping(tt.mapping); err != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestMapping_Resolve(t *testing.T) {
+	m := DefaultMapping()
+
+	tests := []struct {
+		score          float64
+		expectedLoad   float64
+		expectedVolume float64
+	}{
+		{90, 100, 100},
+		{40, 100, 100},
+		{39.9, 95, 100},
+		{12.5, 90, 80},
+		{0, 85, 60},
+	}
+
+	for _, tt := range tests {
+		tier := m.Resolve(tt.score)
+		if tier.LoadModifier != tt.expectedLoad || tier.VolumeModifier != tt.expectedVolume {
+			t.Errorf("Resolve(%v) = %+v, expected load %v volume %v", tt.score, tier, tt.expectedLoad, tt.expectedVolume)
+		}
+	}
+
+	// Scores below every tier are left unadjusted
+	partial := Mapping{Tiers: []Tier{{MinScore: 50, LoadModifier: 105, VolumeModifier: 100}}}
+	if tier := partial.Resolve(20); tier.LoadModifier != NeutralModifier || tier.VolumeModifier != NeutralModifier {
+		t.Errorf("expected neutral tier, got %+v", tier)
+	}
+}
+
+func TestMapping_Normalize(t *testing.T) {
+	m := Mapping{Tiers: []Tier{
+		{MinScore: 0, LoadModifier: 90, VolumeModifier: 100},
+		{MinScore: 60, LoadModifier: 100, VolumeModifier: 100},
+		{MinScore: 30, LoadModifier: 95, VolumeModifier: 100},
+	}}
+	normalized := m.Normalize()
+	for i, expected := range []float64{60, 30, 0} {
+		if normalized.Tiers[i].MinScore != expected {
+			t.Errorf("tier %d: expected minScore %v, got %v", i, expected, normalized.Tiers[i].MinScore)
+		}
+	}
+	if m.Tiers[0].MinScore != 0 {
+		t.Error("Normalize should not modify the original mapping")
+	}
+}
+
+func TestAdjustment_IsNeutral(t *testing.T) {
+	var nilAdj *Adjustment
+	if !nilAdj.IsNeutral() {
+		t.Error("nil adjustment should be neutral")
+	}
+	if !NewAdjustment("c1", 80, DefaultMapping()).IsNeutral() {
+		t.Error("high score should be neutral under the default mapping")
+	}
+	if NewAdjustment("c1", 20, DefaultMapping()).IsNeutral() {
+		t.Error("low score should not be neutral under the default mapping")
+	}
+}
@@ -44,6 +44,7 @@ func (a *AMRAPSetScheme) Type() SetSchemeType {
 //   - Weight: baseWeight (unchanged)
 //   - TargetReps: MinReps value (the minimum to "succeed")
 //   - IsWorkSet: true (all AMRAP sets are work sets)
+//   - IsAMRAP: true
 //
 // Note: The actual "AMRAP" behavior (logging more reps than target) happens
 // at workout logging time. For generation purposes, AMRAP works like Fixed.
@@ -59,6 +60,7 @@ func (a *AMRAPSetScheme) GenerateSets(baseWeight float64, _ SetGenerationContext
 			Weight:     baseWeight,
 			TargetReps: a.MinReps,
 			IsWorkSet:  true,
+			IsAMRAP:    true,
 		}
 	}
 	return sets, nil
@@ -150,6 +150,9 @@ func TestAMRAPSetScheme_GenerateSets(t *testing.T) {
 				if !set.IsWorkSet {
 					t.Errorf("set %d: expected IsWorkSet to be true", i)
 				}
+				if !set.IsAMRAP {
+					t.Errorf("set %d: expected IsAMRAP to be true", i)
+				}
 			}
 		})
 	}
@@ -51,6 +51,7 @@ func (g *GreySkullSetScheme) Type() SetSchemeType {
 //   - Weight: baseWeight (unchanged)
 //   - TargetReps: FixedReps for fixed sets, MinAMRAPReps for AMRAP sets
 //   - IsWorkSet: true (all GreySkull sets are work sets)
+//   - IsAMRAP: true for the AMRAP sets
 //
 // The first FixedSets sets are fixed sets, followed by AMRAPSets AMRAP sets.
 // AMRAP sets use MinAMRAPReps as the target, representing the minimum to "succeed".
@@ -79,6 +80,7 @@ func (g *GreySkullSetScheme) GenerateSets(baseWeight float64, _ SetGenerationCon
 			Weight:     baseWeight,
 			TargetReps: g.MinAMRAPReps,
 			IsWorkSet:  true,
+			IsAMRAP:    true,
 		})
 	}
 
@@ -201,6 +201,9 @@ func TestGreySkullSetScheme_GenerateSets(t *testing.T) {
 				if !set.IsWorkSet {
 					t.Errorf("fixed set %d: expected IsWorkSet to be true", i)
 				}
+				if set.IsAMRAP {
+					t.Errorf("fixed set %d: expected IsAMRAP to be false", i)
+				}
 			}
 
 			// Verify AMRAP sets
@@ -220,6 +223,9 @@ func TestGreySkullSetScheme_GenerateSets(t *testing.T) {
 				if !set.IsWorkSet {
 					t.Errorf("AMRAP set %d: expected IsWorkSet to be true", i)
 				}
+				if !set.IsAMRAP {
+					t.Errorf("AMRAP set %d: expected IsAMRAP to be true", i)
+				}
 			}
 		})
 	}
@@ -68,6 +68,9 @@ type GeneratedSet struct {
 	// IsProvisional is true for variable schemes until the set is logged.
 	// When true, more sets may be added based on session performance.
 	IsProvisional bool `json:"isProvisional,omitempty"`
+	// IsAMRAP is true when the lifter performs as many reps as possible, with TargetReps
+	// as the minimum.
+	IsAMRAP bool `json:"isAmrap,omitempty"`
 }
 
 // SetGenerationContext provides additional context for set generation.
@@ -0,0 +1,363 @@
+// Package strengthscore provides domain logic for bodyweight-relative strength scores.
+// It implements the DOTS, IPF GL, and Wilks formulas along with age coefficients
+// for junior and masters lifters. All calculations are performed in kilograms.
+package strengthscore
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Sex identifies which coefficient set a formula should use.
+type Sex string
+
+const (
+	// Male selects the men's coefficients.
+	Male Sex = "male"
+	// Female selects the women's coefficients.
+	Female Sex = "female"
+)
+
+// Event identifies the competition event for IPF GL points.
+type Event string
+
+const (
+	// EventPowerlifting is the three-lift total (squat, bench press, deadlift).
+	EventPowerlifting Event = "POWERLIFTING"
+	// EventBench is a bench press only competition.
+	EventBench Event = "BENCH"
+)
+
+// Equipment identifies the equipment division for IPF GL points.
+type Equipment string
+
+const (
+	// EquipmentClassic is raw (unequipped) lifting.
+	EquipmentClassic Equipment = "CLASSIC"
+	// EquipmentEquipped is single-ply equipped lifting.
+	EquipmentEquipped Equipment = "EQUIPPED"
+)
+
+// LbPerKg is the number of pounds in one kilogram.
+const LbPerKg = 2.20462262
+
+// Validation errors
+var (
+	ErrInvalidSex        = errors.New("sex must be male or female")
+	ErrBodyweightInvalid = errors.New("bodyweight must be greater than 0")
+	ErrResultInvalid     = errors.New("lifted total must be greater than 0")
+	ErrInvalidEvent      = errors.New("event must be POWERLIFTING or BENCH")
+	ErrInvalidEquipment  = errors.New("equipment must be CLASSIC or EQUIPPED")
+)
+
+// ToKg converts a weight in the given unit ("lb" or "kg") to kilograms.
+func ToKg(weight float64, unit string) float64 {
+	if unit == "lb" {
+		return weight / LbPerKg
+	}
+	return weight
+}
+
+// FromKg converts a weight in kilograms to the given unit ("lb" or "kg").
+func FromKg(weightKg float64, unit string) float64 {
+	if unit == "lb" {
+		return weightKg * LbPerKg
+	}
+	return weightKg
+}
+
+// ValidateSex validates a sex value.
+func ValidateSex(sex Sex) error {
+	if sex != Male && sex != Female {
+		return ErrInvalidSex
+	}
+	return nil
+}
+
+func validateInputs(sex Sex, bodyweightKg, resultKg float64) error {
+	if err := ValidateSex(sex); err != nil {
+		return err
+	}
+	if bodyweightKg <= 0 {
+		return fmt.Errorf("%w: got %.2f", ErrBodyweightInvalid, bodyweightKg)
+	}
+	if resultKg <= 0 {
+		return fmt.Errorf("%w: got %.2f", ErrResultInvalid, resultKg)
+	}
+	return nil
+}
+
+// DOTS coefficients (2019 revision).
+var dotsCoefficients = map[Sex][5]float64{
+	Male:   {-307.75076, 24.0900756, -0.1918759221, 0.0007391293, -0.000001093},
+	Female: {-57.96288, 13.6175032, -0.1126655495, 0.0005158568, -0.0000010706},
+}
+
+// DOTS bodyweight bounds: bodyweights outside these ranges are clamped.
+var dotsBounds = map[Sex][2]float64{
+	Male:   {40, 210},
+	Female: {40, 150},
+}
+
+// DOTS calculates DOTS points for a lifted total.
+// Formula: result × 500 / (a + b·bw + c·bw² + d·bw³ + e·bw⁴)
+func DOTS(sex Sex, bodyweightKg, resultKg float64) (float64, error) {
+	if err := validateInputs(sex, bodyweightKg, resultKg); err != nil {
+		return 0, err
+	}
+
+	bounds := dotsBounds[sex]
+	bw := clamp(bodyweightKg, bounds[0], bounds[1])
+	c := dotsCoefficients[sex]
+	denominator := c[0] + c[1]*bw + c[2]*math.Pow(bw, 2) + c[3]*math.Pow(bw, 3) + c[4]*math.Pow(bw, 4)
+
+	return roundTo(resultKg*500/denominator, 2), nil
+}
+
+// ipfGLKey identifies a row in the IPF GL coefficient table.
+type ipfGLKey struct {
+	sex       Sex
+	event     Event
+	equipment Equipment
+}
+
+// IPF GL coefficients (A, B, C) effective 2020.
+var ipfGLCoefficients = map[ipfGLKey][3]float64{
+	{Male, EventPowerlifting, EquipmentClassic}:    {1199.72839, 1025.18162, 0.00921},
+	{Male, EventPowerlifting, EquipmentEquipped}:   {1236.25115, 1449.21864, 0.01644},
+	{Male, EventBench, EquipmentClassic}:           {320.98041, 281.40258, 0.01008},
+	{Male, EventBench, EquipmentEquipped}:          {381.22073, 733.79378, 0.02398},
+	{Female, EventPowerlifting, EquipmentClassic}:  {610.32796, 1045.59282, 0.03048},
+	{Female, EventPowerlifting, EquipmentEquipped}: {758.63878, 949.31382, 0.02435},
+	{Female, EventBench, EquipmentClassic}:         {142.40398, 442.52671, 0.04724},
+	{Female, EventBench, EquipmentEquipped}:        {221.82209, 357.00377, 0.02937},
+}
+
+// IPFGL calculates IPF GoodLift points for a result.
+// Formula: result × 100 / (A − B·e^(−C·bw))
+// Lifters below 35kg bodyweight are not scored by the IPF; they are clamped to 35kg.
+func IPFGL(sex Sex, event Event, equipment Equipment, bodyweightKg, resultKg float64) (float64, error) {
+	if err := validateInputs(sex, bodyweightKg, resultKg); err != nil {
+		return 0, err
+	}
+	if event != EventPowerlifting && event != EventBench {
+		return 0, ErrInvalidEvent
+	}
+	if equipment != EquipmentClassic && equipment != EquipmentEquipped {
+		return 0, ErrInvalidEquipment
+	}
+
+	c := ipfGLCoefficients[ipfGLKey{sex, event, equipment}]
+	bw := math.Max(bodyweightKg, 35)
+	denominator := c[0] - c[1]*math.Exp(-c[2]*bw)
+
+	return roundTo(resultKg*100/denominator, 2), nil
+}
+
+// Wilks coefficients (original formula).
+var wilksCoefficients = map[Sex][6]float64{
+	Male:   {-216.0475144, 16.2606339, -0.002388645, -0.00113732, 7.01863e-06, -1.291e-08},
+	Female: {594.31747775582, -27.23842536447, 0.82112226871, -0.00930733913, 4.731582e-05, -9.054e-08},
+}
+
+// Wilks bodyweight bounds: bodyweights outside these ranges are clamped.
+var wilksBounds = map[Sex][2]float64{
+	Male:   {40, 201.9},
+	Female: {26.51, 154.53},
+}
+
+// Wilks calculates Wilks points for a lifted total.
+// Formula: result × 500 / (a + b·bw + c·bw² + d·bw³ + e·bw⁴ + f·bw⁵)
+func Wilks(sex Sex, bodyweightKg, resultKg float64) (float64, error) {
+	if err := validateInputs(sex, bodyweightKg, resultKg); err != nil {
+		return 0, err
+	}
+
+	bounds := wilksBounds[sex]
+	bw := clamp(bodyweightKg, bounds[0], bounds[1])
+	c := wilksCoefficients[sex]
+	denominator := c[0] + c[1]*bw + c[2]*math.Pow(bw, 2) + c[3]*math.Pow(bw, 3) +
+		c[4]*math.Pow(bw, 4) + c[5]*math.Pow(bw, 5)
+
+	return roundTo(resultKg*500/denominator, 2), nil
+}
+
+// Junior age coefficients (Foster), indexed by age in years.
+var juniorAgeCoefficients = map[int]float64{
+	14: 1.23, 15: 1.18, 16: 1.13, 17: 1.08, 18: 1.06,
+	19: 1.04, 20: 1.03, 21: 1.02, 22: 1.01,
+}
+
+// Masters age coefficients (McCulloch), indexed by age in years.
+var mastersAgeCoefficients = map[int]float64{
+	41: 1.010, 42: 1.020, 43: 1.031, 44: 1.043, 45: 1.055,
+	46: 1.068, 47: 1.082, 48: 1.097, 49: 1.113, 50: 1.130,
+	51: 1.147, 52: 1.165, 53: 1.184, 54: 1.204, 55: 1.225,
+	56: 1.246, 57: 1.268, 58: 1.291, 59: 1.315, 60: 1.340,
+	61: 1.366, 62: 1.393, 63: 1.421, 64: 1.450, 65: 1.480,
+	66: 1.511, 67: 1.543, 68: 1.576, 69: 1.610, 70: 1.645,
+	71: 1.681, 72: 1.718, 73: 1.756, 74: 1.795, 75: 1.835,
+	76: 1.876, 77: 1.918, 78: 1.961, 79: 2.005, 80: 2.050,
+}
+
+// AgeCoefficient returns the age adjustment multiplier for a lifter.
+// Juniors (14-22) use the Foster coefficients and masters (41-80) use the
+// McCulloch coefficients. Ages between 23 and 40 return 1.0. Ages below 14
+// use the age-14 coefficient and ages above 80 use the age-80 coefficient.
+func AgeCoefficient(age int) float64 {
+	switch {
+	case age < 14:
+		return juniorAgeCoefficients[14]
+	case age > 80:
+		return mastersAgeCoefficients[80]
+	}
+	if c, ok := juniorAgeCoefficients[age]; ok {
+		return c
+	}
+	if c, ok := mastersAgeCoefficients[age]; ok {
+		return c
+	}
+	return 1.0
+}
+
+// Scores holds every supported score for a single total.
+type Scores struct {
+	TotalKg        float64
+	BodyweightKg   float64
+	DOTS           float64
+	IPFGL          float64
+	Wilks          float64
+	AgeCoefficient float64
+	// AgeAdjustedDOTS is DOTS multiplied by the age coefficient (nil when age is unknown).
+	AgeAdjustedDOTS *float64
+}
+
+// Calculate computes DOTS, IPF GL (classic powerlifting) and Wilks for a three-lift total.
+// If age is non-nil, the age coefficient and age-adjusted DOTS are also populated.
+func Calculate(sex Sex, bodyweightKg, totalKg float64, age *int) (*Scores, error) {
+	dots, err := DOTS(sex, bodyweightKg, totalKg)
+	if err != nil {
+		return nil, err
+	}
+	gl, err := IPFGL(sex, EventPowerlifting, EquipmentClassic, bodyweightKg, totalKg)
+	if err != nil {
+		return nil, err
+	}
+	wilks, err := Wilks(sex, bodyweightKg, totalKg)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := &Scores{
+		TotalKg:        roundTo(totalKg, 2),
+		BodyweightKg:   roundTo(bodyweightKg, 2),
+		DOTS:           dots,
+		IPFGL:          gl,
+		Wilks:          wilks,
+		AgeCoefficient: 1.0,
+	}
+
+	if age != nil {
+		scores.AgeCoefficient = AgeCoefficient(*age)
+		adjusted := roundTo(dots*scores.AgeCoefficient, 2)
+		scores.AgeAdjustedDOTS = &adjusted
+	}
+
+	return scores, nil
+}
+
+// clamp restricts v to the closed interval [lo, hi].
+func clamp(v, lo, hi float64) float64 {
+	return math.Min(math.Max(v, lo), hi)
+}
+
+// roundTo rounds v to the given number of decimal places.
+func roundTo(v float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(v*factor) / factor
+}
+
+// WeightClass is an IPF bodyweight class, e.g. "93" or "120+".
+type WeightClass struct {
+	Name string
+	// LimitKg is the class upper limit in kilograms. Unlimited (superheavyweight) classes use 0.
+	LimitKg float64
+}
+
+// Unlimited reports whether the class has no upper bodyweight limit.
+func (c WeightClass) Unlimited() bool {
+	return c.LimitKg == 0
+}
+
+// IPF weight classes by sex, ordered lightest to heaviest.
+var weightClasses = map[Sex][]WeightClass{
+	Male: {
+		{"59", 59}, {"66", 66}, {"74", 74}, {"83", 83},
+		{"93", 93}, {"105", 105}, {"120", 120}, {"120+", 0},
+	},
+	Female: {
+		{"47", 47}, {"52", 52}, {"57", 57}, {"63", 63},
+		{"69", 69}, {"76", 76}, {"84", 84}, {"84+", 0},
+	},
+}
+
+// ErrUnknownWeightClass is returned when a weight class name is not recognized.
+var ErrUnknownWeightClass = errors.New("unknown weight class")
+
+// WeightClasses returns the IPF weight classes for the given sex.
+func WeightClasses(sex Sex) []WeightClass {
+	return weightClasses[sex]
+}
+
+// IsWeightClassName reports whether name is a weight class for either sex.
+func IsWeightClassName(name string) bool {
+	for _, classes := range weightClasses {
+		for _, c := range classes {
+			if c.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LookupWeightClass finds a named weight class for the given sex.
+func LookupWeightClass(sex Sex, name string) (WeightClass, error) {
+	if err := ValidateSex(sex); err != nil {
+		return WeightClass{}, err
+	}
+	for _, c := range weightClasses[sex] {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	return WeightClass{}, fmt.Errorf("%w: %q", ErrUnknownWeightClass, name)
+}
+
+// ClassForBodyweight returns the weight class a lifter of the given bodyweight competes in.
+func ClassForBodyweight(sex Sex, bodyweightKg float64) (WeightClass, error) {
+	if err := ValidateSex(sex); err != nil {
+		return WeightClass{}, err
+	}
+	if bodyweightKg <= 0 {
+		return WeightClass{}, ErrBodyweightInvalid
+	}
+	classes := weightClasses[sex]
+	for _, c := range classes {
+		if !c.Unlimited() && bodyweightKg <= c.LimitKg {
+			return c, nil
+		}
+	}
+	return classes[len(classes)-1], nil
+}
+
+// AgeOn returns the age in whole years of someone born on birthDate at the given time.
+func AgeOn(birthDate, at time.Time) int {
+	age := at.Year() - birthDate.Year()
+	if at.Month() < birthDate.Month() || (at.Month() == birthDate.Month() && at.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,252 @@
+package strengthscore
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func assertNear(t *testing.T, name string, expected, actual, tolerance float64) {
+	t.Helper()
+	if math.Abs(expected-actual) > tolerance {
+		t.Errorf("%s: expected %.2f, got %.2f", name, expected, actual)
+	}
+}
+
+func TestDOTS(t *testing.T) {
+	tests := []struct {
+		name       string
+		sex        Sex
+		bodyweight float64
+		total      float64
+		expected   float64
+	}{
+		{"male 93kg 700kg total", Male, 93, 700, 445.38},
+		{"female 63kg 400kg total", Female, 63, 400, 430.21},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DOTS(tt.sex, tt.bodyweight, tt.total)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertNear(t, "DOTS", tt.expected, got, 0.01)
+		})
+	}
+}
+
+func TestDOTS_ClampsBodyweight(t *testing.T) {
+	// Bodyweights above the upper bound score the same as the bound itself
+	atBound, _ := DOTS(Male, 210, 1000)
+	above, _ := DOTS(Male, 250, 1000)
+	if atBound != above {
+		t.Errorf("expected clamped score %.2f, got %.2f", atBound, above)
+	}
+
+	atBound, _ = DOTS(Female, 40, 200)
+	below, _ := DOTS(Female, 30, 200)
+	if atBound != below {
+		t.Errorf("expected clamped score %.2f, got %.2f", atBound, below)
+	}
+}
+
+func TestIPFGL(t *testing.T) {
+	tests := []struct {
+		name       string
+		sex        Sex
+		event      Event
+		equipment  Equipment
+		bodyweight float64
+		result     float64
+		expected   float64
+	}{
+		{"male classic powerlifting", Male, EventPowerlifting, EquipmentClassic, 93, 700, 91.57},
+		{"female classic powerlifting", Female, EventPowerlifting, EquipmentClassic, 63, 400, 87.51},
+		{"male classic bench", Male, EventBench, EquipmentClassic, 93, 200, 94.89},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IPFGL(tt.sex, tt.event, tt.equipment, tt.bodyweight, tt.result)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertNear(t, "IPF GL", tt.expected, got, 0.01)
+		})
+	}
+}
+
+func TestIPFGL_EquippedScoresLowerThanClassic(t *testing.T) {
+	classic, _ := IPFGL(Male, EventPowerlifting, EquipmentClassic, 93, 800)
+	equipped, _ := IPFGL(Male, EventPowerlifting, EquipmentEquipped, 93, 800)
+	if equipped >= classic {
+		t.Errorf("expected equipped score (%.2f) below classic score (%.2f)", equipped, classic)
+	}
+}
+
+func TestIPFGL_InvalidEventAndEquipment(t *testing.T) {
+	if _, err := IPFGL(Male, "SQUAT", EquipmentClassic, 93, 700); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("expected ErrInvalidEvent, got %v", err)
+	}
+	if _, err := IPFGL(Male, EventPowerlifting, "MULTIPLY", 93, 700); !errors.Is(err, ErrInvalidEquipment) {
+		t.Errorf("expected ErrInvalidEquipment, got %v", err)
+	}
+}
+
+func TestWilks(t *testing.T) {
+	got, err := Wilks(Male, 93, 700)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertNear(t, "Wilks male", 439.73, got, 0.01)
+
+	got, err = Wilks(Female, 63, 400)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertNear(t, "Wilks female", 429.58, got, 0.01)
+}
+
+func TestScores_InvalidInputs(t *testing.T) {
+	formulas := map[string]func(Sex, float64, float64) (float64, error){
+		"DOTS":  DOTS,
+		"Wilks": Wilks,
+		"IPFGL": func(s Sex, bw, r float64) (float64, error) {
+			return IPFGL(s, EventPowerlifting, EquipmentClassic, bw, r)
+		},
+	}
+
+	for name, fn := range formulas {
+		if _, err := fn("other", 93, 700); !errors.Is(err, ErrInvalidSex) {
+			t.Errorf("%s: expected ErrInvalidSex, got %v", name, err)
+		}
+		if _, err := fn(Male, 0, 700); !errors.Is(err, ErrBodyweightInvalid) {
+			t.Errorf("%s: expected ErrBodyweightInvalid, got %v", name, err)
+		}
+		if _, err := fn(Male, 93, -5); !errors.Is(err, ErrResultInvalid) {
+			t.Errorf("%s: expected ErrResultInvalid, got %v", name, err)
+		}
+	}
+}
+
+func TestAgeCoefficient(t *testing.T) {
+	tests := []struct {
+		age      int
+		expected float64
+	}{
+		{10, 1.23},
+		{14, 1.23},
+		{18, 1.06},
+		{22, 1.01},
+		{23, 1.0},
+		{30, 1.0},
+		{40, 1.0},
+		{41, 1.010},
+		{50, 1.130},
+		{65, 1.480},
+		{80, 2.050},
+		{90, 2.050},
+	}
+
+	for _, tt := range tests {
+		if got := AgeCoefficient(tt.age); got != tt.expected {
+			t.Errorf("age %d: expected %.3f, got %.3f", tt.age, tt.expected, got)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	scores, err := Calculate(Male, 93, 700, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertNear(t, "DOTS", 445.38, scores.DOTS, 0.01)
+	assertNear(t, "IPF GL", 91.57, scores.IPFGL, 0.01)
+	assertNear(t, "Wilks", 439.73, scores.Wilks, 0.01)
+	if scores.AgeCoefficient != 1.0 {
+		t.Errorf("expected age coefficient 1.0 without age, got %.3f", scores.AgeCoefficient)
+	}
+	if scores.AgeAdjustedDOTS != nil {
+		t.Error("expected nil age-adjusted DOTS without age")
+	}
+
+	age := 50
+	scores, err = Calculate(Male, 93, 700, &age)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scores.AgeAdjustedDOTS == nil {
+		t.Fatal("expected age-adjusted DOTS with age")
+	}
+	assertNear(t, "age-adjusted DOTS", 445.38*1.130, *scores.AgeAdjustedDOTS, 0.01)
+}
+
+func TestUnitConversion(t *testing.T) {
+	assertNear(t, "lb to kg", 100, ToKg(220.462262, "lb"), 0.0001)
+	assertNear(t, "kg to kg", 100, ToKg(100, "kg"), 0)
+	assertNear(t, "kg to lb", 220.462262, FromKg(100, "lb"), 0.0001)
+}
+
+func TestClassForBodyweight(t *testing.T) {
+	tests := []struct {
+		sex        Sex
+		bodyweight float64
+		expected   string
+	}{
+		{Male, 55, "59"},
+		{Male, 93, "93"},
+		{Male, 93.1, "105"},
+		{Male, 140, "120+"},
+		{Female, 63, "63"},
+		{Female, 90, "84+"},
+	}
+
+	for _, tt := range tests {
+		c, err := ClassForBodyweight(tt.sex, tt.bodyweight)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.Name != tt.expected {
+			t.Errorf("%s %.1fkg: expected class %s, got %s", tt.sex, tt.bodyweight, tt.expected, c.Name)
+		}
+	}
+}
+
+func TestLookupWeightClass(t *testing.T) {
+	c, err := LookupWeightClass(Male, "83")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.LimitKg != 83 || c.Unlimited() {
+		t.Errorf("expected 83kg limited class, got %+v", c)
+	}
+
+	c, err = LookupWeightClass(Female, "84+")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !c.Unlimited() {
+		t.Error("expected 84+ to be unlimited")
+	}
+
+	// Men's class is not valid for women
+	if _, err := LookupWeightClass(Female, "93"); !errors.Is(err, ErrUnknownWeightClass) {
+		t.Errorf("expected ErrUnknownWeightClass, got %v", err)
+	}
+
+	if !IsWeightClassName("93") || IsWeightClassName("94") {
+		t.Error("IsWeightClassName returned unexpected result")
+	}
+}
+
+func TestAgeOn(t *testing.T) {
+	birth := time.Date(1980, 6, 15, 0, 0, 0, 0, time.UTC)
+	if got := AgeOn(birth, time.Date(2020, 6, 14, 0, 0, 0, 0, time.UTC)); got != 39 {
+		t.Errorf("expected 39 the day before birthday, got %d", got)
+	}
+	if got := AgeOn(birth, time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC)); got != 40 {
+		t.Errorf("expected 40 on birthday, got %d", got)
+	}
+}
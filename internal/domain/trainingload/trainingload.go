@@ -0,0 +1,138 @@
+// Package trainingload provides domain logic for training load monitoring.
+// It defines the period bucketing used by analytics series, the thresholds for
+// hard sets and INOL (intensity x number of lifts), and the acute:chronic
+// workload ratio (ACWR).
+package trainingload
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Bucket identifies the period size used to group analytics series.
+type Bucket string
+
+const (
+	// BucketDay groups by calendar day.
+	BucketDay Bucket = "DAY"
+	// BucketWeek groups by ISO week (Monday start).
+	BucketWeek Bucket = "WEEK"
+	// BucketMonth groups by calendar month.
+	BucketMonth Bucket = "MONTH"
+)
+
+// ValidBuckets contains all valid buckets for validation.
+var ValidBuckets = map[Bucket]bool{
+	BucketDay:   true,
+	BucketWeek:  true,
+	BucketMonth: true,
+}
+
+const (
+	// HardSetMinRPE is the lowest RPE at which a set counts as a hard set.
+	HardSetMinRPE = 7.0
+	// HardSetMinIntensity is the lowest relative intensity (fraction of 1RM) at which
+	// a set without a recorded RPE counts as a hard set.
+	HardSetMinIntensity = 0.8
+	// MaxINOLIntensity caps relative intensity in the INOL formula so sets at or above
+	// 100% of the 1RM do not divide by zero.
+	MaxINOLIntensity = 0.99
+	// AcuteDays is the length of the acute workload window.
+	AcuteDays = 7
+	// ChronicDays is the length of the chronic workload window.
+	ChronicDays = 28
+	// MaxRangeDays is the longest date range an analytics query may cover.
+	MaxRangeDays = 731
+)
+
+// Validation errors
+var (
+	ErrInvalidBucket = errors.New("bucket must be DAY, WEEK, or MONTH")
+	ErrInvalidRange  = errors.New("start must be before end")
+	ErrRangeTooLong  = errors.New("date range must not exceed two years")
+)
+
+// ValidateRange checks that start is before end and the range is not too long.
+func ValidateRange(start, end time.Time) error {
+	if !start.Before(end) {
+		return ErrInvalidRange
+	}
+	if end.Sub(start) > MaxRangeDays*24*time.Hour {
+		return ErrRangeTooLong
+	}
+	return nil
+}
+
+// Day truncates t to midnight UTC.
+func Day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// PeriodStart returns the start of the bucket period containing t, in UTC.
+func PeriodStart(t time.Time, bucket Bucket) time.Time {
+	day := Day(t)
+	switch bucket {
+	case BucketWeek:
+		// time.Weekday has Sunday = 0; weeks start on Monday
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case BucketMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// NextPeriod returns the start of the period after the one starting at periodStart.
+func NextPeriod(periodStart time.Time, bucket Bucket) time.Time {
+	switch bucket {
+	case BucketWeek:
+		return periodStart.AddDate(0, 0, 7)
+	case BucketMonth:
+		return periodStart.AddDate(0, 1, 0)
+	default:
+		return periodStart.AddDate(0, 0, 1)
+	}
+}
+
+// Periods returns the start of every bucket period overlapping [start, end], oldest first.
+func Periods(start, end time.Time, bucket Bucket) []time.Time {
+	periods := []time.Time{}
+	last := PeriodStart(end, bucket)
+	for p := PeriodStart(start, bucket); !p.After(last); p = NextPeriod(p, bucket) {
+		periods = append(periods, p)
+	}
+	return periods
+}
+
+// INOL returns the INOL contribution of a set: reps / (100 - intensity%).
+// Intensity is the fraction of 1RM and is capped at MaxINOLIntensity.
+func INOL(reps int, intensity float64) float64 {
+	intensity = math.Min(intensity, MaxINOLIntensity)
+	return float64(reps) / (100 * (1 - intensity))
+}
+
+// WindowSum sums daily values for the days in the window of the given length ending on end (inclusive).
+func WindowSum(daily map[time.Time]float64, end time.Time, days int) float64 {
+	var sum float64
+	end = Day(end)
+	for i := 0; i < days; i++ {
+		sum += daily[end.AddDate(0, 0, -i)]
+	}
+	return sum
+}
+
+// ACWR returns the acute:chronic workload ratio for the windows ending on end:
+// the acute (7-day) load divided by the average weekly load over the chronic (28-day) window.
+// Returns nil when there is no chronic load.
+func ACWR(daily map[time.Time]float64, end time.Time) *float64 {
+	chronic := WindowSum(daily, end, ChronicDays)
+	if chronic <= 0 {
+		return nil
+	}
+	acute := WindowSum(daily, end, AcuteDays)
+	ratio := math.Round(acute/(chronic*AcuteDays/ChronicDays)*100) / 100
+	return &ratio
+}
@@ -0,0 +1,109 @@
+package trainingload
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPeriodStart(t *testing.T) {
+	// Wednesday 2024-01-17 afternoon
+	ts := time.Date(2024, 1, 17, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		bucket   Bucket
+		expected time.Time
+	}{
+		{BucketDay, date(2024, 1, 17)},
+		{BucketWeek, date(2024, 1, 15)},
+		{BucketMonth, date(2024, 1, 1)},
+	}
+
+	for _, tt := range tests {
+		if got := PeriodStart(ts, tt.bucket); !got.Equal(tt.expected) {
+			t.Errorf("PeriodStart(%s) = %s, expected %s", tt.bucket, got, tt.expected)
+		}
+	}
+
+	// Sunday belongs to the week that started the previous Monday
+	if got := PeriodStart(date(2024, 1, 21), BucketWeek); !got.Equal(date(2024, 1, 15)) {
+		t.Errorf("expected Sunday to map to Monday 2024-01-15, got %s", got)
+	}
+}
+
+func TestPeriods(t *testing.T) {
+	periods := Periods(date(2024, 1, 17), date(2024, 2, 5), BucketWeek)
+	expected := []time.Time{date(2024, 1, 15), date(2024, 1, 22), date(2024, 1, 29), date(2024, 2, 5)}
+	if len(periods) != len(expected) {
+		t.Fatalf("expected %d periods, got %d", len(expected), len(periods))
+	}
+	for i := range expected {
+		if !periods[i].Equal(expected[i]) {
+			t.Errorf("period %d: expected %s, got %s", i, expected[i], periods[i])
+		}
+	}
+
+	months := Periods(date(2024, 1, 31), date(2024, 3, 1), BucketMonth)
+	if len(months) != 3 {
+		t.Errorf("expected 3 monthly periods, got %d", len(months))
+	}
+}
+
+func TestValidateRange(t *testing.T) {
+	start := date(2024, 1, 1)
+
+	if err := ValidateRange(start, start.AddDate(0, 3, 0)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateRange(start, start); err != ErrInvalidRange {
+		t.Errorf("expected ErrInvalidRange, got %v", err)
+	}
+	if err := ValidateRange(start, start.AddDate(3, 0, 0)); err != ErrRangeTooLong {
+		t.Errorf("expected ErrRangeTooLong, got %v", err)
+	}
+}
+
+func TestINOL(t *testing.T) {
+	// 5 reps at 80%: 5 / 20 = 0.25
+	if got := INOL(5, 0.8); math.Abs(got-0.25) > 1e-9 {
+		t.Errorf("expected 0.25, got %f", got)
+	}
+	// Intensity above 1RM is capped: 1 / (100 * 0.01) = 1
+	if got := INOL(1, 1.05); math.Abs(got-1) > 1e-9 {
+		t.Errorf("expected capped INOL 1, got %f", got)
+	}
+}
+
+func TestACWR(t *testing.T) {
+	end := date(2024, 1, 28)
+
+	if ACWR(map[time.Time]float64{}, end) != nil {
+		t.Error("expected nil ACWR without chronic load")
+	}
+
+	// 1000 per week for four weeks → ratio 1.0
+	steady := map[time.Time]float64{}
+	for week := 0; week < 4; week++ {
+		steady[end.AddDate(0, 0, -7*week)] = 1000
+	}
+	if got := ACWR(steady, end); got == nil || *got != 1 {
+		t.Errorf("expected ACWR 1, got %v", got)
+	}
+
+	// Spike: 2500 this week after three weeks of 500 → 2500 / (4000/4) = 2.5
+	spike := map[time.Time]float64{
+		end:                    2500,
+		end.AddDate(0, 0, -7):  500,
+		end.AddDate(0, 0, -14): 500,
+		end.AddDate(0, 0, -21): 500,
+		// Outside the chronic window
+		end.AddDate(0, 0, -28): 10000,
+	}
+	if got := ACWR(spike, end); got == nil || *got != 2.5 {
+		t.Errorf("expected ACWR 2.5, got %v", got)
+	}
+}
@@ -28,12 +28,13 @@ const MaxNameLength = 100
 
 // WeeklyLookup represents a weekly lookup domain entity with all business rules.
 type WeeklyLookup struct {
-	ID        string
-	Name      string
-	Entries   []WeeklyLookupEntry
-	ProgramID *string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID             string
+	Name           string
+	Entries        []WeeklyLookupEntry
+	ProgramID      *string
+	OrganizationID *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 // WeeklyLookupEntry represents an entry in a weekly lookup table.
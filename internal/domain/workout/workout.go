@@ -7,10 +7,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/waynenilsen/power-pro-v3/internal/domain/loadstrategy"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/prescription"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/readiness"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/setscheme"
 )
 
@@ -39,6 +41,8 @@ type SetInfo struct {
 	Weight     float64 `json:"weight"`
 	TargetReps int     `json:"targetReps"`
 	IsWorkSet  bool    `json:"isWorkSet"`
+	// IsAMRAP marks as-many-reps-as-possible sets; TargetReps is their minimum.
+	IsAMRAP bool `json:"isAmrap,omitempty"`
 }
 
 // ExerciseInfo represents a resolved exercise in a workout.
@@ -59,6 +63,8 @@ type Workout struct {
 	DaySlug        string         `json:"daySlug"`
 	Date           string         `json:"date"`
 	Exercises      []ExerciseInfo `json:"exercises"`
+	// Readiness is the adjustment from the day's readiness check-in, if any.
+	Readiness *readiness.Adjustment `json:"readiness,omitempty"`
 }
 
 // GenerationParams contains parameters for generating a workout.
@@ -110,6 +116,11 @@ type GenerationContext struct {
 
 	// LookupContext provides week/day context for lookup-based load modifications.
 	LookupContext *loadstrategy.LookupContext
+
+	// Readiness is the adjustment from the lifter's readiness check-in. Its load modifier
+	// must also be set on LookupContext; its volume modifier is applied to the generated sets.
+	// Optional: if nil, the workout is generated as prescribed.
+	Readiness *readiness.Adjustment
 }
 
 // DefaultGenerationContext returns a GenerationContext with default values.
@@ -137,6 +148,7 @@ func DefaultGenerationContext(liftLookup prescription.LiftLookup) GenerationCont
 //   - LiftLookup: Provides lift names/slugs for display
 //   - SetGenContext: Configuration like work set threshold (default 80%)
 //   - LookupContext: Week/day-specific modifiers for periodization
+//   - Readiness: Daily check-in adjustment (volume is trimmed after resolution)
 //
 // If any prescription fails to resolve (e.g., missing max value), the entire workout
 // generation fails. This is intentional - partial workouts could lead to imbalanced training.
@@ -170,6 +182,11 @@ func GenerateWorkout(
 			return nil, fmt.Errorf("failed to resolve prescription %s: %w", p.ID, err)
 		}
 
+		sets := convertSets(resolved.Sets)
+		if genCtx.Readiness != nil {
+			sets = ApplyVolumeModifier(sets, genCtx.Readiness.VolumeModifier)
+		}
+
 		exercise := ExerciseInfo{
 			PrescriptionID: resolved.PrescriptionID,
 			Lift: LiftInfo{
@@ -177,7 +194,7 @@ func GenerateWorkout(
 				Name: resolved.Lift.Name,
 				Slug: resolved.Lift.Slug,
 			},
-			Sets:        convertSets(resolved.Sets),
+			Sets:        sets,
 			Notes:       resolved.Notes,
 			RestSeconds: resolved.RestSeconds,
 		}
@@ -193,9 +210,55 @@ func GenerateWorkout(
 		DaySlug:        dayCtx.DaySlug,
 		Date:           date,
 		Exercises:      exercises,
+		Readiness:      genCtx.Readiness,
 	}, nil
 }
 
+// ApplyVolumeModifier keeps the given percentage of an exercise's work sets, rounded up
+// so at least one work set remains. Straight work sets are dropped from the end first so
+// AMRAP sets (such as a 5/3/1 top set) survive as long as possible; warmup sets are always
+// kept. A modifier of 100 or more returns the sets unchanged.
+func ApplyVolumeModifier(sets []SetInfo, volumeModifier float64) []SetInfo {
+	if volumeModifier >= 100 || volumeModifier <= 0 {
+		return sets
+	}
+
+	workSets := 0
+	for _, s := range sets {
+		if s.IsWorkSet {
+			workSets++
+		}
+	}
+	if workSets == 0 {
+		return sets
+	}
+
+	keep := int(math.Ceil(float64(workSets) * volumeModifier / 100))
+	if keep < 1 {
+		keep = 1
+	}
+
+	// Mark sets to drop from the end: non-AMRAP work sets first, then AMRAP sets
+	drop := make([]bool, len(sets))
+	remaining := workSets - keep
+	for _, amrap := range []bool{false, true} {
+		for i := len(sets) - 1; i >= 0 && remaining > 0; i-- {
+			if sets[i].IsWorkSet && sets[i].IsAMRAP == amrap {
+				drop[i] = true
+				remaining--
+			}
+		}
+	}
+
+	result := make([]SetInfo, 0, len(sets))
+	for i, s := range sets {
+		if !drop[i] {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
 // convertSets converts generated sets to set info.
 func convertSets(sets []setscheme.GeneratedSet) []SetInfo {
 	result := make([]SetInfo, len(sets))
@@ -205,6 +268,7 @@ func convertSets(sets []setscheme.GeneratedSet) []SetInfo {
 			Weight:     s.Weight,
 			TargetReps: s.TargetReps,
 			IsWorkSet:  s.IsWorkSet,
+			IsAMRAP:    s.IsAMRAP,
 		}
 	}
 	return result
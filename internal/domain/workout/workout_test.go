@@ -685,6 +685,89 @@ func TestConvertSets_Empty(t *testing.T) {
 	}
 }
 
+// ==================== ApplyVolumeModifier Tests ====================
+
+func TestApplyVolumeModifier(t *testing.T) {
+	sets := []SetInfo{
+		{SetNumber: 1, Weight: 135, TargetReps: 5, IsWorkSet: false},
+		{SetNumber: 2, Weight: 225, TargetReps: 5, IsWorkSet: true},
+		{SetNumber: 3, Weight: 225, TargetReps: 5, IsWorkSet: true},
+		{SetNumber: 4, Weight: 225, TargetReps: 5, IsWorkSet: true},
+		{SetNumber: 5, Weight: 225, TargetReps: 5, IsWorkSet: true},
+		{SetNumber: 6, Weight: 225, TargetReps: 5, IsWorkSet: true},
+	}
+
+	tests := []struct {
+		name           string
+		modifier       float64
+		expectedLength int
+	}{
+		{"full volume", 100, 6},
+		{"80% of 5 work sets keeps 4", 80, 5},
+		{"50% rounds up to 3", 50, 4},
+		{"always keeps one work set", 10, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ApplyVolumeModifier(sets, tt.modifier)
+			if len(result) != tt.expectedLength {
+				t.Fatalf("len(result) = %d, want %d", len(result), tt.expectedLength)
+			}
+			// The warmup set is always kept and work sets are dropped from the end
+			if result[0].IsWorkSet {
+				t.Error("expected warmup set to be kept")
+			}
+			if last := result[len(result)-1]; last.SetNumber != tt.expectedLength {
+				t.Errorf("last set number = %d, want %d", last.SetNumber, tt.expectedLength)
+			}
+		})
+	}
+}
+
+func TestApplyVolumeModifier_KeepsAMRAPSets(t *testing.T) {
+	// A 5/3/1 week: warmup, two straight work sets, then the AMRAP top set
+	sets := []SetInfo{
+		{SetNumber: 1, Weight: 135, TargetReps: 5, IsWorkSet: false},
+		{SetNumber: 2, Weight: 195, TargetReps: 5, IsWorkSet: true},
+		{SetNumber: 3, Weight: 225, TargetReps: 5, IsWorkSet: true},
+		{SetNumber: 4, Weight: 255, TargetReps: 5, IsWorkSet: true, IsAMRAP: true},
+	}
+
+	tests := []struct {
+		name     string
+		modifier float64
+		expected []int
+	}{
+		{"drops the straight set before the AMRAP set", 60, []int{1, 2, 4}},
+		{"keeps only the AMRAP set", 10, []int{1, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ApplyVolumeModifier(sets, tt.modifier)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("len(result) = %d, want %d", len(result), len(tt.expected))
+			}
+			for i, setNumber := range tt.expected {
+				if result[i].SetNumber != setNumber {
+					t.Errorf("result[%d].SetNumber = %d, want %d", i, result[i].SetNumber, setNumber)
+				}
+			}
+		})
+	}
+
+	// With several AMRAP sets and no straight sets left, AMRAP sets go from the end
+	allAMRAP := []SetInfo{
+		{SetNumber: 1, Weight: 225, TargetReps: 5, IsWorkSet: true, IsAMRAP: true},
+		{SetNumber: 2, Weight: 225, TargetReps: 5, IsWorkSet: true, IsAMRAP: true},
+	}
+	result := ApplyVolumeModifier(allAMRAP, 50)
+	if len(result) != 1 || result[0].SetNumber != 1 {
+		t.Errorf("expected only the first AMRAP set to remain, got %+v", result)
+	}
+}
+
 // ==================== GetDateString Tests ====================
 
 func TestGetDateString(t *testing.T) {
@@ -6,6 +6,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // Standard error categories for HTTP status code mapping.
@@ -31,6 +32,9 @@ var (
 
 	// ErrBadRequest indicates a malformed request.
 	ErrBadRequest = errors.New("bad request")
+
+	// ErrTooManyRequests indicates the client must wait before trying again.
+	ErrTooManyRequests = errors.New("too many requests")
 )
 
 // DomainError represents a domain-specific error with context.
@@ -44,6 +48,8 @@ type DomainError struct {
 	Field string
 	// Cause is the underlying error, if any.
 	Cause error
+	// RetryAfter is how long to wait before trying again, for too many requests errors.
+	RetryAfter time.Duration
 }
 
 // Error implements the error interface.
@@ -133,6 +139,15 @@ func NewBadRequest(message string) *DomainError {
 	}
 }
 
+// NewTooManyRequests creates an error telling the client to wait before trying again.
+func NewTooManyRequests(message string, retryAfter time.Duration) *DomainError {
+	return &DomainError{
+		Category:   ErrTooManyRequests,
+		Message:    message,
+		RetryAfter: retryAfter,
+	}
+}
+
 // ErrInvalidParameter creates an error for an invalid query parameter.
 func ErrInvalidParameter(param, reason string) error {
 	return fmt.Errorf("invalid parameter %s: %s", param, reason)
@@ -195,6 +210,20 @@ func IsBadRequest(err error) bool {
 	return errors.Is(err, ErrBadRequest)
 }
 
+// IsTooManyRequests checks if an error is a too many requests error.
+func IsTooManyRequests(err error) bool {
+	return errors.Is(err, ErrTooManyRequests)
+}
+
+// GetRetryAfter extracts how long to wait before trying again from an error, or 0.
+func GetRetryAfter(err error) time.Duration {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.RetryAfter
+	}
+	return 0
+}
+
 // GetCategory extracts the error category from an error.
 // Returns ErrInternal if the error is not a DomainError.
 func GetCategory(err error) error {
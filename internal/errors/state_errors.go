@@ -47,6 +47,8 @@ const (
 	CodeInvalidEnrollmentState   = "invalid_enrollment_state"
 	CodeSessionNotActive         = "session_not_active"
 	CodeNotEnrolled              = "not_enrolled"
+	CodeSessionRequiresAmendment = "session_requires_amendment"
+	CodeSessionNotEditable       = "session_not_editable"
 )
 
 // NewWorkoutAlreadyInProgress creates an error for when a workout is already in progress.
@@ -94,6 +96,30 @@ func NewSessionNotActive(sessionStatus string) *StateError {
 	}
 }
 
+// NewSessionRequiresAmendment creates an error for direct set edits on a completed session.
+func NewSessionRequiresAmendment(sessionID string) *StateError {
+	return &StateError{
+		Category: ErrConflict,
+		Code:     CodeSessionRequiresAmendment,
+		Message:  "Sets in a completed session can only be changed by amending the session",
+		Details: map[string]interface{}{
+			"session_id": sessionID,
+		},
+	}
+}
+
+// NewSessionNotEditable creates an error for set changes on an abandoned session.
+func NewSessionNotEditable(sessionStatus string) *StateError {
+	return &StateError{
+		Category: ErrBadRequest,
+		Code:     CodeSessionNotEditable,
+		Message:  "Cannot change sets in a session that was abandoned",
+		Details: map[string]interface{}{
+			"session_status": sessionStatus,
+		},
+	}
+}
+
 // NewNotEnrolled creates an error for when a user is not enrolled in a program.
 func NewNotEnrolled() *StateError {
 	return &StateError{
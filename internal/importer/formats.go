@@ -0,0 +1,407 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// Supported source formats.
+const (
+	FormatStrong   = "STRONG"
+	FormatHevy     = "HEVY"
+	FormatFitNotes = "FITNOTES"
+	FormatGeneric  = "GENERIC"
+)
+
+// Weight units.
+const (
+	WeightUnitLb = "lb"
+	WeightUnitKg = "kg"
+)
+
+// dateLayouts are the timestamp layouts tried when a format does not fix one. Timestamps
+// without a zone are read as UTC.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2 Jan 2006, 15:04",
+	"2 Jan 2006 15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+}
+
+// Columns maps the columns of a generic CSV file to set fields by header name.
+// Date, Exercise, Weight and Reps are required; the others are optional.
+type Columns struct {
+	Date     string `json:"date"`
+	Exercise string `json:"exercise"`
+	Weight   string `json:"weight"`
+	Reps     string `json:"reps"`
+	// Workout names the workout. Sets with the same date and workout form one session.
+	Workout string `json:"workout"`
+	// Unit holds the weight unit of each set ("lb", "lbs", "kg" or "kgs").
+	Unit string `json:"unit"`
+	RPE  string `json:"rpe"`
+	// SetType marks warm-up sets ("w", "warmup" or "warm-up"), which are skipped.
+	SetType string `json:"setType"`
+	// DateLayout is a Go time layout for Date, for files whose dates are ambiguous.
+	DateLayout string `json:"dateLayout"`
+}
+
+// Row is one set read from a source file. Weight is in Unit, or in the import's default
+// unit when the file does not say.
+type Row struct {
+	Line       int
+	Workout    string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	Exercise   string
+	Weight     float64
+	Unit       string
+	Reps       int
+	RPE        *float64
+}
+
+// parseResult is the outcome of reading a source file.
+type parseResult struct {
+	rows []Row
+	// warmups and empty count skipped warm-up sets and sets without weight or reps.
+	warmups  int
+	empty    int
+	warnings []string
+}
+
+// columnLayout locates set fields in a file by column index; -1 means absent.
+type columnLayout struct {
+	date, finished, duration, workout, exercise, setType, weight, unit, reps, rpe int
+	// weightUnit is the unit named by the weight column's header, if any.
+	weightUnit string
+	dateLayout string
+	// finishedIsDate tells whether finished holds a timestamp rather than nothing.
+	finishedIsDate bool
+}
+
+// header finds columns in a CSV header by name, ignoring case and surrounding space.
+type header map[string]int
+
+func newHeader(record []string) header {
+	h := header{}
+	for i, name := range record {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := h[name]; !ok {
+			h[name] = i
+		}
+	}
+	return h
+}
+
+// find returns the index of the first of names present in the header, or -1.
+func (h header) find(names ...string) int {
+	for _, name := range names {
+		if i, ok := h[strings.ToLower(strings.TrimSpace(name))]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// require is find for mandatory columns.
+func (h header) require(format string, names ...string) (int, error) {
+	i := h.find(names...)
+	if i < 0 {
+		return -1, apperrors.NewValidation("csv", fmt.Sprintf("missing column %q for %s files", names[0], format))
+	}
+	return i, nil
+}
+
+// parse reads the sets in a CSV file of the given format.
+func parse(format, data string, columns *Columns) (*parseResult, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	first, err := reader.Read()
+	if err == io.EOF {
+		return nil, apperrors.NewValidation("csv", "csv is empty")
+	}
+	if err != nil {
+		return nil, apperrors.NewValidation("csv", fmt.Sprintf("invalid csv: %v", err))
+	}
+
+	layout, err := newColumnLayout(format, newHeader(first), columns)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &parseResult{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, apperrors.NewValidation("csv", fmt.Sprintf("invalid csv: %v", err))
+		}
+		if len(result.rows) >= maxRows {
+			return nil, apperrors.NewValidation("csv", fmt.Sprintf("csv must have at most %d sets", maxRows))
+		}
+		line, _ := reader.FieldPos(0)
+		result.read(layout, record, line)
+	}
+	return result, nil
+}
+
+func newColumnLayout(format string, h header, columns *Columns) (*columnLayout, error) {
+	l := &columnLayout{finished: -1, duration: -1, workout: -1, setType: -1, unit: -1, rpe: -1}
+	var err error
+	switch format {
+	case FormatStrong:
+		if l.date, err = h.require(format, "Date"); err != nil {
+			return nil, err
+		}
+		if l.exercise, err = h.require(format, "Exercise Name"); err != nil {
+			return nil, err
+		}
+		if l.reps, err = h.require(format, "Reps"); err != nil {
+			return nil, err
+		}
+		if l.weight, err = h.require(format, "Weight", "Weight (kg)", "Weight (lbs)"); err != nil {
+			return nil, err
+		}
+		l.workout = h.find("Workout Name")
+		l.duration = h.find("Duration")
+		l.setType = h.find("Set Order")
+		l.unit = h.find("Weight Unit")
+		l.rpe = h.find("RPE")
+	case FormatHevy:
+		if l.date, err = h.require(format, "start_time"); err != nil {
+			return nil, err
+		}
+		if l.exercise, err = h.require(format, "exercise_title"); err != nil {
+			return nil, err
+		}
+		if l.reps, err = h.require(format, "reps"); err != nil {
+			return nil, err
+		}
+		if l.weight, err = h.require(format, "weight_lbs", "weight_kg"); err != nil {
+			return nil, err
+		}
+		l.workout = h.find("title")
+		l.finished = h.find("end_time")
+		l.finishedIsDate = l.finished >= 0
+		l.setType = h.find("set_type")
+		l.rpe = h.find("rpe")
+	case FormatFitNotes:
+		if l.date, err = h.require(format, "Date"); err != nil {
+			return nil, err
+		}
+		if l.exercise, err = h.require(format, "Exercise"); err != nil {
+			return nil, err
+		}
+		if l.reps, err = h.require(format, "Reps"); err != nil {
+			return nil, err
+		}
+		if l.weight, err = h.require(format, "Weight (kgs)", "Weight (lbs)", "Weight"); err != nil {
+			return nil, err
+		}
+		l.unit = h.find("Weight Unit")
+	case FormatGeneric:
+		if columns == nil || columns.Date == "" || columns.Exercise == "" || columns.Weight == "" || columns.Reps == "" {
+			return nil, apperrors.NewValidation("columns", "columns must name the date, exercise, weight and reps columns")
+		}
+		if l.date, err = h.require(format, columns.Date); err != nil {
+			return nil, err
+		}
+		if l.exercise, err = h.require(format, columns.Exercise); err != nil {
+			return nil, err
+		}
+		if l.weight, err = h.require(format, columns.Weight); err != nil {
+			return nil, err
+		}
+		if l.reps, err = h.require(format, columns.Reps); err != nil {
+			return nil, err
+		}
+		for _, optional := range []struct {
+			name  string
+			index *int
+		}{
+			{columns.Workout, &l.workout},
+			{columns.Unit, &l.unit},
+			{columns.RPE, &l.rpe},
+			{columns.SetType, &l.setType},
+		} {
+			if optional.name == "" {
+				continue
+			}
+			if *optional.index, err = h.require(format, optional.name); err != nil {
+				return nil, err
+			}
+		}
+		l.dateLayout = columns.DateLayout
+	default:
+		return nil, apperrors.NewValidation("format", "format must be one of STRONG, HEVY, FITNOTES, GENERIC")
+	}
+
+	for name, i := range h {
+		if i == l.weight {
+			l.weightUnit = unitInHeader(name)
+		}
+	}
+	return l, nil
+}
+
+// read adds one CSV record to the result, or records why it was skipped.
+func (p *parseResult) read(l *columnLayout, record []string, line int) {
+	field := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	exercise := field(l.exercise)
+	if exercise == "" {
+		p.warn(line, "missing exercise name")
+		return
+	}
+	if isWarmup(field(l.setType)) {
+		p.warmups++
+		return
+	}
+
+	startedAt, err := parseTime(field(l.date), l.dateLayout)
+	if err != nil {
+		p.warn(line, fmt.Sprintf("invalid date %q", field(l.date)))
+		return
+	}
+
+	weightText, repsText := field(l.weight), field(l.reps)
+	if weightText == "" || repsText == "" {
+		p.empty++
+		return
+	}
+	weight, err := parseNumber(weightText)
+	if err != nil {
+		p.warn(line, fmt.Sprintf("invalid weight %q", weightText))
+		return
+	}
+	reps, err := parseNumber(repsText)
+	if err != nil || reps != float64(int(reps)) {
+		p.warn(line, fmt.Sprintf("invalid reps %q", repsText))
+		return
+	}
+	if weight <= 0 || reps <= 0 {
+		p.empty++
+		return
+	}
+
+	unit := l.weightUnit
+	if l.unit >= 0 && field(l.unit) != "" {
+		unit = parseUnit(field(l.unit))
+		if unit == "" {
+			p.warn(line, fmt.Sprintf("invalid weight unit %q", field(l.unit)))
+			return
+		}
+	}
+
+	row := Row{
+		Line:      line,
+		Workout:   field(l.workout),
+		StartedAt: startedAt,
+		Exercise:  exercise,
+		Weight:    weight,
+		Unit:      unit,
+		Reps:      int(reps),
+	}
+	if l.finishedIsDate {
+		if finishedAt, err := parseTime(field(l.finished), l.dateLayout); err == nil {
+			row.FinishedAt = &finishedAt
+		}
+	} else if d, ok := parseDuration(field(l.duration)); ok {
+		finishedAt := startedAt.Add(d)
+		row.FinishedAt = &finishedAt
+	}
+	if rpe, err := parseNumber(field(l.rpe)); err == nil && rpe >= 1 && rpe <= 10 {
+		row.RPE = &rpe
+	}
+	p.rows = append(p.rows, row)
+}
+
+// warn records a skipped line. Only the first maxWarnings lines are described.
+func (p *parseResult) warn(line int, reason string) {
+	if len(p.warnings) < maxWarnings {
+		p.warnings = append(p.warnings, fmt.Sprintf("line %d skipped: %s", line, reason))
+	}
+}
+
+func parseTime(s, layout string) (time.Time, error) {
+	if layout != "" {
+		return time.Parse(layout, s)
+	}
+	for _, l := range dateLayouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", s)
+}
+
+// parseNumber reads a number, accepting a decimal comma.
+func parseNumber(s string) (float64, error) {
+	if !strings.Contains(s, ".") {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// parseDuration reads durations such as "1h 5m", "45m" or "50s", as written by Strong.
+func parseDuration(s string) (time.Duration, bool) {
+	s = strings.ReplaceAll(s, " ", "")
+	if s == "" {
+		return 0, false
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+func parseUnit(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "lb", "lbs":
+		return WeightUnitLb
+	case "kg", "kgs":
+		return WeightUnitKg
+	}
+	return ""
+}
+
+// unitInHeader returns the unit named by a weight column header such as "Weight (kgs)"
+// or "weight_lbs", or "" when it names none.
+func unitInHeader(name string) string {
+	for _, suffix := range []string{"lbs", "lb", "kgs", "kg"} {
+		if strings.HasSuffix(strings.TrimSuffix(name, ")"), suffix) {
+			return parseUnit(suffix)
+		}
+	}
+	return ""
+}
+
+func isWarmup(setType string) bool {
+	switch strings.ToLower(setType) {
+	case "w", "warmup", "warm-up", "warm up":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Exercise match statuses.
+const (
+	// MatchStatusMatched means the name matched a lift by alias, name or slug.
+	MatchStatusMatched = "MATCHED"
+	// MatchStatusConfirmed means the request mapped the name to a lift.
+	MatchStatusConfirmed = "CONFIRMED"
+	// MatchStatusSkipped means the request mapped the name to no lift; its sets are skipped.
+	MatchStatusSkipped = "SKIPPED"
+	// MatchStatusNeedsConfirmation means the name has no certain match. The import
+	// proceeds only once the request maps or skips it.
+	MatchStatusNeedsConfirmation = "NEEDS_CONFIRMATION"
+)
+
+const (
+	// minSuggestionScore is the lowest similarity for a lift to be suggested.
+	minSuggestionScore = 0.5
+	// maxSuggestions is the number of lifts suggested for an unmatched name.
+	maxSuggestions = 3
+)
+
+// Lift is a lift an exercise can be matched to.
+type Lift struct {
+	ID   string
+	Name string
+	Slug string
+}
+
+// Alias maps a normalized exercise name to a lift. UserID is nil for built-in aliases.
+type Alias struct {
+	LiftID string
+	UserID *string
+	Alias  string
+}
+
+// Suggestion is a lift that may be meant by an unmatched exercise name.
+type Suggestion struct {
+	LiftID   string  `json:"liftId"`
+	LiftName string  `json:"liftName"`
+	LiftSlug string  `json:"liftSlug"`
+	Score    float64 `json:"score"`
+}
+
+// matcher matches exercise names from other apps to lifts.
+type matcher struct {
+	lifts []Lift
+	byID  map[string]Lift
+	// certain maps normalized names to the lift they certainly mean: the user's aliases,
+	// then built-in aliases, then lift names and slugs.
+	certain map[string]string
+	// names holds the normalized names of each lift, for fuzzy matching.
+	names map[string][]string
+}
+
+func newMatcher(lifts []Lift, aliases []Alias) *matcher {
+	m := &matcher{
+		lifts:   lifts,
+		byID:    map[string]Lift{},
+		certain: map[string]string{},
+		names:   map[string][]string{},
+	}
+	for _, l := range lifts {
+		m.byID[l.ID] = l
+		for _, name := range []string{normalizeName(l.Name), normalizeName(l.Slug)} {
+			m.addCertain(name, l.ID)
+			m.names[l.ID] = append(m.names[l.ID], name)
+		}
+	}
+	// User aliases take precedence over built-in ones, which take precedence over names
+	for _, userAliases := range []bool{false, true} {
+		for _, a := range aliases {
+			if (a.UserID != nil) != userAliases {
+				continue
+			}
+			if _, ok := m.byID[a.LiftID]; !ok {
+				continue
+			}
+			m.certain[a.Alias] = a.LiftID
+			if !userAliases {
+				m.names[a.LiftID] = append(m.names[a.LiftID], a.Alias)
+			}
+		}
+	}
+	return m
+}
+
+func (m *matcher) addCertain(name, liftID string) {
+	if _, ok := m.certain[name]; !ok {
+		m.certain[name] = liftID
+	}
+}
+
+// match returns the lift an exercise name certainly means, or suggestions when it has none.
+func (m *matcher) match(name string) (*Lift, []Suggestion) {
+	normalized := normalizeName(name)
+	if id, ok := m.certain[normalized]; ok {
+		lift := m.byID[id]
+		return &lift, nil
+	}
+
+	var suggestions []Suggestion
+	for _, l := range m.lifts {
+		best := 0.0
+		for _, candidate := range m.names[l.ID] {
+			if score := similarity(normalized, candidate); score > best {
+				best = score
+			}
+		}
+		if best >= 1 {
+			// Same words up to order and plurals
+			lift := l
+			return &lift, nil
+		}
+		if best >= minSuggestionScore {
+			suggestions = append(suggestions, Suggestion{LiftID: l.ID, LiftName: l.Name, LiftSlug: l.Slug, Score: roundScore(best)})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].LiftName < suggestions[j].LiftName
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return nil, suggestions
+}
+
+// resolve finds a visible lift by ID or slug.
+func (m *matcher) resolve(idOrSlug string) *Lift {
+	if l, ok := m.byID[idOrSlug]; ok {
+		return &l
+	}
+	for _, l := range m.lifts {
+		if l.Slug == idOrSlug {
+			lift := l
+			return &lift
+		}
+	}
+	return nil
+}
+
+// normalizeName lower-cases a name and reduces it to words separated by single spaces,
+// so that "Bench Press (Barbell)" becomes "bench press barbell".
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// similarity is the Dice coefficient of the word sets of two normalized names, with
+// plurals folded: 1 for the same words, 0 for no words in common.
+func similarity(a, b string) float64 {
+	wordsA, wordsB := wordSet(a), wordSet(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+	common := 0
+	for w := range wordsA {
+		if wordsB[w] {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(wordsA)+len(wordsB))
+}
+
+func wordSet(name string) map[string]bool {
+	words := map[string]bool{}
+	for _, w := range strings.Fields(name) {
+		if len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+			w = strings.TrimSuffix(w, "s")
+		}
+		words[w] = true
+	}
+	return words
+}
+
+func roundScore(score float64) float64 {
+	return float64(int(score*100+0.5)) / 100
+}
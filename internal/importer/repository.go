@@ -0,0 +1,224 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// RecordRebuilder rebuilds a user's personal records within a transaction.
+type RecordRebuilder interface {
+	RebuildRecordsTx(ctx context.Context, tx *sql.Tx, userID string) error
+}
+
+// SQLiteRepository implements Repository using SQLite.
+type SQLiteRepository struct {
+	db      *sql.DB
+	records RecordRebuilder
+}
+
+// NewSQLiteRepository creates a new SQLite-backed import repository. Imported sets are
+// older than the sets already logged, so records rebuilds personal records after saving.
+func NewSQLiteRepository(db *sql.DB, records RecordRebuilder) *SQLiteRepository {
+	return &SQLiteRepository{db: db, records: records}
+}
+
+// ListLifts returns the global lifts and the lifts of the user's organizations.
+func (r *SQLiteRepository) ListLifts(ctx context.Context, userID string) ([]Lift, error) {
+	var lifts []Lift
+	err := r.query(ctx, "lifts", func(rows *sql.Rows) error {
+		var l Lift
+		if err := rows.Scan(&l.ID, &l.Name, &l.Slug); err != nil {
+			return err
+		}
+		lifts = append(lifts, l)
+		return nil
+	}, `
+		SELECT id, name, slug FROM lifts
+		WHERE organization_id IS NULL
+			OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ?)
+		ORDER BY name, id
+	`, userID)
+	return lifts, err
+}
+
+// ListAliases returns the built-in aliases and the user's own.
+func (r *SQLiteRepository) ListAliases(ctx context.Context, userID string) ([]Alias, error) {
+	var aliases []Alias
+	err := r.query(ctx, "lift aliases", func(rows *sql.Rows) error {
+		var a Alias
+		var aliasUserID sql.NullString
+		if err := rows.Scan(&a.LiftID, &aliasUserID, &a.Alias); err != nil {
+			return err
+		}
+		if aliasUserID.Valid {
+			a.UserID = &aliasUserID.String
+		}
+		aliases = append(aliases, a)
+		return nil
+	}, `SELECT lift_id, user_id, alias FROM lift_aliases WHERE user_id IS NULL OR user_id = ? ORDER BY alias`, userID)
+	return aliases, err
+}
+
+// ListSourceKeys returns the source keys of the user's imported sessions.
+func (r *SQLiteRepository) ListSourceKeys(ctx context.Context, userID string) (map[string]bool, error) {
+	keys := map[string]bool{}
+	err := r.query(ctx, "imported sessions", func(rows *sql.Rows) error {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return err
+		}
+		keys[key] = true
+		return nil
+	}, `SELECT source_key FROM imported_sessions WHERE user_id = ?`, userID)
+	return keys, err
+}
+
+// ListLiftsWithOneRM returns the IDs of lifts the user has a one-rep max for.
+func (r *SQLiteRepository) ListLiftsWithOneRM(ctx context.Context, userID string) (map[string]bool, error) {
+	lifts := map[string]bool{}
+	err := r.query(ctx, "lift maxes", func(rows *sql.Rows) error {
+		var liftID string
+		if err := rows.Scan(&liftID); err != nil {
+			return err
+		}
+		lifts[liftID] = true
+		return nil
+	}, `SELECT DISTINCT lift_id FROM lift_maxes WHERE user_id = ? AND type = 'ONE_RM'`, userID)
+	return lifts, err
+}
+
+// Save stores the plan and rebuilds the user's personal records in one transaction.
+func (r *SQLiteRepository) Save(ctx context.Context, plan *Plan) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return apperrors.NewInternal("failed to begin transaction", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, a := range plan.Aliases {
+		if _, err = tx.ExecContext(ctx, `DELETE FROM lift_aliases WHERE user_id = ? AND alias = ?`, a.UserID, a.Alias); err != nil {
+			return apperrors.NewInternal("failed to replace lift alias", err)
+		}
+		if _, err = tx.ExecContext(ctx, `
+			INSERT INTO lift_aliases (id, lift_id, user_id, alias, created_at) VALUES (?, ?, ?, ?, ?)
+		`, uuid.New().String(), a.LiftID, a.UserID, a.Alias, now); err != nil {
+			return apperrors.NewInternal("failed to create lift alias", err)
+		}
+	}
+
+	if len(plan.Sessions) == 0 {
+		if err = tx.Commit(); err != nil {
+			return apperrors.NewInternal("failed to commit transaction", err)
+		}
+		return nil
+	}
+
+	imp := plan.Import
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO training_imports (id, user_id, format, session_count, set_count, max_count, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, imp.ID, imp.UserID, imp.Format, imp.SessionCount, imp.SetCount, imp.MaxCount, imp.CreatedAt.Format(time.RFC3339)); err != nil {
+		return apperrors.NewInternal("failed to create import", err)
+	}
+
+	for _, s := range plan.Sessions {
+		var finishedAt sql.NullString
+		if s.FinishedAt != nil {
+			finishedAt = sql.NullString{String: s.FinishedAt.UTC().Format(time.RFC3339), Valid: true}
+		}
+		startedAt := s.StartedAt.UTC().Format(time.RFC3339)
+		var res sql.Result
+		res, err = tx.ExecContext(ctx, `
+			INSERT INTO imported_sessions (id, import_id, user_id, source_key, name, started_at, finished_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (user_id, source_key) DO NOTHING
+		`, s.ID, imp.ID, imp.UserID, s.SourceKey, s.Name, startedAt, finishedAt)
+		if err != nil {
+			return apperrors.NewInternal("failed to create imported session", err)
+		}
+		var affected int64
+		if affected, err = res.RowsAffected(); err != nil {
+			return apperrors.NewInternal("failed to create imported session", err)
+		}
+		if affected == 0 {
+			err = apperrors.NewConflict("another import added some of these workouts; run the import again")
+			return err
+		}
+
+		for _, set := range s.Sets {
+			if _, err = tx.ExecContext(ctx, `
+				INSERT INTO logged_sets (id, user_id, session_id, prescription_id, lift_id, set_number, weight,
+					target_reps, reps_performed, is_amrap, rpe, created_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?)
+			`, set.ID, imp.UserID, s.ID, ImportedPrescriptionPrefix+set.LiftID, set.LiftID, set.SetNumber, set.Weight,
+				set.Reps, set.Reps, set.RPE, startedAt); err != nil {
+				return apperrors.NewInternal("failed to create logged set", err)
+			}
+		}
+	}
+
+	for _, m := range plan.Maxes {
+		if _, err = tx.ExecContext(ctx, `
+			INSERT INTO lift_maxes (id, user_id, lift_id, type, value, effective_date, created_at, updated_at)
+			VALUES (?, ?, ?, 'ONE_RM', ?, ?, ?, ?)
+			ON CONFLICT (user_id, lift_id, type, effective_date) DO NOTHING
+		`, uuid.New().String(), imp.UserID, m.LiftID, m.Value, m.EffectiveDate.UTC().Format(time.RFC3339), now, now); err != nil {
+			return apperrors.NewInternal("failed to create lift max", err)
+		}
+	}
+
+	if err = r.records.RebuildRecordsTx(ctx, tx, imp.UserID); err != nil {
+		return apperrors.NewInternal("failed to rebuild personal records", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return apperrors.NewInternal("failed to commit transaction", err)
+	}
+	return nil
+}
+
+// ListImports returns the user's imports, newest first.
+func (r *SQLiteRepository) ListImports(ctx context.Context, userID string) ([]Import, error) {
+	imports := []Import{}
+	err := r.query(ctx, "imports", func(rows *sql.Rows) error {
+		var imp Import
+		var createdAt string
+		if err := rows.Scan(&imp.ID, &imp.UserID, &imp.Format, &imp.SessionCount, &imp.SetCount, &imp.MaxCount, &createdAt); err != nil {
+			return err
+		}
+		imp.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		imports = append(imports, imp)
+		return nil
+	}, `
+		SELECT id, user_id, format, session_count, set_count, max_count, created_at
+		FROM training_imports WHERE user_id = ?
+		ORDER BY created_at DESC, id
+	`, userID)
+	return imports, err
+}
+
+// query runs a query and scans every row, wrapping failures as internal errors.
+func (r *SQLiteRepository) query(ctx context.Context, what string, scan func(*sql.Rows) error, query string, args ...interface{}) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return apperrors.NewInternal("failed to list "+what, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return apperrors.NewInternal("failed to scan "+what, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return apperrors.NewInternal("failed to list "+what, err)
+	}
+	return nil
+}
@@ -0,0 +1,498 @@
+// Package importer imports training history exported by other lifting apps.
+// It reads CSV exports from Strong, Hevy and FitNotes, or any CSV file with mapped columns,
+// matches their exercise names to lifts, and records the workouts as historical sessions
+// with logged sets. Imports can be previewed with a dry run, and importing the same file
+// again skips the workouts it already created.
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/personalrecord"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/strengthscore"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/profile"
+)
+
+const (
+	// maxRows is the largest number of sets one import reads.
+	maxRows = 50000
+	// maxWarnings is the number of skipped lines described in a report.
+	maxWarnings = 50
+	// ImportedPrescriptionPrefix prefixes the prescription ID of imported sets, which are
+	// not prescribed by a program. The lift ID follows it.
+	ImportedPrescriptionPrefix = "imported:"
+)
+
+// Request is a request to import training history.
+type Request struct {
+	// Format is the source app: STRONG, HEVY, FITNOTES or GENERIC.
+	Format string
+	// CSV is the contents of the exported file.
+	CSV string
+	// Columns maps the columns of GENERIC files.
+	Columns *Columns
+	// Unit is the weight unit of files that do not name one. Empty means the user's unit.
+	Unit string
+	// Exercises maps exercise names in the file to a lift ID or slug. An empty value skips
+	// the exercise. Mappings are remembered for later imports.
+	Exercises map[string]string
+	// DryRun reports what the import would do without saving anything.
+	DryRun bool
+	// DeriveMaxes creates a one-rep max, estimated from the best imported set, for each
+	// imported lift that has no one-rep max yet.
+	DeriveMaxes bool
+}
+
+// Report describes what an import did, or would do for a dry run.
+type Report struct {
+	// ImportID identifies the saved import. Nil for dry runs and imports that added nothing.
+	ImportID  *string         `json:"importId"`
+	DryRun    bool            `json:"dryRun"`
+	Format    string          `json:"format"`
+	Unit      string          `json:"unit"`
+	Sessions  SessionCounts   `json:"sessions"`
+	Sets      SetCounts       `json:"sets"`
+	Exercises []ExerciseMatch `json:"exercises"`
+	Maxes     []DerivedMax    `json:"maxes"`
+	Warnings  []string        `json:"warnings"`
+}
+
+// SessionCounts counts the workouts in an import file.
+type SessionCounts struct {
+	Total int `json:"total"`
+	// New is the number of workouts imported.
+	New int `json:"new"`
+	// AlreadyImported is the number of workouts skipped because an earlier import has them.
+	AlreadyImported int `json:"alreadyImported"`
+	// Skipped is the number of workouts without any set to import.
+	Skipped int `json:"skipped"`
+}
+
+// SetCounts counts the sets in an import file.
+type SetCounts struct {
+	Total           int `json:"total"`
+	New             int `json:"new"`
+	AlreadyImported int `json:"alreadyImported"`
+	// Skipped counts warm-up sets, sets without weight or reps, unreadable lines, and sets
+	// of skipped or unconfirmed exercises.
+	Skipped int `json:"skipped"`
+}
+
+// ExerciseMatch is the lift an exercise name in the file was matched to.
+type ExerciseMatch struct {
+	Name        string       `json:"name"`
+	SetCount    int          `json:"setCount"`
+	Status      string       `json:"status"`
+	LiftID      *string      `json:"liftId"`
+	LiftName    *string      `json:"liftName"`
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// DerivedMax is a one-rep max estimated from the best imported set of a lift.
+type DerivedMax struct {
+	LiftID        string    `json:"liftId"`
+	LiftName      string    `json:"liftName"`
+	Value         float64   `json:"value"`
+	Weight        float64   `json:"weight"`
+	Reps          int       `json:"reps"`
+	EffectiveDate time.Time `json:"effectiveDate"`
+}
+
+// Import is a saved import.
+type Import struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"userId"`
+	Format       string    `json:"format"`
+	SessionCount int       `json:"sessionCount"`
+	SetCount     int       `json:"setCount"`
+	MaxCount     int       `json:"maxCount"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// Session is an imported workout.
+type Session struct {
+	ID         string
+	SourceKey  string
+	Name       *string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	Sets       []Set
+}
+
+// Set is an imported set. Weight is in the user's weight unit.
+type Set struct {
+	ID        string
+	LiftID    string
+	SetNumber int
+	Weight    float64
+	Reps      int
+	RPE       *float64
+}
+
+// Plan is everything an import saves.
+type Plan struct {
+	Import   Import
+	Sessions []Session
+	// Aliases are the user's confirmed exercise names.
+	Aliases []Alias
+	Maxes   []DerivedMax
+}
+
+// Repository defines the interface for import persistence.
+type Repository interface {
+	// ListLifts returns the lifts visible to the user: the global catalog and the lifts of
+	// the user's organizations.
+	ListLifts(ctx context.Context, userID string) ([]Lift, error)
+	// ListAliases returns the built-in aliases and the user's own.
+	ListAliases(ctx context.Context, userID string) ([]Alias, error)
+	// ListSourceKeys returns the source keys of the user's imported sessions.
+	ListSourceKeys(ctx context.Context, userID string) (map[string]bool, error)
+	// ListLiftsWithOneRM returns the IDs of lifts the user has a one-rep max for.
+	ListLiftsWithOneRM(ctx context.Context, userID string) (map[string]bool, error)
+	// Save stores the plan and rebuilds the user's personal records in one transaction.
+	// It returns a conflict error if another import saved one of the sessions first.
+	Save(ctx context.Context, plan *Plan) error
+	// ListImports returns the user's imports, newest first.
+	ListImports(ctx context.Context, userID string) ([]Import, error)
+}
+
+// Service provides training history imports.
+type Service struct {
+	repo           Repository
+	profileService *profile.Service
+	estimator      *personalrecord.Estimator
+	now            func() time.Time
+}
+
+// NewService creates a new import service.
+func NewService(repo Repository, profileService *profile.Service) *Service {
+	return &Service{
+		repo:           repo,
+		profileService: profileService,
+		estimator:      personalrecord.NewEstimator(),
+		now:            time.Now,
+	}
+}
+
+// sessionGroup collects the rows of one workout in the file.
+type sessionGroup struct {
+	key  string
+	rows []Row
+}
+
+// Import reads a file and imports its workouts, or reports what it would import.
+// A real import requires every exercise with sets to import to be matched, confirmed or
+// skipped; a dry run reports the exercises that need confirmation with suggestions.
+func (s *Service) Import(ctx context.Context, userID string, req Request) (*Report, error) {
+	if userID == "" {
+		return nil, apperrors.NewBadRequest("user ID is required")
+	}
+	format := strings.ToUpper(strings.TrimSpace(req.Format))
+	if strings.TrimSpace(req.CSV) == "" {
+		return nil, apperrors.NewValidation("csv", "csv is required")
+	}
+	if req.Unit != "" && req.Unit != WeightUnitLb && req.Unit != WeightUnitKg {
+		return nil, apperrors.NewValidation("unit", "unit must be 'lb' or 'kg'")
+	}
+
+	parsed, err := parse(format, req.CSV, req.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.profileService.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	fileUnit := req.Unit
+	if fileUnit == "" {
+		fileUnit = p.WeightUnit
+	}
+
+	lifts, err := s.repo.ListLifts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := s.repo.ListAliases(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sourceKeys, err := s.repo.ListSourceKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		DryRun:    req.DryRun,
+		Format:    format,
+		Unit:      p.WeightUnit,
+		Exercises: []ExerciseMatch{},
+		Maxes:     []DerivedMax{},
+		Warnings:  parsed.warnings,
+	}
+	if report.Warnings == nil {
+		report.Warnings = []string{}
+	}
+	report.Sets.Total = len(parsed.rows) + parsed.warmups + parsed.empty + len(parsed.warnings)
+	report.Sets.Skipped = parsed.warmups + parsed.empty + len(parsed.warnings)
+	if parsed.warmups > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d warm-up sets skipped", parsed.warmups))
+	}
+	if parsed.empty > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d sets without weight or reps skipped", parsed.empty))
+	}
+
+	// Match every exercise name once, in the order the file first uses it
+	m := newMatcher(lifts, aliases)
+	matches := map[string]*ExerciseMatch{}
+	var newAliases []Alias
+	for _, row := range parsed.rows {
+		if match, ok := matches[row.Exercise]; ok {
+			match.SetCount++
+			continue
+		}
+		match, alias, err := s.matchExercise(m, userID, row.Exercise, req.Exercises)
+		if err != nil {
+			return nil, err
+		}
+		match.SetCount = 1
+		matches[row.Exercise] = match
+		report.Exercises = append(report.Exercises, *match)
+		if alias != nil {
+			newAliases = append(newAliases, *alias)
+		}
+	}
+	for i := range report.Exercises {
+		report.Exercises[i].SetCount = matches[report.Exercises[i].Name].SetCount
+	}
+
+	// Group sets into workouts by start time and workout name
+	groups := map[string]*sessionGroup{}
+	var order []*sessionGroup
+	for _, row := range parsed.rows {
+		key := sourceKey(row.StartedAt, row.Workout)
+		g, ok := groups[key]
+		if !ok {
+			g = &sessionGroup{key: key}
+			groups[key] = g
+			order = append(order, g)
+		}
+		g.rows = append(g.rows, row)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].rows[0].StartedAt.Before(order[j].rows[0].StartedAt)
+	})
+
+	var sessions []Session
+	// pending holds the unconfirmed exercises of workouts not imported yet
+	pending := map[string]bool{}
+	report.Sessions.Total = len(order)
+	for _, g := range order {
+		if sourceKeys[g.key] {
+			report.Sessions.AlreadyImported++
+			report.Sets.AlreadyImported += len(g.rows)
+			continue
+		}
+		for _, row := range g.rows {
+			if matches[row.Exercise].Status == MatchStatusNeedsConfirmation {
+				pending[row.Exercise] = true
+			}
+		}
+		session := s.buildSession(g, matches, fileUnit, p.WeightUnit)
+		report.Sets.Skipped += len(g.rows) - len(session.Sets)
+		if len(session.Sets) == 0 {
+			report.Sessions.Skipped++
+			continue
+		}
+		report.Sessions.New++
+		report.Sets.New += len(session.Sets)
+		sessions = append(sessions, session)
+	}
+
+	if req.DeriveMaxes && len(sessions) > 0 {
+		withMax, err := s.repo.ListLiftsWithOneRM(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		report.Maxes = s.deriveMaxes(sessions, m, withMax)
+	}
+
+	if req.DryRun {
+		return report, nil
+	}
+	var unconfirmed []string
+	for _, e := range report.Exercises {
+		if pending[e.Name] {
+			unconfirmed = append(unconfirmed, e.Name)
+		}
+	}
+	if len(unconfirmed) > 0 {
+		return nil, apperrors.NewValidation("exercises",
+			fmt.Sprintf("exercises need confirmation: %s; map them to a lift or to \"\" to skip them", strings.Join(unconfirmed, ", ")))
+	}
+	if len(sessions) == 0 && len(newAliases) == 0 {
+		return report, nil
+	}
+
+	plan := &Plan{Sessions: sessions, Aliases: newAliases, Maxes: report.Maxes}
+	if len(sessions) > 0 {
+		plan.Import = Import{
+			ID:           uuid.New().String(),
+			UserID:       userID,
+			Format:       format,
+			SessionCount: report.Sessions.New,
+			SetCount:     report.Sets.New,
+			MaxCount:     len(report.Maxes),
+			CreatedAt:    s.now().UTC(),
+		}
+		report.ImportID = &plan.Import.ID
+	}
+	if err := s.repo.Save(ctx, plan); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ListImports returns the user's imports, newest first.
+func (s *Service) ListImports(ctx context.Context, userID string) ([]Import, error) {
+	if userID == "" {
+		return nil, apperrors.NewBadRequest("user ID is required")
+	}
+	return s.repo.ListImports(ctx, userID)
+}
+
+// matchExercise matches one exercise name, applying the request's confirmations. It
+// returns the alias to remember when the request confirms a lift for the name.
+func (s *Service) matchExercise(m *matcher, userID, name string, confirmations map[string]string) (*ExerciseMatch, *Alias, error) {
+	match := &ExerciseMatch{Name: name, Suggestions: []Suggestion{}}
+	if target, ok := confirmations[name]; ok {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			match.Status = MatchStatusSkipped
+			return match, nil, nil
+		}
+		lift := m.resolve(target)
+		if lift == nil {
+			return nil, nil, apperrors.NewValidation("exercises", fmt.Sprintf("lift %q for exercise %q not found", target, name))
+		}
+		match.Status = MatchStatusConfirmed
+		match.LiftID, match.LiftName = &lift.ID, &lift.Name
+		uid := userID
+		return match, &Alias{LiftID: lift.ID, UserID: &uid, Alias: normalizeName(name)}, nil
+	}
+
+	lift, suggestions := m.match(name)
+	if lift == nil {
+		match.Status = MatchStatusNeedsConfirmation
+		if suggestions != nil {
+			match.Suggestions = suggestions
+		}
+		return match, nil, nil
+	}
+	match.Status = MatchStatusMatched
+	match.LiftID, match.LiftName = &lift.ID, &lift.Name
+	return match, nil, nil
+}
+
+// buildSession turns a workout's rows into a session with the sets of matched exercises,
+// numbered per lift in file order.
+func (s *Service) buildSession(g *sessionGroup, matches map[string]*ExerciseMatch, fileUnit, userUnit string) Session {
+	first := g.rows[0]
+	session := Session{
+		ID:         uuid.New().String(),
+		SourceKey:  g.key,
+		StartedAt:  first.StartedAt,
+		FinishedAt: first.FinishedAt,
+	}
+	if first.Workout != "" {
+		name := first.Workout
+		session.Name = &name
+	}
+
+	setNumbers := map[string]int{}
+	for _, row := range g.rows {
+		match := matches[row.Exercise]
+		if match.LiftID == nil {
+			continue
+		}
+		unit := row.Unit
+		if unit == "" {
+			unit = fileUnit
+		}
+		setNumbers[*match.LiftID]++
+		session.Sets = append(session.Sets, Set{
+			ID:        uuid.New().String(),
+			LiftID:    *match.LiftID,
+			SetNumber: setNumbers[*match.LiftID],
+			Weight:    convertWeight(row.Weight, unit, userUnit),
+			Reps:      row.Reps,
+			RPE:       row.RPE,
+		})
+	}
+	return session
+}
+
+// deriveMaxes estimates a one-rep max from the best set of each imported lift that has no
+// one-rep max yet. The max takes effect on the date of the set.
+func (s *Service) deriveMaxes(sessions []Session, m *matcher, withMax map[string]bool) []DerivedMax {
+	best := map[string]*DerivedMax{}
+	var order []string
+	for _, session := range sessions {
+		for _, set := range session.Sets {
+			if withMax[set.LiftID] {
+				continue
+			}
+			estimate := roundWeight(s.estimator.EstimateOneRM(set.Weight, set.Reps, set.RPE))
+			if estimate <= 0 {
+				continue
+			}
+			current, ok := best[set.LiftID]
+			if !ok {
+				order = append(order, set.LiftID)
+			}
+			if !ok || estimate > current.Value {
+				best[set.LiftID] = &DerivedMax{
+					LiftID:        set.LiftID,
+					LiftName:      m.byID[set.LiftID].Name,
+					Value:         estimate,
+					Weight:        set.Weight,
+					Reps:          set.Reps,
+					EffectiveDate: session.StartedAt,
+				}
+			}
+		}
+	}
+
+	maxes := []DerivedMax{}
+	for _, liftID := range order {
+		maxes = append(maxes, *best[liftID])
+	}
+	sort.SliceStable(maxes, func(i, j int) bool { return maxes[i].LiftName < maxes[j].LiftName })
+	return maxes
+}
+
+// sourceKey identifies a workout in a source file by its start time and name.
+func sourceKey(startedAt time.Time, workout string) string {
+	sum := sha256.Sum256([]byte(startedAt.UTC().Format(time.RFC3339) + "\x00" + workout))
+	return hex.EncodeToString(sum[:])
+}
+
+func convertWeight(weight float64, from, to string) float64 {
+	if from == to {
+		return weight
+	}
+	return roundWeight(strengthscore.FromKg(strengthscore.ToKg(weight, from), to))
+}
+
+// roundWeight rounds a weight to two decimal places.
+func roundWeight(weight float64) float64 {
+	return float64(int64(weight*100+0.5)) / 100
+}
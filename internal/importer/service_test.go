@@ -0,0 +1,277 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waynenilsen/power-pro-v3/internal/database"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/profile"
+	"github.com/waynenilsen/power-pro-v3/internal/service"
+)
+
+const (
+	squatID    = "00000000-0000-0000-0000-000000000001"
+	benchID    = "00000000-0000-0000-0000-000000000002"
+	deadliftID = "00000000-0000-0000-0000-000000000003"
+)
+
+const strongCSV = `Date,Workout Name,Duration,Exercise Name,Set Order,Weight,Reps,Distance,Seconds,Notes,Workout Notes,RPE
+2023-01-10 08:00:00,"Day A",1h 5m,"Squat (Barbell)",W,135,5,0,0,,,
+2023-01-10 08:00:00,"Day A",1h 5m,"Squat (Barbell)",1,275,5,0,0,,,8
+2023-01-10 08:00:00,"Day A",1h 5m,"Squat (Barbell)",2,275,5,0,0,,,9
+2023-01-10 08:00:00,"Day A",1h 5m,"Bench Press (Barbell)",1,185,5,0,0,,,
+2023-01-10 08:00:00,"Day A",1h 5m,"Plank",1,0,0,0,60,,,
+2023-01-12 08:00:00,"Day B",50m,"Deadlift (Barbell)",1,365,3,0,0,,,
+2023-01-12 08:00:00,"Day B",50m,"Pause Squat",1,225,3,0,0,,,
+2023-01-12 08:00:00,"Day B",50m,"Pause Squat",2,225,3,0,0,,,
+`
+
+func setupTestService(t *testing.T) (*Service, *sql.DB, func()) {
+	sqlDB, cleanup, err := database.OpenTemp("../../migrations")
+	require.NoError(t, err)
+	repo := NewSQLiteRepository(sqlDB, service.NewPersonalRecordService(sqlDB))
+	svc := NewService(repo, profile.NewService(profile.NewSQLiteProfileRepository(sqlDB)))
+	svc.now = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }
+	return svc, sqlDB, cleanup
+}
+
+func createUser(t *testing.T, sqlDB *sql.DB, userID, unit string) {
+	_, err := sqlDB.Exec(`
+		INSERT INTO users (id, email, weight_unit, created_at, updated_at)
+		VALUES (?, ? || '@example.com', ?, '2024-01-01T00:00:00Z', '2024-01-01T00:00:00Z')
+	`, userID, userID, unit)
+	require.NoError(t, err)
+}
+
+func countRows(t *testing.T, sqlDB *sql.DB, query string, args ...interface{}) int {
+	var n int
+	require.NoError(t, sqlDB.QueryRow(query, args...).Scan(&n))
+	return n
+}
+
+func exerciseByName(report *Report, name string) ExerciseMatch {
+	for _, e := range report.Exercises {
+		if e.Name == name {
+			return e
+		}
+	}
+	return ExerciseMatch{}
+}
+
+func TestParse(t *testing.T) {
+	t.Run("strong", func(t *testing.T) {
+		result, err := parse(FormatStrong, strongCSV, nil)
+		require.NoError(t, err)
+		assert.Len(t, result.rows, 6)
+		assert.Equal(t, 1, result.warmups)
+		assert.Equal(t, 1, result.empty)
+
+		first := result.rows[0]
+		assert.Equal(t, "Day A", first.Workout)
+		assert.Equal(t, time.Date(2023, 1, 10, 8, 0, 0, 0, time.UTC), first.StartedAt)
+		require.NotNil(t, first.FinishedAt)
+		assert.Equal(t, 65*time.Minute, first.FinishedAt.Sub(first.StartedAt))
+		assert.Equal(t, 275.0, first.Weight)
+		assert.Equal(t, "", first.Unit)
+		require.NotNil(t, first.RPE)
+		assert.Equal(t, 8.0, *first.RPE)
+	})
+
+	t.Run("hevy", func(t *testing.T) {
+		csv := `"title","start_time","end_time","description","exercise_title","superset_id","exercise_notes","set_index","set_type","weight_kg","reps","distance_km","duration_seconds","rpe"
+"Upper","15 Jan 2023, 18:30","15 Jan 2023, 19:40","","Bench Press (Barbell)",,"",0,"warmup",40,10,,,
+"Upper","15 Jan 2023, 18:30","15 Jan 2023, 19:40","","Bench Press (Barbell)",,"",1,"normal",100,5,,,8.5
+`
+		result, err := parse(FormatHevy, csv, nil)
+		require.NoError(t, err)
+		require.Len(t, result.rows, 1)
+		assert.Equal(t, 1, result.warmups)
+		row := result.rows[0]
+		assert.Equal(t, WeightUnitKg, row.Unit)
+		assert.Equal(t, time.Date(2023, 1, 15, 18, 30, 0, 0, time.UTC), row.StartedAt)
+		require.NotNil(t, row.FinishedAt)
+		assert.Equal(t, 70*time.Minute, row.FinishedAt.Sub(row.StartedAt))
+	})
+
+	t.Run("fitnotes", func(t *testing.T) {
+		csv := "Date,Exercise,Category,Weight,Weight Unit,Reps,Distance,Distance Unit,Time,Comment\n" +
+			"2023-02-01,Flat Barbell Bench Press,Chest,80.0,kgs,5,,,,\n" +
+			"2023-02-01,Barbell Squat,Legs,225,lbs,5,,,,\n" +
+			"2023-02-01,Barbell Squat,Legs,bad,lbs,5,,,,\n"
+		result, err := parse(FormatFitNotes, csv, nil)
+		require.NoError(t, err)
+		require.Len(t, result.rows, 2)
+		assert.Equal(t, WeightUnitKg, result.rows[0].Unit)
+		assert.Equal(t, WeightUnitLb, result.rows[1].Unit)
+		assert.Equal(t, []string{`line 4 skipped: invalid weight "bad"`}, result.warnings)
+	})
+
+	t.Run("generic", func(t *testing.T) {
+		csv := "when;lift;kg;reps\n"
+		_, err := parse(FormatGeneric, csv, nil)
+		assert.True(t, apperrors.IsValidation(err))
+
+		csv = "when,lift,load,count\n03/02/2023,Deadlift,180,3\n"
+		result, err := parse(FormatGeneric, csv, &Columns{
+			Date: "when", Exercise: "lift", Weight: "load", Reps: "count", DateLayout: "02/01/2006",
+		})
+		require.NoError(t, err)
+		require.Len(t, result.rows, 1)
+		assert.Equal(t, time.Date(2023, 2, 3, 0, 0, 0, 0, time.UTC), result.rows[0].StartedAt)
+
+		_, err = parse(FormatGeneric, csv, &Columns{Date: "when", Exercise: "lift", Weight: "weight", Reps: "count"})
+		assert.True(t, apperrors.IsValidation(err))
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := parse("JEFIT", strongCSV, nil)
+		assert.True(t, apperrors.IsValidation(err))
+	})
+}
+
+func TestMatcher(t *testing.T) {
+	lifts := []Lift{
+		{ID: squatID, Name: "Squat", Slug: "squat"},
+		{ID: benchID, Name: "Bench Press", Slug: "bench-press"},
+		{ID: deadliftID, Name: "Deadlift", Slug: "deadlift"},
+	}
+	userID := "user"
+	m := newMatcher(lifts, []Alias{
+		{LiftID: benchID, Alias: "bench press barbell"},
+		{LiftID: squatID, UserID: &userID, Alias: "pause squat"},
+	})
+
+	for name, want := range map[string]string{
+		"Bench Press (Barbell)": benchID,
+		"bench-press":           benchID,
+		"Deadlifts":             deadliftID,
+		"Pause Squat":           squatID,
+	} {
+		lift, _ := m.match(name)
+		if assert.NotNil(t, lift, name) {
+			assert.Equal(t, want, lift.ID, name)
+		}
+	}
+
+	lift, suggestions := m.match("Incline Bench Press (Barbell)")
+	assert.Nil(t, lift)
+	require.NotEmpty(t, suggestions)
+	assert.Equal(t, benchID, suggestions[0].LiftID)
+
+	lift, suggestions = m.match("Lat Pulldown")
+	assert.Nil(t, lift)
+	assert.Empty(t, suggestions)
+}
+
+func TestImport(t *testing.T) {
+	svc, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	createUser(t, sqlDB, "importer", "lb")
+
+	t.Run("dry run reports without saving", func(t *testing.T) {
+		report, err := svc.Import(ctx, "importer", Request{Format: "strong", CSV: strongCSV, DryRun: true, DeriveMaxes: true})
+		require.NoError(t, err)
+		assert.Nil(t, report.ImportID)
+		assert.Equal(t, SessionCounts{Total: 2, New: 2}, report.Sessions)
+		assert.Equal(t, SetCounts{Total: 8, New: 4, Skipped: 4}, report.Sets)
+
+		assert.Equal(t, MatchStatusMatched, exerciseByName(report, "Squat (Barbell)").Status)
+		pause := exerciseByName(report, "Pause Squat")
+		assert.Equal(t, MatchStatusNeedsConfirmation, pause.Status)
+		assert.Equal(t, 2, pause.SetCount)
+		require.NotEmpty(t, pause.Suggestions)
+		assert.Equal(t, squatID, pause.Suggestions[0].LiftID)
+		assert.Len(t, report.Maxes, 3)
+
+		assert.Equal(t, 0, countRows(t, sqlDB, `SELECT COUNT(*) FROM logged_sets WHERE user_id = 'importer'`))
+	})
+
+	t.Run("unconfirmed exercises block the import", func(t *testing.T) {
+		_, err := svc.Import(ctx, "importer", Request{Format: FormatStrong, CSV: strongCSV})
+		assert.True(t, apperrors.IsValidation(err))
+	})
+
+	t.Run("confirmed import creates history", func(t *testing.T) {
+		report, err := svc.Import(ctx, "importer", Request{
+			Format:      FormatStrong,
+			CSV:         strongCSV,
+			Exercises:   map[string]string{"Pause Squat": "squat"},
+			DeriveMaxes: true,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, report.ImportID)
+		assert.Equal(t, SessionCounts{Total: 2, New: 2}, report.Sessions)
+		assert.Equal(t, 6, report.Sets.New)
+
+		assert.Equal(t, 2, countRows(t, sqlDB, `SELECT COUNT(*) FROM imported_sessions WHERE import_id = ?`, *report.ImportID))
+		assert.Equal(t, 6, countRows(t, sqlDB, `SELECT COUNT(*) FROM logged_sets WHERE user_id = 'importer'`))
+		assert.Equal(t, 4, countRows(t, sqlDB, `SELECT COUNT(*) FROM logged_sets WHERE user_id = 'importer' AND lift_id = ?`, squatID))
+		assert.Equal(t, 1, countRows(t, sqlDB, `
+			SELECT COUNT(*) FROM logged_sets
+			WHERE user_id = 'importer' AND prescription_id = ? AND set_number = 2 AND created_at = '2023-01-10T08:00:00Z'
+		`, ImportedPrescriptionPrefix+squatID))
+		assert.Greater(t, countRows(t, sqlDB, `SELECT COUNT(*) FROM personal_records WHERE user_id = 'importer'`), 0)
+
+		// Maxes are derived from the best set of each lift
+		require.Len(t, report.Maxes, 3)
+		assert.Equal(t, 3, countRows(t, sqlDB, `SELECT COUNT(*) FROM lift_maxes WHERE user_id = 'importer' AND type = 'ONE_RM'`))
+		var squatMax float64
+		var effective string
+		require.NoError(t, sqlDB.QueryRow(`SELECT value, effective_date FROM lift_maxes WHERE user_id = 'importer' AND lift_id = ?`, squatID).Scan(&squatMax, &effective))
+		assert.Greater(t, squatMax, 275.0)
+		assert.Equal(t, "2023-01-10T08:00:00Z", effective)
+
+		imports, err := svc.ListImports(ctx, "importer")
+		require.NoError(t, err)
+		require.Len(t, imports, 1)
+		assert.Equal(t, Import{
+			ID: *report.ImportID, UserID: "importer", Format: FormatStrong,
+			SessionCount: 2, SetCount: 6, MaxCount: 3, CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		}, imports[0])
+	})
+
+	t.Run("importing again skips imported workouts", func(t *testing.T) {
+		report, err := svc.Import(ctx, "importer", Request{Format: FormatStrong, CSV: strongCSV, DeriveMaxes: true})
+		require.NoError(t, err)
+		assert.Nil(t, report.ImportID)
+		assert.Equal(t, SessionCounts{Total: 2, AlreadyImported: 2}, report.Sessions)
+		assert.Equal(t, 6, report.Sets.AlreadyImported)
+		assert.Empty(t, report.Maxes)
+		// The confirmed name is remembered
+		assert.Equal(t, MatchStatusMatched, exerciseByName(report, "Pause Squat").Status)
+		assert.Equal(t, 6, countRows(t, sqlDB, `SELECT COUNT(*) FROM logged_sets WHERE user_id = 'importer'`))
+	})
+
+	t.Run("skipped exercises and unit conversion", func(t *testing.T) {
+		createUser(t, sqlDB, "hevy-user", "lb")
+		csv := `"title","start_time","end_time","exercise_title","set_type","weight_kg","reps","rpe"
+"Upper","15 Jan 2023, 18:30","15 Jan 2023, 19:40","Bench Press (Barbell)","normal",100,5,
+"Upper","15 Jan 2023, 18:30","15 Jan 2023, 19:40","Lat Pulldown (Cable)","normal",60,10,
+`
+		report, err := svc.Import(ctx, "hevy-user", Request{
+			Format: FormatHevy, CSV: csv, Exercises: map[string]string{"Lat Pulldown (Cable)": ""},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, report.ImportID)
+		assert.Equal(t, MatchStatusSkipped, exerciseByName(report, "Lat Pulldown (Cable)").Status)
+		assert.Equal(t, SetCounts{Total: 2, New: 1, Skipped: 1}, report.Sets)
+
+		var weight float64
+		require.NoError(t, sqlDB.QueryRow(`SELECT weight FROM logged_sets WHERE user_id = 'hevy-user'`).Scan(&weight))
+		assert.Equal(t, 220.46, weight)
+		assert.Equal(t, 0, countRows(t, sqlDB, `SELECT COUNT(*) FROM lift_aliases WHERE user_id = 'hevy-user'`))
+	})
+
+	t.Run("confirmations must name a lift", func(t *testing.T) {
+		_, err := svc.Import(ctx, "importer", Request{
+			Format: FormatStrong, CSV: strongCSV, Exercises: map[string]string{"Pause Squat": "box-squat"},
+		})
+		assert.True(t, apperrors.IsValidation(err))
+	})
+}
@@ -0,0 +1,175 @@
+// Package mail sends transactional email, such as password reset and email verification
+// messages. Mailers deliver over SMTP, write messages to files for local development,
+// or keep them in memory for tests.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends messages.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// format renders the message as an RFC 5322 email from the sender.
+func (m Message) format(from string, now time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", m.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(m.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return b.Bytes()
+}
+
+// validate rejects recipients and subjects that could inject headers.
+func (m Message) validate() error {
+	if m.To == "" || strings.ContainsAny(m.To, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", m.To)
+	}
+	if strings.ContainsAny(m.Subject, "\r\n") {
+		return fmt.Errorf("subject must be a single line")
+	}
+	return nil
+}
+
+// SMTPConfig configures delivery through an SMTP server.
+type SMTPConfig struct {
+	Host string
+	Port int
+	// Username and Password authenticate with PLAIN auth. Leave Username empty for servers
+	// that accept mail without authentication.
+	Username string
+	Password string
+	// From is the sender address.
+	From string
+}
+
+// SMTPMailer delivers messages through an SMTP server, using STARTTLS when the server
+// offers it.
+type SMTPMailer struct {
+	config SMTPConfig
+	now    func() time.Time
+}
+
+// NewSMTPMailer creates a mailer for the SMTP server.
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{config: config, now: time.Now}
+}
+
+// Send implements Mailer.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+	addr := m.config.Host + ":" + strconv.Itoa(m.config.Port)
+	if err := smtp.SendMail(addr, auth, m.config.From, []string{msg.To}, msg.format(m.config.From, m.now())); err != nil {
+		return fmt.Errorf("failed to send mail to %s: %w", msg.To, err)
+	}
+	return nil
+}
+
+// FileMailer writes each message to its own .eml file in a directory instead of sending
+// it, for local development.
+type FileMailer struct {
+	dir  string
+	from string
+	now  func() time.Time
+}
+
+// NewFileMailer creates a mailer that writes messages from the sender into dir, creating
+// the directory if needed.
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from, now: time.Now}
+}
+
+// Send implements Mailer.
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	now := m.now()
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000Z"), hex.EncodeToString(suffix))
+	if err := os.WriteFile(filepath.Join(m.dir, name), msg.format(m.from, now), 0o600); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+	return nil
+}
+
+// MemoryMailer keeps sent messages in memory, for tests.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemoryMailer creates an empty in-memory mailer.
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send implements Mailer.
+func (m *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns the messages sent so far, oldest first.
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
+
+// Last returns the most recent message sent to the address, ignoring case.
+func (m *MemoryMailer) Last(to string) (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if strings.EqualFold(m.messages[i].To, to) {
+			return m.messages[i], true
+		}
+	}
+	return Message{}, false
+}
@@ -0,0 +1,62 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	mailer := NewFileMailer(dir, "PowerPro <noreply@powerpro.test>")
+
+	require.NoError(t, mailer.Send(context.Background(), Message{
+		To:      "lifter@example.com",
+		Subject: "Reset your password",
+		Body:    "Line one\nLine two",
+	}))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.True(t, strings.HasSuffix(entries[0].Name(), ".eml"))
+
+	data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "From: PowerPro <noreply@powerpro.test>\r\n")
+	assert.Contains(t, content, "To: lifter@example.com\r\n")
+	assert.Contains(t, content, "Subject: Reset your password\r\n")
+	assert.True(t, strings.HasSuffix(content, "\r\n\r\nLine one\r\nLine two"))
+}
+
+func TestMemoryMailer(t *testing.T) {
+	mailer := NewMemoryMailer()
+	ctx := context.Background()
+
+	require.NoError(t, mailer.Send(ctx, Message{To: "a@example.com", Subject: "First"}))
+	require.NoError(t, mailer.Send(ctx, Message{To: "b@example.com", Subject: "Second"}))
+	require.NoError(t, mailer.Send(ctx, Message{To: "a@example.com", Subject: "Third"}))
+
+	assert.Len(t, mailer.Messages(), 3)
+	msg, ok := mailer.Last("A@example.com")
+	require.True(t, ok)
+	assert.Equal(t, "Third", msg.Subject)
+	_, ok = mailer.Last("c@example.com")
+	assert.False(t, ok)
+}
+
+func TestMessagesCannotInjectHeaders(t *testing.T) {
+	mailer := NewMemoryMailer()
+	ctx := context.Background()
+
+	assert.Error(t, mailer.Send(ctx, Message{To: "a@example.com\r\nBcc: victim@example.com", Subject: "Hi"}))
+	assert.Error(t, mailer.Send(ctx, Message{To: "a@example.com", Subject: "Hi\r\nBcc: victim@example.com"}))
+	assert.Error(t, mailer.Send(ctx, Message{Subject: "Hi"}))
+	assert.Empty(t, mailer.Messages())
+}
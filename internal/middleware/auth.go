@@ -3,6 +3,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
@@ -19,8 +20,25 @@ const (
 	IsAdminKey contextKey = "is_admin"
 	// UserKey is the context key for the full user object.
 	UserKey contextKey = "user"
+
+	accessCheckerKey contextKey = "access_checker"
+	accessGrantKey   contextKey = "access_grant"
+	routeScopeKey    contextKey = "route_scope"
+	credentialKey    contextKey = "credential"
 )
 
+// ErrCredentialChanged is returned by Revalidate when the request's token now belongs
+// to a different user.
+var ErrCredentialChanged = errors.New("token no longer belongs to the authenticated user")
+
+// credential is the token a request was authenticated with, kept so long-lived
+// requests can check that it is still valid.
+type credential struct {
+	validator SessionValidator
+	token     string
+	userID    string
+}
+
 // AuthUser represents a user in the context for middleware purposes.
 // This is separate from the auth.User to avoid circular dependencies.
 type AuthUser struct {
@@ -28,6 +46,9 @@ type AuthUser struct {
 	Email   string
 	Name    string
 	IsAdmin bool
+	// Scopes limits an access token to routes that allow one of them. Nil for sessions,
+	// which can use every route.
+	Scopes []string
 }
 
 // SessionValidator validates session tokens and returns user information.
@@ -35,6 +56,12 @@ type SessionValidator interface {
 	ValidateSession(ctx context.Context, token string) (*AuthUser, error)
 }
 
+// AccessChecker decides whether one user may act on another user's data, such as a
+// coach viewing an athlete's training logs. Permissions are opaque to the middleware.
+type AccessChecker interface {
+	CanAccess(ctx context.Context, actorID, ownerID, permission string) (bool, error)
+}
+
 // GetUserID retrieves the authenticated user ID from the request context.
 func GetUserID(r *http.Request) string {
 	if id, ok := r.Context().Value(UserIDKey).(string); ok {
@@ -81,6 +108,9 @@ type AuthConfig struct {
 	WriteError func(w http.ResponseWriter, status int, message string)
 	// SessionValidator validates session tokens.
 	SessionValidator SessionValidator
+	// AccessChecker grants access to other users' data. When nil, only owners and
+	// admins can access a user's data.
+	AccessChecker AccessChecker
 }
 
 // isTestMode checks if the application is running in test mode.
@@ -108,10 +138,18 @@ func RequireAuth(cfg AuthConfig) func(http.Handler) http.Handler {
 						return
 					}
 
+					if user.Scopes != nil && !hasRouteScope(ctx, user.Scopes) {
+						log.Printf("AUTH: Forbidden request to %s by user %s - access token lacks scope %q", r.URL.Path, user.ID, routeScope(ctx))
+						cfg.WriteError(w, http.StatusForbidden, scopeDeniedMessage(ctx))
+						return
+					}
+
 					// Set user info in context
 					ctx = context.WithValue(ctx, UserIDKey, user.ID)
 					ctx = context.WithValue(ctx, IsAdminKey, user.IsAdmin)
 					ctx = context.WithValue(ctx, UserKey, user)
+					ctx = context.WithValue(ctx, credentialKey, credential{validator: cfg.SessionValidator, token: token, userID: user.ID})
+					ctx = withAccessChecker(ctx, cfg.AccessChecker)
 
 					next.ServeHTTP(w, r.WithContext(ctx))
 					return
@@ -138,6 +176,7 @@ func RequireAuth(cfg AuthConfig) func(http.Handler) http.Handler {
 						ID:      userID,
 						IsAdmin: isAdmin,
 					})
+					ctx = withAccessChecker(ctx, cfg.AccessChecker)
 
 					next.ServeHTTP(w, r.WithContext(ctx))
 					return
@@ -150,6 +189,92 @@ func RequireAuth(cfg AuthConfig) func(http.Handler) http.Handler {
 	}
 }
 
+// Revalidate checks that the token the request was authenticated with is still valid
+// and still belongs to the same user. Long-lived requests, such as event streams, call
+// it periodically so a session that is revoked or expires ends them. Requests
+// authenticated with test mode headers have no token and always pass.
+func Revalidate(r *http.Request) error {
+	cred, ok := r.Context().Value(credentialKey).(credential)
+	if !ok {
+		return nil
+	}
+	user, err := cred.validator.ValidateSession(r.Context(), cred.token)
+	if err != nil {
+		return err
+	}
+	if user.ID != cred.userID {
+		return ErrCredentialChanged
+	}
+	return nil
+}
+
+// AllowQueryToken creates middleware that accepts a session token from the named query
+// parameter when the request has no Authorization header. Browsers cannot set headers on
+// EventSource connections, so streaming endpoints use this before RequireAuth.
+// The parameter is removed from the request so the token is not passed further along,
+// but the full URL, token included, is still written to the access logs of proxies and
+// load balancers in front of the server. Clients that can set headers should send the
+// Authorization header instead.
+func AllowQueryToken(param string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			token := query.Get(param)
+			if token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			r = r.Clone(r.Context())
+			query.Del(param)
+			r.URL.RawQuery = query.Encode()
+			if r.Header.Get("Authorization") == "" {
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AllowScope creates middleware that lets personal access tokens holding the scope use
+// the route. It must wrap RequireAuth. Access tokens cannot use routes without a scope,
+// so account and credential management stays limited to sessions.
+func AllowScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), routeScopeKey, scope)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// routeScope returns the scope allowed by AllowScope, or "" when the route has none.
+func routeScope(ctx context.Context) string {
+	scope, _ := ctx.Value(routeScopeKey).(string)
+	return scope
+}
+
+// hasRouteScope reports whether scopes include the route's scope.
+func hasRouteScope(ctx context.Context, scopes []string) bool {
+	required := routeScope(ctx)
+	if required == "" {
+		return false
+	}
+	for _, s := range scopes {
+		if s == required {
+			return true
+		}
+	}
+	return false
+}
+
+func scopeDeniedMessage(ctx context.Context) string {
+	if required := routeScope(ctx); required != "" {
+		return "Access token lacks the " + required + " scope"
+	}
+	return "Access tokens cannot be used for this route"
+}
+
 // RequireAdmin creates middleware that requires admin privileges.
 // It must be used after RequireAuth middleware.
 func RequireAdmin(cfg AuthConfig) func(http.Handler) http.Handler {
@@ -220,6 +345,84 @@ func RequireOwnerOrAdmin(cfg AuthConfig, ownerFunc ResourceOwnerFunc) func(http.
 	}
 }
 
+// accessGrant records a permission already granted for a request.
+type accessGrant struct {
+	ownerID    string
+	permission string
+}
+
+func withAccessChecker(ctx context.Context, checker AccessChecker) context.Context {
+	if checker == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, accessCheckerKey, checker)
+}
+
+// CanAccessUser reports whether the authenticated user may access ownerID's data with
+// the given permission. Owners and admins can always access; other users need a grant
+// from the AccessChecker configured on RequireAuth. An empty permission allows only the
+// owner and admins.
+func CanAccessUser(r *http.Request, ownerID, permission string) bool {
+	userID := GetUserID(r)
+	if userID == "" {
+		return false
+	}
+	if userID == ownerID || IsAdmin(r) {
+		return true
+	}
+	if permission == "" {
+		return false
+	}
+
+	ctx := r.Context()
+	if grant, ok := ctx.Value(accessGrantKey).(accessGrant); ok && grant.ownerID == ownerID && grant.permission == permission {
+		return true
+	}
+	checker, ok := ctx.Value(accessCheckerKey).(AccessChecker)
+	if !ok {
+		return false
+	}
+	allowed, err := checker.CanAccess(ctx, userID, ownerID, permission)
+	if err != nil {
+		log.Printf("AUTH: Error checking access for %s: %v", r.URL.Path, err)
+		return false
+	}
+	return allowed
+}
+
+// RequireUserAccess creates middleware that requires access to the data of the user
+// returned by ownerFunc: the user themselves, an admin, or a user the AccessChecker
+// grants the permission to. It must be used after RequireAuth middleware.
+func RequireUserAccess(cfg AuthConfig, permission string, ownerFunc ResourceOwnerFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := GetUserID(r)
+			if userID == "" {
+				log.Printf("AUTH: Unauthorized request to %s - no user context", r.URL.Path)
+				cfg.WriteError(w, http.StatusUnauthorized, "Authentication required")
+				return
+			}
+
+			ownerID, err := ownerFunc(r)
+			if err != nil {
+				log.Printf("AUTH: Error checking ownership for %s: %v", r.URL.Path, err)
+				cfg.WriteError(w, http.StatusInternalServerError, "Failed to verify resource ownership")
+				return
+			}
+
+			if !CanAccessUser(r, ownerID, permission) {
+				log.Printf("AUTH: Forbidden request to %s by user %s - no %q access to user %s", r.URL.Path, userID, permission, ownerID)
+				cfg.WriteError(w, http.StatusForbidden, "Access denied: you do not have permission to access this resource")
+				return
+			}
+
+			// Later checks for the same access in the handler need not ask the checker again
+			ctx := context.WithValue(r.Context(), accessGrantKey, accessGrant{ownerID: ownerID, permission: permission})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 // ChainMiddleware chains multiple middleware functions together.
 func ChainMiddleware(middlewares ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
 	return func(final http.Handler) http.Handler {
@@ -478,6 +478,106 @@ func TestRequireOwnerOrAdmin(t *testing.T) {
 	}
 }
 
+// mockAccessChecker grants access from a fixed set of "actor:owner:permission" keys.
+type mockAccessChecker struct {
+	grants map[string]bool
+	err    error
+	calls  int
+}
+
+func (m *mockAccessChecker) CanAccess(ctx context.Context, actorID, ownerID, permission string) (bool, error) {
+	m.calls++
+	if m.err != nil {
+		return false, m.err
+	}
+	return m.grants[actorID+":"+ownerID+":"+permission], nil
+}
+
+func TestRequireUserAccess(t *testing.T) {
+	checker := &mockAccessChecker{grants: map[string]bool{"coach-1:athlete-1:VIEW_LOGS": true}}
+	errWriter := &mockErrorWriter{}
+	cfg := AuthConfig{
+		WriteError:    errWriter.writeError,
+		AccessChecker: checker,
+	}
+
+	tests := []struct {
+		name       string
+		userID     string
+		isAdmin    bool
+		permission string
+		checkErr   error
+		wantStatus int
+	}{
+		{name: "owner can access", userID: "athlete-1", permission: "VIEW_LOGS", wantStatus: http.StatusOK},
+		{name: "admin can access", userID: "admin-1", isAdmin: true, permission: "EDIT_MAXES", wantStatus: http.StatusOK},
+		{name: "granted user can access", userID: "coach-1", permission: "VIEW_LOGS", wantStatus: http.StatusOK},
+		{name: "missing permission forbidden", userID: "coach-1", permission: "EDIT_MAXES", wantStatus: http.StatusForbidden},
+		{name: "empty permission is owner only", userID: "coach-1", permission: "", wantStatus: http.StatusForbidden},
+		{name: "other user forbidden", userID: "user-2", permission: "VIEW_LOGS", wantStatus: http.StatusForbidden},
+		{name: "checker error forbidden", userID: "coach-1", permission: "VIEW_LOGS", checkErr: errors.New("database error"), wantStatus: http.StatusForbidden},
+		{name: "no user unauthorized", userID: "", permission: "VIEW_LOGS", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker.err = tt.checkErr
+			checker.calls = 0
+
+			ownerFunc := func(r *http.Request) (string, error) {
+				return "athlete-1", nil
+			}
+			var handlerAllowed bool
+			handler := RequireUserAccess(cfg, tt.permission, ownerFunc)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerAllowed = CanAccessUser(r, "athlete-1", tt.permission)
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			ctx := req.Context()
+			if tt.userID != "" {
+				ctx = context.WithValue(ctx, UserIDKey, tt.userID)
+				ctx = context.WithValue(ctx, IsAdminKey, tt.isAdmin)
+				ctx = withAccessChecker(ctx, checker)
+			}
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.True(t, handlerAllowed)
+				assert.LessOrEqual(t, checker.calls, 1, "handler check should reuse the middleware grant")
+			}
+		})
+	}
+
+	t.Run("RequireAuth provides the checker to handlers", func(t *testing.T) {
+		checker.err = nil
+		validator := newMockSessionValidator()
+		validator.addUser("coach-token", &AuthUser{ID: "coach-1"})
+		authCfg := AuthConfig{
+			WriteError:       errWriter.writeError,
+			SessionValidator: validator,
+			AccessChecker:    checker,
+		}
+
+		var viewLogs, editMaxes bool
+		handler := RequireAuth(authCfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			viewLogs = CanAccessUser(r, "athlete-1", "VIEW_LOGS")
+			editMaxes = CanAccessUser(r, "athlete-1", "EDIT_MAXES")
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer coach-token")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.True(t, viewLogs)
+		assert.False(t, editMaxes)
+	})
+}
+
 func TestContextHelpers(t *testing.T) {
 	t.Run("GetUserID from request", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/test", nil)
@@ -540,6 +640,172 @@ func TestContextHelpers(t *testing.T) {
 	})
 }
 
+func TestAllowQueryToken(t *testing.T) {
+	validator := newMockSessionValidator()
+	validator.addUser("valid-token", &AuthUser{ID: "user-123"})
+	validator.addUser("header-token", &AuthUser{ID: "user-456"})
+
+	errWriter := &mockErrorWriter{}
+	cfg := AuthConfig{
+		WriteError:       errWriter.writeError,
+		SessionValidator: validator,
+	}
+
+	var gotUserID, gotQuery string
+	handler := ChainMiddleware(AllowQueryToken("access_token"), RequireAuth(cfg))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserID = GetUserID(r)
+		gotQuery = r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		target     string
+		authHeader string
+		wantStatus int
+		wantUserID string
+		wantQuery  string
+	}{
+		{
+			name:       "query token authenticates user",
+			target:     "/stream?access_token=valid-token&lastEventId=4",
+			wantStatus: http.StatusOK,
+			wantUserID: "user-123",
+			wantQuery:  "lastEventId=4",
+		},
+		{
+			name:       "authorization header takes precedence",
+			target:     "/stream?access_token=valid-token",
+			authHeader: "Bearer header-token",
+			wantStatus: http.StatusOK,
+			wantUserID: "user-456",
+		},
+		{
+			name:       "invalid query token returns 401",
+			target:     "/stream?access_token=invalid-token",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "missing token returns 401",
+			target:     "/stream",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUserID, gotQuery = "", ""
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.wantUserID, gotUserID)
+				assert.Equal(t, tt.wantQuery, gotQuery)
+			}
+		})
+	}
+}
+
+func TestRevalidate(t *testing.T) {
+	validator := newMockSessionValidator()
+	validator.addUser("stream-token", &AuthUser{ID: "user-123"})
+
+	errWriter := &mockErrorWriter{}
+	cfg := AuthConfig{
+		WriteError:       errWriter.writeError,
+		SessionValidator: validator,
+	}
+
+	var authed *http.Request
+	handler := RequireAuth(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authed = r
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req.Header.Set("Authorization", "Bearer stream-token")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.NotNil(t, authed)
+
+	assert.NoError(t, Revalidate(authed))
+
+	validator.addUser("stream-token", &AuthUser{ID: "user-456"})
+	assert.ErrorIs(t, Revalidate(authed), ErrCredentialChanged)
+
+	delete(validator.users, "stream-token")
+	assert.Error(t, Revalidate(authed))
+
+	t.Run("requests without a token pass", func(t *testing.T) {
+		assert.NoError(t, Revalidate(httptest.NewRequest(http.MethodGet, "/stream", nil)))
+	})
+}
+
+func TestAllowScope(t *testing.T) {
+	validator := newMockSessionValidator()
+	validator.addUser("session-token", &AuthUser{ID: "user-123"})
+	validator.addUser("logs-token", &AuthUser{ID: "user-123", Scopes: []string{"read:logs"}})
+
+	errWriter := &mockErrorWriter{}
+	cfg := AuthConfig{
+		WriteError:       errWriter.writeError,
+		SessionValidator: validator,
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	routes := map[string]http.Handler{
+		"read:logs":  ChainMiddleware(AllowScope("read:logs"), RequireAuth(cfg))(ok),
+		"write:sets": ChainMiddleware(AllowScope("write:sets"), RequireAuth(cfg))(ok),
+		"none":       RequireAuth(cfg)(ok),
+	}
+
+	tests := []struct {
+		name        string
+		route       string
+		token       string
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "session uses scoped route", route: "write:sets", token: "session-token", wantStatus: http.StatusOK},
+		{name: "session uses unscoped route", route: "none", token: "session-token", wantStatus: http.StatusOK},
+		{name: "token uses route with its scope", route: "read:logs", token: "logs-token", wantStatus: http.StatusOK},
+		{
+			name:        "token without the route's scope returns 403",
+			route:       "write:sets",
+			token:       "logs-token",
+			wantStatus:  http.StatusForbidden,
+			wantMessage: "Access token lacks the write:sets scope",
+		},
+		{
+			name:        "token cannot use unscoped route",
+			route:       "none",
+			token:       "logs-token",
+			wantStatus:  http.StatusForbidden,
+			wantMessage: "Access tokens cannot be used for this route",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errWriter.message = ""
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rr := httptest.NewRecorder()
+			routes[tt.route].ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantMessage != "" {
+				assert.Equal(t, tt.wantMessage, errWriter.message)
+			}
+		})
+	}
+}
+
 func TestChainMiddleware(t *testing.T) {
 	var order []string
 
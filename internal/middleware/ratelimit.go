@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	clientIPKey contextKey = "client_ip"
+
+	// sweepInterval is how often idle buckets are dropped so the limiter's memory stays
+	// bounded by the clients seen recently.
+	sweepInterval = time.Minute
+)
+
+// RateLimit is a token bucket allowance: bursts of up to Requests requests, refilled
+// evenly over Per. A zero RateLimit disables limiting.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// PerMinute returns a RateLimit of n requests a minute, or a disabled one for n <= 0.
+func PerMinute(n int) RateLimit {
+	if n <= 0 {
+		return RateLimit{}
+	}
+	return RateLimit{Requests: n, Per: time.Minute}
+}
+
+// Enabled reports whether the limit restricts anything.
+func (l RateLimit) Enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+// bucket tracks one client's tokens within a route group.
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  RateLimit
+}
+
+// RateLimiter counts requests in token buckets keyed by route group and client: the
+// authenticated user when there is one, otherwise the client IP. Buckets live in memory,
+// so limits apply per server process.
+type RateLimiter struct {
+	writeError func(w http.ResponseWriter, status int, message string)
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewRateLimiter creates a RateLimiter that reports rejected requests with writeError.
+func NewRateLimiter(writeError func(w http.ResponseWriter, status int, message string)) *RateLimiter {
+	return &RateLimiter{
+		writeError: writeError,
+		buckets:    make(map[string]*bucket),
+		now:        time.Now,
+	}
+}
+
+// Allow takes a token from the bucket for key. When the bucket is empty it returns false
+// and how long until a token is available.
+func (l *RateLimiter) Allow(key string, limit RateLimit) (bool, time.Duration) {
+	if !limit.Enabled() {
+		return true, 0
+	}
+	rate := float64(limit.Requests) / limit.Per.Seconds()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{tokens: float64(limit.Requests), last: now, limit: limit}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(limit.Requests), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep drops buckets that have refilled completely, since a new bucket is the same.
+// Callers hold l.mu.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= b.limit.Per {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Limit creates middleware that applies limit to requests in the route group. Used after
+// RequireAuth, each user gets their own bucket; otherwise clients are told apart by IP.
+func (l *RateLimiter) Limit(group string, limit RateLimit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !limit.Enabled() {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !l.allowRequest(w, r, group, limit) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// LimitByMethod creates middleware that applies the read limit to GET, HEAD and OPTIONS
+// requests and the write limit to all others, in the "read" and "write" route groups.
+func (l *RateLimiter) LimitByMethod(read, write RateLimit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !read.Enabled() && !write.Enabled() {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			group, limit := "write", write
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				group, limit = "read", read
+			}
+			if !l.allowRequest(w, r, group, limit) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// allowRequest takes a token for the request, writing a 429 response when there is none.
+func (l *RateLimiter) allowRequest(w http.ResponseWriter, r *http.Request, group string, limit RateLimit) bool {
+	client := "ip:" + ClientIP(r)
+	if userID := GetUserID(r); userID != "" {
+		client = "user:" + userID
+	}
+
+	allowed, wait := l.Allow(group+"|"+client, limit)
+	if allowed {
+		return true
+	}
+
+	seconds := int(math.Ceil(wait.Seconds()))
+	log.Printf("RATE LIMIT: Rejected %s %s from %s - %s limit reached", r.Method, r.URL.Path, client, group)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	l.writeError(w, http.StatusTooManyRequests, fmt.Sprintf("Too many requests; try again in %d seconds", seconds))
+	return false
+}
+
+// ClientAddress creates middleware that records the client IP for ClientIP. With
+// trustProxy, the IP is taken from the last X-Forwarded-For entry, as added by a single
+// reverse proxy in front of the server; otherwise it is the connection's remote address.
+// Only trust the header when every request passes through such a proxy, since clients
+// can set it themselves.
+func ClientAddress(trustProxy bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := remoteIP(r.RemoteAddr)
+			if trustProxy {
+				if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+					entries := strings.Split(forwarded, ",")
+					if last := strings.TrimSpace(entries[len(entries)-1]); last != "" {
+						ip = last
+					}
+				}
+			}
+			ctx := context.WithValue(r.Context(), clientIPKey, ip)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ClientIP returns the client IP recorded by ClientAddress, falling back to the
+// connection's remote address.
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey).(string); ok {
+		return ip
+	}
+	return remoteIP(r.RemoteAddr)
+}
+
+func remoteIP(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
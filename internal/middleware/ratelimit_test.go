@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRateLimiter() (*RateLimiter, *time.Time) {
+	limiter := NewRateLimiter((&mockErrorWriter{}).writeError)
+	clock := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return clock }
+	return limiter, &clock
+}
+
+func TestRateLimiter_Allow(t *testing.T) {
+	limiter, clock := newTestRateLimiter()
+	limit := RateLimit{Requests: 3, Per: time.Minute}
+
+	for i := 0; i < 3; i++ {
+		allowed, _ := limiter.Allow("client", limit)
+		assert.True(t, allowed, "request %d is within the burst", i+1)
+	}
+	allowed, wait := limiter.Allow("client", limit)
+	assert.False(t, allowed)
+	assert.Equal(t, 20*time.Second, wait)
+
+	allowed, _ = limiter.Allow("other-client", limit)
+	assert.True(t, allowed, "buckets are per key")
+
+	*clock = clock.Add(20 * time.Second)
+	allowed, _ = limiter.Allow("client", limit)
+	assert.True(t, allowed, "a token refills every 20 seconds")
+	allowed, _ = limiter.Allow("client", limit)
+	assert.False(t, allowed)
+
+	allowed, _ = limiter.Allow("client", RateLimit{})
+	assert.True(t, allowed, "zero limits are disabled")
+}
+
+func TestRateLimiter_SweepsIdleBuckets(t *testing.T) {
+	limiter, clock := newTestRateLimiter()
+	limit := RateLimit{Requests: 1, Per: time.Minute}
+
+	limiter.Allow("a", limit)
+	limiter.Allow("b", limit)
+	assert.Len(t, limiter.buckets, 2)
+
+	*clock = clock.Add(2 * time.Minute)
+	limiter.Allow("c", limit)
+	assert.Len(t, limiter.buckets, 1)
+}
+
+func TestRateLimiter_Limit(t *testing.T) {
+	limiter, _ := newTestRateLimiter()
+	handler := limiter.Limit("auth", RateLimit{Requests: 1, Per: time.Minute})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := func(remoteAddr, userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+		req.RemoteAddr = remoteAddr
+		if userID != "" {
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, userID))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:1234", "").Code)
+	rec := request("10.0.0.1:5678", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, request("10.0.0.2:1234", "").Code)
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:1234", "user-1").Code, "authenticated users have their own bucket")
+}
+
+func TestRateLimiter_LimitByMethod(t *testing.T) {
+	limiter, _ := newTestRateLimiter()
+	handler := limiter.LimitByMethod(RateLimit{Requests: 2, Per: time.Minute}, RateLimit{Requests: 1, Per: time.Minute})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	request := func(method string) int {
+		req := httptest.NewRequest(method, "/lifts", nil)
+		req = req.WithContext(context.WithValue(req.Context(), UserIDKey, "user-1"))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request(http.MethodPost))
+	assert.Equal(t, http.StatusTooManyRequests, request(http.MethodPut))
+	assert.Equal(t, http.StatusOK, request(http.MethodGet), "reads have their own limit")
+	assert.Equal(t, http.StatusOK, request(http.MethodGet))
+	assert.Equal(t, http.StatusTooManyRequests, request(http.MethodGet))
+}
+
+func TestClientAddress(t *testing.T) {
+	tests := []struct {
+		name       string
+		trustProxy bool
+		forwarded  string
+		want       string
+	}{
+		{name: "remote address", want: "10.0.0.1"},
+		{name: "ignores untrusted header", forwarded: "203.0.113.9", want: "10.0.0.1"},
+		{name: "trusted proxy", trustProxy: true, forwarded: "198.51.100.7, 203.0.113.9", want: "203.0.113.9"},
+		{name: "trusted proxy without header", trustProxy: true, want: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := ClientAddress(tt.trustProxy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ClientIP(r)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "10.0.0.1:4321"
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,406 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clockSkew is how far the provider's clock may be ahead of or behind ours.
+const clockSkew = time.Minute
+
+// providerNamePattern restricts provider names to what can appear in a URL path segment.
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// ProviderConfig configures an OpenID Connect identity provider.
+type ProviderConfig struct {
+	// Name identifies the provider in URLs, such as "google".
+	Name string `json:"name"`
+	// DisplayName is shown to users, such as "Google". Defaults to Name.
+	DisplayName string `json:"displayName,omitempty"`
+	// Issuer is the provider's issuer URL. Its discovery document is read from
+	// {issuer}/.well-known/openid-configuration.
+	Issuer       string `json:"issuer"`
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	// RedirectURL is where the provider sends users back to: this API's
+	// /auth/oidc/{name}/callback, or a client app that passes the code and state on to it.
+	RedirectURL string `json:"redirectUrl"`
+	// Scopes are requested in addition to openid. Defaults to email and profile.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// LoadProviders reads provider configurations from a JSON file holding an array of them.
+func LoadProviders(path string) ([]ProviderConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OIDC provider config: %w", err)
+	}
+	var configs []ProviderConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse OIDC provider config: %w", err)
+	}
+	if err := validateConfigs(configs); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// validateConfigs checks each provider has the required settings and a unique name.
+func validateConfigs(configs []ProviderConfig) error {
+	seen := make(map[string]bool, len(configs))
+	for i, cfg := range configs {
+		if !providerNamePattern.MatchString(cfg.Name) {
+			return fmt.Errorf("OIDC provider %d: name %q must be lowercase letters, digits and hyphens", i, cfg.Name)
+		}
+		if seen[cfg.Name] {
+			return fmt.Errorf("OIDC provider %q is configured more than once", cfg.Name)
+		}
+		seen[cfg.Name] = true
+		if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+			return fmt.Errorf("OIDC provider %q: issuer, clientId and redirectUrl are required", cfg.Name)
+		}
+	}
+	return nil
+}
+
+// metadata holds the parts of a provider's discovery document the relying party uses.
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Claims are the ID token claims used to identify the user.
+type Claims struct {
+	Issuer        string       `json:"iss"`
+	Subject       string       `json:"sub"`
+	Audience      audience     `json:"aud"`
+	Expiry        int64        `json:"exp"`
+	IssuedAt      int64        `json:"iat"`
+	Nonce         string       `json:"nonce"`
+	Email         string       `json:"email"`
+	EmailVerified flexibleBool `json:"email_verified"`
+	Name          string       `json:"name"`
+}
+
+// audience is the aud claim, which is a string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a audience) contains(clientID string) bool {
+	for _, aud := range a {
+		if aud == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// flexibleBool accepts true and "true"; some providers send email_verified as a string.
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case bool:
+		*b = flexibleBool(t)
+	case string:
+		*b = flexibleBool(strings.EqualFold(t, "true"))
+	default:
+		*b = false
+	}
+	return nil
+}
+
+// provider talks to one identity provider. Its discovery document and signing keys are
+// fetched on first use and cached.
+type provider struct {
+	config ProviderConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     map[string]*rsa.PublicKey
+}
+
+func newProvider(config ProviderConfig, client *http.Client) *provider {
+	if config.DisplayName == "" {
+		config.DisplayName = config.Name
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"email", "profile"}
+	}
+	return &provider{config: config, client: client}
+}
+
+// discover returns the provider's metadata, fetching the discovery document once.
+func (p *provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	issuer := strings.TrimSuffix(p.config.Issuer, "/")
+	var md metadata
+	if err := p.getJSON(ctx, issuer+"/.well-known/openid-configuration", &md); err != nil {
+		return nil, fmt.Errorf("discovery failed: %w", err)
+	}
+	if strings.TrimSuffix(md.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("discovery document issuer %q does not match %q", md.Issuer, p.config.Issuer)
+	}
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, errors.New("discovery document is missing endpoints")
+	}
+	p.metadata = &md
+	return p.metadata, nil
+}
+
+// authCodeURL returns the URL that starts the authorization code flow with PKCE.
+func (p *provider) authCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(md.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.config.ClientID)
+	q.Set("redirect_uri", p.config.RedirectURL)
+	q.Set("scope", strings.Join(append([]string{"openid"}, p.config.Scopes...), " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// exchange redeems an authorization code for the provider's ID token.
+func (p *provider) exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid token response (status %d): %w", resp.StatusCode, err)
+	}
+	if body.Error != "" {
+		if body.ErrorDescription != "" {
+			return "", fmt.Errorf("token request rejected: %s: %s", body.Error, body.ErrorDescription)
+		}
+		return "", fmt.Errorf("token request rejected: %s", body.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request failed with status %d", resp.StatusCode)
+	}
+	if body.IDToken == "" {
+		return "", errors.New("token response has no id_token")
+	}
+	return body.IDToken, nil
+}
+
+// verifyIDToken checks an ID token's signature and claims and returns its claims.
+func (p *provider) verifyIDToken(ctx context.Context, raw, nonce string, now time.Time) (*Claims, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed id token")
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.New("malformed id token header")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, errors.New("malformed id token header")
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("unsupported id token algorithm %q", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("malformed id token signature")
+	}
+
+	key, err := p.signingKey(ctx, md, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.New("invalid id token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("malformed id token payload")
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errors.New("malformed id token payload")
+	}
+
+	switch {
+	case strings.TrimSuffix(claims.Issuer, "/") != strings.TrimSuffix(md.Issuer, "/"):
+		return nil, errors.New("id token issuer mismatch")
+	case !claims.Audience.contains(p.config.ClientID):
+		return nil, errors.New("id token was not issued for this client")
+	case now.After(time.Unix(claims.Expiry, 0).Add(clockSkew)):
+		return nil, errors.New("id token expired")
+	case claims.IssuedAt != 0 && time.Unix(claims.IssuedAt, 0).After(now.Add(clockSkew)):
+		return nil, errors.New("id token issued in the future")
+	case claims.Nonce != nonce:
+		return nil, errors.New("id token nonce mismatch")
+	case claims.Subject == "":
+		return nil, errors.New("id token has no subject")
+	}
+	return &claims, nil
+}
+
+// signingKey returns the key with the ID, refetching the key set once when the provider
+// has rotated its keys. Tokens without a key ID need a key set holding a single key.
+func (p *provider) signingKey(ctx context.Context, md *metadata, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if p.keys == nil || attempt == 1 {
+			keys, err := p.fetchKeys(ctx, md.JWKSURI)
+			if err != nil {
+				return nil, err
+			}
+			p.keys = keys
+		}
+		if key, ok := p.keys[kid]; ok {
+			return key, nil
+		}
+		if kid == "" && len(p.keys) == 1 {
+			for _, key := range p.keys {
+				return key, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown id token signing key %q", kid)
+}
+
+// fetchKeys reads the provider's RSA signing keys from its JWK set.
+func (p *provider) fetchKeys(ctx context.Context, jwksURI string) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := p.getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+func (p *provider) getJSON(ctx context.Context, rawURL string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned status %d", rawURL, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
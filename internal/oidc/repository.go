@@ -0,0 +1,176 @@
+package oidc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// SQLiteRepository implements Repository using SQLite.
+type SQLiteRepository struct {
+	db *sql.DB
+	// tx, when set, is the transaction the repository runs in.
+	tx *sql.Tx
+}
+
+// NewSQLiteRepository creates a new SQLite-backed identity repository.
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// WithTx returns a repository that runs its queries in tx.
+func (r *SQLiteRepository) WithTx(tx *sql.Tx) Repository {
+	return &SQLiteRepository{db: r.db, tx: tx}
+}
+
+// conn returns the repository's transaction, or else its database.
+func (r *SQLiteRepository) conn() db.DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const identityColumns = `id, user_id, provider, subject, email, created_at, last_login_at`
+
+// CreateLoginState inserts a login state.
+func (r *SQLiteRepository) CreateLoginState(ctx context.Context, state *LoginState) error {
+	if _, err := r.conn().ExecContext(ctx, `
+		INSERT INTO oidc_login_states (state, provider, nonce, code_verifier, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, state.State, state.Provider, state.Nonce, state.CodeVerifier,
+		state.ExpiresAt.Format(time.RFC3339), state.CreatedAt.Format(time.RFC3339)); err != nil {
+		return apperrors.NewInternal("failed to create login state", err)
+	}
+	return nil
+}
+
+// TakeLoginState deletes and returns a login state, or nil when there is none.
+func (r *SQLiteRepository) TakeLoginState(ctx context.Context, state string) (*LoginState, error) {
+	var ls LoginState
+	var expiresAt, createdAt string
+	err := r.conn().QueryRowContext(ctx, `
+		DELETE FROM oidc_login_states WHERE state = ?
+		RETURNING state, provider, nonce, code_verifier, expires_at, created_at
+	`, state).Scan(&ls.State, &ls.Provider, &ls.Nonce, &ls.CodeVerifier, &expiresAt, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve login state", err)
+	}
+	ls.ExpiresAt, _ = time.Parse(time.RFC3339, expiresAt)
+	ls.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &ls, nil
+}
+
+// DeleteExpiredLoginStates deletes login states that expired before the given time.
+func (r *SQLiteRepository) DeleteExpiredLoginStates(ctx context.Context, before time.Time) error {
+	if _, err := r.conn().ExecContext(ctx, `
+		DELETE FROM oidc_login_states WHERE expires_at < ?
+	`, before.Format(time.RFC3339)); err != nil {
+		return apperrors.NewInternal("failed to delete expired login states", err)
+	}
+	return nil
+}
+
+// GetIdentity returns the identity for a provider's subject, or nil when there is none.
+func (r *SQLiteRepository) GetIdentity(ctx context.Context, provider, subject string) (*Identity, error) {
+	row := r.conn().QueryRowContext(ctx, `
+		SELECT `+identityColumns+` FROM user_identities WHERE provider = ? AND subject = ?
+	`, provider, subject)
+	identity, err := scanIdentity(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve identity", err)
+	}
+	return identity, nil
+}
+
+// CreateIdentity inserts an identity.
+func (r *SQLiteRepository) CreateIdentity(ctx context.Context, identity *Identity) error {
+	if _, err := r.conn().ExecContext(ctx, `
+		INSERT INTO user_identities (`+identityColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, identity.ID, identity.UserID, identity.Provider, identity.Subject, identity.Email,
+		identity.CreatedAt.Format(time.RFC3339), identity.LastLoginAt.Format(time.RFC3339)); err != nil {
+		return apperrors.NewInternal("failed to link identity", err)
+	}
+	return nil
+}
+
+// UpdateIdentityLogin saves an identity's email and last sign-in time.
+func (r *SQLiteRepository) UpdateIdentityLogin(ctx context.Context, identity *Identity) error {
+	if _, err := r.conn().ExecContext(ctx, `
+		UPDATE user_identities SET email = ?, last_login_at = ? WHERE id = ?
+	`, identity.Email, identity.LastLoginAt.Format(time.RFC3339), identity.ID); err != nil {
+		return apperrors.NewInternal("failed to update identity", err)
+	}
+	return nil
+}
+
+// ListIdentities returns a user's identities in the order they were linked.
+func (r *SQLiteRepository) ListIdentities(ctx context.Context, userID string) ([]Identity, error) {
+	rows, err := r.conn().QueryContext(ctx, `
+		SELECT `+identityColumns+` FROM user_identities WHERE user_id = ?
+		ORDER BY created_at ASC, rowid ASC
+	`, userID)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list identities", err)
+	}
+	defer rows.Close()
+
+	identities := []Identity{}
+	for rows.Next() {
+		identity, err := scanIdentity(rows)
+		if err != nil {
+			return nil, apperrors.NewInternal("failed to list identities", err)
+		}
+		identities = append(identities, *identity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewInternal("failed to list identities", err)
+	}
+	return identities, nil
+}
+
+// DeleteIdentity deletes an identity.
+func (r *SQLiteRepository) DeleteIdentity(ctx context.Context, id string) error {
+	res, err := r.conn().ExecContext(ctx, `DELETE FROM user_identities WHERE id = ?`, id)
+	if err != nil {
+		return apperrors.NewInternal("failed to unlink identity", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NewNotFound("identity", id)
+	}
+	return nil
+}
+
+// rowScanner abstracts *sql.Row and *sql.Rows for scanning.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanIdentity scans a single identity row.
+func scanIdentity(row rowScanner) (*Identity, error) {
+	var identity Identity
+	var email sql.NullString
+	var createdAt, lastLoginAt string
+
+	if err := row.Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject,
+		&email, &createdAt, &lastLoginAt); err != nil {
+		return nil, err
+	}
+	if email.Valid {
+		identity.Email = &email.String
+	}
+	identity.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	identity.LastLoginAt, _ = time.Parse(time.RFC3339, lastLoginAt)
+
+	return &identity, nil
+}
@@ -0,0 +1,292 @@
+// Package oidc provides single sign-on through external OpenID Connect identity providers.
+// It is a relying party for the authorization code flow with PKCE: users are sent to the
+// provider, and the code it returns is exchanged for an ID token that identifies them.
+// Identities are linked to users by provider and subject; a first sign-in links to the
+// account with the provider-verified email, or provisions a new account.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/auth"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+const (
+	// loginStateLifetime is how long a user has to complete a sign-in at the provider.
+	loginStateLifetime = 10 * time.Minute
+	// secretBytes is the number of random bytes in states, nonces and PKCE verifiers.
+	secretBytes = 32
+)
+
+// Identity links a user to their account at an identity provider.
+type Identity struct {
+	ID       string
+	UserID   string
+	Provider string
+	// Subject is the provider's identifier for the user, unique within the provider.
+	Subject     string
+	Email       *string
+	CreatedAt   time.Time
+	LastLoginAt time.Time
+}
+
+// LoginState holds the secrets of a sign-in between the redirect to the provider and its
+// callback.
+type LoginState struct {
+	State        string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// Repository defines the interface for identity and login state persistence.
+type Repository interface {
+	CreateLoginState(ctx context.Context, state *LoginState) error
+	// TakeLoginState deletes and returns a login state, or nil when there is none, so each
+	// state can complete at most one sign-in.
+	TakeLoginState(ctx context.Context, state string) (*LoginState, error)
+	DeleteExpiredLoginStates(ctx context.Context, before time.Time) error
+	// GetIdentity returns the identity for a provider's subject, or nil when there is none.
+	GetIdentity(ctx context.Context, provider, subject string) (*Identity, error)
+	CreateIdentity(ctx context.Context, identity *Identity) error
+	UpdateIdentityLogin(ctx context.Context, identity *Identity) error
+	// ListIdentities returns a user's identities in the order they were linked.
+	ListIdentities(ctx context.Context, userID string) ([]Identity, error)
+	DeleteIdentity(ctx context.Context, id string) error
+	// WithTx returns a repository that runs its queries in tx.
+	WithTx(tx *sql.Tx) Repository
+}
+
+// Service signs users in through the configured identity providers.
+type Service struct {
+	repo        Repository
+	authService *auth.Service
+	providers   map[string]*provider
+	// names lists the providers in configuration order.
+	names []string
+	now   func() time.Time
+}
+
+// NewService creates a new single sign-on service for the providers. A nil client means
+// a client with a 10 second timeout.
+func NewService(repo Repository, authService *auth.Service, configs []ProviderConfig, client *http.Client) *Service {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	s := &Service{
+		repo:        repo,
+		authService: authService,
+		providers:   make(map[string]*provider, len(configs)),
+		now:         time.Now,
+	}
+	for _, cfg := range configs {
+		s.providers[cfg.Name] = newProvider(cfg, client)
+		s.names = append(s.names, cfg.Name)
+	}
+	return s
+}
+
+// WithTx returns a copy of the service whose repository runs in tx.
+func (s *Service) WithTx(tx *sql.Tx) *Service {
+	txService := *s
+	txService.repo = s.repo.WithTx(tx)
+	return &txService
+}
+
+// Providers returns the configured providers in configuration order, without secrets.
+func (s *Service) Providers() []ProviderConfig {
+	configs := make([]ProviderConfig, len(s.names))
+	for i, name := range s.names {
+		cfg := s.providers[name].config
+		cfg.ClientSecret = ""
+		configs[i] = cfg
+	}
+	return configs
+}
+
+// Begin starts a sign-in with the provider and returns the URL to send the user to.
+func (s *Service) Begin(ctx context.Context, providerName string) (string, error) {
+	p, ok := s.providers[providerName]
+	if !ok {
+		return "", apperrors.NewNotFound("identity provider", providerName)
+	}
+
+	state, err := randomSecret()
+	if err != nil {
+		return "", apperrors.NewInternal("failed to generate login state", err)
+	}
+	nonce, err := randomSecret()
+	if err != nil {
+		return "", apperrors.NewInternal("failed to generate nonce", err)
+	}
+	verifier, err := randomSecret()
+	if err != nil {
+		return "", apperrors.NewInternal("failed to generate code verifier", err)
+	}
+
+	authURL, err := p.authCodeURL(ctx, state, nonce, codeChallenge(verifier))
+	if err != nil {
+		return "", apperrors.NewInternal("identity provider is unavailable", err)
+	}
+
+	now := s.now().UTC()
+	if err := s.repo.DeleteExpiredLoginStates(ctx, now); err != nil {
+		return "", err
+	}
+	if err := s.repo.CreateLoginState(ctx, &LoginState{
+		State:        state,
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    now.Add(loginStateLifetime),
+		CreatedAt:    now,
+	}); err != nil {
+		return "", err
+	}
+	return authURL, nil
+}
+
+// Complete finishes a sign-in with the code and state the provider returned, and starts a
+// session for the identity's user, linking or provisioning the user on first sign-in.
+func (s *Service) Complete(ctx context.Context, providerName, code, state string) (*auth.LoginResult, error) {
+	p, ok := s.providers[providerName]
+	if !ok {
+		return nil, apperrors.NewNotFound("identity provider", providerName)
+	}
+	if code == "" || state == "" {
+		return nil, apperrors.NewBadRequest("code and state are required")
+	}
+
+	login, err := s.repo.TakeLoginState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	if login == nil || login.Provider != providerName || now.After(login.ExpiresAt) {
+		return nil, apperrors.NewUnauthorized("invalid or expired login state")
+	}
+
+	rawIDToken, err := p.exchange(ctx, code, login.CodeVerifier)
+	if err != nil {
+		return nil, apperrors.NewUnauthorized("single sign-on failed: " + err.Error())
+	}
+	claims, err := p.verifyIDToken(ctx, rawIDToken, login.Nonce, now)
+	if err != nil {
+		return nil, apperrors.NewUnauthorized("single sign-on failed: " + err.Error())
+	}
+
+	userID, err := s.resolveUser(ctx, providerName, claims, now)
+	if err != nil {
+		return nil, err
+	}
+	return s.authService.StartSession(ctx, userID)
+}
+
+// resolveUser returns the user linked to the claims' identity, linking the identity to
+// the account with its verified email or to a new account the first time it signs in.
+func (s *Service) resolveUser(ctx context.Context, providerName string, claims *Claims, now time.Time) (string, error) {
+	var email *string
+	if e := strings.ToLower(strings.TrimSpace(claims.Email)); e != "" {
+		email = &e
+	}
+
+	identity, err := s.repo.GetIdentity(ctx, providerName, claims.Subject)
+	if err != nil {
+		return "", err
+	}
+	if identity != nil {
+		identity.Email = email
+		identity.LastLoginAt = now
+		if err := s.repo.UpdateIdentityLogin(ctx, identity); err != nil {
+			return "", err
+		}
+		return identity.UserID, nil
+	}
+
+	if email == nil {
+		return "", apperrors.NewUnauthorized("the identity provider did not share an email address")
+	}
+	user, err := s.authService.FindUserByEmail(ctx, *email)
+	if err != nil {
+		return "", err
+	}
+	if user != nil && !bool(claims.EmailVerified) {
+		return "", apperrors.NewConflict("an account with this email already exists and the identity provider has not verified the email")
+	}
+	if user == nil {
+		if user, err = s.authService.ProvisionUser(ctx, *email, claims.Name); err != nil {
+			return "", err
+		}
+	}
+
+	if err := s.repo.CreateIdentity(ctx, &Identity{
+		ID:          uuid.New().String(),
+		UserID:      user.ID,
+		Provider:    providerName,
+		Subject:     claims.Subject,
+		Email:       email,
+		CreatedAt:   now,
+		LastLoginAt: now,
+	}); err != nil {
+		return "", err
+	}
+	return user.ID, nil
+}
+
+// ListIdentities returns a user's linked identities in the order they were linked.
+func (s *Service) ListIdentities(ctx context.Context, userID string) ([]Identity, error) {
+	return s.repo.ListIdentities(ctx, userID)
+}
+
+// Unlink removes one of a user's identities. A user without a password keeps at least one
+// identity, so they can still sign in.
+func (s *Service) Unlink(ctx context.Context, userID, identityID string) error {
+	identities, err := s.repo.ListIdentities(ctx, userID)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, identity := range identities {
+		found = found || identity.ID == identityID
+	}
+	if !found {
+		return apperrors.NewNotFound("identity", identityID)
+	}
+
+	if len(identities) == 1 {
+		hasPassword, err := s.authService.HasPassword(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !hasPassword {
+			return apperrors.NewConflict("cannot unlink the only way to sign in to this account")
+		}
+	}
+	return s.repo.DeleteIdentity(ctx, identityID)
+}
+
+// randomSecret returns a URL-safe random string.
+func randomSecret() (string, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// codeChallenge returns the S256 PKCE challenge for a verifier.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
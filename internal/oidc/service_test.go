@@ -0,0 +1,198 @@
+package oidc
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waynenilsen/power-pro-v3/internal/auth"
+	"github.com/waynenilsen/power-pro-v3/internal/database"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/oidc/oidctest"
+)
+
+const (
+	testIssuer      = "http://idp.test"
+	testRedirectURL = "http://powerpro.test/auth/oidc/test-idp/callback"
+)
+
+func setupTestService(t *testing.T) (*Service, *oidctest.Provider, *auth.Service, *sql.DB, func()) {
+	sqlDB, cleanup, err := database.OpenTemp("../../migrations")
+	require.NoError(t, err)
+
+	idp := oidctest.NewProvider(testIssuer, "powerpro", "client-secret")
+	authService := auth.NewService(auth.NewSQLiteUserRepository(sqlDB), auth.NewSQLiteSessionRepository(sqlDB))
+	svc := NewService(NewSQLiteRepository(sqlDB), authService, []ProviderConfig{{
+		Name:         "test-idp",
+		DisplayName:  "Test IdP",
+		Issuer:       testIssuer,
+		ClientID:     "powerpro",
+		ClientSecret: "client-secret",
+		RedirectURL:  testRedirectURL,
+	}}, idp.Client())
+	return svc, idp, authService, sqlDB, cleanup
+}
+
+// signIn runs a sign-in through the stand-in provider and returns the callback parameters.
+func signIn(t *testing.T, svc *Service, idp *oidctest.Provider) url.Values {
+	t.Helper()
+	authURL, err := svc.Begin(context.Background(), "test-idp")
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	q := parsed.Query()
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	assert.Equal(t, "openid email profile", q.Get("scope"))
+	assert.Equal(t, testRedirectURL, q.Get("redirect_uri"))
+
+	callback, err := idp.SignIn(authURL)
+	require.NoError(t, err)
+	return callback.Query()
+}
+
+func TestSignIn(t *testing.T) {
+	svc, idp, authService, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var userID string
+
+	t.Run("first sign-in provisions an account", func(t *testing.T) {
+		idp.SetUser(oidctest.User{Subject: "sub-1", Email: "New.Lifter@Example.com", EmailVerified: true, Name: "New Lifter"})
+		params := signIn(t, svc, idp)
+
+		result, err := svc.Complete(ctx, "test-idp", params.Get("code"), params.Get("state"))
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.Token)
+		assert.Equal(t, "new.lifter@example.com", result.User.Email)
+		assert.Equal(t, "New Lifter", result.User.Name)
+		userID = result.User.ID
+
+		user, err := authService.ValidateSession(ctx, result.Token)
+		require.NoError(t, err)
+		assert.Equal(t, userID, user.ID)
+
+		hasPassword, err := authService.HasPassword(ctx, userID)
+		require.NoError(t, err)
+		assert.False(t, hasPassword)
+	})
+
+	t.Run("later sign-ins find the linked identity", func(t *testing.T) {
+		idp.SetUser(oidctest.User{Subject: "sub-1", Email: "renamed@example.com", EmailVerified: true})
+		params := signIn(t, svc, idp)
+
+		result, err := svc.Complete(ctx, "test-idp", params.Get("code"), params.Get("state"))
+		require.NoError(t, err)
+		assert.Equal(t, userID, result.User.ID)
+
+		identities, err := svc.ListIdentities(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, identities, 1)
+		assert.Equal(t, "renamed@example.com", *identities[0].Email)
+	})
+
+	t.Run("states cannot be reused", func(t *testing.T) {
+		idp.SetUser(oidctest.User{Subject: "sub-1", Email: "renamed@example.com", EmailVerified: true})
+		params := signIn(t, svc, idp)
+		_, err := svc.Complete(ctx, "test-idp", params.Get("code"), params.Get("state"))
+		require.NoError(t, err)
+
+		_, err = svc.Complete(ctx, "test-idp", params.Get("code"), params.Get("state"))
+		assert.True(t, apperrors.IsUnauthorized(err))
+	})
+
+	t.Run("expired states are rejected", func(t *testing.T) {
+		params := signIn(t, svc, idp)
+		svc.now = func() time.Time { return time.Now().Add(loginStateLifetime + time.Minute) }
+		defer func() { svc.now = time.Now }()
+
+		_, err := svc.Complete(ctx, "test-idp", params.Get("code"), params.Get("state"))
+		assert.True(t, apperrors.IsUnauthorized(err))
+	})
+
+	t.Run("verified email links an existing account", func(t *testing.T) {
+		registered, err := authService.Register(ctx, auth.RegisterRequest{Email: "member@example.com", Password: "password123"})
+		require.NoError(t, err)
+
+		idp.SetUser(oidctest.User{Subject: "sub-unverified", Email: "member@example.com", EmailVerified: false})
+		params := signIn(t, svc, idp)
+		_, err = svc.Complete(ctx, "test-idp", params.Get("code"), params.Get("state"))
+		assert.True(t, apperrors.IsConflict(err))
+
+		idp.SetUser(oidctest.User{Subject: "sub-2", Email: "member@example.com", EmailVerified: true})
+		params = signIn(t, svc, idp)
+		result, err := svc.Complete(ctx, "test-idp", params.Get("code"), params.Get("state"))
+		require.NoError(t, err)
+		assert.Equal(t, registered.User.ID, result.User.ID)
+
+		// Users with a password can unlink their only identity
+		identities, err := svc.ListIdentities(ctx, registered.User.ID)
+		require.NoError(t, err)
+		require.Len(t, identities, 1)
+		require.NoError(t, svc.Unlink(ctx, registered.User.ID, identities[0].ID))
+	})
+
+	t.Run("passwordless users keep their last identity", func(t *testing.T) {
+		identities, err := svc.ListIdentities(ctx, userID)
+		require.NoError(t, err)
+
+		err = svc.Unlink(ctx, userID, identities[0].ID)
+		assert.True(t, apperrors.IsConflict(err))
+
+		err = svc.Unlink(ctx, "someone-else", identities[0].ID)
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+
+	t.Run("provider errors and unknown providers", func(t *testing.T) {
+		idp.SetDeny(true)
+		params := signIn(t, svc, idp)
+		idp.SetDeny(false)
+		assert.Equal(t, "access_denied", params.Get("error"))
+
+		_, err := svc.Begin(ctx, "unknown")
+		assert.True(t, apperrors.IsNotFound(err))
+
+		_, err = svc.Complete(ctx, "test-idp", "bogus-code", params.Get("state"))
+		assert.True(t, apperrors.IsUnauthorized(err))
+	})
+
+	t.Run("identities without an email are not provisioned", func(t *testing.T) {
+		idp.SetUser(oidctest.User{Subject: "sub-no-email"})
+		params := signIn(t, svc, idp)
+		_, err := svc.Complete(ctx, "test-idp", params.Get("code"), params.Get("state"))
+		assert.True(t, apperrors.IsUnauthorized(err))
+
+		var count int
+		require.NoError(t, sqlDB.QueryRow(`SELECT COUNT(*) FROM user_identities WHERE subject = 'sub-no-email'`).Scan(&count))
+		assert.Zero(t, count)
+	})
+}
+
+func TestProvidersHideSecrets(t *testing.T) {
+	svc, _, _, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	providers := svc.Providers()
+	require.Len(t, providers, 1)
+	assert.Equal(t, "Test IdP", providers[0].DisplayName)
+	assert.Empty(t, providers[0].ClientSecret)
+}
+
+func TestValidateConfigs(t *testing.T) {
+	valid := ProviderConfig{Name: "gym-idp", Issuer: testIssuer, ClientID: "id", RedirectURL: testRedirectURL}
+	assert.NoError(t, validateConfigs([]ProviderConfig{valid}))
+	assert.Error(t, validateConfigs([]ProviderConfig{valid, valid}))
+
+	badName := valid
+	badName.Name = "Gym IdP"
+	assert.Error(t, validateConfigs([]ProviderConfig{badName}))
+
+	missingIssuer := valid
+	missingIssuer.Issuer = ""
+	assert.Error(t, validateConfigs([]ProviderConfig{missingIssuer}))
+}
@@ -0,0 +1,482 @@
+package organization
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// SQLiteRepository implements Repository using SQLite.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository creates a new SQLite-backed organization repository.
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+const organizationColumns = `id, name, slug, created_by, created_at, updated_at`
+
+const memberColumns = `organization_id, user_id, role, joined_at`
+
+const invitationColumns = `id, organization_id, user_id, role, status, invited_by, created_at, responded_at`
+
+// FindUserIDByEmail returns the ID of the user with the email, ignoring case.
+func (r *SQLiteRepository) FindUserIDByEmail(ctx context.Context, email string) (string, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id FROM users WHERE lower(email) = lower(?)
+	`, email).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", apperrors.NewNotFound("user", email)
+	}
+	if err != nil {
+		return "", apperrors.NewInternal("failed to look up user", err)
+	}
+	return id, nil
+}
+
+// UserExists reports whether a user with the ID exists.
+func (r *SQLiteRepository) UserExists(ctx context.Context, userID string) (bool, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM users WHERE id = ?)
+	`, userID).Scan(&exists); err != nil {
+		return false, apperrors.NewInternal("failed to look up user", err)
+	}
+	return exists, nil
+}
+
+// SlugExists reports whether another organization uses the slug.
+func (r *SQLiteRepository) SlugExists(ctx context.Context, slug, excludeID string) (bool, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM organizations WHERE slug = ? AND id != ?)
+	`, slug, excludeID).Scan(&exists); err != nil {
+		return false, apperrors.NewInternal("failed to check slug uniqueness", err)
+	}
+	return exists, nil
+}
+
+// Create inserts the organization and its first member in one transaction.
+func (r *SQLiteRepository) Create(ctx context.Context, org *Organization, owner *Member) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return apperrors.NewInternal("failed to begin transaction", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO organizations (`+organizationColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, org.ID, org.Name, org.Slug, org.CreatedBy,
+		org.CreatedAt.Format(time.RFC3339), org.UpdatedAt.Format(time.RFC3339)); err != nil {
+		return apperrors.NewInternal("failed to create organization", err)
+	}
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO organization_members (`+memberColumns+`)
+		VALUES (?, ?, ?, ?)
+	`, owner.OrganizationID, owner.UserID, string(owner.Role), owner.JoinedAt.Format(time.RFC3339)); err != nil {
+		return apperrors.NewInternal("failed to add organization owner", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return apperrors.NewInternal("failed to commit transaction", err)
+	}
+	return nil
+}
+
+// Get retrieves an organization by its ID.
+func (r *SQLiteRepository) Get(ctx context.Context, id string) (*Organization, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+organizationColumns+` FROM organizations WHERE id = ?
+	`, id)
+
+	org, err := scanOrganization(row)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("organization", id)
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve organization", err)
+	}
+	return org, nil
+}
+
+// List returns a page of organizations, by name, along with the total count. A non-nil
+// memberID limits the list to that user's organizations.
+func (r *SQLiteRepository) List(ctx context.Context, memberID *string, limit, offset int64) ([]Organization, int64, error) {
+	where := "1 = 1"
+	args := []interface{}{}
+	if memberID != nil {
+		where = "id IN (SELECT organization_id FROM organization_members WHERE user_id = ?)"
+		args = append(args, *memberID)
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM organizations WHERE "+where, args...,
+	).Scan(&total); err != nil {
+		return nil, 0, apperrors.NewInternal("failed to count organizations", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+organizationColumns+` FROM organizations WHERE `+where+`
+		ORDER BY name ASC, rowid ASC
+		LIMIT ? OFFSET ?
+	`, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list organizations", err)
+	}
+	defer rows.Close()
+
+	orgs := []Organization{}
+	for rows.Next() {
+		org, err := scanOrganization(rows)
+		if err != nil {
+			return nil, 0, apperrors.NewInternal("failed to list organizations", err)
+		}
+		orgs = append(orgs, *org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list organizations", err)
+	}
+	return orgs, total, nil
+}
+
+// Update saves an organization's name and slug.
+func (r *SQLiteRepository) Update(ctx context.Context, org *Organization) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE organizations SET name = ?, slug = ?, updated_at = ? WHERE id = ?
+	`, org.Name, org.Slug, org.UpdatedAt.Format(time.RFC3339), org.ID)
+	if err != nil {
+		return apperrors.NewInternal("failed to update organization", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NewNotFound("organization", org.ID)
+	}
+	return nil
+}
+
+// Delete deletes an organization; its memberships are removed with it.
+func (r *SQLiteRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM organizations WHERE id = ?`, id)
+	if err != nil {
+		return apperrors.NewInternal("failed to delete organization", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NewNotFound("organization", id)
+	}
+	return nil
+}
+
+// HasCatalogEntries reports whether the organization owns any lifts, programs or lookups.
+func (r *SQLiteRepository) HasCatalogEntries(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM lifts WHERE organization_id = ?1)
+			OR EXISTS(SELECT 1 FROM programs WHERE organization_id = ?1)
+			OR EXISTS(SELECT 1 FROM weekly_lookups WHERE organization_id = ?1)
+			OR EXISTS(SELECT 1 FROM daily_lookups WHERE organization_id = ?1)
+	`, id).Scan(&exists); err != nil {
+		return false, apperrors.NewInternal("failed to check organization catalog", err)
+	}
+	return exists, nil
+}
+
+// CreateInvitation inserts an invitation.
+func (r *SQLiteRepository) CreateInvitation(ctx context.Context, inv *Invitation) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO organization_invitations (`+invitationColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, inv.ID, inv.OrganizationID, inv.UserID, string(inv.Role), string(inv.Status), inv.InvitedBy,
+		inv.CreatedAt.Format(time.RFC3339), formatOptionalTime(inv.RespondedAt))
+	if err != nil {
+		return apperrors.NewInternal("failed to create organization invitation", err)
+	}
+	return nil
+}
+
+// GetInvitation retrieves an invitation by its ID.
+func (r *SQLiteRepository) GetInvitation(ctx context.Context, id string) (*Invitation, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+invitationColumns+` FROM organization_invitations WHERE id = ?
+	`, id)
+
+	inv, err := scanInvitation(row)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("organization invitation", id)
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve organization invitation", err)
+	}
+	return inv, nil
+}
+
+// GetPendingInvitation returns the user's pending invitation to the organization, or nil
+// when there is none.
+func (r *SQLiteRepository) GetPendingInvitation(ctx context.Context, organizationID, userID string) (*Invitation, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+invitationColumns+` FROM organization_invitations
+		WHERE organization_id = ? AND user_id = ? AND status = 'PENDING'
+	`, organizationID, userID)
+
+	inv, err := scanInvitation(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve organization invitation", err)
+	}
+	return inv, nil
+}
+
+// ListPendingInvitations returns pending invitations, oldest first, to an organization
+// or, when organizationID is empty, to a user.
+func (r *SQLiteRepository) ListPendingInvitations(ctx context.Context, organizationID, userID string) ([]Invitation, error) {
+	where := "status = 'PENDING' AND organization_id = ?"
+	arg := organizationID
+	if organizationID == "" {
+		where = "status = 'PENDING' AND user_id = ?"
+		arg = userID
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+invitationColumns+` FROM organization_invitations WHERE `+where+`
+		ORDER BY created_at ASC, rowid ASC
+	`, arg)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list organization invitations", err)
+	}
+	defer rows.Close()
+
+	invitations := []Invitation{}
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, apperrors.NewInternal("failed to list organization invitations", err)
+		}
+		invitations = append(invitations, *inv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewInternal("failed to list organization invitations", err)
+	}
+	return invitations, nil
+}
+
+// AcceptInvitation saves the accepted invitation and inserts the membership in one
+// transaction.
+func (r *SQLiteRepository) AcceptInvitation(ctx context.Context, inv *Invitation, m *Member) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return apperrors.NewInternal("failed to begin transaction", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE organization_invitations SET status = ?, responded_at = ?
+		WHERE id = ? AND status = 'PENDING'
+	`, string(inv.Status), formatOptionalTime(inv.RespondedAt), inv.ID)
+	if err != nil {
+		return apperrors.NewInternal("failed to update organization invitation", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NewConflict("invitation is no longer PENDING")
+	}
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO organization_members (`+memberColumns+`)
+		VALUES (?, ?, ?, ?)
+	`, m.OrganizationID, m.UserID, string(m.Role), m.JoinedAt.Format(time.RFC3339)); err != nil {
+		return apperrors.NewInternal("failed to add organization member", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return apperrors.NewInternal("failed to commit transaction", err)
+	}
+	return nil
+}
+
+// UpdateInvitation saves an invitation's status and response time.
+func (r *SQLiteRepository) UpdateInvitation(ctx context.Context, inv *Invitation) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE organization_invitations SET status = ?, responded_at = ? WHERE id = ?
+	`, string(inv.Status), formatOptionalTime(inv.RespondedAt), inv.ID)
+	if err != nil {
+		return apperrors.NewInternal("failed to update organization invitation", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NewNotFound("organization invitation", inv.ID)
+	}
+	return nil
+}
+
+// DeleteInvitation deletes an invitation.
+func (r *SQLiteRepository) DeleteInvitation(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM organization_invitations WHERE id = ?`, id)
+	if err != nil {
+		return apperrors.NewInternal("failed to delete organization invitation", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NewNotFound("organization invitation", id)
+	}
+	return nil
+}
+
+// GetMember returns the user's membership, or nil when they are not a member.
+func (r *SQLiteRepository) GetMember(ctx context.Context, organizationID, userID string) (*Member, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+memberColumns+` FROM organization_members
+		WHERE organization_id = ? AND user_id = ?
+	`, organizationID, userID)
+
+	m, err := scanMember(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve organization member", err)
+	}
+	return m, nil
+}
+
+// ListMembers returns an organization's members in the order they joined. A non-nil role
+// limits the list to members with that role.
+func (r *SQLiteRepository) ListMembers(ctx context.Context, organizationID string, role *Role) ([]Member, error) {
+	where := "organization_id = ?"
+	args := []interface{}{organizationID}
+	if role != nil {
+		where += " AND role = ?"
+		args = append(args, string(*role))
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+memberColumns+` FROM organization_members WHERE `+where+`
+		ORDER BY joined_at ASC, rowid ASC
+	`, args...)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list organization members", err)
+	}
+	defer rows.Close()
+
+	members := []Member{}
+	for rows.Next() {
+		m, err := scanMember(rows)
+		if err != nil {
+			return nil, apperrors.NewInternal("failed to list organization members", err)
+		}
+		members = append(members, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewInternal("failed to list organization members", err)
+	}
+	return members, nil
+}
+
+// UpdateMemberRole changes a member's role.
+func (r *SQLiteRepository) UpdateMemberRole(ctx context.Context, organizationID, userID string, role Role) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE organization_members SET role = ? WHERE organization_id = ? AND user_id = ?
+	`, string(role), organizationID, userID)
+	if err != nil {
+		return apperrors.NewInternal("failed to update organization member", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NewNotFound("organization member", userID)
+	}
+	return nil
+}
+
+// RemoveMember deletes a membership.
+func (r *SQLiteRepository) RemoveMember(ctx context.Context, organizationID, userID string) error {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM organization_members WHERE organization_id = ? AND user_id = ?
+	`, organizationID, userID)
+	if err != nil {
+		return apperrors.NewInternal("failed to remove organization member", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NewNotFound("organization member", userID)
+	}
+	return nil
+}
+
+// CountOwners returns the number of owners an organization has.
+func (r *SQLiteRepository) CountOwners(ctx context.Context, organizationID string) (int64, error) {
+	var count int64
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM organization_members WHERE organization_id = ? AND role = 'OWNER'
+	`, organizationID).Scan(&count); err != nil {
+		return 0, apperrors.NewInternal("failed to count organization owners", err)
+	}
+	return count, nil
+}
+
+// rowScanner abstracts *sql.Row and *sql.Rows for scanning.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanOrganization scans a single organization row.
+func scanOrganization(row rowScanner) (*Organization, error) {
+	var org Organization
+	var createdAt, updatedAt string
+
+	if err := row.Scan(&org.ID, &org.Name, &org.Slug, &org.CreatedBy, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	org.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	org.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+
+	return &org, nil
+}
+
+// scanMember scans a single membership row.
+func scanMember(row rowScanner) (*Member, error) {
+	var m Member
+	var role, joinedAt string
+
+	if err := row.Scan(&m.OrganizationID, &m.UserID, &role, &joinedAt); err != nil {
+		return nil, err
+	}
+	m.Role = Role(role)
+	m.JoinedAt, _ = time.Parse(time.RFC3339, joinedAt)
+
+	return &m, nil
+}
+
+// scanInvitation scans a single invitation row.
+func scanInvitation(row rowScanner) (*Invitation, error) {
+	var inv Invitation
+	var role, status, createdAt string
+	var respondedAt sql.NullString
+
+	if err := row.Scan(&inv.ID, &inv.OrganizationID, &inv.UserID, &role, &status, &inv.InvitedBy,
+		&createdAt, &respondedAt); err != nil {
+		return nil, err
+	}
+	inv.Role = Role(role)
+	inv.Status = InvitationStatus(status)
+	inv.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	if respondedAt.Valid {
+		t, _ := time.Parse(time.RFC3339, respondedAt.String)
+		inv.RespondedAt = &t
+	}
+
+	return &inv, nil
+}
+
+// formatOptionalTime formats t for storage, or returns nil when it is unset.
+func formatOptionalTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
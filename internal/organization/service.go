@@ -0,0 +1,529 @@
+// Package organization provides teams and gyms: organizations with members who hold an
+// OWNER, COACH or ATHLETE role. Owners invite users, who become members when they accept.
+// Lifts, programs and lookups can belong to an organization, in which case only its
+// members see them alongside the global catalog, and owners and coaches maintain them.
+package organization
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/validation"
+)
+
+const (
+	// maxNameLength is the maximum allowed length for an organization name.
+	maxNameLength = 100
+	// maxSlugLength is the maximum allowed length for an organization slug.
+	maxSlugLength = 100
+)
+
+// Role is a member's role in an organization.
+type Role string
+
+const (
+	// RoleOwner manages the organization, its members and its catalog.
+	RoleOwner Role = "OWNER"
+	// RoleCoach manages the organization's catalog and views the roster dashboard.
+	RoleCoach Role = "COACH"
+	// RoleAthlete sees the organization's catalog.
+	RoleAthlete Role = "ATHLETE"
+)
+
+// IsValid reports whether the role is known.
+func (r Role) IsValid() bool {
+	return r == RoleOwner || r == RoleCoach || r == RoleAthlete
+}
+
+// CanManageCatalog reports whether the role may create, update and delete the
+// organization's lifts, programs and lookups.
+func (r Role) CanManageCatalog() bool {
+	return r == RoleOwner || r == RoleCoach
+}
+
+// Organization is a team or gym.
+type Organization struct {
+	ID        string
+	Name      string
+	Slug      string
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Member is a user's membership in an organization.
+type Member struct {
+	OrganizationID string
+	UserID         string
+	Role           Role
+	JoinedAt       time.Time
+}
+
+// InvitationStatus is the state of an invitation.
+type InvitationStatus string
+
+const (
+	// InvitationPending is waiting for the invitee to respond.
+	InvitationPending InvitationStatus = "PENDING"
+	// InvitationAccepted made the invitee a member.
+	InvitationAccepted InvitationStatus = "ACCEPTED"
+	// InvitationDeclined was turned down by the invitee.
+	InvitationDeclined InvitationStatus = "DECLINED"
+)
+
+// Invitation invites a user to join an organization with a role. The user is not a
+// member, and sees neither the roster nor the organization's catalog, until they accept.
+type Invitation struct {
+	ID             string
+	OrganizationID string
+	UserID         string
+	Role           Role
+	Status         InvitationStatus
+	InvitedBy      string
+	CreatedAt      time.Time
+	RespondedAt    *time.Time
+}
+
+// CreateRequest holds the fields for a new organization. An empty slug is generated
+// from the name.
+type CreateRequest struct {
+	Name string
+	Slug string
+}
+
+// UpdateRequest holds organization changes. Nil fields are left unchanged.
+type UpdateRequest struct {
+	Name *string
+	Slug *string
+}
+
+// InviteRequest identifies the user to invite, by ID or email, and the role they join
+// with. An empty role invites the user as an athlete.
+type InviteRequest struct {
+	UserID string
+	Email  string
+	Role   Role
+}
+
+// Repository defines the interface for organization persistence.
+type Repository interface {
+	// FindUserIDByEmail returns the ID of the user with the email, ignoring case.
+	FindUserIDByEmail(ctx context.Context, email string) (string, error)
+	UserExists(ctx context.Context, userID string) (bool, error)
+	SlugExists(ctx context.Context, slug, excludeID string) (bool, error)
+	// Create inserts the organization and its first member in one transaction.
+	Create(ctx context.Context, org *Organization, owner *Member) error
+	Get(ctx context.Context, id string) (*Organization, error)
+	// List returns a page of organizations, by name, along with the total count. A
+	// non-nil memberID limits the list to that user's organizations.
+	List(ctx context.Context, memberID *string, limit, offset int64) ([]Organization, int64, error)
+	Update(ctx context.Context, org *Organization) error
+	Delete(ctx context.Context, id string) error
+	// HasCatalogEntries reports whether the organization owns any lifts, programs or lookups.
+	HasCatalogEntries(ctx context.Context, id string) (bool, error)
+	CreateInvitation(ctx context.Context, inv *Invitation) error
+	GetInvitation(ctx context.Context, id string) (*Invitation, error)
+	// GetPendingInvitation returns the user's pending invitation to the organization, or
+	// nil when there is none.
+	GetPendingInvitation(ctx context.Context, organizationID, userID string) (*Invitation, error)
+	// ListPendingInvitations returns pending invitations, oldest first, to an organization
+	// or, when organizationID is empty, to a user.
+	ListPendingInvitations(ctx context.Context, organizationID, userID string) ([]Invitation, error)
+	// AcceptInvitation marks the invitation accepted and adds the member in one transaction.
+	AcceptInvitation(ctx context.Context, inv *Invitation, m *Member) error
+	UpdateInvitation(ctx context.Context, inv *Invitation) error
+	DeleteInvitation(ctx context.Context, id string) error
+	// GetMember returns the user's membership, or nil when they are not a member.
+	GetMember(ctx context.Context, organizationID, userID string) (*Member, error)
+	// ListMembers returns an organization's members in the order they joined. A non-nil
+	// role limits the list to members with that role.
+	ListMembers(ctx context.Context, organizationID string, role *Role) ([]Member, error)
+	UpdateMemberRole(ctx context.Context, organizationID, userID string, role Role) error
+	RemoveMember(ctx context.Context, organizationID, userID string) error
+	CountOwners(ctx context.Context, organizationID string) (int64, error)
+}
+
+// Service manages organizations and their members.
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+// NewService creates a new organization service.
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Create creates an organization with the creator as its owner.
+func (s *Service) Create(ctx context.Context, creatorID string, req CreateRequest) (*Organization, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	slug := strings.TrimSpace(req.Slug)
+	if slug == "" {
+		slug = validation.GenerateSlug(name)
+	}
+	if err := s.checkSlug(ctx, slug, ""); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	org := &Organization{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Slug:      slug,
+		CreatedBy: creatorID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	owner := &Member{
+		OrganizationID: org.ID,
+		UserID:         creatorID,
+		Role:           RoleOwner,
+		JoinedAt:       now,
+	}
+	if err := s.repo.Create(ctx, org, owner); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// Get retrieves an organization by ID.
+func (s *Service) Get(ctx context.Context, id string) (*Organization, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// List returns a page of organizations by name. A non-nil memberID limits the list to
+// that user's organizations.
+func (s *Service) List(ctx context.Context, memberID *string, limit, offset int64) ([]Organization, int64, error) {
+	return s.repo.List(ctx, memberID, limit, offset)
+}
+
+// Update renames an organization or changes its slug.
+func (s *Service) Update(ctx context.Context, id string, req UpdateRequest) (*Organization, error) {
+	org, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := validateName(name); err != nil {
+			return nil, err
+		}
+		org.Name = name
+	}
+	if req.Slug != nil {
+		slug := strings.TrimSpace(*req.Slug)
+		if slug != org.Slug {
+			if err := s.checkSlug(ctx, slug, org.ID); err != nil {
+				return nil, err
+			}
+			org.Slug = slug
+		}
+	}
+
+	org.UpdatedAt = s.now().UTC()
+	if err := s.repo.Update(ctx, org); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// Delete deletes an organization and its memberships. Organizations that still own
+// catalog entries cannot be deleted, so members never lose programs they are enrolled in.
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if _, err := s.repo.Get(ctx, id); err != nil {
+		return err
+	}
+	hasEntries, err := s.repo.HasCatalogEntries(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hasEntries {
+		return apperrors.NewConflict("organization still owns lifts, programs or lookups; delete them first")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Invite invites a user to an organization. invitedBy is the authenticated caller.
+// The user joins when they accept the invitation.
+func (s *Service) Invite(ctx context.Context, organizationID, invitedBy string, req InviteRequest) (*Invitation, error) {
+	role := req.Role
+	if role == "" {
+		role = RoleAthlete
+	}
+	if !role.IsValid() {
+		return nil, apperrors.NewValidation("role", fmt.Sprintf("unknown role %q", role))
+	}
+	if _, err := s.repo.Get(ctx, organizationID); err != nil {
+		return nil, err
+	}
+	userID, err := s.resolveUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetMember(ctx, organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, apperrors.NewConflict("user is already a member of this organization")
+	}
+	pending, err := s.repo.GetPendingInvitation(ctx, organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, apperrors.NewConflict("user already has a pending invitation to this organization")
+	}
+
+	inv := &Invitation{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Role:           role,
+		Status:         InvitationPending,
+		InvitedBy:      invitedBy,
+		CreatedAt:      s.now().UTC(),
+	}
+	if err := s.repo.CreateInvitation(ctx, inv); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// GetInvitation retrieves an invitation by ID.
+func (s *Service) GetInvitation(ctx context.Context, id string) (*Invitation, error) {
+	return s.repo.GetInvitation(ctx, id)
+}
+
+// ListInvitations returns an organization's pending invitations, oldest first.
+func (s *Service) ListInvitations(ctx context.Context, organizationID string) ([]Invitation, error) {
+	return s.repo.ListPendingInvitations(ctx, organizationID, "")
+}
+
+// ListUserInvitations returns a user's pending invitations, oldest first.
+func (s *Service) ListUserInvitations(ctx context.Context, userID string) ([]Invitation, error) {
+	return s.repo.ListPendingInvitations(ctx, "", userID)
+}
+
+// AcceptInvitation accepts a pending invitation, making the invitee a member with the
+// invited role.
+func (s *Service) AcceptInvitation(ctx context.Context, id string) (*Member, error) {
+	inv, err := s.pendingInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	inv.Status = InvitationAccepted
+	inv.RespondedAt = &now
+	m := &Member{
+		OrganizationID: inv.OrganizationID,
+		UserID:         inv.UserID,
+		Role:           inv.Role,
+		JoinedAt:       now,
+	}
+	if err := s.repo.AcceptInvitation(ctx, inv, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DeclineInvitation declines a pending invitation.
+func (s *Service) DeclineInvitation(ctx context.Context, id string) (*Invitation, error) {
+	inv, err := s.pendingInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	inv.Status = InvitationDeclined
+	inv.RespondedAt = &now
+	if err := s.repo.UpdateInvitation(ctx, inv); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// CancelInvitation withdraws a pending invitation.
+func (s *Service) CancelInvitation(ctx context.Context, id string) error {
+	if _, err := s.pendingInvitation(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteInvitation(ctx, id)
+}
+
+func (s *Service) pendingInvitation(ctx context.Context, id string) (*Invitation, error) {
+	inv, err := s.repo.GetInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status != InvitationPending {
+		return nil, apperrors.NewConflict(fmt.Sprintf("invitation is %s, not PENDING", inv.Status))
+	}
+	return inv, nil
+}
+
+func (s *Service) resolveUser(ctx context.Context, req InviteRequest) (string, error) {
+	userID := strings.TrimSpace(req.UserID)
+	email := strings.TrimSpace(req.Email)
+	switch {
+	case userID != "" && email != "":
+		return "", apperrors.NewValidation("userId", "provide either userId or email, not both")
+	case email != "":
+		return s.repo.FindUserIDByEmail(ctx, email)
+	case userID != "":
+		exists, err := s.repo.UserExists(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return "", apperrors.NewNotFound("user", userID)
+		}
+		return userID, nil
+	default:
+		return "", apperrors.NewValidation("userId", "userId or email is required")
+	}
+}
+
+// GetMember returns a user's membership, or a not found error when they are not a member.
+func (s *Service) GetMember(ctx context.Context, organizationID, userID string) (*Member, error) {
+	m, err := s.repo.GetMember(ctx, organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, apperrors.NewNotFound("organization member", userID)
+	}
+	return m, nil
+}
+
+// ListMembers returns an organization's members in the order they joined. A non-nil role
+// limits the list to members with that role.
+func (s *Service) ListMembers(ctx context.Context, organizationID string, role *Role) ([]Member, error) {
+	if role != nil && !role.IsValid() {
+		return nil, apperrors.NewValidation("role", fmt.Sprintf("unknown role %q", *role))
+	}
+	return s.repo.ListMembers(ctx, organizationID, role)
+}
+
+// UpdateMemberRole changes a member's role. The last owner cannot be demoted.
+func (s *Service) UpdateMemberRole(ctx context.Context, organizationID, userID string, role Role) (*Member, error) {
+	if !role.IsValid() {
+		return nil, apperrors.NewValidation("role", fmt.Sprintf("unknown role %q", role))
+	}
+	m, err := s.GetMember(ctx, organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if m.Role == role {
+		return m, nil
+	}
+	if m.Role == RoleOwner {
+		if err := s.checkNotLastOwner(ctx, organizationID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateMemberRole(ctx, organizationID, userID, role); err != nil {
+		return nil, err
+	}
+	m.Role = role
+	return m, nil
+}
+
+// RemoveMember removes a user from an organization. The last owner cannot leave.
+func (s *Service) RemoveMember(ctx context.Context, organizationID, userID string) error {
+	m, err := s.GetMember(ctx, organizationID, userID)
+	if err != nil {
+		return err
+	}
+	if m.Role == RoleOwner {
+		if err := s.checkNotLastOwner(ctx, organizationID); err != nil {
+			return err
+		}
+	}
+	return s.repo.RemoveMember(ctx, organizationID, userID)
+}
+
+// Role returns the user's role in the organization, or an empty role when they are not
+// a member.
+func (s *Service) Role(ctx context.Context, organizationID, userID string) (Role, error) {
+	m, err := s.repo.GetMember(ctx, organizationID, userID)
+	if err != nil || m == nil {
+		return "", err
+	}
+	return m.Role, nil
+}
+
+// CanView reports whether the user may see a catalog entry owned by organizationID.
+// Entries without an organization belong to the global catalog and everyone sees them.
+func (s *Service) CanView(ctx context.Context, organizationID *string, userID string) (bool, error) {
+	if organizationID == nil {
+		return true, nil
+	}
+	role, err := s.Role(ctx, *organizationID, userID)
+	if err != nil {
+		return false, err
+	}
+	return role != "", nil
+}
+
+// CanManageCatalog reports whether the user may create, update or delete catalog entries
+// owned by organizationID. Only admins manage the global catalog, so a nil
+// organizationID is never manageable here.
+func (s *Service) CanManageCatalog(ctx context.Context, organizationID *string, userID string) (bool, error) {
+	if organizationID == nil {
+		return false, nil
+	}
+	role, err := s.Role(ctx, *organizationID, userID)
+	if err != nil {
+		return false, err
+	}
+	return role.CanManageCatalog(), nil
+}
+
+func (s *Service) checkNotLastOwner(ctx context.Context, organizationID string) error {
+	owners, err := s.repo.CountOwners(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return apperrors.NewConflict("an organization must keep at least one owner")
+	}
+	return nil
+}
+
+func (s *Service) checkSlug(ctx context.Context, slug, excludeID string) error {
+	if err := validation.ValidateSlug(slug, maxSlugLength); err != nil {
+		return apperrors.NewValidation("slug", err.Error())
+	}
+	exists, err := s.repo.SlugExists(ctx, slug, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return apperrors.NewConflict("slug already exists")
+	}
+	return nil
+}
+
+func validateName(name string) error {
+	if name == "" {
+		return apperrors.NewValidation("name", "name is required")
+	}
+	if len(name) > maxNameLength {
+		return apperrors.NewValidation("name", fmt.Sprintf("name must be at most %d characters", maxNameLength))
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package organization
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waynenilsen/power-pro-v3/internal/database"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+func setupTestService(t *testing.T) (*Service, *sql.DB, func()) {
+	sqlDB, cleanup, err := database.OpenTemp("../../migrations")
+	require.NoError(t, err)
+	for _, userID := range []string{"owner", "coach", "athlete", "outsider"} {
+		_, err := sqlDB.Exec(`
+			INSERT INTO users (id, email, created_at, updated_at)
+			VALUES (?, ?, datetime('now'), datetime('now'))
+		`, userID, userID+"@example.com")
+		require.NoError(t, err)
+	}
+	return NewService(NewSQLiteRepository(sqlDB)), sqlDB, cleanup
+}
+
+func TestOrganizationLifecycle(t *testing.T) {
+	svc, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	org, err := svc.Create(ctx, "owner", CreateRequest{Name: "  Iron Temple Gym "})
+	require.NoError(t, err)
+	assert.Equal(t, "Iron Temple Gym", org.Name)
+	assert.Equal(t, "iron-temple-gym", org.Slug)
+
+	t.Run("creator becomes the owner", func(t *testing.T) {
+		role, err := svc.Role(ctx, org.ID, "owner")
+		require.NoError(t, err)
+		assert.Equal(t, RoleOwner, role)
+
+		role, err = svc.Role(ctx, org.ID, "outsider")
+		require.NoError(t, err)
+		assert.Equal(t, Role(""), role)
+	})
+
+	t.Run("validation and slug conflicts", func(t *testing.T) {
+		_, err := svc.Create(ctx, "owner", CreateRequest{Name: " "})
+		assert.True(t, apperrors.IsValidation(err))
+
+		_, err = svc.Create(ctx, "owner", CreateRequest{Name: "Other", Slug: "Not A Slug"})
+		assert.True(t, apperrors.IsValidation(err))
+
+		_, err = svc.Create(ctx, "coach", CreateRequest{Name: "Iron Temple Gym"})
+		assert.True(t, apperrors.IsConflict(err))
+	})
+
+	t.Run("lists only the member's organizations", func(t *testing.T) {
+		_, err := svc.Create(ctx, "outsider", CreateRequest{Name: "Garage Barbell"})
+		require.NoError(t, err)
+
+		owner := "owner"
+		orgs, total, err := svc.List(ctx, &owner, 20, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, orgs, 1)
+		assert.Equal(t, org.ID, orgs[0].ID)
+
+		_, total, err = svc.List(ctx, nil, 20, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		name, slug := "Iron Temple", "iron-temple"
+		updated, err := svc.Update(ctx, org.ID, UpdateRequest{Name: &name, Slug: &slug})
+		require.NoError(t, err)
+		assert.Equal(t, "Iron Temple", updated.Name)
+		assert.Equal(t, "iron-temple", updated.Slug)
+
+		taken := "garage-barbell"
+		_, err = svc.Update(ctx, org.ID, UpdateRequest{Slug: &taken})
+		assert.True(t, apperrors.IsConflict(err))
+	})
+
+	t.Run("cannot delete an organization that owns catalog entries", func(t *testing.T) {
+		_, err := sqlDB.Exec(`
+			INSERT INTO lifts (id, name, slug, is_competition_lift, created_at, updated_at, organization_id)
+			VALUES ('org-lift', 'Gym Squat', 'gym-squat', 0, datetime('now'), datetime('now'), ?)
+		`, org.ID)
+		require.NoError(t, err)
+
+		err = svc.Delete(ctx, org.ID)
+		assert.True(t, apperrors.IsConflict(err))
+
+		_, err = sqlDB.Exec(`DELETE FROM lifts WHERE id = 'org-lift'`)
+		require.NoError(t, err)
+		require.NoError(t, svc.Delete(ctx, org.ID))
+
+		_, err = svc.Get(ctx, org.ID)
+		assert.True(t, apperrors.IsNotFound(err))
+		role, err := svc.Role(ctx, org.ID, "owner")
+		require.NoError(t, err)
+		assert.Equal(t, Role(""), role)
+	})
+}
+
+func TestMembers(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	org, err := svc.Create(ctx, "owner", CreateRequest{Name: "Team"})
+	require.NoError(t, err)
+
+	t.Run("invite members by ID or email", func(t *testing.T) {
+		inv, err := svc.Invite(ctx, org.ID, "owner", InviteRequest{Email: "Coach@Example.com", Role: RoleCoach})
+		require.NoError(t, err)
+		assert.Equal(t, "coach", inv.UserID)
+		assert.Equal(t, RoleCoach, inv.Role)
+		assert.Equal(t, InvitationPending, inv.Status)
+		assert.Equal(t, "owner", inv.InvitedBy)
+
+		athleteInv, err := svc.Invite(ctx, org.ID, "owner", InviteRequest{UserID: "athlete"})
+		require.NoError(t, err)
+		assert.Equal(t, RoleAthlete, athleteInv.Role)
+
+		_, err = svc.Invite(ctx, org.ID, "owner", InviteRequest{UserID: "athlete"})
+		assert.True(t, apperrors.IsConflict(err))
+		_, err = svc.Invite(ctx, org.ID, "owner", InviteRequest{UserID: "owner"})
+		assert.True(t, apperrors.IsConflict(err))
+		_, err = svc.Invite(ctx, org.ID, "owner", InviteRequest{UserID: "outsider", Role: "MANAGER"})
+		assert.True(t, apperrors.IsValidation(err))
+		_, err = svc.Invite(ctx, org.ID, "owner", InviteRequest{UserID: "nobody"})
+		assert.True(t, apperrors.IsNotFound(err))
+		_, err = svc.Invite(ctx, org.ID, "owner", InviteRequest{})
+		assert.True(t, apperrors.IsValidation(err))
+
+		invitations, err := svc.ListInvitations(ctx, org.ID)
+		require.NoError(t, err)
+		assert.Len(t, invitations, 2)
+		invitations, err = svc.ListUserInvitations(ctx, "athlete")
+		require.NoError(t, err)
+		require.Len(t, invitations, 1)
+		assert.Equal(t, athleteInv.ID, invitations[0].ID)
+	})
+
+	t.Run("invitees are not members until they accept", func(t *testing.T) {
+		members, err := svc.ListMembers(ctx, org.ID, nil)
+		require.NoError(t, err)
+		assert.Len(t, members, 1)
+		view, err := svc.CanView(ctx, &org.ID, "athlete")
+		require.NoError(t, err)
+		assert.False(t, view)
+
+		invitations, err := svc.ListInvitations(ctx, org.ID)
+		require.NoError(t, err)
+		for _, inv := range invitations {
+			m, err := svc.AcceptInvitation(ctx, inv.ID)
+			require.NoError(t, err)
+			assert.Equal(t, inv.UserID, m.UserID)
+			assert.Equal(t, inv.Role, m.Role)
+
+			_, err = svc.AcceptInvitation(ctx, inv.ID)
+			assert.True(t, apperrors.IsConflict(err))
+			accepted, err := svc.GetInvitation(ctx, inv.ID)
+			require.NoError(t, err)
+			assert.Equal(t, InvitationAccepted, accepted.Status)
+			assert.NotNil(t, accepted.RespondedAt)
+		}
+
+		invitations, err = svc.ListInvitations(ctx, org.ID)
+		require.NoError(t, err)
+		assert.Empty(t, invitations)
+	})
+
+	t.Run("decline and cancel invitations", func(t *testing.T) {
+		inv, err := svc.Invite(ctx, org.ID, "owner", InviteRequest{UserID: "outsider"})
+		require.NoError(t, err)
+		declined, err := svc.DeclineInvitation(ctx, inv.ID)
+		require.NoError(t, err)
+		assert.Equal(t, InvitationDeclined, declined.Status)
+		_, err = svc.AcceptInvitation(ctx, inv.ID)
+		assert.True(t, apperrors.IsConflict(err))
+		assert.True(t, apperrors.IsConflict(svc.CancelInvitation(ctx, inv.ID)))
+
+		// A declined invitation does not stop a new one
+		inv, err = svc.Invite(ctx, org.ID, "owner", InviteRequest{UserID: "outsider"})
+		require.NoError(t, err)
+		require.NoError(t, svc.CancelInvitation(ctx, inv.ID))
+		_, err = svc.GetInvitation(ctx, inv.ID)
+		assert.True(t, apperrors.IsNotFound(err))
+
+		role, err := svc.Role(ctx, org.ID, "outsider")
+		require.NoError(t, err)
+		assert.Equal(t, Role(""), role)
+	})
+
+	t.Run("list members", func(t *testing.T) {
+		members, err := svc.ListMembers(ctx, org.ID, nil)
+		require.NoError(t, err)
+		require.Len(t, members, 3)
+		assert.Equal(t, "owner", members[0].UserID)
+
+		athletes := RoleAthlete
+		members, err = svc.ListMembers(ctx, org.ID, &athletes)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		assert.Equal(t, "athlete", members[0].UserID)
+	})
+
+	t.Run("catalog permissions follow the role", func(t *testing.T) {
+		cases := map[string]struct{ view, manage bool }{
+			"owner":    {true, true},
+			"coach":    {true, true},
+			"athlete":  {true, false},
+			"outsider": {false, false},
+		}
+		for userID, want := range cases {
+			view, err := svc.CanView(ctx, &org.ID, userID)
+			require.NoError(t, err)
+			assert.Equal(t, want.view, view, userID)
+			manage, err := svc.CanManageCatalog(ctx, &org.ID, userID)
+			require.NoError(t, err)
+			assert.Equal(t, want.manage, manage, userID)
+		}
+
+		// The global catalog is visible to everyone and managed only by admins
+		view, err := svc.CanView(ctx, nil, "outsider")
+		require.NoError(t, err)
+		assert.True(t, view)
+		manage, err := svc.CanManageCatalog(ctx, nil, "owner")
+		require.NoError(t, err)
+		assert.False(t, manage)
+	})
+
+	t.Run("the last owner cannot be demoted or removed", func(t *testing.T) {
+		_, err := svc.UpdateMemberRole(ctx, org.ID, "owner", RoleCoach)
+		assert.True(t, apperrors.IsConflict(err))
+		err = svc.RemoveMember(ctx, org.ID, "owner")
+		assert.True(t, apperrors.IsConflict(err))
+
+		m, err := svc.UpdateMemberRole(ctx, org.ID, "coach", RoleOwner)
+		require.NoError(t, err)
+		assert.Equal(t, RoleOwner, m.Role)
+
+		require.NoError(t, svc.RemoveMember(ctx, org.ID, "owner"))
+		_, err = svc.GetMember(ctx, org.ID, "owner")
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+
+	t.Run("unknown members", func(t *testing.T) {
+		_, err := svc.UpdateMemberRole(ctx, org.ID, "outsider", RoleCoach)
+		assert.True(t, apperrors.IsNotFound(err))
+		err = svc.RemoveMember(ctx, org.ID, "outsider")
+		assert.True(t, apperrors.IsNotFound(err))
+		_, err = svc.UpdateMemberRole(ctx, org.ID, "athlete", "BOSS")
+		assert.True(t, apperrors.IsValidation(err))
+	})
+}
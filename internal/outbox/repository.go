@@ -0,0 +1,318 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/domain/event"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// SQLiteRepository implements Repository using SQLite.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository creates a new SQLite-backed outbox repository.
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+const eventColumns = `seq, id, event_type, user_id, program_id, payload, occurred_at, created_at`
+
+// AppendEvents stores events in tx, assigning each its Seq.
+// SQLite allows one writer at a time, so Seq order is also commit order.
+func (r *SQLiteRepository) AppendEvents(ctx context.Context, tx *sql.Tx, events []Event) error {
+	for i := range events {
+		evt := &events[i]
+		payload, err := json.Marshal(evt.Payload)
+		if err != nil {
+			return apperrors.NewInternal("failed to encode event payload", err)
+		}
+
+		var programID sql.NullString
+		if evt.ProgramID != "" {
+			programID = sql.NullString{String: evt.ProgramID, Valid: true}
+		}
+
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO outbox_events (id, event_type, user_id, program_id, payload, occurred_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, evt.ID, string(evt.Type), evt.UserID, programID, string(payload),
+			evt.OccurredAt.UTC().Format(time.RFC3339), evt.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return apperrors.NewInternal("failed to store event", err)
+		}
+		if evt.Seq, err = res.LastInsertId(); err != nil {
+			return apperrors.NewInternal("failed to store event", err)
+		}
+	}
+	return nil
+}
+
+// GetEvent retrieves an event by its ID.
+func (r *SQLiteRepository) GetEvent(ctx context.Context, id string) (*Event, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+eventColumns+` FROM outbox_events WHERE id = ?
+	`, id)
+
+	evt, err := scanEvent(row)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("event", id)
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve event", err)
+	}
+	return evt, nil
+}
+
+// ListEvents returns a page of events matching filter, newest first, along with the total count.
+func (r *SQLiteRepository) ListEvents(ctx context.Context, filter EventFilter, limit, offset int64) ([]Event, int64, error) {
+	where := "1 = 1"
+	args := []interface{}{}
+	if filter.EventType != nil {
+		where += " AND event_type = ?"
+		args = append(args, *filter.EventType)
+	}
+	if filter.UserID != nil {
+		where += " AND user_id = ?"
+		args = append(args, *filter.UserID)
+	}
+	if filter.UserIDs != nil {
+		if len(filter.UserIDs) == 0 {
+			return []Event{}, 0, nil
+		}
+		where += " AND user_id IN (?" + strings.Repeat(", ?", len(filter.UserIDs)-1) + ")"
+		for _, id := range filter.UserIDs {
+			args = append(args, id)
+		}
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM outbox_events WHERE "+where, args...,
+	).Scan(&total); err != nil {
+		return nil, 0, apperrors.NewInternal("failed to count events", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+eventColumns+` FROM outbox_events WHERE `+where+`
+		ORDER BY seq DESC
+		LIMIT ? OFFSET ?
+	`, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list events", err)
+	}
+	defer rows.Close()
+
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list events", err)
+	}
+	return events, total, nil
+}
+
+// ListEventsAfter returns up to limit events with Seq greater than seq, oldest first.
+func (r *SQLiteRepository) ListEventsAfter(ctx context.Context, seq int64, limit int64) ([]Event, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+eventColumns+` FROM outbox_events
+		WHERE seq > ?
+		ORDER BY seq ASC
+		LIMIT ?
+	`, seq, limit)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list events", err)
+	}
+	defer rows.Close()
+
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list events", err)
+	}
+	return events, nil
+}
+
+// ListUserEventsAfter returns up to limit of a user's events with Seq greater than seq, oldest first.
+func (r *SQLiteRepository) ListUserEventsAfter(ctx context.Context, userID string, seq int64, limit int64) ([]Event, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+eventColumns+` FROM outbox_events
+		WHERE user_id = ? AND seq > ?
+		ORDER BY seq ASC
+		LIMIT ?
+	`, userID, seq, limit)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list events", err)
+	}
+	defer rows.Close()
+
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list events", err)
+	}
+	return events, nil
+}
+
+// LatestSeq returns the Seq of the newest event, or 0 when the outbox is empty.
+func (r *SQLiteRepository) LatestSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(seq), 0) FROM outbox_events`).Scan(&seq); err != nil {
+		return 0, apperrors.NewInternal("failed to read latest event", err)
+	}
+	return seq, nil
+}
+
+// GetCheckpoint returns the handler's checkpoint, or a zero checkpoint when it has none.
+func (r *SQLiteRepository) GetCheckpoint(ctx context.Context, handler string) (*Checkpoint, error) {
+	cp := Checkpoint{Handler: handler}
+	var lastError sql.NullString
+	var updatedAt string
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT last_seq, attempts, last_error, updated_at FROM outbox_checkpoints WHERE handler = ?
+	`, handler).Scan(&cp.LastSeq, &cp.Attempts, &lastError, &updatedAt)
+	if err == sql.ErrNoRows {
+		return &cp, nil
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve checkpoint", err)
+	}
+
+	if lastError.Valid {
+		cp.LastError = &lastError.String
+	}
+	cp.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return &cp, nil
+}
+
+// SaveCheckpoint creates or replaces the handler's checkpoint.
+func (r *SQLiteRepository) SaveCheckpoint(ctx context.Context, cp *Checkpoint) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO outbox_checkpoints (handler, last_seq, attempts, last_error, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (handler) DO UPDATE SET
+			last_seq = excluded.last_seq,
+			attempts = excluded.attempts,
+			last_error = excluded.last_error,
+			updated_at = excluded.updated_at
+	`, cp.Handler, cp.LastSeq, cp.Attempts, nullString(cp.LastError), cp.UpdatedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return apperrors.NewInternal("failed to save checkpoint", err)
+	}
+	return nil
+}
+
+// RecordFailure creates or replaces the failure record for a handler and event.
+func (r *SQLiteRepository) RecordFailure(ctx context.Context, f *Failure) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO outbox_failures (handler, event_id, attempts, error, failed_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (handler, event_id) DO UPDATE SET
+			attempts = excluded.attempts,
+			error = excluded.error,
+			failed_at = excluded.failed_at
+	`, f.Handler, f.EventID, f.Attempts, f.Error, f.FailedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return apperrors.NewInternal("failed to record event failure", err)
+	}
+	return nil
+}
+
+// DeleteFailure removes the failure record for a handler and event, if any.
+func (r *SQLiteRepository) DeleteFailure(ctx context.Context, handler, eventID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM outbox_failures WHERE handler = ? AND event_id = ?
+	`, handler, eventID)
+	if err != nil {
+		return apperrors.NewInternal("failed to clear event failure", err)
+	}
+	return nil
+}
+
+// ListFailuresForEvent returns the failure records for an event.
+func (r *SQLiteRepository) ListFailuresForEvent(ctx context.Context, eventID string) ([]Failure, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT handler, event_id, attempts, error, failed_at FROM outbox_failures
+		WHERE event_id = ?
+		ORDER BY handler ASC
+	`, eventID)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list event failures", err)
+	}
+	defer rows.Close()
+
+	failures := []Failure{}
+	for rows.Next() {
+		var f Failure
+		var failedAt string
+		if err := rows.Scan(&f.Handler, &f.EventID, &f.Attempts, &f.Error, &failedAt); err != nil {
+			return nil, apperrors.NewInternal("failed to list event failures", err)
+		}
+		f.FailedAt, _ = time.Parse(time.RFC3339, failedAt)
+		failures = append(failures, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewInternal("failed to list event failures", err)
+	}
+	return failures, nil
+}
+
+// CountFailures returns the number of events the handler gave up on.
+func (r *SQLiteRepository) CountFailures(ctx context.Context, handler string) (int64, error) {
+	var count int64
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM outbox_failures WHERE handler = ?
+	`, handler).Scan(&count); err != nil {
+		return 0, apperrors.NewInternal("failed to count event failures", err)
+	}
+	return count, nil
+}
+
+// rowScanner abstracts *sql.Row and *sql.Rows for scanning.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanEvent scans a single event row.
+func scanEvent(row rowScanner) (*Event, error) {
+	var evt Event
+	var eventType, payload, occurredAt, createdAt string
+	var programID sql.NullString
+
+	if err := row.Scan(&evt.Seq, &evt.ID, &eventType, &evt.UserID, &programID, &payload,
+		&occurredAt, &createdAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(payload), &evt.Payload); err != nil {
+		return nil, err
+	}
+	evt.Type = event.EventType(eventType)
+	evt.ProgramID = programID.String
+	evt.OccurredAt, _ = time.Parse(time.RFC3339, occurredAt)
+	evt.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+
+	return &evt, nil
+}
+
+// scanEvents scans all rows into events.
+func scanEvents(rows *sql.Rows) ([]Event, error) {
+	events := []Event{}
+	for rows.Next() {
+		evt, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *evt)
+	}
+	return events, rows.Err()
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
@@ -0,0 +1,610 @@
+// Package outbox provides a transactional outbox for state events.
+//
+// Events are written to the outbox in the same database transaction as the state change
+// that caused them, so an event is stored if and only if the change commits. The service
+// then dispatches stored events to named subscribers at least once and in order, tracking
+// each subscriber's progress with a checkpoint so nothing is lost across restarts.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/event"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+const (
+	// MaxAttempts is how many times a subscriber is called for an event before the event
+	// is recorded as failed for that subscriber and skipped.
+	MaxAttempts = 5
+
+	dispatchBatchSize = 100
+	maxErrorLength    = 500
+)
+
+// HandlerStatus is the delivery status of an event for one subscriber.
+type HandlerStatus string
+
+const (
+	// HandlerStatusPending means the subscriber has not handled the event yet.
+	HandlerStatusPending HandlerStatus = "PENDING"
+	// HandlerStatusDelivered means the subscriber handled the event.
+	HandlerStatusDelivered HandlerStatus = "DELIVERED"
+	// HandlerStatusFailed means the subscriber failed MaxAttempts times and skipped the event.
+	HandlerStatusFailed HandlerStatus = "FAILED"
+)
+
+// Event is a state event stored in the outbox.
+type Event struct {
+	// Seq orders events by commit; subscribers receive events in Seq order.
+	Seq        int64
+	ID         string
+	Type       event.EventType
+	UserID     string
+	ProgramID  string
+	Payload    map[string]interface{}
+	OccurredAt time.Time
+	CreatedAt  time.Time
+}
+
+// StateEvent converts the stored event back to the event handlers receive.
+func (e *Event) StateEvent() event.StateEvent {
+	return event.StateEvent{
+		ID:        e.ID,
+		Type:      e.Type,
+		UserID:    e.UserID,
+		ProgramID: e.ProgramID,
+		Timestamp: e.OccurredAt,
+		Payload:   e.Payload,
+	}
+}
+
+// Checkpoint records how far a subscriber has got through the outbox.
+type Checkpoint struct {
+	Handler string
+	// LastSeq is the Seq of the last event the subscriber handled or skipped.
+	LastSeq int64
+	// Attempts and LastError describe failed attempts at the event after LastSeq.
+	Attempts  int
+	LastError *string
+	UpdatedAt time.Time
+}
+
+// Failure records an event a subscriber gave up on.
+type Failure struct {
+	Handler  string
+	EventID  string
+	Attempts int
+	Error    string
+	FailedAt time.Time
+}
+
+// EventFilter narrows the event log. Nil fields are not filtered on.
+type EventFilter struct {
+	EventType *string
+	UserID    *string
+	// UserIDs restricts events to any of the users. A non-nil empty slice matches nothing.
+	UserIDs []string
+}
+
+// HandlerDelivery is the delivery status of an event for one subscriber.
+type HandlerDelivery struct {
+	Handler  string
+	Status   HandlerStatus
+	Attempts int
+	Error    *string
+}
+
+// EventDetail is a stored event with its delivery status for each subscriber that wants it.
+type EventDetail struct {
+	Event
+	Deliveries []HandlerDelivery
+}
+
+// HandlerState describes a subscriber's progress through the outbox.
+type HandlerState struct {
+	Name string
+	// EventTypes is nil when the subscriber receives every event type.
+	EventTypes []event.EventType
+	Checkpoint Checkpoint
+	// Lag is the number of stored events after the subscriber's checkpoint.
+	Lag          int64
+	FailedEvents int64
+}
+
+// ReplayResult is the outcome of replaying an event to one subscriber.
+type ReplayResult struct {
+	Handler string
+	Error   *string
+}
+
+// Repository defines the persistence operations for the outbox.
+type Repository interface {
+	// AppendEvents stores events in tx, assigning each its Seq.
+	AppendEvents(ctx context.Context, tx *sql.Tx, events []Event) error
+	GetEvent(ctx context.Context, id string) (*Event, error)
+	// ListEvents returns a page of events matching filter, newest first, along with the total count.
+	ListEvents(ctx context.Context, filter EventFilter, limit, offset int64) ([]Event, int64, error)
+	// ListEventsAfter returns up to limit events with Seq greater than seq, oldest first.
+	ListEventsAfter(ctx context.Context, seq int64, limit int64) ([]Event, error)
+	// ListUserEventsAfter returns up to limit of a user's events with Seq greater than seq, oldest first.
+	ListUserEventsAfter(ctx context.Context, userID string, seq int64, limit int64) ([]Event, error)
+	// LatestSeq returns the Seq of the newest event, or 0 when the outbox is empty.
+	LatestSeq(ctx context.Context) (int64, error)
+	// GetCheckpoint returns the handler's checkpoint, or a zero checkpoint when it has none.
+	GetCheckpoint(ctx context.Context, handler string) (*Checkpoint, error)
+	SaveCheckpoint(ctx context.Context, cp *Checkpoint) error
+	RecordFailure(ctx context.Context, f *Failure) error
+	DeleteFailure(ctx context.Context, handler, eventID string) error
+	ListFailuresForEvent(ctx context.Context, eventID string) ([]Failure, error)
+	CountFailures(ctx context.Context, handler string) (int64, error)
+}
+
+// subscriber is a named handler registered with the service.
+type subscriber struct {
+	name       string
+	eventTypes []event.EventType
+	handler    event.EventHandler
+}
+
+func (sub *subscriber) wants(eventType event.EventType) bool {
+	if sub.eventTypes == nil {
+		return true
+	}
+	for _, et := range sub.eventTypes {
+		if et == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Service stores state events and dispatches them to subscribers.
+type Service struct {
+	db   *sql.DB
+	repo Repository
+	now  func() time.Time
+
+	mu          sync.RWMutex
+	subscribers []*subscriber
+
+	// dispatchMu ensures only one dispatch pass runs at a time.
+	dispatchMu sync.Mutex
+	wake       chan struct{}
+
+	// changed is closed and replaced each time events are committed.
+	changedMu sync.Mutex
+	changed   chan struct{}
+}
+
+// NewService creates a new outbox service.
+// db is used to begin the transactions events are stored in.
+func NewService(db *sql.DB, repo Repository) *Service {
+	return &Service{
+		db:      db,
+		repo:    repo,
+		now:     time.Now,
+		wake:    make(chan struct{}, 1),
+		changed: make(chan struct{}),
+	}
+}
+
+// Subscribe registers a named handler for eventTypes, or for every event type when
+// eventTypes is nil. The name identifies the handler's checkpoint, so it must be stable
+// across restarts. A handler with no checkpoint starts from the oldest stored event.
+// Subscribe panics if the name is already registered.
+func (s *Service) Subscribe(name string, eventTypes []event.EventType, handler event.EventHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sub := range s.subscribers {
+		if sub.name == name {
+			panic(fmt.Sprintf("outbox: handler %q already subscribed", name))
+		}
+	}
+	s.subscribers = append(s.subscribers, &subscriber{
+		name:       name,
+		eventTypes: eventTypes,
+		handler:    handler,
+	})
+}
+
+// Transact runs fn in a transaction and stores the events it returns in the same
+// transaction. The events are dispatched once the transaction commits; if fn returns an
+// error, the transaction is rolled back and no events are stored.
+// fn must make all of its writes through tx.
+func (s *Service) Transact(ctx context.Context, fn func(tx *sql.Tx) ([]event.StateEvent, error)) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return apperrors.NewInternal("failed to begin transaction", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	events, err := fn(tx)
+	if err != nil {
+		return err
+	}
+	if err = s.append(ctx, tx, events); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return apperrors.NewInternal("failed to commit transaction", err)
+	}
+
+	if len(events) > 0 {
+		s.Notify()
+		s.broadcast()
+	}
+	return nil
+}
+
+// Publish stores events in a transaction of their own. Use Transact instead when the
+// events describe a state change that can be made in the same transaction.
+func (s *Service) Publish(ctx context.Context, events ...event.StateEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return s.Transact(ctx, func(*sql.Tx) ([]event.StateEvent, error) {
+		return events, nil
+	})
+}
+
+func (s *Service) append(ctx context.Context, tx *sql.Tx, events []event.StateEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := s.now().UTC()
+	stored := make([]Event, len(events))
+	for i, evt := range events {
+		id := evt.ID
+		if id == "" {
+			id = uuid.New().String()
+		}
+		occurredAt := evt.Timestamp
+		if occurredAt.IsZero() {
+			occurredAt = now
+		}
+		payload := evt.Payload
+		if payload == nil {
+			payload = map[string]interface{}{}
+		}
+		stored[i] = Event{
+			ID:         id,
+			Type:       evt.Type,
+			UserID:     evt.UserID,
+			ProgramID:  evt.ProgramID,
+			Payload:    payload,
+			OccurredAt: occurredAt.UTC(),
+			CreatedAt:  now,
+		}
+	}
+	return s.repo.AppendEvents(ctx, tx, stored)
+}
+
+// Notify wakes Run to dispatch without waiting for the next interval. It never blocks.
+func (s *Service) Notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Changed returns a channel that is closed the next time events are committed. Readers
+// that follow the outbox should call Changed before reading, so a commit between the
+// read and the wait is not missed.
+func (s *Service) Changed() <-chan struct{} {
+	s.changedMu.Lock()
+	defer s.changedMu.Unlock()
+	return s.changed
+}
+
+func (s *Service) broadcast() {
+	s.changedMu.Lock()
+	defer s.changedMu.Unlock()
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Dispatch delivers stored events after each subscriber's checkpoint, in order.
+// A subscriber that fails an event stops at that event until the next pass; after
+// MaxAttempts failures the event is recorded as failed and skipped.
+// Returns the number of handler calls that succeeded.
+func (s *Service) Dispatch(ctx context.Context) (int, error) {
+	s.dispatchMu.Lock()
+	defer s.dispatchMu.Unlock()
+
+	var handled int
+	var firstErr error
+	for _, sub := range s.snapshot() {
+		n, err := s.dispatchTo(ctx, sub)
+		handled += n
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return handled, firstErr
+}
+
+func (s *Service) dispatchTo(ctx context.Context, sub *subscriber) (int, error) {
+	cp, err := s.repo.GetCheckpoint(ctx, sub.name)
+	if err != nil {
+		return 0, err
+	}
+
+	handled := 0
+	for {
+		events, err := s.repo.ListEventsAfter(ctx, cp.LastSeq, dispatchBatchSize)
+		if err != nil {
+			return handled, err
+		}
+
+		dirty := false
+		for i := range events {
+			evt := &events[i]
+			if sub.wants(evt.Type) {
+				if err := sub.handler(ctx, evt.StateEvent()); err != nil {
+					cp.Attempts++
+					msg := truncateError(err.Error())
+					cp.UpdatedAt = s.now().UTC()
+					if cp.Attempts < MaxAttempts {
+						// Stop at this event so the subscriber keeps receiving events in order
+						cp.LastError = &msg
+						return handled, s.repo.SaveCheckpoint(ctx, cp)
+					}
+					if err := s.repo.RecordFailure(ctx, &Failure{
+						Handler:  sub.name,
+						EventID:  evt.ID,
+						Attempts: cp.Attempts,
+						Error:    msg,
+						FailedAt: cp.UpdatedAt,
+					}); err != nil {
+						return handled, err
+					}
+				} else {
+					handled++
+				}
+				cp.LastSeq = evt.Seq
+				cp.Attempts = 0
+				cp.LastError = nil
+				cp.UpdatedAt = s.now().UTC()
+				if err := s.repo.SaveCheckpoint(ctx, cp); err != nil {
+					return handled, err
+				}
+				dirty = false
+				continue
+			}
+			cp.LastSeq = evt.Seq
+			dirty = true
+		}
+
+		if dirty {
+			cp.UpdatedAt = s.now().UTC()
+			if err := s.repo.SaveCheckpoint(ctx, cp); err != nil {
+				return handled, err
+			}
+		}
+		if len(events) < dispatchBatchSize {
+			return handled, nil
+		}
+	}
+}
+
+// Run dispatches events whenever Notify is called and every interval, until ctx is
+// cancelled. It dispatches once on start to deliver events stored before a restart.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	_, _ = s.Dispatch(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		_, _ = s.Dispatch(ctx)
+	}
+}
+
+// ListEvents returns a page of stored events, newest first.
+func (s *Service) ListEvents(ctx context.Context, filter EventFilter, limit, offset int64) ([]Event, int64, error) {
+	return s.repo.ListEvents(ctx, filter, limit, offset)
+}
+
+// ListUserEventsAfter returns up to limit of a user's events with Seq greater than seq, oldest first.
+func (s *Service) ListUserEventsAfter(ctx context.Context, userID string, seq int64, limit int64) ([]Event, error) {
+	return s.repo.ListUserEventsAfter(ctx, userID, seq, limit)
+}
+
+// LatestSeq returns the Seq of the newest stored event, or 0 when the outbox is empty.
+func (s *Service) LatestSeq(ctx context.Context) (int64, error) {
+	return s.repo.LatestSeq(ctx)
+}
+
+// GetEvent returns a stored event with its delivery status for each subscriber that wants it.
+func (s *Service) GetEvent(ctx context.Context, id string) (*EventDetail, error) {
+	evt, err := s.repo.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	failures, err := s.repo.ListFailuresForEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	failed := make(map[string]*Failure, len(failures))
+	for i := range failures {
+		failed[failures[i].Handler] = &failures[i]
+	}
+
+	detail := &EventDetail{Event: *evt, Deliveries: []HandlerDelivery{}}
+	for _, sub := range s.snapshot() {
+		if !sub.wants(evt.Type) {
+			continue
+		}
+		d := HandlerDelivery{Handler: sub.name, Status: HandlerStatusPending}
+		if f, ok := failed[sub.name]; ok {
+			d.Status = HandlerStatusFailed
+			d.Attempts = f.Attempts
+			d.Error = &f.Error
+		} else {
+			cp, err := s.repo.GetCheckpoint(ctx, sub.name)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case cp.LastSeq >= evt.Seq:
+				d.Status = HandlerStatusDelivered
+			case cp.Attempts > 0:
+				// Failed attempts on a checkpoint belong to the first event after it
+				next, err := s.repo.ListEventsAfter(ctx, cp.LastSeq, 1)
+				if err != nil {
+					return nil, err
+				}
+				if len(next) == 1 && next[0].Seq == evt.Seq {
+					d.Attempts = cp.Attempts
+					d.Error = cp.LastError
+				}
+			}
+		}
+		detail.Deliveries = append(detail.Deliveries, d)
+	}
+	return detail, nil
+}
+
+// Replay calls the named subscribers with a stored event again, or every subscriber that
+// wants the event when handlers is empty. Checkpoints are not moved; a successful replay
+// clears the subscriber's failure record for the event.
+func (s *Service) Replay(ctx context.Context, eventID string, handlers []string) ([]ReplayResult, error) {
+	evt, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := s.selectSubscribers(evt.Type, handlers)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ReplayResult, 0, len(subs))
+	for _, sub := range subs {
+		result := ReplayResult{Handler: sub.name}
+		if err := sub.handler(ctx, evt.StateEvent()); err != nil {
+			msg := truncateError(err.Error())
+			result.Error = &msg
+		} else if err := s.repo.DeleteFailure(ctx, sub.name, evt.ID); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// ReplayTargets returns the names of the subscribers Replay would call with the event.
+func (s *Service) ReplayTargets(ctx context.Context, eventID string, handlers []string) ([]string, error) {
+	evt, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	subs, err := s.selectSubscribers(evt.Type, handlers)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(subs))
+	for i, sub := range subs {
+		names[i] = sub.name
+	}
+	return names, nil
+}
+
+func (s *Service) selectSubscribers(eventType event.EventType, names []string) ([]*subscriber, error) {
+	all := s.snapshot()
+	if len(names) == 0 {
+		subs := []*subscriber{}
+		for _, sub := range all {
+			if sub.wants(eventType) {
+				subs = append(subs, sub)
+			}
+		}
+		return subs, nil
+	}
+
+	byName := make(map[string]*subscriber, len(all))
+	for _, sub := range all {
+		byName[sub.name] = sub
+	}
+	subs := make([]*subscriber, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		sub, ok := byName[name]
+		if !ok {
+			return nil, apperrors.NewValidation("handlers", fmt.Sprintf("unknown handler %q", name))
+		}
+		if !sub.wants(eventType) {
+			return nil, apperrors.NewValidation("handlers", fmt.Sprintf("handler %q does not receive %s events", name, eventType))
+		}
+		if !seen[name] {
+			seen[name] = true
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+// ListHandlers returns every subscriber's progress through the outbox, in registration order.
+func (s *Service) ListHandlers(ctx context.Context) ([]HandlerState, error) {
+	latest, err := s.repo.LatestSeq(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subs := s.snapshot()
+	states := make([]HandlerState, len(subs))
+	for i, sub := range subs {
+		cp, err := s.repo.GetCheckpoint(ctx, sub.name)
+		if err != nil {
+			return nil, err
+		}
+		failed, err := s.repo.CountFailures(ctx, sub.name)
+		if err != nil {
+			return nil, err
+		}
+		lag := latest - cp.LastSeq
+		if lag < 0 {
+			lag = 0
+		}
+		states[i] = HandlerState{
+			Name:         sub.name,
+			EventTypes:   sub.eventTypes,
+			Checkpoint:   *cp,
+			Lag:          lag,
+			FailedEvents: failed,
+		}
+	}
+	return states, nil
+}
+
+func (s *Service) snapshot() []*subscriber {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	subs := make([]*subscriber, len(s.subscribers))
+	copy(subs, s.subscribers)
+	return subs
+}
+
+func truncateError(msg string) string {
+	if len(msg) > maxErrorLength {
+		return msg[:maxErrorLength]
+	}
+	return msg
+}
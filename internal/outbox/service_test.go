@@ -0,0 +1,331 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waynenilsen/power-pro-v3/internal/database"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/event"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// recorder is a subscriber that records the events it receives and fails while
+// failures remain.
+type recorder struct {
+	mu       sync.Mutex
+	events   []event.StateEvent
+	failures int
+}
+
+func (rec *recorder) handle(_ context.Context, evt event.StateEvent) error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.failures > 0 {
+		rec.failures--
+		return errors.New("handler unavailable")
+	}
+	rec.events = append(rec.events, evt)
+	return nil
+}
+
+func (rec *recorder) types() []event.EventType {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	types := make([]event.EventType, len(rec.events))
+	for i, evt := range rec.events {
+		types[i] = evt.Type
+	}
+	return types
+}
+
+func setupTestService(t *testing.T) (*Service, *sql.DB, func()) {
+	sqlDB, cleanup, err := database.OpenTemp("../../migrations")
+	require.NoError(t, err)
+	return NewService(sqlDB, NewSQLiteRepository(sqlDB)), sqlDB, cleanup
+}
+
+func createTestUser(tx *sql.Tx, userID string) error {
+	_, err := tx.Exec(`
+		INSERT INTO users (id, email, created_at, updated_at)
+		VALUES (?, ?, datetime('now'), datetime('now'))
+	`, userID, userID+"@example.com")
+	return err
+}
+
+func publish(t *testing.T, svc *Service, eventTypes ...event.EventType) []Event {
+	ctx := context.Background()
+	for _, et := range eventTypes {
+		require.NoError(t, svc.Publish(ctx, event.NewStateEvent(et, "user-1", "program-1").
+			WithPayload(event.PayloadWeekNumber, 2)))
+	}
+	events, _, err := svc.ListEvents(ctx, EventFilter{}, 100, 0)
+	require.NoError(t, err)
+	return events
+}
+
+func TestTransact(t *testing.T) {
+	svc, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	t.Run("stores events with the state change", func(t *testing.T) {
+		err := svc.Transact(ctx, func(tx *sql.Tx) ([]event.StateEvent, error) {
+			if err := createTestUser(tx, "user-committed"); err != nil {
+				return nil, err
+			}
+			return []event.StateEvent{event.NewStateEvent(event.EventEnrolled, "user-committed", "program-1")}, nil
+		})
+		require.NoError(t, err)
+
+		var count int
+		require.NoError(t, sqlDB.QueryRow(`SELECT COUNT(*) FROM users WHERE id = 'user-committed'`).Scan(&count))
+		assert.Equal(t, 1, count)
+
+		userID := "user-committed"
+		events, total, err := svc.ListEvents(ctx, EventFilter{UserID: &userID}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, event.EventEnrolled, events[0].Type)
+		assert.NotEmpty(t, events[0].ID)
+		assert.Equal(t, "program-1", events[0].ProgramID)
+
+		_, total, err = svc.ListEvents(ctx, EventFilter{UserIDs: []string{"someone-else", userID}}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		_, total, err = svc.ListEvents(ctx, EventFilter{UserIDs: []string{}}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("stores neither when the state change fails", func(t *testing.T) {
+		err := svc.Transact(ctx, func(tx *sql.Tx) ([]event.StateEvent, error) {
+			if err := createTestUser(tx, "user-rolled-back"); err != nil {
+				return nil, err
+			}
+			return nil, apperrors.NewConflict("changed concurrently")
+		})
+		assert.True(t, apperrors.IsConflict(err))
+
+		var count int
+		require.NoError(t, sqlDB.QueryRow(`SELECT COUNT(*) FROM users WHERE id = 'user-rolled-back'`).Scan(&count))
+		assert.Equal(t, 0, count)
+
+		userID := "user-rolled-back"
+		_, total, err := svc.ListEvents(ctx, EventFilter{UserID: &userID}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+}
+
+func TestDispatch_DeliversInOrderFromCheckpoint(t *testing.T) {
+	svc, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	all := &recorder{}
+	sessions := &recorder{}
+	svc.Subscribe("all", nil, all.handle)
+	svc.Subscribe("sessions", []event.EventType{event.EventWorkoutCompleted}, sessions.handle)
+
+	publish(t, svc, event.EventWorkoutStarted, event.EventSetLogged, event.EventWorkoutCompleted)
+
+	handled, err := svc.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, handled)
+	assert.Equal(t, []event.EventType{event.EventWorkoutStarted, event.EventSetLogged, event.EventWorkoutCompleted}, all.types())
+	assert.Equal(t, []event.EventType{event.EventWorkoutCompleted}, sessions.types())
+
+	// Payloads survive the round trip through the outbox
+	assert.Equal(t, 2, all.events[0].GetInt(event.PayloadWeekNumber))
+	assert.NotEmpty(t, all.events[0].ID)
+
+	// A second pass has nothing new to deliver
+	handled, err = svc.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, handled)
+
+	// After a restart, subscribers resume from their checkpoints
+	publish(t, svc, event.EventWorkoutCompleted)
+	restarted := NewService(sqlDB, NewSQLiteRepository(sqlDB))
+	resumed := &recorder{}
+	restarted.Subscribe("sessions", []event.EventType{event.EventWorkoutCompleted}, resumed.handle)
+	_, err = restarted.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Len(t, resumed.events, 1)
+}
+
+func TestDispatch_RetriesThenRecordsFailure(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	rec := &recorder{failures: MaxAttempts}
+	svc.Subscribe("flaky", nil, rec.handle)
+	events := publish(t, svc, event.EventWorkoutStarted, event.EventWorkoutCompleted)
+	first, second := events[1], events[0]
+
+	// Each failed pass stops at the failing event
+	for i := 1; i < MaxAttempts; i++ {
+		_, err := svc.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, rec.events)
+
+		detail, err := svc.GetEvent(ctx, first.ID)
+		require.NoError(t, err)
+		require.Len(t, detail.Deliveries, 1)
+		assert.Equal(t, HandlerStatusPending, detail.Deliveries[0].Status)
+		assert.Equal(t, i, detail.Deliveries[0].Attempts)
+		require.NotNil(t, detail.Deliveries[0].Error)
+		assert.Equal(t, "handler unavailable", *detail.Deliveries[0].Error)
+	}
+
+	// The final attempt records the failure and moves on to the next event
+	_, err := svc.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []event.EventType{event.EventWorkoutCompleted}, rec.types())
+
+	detail, err := svc.GetEvent(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, HandlerStatusFailed, detail.Deliveries[0].Status)
+	assert.Equal(t, MaxAttempts, detail.Deliveries[0].Attempts)
+
+	detail, err = svc.GetEvent(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, HandlerStatusDelivered, detail.Deliveries[0].Status)
+
+	handlers, err := svc.ListHandlers(ctx)
+	require.NoError(t, err)
+	require.Len(t, handlers, 1)
+	assert.Equal(t, "flaky", handlers[0].Name)
+	assert.Equal(t, int64(0), handlers[0].Lag)
+	assert.Equal(t, int64(1), handlers[0].FailedEvents)
+	assert.Equal(t, 0, handlers[0].Checkpoint.Attempts)
+}
+
+func TestReplay(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	flaky := &recorder{failures: MaxAttempts}
+	sessions := &recorder{}
+	svc.Subscribe("flaky", nil, flaky.handle)
+	svc.Subscribe("sessions", []event.EventType{event.EventWorkoutCompleted}, sessions.handle)
+	events := publish(t, svc, event.EventWorkoutStarted)
+	evt := events[0]
+
+	for i := 0; i < MaxAttempts; i++ {
+		_, err := svc.Dispatch(ctx)
+		require.NoError(t, err)
+	}
+
+	t.Run("replays to every subscriber that wants the event", func(t *testing.T) {
+		results, err := svc.Replay(ctx, evt.ID, nil)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "flaky", results[0].Handler)
+		assert.Nil(t, results[0].Error)
+		require.Len(t, flaky.events, 1)
+		assert.Equal(t, evt.ID, flaky.events[0].ID)
+
+		// A successful replay clears the failure
+		detail, err := svc.GetEvent(ctx, evt.ID)
+		require.NoError(t, err)
+		assert.Equal(t, HandlerStatusDelivered, detail.Deliveries[0].Status)
+	})
+
+	t.Run("reports handler errors", func(t *testing.T) {
+		flaky.failures = 1
+		results, err := svc.Replay(ctx, evt.ID, []string{"flaky"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.NotNil(t, results[0].Error)
+		assert.Equal(t, "handler unavailable", *results[0].Error)
+	})
+
+	t.Run("rejects unknown and uninterested handlers", func(t *testing.T) {
+		_, err := svc.Replay(ctx, evt.ID, []string{"missing"})
+		assert.True(t, apperrors.IsValidation(err))
+
+		_, err = svc.Replay(ctx, evt.ID, []string{"sessions"})
+		assert.True(t, apperrors.IsValidation(err))
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		_, err := svc.Replay(ctx, "missing", nil)
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+}
+
+func TestRun_DispatchesOnCommit(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	rec := &recorder{}
+	svc.Subscribe("all", nil, rec.handle)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		svc.Run(ctx, time.Hour)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	require.NoError(t, svc.Publish(context.Background(), event.NewStateEvent(event.EventEnrolled, "user-1", "program-1")))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(rec.types()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, []event.EventType{event.EventEnrolled}, rec.types())
+}
+
+func TestChanged_FollowsUserEvents(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	changed := svc.Changed()
+	select {
+	case <-changed:
+		t.Fatal("Changed closed before any commit")
+	default:
+	}
+
+	require.NoError(t, svc.Publish(ctx,
+		event.NewStateEvent(event.EventWorkoutStarted, "user-1", "program-1"),
+		event.NewStateEvent(event.EventWorkoutStarted, "user-2", "program-1"),
+		event.NewStateEvent(event.EventSetLogged, "user-1", "program-1"),
+	))
+	select {
+	case <-changed:
+	default:
+		t.Fatal("Changed not closed after a commit")
+	}
+	assert.NotEqual(t, changed, svc.Changed())
+
+	latest, err := svc.LatestSeq(ctx)
+	require.NoError(t, err)
+
+	events, err := svc.ListUserEventsAfter(ctx, "user-1", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, event.EventWorkoutStarted, events[0].Type)
+	assert.Equal(t, event.EventSetLogged, events[1].Type)
+	assert.Equal(t, latest, events[1].Seq)
+
+	events, err = svc.ListUserEventsAfter(ctx, "user-1", events[0].Seq, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, event.EventSetLogged, events[0].Type)
+}
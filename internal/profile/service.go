@@ -8,12 +8,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/strengthscore"
 	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
 )
 
 const (
 	// maxNameLength is the maximum allowed length for a user's name.
 	maxNameLength = 100
+	// birthDateLayout is the format used for birth dates (YYYY-MM-DD).
+	birthDateLayout = "2006-01-02"
 )
 
 // Valid weight units.
@@ -24,12 +28,31 @@ const (
 
 // Profile represents a user's profile information.
 type Profile struct {
-	ID         string    `json:"id"`
-	Email      string    `json:"email"`
-	Name       *string   `json:"name"`
-	WeightUnit string    `json:"weightUnit"`
-	CreatedAt  time.Time `json:"createdAt"`
-	UpdatedAt  time.Time `json:"updatedAt"`
+	ID         string  `json:"id"`
+	Email      string  `json:"email"`
+	Name       *string `json:"name"`
+	WeightUnit string  `json:"weightUnit"`
+	// Sex is "male" or "female", used to select strength score coefficients.
+	Sex *string `json:"sex"`
+	// BirthDate is the user's date of birth (YYYY-MM-DD), used for age coefficients.
+	BirthDate *string `json:"birthDate"`
+	// WeightClassTarget is the IPF weight class the user is aiming for (e.g. "93", "84+").
+	WeightClassTarget *string   `json:"weightClassTarget"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// Age returns the user's age in whole years at the given time, or nil if no birth date is set.
+func (p *Profile) Age(at time.Time) *int {
+	if p.BirthDate == nil {
+		return nil
+	}
+	birthDate, err := time.Parse(birthDateLayout, *p.BirthDate)
+	if err != nil {
+		return nil
+	}
+	age := strengthscore.AgeOn(birthDate, at)
+	return &age
 }
 
 // UpdateProfileRequest represents a request to update a user's profile.
@@ -39,6 +62,12 @@ type UpdateProfileRequest struct {
 	Name *string
 	// WeightUnit is the user's preferred weight unit ("lb" or "kg"). Nil means don't change.
 	WeightUnit *string
+	// Sex is "male" or "female". Nil means don't change, empty string means clear.
+	Sex *string
+	// BirthDate is the date of birth (YYYY-MM-DD). Nil means don't change, empty string means clear.
+	BirthDate *string
+	// WeightClassTarget is an IPF weight class name. Nil means don't change, empty string means clear.
+	WeightClassTarget *string
 }
 
 // ProfileUpdate represents the changes to apply to a profile.
@@ -52,6 +81,18 @@ type ProfileUpdate struct {
 	WeightUnit string
 	// SetWeightUnit indicates whether to update the weight unit field.
 	SetWeightUnit bool
+	// Sex is the new sex value. Only used if SetSex is true.
+	Sex *string
+	// SetSex indicates whether to update the sex field.
+	SetSex bool
+	// BirthDate is the new birth date. Only used if SetBirthDate is true.
+	BirthDate *string
+	// SetBirthDate indicates whether to update the birth date field.
+	SetBirthDate bool
+	// WeightClassTarget is the new weight class target. Only used if SetWeightClassTarget is true.
+	WeightClassTarget *string
+	// SetWeightClassTarget indicates whether to update the weight class target field.
+	SetWeightClassTarget bool
 	// UpdatedAt is the timestamp for the update.
 	UpdatedAt time.Time
 }
@@ -60,6 +101,8 @@ type ProfileUpdate struct {
 type ProfileRepository interface {
 	GetByUserID(ctx context.Context, userID string) (*Profile, error)
 	Update(ctx context.Context, userID string, update ProfileUpdate) (*Profile, error)
+	// WithTx returns a repository that runs its queries in tx.
+	WithTx(tx *sql.Tx) ProfileRepository
 }
 
 // Service provides profile operations.
@@ -76,6 +119,13 @@ func NewService(profileRepo ProfileRepository) *Service {
 	}
 }
 
+// WithTx returns a copy of the service whose repository runs in tx.
+func (s *Service) WithTx(tx *sql.Tx) *Service {
+	txService := *s
+	txService.profileRepo = s.profileRepo.WithTx(tx)
+	return &txService
+}
+
 // GetProfile retrieves a user's profile by their user ID.
 func (s *Service) GetProfile(ctx context.Context, userID string) (*Profile, error) {
 	if userID == "" {
@@ -111,8 +161,29 @@ func (s *Service) UpdateProfile(ctx context.Context, userID string, req UpdatePr
 		}
 	}
 
+	// Validate sex if provided
+	if req.Sex != nil {
+		if err := validateSex(*req.Sex); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate birth date if provided
+	if req.BirthDate != nil {
+		if err := validateBirthDate(*req.BirthDate, s.now()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate weight class target if provided
+	if req.WeightClassTarget != nil {
+		if err := validateWeightClassTarget(*req.WeightClassTarget); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check if there's anything to update
-	if req.Name == nil && req.WeightUnit == nil {
+	if req.Name == nil && req.WeightUnit == nil && req.Sex == nil && req.BirthDate == nil && req.WeightClassTarget == nil {
 		// Nothing to update, just return the current profile
 		return s.profileRepo.GetByUserID(ctx, userID)
 	}
@@ -139,6 +210,20 @@ func (s *Service) UpdateProfile(ctx context.Context, userID string, req UpdatePr
 		update.WeightUnit = *req.WeightUnit
 	}
 
+	// Handle optional field updates - empty string means clear (set to NULL)
+	if req.Sex != nil {
+		update.SetSex = true
+		update.Sex = nilIfBlank(*req.Sex)
+	}
+	if req.BirthDate != nil {
+		update.SetBirthDate = true
+		update.BirthDate = nilIfBlank(*req.BirthDate)
+	}
+	if req.WeightClassTarget != nil {
+		update.SetWeightClassTarget = true
+		update.WeightClassTarget = nilIfBlank(*req.WeightClassTarget)
+	}
+
 	// Update the profile
 	profile, err := s.profileRepo.Update(ctx, userID, update)
 	if err != nil {
@@ -169,9 +254,63 @@ func validateWeightUnit(unit string) error {
 	return nil
 }
 
+// validateSex validates the user's sex.
+func validateSex(sex string) error {
+	trimmed := strings.TrimSpace(sex)
+	// Empty string is valid - it means "clear the sex"
+	if trimmed == "" {
+		return nil
+	}
+	if err := strengthscore.ValidateSex(strengthscore.Sex(trimmed)); err != nil {
+		return apperrors.NewValidation("sex", "sex must be 'male' or 'female'")
+	}
+	return nil
+}
+
+// validateBirthDate validates the user's birth date.
+func validateBirthDate(birthDate string, now time.Time) error {
+	trimmed := strings.TrimSpace(birthDate)
+	// Empty string is valid - it means "clear the birth date"
+	if trimmed == "" {
+		return nil
+	}
+	parsed, err := time.Parse(birthDateLayout, trimmed)
+	if err != nil {
+		return apperrors.NewValidation("birthDate", "birth date must be in YYYY-MM-DD format")
+	}
+	if parsed.After(now) {
+		return apperrors.NewValidation("birthDate", "birth date cannot be in the future")
+	}
+	return nil
+}
+
+// validateWeightClassTarget validates the user's weight class target.
+func validateWeightClassTarget(weightClass string) error {
+	trimmed := strings.TrimSpace(weightClass)
+	// Empty string is valid - it means "clear the target"
+	if trimmed == "" {
+		return nil
+	}
+	if !strengthscore.IsWeightClassName(trimmed) {
+		return apperrors.NewValidation("weightClassTarget", "weight class target must be an IPF weight class (e.g. '93' or '84+')")
+	}
+	return nil
+}
+
+// nilIfBlank trims a string and returns nil if it is empty.
+func nilIfBlank(s string) *string {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
 // SQLiteProfileRepository implements ProfileRepository using SQLite.
 type SQLiteProfileRepository struct {
 	db *sql.DB
+	// tx, when set, is the transaction the repository runs in.
+	tx *sql.Tx
 }
 
 // NewSQLiteProfileRepository creates a new SQLite-backed profile repository.
@@ -179,16 +318,30 @@ func NewSQLiteProfileRepository(db *sql.DB) *SQLiteProfileRepository {
 	return &SQLiteProfileRepository{db: db}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *SQLiteProfileRepository) WithTx(tx *sql.Tx) ProfileRepository {
+	return &SQLiteProfileRepository{db: r.db, tx: tx}
+}
+
+// conn returns the repository's transaction, or else its database.
+func (r *SQLiteProfileRepository) conn() db.DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
 // GetByUserID retrieves a user's profile by their user ID.
 func (r *SQLiteProfileRepository) GetByUserID(ctx context.Context, userID string) (*Profile, error) {
 	var profile Profile
-	var name sql.NullString
+	var name, sex, birthDate, weightClassTarget sql.NullString
 	var createdAt, updatedAt string
 
-	err := r.db.QueryRowContext(ctx, `
-		SELECT id, email, name, weight_unit, created_at, updated_at
+	err := r.conn().QueryRowContext(ctx, `
+		SELECT id, email, name, weight_unit, sex, birth_date, weight_class_target, created_at, updated_at
 		FROM users WHERE id = ?
-	`, userID).Scan(&profile.ID, &profile.Email, &name, &profile.WeightUnit, &createdAt, &updatedAt)
+	`, userID).Scan(&profile.ID, &profile.Email, &name, &profile.WeightUnit,
+		&sex, &birthDate, &weightClassTarget, &createdAt, &updatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, apperrors.NewNotFound("user", userID)
@@ -200,6 +353,15 @@ func (r *SQLiteProfileRepository) GetByUserID(ctx context.Context, userID string
 	if name.Valid {
 		profile.Name = &name.String
 	}
+	if sex.Valid {
+		profile.Sex = &sex.String
+	}
+	if birthDate.Valid {
+		profile.BirthDate = &birthDate.String
+	}
+	if weightClassTarget.Valid {
+		profile.WeightClassTarget = &weightClassTarget.String
+	}
 	profile.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	profile.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 
@@ -226,10 +388,25 @@ func (r *SQLiteProfileRepository) Update(ctx context.Context, userID string, upd
 		args = append(args, update.WeightUnit)
 	}
 
+	if update.SetSex {
+		query += ", sex = ?"
+		args = append(args, nullableString(update.Sex))
+	}
+
+	if update.SetBirthDate {
+		query += ", birth_date = ?"
+		args = append(args, nullableString(update.BirthDate))
+	}
+
+	if update.SetWeightClassTarget {
+		query += ", weight_class_target = ?"
+		args = append(args, nullableString(update.WeightClassTarget))
+	}
+
 	query += " WHERE id = ?"
 	args = append(args, userID)
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := r.conn().ExecContext(ctx, query, args...)
 	if err != nil {
 		return nil, apperrors.NewInternal("failed to update profile", err)
 	}
@@ -245,3 +422,11 @@ func (r *SQLiteProfileRepository) Update(ctx context.Context, userID string, upd
 	// Fetch and return the updated profile
 	return r.GetByUserID(ctx, userID)
 }
+
+// nullableString converts an optional string to a SQL parameter.
+func nullableString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
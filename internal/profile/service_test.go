@@ -64,6 +64,15 @@ func (m *mockProfileRepo) Update(ctx context.Context, userID string, update Prof
 	if update.SetWeightUnit {
 		profile.WeightUnit = update.WeightUnit
 	}
+	if update.SetSex {
+		profile.Sex = update.Sex
+	}
+	if update.SetBirthDate {
+		profile.BirthDate = update.BirthDate
+	}
+	if update.SetWeightClassTarget {
+		profile.WeightClassTarget = update.WeightClassTarget
+	}
 	profile.UpdatedAt = update.UpdatedAt
 
 	// Return a copy
@@ -75,6 +84,10 @@ func (m *mockProfileRepo) Update(ctx context.Context, userID string, update Prof
 	return &copy, nil
 }
 
+func (m *mockProfileRepo) WithTx(tx *sql.Tx) ProfileRepository {
+	return m
+}
+
 // Helper to create a string pointer
 func strPtr(s string) *string {
 	return &s
@@ -580,6 +593,99 @@ func TestValidateWeightUnit(t *testing.T) {
 	}
 }
 
+func TestValidateSex(t *testing.T) {
+	tests := []struct {
+		sex     string
+		wantErr bool
+	}{
+		{"male", false},
+		{"female", false},
+		{"", false}, // Empty clears the value
+		{"Male", true},
+		{"other", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sex, func(t *testing.T) {
+			err := validateSex(tt.sex)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.True(t, apperrors.IsValidation(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateBirthDate(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		birthDate string
+		wantErr   bool
+	}{
+		{"valid date", "1990-05-20", false},
+		{"empty string clears", "", false},
+		{"wrong format", "05/20/1990", true},
+		{"includes time", "1990-05-20T00:00:00Z", true},
+		{"future date", "2030-01-01", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBirthDate(tt.birthDate, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.True(t, apperrors.IsValidation(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateWeightClassTarget(t *testing.T) {
+	tests := []struct {
+		weightClass string
+		wantErr     bool
+	}{
+		{"93", false},
+		{"120+", false},
+		{"63", false},
+		{"84+", false},
+		{"", false},
+		{"94", true},
+		{"heavy", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.weightClass, func(t *testing.T) {
+			err := validateWeightClassTarget(tt.weightClass)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.True(t, apperrors.IsValidation(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProfile_Age(t *testing.T) {
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	p := Profile{}
+	assert.Nil(t, p.Age(at), "age should be nil without birth date")
+
+	p.BirthDate = strPtr("1990-01-16")
+	require.NotNil(t, p.Age(at))
+	assert.Equal(t, 33, *p.Age(at))
+
+	p.BirthDate = strPtr("1990-01-15")
+	assert.Equal(t, 34, *p.Age(at))
+}
+
 func TestProfileJSONTags(t *testing.T) {
 	// Verify that the Profile struct has the expected JSON tags
 	// This is a compile-time check - if the tags are wrong, the API contract breaks
@@ -886,6 +992,34 @@ func TestProfileServiceWithRealDB(t *testing.T) {
 		assert.Nil(t, profile.Name, "name should be cleared")
 	})
 
+	t.Run("UpdateProfile with real DB - strength score fields", func(t *testing.T) {
+		req := UpdateProfileRequest{
+			Sex:               strPtr("female"),
+			BirthDate:         strPtr("1985-03-10"),
+			WeightClassTarget: strPtr("63"),
+		}
+
+		profile, err := svc.UpdateProfile(ctx, "svc-test-user", req)
+		require.NoError(t, err)
+		require.NotNil(t, profile.Sex)
+		assert.Equal(t, "female", *profile.Sex)
+		require.NotNil(t, profile.BirthDate)
+		assert.Equal(t, "1985-03-10", *profile.BirthDate)
+		require.NotNil(t, profile.WeightClassTarget)
+		assert.Equal(t, "63", *profile.WeightClassTarget)
+
+		// Clearing with empty strings sets the fields back to NULL
+		profile, err = svc.UpdateProfile(ctx, "svc-test-user", UpdateProfileRequest{
+			Sex:               strPtr(""),
+			BirthDate:         strPtr(""),
+			WeightClassTarget: strPtr(""),
+		})
+		require.NoError(t, err)
+		assert.Nil(t, profile.Sex)
+		assert.Nil(t, profile.BirthDate)
+		assert.Nil(t, profile.WeightClassTarget)
+	})
+
 	t.Run("GetProfile returns not found for nonexistent user", func(t *testing.T) {
 		_, err := svc.GetProfile(ctx, "nonexistent")
 		require.Error(t, err)
@@ -23,6 +23,13 @@ func NewCycleRepository(sqlDB *sql.DB) *CycleRepository {
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *CycleRepository) WithTx(tx *sql.Tx) *CycleRepository {
+	return &CycleRepository{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
 // CycleSortField represents a field to sort by.
 type CycleSortField string
 
@@ -24,6 +24,13 @@ func NewDailyLookupRepository(sqlDB *sql.DB) *DailyLookupRepository {
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *DailyLookupRepository) WithTx(tx *sql.Tx) *DailyLookupRepository {
+	return &DailyLookupRepository{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
 // DailyLookupSortField represents a field to sort by.
 type DailyLookupSortField string
 
@@ -38,6 +45,9 @@ type DailyLookupListParams struct {
 	Offset    int64
 	SortBy    DailyLookupSortField
 	SortOrder SortOrder
+	// ViewerID limits results to the global catalog and the viewer's organizations.
+	// Nil lists every entry.
+	ViewerID *string
 }
 
 // GetByID retrieves a daily lookup by its ID.
@@ -68,7 +78,7 @@ func (r *DailyLookupRepository) List(params DailyLookupListParams) ([]dailylooku
 		params.SortOrder = SortAsc
 	}
 
-	total, err := r.queries.CountDailyLookups(ctx)
+	total, err := r.queries.CountDailyLookups(ctx, viewerParam(params.ViewerID))
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count daily lookups: %w", err)
 	}
@@ -79,28 +89,33 @@ func (r *DailyLookupRepository) List(params DailyLookupListParams) ([]dailylooku
 	switch {
 	case params.SortBy == DailyLookupSortByName && params.SortOrder == SortAsc:
 		dbLookups, err = r.queries.ListDailyLookupsByNameAsc(ctx, db.ListDailyLookupsByNameAscParams{
-			Limit:  params.Limit,
-			Offset: params.Offset,
+			ViewerID: viewerParam(params.ViewerID),
+			Limit:    params.Limit,
+			Offset:   params.Offset,
 		})
 	case params.SortBy == DailyLookupSortByName && params.SortOrder == SortDesc:
 		dbLookups, err = r.queries.ListDailyLookupsByNameDesc(ctx, db.ListDailyLookupsByNameDescParams{
-			Limit:  params.Limit,
-			Offset: params.Offset,
+			ViewerID: viewerParam(params.ViewerID),
+			Limit:    params.Limit,
+			Offset:   params.Offset,
 		})
 	case params.SortBy == DailyLookupSortByCreatedAt && params.SortOrder == SortAsc:
 		dbLookups, err = r.queries.ListDailyLookupsByCreatedAtAsc(ctx, db.ListDailyLookupsByCreatedAtAscParams{
-			Limit:  params.Limit,
-			Offset: params.Offset,
+			ViewerID: viewerParam(params.ViewerID),
+			Limit:    params.Limit,
+			Offset:   params.Offset,
 		})
 	case params.SortBy == DailyLookupSortByCreatedAt && params.SortOrder == SortDesc:
 		dbLookups, err = r.queries.ListDailyLookupsByCreatedAtDesc(ctx, db.ListDailyLookupsByCreatedAtDescParams{
-			Limit:  params.Limit,
-			Offset: params.Offset,
+			ViewerID: viewerParam(params.ViewerID),
+			Limit:    params.Limit,
+			Offset:   params.Offset,
 		})
 	default:
 		dbLookups, err = r.queries.ListDailyLookupsByNameAsc(ctx, db.ListDailyLookupsByNameAscParams{
-			Limit:  params.Limit,
-			Offset: params.Offset,
+			ViewerID: viewerParam(params.ViewerID),
+			Limit:    params.Limit,
+			Offset:   params.Offset,
 		})
 	}
 
@@ -130,12 +145,13 @@ func (r *DailyLookupRepository) Create(d *dailylookup.DailyLookup) error {
 	}
 
 	err = r.queries.CreateDailyLookup(ctx, db.CreateDailyLookupParams{
-		ID:        d.ID,
-		Name:      d.Name,
-		Entries:   string(entriesJSON),
-		ProgramID: nullStringPtr(d.ProgramID),
-		CreatedAt: d.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: d.UpdatedAt.Format(time.RFC3339),
+		ID:             d.ID,
+		Name:           d.Name,
+		Entries:        string(entriesJSON),
+		ProgramID:      nullStringPtr(d.ProgramID),
+		CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      d.UpdatedAt.Format(time.RFC3339),
+		OrganizationID: nullStringPtr(d.OrganizationID),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create daily lookup: %w", err)
@@ -204,11 +220,12 @@ func dbDailyLookupToDomain(dbLookup db.DailyLookup) (*dailylookup.DailyLookup, e
 	}
 
 	return &dailylookup.DailyLookup{
-		ID:        dbLookup.ID,
-		Name:      dbLookup.Name,
-		Entries:   entries,
-		ProgramID: programID,
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
+		ID:             dbLookup.ID,
+		Name:           dbLookup.Name,
+		Entries:        entries,
+		ProgramID:      programID,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+		OrganizationID: nullStringToStringPtr(dbLookup.OrganizationID),
 	}, nil
 }
@@ -24,6 +24,13 @@ func NewDayRepository(sqlDB *sql.DB) *DayRepository {
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *DayRepository) WithTx(tx *sql.Tx) *DayRepository {
+	return &DayRepository{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
 // DaySortField represents a field to sort by.
 type DaySortField string
 
@@ -0,0 +1,58 @@
+// Package repository provides database repository implementations.
+package repository
+
+import (
+	"math"
+	"time"
+)
+
+// Keyset marks a position in a list ordered by a stored column and then by rowid.
+// Value holds the column exactly as stored, so a page resumes right after the last
+// row of the previous one whatever precision its timestamp was written with. Rows
+// sharing a value keep the order they were inserted in, which random IDs would not.
+type Keyset struct {
+	Value string
+	Seq   int64
+}
+
+// keysetMax sorts after every stored timestamp.
+const keysetMax = "\U0010FFFF"
+
+// keysetStart returns the position a page starts after: after itself when set,
+// otherwise the beginning of the list in the given order.
+func keysetStart(after *Keyset, order SortOrder) Keyset {
+	if after != nil {
+		return *after
+	}
+	if order == SortAsc {
+		return Keyset{}
+	}
+	return Keyset{Value: keysetMax, Seq: math.MaxInt64}
+}
+
+// fromTimeParam formats an inclusive lower time bound for comparison against stored
+// timestamps. Like changedSinceParam it uses the second-precision prefix in the zone
+// timestamps are written in, which sorts before every timestamp in that second.
+func fromTimeParam(from *time.Time) interface{} {
+	if from == nil {
+		return nil
+	}
+	return changedSinceParam(*from)
+}
+
+// beforeTimeParam turns an inclusive upper time bound into the exclusive bound the
+// history queries compare with, so timestamps with fractional seconds stay in range.
+func beforeTimeParam(to *time.Time) interface{} {
+	if to == nil {
+		return nil
+	}
+	return changedSinceParam(to.Truncate(time.Second).Add(time.Second))
+}
+
+// filterParam converts an optional filter value to the nullable parameter sqlc expects.
+func filterParam(value *string) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -23,6 +23,13 @@ func NewLiftRepository(sqlDB *sql.DB) *LiftRepository {
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *LiftRepository) WithTx(tx *sql.Tx) *LiftRepository {
+	return &LiftRepository{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
 // GetByID retrieves a lift by its ID.
 func (r *LiftRepository) GetByID(id string) (*lift.Lift, error) {
 	ctx := context.Background()
@@ -72,6 +79,9 @@ type ListParams struct {
 	SortBy            SortField
 	SortOrder         SortOrder
 	FilterCompetition *bool
+	// ViewerID limits results to the global catalog and the viewer's organizations.
+	// Nil lists every entry.
+	ViewerID *string
 }
 
 // List retrieves lifts with pagination, sorting, and optional filtering.
@@ -100,7 +110,10 @@ func (r *LiftRepository) List(params ListParams) ([]lift.Lift, int64, error) {
 			isCompetition = 1
 		}
 
-		total, err = r.queries.CountLiftsFilteredByCompetition(ctx, isCompetition)
+		total, err = r.queries.CountLiftsFilteredByCompetition(ctx, db.CountLiftsFilteredByCompetitionParams{
+			ViewerID:          viewerParam(params.ViewerID),
+			IsCompetitionLift: isCompetition,
+		})
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to count lifts: %w", err)
 		}
@@ -109,30 +122,35 @@ func (r *LiftRepository) List(params ListParams) ([]lift.Lift, int64, error) {
 		switch {
 		case params.SortBy == SortByName && params.SortOrder == SortAsc:
 			dbLifts, err = r.queries.ListLiftsFilteredByCompetitionByNameAsc(ctx, db.ListLiftsFilteredByCompetitionByNameAscParams{
+				ViewerID:          viewerParam(params.ViewerID),
 				IsCompetitionLift: isCompetition,
 				Limit:             params.Limit,
 				Offset:            params.Offset,
 			})
 		case params.SortBy == SortByName && params.SortOrder == SortDesc:
 			dbLifts, err = r.queries.ListLiftsFilteredByCompetitionByNameDesc(ctx, db.ListLiftsFilteredByCompetitionByNameDescParams{
+				ViewerID:          viewerParam(params.ViewerID),
 				IsCompetitionLift: isCompetition,
 				Limit:             params.Limit,
 				Offset:            params.Offset,
 			})
 		case params.SortBy == SortByCreatedAt && params.SortOrder == SortAsc:
 			dbLifts, err = r.queries.ListLiftsFilteredByCompetitionByCreatedAtAsc(ctx, db.ListLiftsFilteredByCompetitionByCreatedAtAscParams{
+				ViewerID:          viewerParam(params.ViewerID),
 				IsCompetitionLift: isCompetition,
 				Limit:             params.Limit,
 				Offset:            params.Offset,
 			})
 		case params.SortBy == SortByCreatedAt && params.SortOrder == SortDesc:
 			dbLifts, err = r.queries.ListLiftsFilteredByCompetitionByCreatedAtDesc(ctx, db.ListLiftsFilteredByCompetitionByCreatedAtDescParams{
+				ViewerID:          viewerParam(params.ViewerID),
 				IsCompetitionLift: isCompetition,
 				Limit:             params.Limit,
 				Offset:            params.Offset,
 			})
 		default:
 			dbLifts, err = r.queries.ListLiftsFilteredByCompetitionByNameAsc(ctx, db.ListLiftsFilteredByCompetitionByNameAscParams{
+				ViewerID:          viewerParam(params.ViewerID),
 				IsCompetitionLift: isCompetition,
 				Limit:             params.Limit,
 				Offset:            params.Offset,
@@ -140,7 +158,7 @@ func (r *LiftRepository) List(params ListParams) ([]lift.Lift, int64, error) {
 		}
 	} else {
 		// No filter
-		total, err = r.queries.CountLifts(ctx)
+		total, err = r.queries.CountLifts(ctx, viewerParam(params.ViewerID))
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to count lifts: %w", err)
 		}
@@ -149,28 +167,33 @@ func (r *LiftRepository) List(params ListParams) ([]lift.Lift, int64, error) {
 		switch {
 		case params.SortBy == SortByName && params.SortOrder == SortAsc:
 			dbLifts, err = r.queries.ListLiftsByNameAsc(ctx, db.ListLiftsByNameAscParams{
-				Limit:  params.Limit,
-				Offset: params.Offset,
+				ViewerID: viewerParam(params.ViewerID),
+				Limit:    params.Limit,
+				Offset:   params.Offset,
 			})
 		case params.SortBy == SortByName && params.SortOrder == SortDesc:
 			dbLifts, err = r.queries.ListLiftsByNameDesc(ctx, db.ListLiftsByNameDescParams{
-				Limit:  params.Limit,
-				Offset: params.Offset,
+				ViewerID: viewerParam(params.ViewerID),
+				Limit:    params.Limit,
+				Offset:   params.Offset,
 			})
 		case params.SortBy == SortByCreatedAt && params.SortOrder == SortAsc:
 			dbLifts, err = r.queries.ListLiftsByCreatedAtAsc(ctx, db.ListLiftsByCreatedAtAscParams{
-				Limit:  params.Limit,
-				Offset: params.Offset,
+				ViewerID: viewerParam(params.ViewerID),
+				Limit:    params.Limit,
+				Offset:   params.Offset,
 			})
 		case params.SortBy == SortByCreatedAt && params.SortOrder == SortDesc:
 			dbLifts, err = r.queries.ListLiftsByCreatedAtDesc(ctx, db.ListLiftsByCreatedAtDescParams{
-				Limit:  params.Limit,
-				Offset: params.Offset,
+				ViewerID: viewerParam(params.ViewerID),
+				Limit:    params.Limit,
+				Offset:   params.Offset,
 			})
 		default:
 			dbLifts, err = r.queries.ListLiftsByNameAsc(ctx, db.ListLiftsByNameAscParams{
-				Limit:  params.Limit,
-				Offset: params.Offset,
+				ViewerID: viewerParam(params.ViewerID),
+				Limit:    params.Limit,
+				Offset:   params.Offset,
 			})
 		}
 	}
@@ -221,6 +244,7 @@ func (r *LiftRepository) Create(l *lift.Lift) error {
 		ParentLiftID:      stringPtrToNullString(l.ParentLiftID),
 		CreatedAt:         l.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:         l.UpdatedAt.Format(time.RFC3339),
+		OrganizationID:    stringPtrToNullString(l.OrganizationID),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create lift: %w", err)
@@ -282,7 +306,17 @@ func dbLiftToDomain(dbLift db.Lift) *lift.Lift {
 		ParentLiftID:      nullStringToStringPtr(dbLift.ParentLiftID),
 		CreatedAt:         createdAt,
 		UpdatedAt:         updatedAt,
+		OrganizationID:    nullStringToStringPtr(dbLift.OrganizationID),
+	}
+}
+
+// viewerParam converts an optional viewer ID to the nullable parameter the
+// catalog visibility filters expect.
+func viewerParam(viewerID *string) interface{} {
+	if viewerID == nil {
+		return nil
 	}
+	return *viewerID
 }
 
 func boolToInt64(b bool) int64 {
@@ -23,12 +23,27 @@ func NewLiftMaxRepository(sqlDB *sql.DB) *LiftMaxRepository {
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *LiftMaxRepository) WithTx(tx *sql.Tx) *LiftMaxRepository {
+	return &LiftMaxRepository{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
+// LiftMaxFilter narrows the lift maxes listed for a user.
+type LiftMaxFilter struct {
+	UserID string
+	LiftID *string
+	Type   *string
+	From   *time.Time
+	To     *time.Time
+}
+
 // LiftMaxListParams contains parameters for listing lift maxes.
 type LiftMaxListParams struct {
-	UserID    string
-	LiftID    *string
-	Type      *string
+	Filter    LiftMaxFilter
 	SortOrder SortOrder
+	After     *Keyset
 	Limit     int64
 	Offset    int64
 }
@@ -80,8 +95,9 @@ func (r *LiftMaxRepository) GetCurrentMax(userID, liftID, maxType string) (*lift
 	return dbLiftMaxToDomain(dbMax), nil
 }
 
-// List retrieves lift maxes with pagination and optional filtering.
-func (r *LiftMaxRepository) List(params LiftMaxListParams) ([]liftmax.LiftMax, int64, error) {
+// List retrieves a page of a user's lift maxes ordered by effective date (latest first by
+// default), along with the position the next page starts after, or nil on the last page.
+func (r *LiftMaxRepository) List(params LiftMaxListParams) ([]liftmax.LiftMax, *Keyset, error) {
 	ctx := context.Background()
 
 	// Set defaults
@@ -91,111 +107,75 @@ func (r *LiftMaxRepository) List(params LiftMaxListParams) ([]liftmax.LiftMax, i
 	if params.SortOrder == "" {
 		params.SortOrder = SortDesc // Default to descending by effective_date
 	}
+	start := keysetStart(params.After, params.SortOrder)
 
-	var dbMaxes []db.LiftMax
-	var total int64
+	var dbMaxes []db.ListLiftMaxesByUserByEffectiveDateAscRow
 	var err error
-
-	// Determine which query variant to use based on filters
-	hasLiftFilter := params.LiftID != nil
-	hasTypeFilter := params.Type != nil
-
-	// Get count
-	switch {
-	case hasLiftFilter && hasTypeFilter:
-		total, err = r.queries.CountLiftMaxesByUserFilterLiftAndType(ctx, db.CountLiftMaxesByUserFilterLiftAndTypeParams{
-			UserID: params.UserID,
-			LiftID: *params.LiftID,
-			Type:   *params.Type,
-		})
-	case hasLiftFilter:
-		total, err = r.queries.CountLiftMaxesByUserFilterLift(ctx, db.CountLiftMaxesByUserFilterLiftParams{
-			UserID: params.UserID,
-			LiftID: *params.LiftID,
+	if params.SortOrder == SortAsc {
+		dbMaxes, err = r.queries.ListLiftMaxesByUserByEffectiveDateAsc(ctx, db.ListLiftMaxesByUserByEffectiveDateAscParams{
+			UserID:     params.Filter.UserID,
+			LiftID:     filterParam(params.Filter.LiftID),
+			Type:       filterParam(params.Filter.Type),
+			FromTime:   fromTimeParam(params.Filter.From),
+			BeforeTime: beforeTimeParam(params.Filter.To),
+			AfterValue: start.Value,
+			AfterSeq:   start.Seq,
+			Limit:      params.Limit + 1,
+			Offset:     params.Offset,
 		})
-	case hasTypeFilter:
-		total, err = r.queries.CountLiftMaxesByUserFilterType(ctx, db.CountLiftMaxesByUserFilterTypeParams{
-			UserID: params.UserID,
-			Type:   *params.Type,
+	} else {
+		var descMaxes []db.ListLiftMaxesByUserByEffectiveDateDescRow
+		descMaxes, err = r.queries.ListLiftMaxesByUserByEffectiveDateDesc(ctx, db.ListLiftMaxesByUserByEffectiveDateDescParams{
+			UserID:     params.Filter.UserID,
+			LiftID:     filterParam(params.Filter.LiftID),
+			Type:       filterParam(params.Filter.Type),
+			FromTime:   fromTimeParam(params.Filter.From),
+			BeforeTime: beforeTimeParam(params.Filter.To),
+			AfterValue: start.Value,
+			AfterSeq:   start.Seq,
+			Limit:      params.Limit + 1,
+			Offset:     params.Offset,
 		})
-	default:
-		total, err = r.queries.CountLiftMaxesByUser(ctx, params.UserID)
+		// Convert to common row type
+		dbMaxes = make([]db.ListLiftMaxesByUserByEffectiveDateAscRow, len(descMaxes))
+		for i, row := range descMaxes {
+			dbMaxes[i] = db.ListLiftMaxesByUserByEffectiveDateAscRow(row)
+		}
 	}
-
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count lift maxes: %w", err)
-	}
-
-	// Get data based on filters and sort order
-	switch {
-	case hasLiftFilter && hasTypeFilter && params.SortOrder == SortDesc:
-		dbMaxes, err = r.queries.ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateDesc(ctx, db.ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateDescParams{
-			UserID: params.UserID,
-			LiftID: *params.LiftID,
-			Type:   *params.Type,
-			Limit:  params.Limit,
-			Offset: params.Offset,
-		})
-	case hasLiftFilter && hasTypeFilter && params.SortOrder == SortAsc:
-		dbMaxes, err = r.queries.ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateAsc(ctx, db.ListLiftMaxesByUserFilterLiftAndTypeByEffectiveDateAscParams{
-			UserID: params.UserID,
-			LiftID: *params.LiftID,
-			Type:   *params.Type,
-			Limit:  params.Limit,
-			Offset: params.Offset,
-		})
-	case hasLiftFilter && params.SortOrder == SortDesc:
-		dbMaxes, err = r.queries.ListLiftMaxesByUserFilterLiftByEffectiveDateDesc(ctx, db.ListLiftMaxesByUserFilterLiftByEffectiveDateDescParams{
-			UserID: params.UserID,
-			LiftID: *params.LiftID,
-			Limit:  params.Limit,
-			Offset: params.Offset,
-		})
-	case hasLiftFilter && params.SortOrder == SortAsc:
-		dbMaxes, err = r.queries.ListLiftMaxesByUserFilterLiftByEffectiveDateAsc(ctx, db.ListLiftMaxesByUserFilterLiftByEffectiveDateAscParams{
-			UserID: params.UserID,
-			LiftID: *params.LiftID,
-			Limit:  params.Limit,
-			Offset: params.Offset,
-		})
-	case hasTypeFilter && params.SortOrder == SortDesc:
-		dbMaxes, err = r.queries.ListLiftMaxesByUserFilterTypeByEffectiveDateDesc(ctx, db.ListLiftMaxesByUserFilterTypeByEffectiveDateDescParams{
-			UserID: params.UserID,
-			Type:   *params.Type,
-			Limit:  params.Limit,
-			Offset: params.Offset,
-		})
-	case hasTypeFilter && params.SortOrder == SortAsc:
-		dbMaxes, err = r.queries.ListLiftMaxesByUserFilterTypeByEffectiveDateAsc(ctx, db.ListLiftMaxesByUserFilterTypeByEffectiveDateAscParams{
-			UserID: params.UserID,
-			Type:   *params.Type,
-			Limit:  params.Limit,
-			Offset: params.Offset,
-		})
-	case params.SortOrder == SortAsc:
-		dbMaxes, err = r.queries.ListLiftMaxesByUserByEffectiveDateAsc(ctx, db.ListLiftMaxesByUserByEffectiveDateAscParams{
-			UserID: params.UserID,
-			Limit:  params.Limit,
-			Offset: params.Offset,
-		})
-	default:
-		dbMaxes, err = r.queries.ListLiftMaxesByUserByEffectiveDateDesc(ctx, db.ListLiftMaxesByUserByEffectiveDateDescParams{
-			UserID: params.UserID,
-			Limit:  params.Limit,
-			Offset: params.Offset,
-		})
+		return nil, nil, fmt.Errorf("failed to list lift maxes: %w", err)
 	}
 
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list lift maxes: %w", err)
+	// One row past the limit is fetched to tell whether another page follows
+	var next *Keyset
+	if int64(len(dbMaxes)) > params.Limit {
+		dbMaxes = dbMaxes[:params.Limit]
+		last := dbMaxes[len(dbMaxes)-1]
+		next = &Keyset{Value: last.LiftMax.EffectiveDate, Seq: last.Seq}
 	}
 
 	maxes := make([]liftmax.LiftMax, len(dbMaxes))
-	for i, dbMax := range dbMaxes {
-		maxes[i] = *dbLiftMaxToDomain(dbMax)
+	for i, row := range dbMaxes {
+		maxes[i] = *dbLiftMaxToDomain(row.LiftMax)
 	}
 
-	return maxes, total, nil
+	return maxes, next, nil
+}
+
+// Count returns the number of a user's lift maxes matching the filter.
+func (r *LiftMaxRepository) Count(filter LiftMaxFilter) (int64, error) {
+	ctx := context.Background()
+	count, err := r.queries.CountLiftMaxesByUser(ctx, db.CountLiftMaxesByUserParams{
+		UserID:     filter.UserID,
+		LiftID:     filterParam(filter.LiftID),
+		Type:       filterParam(filter.Type),
+		FromTime:   fromTimeParam(filter.From),
+		BeforeTime: beforeTimeParam(filter.To),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count lift maxes: %w", err)
+	}
+	return count, nil
 }
 
 // Create persists a new lift max to the database.
@@ -276,6 +256,24 @@ func (r *LiftMaxRepository) UniqueConstraintExists(userID, liftID, maxType strin
 	return exists == 1, nil
 }
 
+// ListChangedSince retrieves a user's lift maxes created or updated at or after since, oldest change first.
+func (r *LiftMaxRepository) ListChangedSince(userID string, since time.Time) ([]liftmax.LiftMax, error) {
+	ctx := context.Background()
+	dbMaxes, err := r.queries.ListLiftMaxChangesSince(ctx, db.ListLiftMaxChangesSinceParams{
+		UserID: userID,
+		Since:  changedSinceParam(since),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed lift maxes: %w", err)
+	}
+
+	maxes := make([]liftmax.LiftMax, len(dbMaxes))
+	for i, dbMax := range dbMaxes {
+		maxes[i] = *dbLiftMaxToDomain(dbMax)
+	}
+	return maxes, nil
+}
+
 // Helper function to convert database model to domain model.
 func dbLiftMaxToDomain(dbMax db.LiftMax) *liftmax.LiftMax {
 	effectiveDate, _ := time.Parse(time.RFC3339, dbMax.EffectiveDate)
@@ -23,6 +23,13 @@ func NewLoggedSetRepository(sqlDB *sql.DB) *LoggedSetRepository {
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *LoggedSetRepository) WithTx(tx *sql.Tx) *LoggedSetRepository {
+	return &LoggedSetRepository{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
 // GetByID retrieves a logged set by its ID.
 func (r *LoggedSetRepository) GetByID(id string) (*loggedset.LoggedSet, error) {
 	ctx := context.Background()
@@ -51,40 +58,96 @@ func (r *LoggedSetRepository) ListBySession(sessionID string) ([]loggedset.Logge
 	return sets, nil
 }
 
+// LoggedSetFilter narrows the logged sets listed for a user.
+type LoggedSetFilter struct {
+	UserID string
+	LiftID *string
+	From   *time.Time
+	To     *time.Time
+}
+
 // LoggedSetListParams contains parameters for listing logged sets by user.
 type LoggedSetListParams struct {
-	UserID string
-	Limit  int64
-	Offset int64
+	Filter    LoggedSetFilter
+	SortOrder SortOrder
+	After     *Keyset
+	Limit     int64
+	Offset    int64
 }
 
-// ListByUser retrieves logged sets for a user with pagination.
-func (r *LoggedSetRepository) ListByUser(params LoggedSetListParams) ([]loggedset.LoggedSet, int64, error) {
+// ListByUser retrieves a page of a user's logged sets ordered by creation time (newest
+// first by default), along with the position the next page starts after, or nil on the last page.
+func (r *LoggedSetRepository) ListByUser(params LoggedSetListParams) ([]loggedset.LoggedSet, *Keyset, error) {
 	ctx := context.Background()
 
 	if params.Limit <= 0 {
 		params.Limit = 20
 	}
-
-	total, err := r.queries.CountLoggedSetsByUser(ctx, params.UserID)
+	start := keysetStart(params.After, params.SortOrder)
+
+	var dbSets []db.ListLoggedSetsByUserByCreatedAtAscRow
+	var err error
+	if params.SortOrder == SortAsc {
+		dbSets, err = r.queries.ListLoggedSetsByUserByCreatedAtAsc(ctx, db.ListLoggedSetsByUserByCreatedAtAscParams{
+			UserID:     params.Filter.UserID,
+			LiftID:     filterParam(params.Filter.LiftID),
+			FromTime:   fromTimeParam(params.Filter.From),
+			BeforeTime: beforeTimeParam(params.Filter.To),
+			AfterValue: start.Value,
+			AfterSeq:   start.Seq,
+			Limit:      params.Limit + 1,
+			Offset:     params.Offset,
+		})
+	} else {
+		var descSets []db.ListLoggedSetsByUserByCreatedAtDescRow
+		descSets, err = r.queries.ListLoggedSetsByUserByCreatedAtDesc(ctx, db.ListLoggedSetsByUserByCreatedAtDescParams{
+			UserID:     params.Filter.UserID,
+			LiftID:     filterParam(params.Filter.LiftID),
+			FromTime:   fromTimeParam(params.Filter.From),
+			BeforeTime: beforeTimeParam(params.Filter.To),
+			AfterValue: start.Value,
+			AfterSeq:   start.Seq,
+			Limit:      params.Limit + 1,
+			Offset:     params.Offset,
+		})
+		// Convert to common row type
+		dbSets = make([]db.ListLoggedSetsByUserByCreatedAtAscRow, len(descSets))
+		for i, row := range descSets {
+			dbSets[i] = db.ListLoggedSetsByUserByCreatedAtAscRow(row)
+		}
+	}
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count logged sets: %w", err)
+		return nil, nil, fmt.Errorf("failed to list logged sets by user: %w", err)
 	}
 
-	dbSets, err := r.queries.ListLoggedSetsByUser(ctx, db.ListLoggedSetsByUserParams{
-		UserID: params.UserID,
-		Limit:  params.Limit,
-		Offset: params.Offset,
-	})
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list logged sets by user: %w", err)
+	// One row past the limit is fetched to tell whether another page follows
+	var next *Keyset
+	if int64(len(dbSets)) > params.Limit {
+		dbSets = dbSets[:params.Limit]
+		last := dbSets[len(dbSets)-1]
+		next = &Keyset{Value: last.CreatedAt, Seq: last.Seq}
 	}
 
 	sets := make([]loggedset.LoggedSet, len(dbSets))
 	for i, dbSet := range dbSets {
 		sets[i] = *dbListLoggedSetsByUserRowToDomain(dbSet)
 	}
-	return sets, total, nil
+	return sets, next, nil
+}
+
+// CountByUser returns the number of a user's logged sets matching the filter.
+func (r *LoggedSetRepository) CountByUser(filter LoggedSetFilter) (int64, error) {
+	ctx := context.Background()
+	count, err := r.queries.CountLoggedSetsByUser(ctx, db.CountLoggedSetsByUserParams{
+		UserID:     filter.UserID,
+		LiftID:     filterParam(filter.LiftID),
+		FromTime:   fromTimeParam(filter.From),
+		BeforeTime: beforeTimeParam(filter.To),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count logged sets: %w", err)
+	}
+	return count, nil
 }
 
 // GetLatestAMRAPForLift retrieves the most recent AMRAP set for a user's lift.
@@ -103,6 +166,51 @@ func (r *LoggedSetRepository) GetLatestAMRAPForLift(userID, liftID string) (*log
 	return dbGetLatestAMRAPForLiftRowToDomain(dbSet), nil
 }
 
+// ListChangedSince retrieves a user's logged sets created or edited at or after since,
+// including sets logged through sync since then with an earlier performed time.
+func (r *LoggedSetRepository) ListChangedSince(userID string, since time.Time) ([]loggedset.LoggedSet, error) {
+	ctx := context.Background()
+	dbSets, err := r.queries.ListLoggedSetChangesSince(ctx, db.ListLoggedSetChangesSinceParams{
+		UserID: userID,
+		Since:  changedSinceParam(since),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed logged sets: %w", err)
+	}
+
+	sets := make([]loggedset.LoggedSet, len(dbSets))
+	for i, dbSet := range dbSets {
+		sets[i] = *dbListLoggedSetChangesSinceRowToDomain(dbSet)
+	}
+	return sets, nil
+}
+
+// LoggedSetDeletion identifies a logged set that was deleted.
+type LoggedSetDeletion struct {
+	ID        string
+	SessionID string
+	DeletedAt time.Time
+}
+
+// ListDeletedSince retrieves the user's logged sets deleted at or after since, oldest first.
+func (r *LoggedSetRepository) ListDeletedSince(userID string, since time.Time) ([]LoggedSetDeletion, error) {
+	ctx := context.Background()
+	rows, err := r.queries.ListDeletedLoggedSetsSince(ctx, db.ListDeletedLoggedSetsSinceParams{
+		UserID: userID,
+		Since:  changedSinceParam(since),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted logged sets: %w", err)
+	}
+
+	deletions := make([]LoggedSetDeletion, len(rows))
+	for i, row := range rows {
+		deletedAt, _ := time.Parse(time.RFC3339, row.CreatedAt)
+		deletions[i] = LoggedSetDeletion{ID: row.LoggedSetID, SessionID: row.SessionID, DeletedAt: deletedAt}
+	}
+	return deletions, nil
+}
+
 // Create persists a new logged set to the database.
 func (r *LoggedSetRepository) Create(ls *loggedset.LoggedSet) error {
 	ctx := context.Background()
@@ -220,7 +328,7 @@ func dbListLoggedSetsBySessionRowToDomain(dbSet db.ListLoggedSetsBySessionRow) *
 	}
 }
 
-func dbListLoggedSetsByUserRowToDomain(dbSet db.ListLoggedSetsByUserRow) *loggedset.LoggedSet {
+func dbListLoggedSetsByUserRowToDomain(dbSet db.ListLoggedSetsByUserByCreatedAtAscRow) *loggedset.LoggedSet {
 	createdAt, _ := time.Parse(time.RFC3339, dbSet.CreatedAt)
 
 	return &loggedset.LoggedSet{
@@ -276,3 +384,22 @@ func dbListLoggedSetsBySessionAndPrescriptionRowToDomain(dbSet db.ListLoggedSets
 		CreatedAt:      createdAt,
 	}
 }
+
+func dbListLoggedSetChangesSinceRowToDomain(dbSet db.ListLoggedSetChangesSinceRow) *loggedset.LoggedSet {
+	createdAt, _ := time.Parse(time.RFC3339, dbSet.CreatedAt)
+
+	return &loggedset.LoggedSet{
+		ID:             dbSet.ID,
+		UserID:         dbSet.UserID,
+		SessionID:      dbSet.SessionID,
+		PrescriptionID: dbSet.PrescriptionID,
+		LiftID:         dbSet.LiftID,
+		SetNumber:      int(dbSet.SetNumber),
+		Weight:         dbSet.Weight,
+		TargetReps:     int(dbSet.TargetReps),
+		RepsPerformed:  int(dbSet.RepsPerformed),
+		IsAMRAP:        dbSet.IsAmrap,
+		RPE:            nullFloat64ToPtr(dbSet.Rpe),
+		CreatedAt:      createdAt,
+	}
+}
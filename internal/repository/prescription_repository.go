@@ -30,6 +30,15 @@ func NewPrescriptionRepository(sqlDB *sql.DB, strategyFactory *loadstrategy.Stra
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *PrescriptionRepository) WithTx(tx *sql.Tx) *PrescriptionRepository {
+	return &PrescriptionRepository{
+		queries:         r.queries.WithTx(tx),
+		strategyFactory: r.strategyFactory,
+		schemeFactory:   r.schemeFactory,
+	}
+}
+
 // PrescriptionSortField represents a field to sort by.
 type PrescriptionSortField string
 
@@ -45,6 +45,13 @@ func NewProgramProgressionRepository(sqlDB *sql.DB) *ProgramProgressionRepositor
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *ProgramProgressionRepository) WithTx(tx *sql.Tx) *ProgramProgressionRepository {
+	return &ProgramProgressionRepository{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
 // GetByID retrieves a program progression by its ID.
 func (r *ProgramProgressionRepository) GetByID(id string) (*ProgramProgressionEntity, error) {
 	ctx := context.Background()
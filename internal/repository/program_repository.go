@@ -23,6 +23,13 @@ func NewProgramRepository(sqlDB *sql.DB) *ProgramRepository {
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *ProgramRepository) WithTx(tx *sql.Tx) *ProgramRepository {
+	return &ProgramRepository{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
 // ProgramSortField represents a field to sort by.
 type ProgramSortField string
 
@@ -38,6 +45,9 @@ type ProgramListParams struct {
 	SortBy    ProgramSortField
 	SortOrder SortOrder
 	Filters   *program.FilterOptions
+	// ViewerID limits results to the global catalog and the viewer's organizations.
+	// Nil lists every entry.
+	ViewerID *string
 }
 
 // GetByID retrieves a program by its ID.
@@ -86,7 +96,7 @@ func (r *ProgramRepository) List(params ProgramListParams) ([]program.Program, i
 		params.Filters.DaysPerWeek != nil ||
 		params.Filters.Focus != nil ||
 		params.Filters.HasAmrap != nil ||
-		params.Filters.Search != nil)
+		params.Filters.Search != nil) || params.ViewerID != nil
 
 	var total int64
 	var err error
@@ -100,6 +110,7 @@ func (r *ProgramRepository) List(params ProgramListParams) ([]program.Program, i
 			Focus:       filterParams.focus,
 			HasAmrap:    filterParams.hasAmrap,
 			Search:      filterParams.search,
+			ViewerID:    viewerParam(params.ViewerID),
 		})
 	} else {
 		total, err = r.queries.CountPrograms(ctx)
@@ -175,6 +186,7 @@ func (r *ProgramRepository) listProgramsFiltered(ctx context.Context, params Pro
 			Focus:       filterParams.focus,
 			HasAmrap:    filterParams.hasAmrap,
 			Search:      filterParams.search,
+			ViewerID:    viewerParam(params.ViewerID),
 			Limit:       params.Limit,
 			Offset:      params.Offset,
 		})
@@ -185,6 +197,7 @@ func (r *ProgramRepository) listProgramsFiltered(ctx context.Context, params Pro
 			Focus:       filterParams.focus,
 			HasAmrap:    filterParams.hasAmrap,
 			Search:      filterParams.search,
+			ViewerID:    viewerParam(params.ViewerID),
 			Limit:       params.Limit,
 			Offset:      params.Offset,
 		})
@@ -203,6 +216,7 @@ func (r *ProgramRepository) listProgramsFiltered(ctx context.Context, params Pro
 			Focus:       filterParams.focus,
 			HasAmrap:    filterParams.hasAmrap,
 			Search:      filterParams.search,
+			ViewerID:    viewerParam(params.ViewerID),
 			Limit:       params.Limit,
 			Offset:      params.Offset,
 		})
@@ -220,6 +234,7 @@ func (r *ProgramRepository) listProgramsFiltered(ctx context.Context, params Pro
 			Focus:       filterParams.focus,
 			HasAmrap:    filterParams.hasAmrap,
 			Search:      filterParams.search,
+			ViewerID:    viewerParam(params.ViewerID),
 			Limit:       params.Limit,
 			Offset:      params.Offset,
 		})
@@ -237,6 +252,7 @@ func (r *ProgramRepository) listProgramsFiltered(ctx context.Context, params Pro
 			Focus:       filterParams.focus,
 			HasAmrap:    filterParams.hasAmrap,
 			Search:      filterParams.search,
+			ViewerID:    viewerParam(params.ViewerID),
 			Limit:       params.Limit,
 			Offset:      params.Offset,
 		})
@@ -344,6 +360,7 @@ func (r *ProgramRepository) Create(p *program.Program) error {
 		HasAmrap:        hasAmrap,
 		CreatedAt:       p.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:       p.UpdatedAt.Format(time.RFC3339),
+		OrganizationID:  stringPtrToNullString(p.OrganizationID),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create program: %w", err)
@@ -620,6 +637,7 @@ func dbProgramToDomain(dbProg db.Program) *program.Program {
 		HasAmrap:        dbProg.HasAmrap == 1,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
+		OrganizationID:  nullStringToStringPtr(dbProg.OrganizationID),
 	}
 }
 
@@ -643,6 +661,7 @@ func dbFilteredRowToDomain(row db.ListProgramsFilteredByNameAscRow) *program.Pro
 		HasAmrap:        row.HasAmrap == 1,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
+		OrganizationID:  nullStringToStringPtr(row.OrganizationID),
 	}
 }
 
@@ -666,6 +685,7 @@ func dbUnfilteredRowToDomain(row db.ListProgramsByNameAscRow) *program.Program {
 		HasAmrap:        row.HasAmrap == 1,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
+		OrganizationID:  nullStringToStringPtr(row.OrganizationID),
 	}
 }
 
@@ -689,6 +709,7 @@ func dbGetProgramRowToDomain(row db.GetProgramRow) *program.Program {
 		HasAmrap:        row.HasAmrap == 1,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
+		OrganizationID:  nullStringToStringPtr(row.OrganizationID),
 	}
 }
 
@@ -712,6 +733,7 @@ func dbGetProgramBySlugRowToDomain(row db.GetProgramBySlugRow) *program.Program
 		HasAmrap:        row.HasAmrap == 1,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
+		OrganizationID:  nullStringToStringPtr(row.OrganizationID),
 	}
 }
 
@@ -6,8 +6,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/db"
 )
 
 // ProgressionHistoryEntry represents a progression log entry with joined data.
@@ -24,6 +25,8 @@ type ProgressionHistoryEntry struct {
 	TriggerType     string          `json:"triggerType"`
 	TriggerContext  json.RawMessage `json:"triggerContext"`
 	AppliedAt       time.Time       `json:"appliedAt"`
+	RevertsLogID    *string         `json:"revertsLogId,omitempty"`
+	RevertedByLogID *string         `json:"revertedByLogId,omitempty"`
 }
 
 // ProgressionHistoryFilter contains filter parameters for querying progression history.
@@ -32,146 +35,141 @@ type ProgressionHistoryFilter struct {
 	LiftID          *string
 	ProgressionType *string
 	TriggerType     *string
-	StartDate       *time.Time
-	EndDate         *time.Time
-	Limit           int64
-	Offset          int64
+	From            *time.Time
+	To              *time.Time
+}
+
+// ProgressionHistoryListParams contains parameters for listing progression history.
+type ProgressionHistoryListParams struct {
+	Filter    ProgressionHistoryFilter
+	SortOrder SortOrder
+	After     *Keyset
+	Limit     int64
+	Offset    int64
 }
 
 // ProgressionHistoryRepository handles progression history queries.
 type ProgressionHistoryRepository struct {
-	db *sql.DB
+	queries *db.Queries
 }
 
 // NewProgressionHistoryRepository creates a new ProgressionHistoryRepository.
 func NewProgressionHistoryRepository(sqlDB *sql.DB) *ProgressionHistoryRepository {
 	return &ProgressionHistoryRepository{
-		db: sqlDB,
+		queries: db.New(sqlDB),
 	}
 }
 
-// List retrieves progression history entries with filters.
-func (r *ProgressionHistoryRepository) List(ctx context.Context, filter ProgressionHistoryFilter) ([]ProgressionHistoryEntry, int64, error) {
-	// Set defaults
-	if filter.Limit <= 0 {
-		filter.Limit = 20
-	}
-	if filter.Limit > 100 {
-		filter.Limit = 100
-	}
-
-	// Build WHERE clause dynamically
-	var whereConditions []string
-	var args []interface{}
-
-	whereConditions = append(whereConditions, "pl.user_id = ?")
-	args = append(args, filter.UserID)
-
-	if filter.LiftID != nil {
-		whereConditions = append(whereConditions, "pl.lift_id = ?")
-		args = append(args, *filter.LiftID)
-	}
-
-	if filter.ProgressionType != nil {
-		whereConditions = append(whereConditions, "p.type = ?")
-		args = append(args, *filter.ProgressionType)
+// WithTx returns a repository that runs its queries in tx.
+func (r *ProgressionHistoryRepository) WithTx(tx *sql.Tx) *ProgressionHistoryRepository {
+	return &ProgressionHistoryRepository{
+		queries: r.queries.WithTx(tx),
 	}
+}
 
-	if filter.TriggerType != nil {
-		whereConditions = append(whereConditions, "pl.trigger_type = ?")
-		args = append(args, *filter.TriggerType)
+// List retrieves a page of progression history entries ordered by when they were applied
+// (most recent first by default), along with the position the next page starts after, or
+// nil on the last page.
+func (r *ProgressionHistoryRepository) List(ctx context.Context, params ProgressionHistoryListParams) ([]ProgressionHistoryEntry, *Keyset, error) {
+	// Set defaults
+	if params.Limit <= 0 {
+		params.Limit = 20
 	}
-
-	if filter.StartDate != nil {
-		whereConditions = append(whereConditions, "pl.applied_at >= ?")
-		args = append(args, filter.StartDate.Format(time.RFC3339))
+	if params.Limit > 100 {
+		params.Limit = 100
 	}
-
-	if filter.EndDate != nil {
-		whereConditions = append(whereConditions, "pl.applied_at <= ?")
-		args = append(args, filter.EndDate.Format(time.RFC3339))
+	start := keysetStart(params.After, params.SortOrder)
+	filter := params.Filter
+
+	var rows []db.ListProgressionHistoryByAppliedAtAscRow
+	var err error
+	if params.SortOrder == SortAsc {
+		rows, err = r.queries.ListProgressionHistoryByAppliedAtAsc(ctx, db.ListProgressionHistoryByAppliedAtAscParams{
+			UserID:          filter.UserID,
+			LiftID:          filterParam(filter.LiftID),
+			ProgressionType: filterParam(filter.ProgressionType),
+			TriggerType:     filterParam(filter.TriggerType),
+			FromTime:        fromTimeParam(filter.From),
+			BeforeTime:      beforeTimeParam(filter.To),
+			AfterValue:      start.Value,
+			AfterSeq:        start.Seq,
+			Limit:           params.Limit + 1,
+			Offset:          params.Offset,
+		})
+	} else {
+		var descRows []db.ListProgressionHistoryByAppliedAtDescRow
+		descRows, err = r.queries.ListProgressionHistoryByAppliedAtDesc(ctx, db.ListProgressionHistoryByAppliedAtDescParams{
+			UserID:          filter.UserID,
+			LiftID:          filterParam(filter.LiftID),
+			ProgressionType: filterParam(filter.ProgressionType),
+			TriggerType:     filterParam(filter.TriggerType),
+			FromTime:        fromTimeParam(filter.From),
+			BeforeTime:      beforeTimeParam(filter.To),
+			AfterValue:      start.Value,
+			AfterSeq:        start.Seq,
+			Limit:           params.Limit + 1,
+			Offset:          params.Offset,
+		})
+		// Convert to common row type
+		rows = make([]db.ListProgressionHistoryByAppliedAtAscRow, len(descRows))
+		for i, row := range descRows {
+			rows[i] = db.ListProgressionHistoryByAppliedAtAscRow(row)
+		}
 	}
-
-	whereClause := strings.Join(whereConditions, " AND ")
-
-	// Get total count
-	countQuery := `
-		SELECT COUNT(*) FROM progression_logs pl
-		JOIN progressions p ON pl.progression_id = p.id
-		WHERE ` + whereClause
-
-	var total int64
-	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count progression history: %w", err)
+		return nil, nil, fmt.Errorf("failed to list progression history: %w", err)
 	}
 
-	// Get entries with pagination
-	listQuery := `
-		SELECT
-			pl.id,
-			pl.user_id,
-			pl.progression_id,
-			pl.lift_id,
-			pl.previous_value,
-			pl.new_value,
-			pl.delta,
-			pl.trigger_type,
-			pl.trigger_context,
-			pl.applied_at,
-			p.name AS progression_name,
-			p.type AS progression_type,
-			l.name AS lift_name
-		FROM progression_logs pl
-		JOIN progressions p ON pl.progression_id = p.id
-		JOIN lifts l ON pl.lift_id = l.id
-		WHERE ` + whereClause + `
-		ORDER BY pl.applied_at DESC
-		LIMIT ? OFFSET ?`
-
-	// Add pagination args
-	listArgs := append(args, filter.Limit, filter.Offset)
-
-	rows, err := r.db.QueryContext(ctx, listQuery, listArgs...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list progression history: %w", err)
+	// One row past the limit is fetched to tell whether another page follows
+	var next *Keyset
+	if int64(len(rows)) > params.Limit {
+		rows = rows[:params.Limit]
+		last := rows[len(rows)-1]
+		next = &Keyset{Value: last.AppliedAt, Seq: last.Seq}
 	}
-	defer rows.Close()
 
-	var entries []ProgressionHistoryEntry
-	for rows.Next() {
-		var entry ProgressionHistoryEntry
-		var userID, appliedAtStr, triggerContext string
-
-		err := rows.Scan(
-			&entry.ID,
-			&userID,
-			&entry.ProgressionID,
-			&entry.LiftID,
-			&entry.PreviousValue,
-			&entry.NewValue,
-			&entry.Delta,
-			&entry.TriggerType,
-			&triggerContext,
-			&appliedAtStr,
-			&entry.ProgressionName,
-			&entry.ProgressionType,
-			&entry.LiftName,
-		)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan progression history entry: %w", err)
+	entries := make([]ProgressionHistoryEntry, len(rows))
+	for i, row := range rows {
+		appliedAt, _ := time.Parse(time.RFC3339, row.AppliedAt)
+		entries[i] = ProgressionHistoryEntry{
+			ID:              row.ID,
+			ProgressionID:   row.ProgressionID,
+			ProgressionName: row.ProgressionName,
+			ProgressionType: row.ProgressionType,
+			LiftID:          row.LiftID,
+			LiftName:        row.LiftName,
+			PreviousValue:   row.PreviousValue,
+			NewValue:        row.NewValue,
+			Delta:           row.Delta,
+			TriggerType:     row.TriggerType,
+			AppliedAt:       appliedAt,
+		}
+		if row.TriggerContext.Valid {
+			entries[i].TriggerContext = json.RawMessage(row.TriggerContext.String)
+		}
+		if row.RevertsLogID.Valid {
+			entries[i].RevertsLogID = &row.RevertsLogID.String
+		}
+		if row.RevertedByLogID.Valid {
+			entries[i].RevertedByLogID = &row.RevertedByLogID.String
 		}
-
-		appliedAt, _ := time.Parse(time.RFC3339, appliedAtStr)
-		entry.AppliedAt = appliedAt
-		entry.TriggerContext = json.RawMessage(triggerContext)
-
-		entries = append(entries, entry)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("error iterating progression history: %w", err)
-	}
+	return entries, next, nil
+}
 
-	return entries, total, nil
+// Count returns the number of progression history entries matching the filter.
+func (r *ProgressionHistoryRepository) Count(ctx context.Context, filter ProgressionHistoryFilter) (int64, error) {
+	count, err := r.queries.CountProgressionHistory(ctx, db.CountProgressionHistoryParams{
+		UserID:          filter.UserID,
+		LiftID:          filterParam(filter.LiftID),
+		ProgressionType: filterParam(filter.ProgressionType),
+		TriggerType:     filterParam(filter.TriggerType),
+		FromTime:        fromTimeParam(filter.From),
+		BeforeTime:      beforeTimeParam(filter.To),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count progression history: %w", err)
+	}
+	return count, nil
 }
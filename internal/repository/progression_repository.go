@@ -43,6 +43,13 @@ func NewProgressionRepository(sqlDB *sql.DB) *ProgressionRepository {
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *ProgressionRepository) WithTx(tx *sql.Tx) *ProgressionRepository {
+	return &ProgressionRepository{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
 // GetByID retrieves a progression by its ID.
 func (r *ProgressionRepository) GetByID(id string) (*ProgressionEntity, error) {
 	ctx := context.Background()
@@ -23,6 +23,13 @@ func NewUserProgramStateRepository(sqlDB *sql.DB) *UserProgramStateRepository {
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *UserProgramStateRepository) WithTx(tx *sql.Tx) *UserProgramStateRepository {
+	return &UserProgramStateRepository{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
 // GetByUserID retrieves a user's program state by their user ID.
 func (r *UserProgramStateRepository) GetByUserID(userID string) (*userprogramstate.UserProgramState, error) {
 	ctx := context.Background()
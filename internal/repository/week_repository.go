@@ -23,6 +23,13 @@ func NewWeekRepository(sqlDB *sql.DB) *WeekRepository {
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *WeekRepository) WithTx(tx *sql.Tx) *WeekRepository {
+	return &WeekRepository{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
 // WeekSortField represents a field to sort by.
 type WeekSortField string
 
@@ -24,6 +24,13 @@ func NewWeeklyLookupRepository(sqlDB *sql.DB) *WeeklyLookupRepository {
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *WeeklyLookupRepository) WithTx(tx *sql.Tx) *WeeklyLookupRepository {
+	return &WeeklyLookupRepository{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
 // WeeklyLookupSortField represents a field to sort by.
 type WeeklyLookupSortField string
 
@@ -38,6 +45,9 @@ type WeeklyLookupListParams struct {
 	Offset    int64
 	SortBy    WeeklyLookupSortField
 	SortOrder SortOrder
+	// ViewerID limits results to the global catalog and the viewer's organizations.
+	// Nil lists every entry.
+	ViewerID *string
 }
 
 // GetByID retrieves a weekly lookup by its ID.
@@ -68,7 +78,7 @@ func (r *WeeklyLookupRepository) List(params WeeklyLookupListParams) ([]weeklylo
 		params.SortOrder = SortAsc
 	}
 
-	total, err := r.queries.CountWeeklyLookups(ctx)
+	total, err := r.queries.CountWeeklyLookups(ctx, viewerParam(params.ViewerID))
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count weekly lookups: %w", err)
 	}
@@ -79,28 +89,33 @@ func (r *WeeklyLookupRepository) List(params WeeklyLookupListParams) ([]weeklylo
 	switch {
 	case params.SortBy == WeeklyLookupSortByName && params.SortOrder == SortAsc:
 		dbLookups, err = r.queries.ListWeeklyLookupsByNameAsc(ctx, db.ListWeeklyLookupsByNameAscParams{
-			Limit:  params.Limit,
-			Offset: params.Offset,
+			ViewerID: viewerParam(params.ViewerID),
+			Limit:    params.Limit,
+			Offset:   params.Offset,
 		})
 	case params.SortBy == WeeklyLookupSortByName && params.SortOrder == SortDesc:
 		dbLookups, err = r.queries.ListWeeklyLookupsByNameDesc(ctx, db.ListWeeklyLookupsByNameDescParams{
-			Limit:  params.Limit,
-			Offset: params.Offset,
+			ViewerID: viewerParam(params.ViewerID),
+			Limit:    params.Limit,
+			Offset:   params.Offset,
 		})
 	case params.SortBy == WeeklyLookupSortByCreatedAt && params.SortOrder == SortAsc:
 		dbLookups, err = r.queries.ListWeeklyLookupsByCreatedAtAsc(ctx, db.ListWeeklyLookupsByCreatedAtAscParams{
-			Limit:  params.Limit,
-			Offset: params.Offset,
+			ViewerID: viewerParam(params.ViewerID),
+			Limit:    params.Limit,
+			Offset:   params.Offset,
 		})
 	case params.SortBy == WeeklyLookupSortByCreatedAt && params.SortOrder == SortDesc:
 		dbLookups, err = r.queries.ListWeeklyLookupsByCreatedAtDesc(ctx, db.ListWeeklyLookupsByCreatedAtDescParams{
-			Limit:  params.Limit,
-			Offset: params.Offset,
+			ViewerID: viewerParam(params.ViewerID),
+			Limit:    params.Limit,
+			Offset:   params.Offset,
 		})
 	default:
 		dbLookups, err = r.queries.ListWeeklyLookupsByNameAsc(ctx, db.ListWeeklyLookupsByNameAscParams{
-			Limit:  params.Limit,
-			Offset: params.Offset,
+			ViewerID: viewerParam(params.ViewerID),
+			Limit:    params.Limit,
+			Offset:   params.Offset,
 		})
 	}
 
@@ -130,12 +145,13 @@ func (r *WeeklyLookupRepository) Create(w *weeklylookup.WeeklyLookup) error {
 	}
 
 	err = r.queries.CreateWeeklyLookup(ctx, db.CreateWeeklyLookupParams{
-		ID:        w.ID,
-		Name:      w.Name,
-		Entries:   string(entriesJSON),
-		ProgramID: nullStringPtr(w.ProgramID),
-		CreatedAt: w.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: w.UpdatedAt.Format(time.RFC3339),
+		ID:             w.ID,
+		Name:           w.Name,
+		Entries:        string(entriesJSON),
+		ProgramID:      nullStringPtr(w.ProgramID),
+		CreatedAt:      w.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      w.UpdatedAt.Format(time.RFC3339),
+		OrganizationID: nullStringPtr(w.OrganizationID),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create weekly lookup: %w", err)
@@ -204,12 +220,13 @@ func dbWeeklyLookupToDomain(dbLookup db.WeeklyLookup) (*weeklylookup.WeeklyLooku
 	}
 
 	return &weeklylookup.WeeklyLookup{
-		ID:        dbLookup.ID,
-		Name:      dbLookup.Name,
-		Entries:   entries,
-		ProgramID: programID,
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
+		ID:             dbLookup.ID,
+		Name:           dbLookup.Name,
+		Entries:        entries,
+		ProgramID:      programID,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+		OrganizationID: nullStringToStringPtr(dbLookup.OrganizationID),
 	}, nil
 }
 
@@ -458,3 +458,73 @@ func (r *WorkoutRepository) GetWorkoutGenerationData(userID string, weekNumber *
 		DailyLookup:   dailyLookup,
 	}, nil
 }
+
+// CycleDayData is a day of a cycle with its prescriptions.
+type CycleDayData struct {
+	Day           DayData
+	Prescriptions []*prescription.Prescription
+}
+
+// CycleWeekData is a week of a cycle with its days in order.
+type CycleWeekData struct {
+	WeekNumber int
+	Days       []CycleDayData
+}
+
+// CycleGenerationData bundles the data needed to generate every workout in a user's
+// current cycle.
+type CycleGenerationData struct {
+	Enrollment   *EnrollmentData
+	Weeks        []CycleWeekData
+	WeeklyLookup *weeklylookup.WeeklyLookup
+	DailyLookup  *dailylookup.DailyLookup
+}
+
+// GetCycleGenerationData retrieves every week and day of the user's current cycle, with
+// their prescriptions. Week numbers missing from the cycle are left out.
+func (r *WorkoutRepository) GetCycleGenerationData(userID string) (*CycleGenerationData, error) {
+	enrollment, err := r.GetEnrollmentForWorkout(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, workout.ErrUserNotEnrolled
+	}
+
+	data := &CycleGenerationData{Enrollment: enrollment}
+	for weekNumber := 1; weekNumber <= enrollment.CycleLengthWeeks; weekNumber++ {
+		weekID, err := r.GetWeekByNumberAndCycle(enrollment.CycleID, weekNumber)
+		if err != nil {
+			return nil, err
+		}
+		if weekID == "" {
+			continue
+		}
+
+		days, err := r.GetDaysForWeek(weekID)
+		if err != nil {
+			return nil, err
+		}
+		week := CycleWeekData{WeekNumber: weekNumber, Days: make([]CycleDayData, len(days))}
+		for i, day := range days {
+			prescriptions, err := r.GetPrescriptionsForDay(day.ID)
+			if err != nil {
+				return nil, err
+			}
+			week.Days[i] = CycleDayData{Day: day, Prescriptions: prescriptions}
+		}
+		data.Weeks = append(data.Weeks, week)
+	}
+
+	if enrollment.WeeklyLookupID != nil {
+		if data.WeeklyLookup, err = r.GetWeeklyLookup(*enrollment.WeeklyLookupID); err != nil {
+			return nil, err
+		}
+	}
+	if enrollment.DailyLookupID != nil {
+		if data.DailyLookup, err = r.GetDailyLookup(*enrollment.DailyLookupID); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
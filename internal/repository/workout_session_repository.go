@@ -23,6 +23,13 @@ func NewWorkoutSessionRepository(sqlDB *sql.DB) *WorkoutSessionRepository {
 	}
 }
 
+// WithTx returns a repository that runs its queries in tx.
+func (r *WorkoutSessionRepository) WithTx(tx *sql.Tx) *WorkoutSessionRepository {
+	return &WorkoutSessionRepository{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
 // GetByID retrieves a workout session by its ID.
 func (r *WorkoutSessionRepository) GetByID(id string) (*workoutsession.WorkoutSession, error) {
 	ctx := context.Background()
@@ -140,62 +147,94 @@ func (r *WorkoutSessionRepository) Delete(id string) error {
 	return nil
 }
 
-// GetByUserID retrieves paginated workout sessions for a user, optionally filtered by status.
-func (r *WorkoutSessionRepository) GetByUserID(userID string, status *string, limit, offset int) ([]*workoutsession.WorkoutSession, error) {
+// WorkoutSessionFilter narrows the workout sessions listed for a user.
+type WorkoutSessionFilter struct {
+	UserID string
+	Status *string
+	From   *time.Time
+	To     *time.Time
+}
+
+// WorkoutSessionListParams contains parameters for listing a user's workout sessions.
+type WorkoutSessionListParams struct {
+	Filter    WorkoutSessionFilter
+	SortOrder SortOrder
+	After     *Keyset
+	Limit     int64
+	Offset    int64
+}
+
+// ListByUser retrieves a page of a user's workout sessions ordered by start time (most
+// recent first by default), along with the position the next page starts after, or nil on the last page.
+func (r *WorkoutSessionRepository) ListByUser(params WorkoutSessionListParams) ([]*workoutsession.WorkoutSession, *Keyset, error) {
 	ctx := context.Background()
 
-	if status != nil {
-		dbSessions, err := r.queries.GetWorkoutSessionsByUserIDWithStatus(ctx, db.GetWorkoutSessionsByUserIDWithStatusParams{
-			UserID: userID,
-			Status: *status,
-			Limit:  int64(limit),
-			Offset: int64(offset),
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	start := keysetStart(params.After, params.SortOrder)
+
+	var dbSessions []db.ListWorkoutSessionsByUserByStartedAtAscRow
+	var err error
+	if params.SortOrder == SortAsc {
+		dbSessions, err = r.queries.ListWorkoutSessionsByUserByStartedAtAsc(ctx, db.ListWorkoutSessionsByUserByStartedAtAscParams{
+			UserID:     params.Filter.UserID,
+			Status:     filterParam(params.Filter.Status),
+			FromTime:   fromTimeParam(params.Filter.From),
+			BeforeTime: beforeTimeParam(params.Filter.To),
+			AfterValue: start.Value,
+			AfterSeq:   start.Seq,
+			Limit:      params.Limit + 1,
+			Offset:     params.Offset,
 		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get workout sessions by user ID with status: %w", err)
-		}
-
-		sessions := make([]*workoutsession.WorkoutSession, len(dbSessions))
-		for i, dbSession := range dbSessions {
-			sessions[i] = dbWorkoutSessionToDomain(dbSession)
+	} else {
+		var descSessions []db.ListWorkoutSessionsByUserByStartedAtDescRow
+		descSessions, err = r.queries.ListWorkoutSessionsByUserByStartedAtDesc(ctx, db.ListWorkoutSessionsByUserByStartedAtDescParams{
+			UserID:     params.Filter.UserID,
+			Status:     filterParam(params.Filter.Status),
+			FromTime:   fromTimeParam(params.Filter.From),
+			BeforeTime: beforeTimeParam(params.Filter.To),
+			AfterValue: start.Value,
+			AfterSeq:   start.Seq,
+			Limit:      params.Limit + 1,
+			Offset:     params.Offset,
+		})
+		// Convert to common row type
+		dbSessions = make([]db.ListWorkoutSessionsByUserByStartedAtAscRow, len(descSessions))
+		for i, row := range descSessions {
+			dbSessions[i] = db.ListWorkoutSessionsByUserByStartedAtAscRow(row)
 		}
-		return sessions, nil
 	}
-
-	dbSessions, err := r.queries.GetWorkoutSessionsByUserID(ctx, db.GetWorkoutSessionsByUserIDParams{
-		UserID: userID,
-		Limit:  int64(limit),
-		Offset: int64(offset),
-	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get workout sessions by user ID: %w", err)
+		return nil, nil, fmt.Errorf("failed to list workout sessions by user: %w", err)
+	}
+
+	// One row past the limit is fetched to tell whether another page follows
+	var next *Keyset
+	if int64(len(dbSessions)) > params.Limit {
+		dbSessions = dbSessions[:params.Limit]
+		last := dbSessions[len(dbSessions)-1]
+		next = &Keyset{Value: last.WorkoutSession.StartedAt, Seq: last.Seq}
 	}
 
 	sessions := make([]*workoutsession.WorkoutSession, len(dbSessions))
-	for i, dbSession := range dbSessions {
-		sessions[i] = dbWorkoutSessionToDomain(dbSession)
+	for i, row := range dbSessions {
+		sessions[i] = dbWorkoutSessionToDomain(row.WorkoutSession)
 	}
-	return sessions, nil
+	return sessions, next, nil
 }
 
-// CountByUserID returns the total count of workout sessions for a user, optionally filtered by status.
-func (r *WorkoutSessionRepository) CountByUserID(userID string, status *string) (int64, error) {
+// CountByUser returns the number of a user's workout sessions matching the filter.
+func (r *WorkoutSessionRepository) CountByUser(filter WorkoutSessionFilter) (int64, error) {
 	ctx := context.Background()
-
-	if status != nil {
-		count, err := r.queries.CountWorkoutSessionsByUserIDWithStatus(ctx, db.CountWorkoutSessionsByUserIDWithStatusParams{
-			UserID: userID,
-			Status: *status,
-		})
-		if err != nil {
-			return 0, fmt.Errorf("failed to count workout sessions by user ID with status: %w", err)
-		}
-		return count, nil
-	}
-
-	count, err := r.queries.CountWorkoutSessionsByUserID(ctx, userID)
+	count, err := r.queries.CountWorkoutSessionsByUser(ctx, db.CountWorkoutSessionsByUserParams{
+		UserID:     filter.UserID,
+		Status:     filterParam(filter.Status),
+		FromTime:   fromTimeParam(filter.From),
+		BeforeTime: beforeTimeParam(filter.To),
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to count workout sessions by user ID: %w", err)
+		return 0, fmt.Errorf("failed to count workout sessions by user: %w", err)
 	}
 	return count, nil
 }
@@ -213,8 +252,33 @@ func (r *WorkoutSessionRepository) GetActiveByUserID(userID string) (*workoutses
 	return dbWorkoutSessionToDomain(dbSession), nil
 }
 
+// ListChangedSince retrieves a user's workout sessions updated at or after since, oldest change first.
+func (r *WorkoutSessionRepository) ListChangedSince(userID string, since time.Time) ([]*workoutsession.WorkoutSession, error) {
+	ctx := context.Background()
+	dbSessions, err := r.queries.ListWorkoutSessionChangesSince(ctx, db.ListWorkoutSessionChangesSinceParams{
+		UserID: userID,
+		Since:  changedSinceParam(since),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed workout sessions: %w", err)
+	}
+
+	sessions := make([]*workoutsession.WorkoutSession, len(dbSessions))
+	for i, dbSession := range dbSessions {
+		sessions[i] = dbWorkoutSessionToDomain(dbSession)
+	}
+	return sessions, nil
+}
+
 // Helper functions
 
+// changedSinceParam formats a change cursor for comparison against stored timestamps.
+// Stored timestamps mix RFC3339 and RFC3339Nano, so change queries compare only the
+// second-precision prefix; the cursor is converted to the zone timestamps are written in.
+func changedSinceParam(since time.Time) string {
+	return since.In(time.Local).Format("2006-01-02T15:04:05")
+}
+
 func dbWorkoutSessionToDomain(dbSession db.WorkoutSession) *workoutsession.WorkoutSession {
 	startedAt, _ := time.Parse(time.RFC3339, dbSession.StartedAt)
 	createdAt, _ := time.Parse(time.RFC3339, dbSession.CreatedAt)
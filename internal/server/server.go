@@ -7,26 +7,99 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/waynenilsen/power-pro-v3/internal/analytics"
 	"github.com/waynenilsen/power-pro-v3/internal/api"
+	"github.com/waynenilsen/power-pro-v3/internal/audit"
 	"github.com/waynenilsen/power-pro-v3/internal/auth"
+	"github.com/waynenilsen/power-pro-v3/internal/bodyweight"
+	"github.com/waynenilsen/power-pro-v3/internal/calendar"
+	"github.com/waynenilsen/power-pro-v3/internal/coaching"
 	"github.com/waynenilsen/power-pro-v3/internal/dashboard"
-	"github.com/waynenilsen/power-pro-v3/internal/domain/event"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/loadstrategy"
 	"github.com/waynenilsen/power-pro-v3/internal/domain/setscheme"
+	"github.com/waynenilsen/power-pro-v3/internal/importer"
+	"github.com/waynenilsen/power-pro-v3/internal/mail"
 	"github.com/waynenilsen/power-pro-v3/internal/middleware"
+	"github.com/waynenilsen/power-pro-v3/internal/oidc"
+	"github.com/waynenilsen/power-pro-v3/internal/organization"
+	"github.com/waynenilsen/power-pro-v3/internal/outbox"
 	"github.com/waynenilsen/power-pro-v3/internal/profile"
 	"github.com/waynenilsen/power-pro-v3/internal/repository"
 	"github.com/waynenilsen/power-pro-v3/internal/service"
+	"github.com/waynenilsen/power-pro-v3/internal/strength"
+	"github.com/waynenilsen/power-pro-v3/internal/userdata"
+	"github.com/waynenilsen/power-pro-v3/internal/webhook"
 )
 
 // Config holds server configuration.
 type Config struct {
 	Port int
 	DB   *sql.DB
+	// WebhookClient is the HTTP client used for webhook deliveries.
+	// Nil means webhook.NewDeliveryClient, which refuses private and reserved
+	// addresses and redirects. Setting it overrides those protections; only tests
+	// that deliver to in-process receivers should.
+	WebhookClient *http.Client
+	// OIDCProviders are the identity providers users can sign in with.
+	OIDCProviders []oidc.ProviderConfig
+	// OIDCClient is the HTTP client used to talk to identity providers.
+	// Nil means a client with a 10 second timeout.
+	OIDCClient *http.Client
+	// Mailer sends password reset and email verification messages.
+	// Nil means messages are written to files in the mail directory.
+	Mailer mail.Mailer
+	// AppURL is the client app that emailed links open. Empty means messages hold only
+	// the token.
+	AppURL string
+	// RequireAdminTwoFactor withholds admin privileges from admins until they enable
+	// two-factor authentication.
+	RequireAdminTwoFactor bool
+	// RateLimits limits how fast clients can make requests. Zero limits are disabled.
+	RateLimits RateLimits
+	// LoginLockout locks out email addresses and client IPs after repeated failed logins.
+	// Zero attempts disable the lockout.
+	LoginLockout auth.LockoutConfig
+	// Sessions configures session token lifetimes and sliding expiration. Zero makes
+	// session tokens last a fixed seven days, without refresh tokens.
+	Sessions auth.SessionConfig
+	// AccountDeletionGrace is how long accounts are kept after their deletion is requested,
+	// during which it can be cancelled. Zero means 30 days.
+	AccountDeletionGrace time.Duration
+	// TrustProxy takes client IPs from the X-Forwarded-For header set by a reverse proxy.
+	// Enable it only when all requests come through one.
+	TrustProxy bool
 }
 
+// RateLimits holds the request rate limits per route group.
+type RateLimits struct {
+	// Auth limits each client IP on the public sign-in, registration and recovery routes.
+	Auth middleware.RateLimit
+	// Read limits each authenticated user's GET requests.
+	Read middleware.RateLimit
+	// Write limits each authenticated user's other requests.
+	Write middleware.RateLimit
+}
+
+// defaultMailFrom is the sender of messages written by the default mailer.
+const defaultMailFrom = "PowerPro <noreply@localhost>"
+
+// webhookRetryInterval is how often pending webhook deliveries are checked for retries.
+const webhookRetryInterval = 15 * time.Second
+
+// sessionCleanupInterval is how often expired sessions are deleted.
+const sessionCleanupInterval = time.Hour
+
+// accountDeletionInterval is how often accounts whose deletion grace period has passed
+// are deleted.
+const accountDeletionInterval = time.Hour
+
+// outboxDispatchInterval is how often stored events are dispatched when no commit has
+// triggered a dispatch, which is when subscribers that failed an event retry it.
+const outboxDispatchInterval = 5 * time.Second
+
 // Server represents the HTTP server.
 type Server struct {
 	config                 Config
@@ -49,14 +122,35 @@ type Server struct {
 	workoutSessionRepo     *repository.WorkoutSessionRepository
 	progressionService     *service.ProgressionService
 	failureService         *service.FailureService
+	prService              *service.PersonalRecordService
+	loggedSetService       *service.LoggedSetService
+	readinessService       *service.ReadinessService
+	syncService            *service.SyncService
 	sessionService         *service.SessionService
 	strategyFactory        *loadstrategy.StrategyFactory
 	schemeFactory          *setscheme.SchemeFactory
-	eventBus               *event.Bus
+	outboxService          *outbox.Service
 	authService            *auth.Service
 	authValidator          *auth.SessionValidatorAdapter
+	accessTokenService     *auth.AccessTokenService
+	oidcService            *oidc.Service
+	accountService         *auth.AccountService
+	twoFactorService       *auth.TwoFactorService
 	profileService         *profile.Service
 	dashboardService       *dashboard.Service
+	bodyweightService      *bodyweight.Service
+	strengthService        *strength.Service
+	analyticsService       *analytics.Service
+	webhookService         *webhook.Service
+	coachingService        *coaching.Service
+	orgService             *organization.Service
+	userDataService        *userdata.Service
+	importService          *importer.Service
+	calendarService        *calendar.Service
+	auditService           *audit.Service
+	streamHandler          *api.StreamHandler
+	stopWorkers            context.CancelFunc
+	workers                sync.WaitGroup
 }
 
 // New creates a new Server instance.
@@ -95,21 +189,75 @@ func New(cfg Config) *Server {
 	progressionFactory := service.GetDefaultFactory()
 	progressionService := service.NewProgressionService(cfg.DB, progressionFactory)
 	failureService := service.NewFailureService(cfg.DB, progressionFactory)
+	prService := service.NewPersonalRecordService(cfg.DB)
+	loggedSetService := service.NewLoggedSetService(cfg.DB, progressionService, prService)
+	readinessService := service.NewReadinessService(cfg.DB)
 	sessionService := service.NewSessionService(prescriptionRepo, loggedSetRepo)
-	eventBus := event.NewBus()
+	// Outbox stores state events with the changes that cause them and dispatches them
+	outboxService := outbox.NewService(cfg.DB, outbox.NewSQLiteRepository(cfg.DB))
+	syncService := service.NewSyncService(cfg.DB, outboxService, prService, failureService, readinessService)
 
 	// Auth service and validator
 	userRepo := auth.NewSQLiteUserRepository(cfg.DB)
 	authSessionRepo := auth.NewSQLiteSessionRepository(cfg.DB)
 	authService := auth.NewService(userRepo, authSessionRepo)
-	authValidator := auth.NewSessionValidatorAdapter(authService)
+	// Two-factor authentication makes logins of enrolled users return a challenge first
+	twoFactorService := auth.NewTwoFactorService(authService, auth.NewSQLiteTwoFactorRepository(cfg.DB), auth.TwoFactorConfig{
+		RequireForAdmins: cfg.RequireAdminTwoFactor,
+	})
+	authService.WithTwoFactor(twoFactorService)
+	authService.WithLoginGuard(auth.NewLoginGuard(cfg.LoginLockout)).WithSessionConfig(cfg.Sessions)
+	// Personal access tokens authenticate scripts and integrations alongside sessions
+	accessTokenRepo := auth.NewSQLiteAccessTokenRepository(cfg.DB)
+	accessTokenService := auth.NewAccessTokenService(userRepo, accessTokenRepo)
+	authValidator := auth.NewSessionValidatorAdapter(authService).WithAccessTokens(accessTokenService).WithTwoFactor(twoFactorService)
+	// Single sign-on links identities at the configured providers to users
+	oidcService := oidc.NewService(oidc.NewSQLiteRepository(cfg.DB), authService, cfg.OIDCProviders, cfg.OIDCClient)
+	// Account service mails password reset and email verification tokens
+	mailer := cfg.Mailer
+	if mailer == nil {
+		mailer = mail.NewFileMailer("mail", defaultMailFrom)
+	}
+	accountService := auth.NewAccountService(authService, userRepo, auth.NewSQLiteAccountTokenRepository(cfg.DB), accessTokenRepo, mailer, auth.AccountConfig{
+		AppURL: cfg.AppURL,
+	})
 
 	// Profile service
 	profileRepo := profile.NewSQLiteProfileRepository(cfg.DB)
 	profileService := profile.NewService(profileRepo)
 
+	// Bodyweight and strength score services
+	bodyweightRepo := bodyweight.NewSQLiteRepository(cfg.DB)
+	bodyweightService := bodyweight.NewService(bodyweightRepo, profileService)
+	strengthService := strength.NewService(cfg.DB, profileService, bodyweightService)
+
 	// Dashboard service
-	dashboardService := dashboard.NewService(cfg.DB, profileService)
+	dashboardService := dashboard.NewService(cfg.DB, profileService, strengthService)
+
+	// Training analytics service
+	analyticsService := analytics.NewService(cfg.DB, profileService)
+
+	// Webhook service delivers every stored event to matching subscriptions
+	webhookService := webhook.NewService(webhook.NewSQLiteRepository(cfg.DB), cfg.WebhookClient)
+	outboxService.Subscribe("webhooks", nil, webhookService.HandleEvent)
+
+	// Coaching service grants coaches delegated access to their athletes' data
+	coachingService := coaching.NewService(coaching.NewSQLiteRepository(cfg.DB))
+
+	// Organization service scopes private catalog entries to teams and gyms
+	orgService := organization.NewService(organization.NewSQLiteRepository(cfg.DB))
+
+	// User data service exports users' data and deletes accounts after a grace period
+	userDataService := userdata.NewService(userdata.NewSQLiteRepository(cfg.DB), cfg.AccountDeletionGrace)
+
+	// Import service brings in training history exported by other lifting apps
+	importService := importer.NewService(importer.NewSQLiteRepository(cfg.DB, prService), profileService)
+
+	// Calendar service publishes upcoming sessions as iCalendar feeds
+	calendarService := calendar.NewService(calendar.NewSQLiteRepository(cfg.DB))
+
+	// Audit service keeps an append-only log of changes to shared data
+	auditService := audit.NewService(cfg.DB, audit.NewSQLiteRepository(cfg.DB))
 
 	s := &Server{
 		config:                 cfg,
@@ -131,14 +279,32 @@ func New(cfg Config) *Server {
 		workoutSessionRepo:     workoutSessionRepo,
 		progressionService:     progressionService,
 		failureService:         failureService,
+		prService:              prService,
+		loggedSetService:       loggedSetService,
+		readinessService:       readinessService,
+		syncService:            syncService,
 		sessionService:         sessionService,
 		strategyFactory:        strategyFactory,
 		schemeFactory:          schemeFactory,
-		eventBus:               eventBus,
+		outboxService:          outboxService,
 		authService:            authService,
 		authValidator:          authValidator,
+		accessTokenService:     accessTokenService,
+		oidcService:            oidcService,
+		accountService:         accountService,
+		twoFactorService:       twoFactorService,
 		profileService:         profileService,
 		dashboardService:       dashboardService,
+		bodyweightService:      bodyweightService,
+		strengthService:        strengthService,
+		analyticsService:       analyticsService,
+		webhookService:         webhookService,
+		coachingService:        coachingService,
+		orgService:             orgService,
+		userDataService:        userDataService,
+		importService:          importService,
+		calendarService:        calendarService,
+		auditService:           auditService,
 	}
 
 	mux := http.NewServeMux()
@@ -146,11 +312,13 @@ func New(cfg Config) *Server {
 
 	s.httpServer = &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Port),
-		Handler:      mux,
+		Handler:      middleware.ClientAddress(cfg.TrustProxy)(mux),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	// Event streams stay open until the client leaves, so end them on shutdown
+	s.httpServer.RegisterOnShutdown(s.streamHandler.Close)
 
 	return s
 }
@@ -166,15 +334,34 @@ func (s *Server) Handler() http.Handler {
 // registerRoutes sets up all API routes.
 func (s *Server) registerRoutes(mux *http.ServeMux) {
 	// Create handlers
-	liftHandler := api.NewLiftHandler(s.liftRepo)
+	liftHandler := api.NewLiftHandler(s.liftRepo, s.orgService)
 	liftMaxHandler := api.NewLiftMaxHandler(s.liftMaxRepo, s.liftRepo)
 	prescriptionHandler := api.NewPrescriptionHandler(s.prescriptionRepo, s.liftRepo, s.liftMaxRepo, s.strategyFactory, s.schemeFactory)
 	dayHandler := api.NewDayHandler(s.dayRepo, s.prescriptionRepo)
 	weekHandler := api.NewWeekHandler(s.weekRepo)
 	cycleHandler := api.NewCycleHandler(s.cycleRepo)
-	weeklyLookupHandler := api.NewWeeklyLookupHandler(s.weeklyLookupRepo)
-	dailyLookupHandler := api.NewDailyLookupHandler(s.dailyLookupRepo)
-	programHandler := api.NewProgramHandler(s.programRepo, s.cycleRepo)
+	weeklyLookupHandler := api.NewWeeklyLookupHandler(s.weeklyLookupRepo, s.orgService)
+	dailyLookupHandler := api.NewDailyLookupHandler(s.dailyLookupRepo, s.orgService)
+	programHandler := api.NewProgramHandler(s.programRepo, s.cycleRepo, s.weeklyLookupRepo, s.dailyLookupRepo, s.orgService)
+	progressionHandler := api.NewProgressionHandler(s.progressionRepo)
+	programProgressionHandler := api.NewProgramProgressionHandler(s.programProgressionRepo, s.programRepo, s.progressionRepo, s.liftRepo)
+	readinessHandler := api.NewReadinessHandler(s.readinessService, s.programRepo)
+
+	// Audited entities record catalog changes, and lift max changes made for another user.
+	// Other audited entities are declared with their routes.
+	auditor := api.NewAuditor(s.auditService)
+	auditedLift := api.AuditEntity(auditor, audit.EntityLift, "id", liftHandler.WithTx, (*api.LiftHandler).Get)
+	auditedLiftMax := api.AuditEntity(auditor, audit.EntityLiftMax, "id", liftMaxHandler.WithTx, (*api.LiftMaxHandler).Get).OnBehalf()
+	auditedPrescription := api.AuditEntity(auditor, audit.EntityPrescription, "id", prescriptionHandler.WithTx, (*api.PrescriptionHandler).Get)
+	auditedDay := api.AuditEntity(auditor, audit.EntityDay, "id", dayHandler.WithTx, (*api.DayHandler).Get)
+	auditedWeek := api.AuditEntity(auditor, audit.EntityWeek, "id", weekHandler.WithTx, (*api.WeekHandler).Get)
+	auditedCycle := api.AuditEntity(auditor, audit.EntityCycle, "id", cycleHandler.WithTx, (*api.CycleHandler).Get)
+	auditedWeeklyLookup := api.AuditEntity(auditor, audit.EntityWeeklyLookup, "id", weeklyLookupHandler.WithTx, (*api.WeeklyLookupHandler).Get)
+	auditedDailyLookup := api.AuditEntity(auditor, audit.EntityDailyLookup, "id", dailyLookupHandler.WithTx, (*api.DailyLookupHandler).Get)
+	auditedProgram := api.AuditEntity(auditor, audit.EntityProgram, "id", programHandler.WithTx, (*api.ProgramHandler).Get)
+	auditedProgression := api.AuditEntity(auditor, audit.EntityProgression, "id", progressionHandler.WithTx, (*api.ProgressionHandler).Get)
+	auditedProgramProgression := api.AuditEntity(auditor, audit.EntityProgramProgression, "configId", programProgressionHandler.WithTx, (*api.ProgramProgressionHandler).Get)
+	auditedReadinessMapping := api.AuditEntity(auditor, audit.EntityReadinessMapping, "id", readinessHandler.WithTx, (*api.ReadinessHandler).GetMapping)
 
 	// Auth handler (routes don't require auth middleware)
 	authHandler := api.NewAuthHandler(s.authService)
@@ -183,11 +370,20 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	authCfg := middleware.AuthConfig{
 		WriteError:       api.WriteError,
 		SessionValidator: s.authValidator,
+		AccessChecker:    s.coachingService,
 	}
 
-	// Create middleware
-	requireAuth := middleware.RequireAuth(authCfg)
+	// Create middleware. Authenticated requests count against the user's read or write
+	// limit; the public auth routes count against the client IP's auth limit.
+	rateLimiter := middleware.NewRateLimiter(api.WriteError)
+	requireAuth := middleware.ChainMiddleware(
+		middleware.RequireAuth(authCfg),
+		rateLimiter.LimitByMethod(s.config.RateLimits.Read, s.config.RateLimits.Write),
+	)
 	requireAdmin := middleware.RequireAdmin(authCfg)
+	limitAuth := func(h http.HandlerFunc) http.Handler {
+		return rateLimiter.Limit("auth", s.config.RateLimits.Auth)(h)
+	}
 
 	// Helper to wrap handler with middleware
 	withAuth := func(h http.HandlerFunc) http.Handler {
@@ -197,23 +393,34 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 		return middleware.ChainMiddleware(requireAuth, requireAdmin)(http.HandlerFunc(h))
 	}
 
-	// LiftMax ownership check middleware
-	liftMaxOwnerCheck := func(h http.HandlerFunc) http.Handler {
-		ownerFunc := func(r *http.Request) (string, error) {
-			// For routes with {userId} in path, that is the owner
-			if userID := r.PathValue("userId"); userID != "" {
-				return userID, nil
-			}
-			// For routes with {id}, we need to look up the resource
-			// The handler will do the ownership check after fetching the resource
-			// Return empty to skip middleware ownership check
-			return "", nil
+	// userFromPath returns the user a /users/... route is scoped to
+	userFromPath := func(r *http.Request) (string, error) {
+		if userID := r.PathValue("userId"); userID != "" {
+			return userID, nil
 		}
+		return r.PathValue("id"), nil
+	}
+
+	// withUserAccess requires the route's user, an admin, or a coach the user has granted
+	// the permission to. Handlers for routes without the user in the path perform the same
+	// check once they have loaded the resource.
+	withUserAccess := func(permission coaching.Permission, h http.HandlerFunc) http.Handler {
 		return middleware.ChainMiddleware(
 			requireAuth,
-			middleware.RequireOwnerOrAdmin(authCfg, ownerFunc),
+			middleware.RequireUserAccess(authCfg, string(permission), userFromPath),
 		)(http.HandlerFunc(h))
 	}
+	// withOwner requires the route's user or an admin; coaches have no access.
+	withOwner := func(h http.HandlerFunc) http.Handler {
+		return withUserAccess("", h)
+	}
+
+	// scoped lets personal access tokens holding the scope use a route. Routes without a
+	// scope, such as account, credential, coaching and organization management, accept
+	// only session tokens.
+	scoped := func(scope auth.Scope, h http.Handler) http.Handler {
+		return middleware.AllowScope(string(scope))(h)
+	}
 
 	// Health check (no auth required)
 	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
@@ -222,222 +429,530 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	})
 
 	// Auth routes (no auth required for register/login)
-	mux.HandleFunc("POST /auth/register", authHandler.Register)
-	mux.HandleFunc("POST /auth/login", authHandler.Login)
+	mux.Handle("POST /auth/register", limitAuth(authHandler.Register))
+	mux.Handle("POST /auth/login", limitAuth(authHandler.Login))
 	mux.Handle("POST /auth/logout", withAuth(authHandler.Logout))
 	mux.Handle("GET /auth/me", withAuth(authHandler.Me))
 
+	// Session routes:
+	// - Short-lived session tokens are exchanged for new ones with single-use refresh tokens;
+	//   reusing a refresh token revokes its session
+	// - Users list their sessions and sign out one or all other sessions
+	mux.Handle("POST /auth/refresh", limitAuth(authHandler.Refresh))
+	mux.Handle("GET /auth/sessions", withAuth(authHandler.ListSessions))
+	mux.Handle("DELETE /auth/sessions/others", withAuth(authHandler.RevokeOtherSessions))
+	mux.Handle("DELETE /auth/sessions/{sessionId}", withAuth(authHandler.RevokeSession))
+
+	// Two-factor authentication routes:
+	// - Logins of enrolled users return a challenge, completed here with a code for a session
+	// - Users set up, confirm and turn off their own TOTP authenticator and recovery codes
+	// - Admins can view any user's status and turn off their two-factor authentication
+	twoFactorHandler := api.NewTwoFactorHandler(s.twoFactorService)
+	mux.Handle("POST /auth/2fa/verify", limitAuth(twoFactorHandler.Verify))
+	mux.Handle("GET /users/{userId}/2fa", withOwner(twoFactorHandler.Status))
+	mux.Handle("POST /users/{userId}/2fa/totp", withOwner(twoFactorHandler.Enroll))
+	mux.Handle("POST /users/{userId}/2fa/totp/confirm", withOwner(twoFactorHandler.Confirm))
+	auditedTwoFactor := api.AuditEntity(auditor, audit.EntityTwoFactor, "userId", twoFactorHandler.WithTx, (*api.TwoFactorHandler).Status).ForUser("userId").OnBehalf()
+	mux.Handle("DELETE /users/{userId}/2fa/totp", withOwner(auditedTwoFactor.Delete((*api.TwoFactorHandler).Disable)))
+	mux.Handle("POST /users/{userId}/2fa/recovery-codes", withOwner(twoFactorHandler.RegenerateRecoveryCodes))
+
+	// Password reset and email verification routes:
+	// - Anyone can request a reset; the response does not reveal whether the email is registered
+	// - Mailed tokens reset the password (ending all sessions and revoking access tokens) or verify an email address
+	// - Users can resend their verification and change their email; changes apply once verified
+	accountHandler := api.NewAccountHandler(s.accountService)
+	mux.Handle("POST /auth/password-reset", limitAuth(accountHandler.RequestPasswordReset))
+	mux.Handle("POST /auth/password-reset/confirm", limitAuth(accountHandler.ConfirmPasswordReset))
+	mux.Handle("POST /auth/verify-email", limitAuth(accountHandler.VerifyEmail))
+	mux.Handle("POST /users/{userId}/email-verification", withOwner(accountHandler.SendEmailVerification))
+	mux.Handle("PUT /users/{userId}/email", withOwner(accountHandler.ChangeEmail))
+
+	// Single sign-on routes (no auth required to sign in):
+	// - Login redirects to the provider; its callback returns a session like /auth/login
+	// - First sign-ins link to the account with the provider-verified email or create one
+	// - Users can list and unlink their own identities; admins can for any user
+	oidcHandler := api.NewOIDCHandler(s.oidcService)
+	mux.HandleFunc("GET /auth/oidc/providers", oidcHandler.ListProviders)
+	mux.Handle("GET /auth/oidc/{provider}/login", limitAuth(oidcHandler.Login))
+	mux.Handle("GET /auth/oidc/{provider}/callback", limitAuth(oidcHandler.Callback))
+	mux.Handle("GET /users/{userId}/identities", withOwner(oidcHandler.ListIdentities))
+	auditedIdentity := api.AuditEntity(auditor, audit.EntityIdentity, "identityId", oidcHandler.WithTx, nil).ForUser("userId").OnBehalf()
+	mux.Handle("DELETE /users/{userId}/identities/{identityId}", withOwner(auditedIdentity.Delete((*api.OIDCHandler).Unlink)))
+
+	// Personal access token routes:
+	// - Users can create, list and revoke their own tokens; tokens cannot manage tokens
+	// - Admins can list and revoke any user's tokens but not create them
+	// - Handler performs its own authorization check for creation
+	accessTokenHandler := api.NewAccessTokenHandler(s.accessTokenService)
+	mux.Handle("POST /users/{userId}/access-tokens", withOwner(accessTokenHandler.Create))
+	mux.Handle("GET /users/{userId}/access-tokens", withOwner(accessTokenHandler.List))
+	auditedAccessToken := api.AuditEntity(auditor, audit.EntityAccessToken, "tokenId", accessTokenHandler.WithTx, nil).ForUser("userId").OnBehalf()
+	mux.Handle("DELETE /users/{userId}/access-tokens/{tokenId}", withOwner(auditedAccessToken.Delete((*api.AccessTokenHandler).Revoke)))
+
+	// Data export and account deletion routes:
+	// - Users can export their data as a zip archive of JSON and CSV files
+	// - Users schedule their account's deletion with their password and can cancel it
+	//   during the grace period
+	// - Admins can export any user's data and schedule or cancel any account's deletion
+	userDataHandler := api.NewUserDataHandler(s.userDataService, s.authService)
+	mux.Handle("POST /users/{userId}/export", withOwner(userDataHandler.Export))
+	mux.Handle("GET /users/{userId}/deletion", withOwner(userDataHandler.GetDeletion))
+	auditedDeletion := api.AuditEntity(auditor, audit.EntityAccountDeletion, "userId", userDataHandler.WithTx, (*api.UserDataHandler).GetDeletion).ForUser("userId").OnBehalf()
+	mux.Handle("POST /users/{userId}/deletion", withOwner(auditedDeletion.Create((*api.UserDataHandler).ScheduleDeletion)))
+	mux.Handle("DELETE /users/{userId}/deletion", withOwner(auditedDeletion.Delete((*api.UserDataHandler).CancelDeletion)))
+
+	// Training history import routes:
+	// - Users can import CSV exports of other lifting apps, previewing them with a dry run
+	// - Admins can import and list imports for any user
+	importHandler := api.NewImportHandler(s.importService)
+	mux.Handle("POST /users/{userId}/imports", withOwner(importHandler.Create))
+	mux.Handle("GET /users/{userId}/imports", withOwner(importHandler.List))
+
 	// Profile routes:
 	// - Users can view and update their own profile
+	// - Coaches with VIEW_LOGS can view their athletes' profiles
 	// - Admins can view and update any user's profile
 	profileHandler := api.NewProfileHandler(s.profileService)
-	mux.Handle("GET /users/{userId}/profile", withAuth(profileHandler.Get))
-	mux.Handle("PUT /users/{userId}/profile", withAuth(profileHandler.Update))
+	mux.Handle("GET /users/{userId}/profile", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, profileHandler.Get)))
+	auditedProfile := api.AuditEntity(auditor, audit.EntityProfile, "userId", profileHandler.WithTx, (*api.ProfileHandler).Get).ForUser("userId").OnBehalf()
+	mux.Handle("PUT /users/{userId}/profile", withOwner(auditedProfile.Update((*api.ProfileHandler).Update)))
 
 	// Lift routes (NFR-007):
-	// - All authenticated users can read lift data
-	// - Only admins can create/update/delete lifts
-	mux.Handle("GET /lifts", withAuth(liftHandler.List))
-	mux.Handle("GET /lifts/{id}", withAuth(liftHandler.Get))
-	mux.Handle("GET /lifts/by-slug/{slug}", withAuth(liftHandler.GetBySlug))
-	mux.Handle("POST /lifts", withAdmin(liftHandler.Create))
-	mux.Handle("PUT /lifts/{id}", withAdmin(liftHandler.Update))
-	mux.Handle("DELETE /lifts/{id}", withAdmin(liftHandler.Delete))
+	// - All authenticated users can read global lifts; organization lifts only their members
+	// - Only admins can create/update/delete global lifts
+	// - Organization owners and coaches can create/update/delete their organization's lifts
+	// - Handler performs its own authorization check
+	mux.Handle("GET /lifts", scoped(auth.ScopeReadPrograms, withAuth(liftHandler.List)))
+	mux.Handle("GET /lifts/{id}", scoped(auth.ScopeReadPrograms, withAuth(liftHandler.Get)))
+	mux.Handle("GET /lifts/by-slug/{slug}", scoped(auth.ScopeReadPrograms, withAuth(liftHandler.GetBySlug)))
+	mux.Handle("POST /lifts", scoped(auth.ScopeAdminPrograms, withAuth(auditedLift.Create((*api.LiftHandler).Create))))
+	mux.Handle("PUT /lifts/{id}", scoped(auth.ScopeAdminPrograms, withAuth(auditedLift.Update((*api.LiftHandler).Update))))
+	mux.Handle("DELETE /lifts/{id}", scoped(auth.ScopeAdminPrograms, withAuth(auditedLift.Delete((*api.LiftHandler).Delete))))
 
 	// LiftMax routes (NFR-006):
 	// - Users can only access their own LiftMax data
+	// - Coaches with VIEW_LOGS can view, and with EDIT_MAXES change, their athletes' maxes
 	// - Admins can access any user's LiftMax data
-	mux.Handle("GET /users/{userId}/lift-maxes/current", liftMaxOwnerCheck(liftMaxHandler.GetCurrent))
-	mux.Handle("GET /users/{userId}/lift-maxes", liftMaxOwnerCheck(liftMaxHandler.List))
-	mux.Handle("GET /lift-maxes/{id}/convert", withAuth(liftMaxHandler.Convert))
-	mux.Handle("GET /lift-maxes/{id}", withAuth(liftMaxHandler.Get))
-	mux.Handle("POST /users/{userId}/lift-maxes", liftMaxOwnerCheck(liftMaxHandler.Create))
-	mux.Handle("PUT /lift-maxes/{id}", withAuth(liftMaxHandler.Update))
-	mux.Handle("DELETE /lift-maxes/{id}", withAuth(liftMaxHandler.Delete))
+	// - Changes to another user's maxes are recorded in the audit log
+	mux.Handle("GET /users/{userId}/lift-maxes/current", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, liftMaxHandler.GetCurrent)))
+	mux.Handle("GET /users/{userId}/lift-maxes", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, liftMaxHandler.List)))
+	mux.Handle("GET /lift-maxes/{id}/convert", scoped(auth.ScopeReadLogs, withAuth(liftMaxHandler.Convert)))
+	mux.Handle("GET /lift-maxes/{id}", scoped(auth.ScopeReadLogs, withAuth(liftMaxHandler.Get)))
+	mux.Handle("POST /users/{userId}/lift-maxes", scoped(auth.ScopeWriteMaxes, withUserAccess(coaching.PermissionEditMaxes, auditedLiftMax.Create((*api.LiftMaxHandler).Create))))
+	mux.Handle("PUT /lift-maxes/{id}", scoped(auth.ScopeWriteMaxes, withAuth(auditedLiftMax.Update((*api.LiftMaxHandler).Update))))
+	mux.Handle("DELETE /lift-maxes/{id}", scoped(auth.ScopeWriteMaxes, withAuth(auditedLiftMax.Delete((*api.LiftMaxHandler).Delete))))
 
 	// Prescription routes:
 	// - All authenticated users can read prescription data
 	// - Only admins can create/update/delete prescriptions
 	// - Authenticated users can resolve prescriptions (needs their userId for max lookup)
-	mux.Handle("GET /prescriptions", withAuth(prescriptionHandler.List))
-	mux.Handle("GET /prescriptions/{id}", withAuth(prescriptionHandler.Get))
-	mux.Handle("POST /prescriptions", withAdmin(prescriptionHandler.Create))
-	mux.Handle("PUT /prescriptions/{id}", withAdmin(prescriptionHandler.Update))
-	mux.Handle("DELETE /prescriptions/{id}", withAdmin(prescriptionHandler.Delete))
-	mux.Handle("POST /prescriptions/{id}/resolve", withAuth(prescriptionHandler.Resolve))
-	mux.Handle("POST /prescriptions/resolve-batch", withAuth(prescriptionHandler.ResolveBatch))
+	mux.Handle("GET /prescriptions", scoped(auth.ScopeReadPrograms, withAuth(prescriptionHandler.List)))
+	mux.Handle("GET /prescriptions/{id}", scoped(auth.ScopeReadPrograms, withAuth(prescriptionHandler.Get)))
+	mux.Handle("POST /prescriptions", scoped(auth.ScopeAdminPrograms, withAdmin(auditedPrescription.Create((*api.PrescriptionHandler).Create))))
+	mux.Handle("PUT /prescriptions/{id}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedPrescription.Update((*api.PrescriptionHandler).Update))))
+	mux.Handle("DELETE /prescriptions/{id}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedPrescription.Delete((*api.PrescriptionHandler).Delete))))
+	mux.Handle("POST /prescriptions/{id}/resolve", scoped(auth.ScopeReadPrograms, withAuth(prescriptionHandler.Resolve)))
+	mux.Handle("POST /prescriptions/resolve-batch", scoped(auth.ScopeReadPrograms, withAuth(prescriptionHandler.ResolveBatch)))
 
 	// Day routes:
 	// - All authenticated users can read day data
 	// - Only admins can create/update/delete days and manage prescriptions
-	mux.Handle("GET /days", withAuth(dayHandler.List))
-	mux.Handle("GET /days/{id}", withAuth(dayHandler.Get))
-	mux.Handle("GET /days/by-slug/{slug}", withAuth(dayHandler.GetBySlug))
-	mux.Handle("POST /days", withAdmin(dayHandler.Create))
-	mux.Handle("PUT /days/{id}", withAdmin(dayHandler.Update))
-	mux.Handle("DELETE /days/{id}", withAdmin(dayHandler.Delete))
-	mux.Handle("POST /days/{id}/prescriptions", withAdmin(dayHandler.AddPrescription))
-	mux.Handle("DELETE /days/{id}/prescriptions/{prescriptionId}", withAdmin(dayHandler.RemovePrescription))
-	mux.Handle("PUT /days/{id}/prescriptions/reorder", withAdmin(dayHandler.ReorderPrescriptions))
+	mux.Handle("GET /days", scoped(auth.ScopeReadPrograms, withAuth(dayHandler.List)))
+	mux.Handle("GET /days/{id}", scoped(auth.ScopeReadPrograms, withAuth(dayHandler.Get)))
+	mux.Handle("GET /days/by-slug/{slug}", scoped(auth.ScopeReadPrograms, withAuth(dayHandler.GetBySlug)))
+	mux.Handle("POST /days", scoped(auth.ScopeAdminPrograms, withAdmin(auditedDay.Create((*api.DayHandler).Create))))
+	mux.Handle("PUT /days/{id}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedDay.Update((*api.DayHandler).Update))))
+	mux.Handle("DELETE /days/{id}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedDay.Delete((*api.DayHandler).Delete))))
+	mux.Handle("POST /days/{id}/prescriptions", scoped(auth.ScopeAdminPrograms, withAdmin(auditedDay.Update((*api.DayHandler).AddPrescription))))
+	mux.Handle("DELETE /days/{id}/prescriptions/{prescriptionId}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedDay.Update((*api.DayHandler).RemovePrescription))))
+	mux.Handle("PUT /days/{id}/prescriptions/reorder", scoped(auth.ScopeAdminPrograms, withAdmin(auditedDay.Update((*api.DayHandler).ReorderPrescriptions))))
 
 	// Week routes:
 	// - All authenticated users can read week data
 	// - Only admins can create/update/delete weeks and manage day mappings
-	mux.Handle("GET /weeks", withAuth(weekHandler.List))
-	mux.Handle("GET /weeks/{id}", withAuth(weekHandler.Get))
-	mux.Handle("POST /weeks", withAdmin(weekHandler.Create))
-	mux.Handle("PUT /weeks/{id}", withAdmin(weekHandler.Update))
-	mux.Handle("DELETE /weeks/{id}", withAdmin(weekHandler.Delete))
-	mux.Handle("POST /weeks/{id}/days", withAdmin(weekHandler.AddDay))
-	mux.Handle("DELETE /weeks/{id}/days/{dayId}", withAdmin(weekHandler.RemoveDay))
+	mux.Handle("GET /weeks", scoped(auth.ScopeReadPrograms, withAuth(weekHandler.List)))
+	mux.Handle("GET /weeks/{id}", scoped(auth.ScopeReadPrograms, withAuth(weekHandler.Get)))
+	mux.Handle("POST /weeks", scoped(auth.ScopeAdminPrograms, withAdmin(auditedWeek.Create((*api.WeekHandler).Create))))
+	mux.Handle("PUT /weeks/{id}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedWeek.Update((*api.WeekHandler).Update))))
+	mux.Handle("DELETE /weeks/{id}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedWeek.Delete((*api.WeekHandler).Delete))))
+	mux.Handle("POST /weeks/{id}/days", scoped(auth.ScopeAdminPrograms, withAdmin(auditedWeek.Update((*api.WeekHandler).AddDay))))
+	mux.Handle("DELETE /weeks/{id}/days/{dayId}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedWeek.Update((*api.WeekHandler).RemoveDay))))
 
 	// Cycle routes:
 	// - All authenticated users can read cycle data
 	// - Only admins can create/update/delete cycles
-	mux.Handle("GET /cycles", withAuth(cycleHandler.List))
-	mux.Handle("GET /cycles/{id}", withAuth(cycleHandler.Get))
-	mux.Handle("POST /cycles", withAdmin(cycleHandler.Create))
-	mux.Handle("PUT /cycles/{id}", withAdmin(cycleHandler.Update))
-	mux.Handle("DELETE /cycles/{id}", withAdmin(cycleHandler.Delete))
+	mux.Handle("GET /cycles", scoped(auth.ScopeReadPrograms, withAuth(cycleHandler.List)))
+	mux.Handle("GET /cycles/{id}", scoped(auth.ScopeReadPrograms, withAuth(cycleHandler.Get)))
+	mux.Handle("POST /cycles", scoped(auth.ScopeAdminPrograms, withAdmin(auditedCycle.Create((*api.CycleHandler).Create))))
+	mux.Handle("PUT /cycles/{id}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedCycle.Update((*api.CycleHandler).Update))))
+	mux.Handle("DELETE /cycles/{id}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedCycle.Delete((*api.CycleHandler).Delete))))
 
 	// WeeklyLookup routes:
-	// - All authenticated users can read weekly lookup data
-	// - Only admins can create/update/delete weekly lookups
-	mux.Handle("GET /weekly-lookups", withAuth(weeklyLookupHandler.List))
-	mux.Handle("GET /weekly-lookups/{id}", withAuth(weeklyLookupHandler.Get))
-	mux.Handle("POST /weekly-lookups", withAdmin(weeklyLookupHandler.Create))
-	mux.Handle("PUT /weekly-lookups/{id}", withAdmin(weeklyLookupHandler.Update))
-	mux.Handle("DELETE /weekly-lookups/{id}", withAdmin(weeklyLookupHandler.Delete))
+	// - All authenticated users can read global weekly lookups; organization lookups only their members
+	// - Only admins can create/update/delete global weekly lookups
+	// - Organization owners and coaches can create/update/delete their organization's weekly lookups
+	// - Handler performs its own authorization check
+	mux.Handle("GET /weekly-lookups", scoped(auth.ScopeReadPrograms, withAuth(weeklyLookupHandler.List)))
+	mux.Handle("GET /weekly-lookups/{id}", scoped(auth.ScopeReadPrograms, withAuth(weeklyLookupHandler.Get)))
+	mux.Handle("POST /weekly-lookups", scoped(auth.ScopeAdminPrograms, withAuth(auditedWeeklyLookup.Create((*api.WeeklyLookupHandler).Create))))
+	mux.Handle("PUT /weekly-lookups/{id}", scoped(auth.ScopeAdminPrograms, withAuth(auditedWeeklyLookup.Update((*api.WeeklyLookupHandler).Update))))
+	mux.Handle("DELETE /weekly-lookups/{id}", scoped(auth.ScopeAdminPrograms, withAuth(auditedWeeklyLookup.Delete((*api.WeeklyLookupHandler).Delete))))
 
 	// DailyLookup routes:
-	// - All authenticated users can read daily lookup data
-	// - Only admins can create/update/delete daily lookups
-	mux.Handle("GET /daily-lookups", withAuth(dailyLookupHandler.List))
-	mux.Handle("GET /daily-lookups/{id}", withAuth(dailyLookupHandler.Get))
-	mux.Handle("POST /daily-lookups", withAdmin(dailyLookupHandler.Create))
-	mux.Handle("PUT /daily-lookups/{id}", withAdmin(dailyLookupHandler.Update))
-	mux.Handle("DELETE /daily-lookups/{id}", withAdmin(dailyLookupHandler.Delete))
+	// - All authenticated users can read global daily lookups; organization lookups only their members
+	// - Only admins can create/update/delete global daily lookups
+	// - Organization owners and coaches can create/update/delete their organization's daily lookups
+	// - Handler performs its own authorization check
+	mux.Handle("GET /daily-lookups", scoped(auth.ScopeReadPrograms, withAuth(dailyLookupHandler.List)))
+	mux.Handle("GET /daily-lookups/{id}", scoped(auth.ScopeReadPrograms, withAuth(dailyLookupHandler.Get)))
+	mux.Handle("POST /daily-lookups", scoped(auth.ScopeAdminPrograms, withAuth(auditedDailyLookup.Create((*api.DailyLookupHandler).Create))))
+	mux.Handle("PUT /daily-lookups/{id}", scoped(auth.ScopeAdminPrograms, withAuth(auditedDailyLookup.Update((*api.DailyLookupHandler).Update))))
+	mux.Handle("DELETE /daily-lookups/{id}", scoped(auth.ScopeAdminPrograms, withAuth(auditedDailyLookup.Delete((*api.DailyLookupHandler).Delete))))
 
 	// Program routes:
-	// - All authenticated users can read program data
-	// - Only admins can create/update/delete programs
-	mux.Handle("GET /programs", withAuth(programHandler.List))
-	mux.Handle("GET /programs/{id}", withAuth(programHandler.Get))
-	mux.Handle("POST /programs", withAdmin(programHandler.Create))
-	mux.Handle("PUT /programs/{id}", withAdmin(programHandler.Update))
-	mux.Handle("DELETE /programs/{id}", withAdmin(programHandler.Delete))
+	// - All authenticated users can read global programs; organization programs only their members
+	// - Only admins can create/update/delete global programs
+	// - Organization owners and coaches can create/update/delete their organization's programs
+	// - Handler performs its own authorization check
+	mux.Handle("GET /programs", scoped(auth.ScopeReadPrograms, withAuth(programHandler.List)))
+	mux.Handle("GET /programs/{id}", scoped(auth.ScopeReadPrograms, withAuth(programHandler.Get)))
+	mux.Handle("POST /programs", scoped(auth.ScopeAdminPrograms, withAuth(auditedProgram.Create((*api.ProgramHandler).Create))))
+	mux.Handle("PUT /programs/{id}", scoped(auth.ScopeAdminPrograms, withAuth(auditedProgram.Update((*api.ProgramHandler).Update))))
+	mux.Handle("DELETE /programs/{id}", scoped(auth.ScopeAdminPrograms, withAuth(auditedProgram.Delete((*api.ProgramHandler).Delete))))
 
 	// Progression routes:
 	// - All authenticated users can read progression data
 	// - Only admins can create/update/delete progressions
-	progressionHandler := api.NewProgressionHandler(s.progressionRepo)
-	mux.Handle("GET /progressions", withAuth(progressionHandler.List))
-	mux.Handle("GET /progressions/{id}", withAuth(progressionHandler.Get))
-	mux.Handle("POST /progressions", withAdmin(progressionHandler.Create))
-	mux.Handle("PUT /progressions/{id}", withAdmin(progressionHandler.Update))
-	mux.Handle("DELETE /progressions/{id}", withAdmin(progressionHandler.Delete))
+	mux.Handle("GET /progressions", scoped(auth.ScopeReadPrograms, withAuth(progressionHandler.List)))
+	mux.Handle("GET /progressions/{id}", scoped(auth.ScopeReadPrograms, withAuth(progressionHandler.Get)))
+	mux.Handle("POST /progressions", scoped(auth.ScopeAdminPrograms, withAdmin(auditedProgression.Create((*api.ProgressionHandler).Create))))
+	mux.Handle("PUT /progressions/{id}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedProgression.Update((*api.ProgressionHandler).Update))))
+	mux.Handle("DELETE /progressions/{id}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedProgression.Delete((*api.ProgressionHandler).Delete))))
 
 	// Program Progression Configuration routes:
 	// - All authenticated users can read program progression configurations
 	// - Only admins can create/update/delete program progression configurations
-	programProgressionHandler := api.NewProgramProgressionHandler(s.programProgressionRepo, s.programRepo, s.progressionRepo, s.liftRepo)
-	mux.Handle("GET /programs/{programId}/progressions", withAuth(programProgressionHandler.List))
-	mux.Handle("GET /programs/{programId}/progressions/{configId}", withAuth(programProgressionHandler.Get))
-	mux.Handle("POST /programs/{programId}/progressions", withAdmin(programProgressionHandler.Create))
-	mux.Handle("PUT /programs/{programId}/progressions/{configId}", withAdmin(programProgressionHandler.Update))
-	mux.Handle("DELETE /programs/{programId}/progressions/{configId}", withAdmin(programProgressionHandler.Delete))
+	mux.Handle("GET /programs/{programId}/progressions", scoped(auth.ScopeReadPrograms, withAuth(programProgressionHandler.List)))
+	mux.Handle("GET /programs/{programId}/progressions/{configId}", scoped(auth.ScopeReadPrograms, withAuth(programProgressionHandler.Get)))
+	mux.Handle("POST /programs/{programId}/progressions", scoped(auth.ScopeAdminPrograms, withAdmin(auditedProgramProgression.Create((*api.ProgramProgressionHandler).Create))))
+	mux.Handle("PUT /programs/{programId}/progressions/{configId}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedProgramProgression.Update((*api.ProgramProgressionHandler).Update))))
+	mux.Handle("DELETE /programs/{programId}/progressions/{configId}", scoped(auth.ScopeAdminPrograms, withAdmin(auditedProgramProgression.Delete((*api.ProgramProgressionHandler).Delete))))
 
 	// User Program Enrollment routes:
 	// - Users can manage their own enrollment (enroll, view, unenroll)
+	// - Users can only enroll in organization programs of their own organizations
+	// - Coaches with MANAGE_ENROLLMENT can manage, and with VIEW_LOGS view, their athletes' enrollment
 	// - Admins can manage any user's enrollment
-	enrollmentHandler := api.NewEnrollmentHandler(s.userProgramStateRepo, s.programRepo, s.workoutSessionRepo, s.eventBus)
-	mux.Handle("POST /users/{userId}/program", withAuth(enrollmentHandler.Enroll))
-	mux.Handle("GET /users/{userId}/program", withAuth(enrollmentHandler.Get))
-	mux.Handle("DELETE /users/{userId}/program", withAuth(enrollmentHandler.Unenroll))
-	mux.Handle("POST /users/{userId}/enrollment/next-cycle", withAuth(enrollmentHandler.NextCycle))
-	mux.Handle("POST /users/{userId}/enrollment/advance-week", withAuth(enrollmentHandler.AdvanceWeek))
+	enrollmentHandler := api.NewEnrollmentHandler(s.userProgramStateRepo, s.programRepo, s.workoutSessionRepo, s.progressionService, s.outboxService, s.orgService)
+	mux.Handle("POST /users/{userId}/program", withUserAccess(coaching.PermissionManageEnrollment, enrollmentHandler.Enroll))
+	mux.Handle("GET /users/{userId}/program", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, enrollmentHandler.Get)))
+	mux.Handle("DELETE /users/{userId}/program", withUserAccess(coaching.PermissionManageEnrollment, enrollmentHandler.Unenroll))
+	mux.Handle("POST /users/{userId}/enrollment/next-cycle", withUserAccess(coaching.PermissionManageEnrollment, enrollmentHandler.NextCycle))
+	mux.Handle("POST /users/{userId}/enrollment/advance-week", withUserAccess(coaching.PermissionManageEnrollment, enrollmentHandler.AdvanceWeek))
 
 	// Meet Date routes:
 	// - Users can manage their own meet date
+	// - Coaches with MANAGE_ENROLLMENT can set, and with VIEW_LOGS view, their athletes' meet dates
 	// - Admins can manage any user's meet date
 	meetDateHandler := api.NewMeetDateHandler(s.userProgramStateRepo)
-	mux.Handle("PUT /users/{userId}/programs/{programId}/state/meet-date", withAuth(meetDateHandler.SetMeetDate))
-	mux.Handle("GET /users/{userId}/programs/{programId}/state/countdown", withAuth(meetDateHandler.GetCountdown))
+	mux.Handle("PUT /users/{userId}/programs/{programId}/state/meet-date", withUserAccess(coaching.PermissionManageEnrollment, meetDateHandler.SetMeetDate))
+	mux.Handle("GET /users/{userId}/programs/{programId}/state/countdown", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, meetDateHandler.GetCountdown)))
 
 	// State Advancement routes:
 	// - Users can advance their own program state
+	// - Coaches with MANAGE_ENROLLMENT can advance their athletes' program state
 	// - Admins can advance any user's program state
 	stateAdvancementHandler := api.NewStateAdvancementHandler(s.userProgramStateRepo, s.config.DB)
-	mux.Handle("POST /users/{userId}/program-state/advance", withAuth(stateAdvancementHandler.Advance))
+	mux.Handle("POST /users/{userId}/program-state/advance", withUserAccess(coaching.PermissionManageEnrollment, stateAdvancementHandler.Advance))
 
 	// Workout Generation routes:
 	// - Users can generate/preview their own workouts
+	// - Coaches with VIEW_LOGS can generate/preview their athletes' workouts
 	// - Admins can generate/preview any user's workouts
-	workoutHandler := api.NewWorkoutHandler(s.workoutRepo, s.config.DB)
-	mux.Handle("GET /users/{userId}/workout", withAuth(workoutHandler.Generate))
-	mux.Handle("GET /users/{userId}/workout/preview", withAuth(workoutHandler.Preview))
+	workoutHandler := api.NewWorkoutHandler(s.workoutRepo, s.config.DB, s.readinessService)
+	mux.Handle("GET /users/{userId}/workout", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, workoutHandler.Generate)))
+	mux.Handle("GET /users/{userId}/workout/preview", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, workoutHandler.Preview)))
+
+	// Cycle sheet routes:
+	// - Users can print their current cycle as HTML, Markdown or CSV
+	// - Coaches with VIEW_LOGS can print their athletes' cycles
+	// - Admins can print any user's cycle
+	cycleSheetHandler := api.NewCycleSheetHandler(s.workoutRepo, s.config.DB, s.profileService)
+	mux.Handle("GET /users/{userId}/cycle-sheet", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, cycleSheetHandler.Get)))
+
+	// Calendar feed routes:
+	// - Users set up, rotate and remove their own feed; admins can for any user
+	// - Calendar apps subscribe with the secret feed URL, which needs no sign-in and is
+	//   rate limited like the other public routes
+	calendarHandler := api.NewCalendarHandler(s.calendarService, s.workoutRepo, s.config.DB, s.profileService)
+	mux.Handle("GET /users/{userId}/calendar-feed", withOwner(calendarHandler.Get))
+	mux.Handle("PUT /users/{userId}/calendar-feed", withOwner(calendarHandler.Put))
+	mux.Handle("POST /users/{userId}/calendar-feed/token", withOwner(calendarHandler.RotateToken))
+	mux.Handle("DELETE /users/{userId}/calendar-feed", withOwner(calendarHandler.Delete))
+	mux.Handle("GET /calendar/{token}", limitAuth(calendarHandler.Feed))
 
 	// Progression History routes:
 	// - Users can query their own progression history
+	// - Coaches with VIEW_LOGS can query, and with EDIT_MAXES revert, their athletes' progressions
 	// - Admins can query any user's progression history
+	// - Users can revert their own applied progressions
+	// - Reverts made for another user are recorded in the audit log
 	// - Handler performs its own authorization check
-	progressionHistoryHandler := api.NewProgressionHistoryHandler(s.progressionHistoryRepo)
-	mux.Handle("GET /users/{userId}/progression-history", withAuth(progressionHistoryHandler.List))
+	progressionHistoryHandler := api.NewProgressionHistoryHandler(s.progressionHistoryRepo, s.progressionService)
+	auditedRevert := api.AuditEntity(auditor, audit.EntityProgressionRevert, "logId", progressionHistoryHandler.WithTx, nil).ForUser("userId").OnBehalf()
+	mux.Handle("GET /users/{userId}/progression-history", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, progressionHistoryHandler.List)))
+	mux.Handle("POST /users/{userId}/progression-history/{logId}/revert", scoped(auth.ScopeWriteMaxes, withUserAccess(coaching.PermissionEditMaxes, auditedRevert.Create((*api.ProgressionHistoryHandler).Revert))))
 
 	// Manual Progression Trigger routes:
 	// - Users can trigger their own progressions
+	// - Coaches with EDIT_MAXES can trigger progressions for their athletes
 	// - Admins can trigger progressions for any user
+	// - Triggers for another user are recorded in the audit log; dry runs are not
 	// - Handler performs its own authorization check
 	manualTriggerHandler := api.NewManualTriggerHandler(s.progressionService)
-	mux.Handle("POST /users/{userId}/progressions/trigger", withAuth(manualTriggerHandler.Trigger))
+	auditedTrigger := api.AuditEntity(auditor, audit.EntityManualProgression, "", manualTriggerHandler.WithTx, nil).IDFromBody("progressionId").ForUser("userId").OnBehalf()
+	mux.Handle("POST /users/{userId}/progressions/trigger", scoped(auth.ScopeWriteMaxes, withUserAccess(coaching.PermissionEditMaxes, auditedTrigger.Create((*api.ManualTriggerHandler).Trigger))))
 
 	// Logged Set routes:
 	// - Users can log sets for their own sessions
 	// - Users can query their own logged sets
+	// - Coaches with VIEW_LOGS can query their athletes' logged sets and revisions
+	// - Users can correct or delete sets in their in-progress sessions, and amend completed ones
 	// - Handler performs its own authorization check for user-specific data
-	loggedSetHandler := api.NewLoggedSetHandler(s.loggedSetRepo, s.workoutSessionRepo, s.userProgramStateRepo, s.failureService, s.eventBus)
-	mux.Handle("POST /sessions/{sessionId}/sets", withAuth(loggedSetHandler.CreateBatch))
-	mux.Handle("GET /sessions/{sessionId}/sets", withAuth(loggedSetHandler.ListBySession))
-	mux.Handle("GET /users/{userId}/logged-sets", withAuth(loggedSetHandler.ListByUser))
+	loggedSetHandler := api.NewLoggedSetHandler(s.loggedSetRepo, s.workoutSessionRepo, s.userProgramStateRepo, s.failureService, s.prService, s.loggedSetService, s.outboxService)
+	mux.Handle("POST /sessions/{sessionId}/sets", scoped(auth.ScopeWriteSets, withAuth(loggedSetHandler.CreateBatch)))
+	mux.Handle("GET /sessions/{sessionId}/sets", scoped(auth.ScopeReadLogs, withAuth(loggedSetHandler.ListBySession)))
+	mux.Handle("GET /sessions/{sessionId}/sets/revisions", scoped(auth.ScopeReadLogs, withAuth(loggedSetHandler.ListRevisions)))
+	mux.Handle("PATCH /sessions/{sessionId}/sets/{setId}", scoped(auth.ScopeWriteSets, withAuth(loggedSetHandler.Update)))
+	mux.Handle("DELETE /sessions/{sessionId}/sets/{setId}", scoped(auth.ScopeWriteSets, withAuth(loggedSetHandler.Delete)))
+	mux.Handle("POST /workouts/{id}/amend", scoped(auth.ScopeWriteSets, withAuth(loggedSetHandler.AmendSession)))
+	mux.Handle("GET /users/{userId}/logged-sets", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, loggedSetHandler.ListByUser)))
+
+	// Sync routes:
+	// - Offline clients apply batches of recorded mutations and fetch changes since a cursor
+	// - Users can sync their own data; admins can sync any user's data
+	// - Handler performs its own authorization check
+	syncHandler := api.NewSyncHandler(s.syncService)
+	mux.Handle("POST /users/{userId}/sync", scoped(auth.ScopeWriteSets, withOwner(syncHandler.Sync)))
 
 	// Failure Counter routes:
 	// - Users can query their own failure counters
+	// - Coaches with VIEW_LOGS can query their athletes' failure counters
 	// - Admins can query any user's failure counters
 	// - Handler performs its own authorization check
 	failureCounterHandler := api.NewFailureCounterHandler(s.failureService)
-	mux.Handle("GET /users/{userId}/failure-counters", withAuth(failureCounterHandler.Get))
+	mux.Handle("GET /users/{userId}/failure-counters", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, failureCounterHandler.Get)))
+
+	// Personal Record routes:
+	// - Users can query their own current personal records
+	// - Coaches with VIEW_LOGS can query their athletes' personal records
+	// - Admins can query any user's personal records
+	// - Handler performs its own authorization check
+	personalRecordHandler := api.NewPersonalRecordHandler(s.prService)
+	mux.Handle("GET /users/{userId}/records", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, personalRecordHandler.List)))
+
+	// Readiness routes:
+	// - Users can check in and view their own readiness check-ins
+	// - Coaches with VIEW_LOGS can view their athletes' check-ins
+	// - Admins can access any user's check-ins
+	// - Anyone authenticated can view a program's readiness mapping; only admins can change it
+	mux.Handle("POST /users/{userId}/readiness", scoped(auth.ScopeWriteSets, withOwner(readinessHandler.CheckIn)))
+	mux.Handle("GET /users/{userId}/readiness", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, readinessHandler.List)))
+	mux.Handle("GET /programs/{id}/readiness-mapping", scoped(auth.ScopeReadPrograms, withAuth(readinessHandler.GetMapping)))
+	mux.Handle("PUT /programs/{id}/readiness-mapping", scoped(auth.ScopeAdminPrograms, withAdmin(auditedReadinessMapping.Update((*api.ReadinessHandler).SetMapping))))
+	mux.Handle("DELETE /programs/{id}/readiness-mapping", scoped(auth.ScopeAdminPrograms, withAdmin(auditedReadinessMapping.Delete((*api.ReadinessHandler).DeleteMapping))))
 
 	// Session routes (variable scheme next-set generation):
 	// - Users can query their next set for variable schemes during a session
 	// - Session ID is user-provided (client generates UUID)
 	sessionHandler := api.NewSessionHandler(s.sessionService)
-	mux.Handle("GET /sessions/{sessionId}/prescriptions/{prescriptionId}/next-set", withAuth(sessionHandler.GetNextSet))
+	mux.Handle("GET /sessions/{sessionId}/prescriptions/{prescriptionId}/next-set", scoped(auth.ScopeReadLogs, withAuth(sessionHandler.GetNextSet)))
 
 	// Workout Session routes:
 	// - Users can start/finish/abandon their own workout sessions
 	// - Users can view their own workout history
+	// - Coaches with VIEW_LOGS can view their athletes' sessions and workout history
+	// - Handler performs its own authorization check
+	workoutSessionHandler := api.NewWorkoutSessionHandler(s.workoutSessionRepo, s.userProgramStateRepo, s.readinessService, s.progressionService, s.outboxService)
+	mux.Handle("POST /workouts/start", scoped(auth.ScopeWriteSets, withAuth(workoutSessionHandler.Start)))
+	mux.Handle("GET /workouts/{id}", scoped(auth.ScopeReadLogs, withAuth(workoutSessionHandler.Get)))
+	mux.Handle("POST /workouts/{id}/finish", scoped(auth.ScopeWriteSets, withAuth(workoutSessionHandler.Finish)))
+	mux.Handle("POST /workouts/{id}/abandon", scoped(auth.ScopeWriteSets, withAuth(workoutSessionHandler.Abandon)))
+	mux.Handle("GET /users/{id}/workouts", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, workoutSessionHandler.ListByUser)))
+	mux.Handle("GET /users/{id}/workouts/current", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, workoutSessionHandler.GetCurrentByUser)))
+
+	// Live stream routes:
+	// - Users can stream their own workout sessions and events as Server-Sent Events
+	// - Coaches with VIEW_LOGS can stream their athletes'
+	// - Admins can stream any user's
+	// - EventSource clients cannot set headers, so the session token may be passed as access_token;
+	//   query strings reach proxy access logs, so the stream closes once that session ends
 	// - Handler performs its own authorization check
-	workoutSessionHandler := api.NewWorkoutSessionHandler(s.workoutSessionRepo, s.userProgramStateRepo, s.eventBus)
-	mux.Handle("POST /workouts/start", withAuth(workoutSessionHandler.Start))
-	mux.Handle("GET /workouts/{id}", withAuth(workoutSessionHandler.Get))
-	mux.Handle("POST /workouts/{id}/finish", withAuth(workoutSessionHandler.Finish))
-	mux.Handle("POST /workouts/{id}/abandon", withAuth(workoutSessionHandler.Abandon))
-	mux.Handle("GET /users/{id}/workouts", withAuth(workoutSessionHandler.ListByUser))
-	mux.Handle("GET /users/{id}/workouts/current", withAuth(workoutSessionHandler.GetCurrentByUser))
+	s.streamHandler = api.NewStreamHandler(s.outboxService, s.workoutSessionRepo, s.userProgramStateRepo, s.sessionService)
+	withStreamAuth := func(h http.HandlerFunc) http.Handler {
+		return middleware.ChainMiddleware(middleware.AllowQueryToken("access_token"), requireAuth)(http.HandlerFunc(h))
+	}
+	mux.Handle("GET /workouts/{id}/stream", scoped(auth.ScopeReadLogs, withStreamAuth(s.streamHandler.StreamWorkout)))
+	mux.Handle("GET /users/{userId}/stream", scoped(auth.ScopeReadLogs, middleware.AllowQueryToken("access_token")(withUserAccess(coaching.PermissionViewLogs, s.streamHandler.StreamUser))))
 
 	// Dashboard routes:
 	// - Users can only view their own dashboard (owner-only, not even admins)
 	dashboardHandler := api.NewDashboardHandler(s.dashboardService)
-	mux.Handle("GET /users/{id}/dashboard", withAuth(dashboardHandler.Get))
+	mux.Handle("GET /users/{id}/dashboard", scoped(auth.ScopeReadLogs, withOwner(dashboardHandler.Get)))
+
+	// Bodyweight routes:
+	// - Users can log, view and delete their own bodyweight entries
+	// - Coaches with VIEW_LOGS can view their athletes' bodyweight data
+	// - Admins can access any user's bodyweight data
+	// - Handler performs its own authorization check
+	bodyweightHandler := api.NewBodyweightHandler(s.bodyweightService)
+	mux.Handle("GET /users/{userId}/bodyweight/summary", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, bodyweightHandler.GetSummary)))
+	mux.Handle("GET /users/{userId}/bodyweight/trend", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, bodyweightHandler.GetTrend)))
+	mux.Handle("GET /users/{userId}/bodyweight", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, bodyweightHandler.List)))
+	mux.Handle("POST /users/{userId}/bodyweight", scoped(auth.ScopeWriteSets, withOwner(bodyweightHandler.Create)))
+	mux.Handle("DELETE /users/{userId}/bodyweight/{entryId}", scoped(auth.ScopeWriteSets, withOwner(bodyweightHandler.Delete)))
+
+	// Strength score routes:
+	// - Users can view their own DOTS / IPF GL / Wilks scores and score history
+	// - Coaches with VIEW_LOGS can view their athletes' scores
+	// - Admins can view any user's scores
+	strengthScoreHandler := api.NewStrengthScoreHandler(s.strengthService)
+	mux.Handle("GET /users/{userId}/strength-scores", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, strengthScoreHandler.Get)))
+
+	// Training analytics routes:
+	// - Users can view their own tonnage, hard sets, intensity, INOL and ACWR series
+	// - Coaches with VIEW_LOGS can view their athletes' analytics
+	// - Admins can view any user's analytics
+	analyticsHandler := api.NewAnalyticsHandler(s.analyticsService)
+	mux.Handle("GET /users/{userId}/analytics", scoped(auth.ScopeReadLogs, withUserAccess(coaching.PermissionViewLogs, analyticsHandler.Get)))
+
+	// Coaching routes:
+	// - Athletes invite coaches and coaches invite athletes, by user ID or email
+	// - The invitee accepts or declines; either party can end the relationship
+	// - Athletes choose the coach's permissions: VIEW_LOGS, EDIT_MAXES, MANAGE_ENROLLMENT, COMMENT
+	// - Coaches see events across all athletes whose logs they can view
+	// - Session owners and coaches with COMMENT can comment on workout sessions
+	// - Handler performs its own authorization check for relationship and comment routes
+	coachingHandler := api.NewCoachingHandler(s.coachingService, s.outboxService, s.workoutSessionRepo, s.userProgramStateRepo)
+	mux.Handle("POST /users/{userId}/coaches", withOwner(coachingHandler.InviteCoach))
+	mux.Handle("GET /users/{userId}/coaches", withOwner(coachingHandler.ListCoaches))
+	mux.Handle("POST /users/{userId}/athletes", withOwner(coachingHandler.InviteAthlete))
+	mux.Handle("GET /users/{userId}/athletes", withOwner(coachingHandler.ListAthletes))
+	mux.Handle("GET /users/{userId}/athletes/events", withOwner(coachingHandler.ListAthleteEvents))
+	mux.Handle("GET /coaching-relationships/{id}", withAuth(coachingHandler.Get))
+	mux.Handle("POST /coaching-relationships/{id}/accept", withAuth(coachingHandler.Accept))
+	mux.Handle("POST /coaching-relationships/{id}/decline", withAuth(coachingHandler.Decline))
+	mux.Handle("PUT /coaching-relationships/{id}/permissions", withAuth(coachingHandler.UpdatePermissions))
+	mux.Handle("DELETE /coaching-relationships/{id}", withAuth(coachingHandler.End))
+	mux.Handle("GET /workouts/{id}/comments", withAuth(coachingHandler.ListComments))
+	mux.Handle("POST /workouts/{id}/comments", withAuth(coachingHandler.CreateComment))
+	mux.Handle("DELETE /workouts/{id}/comments/{commentId}", withAuth(coachingHandler.DeleteComment))
+
+	// Organization routes:
+	// - Any authenticated user can create an organization and becomes its owner
+	// - Members can view the organization and its roster; admins can view any organization
+	// - Owners (or admins) can rename or delete it, invite members, and change or remove them
+	// - Invited users join only once they accept; until then they see neither the roster nor the catalog
+	// - Members can leave; an organization always keeps at least one owner
+	// - Owners and coaches can view the roster dashboard (not even admins otherwise)
+	// - Handler performs its own authorization check
+	organizationHandler := api.NewOrganizationHandler(s.orgService, s.dashboardService)
+	mux.Handle("POST /organizations", withAuth(organizationHandler.Create))
+	mux.Handle("GET /organizations", withAuth(organizationHandler.List))
+	mux.Handle("GET /organizations/{id}", withAuth(organizationHandler.Get))
+	mux.Handle("PUT /organizations/{id}", withAuth(organizationHandler.Update))
+	mux.Handle("DELETE /organizations/{id}", withAuth(organizationHandler.Delete))
+	mux.Handle("GET /organizations/{id}/members", withAuth(organizationHandler.ListMembers))
+	mux.Handle("PUT /organizations/{id}/members/{userId}", withAuth(organizationHandler.UpdateMember))
+	mux.Handle("DELETE /organizations/{id}/members/{userId}", withAuth(organizationHandler.RemoveMember))
+	mux.Handle("POST /organizations/{id}/invitations", withAuth(organizationHandler.Invite))
+	mux.Handle("GET /organizations/{id}/invitations", withAuth(organizationHandler.ListInvitations))
+	mux.Handle("GET /users/{userId}/organization-invitations", withOwner(organizationHandler.ListUserInvitations))
+	mux.Handle("POST /organization-invitations/{id}/accept", withAuth(organizationHandler.AcceptInvitation))
+	mux.Handle("POST /organization-invitations/{id}/decline", withAuth(organizationHandler.DeclineInvitation))
+	mux.Handle("DELETE /organization-invitations/{id}", withAuth(organizationHandler.CancelInvitation))
+	mux.Handle("GET /organizations/{id}/dashboard", withAuth(organizationHandler.Dashboard))
+
+	// Webhook routes:
+	// - Users can manage webhooks for their own events and view their delivery logs
+	// - Admins can manage admin webhooks, which receive events for every user, and any user's webhooks
+	// - Changes to admin webhooks, and to webhooks made for another user, are recorded in the audit log
+	// - Handler performs its own authorization check for subscription-specific routes
+	webhookHandler := api.NewWebhookHandler(s.webhookService)
+	auditedWebhook := api.AuditEntity(auditor, audit.EntityWebhook, "id", webhookHandler.WithTx, (*api.WebhookHandler).Get).OnBehalf()
+	mux.Handle("POST /users/{userId}/webhooks", withOwner(auditedWebhook.Create((*api.WebhookHandler).CreateForUser)))
+	mux.Handle("GET /users/{userId}/webhooks", withOwner(webhookHandler.ListForUser))
+	mux.Handle("POST /webhooks", withAdmin(auditedWebhook.Create((*api.WebhookHandler).CreateAdmin)))
+	mux.Handle("GET /webhooks", withAdmin(webhookHandler.ListAdmin))
+	mux.Handle("GET /webhooks/{id}", withAuth(webhookHandler.Get))
+	mux.Handle("PUT /webhooks/{id}", withAuth(auditedWebhook.Update((*api.WebhookHandler).Update)))
+	mux.Handle("DELETE /webhooks/{id}", withAuth(auditedWebhook.Delete((*api.WebhookHandler).Delete)))
+	mux.Handle("GET /webhooks/{id}/deliveries", withAuth(webhookHandler.ListDeliveries))
+	mux.Handle("POST /webhooks/{id}/deliveries/{deliveryId}/replay", withAuth(webhookHandler.ReplayDelivery))
+
+	// Event outbox routes (admin only):
+	// - Inspect stored state events and each handler's delivery status
+	// - Replay an event to its handlers; replays are recorded in the audit log
+	outboxHandler := api.NewOutboxHandler(s.outboxService, s.auditService)
+	mux.Handle("GET /events", withAdmin(outboxHandler.List))
+	mux.Handle("GET /events/{id}", withAdmin(outboxHandler.Get))
+	mux.Handle("POST /events/{id}/replay", withAdmin(outboxHandler.Replay))
+	mux.Handle("GET /event-handlers", withAdmin(outboxHandler.ListHandlers))
+
+	// Audit log routes (admin only):
+	// - Every change to lifts, prescriptions, days, weeks, cycles, lookups, programs and
+	//   progressions is recorded with its actor and the entity before and after the change
+	// - Admin webhook changes and event replays are recorded
+	// - Changes to a user's lift maxes, progressions, webhooks, profile, two-factor
+	//   authentication, access tokens, identities and account deletion are recorded when
+	//   made on behalf of another user
+	// - A change whose entry cannot be recorded fails with 500 Internal Server Error
+	// - Entries cannot be changed or deleted
+	auditHandler := api.NewAuditHandler(s.auditService)
+	mux.Handle("GET /admin/audit", withAdmin(auditHandler.List))
 }
 
-// Start starts the HTTP server.
+// Start starts the background workers and the HTTP server.
 func (s *Server) Start() error {
+	s.StartWorkers()
 	return s.httpServer.ListenAndServe()
 }
 
-// Stop gracefully shuts down the server.
+// StartWorkers starts the background workers: the event dispatcher, webhook retries,
+// expired session cleanup and scheduled account deletion.
+// Start calls it; call it directly when serving Handler without Start. Stop stops them.
+func (s *Server) StartWorkers() {
+	if s.stopWorkers != nil {
+		return
+	}
+	workerCtx, cancel := context.WithCancel(context.Background())
+	s.stopWorkers = cancel
+
+	s.workers.Add(4)
+	go func() {
+		defer s.workers.Done()
+		s.outboxService.Run(workerCtx, outboxDispatchInterval)
+	}()
+	go func() {
+		defer s.workers.Done()
+		s.webhookService.Run(workerCtx, webhookRetryInterval)
+	}()
+	go func() {
+		defer s.workers.Done()
+		s.authService.RunSessionCleanup(workerCtx, sessionCleanupInterval)
+	}()
+	go func() {
+		defer s.workers.Done()
+		s.userDataService.RunDeletions(workerCtx, accountDeletionInterval)
+	}()
+}
+
+// Stop gracefully shuts down the server and waits for its background workers to stop.
 func (s *Server) Stop(ctx context.Context) error {
+	if s.stopWorkers != nil {
+		s.stopWorkers()
+		s.workers.Wait()
+	}
 	return s.httpServer.Shutdown(ctx)
 }
 
@@ -0,0 +1,669 @@
+// Package service provides application service layer implementations.
+// This file implements the LoggedSetService which corrects and deletes logged sets.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/loggedset"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/progression"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/workoutsession"
+)
+
+// Errors for logged set edit operations.
+var (
+	ErrLoggedSetNotFound        = errors.New("logged set not found")
+	ErrWorkoutSessionNotFound   = errors.New("workout session not found")
+	ErrSessionRequiresAmendment = errors.New("sets in a completed session can only be changed by amending the session")
+	ErrSessionNotEditable       = errors.New("sets in an abandoned session cannot be changed")
+	ErrNoSetChanges             = errors.New("at least one set update or delete is required")
+	ErrDuplicateSetChange       = errors.New("each set can only be changed once per request")
+	ErrAmendReasonRequired      = errors.New("a reason is required to amend a session")
+	ErrRevisionReasonTooLong    = errors.New("reason must be 500 characters or less")
+)
+
+// MaxRevisionReasonLength is the longest reason that can be recorded with a revision.
+const MaxRevisionReasonLength = 500
+
+// RevisionAction is the change a revision records.
+type RevisionAction string
+
+// Revision actions.
+const (
+	RevisionActionUpdate RevisionAction = "UPDATE"
+	RevisionActionDelete RevisionAction = "DELETE"
+)
+
+// RevisionSource is how a revision was made.
+type RevisionSource string
+
+// Revision sources.
+const (
+	// RevisionSourceEdit is a direct edit of a set in an in-progress session.
+	RevisionSourceEdit RevisionSource = "EDIT"
+	// RevisionSourceAmend is a change made through the session amend flow.
+	RevisionSourceAmend RevisionSource = "AMEND"
+)
+
+// SetValidationError is returned when a set update fails domain validation.
+type SetValidationError struct {
+	LoggedSetID string
+	Errors      []error
+}
+
+// Error implements the error interface.
+func (e *SetValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("invalid update for set %s: %s", e.LoggedSetID, strings.Join(msgs, "; "))
+}
+
+// LoggedSetSnapshot is the state of a logged set recorded in a revision.
+type LoggedSetSnapshot struct {
+	SetNumber     int      `json:"setNumber"`
+	Weight        float64  `json:"weight"`
+	TargetReps    int      `json:"targetReps"`
+	RepsPerformed int      `json:"repsPerformed"`
+	IsAMRAP       bool     `json:"isAmrap"`
+	RPE           *float64 `json:"rpe,omitempty"`
+}
+
+// LoggedSetRevision is an audit record of a change to a logged set.
+type LoggedSetRevision struct {
+	ID          string
+	LoggedSetID string
+	SessionID   string
+	UserID      string
+	ChangedBy   string
+	Action      RevisionAction
+	Source      RevisionSource
+	Reason      *string
+	Previous    LoggedSetSnapshot
+	// New is nil when the set was deleted.
+	New       *LoggedSetSnapshot
+	CreatedAt time.Time
+}
+
+// LoggedSetChange is a single update or delete of a logged set.
+type LoggedSetChange struct {
+	LoggedSetID string
+	// Delete removes the set; Update is ignored when set.
+	Delete bool
+	Update loggedset.UpdateLoggedSetInput
+}
+
+// SetEditResult contains the outcome of changing one or more logged sets.
+type SetEditResult struct {
+	SessionID string
+	// Sets are the updated sets, after the change.
+	Sets []loggedset.LoggedSet
+	// DeletedSetIDs are the sets that were removed.
+	DeletedSetIDs []string
+	Revisions     []LoggedSetRevision
+	// RevertedProgressions are the progressions the changed sets had triggered
+	// that no longer apply.
+	RevertedProgressions []RevertResult
+	// ReappliedProgressions are the AFTER_SET progressions evaluated again
+	// against the corrected sets.
+	ReappliedProgressions []TriggerResult
+}
+
+// LoggedSetService corrects and deletes logged sets. Everything derived from a
+// changed set is recomputed in the same transaction: progressions it triggered are
+// reverted, failure counters are replayed, and personal records are rebuilt.
+// AFTER_SET progressions are evaluated again once the correction is committed.
+type LoggedSetService struct {
+	sqlDB              *sql.DB
+	queries            *db.Queries
+	progressionService *ProgressionService
+	prService          *PersonalRecordService
+}
+
+// NewLoggedSetService creates a new LoggedSetService.
+func NewLoggedSetService(sqlDB *sql.DB, progressionService *ProgressionService, prService *PersonalRecordService) *LoggedSetService {
+	return &LoggedSetService{
+		sqlDB:              sqlDB,
+		queries:            db.New(sqlDB),
+		progressionService: progressionService,
+		prService:          prService,
+	}
+}
+
+// UpdateSet corrects a logged set in an in-progress session.
+func (s *LoggedSetService) UpdateSet(ctx context.Context, changedBy, sessionID, setID string, input loggedset.UpdateLoggedSetInput, reason *string) (*SetEditResult, error) {
+	changes := []LoggedSetChange{{LoggedSetID: setID, Update: input}}
+	return s.applyChanges(ctx, changedBy, sessionID, RevisionSourceEdit, changes, reason)
+}
+
+// DeleteSet deletes a logged set from an in-progress session.
+func (s *LoggedSetService) DeleteSet(ctx context.Context, changedBy, sessionID, setID string, reason *string) (*SetEditResult, error) {
+	changes := []LoggedSetChange{{LoggedSetID: setID, Delete: true}}
+	return s.applyChanges(ctx, changedBy, sessionID, RevisionSourceEdit, changes, reason)
+}
+
+// AmendSession applies a batch of set corrections to an in-progress or completed
+// session. All changes are applied atomically and a reason is required.
+func (s *LoggedSetService) AmendSession(ctx context.Context, changedBy, sessionID string, changes []LoggedSetChange, reason string) (*SetEditResult, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, ErrAmendReasonRequired
+	}
+	return s.applyChanges(ctx, changedBy, sessionID, RevisionSourceAmend, changes, &reason)
+}
+
+// ListRevisions returns the revisions recorded for a session's sets, oldest first.
+func (s *LoggedSetService) ListRevisions(ctx context.Context, sessionID string) ([]LoggedSetRevision, error) {
+	rows, err := s.queries.ListLoggedSetRevisionsBySession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list logged set revisions: %w", err)
+	}
+
+	revisions := make([]LoggedSetRevision, 0, len(rows))
+	for _, row := range rows {
+		revision, err := dbLoggedSetRevisionToDomain(row)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+// pendingReapply is an AFTER_SET progression to evaluate again after commit.
+type pendingReapply struct {
+	progressionID string
+	set           loggedset.LoggedSet
+}
+
+func (s *LoggedSetService) applyChanges(
+	ctx context.Context,
+	changedBy string,
+	sessionID string,
+	source RevisionSource,
+	changes []LoggedSetChange,
+	reason *string,
+) (result *SetEditResult, err error) {
+	if len(changes) == 0 {
+		return nil, ErrNoSetChanges
+	}
+	if reason != nil && len(*reason) > MaxRevisionReasonLength {
+		return nil, ErrRevisionReasonTooLong
+	}
+	seen := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		if seen[change.LoggedSetID] {
+			return nil, wrapErrorString(ErrDuplicateSetChange, change.LoggedSetID)
+		}
+		seen[change.LoggedSetID] = true
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	txQueries := s.queries.WithTx(tx)
+
+	session, err := txQueries.GetWorkoutSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWorkoutSessionNotFound
+		}
+		return nil, wrapError("failed to get workout session", err)
+	}
+	switch workoutsession.Status(session.Status) {
+	case workoutsession.StatusAbandoned:
+		return nil, ErrSessionNotEditable
+	case workoutsession.StatusCompleted:
+		if source != RevisionSourceAmend {
+			return nil, ErrSessionRequiresAmendment
+		}
+	}
+
+	result = &SetEditResult{
+		SessionID:             sessionID,
+		Sets:                  []loggedset.LoggedSet{},
+		DeletedSetIDs:         []string{},
+		Revisions:             []LoggedSetRevision{},
+		RevertedProgressions:  []RevertResult{},
+		ReappliedProgressions: []TriggerResult{},
+	}
+
+	var userID string
+	var reapply []pendingReapply
+	changedLifts := make(map[string]bool)
+	now := time.Now()
+
+	for _, change := range changes {
+		row, err := txQueries.GetLoggedSet(ctx, change.LoggedSetID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, wrapErrorString(ErrLoggedSetNotFound, change.LoggedSetID)
+			}
+			return nil, wrapError("failed to get logged set", err)
+		}
+		if row.SessionID != sessionID || (userID != "" && row.UserID != userID) {
+			return nil, wrapErrorString(ErrLoggedSetNotFound, change.LoggedSetID)
+		}
+		userID = row.UserID
+
+		ls := dbLoggedSetRowToDomain(row)
+		previous := snapshotLoggedSet(ls)
+
+		if !change.Delete {
+			if validation := loggedset.UpdateLoggedSet(ls, change.Update); !validation.Valid {
+				return nil, &SetValidationError{LoggedSetID: ls.ID, Errors: validation.Errors}
+			}
+		}
+		changed := change.Delete || performanceChanged(previous, snapshotLoggedSet(ls))
+
+		// Undo the progressions this set triggered that the change invalidates
+		if changed {
+			logs, err := txQueries.ListActiveProgressionLogsByLoggedSet(ctx, db.ListActiveProgressionLogsByLoggedSetParams{
+				UserID:      userID,
+				LoggedSetID: ls.ID,
+			})
+			if err != nil {
+				return nil, wrapError("failed to list progressions triggered by set", err)
+			}
+			for _, logEntry := range logs {
+				triggerType := progression.TriggerType(logEntry.TriggerType)
+				revert := change.Delete || triggerType == progression.TriggerAfterSet ||
+					(triggerType == progression.TriggerOnFailure && !ls.IsFailure())
+				if !revert {
+					continue
+				}
+				reverted, err := s.progressionService.revertProgressionInTx(ctx, txQueries, userID, logEntry.ID)
+				if err != nil {
+					return nil, err
+				}
+				result.RevertedProgressions = append(result.RevertedProgressions, *reverted)
+				if triggerType == progression.TriggerAfterSet && !change.Delete && ls.IsAMRAP {
+					reapply = append(reapply, pendingReapply{progressionID: logEntry.ProgressionID, set: *ls})
+				}
+			}
+			changedLifts[ls.LiftID] = true
+		}
+
+		action := RevisionActionUpdate
+		var newData sql.NullString
+		if change.Delete {
+			action = RevisionActionDelete
+			if err := txQueries.DeleteLoggedSet(ctx, ls.ID); err != nil {
+				return nil, wrapError("failed to delete logged set", err)
+			}
+			result.DeletedSetIDs = append(result.DeletedSetIDs, ls.ID)
+		} else {
+			var rpe sql.NullFloat64
+			if ls.RPE != nil {
+				rpe = sql.NullFloat64{Float64: *ls.RPE, Valid: true}
+			}
+			err := txQueries.UpdateLoggedSet(ctx, db.UpdateLoggedSetParams{
+				SetNumber:     int64(ls.SetNumber),
+				Weight:        ls.Weight,
+				TargetReps:    int64(ls.TargetReps),
+				RepsPerformed: int64(ls.RepsPerformed),
+				IsAmrap:       ls.IsAMRAP,
+				Rpe:           rpe,
+				ID:            ls.ID,
+			})
+			if err != nil {
+				return nil, wrapError("failed to update logged set", err)
+			}
+			newJSON, err := json.Marshal(snapshotLoggedSet(ls))
+			if err != nil {
+				return nil, wrapError("failed to serialize logged set", err)
+			}
+			newData = sql.NullString{String: string(newJSON), Valid: true}
+			result.Sets = append(result.Sets, *ls)
+		}
+
+		previousJSON, err := json.Marshal(previous)
+		if err != nil {
+			return nil, wrapError("failed to serialize logged set", err)
+		}
+		revisionRow := db.LoggedSetRevision{
+			ID:           uuid.New().String(),
+			LoggedSetID:  ls.ID,
+			SessionID:    sessionID,
+			UserID:       userID,
+			ChangedBy:    changedBy,
+			Action:       string(action),
+			Source:       string(source),
+			PreviousData: string(previousJSON),
+			NewData:      newData,
+			CreatedAt:    now.Format(time.RFC3339Nano),
+		}
+		if reason != nil && *reason != "" {
+			revisionRow.Reason = sql.NullString{String: *reason, Valid: true}
+		}
+		if err := txQueries.CreateLoggedSetRevision(ctx, db.CreateLoggedSetRevisionParams(revisionRow)); err != nil {
+			return nil, wrapError("failed to record logged set revision", err)
+		}
+		revision, err := dbLoggedSetRevisionToDomain(revisionRow)
+		if err != nil {
+			return nil, err
+		}
+		result.Revisions = append(result.Revisions, revision)
+	}
+
+	// Recompute everything derived from the changed sets
+	if len(changedLifts) > 0 {
+		reverted, err := s.revertSessionProgressions(ctx, txQueries, userID, sessionID, changedLifts)
+		if err != nil {
+			return nil, err
+		}
+		result.RevertedProgressions = append(result.RevertedProgressions, reverted...)
+		for liftID := range changedLifts {
+			if err := s.recomputeFailureCounters(ctx, txQueries, userID, session.UserProgramStateID, liftID); err != nil {
+				return nil, err
+			}
+		}
+		if s.prService != nil {
+			if err := s.prService.rebuildRecords(ctx, txQueries, userID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Evaluate AFTER_SET progressions again against the corrected sets. Each runs in its
+	// own transaction like any other trigger; failures are reported in the results.
+	for _, p := range reapply {
+		result.ReappliedProgressions = append(result.ReappliedProgressions, s.reapplySetProgression(ctx, p))
+	}
+
+	return result, nil
+}
+
+// revertSessionProgressions reverts the AFTER_SESSION progressions a session applied to
+// the changed lifts the session no longer has any sets of. Session progressions only
+// depend on which lifts were trained, so they stand while the lift has sets left.
+// AFTER_WEEK and AFTER_CYCLE progressions do not depend on individual sets and are
+// never changed by a set correction.
+func (s *LoggedSetService) revertSessionProgressions(ctx context.Context, q *db.Queries, userID, sessionID string, changedLifts map[string]bool) ([]RevertResult, error) {
+	rows, err := q.ListLoggedSetsBySession(ctx, sessionID)
+	if err != nil {
+		return nil, wrapError("failed to list logged sets", err)
+	}
+	trained := make(map[string]bool)
+	for _, row := range rows {
+		trained[row.LiftID] = true
+	}
+
+	reverted := []RevertResult{}
+	for liftID := range changedLifts {
+		if trained[liftID] {
+			continue
+		}
+		logs, err := q.ListActiveSessionProgressionLogsByLift(ctx, db.ListActiveSessionProgressionLogsByLiftParams{
+			UserID:    userID,
+			LiftID:    liftID,
+			SessionID: sessionID,
+		})
+		if err != nil {
+			return nil, wrapError("failed to list progressions applied by session", err)
+		}
+		for _, logEntry := range logs {
+			result, err := s.progressionService.revertProgressionInTx(ctx, q, userID, logEntry.ID)
+			if err != nil {
+				return nil, err
+			}
+			reverted = append(reverted, *result)
+		}
+	}
+	return reverted, nil
+}
+
+// recomputeFailureCounters replays the sets of a lift logged in the sessions of a program
+// enrollment to rebuild the failure counter of every enabled progression of the program
+// that tracks the lift. Only sets logged after the progression last reset the counter are
+// replayed. Sets from earlier enrollments are not replayed; their sessions are removed
+// when the user enrolls in another program.
+func (s *LoggedSetService) recomputeFailureCounters(ctx context.Context, q *db.Queries, userID, programStateID, liftID string) error {
+	enrollment, err := q.GetUserProgramStateByID(ctx, programStateID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// The enrollment has ended, no counters are tracked for it
+			return nil
+		}
+		return wrapError("failed to get user enrollment", err)
+	}
+
+	programProgressions, err := q.ListEnabledProgramProgressionsByProgram(ctx, enrollment.ProgramID)
+	if err != nil {
+		return wrapError("failed to get program progressions", err)
+	}
+
+	rows, err := q.ListLoggedSetsByProgramStateAndLift(ctx, db.ListLoggedSetsByProgramStateAndLiftParams{
+		UserProgramStateID: programStateID,
+		LiftID:             liftID,
+	})
+	if err != nil {
+		return wrapError("failed to list logged sets", err)
+	}
+
+	nowStr := time.Now().Format(time.RFC3339)
+	for _, pp := range programProgressions {
+		if pp.LiftID.Valid && pp.LiftID.String != liftID {
+			continue
+		}
+
+		var since time.Time
+		resetLog, err := q.GetLatestFailureResetLog(ctx, db.GetLatestFailureResetLogParams{
+			UserID:        userID,
+			LiftID:        liftID,
+			ProgressionID: pp.ProgressionID,
+		})
+		if err == nil {
+			since, _ = time.Parse(time.RFC3339Nano, resetLog.AppliedAt)
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return wrapError("failed to get latest failure reset", err)
+		}
+
+		var failures int64
+		var lastFailureAt, lastSuccessAt sql.NullString
+		for _, row := range rows {
+			createdAt, _ := time.Parse(time.RFC3339, row.CreatedAt)
+			if !createdAt.After(since) {
+				continue
+			}
+			if row.RepsPerformed < row.TargetReps {
+				failures++
+				lastFailureAt = sql.NullString{String: row.CreatedAt, Valid: true}
+			} else {
+				failures = 0
+				lastSuccessAt = sql.NullString{String: row.CreatedAt, Valid: true}
+			}
+		}
+
+		counter, err := q.GetFailureCounterByKey(ctx, db.GetFailureCounterByKeyParams{
+			UserID:        userID,
+			LiftID:        liftID,
+			ProgressionID: pp.ProgressionID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			if !lastFailureAt.Valid && !lastSuccessAt.Valid {
+				continue
+			}
+			err = q.CreateFailureCounter(ctx, db.CreateFailureCounterParams{
+				ID:                  uuid.New().String(),
+				UserID:              userID,
+				LiftID:              liftID,
+				ProgressionID:       pp.ProgressionID,
+				ConsecutiveFailures: failures,
+				LastFailureAt:       lastFailureAt,
+				LastSuccessAt:       lastSuccessAt,
+				CreatedAt:           nowStr,
+				UpdatedAt:           nowStr,
+			})
+			if err != nil {
+				return wrapError("failed to create failure counter", err)
+			}
+			continue
+		}
+		if err != nil {
+			return wrapError("failed to get failure counter", err)
+		}
+
+		// Keep the recorded timestamps when no set since the last reset replaces them
+		if !lastFailureAt.Valid {
+			lastFailureAt = counter.LastFailureAt
+		}
+		if !lastSuccessAt.Valid {
+			lastSuccessAt = counter.LastSuccessAt
+		}
+		err = q.UpdateFailureCounter(ctx, db.UpdateFailureCounterParams{
+			ConsecutiveFailures: failures,
+			LastFailureAt:       lastFailureAt,
+			LastSuccessAt:       lastSuccessAt,
+			UpdatedAt:           nowStr,
+			ID:                  counter.ID,
+		})
+		if err != nil {
+			return wrapError("failed to update failure counter", err)
+		}
+	}
+	return nil
+}
+
+// reapplySetProgression applies an AFTER_SET progression again for a corrected set.
+// It bypasses the idempotency check like a forced manual trigger, since the original
+// application was reverted as part of the correction.
+func (s *LoggedSetService) reapplySetProgression(ctx context.Context, p pendingReapply) TriggerResult {
+	failed := func(msg string) TriggerResult {
+		return TriggerResult{ProgressionID: p.progressionID, LiftID: p.set.LiftID, Error: msg}
+	}
+
+	enrollment, err := s.queries.GetUserProgramStateByUserID(ctx, p.set.UserID)
+	if err != nil {
+		return failed(fmt.Sprintf("failed to get user enrollment: %v", err))
+	}
+	programProgressions, err := s.queries.ListEnabledProgramProgressionsByProgramAndProgression(ctx, db.ListEnabledProgramProgressionsByProgramAndProgressionParams{
+		ProgramID:     enrollment.ProgramID,
+		ProgressionID: p.progressionID,
+	})
+	if err != nil {
+		return failed(fmt.Sprintf("failed to get program progressions: %v", err))
+	}
+
+	var pp *db.ProgramProgression
+	for i := range programProgressions {
+		if programProgressions[i].LiftID.String == p.set.LiftID {
+			pp = &programProgressions[i]
+			break
+		}
+	}
+	if pp == nil {
+		return TriggerResult{
+			ProgressionID: p.progressionID,
+			LiftID:        p.set.LiftID,
+			Skipped:       true,
+			SkipReason:    "progression is no longer enabled for this lift",
+		}
+	}
+
+	progressionDef, err := s.queries.GetProgression(ctx, p.progressionID)
+	if err != nil {
+		return failed(fmt.Sprintf("failed to get progression: %v", err))
+	}
+	prog, err := s.progressionService.factory.Create(progression.ProgressionType(progressionDef.Type), json.RawMessage(progressionDef.Parameters))
+	if err != nil {
+		return failed(fmt.Sprintf("failed to parse progression: %v", err))
+	}
+
+	event := progression.NewSetTriggerEvent(p.set.UserID, progression.SetTriggerContext{
+		LoggedSetID:   p.set.ID,
+		SessionID:     p.set.SessionID,
+		LiftID:        p.set.LiftID,
+		Weight:        p.set.Weight,
+		TargetReps:    p.set.TargetReps,
+		RepsPerformed: p.set.RepsPerformed,
+		IsAMRAP:       p.set.IsAMRAP,
+	})
+	return s.progressionService.applyProgressionWithTransactionForce(ctx, event, *pp, prog, nil)
+}
+
+// performanceChanged reports whether a change affects anything derived from the set.
+// Set number and RPE corrections do not.
+func performanceChanged(before, after LoggedSetSnapshot) bool {
+	return before.Weight != after.Weight ||
+		before.TargetReps != after.TargetReps ||
+		before.RepsPerformed != after.RepsPerformed ||
+		before.IsAMRAP != after.IsAMRAP
+}
+
+func snapshotLoggedSet(ls *loggedset.LoggedSet) LoggedSetSnapshot {
+	return LoggedSetSnapshot{
+		SetNumber:     ls.SetNumber,
+		Weight:        ls.Weight,
+		TargetReps:    ls.TargetReps,
+		RepsPerformed: ls.RepsPerformed,
+		IsAMRAP:       ls.IsAMRAP,
+		RPE:           ls.RPE,
+	}
+}
+
+func dbLoggedSetRowToDomain(row db.GetLoggedSetRow) *loggedset.LoggedSet {
+	createdAt, _ := time.Parse(time.RFC3339, row.CreatedAt)
+	ls := &loggedset.LoggedSet{
+		ID:             row.ID,
+		UserID:         row.UserID,
+		SessionID:      row.SessionID,
+		PrescriptionID: row.PrescriptionID,
+		LiftID:         row.LiftID,
+		SetNumber:      int(row.SetNumber),
+		Weight:         row.Weight,
+		TargetReps:     int(row.TargetReps),
+		RepsPerformed:  int(row.RepsPerformed),
+		IsAMRAP:        row.IsAmrap,
+		CreatedAt:      createdAt,
+	}
+	if row.Rpe.Valid {
+		ls.RPE = &row.Rpe.Float64
+	}
+	return ls
+}
+
+func dbLoggedSetRevisionToDomain(row db.LoggedSetRevision) (LoggedSetRevision, error) {
+	createdAt, _ := time.Parse(time.RFC3339Nano, row.CreatedAt)
+	revision := LoggedSetRevision{
+		ID:          row.ID,
+		LoggedSetID: row.LoggedSetID,
+		SessionID:   row.SessionID,
+		UserID:      row.UserID,
+		ChangedBy:   row.ChangedBy,
+		Action:      RevisionAction(row.Action),
+		Source:      RevisionSource(row.Source),
+		Reason:      nullStringToPtr(row.Reason),
+		CreatedAt:   createdAt,
+	}
+	if err := json.Unmarshal([]byte(row.PreviousData), &revision.Previous); err != nil {
+		return LoggedSetRevision{}, wrapError("failed to parse logged set revision", err)
+	}
+	if row.NewData.Valid {
+		var snapshot LoggedSetSnapshot
+		if err := json.Unmarshal([]byte(row.NewData.String), &snapshot); err != nil {
+			return LoggedSetRevision{}, wrapError("failed to parse logged set revision", err)
+		}
+		revision.New = &snapshot
+	}
+	return revision, nil
+}
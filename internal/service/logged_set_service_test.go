@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/loggedset"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/progression"
+)
+
+// createTestWorkoutSession creates a workout session for the test user's enrollment.
+func createTestWorkoutSession(t *testing.T, sqlDB *sql.DB, data testData, status string) string {
+	t.Helper()
+	var stateID string
+	if err := sqlDB.QueryRow("SELECT id FROM user_program_states WHERE user_id = ?", data.UserID).Scan(&stateID); err != nil {
+		t.Fatalf("failed to find enrollment: %v", err)
+	}
+	sessionID := uuid.New().String()
+	_, err := sqlDB.Exec(
+		"INSERT INTO workout_sessions (id, user_program_state_id, week_number, day_index, status) VALUES (?, ?, 1, 0, ?)",
+		sessionID, stateID, status,
+	)
+	if err != nil {
+		t.Fatalf("failed to create workout session: %v", err)
+	}
+	return sessionID
+}
+
+// logTestSet logs a squat set through the personal record service, as the API does.
+func logTestSet(t *testing.T, prService *PersonalRecordService, data testData, sessionID string, reps int, amrap bool, at time.Time) *loggedset.LoggedSet {
+	t.Helper()
+	ls, result := loggedset.NewLoggedSet(loggedset.CreateLoggedSetInput{
+		UserID:         data.UserID,
+		SessionID:      sessionID,
+		PrescriptionID: uuid.New().String(),
+		LiftID:         data.SquatID,
+		SetNumber:      1,
+		Weight:         200,
+		TargetReps:     5,
+		RepsPerformed:  reps,
+		IsAMRAP:        amrap,
+	}, uuid.New().String())
+	if !result.Valid {
+		t.Fatalf("invalid logged set: %v", result.Errors)
+	}
+	ls.CreatedAt = at
+	if _, err := prService.LogSetWithRecords(context.Background(), ls); err != nil {
+		t.Fatalf("failed to log set: %v", err)
+	}
+	return ls
+}
+
+// setupAMRAPProgression adds an AFTER_SET AMRAP progression for squat to the test program.
+func setupAMRAPProgression(t *testing.T, queries *db.Queries, data testData) string {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now().Format(time.RFC3339)
+	progressionID := uuid.New().String()
+	err := queries.CreateProgression(ctx, db.CreateProgressionParams{
+		ID:   progressionID,
+		Name: "AMRAP",
+		Type: string(progression.TypeAMRAP),
+		Parameters: `{
+			"id": "` + progressionID + `",
+			"name": "AMRAP",
+			"maxType": "TRAINING_MAX",
+			"triggerType": "AFTER_SET",
+			"thresholds": [{"minReps": 1, "increment": 5}, {"minReps": 10, "increment": 10}]
+		}`,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("failed to create AMRAP progression: %v", err)
+	}
+	err = queries.CreateProgramProgression(ctx, db.CreateProgramProgressionParams{
+		ID:            uuid.New().String(),
+		ProgramID:     data.ProgramID,
+		ProgressionID: progressionID,
+		LiftID:        sql.NullString{String: data.SquatID, Valid: true},
+		Priority:      10,
+		Enabled:       1,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		t.Fatalf("failed to create program progression: %v", err)
+	}
+	return progressionID
+}
+
+// applyAMRAPForSet applies the AMRAP progression for a set, as an AFTER_SET trigger would.
+func applyAMRAPForSet(t *testing.T, service *ProgressionService, queries *db.Queries, data testData, progressionID string, ls *loggedset.LoggedSet) {
+	t.Helper()
+	ctx := context.Background()
+	pps, err := queries.ListEnabledProgramProgressionsByProgramAndProgression(ctx, db.ListEnabledProgramProgressionsByProgramAndProgressionParams{
+		ProgramID:     data.ProgramID,
+		ProgressionID: progressionID,
+	})
+	if err != nil || len(pps) != 1 {
+		t.Fatalf("failed to get program progression: %v", err)
+	}
+	prog, err := service.factory.Create(progression.TypeAMRAP, []byte(mustGetProgressionParams(t, queries, progressionID)))
+	if err != nil {
+		t.Fatalf("failed to parse progression: %v", err)
+	}
+	event := progression.NewSetTriggerEvent(data.UserID, progression.SetTriggerContext{
+		LoggedSetID:   ls.ID,
+		SessionID:     ls.SessionID,
+		LiftID:        ls.LiftID,
+		Weight:        ls.Weight,
+		TargetReps:    ls.TargetReps,
+		RepsPerformed: ls.RepsPerformed,
+		IsAMRAP:       ls.IsAMRAP,
+	})
+	result := service.applyProgressionWithTransaction(ctx, event, pps[0], prog, nil)
+	if !result.Applied {
+		t.Fatalf("expected AMRAP progression to apply, got %+v", result)
+	}
+}
+
+func mustGetProgressionParams(t *testing.T, queries *db.Queries, progressionID string) string {
+	t.Helper()
+	def, err := queries.GetProgression(context.Background(), progressionID)
+	if err != nil {
+		t.Fatalf("failed to get progression: %v", err)
+	}
+	return def.Parameters
+}
+
+// TestLoggedSetService_UpdateSetRecomputesFailureCounter tests that correcting a failed
+// set replays the failure counter.
+func TestLoggedSetService_UpdateSetRecomputesFailureCounter(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	ctx := context.Background()
+	prService := NewPersonalRecordService(sqlDB)
+	failureService := NewFailureService(sqlDB, GetDefaultFactory())
+	service := NewLoggedSetService(sqlDB, NewProgressionService(sqlDB, GetDefaultFactory()), prService)
+	sessionID := createTestWorkoutSession(t, sqlDB, data, "IN_PROGRESS")
+
+	first := logTestSet(t, prService, data, sessionID, 3, false, time.Now().Add(-2*time.Minute))
+	second := logTestSet(t, prService, data, sessionID, 4, false, time.Now().Add(-time.Minute))
+	for _, ls := range []*loggedset.LoggedSet{first, second} {
+		if _, err := failureService.ProcessLoggedSet(ctx, ls); err != nil {
+			t.Fatalf("failed to process logged set: %v", err)
+		}
+	}
+	if count, _ := failureService.GetFailureCount(data.UserID, data.SquatID, data.LinearSessionProgressionID); count != 2 {
+		t.Fatalf("expected 2 failures before correction, got %d", count)
+	}
+
+	reps := 5
+	reason := "miscounted"
+	result, err := service.UpdateSet(ctx, data.UserID, sessionID, first.ID, loggedset.UpdateLoggedSetInput{RepsPerformed: &reps}, &reason)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Sets) != 1 || result.Sets[0].RepsPerformed != 5 {
+		t.Errorf("expected corrected set with 5 reps, got %+v", result.Sets)
+	}
+	if count, _ := failureService.GetFailureCount(data.UserID, data.SquatID, data.LinearSessionProgressionID); count != 1 {
+		t.Errorf("expected 1 failure after correcting the first set, got %d", count)
+	}
+
+	revisions, err := service.ListRevisions(ctx, sessionID)
+	if err != nil {
+		t.Fatalf("failed to list revisions: %v", err)
+	}
+	if len(revisions) != 1 {
+		t.Fatalf("expected 1 revision, got %d", len(revisions))
+	}
+	rev := revisions[0]
+	if rev.Action != RevisionActionUpdate || rev.Source != RevisionSourceEdit || rev.Previous.RepsPerformed != 3 ||
+		rev.New == nil || rev.New.RepsPerformed != 5 || rev.Reason == nil || *rev.Reason != reason {
+		t.Errorf("unexpected revision: %+v", rev)
+	}
+
+	t.Run("delete replays remaining sets", func(t *testing.T) {
+		if _, err := service.DeleteSet(ctx, data.UserID, sessionID, second.ID, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if count, _ := failureService.GetFailureCount(data.UserID, data.SquatID, data.LinearSessionProgressionID); count != 0 {
+			t.Errorf("expected no failures after deleting the failed set, got %d", count)
+		}
+	})
+
+	t.Run("invalid update", func(t *testing.T) {
+		bad := -1
+		_, err := service.UpdateSet(ctx, data.UserID, sessionID, first.ID, loggedset.UpdateLoggedSetInput{RepsPerformed: &bad}, nil)
+		var validationErr *SetValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("expected SetValidationError, got %v", err)
+		}
+	})
+
+	t.Run("set in another session", func(t *testing.T) {
+		_, err := service.DeleteSet(ctx, data.UserID, createTestWorkoutSession(t, sqlDB, data, "IN_PROGRESS"), first.ID, nil)
+		if !errors.Is(err, ErrLoggedSetNotFound) {
+			t.Errorf("expected ErrLoggedSetNotFound, got %v", err)
+		}
+	})
+}
+
+// TestLoggedSetService_UpdateSetReevaluatesAMRAP tests that correcting an AMRAP set
+// reverts the progression it triggered and evaluates it again with the corrected reps.
+func TestLoggedSetService_UpdateSetReevaluatesAMRAP(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	queries := db.New(sqlDB)
+	ctx := context.Background()
+	prService := NewPersonalRecordService(sqlDB)
+	progressionService := NewProgressionService(sqlDB, GetDefaultFactory())
+	service := NewLoggedSetService(sqlDB, progressionService, prService)
+	amrapID := setupAMRAPProgression(t, queries, data)
+	sessionID := createTestWorkoutSession(t, sqlDB, data, "IN_PROGRESS")
+
+	ls := logTestSet(t, prService, data, sessionID, 50, true, time.Now().Add(-time.Minute))
+	applyAMRAPForSet(t, progressionService, queries, data, amrapID, ls)
+	if got := currentSquatMax(t, queries, data); got != 310 {
+		t.Fatalf("expected squat max 310 after 50 rep AMRAP, got %f", got)
+	}
+
+	reps := 5
+	result, err := service.UpdateSet(ctx, data.UserID, sessionID, ls.ID, loggedset.UpdateLoggedSetInput{RepsPerformed: &reps}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.RevertedProgressions) != 1 || result.RevertedProgressions[0].RestoredValue != 300 {
+		t.Errorf("expected the AMRAP progression to be reverted to 300, got %+v", result.RevertedProgressions)
+	}
+	if len(result.ReappliedProgressions) != 1 || !result.ReappliedProgressions[0].Applied {
+		t.Fatalf("expected the AMRAP progression to be applied again, got %+v", result.ReappliedProgressions)
+	}
+	if got := currentSquatMax(t, queries, data); got != 305 {
+		t.Errorf("expected squat max 305 after correcting to 5 reps, got %f", got)
+	}
+
+	// The E1RM record is rebuilt from the corrected set
+	best, err := queries.GetBestE1RMRecord(ctx, db.GetBestE1RMRecordParams{
+		UserID: data.UserID,
+		LiftID: sql.NullString{String: data.SquatID, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("failed to get E1RM record: %v", err)
+	}
+	if best.LoggedSetID.String != ls.ID || best.Reps.Int64 != 5 {
+		t.Errorf("expected E1RM record rebuilt from the corrected set, got %+v", best)
+	}
+
+	t.Run("delete reverts without reapplying", func(t *testing.T) {
+		result, err := service.DeleteSet(ctx, data.UserID, sessionID, ls.ID, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.RevertedProgressions) != 1 || len(result.ReappliedProgressions) != 0 {
+			t.Errorf("expected one revert and no reapplication, got %+v", result)
+		}
+		if got := currentSquatMax(t, queries, data); got != 300 {
+			t.Errorf("expected squat max restored to 300, got %f", got)
+		}
+		var records int
+		if err := sqlDB.QueryRow("SELECT COUNT(*) FROM personal_records WHERE user_id = ?", data.UserID).Scan(&records); err != nil {
+			t.Fatalf("failed to count records: %v", err)
+		}
+		if records != 0 {
+			t.Errorf("expected no personal records after deleting the only set, got %d", records)
+		}
+	})
+}
+
+// TestLoggedSetService_SessionStatus tests which sessions allow edits and amendments.
+func TestLoggedSetService_SessionStatus(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	ctx := context.Background()
+	prService := NewPersonalRecordService(sqlDB)
+	service := NewLoggedSetService(sqlDB, NewProgressionService(sqlDB, GetDefaultFactory()), prService)
+	reps := 5
+
+	completedID := createTestWorkoutSession(t, sqlDB, data, "COMPLETED")
+	completedSet := logTestSet(t, prService, data, completedID, 3, false, time.Now().Add(-time.Minute))
+
+	_, err := service.UpdateSet(ctx, data.UserID, completedID, completedSet.ID, loggedset.UpdateLoggedSetInput{RepsPerformed: &reps}, nil)
+	if !errors.Is(err, ErrSessionRequiresAmendment) {
+		t.Errorf("expected ErrSessionRequiresAmendment, got %v", err)
+	}
+
+	changes := []LoggedSetChange{{LoggedSetID: completedSet.ID, Update: loggedset.UpdateLoggedSetInput{RepsPerformed: &reps}}}
+	if _, err := service.AmendSession(ctx, data.UserID, completedID, changes, ""); !errors.Is(err, ErrAmendReasonRequired) {
+		t.Errorf("expected ErrAmendReasonRequired, got %v", err)
+	}
+	result, err := service.AmendSession(ctx, data.UserID, completedID, changes, "logged the wrong reps")
+	if err != nil {
+		t.Fatalf("unexpected error amending completed session: %v", err)
+	}
+	if len(result.Revisions) != 1 || result.Revisions[0].Source != RevisionSourceAmend {
+		t.Errorf("expected one AMEND revision, got %+v", result.Revisions)
+	}
+
+	abandonedID := createTestWorkoutSession(t, sqlDB, data, "ABANDONED")
+	abandonedSet := logTestSet(t, prService, data, abandonedID, 3, false, time.Now())
+	changes = []LoggedSetChange{{LoggedSetID: abandonedSet.ID, Delete: true}}
+	if _, err := service.AmendSession(ctx, data.UserID, abandonedID, changes, "cleanup"); !errors.Is(err, ErrSessionNotEditable) {
+		t.Errorf("expected ErrSessionNotEditable, got %v", err)
+	}
+}
+
+// TestLoggedSetService_RecomputeScopedToEnrollment tests that failure counters are only
+// replayed from the sets logged in the sessions of the current enrollment.
+func TestLoggedSetService_RecomputeScopedToEnrollment(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	ctx := context.Background()
+	prService := NewPersonalRecordService(sqlDB)
+	failureService := NewFailureService(sqlDB, GetDefaultFactory())
+	service := NewLoggedSetService(sqlDB, NewProgressionService(sqlDB, GetDefaultFactory()), prService)
+
+	// A failed set left over from an earlier enrollment, whose sessions no longer exist
+	logTestSet(t, prService, data, uuid.New().String(), 2, false, time.Now().Add(-time.Hour))
+
+	sessionID := createTestWorkoutSession(t, sqlDB, data, "IN_PROGRESS")
+	first := logTestSet(t, prService, data, sessionID, 3, false, time.Now().Add(-2*time.Minute))
+	second := logTestSet(t, prService, data, sessionID, 4, false, time.Now().Add(-time.Minute))
+	for _, ls := range []*loggedset.LoggedSet{first, second} {
+		if _, err := failureService.ProcessLoggedSet(ctx, ls); err != nil {
+			t.Fatalf("failed to process logged set: %v", err)
+		}
+	}
+
+	reps := 2
+	if _, err := service.UpdateSet(ctx, data.UserID, sessionID, second.ID, loggedset.UpdateLoggedSetInput{RepsPerformed: &reps}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count, _ := failureService.GetFailureCount(data.UserID, data.SquatID, data.LinearSessionProgressionID); count != 2 {
+		t.Errorf("expected 2 failures from the current enrollment, got %d", count)
+	}
+}
+
+// TestLoggedSetService_RecomputeSinceFailureReset tests that failure counters are only
+// replayed from the sets logged after a progression last reset the counter.
+func TestLoggedSetService_RecomputeSinceFailureReset(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	queries := db.New(sqlDB)
+	ctx := context.Background()
+	prService := NewPersonalRecordService(sqlDB)
+	failureService := NewFailureService(sqlDB, GetDefaultFactory())
+	progressionService := NewProgressionService(sqlDB, GetDefaultFactory())
+	service := NewLoggedSetService(sqlDB, progressionService, prService)
+	now := time.Now().Format(time.RFC3339)
+
+	deloadID := uuid.New().String()
+	err := queries.CreateProgression(ctx, db.CreateProgressionParams{
+		ID:   deloadID,
+		Name: "Deload",
+		Type: string(progression.TypeDeloadOnFailure),
+		Parameters: `{
+			"id": "` + deloadID + `",
+			"name": "Deload",
+			"failureThreshold": 2,
+			"deloadType": "fixed",
+			"deloadAmount": 10,
+			"resetOnDeload": true,
+			"maxType": "TRAINING_MAX"
+		}`,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("failed to create deload progression: %v", err)
+	}
+	err = queries.CreateProgramProgression(ctx, db.CreateProgramProgressionParams{
+		ID:            uuid.New().String(),
+		ProgramID:     data.ProgramID,
+		ProgressionID: deloadID,
+		LiftID:        sql.NullString{String: data.SquatID, Valid: true},
+		Priority:      10,
+		Enabled:       1,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		t.Fatalf("failed to create program progression: %v", err)
+	}
+
+	sessionID := createTestWorkoutSession(t, sqlDB, data, "IN_PROGRESS")
+	for _, at := range []time.Time{time.Now().Add(-3 * time.Minute), time.Now().Add(-2 * time.Minute)} {
+		ls := logTestSet(t, prService, data, sessionID, 3, false, at)
+		if _, err := failureService.ProcessLoggedSet(ctx, ls); err != nil {
+			t.Fatalf("failed to process logged set: %v", err)
+		}
+	}
+
+	applied, err := progressionService.ApplyProgressionManually(ctx, data.UserID, deloadID, data.SquatID, false)
+	if err != nil || applied.TotalApplied != 1 {
+		t.Fatalf("expected deload to apply, got %+v, %v", applied, err)
+	}
+	if count, _ := failureService.GetFailureCount(data.UserID, data.SquatID, deloadID); count != 0 {
+		t.Fatalf("expected the deload to reset the failure counter, got %d", count)
+	}
+
+	last := logTestSet(t, prService, data, sessionID, 3, false, time.Now().Add(time.Minute))
+	if _, err := failureService.ProcessLoggedSet(ctx, last); err != nil {
+		t.Fatalf("failed to process logged set: %v", err)
+	}
+
+	reps := 2
+	if _, err := service.UpdateSet(ctx, data.UserID, sessionID, last.ID, loggedset.UpdateLoggedSetInput{RepsPerformed: &reps}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count, _ := failureService.GetFailureCount(data.UserID, data.SquatID, deloadID); count != 1 {
+		t.Errorf("expected 1 failure since the deload reset the counter, got %d", count)
+	}
+}
+
+// TestLoggedSetService_AmendRevertsSessionProgression tests that amending a completed
+// session reverts its AFTER_SESSION progression for a lift once no sets of the lift remain.
+func TestLoggedSetService_AmendRevertsSessionProgression(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	queries := db.New(sqlDB)
+	ctx := context.Background()
+	prService := NewPersonalRecordService(sqlDB)
+	progressionService := NewProgressionService(sqlDB, GetDefaultFactory())
+	service := NewLoggedSetService(sqlDB, progressionService, prService)
+
+	sessionID := createTestWorkoutSession(t, sqlDB, data, "COMPLETED")
+	first := logTestSet(t, prService, data, sessionID, 5, false, time.Now().Add(-2*time.Minute))
+	second := logTestSet(t, prService, data, sessionID, 5, false, time.Now().Add(-time.Minute))
+
+	event := progression.NewSessionTriggerEvent(data.UserID, sessionID, "day-a", 1, []string{data.SquatID})
+	applied, err := progressionService.HandleSessionComplete(ctx, event)
+	if err != nil || applied.TotalApplied != 1 {
+		t.Fatalf("expected the session progression to apply, got %+v (%v)", applied, err)
+	}
+	if got := currentSquatMax(t, queries, data); got != 305 {
+		t.Fatalf("expected squat max 305 after the session, got %f", got)
+	}
+
+	// Squat is still trained in the session, so the progression stands
+	result, err := service.AmendSession(ctx, data.UserID, sessionID, []LoggedSetChange{{LoggedSetID: first.ID, Delete: true}}, "duplicate set")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.RevertedProgressions) != 0 || currentSquatMax(t, queries, data) != 305 {
+		t.Errorf("expected no revert while squat sets remain, got %+v", result.RevertedProgressions)
+	}
+
+	result, err = service.AmendSession(ctx, data.UserID, sessionID, []LoggedSetChange{{LoggedSetID: second.ID, Delete: true}}, "squat was not trained")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.RevertedProgressions) != 1 || result.RevertedProgressions[0].RestoredValue != 300 {
+		t.Errorf("expected the session progression to be reverted to 300, got %+v", result.RevertedProgressions)
+	}
+	if got := currentSquatMax(t, queries, data); got != 300 {
+		t.Errorf("expected squat max restored to 300, got %f", got)
+	}
+}
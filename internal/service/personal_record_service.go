@@ -0,0 +1,467 @@
+// Package service provides application service layer implementations.
+// This file implements the PersonalRecordService which detects personal records as sets are logged.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/loggedset"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/personalrecord"
+)
+
+// PersonalRecordService persists logged sets and detects the personal records they set.
+// Set creation and record detection share a transaction so records never reference
+// a set that failed to save.
+type PersonalRecordService struct {
+	sqlDB     *sql.DB
+	queries   *db.Queries
+	estimator *personalrecord.Estimator
+}
+
+// NewPersonalRecordService creates a new PersonalRecordService.
+func NewPersonalRecordService(sqlDB *sql.DB) *PersonalRecordService {
+	return &PersonalRecordService{
+		sqlDB:     sqlDB,
+		queries:   db.New(sqlDB),
+		estimator: personalrecord.NewEstimator(),
+	}
+}
+
+// PersonalRecord is a record a user set with a logged set.
+type PersonalRecord struct {
+	ID         string
+	UserID     string
+	LiftID     *string
+	RecordType personalrecord.RecordType
+	// RepCount is the rep-max bucket (1-12, where 12 means 12+). Only set for REP_MAX records.
+	RepCount *int
+	// Value is the weight (REP_MAX), estimated 1RM (E1RM), weight x reps (SESSION_VOLUME),
+	// or sum of competition lift estimated 1RMs (COMPETITION_TOTAL).
+	Value         float64
+	Weight        *float64
+	Reps          *int
+	PreviousValue *float64
+	LoggedSetID   *string
+	SessionID     *string
+	AchievedAt    time.Time
+	CreatedAt     time.Time
+}
+
+// CurrentPersonalRecord is the best record for a lift, record type, and rep count.
+type CurrentPersonalRecord struct {
+	PersonalRecord
+	LiftName *string
+	LiftSlug *string
+}
+
+// LogSetWithRecords creates the logged set and records any personal records it sets,
+// in a single transaction. Returns the records achieved by the set (empty if none).
+//
+// A set can achieve several records at once: a rep max for its rep count, an
+// estimated 1RM, the session volume for its lift, and, for competition lifts,
+// the competition total. A session volume record is only reported the first
+// time the session passes the previous best; later sets in the same session
+// raise the record silently.
+func (s *PersonalRecordService) LogSetWithRecords(ctx context.Context, ls *loggedset.LoggedSet) (records []PersonalRecord, err error) {
+	if ls == nil {
+		return nil, ErrLoggedSetRequired
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	records, err = s.LogSetWithRecordsTx(ctx, tx, ls)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return records, nil
+}
+
+// LogSetWithRecordsTx is LogSetWithRecords within the caller's transaction.
+// The caller commits or rolls back tx.
+func (s *PersonalRecordService) LogSetWithRecordsTx(ctx context.Context, tx *sql.Tx, ls *loggedset.LoggedSet) ([]PersonalRecord, error) {
+	if ls == nil {
+		return nil, ErrLoggedSetRequired
+	}
+
+	txQueries := s.queries.WithTx(tx)
+
+	var rpe sql.NullFloat64
+	if ls.RPE != nil {
+		rpe = sql.NullFloat64{Float64: *ls.RPE, Valid: true}
+	}
+	err := txQueries.CreateLoggedSet(ctx, db.CreateLoggedSetParams{
+		ID:             ls.ID,
+		UserID:         ls.UserID,
+		SessionID:      ls.SessionID,
+		PrescriptionID: ls.PrescriptionID,
+		LiftID:         ls.LiftID,
+		SetNumber:      int64(ls.SetNumber),
+		Weight:         ls.Weight,
+		TargetReps:     int64(ls.TargetReps),
+		RepsPerformed:  int64(ls.RepsPerformed),
+		IsAmrap:        ls.IsAMRAP,
+		Rpe:            rpe,
+		CreatedAt:      ls.CreatedAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logged set: %w", err)
+	}
+
+	return s.detectRecords(ctx, txQueries, ls)
+}
+
+// ListCurrentRecords returns the user's current best record for every lift, record type,
+// and rep count, ordered by record type, lift name, and rep count.
+func (s *PersonalRecordService) ListCurrentRecords(ctx context.Context, userID string) ([]CurrentPersonalRecord, error) {
+	rows, err := s.queries.ListCurrentPersonalRecordsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list personal records: %w", err)
+	}
+
+	records := make([]CurrentPersonalRecord, len(rows))
+	for i, row := range rows {
+		records[i] = CurrentPersonalRecord{
+			PersonalRecord: dbPersonalRecordToDomain(db.PersonalRecord{
+				ID:            row.ID,
+				UserID:        row.UserID,
+				LiftID:        row.LiftID,
+				RecordType:    row.RecordType,
+				RepCount:      row.RepCount,
+				Value:         row.Value,
+				Weight:        row.Weight,
+				Reps:          row.Reps,
+				PreviousValue: row.PreviousValue,
+				LoggedSetID:   row.LoggedSetID,
+				SessionID:     row.SessionID,
+				AchievedAt:    row.AchievedAt,
+				CreatedAt:     row.CreatedAt,
+			}),
+			LiftName: nullStringToPtr(row.LiftName),
+			LiftSlug: nullStringToPtr(row.LiftSlug),
+		}
+	}
+	return records, nil
+}
+
+// detectRecords checks a newly created set against the user's existing records.
+func (s *PersonalRecordService) detectRecords(ctx context.Context, q *db.Queries, ls *loggedset.LoggedSet) ([]PersonalRecord, error) {
+	records := []PersonalRecord{}
+	liftID := sql.NullString{String: ls.LiftID, Valid: true}
+
+	// Rep max for this rep count
+	if bucket := personalrecord.RepBucket(ls.RepsPerformed); bucket > 0 && ls.Weight > 0 {
+		best, err := q.GetBestRepMaxRecord(ctx, db.GetBestRepMaxRecordParams{
+			UserID:   ls.UserID,
+			LiftID:   liftID,
+			RepCount: sql.NullInt64{Int64: int64(bucket), Valid: true},
+		})
+		previous, err := bestValue(best, err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rep max record: %w", err)
+		}
+		if personalrecord.IsImprovement(ls.Weight, previous) {
+			record, err := s.createRecord(ctx, q, ls, personalrecord.TypeRepMax, &bucket, ls.Weight, previous)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, *record)
+		}
+	}
+
+	// Estimated 1RM
+	e1rmRecord := false
+	if estimate := s.estimator.EstimateOneRM(ls.Weight, ls.RepsPerformed, ls.RPE); estimate > 0 {
+		best, err := q.GetBestE1RMRecord(ctx, db.GetBestE1RMRecordParams{
+			UserID: ls.UserID,
+			LiftID: liftID,
+		})
+		previous, err := bestValue(best, err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get E1RM record: %w", err)
+		}
+		if personalrecord.IsImprovement(estimate, previous) {
+			record, err := s.createRecord(ctx, q, ls, personalrecord.TypeE1RM, nil, estimate, previous)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, *record)
+			e1rmRecord = true
+		}
+	}
+
+	// Session volume
+	volumeRecord, err := s.detectSessionVolumeRecord(ctx, q, ls)
+	if err != nil {
+		return nil, err
+	}
+	if volumeRecord != nil {
+		records = append(records, *volumeRecord)
+	}
+
+	// Competition total, only when a competition lift's estimated 1RM improved
+	if e1rmRecord {
+		totalRecord, err := s.detectCompetitionTotalRecord(ctx, q, ls)
+		if err != nil {
+			return nil, err
+		}
+		if totalRecord != nil {
+			records = append(records, *totalRecord)
+		}
+	}
+
+	return records, nil
+}
+
+// detectSessionVolumeRecord compares the session's volume for the set's lift against
+// the best volume from other sessions.
+func (s *PersonalRecordService) detectSessionVolumeRecord(ctx context.Context, q *db.Queries, ls *loggedset.LoggedSet) (*PersonalRecord, error) {
+	volume, err := q.GetSessionLiftVolume(ctx, db.GetSessionLiftVolumeParams{
+		SessionID: ls.SessionID,
+		LiftID:    ls.LiftID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session volume: %w", err)
+	}
+	volume = personalrecord.Round(volume)
+	if volume <= 0 {
+		return nil, nil
+	}
+
+	liftID := sql.NullString{String: ls.LiftID, Valid: true}
+	sessionID := sql.NullString{String: ls.SessionID, Valid: true}
+
+	// This session already holds a volume record: raise it without reporting a new PR
+	existing, err := q.GetSessionVolumeRecord(ctx, db.GetSessionVolumeRecordParams{
+		SessionID: sessionID,
+		LiftID:    liftID,
+	})
+	if err == nil {
+		if volume > existing.Value {
+			err = q.UpdatePersonalRecordValue(ctx, db.UpdatePersonalRecordValueParams{
+				Value:       volume,
+				Weight:      sql.NullFloat64{Float64: ls.Weight, Valid: true},
+				Reps:        sql.NullInt64{Int64: int64(ls.RepsPerformed), Valid: true},
+				LoggedSetID: sql.NullString{String: ls.ID, Valid: true},
+				AchievedAt:  ls.CreatedAt.Format(time.RFC3339),
+				ID:          existing.ID,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to update session volume record: %w", err)
+			}
+		}
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get session volume record: %w", err)
+	}
+
+	best, err := q.GetBestSessionVolumeRecordExcludingSession(ctx, db.GetBestSessionVolumeRecordExcludingSessionParams{
+		UserID:    ls.UserID,
+		LiftID:    liftID,
+		SessionID: sessionID,
+	})
+	previous, err := bestValue(best, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session volume record: %w", err)
+	}
+	if !personalrecord.IsImprovement(volume, previous) {
+		return nil, nil
+	}
+	return s.createRecord(ctx, q, ls, personalrecord.TypeSessionVolume, nil, volume, previous)
+}
+
+// detectCompetitionTotalRecord sums the best estimated 1RM of every competition lift.
+// No total is recorded until each competition lift has an estimated 1RM.
+func (s *PersonalRecordService) detectCompetitionTotalRecord(ctx context.Context, q *db.Queries, ls *loggedset.LoggedSet) (*PersonalRecord, error) {
+	bests, err := q.ListCompetitionLiftBestE1RMs(ctx, ls.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list competition lift records: %w", err)
+	}
+
+	isCompetitionLift := false
+	var total float64
+	for _, b := range bests {
+		if b.BestValue <= 0 {
+			return nil, nil
+		}
+		if b.LiftID == ls.LiftID {
+			isCompetitionLift = true
+		}
+		total += b.BestValue
+	}
+	if !isCompetitionLift {
+		return nil, nil
+	}
+	total = personalrecord.Round(total)
+
+	best, err := q.GetBestCompetitionTotalRecord(ctx, ls.UserID)
+	previous, err := bestValue(best, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get competition total record: %w", err)
+	}
+	if !personalrecord.IsImprovement(total, previous) {
+		return nil, nil
+	}
+	return s.createRecord(ctx, q, ls, personalrecord.TypeCompetitionTotal, nil, total, previous)
+}
+
+// createRecord persists a record achieved by the given set.
+func (s *PersonalRecordService) createRecord(
+	ctx context.Context,
+	q *db.Queries,
+	ls *loggedset.LoggedSet,
+	recordType personalrecord.RecordType,
+	repCount *int,
+	value float64,
+	previous *float64,
+) (*PersonalRecord, error) {
+	now := time.Now()
+	record := db.PersonalRecord{
+		ID:          uuid.New().String(),
+		UserID:      ls.UserID,
+		RecordType:  string(recordType),
+		Value:       value,
+		Weight:      sql.NullFloat64{Float64: ls.Weight, Valid: true},
+		Reps:        sql.NullInt64{Int64: int64(ls.RepsPerformed), Valid: true},
+		LoggedSetID: sql.NullString{String: ls.ID, Valid: true},
+		SessionID:   sql.NullString{String: ls.SessionID, Valid: true},
+		AchievedAt:  ls.CreatedAt.Format(time.RFC3339),
+		CreatedAt:   now.Format(time.RFC3339),
+	}
+	if recordType != personalrecord.TypeCompetitionTotal {
+		record.LiftID = sql.NullString{String: ls.LiftID, Valid: true}
+	}
+	if repCount != nil {
+		record.RepCount = sql.NullInt64{Int64: int64(*repCount), Valid: true}
+	}
+	if previous != nil {
+		record.PreviousValue = sql.NullFloat64{Float64: *previous, Valid: true}
+	}
+
+	err := q.CreatePersonalRecord(ctx, db.CreatePersonalRecordParams(record))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s record: %w", recordType, err)
+	}
+
+	result := dbPersonalRecordToDomain(record)
+	return &result, nil
+}
+
+// bestValue extracts the value of a best-record lookup, treating no rows as no record.
+func bestValue(record db.PersonalRecord, err error) (*float64, error) {
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record.Value, nil
+}
+
+func dbPersonalRecordToDomain(r db.PersonalRecord) PersonalRecord {
+	achievedAt, _ := time.Parse(time.RFC3339, r.AchievedAt)
+	createdAt, _ := time.Parse(time.RFC3339, r.CreatedAt)
+
+	record := PersonalRecord{
+		ID:          r.ID,
+		UserID:      r.UserID,
+		LiftID:      nullStringToPtr(r.LiftID),
+		RecordType:  personalrecord.RecordType(r.RecordType),
+		Value:       r.Value,
+		LoggedSetID: nullStringToPtr(r.LoggedSetID),
+		SessionID:   nullStringToPtr(r.SessionID),
+		AchievedAt:  achievedAt,
+		CreatedAt:   createdAt,
+	}
+	if r.RepCount.Valid {
+		repCount := int(r.RepCount.Int64)
+		record.RepCount = &repCount
+	}
+	if r.Weight.Valid {
+		record.Weight = &r.Weight.Float64
+	}
+	if r.Reps.Valid {
+		reps := int(r.Reps.Int64)
+		record.Reps = &reps
+	}
+	if r.PreviousValue.Valid {
+		record.PreviousValue = &r.PreviousValue.Float64
+	}
+	return record
+}
+
+func nullStringToPtr(ns sql.NullString) *string {
+	if ns.Valid {
+		return &ns.String
+	}
+	return nil
+}
+
+// RebuildRecordsTx detects the user's personal records again from all of their logged sets,
+// within the caller's transaction. It is used after sets are added out of order, such as
+// imported training history. The caller commits or rolls back tx.
+func (s *PersonalRecordService) RebuildRecordsTx(ctx context.Context, tx *sql.Tx, userID string) error {
+	return s.rebuildRecords(ctx, s.queries.WithTx(tx), userID)
+}
+
+// rebuildRecords discards the user's personal records and detects them again by replaying
+// every logged set in the order it was logged. It is used after logged sets are corrected
+// or deleted, since any later record may have been measured against the changed set.
+//
+// Session volume is read for the whole session, so a rebuilt volume record is attributed
+// to the first set of the session that holds it.
+func (s *PersonalRecordService) rebuildRecords(ctx context.Context, q *db.Queries, userID string) error {
+	if err := q.DeletePersonalRecordsByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete personal records: %w", err)
+	}
+
+	rows, err := q.ListLoggedSetsByUserChronological(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list logged sets: %w", err)
+	}
+	for _, row := range rows {
+		if _, err := s.detectRecords(ctx, q, chronologicalRowToLoggedSet(row)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func chronologicalRowToLoggedSet(row db.ListLoggedSetsByUserChronologicalRow) *loggedset.LoggedSet {
+	createdAt, _ := time.Parse(time.RFC3339, row.CreatedAt)
+	ls := &loggedset.LoggedSet{
+		ID:             row.ID,
+		UserID:         row.UserID,
+		SessionID:      row.SessionID,
+		PrescriptionID: row.PrescriptionID,
+		LiftID:         row.LiftID,
+		SetNumber:      int(row.SetNumber),
+		Weight:         row.Weight,
+		TargetReps:     int(row.TargetReps),
+		RepsPerformed:  int(row.RepsPerformed),
+		IsAMRAP:        row.IsAmrap,
+		CreatedAt:      createdAt,
+	}
+	if row.Rpe.Valid {
+		ls.RPE = &row.Rpe.Float64
+	}
+	return ls
+}
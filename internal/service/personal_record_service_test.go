@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/loggedset"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/personalrecord"
+)
+
+// prTestSet builds a logged set for personal record tests.
+func prTestSet(userID, sessionID, liftID string, setNumber int, weight float64, reps int) *loggedset.LoggedSet {
+	return &loggedset.LoggedSet{
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		SessionID:      sessionID,
+		PrescriptionID: "prescription-" + liftID,
+		LiftID:         liftID,
+		SetNumber:      setNumber,
+		Weight:         weight,
+		TargetReps:     reps,
+		RepsPerformed:  reps,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// recordTypes returns the record types in the order they were reported.
+func recordTypes(records []PersonalRecord) []personalrecord.RecordType {
+	types := make([]personalrecord.RecordType, len(records))
+	for i, r := range records {
+		types[i] = r.RecordType
+	}
+	return types
+}
+
+func hasRecordType(records []PersonalRecord, recordType personalrecord.RecordType) bool {
+	for _, r := range records {
+		if r.RecordType == recordType {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPersonalRecordService_LogSetWithRecords(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := NewPersonalRecordService(sqlDB)
+
+	userID := uuid.New().String()
+	now := time.Now().Format(time.RFC3339)
+	if err := db.New(sqlDB).CreateUser(ctx, db.CreateUserParams{ID: userID, CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	t.Run("first set of a lift sets every applicable record", func(t *testing.T) {
+		records, err := svc.LogSetWithRecords(ctx, prTestSet(userID, "session-1", seededSquatID, 1, 300, 5))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []personalrecord.RecordType{personalrecord.TypeRepMax, personalrecord.TypeE1RM, personalrecord.TypeSessionVolume}
+		types := recordTypes(records)
+		if len(types) != len(expected) {
+			t.Fatalf("expected records %v, got %v", expected, types)
+		}
+		for i := range expected {
+			if types[i] != expected[i] {
+				t.Errorf("expected records %v, got %v", expected, types)
+			}
+		}
+		if records[0].RepCount == nil || *records[0].RepCount != 5 {
+			t.Errorf("expected rep max for 5 reps, got %v", records[0].RepCount)
+		}
+		if records[1].Value != 350 {
+			t.Errorf("expected E1RM 350, got %.1f", records[1].Value)
+		}
+		if records[2].Value != 1500 {
+			t.Errorf("expected session volume 1500, got %.1f", records[2].Value)
+		}
+	})
+
+	t.Run("lighter set in the same session only raises session volume silently", func(t *testing.T) {
+		records, err := svc.LogSetWithRecords(ctx, prTestSet(userID, "session-1", seededSquatID, 2, 280, 5))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(records) != 0 {
+			t.Errorf("expected no records, got %v", recordTypes(records))
+		}
+	})
+
+	t.Run("heavier set beats the previous record", func(t *testing.T) {
+		records, err := svc.LogSetWithRecords(ctx, prTestSet(userID, "session-2", seededSquatID, 1, 315, 5))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !hasRecordType(records, personalrecord.TypeRepMax) || !hasRecordType(records, personalrecord.TypeE1RM) {
+			t.Fatalf("expected rep max and E1RM records, got %v", recordTypes(records))
+		}
+		// Session 1 volume is 1500 + 1400 = 2900; session 2 has 1575 so far
+		if hasRecordType(records, personalrecord.TypeSessionVolume) {
+			t.Error("expected no session volume record")
+		}
+		for _, r := range records {
+			if r.RecordType == personalrecord.TypeRepMax {
+				if r.PreviousValue == nil || *r.PreviousValue != 300 {
+					t.Errorf("expected previous rep max 300, got %v", r.PreviousValue)
+				}
+			}
+		}
+	})
+
+	t.Run("sets beyond 12 reps count toward the 12+ rep max", func(t *testing.T) {
+		records, err := svc.LogSetWithRecords(ctx, prTestSet(userID, "session-3", seededSquatID, 1, 135, 15))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(records) == 0 || records[0].RecordType != personalrecord.TypeRepMax {
+			t.Fatalf("expected rep max record, got %v", recordTypes(records))
+		}
+		if *records[0].RepCount != personalrecord.MaxRepBucket {
+			t.Errorf("expected rep count 12, got %d", *records[0].RepCount)
+		}
+		if hasRecordType(records, personalrecord.TypeE1RM) {
+			t.Error("expected no E1RM record for a set above 12 reps")
+		}
+	})
+
+	t.Run("competition total requires all competition lifts", func(t *testing.T) {
+		records, err := svc.LogSetWithRecords(ctx, prTestSet(userID, "session-4", seededBenchID, 1, 225, 1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if hasRecordType(records, personalrecord.TypeCompetitionTotal) {
+			t.Error("expected no competition total without a deadlift")
+		}
+
+		records, err = svc.LogSetWithRecords(ctx, prTestSet(userID, "session-4", seededDeadliftID, 1, 405, 1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !hasRecordType(records, personalrecord.TypeCompetitionTotal) {
+			t.Fatalf("expected competition total record, got %v", recordTypes(records))
+		}
+		total := records[len(records)-1]
+		// Squat E1RM 315 x 5 → 367.5, bench 225, deadlift 405
+		if total.Value != 997.5 {
+			t.Errorf("expected total 997.5, got %.1f", total.Value)
+		}
+		if total.LiftID != nil {
+			t.Error("expected competition total to have no lift")
+		}
+	})
+
+	t.Run("list current records returns the best per lift, type and rep count", func(t *testing.T) {
+		records, err := svc.ListCurrentRecords(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var squatFiveRM *CurrentPersonalRecord
+		for i, r := range records {
+			if r.RecordType == personalrecord.TypeRepMax && *r.LiftID == seededSquatID && *r.RepCount == 5 {
+				if squatFiveRM != nil {
+					t.Fatal("expected a single current 5RM for squat")
+				}
+				squatFiveRM = &records[i]
+			}
+		}
+		if squatFiveRM == nil {
+			t.Fatal("expected a current 5RM for squat")
+		}
+		if squatFiveRM.Value != 315 {
+			t.Errorf("expected current 5RM 315, got %.1f", squatFiveRM.Value)
+		}
+		if squatFiveRM.LiftSlug == nil || *squatFiveRM.LiftSlug != "squat" {
+			t.Errorf("expected lift slug squat, got %v", squatFiveRM.LiftSlug)
+		}
+	})
+
+	t.Run("failed insert rolls back without records", func(t *testing.T) {
+		set := prTestSet(userID, "session-5", seededSquatID, 1, 500, 1)
+		duplicate := *set
+		duplicate.ID = uuid.New().String()
+
+		if _, err := svc.LogSetWithRecords(ctx, set); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		before, err := svc.ListCurrentRecords(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Same session, prescription and set number violates the unique constraint
+		if _, err := svc.LogSetWithRecords(ctx, &duplicate); err == nil {
+			t.Fatal("expected error for duplicate set")
+		}
+		after, err := svc.ListCurrentRecords(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(before) != len(after) {
+			t.Errorf("expected %d records after rollback, got %d", len(before), len(after))
+		}
+	})
+}
@@ -0,0 +1,75 @@
+// Package service provides application service layer implementations.
+// This file implements dry-run previews of progression triggers.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/progression"
+)
+
+// DryRunSessionComplete previews the AFTER_SESSION progressions for a trigger event.
+// Each progression runs exactly as in HandleSessionComplete, inside a transaction
+// that is rolled back, so nothing is persisted.
+func (s *ProgressionService) DryRunSessionComplete(ctx context.Context, event *progression.TriggerEventV2) (*AggregateResult, error) {
+	return s.handleSessionComplete(ctx, event, true)
+}
+
+// DryRunWeekAdvance previews the AFTER_WEEK progressions for a trigger event without persisting them.
+func (s *ProgressionService) DryRunWeekAdvance(ctx context.Context, event *progression.TriggerEventV2) (*AggregateResult, error) {
+	return s.handleWeekAdvance(ctx, event, true)
+}
+
+// DryRunCycleComplete previews the AFTER_CYCLE progressions for a trigger event without persisting them.
+func (s *ProgressionService) DryRunCycleComplete(ctx context.Context, event *progression.TriggerEventV2) (*AggregateResult, error) {
+	return s.handleCycleComplete(ctx, event, true)
+}
+
+// DryRunProgressionManually previews ApplyProgressionManually without persisting anything.
+func (s *ProgressionService) DryRunProgressionManually(
+	ctx context.Context,
+	userID, progressionID, liftID string,
+	force bool,
+) (*ManualTriggerResult, error) {
+	return s.applyProgressionManually(ctx, userID, progressionID, liftID, force, true)
+}
+
+// NewWorkoutSessionTriggerEvent builds the AFTER_SESSION trigger event for finishing a
+// workout session. The lifts performed are the lifts with sets logged in the session.
+func (s *ProgressionService) NewWorkoutSessionTriggerEvent(
+	ctx context.Context,
+	userID, programID, sessionID string,
+	weekNumber, dayIndex int,
+) (*progression.TriggerEventV2, error) {
+	daySlug := fmt.Sprintf("day-%d", dayIndex+1)
+	day, err := s.queries.GetDayForWeekPosition(ctx, db.GetDayForWeekPositionParams{
+		ID:         programID,
+		WeekNumber: int64(weekNumber),
+		Offset:     int64(dayIndex),
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, wrapError("failed to get session day", err)
+	}
+	if err == nil {
+		daySlug = day.Slug
+	}
+
+	sets, err := s.queries.ListLoggedSetsBySession(ctx, sessionID)
+	if err != nil {
+		return nil, wrapError("failed to get logged sets", err)
+	}
+
+	seen := make(map[string]bool)
+	liftsPerformed := []string{}
+	for _, set := range sets {
+		if !seen[set.LiftID] {
+			liftsPerformed = append(liftsPerformed, set.LiftID)
+			seen[set.LiftID] = true
+		}
+	}
+
+	return progression.NewSessionTriggerEvent(userID, sessionID, daySlug, weekNumber, liftsPerformed), nil
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/progression"
+)
+
+// TestProgressionService_DryRunSessionComplete tests that a dry run reports the
+// progression without persisting it.
+func TestProgressionService_DryRunSessionComplete(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	service := NewProgressionService(sqlDB, GetDefaultFactory())
+	queries := db.New(sqlDB)
+	ctx := context.Background()
+
+	event := progression.NewSessionTriggerEvent(data.UserID, "session-1", "day-a", 1, []string{data.SquatID})
+
+	result, err := service.DryRunSessionComplete(ctx, event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.DryRun {
+		t.Error("expected DryRun=true")
+	}
+	if result.TotalApplied != 1 || result.Results[0].Result.NewValue != 305 {
+		t.Fatalf("expected squat to progress to 305, got %+v", result.Results)
+	}
+
+	currentMax, err := queries.GetCurrentMax(ctx, db.GetCurrentMaxParams{UserID: data.UserID, LiftID: data.SquatID, Type: "TRAINING_MAX"})
+	if err != nil {
+		t.Fatalf("failed to get current max: %v", err)
+	}
+	if currentMax.Value != 300 {
+		t.Errorf("expected max to remain 300 after dry run, got %f", currentMax.Value)
+	}
+
+	var logCount int
+	if err := sqlDB.QueryRow("SELECT COUNT(*) FROM progression_logs WHERE user_id = ?", data.UserID).Scan(&logCount); err != nil {
+		t.Fatalf("failed to count progression logs: %v", err)
+	}
+	if logCount != 0 {
+		t.Errorf("expected no progression logs after dry run, got %d", logCount)
+	}
+
+	// The same event still applies for real afterwards
+	applied, err := service.HandleSessionComplete(ctx, event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if applied.DryRun || applied.TotalApplied != 1 || applied.Results[0].Result.NewValue != 305 {
+		t.Errorf("expected real application to match dry run, got %+v", applied.Results)
+	}
+}
+
+// TestProgressionService_DryRunChainsProgressions tests that a dry run previews each
+// progression of a lift on top of the ones previewed before it, so the preview matches
+// applying the same event.
+func TestProgressionService_DryRunChainsProgressions(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	service := NewProgressionService(sqlDB, GetDefaultFactory())
+	queries := db.New(sqlDB)
+	ctx := context.Background()
+	now := time.Now().Format(time.RFC3339)
+
+	bonusID := uuid.New().String()
+	err := queries.CreateProgression(ctx, db.CreateProgressionParams{
+		ID:   bonusID,
+		Name: "Session Bonus",
+		Type: string(progression.TypeLinear),
+		Parameters: `{
+			"id": "` + bonusID + `",
+			"name": "Session Bonus",
+			"increment": 10.0,
+			"maxType": "TRAINING_MAX",
+			"triggerType": "AFTER_SESSION"
+		}`,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("failed to create bonus progression: %v", err)
+	}
+	err = queries.CreateProgramProgression(ctx, db.CreateProgramProgressionParams{
+		ID:            uuid.New().String(),
+		ProgramID:     data.ProgramID,
+		ProgressionID: bonusID,
+		LiftID:        sql.NullString{String: data.SquatID, Valid: true},
+		Priority:      4,
+		Enabled:       1,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		t.Fatalf("failed to create program progression: %v", err)
+	}
+
+	event := progression.NewSessionTriggerEvent(data.UserID, "session-1", "day-a", 1, []string{data.SquatID})
+	preview, err := service.DryRunSessionComplete(ctx, event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(preview.Results) != 2 || !preview.Results[0].Applied || preview.Results[0].Result.NewValue != 305 {
+		t.Fatalf("expected the session progression to preview 300 -> 305, got %+v", preview.Results)
+	}
+	// The bonus progression sees the max previewed by the session progression, which
+	// already takes this event's effective date
+	if preview.Results[1].Applied || preview.Results[1].Error == "" {
+		t.Errorf("expected the bonus progression to conflict with the previewed max, got %+v", preview.Results[1])
+	}
+
+	currentMax, err := queries.GetCurrentMax(ctx, db.GetCurrentMaxParams{UserID: data.UserID, LiftID: data.SquatID, Type: "TRAINING_MAX"})
+	if err != nil {
+		t.Fatalf("failed to get current max: %v", err)
+	}
+	if currentMax.Value != 300 {
+		t.Errorf("expected max to remain 300 after dry run, got %f", currentMax.Value)
+	}
+
+	applied, err := service.HandleSessionComplete(ctx, event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, result := range applied.Results {
+		if result.Applied != preview.Results[i].Applied || result.Error != preview.Results[i].Error {
+			t.Errorf("result %d: expected application %+v to match dry run %+v", i, result, preview.Results[i])
+		}
+	}
+}
+
+// TestProgressionService_DryRunFailureCounterReset tests that failure counter resets are
+// reported by a dry run and only persisted when the progression is applied.
+func TestProgressionService_DryRunFailureCounterReset(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	service := NewProgressionService(sqlDB, GetDefaultFactory())
+	queries := db.New(sqlDB)
+	ctx := context.Background()
+	now := time.Now().Format(time.RFC3339)
+
+	deloadID := uuid.New().String()
+	err := queries.CreateProgression(ctx, db.CreateProgressionParams{
+		ID:   deloadID,
+		Name: "Deload",
+		Type: string(progression.TypeDeloadOnFailure),
+		Parameters: `{
+			"id": "` + deloadID + `",
+			"name": "Deload",
+			"failureThreshold": 2,
+			"deloadType": "fixed",
+			"deloadAmount": 10,
+			"resetOnDeload": true,
+			"maxType": "TRAINING_MAX"
+		}`,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("failed to create deload progression: %v", err)
+	}
+
+	err = queries.CreateFailureCounter(ctx, db.CreateFailureCounterParams{
+		ID:                  uuid.New().String(),
+		UserID:              data.UserID,
+		LiftID:              data.SquatID,
+		ProgressionID:       deloadID,
+		ConsecutiveFailures: 2,
+		LastFailureAt:       sql.NullString{String: now, Valid: true},
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	})
+	if err != nil {
+		t.Fatalf("failed to create failure counter: %v", err)
+	}
+
+	failureCount := func() int64 {
+		counter, err := queries.GetFailureCounterByKey(ctx, db.GetFailureCounterByKeyParams{
+			UserID:        data.UserID,
+			LiftID:        data.SquatID,
+			ProgressionID: deloadID,
+		})
+		if err != nil {
+			t.Fatalf("failed to get failure counter: %v", err)
+		}
+		return counter.ConsecutiveFailures
+	}
+
+	preview, err := service.DryRunProgressionManually(ctx, data.UserID, deloadID, data.SquatID, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !preview.DryRun || preview.TotalApplied != 1 {
+		t.Fatalf("expected one previewed application, got %+v", preview)
+	}
+	change := preview.Results[0].FailureCounterChange
+	if change == nil || change.PreviousFailures != 2 || change.NewFailures != 0 {
+		t.Errorf("expected failure counter change 2 -> 0, got %+v", change)
+	}
+	if preview.Results[0].Result.NewValue != 290 {
+		t.Errorf("expected deload to 290, got %f", preview.Results[0].Result.NewValue)
+	}
+	if got := failureCount(); got != 2 {
+		t.Errorf("expected failure counter to remain 2 after dry run, got %d", got)
+	}
+
+	applied, err := service.ApplyProgressionManually(ctx, data.UserID, deloadID, data.SquatID, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if applied.Results[0].FailureCounterChange == nil {
+		t.Error("expected failure counter change on application")
+	}
+	if got := failureCount(); got != 0 {
+		t.Errorf("expected failure counter reset to 0, got %d", got)
+	}
+}
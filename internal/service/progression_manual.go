@@ -19,6 +19,8 @@ type ManualTriggerResult struct {
 	TotalApplied int             `json:"totalApplied"`
 	TotalSkipped int             `json:"totalSkipped"`
 	TotalErrors  int             `json:"totalErrors"`
+	// DryRun is true when the results are a preview and nothing was persisted.
+	DryRun bool `json:"dryRun"`
 }
 
 // ApplyProgressionManually applies a progression manually (for testing/admin override).
@@ -28,6 +30,14 @@ func (s *ProgressionService) ApplyProgressionManually(
 	ctx context.Context,
 	userID, progressionID, liftID string,
 	force bool,
+) (*ManualTriggerResult, error) {
+	return s.applyProgressionManually(ctx, userID, progressionID, liftID, force, false)
+}
+
+func (s *ProgressionService) applyProgressionManually(
+	ctx context.Context,
+	userID, progressionID, liftID string,
+	force, dryRun bool,
 ) (*ManualTriggerResult, error) {
 	// Get the progression definition
 	progressionDef, err := s.queries.GetProgression(ctx, progressionID)
@@ -96,11 +106,23 @@ func (s *ProgressionService) ApplyProgressionManually(
 	// Build the results
 	result := &ManualTriggerResult{
 		Results: make([]TriggerResult, 0, len(liftIDs)),
+		DryRun:  dryRun,
+	}
+
+	// A dry run applies every lift in one transaction that is rolled back at the end
+	var dryRunTx *sql.Tx
+	if dryRun {
+		var discard func()
+		dryRunTx, discard, err = s.beginDryRun(ctx)
+		if err != nil {
+			return nil, wrapError("failed to begin dry run transaction", err)
+		}
+		defer discard()
 	}
 
 	// Apply progression to each lift
 	for _, lid := range liftIDs {
-		triggerResult := s.applyManualProgressionToLift(ctx, userID, progressionID, lid, enrollment.ProgramID, prog, force)
+		triggerResult := s.applyManualProgressionToLift(ctx, userID, progressionID, lid, enrollment.ProgramID, prog, force, dryRunTx)
 		result.Results = append(result.Results, triggerResult)
 
 		if triggerResult.Applied {
@@ -121,6 +143,7 @@ func (s *ProgressionService) applyManualProgressionToLift(
 	userID, progressionID, liftID, programID string,
 	prog progression.Progression,
 	force bool,
+	dryRunTx *sql.Tx,
 ) TriggerResult {
 	// Build a synthetic trigger event with ManualTriggerContext
 	now := time.Now()
@@ -198,8 +221,8 @@ func (s *ProgressionService) applyManualProgressionToLift(
 
 	// If force is true, use applyProgressionWithTransactionForce which bypasses idempotency
 	if force {
-		return s.applyProgressionWithTransactionForce(ctx, event, pp, prog)
+		return s.applyProgressionWithTransactionForce(ctx, event, pp, prog, dryRunTx)
 	}
 
-	return s.applyProgressionWithTransaction(ctx, event, pp, prog)
+	return s.applyProgressionWithTransaction(ctx, event, pp, prog, dryRunTx)
 }
@@ -0,0 +1,278 @@
+// Package service provides application service layer implementations.
+// This file implements reverting an applied progression.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/progression"
+)
+
+// Errors for progression revert operations.
+var (
+	ErrProgressionLogNotFound     = errors.New("progression log entry not found")
+	ErrProgressionAlreadyReverted = errors.New("progression has already been reverted")
+	ErrCannotRevertRevert         = errors.New("a revert entry cannot itself be reverted")
+	ErrProgressionHasDependents   = errors.New("later progressions for this lift depend on this progression")
+	ErrMaxChangedSinceProgression = errors.New("the lift max has changed since this progression was applied")
+)
+
+// RevertResult describes the outcome of reverting a progression.
+type RevertResult struct {
+	// RevertedLogID is the progression log entry that was undone.
+	RevertedLogID string `json:"revertedLogId"`
+	// RevertLogID is the compensating log entry written for the revert.
+	RevertLogID   string    `json:"revertLogId"`
+	ProgressionID string    `json:"progressionId"`
+	LiftID        string    `json:"liftId"`
+	MaxType       string    `json:"maxType"`
+	PreviousValue float64   `json:"previousValue"`
+	RestoredValue float64   `json:"restoredValue"`
+	Delta         float64   `json:"delta"`
+	RevertedAt    time.Time `json:"revertedAt"`
+	// StageChange is set when the stage progression state was restored.
+	StageChange *StageChange `json:"stageChange,omitempty"`
+	// FailureCounterChange is set when a failure count reset by the progression was restored.
+	FailureCounterChange *FailureCounterChange `json:"failureCounterChange,omitempty"`
+}
+
+// revertTriggerContext is stored as the trigger context of a compensating log entry.
+type revertTriggerContext struct {
+	RevertsLogID string `json:"revertsLogId"`
+}
+
+// RevertProgression undoes the progression recorded by a log entry. The lift max is
+// restored to the value it had before the progression, the stage and failure counter
+// the progression replaced are restored, and a compensating log entry is written.
+//
+// A progression can only be reverted while it is the latest change to the lift max:
+// if later progressions for the same lift are still applied, or the max was changed
+// some other way, the revert is refused. Dependent progressions can be reverted first,
+// newest to oldest.
+func (s *ProgressionService) RevertProgression(ctx context.Context, userID, logID string) (result *RevertResult, err error) {
+	if s.tx != nil {
+		return s.revertProgressionInTx(ctx, s.queries, userID, logID)
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	result, err = s.revertProgressionInTx(ctx, s.queries.WithTx(tx), userID, logID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// revertProgressionInTx reverts a progression using queries bound to the caller's
+// transaction, so a revert can be combined with other changes atomically.
+func (s *ProgressionService) revertProgressionInTx(ctx context.Context, txQueries *db.Queries, userID, logID string) (*RevertResult, error) {
+	logEntry, err := txQueries.GetProgressionLog(ctx, logID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrProgressionLogNotFound
+		}
+		return nil, wrapError("failed to get progression log", err)
+	}
+	if logEntry.UserID != userID {
+		return nil, ErrProgressionLogNotFound
+	}
+	if logEntry.RevertsLogID.Valid {
+		return nil, ErrCannotRevertRevert
+	}
+	if logEntry.RevertedByLogID.Valid {
+		return nil, ErrProgressionAlreadyReverted
+	}
+
+	// Resolve which max the progression modified
+	progressionDef, err := txQueries.GetProgression(ctx, logEntry.ProgressionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrProgressionNotFound
+		}
+		return nil, wrapError("failed to get progression", err)
+	}
+	prog, err := s.factory.Create(progression.ProgressionType(progressionDef.Type), json.RawMessage(progressionDef.Parameters))
+	if err != nil {
+		return nil, wrapError("failed to parse progression", err)
+	}
+	maxType, err := getMaxTypeFromProgression(prog)
+	if err != nil {
+		return nil, wrapError("failed to get max type", err)
+	}
+
+	dependents, err := txQueries.ListDependentProgressionLogs(ctx, db.ListDependentProgressionLogsParams{
+		UserID:    userID,
+		LiftID:    logEntry.LiftID,
+		AppliedAt: logEntry.AppliedAt,
+		ID:        logEntry.ID,
+	})
+	if err != nil {
+		return nil, wrapError("failed to list dependent progressions", err)
+	}
+	if len(dependents) > 0 {
+		ids := make([]string, len(dependents))
+		for i, d := range dependents {
+			ids[i] = d.ID
+		}
+		return nil, wrapErrorString(ErrProgressionHasDependents, "revert "+strings.Join(ids, ", ")+" first")
+	}
+
+	currentMax, err := txQueries.GetCurrentMax(ctx, db.GetCurrentMaxParams{
+		UserID: userID,
+		LiftID: logEntry.LiftID,
+		Type:   string(maxType),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNoCurrentMax
+		}
+		return nil, wrapError("failed to get current max", err)
+	}
+	if currentMax.Value != logEntry.NewValue {
+		return nil, ErrMaxChangedSinceProgression
+	}
+
+	// The +1 second offset keeps the restored max sorting after the max being replaced
+	// (see the timestamp note in applyProgressionCore)
+	now := time.Now().Add(time.Second)
+	nowStr := now.Format(time.RFC3339Nano)
+
+	err = txQueries.CreateLiftMax(ctx, db.CreateLiftMaxParams{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		LiftID:        logEntry.LiftID,
+		Type:          string(maxType),
+		Value:         logEntry.PreviousValue,
+		EffectiveDate: nowStr,
+		CreatedAt:     nowStr,
+		UpdatedAt:     nowStr,
+	})
+	if err != nil {
+		return nil, wrapError("failed to restore lift max", err)
+	}
+
+	result := &RevertResult{
+		RevertedLogID: logEntry.ID,
+		ProgressionID: logEntry.ProgressionID,
+		LiftID:        logEntry.LiftID,
+		MaxType:       string(maxType),
+		PreviousValue: logEntry.NewValue,
+		RestoredValue: logEntry.PreviousValue,
+		Delta:         -logEntry.Delta,
+		RevertedAt:    now,
+	}
+
+	// Restore the stage the progression moved away from
+	if logEntry.PreviousStage.Valid {
+		state, stateErr := txQueries.GetUserProgressionState(ctx, db.GetUserProgressionStateParams{
+			UserID:        userID,
+			LiftID:        logEntry.LiftID,
+			ProgressionID: logEntry.ProgressionID,
+		})
+		if stateErr != nil && stateErr != sql.ErrNoRows {
+			return nil, wrapError("failed to get progression state", stateErr)
+		}
+		currentStage := int(logEntry.PreviousStage.Int64)
+		if stateErr == nil {
+			currentStage = int(state.CurrentStage)
+		}
+		err = txQueries.UpsertUserProgressionState(ctx, db.UpsertUserProgressionStateParams{
+			ID:            uuid.New().String(),
+			UserID:        userID,
+			LiftID:        logEntry.LiftID,
+			ProgressionID: logEntry.ProgressionID,
+			CurrentStage:  logEntry.PreviousStage.Int64,
+			StateData:     sql.NullString{},
+		})
+		if err != nil {
+			return nil, wrapError("failed to restore progression state", err)
+		}
+		if currentStage != int(logEntry.PreviousStage.Int64) {
+			result.StageChange = &StageChange{PreviousStage: currentStage, NewStage: int(logEntry.PreviousStage.Int64)}
+		}
+	}
+
+	// Restore the failures the progression reset. Failures recorded since the
+	// progression are kept on top of the restored count.
+	if logEntry.PreviousFailures.Valid && logEntry.PreviousFailures.Int64 > 0 {
+		counter, counterErr := txQueries.GetFailureCounterByKey(ctx, db.GetFailureCounterByKeyParams{
+			UserID:        userID,
+			LiftID:        logEntry.LiftID,
+			ProgressionID: logEntry.ProgressionID,
+		})
+		if counterErr != nil && counterErr != sql.ErrNoRows {
+			return nil, wrapError("failed to get failure counter", counterErr)
+		}
+		if counterErr == nil {
+			restored := logEntry.PreviousFailures.Int64 + counter.ConsecutiveFailures
+			err = txQueries.UpdateFailureCounter(ctx, db.UpdateFailureCounterParams{
+				ConsecutiveFailures: restored,
+				LastFailureAt:       counter.LastFailureAt,
+				LastSuccessAt:       counter.LastSuccessAt,
+				UpdatedAt:           nowStr,
+				ID:                  counter.ID,
+			})
+			if err != nil {
+				return nil, wrapError("failed to restore failure counter", err)
+			}
+			result.FailureCounterChange = &FailureCounterChange{
+				PreviousFailures: int(counter.ConsecutiveFailures),
+				NewFailures:      int(restored),
+			}
+		}
+	}
+
+	// Write the compensating log entry and link the original to it
+	triggerContextJSON, err := json.Marshal(revertTriggerContext{RevertsLogID: logEntry.ID})
+	if err != nil {
+		return nil, wrapError("failed to serialize trigger context", err)
+	}
+	result.RevertLogID = uuid.New().String()
+	err = txQueries.CreateProgressionLog(ctx, db.CreateProgressionLogParams{
+		ID:             result.RevertLogID,
+		UserID:         userID,
+		ProgressionID:  logEntry.ProgressionID,
+		LiftID:         logEntry.LiftID,
+		PreviousValue:  logEntry.NewValue,
+		NewValue:       logEntry.PreviousValue,
+		Delta:          -logEntry.Delta,
+		TriggerType:    logEntry.TriggerType,
+		TriggerContext: sql.NullString{String: string(triggerContextJSON), Valid: true},
+		AppliedAt:      nowStr,
+		RevertsLogID:   sql.NullString{String: logEntry.ID, Valid: true},
+	})
+	if err != nil {
+		return nil, wrapError("failed to create revert log", err)
+	}
+
+	err = txQueries.MarkProgressionLogReverted(ctx, db.MarkProgressionLogRevertedParams{
+		RevertedByLogID: sql.NullString{String: result.RevertLogID, Valid: true},
+		ID:              logEntry.ID,
+	})
+	if err != nil {
+		return nil, wrapError("failed to mark progression log reverted", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/progression"
+)
+
+// applySquatSession applies the linear session progression to squat for a session
+// completed at the given time and returns the progression log ID.
+func applySquatSession(t *testing.T, service *ProgressionService, sqlDB *sql.DB, data testData, at time.Time) string {
+	t.Helper()
+	event := progression.NewSessionTriggerEvent(data.UserID, uuid.New().String(), "day-a", 1, []string{data.SquatID})
+	event.Timestamp = at
+	result, err := service.HandleSessionComplete(context.Background(), event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TotalApplied != 1 {
+		t.Fatalf("expected one applied progression, got %+v", result.Results)
+	}
+
+	var logID string
+	err = sqlDB.QueryRow(
+		"SELECT id FROM progression_logs WHERE user_id = ? AND applied_at = ?",
+		data.UserID, at.Format(time.RFC3339),
+	).Scan(&logID)
+	if err != nil {
+		t.Fatalf("failed to find progression log: %v", err)
+	}
+	return logID
+}
+
+func currentSquatMax(t *testing.T, queries *db.Queries, data testData) float64 {
+	t.Helper()
+	currentMax, err := queries.GetCurrentMax(context.Background(), db.GetCurrentMaxParams{UserID: data.UserID, LiftID: data.SquatID, Type: "TRAINING_MAX"})
+	if err != nil {
+		t.Fatalf("failed to get current max: %v", err)
+	}
+	return currentMax.Value
+}
+
+// TestProgressionService_RevertProgression tests reverting a single applied progression.
+func TestProgressionService_RevertProgression(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	service := NewProgressionService(sqlDB, GetDefaultFactory())
+	queries := db.New(sqlDB)
+	ctx := context.Background()
+
+	logID := applySquatSession(t, service, sqlDB, data, time.Now().Add(-time.Hour))
+	if got := currentSquatMax(t, queries, data); got != 305 {
+		t.Fatalf("expected squat max 305 after progression, got %f", got)
+	}
+
+	result, err := service.RevertProgression(ctx, data.UserID, logID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RevertedLogID != logID || result.PreviousValue != 305 || result.RestoredValue != 300 || result.Delta != -5 {
+		t.Errorf("unexpected revert result: %+v", result)
+	}
+	if got := currentSquatMax(t, queries, data); got != 300 {
+		t.Errorf("expected squat max restored to 300, got %f", got)
+	}
+
+	original, err := queries.GetProgressionLog(ctx, logID)
+	if err != nil {
+		t.Fatalf("failed to get original log: %v", err)
+	}
+	if original.RevertedByLogID.String != result.RevertLogID {
+		t.Errorf("expected original log to be marked reverted by %s, got %+v", result.RevertLogID, original.RevertedByLogID)
+	}
+	compensating, err := queries.GetProgressionLog(ctx, result.RevertLogID)
+	if err != nil {
+		t.Fatalf("failed to get revert log: %v", err)
+	}
+	if compensating.RevertsLogID.String != logID || compensating.PreviousValue != 305 || compensating.NewValue != 300 {
+		t.Errorf("unexpected compensating log: %+v", compensating)
+	}
+
+	t.Run("already reverted", func(t *testing.T) {
+		_, err := service.RevertProgression(ctx, data.UserID, logID)
+		if !errors.Is(err, ErrProgressionAlreadyReverted) {
+			t.Errorf("expected ErrProgressionAlreadyReverted, got %v", err)
+		}
+	})
+
+	t.Run("revert entry", func(t *testing.T) {
+		_, err := service.RevertProgression(ctx, data.UserID, result.RevertLogID)
+		if !errors.Is(err, ErrCannotRevertRevert) {
+			t.Errorf("expected ErrCannotRevertRevert, got %v", err)
+		}
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		_, err := service.RevertProgression(ctx, uuid.New().String(), logID)
+		if !errors.Is(err, ErrProgressionLogNotFound) {
+			t.Errorf("expected ErrProgressionLogNotFound, got %v", err)
+		}
+	})
+}
+
+// TestProgressionService_RevertProgressionDependents tests that a progression with later
+// progressions for the same lift is only reverted once those are reverted.
+func TestProgressionService_RevertProgressionDependents(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	service := NewProgressionService(sqlDB, GetDefaultFactory())
+	queries := db.New(sqlDB)
+	ctx := context.Background()
+
+	firstID := applySquatSession(t, service, sqlDB, data, time.Now().Add(-2*time.Hour))
+	secondID := applySquatSession(t, service, sqlDB, data, time.Now().Add(-time.Hour))
+	if got := currentSquatMax(t, queries, data); got != 310 {
+		t.Fatalf("expected squat max 310 after two progressions, got %f", got)
+	}
+
+	_, err := service.RevertProgression(ctx, data.UserID, firstID)
+	if !errors.Is(err, ErrProgressionHasDependents) {
+		t.Fatalf("expected ErrProgressionHasDependents, got %v", err)
+	}
+	if got := currentSquatMax(t, queries, data); got != 310 {
+		t.Errorf("expected squat max to remain 310 after refused revert, got %f", got)
+	}
+
+	if _, err := service.RevertProgression(ctx, data.UserID, secondID); err != nil {
+		t.Fatalf("unexpected error reverting latest progression: %v", err)
+	}
+	if _, err := service.RevertProgression(ctx, data.UserID, firstID); err != nil {
+		t.Fatalf("unexpected error reverting first progression: %v", err)
+	}
+	if got := currentSquatMax(t, queries, data); got != 300 {
+		t.Errorf("expected squat max restored to 300, got %f", got)
+	}
+}
+
+// TestProgressionService_RevertProgressionMaxChanged tests that a revert is refused when
+// the max was changed outside of progressions after the progression was applied.
+func TestProgressionService_RevertProgressionMaxChanged(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	service := NewProgressionService(sqlDB, GetDefaultFactory())
+	queries := db.New(sqlDB)
+	ctx := context.Background()
+
+	logID := applySquatSession(t, service, sqlDB, data, time.Now().Add(-time.Hour))
+
+	now := time.Now().Format(time.RFC3339)
+	err := queries.CreateLiftMax(ctx, db.CreateLiftMaxParams{
+		ID:            uuid.New().String(),
+		UserID:        data.UserID,
+		LiftID:        data.SquatID,
+		Type:          "TRAINING_MAX",
+		Value:         320,
+		EffectiveDate: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		t.Fatalf("failed to create lift max: %v", err)
+	}
+
+	_, err = service.RevertProgression(ctx, data.UserID, logID)
+	if !errors.Is(err, ErrMaxChangedSinceProgression) {
+		t.Errorf("expected ErrMaxChangedSinceProgression, got %v", err)
+	}
+	if got := currentSquatMax(t, queries, data); got != 320 {
+		t.Errorf("expected squat max to remain 320, got %f", got)
+	}
+}
+
+// TestProgressionService_RevertProgressionFailureCounter tests that a failure count reset
+// by a deload is restored when the deload is reverted.
+func TestProgressionService_RevertProgressionFailureCounter(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	service := NewProgressionService(sqlDB, GetDefaultFactory())
+	queries := db.New(sqlDB)
+	ctx := context.Background()
+	now := time.Now().Format(time.RFC3339)
+
+	deloadID := uuid.New().String()
+	err := queries.CreateProgression(ctx, db.CreateProgressionParams{
+		ID:   deloadID,
+		Name: "Deload",
+		Type: string(progression.TypeDeloadOnFailure),
+		Parameters: `{
+			"id": "` + deloadID + `",
+			"name": "Deload",
+			"failureThreshold": 2,
+			"deloadType": "fixed",
+			"deloadAmount": 10,
+			"resetOnDeload": true,
+			"maxType": "TRAINING_MAX"
+		}`,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("failed to create deload progression: %v", err)
+	}
+
+	err = queries.CreateFailureCounter(ctx, db.CreateFailureCounterParams{
+		ID:                  uuid.New().String(),
+		UserID:              data.UserID,
+		LiftID:              data.SquatID,
+		ProgressionID:       deloadID,
+		ConsecutiveFailures: 2,
+		LastFailureAt:       sql.NullString{String: now, Valid: true},
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	})
+	if err != nil {
+		t.Fatalf("failed to create failure counter: %v", err)
+	}
+
+	applied, err := service.ApplyProgressionManually(ctx, data.UserID, deloadID, data.SquatID, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if applied.TotalApplied != 1 {
+		t.Fatalf("expected deload to apply, got %+v", applied.Results)
+	}
+
+	var logID string
+	if err := sqlDB.QueryRow("SELECT id FROM progression_logs WHERE progression_id = ?", deloadID).Scan(&logID); err != nil {
+		t.Fatalf("failed to find progression log: %v", err)
+	}
+
+	result, err := service.RevertProgression(ctx, data.UserID, logID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	change := result.FailureCounterChange
+	if change == nil || change.PreviousFailures != 0 || change.NewFailures != 2 {
+		t.Errorf("expected failure counter change 0 -> 2, got %+v", change)
+	}
+
+	counter, err := queries.GetFailureCounterByKey(ctx, db.GetFailureCounterByKeyParams{
+		UserID:        data.UserID,
+		LiftID:        data.SquatID,
+		ProgressionID: deloadID,
+	})
+	if err != nil {
+		t.Fatalf("failed to get failure counter: %v", err)
+	}
+	if counter.ConsecutiveFailures != 2 {
+		t.Errorf("expected failure counter restored to 2, got %d", counter.ConsecutiveFailures)
+	}
+	if got := currentSquatMax(t, queries, data); got != 300 {
+		t.Errorf("expected squat max restored to 300, got %f", got)
+	}
+}
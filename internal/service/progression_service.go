@@ -33,6 +33,8 @@ type ProgressionService struct {
 	sqlDB   *sql.DB
 	queries *db.Queries
 	factory *progression.ProgressionFactory
+	// tx, when set, is the caller's transaction progressions are applied and reverted in.
+	tx *sql.Tx
 }
 
 // NewProgressionService creates a new ProgressionService.
@@ -44,6 +46,16 @@ func NewProgressionService(sqlDB *sql.DB, factory *progression.ProgressionFactor
 	}
 }
 
+// WithTx returns a copy of the service that reads, applies and reverts progressions in
+// tx instead of transactions of its own, so they are committed or rolled back with the
+// caller's other changes.
+func (s *ProgressionService) WithTx(tx *sql.Tx) *ProgressionService {
+	txService := *s
+	txService.tx = tx
+	txService.queries = s.queries.WithTx(tx)
+	return &txService
+}
+
 // TriggerResult represents the result of processing a single progression during a trigger.
 type TriggerResult struct {
 	ProgressionID string                         `json:"progressionId"`
@@ -53,6 +65,22 @@ type TriggerResult struct {
 	SkipReason    string                         `json:"skipReason,omitempty"`
 	Result        *progression.ProgressionResult `json:"result,omitempty"`
 	Error         string                         `json:"error,omitempty"`
+	// StageChange is set when a stage progression moved the lift to a different stage.
+	StageChange *StageChange `json:"stageChange,omitempty"`
+	// FailureCounterChange is set when applying the progression reset the failure counter.
+	FailureCounterChange *FailureCounterChange `json:"failureCounterChange,omitempty"`
+}
+
+// StageChange describes the stage transition made by a stage progression.
+type StageChange struct {
+	PreviousStage int `json:"previousStage"`
+	NewStage      int `json:"newStage"`
+}
+
+// FailureCounterChange describes the change to a failure counter made by a progression.
+type FailureCounterChange struct {
+	PreviousFailures int `json:"previousFailures"`
+	NewFailures      int `json:"newFailures"`
 }
 
 // AggregateResult represents the result of processing all progressions for a trigger event.
@@ -65,12 +93,18 @@ type AggregateResult struct {
 	TotalApplied int                     `json:"totalApplied"`
 	TotalSkipped int                     `json:"totalSkipped"`
 	TotalErrors  int                     `json:"totalErrors"`
+	// DryRun is true when the results are a preview and nothing was persisted.
+	DryRun bool `json:"dryRun"`
 }
 
 // HandleSessionComplete handles AFTER_SESSION triggers.
 // This is called when a user completes a training day.
 // Only applies progressions to lifts that were performed in the session.
 func (s *ProgressionService) HandleSessionComplete(ctx context.Context, event *progression.TriggerEventV2) (*AggregateResult, error) {
+	return s.handleSessionComplete(ctx, event, false)
+}
+
+func (s *ProgressionService) handleSessionComplete(ctx context.Context, event *progression.TriggerEventV2, dryRun bool) (*AggregateResult, error) {
 	if event.Type != progression.TriggerAfterSession {
 		return nil, fmt.Errorf("%w: expected AFTER_SESSION, got %s", ErrInvalidTriggerContext, event.Type)
 	}
@@ -84,13 +118,17 @@ func (s *ProgressionService) HandleSessionComplete(ctx context.Context, event *p
 		return nil, fmt.Errorf("invalid trigger event: %w", err)
 	}
 
-	return s.processProgressions(ctx, event, sessionCtx.LiftsPerformed)
+	return s.processProgressions(ctx, event, sessionCtx.LiftsPerformed, dryRun)
 }
 
 // HandleWeekAdvance handles AFTER_WEEK triggers.
 // This is called when a user advances from week N to week N+1.
 // Applies progressions to all configured lifts.
 func (s *ProgressionService) HandleWeekAdvance(ctx context.Context, event *progression.TriggerEventV2) (*AggregateResult, error) {
+	return s.handleWeekAdvance(ctx, event, false)
+}
+
+func (s *ProgressionService) handleWeekAdvance(ctx context.Context, event *progression.TriggerEventV2, dryRun bool) (*AggregateResult, error) {
 	if event.Type != progression.TriggerAfterWeek {
 		return nil, fmt.Errorf("%w: expected AFTER_WEEK, got %s", ErrInvalidTriggerContext, event.Type)
 	}
@@ -100,13 +138,17 @@ func (s *ProgressionService) HandleWeekAdvance(ctx context.Context, event *progr
 	}
 
 	// For week triggers, apply to all configured lifts (nil = all lifts)
-	return s.processProgressions(ctx, event, nil)
+	return s.processProgressions(ctx, event, nil, dryRun)
 }
 
 // HandleCycleComplete handles AFTER_CYCLE triggers.
 // This is called when a user completes a cycle (week wraps to 1).
 // Applies progressions to all configured lifts.
 func (s *ProgressionService) HandleCycleComplete(ctx context.Context, event *progression.TriggerEventV2) (*AggregateResult, error) {
+	return s.handleCycleComplete(ctx, event, false)
+}
+
+func (s *ProgressionService) handleCycleComplete(ctx context.Context, event *progression.TriggerEventV2, dryRun bool) (*AggregateResult, error) {
 	if event.Type != progression.TriggerAfterCycle {
 		return nil, fmt.Errorf("%w: expected AFTER_CYCLE, got %s", ErrInvalidTriggerContext, event.Type)
 	}
@@ -116,7 +158,7 @@ func (s *ProgressionService) HandleCycleComplete(ctx context.Context, event *pro
 	}
 
 	// For cycle triggers, apply to all configured lifts (nil = all lifts)
-	return s.processProgressions(ctx, event, nil)
+	return s.processProgressions(ctx, event, nil, dryRun)
 }
 
 // processProgressions is the core method that processes all applicable progressions for a trigger event.
@@ -141,7 +183,11 @@ func (s *ProgressionService) HandleCycleComplete(ctx context.Context, event *pro
 //   - A squat progression doesn't fire when the user only did bench press
 //   - Progressions are never applied twice for the same trigger event
 //   - Failures in one progression don't prevent others from being processed
-func (s *ProgressionService) processProgressions(ctx context.Context, event *progression.TriggerEventV2, liftsFilter []string) (*AggregateResult, error) {
+//
+// When dryRun is true, every progression is applied in one transaction that is rolled
+// back at the end, so the results show exactly what would happen, including several
+// progressions of the same lift building on each other, without persisting anything.
+func (s *ProgressionService) processProgressions(ctx context.Context, event *progression.TriggerEventV2, liftsFilter []string, dryRun bool) (*AggregateResult, error) {
 	// User must be enrolled in a program - progressions are always program-specific
 	enrollment, err := s.queries.GetUserProgramStateByUserID(ctx, event.UserID)
 	if err != nil {
@@ -170,6 +216,7 @@ func (s *ProgressionService) processProgressions(ctx context.Context, event *pro
 			TotalApplied: 0,
 			TotalSkipped: 0,
 			TotalErrors:  0,
+			DryRun:       dryRun,
 		}, nil
 	}
 
@@ -188,11 +235,22 @@ func (s *ProgressionService) processProgressions(ctx context.Context, event *pro
 		TotalApplied: 0,
 		TotalSkipped: 0,
 		TotalErrors:  0,
+		DryRun:       dryRun,
+	}
+
+	var dryRunTx *sql.Tx
+	if dryRun {
+		var discard func()
+		dryRunTx, discard, err = s.beginDryRun(ctx)
+		if err != nil {
+			return nil, wrapError("failed to begin dry run transaction", err)
+		}
+		defer discard()
 	}
 
 	// Process each progression independently - failures in one don't affect others
 	for _, pp := range programProgressions {
-		triggerResult := s.processSingleProgression(ctx, event, pp, liftsFilter, liftsFilterSet)
+		triggerResult := s.processSingleProgression(ctx, event, pp, liftsFilter, liftsFilterSet, dryRunTx)
 		if triggerResult != nil {
 			result.Results = append(result.Results, *triggerResult)
 			if triggerResult.Applied {
@@ -216,6 +274,7 @@ func (s *ProgressionService) processSingleProgression(
 	pp db.ProgramProgression,
 	liftsFilter []string,
 	liftsFilterSet map[string]bool,
+	dryRunTx *sql.Tx,
 ) *TriggerResult {
 	// Skip if no lift ID and we have a lift filter (can't apply program-wide progression to specific lifts)
 	if !pp.LiftID.Valid && liftsFilter != nil {
@@ -280,7 +339,7 @@ func (s *ProgressionService) processSingleProgression(
 	}
 
 	// Apply progression for this lift
-	triggerResult := s.applyProgressionWithTransaction(ctx, event, pp, prog)
+	triggerResult := s.applyProgressionWithTransaction(ctx, event, pp, prog, dryRunTx)
 	return &triggerResult
 }
 
@@ -14,18 +14,99 @@ import (
 	"github.com/waynenilsen/power-pro-v3/internal/domain/progression"
 )
 
+// progressionTx is the transaction a single progression is applied in. Normally it
+// is a database transaction of its own. During a dry run it is a savepoint in the
+// dry run's transaction instead, so each progression sees the changes previewed
+// before it and everything is discarded when the dry run's transaction is rolled back.
+// Likewise it is a savepoint in the caller's transaction when the service has one.
+type progressionTx struct {
+	*sql.Tx
+	savepoint bool
+	done      bool
+}
+
+// beginProgressionTx starts the transaction for a single progression, as a savepoint
+// of dryRunTx when it is set, or else of the service's transaction.
+func (s *ProgressionService) beginProgressionTx(ctx context.Context, dryRunTx *sql.Tx) (*progressionTx, error) {
+	outer := dryRunTx
+	if outer == nil {
+		outer = s.tx
+	}
+	if outer == nil {
+		tx, err := s.sqlDB.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &progressionTx{Tx: tx}, nil
+	}
+	if _, err := outer.ExecContext(ctx, "SAVEPOINT progression"); err != nil {
+		return nil, err
+	}
+	return &progressionTx{Tx: outer, savepoint: true}, nil
+}
+
+// beginDryRun starts the transaction a dry run previews its changes in, and returns
+// the function that discards them. When the service has a transaction, the dry run is
+// a savepoint in it.
+func (s *ProgressionService) beginDryRun(ctx context.Context) (*sql.Tx, func(), error) {
+	if s.tx == nil {
+		tx, err := s.sqlDB.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tx, func() { _ = tx.Rollback() }, nil
+	}
+	if _, err := s.tx.ExecContext(ctx, "SAVEPOINT dry_run"); err != nil {
+		return nil, nil, err
+	}
+	return s.tx, func() {
+		_, _ = s.tx.Exec("ROLLBACK TO SAVEPOINT dry_run")
+		_, _ = s.tx.Exec("RELEASE SAVEPOINT dry_run")
+	}, nil
+}
+
+// Commit commits the progression. During a dry run its changes are kept in the dry
+// run's transaction for the progressions that follow.
+func (t *progressionTx) Commit() error {
+	t.done = true
+	if t.savepoint {
+		_, err := t.Tx.Exec("RELEASE SAVEPOINT progression")
+		return err
+	}
+	return t.Tx.Commit()
+}
+
+// Rollback discards the progression's changes. It does nothing once the progression
+// has been committed or rolled back.
+func (t *progressionTx) Rollback() error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+	if t.savepoint {
+		if _, err := t.Tx.Exec("ROLLBACK TO SAVEPOINT progression"); err != nil {
+			return err
+		}
+		_, err := t.Tx.Exec("RELEASE SAVEPOINT progression")
+		return err
+	}
+	return t.Tx.Rollback()
+}
+
 // applyProgressionWithTransaction applies a single progression in an atomic transaction.
 // It performs idempotency check, updates LiftMax, and logs the application.
+// When dryRunTx is set, the progression is applied within it (see progressionTx).
 func (s *ProgressionService) applyProgressionWithTransaction(
 	ctx context.Context,
 	event *progression.TriggerEventV2,
 	pp db.ProgramProgression,
 	prog progression.Progression,
+	dryRunTx *sql.Tx,
 ) TriggerResult {
 	liftID := pp.LiftID.String
 
 	// Begin transaction
-	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	tx, err := s.beginProgressionTx(ctx, dryRunTx)
 	if err != nil {
 		return TriggerResult{
 			ProgressionID: pp.ProgressionID,
@@ -35,9 +116,8 @@ func (s *ProgressionService) applyProgressionWithTransaction(
 		}
 	}
 	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
+		// Discards anything not committed, including on error results
+		_ = tx.Rollback()
 	}()
 
 	txQueries := db.New(tx)
@@ -82,11 +162,12 @@ func (s *ProgressionService) applyProgressionWithTransactionForce(
 	event *progression.TriggerEventV2,
 	pp db.ProgramProgression,
 	prog progression.Progression,
+	dryRunTx *sql.Tx,
 ) TriggerResult {
 	liftID := pp.LiftID.String
 
 	// Begin transaction
-	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	tx, err := s.beginProgressionTx(ctx, dryRunTx)
 	if err != nil {
 		return TriggerResult{
 			ProgressionID: pp.ProgressionID,
@@ -96,9 +177,8 @@ func (s *ProgressionService) applyProgressionWithTransactionForce(
 		}
 	}
 	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
+		// Discards anything not committed, including on error results
+		_ = tx.Rollback()
 	}()
 
 	txQueries := db.New(tx)
@@ -117,10 +197,11 @@ func (s *ProgressionService) applyProgressionWithTransactionForce(
 }
 
 // applyProgressionCore contains the shared logic for applying a progression.
-// It handles max lookup, progression application, LiftMax creation, and logging.
+// It handles max lookup, progression application, LiftMax creation, stage and
+// failure counter updates, and logging.
 func (s *ProgressionService) applyProgressionCore(
 	ctx context.Context,
-	tx *sql.Tx,
+	tx *progressionTx,
 	txQueries *db.Queries,
 	event *progression.TriggerEventV2,
 	pp db.ProgramProgression,
@@ -177,6 +258,7 @@ func (s *ProgressionService) applyProgressionCore(
 	// For StageProgression, load the current stage from persistent storage
 	// This is necessary because the progression is re-created from stored params each time
 	var stageProg *progression.StageProgression
+	previousStage := 0
 	if sp, ok := prog.(*progression.StageProgression); ok {
 		stageProg = sp
 		// Look up current stage from user_progression_states
@@ -192,6 +274,7 @@ func (s *ProgressionService) applyProgressionCore(
 				_ = stageProg.SetCurrentStage(0)
 			}
 		}
+		previousStage = stageProg.CurrentStage
 		// If not found (sql.ErrNoRows), use default stage 0
 	}
 
@@ -266,7 +349,77 @@ func (s *ProgressionService) applyProgressionCore(
 		}
 	}
 
+	// For StageProgression, persist the new stage after successful application
+	// The stageProg.CurrentStage was updated in-memory by Apply()
+	var stageChange *StageChange
+	if stageProg != nil {
+		stateID := uuid.New().String()
+		err = txQueries.UpsertUserProgressionState(ctx, db.UpsertUserProgressionStateParams{
+			ID:            stateID,
+			UserID:        event.UserID,
+			LiftID:        liftID,
+			ProgressionID: pp.ProgressionID,
+			CurrentStage:  int64(stageProg.CurrentStage),
+			StateData:     sql.NullString{}, // No additional state data needed
+		})
+		if err != nil {
+			return TriggerResult{
+				ProgressionID: pp.ProgressionID,
+				LiftID:        liftID,
+				Applied:       false,
+				Error:         fmt.Sprintf("failed to persist stage state: %v", err),
+			}
+		}
+		if stageProg.CurrentStage != previousStage {
+			stageChange = &StageChange{PreviousStage: previousStage, NewStage: stageProg.CurrentStage}
+		}
+	}
+
+	// Progressions that change the set/rep scheme or deload (see ShouldResetFailureCounter)
+	// start the failure count over for the lift
+	var counterChange *FailureCounterChange
+	if resetter, ok := prog.(failureCounterResetter); ok && resetter.ShouldResetFailureCounter() {
+		counter, err := txQueries.GetFailureCounterByKey(ctx, db.GetFailureCounterByKeyParams{
+			UserID:        event.UserID,
+			LiftID:        liftID,
+			ProgressionID: pp.ProgressionID,
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return TriggerResult{
+				ProgressionID: pp.ProgressionID,
+				LiftID:        liftID,
+				Applied:       false,
+				Error:         fmt.Sprintf("failed to get failure counter: %v", err),
+			}
+		}
+		if err == nil && counter.ConsecutiveFailures > 0 {
+			err = txQueries.ResetFailureCounter(ctx, db.ResetFailureCounterParams{
+				LastSuccessAt: counter.LastSuccessAt,
+				UpdatedAt:     nowStr,
+				UserID:        event.UserID,
+				LiftID:        liftID,
+				ProgressionID: pp.ProgressionID,
+			})
+			if err != nil {
+				return TriggerResult{
+					ProgressionID: pp.ProgressionID,
+					LiftID:        liftID,
+					Applied:       false,
+					Error:         fmt.Sprintf("failed to reset failure counter: %v", err),
+				}
+			}
+			counterChange = &FailureCounterChange{PreviousFailures: int(counter.ConsecutiveFailures), NewFailures: 0}
+		}
+	}
+
 	// Create progression log entry
+	var previousStageValue, previousFailuresValue sql.NullInt64
+	if stageProg != nil {
+		previousStageValue = sql.NullInt64{Int64: int64(previousStage), Valid: true}
+	}
+	if counterChange != nil {
+		previousFailuresValue = sql.NullInt64{Int64: int64(counterChange.PreviousFailures), Valid: true}
+	}
 	triggerContextJSON, err := json.Marshal(event.Context)
 	if err != nil {
 		return TriggerResult{
@@ -289,6 +442,9 @@ func (s *ProgressionService) applyProgressionCore(
 		TriggerType:    string(event.Type),
 		TriggerContext: sql.NullString{String: string(triggerContextJSON), Valid: true},
 		AppliedAt:      appliedAtStr,
+		// Record the stage and failure count this progression replaced so it can be reverted
+		PreviousStage:    previousStageValue,
+		PreviousFailures: previousFailuresValue,
 	})
 	if err != nil {
 		return TriggerResult{
@@ -299,28 +455,6 @@ func (s *ProgressionService) applyProgressionCore(
 		}
 	}
 
-	// For StageProgression, persist the new stage after successful application
-	// The stageProg.CurrentStage was updated in-memory by Apply()
-	if stageProg != nil {
-		stateID := uuid.New().String()
-		err = txQueries.UpsertUserProgressionState(ctx, db.UpsertUserProgressionStateParams{
-			ID:            stateID,
-			UserID:        event.UserID,
-			LiftID:        liftID,
-			ProgressionID: pp.ProgressionID,
-			CurrentStage:  int64(stageProg.CurrentStage),
-			StateData:     sql.NullString{}, // No additional state data needed
-		})
-		if err != nil {
-			return TriggerResult{
-				ProgressionID: pp.ProgressionID,
-				LiftID:        liftID,
-				Applied:       false,
-				Error:         fmt.Sprintf("failed to persist stage state: %v", err),
-			}
-		}
-	}
-
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
 		return TriggerResult{
@@ -332,13 +466,21 @@ func (s *ProgressionService) applyProgressionCore(
 	}
 
 	return TriggerResult{
-		ProgressionID: pp.ProgressionID,
-		LiftID:        liftID,
-		Applied:       true,
-		Result:        &progressionResult,
+		ProgressionID:        pp.ProgressionID,
+		LiftID:               liftID,
+		Applied:              true,
+		Result:               &progressionResult,
+		StageChange:          stageChange,
+		FailureCounterChange: counterChange,
 	}
 }
 
+// failureCounterResetter is implemented by progressions that reset the lift's
+// failure counter when they are applied.
+type failureCounterResetter interface {
+	ShouldResetFailureCounter() bool
+}
+
 // getMaxTypeFromProgression extracts the MaxType from a progression.
 func getMaxTypeFromProgression(prog progression.Progression) (progression.MaxType, error) {
 	switch p := prog.(type) {
@@ -380,6 +522,11 @@ func buildTriggerEvent(event *progression.TriggerEventV2) progression.TriggerEve
 		triggerEvent.ConsecutiveFailures = &ctx.ConsecutiveFailures
 		triggerEvent.RepsPerformed = &ctx.RepsPerformed
 		triggerEvent.TargetReps = &ctx.TargetReps
+	case progression.SetTriggerContext:
+		triggerEvent.RepsPerformed = &ctx.RepsPerformed
+		triggerEvent.TargetReps = &ctx.TargetReps
+		triggerEvent.IsAMRAP = ctx.IsAMRAP
+		triggerEvent.SetWeight = &ctx.Weight
 	case *progression.ManualTriggerContext:
 		// For manual triggers, extract from the underlying context
 		underlyingEvent := &progression.TriggerEventV2{
@@ -0,0 +1,312 @@
+// Package service provides application service layer implementations.
+// This file implements the ReadinessService which records daily readiness check-ins
+// and resolves the load and volume adjustments they apply to a day's workout.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/readiness"
+)
+
+// CheckInDateFormat is the layout of readiness check-in dates (YYYY-MM-DD).
+const CheckInDateFormat = "2006-01-02"
+
+// ReadinessService records readiness check-ins and resolves workout adjustments.
+type ReadinessService struct {
+	queries *db.Queries
+	now     func() time.Time
+}
+
+// NewReadinessService creates a new ReadinessService.
+func NewReadinessService(sqlDB *sql.DB) *ReadinessService {
+	return &ReadinessService{
+		queries: db.New(sqlDB),
+		now:     time.Now,
+	}
+}
+
+// WithTx returns a copy of the service that runs its queries in tx.
+func (s *ReadinessService) WithTx(tx *sql.Tx) *ReadinessService {
+	txService := *s
+	txService.queries = s.queries.WithTx(tx)
+	return &txService
+}
+
+// ReadinessCheckIn is a lifter's readiness check-in for a day.
+type ReadinessCheckIn struct {
+	ID     string
+	UserID string
+	// Date is the calendar day the check-in applies to (YYYY-MM-DD).
+	Date string
+	readiness.Inputs
+	// Score is the composite 0-100 readiness score.
+	Score     float64
+	Notes     *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ReadinessMapping is the readiness mapping in effect for a program.
+type ReadinessMapping struct {
+	ProgramID string
+	readiness.Mapping
+	// IsDefault is true when the program has no mapping of its own.
+	IsDefault bool
+}
+
+// RecordCheckIn records the check-in for a user and day, replacing any earlier
+// check-in for the same day. The score is computed against the user's HRV baseline
+// from previous days. Inputs must already be validated.
+func (s *ReadinessService) RecordCheckIn(ctx context.Context, userID, date string, in readiness.Inputs, notes *string) (*ReadinessCheckIn, error) {
+	baseline, err := s.queries.GetReadinessHRVBaseline(ctx, db.GetReadinessHRVBaselineParams{
+		UserID:      userID,
+		CheckinDate: date,
+		Limit:       readiness.HRVBaselineWindow,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HRV baseline: %w", err)
+	}
+
+	score := readiness.Score(in, nullFloat64ToPtr(baseline))
+
+	now := s.now().UTC().Format(time.RFC3339)
+	err = s.queries.UpsertReadinessCheckIn(ctx, db.UpsertReadinessCheckInParams{
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		CheckinDate:    date,
+		SleepQuality:   intPtrToNullInt64(in.SleepQuality),
+		Soreness:       intPtrToNullInt64(in.Soreness),
+		Stress:         intPtrToNullInt64(in.Stress),
+		ReadinessScore: intPtrToNullInt64(in.ReadinessScore),
+		Hrv:            floatPtrToNullFloat64(in.HRV),
+		Score:          score,
+		Notes:          stringPtrToNullString(notes),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save readiness check-in: %w", err)
+	}
+
+	return s.GetCheckIn(ctx, userID, date)
+}
+
+// GetCheckIn returns the user's check-in for a day, or nil if they have not checked in.
+func (s *ReadinessService) GetCheckIn(ctx context.Context, userID, date string) (*ReadinessCheckIn, error) {
+	row, err := s.queries.GetReadinessCheckInByDate(ctx, db.GetReadinessCheckInByDateParams{
+		UserID:      userID,
+		CheckinDate: date,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get readiness check-in: %w", err)
+	}
+	return dbReadinessCheckInToDomain(row), nil
+}
+
+// ListCheckIns returns a user's check-ins, most recent day first, with the total count.
+func (s *ReadinessService) ListCheckIns(ctx context.Context, userID string, limit, offset int64) ([]ReadinessCheckIn, int64, error) {
+	rows, err := s.queries.ListReadinessCheckInsByUser(ctx, db.ListReadinessCheckInsByUserParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list readiness check-ins: %w", err)
+	}
+
+	total, err := s.queries.CountReadinessCheckInsByUser(ctx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count readiness check-ins: %w", err)
+	}
+
+	checkIns := make([]ReadinessCheckIn, len(rows))
+	for i, row := range rows {
+		checkIns[i] = *dbReadinessCheckInToDomain(row)
+	}
+	return checkIns, total, nil
+}
+
+// GetMapping returns the readiness mapping for a program, falling back to the default
+// mapping when the program has not configured one.
+func (s *ReadinessService) GetMapping(ctx context.Context, programID string) (*ReadinessMapping, error) {
+	row, err := s.queries.GetProgramReadinessMapping(ctx, programID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &ReadinessMapping{
+				ProgramID: programID,
+				Mapping:   readiness.DefaultMapping(),
+				IsDefault: true,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to get readiness mapping: %w", err)
+	}
+
+	var tiers []readiness.Tier
+	if err := json.Unmarshal([]byte(row.Tiers), &tiers); err != nil {
+		return nil, fmt.Errorf("failed to parse readiness mapping: %w", err)
+	}
+
+	return &ReadinessMapping{
+		ProgramID: programID,
+		Mapping:   readiness.Mapping{Tiers: tiers},
+	}, nil
+}
+
+// SetMapping saves a program's readiness mapping. The mapping must already be validated;
+// tiers are stored ordered by descending minimum score.
+func (s *ReadinessService) SetMapping(ctx context.Context, programID string, m readiness.Mapping) (*ReadinessMapping, error) {
+	normalized := m.Normalize()
+	tiers, err := json.Marshal(normalized.Tiers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize readiness mapping: %w", err)
+	}
+
+	now := s.now().UTC().Format(time.RFC3339)
+	err = s.queries.UpsertProgramReadinessMapping(ctx, db.UpsertProgramReadinessMappingParams{
+		ProgramID: programID,
+		Tiers:     string(tiers),
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save readiness mapping: %w", err)
+	}
+
+	return &ReadinessMapping{ProgramID: programID, Mapping: normalized}, nil
+}
+
+// DeleteMapping removes a program's readiness mapping so it uses the default again.
+func (s *ReadinessService) DeleteMapping(ctx context.Context, programID string) error {
+	if err := s.queries.DeleteProgramReadinessMapping(ctx, programID); err != nil {
+		return fmt.Errorf("failed to delete readiness mapping: %w", err)
+	}
+	return nil
+}
+
+// GetAdjustment resolves the readiness adjustment for a user's workout on a day
+// under the program's mapping. Returns nil if the user has not checked in that day.
+func (s *ReadinessService) GetAdjustment(ctx context.Context, userID, programID, date string) (*readiness.Adjustment, error) {
+	checkIn, err := s.GetCheckIn(ctx, userID, date)
+	if err != nil || checkIn == nil {
+		return nil, err
+	}
+
+	mapping, err := s.GetMapping(ctx, programID)
+	if err != nil {
+		return nil, err
+	}
+
+	return readiness.NewAdjustment(checkIn.ID, checkIn.Score, mapping.Mapping), nil
+}
+
+// RecordSessionAdjustment records the adjustment applied to a workout session.
+func (s *ReadinessService) RecordSessionAdjustment(ctx context.Context, sessionID string, adj *readiness.Adjustment) error {
+	return s.recordSessionAdjustment(ctx, s.queries, sessionID, adj)
+}
+
+// RecordSessionAdjustmentTx is RecordSessionAdjustment within the caller's transaction.
+// The caller commits or rolls back tx.
+func (s *ReadinessService) RecordSessionAdjustmentTx(ctx context.Context, tx *sql.Tx, sessionID string, adj *readiness.Adjustment) error {
+	return s.recordSessionAdjustment(ctx, s.queries.WithTx(tx), sessionID, adj)
+}
+
+func (s *ReadinessService) recordSessionAdjustment(ctx context.Context, q *db.Queries, sessionID string, adj *readiness.Adjustment) error {
+	err := q.CreateWorkoutSessionReadiness(ctx, db.CreateWorkoutSessionReadinessParams{
+		SessionID:      sessionID,
+		CheckinID:      stringPtrToNullString(adj.CheckInID),
+		Score:          adj.Score,
+		LoadModifier:   adj.LoadModifier,
+		VolumeModifier: adj.VolumeModifier,
+		CreatedAt:      s.now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record session readiness: %w", err)
+	}
+	return nil
+}
+
+// GetSessionAdjustment returns the adjustment recorded on a workout session,
+// or nil if the session was started without a check-in.
+func (s *ReadinessService) GetSessionAdjustment(ctx context.Context, sessionID string) (*readiness.Adjustment, error) {
+	row, err := s.queries.GetWorkoutSessionReadiness(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get session readiness: %w", err)
+	}
+	return &readiness.Adjustment{
+		CheckInID:      nullStringToPtr(row.CheckinID),
+		Score:          row.Score,
+		LoadModifier:   row.LoadModifier,
+		VolumeModifier: row.VolumeModifier,
+	}, nil
+}
+
+func dbReadinessCheckInToDomain(row db.ReadinessCheckin) *ReadinessCheckIn {
+	createdAt, _ := time.Parse(time.RFC3339, row.CreatedAt)
+	updatedAt, _ := time.Parse(time.RFC3339, row.UpdatedAt)
+	return &ReadinessCheckIn{
+		ID:     row.ID,
+		UserID: row.UserID,
+		Date:   row.CheckinDate,
+		Inputs: readiness.Inputs{
+			SleepQuality:   nullInt64ToIntPtr(row.SleepQuality),
+			Soreness:       nullInt64ToIntPtr(row.Soreness),
+			Stress:         nullInt64ToIntPtr(row.Stress),
+			ReadinessScore: nullInt64ToIntPtr(row.ReadinessScore),
+			HRV:            nullFloat64ToPtr(row.Hrv),
+		},
+		Score:     row.Score,
+		Notes:     nullStringToPtr(row.Notes),
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}
+}
+
+func nullInt64ToIntPtr(n sql.NullInt64) *int {
+	if n.Valid {
+		v := int(n.Int64)
+		return &v
+	}
+	return nil
+}
+
+func nullFloat64ToPtr(n sql.NullFloat64) *float64 {
+	if n.Valid {
+		return &n.Float64
+	}
+	return nil
+}
+
+func intPtrToNullInt64(v *int) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*v), Valid: true}
+}
+
+func floatPtrToNullFloat64(v *float64) sql.NullFloat64 {
+	if v == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *v, Valid: true}
+}
+
+func stringPtrToNullString(v *string) sql.NullString {
+	if v == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *v, Valid: true}
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/readiness"
+)
+
+func readinessIntPtr(i int) *int {
+	return &i
+}
+
+func TestReadinessService_RecordCheckIn(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := NewReadinessService(sqlDB)
+
+	userID := uuid.New().String()
+	now := time.Now().Format(time.RFC3339)
+	if err := db.New(sqlDB).CreateUser(ctx, db.CreateUserParams{ID: userID, CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	t.Run("computes the score and replaces same-day check-ins", func(t *testing.T) {
+		first, err := svc.RecordCheckIn(ctx, userID, "2024-03-04", readiness.Inputs{ReadinessScore: readinessIntPtr(1)}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if first.Score != 0 {
+			t.Errorf("expected score 0, got %v", first.Score)
+		}
+
+		notes := "slept well after all"
+		second, err := svc.RecordCheckIn(ctx, userID, "2024-03-04", readiness.Inputs{SleepQuality: readinessIntPtr(5)}, &notes)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if second.ID != first.ID {
+			t.Errorf("expected same-day check-in to keep ID %s, got %s", first.ID, second.ID)
+		}
+		if second.Score != 100 || second.ReadinessScore != nil {
+			t.Errorf("expected replaced check-in with score 100, got %+v", second)
+		}
+		if second.Notes == nil || *second.Notes != notes {
+			t.Errorf("expected notes %q, got %v", notes, second.Notes)
+		}
+	})
+
+	t.Run("scores HRV against previous readings", func(t *testing.T) {
+		for i, hrv := range []float64{58, 62} {
+			date := time.Date(2024, 3, 10+i, 0, 0, 0, 0, time.UTC).Format(CheckInDateFormat)
+			if _, err := svc.RecordCheckIn(ctx, userID, date, readiness.Inputs{Stress: readinessIntPtr(3), HRV: &hrv}, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		// Baseline 60: 54 is 10% below and scores 0, averaged with neutral stress
+		hrv := 54.0
+		checkIn, err := svc.RecordCheckIn(ctx, userID, "2024-03-12", readiness.Inputs{Stress: readinessIntPtr(3), HRV: &hrv}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if checkIn.Score != 25 {
+			t.Errorf("expected score 25, got %v", checkIn.Score)
+		}
+	})
+
+	t.Run("lists check-ins newest first", func(t *testing.T) {
+		checkIns, total, err := svc.ListCheckIns(ctx, userID, 2, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if total != 4 {
+			t.Errorf("expected 4 check-ins, got %d", total)
+		}
+		if len(checkIns) != 2 || checkIns[0].Date != "2024-03-12" {
+			t.Errorf("expected newest check-in first, got %+v", checkIns)
+		}
+	})
+}
+
+func TestReadinessService_Adjustment(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := NewReadinessService(sqlDB)
+
+	userID := uuid.New().String()
+	now := time.Now().Format(time.RFC3339)
+	if err := db.New(sqlDB).CreateUser(ctx, db.CreateUserParams{ID: userID, CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	var programID string
+	if err := sqlDB.QueryRow("SELECT id FROM programs ORDER BY slug LIMIT 1").Scan(&programID); err != nil {
+		t.Fatalf("failed to find seeded program: %v", err)
+	}
+
+	t.Run("no check-in means no adjustment", func(t *testing.T) {
+		adj, err := svc.GetAdjustment(ctx, userID, programID, "2024-03-04")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if adj != nil {
+			t.Errorf("expected no adjustment, got %+v", adj)
+		}
+	})
+
+	// Score 25: 95% load under the default mapping
+	if _, err := svc.RecordCheckIn(ctx, userID, "2024-03-04", readiness.Inputs{Soreness: readinessIntPtr(4)}, nil); err != nil {
+		t.Fatalf("failed to record check-in: %v", err)
+	}
+
+	t.Run("uses the default mapping", func(t *testing.T) {
+		mapping, err := svc.GetMapping(ctx, programID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !mapping.IsDefault {
+			t.Error("expected default mapping")
+		}
+
+		adj, err := svc.GetAdjustment(ctx, userID, programID, "2024-03-04")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if adj == nil || adj.LoadModifier != 95 || adj.VolumeModifier != 100 {
+			t.Errorf("expected 95%% load, got %+v", adj)
+		}
+	})
+
+	t.Run("uses the program mapping once set", func(t *testing.T) {
+		_, err := svc.SetMapping(ctx, programID, readiness.Mapping{Tiers: []readiness.Tier{
+			{MinScore: 0, LoadModifier: 90, VolumeModifier: 50},
+			{MinScore: 50, LoadModifier: 100, VolumeModifier: 100},
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		mapping, err := svc.GetMapping(ctx, programID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mapping.IsDefault || mapping.Tiers[0].MinScore != 50 {
+			t.Errorf("expected stored mapping ordered by minScore, got %+v", mapping)
+		}
+
+		adj, err := svc.GetAdjustment(ctx, userID, programID, "2024-03-04")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if adj.LoadModifier != 90 || adj.VolumeModifier != 50 {
+			t.Errorf("expected 90%% load and 50%% volume, got %+v", adj)
+		}
+
+		if err := svc.DeleteMapping(ctx, programID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		mapping, err = svc.GetMapping(ctx, programID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !mapping.IsDefault {
+			t.Error("expected default mapping after delete")
+		}
+	})
+}
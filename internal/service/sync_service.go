@@ -0,0 +1,709 @@
+// Package service provides application service layer implementations.
+// This file implements the SyncService which applies batches of mutations recorded by
+// offline clients and returns the changes the client has not yet seen.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/event"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/liftmax"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/loggedset"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/userprogramstate"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/workoutsession"
+	"github.com/waynenilsen/power-pro-v3/internal/outbox"
+	"github.com/waynenilsen/power-pro-v3/internal/repository"
+)
+
+// MaxSyncMutations is the maximum number of mutations accepted in one sync request.
+const MaxSyncMutations = 100
+
+// Errors for sync operations.
+var (
+	ErrTooManySyncMutations = errors.New("too many mutations in sync request")
+)
+
+// SyncMutationType identifies the operation a client mutation performs.
+type SyncMutationType string
+
+const (
+	SyncStartSession  SyncMutationType = "START_SESSION"
+	SyncLogSets       SyncMutationType = "LOG_SETS"
+	SyncFinishSession SyncMutationType = "FINISH_SESSION"
+	SyncSetMax        SyncMutationType = "SET_MAX"
+)
+
+// ValidSyncMutationTypes contains the mutation types the sync endpoint accepts.
+var ValidSyncMutationTypes = map[SyncMutationType]bool{
+	SyncStartSession:  true,
+	SyncLogSets:       true,
+	SyncFinishSession: true,
+	SyncSetMax:        true,
+}
+
+// SyncMutationStatus is the outcome of applying a client mutation.
+type SyncMutationStatus string
+
+const (
+	// SyncApplied means the mutation was applied.
+	SyncApplied SyncMutationStatus = "APPLIED"
+	// SyncDuplicate means the entity the mutation creates already exists; nothing changed.
+	SyncDuplicate SyncMutationStatus = "DUPLICATE"
+	// SyncConflict means the mutation conflicts with server state and the server state was kept.
+	SyncConflict SyncMutationStatus = "CONFLICT"
+	// SyncRejected means the mutation is invalid and can never be applied.
+	SyncRejected SyncMutationStatus = "REJECTED"
+)
+
+// Codes explaining why a mutation was not applied.
+const (
+	SyncCodeSessionInProgress      = "SESSION_IN_PROGRESS"
+	SyncCodeSessionAlreadyFinished = "SESSION_ALREADY_FINISHED"
+	SyncCodeSessionAbandoned       = "SESSION_ABANDONED"
+	SyncCodeSetAfterFinish         = "SET_AFTER_FINISH"
+	SyncCodeMaxAlreadyExists       = "MAX_ALREADY_EXISTS"
+	SyncCodeSessionNotFound        = "SESSION_NOT_FOUND"
+	SyncCodeLiftNotFound           = "LIFT_NOT_FOUND"
+	SyncCodeNotEnrolled            = "NOT_ENROLLED"
+	SyncCodeInvalidEnrollmentState = "INVALID_ENROLLMENT_STATE"
+	SyncCodeIDConflict             = "ID_CONFLICT"
+	SyncCodeValidation             = "VALIDATION_ERROR"
+)
+
+// SyncSet is a set recorded offline, as sent in a LOG_SETS mutation.
+type SyncSet struct {
+	// ID is the client-generated set ID.
+	ID             string
+	PrescriptionID string
+	LiftID         string
+	SetNumber      int
+	Weight         float64
+	TargetReps     int
+	RepsPerformed  int
+	IsAMRAP        bool
+	RPE            *float64
+	// LoggedAt is when the set was performed. Defaults to the mutation's client timestamp.
+	LoggedAt *time.Time
+}
+
+// SyncMutation is a single client mutation. Exactly the payload matching Type is used.
+type SyncMutation struct {
+	// ID is the client-generated mutation ID used for idempotency.
+	ID              string
+	Type            SyncMutationType
+	ClientTimestamp time.Time
+	// SessionID is the client-generated ID of the session to start, or the session
+	// the sets are logged to or that is finished.
+	SessionID string
+	Sets      []SyncSet
+	// MaxID, LiftID, Value and EffectiveDate describe the 1RM recorded by SET_MAX.
+	MaxID         string
+	LiftID        string
+	Value         float64
+	EffectiveDate *time.Time
+}
+
+// SyncLoggedSet is a set created by a LOG_SETS mutation with the records it achieved.
+type SyncLoggedSet struct {
+	Set     *loggedset.LoggedSet
+	Records []PersonalRecord
+}
+
+// SyncMutationResult is the outcome of one mutation.
+type SyncMutationResult struct {
+	MutationID string
+	Type       SyncMutationType
+	Status     SyncMutationStatus
+	Code       string
+	Message    string
+	// EntityID is the session or lift max the mutation applied to. For conflicts it
+	// identifies the server entity that was kept.
+	EntityID string
+	// Replayed is true when the mutation was already processed by an earlier sync and
+	// the recorded outcome is returned instead.
+	Replayed bool
+
+	// The fields below are set only when the mutation was applied by this request.
+	ProgramID string
+	Session   *workoutsession.WorkoutSession
+	Sets      []SyncLoggedSet
+	LiftMax   *liftmax.LiftMax
+}
+
+// SyncChanges contains the server state changed since a cursor.
+type SyncChanges struct {
+	Sessions    []*workoutsession.WorkoutSession
+	Sets        []loggedset.LoggedSet
+	DeletedSets []repository.LoggedSetDeletion
+	LiftMaxes   []liftmax.LiftMax
+}
+
+// SyncResult is the response to a sync request.
+type SyncResult struct {
+	Results []SyncMutationResult
+	Changes SyncChanges
+	// Cursor is passed on the next sync to receive only later changes.
+	Cursor time.Time
+}
+
+// SyncEventsFunc builds the state events for a mutation applied by a sync.
+type SyncEventsFunc func(userID string, res *SyncMutationResult) []event.StateEvent
+
+// SyncService applies offline client mutations and builds the change feed.
+type SyncService struct {
+	sqlDB            *sql.DB
+	outbox           *outbox.Service
+	tx               *sql.Tx
+	queries          *db.Queries
+	sessionRepo      *repository.WorkoutSessionRepository
+	setRepo          *repository.LoggedSetRepository
+	stateRepo        *repository.UserProgramStateRepository
+	liftRepo         *repository.LiftRepository
+	liftMaxRepo      *repository.LiftMaxRepository
+	prService        *PersonalRecordService
+	failureService   *FailureService
+	readinessService *ReadinessService
+	now              func() time.Time
+}
+
+// NewSyncService creates a new SyncService.
+// outboxService stores the events of applied mutations in the mutations' transactions.
+// failureService and readinessService are optional; when nil, synced sets are not
+// tracked for failures and readiness adjustments are not recorded on synced sessions.
+func NewSyncService(sqlDB *sql.DB, outboxService *outbox.Service, prService *PersonalRecordService, failureService *FailureService, readinessService *ReadinessService) *SyncService {
+	return &SyncService{
+		sqlDB:            sqlDB,
+		outbox:           outboxService,
+		queries:          db.New(sqlDB),
+		sessionRepo:      repository.NewWorkoutSessionRepository(sqlDB),
+		setRepo:          repository.NewLoggedSetRepository(sqlDB),
+		stateRepo:        repository.NewUserProgramStateRepository(sqlDB),
+		liftRepo:         repository.NewLiftRepository(sqlDB),
+		liftMaxRepo:      repository.NewLiftMaxRepository(sqlDB),
+		prService:        prService,
+		failureService:   failureService,
+		readinessService: readinessService,
+		now:              time.Now,
+	}
+}
+
+// withTx returns a copy of the service whose repositories and queries run in tx.
+func (s *SyncService) withTx(tx *sql.Tx) *SyncService {
+	txService := *s
+	txService.tx = tx
+	txService.queries = s.queries.WithTx(tx)
+	txService.sessionRepo = s.sessionRepo.WithTx(tx)
+	txService.setRepo = s.setRepo.WithTx(tx)
+	txService.stateRepo = s.stateRepo.WithTx(tx)
+	txService.liftRepo = s.liftRepo.WithTx(tx)
+	txService.liftMaxRepo = s.liftMaxRepo.WithTx(tx)
+	return &txService
+}
+
+// Sync applies the mutations in order and returns their outcomes together with the
+// changes made since cursor. A nil cursor returns the user's full state. events builds
+// the state events stored for each mutation applied by this sync; it may be nil.
+//
+// Each mutation is applied independently: a conflict or rejection does not stop later
+// mutations. Mutations are idempotent by ID; resending a processed mutation returns its
+// recorded outcome. The change feed is delivered at least once, so entities may repeat
+// across syncs and clients should upsert them by ID.
+func (s *SyncService) Sync(ctx context.Context, userID string, cursor *time.Time, mutations []SyncMutation, events SyncEventsFunc) (*SyncResult, error) {
+	if len(mutations) > MaxSyncMutations {
+		return nil, wrapErrorString(ErrTooManySyncMutations, fmt.Sprintf("at most %d are allowed", MaxSyncMutations))
+	}
+
+	result := &SyncResult{Results: make([]SyncMutationResult, 0, len(mutations))}
+	for _, m := range mutations {
+		res, err := s.processMutation(ctx, userID, m, events)
+		if err != nil {
+			return nil, err
+		}
+		result.Results = append(result.Results, *res)
+	}
+
+	// The cursor is taken before reading the feed and truncated to the second that
+	// change queries compare at, so a change is never skipped between syncs.
+	result.Cursor = s.now().Truncate(time.Second)
+
+	var since time.Time
+	if cursor != nil {
+		since = *cursor
+	}
+	changes, err := s.changesSince(userID, since)
+	if err != nil {
+		return nil, err
+	}
+	result.Changes = *changes
+
+	return result, nil
+}
+
+// processMutation applies one mutation, or returns the recorded outcome of a mutation
+// processed by an earlier sync. The mutation's changes, its recorded outcome and its
+// events are written in one transaction, so a mutation is applied in full or not at all.
+func (s *SyncService) processMutation(ctx context.Context, userID string, m SyncMutation, events SyncEventsFunc) (*SyncMutationResult, error) {
+	var res *SyncMutationResult
+	err := s.outbox.Transact(ctx, func(tx *sql.Tx) ([]event.StateEvent, error) {
+		var err error
+		res, err = s.withTx(tx).applyMutation(ctx, userID, m)
+		if err != nil {
+			return nil, err
+		}
+		if events == nil || res.Status != SyncApplied || res.Replayed {
+			return nil, nil
+		}
+		return events(userID, res), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Failure tracking is best-effort, as it is for sets logged online
+	if s.failureService != nil {
+		for _, set := range res.Sets {
+			_, _ = s.failureService.ProcessLoggedSet(ctx, set.Set)
+		}
+	}
+
+	return res, nil
+}
+
+// applyMutation applies one mutation and records its outcome. It is called on a
+// service scoped to the mutation's transaction.
+func (s *SyncService) applyMutation(ctx context.Context, userID string, m SyncMutation) (*SyncMutationResult, error) {
+	res := &SyncMutationResult{MutationID: m.ID, Type: m.Type}
+
+	if _, err := uuid.Parse(m.ID); err != nil {
+		res.reject(SyncCodeValidation, "mutation id must be a valid UUID")
+		return res, nil
+	}
+	if !ValidSyncMutationTypes[m.Type] {
+		res.reject(SyncCodeValidation, "unknown mutation type: "+string(m.Type))
+		return res, nil
+	}
+
+	recorded, err := s.queries.GetSyncMutation(ctx, m.ID)
+	if err == nil {
+		if recorded.UserID != userID {
+			res.reject(SyncCodeIDConflict, "mutation id is already in use")
+			return res, nil
+		}
+		res.Status = SyncMutationStatus(recorded.Status)
+		res.Code = recorded.Code.String
+		res.Message = recorded.Message.String
+		res.EntityID = recorded.EntityID.String
+		res.Replayed = true
+		return res, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, wrapError("failed to get sync mutation", err)
+	}
+
+	// Client clocks cannot place changes in the future
+	now := s.now()
+	clientTime := m.ClientTimestamp
+	if clientTime.IsZero() || clientTime.After(now) {
+		clientTime = now
+	}
+
+	switch m.Type {
+	case SyncStartSession:
+		err = s.startSession(ctx, userID, m, clientTime, res)
+	case SyncLogSets:
+		err = s.logSets(ctx, userID, m, clientTime, res)
+	case SyncFinishSession:
+		err = s.finishSession(ctx, userID, m, clientTime, res)
+	case SyncSetMax:
+		err = s.setMax(ctx, userID, m, clientTime, res)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.queries.CreateSyncMutation(ctx, db.CreateSyncMutationParams{
+		ID:              m.ID,
+		UserID:          userID,
+		MutationType:    string(m.Type),
+		Status:          string(res.Status),
+		Code:            stringToNullString(res.Code),
+		Message:         stringToNullString(res.Message),
+		EntityID:        stringToNullString(res.EntityID),
+		ClientTimestamp: clientTime.Format(time.RFC3339),
+		CreatedAt:       now.Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, wrapError("failed to record sync mutation", err)
+	}
+
+	return res, nil
+}
+
+// startSession starts a session with the client's ID for the user's current program day.
+// Only one session can be in progress: if another session is already in progress, the
+// mutation conflicts and the server's session is kept.
+func (s *SyncService) startSession(ctx context.Context, userID string, m SyncMutation, startedAt time.Time, res *SyncMutationResult) error {
+	if _, err := uuid.Parse(m.SessionID); err != nil {
+		res.reject(SyncCodeValidation, "sessionId must be a valid UUID")
+		return nil
+	}
+	res.EntityID = m.SessionID
+
+	existing, _, err := s.getUserSession(userID, m.SessionID, res)
+	if err != nil || res.Status != "" {
+		return err
+	}
+	if existing != nil {
+		res.Status = SyncDuplicate
+		return nil
+	}
+
+	enrollment, err := s.stateRepo.GetEnrollmentWithProgram(userID)
+	if err != nil {
+		return wrapError("failed to get enrollment", err)
+	}
+	if enrollment == nil {
+		res.reject(SyncCodeNotEnrolled, "user is not enrolled in a program")
+		return nil
+	}
+	if enrollment.State.EnrollmentStatus != userprogramstate.EnrollmentStatusActive {
+		res.reject(SyncCodeInvalidEnrollmentState, "cannot start workout while enrollment is "+string(enrollment.State.EnrollmentStatus))
+		return nil
+	}
+
+	active, err := s.sessionRepo.GetActiveByUserProgramStateID(enrollment.State.ID)
+	if err != nil {
+		return wrapError("failed to check for active session", err)
+	}
+	if active != nil {
+		res.conflict(SyncCodeSessionInProgress, "another workout session is already in progress", active.ID)
+		return nil
+	}
+
+	dayIndex := 0
+	if enrollment.State.CurrentDayIndex != nil {
+		dayIndex = *enrollment.State.CurrentDayIndex
+	}
+	session, result := workoutsession.NewWorkoutSession(workoutsession.NewWorkoutSessionInput{
+		UserProgramStateID: enrollment.State.ID,
+		WeekNumber:         enrollment.State.CurrentWeek,
+		DayIndex:           dayIndex,
+	}, m.SessionID)
+	if !result.Valid {
+		res.reject(SyncCodeValidation, joinErrors(result.Errors))
+		return nil
+	}
+	session.StartedAt = startedAt
+
+	if err := s.sessionRepo.Create(session); err != nil {
+		return wrapError("failed to create workout session", err)
+	}
+
+	// Record the readiness adjustment from the check-in for the day the session started
+	if s.readinessService != nil {
+		adj, err := s.readinessService.GetAdjustment(ctx, userID, enrollment.State.ProgramID, startedAt.Format(CheckInDateFormat))
+		if err != nil {
+			return wrapError("failed to resolve readiness adjustment", err)
+		}
+		if adj != nil {
+			if err := s.readinessService.RecordSessionAdjustmentTx(ctx, s.tx, session.ID, adj); err != nil {
+				return wrapError("failed to record readiness adjustment", err)
+			}
+		}
+	}
+
+	res.Status = SyncApplied
+	res.ProgramID = enrollment.State.ProgramID
+	res.Session = session
+	return nil
+}
+
+// logSets logs sets to a session. Sets can be added to an in-progress session, or to a
+// session already finished on another device when they were performed before it was
+// finished. Sets whose IDs already exist are skipped.
+func (s *SyncService) logSets(ctx context.Context, userID string, m SyncMutation, clientTime time.Time, res *SyncMutationResult) error {
+	session, state, err := s.getUserSession(userID, m.SessionID, res)
+	if err != nil || res.Status != "" {
+		return err
+	}
+	if session == nil {
+		res.reject(SyncCodeSessionNotFound, "workout session not found")
+		return nil
+	}
+	res.EntityID = session.ID
+	if len(m.Sets) == 0 {
+		res.reject(SyncCodeValidation, "at least one set is required")
+		return nil
+	}
+	if session.Status == workoutsession.StatusAbandoned {
+		res.conflict(SyncCodeSessionAbandoned, "workout session was abandoned", session.ID)
+		return nil
+	}
+
+	// Validate the whole batch before writing so a mutation is applied in full or not at all
+	newSets := make([]*loggedset.LoggedSet, 0, len(m.Sets))
+	for i, in := range m.Sets {
+		if _, err := uuid.Parse(in.ID); err != nil {
+			res.reject(SyncCodeValidation, fmt.Sprintf("set %d: id must be a valid UUID", i+1))
+			return nil
+		}
+		existing, err := s.setRepo.GetByID(in.ID)
+		if err != nil {
+			return wrapError("failed to get logged set", err)
+		}
+		if existing != nil {
+			if existing.UserID != userID || existing.SessionID != session.ID {
+				res.reject(SyncCodeIDConflict, fmt.Sprintf("set %d: id is already in use", i+1))
+				return nil
+			}
+			continue
+		}
+
+		ls, result := loggedset.NewLoggedSet(loggedset.CreateLoggedSetInput{
+			UserID:         userID,
+			SessionID:      session.ID,
+			PrescriptionID: in.PrescriptionID,
+			LiftID:         in.LiftID,
+			SetNumber:      in.SetNumber,
+			Weight:         in.Weight,
+			TargetReps:     in.TargetReps,
+			RepsPerformed:  in.RepsPerformed,
+			IsAMRAP:        in.IsAMRAP,
+			RPE:            in.RPE,
+		}, in.ID)
+		if !result.Valid {
+			res.reject(SyncCodeValidation, fmt.Sprintf("set %d: %s", i+1, joinErrors(result.Errors)))
+			return nil
+		}
+
+		loggedAt := clientTime
+		if in.LoggedAt != nil && !in.LoggedAt.IsZero() && in.LoggedAt.Before(clientTime) {
+			loggedAt = *in.LoggedAt
+		}
+		if session.Status == workoutsession.StatusCompleted && session.FinishedAt != nil && loggedAt.After(*session.FinishedAt) {
+			res.conflict(SyncCodeSetAfterFinish, fmt.Sprintf("set %d was performed after the session was finished", i+1), session.ID)
+			return nil
+		}
+		ls.CreatedAt = loggedAt
+		newSets = append(newSets, ls)
+	}
+
+	if len(newSets) == 0 {
+		res.Status = SyncDuplicate
+		return nil
+	}
+
+	for _, ls := range newSets {
+		var records []PersonalRecord
+		if s.prService != nil {
+			records, err = s.prService.LogSetWithRecordsTx(ctx, s.tx, ls)
+		} else {
+			err = s.setRepo.Create(ls)
+		}
+		if err != nil {
+			return wrapError("failed to create logged set", err)
+		}
+		res.Sets = append(res.Sets, SyncLoggedSet{Set: ls, Records: records})
+	}
+
+	res.Status = SyncApplied
+	res.ProgramID = state.ProgramID
+	return nil
+}
+
+// finishSession completes an in-progress session at the client's finish time. When the
+// session was already finished, for example on another device, the server's finish is
+// kept and the mutation conflicts.
+func (s *SyncService) finishSession(ctx context.Context, userID string, m SyncMutation, finishedAt time.Time, res *SyncMutationResult) error {
+	session, state, err := s.getUserSession(userID, m.SessionID, res)
+	if err != nil || res.Status != "" {
+		return err
+	}
+	if session == nil {
+		res.reject(SyncCodeSessionNotFound, "workout session not found")
+		return nil
+	}
+	res.EntityID = session.ID
+
+	if err := session.Complete(); err != nil {
+		switch err {
+		case workoutsession.ErrAlreadyCompleted:
+			res.conflict(SyncCodeSessionAlreadyFinished, "workout session was already finished", session.ID)
+		case workoutsession.ErrNotInProgress:
+			res.conflict(SyncCodeSessionAbandoned, "workout session was abandoned", session.ID)
+		default:
+			return wrapError("failed to complete session", err)
+		}
+		return nil
+	}
+	if finishedAt.Before(session.StartedAt) {
+		finishedAt = session.StartedAt
+	}
+	session.FinishedAt = &finishedAt
+
+	if err := s.sessionRepo.Complete(session); err != nil {
+		return wrapError("failed to save completed session", err)
+	}
+
+	res.Status = SyncApplied
+	res.ProgramID = state.ProgramID
+	res.Session = session
+	return nil
+}
+
+// setMax records a 1RM with the client's ID and derives the Training Max from it, as
+// creating a lift max online does. A max for the same lift and effective date already on
+// the server is kept and the mutation conflicts.
+func (s *SyncService) setMax(ctx context.Context, userID string, m SyncMutation, clientTime time.Time, res *SyncMutationResult) error {
+	if _, err := uuid.Parse(m.MaxID); err != nil {
+		res.reject(SyncCodeValidation, "liftMax id must be a valid UUID")
+		return nil
+	}
+	res.EntityID = m.MaxID
+
+	existing, err := s.liftMaxRepo.GetByID(m.MaxID)
+	if err != nil {
+		return wrapError("failed to get lift max", err)
+	}
+	if existing != nil {
+		if existing.UserID != userID {
+			res.reject(SyncCodeIDConflict, "liftMax id is already in use")
+			return nil
+		}
+		res.Status = SyncDuplicate
+		return nil
+	}
+
+	lift, err := s.liftRepo.GetByID(m.LiftID)
+	if err != nil {
+		return wrapError("failed to verify lift", err)
+	}
+	if lift == nil {
+		res.reject(SyncCodeLiftNotFound, "lift not found")
+		return nil
+	}
+
+	effectiveDate := clientTime
+	if m.EffectiveDate != nil && !m.EffectiveDate.IsZero() {
+		effectiveDate = *m.EffectiveDate
+	}
+	newMax, result := liftmax.CreateLiftMax(liftmax.CreateLiftMaxInput{
+		UserID:        userID,
+		LiftID:        m.LiftID,
+		Type:          liftmax.OneRM,
+		Value:         m.Value,
+		EffectiveDate: &effectiveDate,
+	}, m.MaxID, s.liftMaxRepo)
+	if !result.Valid {
+		res.reject(SyncCodeValidation, joinErrors(result.Errors))
+		return nil
+	}
+
+	exists, err := s.liftMaxRepo.UniqueConstraintExists(userID, m.LiftID, string(liftmax.OneRM), newMax.EffectiveDate, nil)
+	if err != nil {
+		return wrapError("failed to check uniqueness", err)
+	}
+	if exists {
+		res.conflict(SyncCodeMaxAlreadyExists, "a 1RM for this lift and effective date already exists", "")
+		return nil
+	}
+
+	if err := s.liftMaxRepo.Create(newMax); err != nil {
+		return wrapError("failed to create lift max", err)
+	}
+	// The 1RM is recorded even if the Training Max cannot be derived, as online
+	if err := SyncTrainingMax(s.liftMaxRepo, newMax); err != nil {
+		res.Message = "failed to auto-calculate Training Max: " + err.Error()
+	}
+
+	res.Status = SyncApplied
+	res.LiftMax = newMax
+	return nil
+}
+
+// getUserSession loads a session owned by the user. A session owned by another user is
+// rejected as an ID conflict; a missing session is returned as nil.
+func (s *SyncService) getUserSession(userID, sessionID string, res *SyncMutationResult) (*workoutsession.WorkoutSession, *userprogramstate.UserProgramState, error) {
+	if sessionID == "" {
+		res.reject(SyncCodeValidation, "sessionId is required")
+		return nil, nil, nil
+	}
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return nil, nil, wrapError("failed to get workout session", err)
+	}
+	if session == nil {
+		return nil, nil, nil
+	}
+	state, err := s.stateRepo.GetByID(session.UserProgramStateID)
+	if err != nil {
+		return nil, nil, wrapError("failed to get program state", err)
+	}
+	if state == nil || state.UserID != userID {
+		res.reject(SyncCodeIDConflict, "sessionId is already in use")
+		return nil, nil, nil
+	}
+	return session, state, nil
+}
+
+// changesSince collects the user's sessions, sets and lift maxes changed since the cursor.
+func (s *SyncService) changesSince(userID string, since time.Time) (*SyncChanges, error) {
+	sessions, err := s.sessionRepo.ListChangedSince(userID, since)
+	if err != nil {
+		return nil, err
+	}
+	sets, err := s.setRepo.ListChangedSince(userID, since)
+	if err != nil {
+		return nil, err
+	}
+	maxes, err := s.liftMaxRepo.ListChangedSince(userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.setRepo.ListDeletedSince(userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SyncChanges{
+		Sessions:    sessions,
+		Sets:        sets,
+		DeletedSets: deleted,
+		LiftMaxes:   maxes,
+	}, nil
+}
+
+func (r *SyncMutationResult) reject(code, message string) {
+	r.Status = SyncRejected
+	r.Code = code
+	r.Message = message
+}
+
+func (r *SyncMutationResult) conflict(code, message, entityID string) {
+	r.Status = SyncConflict
+	r.Code = code
+	r.Message = message
+	if entityID != "" {
+		r.EntityID = entityID
+	}
+}
+
+func joinErrors(errs []error) string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func stringToNullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
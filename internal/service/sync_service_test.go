@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/event"
+	"github.com/waynenilsen/power-pro-v3/internal/outbox"
+)
+
+func newTestSyncService(sqlDB *sql.DB) *SyncService {
+	return NewSyncService(sqlDB, outbox.NewService(sqlDB, outbox.NewSQLiteRepository(sqlDB)), NewPersonalRecordService(sqlDB), NewFailureService(sqlDB, GetDefaultFactory()), nil)
+}
+
+// syncTestSet returns a squat set for a LOG_SETS mutation.
+func syncTestSet(data testData, setNumber, reps int, loggedAt *time.Time) SyncSet {
+	return SyncSet{
+		ID:             uuid.New().String(),
+		PrescriptionID: uuid.New().String(),
+		LiftID:         data.SquatID,
+		SetNumber:      setNumber,
+		Weight:         225,
+		TargetReps:     5,
+		RepsPerformed:  reps,
+		LoggedAt:       loggedAt,
+	}
+}
+
+func mustSync(t *testing.T, svc *SyncService, userID string, cursor *time.Time, mutations ...SyncMutation) *SyncResult {
+	t.Helper()
+	result, err := svc.Sync(context.Background(), userID, cursor, mutations, nil)
+	if err != nil {
+		t.Fatalf("unexpected sync error: %v", err)
+	}
+	if len(result.Results) != len(mutations) {
+		t.Fatalf("expected %d results, got %d", len(mutations), len(result.Results))
+	}
+	return result
+}
+
+func expectSyncStatus(t *testing.T, res SyncMutationResult, status SyncMutationStatus, code string) {
+	t.Helper()
+	if res.Status != status || res.Code != code {
+		t.Errorf("mutation %s: expected %s/%q, got %s/%q (%s)", res.Type, status, code, res.Status, res.Code, res.Message)
+	}
+}
+
+// TestSyncService_OfflineWorkout tests syncing a workout recorded offline and replaying it.
+func TestSyncService_OfflineWorkout(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	svc := newTestSyncService(sqlDB)
+
+	started := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	setTime := started.Add(10 * time.Minute)
+	finished := started.Add(time.Hour)
+	sessionID := uuid.New().String()
+
+	mutations := []SyncMutation{
+		{ID: uuid.New().String(), Type: SyncStartSession, ClientTimestamp: started, SessionID: sessionID},
+		{ID: uuid.New().String(), Type: SyncLogSets, ClientTimestamp: setTime, SessionID: sessionID,
+			Sets: []SyncSet{syncTestSet(data, 1, 5, nil), syncTestSet(data, 2, 5, nil)}},
+		{ID: uuid.New().String(), Type: SyncFinishSession, ClientTimestamp: finished, SessionID: sessionID},
+	}
+
+	result := mustSync(t, svc, data.UserID, nil, mutations...)
+	for _, res := range result.Results {
+		expectSyncStatus(t, res, SyncApplied, "")
+		if res.EntityID != sessionID {
+			t.Errorf("expected entity %s, got %s", sessionID, res.EntityID)
+		}
+	}
+
+	session, err := svc.sessionRepo.GetByID(sessionID)
+	if err != nil || session == nil {
+		t.Fatalf("expected synced session, got %v (%v)", session, err)
+	}
+	if !session.StartedAt.Equal(started) || session.FinishedAt == nil || !session.FinishedAt.Equal(finished) {
+		t.Errorf("expected client start and finish times, got %v - %v", session.StartedAt, session.FinishedAt)
+	}
+	sets, err := svc.setRepo.ListBySession(sessionID)
+	if err != nil {
+		t.Fatalf("failed to list sets: %v", err)
+	}
+	if len(sets) != 2 || !sets[0].CreatedAt.Equal(setTime) {
+		t.Fatalf("expected 2 sets logged at %v, got %+v", setTime, sets)
+	}
+	if len(result.Changes.Sessions) != 1 || len(result.Changes.Sets) != 2 {
+		t.Errorf("expected the synced session and sets in the change feed, got %d sessions, %d sets",
+			len(result.Changes.Sessions), len(result.Changes.Sets))
+	}
+
+	t.Run("replay returns recorded outcomes", func(t *testing.T) {
+		replay := mustSync(t, svc, data.UserID, &result.Cursor, mutations...)
+		for _, res := range replay.Results {
+			expectSyncStatus(t, res, SyncApplied, "")
+			if !res.Replayed {
+				t.Errorf("expected %s to be replayed", res.Type)
+			}
+		}
+		sets, _ := svc.setRepo.ListBySession(sessionID)
+		if len(sets) != 2 {
+			t.Errorf("expected replay not to log sets again, got %d sets", len(sets))
+		}
+	})
+
+	t.Run("resent sets with a new mutation id are duplicates", func(t *testing.T) {
+		resend := mutations[1]
+		resend.ID = uuid.New().String()
+		res := mustSync(t, svc, data.UserID, nil, resend).Results[0]
+		expectSyncStatus(t, res, SyncDuplicate, "")
+	})
+
+	t.Run("mutation id of another user", func(t *testing.T) {
+		res := mustSync(t, svc, uuid.New().String(), nil, mutations[0]).Results[0]
+		expectSyncStatus(t, res, SyncRejected, SyncCodeIDConflict)
+	})
+}
+
+// TestSyncService_Conflicts tests the conflict rules for sessions changed on two devices.
+func TestSyncService_Conflicts(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	svc := newTestSyncService(sqlDB)
+
+	started := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	finished := started.Add(time.Hour)
+	sessionID := uuid.New().String()
+
+	mustSync(t, svc, data.UserID, nil,
+		SyncMutation{ID: uuid.New().String(), Type: SyncStartSession, ClientTimestamp: started, SessionID: sessionID})
+
+	t.Run("session started on a second device", func(t *testing.T) {
+		res := mustSync(t, svc, data.UserID, nil,
+			SyncMutation{ID: uuid.New().String(), Type: SyncStartSession, ClientTimestamp: started, SessionID: uuid.New().String()}).Results[0]
+		expectSyncStatus(t, res, SyncConflict, SyncCodeSessionInProgress)
+		if res.EntityID != sessionID {
+			t.Errorf("expected conflict to identify session %s, got %s", sessionID, res.EntityID)
+		}
+	})
+
+	mustSync(t, svc, data.UserID, nil,
+		SyncMutation{ID: uuid.New().String(), Type: SyncFinishSession, ClientTimestamp: finished, SessionID: sessionID})
+
+	t.Run("session finished on a second device", func(t *testing.T) {
+		res := mustSync(t, svc, data.UserID, nil,
+			SyncMutation{ID: uuid.New().String(), Type: SyncFinishSession, ClientTimestamp: finished.Add(5 * time.Minute), SessionID: sessionID}).Results[0]
+		expectSyncStatus(t, res, SyncConflict, SyncCodeSessionAlreadyFinished)
+		session, _ := svc.sessionRepo.GetByID(sessionID)
+		if !session.FinishedAt.Equal(finished) {
+			t.Errorf("expected the first finish time to be kept, got %v", session.FinishedAt)
+		}
+	})
+
+	t.Run("sets performed before the finish are accepted", func(t *testing.T) {
+		before := finished.Add(-time.Minute)
+		res := mustSync(t, svc, data.UserID, nil,
+			SyncMutation{ID: uuid.New().String(), Type: SyncLogSets, ClientTimestamp: time.Now(), SessionID: sessionID,
+				Sets: []SyncSet{syncTestSet(data, 1, 5, &before)}}).Results[0]
+		expectSyncStatus(t, res, SyncApplied, "")
+	})
+
+	t.Run("sets performed after the finish conflict", func(t *testing.T) {
+		after := finished.Add(time.Minute)
+		res := mustSync(t, svc, data.UserID, nil,
+			SyncMutation{ID: uuid.New().String(), Type: SyncLogSets, ClientTimestamp: time.Now(), SessionID: sessionID,
+				Sets: []SyncSet{syncTestSet(data, 2, 5, &after)}}).Results[0]
+		expectSyncStatus(t, res, SyncConflict, SyncCodeSetAfterFinish)
+		sets, _ := svc.setRepo.ListBySession(sessionID)
+		if len(sets) != 1 {
+			t.Errorf("expected the conflicting set not to be logged, got %d sets", len(sets))
+		}
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		res := mustSync(t, svc, data.UserID, nil,
+			SyncMutation{ID: uuid.New().String(), Type: SyncFinishSession, ClientTimestamp: time.Now(), SessionID: uuid.New().String()}).Results[0]
+		expectSyncStatus(t, res, SyncRejected, SyncCodeSessionNotFound)
+	})
+
+	t.Run("max set on two devices", func(t *testing.T) {
+		effective := time.Now().Add(-time.Hour).Truncate(time.Second)
+		first := SyncMutation{ID: uuid.New().String(), Type: SyncSetMax, ClientTimestamp: effective,
+			MaxID: uuid.New().String(), LiftID: data.BenchID, Value: 250}
+		second := first
+		second.ID = uuid.New().String()
+		second.MaxID = uuid.New().String()
+		second.Value = 260
+
+		result := mustSync(t, svc, data.UserID, nil, first, second)
+		expectSyncStatus(t, result.Results[0], SyncApplied, "")
+		expectSyncStatus(t, result.Results[1], SyncConflict, SyncCodeMaxAlreadyExists)
+
+		tm, err := svc.liftMaxRepo.GetCurrentMax(data.UserID, data.BenchID, "TRAINING_MAX")
+		if err != nil || tm == nil {
+			t.Fatalf("expected a derived training max, got %v (%v)", tm, err)
+		}
+		if tm.Value != 225 {
+			t.Errorf("expected training max 225, got %f", tm.Value)
+		}
+	})
+}
+
+// TestSyncService_ChangeFeed tests that the change feed returns only changes since the cursor,
+// including sets synced late with an earlier performed time.
+func TestSyncService_ChangeFeed(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	svc := newTestSyncService(sqlDB)
+	queries := db.New(sqlDB)
+	ctx := context.Background()
+
+	old := time.Now().Add(-48 * time.Hour).Format(time.RFC3339)
+	oldMaxID := uuid.New().String()
+	err := queries.CreateLiftMax(ctx, db.CreateLiftMaxParams{
+		ID:            oldMaxID,
+		UserID:        data.UserID,
+		LiftID:        data.DeadliftID,
+		Type:          "ONE_RM",
+		Value:         400,
+		EffectiveDate: old,
+		CreatedAt:     old,
+		UpdatedAt:     old,
+	})
+	if err != nil {
+		t.Fatalf("failed to create lift max: %v", err)
+	}
+
+	full := mustSync(t, svc, data.UserID, nil)
+	if !containsLiftMax(full.Changes, oldMaxID) {
+		t.Errorf("expected full sync to include lift max %s", oldMaxID)
+	}
+
+	cursor := time.Now().Add(-24 * time.Hour)
+	sessionID := createTestWorkoutSession(t, sqlDB, data, "IN_PROGRESS")
+	performed := time.Now().Add(-36 * time.Hour)
+	set := syncTestSet(data, 1, 5, &performed)
+
+	result := mustSync(t, svc, data.UserID, &cursor,
+		SyncMutation{ID: uuid.New().String(), Type: SyncLogSets, ClientTimestamp: time.Now(), SessionID: sessionID, Sets: []SyncSet{set}})
+	expectSyncStatus(t, result.Results[0], SyncApplied, "")
+
+	if containsLiftMax(result.Changes, oldMaxID) {
+		t.Errorf("expected lift max %s changed before the cursor to be excluded", oldMaxID)
+	}
+	found := false
+	for _, ls := range result.Changes.Sets {
+		if ls.ID == set.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected set %s synced after the cursor to be included, got %+v", set.ID, result.Changes.Sets)
+	}
+	if result.Cursor.Before(cursor) {
+		t.Errorf("expected the returned cursor to advance, got %v", result.Cursor)
+	}
+}
+
+func containsLiftMax(changes SyncChanges, id string) bool {
+	for _, m := range changes.LiftMaxes {
+		if m.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// TestSyncService_MutationIsAtomic tests that a mutation that fails part way leaves no sets
+// and no recorded outcome behind.
+func TestSyncService_MutationIsAtomic(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	svc := newTestSyncService(sqlDB)
+	ctx := context.Background()
+
+	sessionID := uuid.New().String()
+	mustSync(t, svc, data.UserID, nil,
+		SyncMutation{ID: uuid.New().String(), Type: SyncStartSession, SessionID: sessionID})
+
+	// The repeated set ID passes validation but fails to insert after the first set is written
+	set := syncTestSet(data, 1, 5, nil)
+	logSets := SyncMutation{ID: uuid.New().String(), Type: SyncLogSets, SessionID: sessionID,
+		Sets: []SyncSet{set, set}}
+	if _, err := svc.Sync(ctx, data.UserID, nil, []SyncMutation{logSets}, nil); err == nil {
+		t.Fatal("expected sync error for a set that fails to insert")
+	}
+
+	sets, err := svc.setRepo.ListBySession(sessionID)
+	if err != nil {
+		t.Fatalf("failed to list sets: %v", err)
+	}
+	if len(sets) != 0 {
+		t.Errorf("expected no sets from the failed mutation, got %d", len(sets))
+	}
+	if _, err := db.New(sqlDB).GetSyncMutation(ctx, logSets.ID); err != sql.ErrNoRows {
+		t.Errorf("expected the failed mutation not to be recorded, got %v", err)
+	}
+}
+
+// failingOutboxRepository is an outbox repository that cannot store events.
+type failingOutboxRepository struct {
+	outbox.Repository
+}
+
+func (failingOutboxRepository) AppendEvents(context.Context, *sql.Tx, []outbox.Event) error {
+	return errors.New("outbox unavailable")
+}
+
+// TestSyncService_EventsStoredWithMutation tests that a mutation whose events cannot be
+// stored is rolled back, and that its events are stored once it is applied.
+func TestSyncService_EventsStoredWithMutation(t *testing.T) {
+	sqlDB, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := setupTestData(t, sqlDB)
+	ctx := context.Background()
+	sessionEvents := func(userID string, res *SyncMutationResult) []event.StateEvent {
+		return []event.StateEvent{event.NewStateEvent(event.EventWorkoutStarted, userID, res.ProgramID)}
+	}
+
+	start := SyncMutation{ID: uuid.New().String(), Type: SyncStartSession, SessionID: uuid.New().String()}
+	failing := NewSyncService(sqlDB, outbox.NewService(sqlDB, failingOutboxRepository{}), NewPersonalRecordService(sqlDB), nil, nil)
+	if _, err := failing.Sync(ctx, data.UserID, nil, []SyncMutation{start}, sessionEvents); err == nil {
+		t.Fatal("expected sync error when the events cannot be stored")
+	}
+	if session, err := failing.sessionRepo.GetByID(start.SessionID); err != nil || session != nil {
+		t.Errorf("expected no session from the failed mutation, got %v (%v)", session, err)
+	}
+	if _, err := db.New(sqlDB).GetSyncMutation(ctx, start.ID); err != sql.ErrNoRows {
+		t.Errorf("expected the failed mutation not to be recorded, got %v", err)
+	}
+
+	repo := outbox.NewSQLiteRepository(sqlDB)
+	svc := NewSyncService(sqlDB, outbox.NewService(sqlDB, repo), NewPersonalRecordService(sqlDB), nil, nil)
+	result, err := svc.Sync(ctx, data.UserID, nil, []SyncMutation{start}, sessionEvents)
+	if err != nil {
+		t.Fatalf("unexpected sync error: %v", err)
+	}
+	expectSyncStatus(t, result.Results[0], SyncApplied, "")
+	events, total, err := repo.ListEvents(ctx, outbox.EventFilter{UserID: &data.UserID}, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if total != 1 || events[0].Type != event.EventWorkoutStarted {
+		t.Errorf("expected one WORKOUT_STARTED event, got %+v", events)
+	}
+}
@@ -0,0 +1,50 @@
+// Package service provides application service layer implementations.
+// This file implements keeping a lift's Training Max in step with its 1RM.
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/liftmax"
+	"github.com/waynenilsen/power-pro-v3/internal/repository"
+)
+
+// SyncTrainingMax creates or updates a Training Max based on a 1RM value.
+// The TM is set to 90% of the 1RM, rounded to the nearest 0.25.
+func SyncTrainingMax(repo *repository.LiftMaxRepository, oneRM *liftmax.LiftMax) error {
+	calculator := liftmax.NewMaxCalculator()
+	tmValue, err := calculator.ConvertToTM(oneRM.Value, nil) // Uses default 90%
+	if err != nil {
+		return err
+	}
+
+	// Check if a TM already exists for this user/lift
+	existingTM, err := repo.GetCurrentMax(oneRM.UserID, oneRM.LiftID, string(liftmax.TrainingMax))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if existingTM != nil {
+		// Update existing TM
+		existingTM.Value = tmValue
+		existingTM.EffectiveDate = oneRM.EffectiveDate
+		existingTM.UpdatedAt = now
+		return repo.Update(existingTM)
+	}
+
+	// Create new TM
+	newTM := &liftmax.LiftMax{
+		ID:            uuid.New().String(),
+		UserID:        oneRM.UserID,
+		LiftID:        oneRM.LiftID,
+		Type:          liftmax.TrainingMax,
+		Value:         tmValue,
+		EffectiveDate: oneRM.EffectiveDate,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	return repo.Create(newTM)
+}
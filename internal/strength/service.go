@@ -0,0 +1,295 @@
+// Package strength provides bodyweight-relative strength score aggregation.
+// This package combines a user's competition lift maxes, bodyweight log and profile
+// (sex and birth date) into DOTS, IPF GL and Wilks scores.
+package strength
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/bodyweight"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/strengthscore"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+	"github.com/waynenilsen/power-pro-v3/internal/profile"
+)
+
+// Missing requirement identifiers reported when scores cannot be computed.
+const (
+	MissingSex        = "sex"
+	MissingBodyweight = "bodyweight"
+)
+
+// LiftMax is the competition lift max contributing to a total.
+type LiftMax struct {
+	LiftID        string    `json:"liftId"`
+	LiftName      string    `json:"liftName"`
+	LiftSlug      string    `json:"liftSlug"`
+	Value         float64   `json:"value"`
+	EffectiveDate time.Time `json:"effectiveDate"`
+}
+
+// Scores represents a user's current strength scores.
+// Total and Bodyweight are expressed in Unit; the score fields are nil unless Available is true.
+type Scores struct {
+	Available bool `json:"available"`
+	// Missing lists what is needed before scores can be computed: "sex", "bodyweight",
+	// or the slug of a competition lift without a 1RM.
+	Missing         []string  `json:"missing"`
+	Unit            string    `json:"unit"`
+	Lifts           []LiftMax `json:"lifts"`
+	Total           *float64  `json:"total"`
+	Bodyweight      *float64  `json:"bodyweight"`
+	DOTS            *float64  `json:"dots"`
+	IPFGL           *float64  `json:"ipfGl"`
+	Wilks           *float64  `json:"wilks"`
+	Age             *int      `json:"age"`
+	AgeCoefficient  *float64  `json:"ageCoefficient"`
+	AgeAdjustedDOTS *float64  `json:"ageAdjustedDots"`
+}
+
+// HistoryPoint represents the scores in effect after a competition lift max changed.
+type HistoryPoint struct {
+	Date            time.Time `json:"date"`
+	Total           float64   `json:"total"`
+	Bodyweight      float64   `json:"bodyweight"`
+	DOTS            float64   `json:"dots"`
+	IPFGL           float64   `json:"ipfGl"`
+	Wilks           float64   `json:"wilks"`
+	AgeAdjustedDOTS *float64  `json:"ageAdjustedDots"`
+}
+
+// Service provides strength score operations.
+type Service struct {
+	db                *sql.DB
+	profileService    *profile.Service
+	bodyweightService *bodyweight.Service
+	now               func() time.Time
+}
+
+// NewService creates a new strength score service.
+func NewService(sqlDB *sql.DB, profileService *profile.Service, bodyweightService *bodyweight.Service) *Service {
+	return &Service{
+		db:                sqlDB,
+		profileService:    profileService,
+		bodyweightService: bodyweightService,
+		now:               time.Now,
+	}
+}
+
+// GetScores computes the user's current scores from their latest competition lift 1RMs
+// and latest bodyweight. Lift maxes are assumed to be stored in the user's preferred unit.
+func (s *Service) GetScores(ctx context.Context, userID string) (*Scores, error) {
+	p, err := s.profileService.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	lifts, err := s.competitionLifts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.oneRMHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.bodyweightService.ListChronological(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := &Scores{
+		Missing: []string{},
+		Unit:    p.WeightUnit,
+		Lifts:   []LiftMax{},
+	}
+
+	// Latest max per lift: history is chronological so later rows win
+	latest := make(map[string]LiftMax)
+	for _, m := range history {
+		latest[m.LiftID] = m
+	}
+
+	var totalKg float64
+	for _, l := range lifts {
+		m, ok := latest[l.LiftID]
+		if !ok {
+			scores.Missing = append(scores.Missing, l.LiftSlug)
+			continue
+		}
+		scores.Lifts = append(scores.Lifts, m)
+		totalKg += strengthscore.ToKg(m.Value, p.WeightUnit)
+	}
+	if len(scores.Lifts) > 0 && len(scores.Lifts) == len(lifts) {
+		total := round(strengthscore.FromKg(totalKg, p.WeightUnit))
+		scores.Total = &total
+	}
+
+	now := s.now()
+	bodyweightKg := bodyweight.WeightKgAt(entries, now)
+	if bodyweightKg == nil {
+		scores.Missing = append(scores.Missing, MissingBodyweight)
+	} else {
+		bw := round(strengthscore.FromKg(*bodyweightKg, p.WeightUnit))
+		scores.Bodyweight = &bw
+	}
+
+	if p.Sex == nil {
+		scores.Missing = append(scores.Missing, MissingSex)
+	}
+
+	if len(scores.Missing) > 0 || len(lifts) == 0 {
+		return scores, nil
+	}
+
+	scores.Age = p.Age(now)
+	calculated, err := strengthscore.Calculate(strengthscore.Sex(*p.Sex), *bodyweightKg, totalKg, scores.Age)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to calculate strength scores", err)
+	}
+
+	scores.Available = true
+	scores.DOTS = &calculated.DOTS
+	scores.IPFGL = &calculated.IPFGL
+	scores.Wilks = &calculated.Wilks
+	if scores.Age != nil {
+		scores.AgeCoefficient = &calculated.AgeCoefficient
+		scores.AgeAdjustedDOTS = calculated.AgeAdjustedDOTS
+	}
+
+	return scores, nil
+}
+
+// GetHistory computes the scores in effect after each change to the user's competition
+// lift 1RMs, oldest first. Each point uses the bodyweight logged on or before that date.
+// Points are only produced once every competition lift has a 1RM. Returns an empty slice
+// when the user's sex or bodyweight is unknown.
+func (s *Service) GetHistory(ctx context.Context, userID string) ([]HistoryPoint, error) {
+	p, err := s.profileService.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []HistoryPoint{}
+	if p.Sex == nil {
+		return points, nil
+	}
+	sex := strengthscore.Sex(*p.Sex)
+
+	lifts, err := s.competitionLifts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.oneRMHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.bodyweightService.ListChronological(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(lifts) == 0 || len(entries) == 0 {
+		return points, nil
+	}
+
+	latest := make(map[string]float64)
+	for i, m := range history {
+		latest[m.LiftID] = m.Value
+
+		// Emit a single point per effective date once all maxes for that date are applied
+		if i+1 < len(history) && history[i+1].EffectiveDate.Equal(m.EffectiveDate) {
+			continue
+		}
+		if len(latest) < len(lifts) {
+			continue
+		}
+
+		var totalKg float64
+		for _, v := range latest {
+			totalKg += strengthscore.ToKg(v, p.WeightUnit)
+		}
+		bodyweightKg := bodyweight.WeightKgAt(entries, m.EffectiveDate)
+
+		calculated, err := strengthscore.Calculate(sex, *bodyweightKg, totalKg, p.Age(m.EffectiveDate))
+		if err != nil {
+			return nil, apperrors.NewInternal("failed to calculate strength scores", err)
+		}
+
+		points = append(points, HistoryPoint{
+			Date:            m.EffectiveDate,
+			Total:           round(strengthscore.FromKg(totalKg, p.WeightUnit)),
+			Bodyweight:      round(strengthscore.FromKg(*bodyweightKg, p.WeightUnit)),
+			DOTS:            calculated.DOTS,
+			IPFGL:           calculated.IPFGL,
+			Wilks:           calculated.Wilks,
+			AgeAdjustedDOTS: calculated.AgeAdjustedDOTS,
+		})
+	}
+
+	return points, nil
+}
+
+// competitionLifts returns the top-level competition lifts (squat, bench press, deadlift).
+// Organization lifts are never competition lifts, so one organization's catalog cannot
+// change another user's scores.
+func (s *Service) competitionLifts(ctx context.Context) ([]LiftMax, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, name, slug FROM lifts
+		WHERE is_competition_lift = 1 AND parent_lift_id IS NULL AND organization_id IS NULL
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list competition lifts", err)
+	}
+	defer rows.Close()
+
+	lifts := []LiftMax{}
+	for rows.Next() {
+		var l LiftMax
+		if err := rows.Scan(&l.LiftID, &l.LiftName, &l.LiftSlug); err != nil {
+			return nil, apperrors.NewInternal("failed to list competition lifts", err)
+		}
+		lifts = append(lifts, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewInternal("failed to list competition lifts", err)
+	}
+	return lifts, nil
+}
+
+// oneRMHistory returns every ONE_RM for the user's competition lifts, oldest first.
+func (s *Service) oneRMHistory(ctx context.Context, userID string) ([]LiftMax, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT lm.lift_id, l.name, l.slug, lm.value, lm.effective_date
+		FROM lift_maxes lm
+		JOIN lifts l ON lm.lift_id = l.id
+		WHERE lm.user_id = ? AND lm.type = 'ONE_RM'
+		  AND l.is_competition_lift = 1 AND l.parent_lift_id IS NULL AND l.organization_id IS NULL
+		ORDER BY lm.effective_date ASC, lm.created_at ASC
+	`, userID)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list lift max history", err)
+	}
+	defer rows.Close()
+
+	maxes := []LiftMax{}
+	for rows.Next() {
+		var m LiftMax
+		var effectiveDate string
+		if err := rows.Scan(&m.LiftID, &m.LiftName, &m.LiftSlug, &m.Value, &effectiveDate); err != nil {
+			return nil, apperrors.NewInternal("failed to list lift max history", err)
+		}
+		m.EffectiveDate, _ = time.Parse(time.RFC3339, effectiveDate)
+		maxes = append(maxes, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewInternal("failed to list lift max history", err)
+	}
+	return maxes, nil
+}
+
+// round rounds a weight to one decimal place.
+func round(w float64) float64 {
+	return math.Round(w*10) / 10
+}
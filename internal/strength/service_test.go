@@ -0,0 +1,215 @@
+package strength
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waynenilsen/power-pro-v3/internal/bodyweight"
+	"github.com/waynenilsen/power-pro-v3/internal/database"
+	"github.com/waynenilsen/power-pro-v3/internal/profile"
+)
+
+const (
+	squatID    = "00000000-0000-0000-0000-000000000001"
+	benchID    = "00000000-0000-0000-0000-000000000002"
+	deadliftID = "00000000-0000-0000-0000-000000000003"
+)
+
+type testEnv struct {
+	svc            *Service
+	db             *sql.DB
+	profileService *profile.Service
+	bodyweight     *bodyweight.Service
+}
+
+func setupTestEnv(t *testing.T) (*testEnv, func()) {
+	sqlDB, cleanup, err := database.OpenTemp("../../migrations")
+	require.NoError(t, err)
+
+	profileService := profile.NewService(profile.NewSQLiteProfileRepository(sqlDB))
+	bodyweightService := bodyweight.NewService(bodyweight.NewSQLiteRepository(sqlDB), profileService)
+
+	return &testEnv{
+		svc:            NewService(sqlDB, profileService, bodyweightService),
+		db:             sqlDB,
+		profileService: profileService,
+		bodyweight:     bodyweightService,
+	}, cleanup
+}
+
+func (e *testEnv) createUser(t *testing.T, userID, weightUnit string, sex *string) {
+	_, err := e.db.Exec(`
+		INSERT INTO users (id, email, weight_unit, sex, created_at, updated_at)
+		VALUES (?, ?, ?, ?, datetime('now'), datetime('now'))
+	`, userID, userID+"@example.com", weightUnit, sex)
+	require.NoError(t, err)
+}
+
+func (e *testEnv) createOneRM(t *testing.T, userID, liftID string, value float64, effectiveDate time.Time) {
+	ts := effectiveDate.Format(time.RFC3339)
+	_, err := e.db.Exec(`
+		INSERT INTO lift_maxes (id, user_id, lift_id, type, value, effective_date, created_at, updated_at)
+		VALUES (?, ?, ?, 'ONE_RM', ?, ?, ?, ?)
+	`, userID+liftID+ts, userID, liftID, value, ts, ts, ts)
+	require.NoError(t, err)
+}
+
+func (e *testEnv) logBodyweight(t *testing.T, userID string, weight float64, recordedAt time.Time) {
+	_, err := e.bodyweight.LogEntry(context.Background(), userID, bodyweight.LogEntryRequest{
+		Weight:     weight,
+		Unit:       "kg",
+		RecordedAt: &recordedAt,
+	})
+	require.NoError(t, err)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestService_GetScores(t *testing.T) {
+	env, cleanup := setupTestEnv(t)
+	defer cleanup()
+	ctx := context.Background()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("partial maxes report missing lifts and no total", func(t *testing.T) {
+		env.createUser(t, "partial-user", "kg", strPtr("male"))
+		env.createOneRM(t, "partial-user", squatID, 200, day)
+		env.logBodyweight(t, "partial-user", 90, day)
+
+		scores, err := env.svc.GetScores(ctx, "partial-user")
+		require.NoError(t, err)
+		assert.False(t, scores.Available)
+		assert.ElementsMatch(t, []string{"bench-press", "deadlift"}, scores.Missing)
+		assert.Nil(t, scores.Total)
+		require.NotNil(t, scores.Bodyweight)
+		assert.Equal(t, 90.0, *scores.Bodyweight)
+		assert.Len(t, scores.Lifts, 1)
+	})
+
+	t.Run("uses latest maxes and converts pounds", func(t *testing.T) {
+		env.createUser(t, "lb-user", "lb", strPtr("male"))
+		env.createOneRM(t, "lb-user", squatID, 450, day)
+		env.createOneRM(t, "lb-user", squatID, 551.2, day.AddDate(0, 1, 0))
+		env.createOneRM(t, "lb-user", benchID, 352.7, day)
+		env.createOneRM(t, "lb-user", deadliftID, 639.3, day)
+		env.logBodyweight(t, "lb-user", 93, day)
+
+		scores, err := env.svc.GetScores(ctx, "lb-user")
+		require.NoError(t, err)
+		require.True(t, scores.Available)
+		assert.Equal(t, "lb", scores.Unit)
+		require.NotNil(t, scores.Total)
+		assert.Equal(t, 1543.2, *scores.Total)
+		// 1543.2 lb ≈ 700 kg at 93 kg bodyweight
+		assert.InDelta(t, 445.38, *scores.DOTS, 0.1)
+		assert.Nil(t, scores.AgeAdjustedDOTS)
+	})
+}
+
+func TestService_IgnoresOrganizationLifts(t *testing.T) {
+	env, cleanup := setupTestEnv(t)
+	defer cleanup()
+	ctx := context.Background()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// An organization lift flagged as a competition lift, as created before the flag was
+	// restricted to global lifts
+	env.createUser(t, "org-owner", "kg", strPtr("male"))
+	_, err := env.db.Exec(`
+		INSERT INTO organizations (id, name, slug, created_by, created_at, updated_at)
+		VALUES ('org-1', 'Barbell Club', 'barbell-club', 'org-owner', datetime('now'), datetime('now'))
+	`)
+	require.NoError(t, err)
+	_, err = env.db.Exec(`
+		INSERT INTO lifts (id, name, slug, is_competition_lift, organization_id, created_at, updated_at)
+		VALUES ('org-lift', 'Club Squat', 'club-squat', 1, 'org-1', datetime('now'), datetime('now'))
+	`)
+	require.NoError(t, err)
+	env.createOneRM(t, "org-owner", "org-lift", 500, day)
+
+	env.createUser(t, "scored-user", "kg", strPtr("male"))
+	env.createOneRM(t, "scored-user", squatID, 200, day)
+	env.createOneRM(t, "scored-user", benchID, 140, day)
+	env.createOneRM(t, "scored-user", deadliftID, 250, day)
+	env.logBodyweight(t, "scored-user", 90, day)
+
+	scores, err := env.svc.GetScores(ctx, "scored-user")
+	require.NoError(t, err)
+	assert.True(t, scores.Available)
+	assert.Empty(t, scores.Missing)
+	assert.Len(t, scores.Lifts, 3)
+	require.NotNil(t, scores.Total)
+	assert.Equal(t, 590.0, *scores.Total)
+
+	history, err := env.svc.GetHistory(ctx, "scored-user")
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
+
+func TestService_GetHistory(t *testing.T) {
+	env, cleanup := setupTestEnv(t)
+	defer cleanup()
+	ctx := context.Background()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	env.createUser(t, "history-user", "kg", strPtr("male"))
+	_, err := env.profileService.UpdateProfile(ctx, "history-user", profile.UpdateProfileRequest{
+		BirthDate: strPtr("1979-01-01"),
+	})
+	require.NoError(t, err)
+
+	// Squat logged before the other lifts: no point until the total is complete
+	env.createOneRM(t, "history-user", squatID, 240, day)
+	env.createOneRM(t, "history-user", benchID, 160, day.AddDate(0, 0, 7))
+	env.createOneRM(t, "history-user", deadliftID, 290, day.AddDate(0, 0, 7))
+	env.createOneRM(t, "history-user", squatID, 250, day.AddDate(0, 1, 0))
+
+	env.logBodyweight(t, "history-user", 95, day)
+	env.logBodyweight(t, "history-user", 93, day.AddDate(0, 0, 20))
+
+	history, err := env.svc.GetHistory(ctx, "history-user")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	assert.Equal(t, day.AddDate(0, 0, 7), history[0].Date)
+	assert.Equal(t, 690.0, history[0].Total)
+	assert.Equal(t, 95.0, history[0].Bodyweight)
+
+	assert.Equal(t, day.AddDate(0, 1, 0), history[1].Date)
+	assert.Equal(t, 700.0, history[1].Total)
+	assert.Equal(t, 93.0, history[1].Bodyweight)
+	assert.Equal(t, 445.38, history[1].DOTS)
+
+	// Age 45 on both dates → McCulloch coefficient 1.055
+	require.NotNil(t, history[1].AgeAdjustedDOTS)
+	assert.InDelta(t, 445.38*1.055, *history[1].AgeAdjustedDOTS, 0.01)
+}
+
+func TestService_GetHistory_RequiresSex(t *testing.T) {
+	env, cleanup := setupTestEnv(t)
+	defer cleanup()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	env.createUser(t, "no-sex-user", "kg", nil)
+	env.createOneRM(t, "no-sex-user", squatID, 200, day)
+	env.createOneRM(t, "no-sex-user", benchID, 150, day)
+	env.createOneRM(t, "no-sex-user", deadliftID, 250, day)
+	env.logBodyweight(t, "no-sex-user", 90, day)
+
+	history, err := env.svc.GetHistory(context.Background(), "no-sex-user")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	scores, err := env.svc.GetScores(context.Background(), "no-sex-user")
+	require.NoError(t, err)
+	assert.False(t, scores.Available)
+	assert.Equal(t, []string{MissingSex}, scores.Missing)
+	require.NotNil(t, scores.Total)
+	assert.Equal(t, 600.0, *scores.Total)
+}
@@ -13,8 +13,12 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 
+	"github.com/waynenilsen/power-pro-v3/internal/auth"
 	"github.com/waynenilsen/power-pro-v3/internal/database"
+	"github.com/waynenilsen/power-pro-v3/internal/mail"
+	"github.com/waynenilsen/power-pro-v3/internal/oidc"
 	"github.com/waynenilsen/power-pro-v3/internal/server"
 )
 
@@ -22,6 +26,8 @@ import (
 type TestServer struct {
 	Server   *server.Server
 	BaseURL  string
+	Mail     *mail.MemoryMailer // messages the server sends
+	rt       *inProcessRoundTripper
 	port     int
 	dbPath   string
 	db       *sql.DB
@@ -32,6 +38,10 @@ type inProcessRoundTripper struct {
 	baseHost string
 	handler  http.Handler
 	fallback http.RoundTripper
+
+	// hosts maps additional hosts to in-process stand-in handlers.
+	mu    sync.RWMutex
+	hosts map[string]http.Handler
 }
 
 func (rt *inProcessRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -39,11 +49,18 @@ func (rt *inProcessRoundTripper) RoundTrip(req *http.Request) (*http.Response, e
 		return nil, errors.New("nil request")
 	}
 
+	handler := rt.handler
 	if !strings.EqualFold(req.URL.Host, rt.baseHost) {
-		if rt.fallback != nil {
-			return rt.fallback.RoundTrip(req)
+		rt.mu.RLock()
+		standIn, ok := rt.hosts[strings.ToLower(req.URL.Host)]
+		rt.mu.RUnlock()
+		if !ok {
+			if rt.fallback != nil {
+				return rt.fallback.RoundTrip(req)
+			}
+			return nil, fmt.Errorf("unexpected host %q (expected %q)", req.URL.Host, rt.baseHost)
 		}
-		return nil, fmt.Errorf("unexpected host %q (expected %q)", req.URL.Host, rt.baseHost)
+		handler = standIn
 	}
 
 	if req.Body != nil {
@@ -60,17 +77,51 @@ func (rt *inProcessRoundTripper) RoundTrip(req *http.Request) (*http.Response, e
 	}
 
 	rec := httptest.NewRecorder()
-	rt.handler.ServeHTTP(rec, r)
+	handler.ServeHTTP(rec, r)
 
 	res := rec.Result()
 	res.Request = req
 	return res, nil
 }
 
+// Option adjusts the server configuration of a test server.
+type Option func(*server.Config)
+
+// WithOIDCProviders configures single sign-on providers. Stand in for them with
+// HandleHost, since the server reaches them through the test round tripper.
+func WithOIDCProviders(providers ...oidc.ProviderConfig) Option {
+	return func(cfg *server.Config) {
+		cfg.OIDCProviders = providers
+	}
+}
+
+// WithRateLimits configures request rate limits, which test servers otherwise run without.
+func WithRateLimits(limits server.RateLimits) Option {
+	return func(cfg *server.Config) {
+		cfg.RateLimits = limits
+	}
+}
+
+// WithLoginLockout configures the lockout after failed logins, which test servers
+// otherwise run without.
+func WithLoginLockout(lockout auth.LockoutConfig) Option {
+	return func(cfg *server.Config) {
+		cfg.LoginLockout = lockout
+	}
+}
+
+// WithSessions configures session tokens, which test servers otherwise issue for a fixed
+// seven days without refresh tokens.
+func WithSessions(sessions auth.SessionConfig) Option {
+	return func(cfg *server.Config) {
+		cfg.Sessions = sessions
+	}
+}
+
 // NewTestServer creates and starts a new test server with an isolated database.
 // It automatically enables test mode (POWERPRO_TEST_MODE=true) to allow X-User-ID
 // and X-Admin headers to work for authentication in tests.
-func NewTestServer() (*TestServer, error) {
+func NewTestServer(opts ...Option) (*TestServer, error) {
 	originalTestMode, hadOriginalTestMode := os.LookupEnv("POWERPRO_TEST_MODE")
 	// Enable test mode for X-User-ID header authentication
 	os.Setenv("POWERPRO_TEST_MODE", "true")
@@ -99,16 +150,6 @@ func NewTestServer() (*TestServer, error) {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	// Create server without binding to an actual TCP port.
-	//
-	// The test suite routes HTTP requests directly into the handler via a custom
-	// http.RoundTripper. This makes tests work in sandboxed environments where
-	// binding/listening on TCP ports is not allowed.
-	srv := server.New(server.Config{
-		Port: 0,
-		DB:   db,
-	})
-
 	baseURL := "http://powerpro.test"
 	parsedBaseURL, err := url.Parse(baseURL)
 	if err != nil {
@@ -123,12 +164,36 @@ func NewTestServer() (*TestServer, error) {
 		fallbackTransport = http.DefaultTransport
 	}
 
-	http.DefaultClient.Transport = &inProcessRoundTripper{
+	rt := &inProcessRoundTripper{
 		baseHost: parsedBaseURL.Host,
-		handler:  srv.Handler(),
 		fallback: fallbackTransport,
+		hosts:    make(map[string]http.Handler),
 	}
 
+	// Create server without binding to an actual TCP port.
+	//
+	// The test suite routes HTTP requests directly into the handler via a custom
+	// http.RoundTripper. This makes tests work in sandboxed environments where
+	// binding/listening on TCP ports is not allowed. Outgoing webhook deliveries
+	// and identity provider requests use the same round tripper so tests can register
+	// stand-ins for them. Setting WebhookClient explicitly overrides the delivery
+	// client's private address protection, which would refuse in-process receivers.
+	mailer := mail.NewMemoryMailer()
+	cfg := server.Config{
+		Port:          0,
+		DB:            db,
+		WebhookClient: &http.Client{Transport: rt},
+		OIDCClient:    &http.Client{Transport: rt},
+		Mailer:        mailer,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	srv := server.New(cfg)
+	rt.handler = srv.Handler()
+
+	http.DefaultClient.Transport = rt
+
 	// Basic readiness check against /health.
 	req, err := http.NewRequest(http.MethodGet, baseURL+"/health", nil)
 	if err != nil {
@@ -152,9 +217,14 @@ func NewTestServer() (*TestServer, error) {
 		return nil, fmt.Errorf("server health check failed: status %d", resp.StatusCode)
 	}
 
+	// Run the event dispatcher and webhook retries, which Start would otherwise run
+	srv.StartWorkers()
+
 	ts := &TestServer{
 		Server:  srv,
 		BaseURL: baseURL,
+		Mail:    mailer,
+		rt:      rt,
 		port:    0,
 		dbPath:  dbPath,
 		db:      db,
@@ -188,6 +258,14 @@ func (ts *TestServer) URL(path string) string {
 	return ts.BaseURL + path
 }
 
+// HandleHost routes requests for host (e.g. "hooks.test") to handler in-process.
+// Use it to stand in for external services the server calls, such as webhook receivers.
+func (ts *TestServer) HandleHost(host string, handler http.Handler) {
+	ts.rt.mu.Lock()
+	defer ts.rt.mu.Unlock()
+	ts.rt.hosts[strings.ToLower(host)] = handler
+}
+
 // DB returns the underlying database connection for direct access in tests.
 func (ts *TestServer) DB() *sql.DB {
 	return ts.db
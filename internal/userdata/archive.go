@@ -0,0 +1,202 @@
+package userdata
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ArchiveVersion is the version of the export archive layout. It changes when files or
+// fields are removed or change meaning; added files and fields keep the version.
+const ArchiveVersion = 1
+
+// archiveFormat identifies export archives in their manifest.
+const archiveFormat = "powerpro-export"
+
+// Manifest describes an export archive. It is stored as manifest.json.
+type Manifest struct {
+	Format     string         `json:"format"`
+	Version    int            `json:"version"`
+	UserID     string         `json:"userId"`
+	ExportedAt time.Time      `json:"exportedAt"`
+	Files      []ManifestFile `json:"files"`
+}
+
+// ManifestFile describes one file in an export archive. Records is the number of rows in
+// a CSV file and is omitted for JSON files.
+type ManifestFile struct {
+	Name    string `json:"name"`
+	Records *int   `json:"records,omitempty"`
+}
+
+// table is the CSV form of one kind of record.
+type table struct {
+	name   string
+	header []string
+	rows   [][]string
+}
+
+// WriteArchive writes the export as a zip archive holding manifest.json, data.json with
+// the whole export, and a CSV file for each kind of record.
+func WriteArchive(w io.Writer, export *Export) error {
+	tables := export.tables()
+	manifest := Manifest{
+		Format:     archiveFormat,
+		Version:    export.Version,
+		UserID:     export.Profile.ID,
+		ExportedAt: export.ExportedAt,
+		Files:      []ManifestFile{{Name: "data.json"}},
+	}
+	for _, t := range tables {
+		records := len(t.rows)
+		manifest.Files = append(manifest.Files, ManifestFile{Name: t.name + ".csv", Records: &records})
+	}
+
+	archive := zip.NewWriter(w)
+	if err := writeJSONFile(archive, "manifest.json", export.ExportedAt, manifest); err != nil {
+		return err
+	}
+	if err := writeJSONFile(archive, "data.json", export.ExportedAt, export); err != nil {
+		return err
+	}
+	for _, t := range tables {
+		if err := writeCSVFile(archive, t, export.ExportedAt); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func createFile(archive *zip.Writer, name string, modified time.Time) (io.Writer, error) {
+	return archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+}
+
+func writeJSONFile(archive *zip.Writer, name string, modified time.Time, v interface{}) error {
+	f, err := createFile(archive, name, modified)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func writeCSVFile(archive *zip.Writer, t table, modified time.Time) error {
+	f, err := createFile(archive, t.name+".csv", modified)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(f)
+	if err := writer.Write(t.header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(t.rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// tables returns the export's records as CSV tables, one per kind of record.
+func (e *Export) tables() []table {
+	p := e.Profile
+	tables := []table{{
+		name:   "profile",
+		header: []string{"id", "email", "name", "weight_unit", "sex", "birth_date", "weight_class_target", "email_verified_at", "created_at", "updated_at"},
+		rows: [][]string{{p.ID, p.Email, optString(p.Name), p.WeightUnit, optString(p.Sex), optString(p.BirthDate),
+			optString(p.WeightClassTarget), optString(p.EmailVerifiedAt), p.CreatedAt, p.UpdatedAt}},
+	}}
+
+	liftMaxes := table{name: "lift_maxes", header: []string{"id", "lift_id", "lift_name", "type", "value", "effective_date", "created_at"}}
+	for _, m := range e.LiftMaxes {
+		liftMaxes.rows = append(liftMaxes.rows, []string{m.ID, m.LiftID, m.LiftName, m.Type, formatFloat(m.Value), m.EffectiveDate, m.CreatedAt})
+	}
+
+	enrollments := table{name: "enrollments", header: []string{"id", "program_id", "program_name", "enrollment_status", "cycle_status", "week_status",
+		"current_week", "current_cycle_iteration", "current_day_index", "meet_date", "enrolled_at", "updated_at"}}
+	for _, en := range e.Enrollments {
+		enrollments.rows = append(enrollments.rows, []string{en.ID, en.ProgramID, en.ProgramName, en.EnrollmentStatus, en.CycleStatus, en.WeekStatus,
+			strconv.Itoa(en.CurrentWeek), strconv.Itoa(en.CurrentCycleIteration), optInt(en.CurrentDayIndex), optString(en.MeetDate), en.EnrolledAt, en.UpdatedAt})
+	}
+
+	sessions := table{name: "workout_sessions", header: []string{"id", "enrollment_id", "week_number", "day_index", "status", "started_at", "finished_at"}}
+	for _, s := range e.WorkoutSessions {
+		sessions.rows = append(sessions.rows, []string{s.ID, s.EnrollmentID, strconv.Itoa(s.WeekNumber), strconv.Itoa(s.DayIndex), s.Status, s.StartedAt, optString(s.FinishedAt)})
+	}
+
+	imported := table{name: "imported_sessions", header: []string{"id", "import_id", "name", "started_at", "finished_at"}}
+	for _, s := range e.ImportedSessions {
+		imported.rows = append(imported.rows, []string{s.ID, s.ImportID, optString(s.Name), s.StartedAt, optString(s.FinishedAt)})
+	}
+
+	loggedSets := table{name: "logged_sets", header: []string{"id", "session_id", "prescription_id", "lift_id", "lift_name", "set_number",
+		"weight", "target_reps", "reps_performed", "is_amrap", "rpe", "created_at"}}
+	for _, s := range e.LoggedSets {
+		loggedSets.rows = append(loggedSets.rows, []string{s.ID, s.SessionID, s.PrescriptionID, s.LiftID, s.LiftName, strconv.Itoa(s.SetNumber),
+			formatFloat(s.Weight), strconv.Itoa(s.TargetReps), strconv.Itoa(s.RepsPerformed), strconv.FormatBool(s.IsAMRAP), optFloat(s.RPE), s.CreatedAt})
+	}
+
+	progressionLogs := table{name: "progression_logs", header: []string{"id", "progression_id", "progression_name", "lift_id", "lift_name",
+		"previous_value", "new_value", "delta", "trigger_type", "applied_at", "reverts_log_id", "reverted_by_log_id"}}
+	for _, l := range e.ProgressionLogs {
+		progressionLogs.rows = append(progressionLogs.rows, []string{l.ID, l.ProgressionID, l.ProgressionName, l.LiftID, l.LiftName,
+			formatFloat(l.PreviousValue), formatFloat(l.NewValue), formatFloat(l.Delta), l.TriggerType, l.AppliedAt, optString(l.RevertsLogID), optString(l.RevertedByLogID)})
+	}
+
+	failureCounters := table{name: "failure_counters", header: []string{"id", "lift_id", "lift_name", "progression_id", "progression_name",
+		"consecutive_failures", "last_failure_at", "last_success_at", "updated_at"}}
+	for _, c := range e.FailureCounters {
+		failureCounters.rows = append(failureCounters.rows, []string{c.ID, c.LiftID, c.LiftName, c.ProgressionID, c.ProgressionName,
+			strconv.Itoa(c.ConsecutiveFailures), optString(c.LastFailureAt), optString(c.LastSuccessAt), c.UpdatedAt})
+	}
+
+	bodyweight := table{name: "bodyweight_entries", header: []string{"id", "weight", "unit", "recorded_at", "notes"}}
+	for _, b := range e.BodyweightEntries {
+		bodyweight.rows = append(bodyweight.rows, []string{b.ID, formatFloat(b.Weight), b.Unit, b.RecordedAt, optString(b.Notes)})
+	}
+
+	records := table{name: "personal_records", header: []string{"id", "lift_id", "lift_name", "record_type", "rep_count", "value", "weight", "reps", "achieved_at"}}
+	for _, r := range e.PersonalRecords {
+		records.rows = append(records.rows, []string{r.ID, optString(r.LiftID), optString(r.LiftName), r.RecordType, optInt(r.RepCount),
+			formatFloat(r.Value), optFloat(r.Weight), optInt(r.Reps), r.AchievedAt})
+	}
+
+	readiness := table{name: "readiness_checkins", header: []string{"id", "date", "sleep_quality", "soreness", "stress", "readiness_score", "hrv", "score", "notes"}}
+	for _, c := range e.ReadinessCheckins {
+		readiness.rows = append(readiness.rows, []string{c.ID, c.Date, optInt(c.SleepQuality), optInt(c.Soreness), optInt(c.Stress),
+			optInt(c.ReadinessScore), optFloat(c.HRV), formatFloat(c.Score), optString(c.Notes)})
+	}
+
+	return append(tables, liftMaxes, enrollments, sessions, imported, loggedSets, progressionLogs, failureCounters, bodyweight, records, readiness)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func optString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func optInt(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.Itoa(*i)
+}
+
+func optFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return formatFloat(*f)
+}
@@ -0,0 +1,552 @@
+package userdata
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// SQLiteRepository implements Repository using SQLite.
+type SQLiteRepository struct {
+	db *sql.DB
+	// tx, when set, is the transaction the repository runs in.
+	tx *sql.Tx
+}
+
+// NewSQLiteRepository creates a new SQLite-backed user data repository.
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// WithTx returns a repository that runs its queries in tx.
+func (r *SQLiteRepository) WithTx(tx *sql.Tx) Repository {
+	return &SQLiteRepository{db: r.db, tx: tx}
+}
+
+// conn returns the repository's transaction, or else its database.
+func (r *SQLiteRepository) conn() db.DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// GetProfile retrieves the user's account and profile information.
+func (r *SQLiteRepository) GetProfile(ctx context.Context, userID string) (*Profile, error) {
+	var p Profile
+	var email, name, sex, birthDate, weightClassTarget, emailVerifiedAt sql.NullString
+	err := r.conn().QueryRowContext(ctx, `
+		SELECT id, email, name, weight_unit, sex, birth_date, weight_class_target, email_verified_at,
+			created_at, updated_at
+		FROM users WHERE id = ?
+	`, userID).Scan(&p.ID, &email, &name, &p.WeightUnit, &sex, &birthDate, &weightClassTarget, &emailVerifiedAt,
+		&p.CreatedAt, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("user", userID)
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve profile", err)
+	}
+	p.Email = email.String
+	p.Name = nullString(name)
+	p.Sex = nullString(sex)
+	p.BirthDate = nullString(birthDate)
+	p.WeightClassTarget = nullString(weightClassTarget)
+	p.EmailVerifiedAt = nullTime(emailVerifiedAt)
+	p.CreatedAt = normalizeTime(p.CreatedAt)
+	p.UpdatedAt = normalizeTime(p.UpdatedAt)
+	return &p, nil
+}
+
+// ListLiftMaxes returns the user's maxes, oldest first.
+func (r *SQLiteRepository) ListLiftMaxes(ctx context.Context, userID string) ([]LiftMax, error) {
+	maxes := []LiftMax{}
+	err := r.query(ctx, "lift maxes", func(rows *sql.Rows) error {
+		var m LiftMax
+		if err := rows.Scan(&m.ID, &m.LiftID, &m.LiftName, &m.Type, &m.Value, &m.EffectiveDate, &m.CreatedAt); err != nil {
+			return err
+		}
+		m.EffectiveDate = normalizeTime(m.EffectiveDate)
+		m.CreatedAt = normalizeTime(m.CreatedAt)
+		maxes = append(maxes, m)
+		return nil
+	}, `
+		SELECT m.id, m.lift_id, l.name, m.type, m.value, m.effective_date, m.created_at
+		FROM lift_maxes m
+		JOIN lifts l ON l.id = m.lift_id
+		WHERE m.user_id = ?
+		ORDER BY m.effective_date, m.created_at, m.id
+	`, userID)
+	return maxes, err
+}
+
+// ListEnrollments returns the user's program enrollments.
+func (r *SQLiteRepository) ListEnrollments(ctx context.Context, userID string) ([]Enrollment, error) {
+	enrollments := []Enrollment{}
+	err := r.query(ctx, "enrollments", func(rows *sql.Rows) error {
+		var e Enrollment
+		var dayIndex sql.NullInt64
+		var meetDate sql.NullString
+		if err := rows.Scan(&e.ID, &e.ProgramID, &e.ProgramName, &e.EnrollmentStatus, &e.CycleStatus, &e.WeekStatus,
+			&e.CurrentWeek, &e.CurrentCycleIteration, &dayIndex, &meetDate, &e.EnrolledAt, &e.UpdatedAt); err != nil {
+			return err
+		}
+		e.CurrentDayIndex = nullInt(dayIndex)
+		e.MeetDate = nullString(meetDate)
+		e.EnrolledAt = normalizeTime(e.EnrolledAt)
+		e.UpdatedAt = normalizeTime(e.UpdatedAt)
+		enrollments = append(enrollments, e)
+		return nil
+	}, `
+		SELECT s.id, s.program_id, p.name, s.enrollment_status, s.cycle_status, s.week_status,
+			s.current_week, s.current_cycle_iteration, s.current_day_index, s.meet_date, s.enrolled_at, s.updated_at
+		FROM user_program_states s
+		JOIN programs p ON p.id = s.program_id
+		WHERE s.user_id = ?
+		ORDER BY s.enrolled_at, s.id
+	`, userID)
+	return enrollments, err
+}
+
+// ListWorkoutSessions returns the user's workout sessions, oldest first.
+func (r *SQLiteRepository) ListWorkoutSessions(ctx context.Context, userID string) ([]WorkoutSession, error) {
+	sessions := []WorkoutSession{}
+	err := r.query(ctx, "workout sessions", func(rows *sql.Rows) error {
+		var s WorkoutSession
+		var finishedAt sql.NullString
+		if err := rows.Scan(&s.ID, &s.EnrollmentID, &s.WeekNumber, &s.DayIndex, &s.Status, &s.StartedAt, &finishedAt); err != nil {
+			return err
+		}
+		s.StartedAt = normalizeTime(s.StartedAt)
+		s.FinishedAt = nullTime(finishedAt)
+		sessions = append(sessions, s)
+		return nil
+	}, `
+		SELECT w.id, w.user_program_state_id, w.week_number, w.day_index, w.status, w.started_at, w.finished_at
+		FROM workout_sessions w
+		JOIN user_program_states s ON s.id = w.user_program_state_id
+		WHERE s.user_id = ?
+		ORDER BY w.started_at, w.id
+	`, userID)
+	return sessions, err
+}
+
+// ListImportedSessions returns the user's imported workouts, oldest first.
+func (r *SQLiteRepository) ListImportedSessions(ctx context.Context, userID string) ([]ImportedSession, error) {
+	sessions := []ImportedSession{}
+	err := r.query(ctx, "imported sessions", func(rows *sql.Rows) error {
+		var s ImportedSession
+		var name, finishedAt sql.NullString
+		if err := rows.Scan(&s.ID, &s.ImportID, &name, &s.StartedAt, &finishedAt); err != nil {
+			return err
+		}
+		s.Name = nullString(name)
+		s.StartedAt = normalizeTime(s.StartedAt)
+		s.FinishedAt = nullTime(finishedAt)
+		sessions = append(sessions, s)
+		return nil
+	}, `
+		SELECT id, import_id, name, started_at, finished_at
+		FROM imported_sessions WHERE user_id = ?
+		ORDER BY started_at, id
+	`, userID)
+	return sessions, err
+}
+
+// ListLoggedSets returns the user's logged sets, oldest first.
+func (r *SQLiteRepository) ListLoggedSets(ctx context.Context, userID string) ([]LoggedSet, error) {
+	sets := []LoggedSet{}
+	err := r.query(ctx, "logged sets", func(rows *sql.Rows) error {
+		var s LoggedSet
+		var rpe sql.NullFloat64
+		if err := rows.Scan(&s.ID, &s.SessionID, &s.PrescriptionID, &s.LiftID, &s.LiftName, &s.SetNumber,
+			&s.Weight, &s.TargetReps, &s.RepsPerformed, &s.IsAMRAP, &rpe, &s.CreatedAt); err != nil {
+			return err
+		}
+		s.RPE = nullFloat(rpe)
+		s.CreatedAt = normalizeTime(s.CreatedAt)
+		sets = append(sets, s)
+		return nil
+	}, `
+		SELECT ls.id, ls.session_id, ls.prescription_id, ls.lift_id, l.name, ls.set_number,
+			ls.weight, ls.target_reps, ls.reps_performed, ls.is_amrap, ls.rpe, ls.created_at
+		FROM logged_sets ls
+		JOIN lifts l ON l.id = ls.lift_id
+		WHERE ls.user_id = ?
+		ORDER BY ls.created_at, ls.session_id, ls.set_number
+	`, userID)
+	return sets, err
+}
+
+// ListProgressionLogs returns the user's progression history, oldest first.
+func (r *SQLiteRepository) ListProgressionLogs(ctx context.Context, userID string) ([]ProgressionLog, error) {
+	logs := []ProgressionLog{}
+	err := r.query(ctx, "progression logs", func(rows *sql.Rows) error {
+		var l ProgressionLog
+		var revertsLogID, revertedByLogID sql.NullString
+		if err := rows.Scan(&l.ID, &l.ProgressionID, &l.ProgressionName, &l.LiftID, &l.LiftName,
+			&l.PreviousValue, &l.NewValue, &l.Delta, &l.TriggerType, &l.AppliedAt, &revertsLogID, &revertedByLogID); err != nil {
+			return err
+		}
+		l.AppliedAt = normalizeTime(l.AppliedAt)
+		l.RevertsLogID = nullString(revertsLogID)
+		l.RevertedByLogID = nullString(revertedByLogID)
+		logs = append(logs, l)
+		return nil
+	}, `
+		SELECT pl.id, pl.progression_id, p.name, pl.lift_id, l.name,
+			pl.previous_value, pl.new_value, pl.delta, pl.trigger_type, pl.applied_at, pl.reverts_log_id, pl.reverted_by_log_id
+		FROM progression_logs pl
+		JOIN progressions p ON p.id = pl.progression_id
+		JOIN lifts l ON l.id = pl.lift_id
+		WHERE pl.user_id = ?
+		ORDER BY pl.applied_at, pl.id
+	`, userID)
+	return logs, err
+}
+
+// ListFailureCounters returns the user's failure counters.
+func (r *SQLiteRepository) ListFailureCounters(ctx context.Context, userID string) ([]FailureCounter, error) {
+	counters := []FailureCounter{}
+	err := r.query(ctx, "failure counters", func(rows *sql.Rows) error {
+		var c FailureCounter
+		var lastFailureAt, lastSuccessAt sql.NullString
+		if err := rows.Scan(&c.ID, &c.LiftID, &c.LiftName, &c.ProgressionID, &c.ProgressionName,
+			&c.ConsecutiveFailures, &lastFailureAt, &lastSuccessAt, &c.UpdatedAt); err != nil {
+			return err
+		}
+		c.LastFailureAt = nullTime(lastFailureAt)
+		c.LastSuccessAt = nullTime(lastSuccessAt)
+		c.UpdatedAt = normalizeTime(c.UpdatedAt)
+		counters = append(counters, c)
+		return nil
+	}, `
+		SELECT fc.id, fc.lift_id, l.name, fc.progression_id, p.name,
+			fc.consecutive_failures, fc.last_failure_at, fc.last_success_at, fc.updated_at
+		FROM failure_counters fc
+		JOIN lifts l ON l.id = fc.lift_id
+		JOIN progressions p ON p.id = fc.progression_id
+		WHERE fc.user_id = ?
+		ORDER BY l.name, p.name
+	`, userID)
+	return counters, err
+}
+
+// ListBodyweightEntries returns the user's bodyweight entries, oldest first.
+func (r *SQLiteRepository) ListBodyweightEntries(ctx context.Context, userID string) ([]BodyweightEntry, error) {
+	entries := []BodyweightEntry{}
+	err := r.query(ctx, "bodyweight entries", func(rows *sql.Rows) error {
+		var b BodyweightEntry
+		var notes sql.NullString
+		if err := rows.Scan(&b.ID, &b.Weight, &b.Unit, &b.RecordedAt, &notes); err != nil {
+			return err
+		}
+		b.RecordedAt = normalizeTime(b.RecordedAt)
+		b.Notes = nullString(notes)
+		entries = append(entries, b)
+		return nil
+	}, `
+		SELECT id, weight, unit, recorded_at, notes
+		FROM bodyweight_entries
+		WHERE user_id = ?
+		ORDER BY recorded_at, id
+	`, userID)
+	return entries, err
+}
+
+// ListPersonalRecords returns the user's personal records, oldest first.
+func (r *SQLiteRepository) ListPersonalRecords(ctx context.Context, userID string) ([]PersonalRecord, error) {
+	records := []PersonalRecord{}
+	err := r.query(ctx, "personal records", func(rows *sql.Rows) error {
+		var pr PersonalRecord
+		var liftID, liftName sql.NullString
+		var repCount, reps sql.NullInt64
+		var weight sql.NullFloat64
+		if err := rows.Scan(&pr.ID, &liftID, &liftName, &pr.RecordType, &repCount, &pr.Value, &weight, &reps, &pr.AchievedAt); err != nil {
+			return err
+		}
+		pr.LiftID = nullString(liftID)
+		pr.LiftName = nullString(liftName)
+		pr.RepCount = nullInt(repCount)
+		pr.Weight = nullFloat(weight)
+		pr.Reps = nullInt(reps)
+		pr.AchievedAt = normalizeTime(pr.AchievedAt)
+		records = append(records, pr)
+		return nil
+	}, `
+		SELECT pr.id, pr.lift_id, l.name, pr.record_type, pr.rep_count, pr.value, pr.weight, pr.reps, pr.achieved_at
+		FROM personal_records pr
+		LEFT JOIN lifts l ON l.id = pr.lift_id
+		WHERE pr.user_id = ?
+		ORDER BY pr.achieved_at, pr.id
+	`, userID)
+	return records, err
+}
+
+// ListReadinessCheckins returns the user's readiness check-ins, oldest first.
+func (r *SQLiteRepository) ListReadinessCheckins(ctx context.Context, userID string) ([]ReadinessCheckin, error) {
+	checkins := []ReadinessCheckin{}
+	err := r.query(ctx, "readiness check-ins", func(rows *sql.Rows) error {
+		var c ReadinessCheckin
+		var sleepQuality, soreness, stress, readinessScore sql.NullInt64
+		var hrv sql.NullFloat64
+		var notes sql.NullString
+		if err := rows.Scan(&c.ID, &c.Date, &sleepQuality, &soreness, &stress, &readinessScore, &hrv, &c.Score, &notes); err != nil {
+			return err
+		}
+		c.SleepQuality = nullInt(sleepQuality)
+		c.Soreness = nullInt(soreness)
+		c.Stress = nullInt(stress)
+		c.ReadinessScore = nullInt(readinessScore)
+		c.HRV = nullFloat(hrv)
+		c.Notes = nullString(notes)
+		checkins = append(checkins, c)
+		return nil
+	}, `
+		SELECT id, checkin_date, sleep_quality, soreness, stress, readiness_score, hrv, score, notes
+		FROM readiness_checkins
+		WHERE user_id = ?
+		ORDER BY checkin_date
+	`, userID)
+	return checkins, err
+}
+
+// GetDeletion returns the user's scheduled deletion, or nil when none is scheduled.
+func (r *SQLiteRepository) GetDeletion(ctx context.Context, userID string) (*Deletion, error) {
+	var requestedAt, scheduledFor sql.NullString
+	err := r.conn().QueryRowContext(ctx, `
+		SELECT deletion_requested_at, deletion_scheduled_for FROM users WHERE id = ?
+	`, userID).Scan(&requestedAt, &scheduledFor)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("user", userID)
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve account deletion", err)
+	}
+	if !scheduledFor.Valid {
+		return nil, nil
+	}
+	deletion := &Deletion{}
+	deletion.RequestedAt, _ = time.Parse(time.RFC3339, requestedAt.String)
+	deletion.ScheduledFor, _ = time.Parse(time.RFC3339, scheduledFor.String)
+	return deletion, nil
+}
+
+// ScheduleDeletion records the user's scheduled deletion.
+func (r *SQLiteRepository) ScheduleDeletion(ctx context.Context, userID string, deletion Deletion) error {
+	res, err := r.conn().ExecContext(ctx, `
+		UPDATE users SET deletion_requested_at = ?, deletion_scheduled_for = ? WHERE id = ?
+	`, deletion.RequestedAt.UTC().Format(time.RFC3339), deletion.ScheduledFor.UTC().Format(time.RFC3339), userID)
+	if err != nil {
+		return apperrors.NewInternal("failed to schedule account deletion", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NewNotFound("user", userID)
+	}
+	return nil
+}
+
+// CancelDeletion reports whether a scheduled deletion was cancelled.
+func (r *SQLiteRepository) CancelDeletion(ctx context.Context, userID string) (bool, error) {
+	res, err := r.conn().ExecContext(ctx, `
+		UPDATE users SET deletion_requested_at = NULL, deletion_scheduled_for = NULL
+		WHERE id = ? AND deletion_scheduled_for IS NOT NULL
+	`, userID)
+	if err != nil {
+		return false, apperrors.NewInternal("failed to cancel account deletion", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// ListDueDeletions returns the users whose deletion is scheduled for at or before the time.
+func (r *SQLiteRepository) ListDueDeletions(ctx context.Context, at time.Time) ([]string, error) {
+	userIDs := []string{}
+	err := r.query(ctx, "scheduled account deletions", func(rows *sql.Rows) error {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		userIDs = append(userIDs, id)
+		return nil
+	}, `
+		SELECT id FROM users
+		WHERE deletion_scheduled_for IS NOT NULL AND deletion_scheduled_for <= ?
+		ORDER BY deletion_scheduled_for
+	`, at.UTC().Format(time.RFC3339))
+	return userIDs, err
+}
+
+// blockingOrganizationsQuery selects the organizations the user (?1) is the last owner of
+// that have other members or own catalog entries.
+const blockingOrganizationsQuery = `
+	SELECT o.name FROM organizations o
+	JOIN organization_members m ON m.organization_id = o.id AND m.user_id = ?1 AND m.role = 'OWNER'
+	WHERE NOT EXISTS(
+		SELECT 1 FROM organization_members other
+		WHERE other.organization_id = o.id AND other.role = 'OWNER' AND other.user_id != ?1
+	) AND (
+		EXISTS(SELECT 1 FROM organization_members other WHERE other.organization_id = o.id AND other.user_id != ?1)
+		OR EXISTS(SELECT 1 FROM lifts WHERE organization_id = o.id)
+		OR EXISTS(SELECT 1 FROM programs WHERE organization_id = o.id)
+		OR EXISTS(SELECT 1 FROM weekly_lookups WHERE organization_id = o.id)
+		OR EXISTS(SELECT 1 FROM daily_lookups WHERE organization_id = o.id)
+	)
+	ORDER BY o.name
+`
+
+// BlockingOrganizations returns the names of the organizations the user is the last
+// owner of that have other members or catalog entries.
+func (r *SQLiteRepository) BlockingOrganizations(ctx context.Context, userID string) ([]string, error) {
+	var names []string
+	err := r.query(ctx, "organizations", func(rows *sql.Rows) error {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	}, blockingOrganizationsQuery, userID)
+	return names, err
+}
+
+// DeleteUser deletes the user and everything referencing them in one transaction.
+// Foreign keys cascade the deletion to the user's own rows. Organizations the user created
+// pass to another owner, organizations left with no members are deleted, and stored events
+// about the user, which carry no foreign key, are deleted with them.
+func (r *SQLiteRepository) DeleteUser(ctx context.Context, userID string) error {
+	return r.transact(ctx, func(tx *sql.Tx) error {
+		// Check again inside the transaction: members may have joined since deletion was scheduled
+		var blocked bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS(`+blockingOrganizationsQuery+`)`, userID).Scan(&blocked); err != nil {
+			return apperrors.NewInternal("failed to check organizations", err)
+		}
+		if blocked {
+			return apperrors.NewConflict("the user is the last owner of an organization with other members or catalog entries")
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM organizations
+			WHERE id IN (SELECT organization_id FROM organization_members WHERE user_id = ?1)
+				AND NOT EXISTS(
+					SELECT 1 FROM organization_members other
+					WHERE other.organization_id = organizations.id AND other.user_id != ?1
+				)
+		`, userID); err != nil {
+			return apperrors.NewInternal("failed to delete organizations", err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE organizations SET created_by = (
+				SELECT m.user_id FROM organization_members m
+				WHERE m.organization_id = organizations.id AND m.role = 'OWNER' AND m.user_id != ?1
+				ORDER BY m.joined_at, m.user_id
+				LIMIT 1
+			)
+			WHERE created_by = ?1
+		`, userID); err != nil {
+			return apperrors.NewInternal("failed to transfer organizations", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM outbox_events WHERE user_id = ?`, userID); err != nil {
+			return apperrors.NewInternal("failed to delete events", err)
+		}
+
+		res, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, userID)
+		if err != nil {
+			return apperrors.NewInternal("failed to delete user", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return apperrors.NewNotFound("user", userID)
+		}
+		return nil
+	})
+}
+
+// transact runs fn in the repository's transaction, or else in a new one.
+func (r *SQLiteRepository) transact(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return apperrors.NewInternal("failed to begin transaction", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	if err = fn(tx); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return apperrors.NewInternal("failed to commit transaction", err)
+	}
+	return nil
+}
+
+// query runs a query and calls scan for each row. what names the rows in errors.
+func (r *SQLiteRepository) query(ctx context.Context, what string, scan func(*sql.Rows) error, query string, args ...interface{}) error {
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return apperrors.NewInternal("failed to list "+what, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return apperrors.NewInternal("failed to scan "+what, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return apperrors.NewInternal("failed to list "+what, err)
+	}
+	return nil
+}
+
+// sqliteTimeLayout is the format of timestamps set by SQLite's datetime('now') defaults.
+const sqliteTimeLayout = "2006-01-02 15:04:05"
+
+// normalizeTime converts stored timestamps to RFC 3339 in UTC. Timestamps are stored as
+// RFC 3339 or, for rows relying on column defaults, in SQLite's datetime format. Other
+// values, such as dates, are returned unchanged.
+func normalizeTime(s string) string {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC().Format(time.RFC3339)
+	}
+	if t, err := time.Parse(sqliteTimeLayout, s); err == nil {
+		return t.Format(time.RFC3339)
+	}
+	return s
+}
+
+func nullTime(ns sql.NullString) *string {
+	if !ns.Valid {
+		return nil
+	}
+	s := normalizeTime(ns.String)
+	return &s
+}
+
+func nullString(ns sql.NullString) *string {
+	if !ns.Valid {
+		return nil
+	}
+	return &ns.String
+}
+
+func nullInt(ni sql.NullInt64) *int {
+	if !ni.Valid {
+		return nil
+	}
+	i := int(ni.Int64)
+	return &i
+}
+
+func nullFloat(nf sql.NullFloat64) *float64 {
+	if !nf.Valid {
+		return nil
+	}
+	return &nf.Float64
+}
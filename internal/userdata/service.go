@@ -0,0 +1,380 @@
+// Package userdata lets users take their data with them and delete their accounts.
+// Exports are versioned zip archives holding the user's training data as JSON and as one
+// CSV file per kind of record. Account deletion is scheduled with a grace period, during
+// which the user can cancel, after which the user and every row referencing them are
+// deleted.
+package userdata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// DefaultDeletionGracePeriod is how long accounts are kept after their deletion is
+// requested.
+const DefaultDeletionGracePeriod = 30 * 24 * time.Hour
+
+// Profile is the exported account and profile information.
+type Profile struct {
+	ID                string  `json:"id"`
+	Email             string  `json:"email"`
+	Name              *string `json:"name"`
+	WeightUnit        string  `json:"weightUnit"`
+	Sex               *string `json:"sex"`
+	BirthDate         *string `json:"birthDate"`
+	WeightClassTarget *string `json:"weightClassTarget"`
+	EmailVerifiedAt   *string `json:"emailVerifiedAt"`
+	CreatedAt         string  `json:"createdAt"`
+	UpdatedAt         string  `json:"updatedAt"`
+}
+
+// LiftMax is an exported one-rep or training max.
+type LiftMax struct {
+	ID            string  `json:"id"`
+	LiftID        string  `json:"liftId"`
+	LiftName      string  `json:"liftName"`
+	Type          string  `json:"type"`
+	Value         float64 `json:"value"`
+	EffectiveDate string  `json:"effectiveDate"`
+	CreatedAt     string  `json:"createdAt"`
+}
+
+// Enrollment is an exported program enrollment.
+type Enrollment struct {
+	ID                    string  `json:"id"`
+	ProgramID             string  `json:"programId"`
+	ProgramName           string  `json:"programName"`
+	EnrollmentStatus      string  `json:"enrollmentStatus"`
+	CycleStatus           string  `json:"cycleStatus"`
+	WeekStatus            string  `json:"weekStatus"`
+	CurrentWeek           int     `json:"currentWeek"`
+	CurrentCycleIteration int     `json:"currentCycleIteration"`
+	CurrentDayIndex       *int    `json:"currentDayIndex"`
+	MeetDate              *string `json:"meetDate"`
+	EnrolledAt            string  `json:"enrolledAt"`
+	UpdatedAt             string  `json:"updatedAt"`
+}
+
+// WorkoutSession is an exported workout session.
+type WorkoutSession struct {
+	ID           string  `json:"id"`
+	EnrollmentID string  `json:"enrollmentId"`
+	WeekNumber   int     `json:"weekNumber"`
+	DayIndex     int     `json:"dayIndex"`
+	Status       string  `json:"status"`
+	StartedAt    string  `json:"startedAt"`
+	FinishedAt   *string `json:"finishedAt"`
+}
+
+// ImportedSession is an exported workout imported from another app.
+type ImportedSession struct {
+	ID         string  `json:"id"`
+	ImportID   string  `json:"importId"`
+	Name       *string `json:"name"`
+	StartedAt  string  `json:"startedAt"`
+	FinishedAt *string `json:"finishedAt"`
+}
+
+// LoggedSet is an exported logged set.
+type LoggedSet struct {
+	ID             string   `json:"id"`
+	SessionID      string   `json:"sessionId"`
+	PrescriptionID string   `json:"prescriptionId"`
+	LiftID         string   `json:"liftId"`
+	LiftName       string   `json:"liftName"`
+	SetNumber      int      `json:"setNumber"`
+	Weight         float64  `json:"weight"`
+	TargetReps     int      `json:"targetReps"`
+	RepsPerformed  int      `json:"repsPerformed"`
+	IsAMRAP        bool     `json:"isAmrap"`
+	RPE            *float64 `json:"rpe"`
+	CreatedAt      string   `json:"createdAt"`
+}
+
+// ProgressionLog is an exported application or revert of a progression.
+type ProgressionLog struct {
+	ID              string  `json:"id"`
+	ProgressionID   string  `json:"progressionId"`
+	ProgressionName string  `json:"progressionName"`
+	LiftID          string  `json:"liftId"`
+	LiftName        string  `json:"liftName"`
+	PreviousValue   float64 `json:"previousValue"`
+	NewValue        float64 `json:"newValue"`
+	Delta           float64 `json:"delta"`
+	TriggerType     string  `json:"triggerType"`
+	AppliedAt       string  `json:"appliedAt"`
+	RevertsLogID    *string `json:"revertsLogId"`
+	RevertedByLogID *string `json:"revertedByLogId"`
+}
+
+// FailureCounter is an exported count of consecutive failures at a lift and progression.
+type FailureCounter struct {
+	ID                  string  `json:"id"`
+	LiftID              string  `json:"liftId"`
+	LiftName            string  `json:"liftName"`
+	ProgressionID       string  `json:"progressionId"`
+	ProgressionName     string  `json:"progressionName"`
+	ConsecutiveFailures int     `json:"consecutiveFailures"`
+	LastFailureAt       *string `json:"lastFailureAt"`
+	LastSuccessAt       *string `json:"lastSuccessAt"`
+	UpdatedAt           string  `json:"updatedAt"`
+}
+
+// BodyweightEntry is an exported bodyweight measurement.
+type BodyweightEntry struct {
+	ID         string  `json:"id"`
+	Weight     float64 `json:"weight"`
+	Unit       string  `json:"unit"`
+	RecordedAt string  `json:"recordedAt"`
+	Notes      *string `json:"notes"`
+}
+
+// PersonalRecord is an exported personal record.
+type PersonalRecord struct {
+	ID         string   `json:"id"`
+	LiftID     *string  `json:"liftId"`
+	LiftName   *string  `json:"liftName"`
+	RecordType string   `json:"recordType"`
+	RepCount   *int     `json:"repCount"`
+	Value      float64  `json:"value"`
+	Weight     *float64 `json:"weight"`
+	Reps       *int     `json:"reps"`
+	AchievedAt string   `json:"achievedAt"`
+}
+
+// ReadinessCheckin is an exported daily readiness check-in.
+type ReadinessCheckin struct {
+	ID             string   `json:"id"`
+	Date           string   `json:"date"`
+	SleepQuality   *int     `json:"sleepQuality"`
+	Soreness       *int     `json:"soreness"`
+	Stress         *int     `json:"stress"`
+	ReadinessScore *int     `json:"readinessScore"`
+	HRV            *float64 `json:"hrv"`
+	Score          float64  `json:"score"`
+	Notes          *string  `json:"notes"`
+}
+
+// Export holds all of a user's data. Weights are in the units they were recorded in.
+type Export struct {
+	Version           int                `json:"version"`
+	ExportedAt        time.Time          `json:"exportedAt"`
+	Profile           Profile            `json:"profile"`
+	LiftMaxes         []LiftMax          `json:"liftMaxes"`
+	Enrollments       []Enrollment       `json:"enrollments"`
+	WorkoutSessions   []WorkoutSession   `json:"workoutSessions"`
+	ImportedSessions  []ImportedSession  `json:"importedSessions"`
+	LoggedSets        []LoggedSet        `json:"loggedSets"`
+	ProgressionLogs   []ProgressionLog   `json:"progressionLogs"`
+	FailureCounters   []FailureCounter   `json:"failureCounters"`
+	BodyweightEntries []BodyweightEntry  `json:"bodyweightEntries"`
+	PersonalRecords   []PersonalRecord   `json:"personalRecords"`
+	ReadinessCheckins []ReadinessCheckin `json:"readinessCheckins"`
+}
+
+// Deletion is a scheduled account deletion.
+type Deletion struct {
+	RequestedAt  time.Time
+	ScheduledFor time.Time
+}
+
+// Repository defines the interface for reading a user's data and deleting their account.
+type Repository interface {
+	GetProfile(ctx context.Context, userID string) (*Profile, error)
+	ListLiftMaxes(ctx context.Context, userID string) ([]LiftMax, error)
+	ListEnrollments(ctx context.Context, userID string) ([]Enrollment, error)
+	ListWorkoutSessions(ctx context.Context, userID string) ([]WorkoutSession, error)
+	ListImportedSessions(ctx context.Context, userID string) ([]ImportedSession, error)
+	ListLoggedSets(ctx context.Context, userID string) ([]LoggedSet, error)
+	ListProgressionLogs(ctx context.Context, userID string) ([]ProgressionLog, error)
+	ListFailureCounters(ctx context.Context, userID string) ([]FailureCounter, error)
+	ListBodyweightEntries(ctx context.Context, userID string) ([]BodyweightEntry, error)
+	ListPersonalRecords(ctx context.Context, userID string) ([]PersonalRecord, error)
+	ListReadinessCheckins(ctx context.Context, userID string) ([]ReadinessCheckin, error)
+
+	// GetDeletion returns the user's scheduled deletion, or nil when none is scheduled.
+	GetDeletion(ctx context.Context, userID string) (*Deletion, error)
+	ScheduleDeletion(ctx context.Context, userID string, deletion Deletion) error
+	// CancelDeletion reports whether a scheduled deletion was cancelled.
+	CancelDeletion(ctx context.Context, userID string) (bool, error)
+	// ListDueDeletions returns the users whose deletion is scheduled for at or before the time.
+	ListDueDeletions(ctx context.Context, at time.Time) ([]string, error)
+	// BlockingOrganizations returns the names of the organizations the user is the last
+	// owner of that have other members or catalog entries.
+	BlockingOrganizations(ctx context.Context, userID string) ([]string, error)
+	// DeleteUser deletes the user and everything referencing them in one transaction.
+	// Organizations the user created pass to another owner, and organizations left with
+	// no members are deleted.
+	DeleteUser(ctx context.Context, userID string) error
+	// WithTx returns a repository that runs its queries in tx.
+	WithTx(tx *sql.Tx) Repository
+}
+
+// Service exports users' data and deletes their accounts.
+type Service struct {
+	repo        Repository
+	gracePeriod time.Duration
+	now         func() time.Time
+}
+
+// NewService creates a new user data service. Accounts are deleted gracePeriod after
+// their deletion is requested; zero means DefaultDeletionGracePeriod.
+func NewService(repo Repository, gracePeriod time.Duration) *Service {
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultDeletionGracePeriod
+	}
+	return &Service{
+		repo:        repo,
+		gracePeriod: gracePeriod,
+		now:         time.Now,
+	}
+}
+
+// WithTx returns a copy of the service whose repository runs in tx.
+func (s *Service) WithTx(tx *sql.Tx) *Service {
+	txService := *s
+	txService.repo = s.repo.WithTx(tx)
+	return &txService
+}
+
+// Export collects all of the user's data.
+func (s *Service) Export(ctx context.Context, userID string) (*Export, error) {
+	profile, err := s.repo.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	export := &Export{
+		Version:    ArchiveVersion,
+		ExportedAt: s.now().UTC().Truncate(time.Second),
+		Profile:    *profile,
+	}
+	if export.LiftMaxes, err = s.repo.ListLiftMaxes(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.Enrollments, err = s.repo.ListEnrollments(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.WorkoutSessions, err = s.repo.ListWorkoutSessions(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.ImportedSessions, err = s.repo.ListImportedSessions(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.LoggedSets, err = s.repo.ListLoggedSets(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.ProgressionLogs, err = s.repo.ListProgressionLogs(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.FailureCounters, err = s.repo.ListFailureCounters(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.BodyweightEntries, err = s.repo.ListBodyweightEntries(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.PersonalRecords, err = s.repo.ListPersonalRecords(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.ReadinessCheckins, err = s.repo.ListReadinessCheckins(ctx, userID); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// GetDeletion returns the user's scheduled deletion, or nil when none is scheduled.
+func (s *Service) GetDeletion(ctx context.Context, userID string) (*Deletion, error) {
+	return s.repo.GetDeletion(ctx, userID)
+}
+
+// ScheduleDeletion schedules the user's account for deletion after the grace period.
+// Requesting deletion again returns the existing schedule. Users who are the last owner
+// of an organization with other members or catalog entries must first hand it over.
+func (s *Service) ScheduleDeletion(ctx context.Context, userID string) (*Deletion, error) {
+	existing, err := s.repo.GetDeletion(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+	if err := s.checkOrganizations(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	deletion := Deletion{
+		RequestedAt:  now,
+		ScheduledFor: now.Add(s.gracePeriod),
+	}
+	if err := s.repo.ScheduleDeletion(ctx, userID, deletion); err != nil {
+		return nil, err
+	}
+	return &deletion, nil
+}
+
+// CancelDeletion cancels the user's scheduled deletion.
+func (s *Service) CancelDeletion(ctx context.Context, userID string) error {
+	cancelled, err := s.repo.CancelDeletion(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return apperrors.NewNotFound("account deletion", userID)
+	}
+	return nil
+}
+
+// DeleteDueAccounts deletes the accounts whose grace period has passed and returns how
+// many were deleted. Accounts that cannot be deleted yet stay scheduled and are retried.
+func (s *Service) DeleteDueAccounts(ctx context.Context) (int, error) {
+	userIDs, err := s.repo.ListDueDeletions(ctx, s.now())
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, userID := range userIDs {
+		if err := s.repo.DeleteUser(ctx, userID); err != nil {
+			log.Printf("USERDATA: Failed to delete account %s: %v", userID, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// RunDeletions deletes accounts whose grace period has passed every interval until ctx
+// is cancelled.
+func (s *Service) RunDeletions(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.DeleteDueAccounts(ctx); err != nil {
+				log.Printf("USERDATA: Failed to delete scheduled accounts: %v", err)
+			}
+		}
+	}
+}
+
+// checkOrganizations refuses deletion while the user is the last owner of an
+// organization that would be left without one.
+func (s *Service) checkOrganizations(ctx context.Context, userID string) error {
+	names, err := s.repo.BlockingOrganizations(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		return apperrors.NewConflict(fmt.Sprintf(
+			"make another member an owner of %s before deleting the account", strings.Join(names, ", ")))
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package userdata
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waynenilsen/power-pro-v3/internal/database"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+const (
+	squatID       = "00000000-0000-0000-0000-000000000001"
+	programID     = "starting-strength-0000-0000-000000000001"
+	progressionID = "starting-strength-0000-0000-000000000040"
+)
+
+func setupTestService(t *testing.T) (*Service, *sql.DB, *time.Time, func()) {
+	sqlDB, cleanup, err := database.OpenTemp("../../migrations")
+	require.NoError(t, err)
+	svc := NewService(NewSQLiteRepository(sqlDB), 0)
+	clock := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return clock }
+	return svc, sqlDB, &clock, cleanup
+}
+
+// seedUser creates a user with a row in every table holding training data or account state.
+func seedUser(t *testing.T, sqlDB *sql.DB, userID string) {
+	statements := []string{
+		`INSERT INTO users (id, email, name, created_at, updated_at) VALUES (?1, ?1 || '@example.com', 'Sam', '2024-01-01T00:00:00Z', '2024-01-01T00:00:00Z')`,
+		`INSERT INTO lift_maxes (id, user_id, lift_id, type, value, effective_date, created_at, updated_at)
+			VALUES (?1 || '-max', ?1, '` + squatID + `', 'TRAINING_MAX', 315.5, '2024-01-02T00:00:00Z', '2024-01-02T00:00:00Z', '2024-01-02T00:00:00Z')`,
+		`INSERT INTO user_program_states (id, user_id, program_id, current_week, current_cycle_iteration, enrolled_at, updated_at)
+			VALUES (?1 || '-state', ?1, '` + programID + `', 1, 1, '2024-01-03T00:00:00Z', '2024-01-03T00:00:00Z')`,
+		`INSERT INTO workout_sessions (id, user_program_state_id, week_number, day_index, status, started_at)
+			VALUES (?1 || '-session', ?1 || '-state', 1, 0, 'COMPLETED', '2024-01-04 09:00:00')`,
+		`INSERT INTO training_imports (id, user_id, format, session_count, set_count, max_count, created_at)
+			VALUES (?1 || '-import', ?1, 'STRONG', 1, 0, 0, '2024-01-04T12:00:00Z')`,
+		`INSERT INTO imported_sessions (id, import_id, user_id, source_key, name, started_at)
+			VALUES (?1 || '-imported', ?1 || '-import', ?1, 'key', 'Day A', '2023-06-01T08:00:00Z')`,
+		`INSERT INTO logged_sets (id, user_id, session_id, prescription_id, lift_id, set_number, weight, target_reps, reps_performed, is_amrap, rpe, created_at)
+			VALUES (?1 || '-set', ?1, ?1 || '-session', 'prescription', '` + squatID + `', 1, 285, 5, 5, 0, 8.5, '2024-01-04T09:10:00Z')`,
+		`INSERT INTO progression_logs (id, user_id, progression_id, lift_id, previous_value, new_value, delta, trigger_type, applied_at)
+			VALUES (?1 || '-log', ?1, '` + progressionID + `', '` + squatID + `', 315.5, 320.5, 5, 'AFTER_SESSION', '2024-01-04T10:00:00Z')`,
+		`INSERT INTO failure_counters (id, user_id, lift_id, progression_id, consecutive_failures, updated_at)
+			VALUES (?1 || '-failures', ?1, '` + squatID + `', '` + progressionID + `', 2, '2024-01-04T10:00:00Z')`,
+		`INSERT INTO bodyweight_entries (id, user_id, weight, unit, recorded_at, notes, created_at)
+			VALUES (?1 || '-bw', ?1, 200, 'lb', '2024-01-05T07:00:00Z', 'after breakfast, "heavy"', '2024-01-05T07:00:00Z')`,
+		`INSERT INTO readiness_checkins (id, user_id, checkin_date, sleep_quality, score, created_at, updated_at)
+			VALUES (?1 || '-readiness', ?1, '2024-01-05', 4, 75, '2024-01-05T07:00:00Z', '2024-01-05T07:00:00Z')`,
+		`INSERT INTO sessions (id, user_id, token, expires_at, created_at)
+			VALUES (?1 || '-auth', ?1, ?1 || '-token', '2030-01-01T00:00:00Z', '2024-01-05T07:00:00Z')`,
+		`INSERT INTO outbox_events (id, event_type, user_id, payload, occurred_at, created_at)
+			VALUES (?1 || '-event', 'SET_LOGGED', ?1, '{}', '2024-01-04T09:10:00Z', '2024-01-04T09:10:00Z')`,
+	}
+	for _, stmt := range statements {
+		_, err := sqlDB.Exec(stmt, userID)
+		require.NoError(t, err, stmt)
+	}
+}
+
+// countUserRows counts the rows of every table with a column identifying the user.
+func countUserRows(t *testing.T, sqlDB *sql.DB, userID string) map[string]int {
+	rows, err := sqlDB.Query(`
+		SELECT m.name, c.name FROM sqlite_master m, pragma_table_info(m.name) c
+		WHERE m.type = 'table' AND c.name IN ('user_id', 'coach_id', 'athlete_id', 'author_id', 'created_by')
+	`)
+	require.NoError(t, err)
+	type column struct{ table, name string }
+	var columns []column
+	for rows.Next() {
+		var c column
+		require.NoError(t, rows.Scan(&c.table, &c.name))
+		columns = append(columns, c)
+	}
+	require.NoError(t, rows.Close())
+
+	counts := map[string]int{}
+	for _, c := range columns {
+		var n int
+		require.NoError(t, sqlDB.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %q WHERE %q = ?`, c.table, c.name), userID).Scan(&n))
+		if n > 0 {
+			counts[c.table+"."+c.name] = n
+		}
+	}
+	return counts
+}
+
+func readArchive(t *testing.T, data []byte) map[string][]byte {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := map[string][]byte{}
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+	}
+	return files
+}
+
+func TestExport(t *testing.T) {
+	svc, sqlDB, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	seedUser(t, sqlDB, "lifter")
+	seedUser(t, sqlDB, "other")
+
+	export, err := svc.Export(ctx, "lifter")
+	require.NoError(t, err)
+	assert.Equal(t, ArchiveVersion, export.Version)
+	assert.Equal(t, "lifter@example.com", export.Profile.Email)
+	require.Len(t, export.LiftMaxes, 1)
+	assert.Equal(t, "Squat", export.LiftMaxes[0].LiftName)
+	require.Len(t, export.Enrollments, 1)
+	assert.Equal(t, "Starting Strength", export.Enrollments[0].ProgramName)
+	require.Len(t, export.WorkoutSessions, 1)
+	assert.Equal(t, "2024-01-04T09:00:00Z", export.WorkoutSessions[0].StartedAt, "SQLite timestamps are converted to RFC 3339")
+	require.Len(t, export.ImportedSessions, 1)
+	assert.Equal(t, "lifter-import", export.ImportedSessions[0].ImportID)
+	require.Len(t, export.LoggedSets, 1)
+	require.Len(t, export.ProgressionLogs, 1)
+	assert.Equal(t, "Starting Strength +5lb", export.ProgressionLogs[0].ProgressionName)
+	require.Len(t, export.FailureCounters, 1)
+	assert.Equal(t, 2, export.FailureCounters[0].ConsecutiveFailures)
+	require.Len(t, export.BodyweightEntries, 1)
+	require.Len(t, export.ReadinessCheckins, 1)
+	assert.Empty(t, export.PersonalRecords)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteArchive(&buf, export))
+	files := readArchive(t, buf.Bytes())
+
+	t.Run("manifest", func(t *testing.T) {
+		var manifest Manifest
+		require.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+		assert.Equal(t, "powerpro-export", manifest.Format)
+		assert.Equal(t, ArchiveVersion, manifest.Version)
+		assert.Equal(t, "lifter", manifest.UserID)
+		for _, f := range manifest.Files {
+			assert.Contains(t, files, f.Name)
+		}
+		assert.Len(t, files, len(manifest.Files)+1)
+	})
+
+	t.Run("data.json holds the export", func(t *testing.T) {
+		var decoded Export
+		require.NoError(t, json.Unmarshal(files["data.json"], &decoded))
+		assert.Equal(t, *export, decoded)
+		assert.Contains(t, string(files["data.json"]), `"personalRecords": []`, "empty sections are empty lists")
+	})
+
+	t.Run("CSV files", func(t *testing.T) {
+		records, err := csv.NewReader(bytes.NewReader(files["logged_sets.csv"])).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "weight", records[0][6])
+		assert.Equal(t, []string{"lifter-set", "lifter-session", "prescription", squatID, "Squat", "1", "285", "5", "5", "false", "8.5", "2024-01-04T09:10:00Z"}, records[1])
+
+		records, err = csv.NewReader(bytes.NewReader(files["bodyweight_entries.csv"])).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, `after breakfast, "heavy"`, records[1][4])
+
+		records, err = csv.NewReader(bytes.NewReader(files["personal_records.csv"])).ReadAll()
+		require.NoError(t, err)
+		assert.Len(t, records, 1, "empty sections keep their header")
+	})
+
+	t.Run("unknown users", func(t *testing.T) {
+		_, err := svc.Export(ctx, "nobody")
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+}
+
+func TestAccountDeletion(t *testing.T) {
+	svc, sqlDB, clock, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	seedUser(t, sqlDB, "lifter")
+	seedUser(t, sqlDB, "other")
+
+	deletion, err := svc.ScheduleDeletion(ctx, "lifter")
+	require.NoError(t, err)
+	assert.Equal(t, clock.Add(DefaultDeletionGracePeriod), deletion.ScheduledFor)
+
+	again, err := svc.ScheduleDeletion(ctx, "lifter")
+	require.NoError(t, err)
+	assert.Equal(t, deletion, again, "scheduling again keeps the schedule")
+
+	t.Run("cancelling", func(t *testing.T) {
+		require.NoError(t, svc.CancelDeletion(ctx, "lifter"))
+		got, err := svc.GetDeletion(ctx, "lifter")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+		assert.True(t, apperrors.IsNotFound(svc.CancelDeletion(ctx, "lifter")))
+	})
+
+	t.Run("accounts are kept during the grace period", func(t *testing.T) {
+		_, err := svc.ScheduleDeletion(ctx, "lifter")
+		require.NoError(t, err)
+		*clock = clock.Add(DefaultDeletionGracePeriod - time.Minute)
+		deleted, err := svc.DeleteDueAccounts(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+		assert.NotEmpty(t, countUserRows(t, sqlDB, "lifter"))
+	})
+
+	t.Run("deleting removes every row referencing the user", func(t *testing.T) {
+		*clock = clock.Add(time.Minute)
+		deleted, err := svc.DeleteDueAccounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, deleted)
+
+		assert.Empty(t, countUserRows(t, sqlDB, "lifter"))
+		var events int
+		require.NoError(t, sqlDB.QueryRow(`SELECT COUNT(*) FROM outbox_events WHERE user_id = 'lifter'`).Scan(&events))
+		assert.Zero(t, events)
+
+		export, err := svc.Export(ctx, "other")
+		require.NoError(t, err)
+		assert.Len(t, export.LoggedSets, 1, "other users keep their data")
+	})
+}
+
+func TestAccountDeletionOrganizations(t *testing.T) {
+	svc, sqlDB, clock, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	for _, userID := range []string{"founder", "partner", "athlete"} {
+		_, err := sqlDB.Exec(`INSERT INTO users (id, email, created_at, updated_at) VALUES (?1, ?1 || '@example.com', datetime('now'), datetime('now'))`, userID)
+		require.NoError(t, err)
+	}
+	addOrg := func(id, name string, members map[string]string) {
+		_, err := sqlDB.Exec(`INSERT INTO organizations (id, name, slug, created_by, created_at, updated_at) VALUES (?, ?, ?, 'founder', datetime('now'), datetime('now'))`, id, name, id)
+		require.NoError(t, err)
+		for userID, role := range members {
+			_, err := sqlDB.Exec(`INSERT INTO organization_members (organization_id, user_id, role, joined_at) VALUES (?, ?, ?, datetime('now'))`, id, userID, role)
+			require.NoError(t, err)
+		}
+	}
+	addOrg("solo", "Solo Gym", map[string]string{"founder": "OWNER"})
+	addOrg("shared", "Shared Gym", map[string]string{"founder": "OWNER", "partner": "OWNER", "athlete": "ATHLETE"})
+	addOrg("team", "Team Gym", map[string]string{"founder": "OWNER", "athlete": "ATHLETE"})
+
+	_, err := svc.ScheduleDeletion(ctx, "founder")
+	assert.True(t, apperrors.IsConflict(err), "the last owner of an organization with members must hand it over")
+	assert.Contains(t, err.Error(), "Team Gym")
+
+	_, err = sqlDB.Exec(`UPDATE organization_members SET role = 'OWNER' WHERE organization_id = 'team' AND user_id = 'athlete'`)
+	require.NoError(t, err)
+	_, err = svc.ScheduleDeletion(ctx, "founder")
+	require.NoError(t, err)
+
+	*clock = clock.Add(DefaultDeletionGracePeriod)
+	deleted, err := svc.DeleteDueAccounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	var createdBy string
+	require.NoError(t, sqlDB.QueryRow(`SELECT created_by FROM organizations WHERE id = 'shared'`).Scan(&createdBy))
+	assert.Equal(t, "partner", createdBy, "organizations pass to another owner")
+	require.NoError(t, sqlDB.QueryRow(`SELECT created_by FROM organizations WHERE id = 'team'`).Scan(&createdBy))
+	assert.Equal(t, "athlete", createdBy)
+	var solo int
+	require.NoError(t, sqlDB.QueryRow(`SELECT COUNT(*) FROM organizations WHERE id = 'solo'`).Scan(&solo))
+	assert.Zero(t, solo, "organizations without other members are deleted")
+}
@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedAddress is returned when a delivery would connect to an address that is
+// not on the public internet.
+var ErrBlockedAddress = errors.New("webhook address is not publicly routable")
+
+// blockedPrefixes are reserved ranges not covered by the netip.Addr predicates used
+// in isBlockedAddr.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // TEST-NET-1
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // TEST-NET-2
+	netip.MustParsePrefix("203.0.113.0/24"),  // TEST-NET-3
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, which can reach IPv4 private ranges
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+}
+
+// isBlockedAddr reports whether deliveries must not connect to addr: loopback,
+// private (RFC 1918 and unique local), link-local (including the cloud metadata
+// address 169.254.169.254), multicast, unspecified and other reserved addresses.
+func isBlockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+	for _, p := range blockedPrefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDialAddress rejects a resolved "ip:port" address that is blocked.
+// It runs as the dialer's Control hook, after name resolution and before connecting,
+// so a host name that resolves to a blocked address, including one that changes its
+// answer after the subscription was validated, is refused.
+func checkDialAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	if isBlockedAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// NewDeliveryClient returns the HTTP client used for deliveries by default. It only
+// connects to publicly routable addresses, ignores proxy settings so the check applies
+// to the receiver itself, and does not follow redirects; a redirect response is
+// recorded as a failed attempt.
+func NewDeliveryClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   deliveryTimeout,
+		KeepAlive: 30 * time.Second,
+		Control:   checkDialAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   deliveryTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// validateHost rejects subscription hosts that are blocked addresses or local names.
+// Host names are checked again on every delivery, once resolved.
+func validateHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return !isBlockedAddr(addr)
+	}
+	return true
+}
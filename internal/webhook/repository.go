@@ -0,0 +1,383 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/waynenilsen/power-pro-v3/internal/db"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// SQLiteRepository implements Repository using SQLite.
+type SQLiteRepository struct {
+	db *sql.DB
+	// tx, when set, is the transaction the repository runs in.
+	tx *sql.Tx
+}
+
+// NewSQLiteRepository creates a new SQLite-backed webhook repository.
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// WithTx returns a repository that runs its queries in tx.
+func (r *SQLiteRepository) WithTx(tx *sql.Tx) Repository {
+	return &SQLiteRepository{db: r.db, tx: tx}
+}
+
+// conn returns the repository's transaction, or else its database.
+func (r *SQLiteRepository) conn() db.DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+const subscriptionColumns = `id, user_id, url, secret, event_types, description, active, created_by, created_at, updated_at`
+
+const deliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	last_status_code, last_error, replay_of, created_at, updated_at, delivered_at`
+
+// CreateSubscription inserts a new subscription.
+func (r *SQLiteRepository) CreateSubscription(ctx context.Context, sub *Subscription) error {
+	eventTypes, err := json.Marshal(sub.EventTypes)
+	if err != nil {
+		return apperrors.NewInternal("failed to encode event types", err)
+	}
+
+	_, err = r.conn().ExecContext(ctx, `
+		INSERT INTO webhook_subscriptions (`+subscriptionColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, sub.ID, nullString(sub.UserID), sub.URL, sub.Secret, string(eventTypes), nullString(sub.Description),
+		sub.Active, sub.CreatedBy, sub.CreatedAt.Format(time.RFC3339), sub.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		return apperrors.NewInternal("failed to create webhook subscription", err)
+	}
+	return nil
+}
+
+// GetSubscription retrieves a subscription by its ID.
+func (r *SQLiteRepository) GetSubscription(ctx context.Context, id string) (*Subscription, error) {
+	row := r.conn().QueryRowContext(ctx, `
+		SELECT `+subscriptionColumns+` FROM webhook_subscriptions WHERE id = ?
+	`, id)
+
+	sub, err := scanSubscription(row)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("webhook subscription", id)
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve webhook subscription", err)
+	}
+	return sub, nil
+}
+
+// ListSubscriptions returns a page of a user's subscriptions, or of admin subscriptions
+// when userID is nil, oldest first, along with the total count.
+func (r *SQLiteRepository) ListSubscriptions(ctx context.Context, userID *string, limit, offset int64) ([]Subscription, int64, error) {
+	where := "user_id IS NULL"
+	args := []interface{}{}
+	if userID != nil {
+		where = "user_id = ?"
+		args = append(args, *userID)
+	}
+
+	var total int64
+	if err := r.conn().QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM webhook_subscriptions WHERE "+where, args...,
+	).Scan(&total); err != nil {
+		return nil, 0, apperrors.NewInternal("failed to count webhook subscriptions", err)
+	}
+
+	rows, err := r.conn().QueryContext(ctx, `
+		SELECT `+subscriptionColumns+` FROM webhook_subscriptions WHERE `+where+`
+		ORDER BY created_at ASC, id ASC
+		LIMIT ? OFFSET ?
+	`, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list webhook subscriptions", err)
+	}
+	defer rows.Close()
+
+	subs, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list webhook subscriptions", err)
+	}
+	return subs, total, nil
+}
+
+// ListActiveSubscriptionsForUser returns the user's active subscriptions and all active
+// admin subscriptions.
+func (r *SQLiteRepository) ListActiveSubscriptionsForUser(ctx context.Context, userID string) ([]Subscription, error) {
+	rows, err := r.conn().QueryContext(ctx, `
+		SELECT `+subscriptionColumns+` FROM webhook_subscriptions
+		WHERE active = 1 AND (user_id = ? OR user_id IS NULL)
+		ORDER BY created_at ASC, id ASC
+	`, userID)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list webhook subscriptions", err)
+	}
+	defer rows.Close()
+
+	subs, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list webhook subscriptions", err)
+	}
+	return subs, nil
+}
+
+// UpdateSubscription saves the mutable fields of a subscription.
+func (r *SQLiteRepository) UpdateSubscription(ctx context.Context, sub *Subscription) error {
+	eventTypes, err := json.Marshal(sub.EventTypes)
+	if err != nil {
+		return apperrors.NewInternal("failed to encode event types", err)
+	}
+
+	result, err := r.conn().ExecContext(ctx, `
+		UPDATE webhook_subscriptions
+		SET url = ?, event_types = ?, description = ?, active = ?, updated_at = ?
+		WHERE id = ?
+	`, sub.URL, string(eventTypes), nullString(sub.Description), sub.Active, sub.UpdatedAt.Format(time.RFC3339), sub.ID)
+	if err != nil {
+		return apperrors.NewInternal("failed to update webhook subscription", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apperrors.NewInternal("failed to check update result", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NewNotFound("webhook subscription", sub.ID)
+	}
+	return nil
+}
+
+// DeleteSubscription removes a subscription; its deliveries are removed by cascade.
+func (r *SQLiteRepository) DeleteSubscription(ctx context.Context, id string) error {
+	result, err := r.conn().ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = ?", id)
+	if err != nil {
+		return apperrors.NewInternal("failed to delete webhook subscription", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apperrors.NewInternal("failed to check delete result", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NewNotFound("webhook subscription", id)
+	}
+	return nil
+}
+
+// CreateDelivery inserts a new delivery.
+func (r *SQLiteRepository) CreateDelivery(ctx context.Context, d *Delivery) error {
+	_, err := r.conn().ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (`+deliveryColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, d.ID, d.SubscriptionID, d.EventID, d.EventType, d.Payload, string(d.Status), d.Attempts,
+		nullTime(d.NextAttemptAt), nullInt(d.LastStatusCode), nullString(d.LastError), nullString(d.ReplayOf),
+		d.CreatedAt.Format(time.RFC3339), d.UpdatedAt.Format(time.RFC3339), nullTime(d.DeliveredAt))
+	if err != nil {
+		return apperrors.NewInternal("failed to create webhook delivery", err)
+	}
+	return nil
+}
+
+// GetDelivery retrieves a delivery by its ID.
+func (r *SQLiteRepository) GetDelivery(ctx context.Context, id string) (*Delivery, error) {
+	row := r.conn().QueryRowContext(ctx, `
+		SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = ?
+	`, id)
+
+	d, err := scanDelivery(row)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NewNotFound("webhook delivery", id)
+	}
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to retrieve webhook delivery", err)
+	}
+	return d, nil
+}
+
+// UpdateDelivery saves the outcome of a delivery attempt.
+func (r *SQLiteRepository) UpdateDelivery(ctx context.Context, d *Delivery) error {
+	_, err := r.conn().ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?,
+			updated_at = ?, delivered_at = ?
+		WHERE id = ?
+	`, string(d.Status), d.Attempts, nullTime(d.NextAttemptAt), nullInt(d.LastStatusCode), nullString(d.LastError),
+		d.UpdatedAt.Format(time.RFC3339), nullTime(d.DeliveredAt), d.ID)
+	if err != nil {
+		return apperrors.NewInternal("failed to update webhook delivery", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns a page of a subscription's deliveries, newest first,
+// along with the total count.
+func (r *SQLiteRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit, offset int64) ([]Delivery, int64, error) {
+	var total int64
+	if err := r.conn().QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM webhook_deliveries WHERE subscription_id = ?", subscriptionID,
+	).Scan(&total); err != nil {
+		return nil, 0, apperrors.NewInternal("failed to count webhook deliveries", err)
+	}
+
+	rows, err := r.conn().QueryContext(ctx, `
+		SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE subscription_id = ?
+		ORDER BY created_at DESC, rowid DESC
+		LIMIT ? OFFSET ?
+	`, subscriptionID, limit, offset)
+	if err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list webhook deliveries", err)
+	}
+	defer rows.Close()
+
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, 0, apperrors.NewInternal("failed to list webhook deliveries", err)
+	}
+	return deliveries, total, nil
+}
+
+// ListDueDeliveries returns pending deliveries whose next attempt is at or before now,
+// oldest first.
+func (r *SQLiteRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int64) ([]Delivery, error) {
+	rows, err := r.conn().QueryContext(ctx, `
+		SELECT `+deliveryColumns+` FROM webhook_deliveries
+		WHERE status = 'PENDING' AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC, rowid ASC
+		LIMIT ?
+	`, now.UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list due webhook deliveries", err)
+	}
+	defer rows.Close()
+
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, apperrors.NewInternal("failed to list due webhook deliveries", err)
+	}
+	return deliveries, nil
+}
+
+// rowScanner abstracts *sql.Row and *sql.Rows for scanning.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSubscription scans a single subscription row.
+func scanSubscription(row rowScanner) (*Subscription, error) {
+	var sub Subscription
+	var userID, description sql.NullString
+	var eventTypes, createdAt, updatedAt string
+
+	if err := row.Scan(&sub.ID, &userID, &sub.URL, &sub.Secret, &eventTypes, &description,
+		&sub.Active, &sub.CreatedBy, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(eventTypes), &sub.EventTypes); err != nil {
+		return nil, err
+	}
+	if userID.Valid {
+		sub.UserID = &userID.String
+	}
+	if description.Valid {
+		sub.Description = &description.String
+	}
+	sub.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	sub.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+
+	return &sub, nil
+}
+
+// scanSubscriptions scans all rows into subscriptions.
+func scanSubscriptions(rows *sql.Rows) ([]Subscription, error) {
+	subs := []Subscription{}
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, *sub)
+	}
+	return subs, rows.Err()
+}
+
+// scanDelivery scans a single delivery row.
+func scanDelivery(row rowScanner) (*Delivery, error) {
+	var d Delivery
+	var status, createdAt, updatedAt string
+	var nextAttemptAt, lastError, replayOf, deliveredAt sql.NullString
+	var lastStatusCode sql.NullInt64
+
+	if err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &status, &d.Attempts,
+		&nextAttemptAt, &lastStatusCode, &lastError, &replayOf, &createdAt, &updatedAt, &deliveredAt); err != nil {
+		return nil, err
+	}
+
+	d.Status = DeliveryStatus(status)
+	d.NextAttemptAt = parseNullTime(nextAttemptAt)
+	if lastStatusCode.Valid {
+		code := int(lastStatusCode.Int64)
+		d.LastStatusCode = &code
+	}
+	if lastError.Valid {
+		d.LastError = &lastError.String
+	}
+	if replayOf.Valid {
+		d.ReplayOf = &replayOf.String
+	}
+	d.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	d.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	d.DeliveredAt = parseNullTime(deliveredAt)
+
+	return &d, nil
+}
+
+// scanDeliveries scans all rows into deliveries.
+func scanDeliveries(rows *sql.Rows) ([]Delivery, error) {
+	deliveries := []Delivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func nullInt(i *int) sql.NullInt64 {
+	if i == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*i), Valid: true}
+}
+
+func nullTime(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.UTC().Format(time.RFC3339), Valid: true}
+}
+
+func parseNullTime(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
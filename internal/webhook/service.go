@@ -0,0 +1,596 @@
+// Package webhook provides outgoing webhooks for domain events.
+// Users subscribe a URL to the event types they care about; admins can subscribe
+// to events for every user. Each delivery is signed with the subscription secret
+// (HMAC-SHA256), retried with exponential backoff and recorded in a delivery log.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/event"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+const (
+	// MaxAttempts is the number of delivery attempts before a delivery is marked FAILED.
+	MaxAttempts = 6
+	// baseRetryDelay is the delay before the first retry; each later retry doubles it.
+	baseRetryDelay = 30 * time.Second
+	// deliveryTimeout bounds a single delivery attempt.
+	deliveryTimeout = 10 * time.Second
+	// dueBatchSize is the maximum number of due deliveries retried per pass.
+	dueBatchSize = 100
+	// minSecretLength is the minimum length of a caller-provided signing secret.
+	minSecretLength = 16
+	// maxDescriptionLength is the maximum allowed length for a subscription description.
+	maxDescriptionLength = 200
+	// maxErrorLength is the maximum length of a recorded delivery error.
+	maxErrorLength = 500
+)
+
+// Delivery headers sent with every webhook request.
+const (
+	// HeaderEvent carries the event type.
+	HeaderEvent = "X-PowerPro-Event"
+	// HeaderEventID carries the event ID, which is shared by retries and replays of a delivery.
+	HeaderEventID = "X-PowerPro-Event-ID"
+	// HeaderDelivery carries the delivery ID.
+	HeaderDelivery = "X-PowerPro-Delivery"
+	// HeaderSignature carries the timestamped HMAC-SHA256 signature of the body.
+	HeaderSignature = "X-PowerPro-Signature"
+)
+
+// DeliveryStatus represents the state of a webhook delivery.
+type DeliveryStatus string
+
+const (
+	// DeliveryPending is waiting for its first attempt or a retry.
+	DeliveryPending DeliveryStatus = "PENDING"
+	// DeliverySucceeded was acknowledged by the receiver with a 2xx response.
+	DeliverySucceeded DeliveryStatus = "SUCCEEDED"
+	// DeliveryFailed exhausted its attempts or its subscription was disabled.
+	DeliveryFailed DeliveryStatus = "FAILED"
+)
+
+// Subscription is a URL subscribed to a set of event types.
+type Subscription struct {
+	ID string
+	// UserID is the user whose events are delivered. Nil for admin subscriptions,
+	// which receive events for every user.
+	UserID      *string
+	URL         string
+	Secret      string
+	EventTypes  []string
+	Description *string
+	Active      bool
+	CreatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Matches reports whether the subscription wants events of the given type.
+func (s *Subscription) Matches(eventType string) bool {
+	for _, et := range s.EventTypes {
+		if et == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is a single event sent (or to be sent) to a subscription.
+type Delivery struct {
+	ID             string
+	SubscriptionID string
+	EventID        string
+	EventType      string
+	// Payload is the exact request body, so retries and replays send identical bytes.
+	Payload        string
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  *time.Time
+	LastStatusCode *int
+	LastError      *string
+	// ReplayOf is the ID of the delivery this one replays, if any.
+	ReplayOf    *string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeliveredAt *time.Time
+}
+
+// EventPayload is the JSON body of a webhook request.
+type EventPayload struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	UserID    string                 `json:"userId"`
+	ProgramID string                 `json:"programId,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// CreateSubscriptionRequest represents a request to create a subscription.
+type CreateSubscriptionRequest struct {
+	URL        string
+	EventTypes []string
+	// Secret is the signing secret. Empty means generate one.
+	Secret      string
+	Description *string
+}
+
+// UpdateSubscriptionRequest represents a partial update of a subscription.
+// Nil fields are left unchanged.
+type UpdateSubscriptionRequest struct {
+	URL         *string
+	EventTypes  []string
+	Description *string
+	Active      *bool
+}
+
+// Repository defines the interface for webhook persistence.
+type Repository interface {
+	CreateSubscription(ctx context.Context, sub *Subscription) error
+	GetSubscription(ctx context.Context, id string) (*Subscription, error)
+	// ListSubscriptions returns a page of a user's subscriptions, or of admin
+	// subscriptions when userID is nil, along with the total count.
+	ListSubscriptions(ctx context.Context, userID *string, limit, offset int64) ([]Subscription, int64, error)
+	// ListActiveSubscriptionsForUser returns the active subscriptions of the user
+	// and all active admin subscriptions.
+	ListActiveSubscriptionsForUser(ctx context.Context, userID string) ([]Subscription, error)
+	UpdateSubscription(ctx context.Context, sub *Subscription) error
+	DeleteSubscription(ctx context.Context, id string) error
+	CreateDelivery(ctx context.Context, d *Delivery) error
+	GetDelivery(ctx context.Context, id string) (*Delivery, error)
+	UpdateDelivery(ctx context.Context, d *Delivery) error
+	// ListDeliveries returns a page of a subscription's deliveries, newest first.
+	ListDeliveries(ctx context.Context, subscriptionID string, limit, offset int64) ([]Delivery, int64, error)
+	// ListDueDeliveries returns pending deliveries whose next attempt is at or before now.
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int64) ([]Delivery, error)
+	// WithTx returns a repository that runs its queries in tx.
+	WithTx(tx *sql.Tx) Repository
+}
+
+// Service manages webhook subscriptions and delivers events to them.
+type Service struct {
+	repo   Repository
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewService creates a new webhook service.
+// client is used for deliveries; nil means NewDeliveryClient, which only connects to
+// public addresses. A non-nil client replaces that check and is meant for tests that
+// deliver to in-process receivers.
+func NewService(repo Repository, client *http.Client) *Service {
+	if client == nil {
+		client = NewDeliveryClient()
+	}
+	return &Service{
+		repo:   repo,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// WithTx returns a copy of the service whose repository runs in tx.
+func (s *Service) WithTx(tx *sql.Tx) *Service {
+	txService := *s
+	txService.repo = s.repo.WithTx(tx)
+	return &txService
+}
+
+// CreateSubscription creates a subscription for a user, or an admin subscription
+// when userID is empty. createdBy is the authenticated caller.
+func (s *Service) CreateSubscription(ctx context.Context, userID, createdBy string, req CreateSubscriptionRequest) (*Subscription, error) {
+	if err := validateURL(req.URL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := validateEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	description, err := normalizeDescription(req.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		secret, err = generateSecret()
+		if err != nil {
+			return nil, apperrors.NewInternal("failed to generate webhook secret", err)
+		}
+	} else if len(secret) < minSecretLength {
+		return nil, apperrors.NewValidation("secret", fmt.Sprintf("secret must be at least %d characters", minSecretLength))
+	}
+
+	now := s.now().UTC()
+	sub := &Subscription{
+		ID:          uuid.New().String(),
+		URL:         req.URL,
+		Secret:      secret,
+		EventTypes:  eventTypes,
+		Description: description,
+		Active:      true,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if userID != "" {
+		sub.UserID = &userID
+	}
+
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// GetSubscription retrieves a subscription by ID.
+func (s *Service) GetSubscription(ctx context.Context, id string) (*Subscription, error) {
+	return s.repo.GetSubscription(ctx, id)
+}
+
+// ListSubscriptions returns a page of a user's subscriptions, or of admin
+// subscriptions when userID is empty.
+func (s *Service) ListSubscriptions(ctx context.Context, userID string, limit, offset int64) ([]Subscription, int64, error) {
+	var owner *string
+	if userID != "" {
+		owner = &userID
+	}
+	return s.repo.ListSubscriptions(ctx, owner, limit, offset)
+}
+
+// UpdateSubscription applies a partial update to a subscription.
+func (s *Service) UpdateSubscription(ctx context.Context, id string, req UpdateSubscriptionRequest) (*Subscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateURL(*req.URL); err != nil {
+			return nil, err
+		}
+		sub.URL = *req.URL
+	}
+	if req.EventTypes != nil {
+		eventTypes, err := validateEventTypes(req.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+		sub.EventTypes = eventTypes
+	}
+	if req.Description != nil {
+		description, err := normalizeDescription(req.Description)
+		if err != nil {
+			return nil, err
+		}
+		sub.Description = description
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	sub.UpdatedAt = s.now().UTC()
+
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// DeleteSubscription deletes a subscription and its delivery log.
+func (s *Service) DeleteSubscription(ctx context.Context, id string) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+// ListDeliveries returns a page of a subscription's delivery log, newest first.
+func (s *Service) ListDeliveries(ctx context.Context, subscriptionID string, limit, offset int64) ([]Delivery, int64, error) {
+	return s.repo.ListDeliveries(ctx, subscriptionID, limit, offset)
+}
+
+// HandleEvent records a delivery for every active subscription that wants the event
+// and attempts each one immediately. Failed attempts are left for ProcessDue to retry.
+func (s *Service) HandleEvent(ctx context.Context, evt event.StateEvent) error {
+	subs, err := s.repo.ListActiveSubscriptionsForUser(ctx, evt.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Stored events keep their ID, so receivers can deduplicate redeliveries
+	eventID := evt.ID
+	if eventID == "" {
+		eventID = uuid.New().String()
+	}
+	payload := EventPayload{
+		ID:        eventID,
+		Type:      string(evt.Type),
+		UserID:    evt.UserID,
+		ProgramID: evt.ProgramID,
+		Timestamp: evt.Timestamp.UTC(),
+		Data:      evt.Payload,
+	}
+	var body []byte
+	var firstErr error
+	for i := range subs {
+		sub := &subs[i]
+		if !sub.Matches(payload.Type) {
+			continue
+		}
+		// Every subscription receives the same body for an event
+		if body == nil {
+			body, err = json.Marshal(payload)
+			if err != nil {
+				return apperrors.NewInternal("failed to encode webhook payload", err)
+			}
+		}
+
+		d, err := s.newDelivery(ctx, sub.ID, payload.ID, payload.Type, string(body), nil)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if err := s.attempt(ctx, sub, d); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ProcessDue retries pending deliveries whose next attempt is due.
+// Returns the number of deliveries attempted.
+func (s *Service) ProcessDue(ctx context.Context) (int, error) {
+	due, err := s.repo.ListDueDeliveries(ctx, s.now().UTC(), dueBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range due {
+		d := &due[i]
+		sub, err := s.repo.GetSubscription(ctx, d.SubscriptionID)
+		if err != nil {
+			return i, err
+		}
+		if err := s.attempt(ctx, sub, d); err != nil {
+			return i, err
+		}
+	}
+	return len(due), nil
+}
+
+// Run retries due deliveries every interval until ctx is cancelled.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = s.ProcessDue(ctx)
+		}
+	}
+}
+
+// Replay sends a recorded delivery again as a new delivery with the same event ID
+// and body, signed with the subscription's current secret.
+func (s *Service) Replay(ctx context.Context, subscriptionID, deliveryID string) (*Delivery, error) {
+	sub, err := s.repo.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	original, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	// Deliveries of other subscriptions are reported as not found to avoid leaking existence
+	if original.SubscriptionID != sub.ID {
+		return nil, apperrors.NewNotFound("webhook delivery", deliveryID)
+	}
+	if !sub.Active {
+		return nil, apperrors.NewConflict("webhook subscription is disabled")
+	}
+
+	d, err := s.newDelivery(ctx, sub.ID, original.EventID, original.EventType, original.Payload, &original.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attempt(ctx, sub, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// newDelivery records a pending delivery. Its first retry is scheduled one retry delay
+// out, so ProcessDue only picks it up if the immediate attempt never completes.
+func (s *Service) newDelivery(ctx context.Context, subscriptionID, eventID, eventType, payload string, replayOf *string) (*Delivery, error) {
+	now := s.now().UTC()
+	next := now.Add(baseRetryDelay)
+	d := &Delivery{
+		ID:             uuid.New().String(),
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Payload:        payload,
+		Status:         DeliveryPending,
+		NextAttemptAt:  &next,
+		ReplayOf:       replayOf,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.repo.CreateDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// attempt sends a delivery once and records the outcome. The returned error only
+// reports failures to record the outcome; receiver failures are recorded on the delivery.
+func (s *Service) attempt(ctx context.Context, sub *Subscription, d *Delivery) error {
+	d.Attempts++
+	d.UpdatedAt = s.now().UTC()
+
+	if !sub.Active {
+		d.Status = DeliveryFailed
+		d.NextAttemptAt = nil
+		d.LastStatusCode = nil
+		d.LastError = stringPtr("subscription is disabled")
+		return s.repo.UpdateDelivery(ctx, d)
+	}
+
+	statusCode, err := s.send(ctx, sub, d)
+	d.LastStatusCode = statusCode
+	d.LastError = nil
+	if err == nil {
+		deliveredAt := s.now().UTC()
+		d.Status = DeliverySucceeded
+		d.NextAttemptAt = nil
+		d.DeliveredAt = &deliveredAt
+		return s.repo.UpdateDelivery(ctx, d)
+	}
+
+	msg := err.Error()
+	if len(msg) > maxErrorLength {
+		msg = msg[:maxErrorLength]
+	}
+	d.LastError = &msg
+	if d.Attempts >= MaxAttempts {
+		d.Status = DeliveryFailed
+		d.NextAttemptAt = nil
+	} else {
+		next := s.now().UTC().Add(RetryDelay(d.Attempts))
+		d.Status = DeliveryPending
+		d.NextAttemptAt = &next
+	}
+	return s.repo.UpdateDelivery(ctx, d)
+}
+
+// send performs the HTTP request for a delivery. It returns the response status code,
+// if a response was received, and an error unless the receiver answered with a 2xx.
+func (s *Service) send(ctx context.Context, sub *Subscription, d *Delivery) (*int, error) {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PowerPro-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderEventID, d.EventID)
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, s.now().Unix(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	statusCode := resp.StatusCode
+	if statusCode < 200 || statusCode > 299 {
+		return &statusCode, fmt.Errorf("receiver responded with status %d", statusCode)
+	}
+	return &statusCode, nil
+}
+
+// RetryDelay returns the delay before the retry that follows the given attempt:
+// 30s after the first attempt, doubling with each further attempt.
+func RetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return baseRetryDelay << (attempts - 1)
+}
+
+// Sign returns the signature header value for a body sent at the given Unix time:
+// "t=<timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<body>">".
+// Receivers recompute the HMAC with their secret and compare in constant time.
+func Sign(secret string, timestamp int64, body []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// validateURL checks that a subscription URL is an absolute http(s) URL whose host is
+// not a loopback, private or otherwise reserved address.
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return apperrors.NewValidation("url", "url must be an absolute http or https URL")
+	}
+	if !validateHost(u.Hostname()) {
+		return apperrors.NewValidation("url", "url must point to a public host")
+	}
+	return nil
+}
+
+// validateEventTypes checks that at least one known event type is given and
+// returns the list with duplicates removed.
+func validateEventTypes(eventTypes []string) ([]string, error) {
+	if len(eventTypes) == 0 {
+		return nil, apperrors.NewValidation("eventTypes", "at least one event type is required")
+	}
+	seen := make(map[string]bool, len(eventTypes))
+	result := make([]string, 0, len(eventTypes))
+	for _, et := range eventTypes {
+		if !event.ValidEventTypes[event.EventType(et)] {
+			return nil, apperrors.NewValidation("eventTypes", fmt.Sprintf("unknown event type %q", et))
+		}
+		if !seen[et] {
+			seen[et] = true
+			result = append(result, et)
+		}
+	}
+	return result, nil
+}
+
+// normalizeDescription trims a description, treating blank as unset.
+func normalizeDescription(description *string) (*string, error) {
+	if description == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimSpace(*description)
+	if len(trimmed) > maxDescriptionLength {
+		return nil, apperrors.NewValidation("description", "description must be 200 characters or less")
+	}
+	if trimmed == "" {
+		return nil, nil
+	}
+	return &trimmed, nil
+}
+
+// generateSecret returns a random signing secret.
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,493 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/waynenilsen/power-pro-v3/internal/database"
+	"github.com/waynenilsen/power-pro-v3/internal/domain/event"
+	apperrors "github.com/waynenilsen/power-pro-v3/internal/errors"
+)
+
+// receivedRequest is a webhook request captured by the stand-in receiver.
+type receivedRequest struct {
+	Header http.Header
+	Body   []byte
+}
+
+// standInReceiver is a local HTTP receiver that records requests and answers
+// with queued status codes (200 once the queue is empty).
+type standInReceiver struct {
+	mu       sync.Mutex
+	requests []receivedRequest
+	statuses []int
+}
+
+func (rcv *standInReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rcv.mu.Lock()
+	rcv.requests = append(rcv.requests, receivedRequest{Header: r.Header.Clone(), Body: body})
+	status := http.StatusOK
+	if len(rcv.statuses) > 0 {
+		status = rcv.statuses[0]
+		rcv.statuses = rcv.statuses[1:]
+	}
+	rcv.mu.Unlock()
+	w.WriteHeader(status)
+}
+
+func (rcv *standInReceiver) failNext(statuses ...int) {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	rcv.statuses = append(rcv.statuses, statuses...)
+}
+
+func (rcv *standInReceiver) received() []receivedRequest {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	return append([]receivedRequest(nil), rcv.requests...)
+}
+
+// handlerTransport routes requests to an http.Handler in-process, so tests do not
+// need to bind a port.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func setupTestService(t *testing.T) (*Service, *standInReceiver, *testClock, *sql.DB, func()) {
+	sqlDB, cleanup, err := database.OpenTemp("../../migrations")
+	require.NoError(t, err)
+
+	receiver := &standInReceiver{}
+	clock := &testClock{now: time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)}
+	svc := NewService(NewSQLiteRepository(sqlDB), &http.Client{Transport: handlerTransport{receiver}})
+	svc.now = clock.Now
+	return svc, receiver, clock, sqlDB, cleanup
+}
+
+func createTestUser(t *testing.T, sqlDB *sql.DB, userID string) {
+	_, err := sqlDB.Exec(`
+		INSERT INTO users (id, email, created_at, updated_at)
+		VALUES (?, ?, datetime('now'), datetime('now'))
+	`, userID, userID+"@example.com")
+	require.NoError(t, err)
+}
+
+func setLoggedEvent(userID string) event.StateEvent {
+	return event.NewStateEvent(event.EventSetLogged, userID, "program-1").
+		WithPayload(event.PayloadLoggedSetID, "set-1").
+		WithPayload(event.PayloadRepsPerformed, 5)
+}
+
+func TestSign(t *testing.T) {
+	sig := Sign("secret", 1700000000, []byte(`{"a":1}`))
+	assert.True(t, strings.HasPrefix(sig, "t=1700000000,v1="))
+	assert.Equal(t, sig, Sign("secret", 1700000000, []byte(`{"a":1}`)))
+	assert.NotEqual(t, sig, Sign("other-secret", 1700000000, []byte(`{"a":1}`)))
+	assert.NotEqual(t, sig, Sign("secret", 1700000001, []byte(`{"a":1}`)))
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, RetryDelay(1))
+	assert.Equal(t, time.Minute, RetryDelay(2))
+	assert.Equal(t, 2*time.Minute, RetryDelay(3))
+	assert.Equal(t, 8*time.Minute, RetryDelay(5))
+}
+
+func TestCreateSubscription_Validation(t *testing.T) {
+	svc, _, _, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	createTestUser(t, sqlDB, "user-1")
+
+	tests := []struct {
+		name  string
+		req   CreateSubscriptionRequest
+		field string
+	}{
+		{"relative url", CreateSubscriptionRequest{URL: "/hook", EventTypes: []string{"SET_LOGGED"}}, "url"},
+		{"unsupported scheme", CreateSubscriptionRequest{URL: "ftp://example.com/hook", EventTypes: []string{"SET_LOGGED"}}, "url"},
+		{"no event types", CreateSubscriptionRequest{URL: "https://example.com/hook"}, "eventTypes"},
+		{"unknown event type", CreateSubscriptionRequest{URL: "https://example.com/hook", EventTypes: []string{"LIFTED"}}, "eventTypes"},
+		{"short secret", CreateSubscriptionRequest{URL: "https://example.com/hook", EventTypes: []string{"SET_LOGGED"}, Secret: "short"}, "secret"},
+		{"localhost", CreateSubscriptionRequest{URL: "http://localhost:8080/hook", EventTypes: []string{"SET_LOGGED"}}, "url"},
+		{"loopback address", CreateSubscriptionRequest{URL: "http://127.0.0.1/hook", EventTypes: []string{"SET_LOGGED"}}, "url"},
+		{"private address", CreateSubscriptionRequest{URL: "http://10.0.0.5/hook", EventTypes: []string{"SET_LOGGED"}}, "url"},
+		{"metadata address", CreateSubscriptionRequest{URL: "http://169.254.169.254/latest/meta-data", EventTypes: []string{"SET_LOGGED"}}, "url"},
+		{"ipv6 loopback", CreateSubscriptionRequest{URL: "http://[::1]/hook", EventTypes: []string{"SET_LOGGED"}}, "url"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.CreateSubscription(ctx, "user-1", "user-1", tc.req)
+			require.Error(t, err)
+			assert.True(t, apperrors.IsValidation(err))
+			assert.Contains(t, err.Error(), tc.field)
+		})
+	}
+
+	t.Run("generates a secret and removes duplicate event types", func(t *testing.T) {
+		sub, err := svc.CreateSubscription(ctx, "user-1", "user-1", CreateSubscriptionRequest{
+			URL:        "https://example.com/hook",
+			EventTypes: []string{"SET_LOGGED", "SET_LOGGED", "PR_ACHIEVED"},
+		})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(sub.Secret, "whsec_"))
+		assert.Equal(t, []string{"SET_LOGGED", "PR_ACHIEVED"}, sub.EventTypes)
+		require.NotNil(t, sub.UserID)
+		assert.Equal(t, "user-1", *sub.UserID)
+
+		stored, err := svc.GetSubscription(ctx, sub.ID)
+		require.NoError(t, err)
+		assert.Equal(t, sub.Secret, stored.Secret)
+		assert.Equal(t, sub.EventTypes, stored.EventTypes)
+	})
+}
+
+func TestHandleEvent_SignedDelivery(t *testing.T) {
+	svc, receiver, clock, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	createTestUser(t, sqlDB, "user-1")
+
+	sub, err := svc.CreateSubscription(ctx, "user-1", "user-1", CreateSubscriptionRequest{
+		URL:        "https://hooks.example.com/powerpro",
+		EventTypes: []string{"SET_LOGGED"},
+		Secret:     "a-long-enough-secret",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.HandleEvent(ctx, setLoggedEvent("user-1")))
+
+	reqs := receiver.received()
+	require.Len(t, reqs, 1)
+	req := reqs[0]
+	assert.Equal(t, "SET_LOGGED", req.Header.Get(HeaderEvent))
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+	// The receiver can verify the signature with the shared secret
+	expected := Sign("a-long-enough-secret", clock.now.Unix(), req.Body)
+	assert.True(t, hmac.Equal([]byte(expected), []byte(req.Header.Get(HeaderSignature))))
+	assert.Equal(t, "t="+strconv.FormatInt(clock.now.Unix(), 10), strings.Split(req.Header.Get(HeaderSignature), ",")[0])
+
+	var payload EventPayload
+	require.NoError(t, json.Unmarshal(req.Body, &payload))
+	assert.Equal(t, "SET_LOGGED", payload.Type)
+	assert.Equal(t, "user-1", payload.UserID)
+	assert.Equal(t, "set-1", payload.Data[event.PayloadLoggedSetID])
+	assert.Equal(t, payload.ID, req.Header.Get(HeaderEventID))
+
+	deliveries, total, err := svc.ListDeliveries(ctx, sub.ID, 20, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	d := deliveries[0]
+	assert.Equal(t, DeliverySucceeded, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, d.ID, req.Header.Get(HeaderDelivery))
+	require.NotNil(t, d.LastStatusCode)
+	assert.Equal(t, http.StatusOK, *d.LastStatusCode)
+	assert.NotNil(t, d.DeliveredAt)
+	assert.Nil(t, d.NextAttemptAt)
+}
+
+func TestHandleEvent_Filtering(t *testing.T) {
+	svc, receiver, _, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	createTestUser(t, sqlDB, "user-1")
+	createTestUser(t, sqlDB, "user-2")
+
+	mine, err := svc.CreateSubscription(ctx, "user-1", "user-1", CreateSubscriptionRequest{
+		URL: "https://hooks.example.com/mine", EventTypes: []string{"WORKOUT_COMPLETED"},
+	})
+	require.NoError(t, err)
+	admin, err := svc.CreateSubscription(ctx, "", "admin-1", CreateSubscriptionRequest{
+		URL: "https://hooks.example.com/admin", EventTypes: []string{"SET_LOGGED", "WORKOUT_COMPLETED"},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, admin.UserID)
+	disabled, err := svc.CreateSubscription(ctx, "user-1", "user-1", CreateSubscriptionRequest{
+		URL: "https://hooks.example.com/disabled", EventTypes: []string{"SET_LOGGED"},
+	})
+	require.NoError(t, err)
+	inactive := false
+	_, err = svc.UpdateSubscription(ctx, disabled.ID, UpdateSubscriptionRequest{Active: &inactive})
+	require.NoError(t, err)
+
+	// user-1 logs a set: only the admin subscription wants SET_LOGGED and is active
+	require.NoError(t, svc.HandleEvent(ctx, setLoggedEvent("user-1")))
+	// user-2 completes a workout: the admin subscription sees every user, user-1's does not
+	require.NoError(t, svc.HandleEvent(ctx, event.NewStateEvent(event.EventWorkoutCompleted, "user-2", "program-1")))
+
+	assert.Len(t, receiver.received(), 2)
+	_, mineTotal, err := svc.ListDeliveries(ctx, mine.ID, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), mineTotal)
+	_, adminTotal, err := svc.ListDeliveries(ctx, admin.ID, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), adminTotal)
+	_, disabledTotal, err := svc.ListDeliveries(ctx, disabled.ID, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), disabledTotal)
+}
+
+func TestProcessDue_RetriesWithBackoff(t *testing.T) {
+	svc, receiver, clock, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	createTestUser(t, sqlDB, "user-1")
+
+	sub, err := svc.CreateSubscription(ctx, "user-1", "user-1", CreateSubscriptionRequest{
+		URL: "https://hooks.example.com/flaky", EventTypes: []string{"SET_LOGGED"},
+	})
+	require.NoError(t, err)
+
+	receiver.failNext(http.StatusInternalServerError, http.StatusServiceUnavailable)
+	require.NoError(t, svc.HandleEvent(ctx, setLoggedEvent("user-1")))
+
+	getDelivery := func() Delivery {
+		deliveries, _, err := svc.ListDeliveries(ctx, sub.ID, 20, 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		return deliveries[0]
+	}
+
+	d := getDelivery()
+	assert.Equal(t, DeliveryPending, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	require.NotNil(t, d.LastStatusCode)
+	assert.Equal(t, http.StatusInternalServerError, *d.LastStatusCode)
+	require.NotNil(t, d.NextAttemptAt)
+	assert.Equal(t, clock.now.Add(30*time.Second), *d.NextAttemptAt)
+
+	// Not yet due
+	clock.now = clock.now.Add(29 * time.Second)
+	n, err := svc.ProcessDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	// First retry fails again and backs off for twice as long
+	clock.now = clock.now.Add(time.Second)
+	n, err = svc.ProcessDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	d = getDelivery()
+	assert.Equal(t, 2, d.Attempts)
+	assert.Equal(t, clock.now.Add(time.Minute), *d.NextAttemptAt)
+
+	// Second retry succeeds
+	clock.now = clock.now.Add(time.Minute)
+	n, err = svc.ProcessDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	d = getDelivery()
+	assert.Equal(t, DeliverySucceeded, d.Status)
+	assert.Equal(t, 3, d.Attempts)
+	assert.Nil(t, d.LastError)
+
+	// Every attempt carried the same body and event ID
+	reqs := receiver.received()
+	require.Len(t, reqs, 3)
+	for _, req := range reqs[1:] {
+		assert.Equal(t, reqs[0].Body, req.Body)
+		assert.Equal(t, reqs[0].Header.Get(HeaderEventID), req.Header.Get(HeaderEventID))
+	}
+}
+
+func TestProcessDue_GivesUpAfterMaxAttempts(t *testing.T) {
+	svc, receiver, clock, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	createTestUser(t, sqlDB, "user-1")
+
+	sub, err := svc.CreateSubscription(ctx, "user-1", "user-1", CreateSubscriptionRequest{
+		URL: "https://hooks.example.com/down", EventTypes: []string{"SET_LOGGED"},
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < MaxAttempts; i++ {
+		receiver.failNext(http.StatusBadGateway)
+	}
+	require.NoError(t, svc.HandleEvent(ctx, setLoggedEvent("user-1")))
+	for i := 1; i < MaxAttempts; i++ {
+		clock.now = clock.now.Add(RetryDelay(i))
+		_, err := svc.ProcessDue(ctx)
+		require.NoError(t, err)
+	}
+
+	deliveries, _, err := svc.ListDeliveries(ctx, sub.ID, 20, 0)
+	require.NoError(t, err)
+	d := deliveries[0]
+	assert.Equal(t, DeliveryFailed, d.Status)
+	assert.Equal(t, MaxAttempts, d.Attempts)
+	assert.Nil(t, d.NextAttemptAt)
+	require.NotNil(t, d.LastError)
+	assert.Contains(t, *d.LastError, "502")
+
+	// Nothing is retried once the delivery has failed
+	clock.now = clock.now.Add(24 * time.Hour)
+	n, err := svc.ProcessDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Len(t, receiver.received(), MaxAttempts)
+}
+
+func TestReplay(t *testing.T) {
+	svc, receiver, _, sqlDB, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	createTestUser(t, sqlDB, "user-1")
+
+	sub, err := svc.CreateSubscription(ctx, "user-1", "user-1", CreateSubscriptionRequest{
+		URL: "https://hooks.example.com/replay", EventTypes: []string{"SET_LOGGED"},
+	})
+	require.NoError(t, err)
+	other, err := svc.CreateSubscription(ctx, "user-1", "user-1", CreateSubscriptionRequest{
+		URL: "https://hooks.example.com/other", EventTypes: []string{"PR_ACHIEVED"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.HandleEvent(ctx, setLoggedEvent("user-1")))
+	deliveries, _, err := svc.ListDeliveries(ctx, sub.ID, 20, 0)
+	require.NoError(t, err)
+	original := deliveries[0]
+
+	replay, err := svc.Replay(ctx, sub.ID, original.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, original.ID, replay.ID)
+	assert.Equal(t, original.EventID, replay.EventID)
+	assert.Equal(t, DeliverySucceeded, replay.Status)
+	require.NotNil(t, replay.ReplayOf)
+	assert.Equal(t, original.ID, *replay.ReplayOf)
+
+	reqs := receiver.received()
+	require.Len(t, reqs, 2)
+	assert.Equal(t, reqs[0].Body, reqs[1].Body)
+	assert.Equal(t, replay.ID, reqs[1].Header.Get(HeaderDelivery))
+
+	t.Run("delivery of another subscription", func(t *testing.T) {
+		_, err := svc.Replay(ctx, other.ID, original.ID)
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+
+	t.Run("disabled subscription", func(t *testing.T) {
+		inactive := false
+		_, err := svc.UpdateSubscription(ctx, sub.ID, UpdateSubscriptionRequest{Active: &inactive})
+		require.NoError(t, err)
+		_, err = svc.Replay(ctx, sub.ID, original.ID)
+		assert.True(t, apperrors.IsConflict(err))
+	})
+}
+
+func TestIsBlockedAddr(t *testing.T) {
+	tests := []struct {
+		name    string
+		addr    string
+		blocked bool
+	}{
+		{"loopback", "127.0.0.1", true},
+		{"loopback range", "127.8.9.10", true},
+		{"ipv6 loopback", "::1", true},
+		{"rfc1918 10/8", "10.1.2.3", true},
+		{"rfc1918 172.16/12", "172.20.0.1", true},
+		{"rfc1918 192.168/16", "192.168.1.1", true},
+		{"unique local", "fd00::1", true},
+		{"link-local", "169.254.10.20", true},
+		{"metadata", "169.254.169.254", true},
+		{"ipv6 link-local", "fe80::1", true},
+		{"unspecified", "0.0.0.0", true},
+		{"ipv6 unspecified", "::", true},
+		{"this network", "0.1.2.3", true},
+		{"carrier-grade nat", "100.64.0.1", true},
+		{"multicast", "224.0.0.1", true},
+		{"broadcast", "255.255.255.255", true},
+		{"ipv4-mapped loopback", "::ffff:127.0.0.1", true},
+		{"nat64 private", "64:ff9b::a00:1", true},
+		{"public", "93.184.216.34", false},
+		{"public ipv6", "2606:2800:220:1:248:1893:25c8:1946", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.blocked, isBlockedAddr(netip.MustParseAddr(tc.addr)))
+		})
+	}
+}
+
+func TestDeliveryClient(t *testing.T) {
+	client := NewDeliveryClient()
+
+	t.Run("refuses to connect to blocked addresses", func(t *testing.T) {
+		for _, target := range []string{"http://127.0.0.1:1/hook", "http://[::1]:1/hook", "http://169.254.169.254/latest/meta-data"} {
+			resp, err := client.Post(target, "application/json", strings.NewReader("{}"))
+			if resp != nil {
+				resp.Body.Close()
+			}
+			require.Error(t, err, target)
+			assert.ErrorIs(t, err, ErrBlockedAddress, target)
+		}
+	})
+
+	t.Run("does not follow redirects", func(t *testing.T) {
+		require.NotNil(t, client.CheckRedirect)
+		assert.Equal(t, http.ErrUseLastResponse, client.CheckRedirect(nil, nil))
+	})
+
+	t.Run("ignores proxy settings", func(t *testing.T) {
+		transport, ok := client.Transport.(*http.Transport)
+		require.True(t, ok)
+		assert.Nil(t, transport.Proxy)
+	})
+}
+
+func TestDefaultClientRecordsBlockedDelivery(t *testing.T) {
+	sqlDB, cleanup, err := database.OpenTemp("../../migrations")
+	require.NoError(t, err)
+	defer cleanup()
+	ctx := context.Background()
+	createTestUser(t, sqlDB, "user-1")
+
+	// A stored loopback URL stands in for a host name that resolves to a private address,
+	// which only the dial check can catch
+	svc := NewService(NewSQLiteRepository(sqlDB), nil)
+	sub, err := svc.CreateSubscription(ctx, "user-1", "user-1", CreateSubscriptionRequest{
+		URL: "https://hooks.example.com/hook", EventTypes: []string{"SET_LOGGED"},
+	})
+	require.NoError(t, err)
+	sub.URL = "http://127.0.0.1:1/hook"
+	require.NoError(t, svc.repo.UpdateSubscription(ctx, sub))
+
+	require.NoError(t, svc.HandleEvent(ctx, setLoggedEvent("user-1")))
+	deliveries, _, err := svc.ListDeliveries(ctx, sub.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, DeliveryPending, deliveries[0].Status)
+	assert.Nil(t, deliveries[0].LastStatusCode)
+	require.NotNil(t, deliveries[0].LastError)
+	assert.Contains(t, *deliveries[0].LastError, ErrBlockedAddress.Error())
+}
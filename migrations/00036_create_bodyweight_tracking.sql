@@ -0,0 +1,54 @@
+-- +goose Up
+-- Bodyweight tracking and strength score support
+-- Adds sex, birth date, and weight class target to users and a per-user bodyweight log
+
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN sex TEXT CHECK(sex IS NULL OR sex IN ('male', 'female'));
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN birth_date TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN weight_class_target TEXT CHECK(weight_class_target IS NULL OR length(weight_class_target) <= 10);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE bodyweight_entries (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    weight REAL NOT NULL CHECK(weight > 0),
+    unit TEXT NOT NULL DEFAULT 'lb' CHECK(unit IN ('lb', 'kg')),
+    recorded_at TEXT NOT NULL,
+    notes TEXT CHECK(notes IS NULL OR length(notes) <= 500),
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- Index for chronological lookups per user (trend smoothing, bodyweight at date)
+-- +goose StatementBegin
+CREATE INDEX idx_bodyweight_entries_user_recorded ON bodyweight_entries(user_id, recorded_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_bodyweight_entries_user_recorded;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS bodyweight_entries;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN weight_class_target;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN birth_date;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN sex;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- Personal records tracking
+-- Each row is a record that was set; the current record is the best row per
+-- (user, lift, record type, rep count). previous_value keeps the record it beat.
+
+-- +goose StatementBegin
+CREATE TABLE personal_records (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    lift_id TEXT,
+    record_type TEXT NOT NULL CHECK(record_type IN ('REP_MAX', 'E1RM', 'SESSION_VOLUME', 'COMPETITION_TOTAL')),
+    rep_count INTEGER CHECK(rep_count IS NULL OR (rep_count >= 1 AND rep_count <= 12)),
+    value REAL NOT NULL CHECK(value > 0),
+    weight REAL,
+    reps INTEGER,
+    previous_value REAL,
+    logged_set_id TEXT,
+    session_id TEXT,
+    achieved_at TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (lift_id) REFERENCES lifts(id) ON DELETE CASCADE,
+    FOREIGN KEY (logged_set_id) REFERENCES logged_sets(id) ON DELETE SET NULL,
+    CHECK((record_type = 'COMPETITION_TOTAL') = (lift_id IS NULL)),
+    CHECK((record_type = 'REP_MAX') = (rep_count IS NOT NULL))
+);
+-- +goose StatementEnd
+
+-- Index for looking up the best record of a type for a user's lift
+-- +goose StatementBegin
+CREATE INDEX idx_personal_records_user_lift_type ON personal_records(user_id, lift_id, record_type, rep_count, value);
+-- +goose StatementEnd
+
+-- Index for session volume records
+-- +goose StatementBegin
+CREATE INDEX idx_personal_records_session ON personal_records(session_id, lift_id, record_type);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_personal_records_session;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_personal_records_user_lift_type;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS personal_records;
+-- +goose StatementEnd
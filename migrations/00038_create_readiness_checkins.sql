@@ -0,0 +1,62 @@
+-- +goose Up
+-- Daily readiness check-ins
+-- One check-in per user per calendar day; later check-ins on the same day replace earlier ones.
+-- score is the composite 0-100 readiness score computed when the check-in is recorded.
+
+-- +goose StatementBegin
+CREATE TABLE readiness_checkins (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    checkin_date TEXT NOT NULL,
+    sleep_quality INTEGER CHECK(sleep_quality IS NULL OR (sleep_quality >= 1 AND sleep_quality <= 5)),
+    soreness INTEGER CHECK(soreness IS NULL OR (soreness >= 1 AND soreness <= 5)),
+    stress INTEGER CHECK(stress IS NULL OR (stress >= 1 AND stress <= 5)),
+    readiness_score INTEGER CHECK(readiness_score IS NULL OR (readiness_score >= 1 AND readiness_score <= 10)),
+    hrv REAL CHECK(hrv IS NULL OR hrv > 0),
+    score REAL NOT NULL CHECK(score >= 0 AND score <= 100),
+    notes TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(user_id, checkin_date)
+);
+-- +goose StatementEnd
+
+-- Per-program mapping from readiness score to load and volume modifiers.
+-- tiers is a JSON array; programs without a row use the default mapping.
+-- +goose StatementBegin
+CREATE TABLE program_readiness_mappings (
+    program_id TEXT PRIMARY KEY,
+    tiers TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    FOREIGN KEY (program_id) REFERENCES programs(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- Readiness adjustment applied to a workout session when it was started.
+-- +goose StatementBegin
+CREATE TABLE workout_session_readiness (
+    session_id TEXT PRIMARY KEY,
+    checkin_id TEXT,
+    score REAL NOT NULL,
+    load_modifier REAL NOT NULL,
+    volume_modifier REAL NOT NULL,
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (checkin_id) REFERENCES readiness_checkins(id) ON DELETE SET NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS workout_session_readiness;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS program_readiness_mappings;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS readiness_checkins;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- Progression revert support
+-- Records the stage a progression replaced so it can be undone,
+-- and links revert entries to the log entries they compensate
+
+-- +goose StatementBegin
+ALTER TABLE progression_logs ADD COLUMN previous_stage INTEGER;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE progression_logs ADD COLUMN reverts_log_id TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE progression_logs ADD COLUMN reverted_by_log_id TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE progression_logs DROP COLUMN reverted_by_log_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE progression_logs DROP COLUMN reverts_log_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE progression_logs DROP COLUMN previous_stage;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- Audit trail for corrections to logged sets
+-- Each row records one edit or deletion of a logged set with the values before and after
+
+-- +goose StatementBegin
+CREATE TABLE logged_set_revisions (
+    id TEXT PRIMARY KEY,
+    logged_set_id TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    changed_by TEXT NOT NULL,
+    action TEXT NOT NULL CHECK(action IN ('UPDATE', 'DELETE')),
+    source TEXT NOT NULL CHECK(source IN ('EDIT', 'AMEND')),
+    reason TEXT CHECK(reason IS NULL OR length(reason) <= 500),
+    previous_data TEXT NOT NULL,
+    new_data TEXT,
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- Index for listing a session's revisions in order
+-- +goose StatementBegin
+CREATE INDEX idx_logged_set_revisions_session ON logged_set_revisions(session_id, created_at);
+-- +goose StatementEnd
+
+-- Index for looking up the history of a single set
+-- +goose StatementBegin
+CREATE INDEX idx_logged_set_revisions_logged_set ON logged_set_revisions(logged_set_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_logged_set_revisions_logged_set;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_logged_set_revisions_session;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS logged_set_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- Log of client mutations applied through the offline sync endpoint
+-- id is the client-generated mutation ID; replaying a mutation returns the recorded outcome
+-- instead of applying it again. entity_id is the workout session or lift max the mutation
+-- touched (for LOG_SETS, the session the sets were logged to), and created_at is the
+-- server time the mutation was processed.
+
+-- +goose StatementBegin
+CREATE TABLE sync_mutations (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    mutation_type TEXT NOT NULL CHECK(mutation_type IN ('START_SESSION', 'LOG_SETS', 'FINISH_SESSION', 'SET_MAX')),
+    status TEXT NOT NULL CHECK(status IN ('APPLIED', 'DUPLICATE', 'CONFLICT', 'REJECTED')),
+    code TEXT,
+    message TEXT,
+    entity_id TEXT,
+    client_timestamp TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- Index for finding the mutations a user synced since a cursor
+-- +goose StatementBegin
+CREATE INDEX idx_sync_mutations_user_created ON sync_mutations(user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_sync_mutations_user_created;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sync_mutations;
+-- +goose StatementEnd
@@ -0,0 +1,75 @@
+-- +goose Up
+-- Outgoing webhooks for domain events
+-- Subscriptions belong to a user, or to no user (admin subscriptions that receive every user's events).
+-- Each event sent to a subscription is recorded as a delivery, which is retried with backoff until it succeeds.
+
+-- +goose StatementBegin
+CREATE TABLE webhook_subscriptions (
+    id TEXT PRIMARY KEY,
+    user_id TEXT,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    event_types TEXT NOT NULL,
+    description TEXT CHECK(description IS NULL OR length(description) <= 200),
+    active INTEGER NOT NULL DEFAULT 1,
+    created_by TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_webhook_subscriptions_user ON webhook_subscriptions(user_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE webhook_deliveries (
+    id TEXT PRIMARY KEY,
+    subscription_id TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL CHECK(status IN ('PENDING', 'SUCCEEDED', 'FAILED')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TEXT,
+    last_status_code INTEGER,
+    last_error TEXT,
+    replay_of TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    delivered_at TEXT,
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- Index for the per-subscription delivery log
+-- +goose StatementBegin
+CREATE INDEX idx_webhook_deliveries_subscription_created ON webhook_deliveries(subscription_id, created_at);
+-- +goose StatementEnd
+
+-- Index for finding deliveries due for a retry
+-- +goose StatementBegin
+CREATE INDEX idx_webhook_deliveries_status_next_attempt ON webhook_deliveries(status, next_attempt_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_webhook_deliveries_status_next_attempt;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_webhook_deliveries_subscription_created;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_webhook_subscriptions_user;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_subscriptions;
+-- +goose StatementEnd
@@ -0,0 +1,79 @@
+-- +goose Up
+-- Transactional outbox for state events
+-- Events are written in the same transaction as the state change that caused them and
+-- dispatched to subscribers afterwards. Each subscriber keeps a checkpoint of the last
+-- event it handled; events a subscriber repeatedly fails to handle are recorded as failures.
+
+-- +goose StatementBegin
+CREATE TABLE outbox_events (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    id TEXT NOT NULL UNIQUE,
+    event_type TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    program_id TEXT,
+    payload TEXT NOT NULL,
+    occurred_at TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+-- +goose StatementEnd
+
+-- Index for the admin event log filtered by user
+-- +goose StatementBegin
+CREATE INDEX idx_outbox_events_user_seq ON outbox_events(user_id, seq);
+-- +goose StatementEnd
+
+-- Index for the admin event log filtered by type
+-- +goose StatementBegin
+CREATE INDEX idx_outbox_events_type_seq ON outbox_events(event_type, seq);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE outbox_checkpoints (
+    handler TEXT PRIMARY KEY,
+    last_seq INTEGER NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    updated_at TEXT NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE outbox_failures (
+    handler TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    error TEXT NOT NULL,
+    failed_at TEXT NOT NULL,
+    PRIMARY KEY (handler, event_id),
+    FOREIGN KEY (event_id) REFERENCES outbox_events(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_outbox_failures_event ON outbox_failures(event_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_outbox_failures_event;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox_failures;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox_checkpoints;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_outbox_events_type_seq;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_outbox_events_user_seq;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox_events;
+-- +goose StatementEnd
@@ -0,0 +1,77 @@
+-- +goose Up
+-- Coach–athlete relationships and comments on workout sessions
+-- A relationship starts as an invitation from either the coach or the athlete and becomes
+-- ACTIVE when the other user accepts it. The athlete decides which permissions the coach holds.
+
+-- +goose StatementBegin
+CREATE TABLE coaching_relationships (
+    id TEXT PRIMARY KEY,
+    coach_id TEXT NOT NULL,
+    athlete_id TEXT NOT NULL,
+    status TEXT NOT NULL CHECK(status IN ('PENDING', 'ACTIVE', 'DECLINED', 'ENDED')),
+    permissions TEXT NOT NULL,
+    invited_by TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    responded_at TEXT,
+    ended_at TEXT,
+    CHECK(coach_id != athlete_id),
+    FOREIGN KEY (coach_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (athlete_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- A coach and athlete have at most one open (pending or active) relationship
+-- +goose StatementBegin
+CREATE UNIQUE INDEX idx_coaching_relationships_open ON coaching_relationships(coach_id, athlete_id)
+    WHERE status IN ('PENDING', 'ACTIVE');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_coaching_relationships_coach ON coaching_relationships(coach_id, status);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_coaching_relationships_athlete ON coaching_relationships(athlete_id, status);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE workout_comments (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    author_id TEXT NOT NULL,
+    body TEXT NOT NULL CHECK(length(body) BETWEEN 1 AND 2000),
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_workout_comments_session_created ON workout_comments(session_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_workout_comments_session_created;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS workout_comments;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_coaching_relationships_athlete;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_coaching_relationships_coach;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_coaching_relationships_open;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS coaching_relationships;
+-- +goose StatementEnd
@@ -0,0 +1,107 @@
+-- +goose Up
+-- Organizations (teams and gyms) with member roles, and organization-scoped catalog entries
+-- Lifts, programs and lookups with an organization_id are visible only to that organization's
+-- members; rows without one form the global catalog.
+
+-- +goose StatementBegin
+CREATE TABLE organizations (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+    created_by TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE organization_members (
+    organization_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    role TEXT NOT NULL CHECK(role IN ('OWNER', 'COACH', 'ATHLETE')),
+    joined_at TEXT NOT NULL,
+    PRIMARY KEY (organization_id, user_id),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_organization_members_user ON organization_members(user_id);
+-- +goose StatementEnd
+
+-- Catalog ownership. Organizations cannot be deleted while they still own catalog entries,
+-- so the columns carry no foreign key (SQLite cannot drop a column that has one).
+-- +goose StatementBegin
+ALTER TABLE lifts ADD COLUMN organization_id TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE programs ADD COLUMN organization_id TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE weekly_lookups ADD COLUMN organization_id TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE daily_lookups ADD COLUMN organization_id TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_lifts_organization ON lifts(organization_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_programs_organization ON programs(organization_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_weekly_lookups_organization ON weekly_lookups(organization_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_daily_lookups_organization ON daily_lookups(organization_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_daily_lookups_organization;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_weekly_lookups_organization;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_programs_organization;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_lifts_organization;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE daily_lookups DROP COLUMN organization_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE weekly_lookups DROP COLUMN organization_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE programs DROP COLUMN organization_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE lifts DROP COLUMN organization_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS organization_members;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS organizations;
+-- +goose StatementEnd
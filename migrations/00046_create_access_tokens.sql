@@ -0,0 +1,33 @@
+-- +goose Up
+-- Personal access tokens for scripts and integrations
+-- Only a SHA-256 hash of each token is stored; the token itself is shown once, when it is created.
+-- Scopes limit which routes a token can use. Revoked and expired tokens are kept for the owner's records.
+
+-- +goose StatementBegin
+CREATE TABLE access_tokens (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL CHECK(length(name) <= 100),
+    token_hash TEXT NOT NULL UNIQUE,
+    token_prefix TEXT NOT NULL,
+    scopes TEXT NOT NULL,
+    expires_at TEXT,
+    last_used_at TEXT,
+    revoked_at TEXT,
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_access_tokens_user ON access_tokens(user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_access_tokens_user;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS access_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- Single-use tokens mailed to users to reset their password or verify an email address
+-- Only a SHA-256 hash of each token is stored. Verification tokens carry the address they
+-- verify, which becomes the user's email when it differs (an email change).
+
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN email_verified_at TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE account_tokens (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    purpose TEXT NOT NULL CHECK(purpose IN ('password_reset', 'email_verification')),
+    token_hash TEXT NOT NULL UNIQUE,
+    email TEXT NOT NULL,
+    expires_at TEXT NOT NULL,
+    used_at TEXT,
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_account_tokens_user ON account_tokens(user_id, purpose);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_account_tokens_user;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS account_tokens;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN email_verified_at;
+-- +goose StatementEnd
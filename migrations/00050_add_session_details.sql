@@ -0,0 +1,65 @@
+-- +goose Up
+-- Session details for listing and revoking sessions, and refresh tokens
+-- token_expires_at ends the session token before the session itself when tokens are
+-- short-lived; NULL means the token lasts as long as the session. Refresh tokens are
+-- stored as SHA-256 hashes and kept once used, so a reused token can be recognized.
+
+-- +goose StatementBegin
+ALTER TABLE sessions ADD COLUMN token_expires_at TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE sessions ADD COLUMN last_seen_at TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE sessions ADD COLUMN ip_address TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE sessions ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE sessions SET last_seen_at = created_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE refresh_tokens (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    used_at TEXT,
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_refresh_tokens_session ON refresh_tokens(session_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_refresh_tokens_session;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS refresh_tokens;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE sessions DROP COLUMN user_agent;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE sessions DROP COLUMN ip_address;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE sessions DROP COLUMN last_seen_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE sessions DROP COLUMN token_expires_at;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- Scheduled account deletion
+-- A user who asks to delete their account keeps it until deletion_scheduled_for, and can
+-- cancel until then. A background worker deletes accounts once their time has passed.
+
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN deletion_requested_at TEXT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN deletion_scheduled_for TEXT;
+-- +goose StatementEnd
+
+-- Index for the deletion worker's scan of due accounts
+-- +goose StatementBegin
+CREATE INDEX idx_users_deletion_scheduled ON users(deletion_scheduled_for)
+    WHERE deletion_scheduled_for IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_deletion_scheduled;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN deletion_scheduled_for;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN deletion_requested_at;
+-- +goose StatementEnd
@@ -0,0 +1,130 @@
+-- +goose Up
+-- Training history imports from other lifting apps
+-- Lift aliases map exercise names used by other apps to lifts. Aliases without a user_id are
+-- built in; users add their own by confirming matches during an import. Aliases are stored
+-- normalized: lower case words separated by single spaces.
+
+-- +goose StatementBegin
+CREATE TABLE lift_aliases (
+    id TEXT PRIMARY KEY,
+    lift_id TEXT NOT NULL,
+    user_id TEXT,
+    alias TEXT NOT NULL CHECK(length(alias) > 0),
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (lift_id) REFERENCES lifts(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE UNIQUE INDEX idx_lift_aliases_global ON lift_aliases(alias) WHERE user_id IS NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE UNIQUE INDEX idx_lift_aliases_user ON lift_aliases(user_id, alias) WHERE user_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_lift_aliases_lift ON lift_aliases(lift_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO lift_aliases (id, lift_id, user_id, alias, created_at) VALUES
+    ('lift-alias-0000-0000-000000000001', '00000000-0000-0000-0000-000000000001', NULL, 'back squat', datetime('now')),
+    ('lift-alias-0000-0000-000000000002', '00000000-0000-0000-0000-000000000001', NULL, 'barbell squat', datetime('now')),
+    ('lift-alias-0000-0000-000000000003', '00000000-0000-0000-0000-000000000001', NULL, 'barbell back squat', datetime('now')),
+    ('lift-alias-0000-0000-000000000004', '00000000-0000-0000-0000-000000000001', NULL, 'squat barbell', datetime('now')),
+    ('lift-alias-0000-0000-000000000005', '00000000-0000-0000-0000-000000000001', NULL, 'back squat barbell', datetime('now')),
+    ('lift-alias-0000-0000-000000000006', '00000000-0000-0000-0000-000000000001', NULL, 'low bar squat', datetime('now')),
+    ('lift-alias-0000-0000-000000000007', '00000000-0000-0000-0000-000000000001', NULL, 'high bar squat', datetime('now')),
+    ('lift-alias-0000-0000-000000000011', '00000000-0000-0000-0000-000000000002', NULL, 'bench', datetime('now')),
+    ('lift-alias-0000-0000-000000000012', '00000000-0000-0000-0000-000000000002', NULL, 'barbell bench press', datetime('now')),
+    ('lift-alias-0000-0000-000000000013', '00000000-0000-0000-0000-000000000002', NULL, 'flat barbell bench press', datetime('now')),
+    ('lift-alias-0000-0000-000000000014', '00000000-0000-0000-0000-000000000002', NULL, 'flat bench press', datetime('now')),
+    ('lift-alias-0000-0000-000000000015', '00000000-0000-0000-0000-000000000002', NULL, 'bench press barbell', datetime('now')),
+    ('lift-alias-0000-0000-000000000021', '00000000-0000-0000-0000-000000000003', NULL, 'barbell deadlift', datetime('now')),
+    ('lift-alias-0000-0000-000000000022', '00000000-0000-0000-0000-000000000003', NULL, 'conventional deadlift', datetime('now')),
+    ('lift-alias-0000-0000-000000000023', '00000000-0000-0000-0000-000000000003', NULL, 'deadlift barbell', datetime('now')),
+    ('lift-alias-0000-0000-000000000031', '00000000-0000-0000-0000-000000000004', NULL, 'ohp', datetime('now')),
+    ('lift-alias-0000-0000-000000000032', '00000000-0000-0000-0000-000000000004', NULL, 'press', datetime('now')),
+    ('lift-alias-0000-0000-000000000033', '00000000-0000-0000-0000-000000000004', NULL, 'military press', datetime('now')),
+    ('lift-alias-0000-0000-000000000034', '00000000-0000-0000-0000-000000000004', NULL, 'standing press', datetime('now')),
+    ('lift-alias-0000-0000-000000000035', '00000000-0000-0000-0000-000000000004', NULL, 'strict press', datetime('now')),
+    ('lift-alias-0000-0000-000000000036', '00000000-0000-0000-0000-000000000004', NULL, 'barbell overhead press', datetime('now')),
+    ('lift-alias-0000-0000-000000000037', '00000000-0000-0000-0000-000000000004', NULL, 'overhead press barbell', datetime('now')),
+    ('lift-alias-0000-0000-000000000041', '00000000-0000-0000-0000-000000000005', NULL, 'barbell power clean', datetime('now')),
+    ('lift-alias-0000-0000-000000000042', '00000000-0000-0000-0000-000000000005', NULL, 'power clean barbell', datetime('now'));
+-- +goose StatementEnd
+
+-- One row per import run, with what it created
+-- +goose StatementBegin
+CREATE TABLE training_imports (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    format TEXT NOT NULL CHECK(format IN ('STRONG', 'HEVY', 'FITNOTES', 'GENERIC')),
+    session_count INTEGER NOT NULL,
+    set_count INTEGER NOT NULL,
+    max_count INTEGER NOT NULL,
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_training_imports_user ON training_imports(user_id, created_at);
+-- +goose StatementEnd
+
+-- Historical workouts. They are not part of a program, so they live outside workout_sessions;
+-- their logged sets use the imported session's id as session_id. source_key identifies the
+-- workout in the source file so that importing the same file again skips it.
+-- +goose StatementBegin
+CREATE TABLE imported_sessions (
+    id TEXT PRIMARY KEY,
+    import_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    source_key TEXT NOT NULL,
+    name TEXT,
+    started_at TEXT NOT NULL,
+    finished_at TEXT,
+    FOREIGN KEY (import_id) REFERENCES training_imports(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (user_id, source_key)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_imported_sessions_import ON imported_sessions(import_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_imported_sessions_import;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS imported_sessions;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_training_imports_user;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS training_imports;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_lift_aliases_lift;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_lift_aliases_user;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_lift_aliases_global;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS lift_aliases;
+-- +goose StatementEnd
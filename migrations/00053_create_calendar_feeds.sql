@@ -0,0 +1,25 @@
+-- +goose Up
+-- Per-user iCalendar feeds of upcoming training sessions
+-- Only a SHA-256 hash of each feed token is stored; the token itself is shown when the feed
+-- is created or its token is rotated. Training days are weekday codes such as MON,WED,FRI.
+
+-- +goose StatementBegin
+CREATE TABLE calendar_feeds (
+    user_id TEXT PRIMARY KEY,
+    token_hash TEXT NOT NULL UNIQUE,
+    token_prefix TEXT NOT NULL,
+    training_days TEXT NOT NULL,
+    start_time TEXT NOT NULL,
+    duration_minutes INTEGER NOT NULL CHECK(duration_minutes > 0),
+    timezone TEXT NOT NULL,
+    weeks_ahead INTEGER NOT NULL CHECK(weeks_ahead > 0),
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS calendar_feeds;
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- Append-only audit log of changes to the shared catalog and of lift max changes made on
+-- behalf of other users. Each row records who made the change, the entity, and its JSON
+-- before and after the change with the changed fields. Entries outlive the accounts they
+-- mention, so user IDs are not foreign keys; triggers reject updates and deletes.
+
+-- +goose StatementBegin
+CREATE TABLE audit_log (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    id TEXT NOT NULL UNIQUE,
+    actor_id TEXT NOT NULL,
+    action TEXT NOT NULL CHECK(action IN ('CREATE', 'UPDATE', 'DELETE')),
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    subject_user_id TEXT,
+    method TEXT NOT NULL,
+    path TEXT NOT NULL,
+    before_data TEXT,
+    after_data TEXT,
+    changes TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id, seq);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_audit_log_actor ON audit_log(actor_id, seq);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_audit_log_subject ON audit_log(subject_user_id, seq);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit log entries cannot be changed');
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit log entries cannot be deleted');
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS audit_log_no_delete;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS audit_log_no_update;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_log;
+-- +goose StatementEnd
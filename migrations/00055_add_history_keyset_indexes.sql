@@ -0,0 +1,38 @@
+-- +goose Up
+-- Keyset pagination of users' histories
+-- Each list is ordered by a time column and then rowid, so rows with the same time keep
+-- their insertion order. SQLite ends every index entry with the rowid, so these indexes
+-- let a page start directly after the last row of the previous one
+
+-- +goose StatementBegin
+CREATE INDEX idx_logged_sets_user_created_at ON logged_sets(user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_workout_sessions_state_started_at ON workout_sessions(user_program_state_id, started_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_lift_maxes_user_effective_date ON lift_maxes(user_id, effective_date);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_progression_logs_user_applied_at ON progression_logs(user_id, applied_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_progression_logs_user_applied_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_lift_maxes_user_effective_date;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_workout_sessions_state_started_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_logged_sets_user_created_at;
+-- +goose StatementEnd
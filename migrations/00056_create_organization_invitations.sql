@@ -0,0 +1,42 @@
+-- +goose Up
+-- Invitations to join an organization
+-- Owners invite users, who become members only once they accept. Pending invitations
+-- are not memberships, so invitees see neither the roster nor the organization's catalog.
+
+-- +goose StatementBegin
+CREATE TABLE organization_invitations (
+    id TEXT PRIMARY KEY,
+    organization_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    role TEXT NOT NULL CHECK(role IN ('OWNER', 'COACH', 'ATHLETE')),
+    status TEXT NOT NULL CHECK(status IN ('PENDING', 'ACCEPTED', 'DECLINED')),
+    invited_by TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    responded_at TEXT,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- A user has at most one pending invitation to an organization
+-- +goose StatementBegin
+CREATE UNIQUE INDEX idx_organization_invitations_pending ON organization_invitations(organization_id, user_id)
+    WHERE status = 'PENDING';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_organization_invitations_user ON organization_invitations(user_id, status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_organization_invitations_user;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_organization_invitations_pending;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS organization_invitations;
+-- +goose StatementEnd
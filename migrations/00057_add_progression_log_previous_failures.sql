@@ -0,0 +1,11 @@
+-- +goose Up
+-- Records the failure count a progression reset so reverting it can restore the count
+
+-- +goose StatementBegin
+ALTER TABLE progression_logs ADD COLUMN previous_failures INTEGER;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE progression_logs DROP COLUMN previous_failures;
+-- +goose StatementEnd